@@ -23,6 +23,8 @@ export const config = {
     latencySimulation: parseBoolean(process.env['LATENCY_SIMULATION'] || 'true'),
     networkDelayMs: parseInt(process.env['NETWORK_DELAY_MS'] || '50', 10),
     enableFraudDetection: parseBoolean(process.env['ENABLE_FRAUD_DETECTION'] || 'true'),
+    // Lets test suites delete the data they created; never enable in production
+    enableTestDataCleanup: parseBoolean(process.env['ENABLE_TEST_DATA_CLEANUP'] || 'false'),
   },
   security: {
     jwtSecret: process.env['JWT_SECRET'] || 'supersecretjwtkey',
@@ -26,16 +26,22 @@ export async function setupGrpcServer(prisma: PrismaClient): Promise<Server> {
     // Create service implementation
     const bankSimulatorService = new BankSimulatorService(prisma);
 
-    // Add service to server
+    // Add service to server, one handler per rpc of bank_simulator.proto.
+    // The integration suite's contract tests check the two agree.
     server.addService(bankSimulatorProto.BankSimulator.service, {
       ProcessTransaction: bankSimulatorService.processTransaction.bind(bankSimulatorService),
       GetTransactionStatus: bankSimulatorService.getTransactionStatus.bind(bankSimulatorService),
-      GetAccountBalance: bankSimulatorService.getAccountBalance.bind(bankSimulatorService),
       CreateAccount: bankSimulatorService.createAccount.bind(bankSimulatorService),
+      GetAccountBalance: bankSimulatorService.getAccountBalance.bind(bankSimulatorService),
+      GetAccountDetails: bankSimulatorService.getAccountDetails.bind(bankSimulatorService),
       LinkVPA: bankSimulatorService.linkVPA.bind(bankSimulatorService),
-      GetBankConfig: bankSimulatorService.getBankConfig.bind(bankSimulatorService),
-      UpdateBankStatus: bankSimulatorService.updateBankStatus.bind(bankSimulatorService),
-      GetMetrics: bankSimulatorService.getMetrics.bind(bankSimulatorService),
+      UnlinkVPA: bankSimulatorService.unlinkVPA.bind(bankSimulatorService),
+      ResolveVPA: bankSimulatorService.resolveVPA.bind(bankSimulatorService),
+      GetBankInfo: bankSimulatorService.getBankInfo.bind(bankSimulatorService),
+      CheckBankHealth: bankSimulatorService.checkBankHealth.bind(bankSimulatorService),
+      GetBankStats: bankSimulatorService.getBankStats.bind(bankSimulatorService),
+      PurgeTestData: bankSimulatorService.purgeTestData.bind(bankSimulatorService),
+      SetBankFault: bankSimulatorService.setBankFault.bind(bankSimulatorService),
     });
 
     // Return configured server (binding/starting will be handled by server.ts)
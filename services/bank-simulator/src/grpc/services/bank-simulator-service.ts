@@ -1,7 +1,10 @@
 import { ServerUnaryCall, sendUnaryData, status } from '@grpc/grpc-js';
 import { PrismaClient } from '@prisma/client';
+import { config } from '../../config';
 import { SUPPORTED_BANKS } from '../../constants/banks';
 import { TransactionService } from '../../services/transaction-service';
+import { getBankFault, setBankFault } from '../../services/bank-faults';
+import { InvalidPurgeRequestError, TestDataService } from '../../services/test-data-service';
 import logger from '../../utils/logger';
 
 // Types for gRPC method signatures
@@ -10,11 +13,13 @@ type GrpcCallback<T> = sendUnaryData<T>;
 
 export class BankSimulatorService {
   private transactionService: TransactionService;
+  private testDataService: TestDataService;
   private prisma: PrismaClient;
 
   constructor(prisma: PrismaClient) {
     this.prisma = prisma;
     this.transactionService = new TransactionService(prisma);
+    this.testDataService = new TestDataService(prisma);
   }
   
   async processTransaction(
@@ -56,6 +61,7 @@ export class BankSimulatorService {
         reference: request.reference,
         description: request.description,
         metadata: request.metadata || {},
+        callerGone: () => call.cancelled,
       };
 
       const result = await this.transactionService.processTransaction(transactionRequest);
@@ -481,10 +487,10 @@ export class BankSimulatorService {
   }
 
   async getBankInfo(
-    request: any,
+    call: GrpcCall<any>,
     callback: GrpcCallback<any>
   ): Promise<void> {
-    
+    const request = call.request;
     const bank = SUPPORTED_BANKS[request.bank_code];
     
     if (!bank) {
@@ -546,11 +552,12 @@ export class BankSimulatorService {
         }),
       ]);
 
+      const fault = getBankFault(request.bank_code);
       const response = {
         bank_code: bank.code,
-        health_status: 'HEALTH_STATUS_HEALTHY',
-        success_rate_percent: Math.round(100 - (bank.failureRate * 100)),
-        avg_response_time_ms: bank.processingDelayMs,
+        health_status: fault.maintenance ? 'HEALTH_STATUS_MAINTENANCE' : 'HEALTH_STATUS_HEALTHY',
+        success_rate_percent: fault.maintenance ? 0 : Math.round(100 - (bank.failureRate * 100)),
+        avg_response_time_ms: bank.processingDelayMs + fault.extraLatencyMs,
         total_accounts: totalAccounts,
         active_accounts: activeAccounts,
         last_checked: { seconds: Math.floor(Date.now() / 1000), nanos: 0 },
@@ -995,4 +1002,95 @@ export class BankSimulatorService {
       });
     }
   }
+
+  async purgeTestData(
+    call: GrpcCall<any>,
+    callback: GrpcCallback<any>
+  ): Promise<void> {
+    const request = call.request;
+    const requestLogger = logger.child({
+      customerIdPrefix: request.customer_id_prefix,
+      vpaPrefix: request.vpa_prefix,
+      method: 'PurgeTestData'
+    });
+
+    if (!config.simulator.enableTestDataCleanup) {
+      callback({
+        code: status.PERMISSION_DENIED,
+        message: 'Test data cleanup is disabled',
+        details: 'Set ENABLE_TEST_DATA_CLEANUP=true to enable it',
+      });
+      return;
+    }
+
+    try {
+      const result = await this.testDataService.purge({
+        customerIdPrefix: request.customer_id_prefix,
+        vpaPrefix: request.vpa_prefix,
+        dryRun: request.dry_run,
+      });
+
+      callback(null, {
+        accounts_deleted: result.accountsDeleted,
+        vpas_deleted: result.vpasDeleted,
+        transactions_deleted: result.transactionsDeleted,
+      });
+    } catch (error: unknown) {
+      if (error instanceof InvalidPurgeRequestError) {
+        callback({
+          code: status.INVALID_ARGUMENT,
+          message: error.message,
+        });
+        return;
+      }
+      requestLogger.error('Failed to purge test data', { error });
+      callback({
+        code: status.INTERNAL,
+        message: 'Internal server error',
+        details: (error as Error).message || 'Unknown error',
+      });
+    }
+  }
+
+  async setBankFault(
+    call: GrpcCall<any>,
+    callback: GrpcCallback<any>
+  ): Promise<void> {
+    const request = call.request;
+    const requestLogger = logger.child({
+      bankCode: request.bank_code,
+      method: 'SetBankFault'
+    });
+
+    if (!config.simulator.enableTestDataCleanup) {
+      callback({
+        code: status.PERMISSION_DENIED,
+        message: 'Test support is disabled',
+        details: 'Set ENABLE_TEST_DATA_CLEANUP=true to enable it',
+      });
+      return;
+    }
+
+    if (!SUPPORTED_BANKS[request.bank_code]) {
+      callback({
+        code: status.NOT_FOUND,
+        message: 'Bank not found',
+        details: 'BANK_NOT_FOUND',
+      });
+      return;
+    }
+
+    const declineTypes: Record<string, 'DEBIT' | 'CREDIT'> = {
+      TRANSACTION_TYPE_DEBIT: 'DEBIT',
+      TRANSACTION_TYPE_CREDIT: 'CREDIT',
+    };
+    const fault = {
+      maintenance: request.maintenance,
+      extraLatencyMs: request.extra_latency_ms,
+      declineType: declineTypes[request.decline_type] || null,
+    };
+    setBankFault(request.bank_code, fault);
+    requestLogger.warn('Bank fault set', fault);
+    callback(null, { success: true });
+  }
 }
@@ -23,6 +23,43 @@ const adminRoutes: FastifyPluginAsync = async (fastify) => {
     });
   });
 
+  // Test data cleanup, for integration suites; disabled unless
+  // ENABLE_TEST_DATA_CLEANUP=true
+  fastify.delete('/test-data', async (request: FastifyRequest, reply: FastifyReply) => {
+    const { config } = await import('../../config');
+    if (!config.simulator.enableTestDataCleanup) {
+      await reply.status(403).send({
+        success: false,
+        error: 'Test data cleanup is disabled',
+      });
+      return;
+    }
+
+    const query = request.query as { customerIdPrefix?: string; vpaPrefix?: string; dryRun?: string };
+    const { createPrismaClient } = await import('../../config/database');
+    const { TestDataService, InvalidPurgeRequestError } = await import('../../services/test-data-service');
+    try {
+      const result = await new TestDataService(createPrismaClient()).purge({
+        customerIdPrefix: query.customerIdPrefix,
+        vpaPrefix: query.vpaPrefix,
+        dryRun: query.dryRun === 'true',
+      });
+      await reply.send({
+        success: true,
+        data: result,
+      });
+    } catch (error: unknown) {
+      if (error instanceof InvalidPurgeRequestError) {
+        await reply.status(400).send({
+          success: false,
+          error: error.message,
+        });
+        return;
+      }
+      throw error;
+    }
+  });
+
   // Service metrics endpoint
   fastify.get('/metrics', async (_request: FastifyRequest, reply: FastifyReply) => {
     // Placeholder for custom metrics
@@ -0,0 +1,31 @@
+export interface BankFault {
+  maintenance: boolean;
+  extraLatencyMs: number;
+  // Only transactions of this type are declined; null declines none
+  declineType: 'DEBIT' | 'CREDIT' | null;
+}
+
+const NO_FAULT: BankFault = { maintenance: false, extraLatencyMs: 0, declineType: null };
+
+// Kept in memory so a restart always brings every bank back healthy
+const faults = new Map<string, BankFault>();
+
+/**
+ * Set the fault integration suites inject into one bank, replacing any
+ * previous one. A fault with nothing set clears it.
+ */
+export function setBankFault(bankCode: string, fault: BankFault): void {
+  if (!fault.maintenance && fault.extraLatencyMs <= 0 && !fault.declineType) {
+    faults.delete(bankCode);
+    return;
+  }
+  faults.set(bankCode, {
+    maintenance: fault.maintenance,
+    extraLatencyMs: Math.max(0, fault.extraLatencyMs),
+    declineType: fault.declineType,
+  });
+}
+
+export function getBankFault(bankCode: string): BankFault {
+  return faults.get(bankCode) || NO_FAULT;
+}
@@ -0,0 +1,94 @@
+import { PrismaClient } from '@prisma/client';
+import logger from '../utils/logger';
+
+// Shorter prefixes risk matching real customers
+const MIN_PREFIX_LENGTH = 4;
+
+export interface PurgeTestDataRequest {
+  customerIdPrefix?: string | undefined;
+  vpaPrefix?: string | undefined;
+  dryRun?: boolean | undefined;
+}
+
+export interface PurgeTestDataResponse {
+  accountsDeleted: number;
+  vpasDeleted: number;
+  transactionsDeleted: number;
+}
+
+export class InvalidPurgeRequestError extends Error {}
+
+/**
+ * Removes data created by test suites, so repeated runs start from the same
+ * state instead of accumulating accounts that skew performance baselines.
+ */
+export class TestDataService {
+  private prisma: PrismaClient;
+
+  constructor(prisma: PrismaClient) {
+    this.prisma = prisma;
+  }
+
+  /**
+   * Delete accounts whose customer ID starts with customerIdPrefix, along
+   * with everything that references them, and any VPA starting with
+   * vpaPrefix. Runs in one database transaction.
+   */
+  async purge(request: PurgeTestDataRequest): Promise<PurgeTestDataResponse> {
+    const customerIdPrefix = request.customerIdPrefix || '';
+    const vpaPrefix = request.vpaPrefix || '';
+    const prefixes: Array<[string, string]> = [
+      ['customer_id_prefix', customerIdPrefix],
+      ['vpa_prefix', vpaPrefix],
+    ];
+    for (const [name, prefix] of prefixes) {
+      if (prefix !== '' && prefix.length < MIN_PREFIX_LENGTH) {
+        throw new InvalidPurgeRequestError(`${name} must be at least ${MIN_PREFIX_LENGTH} characters`);
+      }
+    }
+    if (customerIdPrefix === '' && vpaPrefix === '') {
+      throw new InvalidPurgeRequestError('customer_id_prefix or vpa_prefix is required');
+    }
+
+    return await this.prisma.$transaction(async (tx) => {
+      const accounts = customerIdPrefix === ''
+        ? []
+        : await tx.account.findMany({
+          where: { customerId: { startsWith: customerIdPrefix } },
+          select: { id: true },
+        });
+      const accountIds = accounts.map((account) => account.id);
+
+      const vpaWhere = {
+        OR: [
+          { accountId: { in: accountIds } },
+          ...(vpaPrefix === '' ? [] : [{ vpa: { startsWith: vpaPrefix } }]),
+        ],
+      };
+      const transactionWhere = { accountId: { in: accountIds } };
+
+      if (request.dryRun) {
+        return {
+          accountsDeleted: accountIds.length,
+          vpasDeleted: await tx.vpaMapping.count({ where: vpaWhere }),
+          transactionsDeleted: await tx.transaction.count({ where: transactionWhere }),
+        };
+      }
+
+      // Children first; none of these relations cascade
+      const vpas = await tx.vpaMapping.deleteMany({ where: vpaWhere });
+      const transactions = await tx.transaction.deleteMany({ where: transactionWhere });
+      await tx.dailyLimit.deleteMany({ where: { accountId: { in: accountIds } } });
+      await tx.auditLog.deleteMany({ where: { entityId: { in: accountIds } } });
+      const deleted = await tx.account.deleteMany({ where: { id: { in: accountIds } } });
+
+      const result = {
+        accountsDeleted: deleted.count,
+        vpasDeleted: vpas.count,
+        transactionsDeleted: transactions.count,
+      };
+      logger.info('Purged test data', { customerIdPrefix, vpaPrefix, ...result });
+      return result;
+    });
+  }
+}
@@ -2,6 +2,7 @@ import { PrismaClient, Prisma } from '@prisma/client';
 import { createTransactionLogger } from '../utils/logger';
 import { SUPPORTED_BANKS, ERROR_CODES, TRANSACTION_STATUSES } from '../constants/banks';
 import { transactionCounter, transactionDuration } from '../metrics/server';
+import { getBankFault } from './bank-faults';
 
 export interface ProcessTransactionRequest {
   transactionId: string;
@@ -12,6 +13,8 @@ export interface ProcessTransactionRequest {
   reference?: string | null;
   description?: string | null;
   metadata?: Record<string, any> | undefined;
+  // Reports whether the caller has stopped waiting, e.g. its deadline passed
+  callerGone?: () => boolean;
 }
 
 export interface ProcessTransactionResponse {
@@ -69,9 +72,25 @@ export class TransactionService {
         throw new Error(`Unsupported bank: ${request.bankCode}`);
       }
 
-      // Simulate network delay for realistic behavior
-      if (bankConfig.processingDelayMs > 0) {
-        await new Promise(resolve => setTimeout(resolve, bankConfig.processingDelayMs));
+      // Simulate network delay for realistic behavior, plus any injected latency
+      const fault = getBankFault(request.bankCode);
+      const delayMs = bankConfig.processingDelayMs + fault.extraLatencyMs;
+      if (delayMs > 0) {
+        await new Promise(resolve => setTimeout(resolve, delayMs));
+      }
+
+      // A bank that timed out drops the transaction rather than applying
+      // it after the caller has moved on, e.g. to reverse the debit
+      if (request.callerGone?.()) {
+        throw new Error(`Simulated timeout: caller gave up after ${delayMs}ms`);
+      }
+
+      if (fault.maintenance) {
+        throw new Error(`Bank is under maintenance: ${request.bankCode}`);
+      }
+
+      if (fault.declineType === request.type) {
+        throw new Error(`Simulated bank system failure: ${request.type.toLowerCase()}s declined`);
       }
 
       // Simulate random failures based on bank configuration
@@ -523,6 +542,9 @@ export class TransactionService {
     if (message.includes('kyc not verified')) {
       return ERROR_CODES.KYC_PENDING;
     }
+    if (message.includes('under maintenance')) {
+      return ERROR_CODES.BANK_MAINTENANCE;
+    }
     if (message.includes('simulated')) {
       return ERROR_CODES.SYSTEM_ERROR;
     }
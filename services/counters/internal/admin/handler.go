@@ -0,0 +1,258 @@
+// Package admin serves authenticated operations for repairing counters
+// during incidents: reset, rename, merge and namespace migration. Every
+// operation is recorded in the audit log before it runs, with the acting
+// operator, and completed with its outcome and the values before and after.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/suuupra/counters/internal/counter"
+	"github.com/suuupra/counters/internal/database"
+	"github.com/suuupra/counters/internal/namespace"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+// ActorHeader names the operator performing an admin operation. The admin
+// token is shared, so it cannot identify who acted on its own.
+const ActorHeader = "X-Admin-Actor"
+
+const maxAuditEntries = 500
+
+// ResetRequest is the body of a counter reset.
+type ResetRequest struct {
+	Counter counter.Ref `json:"counter" binding:"required"`
+	Value   int64       `json:"value"`
+	Reason  string      `json:"reason" binding:"required"`
+}
+
+// MoveRequest is the body of a rename or merge.
+type MoveRequest struct {
+	Source counter.Ref `json:"source" binding:"required"`
+	Target counter.Ref `json:"target" binding:"required"`
+	Reason string      `json:"reason" binding:"required"`
+}
+
+// MigrateRequest is the body of a namespace migration.
+type MigrateRequest struct {
+	SourceNamespace string `json:"source_namespace" binding:"required"`
+	TargetNamespace string `json:"target_namespace" binding:"required"`
+	Reason          string `json:"reason" binding:"required"`
+}
+
+// Handler serves the admin API.
+type Handler struct {
+	counters   *counter.Service
+	db         *database.DB
+	adminToken string
+	logger     logger.Logger
+}
+
+// NewHandler creates an admin handler. The API is disabled when adminToken
+// is empty.
+func NewHandler(counters *counter.Service, db *database.DB, adminToken string, logger logger.Logger) *Handler {
+	return &Handler{
+		counters:   counters,
+		db:         db,
+		adminToken: adminToken,
+		logger:     logger,
+	}
+}
+
+// SetupRoutes registers the admin endpoints.
+func (h *Handler) SetupRoutes(router *gin.Engine) {
+	admin := router.Group("/api/v1/admin", namespace.RequireAdmin(h.adminToken))
+	admin.GET("/audit", h.ListAudit)
+
+	counters := admin.Group("/counters", requireActor)
+	counters.POST("/reset", h.Reset)
+	counters.POST("/rename", h.Rename)
+	counters.POST("/merge", h.Merge)
+	counters.POST("/migrate", h.Migrate)
+}
+
+func requireActor(c *gin.Context) {
+	if c.GetHeader(ActorHeader) == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": ActorHeader + " header is required"})
+		return
+	}
+	c.Next()
+}
+
+// Reset sets a counter to a value.
+func (h *Handler) Reset(c *gin.Context) {
+	var req ResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
+		return
+	}
+
+	auditID, ok := h.begin(c, "reset", req.Reason, req)
+	if !ok {
+		return
+	}
+	before, err := h.counters.Reset(c.Request.Context(), req.Counter, req.Value)
+	if err != nil {
+		h.finish(c, auditID, "reset", err, nil, nil)
+		h.fail(c, "reset", err)
+		return
+	}
+
+	h.finish(c, auditID, "reset", nil,
+		map[string]any{"counter": req.Counter, "value": before},
+		map[string]any{"counter": req.Counter, "value": req.Value},
+	)
+	c.JSON(http.StatusOK, gin.H{
+		"audit_id": auditID,
+		"counter":  req.Counter,
+		"before":   before,
+		"after":    req.Value,
+	})
+}
+
+// Rename moves a counter to a new name that must not exist yet.
+func (h *Handler) Rename(c *gin.Context) {
+	h.move(c, "rename", h.counters.Rename)
+}
+
+// Merge adds one counter onto another and deletes the source.
+func (h *Handler) Merge(c *gin.Context) {
+	h.move(c, "merge", h.counters.Merge)
+}
+
+func (h *Handler) move(c *gin.Context, op string, fn func(ctx context.Context, source, target counter.Ref) (*counter.Move, error)) {
+	var req MoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
+		return
+	}
+
+	auditID, ok := h.begin(c, op, req.Reason, req)
+	if !ok {
+		return
+	}
+	m, err := fn(c.Request.Context(), req.Source, req.Target)
+	if err != nil {
+		h.finish(c, auditID, op, err, nil, nil)
+		h.fail(c, op, err)
+		return
+	}
+
+	h.finish(c, auditID, op, nil,
+		map[string]any{"source": m.Source, "source_value": m.SourceBefore, "target": m.Target, "target_value": m.TargetBefore},
+		map[string]any{"source": m.Source, "source_value": 0, "target": m.Target, "target_value": m.TargetAfter},
+	)
+	c.JSON(http.StatusOK, gin.H{"audit_id": auditID, "move": m})
+}
+
+// Migrate merges every counter in one namespace into another.
+func (h *Handler) Migrate(c *gin.Context) {
+	var req MigrateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
+		return
+	}
+
+	auditID, ok := h.begin(c, "migrate", req.Reason, req)
+	if !ok {
+		return
+	}
+	moves, err := h.counters.MigrateNamespace(c.Request.Context(), req.SourceNamespace, req.TargetNamespace)
+
+	// Record whatever was moved, even if the migration stopped partway.
+	var before, after []map[string]any
+	for _, m := range moves {
+		before = append(before, map[string]any{"source": m.Source, "source_value": m.SourceBefore, "target": m.Target, "target_value": m.TargetBefore})
+		after = append(after, map[string]any{"source": m.Source, "source_value": 0, "target": m.Target, "target_value": m.TargetAfter})
+	}
+	h.finish(c, auditID, "migrate", err, before, after)
+	if err != nil {
+		h.fail(c, "migrate", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"audit_id": auditID, "moved": len(moves), "moves": moves})
+}
+
+// ListAudit returns recent admin operations, newest first.
+func (h *Handler) ListAudit(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit <= 0 || limit > maxAuditEntries {
+		limit = 100
+	}
+
+	entries, err := h.db.ListAudit(c.Request.Context(), limit)
+	if err != nil {
+		h.logger.Error("Failed to list audit log", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list audit log"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// begin records an operation as pending before it runs. The operation is
+// refused if the audit log cannot be written, so nothing is applied without
+// a record of who asked for it.
+func (h *Handler) begin(c *gin.Context, op, reason string, request any) (int64, bool) {
+	actor := c.GetHeader(ActorHeader)
+	requestJSON, _ := json.Marshal(request)
+
+	id, err := h.db.InsertAudit(c.Request.Context(), database.AuditRow{
+		Actor:     actor,
+		Operation: op,
+		Reason:    reason,
+		Request:   requestJSON,
+	})
+	if err != nil {
+		h.logger.Error("Failed to write audit log entry, refusing admin operation", "error", err,
+			"actor", actor, "operation", op, "request", string(requestJSON))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "audit log unavailable"})
+		return 0, false
+	}
+	return id, true
+}
+
+// finish records the outcome of an operation started with begin. The
+// operation has already run and cannot be undone, so if the outcome cannot
+// be written the entry stays pending and the full outcome is logged.
+func (h *Handler) finish(c *gin.Context, id int64, op string, opErr error, before, after any) {
+	row := database.AuditRow{ID: id, Status: database.AuditApplied}
+	if opErr != nil {
+		row.Status = database.AuditFailed
+		row.Error = opErr.Error()
+	}
+	if before != nil {
+		row.Before, _ = json.Marshal(before)
+	}
+	if after != nil {
+		row.After, _ = json.Marshal(after)
+	}
+
+	// The client going away must not leave the entry pending
+	ctx := context.WithoutCancel(c.Request.Context())
+	actor := c.GetHeader(ActorHeader)
+	if err := h.db.CompleteAudit(ctx, row); err != nil {
+		h.logger.Error("Failed to record admin operation outcome", "error", err,
+			"audit_id", id, "actor", actor, "operation", op, "status", row.Status,
+			"before", string(row.Before), "after", string(row.After), "op_error", row.Error)
+		return
+	}
+	h.logger.Info("Admin operation recorded", "audit_id", id, "actor", actor, "operation", op, "status", row.Status)
+}
+
+func (h *Handler) fail(c *gin.Context, op string, err error) {
+	switch {
+	case counter.IsInvalidArgument(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
+	case errors.Is(err, counter.ErrCounterExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Admin operation failed", "operation", op, "actor", c.GetHeader(ActorHeader), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin operation failed"})
+	}
+}
@@ -0,0 +1,428 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/suuupra/counters/internal/config"
+	"github.com/suuupra/counters/internal/counter"
+	"github.com/suuupra/counters/internal/namespace"
+	"github.com/suuupra/counters/pkg/logger"
+	"github.com/suuupra/counters/pkg/metrics"
+)
+
+// ErrorResponse is the body returned for failed requests.
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message,omitempty"`
+}
+
+// IncrementRequest is the body of an increment call.
+type IncrementRequest struct {
+	// Delta defaults to 1 when omitted.
+	Delta *int64 `json:"delta"`
+	// Consistency is "async" (default) or "sync".
+	Consistency string `json:"consistency"`
+}
+
+// DecrementRequest is the body of a decrement call.
+type DecrementRequest struct {
+	// Amount defaults to 1 when omitted.
+	Amount *int64 `json:"amount"`
+	// Floor, when set, is the lowest value the decrement may reach.
+	Floor       *int64 `json:"floor"`
+	Consistency string `json:"consistency"`
+}
+
+// IncrementIfLessThanRequest is the body of a conditional increment call.
+type IncrementIfLessThanRequest struct {
+	// Delta defaults to 1 when omitted.
+	Delta *int64 `json:"delta"`
+	// Limit is required; the increment applies only if the result does not
+	// exceed it.
+	Limit       *int64 `json:"limit" binding:"required"`
+	Consistency string `json:"consistency"`
+}
+
+// BatchIncrementRequest is the body of a batch increment call.
+type BatchIncrementRequest struct {
+	Increments  []counter.Delta `json:"increments" binding:"required"`
+	Consistency string          `json:"consistency"`
+}
+
+// QueryRequest is the body of a bulk read. Either Counters, or Namespace
+// and Pattern, must be set.
+type QueryRequest struct {
+	Counters  []counter.Ref `json:"counters"`
+	Namespace string        `json:"namespace"`
+	// Pattern is a glob over counter names using * and ?.
+	Pattern string `json:"pattern"`
+	// Limit caps pattern results. Default and maximum: 1000.
+	Limit int `json:"limit"`
+}
+
+// ScoreRequest is the body of a leaderboard score update.
+type ScoreRequest struct {
+	Member string  `json:"member" binding:"required"`
+	Delta  float64 `json:"delta"`
+}
+
+// Handler serves the counters HTTP API.
+type Handler struct {
+	cfg        *config.Config
+	counters   *counter.Service
+	namespaces *namespace.Registry
+	logger     logger.Logger
+	upgrader   websocket.Upgrader
+}
+
+// NewHandler creates the HTTP API handler.
+func NewHandler(cfg *config.Config, counters *counter.Service, namespaces *namespace.Registry, logger logger.Logger) *Handler {
+	h := &Handler{
+		cfg:        cfg,
+		counters:   counters,
+		namespaces: namespaces,
+		logger:     logger,
+	}
+	h.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     h.checkOrigin,
+	}
+	return h
+}
+
+// SetupRoutes registers the HTTP API routes.
+func (h *Handler) SetupRoutes(router *gin.Engine) {
+	router.GET("/health", h.Health)
+	router.GET("/ready", h.Ready)
+
+	v1 := router.Group("/api/v1")
+
+	counters := v1.Group("/counters")
+	counters.POST("/batch", h.BatchIncrement)
+	counters.POST("/query", h.Query)
+	counters.GET("/stream", h.StreamSSE)
+	counters.POST("/stream/tickets", h.CreateStreamTicket)
+	counters.GET("/ws", h.StreamWebSocket)
+	counters.GET("/:namespace/:name", h.GetCounter)
+	counters.POST("/:namespace/:name/increment", h.Increment)
+	counters.POST("/:namespace/:name/decrement", h.Decrement)
+	counters.POST("/:namespace/:name/increment_if_lt", h.IncrementIfLessThan)
+
+	leaderboards := v1.Group("/leaderboards")
+	leaderboards.GET("/:namespace/:board", h.GetLeaderboard)
+	leaderboards.POST("/:namespace/:board/scores", h.IncrementScore)
+}
+
+// Health reports liveness.
+func (h *Handler) Health(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "healthy", "service": "counters"})
+}
+
+// Ready reports whether Redis and Postgres are reachable.
+func (h *Handler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	if err := h.counters.Ping(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready", "error": err.Error()})
+		return
+	}
+	if h.counters.Backpressured() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready", "error": counter.ErrBackpressure.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
+
+// Increment adds to a counter.
+func (h *Handler) Increment(c *gin.Context) {
+	var req IncrementRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+	delta := int64(1)
+	if req.Delta != nil {
+		delta = *req.Delta
+	}
+	consistency, err := counter.ParseConsistency(req.Consistency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+
+	ref := refFromPath(c)
+	if !h.authorize(c, namespace.OpWrite, ref.Namespace) {
+		return
+	}
+
+	start := time.Now()
+	ctr, err := h.counters.Increment(c.Request.Context(), ref, delta, consistency)
+	metrics.Observe("http", "increment", start, err)
+	if err != nil {
+		h.fail(c, "increment", err)
+		return
+	}
+	c.JSON(http.StatusOK, ctr)
+}
+
+// Decrement subtracts from a counter, optionally clamping at a floor.
+func (h *Handler) Decrement(c *gin.Context) {
+	var req DecrementRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+	amount := int64(1)
+	if req.Amount != nil {
+		amount = *req.Amount
+	}
+	consistency, err := counter.ParseConsistency(req.Consistency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+
+	ref := refFromPath(c)
+	if !h.authorize(c, namespace.OpWrite, ref.Namespace) {
+		return
+	}
+
+	start := time.Now()
+	res, err := h.counters.Decrement(c.Request.Context(), ref, amount, req.Floor, consistency)
+	metrics.Observe("http", "decrement", start, err)
+	if err != nil {
+		h.fail(c, "decrement", err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// IncrementIfLessThan adds to a counter only if the result stays within a
+// limit. A refused increment is not an error: the response reports applied
+// false with the current value.
+func (h *Handler) IncrementIfLessThan(c *gin.Context) {
+	var req IncrementIfLessThanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+	delta := int64(1)
+	if req.Delta != nil {
+		delta = *req.Delta
+	}
+	consistency, err := counter.ParseConsistency(req.Consistency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+
+	ref := refFromPath(c)
+	if !h.authorize(c, namespace.OpWrite, ref.Namespace) {
+		return
+	}
+
+	start := time.Now()
+	res, err := h.counters.IncrementIfLessThan(c.Request.Context(), ref, delta, *req.Limit, consistency)
+	metrics.Observe("http", "increment_if_lt", start, err)
+	if err != nil {
+		h.fail(c, "increment_if_lt", err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetCounter reads a counter. With ?window=5m it returns how much the
+// counter moved in that trailing window instead of its total.
+func (h *Handler) GetCounter(c *gin.Context) {
+	ref := refFromPath(c)
+	if !h.authorize(c, namespace.OpRead, ref.Namespace) {
+		return
+	}
+	start := time.Now()
+
+	var (
+		ctr *counter.Counter
+		err error
+	)
+	if w := c.Query("window"); w != "" {
+		window, perr := time.ParseDuration(w)
+		if perr != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: "window must be a duration such as 5m or 1h"})
+			return
+		}
+		ctr, err = h.counters.GetWindow(c.Request.Context(), ref, window)
+	} else {
+		ctr, err = h.counters.Get(c.Request.Context(), ref)
+	}
+	metrics.Observe("http", "get", start, err)
+	if err != nil {
+		h.fail(c, "get", err)
+		return
+	}
+	c.JSON(http.StatusOK, ctr)
+}
+
+// BatchIncrement applies several increments in one call.
+func (h *Handler) BatchIncrement(c *gin.Context) {
+	var req BatchIncrementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+
+	consistency, err := counter.ParseConsistency(req.Consistency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+
+	namespaces := make([]string, len(req.Increments))
+	for i, inc := range req.Increments {
+		namespaces[i] = inc.Namespace
+	}
+	if !h.authorize(c, namespace.OpWrite, namespaces...) {
+		return
+	}
+
+	start := time.Now()
+	ctrs, err := h.counters.BatchIncrement(c.Request.Context(), req.Increments, consistency)
+	metrics.Observe("http", "batch_increment", start, err)
+	if err != nil {
+		h.fail(c, "batch increment", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"counters": ctrs})
+}
+
+// Query reads many counters in one call, either by listing them or by a
+// name pattern within a namespace.
+func (h *Handler) Query(c *gin.Context) {
+	var req QueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+	if (len(req.Counters) > 0) == (req.Pattern != "") {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: "set either counters or namespace and pattern"})
+		return
+	}
+
+	start := time.Now()
+	if req.Pattern != "" {
+		if !h.authorize(c, namespace.OpRead, req.Namespace) {
+			return
+		}
+		ctrs, truncated, err := h.counters.QueryPattern(c.Request.Context(), req.Namespace, req.Pattern, req.Limit)
+		metrics.Observe("http", "query_pattern", start, err)
+		if err != nil {
+			h.fail(c, "query pattern", err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"counters": ctrs, "truncated": truncated})
+		return
+	}
+
+	namespaces := make([]string, len(req.Counters))
+	for i, ref := range req.Counters {
+		namespaces[i] = ref.Namespace
+	}
+	if !h.authorize(c, namespace.OpRead, namespaces...) {
+		return
+	}
+	ctrs, err := h.counters.Query(c.Request.Context(), req.Counters)
+	metrics.Observe("http", "query", start, err)
+	if err != nil {
+		h.fail(c, "query", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"counters": ctrs, "truncated": false})
+}
+
+// IncrementScore adds to a member's leaderboard score.
+func (h *Handler) IncrementScore(c *gin.Context) {
+	var req ScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+	if !h.authorize(c, namespace.OpWrite, c.Param("namespace")) {
+		return
+	}
+
+	start := time.Now()
+	entry, err := h.counters.IncrementScore(c.Request.Context(), c.Param("namespace"), c.Param("board"), req.Member, req.Delta)
+	metrics.Observe("http", "increment_score", start, err)
+	if err != nil {
+		h.fail(c, "increment score", err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// GetLeaderboard returns the top members of a leaderboard.
+func (h *Handler) GetLeaderboard(c *gin.Context) {
+	if !h.authorize(c, namespace.OpRead, c.Param("namespace")) {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	start := time.Now()
+	entries, err := h.counters.TopN(c.Request.Context(), c.Param("namespace"), c.Param("board"), limit)
+	metrics.Observe("http", "leaderboard", start, err)
+	if err != nil {
+		h.fail(c, "leaderboard", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// authorize checks the caller's API key and quota for the given
+// namespaces, writing the error response if the request is refused.
+func (h *Handler) authorize(c *gin.Context, op namespace.Op, namespaces ...string) bool {
+	if err := h.namespaces.Authorize(c.Request.Context(), namespace.APIKey(c.Request), op, namespaces...); err != nil {
+		namespace.WriteError(c, err)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) fail(c *gin.Context, op string, err error) {
+	if counter.IsInvalidArgument(err) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, counter.ErrBackpressure) {
+		c.Header("Retry-After", "1")
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "backpressure", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, counter.ErrNotDurable) {
+		h.logger.Error("Durable write failed", "operation", op, "error", err)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{Error: "write could not be made durable"})
+		return
+	}
+	h.logger.Error("Counter operation failed", "operation", op, "error", err)
+	c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+}
+
+// bindOptionalJSON binds a body that may be omitted entirely. Chunked and
+// HTTP/2 requests do not report a Content-Length, so an empty body is only
+// recognised once reading it hits EOF.
+func bindOptionalJSON(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return false
+	}
+	return true
+}
+
+func refFromPath(c *gin.Context) counter.Ref {
+	return counter.Ref{Namespace: c.Param("namespace"), Name: c.Param("name")}
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/suuupra/counters/internal/counter"
+	"github.com/suuupra/counters/internal/namespace"
+	"github.com/suuupra/counters/pkg/metrics"
+)
+
+const (
+	// streamKeepAlive is how often an idle stream sends a heartbeat so
+	// proxies and load balancers do not drop the connection.
+	streamKeepAlive = 15 * time.Second
+	wsWriteTimeout  = 10 * time.Second
+	// wsReadLimit bounds client messages; clients only send control frames.
+	wsReadLimit = 512
+)
+
+var errInvalidCounterRef = errors.New("counters must be a comma-separated list of namespace:name")
+
+// StreamSSE pushes counter updates as server-sent events.
+//
+//	GET /api/v1/counters/stream?counters=video:views,video:likes&interval=1s
+//
+// Clients that can set headers authenticate with their API key as usual;
+// browsers pass a ticket from CreateStreamTicket as the ticket parameter.
+//
+// The current value of every counter is sent first, then one "counter"
+// event per change, at most once per interval per counter.
+func (h *Handler) StreamSSE(c *gin.Context) {
+	refs, interval, err := h.parseWatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+	if !h.authorizeStream(c, refs) {
+		return
+	}
+
+	updates, err := h.counters.Watch(c.Request.Context(), refs, interval)
+	if err != nil {
+		h.fail(c, "stream", err)
+		return
+	}
+
+	// The server's write timeout is sized for unary requests; lift it for
+	// the lifetime of the stream.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to clear write deadline for stream", "error", err)
+	}
+
+	metrics.ActiveWatches.WithLabelValues("sse").Inc()
+	defer metrics.ActiveWatches.WithLabelValues("sse").Dec()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case ctr, ok := <-updates:
+			if !ok {
+				return false
+			}
+			c.SSEvent("counter", ctr)
+		case <-keepAlive.C:
+			c.SSEvent("ping", gin.H{"time": time.Now().UTC()})
+		}
+		return true
+	})
+}
+
+// StreamWebSocket pushes counter updates as JSON messages over a
+// WebSocket. It takes the same query parameters as StreamSSE.
+func (h *Handler) StreamWebSocket(c *gin.Context) {
+	refs, interval, err := h.parseWatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request", Message: err.Error()})
+		return
+	}
+	if !h.authorizeStream(c, refs) {
+		return
+	}
+
+	// Validate the subscription before upgrading so bad requests still get
+	// a proper HTTP error.
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	updates, err := h.counters.Watch(ctx, refs, interval)
+	if err != nil {
+		h.fail(c, "stream", err)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade has already written an error response.
+		h.logger.Warn("WebSocket upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(wsReadLimit)
+
+	metrics.ActiveWatches.WithLabelValues("websocket").Inc()
+	defer metrics.ActiveWatches.WithLabelValues("websocket").Dec()
+
+	// The client never sends anything meaningful, but reading is what
+	// processes close frames and notices a dead peer.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case ctr, ok := <-updates:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+					time.Now().Add(wsWriteTimeout))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(ctr); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// parseWatch reads the counters and interval query parameters shared by
+// both stream endpoints. counters may be repeated or comma-separated.
+func (h *Handler) parseWatch(c *gin.Context) ([]counter.Ref, time.Duration, error) {
+	var refs []counter.Ref
+	for _, param := range c.QueryArray("counters") {
+		for _, item := range strings.Split(param, ",") {
+			ns, name, ok := strings.Cut(strings.TrimSpace(item), ":")
+			if !ok {
+				return nil, 0, errInvalidCounterRef
+			}
+			refs = append(refs, counter.Ref{Namespace: ns, Name: name})
+		}
+	}
+	if len(refs) == 0 {
+		return nil, 0, errInvalidCounterRef
+	}
+
+	var interval time.Duration // Service default
+	if v := c.Query("interval"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, 0, errors.New("interval must be a duration such as 500ms or 2s")
+		}
+		interval = d
+	}
+	return refs, interval, nil
+}
+
+// CreateStreamTicket exchanges the caller's API key for a single-use stream
+// ticket, for browsers that cannot send the key as a header.
+//
+//	POST /api/v1/counters/stream/tickets
+func (h *Handler) CreateStreamTicket(c *gin.Context) {
+	ticket, expiresAt, err := h.namespaces.IssueTicket(c.Request.Context(), namespace.APIKey(c.Request))
+	if errors.Is(err, namespace.ErrUnauthenticated) {
+		namespace.WriteError(c, err)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to issue stream ticket", "error", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"ticket": ticket, "expires_at": expiresAt.UTC()})
+}
+
+// authorizeStream charges one read per watched counter, authenticating with
+// the API key header or, for browsers, a stream ticket.
+func (h *Handler) authorizeStream(c *gin.Context, refs []counter.Ref) bool {
+	namespaces := make([]string, len(refs))
+	for i, ref := range refs {
+		namespaces[i] = ref.Namespace
+	}
+
+	ctx := c.Request.Context()
+	var err error
+	if key := namespace.APIKey(c.Request); key != "" {
+		err = h.namespaces.Authorize(ctx, key, namespace.OpRead, namespaces...)
+	} else {
+		err = h.namespaces.AuthorizeTicket(ctx, c.Query("ticket"), namespace.OpRead, namespaces...)
+	}
+	if err != nil {
+		namespace.WriteError(c, err)
+		return false
+	}
+	return true
+}
+
+// checkOrigin allows non-browser clients, which send no Origin, and
+// browsers on one of the configured origins.
+func (h *Handler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range h.cfg.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
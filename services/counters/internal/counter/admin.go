@@ -0,0 +1,211 @@
+package counter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Admin operations rewrite counters outside the normal increment path, for
+// incident response. They are applied to Redis first and then logged to
+// the WAL with an fsync so Postgres follows. Every change, a reset
+// included, is logged as a relative delta, so a crash between the two
+// leaves Postgres off by the operation for good; the audit log holds the
+// values needed to repair it. Minute buckets and rollups are history and
+// stay with the original counter.
+
+var (
+	ErrCounterExists = errors.New("target counter already exists")
+	ErrSameCounter   = errors.New("source and target must differ")
+)
+
+// takeScript reads and deletes a counter in one step, so increments that
+// race with a move are either moved or land on a fresh counter.
+//
+// KEYS[1] value key
+// Returns the value, or 0 if the counter did not exist
+var takeScript = redis.NewScript(`
+local value = redis.call('GET', KEYS[1])
+if not value then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+return tonumber(value)
+`)
+
+// addScript adds to a counter, optionally only if it does not exist yet.
+// The existence check and the write are one step, so a rename cannot land
+// on a counter created in between.
+//
+// KEYS[1] value key
+// ARGV[1] amount, ARGV[2] "1" to refuse an existing counter
+// Returns {value, added}; value is the existing value when refused
+var addScript = redis.NewScript(`
+if ARGV[2] == '1' and redis.call('EXISTS', KEYS[1]) == 1 then
+	return {tonumber(redis.call('GET', KEYS[1])), 0}
+end
+return {redis.call('INCRBY', KEYS[1], ARGV[1]), 1}
+`)
+
+// setScript sets a counter and returns its previous value.
+//
+// KEYS[1] value key
+// ARGV[1] new value
+var setScript = redis.NewScript(`
+local old = tonumber(redis.call('GET', KEYS[1]) or '0')
+redis.call('SET', KEYS[1], ARGV[1])
+return old
+`)
+
+// Move is the outcome of moving one counter's value onto another.
+type Move struct {
+	Source       Ref   `json:"source"`
+	Target       Ref   `json:"target"`
+	SourceBefore int64 `json:"source_before"`
+	TargetBefore int64 `json:"target_before"`
+	TargetAfter  int64 `json:"target_after"`
+}
+
+// Reset sets a counter to value and returns the value it replaced.
+func (s *Service) Reset(ctx context.Context, ref Ref, value int64) (int64, error) {
+	if err := ref.validate(); err != nil {
+		return 0, err
+	}
+
+	before, err := setScript.Run(ctx, s.rdb, []string{valueKey(ref)}, value).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("reset %s: %w", ref, err)
+	}
+	s.index(ctx, ref)
+	s.logAdminDeltas(Delta{Ref: ref, Delta: value - before})
+	return before, nil
+}
+
+// Rename moves a counter's value to a new name, which must not already
+// exist. The target is checked and written by one script, so a counter
+// created under that name concurrently makes the rename fail rather than
+// be merged into; the source sits on another slot and cannot share it.
+func (s *Service) Rename(ctx context.Context, source, target Ref) (*Move, error) {
+	if err := s.checkMove(source, target); err != nil {
+		return nil, err
+	}
+	return s.move(ctx, source, target, true)
+}
+
+// Merge adds a counter's value onto another and deletes the source.
+func (s *Service) Merge(ctx context.Context, source, target Ref) (*Move, error) {
+	if err := s.checkMove(source, target); err != nil {
+		return nil, err
+	}
+	return s.move(ctx, source, target, false)
+}
+
+// MigrateNamespace merges every counter in one namespace into the
+// same-named counter in another.
+func (s *Service) MigrateNamespace(ctx context.Context, source, target string) ([]Move, error) {
+	if !namePattern.MatchString(source) || !namePattern.MatchString(target) {
+		return nil, ErrInvalidName
+	}
+	if source == target {
+		return nil, ErrSameCounter
+	}
+
+	var refs []Ref
+	err := s.scanMatch(ctx, fmt.Sprintf("ctr:{%s:*}", source), func(batch []Counter) error {
+		for _, c := range batch {
+			refs = append(refs, c.Ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("migrate %s: %w", source, err)
+	}
+
+	moves := make([]Move, 0, len(refs))
+	for _, ref := range refs {
+		m, err := s.move(ctx, ref, Ref{Namespace: target, Name: ref.Name}, false)
+		if err != nil {
+			return moves, err
+		}
+		moves = append(moves, *m)
+	}
+	return moves, nil
+}
+
+func (s *Service) checkMove(source, target Ref) error {
+	if err := source.validate(); err != nil {
+		return err
+	}
+	if err := target.validate(); err != nil {
+		return err
+	}
+	if source == target {
+		return ErrSameCounter
+	}
+	return nil
+}
+
+// move takes the source's value and adds it to the target. The two
+// counters usually live on different slots, so this is two scripts; if the
+// second fails, or refuses an existing target when create is set, the value
+// is put back on the source. The source leaves the index before its value
+// is taken, so an increment racing with the move indexes it again.
+func (s *Service) move(ctx context.Context, source, target Ref, create bool) (*Move, error) {
+	s.unindex(ctx, source)
+	value, err := takeScript.Run(ctx, s.rdb, []string{valueKey(source)}).Int64()
+	if err != nil {
+		s.index(ctx, source)
+		return nil, fmt.Errorf("move %s: %w", source, err)
+	}
+
+	createArg := "0"
+	if create {
+		createArg = "1"
+	}
+	res, err := addScript.Run(ctx, s.rdb, []string{valueKey(target)}, value, createArg).Int64Slice()
+	if err == nil && res[1] == 0 {
+		err = fmt.Errorf("%w: %s", ErrCounterExists, target)
+	}
+	if err != nil {
+		if rerr := s.rdb.IncrBy(ctx, valueKey(source), value).Err(); rerr != nil {
+			s.logger.Error("Failed to restore counter after failed move",
+				"source", source.String(), "value", value, "error", rerr)
+		}
+		s.index(ctx, source)
+		if errors.Is(err, ErrCounterExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("move %s to %s: %w", source, target, err)
+	}
+	after := res[0]
+	s.index(ctx, target)
+
+	s.logAdminDeltas(
+		Delta{Ref: source, Delta: -value},
+		Delta{Ref: target, Delta: value},
+	)
+	return &Move{
+		Source:       source,
+		Target:       target,
+		SourceBefore: value,
+		TargetBefore: after - value,
+		TargetAfter:  after,
+	}, nil
+}
+
+func (s *Service) logAdminDeltas(deltas ...Delta) {
+	changed := deltas[:0:0]
+	for _, d := range deltas {
+		if d.Delta != 0 {
+			changed = append(changed, d)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	if _, err := s.appendDeltas(changed, s.now(), ConsistencySync); err != nil {
+		s.logger.Error("Failed to log admin change to WAL", "error", err, "deltas", changed)
+	}
+}
@@ -0,0 +1,63 @@
+package counter
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRenameRefusesExistingTarget(t *testing.T) {
+	s, _ := newTestService(t)
+	ctx := context.Background()
+	source := Ref{Namespace: "app", Name: "old"}
+	target := Ref{Namespace: "app", Name: "new"}
+
+	if _, err := s.Increment(ctx, source, 5, ConsistencyAsync); err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+	if _, err := s.Increment(ctx, target, 2, ConsistencyAsync); err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+
+	if _, err := s.Rename(ctx, source, target); !errors.Is(err, ErrCounterExists) {
+		t.Fatalf("err = %v, want ErrCounterExists", err)
+	}
+	for ref, want := range map[Ref]int64{source: 5, target: 2} {
+		c, err := s.Get(ctx, ref)
+		if err != nil || c.Value != want {
+			t.Fatalf("%s = %v (%v), want %d after a refused rename", ref, c, err, want)
+		}
+	}
+	if got, _, _ := s.QueryPattern(ctx, "app", "old", 10); len(got) != 1 {
+		t.Fatal("refused rename left the source out of the index")
+	}
+
+	m, err := s.Rename(ctx, source, Ref{Namespace: "app", Name: "renamed"})
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if m.SourceBefore != 5 || m.TargetBefore != 0 || m.TargetAfter != 5 {
+		t.Fatalf("move = %+v, want 5 moved onto a new counter", m)
+	}
+}
+
+func TestMergeAddsOntoExistingTarget(t *testing.T) {
+	s, _ := newTestService(t)
+	ctx := context.Background()
+	source := Ref{Namespace: "app", Name: "a"}
+	target := Ref{Namespace: "app", Name: "b"}
+
+	s.Increment(ctx, source, 5, ConsistencyAsync)
+	s.Increment(ctx, target, 2, ConsistencyAsync)
+
+	m, err := s.Merge(ctx, source, target)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if m.TargetBefore != 2 || m.TargetAfter != 7 {
+		t.Fatalf("move = %+v, want 2 -> 7", m)
+	}
+	if c, _ := s.Get(ctx, source); c.Value != 0 {
+		t.Fatalf("source = %d after merge, want 0", c.Value)
+	}
+}
@@ -0,0 +1,174 @@
+package counter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// decrementScript decrements a counter and its current minute bucket,
+// clamping at an optional floor. A counter already below the floor is left
+// alone rather than raised to it.
+//
+// KEYS[1] value key, KEYS[2] bucket key
+// ARGV[1] amount, ARGV[2] floor or "" for none, ARGV[3] bucket TTL in seconds
+// Returns {value, applied, clamped}
+var decrementScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local amount = tonumber(ARGV[1])
+local target = current - amount
+local clamped = 0
+
+if ARGV[2] ~= '' then
+	local floor = tonumber(ARGV[2])
+	if target < floor then
+		clamped = 1
+		target = math.max(floor, current)
+	end
+end
+
+local applied = current - target
+if applied ~= 0 then
+	redis.call('DECRBY', KEYS[1], applied)
+	redis.call('DECRBY', KEYS[2], applied)
+	redis.call('EXPIRE', KEYS[2], ARGV[3])
+end
+return {target, applied, clamped}
+`)
+
+// incrementIfLtScript increments a counter and its current minute bucket
+// only if the result stays within a limit.
+//
+// KEYS[1] value key, KEYS[2] bucket key
+// ARGV[1] delta, ARGV[2] limit, ARGV[3] bucket TTL in seconds
+// Returns {value, applied}
+var incrementIfLtScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+if current + tonumber(ARGV[1]) > tonumber(ARGV[2]) then
+	return {current, 0}
+end
+
+local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+redis.call('INCRBY', KEYS[2], ARGV[1])
+redis.call('EXPIRE', KEYS[2], ARGV[3])
+return {value, 1}
+`)
+
+// DecrementResult is the outcome of a decrement.
+type DecrementResult struct {
+	Counter
+	// Applied is how much the counter actually went down by.
+	Applied int64 `json:"applied"`
+	// Clamped is set when the floor stopped the full amount from applying.
+	Clamped bool `json:"clamped"`
+}
+
+// Decrement subtracts amount from a counter. With a floor, the counter
+// never goes below it: the decrement is reduced to land exactly on the
+// floor, and Clamped reports that this happened. The check and the write
+// are one atomic script, so concurrent decrements cannot overshoot.
+func (s *Service) Decrement(ctx context.Context, ref Ref, amount int64, floor *int64, consistency Consistency) (*DecrementResult, error) {
+	if err := ref.validate(); err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	now := s.now()
+	seq, err := s.logDeltas([]Delta{{Ref: ref, Delta: -amount}}, now, consistency)
+	if err != nil {
+		return nil, err
+	}
+
+	floorArg := ""
+	if floor != nil {
+		floorArg = strconv.FormatInt(*floor, 10)
+	}
+
+	bucket := bucketStart(now)
+	res, err := decrementScript.Run(ctx, s.rdb,
+		[]string{valueKey(ref), bucketKey(ref, bucket)},
+		amount, floorArg, int64(bucketTTL.Seconds()),
+	).Int64Slice()
+	if err != nil {
+		s.abortDeltas(seq, []Delta{{Ref: ref, Delta: -amount}}, consistency)
+		return nil, fmt.Errorf("decrement %s: %w", ref, err)
+	}
+
+	applied := res[1]
+	s.settleDelta(seq, ref, -amount, -applied, now, consistency)
+	if applied != 0 {
+		s.markWritten(ctx, ref, bucket)
+	}
+
+	return &DecrementResult{
+		Counter: Counter{Ref: ref, Value: res[0], ObservedAt: now, Durable: consistency == ConsistencySync},
+		Applied: applied,
+		Clamped: res[2] == 1,
+	}, nil
+}
+
+// ConditionalResult is the outcome of a conditional increment.
+type ConditionalResult struct {
+	Counter
+	// Applied reports whether the increment went through. When false,
+	// Value is the unchanged current value.
+	Applied bool `json:"applied"`
+}
+
+// IncrementIfLessThan adds delta to a counter only if the result does not
+// exceed limit, for capacity-capped counters such as seats remaining. An
+// increment that would overshoot is rejected whole rather than applied in
+// part, so the counter never ends up past the limit.
+func (s *Service) IncrementIfLessThan(ctx context.Context, ref Ref, delta, limit int64, consistency Consistency) (*ConditionalResult, error) {
+	if err := ref.validate(); err != nil {
+		return nil, err
+	}
+	if delta <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	now := s.now()
+	seq, err := s.logDeltas([]Delta{{Ref: ref, Delta: delta}}, now, consistency)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := bucketStart(now)
+	res, err := incrementIfLtScript.Run(ctx, s.rdb,
+		[]string{valueKey(ref), bucketKey(ref, bucket)},
+		delta, limit, int64(bucketTTL.Seconds()),
+	).Int64Slice()
+	if err != nil {
+		s.abortDeltas(seq, []Delta{{Ref: ref, Delta: delta}}, consistency)
+		return nil, fmt.Errorf("increment_if_lt %s: %w", ref, err)
+	}
+
+	applied := res[1] == 1
+	if applied {
+		s.markWritten(ctx, ref, bucket)
+	} else {
+		s.settleDelta(seq, ref, delta, 0, now, consistency)
+	}
+
+	return &ConditionalResult{
+		Counter: Counter{Ref: ref, Value: res[0], ObservedAt: now, Durable: applied && consistency == ConsistencySync},
+		Applied: applied,
+	}, nil
+}
+
+// markWritten queues a bucket written by a script for aggregation and
+// indexes the counter's name. The dirty set and the index live on different
+// slots from the counter, so they cannot be touched from inside the script.
+func (s *Service) markWritten(ctx context.Context, ref Ref, bucket time.Time) {
+	pipe := s.rdb.Pipeline()
+	pipe.SAdd(ctx, dirtyBucketsKey, bucketMember(ref, bucket))
+	pipe.ZAddNX(ctx, indexKey(ref.Namespace), &redis.Z{Member: ref.Name})
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Warn("Failed to mark bucket for aggregation", "error", err, "counter", ref.String())
+	}
+}
@@ -0,0 +1,37 @@
+package counter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestIncrementIfLtScriptNeverPassesLimit(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	keys := []string{"value", "bucket"}
+
+	tests := []struct {
+		delta   int64
+		want    int64
+		applied int64
+	}{
+		{3, 3, 1},
+		{5, 8, 1},
+		{3, 8, 0}, // 11 would pass the limit
+		{2, 10, 1},
+		{1, 10, 0},
+	}
+	for _, tt := range tests {
+		res, err := incrementIfLtScript.Run(context.Background(), rdb, keys, tt.delta, 10, 60).Int64Slice()
+		if err != nil {
+			t.Fatalf("delta %d: %v", tt.delta, err)
+		}
+		if res[0] != tt.want || res[1] != tt.applied {
+			t.Fatalf("delta %d: got value %d applied %d, want %d and %d", tt.delta, res[0], res[1], tt.want, tt.applied)
+		}
+	}
+}
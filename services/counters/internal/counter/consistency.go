@@ -0,0 +1,129 @@
+package counter
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/suuupra/counters/internal/wal"
+	"github.com/suuupra/counters/pkg/metrics"
+)
+
+// Consistency selects how durable a write must be before it is acknowledged.
+type Consistency string
+
+const (
+	// ConsistencyAsync logs the delta to the write-ahead log without waiting
+	// for an fsync, applies it in Redis and acknowledges. The WAL is fsynced
+	// in the background. This is the default.
+	ConsistencyAsync Consistency = "async"
+	// ConsistencySync requires the delta to be fsynced to the write-ahead
+	// log before the write is applied and acknowledged, for counters with
+	// financial significance.
+	ConsistencySync Consistency = "sync"
+)
+
+var (
+	ErrInvalidConsistency = errors.New("consistency must be async or sync")
+	ErrNotDurable         = errors.New("write could not be made durable")
+	// ErrBackpressure is returned for writes while the write-ahead log is
+	// too far ahead of Postgres. Producers should back off and retry.
+	ErrBackpressure = errors.New("write-ahead log backlog is over its limit")
+)
+
+// ParseConsistency parses a consistency mode, treating the empty string as
+// the async default.
+func ParseConsistency(s string) (Consistency, error) {
+	switch Consistency(s) {
+	case "", ConsistencyAsync:
+		return ConsistencyAsync, nil
+	case ConsistencySync:
+		return ConsistencySync, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidConsistency, s)
+	}
+}
+
+// Backpressured reports whether writes are being refused because the WAL
+// backlog is over its limit.
+func (s *Service) Backpressured() bool {
+	return s.cfg.WALMaxPending > 0 && s.wal.Pending() >= uint64(s.cfg.WALMaxPending)
+}
+
+// logDeltas records deltas in the write-ahead log ahead of applying them
+// and returns the sequence number of the first entry. Sync writes wait for
+// the fsync.
+func (s *Service) logDeltas(deltas []Delta, now time.Time, consistency Consistency) (uint64, error) {
+	if s.Backpressured() {
+		metrics.BackpressureRejections.Inc()
+		return 0, ErrBackpressure
+	}
+	return s.appendDeltas(deltas, now, consistency)
+}
+
+// appendDeltas writes deltas to the WAL without the backpressure check, for
+// entries that settle a write already admitted.
+func (s *Service) appendDeltas(deltas []Delta, now time.Time, consistency Consistency) (uint64, error) {
+	entries := make([]wal.Entry, len(deltas))
+	for i, d := range deltas {
+		entries[i] = wal.Entry{
+			Namespace: d.Namespace,
+			Name:      d.Name,
+			Delta:     d.Delta,
+			Timestamp: now,
+		}
+	}
+
+	appendFn := s.wal.AppendAsync
+	if consistency == ConsistencySync {
+		appendFn = s.wal.Append
+	}
+	first, err := appendFn(entries...)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrNotDurable, err)
+	}
+	return first, nil
+}
+
+// abortDeltas cancels the logged deltas starting at first, whose Redis
+// write failed or was rejected, so Postgres does not keep increments the
+// caller was told had not happened. The abort is as durable as the write it
+// cancels.
+func (s *Service) abortDeltas(first uint64, deltas []Delta, consistency Consistency) {
+	now := s.now()
+	aborts := make([]wal.Entry, len(deltas))
+	for i, d := range deltas {
+		aborts[i] = wal.Entry{
+			Aborts:    first + uint64(i),
+			Namespace: d.Namespace,
+			Name:      d.Name,
+			Delta:     d.Delta,
+			Timestamp: now,
+		}
+	}
+
+	appendFn := s.wal.AppendAsync
+	if consistency == ConsistencySync {
+		appendFn = s.wal.Append
+	}
+	if _, err := appendFn(aborts...); err != nil {
+		s.logger.Error("Failed to abort WAL entries", "error", err, "first_seq", first, "count", len(deltas))
+	}
+}
+
+// settleDelta reconciles the WAL with what a conditional write actually
+// did. The requested delta is logged before the write runs; if Redis
+// applied a different amount, that entry is aborted and the applied amount
+// logged in its place.
+func (s *Service) settleDelta(seq uint64, ref Ref, requested, applied int64, now time.Time, consistency Consistency) {
+	if applied == requested {
+		return
+	}
+	s.abortDeltas(seq, []Delta{{Ref: ref, Delta: requested}}, consistency)
+	if applied == 0 {
+		return
+	}
+	if _, err := s.appendDeltas([]Delta{{Ref: ref, Delta: applied}}, now, consistency); err != nil {
+		s.logger.Error("Failed to log settled delta", "error", err, "counter", ref.String(), "delta", applied)
+	}
+}
@@ -0,0 +1,114 @@
+package counter
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Each namespace keeps a sorted set of its counter names so that pattern
+// queries read one key instead of scanning the cluster. Writes add to it;
+// moves remove the source before taking its value, so a counter is never
+// missing from the index while it exists. Names of deleted counters can
+// linger after a failed removal and are skipped when their value is read.
+
+// index adds counters to their namespaces' name index.
+func (s *Service) index(ctx context.Context, refs ...Ref) {
+	pipe := s.rdb.Pipeline()
+	for _, ref := range refs {
+		pipe.ZAddNX(ctx, indexKey(ref.Namespace), &redis.Z{Member: ref.Name})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Warn("Failed to index counters", "error", err, "counters", len(refs))
+	}
+}
+
+// unindex removes a counter from its namespace's name index.
+func (s *Service) unindex(ctx context.Context, ref Ref) {
+	if err := s.rdb.ZRem(ctx, indexKey(ref.Namespace), ref.Name).Err(); err != nil {
+		s.logger.Warn("Failed to remove counter from index", "error", err, "counter", ref.String())
+	}
+}
+
+// RebuildIndex adds every existing counter to the name index. Writes keep
+// the index current, so this is only needed once for counters written
+// before it existed: a completed rebuild is recorded and later calls
+// return at once. It is safe to run while traffic is served.
+func (s *Service) RebuildIndex(ctx context.Context) error {
+	built, err := s.rdb.Exists(ctx, indexBuiltKey).Result()
+	if err != nil {
+		return fmt.Errorf("rebuild index: %w", err)
+	}
+	if built > 0 {
+		return nil
+	}
+
+	var indexed int
+	err = s.Scan(ctx, func(batch []Counter) error {
+		pipe := s.rdb.Pipeline()
+		for _, c := range batch {
+			pipe.ZAddNX(ctx, indexKey(c.Namespace), &redis.Z{Member: c.Name})
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		indexed += len(batch)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("rebuild index: %w", err)
+	}
+	if err := s.rdb.Set(ctx, indexBuiltKey, 1, 0).Err(); err != nil {
+		return fmt.Errorf("rebuild index: %w", err)
+	}
+	s.logger.Info("Counter index rebuilt", "counters", indexed)
+	return nil
+}
+
+// matchNames calls fn with batches of names in a namespace that match a
+// glob pattern, in name order. Only the range sharing the pattern's literal
+// prefix is read.
+func (s *Service) matchNames(ctx context.Context, namespace, pattern string, fn func([]string) error) error {
+	prefix := pattern
+	if i := strings.IndexAny(pattern, "*?"); i >= 0 {
+		prefix = pattern[:i]
+	}
+	min, max := "-", "+"
+	if prefix != "" {
+		// Names are ASCII, so every name with the prefix sorts below \xff
+		min, max = "["+prefix, "["+prefix+"\xff"
+	}
+
+	key := indexKey(namespace)
+	for {
+		names, err := s.rdb.ZRangeByLex(ctx, key, &redis.ZRangeBy{
+			Min: min, Max: max, Count: scanBatchSize,
+		}).Result()
+		if err != nil {
+			return err
+		}
+
+		var matched []string
+		for _, name := range names {
+			// Patterns cannot contain path separators, brackets or escapes,
+			// so Match only fails on a mismatch
+			if ok, _ := path.Match(pattern, name); ok {
+				matched = append(matched, name)
+			}
+		}
+		if len(matched) > 0 {
+			if err := fn(matched); err != nil {
+				return err
+			}
+		}
+		if len(names) < scanBatchSize {
+			return nil
+		}
+		// Resume after the last name rather than at an offset, so names
+		// added meanwhile cannot shift the page
+		min = "(" + names[len(names)-1]
+	}
+}
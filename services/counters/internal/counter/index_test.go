@@ -0,0 +1,113 @@
+package counter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/suuupra/counters/internal/config"
+	"github.com/suuupra/counters/internal/wal"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+// newTestService returns an engine on an in-memory Redis with a WAL in a
+// temporary directory and no database.
+func newTestService(t *testing.T) (*Service, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	walLog, err := wal.Open(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("open WAL: %v", err)
+	}
+	t.Cleanup(func() { walLog.Close() })
+
+	cfg := &config.Config{MaxBatchSize: 100, MaxQueryKeys: 100}
+	return New(cfg, rdb, nil, walLog, logger.New("error")), mr
+}
+
+func names(counters []Counter) []string {
+	out := make([]string, len(counters))
+	for i, c := range counters {
+		out[i] = c.Name
+	}
+	return out
+}
+
+func TestQueryPatternReadsIndexInNameOrder(t *testing.T) {
+	s, _ := newTestService(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"views.b", "views.a", "clicks.a", "views.c"} {
+		if _, err := s.Increment(ctx, Ref{Namespace: "app", Name: name}, 1, ConsistencyAsync); err != nil {
+			t.Fatalf("increment %s: %v", name, err)
+		}
+	}
+	if _, err := s.Increment(ctx, Ref{Namespace: "other", Name: "views.z"}, 1, ConsistencyAsync); err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+	if _, err := s.Rename(ctx, Ref{Namespace: "app", Name: "views.c"}, Ref{Namespace: "app", Name: "views.d"}); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	tests := []struct {
+		pattern   string
+		limit     int
+		want      []string
+		truncated bool
+	}{
+		{"views.*", 10, []string{"views.a", "views.b", "views.d"}, false},
+		{"*.a", 10, []string{"clicks.a", "views.a"}, false},
+		{"views.?", 2, []string{"views.a", "views.b"}, true},
+		{"views.c", 10, nil, false},
+	}
+	for _, tt := range tests {
+		got, truncated, err := s.QueryPattern(ctx, "app", tt.pattern, tt.limit)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.pattern, err)
+		}
+		if g := names(got); len(g) != len(tt.want) || truncated != tt.truncated {
+			t.Fatalf("%s: got %v truncated=%v, want %v truncated=%v", tt.pattern, g, truncated, tt.want, tt.truncated)
+		}
+		for i, name := range names(got) {
+			if name != tt.want[i] {
+				t.Fatalf("%s: got %v, want %v", tt.pattern, names(got), tt.want)
+			}
+		}
+	}
+}
+
+func TestRebuildIndexCoversExistingCounters(t *testing.T) {
+	s, mr := newTestService(t)
+	ctx := context.Background()
+
+	// Written before the index existed
+	mr.Set(valueKey(Ref{Namespace: "app", Name: "legacy"}), "7")
+	mr.Set(bucketKey(Ref{Namespace: "app", Name: "legacy"}, s.now()), "7")
+
+	if got, _, _ := s.QueryPattern(ctx, "app", "*", 10); len(got) != 0 {
+		t.Fatalf("unindexed counter found before rebuild: %v", names(got))
+	}
+	if err := s.RebuildIndex(ctx); err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	got, _, err := s.QueryPattern(ctx, "app", "*", 10)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "legacy" || got[0].Value != 7 {
+		t.Fatalf("got %+v, want legacy=7", got)
+	}
+
+	// A completed rebuild is not repeated
+	mr.Set(valueKey(Ref{Namespace: "app", Name: "later"}), "1")
+	if err := s.RebuildIndex(ctx); err != nil {
+		t.Fatalf("second rebuild: %v", err)
+	}
+	if got, _, _ := s.QueryPattern(ctx, "app", "*", 10); len(got) != 1 {
+		t.Fatalf("second rebuild scanned again: %v", names(got))
+	}
+}
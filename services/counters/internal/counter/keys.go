@@ -0,0 +1,87 @@
+package counter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// bucketSize is the aggregation granularity. Windowed reads are served
+	// by summing minute buckets.
+	bucketSize = time.Minute
+	// maxWindow is the longest trailing window that can be read from Redis;
+	// older history lives in the counter_rollups table.
+	maxWindow = 24 * time.Hour
+	// bucketTTL keeps buckets around a little longer than maxWindow so the
+	// aggregation worker can still read a bucket after it closes.
+	bucketTTL = maxWindow + time.Hour
+
+	dirtyBucketsKey = "ctr:dirty:buckets"
+	// indexBuiltKey records that the name index covers counters written
+	// before it existed.
+	indexBuiltKey = "ctr:names:built"
+)
+
+// Every key belonging to one counter carries the same hash tag so that
+// multi-key operations on a single counter stay on one cluster slot.
+
+func valueKey(ref Ref) string {
+	return fmt.Sprintf("ctr:{%s}", ref)
+}
+
+func bucketKey(ref Ref, start time.Time) string {
+	return fmt.Sprintf("ctr:{%s}:m:%d", ref, start.Unix())
+}
+
+// indexKey is the sorted set of counter names in a namespace, all at score
+// zero so that they are ordered by name.
+func indexKey(namespace string) string {
+	return fmt.Sprintf("ctr:names:{%s}", namespace)
+}
+
+func leaderboardKey(namespace, board string) string {
+	return fmt.Sprintf("lb:{%s:%s}", namespace, board)
+}
+
+func bucketStart(t time.Time) time.Time {
+	return t.Truncate(bucketSize)
+}
+
+func bucketMember(ref Ref, start time.Time) string {
+	return fmt.Sprintf("%s:%d", ref, start.Unix())
+}
+
+func parseRef(member string) (Ref, bool) {
+	ns, name, ok := strings.Cut(member, ":")
+	if !ok {
+		return Ref{}, false
+	}
+	ref := Ref{Namespace: ns, Name: name}
+	return ref, ref.validate() == nil
+}
+
+func parseBucketMember(member string) (Ref, time.Time, bool) {
+	i := strings.LastIndexByte(member, ':')
+	if i < 0 {
+		return Ref{}, time.Time{}, false
+	}
+	ts, err := strconv.ParseInt(member[i+1:], 10, 64)
+	if err != nil {
+		return Ref{}, time.Time{}, false
+	}
+	ref, ok := parseRef(member[:i])
+	return ref, time.Unix(ts, 0), ok
+}
+
+// parseInt converts an MGET/GET reply to an int64, treating missing keys as
+// zero.
+func parseInt(v interface{}) int64 {
+	s, ok := v.(string)
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
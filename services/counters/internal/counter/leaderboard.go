@@ -0,0 +1,63 @@
+package counter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const maxLeaderboardSize = 1000
+
+// LeaderboardEntry is a member's position on a leaderboard. Rank is 1-based.
+type LeaderboardEntry struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+	Rank   int64   `json:"rank"`
+}
+
+// IncrementScore adds delta to a member's leaderboard score and returns the
+// member's new score and rank.
+func (s *Service) IncrementScore(ctx context.Context, namespace, board, member string, delta float64) (*LeaderboardEntry, error) {
+	if err := (Ref{Namespace: namespace, Name: board}).validate(); err != nil {
+		return nil, err
+	}
+	if member == "" {
+		return nil, ErrMissingMember
+	}
+
+	key := leaderboardKey(namespace, board)
+	pipe := s.rdb.Pipeline()
+	score := pipe.ZIncrBy(ctx, key, delta, member)
+	rank := pipe.ZRevRank(ctx, key, member)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("increment score %s/%s: %w", namespace, board, err)
+	}
+
+	return &LeaderboardEntry{Member: member, Score: score.Val(), Rank: rank.Val() + 1}, nil
+}
+
+// TopN returns the highest scoring members of a leaderboard.
+func (s *Service) TopN(ctx context.Context, namespace, board string, limit int) ([]LeaderboardEntry, error) {
+	if err := (Ref{Namespace: namespace, Name: board}).validate(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxLeaderboardSize {
+		limit = maxLeaderboardSize
+	}
+
+	zs, err := s.rdb.ZRevRangeWithScores(ctx, leaderboardKey(namespace, board), 0, int64(limit-1)).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("leaderboard %s/%s: %w", namespace, board, err)
+	}
+
+	entries := make([]LeaderboardEntry, len(zs))
+	for i, z := range zs {
+		entries[i] = LeaderboardEntry{
+			Member: fmt.Sprint(z.Member),
+			Score:  z.Score,
+			Rank:   int64(i + 1),
+		}
+	}
+	return entries, nil
+}
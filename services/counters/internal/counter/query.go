@@ -0,0 +1,114 @@
+package counter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// maxPatternResults caps how many counters a pattern query returns.
+const maxPatternResults = 1000
+
+var (
+	ErrInvalidPattern = errors.New("pattern must match [A-Za-z0-9_.*?-]{1,128}")
+
+	patternRegexp = regexp.MustCompile(`^[A-Za-z0-9_.\-*?]{1,128}$`)
+
+	errEnoughResults = errors.New("enough results")
+)
+
+// ValidPattern reports whether s is usable as a counter name pattern.
+func ValidPattern(s string) bool {
+	return patternRegexp.MatchString(s)
+}
+
+// Query returns the current values of many counters in one pipelined round
+// trip. Results are in the order requested; counters that were never
+// written read as zero.
+func (s *Service) Query(ctx context.Context, refs []Ref) ([]Counter, error) {
+	if len(refs) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(refs) > s.cfg.MaxQueryKeys {
+		return nil, fmt.Errorf("%w: %d > %d", ErrBatchTooLarge, len(refs), s.cfg.MaxQueryKeys)
+	}
+	for _, ref := range refs {
+		if err := ref.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+	}
+
+	// Keys span slots, so read them with a pipeline rather than one MGET.
+	pipe := s.rdb.Pipeline()
+	cmds := make([]*redis.StringCmd, len(refs))
+	for i, ref := range refs {
+		cmds[i] = pipe.Get(ctx, valueKey(ref))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	now := s.now()
+	counters := make([]Counter, len(refs))
+	for i, ref := range refs {
+		counters[i] = Counter{Ref: ref, Value: parseInt(cmds[i].Val()), ObservedAt: now}
+	}
+	return counters, nil
+}
+
+// QueryPattern returns counters in a namespace whose names match a glob
+// pattern using * and ?, in name order. Names are read from the
+// namespace's index, narrowed to the pattern's literal prefix, so a leading
+// wildcard reads the whole namespace. At most limit counters are returned;
+// truncated reports whether more matched.
+func (s *Service) QueryPattern(ctx context.Context, namespace, pattern string, limit int) ([]Counter, bool, error) {
+	if !namePattern.MatchString(namespace) {
+		return nil, false, ErrInvalidName
+	}
+	if !patternRegexp.MatchString(pattern) {
+		return nil, false, ErrInvalidPattern
+	}
+	if limit <= 0 || limit > maxPatternResults {
+		limit = maxPatternResults
+	}
+
+	var (
+		counters  []Counter
+		truncated bool
+	)
+	err := s.matchNames(ctx, namespace, pattern, func(names []string) error {
+		pipe := s.rdb.Pipeline()
+		cmds := make([]*redis.StringCmd, len(names))
+		for i, name := range names {
+			cmds[i] = pipe.Get(ctx, valueKey(Ref{Namespace: namespace, Name: name}))
+		}
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return err
+		}
+
+		now := s.now()
+		for i, name := range names {
+			if cmds[i].Err() == redis.Nil {
+				// Moved away since it was indexed
+				continue
+			}
+			if len(counters) == limit {
+				truncated = true
+				return errEnoughResults
+			}
+			counters = append(counters, Counter{
+				Ref:        Ref{Namespace: namespace, Name: name},
+				Value:      parseInt(cmds[i].Val()),
+				ObservedAt: now,
+			})
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errEnoughResults) {
+		return nil, false, fmt.Errorf("query pattern: %w", err)
+	}
+	return counters, truncated, nil
+}
@@ -0,0 +1,96 @@
+package counter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const scanBatchSize = 1000
+
+// Scan walks every counter in the cluster and calls fn with batches of
+// current values. Values within a batch are read together, but the scan as
+// a whole is not a point-in-time snapshot: counters keep moving while it
+// runs. Masters are scanned in parallel, but fn is never called
+// concurrently.
+func (s *Service) Scan(ctx context.Context, fn func([]Counter) error) error {
+	return s.scanMatch(ctx, "ctr:{*}", fn)
+}
+
+func (s *Service) scanMatch(ctx context.Context, match string, fn func([]Counter) error) error {
+	var mu sync.Mutex
+	serialized := func(batch []Counter) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return fn(batch)
+	}
+	scanNode := func(ctx context.Context, node *redis.Client) error {
+		return s.scanNode(ctx, node, match, serialized)
+	}
+
+	if cluster, ok := s.rdb.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, scanNode)
+	}
+	if client, ok := s.rdb.(*redis.Client); ok {
+		return scanNode(ctx, client)
+	}
+	return fmt.Errorf("scan: unsupported redis client %T", s.rdb)
+}
+
+func (s *Service) scanNode(ctx context.Context, node *redis.Client, match string, fn func([]Counter) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := node.Scan(ctx, cursor, match, scanBatchSize).Result()
+		if err != nil {
+			return fmt.Errorf("scan %s: %w", node.Options().Addr, err)
+		}
+
+		refs := make([]Ref, 0, len(keys))
+		valueKeys := make([]string, 0, len(keys))
+		for _, key := range keys {
+			ref, ok := refFromValueKey(key)
+			if !ok {
+				continue
+			}
+			refs = append(refs, ref)
+			valueKeys = append(valueKeys, key)
+		}
+
+		if len(refs) > 0 {
+			pipe := node.Pipeline()
+			cmds := make([]*redis.StringCmd, len(valueKeys))
+			for i, key := range valueKeys {
+				cmds[i] = pipe.Get(ctx, key)
+			}
+			if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+				return fmt.Errorf("scan read: %w", err)
+			}
+
+			now := s.now()
+			batch := make([]Counter, len(refs))
+			for i, ref := range refs {
+				batch[i] = Counter{Ref: ref, Value: parseInt(cmds[i].Val()), ObservedAt: now}
+			}
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// refFromValueKey recovers the counter from a value key, rejecting bucket
+// and other per-counter keys that share the prefix.
+func refFromValueKey(key string) (Ref, bool) {
+	if !strings.HasPrefix(key, "ctr:{") || !strings.HasSuffix(key, "}") {
+		return Ref{}, false
+	}
+	return parseRef(key[len("ctr:{") : len(key)-1])
+}
@@ -0,0 +1,241 @@
+// Package counter implements the counter engine: atomic increments and
+// reads in the Redis cluster, minute-level aggregation buckets, leaderboards,
+// and the background workers that persist values to Postgres.
+package counter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/suuupra/counters/internal/config"
+	"github.com/suuupra/counters/internal/database"
+	"github.com/suuupra/counters/internal/wal"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+var (
+	ErrInvalidName   = errors.New("namespace and name must match [A-Za-z0-9_.-]{1,128}")
+	ErrInvalidWindow = errors.New("window must be a whole number of minutes up to 24h")
+	ErrBatchTooLarge = errors.New("batch exceeds maximum size")
+	ErrEmptyBatch    = errors.New("batch must not be empty")
+	ErrMissingMember = errors.New("member is required")
+	ErrInvalidAmount = errors.New("amount must be positive")
+)
+
+// IsInvalidArgument reports whether err was caused by a malformed request
+// rather than a backend failure.
+func IsInvalidArgument(err error) bool {
+	return errors.Is(err, ErrInvalidName) ||
+		errors.Is(err, ErrInvalidWindow) ||
+		errors.Is(err, ErrBatchTooLarge) ||
+		errors.Is(err, ErrEmptyBatch) ||
+		errors.Is(err, ErrMissingMember) ||
+		errors.Is(err, ErrInvalidAmount) ||
+		errors.Is(err, ErrInvalidPattern) ||
+		errors.Is(err, ErrSameCounter) ||
+		errors.Is(err, ErrInvalidConsistency)
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.\-]{1,128}$`)
+
+// ValidName reports whether s is usable as a namespace or counter name.
+func ValidName(s string) bool {
+	return namePattern.MatchString(s)
+}
+
+// Ref identifies a counter.
+type Ref struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func (r Ref) validate() error {
+	if !namePattern.MatchString(r.Namespace) || !namePattern.MatchString(r.Name) {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+func (r Ref) String() string {
+	return r.Namespace + ":" + r.Name
+}
+
+// Counter is a counter value as observed at a point in time.
+type Counter struct {
+	Ref
+	Value      int64     `json:"value"`
+	ObservedAt time.Time `json:"observed_at"`
+	// Durable is set on writes that were logged to the WAL before being
+	// acknowledged.
+	Durable bool `json:"durable,omitempty"`
+}
+
+// Delta is a single increment within a batch.
+type Delta struct {
+	Ref
+	Delta int64 `json:"delta"`
+}
+
+// Service is the counter engine.
+type Service struct {
+	cfg    *config.Config
+	rdb    redis.UniversalClient
+	db     *database.DB
+	wal    *wal.Log
+	logger logger.Logger
+	now    func() time.Time
+}
+
+// New creates the counter engine.
+func New(cfg *config.Config, rdb redis.UniversalClient, db *database.DB, walLog *wal.Log, logger logger.Logger) *Service {
+	return &Service{
+		cfg:    cfg,
+		rdb:    rdb,
+		db:     db,
+		wal:    walLog,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Ping checks connectivity to Redis and Postgres.
+func (s *Service) Ping(ctx context.Context) error {
+	if err := s.rdb.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	if err := s.db.Ping(ctx); err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+	return nil
+}
+
+// Increment adds delta to a counter and returns the new value. A negative
+// delta decrements without any floor; use Decrement to enforce one.
+func (s *Service) Increment(ctx context.Context, ref Ref, delta int64, consistency Consistency) (*Counter, error) {
+	if err := ref.validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	seq, err := s.logDeltas([]Delta{{Ref: ref, Delta: delta}}, now, consistency)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := s.rdb.Pipeline()
+	incr := s.queueIncrement(ctx, pipe, ref, delta, now)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.abortDeltas(seq, []Delta{{Ref: ref, Delta: delta}}, consistency)
+		return nil, fmt.Errorf("increment %s: %w", ref, err)
+	}
+
+	return &Counter{Ref: ref, Value: incr.Val(), ObservedAt: now, Durable: consistency == ConsistencySync}, nil
+}
+
+// BatchIncrement applies several increments in one pipelined round trip.
+// Increments are applied independently; the batch is not atomic. In sync
+// mode the whole batch is logged with a single fsync.
+func (s *Service) BatchIncrement(ctx context.Context, deltas []Delta, consistency Consistency) ([]Counter, error) {
+	if len(deltas) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(deltas) > s.cfg.MaxBatchSize {
+		return nil, fmt.Errorf("%w: %d > %d", ErrBatchTooLarge, len(deltas), s.cfg.MaxBatchSize)
+	}
+	for _, d := range deltas {
+		if err := d.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", d.Ref, err)
+		}
+	}
+
+	now := s.now()
+	seq, err := s.logDeltas(deltas, now, consistency)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := s.rdb.Pipeline()
+	cmds := make([]*redis.IntCmd, len(deltas))
+	for i, d := range deltas {
+		cmds[i] = s.queueIncrement(ctx, pipe, d.Ref, d.Delta, now)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		// A pipeline error does not say which commands were applied; abort
+		// the entries whose increment did not go through.
+		for i, cmd := range cmds {
+			if cmd.Err() != nil {
+				s.abortDeltas(seq+uint64(i), deltas[i:i+1], consistency)
+			}
+		}
+		return nil, fmt.Errorf("batch increment: %w", err)
+	}
+
+	counters := make([]Counter, len(deltas))
+	for i, d := range deltas {
+		counters[i] = Counter{Ref: d.Ref, Value: cmds[i].Val(), ObservedAt: now, Durable: consistency == ConsistencySync}
+	}
+	return counters, nil
+}
+
+// queueIncrement adds everything an increment touches to pipe: the live
+// value, the current minute bucket, the dirty set the aggregation worker
+// drains, and the namespace's name index.
+func (s *Service) queueIncrement(ctx context.Context, pipe redis.Pipeliner, ref Ref, delta int64, now time.Time) *redis.IntCmd {
+	bucket := bucketStart(now)
+	incr := pipe.IncrBy(ctx, valueKey(ref), delta)
+	pipe.IncrBy(ctx, bucketKey(ref, bucket), delta)
+	pipe.Expire(ctx, bucketKey(ref, bucket), bucketTTL)
+	pipe.SAdd(ctx, dirtyBucketsKey, bucketMember(ref, bucket))
+	pipe.ZAddNX(ctx, indexKey(ref.Namespace), &redis.Z{Member: ref.Name})
+	return incr
+}
+
+// Get returns the current value of a counter. Counters that were never
+// written read as zero.
+func (s *Service) Get(ctx context.Context, ref Ref) (*Counter, error) {
+	if err := ref.validate(); err != nil {
+		return nil, err
+	}
+
+	value, err := s.rdb.Get(ctx, valueKey(ref)).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("get %s: %w", ref, err)
+	}
+	return &Counter{Ref: ref, Value: value, ObservedAt: s.now()}, nil
+}
+
+// GetWindow returns how much a counter moved within the trailing window,
+// summed from its minute buckets.
+func (s *Service) GetWindow(ctx context.Context, ref Ref, window time.Duration) (*Counter, error) {
+	if err := ref.validate(); err != nil {
+		return nil, err
+	}
+	if window < bucketSize || window > maxWindow || window%bucketSize != 0 {
+		return nil, ErrInvalidWindow
+	}
+
+	now := s.now()
+	current := bucketStart(now)
+	n := int(window / bucketSize)
+	keys := make([]string, n)
+	for i := 0; i < n; i++ {
+		keys[i] = bucketKey(ref, current.Add(-time.Duration(i)*bucketSize))
+	}
+
+	// All buckets share the counter's hash tag, so this MGET stays on one
+	// cluster slot.
+	vals, err := s.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get window %s: %w", ref, err)
+	}
+
+	var total int64
+	for _, v := range vals {
+		total += parseInt(v)
+	}
+	return &Counter{Ref: ref, Value: total, ObservedAt: now}, nil
+}
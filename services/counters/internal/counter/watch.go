@@ -0,0 +1,98 @@
+package counter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const maxWatchedCounters = 100
+
+// Watch polls the given counters every interval and emits a Counter whenever
+// a value differs from what was last sent. Current values are emitted once
+// up front. The channel is closed when ctx is cancelled. A zero interval
+// uses the configured WatchInterval, so HTTP and gRPC watchers agree.
+//
+// Polling rather than pub/sub keeps updates naturally throttled: a counter
+// incremented thousands of times a second produces at most one update per
+// interval.
+func (s *Service) Watch(ctx context.Context, refs []Ref, interval time.Duration) (<-chan Counter, error) {
+	if len(refs) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(refs) > maxWatchedCounters {
+		return nil, fmt.Errorf("%w: %d > %d", ErrBatchTooLarge, len(refs), maxWatchedCounters)
+	}
+	for _, ref := range refs {
+		if err := ref.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+	}
+	if interval <= 0 {
+		interval = s.cfg.WatchInterval
+	}
+	if interval < s.cfg.MinWatchInterval {
+		interval = s.cfg.MinWatchInterval
+	}
+
+	keys := make([]string, len(refs))
+	for i, ref := range refs {
+		keys[i] = valueKey(ref)
+	}
+
+	updates := make(chan Counter, len(refs))
+	go func() {
+		defer close(updates)
+
+		last := make(map[Ref]int64, len(refs))
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for first := true; ; first = false {
+			if err := s.pollWatched(ctx, refs, keys, last, first, updates); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.logger.Warn("Watch poll failed", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+func (s *Service) pollWatched(ctx context.Context, refs []Ref, keys []string, last map[Ref]int64, force bool, out chan<- Counter) error {
+	// Keys span slots, so read them with a pipeline rather than one MGET.
+	pipe := s.rdb.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return err
+	}
+
+	now := s.now()
+	for i, ref := range refs {
+		value := parseInt(cmds[i].Val())
+		if prev, seen := last[ref]; seen && prev == value && !force {
+			continue
+		}
+		last[ref] = value
+
+		select {
+		case out <- Counter{Ref: ref, Value: value, ObservedAt: now}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package counter
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/suuupra/counters/internal/database"
+	"github.com/suuupra/counters/pkg/metrics"
+)
+
+// StartPersistenceWorker drains the write-ahead log into Postgres and
+// fsyncs entries logged by async writes. It blocks until ctx is cancelled.
+func (s *Service) StartPersistenceWorker(ctx context.Context) {
+	flushTicker := time.NewTicker(s.cfg.PersistInterval)
+	defer flushTicker.Stop()
+	syncTicker := time.NewTicker(s.cfg.WALSyncInterval)
+	defer syncTicker.Stop()
+
+	s.logger.Info("Persistence worker started", "interval", s.cfg.PersistInterval, "wal_sync_interval", s.cfg.WALSyncInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Persistence worker stopped")
+			return
+		case <-syncTicker.C:
+			if err := s.wal.Sync(); err != nil {
+				s.logger.Error("Failed to sync WAL", "error", err)
+			}
+		case <-flushTicker.C:
+			s.drainWAL(ctx)
+		}
+	}
+}
+
+// drainWAL flushes batches until the log is caught up, so a burst of
+// writes does not have to wait one interval per batch.
+func (s *Service) drainWAL(ctx context.Context) {
+	for ctx.Err() == nil {
+		start := time.Now()
+		n, err := s.flushWAL(ctx)
+		metrics.WALPendingEntries.Set(float64(s.wal.Pending()))
+		if err != nil {
+			metrics.WALFlushErrors.Inc()
+			if ctx.Err() == nil {
+				s.logger.Error("Failed to flush WAL", "error", err, "pending", s.wal.Pending())
+			}
+			return
+		}
+		if n == 0 {
+			metrics.WALLagSeconds.Set(0)
+			return
+		}
+		metrics.WALFlushDuration.Observe(time.Since(start).Seconds())
+		if n < s.cfg.PersistBatchSize {
+			return
+		}
+	}
+}
+
+// flushWAL applies the next batch of WAL entries to Postgres and, once the
+// transaction commits, advances the checkpoint past them. It returns the
+// number of entries consumed.
+//
+// An abort is logged only once the Redis outcome is known, which may be
+// after its entry was flushed. An abort in the same batch as its entry
+// drops both; a later one is persisted as a compensating delta.
+func (s *Service) flushWAL(ctx context.Context) (int, error) {
+	checkpoint, err := s.wal.Checkpoint()
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := s.wal.ReadAfter(checkpoint, s.cfg.PersistBatchSize)
+	if err != nil || len(entries) == 0 {
+		return 0, err
+	}
+	metrics.WALLagSeconds.Set(s.now().Sub(entries[0].Timestamp).Seconds())
+
+	first := entries[0].Seq
+	aborted := make(map[uint64]bool)
+	for _, e := range entries {
+		if e.Aborts >= first {
+			aborted[e.Aborts] = true
+		}
+	}
+
+	rows := make([]database.DeltaRow, 0, len(entries))
+	for _, e := range entries {
+		delta := e.Delta
+		switch {
+		case e.Aborts == 0 && aborted[e.Seq]:
+			continue
+		case e.Aborts >= first:
+			continue
+		case e.Aborts != 0 && e.Name == "":
+			// Logged before aborts carried their delta; nothing to undo with
+			s.logger.Warn("Cannot compensate WAL abort without its delta", "seq", e.Seq, "aborts", e.Aborts)
+			continue
+		case e.Aborts != 0:
+			delta = -delta
+		}
+		rows = append(rows, database.DeltaRow{
+			WALSeq:    e.Seq,
+			Namespace: e.Namespace,
+			Name:      e.Name,
+			Delta:     delta,
+			CreatedAt: e.Timestamp,
+		})
+	}
+
+	last := entries[len(entries)-1].Seq
+	applied, err := s.db.ApplyDeltas(ctx, s.wal.ID(), s.cfg.InstanceID, last, rows)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.wal.SetCheckpoint(last); err != nil {
+		return 0, err
+	}
+
+	metrics.WALFlushedEntries.Add(float64(applied))
+	s.logger.Debug("Flushed WAL", "entries", len(entries), "applied", applied, "checkpoint", last)
+	return len(entries), nil
+}
+
+// StartAggregationWorker rolls closed minute buckets up into Postgres and
+// prunes rollups past the retention period. It blocks until ctx is
+// cancelled.
+func (s *Service) StartAggregationWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.AggregationInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("Aggregation worker started", "interval", s.cfg.AggregationInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Aggregation worker stopped")
+			return
+		case <-ticker.C:
+			if err := s.aggregateBuckets(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("Failed to aggregate counters", "error", err)
+			}
+			cutoff := s.now().AddDate(0, 0, -s.cfg.RetentionDays)
+			if _, err := s.db.DeleteRollupsBefore(ctx, cutoff); err != nil && ctx.Err() == nil {
+				s.logger.Error("Failed to prune rollups", "error", err)
+			}
+		}
+	}
+}
+
+func (s *Service) aggregateBuckets(ctx context.Context) error {
+	members, err := s.rdb.SPopN(ctx, dirtyBucketsKey, int64(s.cfg.PersistBatchSize)).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	// Buckets that are still open go back into the set for the next run.
+	current := bucketStart(s.now())
+	var open []string
+	type bucket struct {
+		ref   Ref
+		start time.Time
+	}
+	closed := make([]bucket, 0, len(members))
+	closedMembers := make([]string, 0, len(members))
+	for _, m := range members {
+		ref, start, ok := parseBucketMember(m)
+		if !ok {
+			continue
+		}
+		if !start.Before(current) {
+			open = append(open, m)
+			continue
+		}
+		closed = append(closed, bucket{ref: ref, start: start})
+		closedMembers = append(closedMembers, m)
+	}
+	s.requeue(ctx, dirtyBucketsKey, open)
+	if len(closed) == 0 {
+		return nil
+	}
+
+	pipe := s.rdb.Pipeline()
+	cmds := make([]*redis.StringCmd, len(closed))
+	for i, b := range closed {
+		cmds[i] = pipe.Get(ctx, bucketKey(b.ref, b.start))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		s.requeue(ctx, dirtyBucketsKey, closedMembers)
+		return err
+	}
+
+	rows := make([]database.RollupRow, len(closed))
+	for i, b := range closed {
+		rows[i] = database.RollupRow{
+			Namespace:   b.ref.Namespace,
+			Name:        b.ref.Name,
+			BucketStart: b.start,
+			Value:       parseInt(cmds[i].Val()),
+		}
+	}
+
+	if err := s.db.UpsertRollups(ctx, rows); err != nil {
+		s.requeue(ctx, dirtyBucketsKey, closedMembers)
+		return err
+	}
+
+	s.logger.Debug("Aggregated counter buckets", "count", len(rows))
+	return nil
+}
+
+// requeue puts popped members back so a failed run is retried on the next
+// tick.
+func (s *Service) requeue(ctx context.Context, key string, members []string) {
+	if len(members) == 0 {
+		return
+	}
+	args := make([]interface{}, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	if err := s.rdb.SAdd(ctx, key, args...).Err(); err != nil {
+		s.logger.Error("Failed to requeue dirty counters", "error", err, "count", len(members))
+	}
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Audit entry statuses. An entry is written as pending before the operation
+// runs and updated with its outcome afterwards; one left pending means the
+// outcome could not be recorded and the operation may have been applied.
+const (
+	AuditPending = "pending"
+	AuditApplied = "applied"
+	AuditFailed  = "failed"
+)
+
+// AuditRow is one admin operation in the audit log. Request holds the
+// operation as asked for; Before and After hold the affected counter values
+// as JSON once it has run.
+type AuditRow struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Operation string          `json:"operation"`
+	Reason    string          `json:"reason,omitempty"`
+	Status    string          `json:"status"`
+	Request   json.RawMessage `json:"request,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// InsertAudit records an admin operation about to run as pending and
+// returns its ID.
+func (db *DB) InsertAudit(ctx context.Context, row AuditRow) (int64, error) {
+	var id int64
+	err := db.conn.QueryRowContext(ctx, `
+		INSERT INTO admin_audit_log (actor, operation, reason, status, request)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		row.Actor, row.Operation, row.Reason, AuditPending, nullJSON(row.Request),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+	return id, nil
+}
+
+// CompleteAudit records the outcome of a pending audit entry.
+func (db *DB) CompleteAudit(ctx context.Context, row AuditRow) error {
+	res, err := db.conn.ExecContext(ctx, `
+		UPDATE admin_audit_log SET status = $2, before = $3, after = $4, error = $5
+		WHERE id = $1 AND status = $6`,
+		row.ID, row.Status, nullJSON(row.Before), nullJSON(row.After), row.Error, AuditPending,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete audit entry %d: %w", row.ID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("audit entry %d is not pending", row.ID)
+	}
+	return nil
+}
+
+// ListAudit returns the most recent audit entries, newest first.
+func (db *DB) ListAudit(ctx context.Context, limit int) ([]AuditRow, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT id, actor, operation, reason, status, request, before, after, error, created_at
+		FROM admin_audit_log ORDER BY id DESC LIMIT $1`,
+		limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AuditRow
+	for rows.Next() {
+		var (
+			r                      AuditRow
+			request, before, after []byte
+		)
+		if err := rows.Scan(&r.ID, &r.Actor, &r.Operation, &r.Reason, &r.Status, &request, &before, &after, &r.Error, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		r.Request, r.Before, r.After = request, before, after
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func nullJSON(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return []byte(raw)
+}
@@ -0,0 +1,285 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+// DB is the Postgres store that counter values are persisted to. Redis stays
+// the source of truth for live values; Postgres holds the durable copy and
+// historical rollups.
+type DB struct {
+	conn   *sql.DB
+	logger logger.Logger
+}
+
+// CounterRow is the persisted value of a single counter.
+type CounterRow struct {
+	Namespace string
+	Name      string
+	Value     int64
+	UpdatedAt time.Time
+}
+
+// RollupRow is the count a counter accumulated within one aggregation
+// bucket.
+type RollupRow struct {
+	Namespace   string
+	Name        string
+	BucketStart time.Time
+	Value       int64
+}
+
+// DeltaRow is a single delta read from an instance's write-ahead log.
+type DeltaRow struct {
+	WALSeq    uint64
+	Namespace string
+	Name      string
+	Delta     int64
+	CreatedAt time.Time
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS counters (
+	namespace  TEXT NOT NULL,
+	name       TEXT NOT NULL,
+	value      BIGINT NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (namespace, name)
+);
+
+CREATE TABLE IF NOT EXISTS counter_rollups (
+	namespace    TEXT NOT NULL,
+	name         TEXT NOT NULL,
+	bucket_start TIMESTAMPTZ NOT NULL,
+	value        BIGINT NOT NULL,
+	PRIMARY KEY (namespace, name, bucket_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_counter_rollups_bucket ON counter_rollups (bucket_start);
+
+CREATE TABLE IF NOT EXISTS wal_checkpoints (
+	wal_id      TEXT PRIMARY KEY,
+	instance_id TEXT NOT NULL,
+	applied_seq BIGINT NOT NULL,
+	updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS namespaces (
+	name        TEXT PRIMARY KEY,
+	read_quota  INTEGER NOT NULL DEFAULT 0,
+	write_quota INTEGER NOT NULL DEFAULT 0,
+	created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+	id         TEXT PRIMARY KEY,
+	namespace  TEXT NOT NULL REFERENCES namespaces (name) ON DELETE CASCADE,
+	key_hash   TEXT NOT NULL UNIQUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_namespace ON api_keys (namespace);
+
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+	id         BIGSERIAL PRIMARY KEY,
+	actor      TEXT NOT NULL,
+	operation  TEXT NOT NULL,
+	reason     TEXT NOT NULL DEFAULT '',
+	status     TEXT NOT NULL DEFAULT 'applied',
+	request    JSONB,
+	before     JSONB,
+	after      JSONB,
+	error      TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE admin_audit_log ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'applied';
+ALTER TABLE admin_audit_log ADD COLUMN IF NOT EXISTS request JSONB;
+ALTER TABLE admin_audit_log ADD COLUMN IF NOT EXISTS error TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created ON admin_audit_log (created_at);
+`
+
+// New opens the database and applies the schema.
+func New(databaseURL string, logger logger.Logger) (*DB, error) {
+	conn, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	conn.SetMaxOpenConns(20)
+	conn.SetMaxIdleConns(10)
+	conn.SetConnMaxLifetime(5 * time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, schema); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to apply schema: %w", err)
+	}
+
+	logger.Info("Connected to database")
+	return &DB{conn: conn, logger: logger}, nil
+}
+
+// Close closes the underlying connection pool.
+func (db *DB) Close() error {
+	return db.conn.Close()
+}
+
+// Ping checks database connectivity.
+func (db *DB) Ping(ctx context.Context) error {
+	return db.conn.PingContext(ctx)
+}
+
+// UpsertCounters writes the latest values for a batch of counters.
+func (db *DB) UpsertCounters(ctx context.Context, rows []CounterRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	args := make([]any, 0, len(rows)*4)
+	b.WriteString("INSERT INTO counters (namespace, name, value, updated_at) VALUES ")
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		n := i * 4
+		fmt.Fprintf(&b, "($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4)
+		args = append(args, row.Namespace, row.Name, row.Value, row.UpdatedAt)
+	}
+	b.WriteString(" ON CONFLICT (namespace, name) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at")
+
+	if _, err := db.conn.ExecContext(ctx, b.String(), args...); err != nil {
+		return fmt.Errorf("failed to upsert counters: %w", err)
+	}
+	return nil
+}
+
+// UpsertRollups writes aggregation buckets. Buckets are rewritten with the
+// latest value so re-running aggregation for the same bucket is harmless.
+func (db *DB) UpsertRollups(ctx context.Context, rows []RollupRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	args := make([]any, 0, len(rows)*4)
+	b.WriteString("INSERT INTO counter_rollups (namespace, name, bucket_start, value) VALUES ")
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		n := i * 4
+		fmt.Fprintf(&b, "($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4)
+		args = append(args, row.Namespace, row.Name, row.BucketStart, row.Value)
+	}
+	b.WriteString(" ON CONFLICT (namespace, name, bucket_start) DO UPDATE SET value = EXCLUDED.value")
+
+	if _, err := db.conn.ExecContext(ctx, b.String(), args...); err != nil {
+		return fmt.Errorf("failed to upsert rollups: %w", err)
+	}
+	return nil
+}
+
+// ApplyDeltas adds a batch of write-ahead log entries to the persisted
+// counter values and records that the log has been consumed through
+// throughSeq, in a single transaction. Entries at or below the log's
+// recorded position are skipped, so replaying a range after a crash never
+// double counts. It returns the number of entries applied.
+func (db *DB) ApplyDeltas(ctx context.Context, walID, instanceID string, throughSeq uint64, rows []DeltaRow) (int, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var applied int64
+	err = tx.QueryRowContext(ctx,
+		"SELECT applied_seq FROM wal_checkpoints WHERE wal_id = $1 FOR UPDATE", walID,
+	).Scan(&applied)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to read wal checkpoint: %w", err)
+	}
+
+	type key struct{ namespace, name string }
+	sums := make(map[key]int64)
+	updated := make(map[key]time.Time)
+	var order []key
+	var count int
+	for _, row := range rows {
+		if int64(row.WALSeq) <= applied {
+			continue
+		}
+		k := key{row.Namespace, row.Name}
+		if _, ok := sums[k]; !ok {
+			order = append(order, k)
+		}
+		sums[k] += row.Delta
+		if row.CreatedAt.After(updated[k]) {
+			updated[k] = row.CreatedAt
+		}
+		count++
+	}
+
+	if len(order) > 0 {
+		var b strings.Builder
+		args := make([]any, 0, len(order)*4)
+		b.WriteString("INSERT INTO counters (namespace, name, value, updated_at) VALUES ")
+		for i, k := range order {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			n := i * 4
+			fmt.Fprintf(&b, "($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4)
+			args = append(args, k.namespace, k.name, sums[k], updated[k])
+		}
+		b.WriteString(" ON CONFLICT (namespace, name) DO UPDATE SET value = counters.value + EXCLUDED.value, updated_at = GREATEST(counters.updated_at, EXCLUDED.updated_at)")
+
+		if _, err := tx.ExecContext(ctx, b.String(), args...); err != nil {
+			return 0, fmt.Errorf("failed to apply deltas: %w", err)
+		}
+	}
+
+	if int64(throughSeq) > applied {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO wal_checkpoints (wal_id, instance_id, applied_seq, updated_at)
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (wal_id) DO UPDATE SET instance_id = EXCLUDED.instance_id, applied_seq = EXCLUDED.applied_seq, updated_at = EXCLUDED.updated_at`,
+			walID, instanceID, int64(throughSeq))
+		if err != nil {
+			return 0, fmt.Errorf("failed to record wal checkpoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit deltas: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteRollupsBefore removes rollups older than the retention cutoff.
+func (db *DB) DeleteRollupsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := db.conn.ExecContext(ctx, "DELETE FROM counter_rollups WHERE bucket_start < $1", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete rollups: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrExists   = errors.New("already exists")
+)
+
+// NamespaceRow is a tenant of the counters service. Quotas are operations
+// per second; zero uses the service default and -1 means unlimited.
+type NamespaceRow struct {
+	Name       string    `json:"name"`
+	ReadQuota  int       `json:"read_quota"`
+	WriteQuota int       `json:"write_quota"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// APIKeyRow is an API key scoped to one namespace. Only a hash of the key
+// is stored.
+type APIKeyRow struct {
+	ID        string     `json:"id"`
+	Namespace string     `json:"namespace"`
+	KeyHash   string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateNamespace registers a new namespace.
+func (db *DB) CreateNamespace(ctx context.Context, ns NamespaceRow) (*NamespaceRow, error) {
+	row := db.conn.QueryRowContext(ctx, `
+		INSERT INTO namespaces (name, read_quota, write_quota)
+		VALUES ($1, $2, $3)
+		RETURNING name, read_quota, write_quota, created_at, updated_at`,
+		ns.Name, ns.ReadQuota, ns.WriteQuota)
+
+	var out NamespaceRow
+	if err := row.Scan(&out.Name, &out.ReadQuota, &out.WriteQuota, &out.CreatedAt, &out.UpdatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrExists
+		}
+		return nil, fmt.Errorf("failed to create namespace: %w", err)
+	}
+	return &out, nil
+}
+
+// UpdateNamespaceQuotas changes a namespace's quotas.
+func (db *DB) UpdateNamespaceQuotas(ctx context.Context, name string, readQuota, writeQuota int) (*NamespaceRow, error) {
+	row := db.conn.QueryRowContext(ctx, `
+		UPDATE namespaces SET read_quota = $2, write_quota = $3, updated_at = NOW()
+		WHERE name = $1
+		RETURNING name, read_quota, write_quota, created_at, updated_at`,
+		name, readQuota, writeQuota)
+
+	var out NamespaceRow
+	if err := row.Scan(&out.Name, &out.ReadQuota, &out.WriteQuota, &out.CreatedAt, &out.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to update namespace: %w", err)
+	}
+	return &out, nil
+}
+
+// ListNamespaces returns every registered namespace.
+func (db *DB) ListNamespaces(ctx context.Context) ([]NamespaceRow, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT name, read_quota, write_quota, created_at, updated_at
+		FROM namespaces ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	defer rows.Close()
+
+	var out []NamespaceRow
+	for rows.Next() {
+		var ns NamespaceRow
+		if err := rows.Scan(&ns.Name, &ns.ReadQuota, &ns.WriteQuota, &ns.CreatedAt, &ns.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan namespace: %w", err)
+		}
+		out = append(out, ns)
+	}
+	return out, rows.Err()
+}
+
+// CreateAPIKey stores a new key for a namespace.
+func (db *DB) CreateAPIKey(ctx context.Context, key APIKeyRow) (*APIKeyRow, error) {
+	row := db.conn.QueryRowContext(ctx, `
+		INSERT INTO api_keys (id, namespace, key_hash)
+		VALUES ($1, $2, $3)
+		RETURNING created_at`,
+		key.ID, key.Namespace, key.KeyHash)
+
+	if err := row.Scan(&key.CreatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+	return &key, nil
+}
+
+// RevokeAPIKey marks a key as revoked. Revoking an already revoked key is
+// a no-op.
+func (db *DB) RevokeAPIKey(ctx context.Context, namespace, id string) error {
+	res, err := db.conn.ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE namespace = $1 AND id = $2`,
+		namespace, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListAPIKeys returns the keys of one namespace, or of every namespace
+// when namespace is empty.
+func (db *DB) ListAPIKeys(ctx context.Context, namespace string) ([]APIKeyRow, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT id, namespace, key_hash, created_at, revoked_at
+		FROM api_keys WHERE $1 = '' OR namespace = $1
+		ORDER BY created_at`,
+		namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var out []APIKeyRow
+	for rows.Next() {
+		var k APIKeyRow
+		if err := rows.Scan(&k.ID, &k.Namespace, &k.KeyHash, &k.CreatedAt, &k.RevokedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,189 @@
+// Package exporter publishes selected business counters as Prometheus
+// gauges on the service's /metrics endpoint, so dashboards and alerts can
+// use them without a custom scraper.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/suuupra/counters/internal/counter"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+// queryChunk bounds how many exact counters are read per Query call.
+const queryChunk = 100
+
+// Selector picks counters to export: a single counter, or every counter in
+// a namespace whose name matches a glob pattern.
+type Selector struct {
+	Namespace string
+	Name      string
+	Pattern   bool
+}
+
+// ParseSelectors parses "namespace:name" entries. Names containing * or ?
+// are treated as patterns. Names are checked the way the counter API checks
+// them, so a typo fails at startup rather than on every refresh.
+func ParseSelectors(entries []string) ([]Selector, error) {
+	selectors := make([]Selector, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		ns, name, ok := strings.Cut(entry, ":")
+		if !ok || ns == "" || name == "" {
+			return nil, fmt.Errorf("invalid exported counter %q, want namespace:name", entry)
+		}
+		pattern := strings.ContainsAny(name, "*?")
+		if !counter.ValidName(ns) {
+			return nil, fmt.Errorf("invalid exported counter %q: %w", entry, counter.ErrInvalidName)
+		}
+		if pattern && !counter.ValidPattern(name) {
+			return nil, fmt.Errorf("invalid exported counter %q: %w", entry, counter.ErrInvalidPattern)
+		}
+		if !pattern && !counter.ValidName(name) {
+			return nil, fmt.Errorf("invalid exported counter %q: %w", entry, counter.ErrInvalidName)
+		}
+		selectors = append(selectors, Selector{
+			Namespace: ns,
+			Name:      name,
+			Pattern:   pattern,
+		})
+	}
+	return selectors, nil
+}
+
+// Exporter is a Prometheus collector serving the values of the selected
+// counters as of the last refresh. Scrapes never touch Redis; values are
+// refreshed on a fixed interval instead, so a scrape storm cannot load the
+// cluster and pattern scans run at a predictable rate.
+type Exporter struct {
+	counters  *counter.Service
+	selectors []Selector
+	logger    logger.Logger
+
+	value       *prometheus.Desc
+	lastRefresh *prometheus.Desc
+
+	mu        sync.RWMutex
+	values    []counter.Counter
+	refreshed time.Time
+}
+
+// New creates an exporter for the given selectors.
+func New(counters *counter.Service, selectors []Selector, logger logger.Logger) *Exporter {
+	return &Exporter{
+		counters:  counters,
+		selectors: selectors,
+		logger:    logger,
+		value: prometheus.NewDesc(
+			"counters_exported_value",
+			"Current value of a counter selected for export",
+			[]string{"namespace", "name"}, nil,
+		),
+		lastRefresh: prometheus.NewDesc(
+			"counters_exporter_last_refresh_timestamp_seconds",
+			"Unix time of the last successful refresh of exported counters",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.value
+	ch <- e.lastRefresh
+}
+
+// Collect implements prometheus.Collector.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, c := range e.values {
+		ch <- prometheus.MustNewConstMetric(e.value, prometheus.GaugeValue, float64(c.Value), c.Namespace, c.Name)
+	}
+	if !e.refreshed.IsZero() {
+		ch <- prometheus.MustNewConstMetric(e.lastRefresh, prometheus.GaugeValue, float64(e.refreshed.Unix()))
+	}
+}
+
+// StartRefreshWorker refreshes exported values every interval until ctx is
+// cancelled.
+func (e *Exporter) StartRefreshWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	e.logger.Info("Counter exporter started", "selectors", len(e.selectors), "interval", interval)
+	for {
+		if err := e.Refresh(ctx); err != nil && ctx.Err() == nil {
+			e.logger.Error("Failed to refresh exported counters", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh reads the current value of every selected counter. Counters that
+// no longer match a pattern drop out of the exported set.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	var (
+		exact  []counter.Ref
+		values []counter.Counter
+	)
+	seen := make(map[counter.Ref]bool)
+
+	for _, sel := range e.selectors {
+		if !sel.Pattern {
+			exact = append(exact, counter.Ref{Namespace: sel.Namespace, Name: sel.Name})
+			continue
+		}
+		matched, truncated, err := e.counters.QueryPattern(ctx, sel.Namespace, sel.Name, 0)
+		if err != nil {
+			return fmt.Errorf("%s:%s: %w", sel.Namespace, sel.Name, err)
+		}
+		if truncated {
+			// Matches come back in name order, so the exported set is
+			// stable from one refresh to the next
+			e.logger.Warn("Exported counter pattern matched too many counters, exporting the first by name",
+				"namespace", sel.Namespace, "pattern", sel.Name, "exported", len(matched))
+		}
+		for _, c := range matched {
+			if !seen[c.Ref] {
+				seen[c.Ref] = true
+				values = append(values, c)
+			}
+		}
+	}
+
+	for start := 0; start < len(exact); start += queryChunk {
+		end := min(start+queryChunk, len(exact))
+		read, err := e.counters.Query(ctx, exact[start:end])
+		if err != nil {
+			return err
+		}
+		for _, c := range read {
+			if !seen[c.Ref] {
+				seen[c.Ref] = true
+				values = append(values, c)
+			}
+		}
+	}
+
+	e.mu.Lock()
+	e.values = values
+	e.refreshed = time.Now()
+	e.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,41 @@
+package exporter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/suuupra/counters/internal/counter"
+)
+
+func TestParseSelectors(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    Selector
+		wantErr error
+	}{
+		{"payments:txn.success", Selector{Namespace: "payments", Name: "txn.success"}, nil},
+		{" live:viewers.* ", Selector{Namespace: "live", Name: "viewers.*", Pattern: true}, nil},
+		{"live:room-?", Selector{Namespace: "live", Name: "room-?", Pattern: true}, nil},
+		{"pay ments:txn", Selector{}, counter.ErrInvalidName},
+		{"payments:txn/success", Selector{}, counter.ErrInvalidName},
+		{"payments:txn[0-9]*", Selector{}, counter.ErrInvalidPattern},
+	}
+	for _, tt := range tests {
+		got, err := ParseSelectors([]string{tt.entry})
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%q: err = %v, want %v", tt.entry, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%q: got %+v, %v, want %+v", tt.entry, got, err, tt.want)
+		}
+	}
+
+	for _, entry := range []string{"payments", ":txn", "payments:"} {
+		if _, err := ParseSelectors([]string{entry}); err == nil {
+			t.Errorf("%q: parsed without namespace:name", entry)
+		}
+	}
+}
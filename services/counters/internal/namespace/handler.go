@@ -0,0 +1,292 @@
+package namespace
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/suuupra/counters/internal/counter"
+	"github.com/suuupra/counters/internal/database"
+	"github.com/suuupra/counters/internal/ratelimit"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+const maxUsageDays = 31
+
+// CreateNamespaceRequest is the body of a namespace registration.
+type CreateNamespaceRequest struct {
+	Name       string `json:"name" binding:"required"`
+	ReadQuota  int    `json:"read_quota"`
+	WriteQuota int    `json:"write_quota"`
+}
+
+// UpdateQuotasRequest is the body of a quota change.
+type UpdateQuotasRequest struct {
+	ReadQuota  int `json:"read_quota"`
+	WriteQuota int `json:"write_quota"`
+}
+
+// Handler serves namespace management and usage reporting.
+type Handler struct {
+	registry   *Registry
+	db         *database.DB
+	adminToken string
+	logger     logger.Logger
+}
+
+// NewHandler creates a namespace handler. Management endpoints require
+// adminToken; they are disabled when it is empty.
+func NewHandler(registry *Registry, db *database.DB, adminToken string, logger logger.Logger) *Handler {
+	return &Handler{
+		registry:   registry,
+		db:         db,
+		adminToken: adminToken,
+		logger:     logger,
+	}
+}
+
+// SetupRoutes registers the namespace endpoints.
+func (h *Handler) SetupRoutes(router *gin.Engine) {
+	router.GET("/api/v1/namespaces/:namespace/usage", h.GetUsage)
+
+	admin := router.Group("/api/v1/admin/namespaces", RequireAdmin(h.adminToken))
+	admin.GET("", h.ListNamespaces)
+	admin.POST("", h.CreateNamespace)
+	admin.PUT("/:namespace/quotas", h.UpdateQuotas)
+	admin.GET("/:namespace/keys", h.ListKeys)
+	admin.POST("/:namespace/keys", h.CreateKey)
+	admin.DELETE("/:namespace/keys/:id", h.RevokeKey)
+}
+
+// RequireAdmin rejects requests that do not carry the admin token as a
+// bearer token.
+func RequireAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin api is disabled"})
+			return
+		}
+		got := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// APIKey extracts the caller's API key from X-API-Key or a bearer token.
+func APIKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+func validQuota(q int) bool {
+	return q >= 0 || q == Unlimited
+}
+
+// Authorizer adapts Authorize for handlers in other packages: it checks the
+// request's API key and quota for op, writing the error response if the
+// request is refused.
+func (r *Registry) Authorizer(op Op) func(c *gin.Context, namespaces ...string) bool {
+	return func(c *gin.Context, namespaces ...string) bool {
+		if err := r.Authorize(c.Request.Context(), APIKey(c.Request), op, namespaces...); err != nil {
+			WriteError(c, err)
+			return false
+		}
+		return true
+	}
+}
+
+// GetUsage reports daily usage for the caller's namespace.
+func (h *Handler) GetUsage(c *gin.Context) {
+	ns := c.Param("namespace")
+	if err := h.registry.Authorize(c.Request.Context(), APIKey(c.Request), OpRead, ns); err != nil {
+		WriteError(c, err)
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if days <= 0 || days > maxUsageDays {
+		days = 7
+	}
+
+	usage, err := h.registry.Usage(c.Request.Context(), ns, days)
+	if err != nil {
+		h.logger.Error("Failed to read namespace usage", "namespace", ns, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"namespace":   ns,
+		"usage":       usage,
+		"read_quota":  h.registry.Quota(ns, OpRead),
+		"write_quota": h.registry.Quota(ns, OpWrite),
+	})
+}
+
+// ListNamespaces returns every registered namespace.
+func (h *Handler) ListNamespaces(c *gin.Context) {
+	namespaces, err := h.db.ListNamespaces(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list namespaces", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list namespaces"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"namespaces": namespaces})
+}
+
+// CreateNamespace registers a namespace.
+func (h *Handler) CreateNamespace(c *gin.Context) {
+	var req CreateNamespaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
+		return
+	}
+	if !counter.ValidName(req.Name) || !validQuota(req.ReadQuota) || !validQuota(req.WriteQuota) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": "name must match [A-Za-z0-9_.-]{1,128} and quotas must be positive, 0 for the default or -1 for unlimited"})
+		return
+	}
+	if ratelimit.IsReserved(req.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": ratelimit.ErrReservedNamespace.Error()})
+		return
+	}
+
+	ns, err := h.db.CreateNamespace(c.Request.Context(), database.NamespaceRow{
+		Name:       req.Name,
+		ReadQuota:  req.ReadQuota,
+		WriteQuota: req.WriteQuota,
+	})
+	if errors.Is(err, database.ErrExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": "namespace already exists"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create namespace", "namespace", req.Name, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create namespace"})
+		return
+	}
+
+	h.refresh(c)
+	h.logger.Info("Namespace created", "namespace", ns.Name, "read_quota", ns.ReadQuota, "write_quota", ns.WriteQuota)
+	c.JSON(http.StatusCreated, ns)
+}
+
+// UpdateQuotas changes a namespace's quotas.
+func (h *Handler) UpdateQuotas(c *gin.Context) {
+	var req UpdateQuotasRequest
+	if err := c.ShouldBindJSON(&req); err != nil || !validQuota(req.ReadQuota) || !validQuota(req.WriteQuota) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": "quotas must be positive, 0 for the default or -1 for unlimited"})
+		return
+	}
+
+	ns, err := h.db.UpdateNamespaceQuotas(c.Request.Context(), c.Param("namespace"), req.ReadQuota, req.WriteQuota)
+	if errors.Is(err, database.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "namespace not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update quotas", "namespace", c.Param("namespace"), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update quotas"})
+		return
+	}
+
+	h.refresh(c)
+	h.logger.Info("Namespace quotas updated", "namespace", ns.Name, "read_quota", ns.ReadQuota, "write_quota", ns.WriteQuota)
+	c.JSON(http.StatusOK, ns)
+}
+
+// ListKeys returns a namespace's API keys, without the secrets.
+func (h *Handler) ListKeys(c *gin.Context) {
+	keys, err := h.db.ListAPIKeys(c.Request.Context(), c.Param("namespace"))
+	if err != nil {
+		h.logger.Error("Failed to list api keys", "namespace", c.Param("namespace"), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list api keys"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+// CreateKey issues a new API key. The key is only ever returned here.
+func (h *Handler) CreateKey(c *gin.Context) {
+	key, id, hash, err := GenerateKey()
+	if err != nil {
+		h.logger.Error("Failed to generate api key", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate api key"})
+		return
+	}
+
+	row, err := h.db.CreateAPIKey(c.Request.Context(), database.APIKeyRow{
+		ID:        id,
+		Namespace: c.Param("namespace"),
+		KeyHash:   hash,
+	})
+	if errors.Is(err, database.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "namespace not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create api key", "namespace", c.Param("namespace"), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create api key"})
+		return
+	}
+
+	h.refresh(c)
+	h.logger.Info("API key created", "namespace", row.Namespace, "key_id", row.ID)
+	c.JSON(http.StatusCreated, gin.H{
+		"id":         row.ID,
+		"namespace":  row.Namespace,
+		"key":        key,
+		"created_at": row.CreatedAt,
+	})
+}
+
+// RevokeKey revokes an API key.
+func (h *Handler) RevokeKey(c *gin.Context) {
+	err := h.db.RevokeAPIKey(c.Request.Context(), c.Param("namespace"), c.Param("id"))
+	if errors.Is(err, database.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "api key not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to revoke api key", "namespace", c.Param("namespace"), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke api key"})
+		return
+	}
+
+	h.refresh(c)
+	h.logger.Info("API key revoked", "namespace", c.Param("namespace"), "key_id", c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+// refresh reloads the registry so changes apply on this instance right
+// away; other instances pick them up on their next refresh.
+func (h *Handler) refresh(c *gin.Context) {
+	if err := h.registry.Refresh(c.Request.Context()); err != nil {
+		h.logger.Warn("Failed to refresh namespace registry", "error", err)
+	}
+}
+
+// WriteError writes the HTTP response for an Authorize error.
+func WriteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrQuotaUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrQuotaExceeded):
+		c.Header("Retry-After", strconv.Itoa(int(quotaWindow.Seconds())))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,266 @@
+// Package namespace isolates tenants of the counters service. Each calling
+// service owns a namespace, authenticates with API keys scoped to it, and
+// is held to per-namespace read and write quotas so one misbehaving caller
+// cannot exhaust the shared Redis cluster.
+package namespace
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/suuupra/counters/internal/database"
+	"github.com/suuupra/counters/internal/ratelimit"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("missing or invalid api key")
+	ErrForbidden        = errors.New("api key is not allowed to access this namespace")
+	ErrQuotaExceeded    = errors.New("namespace quota exceeded")
+	ErrQuotaUnavailable = errors.New("namespace quota could not be checked")
+)
+
+// Op is the kind of access a request needs. Reads and writes have separate
+// quotas.
+type Op string
+
+const (
+	OpRead  Op = "read"
+	OpWrite Op = "write"
+)
+
+// Unlimited is the quota of a namespace exempt from rate limiting. A quota
+// of zero means the service default.
+const Unlimited = -1
+
+const (
+	// quotaNamespace is reserved, so the public rate limit endpoint cannot
+	// reach tenants' quota buckets.
+	quotaNamespace = ratelimit.ReservedPrefix + "quota"
+	quotaWindow    = time.Second
+	usageTTL       = 35 * 24 * time.Hour
+	keyPrefix      = "ctr_"
+)
+
+// Registry authenticates API keys and enforces quotas. Namespaces and key
+// hashes are cached in memory and refreshed periodically, so revocations
+// and quota changes take effect within one refresh interval.
+type Registry struct {
+	db      *database.DB
+	rdb     redis.UniversalClient
+	limiter *ratelimit.Limiter
+	logger  logger.Logger
+	opts    Options
+
+	mu         sync.RWMutex
+	keys       map[string]string // key hash -> namespace
+	namespaces map[string]database.NamespaceRow
+}
+
+// Options controls how a registry authenticates and enforces quotas.
+type Options struct {
+	// AuthEnabled requires an API key scoped to the namespace. Without it,
+	// quotas and usage reporting still apply.
+	AuthEnabled bool
+	// DefaultReadQuota and DefaultWriteQuota apply to namespaces whose quota
+	// is zero, including namespaces that were never registered. Unlimited
+	// disables the default.
+	DefaultReadQuota  int
+	DefaultWriteQuota int
+	// QuotaFailOpen lets requests through when the quota cannot be checked;
+	// otherwise they fail with ErrQuotaUnavailable.
+	QuotaFailOpen bool
+}
+
+// NewRegistry creates a registry.
+func NewRegistry(db *database.DB, rdb redis.UniversalClient, limiter *ratelimit.Limiter, opts Options, logger logger.Logger) *Registry {
+	return &Registry{
+		db:         db,
+		rdb:        rdb,
+		limiter:    limiter,
+		logger:     logger,
+		opts:       opts,
+		keys:       make(map[string]string),
+		namespaces: make(map[string]database.NamespaceRow),
+	}
+}
+
+// Refresh reloads namespaces and active keys from Postgres.
+func (r *Registry) Refresh(ctx context.Context) error {
+	nsRows, err := r.db.ListNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	keyRows, err := r.db.ListAPIKeys(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	namespaces := make(map[string]database.NamespaceRow, len(nsRows))
+	for _, ns := range nsRows {
+		namespaces[ns.Name] = ns
+	}
+	keys := make(map[string]string, len(keyRows))
+	for _, k := range keyRows {
+		if k.RevokedAt == nil {
+			keys[k.KeyHash] = k.Namespace
+		}
+	}
+
+	r.mu.Lock()
+	r.namespaces = namespaces
+	r.keys = keys
+	r.mu.Unlock()
+	return nil
+}
+
+// StartRefreshWorker refreshes the cache every interval until ctx is
+// cancelled.
+func (r *Registry) StartRefreshWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil && ctx.Err() == nil {
+				r.logger.Error("Failed to refresh namespace registry", "error", err)
+			}
+		}
+	}
+}
+
+// Authorize checks that apiKey may perform op on every namespace listed,
+// charges each namespace's quota, and records usage. Namespaces may repeat;
+// each occurrence costs one operation.
+func (r *Registry) Authorize(ctx context.Context, apiKey string, op Op, namespaces ...string) error {
+	r.mu.RLock()
+	owner, known := r.keys[hashKey(apiKey)]
+	r.mu.RUnlock()
+	if apiKey == "" {
+		known = false
+	}
+	return r.authorize(ctx, owner, known, op, namespaces)
+}
+
+// authorize is Authorize for a caller already resolved to the namespace
+// that owns its credentials.
+func (r *Registry) authorize(ctx context.Context, owner string, known bool, op Op, namespaces []string) error {
+	costs := make(map[string]int, 1)
+	for _, ns := range namespaces {
+		costs[ns]++
+	}
+
+	r.mu.RLock()
+	quotas := make(map[string]int, len(costs))
+	for ns := range costs {
+		quotas[ns] = r.quotaFor(r.namespaces[ns], op)
+	}
+	r.mu.RUnlock()
+
+	if r.opts.AuthEnabled {
+		if !known {
+			return ErrUnauthenticated
+		}
+		for ns := range costs {
+			if ns != owner {
+				return fmt.Errorf("%w: %s", ErrForbidden, ns)
+			}
+		}
+	}
+
+	for ns, cost := range costs {
+		if err := r.charge(ctx, ns, op, quotas[ns], cost); err != nil {
+			return err
+		}
+	}
+
+	r.recordUsage(ctx, op, costs)
+	return nil
+}
+
+// charge takes cost operations from a namespace's per-second quota. If
+// Redis is unreachable the request fails, unless QuotaFailOpen is set.
+func (r *Registry) charge(ctx context.Context, ns string, op Op, quota, cost int) error {
+	if quota == Unlimited {
+		return nil
+	}
+	if cost > quota {
+		r.recordThrottled(ctx, ns, op)
+		return fmt.Errorf("%w: %s %s", ErrQuotaExceeded, ns, op)
+	}
+
+	res, err := r.limiter.Check(ctx, ratelimit.Request{
+		Namespace: quotaNamespace,
+		Key:       ns + ":" + string(op),
+		Limit:     int64(quota),
+		Window:    quotaWindow,
+		Algorithm: ratelimit.SlidingWindow,
+		Cost:      int64(cost),
+	})
+	if err != nil {
+		if r.opts.QuotaFailOpen {
+			r.logger.Warn("Quota check failed, allowing request", "namespace", ns, "error", err)
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrQuotaUnavailable, err)
+	}
+	if !res.Allowed {
+		r.recordThrottled(ctx, ns, op)
+		return fmt.Errorf("%w: %s %s", ErrQuotaExceeded, ns, op)
+	}
+	return nil
+}
+
+// Namespace returns a cached namespace.
+func (r *Registry) Namespace(name string) (database.NamespaceRow, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ns, ok := r.namespaces[name]
+	return ns, ok
+}
+
+// Quota returns the effective quota of a namespace for op, resolving zero
+// to the service default.
+func (r *Registry) Quota(name string, op Op) int {
+	ns, _ := r.Namespace(name)
+	return r.quotaFor(ns, op)
+}
+
+func (r *Registry) quotaFor(ns database.NamespaceRow, op Op) int {
+	quota, fallback := ns.ReadQuota, r.opts.DefaultReadQuota
+	if op == OpWrite {
+		quota, fallback = ns.WriteQuota, r.opts.DefaultWriteQuota
+	}
+	if quota == 0 {
+		return fallback
+	}
+	return quota
+}
+
+// GenerateKey returns a new random API key, its ID, and the hash to store.
+// The ID is listed and logged, so it is drawn separately and reveals nothing
+// about the key.
+func GenerateKey() (key, id, hash string, err error) {
+	buf := make([]byte, 32+6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+	key = keyPrefix + hex.EncodeToString(buf[:32])
+	return key, hex.EncodeToString(buf[32:]), hashKey(key), nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
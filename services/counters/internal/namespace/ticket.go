@@ -0,0 +1,61 @@
+package namespace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// TicketTTL is how long a stream ticket can be redeemed.
+const TicketTTL = 30 * time.Second
+
+func ticketKey(ticket string) string {
+	return "stream_ticket:" + hashKey(ticket)
+}
+
+// IssueTicket authenticates apiKey and returns a ticket that authorizes one
+// stream as the key's namespace.
+//
+// Browsers cannot set headers on EventSource or WebSocket requests. Rather
+// than putting the API key in the URL, where access and proxy logs would
+// keep it, a client exchanges the key for a ticket and opens the stream
+// with that. Tickets are single-use and short-lived, so one that ends up in
+// a log is useless.
+func (r *Registry) IssueTicket(ctx context.Context, apiKey string) (string, time.Time, error) {
+	r.mu.RLock()
+	owner, known := r.keys[hashKey(apiKey)]
+	r.mu.RUnlock()
+	if r.opts.AuthEnabled && (apiKey == "" || !known) {
+		return "", time.Time{}, ErrUnauthenticated
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+	ticket := hex.EncodeToString(buf)
+	if err := r.rdb.Set(ctx, ticketKey(ticket), owner, TicketTTL).Err(); err != nil {
+		return "", time.Time{}, err
+	}
+	return ticket, time.Now().Add(TicketTTL), nil
+}
+
+// AuthorizeTicket redeems ticket and authorizes op on namespaces as the
+// namespace it was issued to, like Authorize.
+func (r *Registry) AuthorizeTicket(ctx context.Context, ticket string, op Op, namespaces ...string) error {
+	if ticket == "" {
+		return r.authorize(ctx, "", false, op, namespaces)
+	}
+	owner, err := r.rdb.GetDel(ctx, ticketKey(ticket)).Result()
+	if errors.Is(err, redis.Nil) {
+		return r.authorize(ctx, "", false, op, namespaces)
+	}
+	if err != nil {
+		return err
+	}
+	return r.authorize(ctx, owner, true, op, namespaces)
+}
@@ -0,0 +1,82 @@
+package namespace
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Usage is one day of a namespace's activity.
+type Usage struct {
+	Date      string `json:"date"`
+	Reads     int64  `json:"reads"`
+	Writes    int64  `json:"writes"`
+	Throttled int64  `json:"throttled"`
+}
+
+// usageKey holds a namespace's daily counts as a hash of reads, writes and
+// throttled. Days expire after usageTTL.
+func usageKey(ns string, day time.Time) string {
+	return "usage:{" + ns + "}:" + day.UTC().Format("20060102")
+}
+
+func (r *Registry) recordUsage(ctx context.Context, op Op, costs map[string]int) {
+	now := time.Now()
+	field := string(op) + "s"
+
+	pipe := r.rdb.Pipeline()
+	for ns, cost := range costs {
+		key := usageKey(ns, now)
+		pipe.HIncrBy(ctx, key, field, int64(cost))
+		pipe.Expire(ctx, key, usageTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && ctx.Err() == nil {
+		r.logger.Warn("Failed to record namespace usage", "error", err)
+	}
+}
+
+func (r *Registry) recordThrottled(ctx context.Context, ns string, op Op) {
+	key := usageKey(ns, time.Now())
+
+	pipe := r.rdb.Pipeline()
+	pipe.HIncrBy(ctx, key, "throttled", 1)
+	pipe.Expire(ctx, key, usageTTL)
+	if _, err := pipe.Exec(ctx); err != nil && ctx.Err() == nil {
+		r.logger.Warn("Failed to record throttled request", "namespace", ns, "op", op, "error", err)
+	}
+}
+
+// Usage returns daily usage for a namespace, most recent day first.
+func (r *Registry) Usage(ctx context.Context, ns string, days int) ([]Usage, error) {
+	now := time.Now().UTC()
+
+	// Every day of a namespace shares its hash tag, so this pipeline stays
+	// on one cluster node.
+	pipe := r.rdb.Pipeline()
+	cmds := make([]*redis.StringStringMapCmd, days)
+	for i := range cmds {
+		cmds[i] = pipe.HGetAll(ctx, usageKey(ns, now.AddDate(0, 0, -i)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	out := make([]Usage, days)
+	for i, cmd := range cmds {
+		vals := cmd.Val()
+		out[i] = Usage{
+			Date:      now.AddDate(0, 0, -i).Format("2006-01-02"),
+			Reads:     atoi(vals["reads"]),
+			Writes:    atoi(vals["writes"]),
+			Throttled: atoi(vals["throttled"]),
+		}
+	}
+	return out, nil
+}
+
+func atoi(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+// CheckRequest is the JSON body accepted by POST /api/v1/ratelimit/check.
+type CheckRequest struct {
+	Namespace string    `json:"namespace" binding:"required"`
+	Key       string    `json:"key" binding:"required"`
+	Limit     int64     `json:"limit" binding:"required"`
+	Window    string    `json:"window" binding:"required"`
+	Algorithm Algorithm `json:"algorithm"`
+	Cost      int64     `json:"cost"`
+}
+
+// CheckResponse mirrors Result with the retry hint in whole seconds, matching
+// the Retry-After header.
+type CheckResponse struct {
+	*Result
+	RetryAfter int64 `json:"retry_after_seconds,omitempty"`
+}
+
+// AuthorizeFunc checks that the caller may use the given namespaces. When it
+// refuses the request it writes the error response and returns false.
+type AuthorizeFunc func(c *gin.Context, namespaces ...string) bool
+
+// Handler serves the rate limit HTTP API.
+type Handler struct {
+	limiter   *Limiter
+	authorize AuthorizeFunc
+	logger    logger.Logger
+}
+
+// NewHandler creates a rate limit handler. Callers authenticate with an API
+// key for the namespace they check, like every other counters endpoint.
+func NewHandler(limiter *Limiter, authorize AuthorizeFunc, logger logger.Logger) *Handler {
+	return &Handler{
+		limiter:   limiter,
+		authorize: authorize,
+		logger:    logger,
+	}
+}
+
+// SetupRoutes registers the rate limit endpoints.
+func (h *Handler) SetupRoutes(router *gin.Engine) {
+	v1 := router.Group("/api/v1/ratelimit")
+	v1.POST("/check", h.Check)
+}
+
+// Check consumes from a rate limit bucket. Denied calls are still answered
+// with 200 and allowed=false; callers decide how to surface the rejection.
+func (h *Handler) Check(c *gin.Context) {
+	var body CheckRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
+		return
+	}
+
+	if IsReserved(body.Namespace) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": ErrReservedNamespace.Error()})
+		return
+	}
+	if !h.authorize(c, body.Namespace) {
+		return
+	}
+
+	window, err := time.ParseDuration(body.Window)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": "window must be a duration such as 1s or 1m"})
+		return
+	}
+
+	req := Request{
+		Namespace: body.Namespace,
+		Key:       body.Key,
+		Limit:     body.Limit,
+		Window:    window,
+		Algorithm: body.Algorithm,
+		Cost:      body.Cost,
+	}
+	result, err := h.limiter.Check(c.Request.Context(), req)
+	if err != nil {
+		if isValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
+			return
+		}
+		h.logger.Error("Rate limit check failed", "error", err, "namespace", req.Namespace, "key", req.Key)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "rate limit check failed"})
+		return
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+	c.Header("X-RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+	resp := CheckResponse{Result: result}
+	if !result.Allowed {
+		resp.RetryAfter = int64(math.Ceil(result.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.FormatInt(resp.RetryAfter, 10))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func isValidationError(err error) bool {
+	return errors.Is(err, ErrInvalidKey) ||
+		errors.Is(err, ErrInvalidLimit) ||
+		errors.Is(err, ErrInvalidWindow) ||
+		errors.Is(err, ErrInvalidCost) ||
+		errors.Is(err, ErrUnknownAlgorithm)
+}
@@ -0,0 +1,247 @@
+// Package ratelimit exposes the counters Redis cluster as a shared rate
+// limiting backend so that upi-psp, payments and mass-live do not each
+// carry their own implementation.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Algorithm selects how requests are counted against a limit.
+type Algorithm string
+
+const (
+	// FixedWindow counts requests in aligned windows. Cheap, but allows up to
+	// twice the limit across a window boundary.
+	FixedWindow Algorithm = "fixed_window"
+	// SlidingWindow weights the previous window by how much of it still
+	// overlaps the current one, smoothing out boundary bursts.
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+const (
+	keyPrefix = "rl"
+
+	// ReservedPrefix marks namespaces the counters service uses for itself,
+	// such as tenant quotas. The public check endpoint refuses them and
+	// tenants cannot register them, so their buckets are unreachable from
+	// outside.
+	ReservedPrefix = "_"
+
+	// MaxWindow bounds how long a caller can ask us to remember a key.
+	MaxWindow = 24 * time.Hour
+)
+
+var (
+	ErrInvalidKey        = errors.New("namespace and key are required and must not contain braces")
+	ErrReservedNamespace = errors.New("namespaces starting with " + ReservedPrefix + " are reserved")
+	ErrInvalidLimit      = errors.New("limit must be greater than zero")
+	ErrInvalidWindow     = errors.New("window must be between 1ms and 24h")
+	ErrInvalidCost       = errors.New("cost must not be negative or exceed the limit")
+	ErrUnknownAlgorithm  = errors.New("unknown rate limit algorithm")
+)
+
+// fixedWindowScript increments the counter for the current window and rolls
+// the increment back if it would exceed the limit, so rejected calls do not
+// eat into the caller's budget.
+//
+// KEYS[1] window key
+// ARGV[1] limit, ARGV[2] cost, ARGV[3] window in ms
+// Returns {allowed, count, ttl_ms}
+var fixedWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local cost = tonumber(ARGV[2])
+local window = tonumber(ARGV[3])
+
+local count = redis.call('INCRBY', KEYS[1], cost)
+if count == cost then
+	redis.call('PEXPIRE', KEYS[1], window)
+end
+
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], window)
+	ttl = window
+end
+
+if count > limit then
+	count = redis.call('DECRBY', KEYS[1], cost)
+	return {0, count, ttl}
+end
+return {1, count, ttl}
+`)
+
+// slidingWindowScript approximates a sliding log with two fixed windows: the
+// previous window's count is weighted by the fraction of it that still
+// overlaps the sliding window ending now.
+//
+// KEYS[1] current window key, KEYS[2] previous window key
+// ARGV[1] limit, ARGV[2] cost, ARGV[3] window in ms, ARGV[4] ms elapsed in current window
+// Returns {allowed, estimated_count}
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local cost = tonumber(ARGV[2])
+local window = tonumber(ARGV[3])
+local elapsed = tonumber(ARGV[4])
+
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+local weight = (window - elapsed) / window
+local estimated = math.floor(previous * weight + current)
+
+if estimated + cost > limit then
+	return {0, estimated}
+end
+
+redis.call('INCRBY', KEYS[1], cost)
+redis.call('PEXPIRE', KEYS[1], window * 2)
+return {1, estimated + cost}
+`)
+
+// Request describes a single rate limit check.
+type Request struct {
+	Namespace string
+	Key       string
+	Limit     int64
+	Window    time.Duration
+	Algorithm Algorithm
+	// Cost is the number of units consumed by this call. Defaults to 1.
+	Cost int64
+}
+
+// Result is the outcome of a rate limit check.
+type Result struct {
+	Allowed    bool          `json:"allowed"`
+	Algorithm  Algorithm     `json:"algorithm"`
+	Limit      int64         `json:"limit"`
+	Remaining  int64         `json:"remaining"`
+	ResetAt    time.Time     `json:"reset_at"`
+	RetryAfter time.Duration `json:"-"`
+}
+
+// Limiter runs rate limit checks atomically in Redis.
+type Limiter struct {
+	rdb redis.UniversalClient
+	now func() time.Time
+}
+
+// New creates a limiter backed by the given Redis client.
+func New(rdb redis.UniversalClient) *Limiter {
+	return &Limiter{rdb: rdb, now: time.Now}
+}
+
+// Check consumes req.Cost units from the bucket identified by namespace and
+// key and reports whether the call is within the limit.
+func (l *Limiter) Check(ctx context.Context, req Request) (*Result, error) {
+	if err := req.normalize(); err != nil {
+		return nil, err
+	}
+
+	switch req.Algorithm {
+	case FixedWindow:
+		return l.checkFixed(ctx, req)
+	case SlidingWindow:
+		return l.checkSliding(ctx, req)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, req.Algorithm)
+	}
+}
+
+func (l *Limiter) checkFixed(ctx context.Context, req Request) (*Result, error) {
+	now := l.now()
+	windowMs := req.Window.Milliseconds()
+	start := now.UnixMilli() / windowMs * windowMs
+
+	key := windowKey(req, start)
+	vals, err := fixedWindowScript.Run(ctx, l.rdb, []string{key}, req.Limit, req.Cost, windowMs).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("fixed window check: %w", err)
+	}
+
+	allowed, count, ttl := vals[0] == 1, vals[1], vals[2]
+	result := &Result{
+		Allowed:   allowed,
+		Algorithm: FixedWindow,
+		Limit:     req.Limit,
+		Remaining: remaining(req.Limit, count),
+		ResetAt:   now.Add(time.Duration(ttl) * time.Millisecond),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration(ttl) * time.Millisecond
+	}
+	return result, nil
+}
+
+func (l *Limiter) checkSliding(ctx context.Context, req Request) (*Result, error) {
+	now := l.now()
+	windowMs := req.Window.Milliseconds()
+	nowMs := now.UnixMilli()
+	start := nowMs / windowMs * windowMs
+	elapsed := nowMs - start
+
+	keys := []string{windowKey(req, start), windowKey(req, start-windowMs)}
+	vals, err := slidingWindowScript.Run(ctx, l.rdb, keys, req.Limit, req.Cost, windowMs, elapsed).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("sliding window check: %w", err)
+	}
+
+	allowed, estimated := vals[0] == 1, vals[1]
+	resetAt := time.UnixMilli(start + windowMs)
+	result := &Result{
+		Allowed:   allowed,
+		Algorithm: SlidingWindow,
+		Limit:     req.Limit,
+		Remaining: remaining(req.Limit, estimated),
+		ResetAt:   resetAt,
+	}
+	if !allowed {
+		result.RetryAfter = resetAt.Sub(now)
+	}
+	return result, nil
+}
+
+func (r *Request) normalize() error {
+	if r.Namespace == "" || r.Key == "" || strings.ContainsAny(r.Namespace+r.Key, "{}") {
+		return ErrInvalidKey
+	}
+	if r.Limit <= 0 {
+		return ErrInvalidLimit
+	}
+	if r.Window < time.Millisecond || r.Window > MaxWindow {
+		return ErrInvalidWindow
+	}
+	if r.Cost == 0 {
+		r.Cost = 1
+	}
+	if r.Cost < 0 || r.Cost > r.Limit {
+		return ErrInvalidCost
+	}
+	if r.Algorithm == "" {
+		r.Algorithm = FixedWindow
+	}
+	return nil
+}
+
+// IsReserved reports whether ns is an internal namespace.
+func IsReserved(ns string) bool {
+	return strings.HasPrefix(ns, ReservedPrefix)
+}
+
+// windowKey hash-tags on namespace and key so that both sliding window keys
+// land on the same cluster slot and can be touched by a single script.
+func windowKey(req Request, windowStart int64) string {
+	return fmt.Sprintf("%s:%s:{%s:%s}:%d", keyPrefix, req.Algorithm, req.Namespace, req.Key, windowStart)
+}
+
+func remaining(limit, used int64) int64 {
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
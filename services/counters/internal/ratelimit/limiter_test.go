@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// newTestLimiter returns a limiter on an in-memory Redis whose clock starts
+// on a whole second and is advanced with the returned function.
+func newTestLimiter(t *testing.T) (*Limiter, func(time.Duration)) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	now := time.UnixMilli(1_700_000_000_000)
+	l := New(rdb)
+	l.now = func() time.Time { return now }
+	return l, func(d time.Duration) {
+		now = now.Add(d)
+		mr.FastForward(d)
+	}
+}
+
+func check(t *testing.T, l *Limiter, req Request) *Result {
+	t.Helper()
+	res, err := l.Check(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	return res
+}
+
+func TestFixedWindow(t *testing.T) {
+	l, advance := newTestLimiter(t)
+	req := Request{Namespace: "payments", Key: "user-1", Limit: 3, Window: time.Second}
+
+	for i := int64(1); i <= 3; i++ {
+		res := check(t, l, req)
+		if !res.Allowed || res.Remaining != 3-i {
+			t.Fatalf("call %d: allowed=%v remaining=%d, want allowed with %d left", i, res.Allowed, res.Remaining, 3-i)
+		}
+	}
+
+	res := check(t, l, req)
+	if res.Allowed {
+		t.Fatal("call over the limit was allowed")
+	}
+	if res.RetryAfter <= 0 || res.RetryAfter > time.Second {
+		t.Fatalf("RetryAfter = %v, want within the window", res.RetryAfter)
+	}
+
+	advance(time.Second)
+	if res := check(t, l, req); !res.Allowed || res.Remaining != 2 {
+		t.Fatalf("next window: allowed=%v remaining=%d, want a fresh budget", res.Allowed, res.Remaining)
+	}
+}
+
+func TestFixedWindowRejectedCostIsRefunded(t *testing.T) {
+	l, _ := newTestLimiter(t)
+	req := Request{Namespace: "payments", Key: "user-1", Limit: 3, Window: time.Second, Cost: 2}
+
+	if res := check(t, l, req); !res.Allowed {
+		t.Fatal("first call was rejected")
+	}
+	if res := check(t, l, req); res.Allowed || res.Remaining != 1 {
+		t.Fatalf("second call: allowed=%v remaining=%d, want rejected with 1 left", res.Allowed, res.Remaining)
+	}
+
+	req.Cost = 1
+	if res := check(t, l, req); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("call within the refunded budget: allowed=%v remaining=%d", res.Allowed, res.Remaining)
+	}
+}
+
+func TestSlidingWindowWeighsPreviousWindow(t *testing.T) {
+	l, advance := newTestLimiter(t)
+	req := Request{Namespace: "payments", Key: "user-1", Limit: 10, Window: time.Second, Algorithm: SlidingWindow}
+
+	for i := 0; i < 10; i++ {
+		if res := check(t, l, req); !res.Allowed {
+			t.Fatalf("call %d rejected within the limit", i+1)
+		}
+	}
+	if res := check(t, l, req); res.Allowed {
+		t.Fatal("call over the limit was allowed")
+	}
+
+	// Half way into the next window, half of the previous one still counts
+	advance(1500 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		if res := check(t, l, req); !res.Allowed {
+			t.Fatalf("call %d in the next window rejected, want 5 allowed", i+1)
+		}
+	}
+	res := check(t, l, req)
+	if res.Allowed {
+		t.Fatal("sliding window allowed a boundary burst")
+	}
+	if res.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("RetryAfter = %v, want 500ms", res.RetryAfter)
+	}
+}
+
+func TestBucketsAreIsolated(t *testing.T) {
+	l, _ := newTestLimiter(t)
+	a := Request{Namespace: "payments", Key: "user-1", Limit: 1, Window: time.Minute}
+	b := Request{Namespace: "mass-live", Key: "user-1", Limit: 1, Window: time.Minute}
+
+	check(t, l, a)
+	if res := check(t, l, b); !res.Allowed {
+		t.Fatal("another namespace shared the bucket")
+	}
+}
+
+func TestCheckValidation(t *testing.T) {
+	l, _ := newTestLimiter(t)
+	valid := Request{Namespace: "payments", Key: "user-1", Limit: 5, Window: time.Second}
+
+	tests := []struct {
+		name   string
+		modify func(*Request)
+		want   error
+	}{
+		{"missing key", func(r *Request) { r.Key = "" }, ErrInvalidKey},
+		{"braces", func(r *Request) { r.Key = "{slot}" }, ErrInvalidKey},
+		{"zero limit", func(r *Request) { r.Limit = 0 }, ErrInvalidLimit},
+		{"window too long", func(r *Request) { r.Window = 25 * time.Hour }, ErrInvalidWindow},
+		{"cost over limit", func(r *Request) { r.Cost = 6 }, ErrInvalidCost},
+		{"negative cost", func(r *Request) { r.Cost = -1 }, ErrInvalidCost},
+		{"unknown algorithm", func(r *Request) { r.Algorithm = "token_bucket" }, ErrUnknownAlgorithm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			if _, err := l.Check(context.Background(), req); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsReserved(t *testing.T) {
+	if !IsReserved("_quota") {
+		t.Error("_quota is not reserved")
+	}
+	if IsReserved("payments") {
+		t.Error("payments is reserved")
+	}
+}
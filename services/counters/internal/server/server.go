@@ -0,0 +1,330 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/suuupra/counters/internal/counter"
+	"github.com/suuupra/counters/internal/namespace"
+	"github.com/suuupra/counters/pkg/logger"
+	"github.com/suuupra/counters/pkg/metrics"
+	pb "github.com/suuupra/counters/pkg/pb"
+)
+
+// CountersService implements the Counters gRPC service on top of the
+// counter engine.
+type CountersService struct {
+	pb.UnimplementedCountersServer
+	counters   *counter.Service
+	namespaces *namespace.Registry
+	logger     logger.Logger
+}
+
+// NewCountersService creates a new gRPC service instance.
+func NewCountersService(counters *counter.Service, namespaces *namespace.Registry, logger logger.Logger) *CountersService {
+	return &CountersService{
+		counters:   counters,
+		namespaces: namespaces,
+		logger:     logger,
+	}
+}
+
+// RegisterCountersServer registers the service with the gRPC server.
+func RegisterCountersServer(s *grpc.Server, srv *CountersService) {
+	pb.RegisterCountersServer(s, srv)
+}
+
+// Increment adds to a counter.
+func (s *CountersService) Increment(ctx context.Context, req *pb.IncrementRequest) (*pb.Counter, error) {
+	delta := req.Delta
+	if delta == 0 {
+		delta = 1
+	}
+	if err := s.authorize(ctx, namespace.OpWrite, req.Namespace); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	ctr, err := s.counters.Increment(ctx, counter.Ref{Namespace: req.Namespace, Name: req.Name}, delta, fromPBConsistency(req.Consistency))
+	metrics.Observe("grpc", "increment", start, err)
+	if err != nil {
+		return nil, s.toStatus("increment", err)
+	}
+	return toPB(ctr), nil
+}
+
+// Decrement subtracts from a counter, optionally clamping at a floor.
+func (s *CountersService) Decrement(ctx context.Context, req *pb.DecrementRequest) (*pb.DecrementResponse, error) {
+	amount := req.Amount
+	if amount == 0 {
+		amount = 1
+	}
+	if err := s.authorize(ctx, namespace.OpWrite, req.Namespace); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	res, err := s.counters.Decrement(ctx, counter.Ref{Namespace: req.Namespace, Name: req.Name}, amount, req.Floor, fromPBConsistency(req.Consistency))
+	metrics.Observe("grpc", "decrement", start, err)
+	if err != nil {
+		return nil, s.toStatus("decrement", err)
+	}
+	return &pb.DecrementResponse{Counter: toPB(&res.Counter), Applied: res.Applied, Clamped: res.Clamped}, nil
+}
+
+// IncrementIfLessThan adds to a counter only if the result stays within a
+// limit.
+func (s *CountersService) IncrementIfLessThan(ctx context.Context, req *pb.IncrementIfLessThanRequest) (*pb.ConditionalIncrementResponse, error) {
+	delta := req.Delta
+	if delta == 0 {
+		delta = 1
+	}
+	if err := s.authorize(ctx, namespace.OpWrite, req.Namespace); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	res, err := s.counters.IncrementIfLessThan(ctx, counter.Ref{Namespace: req.Namespace, Name: req.Name}, delta, req.Limit, fromPBConsistency(req.Consistency))
+	metrics.Observe("grpc", "increment_if_lt", start, err)
+	if err != nil {
+		return nil, s.toStatus("increment_if_lt", err)
+	}
+	return &pb.ConditionalIncrementResponse{Counter: toPB(&res.Counter), Applied: res.Applied}, nil
+}
+
+// GetCounter reads a counter, optionally over a trailing window.
+func (s *CountersService) GetCounter(ctx context.Context, req *pb.GetCounterRequest) (*pb.Counter, error) {
+	ref := counter.Ref{Namespace: req.Namespace, Name: req.Name}
+	if err := s.authorize(ctx, namespace.OpRead, ref.Namespace); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+
+	var (
+		ctr *counter.Counter
+		err error
+	)
+	if req.Window != "" {
+		window, perr := time.ParseDuration(req.Window)
+		if perr != nil {
+			return nil, status.Error(codes.InvalidArgument, "window must be a duration such as 5m or 1h")
+		}
+		ctr, err = s.counters.GetWindow(ctx, ref, window)
+	} else {
+		ctr, err = s.counters.Get(ctx, ref)
+	}
+	metrics.Observe("grpc", "get", start, err)
+	if err != nil {
+		return nil, s.toStatus("get", err)
+	}
+	return toPB(ctr), nil
+}
+
+// BatchIncrement applies several increments in one call.
+func (s *CountersService) BatchIncrement(ctx context.Context, req *pb.BatchIncrementRequest) (*pb.BatchIncrementResponse, error) {
+	deltas := make([]counter.Delta, len(req.Increments))
+	namespaces := make([]string, len(req.Increments))
+	for i, inc := range req.Increments {
+		d := inc.Delta
+		if d == 0 {
+			d = 1
+		}
+		deltas[i] = counter.Delta{Ref: counter.Ref{Namespace: inc.Namespace, Name: inc.Name}, Delta: d}
+		namespaces[i] = inc.Namespace
+	}
+	if err := s.authorize(ctx, namespace.OpWrite, namespaces...); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	ctrs, err := s.counters.BatchIncrement(ctx, deltas, fromPBConsistency(req.Consistency))
+	metrics.Observe("grpc", "batch_increment", start, err)
+	if err != nil {
+		return nil, s.toStatus("batch increment", err)
+	}
+
+	resp := &pb.BatchIncrementResponse{Counters: make([]*pb.Counter, len(ctrs))}
+	for i := range ctrs {
+		resp.Counters[i] = toPB(&ctrs[i])
+	}
+	return resp, nil
+}
+
+// QueryCounters reads many counters at once, by list or by name pattern.
+func (s *CountersService) QueryCounters(ctx context.Context, req *pb.QueryCountersRequest) (*pb.QueryCountersResponse, error) {
+	if (len(req.Counters) > 0) == (req.Pattern != "") {
+		return nil, status.Error(codes.InvalidArgument, "set either counters or namespace and pattern")
+	}
+
+	var (
+		ctrs      []counter.Counter
+		truncated bool
+		err       error
+	)
+	start := time.Now()
+	if req.Pattern != "" {
+		if err := s.authorize(ctx, namespace.OpRead, req.Namespace); err != nil {
+			return nil, err
+		}
+		ctrs, truncated, err = s.counters.QueryPattern(ctx, req.Namespace, req.Pattern, int(req.Limit))
+		metrics.Observe("grpc", "query_pattern", start, err)
+	} else {
+		refs := make([]counter.Ref, len(req.Counters))
+		namespaces := make([]string, len(req.Counters))
+		for i, r := range req.Counters {
+			refs[i] = counter.Ref{Namespace: r.Namespace, Name: r.Name}
+			namespaces[i] = r.Namespace
+		}
+		if err := s.authorize(ctx, namespace.OpRead, namespaces...); err != nil {
+			return nil, err
+		}
+		ctrs, err = s.counters.Query(ctx, refs)
+		metrics.Observe("grpc", "query", start, err)
+	}
+	if err != nil {
+		return nil, s.toStatus("query", err)
+	}
+
+	resp := &pb.QueryCountersResponse{Counters: make([]*pb.Counter, len(ctrs)), Truncated: truncated}
+	for i := range ctrs {
+		resp.Counters[i] = toPB(&ctrs[i])
+	}
+	return resp, nil
+}
+
+// IncrementScore adds to a member's leaderboard score.
+func (s *CountersService) IncrementScore(ctx context.Context, req *pb.IncrementScoreRequest) (*pb.LeaderboardEntry, error) {
+	if err := s.authorize(ctx, namespace.OpWrite, req.Namespace); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	entry, err := s.counters.IncrementScore(ctx, req.Namespace, req.Board, req.Member, req.Delta)
+	metrics.Observe("grpc", "increment_score", start, err)
+	if err != nil {
+		return nil, s.toStatus("increment score", err)
+	}
+	return &pb.LeaderboardEntry{Member: entry.Member, Score: entry.Score, Rank: entry.Rank}, nil
+}
+
+// GetLeaderboard returns the top members of a leaderboard.
+func (s *CountersService) GetLeaderboard(ctx context.Context, req *pb.GetLeaderboardRequest) (*pb.GetLeaderboardResponse, error) {
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = 10
+	}
+	if err := s.authorize(ctx, namespace.OpRead, req.Namespace); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	entries, err := s.counters.TopN(ctx, req.Namespace, req.Board, limit)
+	metrics.Observe("grpc", "leaderboard", start, err)
+	if err != nil {
+		return nil, s.toStatus("leaderboard", err)
+	}
+
+	resp := &pb.GetLeaderboardResponse{Entries: make([]*pb.LeaderboardEntry, len(entries))}
+	for i, e := range entries {
+		resp.Entries[i] = &pb.LeaderboardEntry{Member: e.Member, Score: e.Score, Rank: e.Rank}
+	}
+	return resp, nil
+}
+
+// WatchCounters streams counter updates until the client goes away.
+func (s *CountersService) WatchCounters(req *pb.WatchCountersRequest, stream pb.Counters_WatchCountersServer) error {
+	refs := make([]counter.Ref, len(req.Counters))
+	namespaces := make([]string, len(req.Counters))
+	for i, r := range req.Counters {
+		refs[i] = counter.Ref{Namespace: r.Namespace, Name: r.Name}
+		namespaces[i] = r.Namespace
+	}
+
+	ctx := stream.Context()
+	if err := s.authorize(ctx, namespace.OpRead, namespaces...); err != nil {
+		return err
+	}
+	updates, err := s.counters.Watch(ctx, refs, time.Duration(req.IntervalMs)*time.Millisecond)
+	if err != nil {
+		return s.toStatus("watch", err)
+	}
+
+	metrics.ActiveWatches.WithLabelValues("grpc").Inc()
+	defer metrics.ActiveWatches.WithLabelValues("grpc").Dec()
+
+	for ctr := range updates {
+		if err := stream.Send(toPB(&ctr)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorize checks the API key from the x-api-key metadata header and
+// charges the namespaces' quotas.
+func (s *CountersService) authorize(ctx context.Context, op namespace.Op, namespaces ...string) error {
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("x-api-key"); len(vals) > 0 {
+			key = vals[0]
+		}
+	}
+
+	err := s.namespaces.Authorize(ctx, key, op, namespaces...)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, namespace.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, namespace.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, namespace.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, namespace.ErrQuotaUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return s.toStatus("authorize", err)
+	}
+}
+
+func (s *CountersService) toStatus(op string, err error) error {
+	if counter.IsInvalidArgument(err) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, counter.ErrBackpressure) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if errors.Is(err, counter.ErrNotDurable) {
+		s.logger.Error("Durable write failed", "operation", op, "error", err)
+		return status.Error(codes.Unavailable, "write could not be made durable")
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	s.logger.Error("Counter operation failed", "operation", op, "error", err)
+	return status.Error(codes.Internal, "counter operation failed")
+}
+
+func toPB(ctr *counter.Counter) *pb.Counter {
+	return &pb.Counter{
+		Namespace:  ctr.Namespace,
+		Name:       ctr.Name,
+		Value:      ctr.Value,
+		ObservedAt: timestamppb.New(ctr.ObservedAt),
+		Durable:    ctr.Durable,
+	}
+}
+
+func fromPBConsistency(c pb.Consistency) counter.Consistency {
+	if c == pb.Consistency_CONSISTENCY_SYNC {
+		return counter.ConsistencySync
+	}
+	return counter.ConsistencyAsync
+}
@@ -0,0 +1,217 @@
+// Package snapshot exports point-in-time copies of every counter value to
+// object storage, on a schedule and on demand, so analytics jobs can read
+// counts without touching the Redis cluster.
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/suuupra/counters/internal/counter"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+var (
+	ErrInvalidFormat = errors.New("format must be csv or parquet")
+	ErrInProgress    = errors.New("a snapshot is already in progress")
+)
+
+// Status is the lifecycle state of a snapshot.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Snapshot describes one export run.
+type Snapshot struct {
+	ID          string     `json:"id"`
+	Format      Format     `json:"format"`
+	Trigger     string     `json:"trigger"`
+	Status      Status     `json:"status"`
+	Key         string     `json:"key,omitempty"`
+	Counters    int64      `json:"counters"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// Exporter writes counter snapshots to a Store. Only one snapshot runs at
+// a time.
+type Exporter struct {
+	counters *counter.Service
+	store    Store
+	prefix   string
+	format   Format
+	logger   logger.Logger
+
+	// base bounds manual snapshots, which outlive the request that started
+	// them but not the server
+	base context.Context
+
+	running sync.Mutex
+	mu      sync.RWMutex
+	latest  *Snapshot
+}
+
+// NewExporter creates an exporter that uploads under prefix using format
+// for scheduled runs. Snapshots in progress are cancelled with ctx.
+func NewExporter(ctx context.Context, counters *counter.Service, store Store, prefix string, format Format, logger logger.Logger) *Exporter {
+	return &Exporter{
+		base:     ctx,
+		counters: counters,
+		store:    store,
+		prefix:   prefix,
+		format:   format,
+		logger:   logger,
+	}
+}
+
+// StartSnapshotWorker takes a snapshot every interval until ctx is
+// cancelled.
+func (e *Exporter) StartSnapshotWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	e.logger.Info("Snapshot worker started", "interval", interval, "format", e.format)
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("Snapshot worker stopped")
+			return
+		case <-ticker.C:
+			snap, err := e.begin("scheduled", e.format)
+			if err != nil {
+				e.logger.Warn("Skipping scheduled snapshot", "error", err)
+				continue
+			}
+			e.run(ctx, snap)
+			e.running.Unlock()
+		}
+	}
+}
+
+// Start begins a snapshot in the background and returns its initial state.
+func (e *Exporter) Start(trigger string, format Format) (*Snapshot, error) {
+	snap, err := e.begin(trigger, format)
+	if err != nil {
+		return nil, err
+	}
+
+	out := *snap
+	go func() {
+		defer e.running.Unlock()
+		e.run(e.base, snap)
+	}()
+	return &out, nil
+}
+
+// begin claims the exporter for a new snapshot. The caller must unlock
+// e.running once the snapshot has finished.
+func (e *Exporter) begin(trigger string, format Format) (*Snapshot, error) {
+	if !e.running.TryLock() {
+		return nil, ErrInProgress
+	}
+
+	now := time.Now().UTC()
+	snap := &Snapshot{
+		ID:        now.Format("20060102T150405.000Z"),
+		Format:    format,
+		Trigger:   trigger,
+		Status:    StatusRunning,
+		StartedAt: now,
+	}
+
+	e.mu.Lock()
+	e.latest = snap
+	e.mu.Unlock()
+	return snap, nil
+}
+
+// Latest returns the most recent snapshot, or nil if none has run.
+func (e *Exporter) Latest() *Snapshot {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.latest == nil {
+		return nil
+	}
+	out := *e.latest
+	return &out
+}
+
+func (e *Exporter) run(ctx context.Context, snap *Snapshot) {
+	key := e.objectKey(snap)
+	count, err := e.export(ctx, snap.Format, key)
+
+	done := time.Now().UTC()
+	e.mu.Lock()
+	snap.Counters = count
+	snap.CompletedAt = &done
+	if err != nil {
+		snap.Status = StatusFailed
+		snap.Error = err.Error()
+	} else {
+		snap.Status = StatusCompleted
+		snap.Key = key
+	}
+	e.mu.Unlock()
+
+	if err != nil {
+		e.logger.Error("Snapshot failed", "id", snap.ID, "error", err)
+		return
+	}
+	e.logger.Info("Snapshot completed", "id", snap.ID, "key", key, "counters", count, "duration", done.Sub(snap.StartedAt))
+}
+
+// export streams every counter into a temporary file and uploads it. The
+// file is staged on disk so large snapshots never sit fully in memory.
+func (e *Exporter) export(ctx context.Context, format Format, key string) (int64, error) {
+	f, err := os.CreateTemp("", "counters-snapshot-*")
+	if err != nil {
+		return 0, fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	w, err := newRowWriter(format, f)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = e.counters.Scan(ctx, func(batch []counter.Counter) error {
+		count += int64(len(batch))
+		return w.Write(batch)
+	})
+	if err != nil {
+		return count, fmt.Errorf("scan counters: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return count, fmt.Errorf("finish %s file: %w", format, err)
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		return count, err
+	}
+	return count, e.store.Put(ctx, key, f, format.contentType())
+}
+
+// objectKey lays snapshots out in date partitions so warehouse loaders can
+// pick up a day at a time.
+func (e *Exporter) objectKey(snap *Snapshot) string {
+	ts := snap.StartedAt
+	return path.Join(
+		e.prefix,
+		"format="+string(snap.Format),
+		"dt="+ts.Format("2006-01-02"),
+		fmt.Sprintf("counters-%s.%s", ts.Format("150405.000"), snap.Format),
+	)
+}
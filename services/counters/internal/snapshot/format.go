@@ -0,0 +1,128 @@
+package snapshot
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/suuupra/counters/internal/counter"
+)
+
+// Format is the file format a snapshot is written in.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat validates a snapshot format, defaulting to Parquet.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatParquet:
+		return FormatParquet, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidFormat, s)
+	}
+}
+
+func (f Format) contentType() string {
+	if f == FormatCSV {
+		return "text/csv"
+	}
+	return "application/vnd.apache.parquet"
+}
+
+// rowWriter encodes counters into a snapshot file.
+type rowWriter interface {
+	Write(batch []counter.Counter) error
+	Close() error
+}
+
+func newRowWriter(format Format, w io.Writer) (rowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatParquet:
+		return newParquetWriter(w)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFormat, format)
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"namespace", "name", "value", "observed_at"}); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) Write(batch []counter.Counter) error {
+	for _, ctr := range batch {
+		if err := c.w.Write([]string{
+			ctr.Namespace,
+			ctr.Name,
+			strconv.FormatInt(ctr.Value, 10),
+			ctr.ObservedAt.UTC().Format(time.RFC3339Nano),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// parquetRow is the Parquet schema for snapshot rows.
+type parquetRow struct {
+	Namespace  string `parquet:"name=namespace, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Name       string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Value      int64  `parquet:"name=value, type=INT64"`
+	ObservedAt int64  `parquet:"name=observed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+type parquetWriter struct {
+	pw *writer.ParquetWriter
+}
+
+func newParquetWriter(w io.Writer) (*parquetWriter, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetRow), 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	return &parquetWriter{pw: pw}, nil
+}
+
+func (p *parquetWriter) Write(batch []counter.Counter) error {
+	for _, ctr := range batch {
+		if err := p.pw.Write(parquetRow{
+			Namespace:  ctr.Namespace,
+			Name:       ctr.Name,
+			Value:      ctr.Value,
+			ObservedAt: ctr.ObservedAt.UnixMilli(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parquetWriter) Close() error {
+	return p.pw.WriteStop()
+}
@@ -0,0 +1,81 @@
+package snapshot
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/suuupra/counters/internal/namespace"
+	"github.com/suuupra/counters/pkg/logger"
+)
+
+// TriggerRequest is the body of an ad-hoc snapshot request.
+type TriggerRequest struct {
+	// Format is "parquet" (default) or "csv".
+	Format string `json:"format"`
+}
+
+// Handler serves the snapshot HTTP API.
+type Handler struct {
+	exporter   *Exporter
+	adminToken string
+	logger     logger.Logger
+}
+
+// NewHandler creates a snapshot handler. A snapshot scans the whole cluster,
+// so the endpoints require adminToken and are disabled when it is empty.
+func NewHandler(exporter *Exporter, adminToken string, logger logger.Logger) *Handler {
+	return &Handler{
+		exporter:   exporter,
+		adminToken: adminToken,
+		logger:     logger,
+	}
+}
+
+// SetupRoutes registers the snapshot endpoints.
+func (h *Handler) SetupRoutes(router *gin.Engine) {
+	admin := router.Group("/api/v1/admin/snapshots", namespace.RequireAdmin(h.adminToken))
+	admin.POST("", h.Trigger)
+	admin.GET("/latest", h.Latest)
+}
+
+// Trigger starts an ad-hoc snapshot and returns immediately. Poll
+// GET /api/v1/admin/snapshots/latest for the outcome.
+func (h *Handler) Trigger(c *gin.Context) {
+	var req TriggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
+		return
+	}
+
+	format, err := ParseFormat(req.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "message": err.Error()})
+		return
+	}
+
+	snap, err := h.exporter.Start("manual", format)
+	if errors.Is(err, ErrInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "snapshot": h.exporter.Latest()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to start snapshot", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start snapshot"})
+		return
+	}
+
+	h.logger.Info("Snapshot triggered", "id", snap.ID, "format", snap.Format)
+	c.JSON(http.StatusAccepted, snap)
+}
+
+// Latest returns the most recent snapshot run.
+func (h *Handler) Latest(c *gin.Context) {
+	snap := h.exporter.Latest()
+	if snap == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no snapshot has run yet"})
+		return
+	}
+	c.JSON(http.StatusOK, snap)
+}
@@ -0,0 +1,54 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Store is where snapshot files are uploaded.
+type Store interface {
+	Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error
+}
+
+// S3Store uploads snapshots to an S3 bucket.
+type S3Store struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3Store creates an S3 store using the default AWS credential chain.
+// A non-empty endpoint points the client at an S3-compatible service such
+// as MinIO, using path-style addressing.
+func NewS3Store(ctx context.Context, bucket, region, endpoint string) (*S3Store, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return &S3Store{client: client, bucket: bucket}, nil
+}
+
+// Put uploads body under key.
+func (s *S3Store) Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("put s3://%s/%s: %w", s.bucket, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,462 @@
+// Package wal is a file-backed write-ahead log of counter deltas. Each
+// service instance owns its own log directory; entries are appended as
+// JSON lines to size-bounded segment files and read back by the
+// persistence worker, which advances a checkpoint once entries are safely
+// in Postgres.
+package wal
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	segmentPrefix  = "wal-"
+	segmentSuffix  = ".log"
+	checkpointFile = "checkpoint"
+	idFile         = "id"
+)
+
+var ErrClosed = errors.New("wal is closed")
+
+// Entry is a single logged delta. An entry with Aborts set cancels an
+// earlier entry whose Redis write failed after it was logged; it repeats
+// that entry's counter and delta so the cancellation can be persisted as a
+// compensating delta if the original has already been flushed.
+type Entry struct {
+	Seq       uint64    `json:"seq"`
+	Namespace string    `json:"ns,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Delta     int64     `json:"delta,omitempty"`
+	Timestamp time.Time `json:"ts"`
+	Aborts    uint64    `json:"aborts,omitempty"`
+}
+
+// Log is an append-only, segmented write-ahead log.
+type Log struct {
+	mu          sync.Mutex
+	dir         string
+	segmentSize int64
+
+	file     *os.File
+	writer   *bufio.Writer
+	size     int64
+	segments []uint64 // first seq of each segment, ascending
+	nextSeq  uint64
+	closed   bool
+
+	id         string
+	checkpoint uint64
+	dirty      bool // written but not yet fsynced
+}
+
+// Open opens the log in dir, creating it if needed, and positions the
+// writer after the last complete entry.
+func Open(dir string, segmentSize int64) (*Log, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create wal dir: %w", err)
+	}
+
+	l := &Log{dir: dir, segmentSize: segmentSize, nextSeq: 1}
+
+	id, err := loadOrCreateID(dir)
+	if err != nil {
+		return nil, err
+	}
+	l.id = id
+
+	if l.checkpoint, err = readCheckpoint(dir); err != nil {
+		return nil, err
+	}
+
+	segments, err := l.listSegments()
+	if err != nil {
+		return nil, err
+	}
+	l.segments = segments
+
+	if len(segments) == 0 {
+		if l.checkpoint > 0 {
+			l.nextSeq = l.checkpoint + 1
+		}
+		if err := l.rotate(); err != nil {
+			return nil, err
+		}
+		return l, nil
+	}
+
+	last := segments[len(segments)-1]
+	lastSeq, validSize, err := scanSegment(l.segmentPath(last))
+	if err != nil {
+		return nil, err
+	}
+	if lastSeq >= l.nextSeq {
+		l.nextSeq = lastSeq + 1
+	} else if last > l.nextSeq {
+		l.nextSeq = last
+	}
+
+	// Drop any torn write left by a crash mid-append.
+	f, err := os.OpenFile(l.segmentPath(last), os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open wal segment: %w", err)
+	}
+	if err := f.Truncate(validSize); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("truncate wal segment: %w", err)
+	}
+	if _, err := f.Seek(validSize, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("seek wal segment: %w", err)
+	}
+	l.file = f
+	l.writer = bufio.NewWriter(f)
+	l.size = validSize
+	return l, nil
+}
+
+// Append durably logs entries, assigning them consecutive sequence numbers,
+// and returns the sequence number of the first. It returns only after the
+// entries have been fsynced, so a batch costs a single fsync.
+func (l *Log) Append(entries ...Entry) (uint64, error) {
+	return l.append(entries, true)
+}
+
+// AppendAsync logs entries like Append but returns once they are handed to
+// the operating system, without waiting for an fsync. They survive a
+// process crash but not a machine crash until the next Sync.
+func (l *Log) AppendAsync(entries ...Entry) (uint64, error) {
+	return l.append(entries, false)
+}
+
+// Sync fsyncs entries written by AppendAsync.
+func (l *Log) Sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+	if !l.dirty {
+		return nil
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("sync wal: %w", err)
+	}
+	l.dirty = false
+	return nil
+}
+
+func (l *Log) append(entries []Entry, sync bool) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, ErrClosed
+	}
+	if len(entries) == 0 {
+		return l.nextSeq, nil
+	}
+
+	if l.size >= l.segmentSize {
+		if err := l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	first := l.nextSeq
+	var written int64
+	for i, e := range entries {
+		e.Seq = first + uint64(i)
+		line, err := json.Marshal(e)
+		if err != nil {
+			return 0, fmt.Errorf("encode wal entry: %w", err)
+		}
+		line = append(line, '\n')
+		if _, err := l.writer.Write(line); err != nil {
+			return 0, fmt.Errorf("write wal entry: %w", err)
+		}
+		written += int64(len(line))
+	}
+
+	if err := l.writer.Flush(); err != nil {
+		return 0, fmt.Errorf("flush wal: %w", err)
+	}
+	if sync {
+		if err := l.file.Sync(); err != nil {
+			return 0, fmt.Errorf("sync wal: %w", err)
+		}
+		l.dirty = false
+	} else {
+		l.dirty = true
+	}
+
+	l.size += written
+	l.nextSeq += uint64(len(entries))
+	return first, nil
+}
+
+// ReadAfter returns up to max entries with a sequence number greater than
+// seq, in order.
+func (l *Log) ReadAfter(seq uint64, max int) ([]Entry, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, ErrClosed
+	}
+	segments := append([]uint64(nil), l.segments...)
+	l.mu.Unlock()
+
+	var entries []Entry
+	for i, first := range segments {
+		// Skip segments that end before seq.
+		if i+1 < len(segments) && segments[i+1] <= seq+1 {
+			continue
+		}
+		if err := readSegment(l.segmentPath(first), func(e Entry) bool {
+			if e.Seq > seq {
+				entries = append(entries, e)
+			}
+			return len(entries) < max
+		}); err != nil {
+			return nil, err
+		}
+		if len(entries) >= max {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// ID returns the log's identity, generated when its directory was first
+// used. Downstream consumers key their progress by it, so a wiped log
+// directory is never mistaken for one that was already persisted.
+func (l *Log) ID() string {
+	return l.id
+}
+
+// Checkpoint returns the sequence number up to which entries have been
+// persisted downstream.
+func (l *Log) Checkpoint() (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.checkpoint, nil
+}
+
+// Pending returns the number of entries written after the checkpoint.
+func (l *Log) Pending() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.nextSeq - 1 - l.checkpoint
+}
+
+// SetCheckpoint records that every entry up to and including seq has been
+// persisted downstream, and deletes segments that are no longer needed.
+func (l *Log) SetCheckpoint(seq uint64) error {
+	tmp := filepath.Join(l.dir, checkpointFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(seq, 10)), 0o644); err != nil {
+		return fmt.Errorf("write wal checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(l.dir, checkpointFile)); err != nil {
+		return fmt.Errorf("commit wal checkpoint: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if seq > l.checkpoint {
+		l.checkpoint = seq
+	}
+
+	// A segment can go once the segment after it starts at or before the
+	// first unpersisted entry. The active segment is always kept.
+	keep := 0
+	for keep+1 < len(l.segments) && l.segments[keep+1] <= seq+1 {
+		if err := os.Remove(l.segmentPath(l.segments[keep])); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove wal segment: %w", err)
+		}
+		keep++
+	}
+	l.segments = l.segments[keep:]
+	return nil
+}
+
+// Close flushes and closes the active segment.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	if err := l.writer.Flush(); err != nil {
+		l.file.Close()
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
+
+// rotate closes the active segment and starts a new one at nextSeq. Callers
+// hold l.mu.
+func (l *Log) rotate() error {
+	if l.file != nil {
+		if err := l.writer.Flush(); err != nil {
+			return fmt.Errorf("flush wal: %w", err)
+		}
+		if err := l.file.Sync(); err != nil {
+			return fmt.Errorf("sync wal: %w", err)
+		}
+		if err := l.file.Close(); err != nil {
+			return fmt.Errorf("close wal segment: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(l.segmentPath(l.nextSeq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("create wal segment: %w", err)
+	}
+	l.file = f
+	l.writer = bufio.NewWriter(f)
+	l.size = 0
+	if len(l.segments) == 0 || l.segments[len(l.segments)-1] != l.nextSeq {
+		l.segments = append(l.segments, l.nextSeq)
+	}
+	return nil
+}
+
+func readCheckpoint(dir string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, checkpointFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read wal checkpoint: %w", err)
+	}
+	cp, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse wal checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+func loadOrCreateID(dir string) (string, error) {
+	path := filepath.Join(dir, idFile)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("read wal id: %w", err)
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate wal id: %w", err)
+	}
+	id := hex.EncodeToString(buf)
+	if err := os.WriteFile(path, []byte(id), 0o644); err != nil {
+		return "", fmt.Errorf("write wal id: %w", err)
+	}
+	return id, nil
+}
+
+func (l *Log) segmentPath(first uint64) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%s%020d%s", segmentPrefix, first, segmentSuffix))
+}
+
+func (l *Log) listSegments() ([]uint64, error) {
+	names, err := filepath.Glob(filepath.Join(l.dir, segmentPrefix+"*"+segmentSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("list wal segments: %w", err)
+	}
+
+	segments := make([]uint64, 0, len(names))
+	for _, name := range names {
+		base := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(name), segmentPrefix), segmentSuffix)
+		first, err := strconv.ParseUint(base, 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, first)
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i] < segments[j] })
+	return segments, nil
+}
+
+// scanSegment returns the last sequence number in a segment and the byte
+// length of its complete entries.
+func scanSegment(path string) (uint64, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("open wal segment: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		lastSeq uint64
+		valid   int64
+	)
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return lastSeq, valid, nil
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("read wal segment: %w", err)
+		}
+		var e Entry
+		if json.Unmarshal(line, &e) != nil {
+			return lastSeq, valid, nil
+		}
+		lastSeq = e.Seq
+		valid += int64(len(line))
+	}
+}
+
+func readSegment(path string, fn func(Entry) bool) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Removed by a concurrent checkpoint.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open wal segment: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read wal segment: %w", err)
+		}
+		var e Entry
+		if json.Unmarshal(line, &e) != nil {
+			return nil
+		}
+		if !fn(e) {
+			return nil
+		}
+	}
+}
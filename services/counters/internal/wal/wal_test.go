@@ -0,0 +1,175 @@
+package wal
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAppendReadAndReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := Open(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	first, err := l.Append(
+		Entry{Namespace: "payments", Name: "settled", Delta: 5, Timestamp: time.Now()},
+		Entry{Namespace: "payments", Name: "settled", Delta: 7, Timestamp: time.Now()},
+	)
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if first != 1 {
+		t.Fatalf("first seq = %d, want 1", first)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	l, err = Open(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer l.Close()
+
+	next, err := l.Append(Entry{Namespace: "payments", Name: "settled", Delta: 1, Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("Append after reopen: %v", err)
+	}
+	if next != 3 {
+		t.Fatalf("seq after reopen = %d, want 3", next)
+	}
+
+	entries, err := l.ReadAfter(1, 10)
+	if err != nil {
+		t.Fatalf("ReadAfter: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Delta != 7 || entries[1].Seq != 3 {
+		t.Fatalf("ReadAfter(1) = %+v", entries)
+	}
+}
+
+func TestTornWriteIsDiscarded(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := Open(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, err := l.Append(Entry{Namespace: "a", Name: "b", Delta: 1, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	path := l.segmentPath(1)
+	l.Close()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"seq":2,"ns":"a","na`)
+	f.Close()
+
+	l, err = Open(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer l.Close()
+
+	seq, err := l.Append(Entry{Namespace: "a", Name: "b", Delta: 2, Timestamp: time.Now()})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if seq != 2 {
+		t.Fatalf("seq = %d, want 2", seq)
+	}
+	entries, err := l.ReadAfter(0, 10)
+	if err != nil {
+		t.Fatalf("ReadAfter: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Delta != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestCheckpointRemovesPersistedSegments(t *testing.T) {
+	dir := t.TempDir()
+
+	// A tiny segment size forces a rotation before every append.
+	l, err := Open(dir, 1)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer l.Close()
+
+	for i := 0; i < 4; i++ {
+		if _, err := l.Append(Entry{Namespace: "a", Name: "b", Delta: 1, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	if err := l.SetCheckpoint(3); err != nil {
+		t.Fatalf("SetCheckpoint: %v", err)
+	}
+	cp, err := l.Checkpoint()
+	if err != nil || cp != 3 {
+		t.Fatalf("Checkpoint = %d, %v", cp, err)
+	}
+
+	segments, err := l.listSegments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 1 || segments[0] != 4 {
+		t.Fatalf("segments after checkpoint = %v, want [4]", segments)
+	}
+
+	entries, err := l.ReadAfter(cp, 10)
+	if err != nil {
+		t.Fatalf("ReadAfter: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Seq != 4 {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
+
+func TestPendingAndIDSurviveReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := Open(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := l.AppendAsync(Entry{Namespace: "a", Name: "b", Delta: 1, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("AppendAsync: %v", err)
+		}
+	}
+	if err := l.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if err := l.SetCheckpoint(2); err != nil {
+		t.Fatalf("SetCheckpoint: %v", err)
+	}
+	if got := l.Pending(); got != 3 {
+		t.Fatalf("Pending = %d, want 3", got)
+	}
+	id := l.ID()
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	l, err = Open(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer l.Close()
+
+	if l.ID() != id {
+		t.Fatalf("ID after reopen = %q, want %q", l.ID(), id)
+	}
+	if got := l.Pending(); got != 3 {
+		t.Fatalf("Pending after reopen = %d, want 3", got)
+	}
+}
@@ -4,6 +4,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,13 +13,22 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/suuupra/counters/internal/admin"
 	"github.com/suuupra/counters/internal/api"
 	"github.com/suuupra/counters/internal/config"
 	"github.com/suuupra/counters/internal/counter"
 	"github.com/suuupra/counters/internal/database"
+	"github.com/suuupra/counters/internal/exporter"
+	"github.com/suuupra/counters/internal/namespace"
+	"github.com/suuupra/counters/internal/ratelimit"
+	grpcserver "github.com/suuupra/counters/internal/server"
+	"github.com/suuupra/counters/internal/snapshot"
+	"github.com/suuupra/counters/internal/wal"
 	"github.com/suuupra/counters/pkg/logger"
 	"github.com/suuupra/counters/pkg/metrics"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -58,19 +68,66 @@ func main() {
 	}
 	defer db.Close()
 
+	// Open the write-ahead log that every increment is recorded in
+	walLog, err := wal.Open(cfg.WALDir, cfg.WALSegmentSize)
+	if err != nil {
+		logger.Error("Failed to open write-ahead log", "error", err, "dir", cfg.WALDir)
+		os.Exit(1)
+	}
+	defer walLog.Close()
+
 	// Initialize counter service
-	counterService := counter.New(cfg, rdb, db, logger)
+	counterService := counter.New(cfg, rdb, db, walLog, logger)
 
 	// Start background services
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Load namespaces and API keys
+	limiter := ratelimit.New(rdb)
+	namespaces := namespace.NewRegistry(db, rdb, limiter, namespace.Options{
+		AuthEnabled:       cfg.AuthEnabled,
+		DefaultReadQuota:  cfg.DefaultReadQuota,
+		DefaultWriteQuota: cfg.DefaultWriteQuota,
+		QuotaFailOpen:     cfg.QuotaFailOpen,
+	}, logger)
+	if err := namespaces.Refresh(ctx); err != nil {
+		logger.Error("Failed to load namespaces", "error", err)
+		os.Exit(1)
+	}
+	go namespaces.StartRefreshWorker(ctx, cfg.NamespaceRefreshInterval)
+	if !cfg.AuthEnabled {
+		logger.Warn("API key authentication is disabled")
+	}
+	if cfg.QuotaFailOpen {
+		logger.Warn("Namespace quotas fail open when Redis is unreachable")
+	}
+
 	// Start persistence worker
 	go counterService.StartPersistenceWorker(ctx)
 
 	// Start aggregation worker
 	go counterService.StartAggregationWorker(ctx)
 
+	// Index counters written before the name index existed
+	go func() {
+		if err := counterService.RebuildIndex(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Failed to rebuild counter index", "error", err)
+		}
+	}()
+
+	// Publish selected business counters on /metrics
+	if len(cfg.ExportedCounters) > 0 {
+		selectors, err := exporter.ParseSelectors(cfg.ExportedCounters)
+		if err != nil {
+			logger.Error("Invalid EXPORTED_COUNTERS", "error", err)
+			os.Exit(1)
+		}
+		counterExporter := exporter.New(counterService, selectors, logger)
+		prometheus.MustRegister(counterExporter)
+		go counterExporter.StartRefreshWorker(ctx, cfg.ExportInterval)
+	}
+
 	// Initialize HTTP server
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -80,9 +137,40 @@ func main() {
 	router.Use(gin.Recovery())
 
 	// Setup API routes
-	apiHandler := api.NewHandler(cfg, counterService, logger)
+	apiHandler := api.NewHandler(cfg, counterService, namespaces, logger)
 	apiHandler.SetupRoutes(router)
 
+	// Namespace management and usage reporting
+	namespaceHandler := namespace.NewHandler(namespaces, db, cfg.AdminToken, logger)
+	namespaceHandler.SetupRoutes(router)
+
+	// Audited admin operations for incident repair
+	adminHandler := admin.NewHandler(counterService, db, cfg.AdminToken, logger)
+	adminHandler.SetupRoutes(router)
+
+	// Shared rate limiting backend for other services
+	rateLimitHandler := ratelimit.NewHandler(limiter, namespaces.Authorizer(namespace.OpWrite), logger)
+	rateLimitHandler.SetupRoutes(router)
+
+	// Counter snapshots exported to object storage for the warehouse
+	if cfg.SnapshotBucket != "" {
+		format, err := snapshot.ParseFormat(cfg.SnapshotFormat)
+		if err != nil {
+			logger.Error("Invalid snapshot format", "error", err)
+			os.Exit(1)
+		}
+		store, err := snapshot.NewS3Store(ctx, cfg.SnapshotBucket, cfg.AWSRegion, cfg.S3Endpoint)
+		if err != nil {
+			logger.Error("Failed to initialize snapshot store", "error", err)
+			os.Exit(1)
+		}
+		exporter := snapshot.NewExporter(ctx, counterService, store, cfg.SnapshotPrefix, format, logger)
+		snapshot.NewHandler(exporter, cfg.AdminToken, logger).SetupRoutes(router)
+		if cfg.SnapshotInterval > 0 {
+			go exporter.StartSnapshotWorker(ctx, cfg.SnapshotInterval)
+		}
+	}
+
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
@@ -103,6 +191,23 @@ func main() {
 		}
 	}()
 
+	// Start gRPC server
+	grpcServer := grpc.NewServer()
+	grpcserver.RegisterCountersServer(grpcServer, grpcserver.NewCountersService(counterService, namespaces, logger))
+
+	go func() {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			logger.Error("Failed to listen for gRPC", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Starting gRPC server", "port", cfg.GRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Error("Failed to start gRPC server", "error", err)
+			os.Exit(1)
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -120,6 +225,7 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown", "error", err)
 	}
+	grpcServer.GracefulStop()
 
 	logger.Info("Server exited")
 }
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// OperationsTotal counts counter operations by API, operation and outcome.
+	OperationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "counters_operations_total",
+			Help: "Total number of counter operations",
+		},
+		[]string{"api", "operation", "status"},
+	)
+
+	// OperationDuration tracks counter operation latency.
+	OperationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "counters_operation_duration_seconds",
+			Help:    "Duration of counter operations in seconds",
+			Buckets: []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25},
+		},
+		[]string{"api", "operation"},
+	)
+
+	// ActiveWatches is the number of open watch subscriptions.
+	ActiveWatches = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "counters_active_watches",
+			Help: "Number of open counter watch subscriptions",
+		},
+		[]string{"api"},
+	)
+
+	// WALPendingEntries is the number of WAL entries not yet in Postgres.
+	WALPendingEntries = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "counters_wal_pending_entries",
+			Help: "Number of write-ahead log entries not yet persisted to Postgres",
+		},
+	)
+
+	// WALLagSeconds is the age of the oldest unpersisted WAL entry.
+	WALLagSeconds = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "counters_wal_lag_seconds",
+			Help: "Age of the oldest write-ahead log entry not yet persisted to Postgres",
+		},
+	)
+
+	// WALFlushedEntries counts deltas applied to Postgres from the WAL.
+	WALFlushedEntries = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "counters_wal_flushed_entries_total",
+			Help: "Total number of write-ahead log entries applied to Postgres",
+		},
+	)
+
+	// WALFlushErrors counts failed WAL flushes.
+	WALFlushErrors = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "counters_wal_flush_errors_total",
+			Help: "Total number of failed write-ahead log flushes",
+		},
+	)
+
+	// WALFlushDuration tracks how long each WAL batch takes to persist.
+	WALFlushDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "counters_wal_flush_duration_seconds",
+			Help:    "Duration of write-ahead log batch flushes in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+	)
+
+	// BackpressureRejections counts writes refused because the WAL backlog
+	// was over its limit.
+	BackpressureRejections = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "counters_backpressure_rejections_total",
+			Help: "Total number of writes rejected due to write-ahead log backpressure",
+		},
+	)
+)
+
+// Init registers the service metrics with the default registry.
+func Init() {
+	prometheus.MustRegister(
+		OperationsTotal,
+		OperationDuration,
+		ActiveWatches,
+		WALPendingEntries,
+		WALLagSeconds,
+		WALFlushedEntries,
+		WALFlushErrors,
+		WALFlushDuration,
+		BackpressureRejections,
+	)
+}
+
+// Observe records the outcome and latency of a counter operation.
+func Observe(api, operation string, start time.Time, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	OperationsTotal.WithLabelValues(api, operation, status).Inc()
+	OperationDuration.WithLabelValues(api, operation).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,445 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v6.32.0
+// source: proto/counters.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Counters_Increment_FullMethodName           = "/counters.Counters/Increment"
+	Counters_GetCounter_FullMethodName          = "/counters.Counters/GetCounter"
+	Counters_BatchIncrement_FullMethodName      = "/counters.Counters/BatchIncrement"
+	Counters_QueryCounters_FullMethodName       = "/counters.Counters/QueryCounters"
+	Counters_Decrement_FullMethodName           = "/counters.Counters/Decrement"
+	Counters_IncrementIfLessThan_FullMethodName = "/counters.Counters/IncrementIfLessThan"
+	Counters_IncrementScore_FullMethodName      = "/counters.Counters/IncrementScore"
+	Counters_GetLeaderboard_FullMethodName      = "/counters.Counters/GetLeaderboard"
+	Counters_WatchCounters_FullMethodName       = "/counters.Counters/WatchCounters"
+)
+
+// CountersClient is the client API for Counters service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CountersClient interface {
+	// Counter operations
+	Increment(ctx context.Context, in *IncrementRequest, opts ...grpc.CallOption) (*Counter, error)
+	GetCounter(ctx context.Context, in *GetCounterRequest, opts ...grpc.CallOption) (*Counter, error)
+	BatchIncrement(ctx context.Context, in *BatchIncrementRequest, opts ...grpc.CallOption) (*BatchIncrementResponse, error)
+	// Reads many counters at once, by list or by name pattern.
+	QueryCounters(ctx context.Context, in *QueryCountersRequest, opts ...grpc.CallOption) (*QueryCountersResponse, error)
+	Decrement(ctx context.Context, in *DecrementRequest, opts ...grpc.CallOption) (*DecrementResponse, error)
+	// Increments only if the result stays within the limit.
+	IncrementIfLessThan(ctx context.Context, in *IncrementIfLessThanRequest, opts ...grpc.CallOption) (*ConditionalIncrementResponse, error)
+	// Leaderboards
+	IncrementScore(ctx context.Context, in *IncrementScoreRequest, opts ...grpc.CallOption) (*LeaderboardEntry, error)
+	GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*GetLeaderboardResponse, error)
+	// Streams the current value of each counter, then every change, at most
+	// once per interval per counter.
+	WatchCounters(ctx context.Context, in *WatchCountersRequest, opts ...grpc.CallOption) (Counters_WatchCountersClient, error)
+}
+
+type countersClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCountersClient(cc grpc.ClientConnInterface) CountersClient {
+	return &countersClient{cc}
+}
+
+func (c *countersClient) Increment(ctx context.Context, in *IncrementRequest, opts ...grpc.CallOption) (*Counter, error) {
+	out := new(Counter)
+	err := c.cc.Invoke(ctx, Counters_Increment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *countersClient) GetCounter(ctx context.Context, in *GetCounterRequest, opts ...grpc.CallOption) (*Counter, error) {
+	out := new(Counter)
+	err := c.cc.Invoke(ctx, Counters_GetCounter_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *countersClient) BatchIncrement(ctx context.Context, in *BatchIncrementRequest, opts ...grpc.CallOption) (*BatchIncrementResponse, error) {
+	out := new(BatchIncrementResponse)
+	err := c.cc.Invoke(ctx, Counters_BatchIncrement_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *countersClient) QueryCounters(ctx context.Context, in *QueryCountersRequest, opts ...grpc.CallOption) (*QueryCountersResponse, error) {
+	out := new(QueryCountersResponse)
+	err := c.cc.Invoke(ctx, Counters_QueryCounters_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *countersClient) Decrement(ctx context.Context, in *DecrementRequest, opts ...grpc.CallOption) (*DecrementResponse, error) {
+	out := new(DecrementResponse)
+	err := c.cc.Invoke(ctx, Counters_Decrement_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *countersClient) IncrementIfLessThan(ctx context.Context, in *IncrementIfLessThanRequest, opts ...grpc.CallOption) (*ConditionalIncrementResponse, error) {
+	out := new(ConditionalIncrementResponse)
+	err := c.cc.Invoke(ctx, Counters_IncrementIfLessThan_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *countersClient) IncrementScore(ctx context.Context, in *IncrementScoreRequest, opts ...grpc.CallOption) (*LeaderboardEntry, error) {
+	out := new(LeaderboardEntry)
+	err := c.cc.Invoke(ctx, Counters_IncrementScore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *countersClient) GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*GetLeaderboardResponse, error) {
+	out := new(GetLeaderboardResponse)
+	err := c.cc.Invoke(ctx, Counters_GetLeaderboard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *countersClient) WatchCounters(ctx context.Context, in *WatchCountersRequest, opts ...grpc.CallOption) (Counters_WatchCountersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Counters_ServiceDesc.Streams[0], Counters_WatchCounters_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &countersWatchCountersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Counters_WatchCountersClient interface {
+	Recv() (*Counter, error)
+	grpc.ClientStream
+}
+
+type countersWatchCountersClient struct {
+	grpc.ClientStream
+}
+
+func (x *countersWatchCountersClient) Recv() (*Counter, error) {
+	m := new(Counter)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CountersServer is the server API for Counters service.
+// All implementations must embed UnimplementedCountersServer
+// for forward compatibility
+type CountersServer interface {
+	// Counter operations
+	Increment(context.Context, *IncrementRequest) (*Counter, error)
+	GetCounter(context.Context, *GetCounterRequest) (*Counter, error)
+	BatchIncrement(context.Context, *BatchIncrementRequest) (*BatchIncrementResponse, error)
+	// Reads many counters at once, by list or by name pattern.
+	QueryCounters(context.Context, *QueryCountersRequest) (*QueryCountersResponse, error)
+	Decrement(context.Context, *DecrementRequest) (*DecrementResponse, error)
+	// Increments only if the result stays within the limit.
+	IncrementIfLessThan(context.Context, *IncrementIfLessThanRequest) (*ConditionalIncrementResponse, error)
+	// Leaderboards
+	IncrementScore(context.Context, *IncrementScoreRequest) (*LeaderboardEntry, error)
+	GetLeaderboard(context.Context, *GetLeaderboardRequest) (*GetLeaderboardResponse, error)
+	// Streams the current value of each counter, then every change, at most
+	// once per interval per counter.
+	WatchCounters(*WatchCountersRequest, Counters_WatchCountersServer) error
+	mustEmbedUnimplementedCountersServer()
+}
+
+// UnimplementedCountersServer must be embedded to have forward compatible implementations.
+type UnimplementedCountersServer struct {
+}
+
+func (UnimplementedCountersServer) Increment(context.Context, *IncrementRequest) (*Counter, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Increment not implemented")
+}
+func (UnimplementedCountersServer) GetCounter(context.Context, *GetCounterRequest) (*Counter, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCounter not implemented")
+}
+func (UnimplementedCountersServer) BatchIncrement(context.Context, *BatchIncrementRequest) (*BatchIncrementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchIncrement not implemented")
+}
+func (UnimplementedCountersServer) QueryCounters(context.Context, *QueryCountersRequest) (*QueryCountersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryCounters not implemented")
+}
+func (UnimplementedCountersServer) Decrement(context.Context, *DecrementRequest) (*DecrementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decrement not implemented")
+}
+func (UnimplementedCountersServer) IncrementIfLessThan(context.Context, *IncrementIfLessThanRequest) (*ConditionalIncrementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IncrementIfLessThan not implemented")
+}
+func (UnimplementedCountersServer) IncrementScore(context.Context, *IncrementScoreRequest) (*LeaderboardEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IncrementScore not implemented")
+}
+func (UnimplementedCountersServer) GetLeaderboard(context.Context, *GetLeaderboardRequest) (*GetLeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderboard not implemented")
+}
+func (UnimplementedCountersServer) WatchCounters(*WatchCountersRequest, Counters_WatchCountersServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCounters not implemented")
+}
+func (UnimplementedCountersServer) mustEmbedUnimplementedCountersServer() {}
+
+// UnsafeCountersServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CountersServer will
+// result in compilation errors.
+type UnsafeCountersServer interface {
+	mustEmbedUnimplementedCountersServer()
+}
+
+func RegisterCountersServer(s grpc.ServiceRegistrar, srv CountersServer) {
+	s.RegisterService(&Counters_ServiceDesc, srv)
+}
+
+func _Counters_Increment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CountersServer).Increment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Counters_Increment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CountersServer).Increment(ctx, req.(*IncrementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Counters_GetCounter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCounterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CountersServer).GetCounter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Counters_GetCounter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CountersServer).GetCounter(ctx, req.(*GetCounterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Counters_BatchIncrement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchIncrementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CountersServer).BatchIncrement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Counters_BatchIncrement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CountersServer).BatchIncrement(ctx, req.(*BatchIncrementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Counters_QueryCounters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryCountersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CountersServer).QueryCounters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Counters_QueryCounters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CountersServer).QueryCounters(ctx, req.(*QueryCountersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Counters_Decrement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecrementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CountersServer).Decrement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Counters_Decrement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CountersServer).Decrement(ctx, req.(*DecrementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Counters_IncrementIfLessThan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementIfLessThanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CountersServer).IncrementIfLessThan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Counters_IncrementIfLessThan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CountersServer).IncrementIfLessThan(ctx, req.(*IncrementIfLessThanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Counters_IncrementScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CountersServer).IncrementScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Counters_IncrementScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CountersServer).IncrementScore(ctx, req.(*IncrementScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Counters_GetLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CountersServer).GetLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Counters_GetLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CountersServer).GetLeaderboard(ctx, req.(*GetLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Counters_WatchCounters_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCountersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CountersServer).WatchCounters(m, &countersWatchCountersServer{stream})
+}
+
+type Counters_WatchCountersServer interface {
+	Send(*Counter) error
+	grpc.ServerStream
+}
+
+type countersWatchCountersServer struct {
+	grpc.ServerStream
+}
+
+func (x *countersWatchCountersServer) Send(m *Counter) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Counters_ServiceDesc is the grpc.ServiceDesc for Counters service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Counters_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "counters.Counters",
+	HandlerType: (*CountersServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Increment",
+			Handler:    _Counters_Increment_Handler,
+		},
+		{
+			MethodName: "GetCounter",
+			Handler:    _Counters_GetCounter_Handler,
+		},
+		{
+			MethodName: "BatchIncrement",
+			Handler:    _Counters_BatchIncrement_Handler,
+		},
+		{
+			MethodName: "QueryCounters",
+			Handler:    _Counters_QueryCounters_Handler,
+		},
+		{
+			MethodName: "Decrement",
+			Handler:    _Counters_Decrement_Handler,
+		},
+		{
+			MethodName: "IncrementIfLessThan",
+			Handler:    _Counters_IncrementIfLessThan_Handler,
+		},
+		{
+			MethodName: "IncrementScore",
+			Handler:    _Counters_IncrementScore_Handler,
+		},
+		{
+			MethodName: "GetLeaderboard",
+			Handler:    _Counters_GetLeaderboard_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCounters",
+			Handler:       _Counters_WatchCounters_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/counters.proto",
+}
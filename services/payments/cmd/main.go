@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/suuupra/payments/internal/middleware"
 	"github.com/suuupra/payments/internal/repository"
 	"github.com/suuupra/payments/internal/services"
+	"github.com/suuupra/payments/pkg/kafka"
 	"github.com/suuupra/payments/pkg/logger"
 	"github.com/suuupra/payments/pkg/metrics"
 	"github.com/suuupra/payments/pkg/redis"
@@ -64,6 +66,11 @@ func main() {
 		logger.WithError(err).Fatal("Failed to run migrations")
 	}
 
+	// Scope API key requests to their merchant's rows
+	if err := services.RegisterTenancy(db); err != nil {
+		logger.WithError(err).Fatal("Failed to set up tenancy")
+	}
+
 	// Initialize repositories
 	repos := repository.NewRepositories(db)
 
@@ -74,14 +81,41 @@ func main() {
 	}
 	defer upiClient.Close()
 
+	// Async payments are queued on Kafka, when brokers are configured
+	var commandQueue services.PaymentCommandQueue
+	if cfg.KafkaBrokers != "" {
+		queue, err := kafka.NewQueue(strings.Split(cfg.KafkaBrokers, ","), cfg.PaymentCommandTopic, cfg.PaymentCommandGroup)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize payment command queue")
+		}
+		defer queue.Close()
+		commandQueue = queue
+	}
+
 	services := services.NewServices(services.Dependencies{
-		Repos:     repos,
-		Redis:     redisClient,
-		UPIClient: upiClient,
-		Logger:    logger,
-		Config:    cfg,
+		Repos:        repos,
+		Redis:        redisClient,
+		UPIClient:    upiClient,
+		CommandQueue: commandQueue,
+		Logger:       logger,
+		Config:       cfg,
 	})
 
+	// Purge expired idempotency keys, retry payments, replay dead-lettered
+	// UPI calls, process async payments and refunds, deliver webhooks,
+	// reconcile settlements and generate reports in the background
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go services.Idempotency.StartCleanupTask(backgroundCtx)
+	go services.Payment.StartRetryWorker(backgroundCtx)
+	go services.Payment.StartUPIDeadLetterWorker(backgroundCtx)
+	go services.Commands.StartOutboxRelay(backgroundCtx)
+	go services.Commands.StartCommandWorkers(backgroundCtx)
+	go services.Refund.StartRefundWorker(backgroundCtx)
+	go services.Webhook.StartDeliveryWorkers(backgroundCtx)
+	go services.Recon.StartReconWorker(backgroundCtx)
+	go services.Reports.StartReportWorker(backgroundCtx)
+
 	// Initialize handlers
 	handlers := handlers.NewHandlers(services, logger)
 
@@ -146,33 +180,103 @@ func setupRouter(cfg *config.Config, handlers *handlers.Handlers, logger *logrus
 	// Metrics endpoint
 	router.GET("/metrics", gin.WrapH(metrics.Handler()))
 
-	// API v1 routes
+	// API v1 routes. API keys need the scope a route requires; JWTs are
+	// not scoped.
+	scope := middleware.RequireScope
 	v1 := router.Group("/api/v1")
-	v1.Use(middleware.Authentication(cfg.JWTSecret))
+	v1.Use(middleware.Authentication(cfg.JWTSecret, handlers.Services.APIKeys))
+	if cfg.RateLimitEnabled {
+		v1.Use(middleware.RateLimit(handlers.Services.RateLimits, logger))
+	}
 	v1.Use(middleware.Idempotency(handlers.Services.Idempotency))
 	{
 		// Payment routes
-		v1.POST("/intents", handlers.CreatePaymentIntent)
-		v1.GET("/intents/:id", handlers.GetPaymentIntent)
-		v1.POST("/payments", handlers.CreatePayment)
-		v1.GET("/payments/:id", handlers.GetPayment)
+		v1.POST("/intents", scope(services.ScopePaymentsWrite), handlers.CreatePaymentIntent)
+		v1.GET("/intents/:id", scope(services.ScopePaymentsRead), handlers.GetPaymentIntent)
+		v1.POST("/intents/:id/payment_method", scope(services.ScopePaymentsWrite), handlers.AttachPaymentMethod)
+		v1.POST("/intents/:id/cancel", scope(services.ScopePaymentsWrite), handlers.CancelPaymentIntent)
+		v1.GET("/intents/:id/transitions", scope(services.ScopePaymentsRead), handlers.ListPaymentIntentTransitions)
+		v1.POST("/payments", scope(services.ScopePaymentsWrite), handlers.CreatePayment)
+		v1.GET("/payments/:id", scope(services.ScopePaymentsRead), handlers.GetPayment)
+		v1.POST("/payments/:id/capture", scope(services.ScopePaymentsWrite), handlers.CapturePayment)
+		v1.GET("/payments/:id/attempts", scope(services.ScopePaymentsRead), handlers.ListPaymentAttempts)
+		v1.GET("/payments/:id/events", scope(services.ScopePaymentsRead), handlers.ListPaymentEvents)
+		v1.GET("/payment-commands/:id", scope(services.ScopePaymentsRead), handlers.GetPaymentCommand)
+
+		// Saved payment methods
+		v1.POST("/customers/:customer_id/payment-methods", scope(services.ScopePaymentMethodsWrite), handlers.CreatePaymentMethod)
+		v1.GET("/customers/:customer_id/payment-methods", scope(services.ScopePaymentMethodsRead), handlers.ListPaymentMethods)
+		v1.DELETE("/customers/:customer_id/payment-methods/:token", scope(services.ScopePaymentMethodsWrite), handlers.DeletePaymentMethod)
 
 		// Refund routes
-		v1.POST("/refunds", handlers.CreateRefund)
-		v1.GET("/refunds/:id", handlers.GetRefund)
+		v1.POST("/refunds", scope(services.ScopeRefundsWrite), handlers.CreateRefund)
+		v1.GET("/refunds/:id", scope(services.ScopeRefundsRead), handlers.GetRefund)
+		v1.POST("/refunds/:id/cancel", scope(services.ScopeRefundsWrite), handlers.CancelRefund)
+		v1.GET("/payments/:id/refunds", scope(services.ScopeRefundsRead), handlers.ListPaymentRefunds)
+
+		// Payment rails
+		v1.GET("/rails", scope(services.ScopeRailsRead), handlers.ListMerchantRails)
+		v1.PUT("/rails/:rail", scope(services.ScopeRailsWrite), handlers.UpdateMerchantRail)
+
+		// Ledger routes
+		v1.GET("/ledger/accounts", scope(services.ScopeLedgerRead), handlers.ListLedgerAccounts)
+		v1.GET("/ledger/accounts/:id", scope(services.ScopeLedgerRead), handlers.GetLedgerAccount)
+		v1.GET("/ledger/accounts/:id/statement", scope(services.ScopeLedgerRead), handlers.GetLedgerStatement)
+		v1.POST("/ledger/payouts", scope(services.ScopeLedgerWrite), handlers.CreatePayout)
 
 		// Risk assessment
-		v1.POST("/risk/assess", handlers.AssessRisk)
+		v1.POST("/risk/assess", scope(services.ScopeRiskWrite), handlers.AssessRisk)
+		v1.GET("/risk/rules", scope(services.ScopeRiskRead), handlers.ListRiskRules)
+		v1.POST("/risk/rules", scope(services.ScopeRiskWrite), handlers.CreateRiskRule)
+		v1.POST("/risk/rules/test", scope(services.ScopeRiskRead), handlers.TestRiskRule)
+		v1.GET("/risk/rules/:id", scope(services.ScopeRiskRead), handlers.GetRiskRule)
+		v1.PUT("/risk/rules/:id", scope(services.ScopeRiskWrite), handlers.UpdateRiskRule)
+		v1.DELETE("/risk/rules/:id", scope(services.ScopeRiskWrite), handlers.DeleteRiskRule)
 
 		// Webhook routes
-		v1.POST("/webhooks/endpoints", handlers.CreateWebhookEndpoint)
-		v1.GET("/webhooks/endpoints", handlers.ListWebhookEndpoints)
-		v1.PUT("/webhooks/endpoints/:id", handlers.UpdateWebhookEndpoint)
-		v1.DELETE("/webhooks/endpoints/:id", handlers.DeleteWebhookEndpoint)
+		v1.POST("/webhooks/endpoints", scope(services.ScopeWebhooksWrite), handlers.CreateWebhookEndpoint)
+		v1.GET("/webhooks/endpoints", scope(services.ScopeWebhooksRead), handlers.ListWebhookEndpoints)
+		v1.PUT("/webhooks/endpoints/:id", scope(services.ScopeWebhooksWrite), handlers.UpdateWebhookEndpoint)
+		v1.DELETE("/webhooks/endpoints/:id", scope(services.ScopeWebhooksWrite), handlers.DeleteWebhookEndpoint)
+		v1.GET("/webhooks/endpoints/:id/deliveries", scope(services.ScopeWebhooksRead), handlers.ListWebhookDeliveries)
+		v1.POST("/webhooks/endpoints/:id/redeliver", scope(services.ScopeWebhooksWrite), handlers.RedeliverDeadLetters)
+		v1.POST("/webhooks/endpoints/:id/rotate-secret", scope(services.ScopeWebhooksWrite), handlers.RotateWebhookSecret)
+		v1.POST("/webhooks/deliveries/:id/redeliver", scope(services.ScopeWebhooksWrite), handlers.RedeliverWebhook)
+
+		// Payment, refund and fee exports
+		v1.POST("/reports", scope(services.ScopeReportsWrite), handlers.CreateReport)
+		v1.GET("/reports", scope(services.ScopeReportsRead), handlers.ListReports)
+		v1.GET("/reports/:id", scope(services.ScopeReportsRead), handlers.GetReport)
+		v1.GET("/reports/:id/download", scope(services.ScopeReportsRead), handlers.DownloadReport)
+
+		// Settlement reconciliation exceptions, resolved with a JWT only
+		v1.GET("/reconciliation/exceptions", scope(services.ScopeReconciliationWrite), handlers.ListReconciliationExceptions)
+		v1.POST("/reconciliation/exceptions/:id/resolve", scope(services.ScopeReconciliationWrite), handlers.ResolveReconciliationException)
+
+		// UPI dead letters, replayed or discarded with a JWT only
+		v1.GET("/upi/dead-letters", scope(services.ScopeUPIDeadLettersWrite), handlers.ListUPIDeadLetters)
+		v1.POST("/upi/dead-letters/replay", scope(services.ScopeUPIDeadLettersWrite), handlers.ReplayUPIDeadLetters)
+		v1.GET("/upi/dead-letters/:id", scope(services.ScopeUPIDeadLettersWrite), handlers.GetUPIDeadLetter)
+		v1.POST("/upi/dead-letters/:id/replay", scope(services.ScopeUPIDeadLettersWrite), handlers.ReplayUPIDeadLetter)
+		v1.POST("/upi/dead-letters/:id/discard", scope(services.ScopeUPIDeadLettersWrite), handlers.DiscardUPIDeadLetter)
+
+		// Merchants' rate limits, managed with a JWT only
+		v1.GET("/rate-limits/:merchant_id", scope(services.ScopeRateLimitsWrite), handlers.GetMerchantRateLimits)
+		v1.PUT("/rate-limits/:merchant_id", scope(services.ScopeRateLimitsWrite), handlers.UpdateMerchantRateLimits)
+
+		// API keys, managed with a JWT only
+		v1.GET("/api-keys", scope(services.ScopeAPIKeysWrite), handlers.ListAPIKeys)
+		v1.POST("/api-keys", scope(services.ScopeAPIKeysWrite), handlers.CreateAPIKey)
+		v1.POST("/api-keys/:id/rotate", scope(services.ScopeAPIKeysWrite), handlers.RotateAPIKey)
+		v1.DELETE("/api-keys/:id", scope(services.ScopeAPIKeysWrite), handlers.RevokeAPIKey)
 	}
 
 	// Webhook delivery endpoint (no auth required)
 	router.POST("/webhooks/receive/:endpoint_id", handlers.ReceiveWebhook)
 
+	// 3-D Secure callback, reached by the payer's browser (no auth required)
+	router.GET("/api/v1/three-ds/callback/:payment_id", handlers.ThreeDSCallback)
+	router.POST("/api/v1/three-ds/callback/:payment_id", handlers.ThreeDSCallback)
+
 	return router
 }
\ No newline at end of file
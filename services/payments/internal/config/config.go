@@ -32,11 +32,64 @@ type Config struct {
 	UPICoreTimeout    string `env:"UPI_CORE_TIMEOUT" default:"30s"`
 	UPICoreMaxRetries int    `env:"UPI_CORE_MAX_RETRIES" default:"3"`
 
+	// Payment rail configuration. Card and netbanking are only routed to
+	// when their gateway URL is set.
+	DefaultRails              string `env:"DEFAULT_RAILS" default:"upi"` // Comma-separated
+	CardGatewayURL            string `env:"CARD_GATEWAY_URL" default:""`
+	CardGatewayAPIKey         string `env:"CARD_GATEWAY_API_KEY" default:""`
+	NetbankingGatewayURL      string `env:"NETBANKING_GATEWAY_URL" default:""`
+	NetbankingGatewayAPIKey   string `env:"NETBANKING_GATEWAY_API_KEY" default:""`
+	RailGatewayTimeoutSeconds int    `env:"RAIL_GATEWAY_TIMEOUT_SECONDS" default:"30"`
+
+	// Payment retry configuration. Rules are category=attempts:delay[:fallback];
+	// retries delayed longer than the inline maximum run in the background.
+	PaymentRetryPolicy                string `env:"PAYMENT_RETRY_POLICY" default:"bank_unavailable=3:30s:fallback,timeout=2:2s,rail_unavailable=3:1s:fallback"`
+	PaymentFallbackRails              string `env:"PAYMENT_FALLBACK_RAILS" default:"upi,card,netbanking"` // Comma-separated, in order
+	PaymentRetryMaxInlineDelaySeconds int    `env:"PAYMENT_RETRY_MAX_INLINE_DELAY_SECONDS" default:"5"`
+
+	// 3-D Secure configuration. Card payers come back to the callback URL
+	// after a challenge; unanswered challenges fail after the timeout.
+	ThreeDSCallbackURL             string `env:"THREE_DS_CALLBACK_URL" default:"http://localhost:8084/api/v1/three-ds/callback"`
+	ThreeDSChallengeTimeoutMinutes int    `env:"THREE_DS_CHALLENGE_TIMEOUT_MINUTES" default:"15"`
+	ThreeDSOnRiskReview            bool   `env:"THREE_DS_ON_RISK_REVIEW" default:"true"` // Step up card payments the risk engine flags for review
+
+	// Manual capture configuration. Authorizations not captured within the
+	// window are voided.
+	AuthCaptureWindowHours int `env:"AUTH_CAPTURE_WINDOW_HOURS" default:"168"`
+
+	// UPI dead letter configuration. Payments UPI Core could not be reached
+	// for are kept processing and replayed every interval once it recovers.
+	UPIDeadLetterEnabled               bool `env:"UPI_DEAD_LETTER_ENABLED" default:"true"`
+	UPIDeadLetterReplayIntervalSeconds int  `env:"UPI_DEAD_LETTER_REPLAY_INTERVAL_SECONDS" default:"60"`
+	UPIDeadLetterMaxReplays            int  `env:"UPI_DEAD_LETTER_MAX_REPLAYS" default:"10"`
+
+	// Async payment configuration. Async payments are queued on Kafka and
+	// processed by the command workers; no brokers disables them.
+	KafkaBrokers          string `env:"KAFKA_BROKERS" default:""` // Comma-separated
+	PaymentCommandTopic   string `env:"PAYMENT_COMMAND_TOPIC" default:"payments.commands"`
+	PaymentCommandGroup   string `env:"PAYMENT_COMMAND_GROUP" default:"payments-processor"`
+	PaymentCommandWorkers int    `env:"PAYMENT_COMMAND_WORKERS" default:"8"`
+
+	// Refund configuration. Refunds a worker claimed but did not get to the
+	// rail within the timeout are sent again.
+	RefundSubmitTimeoutSeconds int `env:"REFUND_SUBMIT_TIMEOUT_SECONDS" default:"60"`
+
+	// Settlement reconciliation configuration. UPI Core's settlement reports
+	// of these banks are reconciled; none disables reconciliation.
+	ReconBankCodes       string `env:"RECON_BANK_CODES" default:""` // Comma-separated
+	ReconIntervalMinutes int    `env:"RECON_INTERVAL_MINUTES" default:"60"`
+	ReconLookbackHours   int    `env:"RECON_LOOKBACK_HOURS" default:"72"`
+
+	// Ledger configuration
+	PlatformFeeBPS int `env:"PLATFORM_FEE_BPS" default:"200"` // Basis points of each payment
+
 	// Security configuration
-	JWTSecret             string `env:"JWT_SECRET" required:"true"`
-	HMACSigningSecret     string `env:"HMAC_SIGNING_SECRET" required:"true"`
-	FieldEncryptionKey    string `env:"FIELD_ENCRYPTION_KEY" required:"true"`
-	WebhookSigningSecret  string `env:"WEBHOOK_SIGNING_SECRET" required:"true"`
+	JWTSecret                   string `env:"JWT_SECRET" required:"true"`
+	HMACSigningSecret           string `env:"HMAC_SIGNING_SECRET" required:"true"`
+	FieldEncryptionKey          string `env:"FIELD_ENCRYPTION_KEY" required:"true"`
+	FieldEncryptionKeyVersion   int    `env:"FIELD_ENCRYPTION_KEY_VERSION" default:"1"`
+	FieldEncryptionPreviousKeys string `env:"FIELD_ENCRYPTION_PREVIOUS_KEYS" default:""` // version:secret, comma-separated
+	WebhookSigningSecret        string `env:"WEBHOOK_SIGNING_SECRET" required:"true"`
 
 	// Observability configuration
 	LogLevel        string `env:"LOG_LEVEL" default:"info"`
@@ -45,22 +98,32 @@ type Config struct {
 	MetricsPort     string `env:"METRICS_PORT" default:"9090"`
 
 	// Business Logic configuration
-	MaxRetryAttempts          int `env:"MAX_RETRY_ATTEMPTS" default:"3"`
-	IdempotencyTTLHours       int `env:"IDEMPOTENCY_TTL_HOURS" default:"24"`
-	WebhookTimeoutSeconds     int `env:"WEBHOOK_TIMEOUT_SECONDS" default:"30"`
-	MaxWebhookRetries         int `env:"MAX_WEBHOOK_RETRIES" default:"5"`
-	PaymentIntentExpiryMinutes int `env:"PAYMENT_INTENT_EXPIRY_MINUTES" default:"15"`
-	MaxRefundAgeDays          int `env:"MAX_REFUND_AGE_DAYS" default:"90"`
+	MaxRetryAttempts              int `env:"MAX_RETRY_ATTEMPTS" default:"3"`
+	IdempotencyTTLHours           int `env:"IDEMPOTENCY_TTL_HOURS" default:"24"`
+	IdempotencyLockTimeoutSeconds int `env:"IDEMPOTENCY_LOCK_TIMEOUT_SECONDS" default:"60"`
+	IdempotencyWaitTimeoutSeconds int `env:"IDEMPOTENCY_WAIT_TIMEOUT_SECONDS" default:"10"`
+	WebhookTimeoutSeconds         int `env:"WEBHOOK_TIMEOUT_SECONDS" default:"30"`
+	MaxWebhookRetries             int `env:"MAX_WEBHOOK_RETRIES" default:"5"`
+	WebhookWorkers                int `env:"WEBHOOK_WORKERS" default:"8"`
+	WebhookBackoffBaseSeconds     int `env:"WEBHOOK_BACKOFF_BASE_SECONDS" default:"60"`
+	WebhookBackoffMaxSeconds      int `env:"WEBHOOK_BACKOFF_MAX_SECONDS" default:"3600"`
+	WebhookDisableAfterFailures   int `env:"WEBHOOK_DISABLE_AFTER_FAILURES" default:"20"` // Consecutive, and
+	WebhookDisableAfterHours      int `env:"WEBHOOK_DISABLE_AFTER_HOURS" default:"24"`    // failing for this long
+	WebhookSecretOverlapHours     int `env:"WEBHOOK_SECRET_OVERLAP_HOURS" default:"24"`
+	APIKeyRotationGraceHours      int `env:"API_KEY_ROTATION_GRACE_HOURS" default:"24"`
+	PaymentIntentExpiryMinutes    int `env:"PAYMENT_INTENT_EXPIRY_MINUTES" default:"15"`
+	MaxRefundAgeDays              int `env:"MAX_REFUND_AGE_DAYS" default:"90"`
 
 	// Rate Limiting configuration
-	RateLimitEnabled           bool `env:"RATE_LIMIT_ENABLED" default:"true"`
-	RateLimitRequestsPerMinute int  `env:"RATE_LIMIT_REQUESTS_PER_MINUTE" default:"1000"`
-	RateLimitBurstSize         int  `env:"RATE_LIMIT_BURST_SIZE" default:"100"`
+	RateLimitEnabled         bool `env:"RATE_LIMIT_ENABLED" default:"true"`
+	RateLimitReadsPerMinute  int  `env:"RATE_LIMIT_READS_PER_MINUTE" default:"1000"`   // Per merchant, unless
+	RateLimitWritesPerMinute int  `env:"RATE_LIMIT_WRITES_PER_MINUTE" default:"200"`   // it has its own
+	RateLimitGlobalPerMinute int  `env:"RATE_LIMIT_GLOBAL_PER_MINUTE" default:"20000"` // All clients; 0 is none
 
 	// Risk Assessment configuration
 	RiskAssessmentEnabled   bool `env:"RISK_ASSESSMENT_ENABLED" default:"true"`
-	RiskHighThreshold       int  `env:"RISK_HIGH_THRESHOLD" default:"75"`
-	RiskMediumThreshold     int  `env:"RISK_MEDIUM_THRESHOLD" default:"50"`
+	RiskHighThreshold       int  `env:"RISK_HIGH_THRESHOLD" default:"75"`   // Score percent blocking payments
+	RiskMediumThreshold     int  `env:"RISK_MEDIUM_THRESHOLD" default:"50"` // Score percent flagging payments for review
 	DefaultRiskWeightAmount int  `env:"DEFAULT_RISK_WEIGHT_AMOUNT" default:"10"`
 	DefaultRiskWeightVelocity int  `env:"DEFAULT_RISK_WEIGHT_VELOCITY" default:"15"`
 	DefaultRiskWeightDevice int  `env:"DEFAULT_RISK_WEIGHT_DEVICE" default:"20"`
@@ -108,10 +171,55 @@ func Load() *Config {
 	cfg.UPICoreTimeout = getEnv("UPI_CORE_TIMEOUT", "30s")
 	cfg.UPICoreMaxRetries = getEnvAsInt("UPI_CORE_MAX_RETRIES", 3)
 	
+	// Payment rails
+	cfg.DefaultRails = getEnv("DEFAULT_RAILS", "upi")
+	cfg.CardGatewayURL = getEnv("CARD_GATEWAY_URL", "")
+	cfg.CardGatewayAPIKey = getEnv("CARD_GATEWAY_API_KEY", "")
+	cfg.NetbankingGatewayURL = getEnv("NETBANKING_GATEWAY_URL", "")
+	cfg.NetbankingGatewayAPIKey = getEnv("NETBANKING_GATEWAY_API_KEY", "")
+	cfg.RailGatewayTimeoutSeconds = getEnvAsInt("RAIL_GATEWAY_TIMEOUT_SECONDS", 30)
+
+	// Payment retries
+	cfg.PaymentRetryPolicy = getEnv("PAYMENT_RETRY_POLICY", "bank_unavailable=3:30s:fallback,timeout=2:2s,rail_unavailable=3:1s:fallback")
+	cfg.PaymentFallbackRails = getEnv("PAYMENT_FALLBACK_RAILS", "upi,card,netbanking")
+	cfg.PaymentRetryMaxInlineDelaySeconds = getEnvAsInt("PAYMENT_RETRY_MAX_INLINE_DELAY_SECONDS", 5)
+
+	// 3-D Secure
+	cfg.ThreeDSCallbackURL = getEnv("THREE_DS_CALLBACK_URL", "http://localhost:8084/api/v1/three-ds/callback")
+	cfg.ThreeDSChallengeTimeoutMinutes = getEnvAsInt("THREE_DS_CHALLENGE_TIMEOUT_MINUTES", 15)
+	cfg.ThreeDSOnRiskReview = getEnvAsBool("THREE_DS_ON_RISK_REVIEW", true)
+
+	// Manual capture
+	cfg.AuthCaptureWindowHours = getEnvAsInt("AUTH_CAPTURE_WINDOW_HOURS", 168)
+
+	// UPI dead letters
+	cfg.UPIDeadLetterEnabled = getEnvAsBool("UPI_DEAD_LETTER_ENABLED", true)
+	cfg.UPIDeadLetterReplayIntervalSeconds = getEnvAsInt("UPI_DEAD_LETTER_REPLAY_INTERVAL_SECONDS", 60)
+	cfg.UPIDeadLetterMaxReplays = getEnvAsInt("UPI_DEAD_LETTER_MAX_REPLAYS", 10)
+
+	// Async payments
+	cfg.KafkaBrokers = getEnv("KAFKA_BROKERS", "")
+	cfg.PaymentCommandTopic = getEnv("PAYMENT_COMMAND_TOPIC", "payments.commands")
+	cfg.PaymentCommandGroup = getEnv("PAYMENT_COMMAND_GROUP", "payments-processor")
+	cfg.PaymentCommandWorkers = getEnvAsInt("PAYMENT_COMMAND_WORKERS", 8)
+
+	// Refunds
+	cfg.RefundSubmitTimeoutSeconds = getEnvAsInt("REFUND_SUBMIT_TIMEOUT_SECONDS", 60)
+
+	// Settlement reconciliation
+	cfg.ReconBankCodes = getEnv("RECON_BANK_CODES", "")
+	cfg.ReconIntervalMinutes = getEnvAsInt("RECON_INTERVAL_MINUTES", 60)
+	cfg.ReconLookbackHours = getEnvAsInt("RECON_LOOKBACK_HOURS", 72)
+
+	// Ledger
+	cfg.PlatformFeeBPS = getEnvAsInt("PLATFORM_FEE_BPS", 200)
+	
 	// Security - these should be overridden in production
 	cfg.JWTSecret = getEnv("JWT_SECRET", "dev-jwt-secret-key")
 	cfg.HMACSigningSecret = getEnv("HMAC_SIGNING_SECRET", "dev-hmac-signing-secret")
 	cfg.FieldEncryptionKey = getEnv("FIELD_ENCRYPTION_KEY", "dev-32-character-encryption-key!!")
+	cfg.FieldEncryptionKeyVersion = getEnvAsInt("FIELD_ENCRYPTION_KEY_VERSION", 1)
+	cfg.FieldEncryptionPreviousKeys = getEnv("FIELD_ENCRYPTION_PREVIOUS_KEYS", "")
 	cfg.WebhookSigningSecret = getEnv("WEBHOOK_SIGNING_SECRET", "dev-webhook-signing-secret")
 	
 	// Observability
@@ -123,15 +231,25 @@ func Load() *Config {
 	// Business Logic
 	cfg.MaxRetryAttempts = getEnvAsInt("MAX_RETRY_ATTEMPTS", 3)
 	cfg.IdempotencyTTLHours = getEnvAsInt("IDEMPOTENCY_TTL_HOURS", 24)
+	cfg.IdempotencyLockTimeoutSeconds = getEnvAsInt("IDEMPOTENCY_LOCK_TIMEOUT_SECONDS", 60)
+	cfg.IdempotencyWaitTimeoutSeconds = getEnvAsInt("IDEMPOTENCY_WAIT_TIMEOUT_SECONDS", 10)
 	cfg.WebhookTimeoutSeconds = getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 30)
 	cfg.MaxWebhookRetries = getEnvAsInt("MAX_WEBHOOK_RETRIES", 5)
+	cfg.WebhookWorkers = getEnvAsInt("WEBHOOK_WORKERS", 8)
+	cfg.WebhookBackoffBaseSeconds = getEnvAsInt("WEBHOOK_BACKOFF_BASE_SECONDS", 60)
+	cfg.WebhookBackoffMaxSeconds = getEnvAsInt("WEBHOOK_BACKOFF_MAX_SECONDS", 3600)
+	cfg.WebhookDisableAfterFailures = getEnvAsInt("WEBHOOK_DISABLE_AFTER_FAILURES", 20)
+	cfg.WebhookDisableAfterHours = getEnvAsInt("WEBHOOK_DISABLE_AFTER_HOURS", 24)
+	cfg.WebhookSecretOverlapHours = getEnvAsInt("WEBHOOK_SECRET_OVERLAP_HOURS", 24)
+	cfg.APIKeyRotationGraceHours = getEnvAsInt("API_KEY_ROTATION_GRACE_HOURS", 24)
 	cfg.PaymentIntentExpiryMinutes = getEnvAsInt("PAYMENT_INTENT_EXPIRY_MINUTES", 15)
 	cfg.MaxRefundAgeDays = getEnvAsInt("MAX_REFUND_AGE_DAYS", 90)
 	
 	// Rate Limiting
 	cfg.RateLimitEnabled = getEnvAsBool("RATE_LIMIT_ENABLED", true)
-	cfg.RateLimitRequestsPerMinute = getEnvAsInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 1000)
-	cfg.RateLimitBurstSize = getEnvAsInt("RATE_LIMIT_BURST_SIZE", 100)
+	cfg.RateLimitReadsPerMinute = getEnvAsInt("RATE_LIMIT_READS_PER_MINUTE", 1000)
+	cfg.RateLimitWritesPerMinute = getEnvAsInt("RATE_LIMIT_WRITES_PER_MINUTE", 200)
+	cfg.RateLimitGlobalPerMinute = getEnvAsInt("RATE_LIMIT_GLOBAL_PER_MINUTE", 20000)
 	
 	// Risk Assessment
 	cfg.RiskAssessmentEnabled = getEnvAsBool("RISK_ASSESSMENT_ENABLED", true)
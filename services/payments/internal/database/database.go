@@ -22,13 +22,28 @@ func Connect(databaseURL string) (*gorm.DB, error) {
 	// Auto-migrate schemas
 	err = db.AutoMigrate(
 		&models.PaymentIntent{},
+		&models.PaymentIntentTransition{},
 		&models.Payment{},
+		&models.PaymentEvent{},
 		&models.Refund{},
 		&models.LedgerEntry{},
+		&models.LedgerAccount{},
+		&models.JournalEntry{},
 		&models.IdempotencyKey{},
 		&models.WebhookEndpoint{},
 		&models.WebhookDelivery{},
+		&models.MerchantRail{},
+		&models.MerchantRateLimit{},
+		&models.PaymentAttempt{},
 		&models.RiskAssessment{},
+		&models.RiskRule{},
+		&models.MerchantAPIKey{},
+		&models.ReconciliationException{},
+		&models.PaymentMethod{},
+		&models.PaymentCommand{},
+		&models.Report{},
+		&models.ThreeDSChallenge{},
+		&models.UPIDeadLetter{},
 		&models.OutboxEvent{},
 	)
 	if err != nil {
@@ -1,20 +1,31 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"github.com/suuupra/payments/internal/services"
+	"github.com/suuupra/payments/pkg/webhooksig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// geoCountryHeader carries the country the edge geolocated the client's
+// IP address to
+const geoCountryHeader = "X-Geo-Country"
+
 // Handlers contains all HTTP handlers
 type Handlers struct {
 	Services *services.Services
@@ -136,6 +147,44 @@ func (h *Handlers) Ready(c *gin.Context) {
 	})
 }
 
+// merchantAllowed rejects requests of a merchant's API key that name
+// another merchant. Operators may name any.
+func (h *Handlers) merchantAllowed(c *gin.Context, merchantID uuid.UUID) bool {
+	tenant, ok := services.MerchantFromContext(c.Request.Context())
+	if !ok || tenant == merchantID {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": "API key belongs to another merchant",
+		"code":  "MERCHANT_MISMATCH",
+	})
+	return false
+}
+
+// queryMerchantID returns the merchant in ?merchant_id=, which defaults to
+// that of the request's API key
+func (h *Handlers) queryMerchantID(c *gin.Context) (uuid.UUID, bool) {
+	merchantIDStr := c.Query("merchant_id")
+	if merchantIDStr == "" {
+		if tenant, ok := services.MerchantFromContext(c.Request.Context()); ok {
+			return tenant, true
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "merchant_id query parameter is required",
+		})
+		return uuid.Nil, false
+	}
+
+	merchantID, err := uuid.Parse(merchantIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid merchant ID",
+		})
+		return uuid.Nil, false
+	}
+	return merchantID, h.merchantAllowed(c, merchantID)
+}
+
 // CreatePaymentIntent creates a new payment intent
 func (h *Handlers) CreatePaymentIntent(c *gin.Context) {
 	var req services.CreatePaymentIntentRequest
@@ -146,9 +195,20 @@ func (h *Handlers) CreatePaymentIntent(c *gin.Context) {
 		})
 		return
 	}
+	if !h.merchantAllowed(c, req.MerchantID) {
+		return
+	}
 
 	intent, err := h.Services.Payment.CreatePaymentIntent(c.Request.Context(), req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "amount") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid amount",
+				"details": err.Error(),
+			})
+			return
+		}
+
 		h.Logger.WithError(err).Error("Failed to create payment intent")
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create payment intent",
@@ -190,9 +250,22 @@ func (h *Handlers) GetPaymentIntent(c *gin.Context) {
 	c.JSON(http.StatusOK, intent)
 }
 
-// CreatePayment creates and processes a payment
-func (h *Handlers) CreatePayment(c *gin.Context) {
-	var req services.CreatePaymentRequest
+// AttachPaymentMethodRequest is the body of AttachPaymentMethod
+type AttachPaymentMethodRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"required"`
+}
+
+// AttachPaymentMethod sets the payment method of an intent awaiting one
+func (h *Handlers) AttachPaymentMethod(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid payment intent ID",
+		})
+		return
+	}
+
+	var req AttachPaymentMethodRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request body",
@@ -201,56 +274,134 @@ func (h *Handlers) CreatePayment(c *gin.Context) {
 		return
 	}
 
-	// Get client IP and User-Agent
-	req.IPAddress = c.ClientIP()
-	req.UserAgent = c.GetHeader("User-Agent")
+	intent, err := h.Services.Payment.AttachPaymentMethod(c.Request.Context(), id, req.PaymentMethod)
+	if err != nil {
+		h.paymentIntentError(c, err, "Failed to attach payment method")
+		return
+	}
 
-	payment, err := h.Services.Payment.CreatePayment(c.Request.Context(), req)
+	c.JSON(http.StatusOK, intent)
+}
+
+// CancelPaymentIntentRequest is the body of CancelPaymentIntent
+type CancelPaymentIntentRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CancelPaymentIntent cancels an intent that is not yet being processed,
+// voiding its payment if it is awaiting capture
+func (h *Handlers) CancelPaymentIntent(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		h.Logger.WithError(err).Error("Failed to create payment")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create payment",
-			"details": err.Error(),
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid payment intent ID",
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, payment)
+	var req CancelPaymentIntentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	intent, err := h.Services.Payment.CancelPaymentIntent(c.Request.Context(), id, req.Reason)
+	if err != nil {
+		h.paymentIntentError(c, err, "Failed to cancel payment intent")
+		return
+	}
+
+	c.JSON(http.StatusOK, intent)
 }
 
-// GetPayment retrieves a payment by ID
-func (h *Handlers) GetPayment(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
+// ListPaymentIntentTransitions returns the status history of an intent
+func (h *Handlers) ListPaymentIntentTransitions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid payment ID",
+			"error": "Invalid payment intent ID",
 		})
 		return
 	}
 
-	payment, err := h.Services.Payment.GetPayment(c.Request.Context(), id)
-	if err != nil {
-		if err.Error() == "payment not found" {
+	if _, err := h.Services.Payment.GetPaymentIntent(c.Request.Context(), id); err != nil {
+		if err.Error() == "payment intent not found" {
 			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Payment not found",
+				"error": "Payment intent not found",
 			})
 			return
 		}
 
-		h.Logger.WithError(err).Error("Failed to get payment")
+		h.Logger.WithError(err).Error("Failed to get payment intent")
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get payment",
+			"error": "Failed to get payment intent",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, payment)
+	transitions, err := h.Services.Payment.GetPaymentIntentTransitions(c.Request.Context(), id)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to get payment intent transitions")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get payment intent transitions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transitions": transitions,
+	})
 }
 
-// CreateRefund creates and processes a refund
-func (h *Handlers) CreateRefund(c *gin.Context) {
-	var req services.CreateRefundRequest
+// paymentIntentError responds with the status of a payment intent error:
+// transitions the intent's status does not allow, or that lost a race with
+// another request, are conflicts; payment methods without a rail the
+// merchant can use are unprocessable
+func (h *Handlers) paymentIntentError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "payment intent not found":
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Payment intent not found",
+		})
+	case errors.Is(err, services.ErrInvalidIntentTransition),
+		errors.Is(err, services.ErrPaymentIntentConflict),
+		errors.Is(err, services.ErrPaymentNotCapturable):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrPaymentMethodNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrPaymentMethodMismatch),
+		errors.Is(err, services.ErrAsyncPaymentsUnavailable),
+		errors.Is(err, services.ErrUnsupportedPaymentMethod),
+		errors.Is(err, services.ErrCaptureUnsupported),
+		errors.Is(err, services.ErrRailNotEnabled),
+		errors.Is(err, services.ErrRailUnavailable):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// CreatePayment creates and processes a payment
+func (h *Handlers) CreatePayment(c *gin.Context) {
+	var req services.CreatePaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request body",
@@ -259,200 +410,1634 @@ func (h *Handlers) CreateRefund(c *gin.Context) {
 		return
 	}
 
-	refund, err := h.Services.Refund.CreateRefund(c.Request.Context(), req)
-	if err != nil {
-		h.Logger.WithError(err).Error("Failed to create refund")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create refund",
+	// Get client IP, its country as the edge geolocated it, and User-Agent
+	req.IPAddress = c.ClientIP()
+	req.IPCountry = c.GetHeader(geoCountryHeader)
+	req.UserAgent = c.GetHeader("User-Agent")
+
+	// An async payment is queued, for the client to follow on the command,
+	// its intent or webhooks
+	if req.Async {
+		command, err := h.Services.Commands.EnqueuePayment(c.Request.Context(), req)
+		if err != nil {
+			h.paymentIntentError(c, err, "Failed to queue payment")
+			return
+		}
+		c.JSON(http.StatusAccepted, command)
+		return
+	}
+
+	payment, err := h.Services.Payment.CreatePayment(c.Request.Context(), req)
+	if errors.Is(err, services.ErrPaymentBlocked) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Payment blocked",
 			"details": err.Error(),
+			"payment": payment,
 		})
 		return
 	}
+	if err != nil {
+		h.paymentIntentError(c, err, "Failed to create payment")
+		return
+	}
 
-	c.JSON(http.StatusCreated, refund)
+	c.JSON(http.StatusCreated, payment)
 }
 
-// GetRefund retrieves a refund by ID
-func (h *Handlers) GetRefund(c *gin.Context) {
+// GetPayment retrieves a payment by ID
+func (h *Handlers) GetPayment(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid refund ID",
+			"error": "Invalid payment ID",
 		})
 		return
 	}
 
-	refund, err := h.Services.Refund.GetRefund(c.Request.Context(), id)
+	payment, err := h.Services.Payment.GetPayment(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "refund not found" {
+		if err.Error() == "payment not found" {
 			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Refund not found",
+				"error": "Payment not found",
 			})
 			return
 		}
 
-		h.Logger.WithError(err).Error("Failed to get refund")
+		h.Logger.WithError(err).Error("Failed to get payment")
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get refund",
+			"error": "Failed to get payment",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, refund)
+	c.JSON(http.StatusOK, payment)
 }
 
-// AssessRisk performs risk assessment
-func (h *Handlers) AssessRisk(c *gin.Context) {
-	var req services.RiskAssessmentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+// CapturePaymentRequest is the body of CapturePayment
+type CapturePaymentRequest struct {
+	Amount *decimal.Decimal `json:"amount"` // All of the authorized amount if empty
+}
+
+// CapturePayment captures all or part of a payment authorized for manual
+// capture
+func (h *Handlers) CapturePayment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
+			"error": "Invalid payment ID",
 		})
 		return
 	}
 
-	// Set client context
-	req.IPAddress = c.ClientIP()
-	req.UserAgent = c.GetHeader("User-Agent")
+	var req CapturePaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
 
-	result, err := h.Services.Risk.AssessRisk(c.Request.Context(), req)
+	payment, err := h.Services.Payment.CapturePayment(c.Request.Context(), id, req.Amount)
 	if err != nil {
-		h.Logger.WithError(err).Error("Failed to assess risk")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to assess risk",
-			"details": err.Error(),
-		})
+		h.captureError(c, err, "Failed to capture payment")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"risk_score": result.RiskScore,
-		"risk_level": result.RiskLevel,
-		"decision":   result.Decision,
-		"factors":    result.Factors,
-		"rules":      result.Rules,
-	})
+	c.JSON(http.StatusOK, payment)
 }
 
-// CreateWebhookEndpoint creates a new webhook endpoint
-func (h *Handlers) CreateWebhookEndpoint(c *gin.Context) {
-	var req services.CreateWebhookEndpointRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+// captureError responds with the status of a capture error: invalid
+// amounts are bad requests, payments that are not authorized or whose
+// authorization expired are conflicts, and captures the rail declined or
+// cannot make are unprocessable
+func (h *Handlers) captureError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "payment not found":
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Payment not found",
+		})
+	case strings.HasPrefix(err.Error(), "capture amount"):
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
+			"error":   message,
 			"details": err.Error(),
 		})
-		return
-	}
-
-	endpoint, err := h.Services.Webhook.CreateWebhookEndpoint(c.Request.Context(), req)
-	if err != nil {
-		h.Logger.WithError(err).Error("Failed to create webhook endpoint")
+	case errors.Is(err, services.ErrPaymentNotCapturable),
+		errors.Is(err, services.ErrAuthorizationExpired),
+		errors.Is(err, services.ErrPaymentIntentConflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrCaptureDeclined), errors.Is(err, services.ErrCaptureUnsupported):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create webhook endpoint",
+			"error":   message,
 			"details": err.Error(),
 		})
-		return
 	}
-
-	c.JSON(http.StatusCreated, endpoint)
 }
 
-// ListWebhookEndpoints lists webhook endpoints for a merchant
-func (h *Handlers) ListWebhookEndpoints(c *gin.Context) {
-	merchantIDStr := c.Query("merchant_id")
-	if merchantIDStr == "" {
+// GetPaymentCommand retrieves an async payment's command, with the payment
+// it made once processed
+func (h *Handlers) GetPaymentCommand(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "merchant_id query parameter is required",
+			"error": "Invalid payment command ID",
 		})
 		return
 	}
 
-	merchantID, err := uuid.Parse(merchantIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid merchant ID",
+	command, err := h.Services.Commands.GetPaymentCommand(c.Request.Context(), id)
+	if errors.Is(err, services.ErrPaymentCommandNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Payment command not found",
 		})
 		return
 	}
-
-	endpoints, err := h.Services.Webhook.GetWebhookEndpoints(c.Request.Context(), merchantID)
 	if err != nil {
-		h.Logger.WithError(err).Error("Failed to get webhook endpoints")
+		h.Logger.WithError(err).Error("Failed to get payment command")
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get webhook endpoints",
+			"error": "Failed to get payment command",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"endpoints": endpoints,
-	})
+	c.JSON(http.StatusOK, command)
 }
 
-// UpdateWebhookEndpoint updates a webhook endpoint
-func (h *Handlers) UpdateWebhookEndpoint(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
+// ThreeDSCallback is where the payer's authentication page sends them back
+// to after a 3-D Secure challenge, posting or linking its result. The
+// payment resumes with it, and the payer is redirected to the payment's
+// return URL if it has one.
+func (h *Handlers) ThreeDSCallback(c *gin.Context) {
+	paymentID, err := uuid.Parse(c.Param("payment_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid endpoint ID",
+			"error": "Invalid payment ID",
 		})
 		return
 	}
 
-	var updates map[string]interface{}
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
+	payload := make(map[string]string)
+	if c.ContentType() == "application/json" {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+		for key, value := range body {
+			payload[key] = fmt.Sprint(value)
+		}
+	} else {
+		if err := c.Request.ParseForm(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+		for key := range c.Request.Form {
+			payload[key] = c.Request.Form.Get(key)
+		}
+	}
+
+	challenge, payment, err := h.Services.Payment.CompleteChallenge(c.Request.Context(), paymentID, payload)
+	if errors.Is(err, services.ErrThreeDSChallengeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "3-D Secure challenge not found",
 		})
 		return
 	}
-
-	endpoint, err := h.Services.Webhook.UpdateWebhookEndpoint(c.Request.Context(), id, updates)
-	if err != nil {
-		h.Logger.WithError(err).Error("Failed to update webhook endpoint")
+	if challenge == nil {
+		h.Logger.WithError(err).Error("Failed to complete 3-D Secure challenge")
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to update webhook endpoint",
+			"error":   "Failed to complete 3-D Secure challenge",
 			"details": err.Error(),
 		})
 		return
 	}
+	// A rail that failed to verify the result failed the payment; the
+	// payer is sent back all the same
+	if err != nil {
+		h.Logger.WithError(err).WithField("payment_id", paymentID).Warn("3-D Secure verification failed")
+	}
 
-	c.JSON(http.StatusOK, endpoint)
+	if challenge.ReturnURL != "" {
+		if returnURL, err := url.Parse(challenge.ReturnURL); err == nil {
+			query := returnURL.Query()
+			query.Set("payment_intent_id", challenge.PaymentIntentID.String())
+			query.Set("payment_id", payment.ID.String())
+			query.Set("status", payment.Status)
+			returnURL.RawQuery = query.Encode()
+			c.Redirect(http.StatusSeeOther, returnURL.String())
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"challenge": challenge,
+		"payment":   payment,
+	})
 }
 
-// DeleteWebhookEndpoint deletes a webhook endpoint
-func (h *Handlers) DeleteWebhookEndpoint(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
+// ListPaymentAttempts returns the rail attempts of a payment, retries and
+// failovers included
+func (h *Handlers) ListPaymentAttempts(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid endpoint ID",
+			"error": "Invalid payment ID",
 		})
 		return
 	}
 
-	err = h.Services.Webhook.DeleteWebhookEndpoint(c.Request.Context(), id)
-	if err != nil {
-		h.Logger.WithError(err).Error("Failed to delete webhook endpoint")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to delete webhook endpoint",
-			"details": err.Error(),
-		})
+	if _, err := h.Services.Payment.GetPayment(c.Request.Context(), id); err != nil {
+		if err.Error() == "payment not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Payment not found",
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to get payment")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get payment",
+		})
+		return
+	}
+
+	attempts, err := h.Services.Payment.GetPaymentAttempts(c.Request.Context(), id)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to get payment attempts")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get payment attempts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attempts": attempts,
+	})
+}
+
+// ListPaymentEvents returns the events of a payment, the payment rebuilt
+// from them, and any fields where the stored payment disagrees
+func (h *Handlers) ListPaymentEvents(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid payment ID",
+		})
+		return
+	}
+
+	history, err := h.Services.Payment.GetPaymentHistory(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "payment not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Payment not found",
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to get payment events")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get payment events",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// CreateRefund creates and processes a refund
+func (h *Handlers) CreateRefund(c *gin.Context) {
+	var req services.CreateRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	refund, err := h.Services.Refund.CreateRefund(c.Request.Context(), req)
+	if err != nil {
+		h.refundError(c, err, "Failed to create refund")
+		return
+	}
+
+	// The refund worker sends it to the rail
+	c.JSON(http.StatusAccepted, refund)
+}
+
+// CancelRefund cancels a refund not yet sent to the rail
+func (h *Handlers) CancelRefund(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid refund ID",
+		})
+		return
+	}
+
+	refund, err := h.Services.Refund.CancelRefund(c.Request.Context(), id)
+	if err != nil {
+		h.refundError(c, err, "Failed to cancel refund")
+		return
+	}
+
+	c.JSON(http.StatusOK, refund)
+}
+
+// ListPaymentRefunds returns the refunds of a payment and how much of it
+// is refunded
+func (h *Handlers) ListPaymentRefunds(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid payment ID",
+		})
+		return
+	}
+
+	summary, err := h.Services.Refund.GetRefundSummary(c.Request.Context(), id)
+	if err != nil {
+		h.refundError(c, err, "Failed to get refunds")
+		return
+	}
+
+	refunds, err := h.Services.Refund.GetRefundsByPayment(c.Request.Context(), id)
+	if err != nil {
+		h.refundError(c, err, "Failed to get refunds")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"refunds": refunds,
+		"summary": summary,
+	})
+}
+
+// refundError responds with the status of a refund error: invalid amounts
+// are bad requests, refunds the payment does not allow are unprocessable,
+// and transitions the refund's status does not allow are conflicts
+func (h *Handlers) refundError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "payment not found", err.Error() == "refund not found":
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "refund amount"):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrPaymentNotRefundable), errors.Is(err, services.ErrRefundExceedsPayment):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidRefundTransition), errors.Is(err, services.ErrRefundConflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// GetRefund retrieves a refund by ID
+func (h *Handlers) GetRefund(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid refund ID",
+		})
+		return
+	}
+
+	refund, err := h.Services.Refund.GetRefund(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "refund not found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Refund not found",
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to get refund")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get refund",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, refund)
+}
+
+// AssessRisk performs risk assessment
+func (h *Handlers) AssessRisk(c *gin.Context) {
+	var req services.RiskAssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Set client context
+	req.IPAddress = c.ClientIP()
+	req.IPCountry = c.GetHeader(geoCountryHeader)
+	req.UserAgent = c.GetHeader("User-Agent")
+
+	result, err := h.Services.Risk.AssessRisk(c.Request.Context(), req)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to assess risk")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to assess risk",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"risk_score": result.RiskScore,
+		"risk_level": result.RiskLevel,
+		"decision":   result.Decision,
+		"factors":    result.Factors,
+		"rules":      result.Rules,
+	})
+}
+
+// ListRiskRules lists the risk engine's rules
+func (h *Handlers) ListRiskRules(c *gin.Context) {
+	rules, err := h.Services.Risk.ListRiskRules(c.Request.Context())
+	if err != nil {
+		h.riskRuleError(c, err, "Failed to list risk rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules":    rules,
+		"features": services.RiskFeatures,
+	})
+}
+
+// GetRiskRule retrieves a risk rule
+func (h *Handlers) GetRiskRule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid risk rule ID",
+		})
+		return
+	}
+
+	rule, err := h.Services.Risk.GetRiskRule(c.Request.Context(), id)
+	if err != nil {
+		h.riskRuleError(c, err, "Failed to get risk rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateRiskRule creates a risk rule
+func (h *Handlers) CreateRiskRule(c *gin.Context) {
+	var req services.RiskRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.Services.Risk.CreateRiskRule(c.Request.Context(), req)
+	if err != nil {
+		h.riskRuleError(c, err, "Failed to create risk rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRiskRule updates a risk rule
+func (h *Handlers) UpdateRiskRule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid risk rule ID",
+		})
+		return
+	}
+
+	var req services.RiskRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.Services.Risk.UpdateRiskRule(c.Request.Context(), id, req)
+	if err != nil {
+		h.riskRuleError(c, err, "Failed to update risk rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRiskRule deletes a risk rule
+func (h *Handlers) DeleteRiskRule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid risk rule ID",
+		})
+		return
+	}
+
+	if err := h.Services.Risk.DeleteRiskRule(c.Request.Context(), id); err != nil {
+		h.riskRuleError(c, err, "Failed to delete risk rule")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// TestRiskRule evaluates a rule against past payments without saving it
+func (h *Handlers) TestRiskRule(c *gin.Context) {
+	var req services.TestRiskRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.Services.Risk.TestRiskRule(c.Request.Context(), req)
+	if err != nil {
+		h.riskRuleError(c, err, "Failed to test risk rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// riskRuleError responds with the status of a risk rule error
+func (h *Handlers) riskRuleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrRiskRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidRiskRule):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrRiskRuleExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// CreateAPIKey creates a merchant API key. The key is only ever returned
+// here.
+func (h *Handlers) CreateAPIKey(c *gin.Context) {
+	var req services.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	key, err := h.Services.APIKeys.CreateAPIKey(c.Request.Context(), req)
+	if err != nil {
+		h.apiKeyError(c, err, "Failed to create API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// ListAPIKeys lists a merchant's API keys
+func (h *Handlers) ListAPIKeys(c *gin.Context) {
+	merchantID, err := uuid.Parse(c.Query("merchant_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "merchant_id query parameter is required",
+		})
+		return
+	}
+
+	keys, err := h.Services.APIKeys.ListAPIKeys(c.Request.Context(), merchantID)
+	if err != nil {
+		h.apiKeyError(c, err, "Failed to list API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": keys,
+		"scopes":   services.APIKeyScopes,
+	})
+}
+
+// RotateAPIKey replaces an API key with a new one, keeping the old one
+// working for a grace period
+func (h *Handlers) RotateAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid API key ID",
+		})
+		return
+	}
+
+	var req services.RotateAPIKeyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	key, err := h.Services.APIKeys.RotateAPIKey(c.Request.Context(), id, req)
+	if err != nil {
+		h.apiKeyError(c, err, "Failed to rotate API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// RevokeAPIKey revokes an API key
+func (h *Handlers) RevokeAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid API key ID",
+		})
+		return
+	}
+
+	key, err := h.Services.APIKeys.RevokeAPIKey(c.Request.Context(), id)
+	if err != nil {
+		h.apiKeyError(c, err, "Failed to revoke API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+// apiKeyError responds with the status of an API key error
+func (h *Handlers) apiKeyError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidAPIKeyRequest):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrAPIKeyRevoked):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// CreatePaymentMethod saves a customer's card or VPA in the vault,
+// returning the token payments can use in its place
+func (h *Handlers) CreatePaymentMethod(c *gin.Context) {
+	customerID, err := uuid.Parse(c.Param("customer_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid customer ID",
+		})
+		return
+	}
+
+	var req services.CreatePaymentMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	req.CustomerID = customerID
+	if !h.merchantAllowed(c, req.MerchantID) {
+		return
+	}
+
+	method, err := h.Services.Vault.CreatePaymentMethod(c.Request.Context(), req)
+	if err != nil {
+		h.vaultError(c, err, "Failed to save payment method")
+		return
+	}
+
+	c.JSON(http.StatusCreated, method)
+}
+
+// ListPaymentMethods lists a customer's saved payment methods
+func (h *Handlers) ListPaymentMethods(c *gin.Context) {
+	customerID, err := uuid.Parse(c.Param("customer_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid customer ID",
+		})
+		return
+	}
+	merchantID, ok := h.queryMerchantID(c)
+	if !ok {
+		return
+	}
+
+	methods, err := h.Services.Vault.ListPaymentMethods(c.Request.Context(), merchantID, customerID)
+	if err != nil {
+		h.vaultError(c, err, "Failed to list payment methods")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"payment_methods": methods,
+	})
+}
+
+// DeletePaymentMethod deletes a customer's saved payment method
+func (h *Handlers) DeletePaymentMethod(c *gin.Context) {
+	customerID, err := uuid.Parse(c.Param("customer_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid customer ID",
+		})
+		return
+	}
+	merchantID, ok := h.queryMerchantID(c)
+	if !ok {
+		return
+	}
+
+	err = h.Services.Vault.DeletePaymentMethod(c.Request.Context(), merchantID, customerID, c.Param("token"))
+	if err != nil {
+		h.vaultError(c, err, "Failed to delete payment method")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// vaultError responds with the status of a payment method error
+func (h *Handlers) vaultError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrPaymentMethodNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidPaymentMethod):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// CreateReport queues an export of a merchant's payments, refunds or fees,
+// answered with 202 while it is generated
+func (h *Handlers) CreateReport(c *gin.Context) {
+	var req services.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !h.merchantAllowed(c, req.MerchantID) {
+		return
+	}
+
+	report, err := h.Services.Reports.CreateReport(c.Request.Context(), req)
+	if err != nil {
+		h.reportError(c, err, "Failed to create report")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, report)
+}
+
+// ListReports lists a merchant's reports, newest first
+func (h *Handlers) ListReports(c *gin.Context) {
+	merchantID, ok := h.queryMerchantID(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.Services.Reports.ListReports(c.Request.Context(), merchantID)
+	if err != nil {
+		h.reportError(c, err, "Failed to list reports")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports": reports,
+	})
+}
+
+// GetReport retrieves a report and its status
+func (h *Handlers) GetReport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid report ID",
+		})
+		return
+	}
+
+	report, err := h.Services.Reports.GetReport(c.Request.Context(), id)
+	if err != nil {
+		h.reportError(c, err, "Failed to get report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// DownloadReport sends a ready report's file
+func (h *Handlers) DownloadReport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid report ID",
+		})
+		return
+	}
+
+	report, err := h.Services.Reports.DownloadReport(c.Request.Context(), id)
+	if err != nil {
+		h.reportError(c, err, "Failed to download report")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", services.ReportFilename(report)))
+	c.Data(http.StatusOK, services.ReportContentType(report.Format), report.Content)
+}
+
+// reportError responds with the status of a report error
+func (h *Handlers) reportError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrReportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Report not found",
+		})
+	case errors.Is(err, services.ErrInvalidReport):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrReportNotReady):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// ListReconciliationExceptions lists settlement records that did not match
+// a captured payment, filtered by status
+func (h *Handlers) ListReconciliationExceptions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	exceptions, err := h.Services.Recon.ListReconciliationExceptions(c.Request.Context(), c.Query("status"), limit)
+	if err != nil {
+		h.reconError(c, err, "Failed to list reconciliation exceptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exceptions": exceptions,
+	})
+}
+
+// ResolveReconciliationException closes a reconciliation exception,
+// settling a payment or writing the record off
+func (h *Handlers) ResolveReconciliationException(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid exception ID",
+		})
+		return
+	}
+
+	var req services.ResolveReconExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	req.ResolvedBy = c.GetString("user_id")
+
+	exception, err := h.Services.Recon.ResolveReconciliationException(c.Request.Context(), id, req)
+	if err != nil {
+		h.reconError(c, err, "Failed to resolve reconciliation exception")
+		return
+	}
+
+	c.JSON(http.StatusOK, exception)
+}
+
+// ListUPIDeadLetters lists payments' calls UPI Core could not be reached
+// for, filtered by status
+func (h *Handlers) ListUPIDeadLetters(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	letters, err := h.Services.Payment.ListUPIDeadLetters(c.Request.Context(), c.Query("status"), limit)
+	if err != nil {
+		h.upiDeadLetterError(c, err, "Failed to list UPI dead letters")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": letters,
+	})
+}
+
+// GetUPIDeadLetter retrieves a UPI dead letter, with the call it keeps
+func (h *Handlers) GetUPIDeadLetter(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid dead letter ID",
+		})
+		return
+	}
+
+	letter, err := h.Services.Payment.GetUPIDeadLetter(c.Request.Context(), id)
+	if err != nil {
+		h.upiDeadLetterError(c, err, "Failed to get UPI dead letter")
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+// ReplayUPIDeadLetter sends a dead letter's payment to UPI Core again
+func (h *Handlers) ReplayUPIDeadLetter(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid dead letter ID",
+		})
+		return
+	}
+
+	letter, err := h.Services.Payment.ReplayUPIDeadLetter(c.Request.Context(), id, c.GetString("user_id"))
+	if err != nil {
+		h.upiDeadLetterError(c, err, "Failed to replay UPI dead letter")
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+// ReplayUPIDeadLetters replays all pending UPI dead letters, stopping if
+// UPI Core is still unavailable
+func (h *Handlers) ReplayUPIDeadLetters(c *gin.Context) {
+	replayed, err := h.Services.Payment.ReplayUPIDeadLetters(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		h.upiDeadLetterError(c, err, "Failed to replay UPI dead letters")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"replayed": replayed,
+	})
+}
+
+// DiscardUPIDeadLetter gives up on a dead letter, failing its payment
+func (h *Handlers) DiscardUPIDeadLetter(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid dead letter ID",
+		})
+		return
+	}
+
+	letter, err := h.Services.Payment.DiscardUPIDeadLetter(c.Request.Context(), id, c.GetString("user_id"))
+	if err != nil {
+		h.upiDeadLetterError(c, err, "Failed to discard UPI dead letter")
+		return
+	}
+
+	c.JSON(http.StatusOK, letter)
+}
+
+// upiDeadLetterError responds with the status of a UPI dead letter error
+func (h *Handlers) upiDeadLetterError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrUPIDeadLetterNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrUPIDeadLetterNotPending):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrUPIUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// reconError responds with the status of a reconciliation error
+func (h *Handlers) reconError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrReconExceptionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidReconResolution):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrReconExceptionResolved):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// CreateWebhookEndpoint creates a new webhook endpoint
+func (h *Handlers) CreateWebhookEndpoint(c *gin.Context) {
+	var req services.CreateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !h.merchantAllowed(c, req.MerchantID) {
+		return
+	}
+
+	endpoint, err := h.Services.Webhook.CreateWebhookEndpoint(c.Request.Context(), req)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to create webhook endpoint")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create webhook endpoint",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// ListWebhookEndpoints lists webhook endpoints for a merchant
+func (h *Handlers) ListWebhookEndpoints(c *gin.Context) {
+	merchantID, ok := h.queryMerchantID(c)
+	if !ok {
+		return
+	}
+
+	endpoints, err := h.Services.Webhook.GetWebhookEndpoints(c.Request.Context(), merchantID)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to get webhook endpoints")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get webhook endpoints",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"endpoints": endpoints,
+	})
+}
+
+// ListMerchantRails lists which payment rails a merchant has enabled
+func (h *Handlers) ListMerchantRails(c *gin.Context) {
+	merchantID, ok := h.queryMerchantID(c)
+	if !ok {
+		return
+	}
+
+	rails, err := h.Services.Rails.MerchantRails(c.Request.Context(), merchantID)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to get merchant rails")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get merchant rails",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rails": rails,
+	})
+}
+
+// UpdateMerchantRailRequest is the body of UpdateMerchantRail
+type UpdateMerchantRailRequest struct {
+	MerchantID uuid.UUID `json:"merchant_id" binding:"required"`
+	Enabled    *bool     `json:"enabled" binding:"required"`
+}
+
+// UpdateMerchantRail enables or disables a payment rail for a merchant
+func (h *Handlers) UpdateMerchantRail(c *gin.Context) {
+	var req UpdateMerchantRailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !h.merchantAllowed(c, req.MerchantID) {
+		return
+	}
+
+	setting, err := h.Services.Rails.SetMerchantRail(c.Request.Context(), req.MerchantID, c.Param("rail"), *req.Enabled)
+	if err != nil {
+		if errors.Is(err, services.ErrRailUnavailable) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Payment rail not found",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		h.Logger.WithError(err).Error("Failed to update merchant rail")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update merchant rail",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// GetMerchantRateLimits returns a merchant's API budgets per minute
+func (h *Handlers) GetMerchantRateLimits(c *gin.Context) {
+	merchantID, err := uuid.Parse(c.Param("merchant_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid merchant ID",
+		})
+		return
+	}
+
+	limits, err := h.Services.RateLimits.GetMerchantRateLimits(c.Request.Context(), merchantID)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to get merchant rate limits")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get merchant rate limits",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
+
+// UpdateMerchantRateLimits sets a merchant's API budgets per minute
+func (h *Handlers) UpdateMerchantRateLimits(c *gin.Context) {
+	merchantID, err := uuid.Parse(c.Param("merchant_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid merchant ID",
+		})
+		return
+	}
+
+	var req services.UpdateMerchantRateLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	limits, err := h.Services.RateLimits.UpdateMerchantRateLimits(c.Request.Context(), merchantID, req)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to update merchant rate limits")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update merchant rate limits",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
+
+// ListLedgerAccounts lists a merchant's ledger accounts, or the platform's
+// without merchant_id. API keys list their merchant's.
+func (h *Handlers) ListLedgerAccounts(c *gin.Context) {
+	var merchantID *uuid.UUID
+	if merchantIDStr := c.Query("merchant_id"); merchantIDStr != "" {
+		id, err := uuid.Parse(merchantIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid merchant ID",
+			})
+			return
+		}
+		if !h.merchantAllowed(c, id) {
+			return
+		}
+		merchantID = &id
+	} else if tenant, ok := services.MerchantFromContext(c.Request.Context()); ok {
+		merchantID = &tenant
+	}
+
+	accounts, err := h.Services.Ledger.ListAccounts(c.Request.Context(), merchantID)
+	if err != nil {
+		h.Logger.WithError(err).Error("Failed to get ledger accounts")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get ledger accounts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"accounts": accounts,
+	})
+}
+
+// GetLedgerAccount returns a ledger account and its balance
+func (h *Handlers) GetLedgerAccount(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid account ID",
+		})
+		return
+	}
+
+	account, err := h.Services.Ledger.GetAccount(c.Request.Context(), id)
+	if err != nil {
+		h.ledgerError(c, err, "Failed to get ledger account")
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// GetLedgerStatement returns the postings to a ledger account with running
+// balances, optionally between the RFC 3339 times from and to
+func (h *Handlers) GetLedgerStatement(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid account ID",
+		})
+		return
+	}
+
+	var bounds [2]*time.Time
+	for i, param := range []string{"from", "to"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   fmt.Sprintf("Invalid %s time", param),
+				"details": err.Error(),
+			})
+			return
+		}
+		bounds[i] = &t
+	}
+
+	statement, err := h.Services.Ledger.GetStatement(c.Request.Context(), id, bounds[0], bounds[1])
+	if err != nil {
+		h.ledgerError(c, err, "Failed to get ledger statement")
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+// CreatePayout posts a payout of a merchant's balance
+func (h *Handlers) CreatePayout(c *gin.Context) {
+	var req services.PayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !h.merchantAllowed(c, req.MerchantID) {
+		return
+	}
+
+	if err := h.Services.Ledger.PostPayout(c.Request.Context(), req); err != nil {
+		h.ledgerError(c, err, "Failed to create payout")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"payout_id":   req.PayoutID,
+		"merchant_id": req.MerchantID,
+		"amount":      req.Amount,
+	})
+}
+
+// ledgerError responds with the status of a ledger error
+func (h *Handlers) ledgerError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrLedgerAccountNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case strings.HasPrefix(err.Error(), "payout amount"):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInsufficientBalance):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// UpdateWebhookEndpoint updates a webhook endpoint
+func (h *Handlers) UpdateWebhookEndpoint(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid endpoint ID",
+		})
+		return
+	}
+
+	var updates map[string]interface{}
+	if err := c.ShouldBindJSON(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	endpoint, err := h.Services.Webhook.UpdateWebhookEndpoint(c.Request.Context(), id, updates)
+	if err != nil {
+		h.webhookError(c, err, "Failed to update webhook endpoint")
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// DeleteWebhookEndpoint deletes a webhook endpoint
+func (h *Handlers) DeleteWebhookEndpoint(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid endpoint ID",
+		})
+		return
+	}
+
+	err = h.Services.Webhook.DeleteWebhookEndpoint(c.Request.Context(), id)
+	if err != nil {
+		h.webhookError(c, err, "Failed to delete webhook endpoint")
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
 
-// ReceiveWebhook handles webhook reception (for testing)
+// ListWebhookDeliveries lists a webhook endpoint's deliveries, optionally
+// those in ?status=, e.g. dead_lettered
+func (h *Handlers) ListWebhookDeliveries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid endpoint ID",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	deliveries, err := h.Services.Webhook.ListWebhookDeliveries(c.Request.Context(), id, c.Query("status"), limit)
+	if err != nil {
+		h.webhookError(c, err, "Failed to list webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+	})
+}
+
+// RedeliverWebhook sends a webhook delivery again
+func (h *Handlers) RedeliverWebhook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid delivery ID",
+		})
+		return
+	}
+
+	delivery, err := h.Services.Webhook.RedeliverWebhook(c.Request.Context(), id)
+	if err != nil {
+		h.webhookError(c, err, "Failed to redeliver webhook")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// RedeliverDeadLetters sends a webhook endpoint's dead-lettered deliveries
+// again
+func (h *Handlers) RedeliverDeadLetters(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid endpoint ID",
+		})
+		return
+	}
+
+	count, err := h.Services.Webhook.RedeliverDeadLetters(c.Request.Context(), id)
+	if err != nil {
+		h.webhookError(c, err, "Failed to redeliver webhooks")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"redelivered": count,
+	})
+}
+
+// RotateWebhookSecret replaces a webhook endpoint's secret, signing with
+// the old one too for an overlap
+func (h *Handlers) RotateWebhookSecret(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid endpoint ID",
+		})
+		return
+	}
+
+	var req services.RotateWebhookSecretRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	endpoint, err := h.Services.Webhook.RotateWebhookSecret(c.Request.Context(), id, req)
+	if err != nil {
+		h.webhookError(c, err, "Failed to rotate webhook secret")
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// webhookError responds with the status of a webhook error
+func (h *Handlers) webhookError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrWebhookEndpointNotFound), errors.Is(err, services.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidWebhookSignature):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidWebhookSecret):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrWebhookEndpointDisabled), errors.Is(err, services.ErrWebhookDeliveryInFlight):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	default:
+		h.Logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
+
+// ReceiveWebhook handles webhook reception (for testing). It verifies
+// deliveries as merchants should, rejecting forged and replayed ones.
 func (h *Handlers) ReceiveWebhook(c *gin.Context) {
 	endpointIDStr := c.Param("endpoint_id")
-	_, err := uuid.Parse(endpointIDStr)
+	endpointID, err := uuid.Parse(endpointIDStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid endpoint ID",
@@ -460,13 +2045,27 @@ func (h *Handlers) ReceiveWebhook(c *gin.Context) {
 		return
 	}
 
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	_, err = h.Services.Webhook.VerifyWebhook(c.Request.Context(), endpointID, payload, c.GetHeader(webhooksig.Header), webhooksig.DefaultTolerance)
+	if err != nil {
+		h.webhookError(c, err, "Failed to verify webhook")
+		return
+	}
+
 	// Log webhook reception for testing purposes
 	h.Logger.WithFields(logrus.Fields{
 		"endpoint_id": endpointIDStr,
 		"event_type":  c.GetHeader("X-Webhook-Event-Type"),
 		"event_id":    c.GetHeader("X-Webhook-Event-ID"),
 		"delivery_id": c.GetHeader("X-Webhook-Delivery-ID"),
-		"signature":   c.GetHeader("X-Webhook-Signature"),
+		"signature":   c.GetHeader(webhooksig.Header),
 	}).Info("Webhook received")
 
 	c.JSON(http.StatusOK, gin.H{
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/internal/services"
+)
+
+func setupAuthRouter(t *testing.T) (*gin.Engine, *services.APIKeyService, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.Exec(`CREATE TABLE merchant_api_keys (
+		id TEXT PRIMARY KEY,
+		merchant_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		prefix TEXT NOT NULL,
+		key_hash TEXT UNIQUE NOT NULL,
+		scopes TEXT NOT NULL,
+		rotated_from_id TEXT,
+		expires_at DATETIME,
+		revoked_at DATETIME,
+		last_used_at DATETIME,
+		last_used_ip TEXT,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`).Error)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	apiKeys := services.NewAPIKeyService(db, log, "test", 24)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Authentication("secret", apiKeys))
+	respond := func(c *gin.Context) {
+		tenant, _ := services.MerchantFromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"merchant_id": c.GetString("merchant_id"), "tenant": tenant})
+	}
+	router.GET("/payments/:id", RequireScope(services.ScopePaymentsRead), respond)
+	router.POST("/refunds", RequireScope(services.ScopeRefundsWrite), respond)
+	router.GET("/api-keys", RequireScope(services.ScopeAPIKeysWrite), respond)
+	return router, apiKeys, db
+}
+
+func requestWithKey(router *gin.Engine, method, path, header, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if header == "Authorization" {
+		key = "Bearer " + key
+	}
+	req.Header.Set(header, key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthentication_APIKeyScopes(t *testing.T) {
+	router, apiKeys, db := setupAuthRouter(t)
+	merchantID := uuid.New()
+	key, err := apiKeys.CreateAPIKey(context.Background(), services.CreateAPIKeyRequest{
+		MerchantID: merchantID,
+		Name:       "backend",
+		Scopes:     []string{services.ScopePaymentsRead},
+	})
+	require.NoError(t, err)
+	assert.Regexp(t, `^sk_test_[0-9A-Za-z]{32}$`, key.Key)
+
+	for _, header := range []string{"Authorization", APIKeyHeader} {
+		w := requestWithKey(router, http.MethodGet, "/payments/pay_1", header, key.Key)
+		assert.Equal(t, http.StatusOK, w.Code, header)
+		assert.Contains(t, w.Body.String(), `"tenant":"`+merchantID.String()+`"`, header)
+	}
+
+	// Unscoped routes, and key management, are refused
+	w := requestWithKey(router, http.MethodPost, "/refunds", APIKeyHeader, key.Key)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "INSUFFICIENT_SCOPE")
+	w = requestWithKey(router, http.MethodGet, "/api-keys", APIKeyHeader, key.Key)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Use is tracked
+	var stored models.MerchantAPIKey
+	require.NoError(t, db.First(&stored, "id = ?", key.ID).Error)
+	assert.NotNil(t, stored.LastUsedAt)
+	assert.NotEqual(t, key.Key, stored.KeyHash)
+
+	w = requestWithKey(router, http.MethodGet, "/payments/pay_1", APIKeyHeader, "sk_test_unknown")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthentication_APIKeyRotationAndRevocation(t *testing.T) {
+	router, apiKeys, _ := setupAuthRouter(t)
+	ctx := context.Background()
+	old, err := apiKeys.CreateAPIKey(ctx, services.CreateAPIKeyRequest{
+		MerchantID: uuid.New(),
+		Name:       "backend",
+		Scopes:     []string{services.ScopePaymentsRead, services.ScopePaymentsRead},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, services.ScopePaymentsRead, old.Scopes)
+
+	_, err = apiKeys.CreateAPIKey(ctx, services.CreateAPIKeyRequest{
+		MerchantID: uuid.New(),
+		Name:       "admin",
+		Scopes:     []string{services.ScopeAPIKeysWrite},
+	})
+	assert.ErrorIs(t, err, services.ErrInvalidAPIKeyRequest)
+
+	// Both keys work during the grace period
+	grace := 3600
+	rotated, err := apiKeys.RotateAPIKey(ctx, old.ID, services.RotateAPIKeyRequest{GraceSeconds: &grace})
+	require.NoError(t, err)
+	assert.Equal(t, old.Scopes, rotated.Scopes)
+	assert.Equal(t, old.ID, *rotated.RotatedFromID)
+	for _, key := range []string{old.Key, rotated.Key} {
+		w := requestWithKey(router, http.MethodGet, "/payments/pay_1", APIKeyHeader, key)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// Rotating without grace revokes the old key straight away
+	noGrace := 0
+	latest, err := apiKeys.RotateAPIKey(ctx, rotated.ID, services.RotateAPIKeyRequest{GraceSeconds: &noGrace})
+	require.NoError(t, err)
+	w := requestWithKey(router, http.MethodGet, "/payments/pay_1", APIKeyHeader, rotated.Key)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	_, err = apiKeys.RotateAPIKey(ctx, rotated.ID, services.RotateAPIKeyRequest{})
+	assert.ErrorIs(t, err, services.ErrAPIKeyRevoked)
+
+	revoked, err := apiKeys.RevokeAPIKey(ctx, latest.ID)
+	require.NoError(t, err)
+	require.NotNil(t, revoked.RevokedAt)
+	assert.WithinDuration(t, time.Now(), *revoked.RevokedAt, time.Minute)
+	w = requestWithKey(router, http.MethodGet, "/payments/pay_1", APIKeyHeader, latest.Key)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
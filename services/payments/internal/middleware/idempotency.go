@@ -2,6 +2,9 @@ package middleware
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 
@@ -11,7 +14,25 @@ import (
 
 const IdempotencyKeyHeader = "Idempotency-Key"
 
-// Idempotency middleware handles idempotency for unsafe HTTP methods
+// maxIdempotencyKeyLength leaves room in idempotency_keys.key for the
+// merchant, method and path the key is scoped to
+const maxIdempotencyKeyLength = 128
+
+// unreplayedHeaders are set per request, not by the handler whose response
+// is replayed
+var unreplayedHeaders = map[string]bool{
+	"Content-Length":                         true,
+	"Date":                                   true,
+	http.CanonicalHeaderKey(RequestIDHeader): true,
+	"X-Idempotency-Store-Error":              true,
+}
+
+// Idempotency middleware handles idempotency for unsafe HTTP methods. The
+// first request with a key is processed; repeats wait for it to finish and
+// get its status code, headers and body replayed. Keys are scoped to the
+// authenticated merchant, the method and the path, so a key reused on
+// another route is another request rather than a replay of the first.
+// Refusals by authentication and scope checks are not stored.
 func Idempotency(idempotencyService *services.IdempotencyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Only apply idempotency to unsafe methods
@@ -30,6 +51,18 @@ func Idempotency(idempotencyService *services.IdempotencyService) gin.HandlerFun
 			c.Abort()
 			return
 		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength),
+				"code":  "INVALID_IDEMPOTENCY_KEY",
+			})
+			c.Abort()
+			return
+		}
+		idempotencyKey = c.Request.Method + " " + c.Request.URL.Path + ":" + idempotencyKey
+		if merchantID := c.GetString("merchant_id"); merchantID != "" {
+			idempotencyKey = merchantID + ":" + idempotencyKey
+		}
 
 		// Read request body
 		requestBody, err := io.ReadAll(c.Request.Body)
@@ -45,77 +78,92 @@ func Idempotency(idempotencyService *services.IdempotencyService) gin.HandlerFun
 		// Restore request body for downstream handlers
 		c.Request.Body = io.NopCloser(bytes.NewReader(requestBody))
 
-		// Check idempotency
-		result, err := idempotencyService.CheckIdempotency(c.Request.Context(), idempotencyKey, requestBody)
-		if err != nil {
+		// Claim the key, or wait for the request holding it
+		stored, err := idempotencyService.Begin(c.Request.Context(), idempotencyKey, requestBody)
+		switch {
+		case errors.Is(err, services.ErrIdempotencyKeyReused):
 			c.JSON(http.StatusConflict, gin.H{
 				"error": "Idempotency key conflict: " + err.Error(),
 				"code":  "IDEMPOTENCY_CONFLICT",
 			})
 			c.Abort()
 			return
+		case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+			c.Header("Retry-After", "1")
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+				"code":  "IDEMPOTENCY_IN_PROGRESS",
+			})
+			c.Abort()
+			return
+		case err != nil:
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Failed to check idempotency key",
+				"code":  "IDEMPOTENCY_UNAVAILABLE",
+			})
+			c.Abort()
+			return
 		}
 
-		// If cached response exists, return it
-		if result.Found {
-			cachedResponse, err := idempotencyService.UnmarshalResponse(result.ResponseData)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": "Failed to parse cached response",
-					"code":  "CACHE_ERROR",
-				})
-				c.Abort()
-				return
+		// If a stored response exists, replay it
+		if stored != nil {
+			for name, values := range stored.Header {
+				if !unreplayedHeaders[name] {
+					c.Writer.Header()[name] = values
+				}
 			}
-
 			c.Header("X-Idempotent-Replay", "true")
-			
-			if cachedResponse.Error != nil {
-				c.JSON(cachedResponse.StatusCode, gin.H{
-					"error": *cachedResponse.Error,
-					"code":  "CACHED_ERROR",
-				})
-			} else {
-				c.JSON(cachedResponse.StatusCode, cachedResponse.Data)
-			}
+			c.Data(stored.StatusCode, stored.Header.Get("Content-Type"), stored.Body)
 			c.Abort()
 			return
 		}
 
-		// Store idempotency key and request body for later use
-		c.Set("idempotency_key", idempotencyKey)
-		c.Set("request_body", requestBody)
-		c.Set("idempotency_service", idempotencyService)
+		// The key is ours until the response is stored; server errors and
+		// panics give it up so the request can be retried. The claim is
+		// settled even if the client has gone away.
+		storeCtx := context.WithoutCancel(c.Request.Context())
+		completed := false
+		defer func() {
+			if !completed {
+				idempotencyService.Release(storeCtx, idempotencyKey)
+			}
+		}()
 
 		// Create a custom response writer to capture the response
 		customWriter := &responseWriter{
 			ResponseWriter: c.Writer,
-			body:          &bytes.Buffer{},
+			body:           &bytes.Buffer{},
 		}
 		c.Writer = customWriter
 
 		// Continue to next handler
 		c.Next()
 
-		// After the handler completes, store the response for idempotency
+		// A request refused by authentication or for its API key's scopes
+		// was not processed, so it can be retried once the credentials
+		// allow it
 		statusCode := customWriter.Status()
-		responseBody := customWriter.body.Bytes()
-
-		// Only cache successful responses or known error responses
-		if statusCode < 500 {
-			err := idempotencyService.StoreIdempotencyResult(
-				c.Request.Context(),
-				idempotencyKey,
-				requestBody,
-				responseBody,
-				statusCode,
-			)
-			if err != nil {
-				// Log error but don't fail the request
-				// The response has already been sent to the client
-				c.Header("X-Idempotency-Store-Error", "true")
+		if statusCode >= 500 || statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+			return
+		}
+
+		header := http.Header{}
+		for name, values := range customWriter.Header() {
+			if !unreplayedHeaders[name] {
+				header[name] = values
 			}
 		}
+		err = idempotencyService.Complete(storeCtx, idempotencyKey, &services.StoredResponse{
+			StatusCode: statusCode,
+			Header:     header,
+			Body:       customWriter.body.Bytes(),
+		})
+		if err != nil {
+			// The response has already been sent to the client; the key
+			// stays claimed until its lock times out
+			c.Header("X-Idempotency-Store-Error", "true")
+		}
+		completed = true
 	}
 }
 
@@ -135,4 +183,4 @@ func (w *responseWriter) WriteString(s string) (int, error) {
 	// Write to both the actual response and our buffer
 	w.body.WriteString(s)
 	return w.ResponseWriter.WriteString(s)
-}
\ No newline at end of file
+}
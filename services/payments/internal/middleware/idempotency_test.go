@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/internal/services"
+)
+
+func setupIdempotencyRouter(t *testing.T, handler gin.HandlerFunc) (*gin.Engine, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.Exec(`CREATE TABLE idempotency_keys (
+		id TEXT PRIMARY KEY,
+		key TEXT UNIQUE NOT NULL,
+		request_hash TEXT NOT NULL,
+		state TEXT NOT NULL DEFAULT 'COMPLETED',
+		response_data BLOB,
+		response_headers BLOB,
+		status_code INTEGER,
+		locked_until DATETIME,
+		expires_at DATETIME NOT NULL,
+		created_at DATETIME
+	)`).Error)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	idempotency := services.NewIdempotencyService(db, log, 24, 60, 5)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("merchant_id", c.GetHeader("X-Merchant"))
+		c.Next()
+	})
+	router.Use(Idempotency(idempotency))
+	router.POST("/payments", handler)
+	return router, db
+}
+
+func postPayment(router *gin.Engine, merchant, key, body string) *httptest.ResponseRecorder {
+	return request(router, http.MethodPost, "/payments", merchant, key, body)
+}
+
+func request(router *gin.Engine, method, path, merchant, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-Merchant", merchant)
+	req.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ConcurrentDuplicatesRunOnce(t *testing.T) {
+	var calls int32
+	router, _ := setupIdempotencyRouter(t, func(c *gin.Context) {
+		n := atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		c.Header("Location", "/api/v1/payments/pay_1")
+		c.JSON(http.StatusCreated, gin.H{"id": "pay_1", "attempt": n})
+	})
+
+	const requests = 25
+	responses := make([]*httptest.ResponseRecorder, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = postPayment(router, "merchant_1", "key-1", `{"amount":100}`)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	replays := 0
+	for _, w := range responses {
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"id":"pay_1","attempt":1}`, w.Body.String())
+		assert.Equal(t, "/api/v1/payments/pay_1", w.Header().Get("Location"))
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		if w.Header().Get("X-Idempotent-Replay") == "true" {
+			replays++
+		}
+	}
+	assert.Equal(t, requests-1, replays)
+}
+
+func TestIdempotency_ConflictsAndRetries(t *testing.T) {
+	var calls int32
+	router, db := setupIdempotencyRouter(t, func(c *gin.Context) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "upstream unavailable"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": "pay_2"})
+	})
+
+	// Server errors release the key so the request can be retried
+	assert.Equal(t, http.StatusBadGateway, postPayment(router, "merchant_1", "key-2", `{"amount":100}`).Code)
+	assert.Equal(t, http.StatusOK, postPayment(router, "merchant_1", "key-2", `{"amount":100}`).Code)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// A different body with the same key is rejected
+	w := postPayment(router, "merchant_1", "key-2", `{"amount":200}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "IDEMPOTENCY_CONFLICT")
+
+	// Keys are scoped to the merchant
+	assert.Equal(t, http.StatusOK, postPayment(router, "merchant_2", "key-2", `{"amount":200}`).Code)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// A claim whose request died is taken over once its lock times out
+	lockedUntil := time.Now().Add(-time.Second)
+	require.NoError(t, db.Create(&models.IdempotencyKey{
+		Key:         "merchant_1:POST /payments:key-3",
+		RequestHash: "stale",
+		State:       services.IdempotencyInProgress,
+		LockedUntil: &lockedUntil,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}).Error)
+	assert.Equal(t, http.StatusOK, postPayment(router, "merchant_1", "key-3", `{"amount":100}`).Code)
+}
+
+func TestIdempotency_KeysAreScopedToTheRoute(t *testing.T) {
+	var calls int32
+	router, _ := setupIdempotencyRouter(t, func(c *gin.Context) {
+		atomic.AddInt32(&calls, 1)
+		c.JSON(http.StatusOK, gin.H{"path": c.Request.URL.Path})
+	})
+	router.POST("/refunds", func(c *gin.Context) {
+		atomic.AddInt32(&calls, 1)
+		c.JSON(http.StatusOK, gin.H{"path": c.Request.URL.Path})
+	})
+	router.DELETE("/payments", func(c *gin.Context) {
+		atomic.AddInt32(&calls, 1)
+		c.JSON(http.StatusOK, gin.H{"path": "deleted"})
+	})
+
+	assert.JSONEq(t, `{"path":"/payments"}`, postPayment(router, "merchant_1", "key-4", `{}`).Body.String())
+
+	// The same key and body on another route or method is not a replay
+	w := request(router, http.MethodPost, "/refunds", "merchant_1", "key-4", `{}`)
+	assert.JSONEq(t, `{"path":"/refunds"}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("X-Idempotent-Replay"))
+	w = request(router, http.MethodDelete, "/payments", "merchant_1", "key-4", `{}`)
+	assert.JSONEq(t, `{"path":"deleted"}`, w.Body.String())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	w = postPayment(router, "merchant_1", "key-4", `{}`)
+	assert.Equal(t, "true", w.Header().Get("X-Idempotent-Replay"))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	w = postPayment(router, "merchant_1", strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_IDEMPOTENCY_KEY")
+}
+
+func TestIdempotency_AuthRefusalsAreNotStored(t *testing.T) {
+	var allowed atomic.Bool
+	var calls int32
+	router, _ := setupIdempotencyRouter(t, func(c *gin.Context) {
+		atomic.AddInt32(&calls, 1)
+		c.JSON(http.StatusCreated, gin.H{"id": "pay_5"})
+	})
+	// As RequireScope and Authentication run: after Idempotency, on the route
+	refuse := func(status int) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if !allowed.Load() {
+				c.AbortWithStatusJSON(status, gin.H{"error": "refused"})
+			}
+		}
+	}
+	router.POST("/scoped", refuse(http.StatusForbidden), func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{"id": "pay_6"}) })
+	router.POST("/authenticated", refuse(http.StatusUnauthorized), func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{"id": "pay_7"}) })
+
+	assert.Equal(t, http.StatusForbidden, request(router, http.MethodPost, "/scoped", "merchant_1", "key-5", `{}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, request(router, http.MethodPost, "/authenticated", "merchant_1", "key-5", `{}`).Code)
+
+	// Once the credentials allow it, the request is processed, not the
+	// refusal replayed
+	allowed.Store(true)
+	w := request(router, http.MethodPost, "/scoped", "merchant_1", "key-5", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("X-Idempotent-Replay"))
+	w = request(router, http.MethodPost, "/authenticated", "merchant_1", "key-5", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("X-Idempotent-Replay"))
+
+	// Other client errors are the request's answer and are replayed
+	router.POST("/invalid", func(c *gin.Context) {
+		atomic.AddInt32(&calls, 1)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid"})
+	})
+	request(router, http.MethodPost, "/invalid", "merchant_1", "key-6", `{}`)
+	w = request(router, http.MethodPost, "/invalid", "merchant_1", "key-6", `{}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "true", w.Header().Get("X-Idempotent-Replay"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
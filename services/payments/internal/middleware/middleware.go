@@ -1,10 +1,10 @@
 package middleware
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
-	"os"
 	"strconv"
 	"time"
 
@@ -12,18 +12,31 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/internal/services"
 	"github.com/suuupra/payments/pkg/metrics"
 )
 
 const (
 	RequestIDHeader = "X-Request-ID"
 	UserIDHeader    = "X-User-ID"
+	APIKeyHeader    = "X-API-Key"
+
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+// Authentication methods, set on the context as auth_method
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
 )
 
 // Logger middleware for structured logging
@@ -53,6 +66,7 @@ func CORS() gin.HandlerFunc {
 		"Content-Length",
 		"Content-Type",
 		"Authorization",
+		APIKeyHeader,
 		"X-Requested-With",
 		"Accept",
 		"Accept-Encoding",
@@ -63,6 +77,11 @@ func CORS() gin.HandlerFunc {
 	config.ExposeHeaders = []string{
 		RequestIDHeader,
 		"X-Idempotent-Replay",
+		RateLimitLimitHeader,
+		RateLimitRemainingHeader,
+		RateLimitResetHeader,
+		RateLimitPolicyHeader,
+		"Retry-After",
 	}
 	return cors.New(config)
 }
@@ -177,8 +196,9 @@ func validateJWTWithIdentityService(tokenString, jwtSecret string) (*JWTClaims,
 	return claims, nil
 }
 
-// Authentication middleware (JWT validation)
-func Authentication(jwtSecret string) gin.HandlerFunc {
+// Authentication middleware. Requests authenticate with a JWT, or a
+// merchant API key as a bearer token or in X-API-Key.
+func Authentication(jwtSecret string, apiKeys *services.APIKeyService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Skip authentication for health check endpoints
 		if c.FullPath() == "/health" || c.FullPath() == "/ready" || c.FullPath() == "/metrics" {
@@ -186,6 +206,11 @@ func Authentication(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+			authenticateAPIKey(c, apiKeys, apiKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(401, gin.H{
@@ -215,6 +240,11 @@ func Authentication(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		if services.IsAPIKey(token) {
+			authenticateAPIKey(c, apiKeys, token)
+			return
+		}
+
 		// Validate JWT token with Identity Service
 		claims, err := validateJWTWithIdentityService(token, jwtSecret)
 		if err != nil {
@@ -237,95 +267,107 @@ func Authentication(jwtSecret string) gin.HandlerFunc {
 		// For backward compatibility, set merchant_id to user_id for now
 		// This can be refined based on actual business logic
 		c.Set("merchant_id", claims.Sub)
+		c.Set("auth_method", AuthMethodJWT)
 
 		c.Next()
 	}
 }
 
-// RateLimit middleware with Redis-based sliding window rate limiting
-func RateLimit() gin.HandlerFunc {
-	return gin.HandlerFunc(func(c *gin.Context) {
-		// Get client identifier (IP address or merchant ID)
-		clientID := c.ClientIP()
-		if merchantID := c.GetString("merchant_id"); merchantID != "" {
-			clientID = merchantID
+// authenticateAPIKey authenticates the request as the merchant that owns
+// key, or rejects it
+func authenticateAPIKey(c *gin.Context, apiKeys *services.APIKeyService, key string) {
+	apiKey, err := apiKeys.Authenticate(c.Request.Context(), key, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAPIKey) {
+			c.JSON(401, gin.H{
+				"error":   "Invalid API key",
+				"code":    "INVALID_API_KEY",
+				"details": err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Failed to check API key",
+				"code":  "AUTHENTICATION_UNAVAILABLE",
+			})
 		}
+		c.Abort()
+		return
+	}
 
-		// Rate limiting configuration
-		rateLimit := 1000 // requests per hour
-		windowSize := time.Hour
-
-		// Redis key for rate limiting
-		redisKey := fmt.Sprintf("rate_limit:payments:%s", clientID)
-
-		// Initialize Redis client
-		rdb := redis.NewClient(&redis.Options{
-			Addr:     os.Getenv("REDIS_HOST") + ":" + os.Getenv("REDIS_PORT"),
-			Password: os.Getenv("REDIS_PASSWORD"),
-			DB:       1, // Use database 1 for rate limiting
-		})
-		defer rdb.Close()
-
-		ctx := context.Background()
-		now := time.Now().Unix()
-
-		// Sliding window rate limiting using Redis sorted sets
-		pipe := rdb.Pipeline()
-
-		// Remove expired entries
-		pipe.ZRemRangeByScore(ctx, redisKey, "0", fmt.Sprintf("%d", now-int64(windowSize.Seconds())))
+	c.Set("merchant_id", apiKey.MerchantID.String())
+	c.Set("auth_method", AuthMethodAPIKey)
+	c.Set("api_key", apiKey)
+	// API keys only reach their merchant's objects
+	c.Request = c.Request.WithContext(services.WithMerchant(c.Request.Context(), apiKey.MerchantID))
+	c.Next()
+}
 
-		// Count current requests in window
-		pipe.ZCard(ctx, redisKey)
+// RequireScope rejects requests made with an API key that was not granted
+// scope. JWTs are not scoped, so they are let through.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_method") != AuthMethodAPIKey {
+			c.Next()
+			return
+		}
 
-		// Add current request
-		pipe.ZAdd(ctx, redisKey, redis.Z{Score: float64(now), Member: uuid.New().String()})
+		value, _ := c.Get("api_key")
+		apiKey, ok := value.(*models.MerchantAPIKey)
+		if !ok || !apiKey.HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "API key lacks the " + scope + " scope",
+				"code":  "INSUFFICIENT_SCOPE",
+				"scope": scope,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
 
-		// Set expiry on the key
-		pipe.Expire(ctx, redisKey, windowSize+time.Minute)
+// RateLimit middleware throttles each merchant, or client IP without one,
+// to its read or write budget over a sliding minute, and all clients to
+// the global budget. Responses carry the RateLimit-* headers of the
+// client's budget. Requests are let through when Redis is unavailable.
+func RateLimit(limiter *services.RateLimitService, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := c.GetString("merchant_id")
+		if client == "" {
+			client = "ip:" + c.ClientIP()
+		}
+		budget := services.RateLimitBudgetWrite
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions {
+			budget = services.RateLimitBudgetRead
+		}
 
-		results, err := pipe.Exec(ctx)
+		decision, err := limiter.Allow(c.Request.Context(), client, budget)
 		if err != nil {
-			// If Redis is unavailable, log and continue with basic headers
-			logrus.WithError(err).Warn("Redis unavailable for rate limiting, allowing request")
-			c.Header("X-RateLimit-Limit", strconv.Itoa(rateLimit))
-			c.Header("X-RateLimit-Remaining", strconv.Itoa(rateLimit-1))
-			c.Header("X-RateLimit-Reset", strconv.FormatInt(now+int64(windowSize.Seconds()), 10))
+			logger.WithError(err).Warn("Rate limiter unavailable, allowing request")
 			c.Next()
 			return
 		}
 
-		// Get current count from pipeline results
-		currentCount := results[1].(*redis.IntCmd).Val()
-
-		// Check if rate limit exceeded
-		if currentCount > int64(rateLimit) {
-			retryAfter := int64(windowSize.Seconds()) - (now % int64(windowSize.Seconds()))
-			c.Header("X-RateLimit-Limit", strconv.Itoa(rateLimit))
-			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("X-RateLimit-Reset", strconv.FormatInt(now+retryAfter, 10))
-			c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+		reset := int64(math.Ceil(decision.Reset.Seconds()))
+		c.Header(RateLimitLimitHeader, strconv.Itoa(decision.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+		c.Header(RateLimitResetHeader, strconv.FormatInt(reset, 10))
+		c.Header(RateLimitPolicyHeader, fmt.Sprintf("%d;w=60", decision.Limit))
 
+		if !decision.Allowed {
+			metrics.RateLimitedRequestsTotal.WithLabelValues(decision.Scope, budget).Inc()
+			c.Header("Retry-After", strconv.FormatInt(reset, 10))
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "Rate limit exceeded",
-				"retry_after": retryAfter,
+				"code":        "RATE_LIMITED",
+				"scope":       decision.Scope,
+				"retry_after": reset,
 			})
 			c.Abort()
 			return
 		}
-
-		// Set rate limit headers
-		remaining := rateLimit - int(currentCount)
-		if remaining < 0 {
-			remaining = 0
-		}
-
-		c.Header("X-RateLimit-Limit", strconv.Itoa(rateLimit))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		c.Header("X-RateLimit-Reset", strconv.FormatInt(now+int64(windowSize.Seconds()), 10))
-
 		c.Next()
-	})
+	}
 }
 
 // SecurityHeaders middleware adds security headers
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/suuupra/payments/internal/services"
+)
+
+func setupRateLimitRouter(t *testing.T, policy services.RateLimitPolicy) (*gin.Engine, *services.RateLimitService, *miniredis.Miniredis) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.Exec(`CREATE TABLE merchant_rate_limits (
+		merchant_id TEXT PRIMARY KEY,
+		reads_per_minute INTEGER,
+		writes_per_minute INTEGER,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`).Error)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	limiter := services.NewRateLimitService(db, rdb, log, policy)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if merchant := c.GetHeader("X-Merchant"); merchant != "" {
+			c.Set("merchant_id", merchant)
+		}
+		c.Next()
+	})
+	router.Use(RateLimit(limiter, log))
+	respond := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	}
+	router.GET("/payments", respond)
+	router.POST("/payments", respond)
+	return router, limiter, mr
+}
+
+func rateLimitedRequest(router *gin.Engine, method, merchant string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/payments", nil)
+	if merchant != "" {
+		req.Header.Set("X-Merchant", merchant)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_ThrottlesWritesSeparatelyFromReads(t *testing.T) {
+	router, _, _ := setupRateLimitRouter(t, services.RateLimitPolicy{ReadsPerMinute: 5, WritesPerMinute: 2})
+	merchant := uuid.NewString()
+
+	w := rateLimitedRequest(router, http.MethodPost, merchant)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+	assert.Equal(t, "1", w.Header().Get(RateLimitRemainingHeader))
+	assert.Equal(t, "60", w.Header().Get(RateLimitResetHeader))
+	assert.Equal(t, "2;w=60", w.Header().Get(RateLimitPolicyHeader))
+
+	require.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodPost, merchant).Code)
+	w = rateLimitedRequest(router, http.MethodPost, merchant)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"scope":"client"`)
+
+	// Reads have a budget of their own, and other merchants theirs
+	w = rateLimitedRequest(router, http.MethodGet, merchant)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "5", w.Header().Get(RateLimitLimitHeader))
+	assert.Equal(t, "4", w.Header().Get(RateLimitRemainingHeader))
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodPost, uuid.NewString()).Code)
+}
+
+func TestRateLimit_MerchantLimitsFromDatabase(t *testing.T) {
+	router, limiter, _ := setupRateLimitRouter(t, services.RateLimitPolicy{ReadsPerMinute: 5, WritesPerMinute: 1})
+	merchantID := uuid.New()
+
+	writes := 3
+	limits, err := limiter.UpdateMerchantRateLimits(context.Background(), merchantID, services.UpdateMerchantRateLimitsRequest{
+		WritesPerMinute: &writes,
+	})
+	require.NoError(t, err)
+	assert.True(t, limits.Custom)
+	assert.Equal(t, 5, limits.ReadsPerMinute)
+	assert.Equal(t, 3, limits.WritesPerMinute)
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodPost, merchantID.String()).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(router, http.MethodPost, merchantID.String()).Code)
+
+	// Back to the default
+	limits, err = limiter.UpdateMerchantRateLimits(context.Background(), merchantID, services.UpdateMerchantRateLimitsRequest{})
+	require.NoError(t, err)
+	assert.False(t, limits.Custom)
+	assert.Equal(t, 1, limits.WritesPerMinute)
+}
+
+func TestRateLimit_GlobalBudget(t *testing.T) {
+	router, _, _ := setupRateLimitRouter(t, services.RateLimitPolicy{ReadsPerMinute: 10, WritesPerMinute: 10, GlobalPerMinute: 3})
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodGet, uuid.NewString()).Code)
+	}
+	w := rateLimitedRequest(router, http.MethodGet, uuid.NewString())
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "3", w.Header().Get(RateLimitLimitHeader))
+	assert.Contains(t, w.Body.String(), `"scope":"global"`)
+}
+
+func TestRateLimit_ClientsWithoutMerchantByIP(t *testing.T) {
+	router, _, _ := setupRateLimitRouter(t, services.RateLimitPolicy{ReadsPerMinute: 1, WritesPerMinute: 1})
+
+	require.Equal(t, http.StatusOK, rateLimitedRequest(router, http.MethodGet, "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(router, http.MethodGet, "").Code)
+}
+
+func TestRateLimit_AllowsWhenRedisUnavailable(t *testing.T) {
+	router, _, mr := setupRateLimitRouter(t, services.RateLimitPolicy{ReadsPerMinute: 1, WritesPerMinute: 1})
+	mr.Close()
+
+	for i := 0; i < 3; i++ {
+		w := rateLimitedRequest(router, http.MethodPost, uuid.NewString())
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+	}
+}
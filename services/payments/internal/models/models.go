@@ -1,34 +1,71 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+
+	"github.com/suuupra/payments/pkg/money"
 )
 
 // PaymentIntent represents a payment intention before processing
 type PaymentIntent struct {
 	ID                uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	MerchantID        uuid.UUID       `json:"merchant_id" gorm:"type:uuid;not null;index"`
-	Amount            decimal.Decimal `json:"amount" gorm:"type:decimal(20,2);not null"`
+	Amount            decimal.Decimal `json:"amount" gorm:"type:decimal(20,3);not null"`
 	Currency          string          `json:"currency" gorm:"type:varchar(3);not null;default:'INR'"`
 	Description       string          `json:"description" gorm:"type:text"`
-	Status            string          `json:"status" gorm:"type:varchar(50);not null;default:'created';index"`
+	Status            string          `json:"status" gorm:"type:varchar(50);not null;default:'requires_payment_method';index"`
 	PaymentMethod     string          `json:"payment_method" gorm:"type:varchar(50);not null"`
+	CaptureMethod     string          `json:"capture_method" gorm:"type:varchar(20);not null;default:'automatic'"` // automatic or manual
 	CustomerID        *uuid.UUID      `json:"customer_id" gorm:"type:uuid;index"`
 	Metadata          map[string]interface{} `json:"metadata" gorm:"type:jsonb"`
 	ExpiresAt         *time.Time      `json:"expires_at"`
+	Version           int64           `json:"version" gorm:"not null;default:1"` // Bumped on every transition
 	CreatedAt         time.Time       `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt         time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
+// Money returns the intent's amount in minor units of its currency
+func (i PaymentIntent) Money() (money.Money, error) {
+	return money.New(i.Amount, i.Currency)
+}
+
+// MarshalJSON writes the intent with its amount as a money.Money: to its
+// currency's exponent and in minor units
+func (i PaymentIntent) MarshalJSON() ([]byte, error) {
+	type paymentIntent PaymentIntent
+	amount, err := i.Money()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		paymentIntent
+		Amount     string `json:"amount"`
+		MinorUnits int64  `json:"minor_units"`
+	}{paymentIntent(i), amount.String(), amount.Minor})
+}
+
+// PaymentIntentTransition records a change of a payment intent's status
+type PaymentIntentTransition struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PaymentIntentID uuid.UUID `json:"payment_intent_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_payment_intent_transitions_version"`
+	FromStatus      string    `json:"from_status" gorm:"type:varchar(50);not null"` // Empty for the intent's creation
+	ToStatus        string    `json:"to_status" gorm:"type:varchar(50);not null"`
+	Reason          string    `json:"reason" gorm:"type:text"`
+	Version         int64     `json:"version" gorm:"not null;uniqueIndex:idx_payment_intent_transitions_version"` // Intent version after the transition
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
 // Payment represents a completed or attempted payment
 type Payment struct {
 	ID                uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	PaymentIntentID   uuid.UUID       `json:"payment_intent_id" gorm:"type:uuid;not null;index"`
 	PaymentIntent     *PaymentIntent  `json:"payment_intent,omitempty" gorm:"foreignKey:PaymentIntentID"`
-	Amount            decimal.Decimal `json:"amount" gorm:"type:decimal(20,2);not null"`
+	Amount            decimal.Decimal `json:"amount" gorm:"type:decimal(20,3);not null"`
 	Currency          string          `json:"currency" gorm:"type:varchar(3);not null;default:'INR'"`
 	Status            string          `json:"status" gorm:"type:varchar(50);not null;index"`
 	PaymentMethod     string          `json:"payment_method" gorm:"type:varchar(50);not null"`
@@ -36,18 +73,69 @@ type Payment struct {
 	FailureCode       *string         `json:"failure_code"`
 	FailureMessage    *string         `json:"failure_message"`
 	ProcessedAt       *time.Time      `json:"processed_at"`
+	AuthorizedAmount  *decimal.Decimal `json:"authorized_amount" gorm:"type:decimal(20,3)"` // Manual capture only; Amount is what was captured
+	AuthorizationExpiresAt *time.Time `json:"authorization_expires_at" gorm:"index"`       // Uncaptured authorizations are voided then
+	CapturedAt        *time.Time      `json:"captured_at"`
 	SettledAt         *time.Time      `json:"settled_at"`
+	SettlementID      *string         `json:"settlement_id" gorm:"type:varchar(100);index"` // UPI Core's, once reconciled
+	RiskScore         *float64        `json:"risk_score" gorm:"type:decimal(5,4)"`
+	RiskDecision      string          `json:"risk_decision" gorm:"type:varchar(20);index"` // ALLOW, REVIEW, BLOCK
+	RiskAssessmentID  *uuid.UUID      `json:"risk_assessment_id" gorm:"type:uuid"`
 	Metadata          map[string]interface{} `json:"metadata" gorm:"type:jsonb"`
+	Version           int64           `json:"version" gorm:"not null;default:0"` // Sequence of its last event
 	CreatedAt         time.Time       `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt         time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
+// Money returns the payment's amount in minor units of its currency
+func (p Payment) Money() (money.Money, error) {
+	return money.New(p.Amount, p.Currency)
+}
+
+// MarshalJSON writes the payment with its amounts as money.Money: to its
+// currency's exponent and in minor units
+func (p Payment) MarshalJSON() ([]byte, error) {
+	type payment Payment
+	amount, err := p.Money()
+	if err != nil {
+		return nil, err
+	}
+	var authorized *string
+	var authorizedMinor *int64
+	if p.AuthorizedAmount != nil {
+		authorizedAmount, err := money.New(*p.AuthorizedAmount, p.Currency)
+		if err != nil {
+			return nil, err
+		}
+		formatted := authorizedAmount.String()
+		authorized, authorizedMinor = &formatted, &authorizedAmount.Minor
+	}
+	return json.Marshal(struct {
+		payment
+		Amount               string  `json:"amount"`
+		MinorUnits           int64   `json:"minor_units"`
+		AuthorizedAmount     *string `json:"authorized_amount"`
+		AuthorizedMinorUnits *int64  `json:"authorized_minor_units"`
+	}{payment(p), amount.String(), amount.Minor, authorized, authorizedMinor})
+}
+
+// PaymentEvent is an immutable record of a change to a payment. A
+// payment's events, in sequence, rebuild it.
+type PaymentEvent struct {
+	ID        uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PaymentID uuid.UUID       `json:"payment_id" gorm:"type:uuid;not null;uniqueIndex:idx_payment_events_sequence"`
+	Sequence  int64           `json:"sequence" gorm:"not null;uniqueIndex:idx_payment_events_sequence"`
+	EventType string          `json:"event_type" gorm:"type:varchar(50);not null;index"`
+	Data      json.RawMessage `json:"data" gorm:"type:jsonb"`
+	CreatedAt time.Time       `json:"created_at" gorm:"autoCreateTime"`
+}
+
 // Refund represents a refund transaction
 type Refund struct {
 	ID              uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	PaymentID       uuid.UUID       `json:"payment_id" gorm:"type:uuid;not null;index"`
 	Payment         *Payment        `json:"payment,omitempty" gorm:"foreignKey:PaymentID"`
-	Amount          decimal.Decimal `json:"amount" gorm:"type:decimal(20,2);not null"`
+	Amount          decimal.Decimal `json:"amount" gorm:"type:decimal(20,3);not null"`
 	Currency        string          `json:"currency" gorm:"type:varchar(3);not null;default:'INR'"`
 	Reason          string          `json:"reason" gorm:"type:varchar(255)"`
 	Status          string          `json:"status" gorm:"type:varchar(50);not null;default:'pending';index"`
@@ -55,19 +143,41 @@ type Refund struct {
 	FailureCode     *string         `json:"failure_code"`
 	FailureMessage  *string         `json:"failure_message"`
 	ProcessedAt     *time.Time      `json:"processed_at"`
+	SubmittedAt     *time.Time      `json:"submitted_at"` // When the rail accepted it
 	Metadata        map[string]interface{} `json:"metadata" gorm:"type:jsonb"`
 	CreatedAt       time.Time       `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
-// LedgerEntry represents an entry in the double-entry ledger
+// Money returns the refund's amount in minor units of its currency
+func (r Refund) Money() (money.Money, error) {
+	return money.New(r.Amount, r.Currency)
+}
+
+// MarshalJSON writes the refund with its amount as a money.Money: to its
+// currency's exponent and in minor units
+func (r Refund) MarshalJSON() ([]byte, error) {
+	type refund Refund
+	amount, err := r.Money()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		refund
+		Amount     string `json:"amount"`
+		MinorUnits int64  `json:"minor_units"`
+	}{refund(r), amount.String(), amount.Minor})
+}
+
+// LedgerEntry represents an entry in the double-entry ledger: a posting of
+// the journal entry TransactionID to an account
 type LedgerEntry struct {
 	ID            uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	TransactionID uuid.UUID       `json:"transaction_id" gorm:"type:uuid;not null;index"`
 	AccountID     uuid.UUID       `json:"account_id" gorm:"type:uuid;not null;index"`
 	AccountType   string          `json:"account_type" gorm:"type:varchar(50);not null"`
-	DebitAmount   decimal.Decimal `json:"debit_amount" gorm:"type:decimal(20,2);default:0"`
-	CreditAmount  decimal.Decimal `json:"credit_amount" gorm:"type:decimal(20,2);default:0"`
+	DebitAmount   decimal.Decimal `json:"debit_amount" gorm:"type:decimal(20,3);default:0"`
+	CreditAmount  decimal.Decimal `json:"credit_amount" gorm:"type:decimal(20,3);default:0"`
 	Currency      string          `json:"currency" gorm:"type:varchar(3);not null;default:'INR'"`
 	Description   string          `json:"description" gorm:"type:text"`
 	ReferenceType string          `json:"reference_type" gorm:"type:varchar(50);not null"` // payment, refund, fee, etc.
@@ -75,29 +185,65 @@ type LedgerEntry struct {
 	CreatedAt     time.Time       `json:"created_at" gorm:"autoCreateTime"`
 }
 
-// IdempotencyKey represents stored idempotency keys with TTL
+// LedgerAccount is an account of the ledger, one per code and currency:
+// the platform's clearing and fee accounts, and an account per merchant.
+// Balance is kept in step with the account's postings, on its normal side.
+type LedgerAccount struct {
+	ID         uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Code       string          `json:"code" gorm:"type:varchar(100);not null;uniqueIndex:idx_ledger_accounts_code_currency"`
+	Type       string          `json:"type" gorm:"type:varchar(50);not null"`
+	MerchantID *uuid.UUID      `json:"merchant_id,omitempty" gorm:"type:uuid;index"`
+	Currency   string          `json:"currency" gorm:"type:varchar(3);not null;uniqueIndex:idx_ledger_accounts_code_currency"`
+	Balance    decimal.Decimal `json:"balance" gorm:"type:decimal(20,3);not null;default:0"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// JournalEntry is a balanced set of ledger entries recording one event, a
+// payment, fee, refund or payout. Each event is journaled once.
+type JournalEntry struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Description   string    `json:"description" gorm:"type:text"`
+	ReferenceType string    `json:"reference_type" gorm:"type:varchar(50);not null;uniqueIndex:idx_journal_entries_reference"`
+	ReferenceID   uuid.UUID `json:"reference_id" gorm:"type:uuid;not null;uniqueIndex:idx_journal_entries_reference"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// IdempotencyKey represents stored idempotency keys with TTL. A key is
+// IN_PROGRESS, claimed by the request processing it until LockedUntil, and
+// then COMPLETED with the response replayed to repeats of the request.
 type IdempotencyKey struct {
-	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Key          string    `json:"key" gorm:"type:varchar(255);unique;not null;index"`
-	RequestHash  string    `json:"request_hash" gorm:"type:varchar(64);not null"`
-	ResponseData []byte    `json:"response_data"`
-	StatusCode   int       `json:"status_code"`
-	ExpiresAt    time.Time `json:"expires_at" gorm:"index"`
-	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Key             string     `json:"key" gorm:"type:varchar(255);unique;not null;index"`
+	RequestHash     string     `json:"request_hash" gorm:"type:varchar(64);not null"`
+	State           string     `json:"state" gorm:"type:varchar(20);not null;default:'COMPLETED'"`
+	ResponseData    []byte     `json:"response_data"`
+	ResponseHeaders []byte     `json:"response_headers"` // JSON-encoded http.Header
+	StatusCode      int        `json:"status_code"`
+	LockedUntil     *time.Time `json:"locked_until"`
+	ExpiresAt       time.Time  `json:"expires_at" gorm:"index"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
 }
 
 // WebhookEndpoint represents a webhook endpoint configuration
 type WebhookEndpoint struct {
-	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	MerchantID  uuid.UUID `json:"merchant_id" gorm:"type:uuid;not null;index"`
-	URL         string    `json:"url" gorm:"type:varchar(255);not null"`
-	Secret      string    `json:"secret" gorm:"type:varchar(255);not null"`
-	Events      []string  `json:"events" gorm:"type:text[]"`
-	Active      bool      `json:"active" gorm:"default:true"`
-	Version     string    `json:"version" gorm:"type:varchar(10);default:'v1'"`
-	Description string    `json:"description" gorm:"type:text"`
-	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                  uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MerchantID          uuid.UUID  `json:"merchant_id" gorm:"type:uuid;not null;index"`
+	URL                 string     `json:"url" gorm:"type:varchar(255);not null"`
+	Secret              string     `json:"secret" gorm:"type:varchar(255);not null"`
+	PreviousSecret      *string    `json:"-" gorm:"type:varchar(255)"`      // Also signed with while rotating,
+	PreviousSecretUntil *time.Time `json:"previous_secret_until,omitempty"` // until then
+	Events              []string   `json:"events" gorm:"type:text[]"`
+	Active              bool       `json:"active" gorm:"default:true"`
+	Version             string     `json:"version" gorm:"type:varchar(10);default:'v1'"`
+	Description         string     `json:"description" gorm:"type:text"`
+	ConsecutiveFailures int        `json:"consecutive_failures" gorm:"not null;default:0"`
+	FailingSince        *time.Time `json:"failing_since"`
+	BackoffUntil        *time.Time `json:"backoff_until"` // No deliveries are attempted before then
+	DisabledAt          *time.Time `json:"disabled_at"`
+	DisabledReason      *string    `json:"disabled_reason"`
+	CreatedAt           time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 // WebhookDelivery represents a webhook delivery attempt
@@ -108,19 +254,106 @@ type WebhookDelivery struct {
 	EventType       string    `json:"event_type" gorm:"type:varchar(100);not null"`
 	EventID         uuid.UUID `json:"event_id" gorm:"type:uuid;not null;index"`
 	Payload         []byte    `json:"payload" gorm:"type:jsonb"`
-	Signature       string    `json:"signature" gorm:"type:varchar(255)"`
-	Status          string    `json:"status" gorm:"type:varchar(50);not null;default:'pending'"`
+	Signature       string    `json:"signature" gorm:"type:varchar(255)"` // Of the last attempt
+	Status          string    `json:"status" gorm:"type:varchar(50);not null;default:'pending';index:idx_webhook_deliveries_due,priority:1"`
 	AttemptCount    int       `json:"attempt_count" gorm:"default:0"`
 	MaxAttempts     int       `json:"max_attempts" gorm:"default:5"`
-	NextAttemptAt   *time.Time `json:"next_attempt_at"`
+	NextAttemptAt   *time.Time `json:"next_attempt_at" gorm:"index:idx_webhook_deliveries_due,priority:2"`
 	ResponseStatus  *int      `json:"response_status"`
 	ResponseBody    *string   `json:"response_body"`
 	FailureReason   *string   `json:"failure_reason"`
 	DeliveredAt     *time.Time `json:"delivered_at"`
+	DeadLetteredAt  *time.Time `json:"dead_lettered_at"`
 	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
+// MerchantRail records whether a merchant has a payment rail enabled,
+// overriding the service's default rails
+type MerchantRail struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MerchantID uuid.UUID `json:"merchant_id" gorm:"type:uuid;not null;uniqueIndex:idx_merchant_rails_merchant_rail"`
+	Rail       string    `json:"rail" gorm:"type:varchar(20);not null;uniqueIndex:idx_merchant_rails_merchant_rail"`
+	Enabled    bool      `json:"enabled" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// MerchantRateLimit holds a merchant's own API budgets, per minute,
+// overriding the service's defaults. A nil budget is the default.
+type MerchantRateLimit struct {
+	MerchantID      uuid.UUID `json:"merchant_id" gorm:"type:uuid;primary_key"`
+	ReadsPerMinute  *int      `json:"reads_per_minute"`
+	WritesPerMinute *int      `json:"writes_per_minute"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// PaymentAttempt records one attempt to send a payment over a rail. Retries
+// and failovers add attempts, each sent under a rail reference of its own.
+type PaymentAttempt struct {
+	ID                 uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PaymentID          uuid.UUID  `json:"payment_id" gorm:"type:uuid;not null;uniqueIndex:idx_payment_attempts_payment_number"`
+	AttemptNumber      int        `json:"attempt_number" gorm:"not null;uniqueIndex:idx_payment_attempts_payment_number"`
+	Rail               string     `json:"rail" gorm:"type:varchar(20);not null"`
+	RailReference      uuid.UUID  `json:"rail_reference" gorm:"type:uuid;not null"`
+	RailTransactionID  string     `json:"rail_transaction_id" gorm:"type:varchar(255)"`
+	Status             string     `json:"status" gorm:"type:varchar(20);not null"`
+	FailureCategory    string     `json:"failure_category,omitempty" gorm:"type:varchar(50)"`
+	FailureCode        *string    `json:"failure_code"`
+	FailureMessage     *string    `json:"failure_message"`
+	RetryAt            *time.Time `json:"retry_at" gorm:"index"`
+	PayerVPA           string     `json:"payer_vpa,omitempty" gorm:"type:varchar(255)"`
+	PayeeVPA           string     `json:"payee_vpa,omitempty" gorm:"type:varchar(255)"`
+	CardToken          string     `json:"-" gorm:"type:varchar(255)"`
+	BankCode           string     `json:"bank_code,omitempty" gorm:"type:varchar(20)"`
+	PaymentMethodToken string     `json:"payment_method_token,omitempty" gorm:"type:varchar(40)"` // Saved method paid with, in place of its instrument
+	CreatedAt          time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ThreeDSChallenge is a step-up authentication, e.g. 3-D Secure, a payment
+// is waiting on. The payer authenticates at RedirectURL, the issuer's ACS,
+// which returns them to the challenge callback; the rail then verifies the
+// result and the payment resumes.
+type ThreeDSChallenge struct {
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PaymentID         uuid.UUID  `json:"payment_id" gorm:"type:uuid;not null;index"`
+	PaymentIntentID   uuid.UUID  `json:"payment_intent_id" gorm:"type:uuid;not null;index"`
+	Rail              string     `json:"rail" gorm:"type:varchar(20);not null"`
+	RailTransactionID string     `json:"rail_transaction_id" gorm:"type:varchar(255)"`
+	RedirectURL       string     `json:"redirect_url" gorm:"type:text;not null"`
+	ReturnURL         string     `json:"return_url,omitempty" gorm:"type:text"`   // Where the payer goes once done
+	Reason            string     `json:"reason" gorm:"type:varchar(20);not null"` // risk_review or rail
+	Status            string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	FailureMessage    *string    `json:"failure_message,omitempty"`
+	ExpiresAt         time.Time  `json:"expires_at" gorm:"not null;index"`
+	CompletedAt       *time.Time `json:"completed_at"`
+	CreatedAt         time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// UPIDeadLetter is a payment's call to UPI Core that failed because UPI
+// Core could not be reached, kept with its request to be replayed once it
+// recovers. The payment stays processing until then.
+type UPIDeadLetter struct {
+	ID              uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PaymentID       uuid.UUID       `json:"payment_id" gorm:"type:uuid;not null;index"`
+	PaymentIntentID uuid.UUID       `json:"payment_intent_id" gorm:"type:uuid;not null"`
+	MerchantID      uuid.UUID       `json:"merchant_id" gorm:"type:uuid;not null;index"`
+	AttemptID       uuid.UUID       `json:"attempt_id" gorm:"type:uuid;not null"` // The attempt whose call failed last
+	Request         json.RawMessage `json:"request" gorm:"type:jsonb"`            // The call as it was sent
+	FailureCode     *string         `json:"failure_code"`
+	FailureMessage  *string         `json:"failure_message"`
+	Status          string          `json:"status" gorm:"type:varchar(20);not null;index"`
+	Replays         int             `json:"replays" gorm:"not null;default:0"`
+	LastReplayedAt  *time.Time      `json:"last_replayed_at"`
+	ResolvedBy      string          `json:"resolved_by,omitempty" gorm:"type:varchar(255)"` // Operator who replayed or discarded it, if not the worker
+	ResolvedAt      *time.Time      `json:"resolved_at"`
+	CreatedAt       time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
 // RiskAssessment represents a risk assessment result
 type RiskAssessment struct {
 	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -128,8 +361,8 @@ type RiskAssessment struct {
 	PaymentIntent   *PaymentIntent `json:"payment_intent,omitempty" gorm:"foreignKey:PaymentIntentID"`
 	RiskScore       float64   `json:"risk_score" gorm:"type:decimal(5,4);not null"`
 	RiskLevel       string    `json:"risk_level" gorm:"type:varchar(20);not null"` // LOW, MEDIUM, HIGH
-	Decision        string    `json:"decision" gorm:"type:varchar(20);not null"`   // PASS, CHALLENGE, BLOCK
-	Factors         map[string]interface{} `json:"factors" gorm:"type:jsonb"`
+	Decision        string    `json:"decision" gorm:"type:varchar(20);not null"`   // ALLOW, REVIEW, BLOCK
+	Factors         map[string]interface{} `json:"factors" gorm:"type:jsonb"` // The extracted features
 	Rules           []string  `json:"rules" gorm:"type:text[]"`
 	DeviceID        *string   `json:"device_id"`
 	IPAddress       string    `json:"ip_address" gorm:"type:varchar(45)"`
@@ -137,6 +370,132 @@ type RiskAssessment struct {
 	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
 }
 
+// RiskRule is a risk engine rule: an expression over the features of a
+// payment, and what a match does to it
+type RiskRule struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name        string     `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Description string     `json:"description" gorm:"type:text"`
+	Expression  string     `json:"expression" gorm:"type:text;not null"`
+	Weight      float64    `json:"weight" gorm:"type:decimal(5,4);not null;default:0"` // Added to the score on a match
+	Action      string     `json:"action" gorm:"type:varchar(20);not null;default:'score'"`
+	MerchantID  *uuid.UUID `json:"merchant_id,omitempty" gorm:"type:uuid;index"` // Nil applies to all merchants
+	Enabled     bool       `json:"enabled" gorm:"not null"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// MerchantAPIKey is an API key a merchant authenticates with instead of a
+// JWT. Only its hash is stored.
+type MerchantAPIKey struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MerchantID    uuid.UUID  `json:"merchant_id" gorm:"type:uuid;not null;index"`
+	Name          string     `json:"name" gorm:"type:varchar(100);not null"`
+	Prefix        string     `json:"prefix" gorm:"type:varchar(20);not null"` // Start of the key, to tell keys apart
+	KeyHash       string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Scopes        string     `json:"scopes" gorm:"type:text;not null"` // Space-separated
+	RotatedFromID *uuid.UUID `json:"rotated_from_id,omitempty" gorm:"type:uuid"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	LastUsedAt    *time.Time `json:"last_used_at"`
+	LastUsedIP    string     `json:"last_used_ip,omitempty" gorm:"type:varchar(45)"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// HasScope reports whether the key was granted scope
+func (k *MerchantAPIKey) HasScope(scope string) bool {
+	for _, granted := range strings.Fields(k.Scopes) {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconciliationException is a record of a settlement report that did not
+// match a captured payment, queued for an operator to resolve
+type ReconciliationException struct {
+	ID             uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	BankCode       string          `json:"bank_code" gorm:"type:varchar(20);not null"`
+	RRN            string          `json:"rrn" gorm:"type:varchar(50);not null;uniqueIndex:idx_reconciliation_exceptions_record"`
+	SettlementID   string          `json:"settlement_id" gorm:"type:varchar(100);not null;uniqueIndex:idx_reconciliation_exceptions_record"`
+	TransactionID  string          `json:"transaction_id" gorm:"type:varchar(100)"` // UPI Core's
+	Amount         decimal.Decimal `json:"amount" gorm:"type:decimal(20,3);not null"`
+	Fee            decimal.Decimal `json:"fee" gorm:"type:decimal(20,3);not null;default:0"`
+	Currency       string          `json:"currency" gorm:"type:varchar(3);not null;default:'INR'"` // The payment's, or INR without one
+	PaymentID      *uuid.UUID      `json:"payment_id" gorm:"type:uuid;index"`                      // The payment with the RRN, if any
+	Reason         string          `json:"reason" gorm:"type:varchar(50);not null"`
+	Details        string          `json:"details" gorm:"type:text"`
+	Status         string          `json:"status" gorm:"type:varchar(20);not null;default:'open';index"`
+	Resolution     string          `json:"resolution,omitempty" gorm:"type:varchar(20)"`
+	ResolutionNote string          `json:"resolution_note,omitempty" gorm:"type:text"`
+	ResolvedBy     string          `json:"resolved_by,omitempty" gorm:"type:varchar(255)"`
+	ResolvedAt     *time.Time      `json:"resolved_at"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// PaymentMethod is a customer's saved card or VPA, referenced by its token.
+// The instrument itself is only stored encrypted; the rest is what can be
+// shown back to the customer.
+type PaymentMethod struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Token         string    `json:"token" gorm:"type:varchar(40);not null;uniqueIndex"`
+	MerchantID    uuid.UUID `json:"merchant_id" gorm:"type:uuid;not null;uniqueIndex:idx_payment_methods_fingerprint"`
+	CustomerID    uuid.UUID `json:"customer_id" gorm:"type:uuid;not null;uniqueIndex:idx_payment_methods_fingerprint"`
+	Type          string    `json:"type" gorm:"type:varchar(20);not null"`
+	Brand         string    `json:"brand,omitempty" gorm:"type:varchar(20)"` // Card
+	Last4         string    `json:"last4,omitempty" gorm:"type:varchar(4)"`  // Card
+	ExpMonth      int       `json:"exp_month,omitempty"`                     // Card
+	ExpYear       int       `json:"exp_year,omitempty"`                      // Card
+	VPA           string    `json:"vpa,omitempty" gorm:"type:varchar(255)"`  // UPI, masked
+	Fingerprint   string    `json:"fingerprint" gorm:"type:varchar(64);not null;uniqueIndex:idx_payment_methods_fingerprint"`
+	EncryptedData []byte    `json:"-" gorm:"not null"`
+	EncryptedKey  []byte    `json:"-" gorm:"not null"` // Data key, encrypted with the vault key
+	KeyID         string    `json:"-" gorm:"type:varchar(20);not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// PaymentCommand is a payment queued to be processed asynchronously. The
+// command's status says whether it was processed; the payment it made, and
+// its intent, say how that went.
+type PaymentCommand struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PaymentIntentID uuid.UUID  `json:"payment_intent_id" gorm:"type:uuid;not null;index"`
+	MerchantID      uuid.UUID  `json:"merchant_id" gorm:"type:uuid;not null;index"`
+	Request         []byte     `json:"-" gorm:"type:jsonb;not null"` // The payment request
+	Status          string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	Attempts        int        `json:"attempts" gorm:"not null;default:0"`
+	PaymentID       *uuid.UUID `json:"payment_id" gorm:"type:uuid"`
+	Error           string     `json:"error,omitempty" gorm:"type:text"`
+	ClaimedAt       *time.Time `json:"claimed_at"`
+	CompletedAt     *time.Time `json:"completed_at"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Report is an export of a merchant's payments, refunds or fees over a
+// date range. It is generated in the background; once ready, Content holds
+// the file to download.
+type Report struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MerchantID   uuid.UUID  `json:"merchant_id" gorm:"type:uuid;not null;index"`
+	Type         string     `json:"type" gorm:"type:varchar(20);not null"`   // payments, refunds or fees
+	Format       string     `json:"format" gorm:"type:varchar(10);not null"` // csv or json
+	DateFrom     time.Time  `json:"from" gorm:"not null"`
+	DateTo       time.Time  `json:"to" gorm:"not null"` // Exclusive
+	FilterStatus string     `json:"filter_status,omitempty" gorm:"type:varchar(50)"`
+	Status       string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	RowCount     int        `json:"row_count" gorm:"not null;default:0"`
+	Content      []byte     `json:"-" gorm:"type:bytea"`
+	Error        string     `json:"error,omitempty" gorm:"type:text"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
 // OutboxEvent represents events to be published for exactly-once semantics
 type OutboxEvent struct {
 	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -151,16 +510,43 @@ type OutboxEvent struct {
 
 // PaymentStatus constants
 const (
-	PaymentIntentStatusCreated   = "created"
-	PaymentIntentStatusExpired   = "expired"
-	PaymentIntentStatusCanceled  = "canceled"
-	PaymentIntentStatusSucceeded = "succeeded"
+	PaymentIntentStatusRequiresPaymentMethod = "requires_payment_method"
+	PaymentIntentStatusRequiresConfirmation  = "requires_confirmation"
+	PaymentIntentStatusProcessing            = "processing"
+	PaymentIntentStatusRequiresAction        = "requires_action" // Waiting on the payer to authenticate
+	PaymentIntentStatusRequiresCapture       = "requires_capture" // Authorized, waiting on the merchant to capture
+	PaymentIntentStatusSucceeded             = "succeeded"
+	PaymentIntentStatusFailed                = "failed"
+	PaymentIntentStatusCanceled              = "canceled"
+
+	PaymentStatusPending        = "pending"
+	PaymentStatusProcessing     = "processing"
+	PaymentStatusSucceeded      = "succeeded"
+	PaymentStatusFailed         = "failed"
+	PaymentStatusCanceled       = "canceled"
+	PaymentStatusRequiresAction = "requires_action"
+	PaymentStatusAuthorized     = "authorized" // Held on the payer's card until captured or voided
+
+	CaptureMethodAutomatic = "automatic"
+	CaptureMethodManual    = "manual"
+
+	PaymentAttemptStatusSucceeded = "succeeded"
+	PaymentAttemptStatusFailed    = "failed"
+	PaymentAttemptStatusPending   = "pending"
+
+	ThreeDSChallengeStatusPending   = "pending"
+	ThreeDSChallengeStatusSucceeded = "succeeded"
+	ThreeDSChallengeStatusFailed    = "failed"
+	ThreeDSChallengeStatusExpired   = "expired"
+
+	ThreeDSReasonRiskReview = "risk_review" // The risk engine flagged the payment
+	ThreeDSReasonRail       = "rail"        // The rail or issuer asked for it
 
-	PaymentStatusPending   = "pending"
-	PaymentStatusProcessing = "processing"
-	PaymentStatusSucceeded = "succeeded"
-	PaymentStatusFailed    = "failed"
-	PaymentStatusCanceled  = "canceled"
+	UPIDeadLetterStatusPending   = "pending"
+	UPIDeadLetterStatusReplaying = "replaying"
+	UPIDeadLetterStatusReplayed  = "replayed"
+	UPIDeadLetterStatusDiscarded = "discarded"
+	UPIDeadLetterStatusExhausted = "exhausted" // Replayed the maximum times; the payment failed
 
 	RefundStatusPending   = "pending"
 	RefundStatusProcessing = "processing"
@@ -168,9 +554,42 @@ const (
 	RefundStatusFailed    = "failed"
 	RefundStatusCanceled  = "canceled"
 
-	RiskDecisionPass      = "PASS"
-	RiskDecisionChallenge = "CHALLENGE"
-	RiskDecisionBlock     = "BLOCK"
+	WebhookDeliveryStatusPending      = "pending"
+	WebhookDeliveryStatusDelivering   = "delivering" // Claimed by a worker until next_attempt_at
+	WebhookDeliveryStatusRetrying     = "retrying"
+	WebhookDeliveryStatusDelivered    = "delivered"
+	WebhookDeliveryStatusDeadLettered = "dead_lettered"
+
+	ReconExceptionStatusOpen     = "open"
+	ReconExceptionStatusResolved = "resolved"
+
+	PaymentMethodTypeCard = "card"
+	PaymentMethodTypeUPI  = "upi"
+
+	PaymentCommandStatusQueued     = "queued"
+	PaymentCommandStatusProcessing = "processing"
+	PaymentCommandStatusCompleted  = "completed"
+	PaymentCommandStatusFailed     = "failed"
+
+	ReportTypePayments = "payments"
+	ReportTypeRefunds  = "refunds"
+	ReportTypeFees     = "fees"
+
+	ReportFormatCSV  = "csv"
+	ReportFormatJSON = "json"
+
+	ReportStatusPending    = "pending"
+	ReportStatusGenerating = "generating"
+	ReportStatusReady      = "ready"
+	ReportStatusFailed     = "failed"
+
+	RiskDecisionAllow  = "ALLOW"
+	RiskDecisionReview = "REVIEW"
+	RiskDecisionBlock  = "BLOCK"
+
+	RiskRuleActionScore  = "score"  // Only adds its weight
+	RiskRuleActionReview = "review" // At least reviews the payment
+	RiskRuleActionBlock  = "block"  // Blocks the payment
 
 	RiskLevelLow    = "LOW"
 	RiskLevelMedium = "MEDIUM"
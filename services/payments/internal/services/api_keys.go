@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// API key scopes. JWTs are not scoped; an API key can only call the routes
+// its scopes cover.
+const (
+	ScopePaymentsRead        = "payments:read"
+	ScopePaymentsWrite       = "payments:write"
+	ScopeRefundsRead         = "refunds:read"
+	ScopeRefundsWrite        = "refunds:write"
+	ScopeRailsRead           = "rails:read"
+	ScopeRailsWrite          = "rails:write"
+	ScopeLedgerRead          = "ledger:read"
+	ScopeLedgerWrite         = "ledger:write"
+	ScopeRiskRead            = "risk:read"
+	ScopeRiskWrite           = "risk:write"
+	ScopeWebhooksRead        = "webhooks:read"
+	ScopeWebhooksWrite       = "webhooks:write"
+	ScopePaymentMethodsRead  = "payment_methods:read"
+	ScopePaymentMethodsWrite = "payment_methods:write"
+	ScopeReportsRead         = "reports:read"
+	ScopeReportsWrite        = "reports:write"
+
+	// ScopeAPIKeysWrite guards API key management. It cannot be granted to
+	// a key, so keys are only managed with a JWT.
+	ScopeAPIKeysWrite = "api_keys:write"
+	// ScopeReconciliationWrite guards the settlement reconciliation
+	// exceptions, which span merchants. Like ScopeAPIKeysWrite it needs a
+	// JWT.
+	ScopeReconciliationWrite = "reconciliation:write"
+	// ScopeRateLimitsWrite guards merchants' rate limits, which merchants
+	// must not raise themselves. Like ScopeAPIKeysWrite it needs a JWT.
+	ScopeRateLimitsWrite = "rate_limits:write"
+	// ScopeUPIDeadLettersWrite guards the UPI dead letter queue, which
+	// spans merchants. Like ScopeAPIKeysWrite it needs a JWT.
+	ScopeUPIDeadLettersWrite = "upi_dead_letters:write"
+)
+
+// APIKeyScopes are the scopes a key can be granted
+var APIKeyScopes = []string{
+	ScopePaymentsRead, ScopePaymentsWrite,
+	ScopeRefundsRead, ScopeRefundsWrite,
+	ScopeRailsRead, ScopeRailsWrite,
+	ScopeLedgerRead, ScopeLedgerWrite,
+	ScopeRiskRead, ScopeRiskWrite,
+	ScopeWebhooksRead, ScopeWebhooksWrite,
+	ScopePaymentMethodsRead, ScopePaymentMethodsWrite,
+	ScopeReportsRead, ScopeReportsWrite,
+}
+
+var (
+	// ErrAPIKeyNotFound is returned for an API key that does not exist
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrInvalidAPIKey is returned when authenticating with a key that
+	// does not exist, was revoked or has expired
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrAPIKeyRevoked is returned when rotating a revoked key
+	ErrAPIKeyRevoked = errors.New("API key is revoked")
+	// ErrInvalidAPIKeyRequest is returned for a key request with unknown
+	// scopes or a bad expiry
+	ErrInvalidAPIKeyRequest = errors.New("invalid API key request")
+)
+
+const (
+	// apiKeySecretLength is the number of random base62 characters in a key
+	apiKeySecretLength = 32
+	// apiKeyDisplayLength is how many random characters are kept, after
+	// the prefix, to identify a key
+	apiKeyDisplayLength = 4
+	// apiKeyLastUsedInterval is how stale last_used_at may get, so every
+	// request does not write to the key
+	apiKeyLastUsedInterval = time.Minute
+)
+
+// APIKeyService manages merchant API keys and authenticates requests made
+// with them. Keys are only stored hashed; the key itself is returned once,
+// when it is created.
+type APIKeyService struct {
+	db          *gorm.DB
+	logger      *logrus.Logger
+	prefix      string        // e.g. sk_live_
+	rotateGrace time.Duration // Default time a rotated key keeps working
+}
+
+// NewAPIKeyService creates a new API key service. Keys are prefixed with
+// sk_live_ in production and sk_test_ elsewhere.
+func NewAPIKeyService(db *gorm.DB, logger *logrus.Logger, environment string, rotateGraceHours int) *APIKeyService {
+	prefix := "sk_test_"
+	if environment == "production" {
+		prefix = "sk_live_"
+	}
+	return &APIKeyService{
+		db:          db,
+		logger:      logger,
+		prefix:      prefix,
+		rotateGrace: time.Duration(rotateGraceHours) * time.Hour,
+	}
+}
+
+// IsAPIKey reports whether a bearer token is an API key rather than a JWT
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, "sk_live_") || strings.HasPrefix(token, "sk_test_")
+}
+
+// CreateAPIKeyRequest represents an API key creation request
+type CreateAPIKeyRequest struct {
+	MerchantID uuid.UUID  `json:"merchant_id" binding:"required"`
+	Name       string     `json:"name" binding:"required,max=100"`
+	Scopes     []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+// CreatedAPIKey is a new key, with the key itself, which is not stored
+type CreatedAPIKey struct {
+	*models.MerchantAPIKey
+	Key string `json:"key"`
+}
+
+// CreateAPIKey creates an API key for a merchant
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expires_at is in the past", ErrInvalidAPIKeyRequest)
+	}
+
+	key, created, err := s.newKey(req.MerchantID, req.Name, scopes, req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(key).Error; err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"merchant_id": key.MerchantID,
+		"api_key_id":  key.ID,
+		"scopes":      key.Scopes,
+	}).Info("API key created")
+	return created, nil
+}
+
+// ListAPIKeys lists a merchant's API keys, including revoked ones
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, merchantID uuid.UUID) ([]models.MerchantAPIKey, error) {
+	var keys []models.MerchantAPIKey
+	err := s.db.WithContext(ctx).
+		Where("merchant_id = ?", merchantID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RotateAPIKeyRequest rotates an API key
+type RotateAPIKeyRequest struct {
+	// GraceSeconds the old key keeps working for; defaults to the
+	// service's grace period, and 0 revokes it straight away
+	GraceSeconds *int `json:"grace_seconds"`
+}
+
+// RotateAPIKey replaces a key with a new one with the same name, scopes
+// and expiry. The old key expires after the grace period, so the merchant
+// can deploy the new one first.
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, id uuid.UUID, req RotateAPIKeyRequest) (*CreatedAPIKey, error) {
+	grace := s.rotateGrace
+	if req.GraceSeconds != nil {
+		if *req.GraceSeconds < 0 {
+			return nil, fmt.Errorf("%w: grace_seconds must not be negative", ErrInvalidAPIKeyRequest)
+		}
+		grace = time.Duration(*req.GraceSeconds) * time.Second
+	}
+
+	var created *CreatedAPIKey
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		old, err := s.lockKey(tx, id)
+		if err != nil {
+			return err
+		}
+		if old.RevokedAt != nil {
+			return ErrAPIKeyRevoked
+		}
+
+		key, newKey, err := s.newKey(old.MerchantID, old.Name, old.Scopes, old.ExpiresAt)
+		if err != nil {
+			return err
+		}
+		key.RotatedFromID = &old.ID
+		if err := tx.Create(key).Error; err != nil {
+			return fmt.Errorf("failed to create API key: %w", err)
+		}
+
+		// The old key expires after the grace period, or when it would have
+		now := time.Now()
+		expiresAt := now.Add(grace)
+		if old.ExpiresAt != nil && old.ExpiresAt.Before(expiresAt) {
+			expiresAt = *old.ExpiresAt
+		}
+		updates := map[string]interface{}{"expires_at": expiresAt, "updated_at": now}
+		if grace == 0 {
+			updates["revoked_at"] = now
+		}
+		if err := tx.Model(old).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to expire rotated API key: %w", err)
+		}
+		created = newKey
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"merchant_id":     created.MerchantID,
+		"api_key_id":      created.ID,
+		"rotated_from_id": id,
+		"grace":           grace,
+	}).Info("API key rotated")
+	return created, nil
+}
+
+// RevokeAPIKey stops a key working immediately
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) (*models.MerchantAPIKey, error) {
+	var key *models.MerchantAPIKey
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if key, err = s.lockKey(tx, id); err != nil {
+			return err
+		}
+		if key.RevokedAt != nil {
+			return nil
+		}
+		now := time.Now()
+		key.RevokedAt = &now
+		key.UpdatedAt = now
+		return tx.Model(key).Select("revoked_at", "updated_at").Updates(key).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"merchant_id": key.MerchantID,
+		"api_key_id":  key.ID,
+	}).Info("API key revoked")
+	return key, nil
+}
+
+// Authenticate returns the key a request presented, if it is live, and
+// records its use
+func (s *APIKeyService) Authenticate(ctx context.Context, presented, clientIP string) (*models.MerchantAPIKey, error) {
+	var key models.MerchantAPIKey
+	err := s.db.WithContext(ctx).Where("key_hash = ?", hashAPIKey(presented)).First(&key).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to look up API key: %w", err)
+	}
+
+	now := time.Now()
+	switch {
+	case key.RevokedAt != nil:
+		return nil, fmt.Errorf("%w: revoked", ErrInvalidAPIKey)
+	case key.ExpiresAt != nil && !now.Before(*key.ExpiresAt):
+		return nil, fmt.Errorf("%w: expired", ErrInvalidAPIKey)
+	}
+
+	// Only one request a minute updates last_used_at
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyLastUsedInterval {
+		err := s.db.WithContext(ctx).Model(&models.MerchantAPIKey{}).
+			Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", key.ID, now.Add(-apiKeyLastUsedInterval)).
+			Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": clientIP}).Error
+		if err != nil {
+			// Tracking is best effort; it must not fail the request
+			s.logger.WithError(err).WithField("api_key_id", key.ID).Warn("Failed to record API key use")
+		}
+		key.LastUsedAt = &now
+		key.LastUsedIP = clientIP
+	}
+	return &key, nil
+}
+
+// newKey generates a key, returning its stored form and the key itself
+func (s *APIKeyService) newKey(merchantID uuid.UUID, name, scopes string, expiresAt *time.Time) (*models.MerchantAPIKey, *CreatedAPIKey, error) {
+	secret, err := randomBase62(apiKeySecretLength)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	plaintext := s.prefix + secret
+
+	now := time.Now()
+	key := &models.MerchantAPIKey{
+		ID:         uuid.New(),
+		MerchantID: merchantID,
+		Name:       name,
+		Prefix:     plaintext[:len(s.prefix)+apiKeyDisplayLength],
+		KeyHash:    hashAPIKey(plaintext),
+		Scopes:     scopes,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	return key, &CreatedAPIKey{MerchantAPIKey: key, Key: plaintext}, nil
+}
+
+// lockKey loads a key for update
+func (s *APIKeyService) lockKey(tx *gorm.DB, id uuid.UUID) (*models.MerchantAPIKey, error) {
+	query := tx.Where("id = ?", id)
+	if tx.Dialector.Name() == "postgres" {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	var key models.MerchantAPIKey
+	if err := query.First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+	return &key, nil
+}
+
+// normalizeScopes checks scopes are grantable, returning them sorted and
+// space-separated as they are stored
+func normalizeScopes(scopes []string) (string, error) {
+	grantable := make(map[string]bool, len(APIKeyScopes))
+	for _, scope := range APIKeyScopes {
+		grantable[scope] = true
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !grantable[scope] {
+			return "", fmt.Errorf("%w: unknown scope %q", ErrInvalidAPIKeyRequest, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	if len(normalized) == 0 {
+		return "", fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIKeyRequest)
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, " "), nil
+}
+
+// hashAPIKey hashes a key for storage. Keys are random, so an unsalted
+// SHA-256 cannot be brute-forced, and lets a key be looked up by its hash.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+func randomBase62(n int) (string, error) {
+	out := make([]byte, n)
+	max := big.NewInt(int64(len(base62)))
+	for i := range out {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		out[i] = base62[idx.Int64()]
+	}
+	return string(out), nil
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/money"
+)
+
+var (
+	// ErrCaptureUnsupported is returned for a manual capture intent paid
+	// over a rail that cannot authorize without capturing
+	ErrCaptureUnsupported = errors.New("payment rail does not support manual capture")
+	// ErrPaymentNotCapturable is returned to capture or void a payment that
+	// is not authorized
+	ErrPaymentNotCapturable = errors.New("payment is not authorized")
+	// ErrAuthorizationExpired is returned to capture an authorization past
+	// its capture window
+	ErrAuthorizationExpired = errors.New("payment authorization has expired")
+	// ErrCaptureDeclined is returned when the rail refused the capture; the
+	// payment stays authorized
+	ErrCaptureDeclined = errors.New("capture declined by rail")
+)
+
+// defaultAuthorizationWindow is how long an authorization can be captured
+// when no policy says otherwise
+const defaultAuthorizationWindow = 7 * 24 * time.Hour
+
+// CapturePolicy is how long payments authorized for manual capture wait on
+// the merchant
+type CapturePolicy struct {
+	AuthorizationWindow time.Duration // Authorizations not captured by then are voided
+}
+
+// authorizationWindow returns the policy's window, or the default
+func (s *PaymentService) authorizationWindow() time.Duration {
+	if s.capture != nil && s.capture.AuthorizationWindow > 0 {
+		return s.capture.AuthorizationWindow
+	}
+	return defaultAuthorizationWindow
+}
+
+// lockPayment reads the payment id, with its intent, locking its row
+func lockPayment(tx *gorm.DB, id uuid.UUID, payment *models.Payment) error {
+	query := tx.Preload("PaymentIntent").Where("id = ?", id)
+	if tx.Dialector.Name() == "postgres" {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := query.First(payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("payment not found")
+		}
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment.PaymentIntent == nil {
+		return fmt.Errorf("payment %s has no payment intent", id)
+	}
+	return nil
+}
+
+// captureRail returns the rail that authorized payment, from its last
+// successful attempt
+func (s *PaymentService) captureRail(tx *gorm.DB, payment *models.Payment) (CaptureRail, error) {
+	var attempt models.PaymentAttempt
+	err := tx.Where("payment_id = ? AND status = ?", payment.ID, models.PaymentAttemptStatusSucceeded).
+		Order("attempt_number DESC").
+		First(&attempt).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authorizing attempt: %w", err)
+	}
+	rail, err := s.rails.RailByName(attempt.Rail)
+	if err != nil {
+		return nil, err
+	}
+	captureRail, ok := rail.(CaptureRail)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCaptureUnsupported, attempt.Rail)
+	}
+	return captureRail, nil
+}
+
+// CapturePayment captures an authorized payment, all of it or, with an
+// amount, part of it. The rest of the authorization is released by the
+// rail. The payment succeeds with the captured amount, which is what is
+// posted to the ledger and can be refunded, and its intent with it.
+func (s *PaymentService) CapturePayment(ctx context.Context, id uuid.UUID, amount *decimal.Decimal) (*models.Payment, error) {
+	log := s.logger.WithField("payment_id", id)
+
+	var payment models.Payment
+	var intentEvent *PaymentIntentEvent
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPayment(tx, id, &payment); err != nil {
+			return err
+		}
+		if payment.Status != models.PaymentStatusAuthorized {
+			return fmt.Errorf("%w: payment is %s", ErrPaymentNotCapturable, payment.Status)
+		}
+		if payment.AuthorizationExpiresAt != nil && time.Now().After(*payment.AuthorizationExpiresAt) {
+			return ErrAuthorizationExpired
+		}
+
+		captured := payment.Amount
+		if amount != nil {
+			m, err := ValidateAmount(*amount, payment.Currency)
+			if err != nil {
+				return fmt.Errorf("capture %w", err)
+			}
+			if m.Decimal().GreaterThan(payment.Amount) {
+				return fmt.Errorf("capture amount %s exceeds authorized amount %s",
+					money.Format(m.Decimal(), payment.Currency), money.Format(payment.Amount, payment.Currency))
+			}
+			captured = m.Decimal()
+		}
+
+		rail, err := s.captureRail(tx, &payment)
+		if err != nil {
+			return err
+		}
+		resp, err := rail.CapturePayment(ctx, RailCaptureRequest{
+			PaymentID:     payment.ID,
+			TransactionID: payment.RailTransactionID,
+			Amount:        captured,
+			Currency:      payment.Currency,
+		})
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			reason := "capture failed"
+			if resp.FailureMessage != nil {
+				reason = *resp.FailureMessage
+			}
+			return fmt.Errorf("%w: %s", ErrCaptureDeclined, reason)
+		}
+
+		now := time.Now()
+		processedAt := resp.ProcessedAt
+		payment.Status = models.PaymentStatusSucceeded
+		payment.Amount = captured
+		payment.ProcessedAt = &processedAt
+		payment.CapturedAt = &now
+		err = appendPaymentEvent(tx, &payment, PaymentEventCaptured, PaymentEventData{
+			Amount:            &captured,
+			RailTransactionID: payment.RailTransactionID,
+			ProcessedAt:       payment.ProcessedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(&payment).Error; err != nil {
+			return fmt.Errorf("failed to update payment status: %w", err)
+		}
+		if err := s.ledgerService.PostPaymentTransaction(ctx, tx, &payment, payment.PaymentIntent.MerchantID); err != nil {
+			return fmt.Errorf("failed to post payment to ledger: %w", err)
+		}
+		intentEvent, err = s.transitionIntent(tx, payment.PaymentIntent, models.PaymentIntentStatusSucceeded, "payment captured")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("amount", payment.Amount.String()).Info("Payment captured")
+	merchantID := payment.PaymentIntent.MerchantID
+	s.emitPaymentIntentEvents(merchantID, intentEvent)
+	go s.webhookService.TriggerWebhook(context.Background(), merchantID, "payment.captured", &payment)
+	return &payment, nil
+}
+
+// voidAuthorization releases an authorized payment uncaptured, canceling
+// it and its intent with failureCode and reason. The payment is canceled
+// even if the rail could not be told; the issuer releases holds it is not
+// told about once they lapse.
+func (s *PaymentService) voidAuthorization(ctx context.Context, id uuid.UUID, failureCode, reason string) (*models.Payment, error) {
+	log := s.logger.WithFields(logrus.Fields{
+		"payment_id":   id,
+		"failure_code": failureCode,
+	})
+
+	var payment models.Payment
+	var intentEvent *PaymentIntentEvent
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPayment(tx, id, &payment); err != nil {
+			return err
+		}
+		if payment.Status != models.PaymentStatusAuthorized {
+			return fmt.Errorf("%w: payment is %s", ErrPaymentNotCapturable, payment.Status)
+		}
+
+		rail, err := s.captureRail(tx, &payment)
+		if err != nil {
+			return err
+		}
+		resp, err := rail.VoidPayment(ctx, RailCaptureRequest{
+			PaymentID:     payment.ID,
+			TransactionID: payment.RailTransactionID,
+			Currency:      payment.Currency,
+		})
+		if err != nil || resp.Status != models.PaymentStatusCanceled {
+			log.WithError(err).Warn("Rail did not void authorization")
+		}
+
+		payment.Status = models.PaymentStatusCanceled
+		payment.FailureCode = &failureCode
+		payment.FailureMessage = &reason
+		err = appendPaymentEvent(tx, &payment, PaymentEventVoided, PaymentEventData{
+			FailureCode:    payment.FailureCode,
+			FailureMessage: payment.FailureMessage,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(&payment).Error; err != nil {
+			return fmt.Errorf("failed to update payment status: %w", err)
+		}
+		intentEvent, err = s.transitionIntent(tx, payment.PaymentIntent, models.PaymentIntentStatusCanceled, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Payment authorization voided")
+	merchantID := payment.PaymentIntent.MerchantID
+	s.emitPaymentIntentEvents(merchantID, intentEvent)
+	go s.webhookService.TriggerWebhook(context.Background(), merchantID, "payment.voided", &payment)
+	return &payment, nil
+}
+
+// voidIntentAuthorization voids the authorized payment of an intent
+// awaiting capture
+func (s *PaymentService) voidIntentAuthorization(ctx context.Context, intent *models.PaymentIntent, reason string) error {
+	var payment models.Payment
+	err := s.db.WithContext(ctx).
+		Where("payment_intent_id = ? AND status = ?", intent.ID, models.PaymentStatusAuthorized).
+		First(&payment).Error
+	if err != nil {
+		return fmt.Errorf("failed to get authorized payment: %w", err)
+	}
+	_, err = s.voidAuthorization(ctx, payment.ID, "CANCELED", reason)
+	return err
+}
+
+// expireAuthorizations voids the authorizations not captured within the
+// capture window
+func (s *PaymentService) expireAuthorizations(ctx context.Context) error {
+	var expired []models.Payment
+	err := s.db.WithContext(ctx).
+		Where("status = ? AND authorization_expires_at <= ?", models.PaymentStatusAuthorized, time.Now()).
+		Order("authorization_expires_at ASC").
+		Limit(50).
+		Find(&expired).Error
+	if err != nil {
+		return fmt.Errorf("failed to get expired authorizations: %w", err)
+	}
+
+	for _, payment := range expired {
+		_, err := s.voidAuthorization(ctx, payment.ID, "AUTHORIZATION_EXPIRED", "authorization expired uncaptured")
+		if err != nil && !errors.Is(err, ErrPaymentNotCapturable) {
+			s.logger.WithError(err).WithField("payment_id", payment.ID).Error("Failed to void expired authorization")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// authorize makes a card payment of a new manual capture intent for
+// amount, which the rail only authorizes
+func (env *testEnv) authorize(t *testing.T, amount string) *models.Payment {
+	intent := env.createIntent(t, amount, "INR", RailCard, 15*time.Minute)
+	require.NoError(t, env.db.Model(intent).Update("capture_method", models.CaptureMethodManual).Error)
+	payment, err := env.payments.CreatePayment(context.Background(), CreatePaymentRequest{PaymentIntentID: intent.ID, CardToken: "tok_visa"})
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusAuthorized, payment.Status)
+	return payment
+}
+
+func (env *testEnv) storedPayment(t *testing.T, payment *models.Payment) *models.Payment {
+	stored, err := env.payments.GetPayment(context.Background(), payment.ID)
+	require.NoError(t, err)
+	return stored
+}
+
+func (env *testEnv) intentStatus(t *testing.T, payment *models.Payment) string {
+	intent, err := env.payments.GetPaymentIntent(context.Background(), payment.PaymentIntentID)
+	require.NoError(t, err)
+	return intent.Status
+}
+
+// postedPayments counts the ledger transactions posted for payment
+func (env *testEnv) postedPayments(t *testing.T, payment *models.Payment) int64 {
+	var posted int64
+	require.NoError(t, env.db.Model(&models.JournalEntry{}).
+		Where("reference_type = ? AND reference_id = ?", "payment", payment.ID).Count(&posted).Error)
+	return posted
+}
+
+func TestCapture_AuthorizesWithoutPosting(t *testing.T) {
+	env := newTestEnv(t)
+	payment := env.authorize(t, "100.00")
+
+	require.Len(t, env.card.payments, 1)
+	assert.True(t, env.card.payments[0].CaptureManually)
+	assert.True(t, payment.AuthorizedAmount.Equal(decimal.NewFromInt(100)))
+	require.NotNil(t, payment.AuthorizationExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(defaultAuthorizationWindow), *payment.AuthorizationExpiresAt, time.Minute)
+	assert.Equal(t, models.PaymentIntentStatusRequiresCapture, env.intentStatus(t, payment))
+	assert.Zero(t, env.postedPayments(t, payment), "authorization posted to the ledger")
+}
+
+func TestCapture_PartialCapture(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	payment := env.authorize(t, "100.00")
+
+	amount := decimal.RequireFromString("60.00")
+	captured, err := env.payments.CapturePayment(ctx, payment.ID, &amount)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusSucceeded, captured.Status)
+	assert.True(t, captured.Amount.Equal(amount), "captured %s", captured.Amount)
+	assert.NotNil(t, captured.CapturedAt)
+
+	require.Len(t, env.card.captures, 1)
+	assert.Equal(t, payment.RailTransactionID, env.card.captures[0].TransactionID)
+	assert.True(t, env.card.captures[0].Amount.Equal(amount))
+	assert.Equal(t, models.PaymentIntentStatusSucceeded, env.intentStatus(t, payment))
+	assert.EqualValues(t, 1, env.postedPayments(t, payment))
+
+	// Only the captured amount can be refunded
+	_, err = env.refunds.CreateRefund(ctx, CreateRefundRequest{PaymentID: payment.ID, Amount: decimal.RequireFromString("60.01")})
+	assert.ErrorIs(t, err, ErrRefundExceedsPayment)
+
+	// Nor can it be captured again
+	_, err = env.payments.CapturePayment(ctx, payment.ID, nil)
+	assert.ErrorIs(t, err, ErrPaymentNotCapturable)
+	assert.Len(t, env.card.captures, 1)
+}
+
+func TestCapture_RejectsOverCapture(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	payment := env.authorize(t, "100.00")
+
+	for _, amount := range []string{"100.01", "0", "10.001"} {
+		over := decimal.RequireFromString(amount)
+		_, err := env.payments.CapturePayment(ctx, payment.ID, &over)
+		assert.Error(t, err, "captured %s", amount)
+	}
+	assert.Empty(t, env.card.captures)
+	assert.Equal(t, models.PaymentStatusAuthorized, env.storedPayment(t, payment).Status)
+
+	// The whole authorization is captured without an amount
+	captured, err := env.payments.CapturePayment(ctx, payment.ID, nil)
+	require.NoError(t, err)
+	assert.True(t, captured.Amount.Equal(decimal.NewFromInt(100)))
+}
+
+func TestCapture_AfterExpiry(t *testing.T) {
+	env := newTestEnv(t)
+	payment := env.authorize(t, "100.00")
+	require.NoError(t, env.db.Model(&models.Payment{}).Where("id = ?", payment.ID).
+		Update("authorization_expires_at", time.Now().Add(-time.Minute)).Error)
+
+	_, err := env.payments.CapturePayment(context.Background(), payment.ID, nil)
+	assert.ErrorIs(t, err, ErrAuthorizationExpired)
+	assert.Empty(t, env.card.captures)
+	assert.Equal(t, models.PaymentStatusAuthorized, env.storedPayment(t, payment).Status)
+}
+
+func TestCapture_VoidOnlyAuthorized(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	authorized := env.authorize(t, "100.00")
+	intent, err := env.payments.CancelPaymentIntent(ctx, authorized.PaymentIntentID, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentIntentStatusCanceled, intent.Status)
+	require.Len(t, env.card.voids, 1)
+	assert.Equal(t, authorized.RailTransactionID, env.card.voids[0].TransactionID)
+	voided := env.storedPayment(t, authorized)
+	assert.Equal(t, models.PaymentStatusCanceled, voided.Status)
+	require.NotNil(t, voided.FailureCode)
+	assert.Equal(t, "CANCELED", *voided.FailureCode)
+	assert.Zero(t, env.postedPayments(t, authorized))
+
+	captured := env.authorize(t, "50.00")
+	_, err = env.payments.CapturePayment(ctx, captured.ID, nil)
+	require.NoError(t, err)
+	_, err = env.payments.voidAuthorization(ctx, captured.ID, "CANCELED", "requested by merchant")
+	assert.ErrorIs(t, err, ErrPaymentNotCapturable)
+	_, err = env.payments.CancelPaymentIntent(ctx, captured.PaymentIntentID, "")
+	assert.Error(t, err, "captured payment's intent canceled")
+	assert.Len(t, env.card.voids, 1)
+	assert.Equal(t, models.PaymentStatusSucceeded, env.storedPayment(t, captured).Status)
+}
+
+func TestCapture_ExpirySweep(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	expired := env.authorize(t, "100.00")
+	current := env.authorize(t, "100.00")
+	require.NoError(t, env.db.Model(&models.Payment{}).Where("id = ?", expired.ID).
+		Update("authorization_expires_at", time.Now().Add(-time.Minute)).Error)
+
+	require.NoError(t, env.payments.expireAuthorizations(ctx))
+
+	voided := env.storedPayment(t, expired)
+	assert.Equal(t, models.PaymentStatusCanceled, voided.Status)
+	require.NotNil(t, voided.FailureCode)
+	assert.Equal(t, "AUTHORIZATION_EXPIRED", *voided.FailureCode)
+	assert.Equal(t, models.PaymentIntentStatusCanceled, env.intentStatus(t, expired))
+	require.Len(t, env.card.voids, 1)
+	assert.Equal(t, expired.RailTransactionID, env.card.voids[0].TransactionID)
+
+	assert.Equal(t, models.PaymentStatusAuthorized, env.storedPayment(t, current).Status)
+
+	// Swept authorizations are not voided again
+	require.NoError(t, env.payments.expireAuthorizations(ctx))
+	assert.Len(t, env.card.voids, 1)
+}
@@ -5,114 +5,234 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/suuupra/payments/internal/models"
 )
 
-// IdempotencyService handles idempotency key management
+// Idempotency key states
+const (
+	IdempotencyInProgress = "IN_PROGRESS"
+	IdempotencyCompleted  = "COMPLETED"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a
+	// different request body
+	ErrIdempotencyKeyReused = errors.New("idempotency key exists but request body differs")
+	// ErrIdempotencyKeyInProgress is returned when the request that claimed
+	// a key did not finish within the wait timeout
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+// How often a duplicate polls the key of the request it waits for
+const (
+	idempotencyMinPoll = 25 * time.Millisecond
+	idempotencyMaxPoll = 500 * time.Millisecond
+)
+
+// IdempotencyService handles idempotency key management. Keys are claimed
+// atomically in the database, so only one of any number of concurrent
+// requests with a key is processed, across all instances of the service.
 type IdempotencyService struct {
-	db     *gorm.DB
-	logger *logrus.Logger
-	ttl    time.Duration
+	db          *gorm.DB
+	logger      *logrus.Logger
+	ttl         time.Duration
+	lockTimeout time.Duration
+	waitTimeout time.Duration
 }
 
-// NewIdempotencyService creates a new idempotency service
-func NewIdempotencyService(db *gorm.DB, logger *logrus.Logger, ttlHours int) *IdempotencyService {
+// NewIdempotencyService creates a new idempotency service. A claimed key is
+// taken over once lockTimeoutSeconds pass without its request finishing;
+// duplicates wait up to waitTimeoutSeconds for it to finish.
+func NewIdempotencyService(db *gorm.DB, logger *logrus.Logger, ttlHours, lockTimeoutSeconds, waitTimeoutSeconds int) *IdempotencyService {
 	return &IdempotencyService{
-		db:     db,
-		logger: logger,
-		ttl:    time.Duration(ttlHours) * time.Hour,
+		db:          db,
+		logger:      logger,
+		ttl:         time.Duration(ttlHours) * time.Hour,
+		lockTimeout: time.Duration(lockTimeoutSeconds) * time.Second,
+		waitTimeout: time.Duration(waitTimeoutSeconds) * time.Second,
 	}
 }
 
-// IdempotencyResult represents the result of an idempotency check
-type IdempotencyResult struct {
-	Found        bool
-	ResponseData []byte
-	StatusCode   int
+// StoredResponse is the response replayed to repeats of a request
+type StoredResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
 }
 
-// CheckIdempotency checks if an idempotency key exists and is valid
-func (s *IdempotencyService) CheckIdempotency(ctx context.Context, key string, requestBody []byte) (*IdempotencyResult, error) {
-	log := s.logger.WithFields(logrus.Fields{
-		"idempotency_key": key,
-		"request_hash":    s.generateRequestHash(requestBody),
-	})
+// Begin claims key for a request with requestBody. It returns nil once the
+// key is claimed: the caller processes the request and then completes or
+// releases the key. If the key was already used for the request, Begin
+// returns its stored response; while the request holding the key is still
+// being processed, Begin waits for it to finish.
+func (s *IdempotencyService) Begin(ctx context.Context, key string, requestBody []byte) (*StoredResponse, error) {
+	requestHash := s.generateRequestHash(requestBody)
+	deadline := time.Now().Add(s.waitTimeout)
+	poll := idempotencyMinPoll
+
+	for {
+		stored, claimed, err := s.claim(ctx, key, requestHash)
+		if err != nil {
+			return nil, err
+		}
+		if claimed || stored != nil {
+			return stored, nil
+		}
 
-	// Clean up expired keys first
-	if err := s.cleanupExpiredKeys(ctx); err != nil {
-		log.WithError(err).Warn("Failed to cleanup expired idempotency keys")
+		if time.Now().Add(poll).After(deadline) {
+			s.logger.WithField("idempotency_key", key).Warn("Idempotency key still in progress, giving up waiting")
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(poll):
+		}
+		if poll *= 2; poll > idempotencyMaxPoll {
+			poll = idempotencyMaxPoll
+		}
 	}
+}
 
-	var idempotencyKey models.IdempotencyKey
-	err := s.db.WithContext(ctx).
-		Where("key = ? AND expires_at > ?", key, time.Now()).
-		First(&idempotencyKey).Error
+// claim inserts key as IN_PROGRESS unless it exists. It reports whether the
+// key was claimed, or else the stored response of a completed key. On
+// Postgres the claim holds a transaction-scoped advisory lock on the key,
+// so taking over an expired key is not raced by another instance.
+func (s *IdempotencyService) claim(ctx context.Context, key, requestHash string) (*StoredResponse, bool, error) {
+	var existing models.IdempotencyKey
+	claimed := false
 
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			log.Debug("Idempotency key not found or expired")
-			return &IdempotencyResult{Found: false}, nil
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", key).Error; err != nil {
+				return fmt.Errorf("failed to lock idempotency key: %w", err)
+			}
+		}
+
+		// Free the key if its response expired or its request died
+		now := time.Now()
+		if err := tx.Where("key = ? AND (expires_at < ? OR (state = ? AND locked_until < ?))", key, now, IdempotencyInProgress, now).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return fmt.Errorf("failed to free idempotency key: %w", err)
+		}
+
+		lockedUntil := now.Add(s.lockTimeout)
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoNothing: true,
+		}).Create(&models.IdempotencyKey{
+			ID:          uuid.New(),
+			Key:         key,
+			RequestHash: requestHash,
+			State:       IdempotencyInProgress,
+			LockedUntil: &lockedUntil,
+			ExpiresAt:   now.Add(s.ttl),
+			CreatedAt:   now,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to claim idempotency key: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			claimed = true
+			return nil
 		}
-		log.WithError(err).Error("Failed to check idempotency key")
-		return nil, fmt.Errorf("failed to check idempotency key: %w", err)
+
+		return tx.Where("key = ?", key).First(&existing).Error
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("idempotency_key", key).Error("Failed to claim idempotency key")
+		return nil, false, err
+	}
+	if claimed {
+		return nil, true, nil
 	}
 
-	// Verify request hash matches
-	requestHash := s.generateRequestHash(requestBody)
-	if idempotencyKey.RequestHash != requestHash {
-		log.WithFields(logrus.Fields{
-			"stored_hash":  idempotencyKey.RequestHash,
-			"request_hash": requestHash,
+	if existing.RequestHash != requestHash {
+		s.logger.WithFields(logrus.Fields{
+			"idempotency_key": key,
+			"stored_hash":     existing.RequestHash,
+			"request_hash":    requestHash,
 		}).Error("Idempotency key found but request hash mismatch")
-		return nil, fmt.Errorf("idempotency key exists but request body differs")
+		return nil, false, ErrIdempotencyKeyReused
+	}
+	if existing.State != IdempotencyCompleted {
+		return nil, false, nil
 	}
 
-	log.WithField("status_code", idempotencyKey.StatusCode).Info("Idempotency key found, returning cached response")
-	return &IdempotencyResult{
-		Found:        true,
-		ResponseData: idempotencyKey.ResponseData,
-		StatusCode:   idempotencyKey.StatusCode,
-	}, nil
+	stored := &StoredResponse{StatusCode: existing.StatusCode, Body: existing.ResponseData, Header: http.Header{}}
+	if len(existing.ResponseHeaders) > 0 {
+		if err := json.Unmarshal(existing.ResponseHeaders, &stored.Header); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal stored response headers: %w", err)
+		}
+	}
+	s.logger.WithFields(logrus.Fields{
+		"idempotency_key": key,
+		"status_code":     stored.StatusCode,
+	}).Info("Idempotency key found, returning stored response")
+	return stored, false, nil
 }
 
-// StoreIdempotencyResult stores the result of an operation with an idempotency key
-func (s *IdempotencyService) StoreIdempotencyResult(ctx context.Context, key string, requestBody []byte, responseData []byte, statusCode int) error {
+// Complete stores the response to the request that claimed key, to be
+// replayed to its repeats until the key expires
+func (s *IdempotencyService) Complete(ctx context.Context, key string, response *StoredResponse) error {
 	log := s.logger.WithFields(logrus.Fields{
 		"idempotency_key": key,
-		"status_code":     statusCode,
-		"response_size":   len(responseData),
+		"status_code":     response.StatusCode,
+		"response_size":   len(response.Body),
 	})
 
-	requestHash := s.generateRequestHash(requestBody)
-	expiresAt := time.Now().Add(s.ttl)
-
-	idempotencyKey := &models.IdempotencyKey{
-		ID:           uuid.New(),
-		Key:          key,
-		RequestHash:  requestHash,
-		ResponseData: responseData,
-		StatusCode:   statusCode,
-		ExpiresAt:    expiresAt,
-		CreatedAt:    time.Now(),
+	header, err := json.Marshal(response.Header)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response headers: %w", err)
 	}
 
-	err := s.db.WithContext(ctx).Create(idempotencyKey).Error
-	if err != nil {
-		log.WithError(err).Error("Failed to store idempotency result")
-		return fmt.Errorf("failed to store idempotency result: %w", err)
+	expiresAt := time.Now().Add(s.ttl)
+	result := s.db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Where("key = ? AND state = ?", key, IdempotencyInProgress).
+		Updates(map[string]interface{}{
+			"state":            IdempotencyCompleted,
+			"response_data":    response.Body,
+			"response_headers": header,
+			"status_code":      response.StatusCode,
+			"locked_until":     nil,
+			"expires_at":       expiresAt,
+		})
+	if result.Error != nil {
+		log.WithError(result.Error).Error("Failed to store idempotency result")
+		return fmt.Errorf("failed to store idempotency result: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		log.Warn("Idempotency key was taken over before its request finished")
+		return nil
 	}
 
 	log.WithField("expires_at", expiresAt).Info("Idempotency result stored successfully")
 	return nil
 }
 
+// Release gives up the claim on key without storing a response, letting
+// the request be retried with the same key
+func (s *IdempotencyService) Release(ctx context.Context, key string) error {
+	err := s.db.WithContext(ctx).
+		Where("key = ? AND state = ?", key, IdempotencyInProgress).
+		Delete(&models.IdempotencyKey{}).Error
+	if err != nil {
+		s.logger.WithError(err).WithField("idempotency_key", key).Error("Failed to release idempotency key")
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
 // generateRequestHash generates a SHA256 hash of the request body
 func (s *IdempotencyService) generateRequestHash(requestBody []byte) string {
 	hash := sha256.Sum256(requestBody)
@@ -155,36 +275,3 @@ func (s *IdempotencyService) StartCleanupTask(ctx context.Context) {
 		}
 	}
 }
-
-// ResponseCacheEntry represents a cached response for idempotency
-type ResponseCacheEntry struct {
-	StatusCode int         `json:"status_code"`
-	Data       interface{} `json:"data"`
-	Error      *string     `json:"error,omitempty"`
-}
-
-// MarshalResponse marshals a response for caching
-func (s *IdempotencyService) MarshalResponse(statusCode int, data interface{}, err error) ([]byte, error) {
-	entry := ResponseCacheEntry{
-		StatusCode: statusCode,
-		Data:       data,
-	}
-
-	if err != nil {
-		errStr := err.Error()
-		entry.Error = &errStr
-	}
-
-	return json.Marshal(entry)
-}
-
-// UnmarshalResponse unmarshals a cached response
-func (s *IdempotencyService) UnmarshalResponse(responseData []byte) (*ResponseCacheEntry, error) {
-	var entry ResponseCacheEntry
-	err := json.Unmarshal(responseData, &entry)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal cached response: %w", err)
-	}
-
-	return &entry, nil
-}
\ No newline at end of file
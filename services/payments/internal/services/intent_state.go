@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+var (
+	// ErrInvalidIntentTransition is returned for a transition the payment
+	// intent state machine does not allow from the intent's status
+	ErrInvalidIntentTransition = errors.New("invalid payment intent transition")
+	// ErrPaymentIntentConflict is returned when the intent was changed by
+	// another request since it was read
+	ErrPaymentIntentConflict = errors.New("payment intent was modified concurrently")
+)
+
+// paymentIntentTransitions lists the statuses each payment intent status
+// can move to. Succeeded, failed and canceled intents are final.
+var paymentIntentTransitions = map[string][]string{
+	models.PaymentIntentStatusRequiresPaymentMethod: {
+		models.PaymentIntentStatusRequiresConfirmation,
+		models.PaymentIntentStatusCanceled,
+	},
+	models.PaymentIntentStatusRequiresConfirmation: {
+		models.PaymentIntentStatusProcessing,
+		models.PaymentIntentStatusCanceled,
+	},
+	models.PaymentIntentStatusProcessing: {
+		models.PaymentIntentStatusRequiresAction,
+		models.PaymentIntentStatusRequiresCapture,
+		models.PaymentIntentStatusSucceeded,
+		models.PaymentIntentStatusFailed,
+	},
+	models.PaymentIntentStatusRequiresAction: {
+		models.PaymentIntentStatusProcessing,
+		models.PaymentIntentStatusSucceeded,
+		models.PaymentIntentStatusFailed,
+	},
+	models.PaymentIntentStatusRequiresCapture: {
+		models.PaymentIntentStatusSucceeded,
+		models.PaymentIntentStatusCanceled, // Voided
+	},
+}
+
+// CanTransitionPaymentIntent reports whether an intent in status from can
+// move to status to
+func CanTransitionPaymentIntent(from, to string) bool {
+	for _, allowed := range paymentIntentTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentIntentEvent is the data of the payment_intent.<status> webhook
+// sent on every transition
+type PaymentIntentEvent struct {
+	PaymentIntent  models.PaymentIntent `json:"payment_intent"`
+	PreviousStatus string               `json:"previous_status"`
+	Reason         string               `json:"reason,omitempty"`
+}
+
+// transitionIntent moves intent to status to within tx, recording the
+// transition. The update is conditional on the version the intent was read
+// at, so of two concurrent transitions only one succeeds.
+func (s *PaymentService) transitionIntent(tx *gorm.DB, intent *models.PaymentIntent, to, reason string) (*PaymentIntentEvent, error) {
+	if !CanTransitionPaymentIntent(intent.Status, to) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidIntentTransition, intent.Status, to)
+	}
+
+	now := time.Now()
+	result := tx.Model(&models.PaymentIntent{}).
+		Where("id = ? AND version = ?", intent.ID, intent.Version).
+		Updates(map[string]interface{}{
+			"status":         to,
+			"payment_method": intent.PaymentMethod,
+			"version":        intent.Version + 1,
+			"updated_at":     now,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update payment intent status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrPaymentIntentConflict
+	}
+
+	transition := &models.PaymentIntentTransition{
+		ID:              uuid.New(),
+		PaymentIntentID: intent.ID,
+		FromStatus:      intent.Status,
+		ToStatus:        to,
+		Reason:          reason,
+		Version:         intent.Version + 1,
+		CreatedAt:       now,
+	}
+	if err := tx.Create(transition).Error; err != nil {
+		return nil, fmt.Errorf("failed to record payment intent transition: %w", err)
+	}
+
+	event := &PaymentIntentEvent{PreviousStatus: intent.Status, Reason: reason}
+	intent.Status = to
+	intent.Version++
+	intent.UpdatedAt = now
+	event.PaymentIntent = *intent
+
+	s.logger.WithFields(logrus.Fields{
+		"intent_id": intent.ID,
+		"from":      event.PreviousStatus,
+		"to":        to,
+		"version":   intent.Version,
+	}).Info("Payment intent transitioned")
+	return event, nil
+}
+
+// TransitionPaymentIntent moves an intent to status to in a transaction of
+// its own and sends the transition's webhook
+func (s *PaymentService) TransitionPaymentIntent(ctx context.Context, intent *models.PaymentIntent, to, reason string) error {
+	var event *PaymentIntentEvent
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		event, err = s.transitionIntent(tx, intent, to, reason)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.emitPaymentIntentEvents(intent.MerchantID, event)
+	return nil
+}
+
+// emitPaymentIntentEvents sends the webhooks of committed transitions
+func (s *PaymentService) emitPaymentIntentEvents(merchantID uuid.UUID, events ...*PaymentIntentEvent) {
+	go func() {
+		for _, event := range events {
+			if event == nil {
+				continue
+			}
+			s.webhookService.TriggerWebhook(context.Background(), merchantID, "payment_intent."+event.PaymentIntent.Status, event)
+		}
+	}()
+}
+
+// AttachPaymentMethod sets the payment method of an intent awaiting one,
+// making it ready for confirmation
+func (s *PaymentService) AttachPaymentMethod(ctx context.Context, id uuid.UUID, paymentMethod string) (*models.PaymentIntent, error) {
+	intent, err := s.GetPaymentIntent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	intent.PaymentMethod = paymentMethod
+	if err := s.TransitionPaymentIntent(ctx, intent, models.PaymentIntentStatusRequiresConfirmation, "payment method attached"); err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// CancelPaymentIntent cancels an intent that is not yet being processed,
+// or voids the authorization of one awaiting capture
+func (s *PaymentService) CancelPaymentIntent(ctx context.Context, id uuid.UUID, reason string) (*models.PaymentIntent, error) {
+	intent, err := s.GetPaymentIntent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		reason = "requested by merchant"
+	}
+	if intent.Status == models.PaymentIntentStatusRequiresCapture {
+		if err := s.voidIntentAuthorization(ctx, intent, reason); err != nil {
+			return nil, err
+		}
+		return s.GetPaymentIntent(ctx, id)
+	}
+	if err := s.TransitionPaymentIntent(ctx, intent, models.PaymentIntentStatusCanceled, reason); err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// GetPaymentIntentTransitions returns the status history of an intent,
+// oldest first
+func (s *PaymentService) GetPaymentIntentTransitions(ctx context.Context, id uuid.UUID) ([]models.PaymentIntentTransition, error) {
+	var transitions []models.PaymentIntentTransition
+	err := s.db.WithContext(ctx).
+		Where("payment_intent_id = ?", id).
+		Order("version ASC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment intent transitions: %w", err)
+	}
+	return transitions, nil
+}
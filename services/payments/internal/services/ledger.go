@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,8 +10,10 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/money"
 )
 
 // AccountType represents different types of accounts in the ledger
@@ -24,40 +27,71 @@ const (
 	AccountTypeEquity    AccountType = "EQUITY"
 )
 
-// LedgerService handles double-entry accounting
+// Platform ledger accounts. Money received over the rails sits in the
+// clearing account until paid out; merchant accounts are what the platform
+// owes each merchant.
+const (
+	LedgerAccountClearing = "platform:clearing"
+	LedgerAccountFees     = "platform:fees"
+)
+
+var (
+	// ErrLedgerAccountNotFound is returned for an account the ledger does
+	// not have
+	ErrLedgerAccountNotFound = errors.New("ledger account not found")
+	// ErrInsufficientBalance is returned for a payout above the merchant's
+	// balance
+	ErrInsufficientBalance = errors.New("insufficient ledger balance")
+)
+
+// MerchantLedgerAccount returns the code of a merchant's ledger account
+func MerchantLedgerAccount(merchantID uuid.UUID) string {
+	return "merchant:" + merchantID.String()
+}
+
+// LedgerService handles double-entry accounting. Every capture, fee,
+// refund and payout is a journal entry of balanced postings, written in the
+// database transaction of the change it records.
 type LedgerService struct {
 	db     *gorm.DB
 	logger *logrus.Logger
+	feeBPS int64
 }
 
-// NewLedgerService creates a new ledger service
-func NewLedgerService(db *gorm.DB, logger *logrus.Logger) *LedgerService {
+// NewLedgerService creates a new ledger service charging merchants feeBPS
+// basis points of each payment
+func NewLedgerService(db *gorm.DB, logger *logrus.Logger, feeBPS int) *LedgerService {
 	return &LedgerService{
 		db:     db,
 		logger: logger,
+		feeBPS: int64(feeBPS),
 	}
 }
 
-// LedgerTransaction represents a complete double-entry transaction
+// LedgerTransaction represents a complete double-entry transaction: a
+// journal entry recording the event it references. An event is posted
+// once; posting it again is a no-op.
 type LedgerTransaction struct {
-	ID          uuid.UUID
-	Description string
-	Entries     []LedgerEntryInput
+	ID            uuid.UUID
+	Description   string
+	ReferenceType string
+	ReferenceID   uuid.UUID
+	Entries       []LedgerEntryInput
 }
 
-// LedgerEntryInput represents input for creating a ledger entry
+// LedgerEntryInput represents input for creating a ledger entry: a posting
+// to the account with AccountCode, opened for MerchantID if it is new
 type LedgerEntryInput struct {
-	AccountID     uuid.UUID
-	AccountType   AccountType
-	DebitAmount   decimal.Decimal
-	CreditAmount  decimal.Decimal
-	Currency      string
-	ReferenceType string
-	ReferenceID   uuid.UUID
+	AccountCode  string
+	AccountType  AccountType
+	MerchantID   *uuid.UUID
+	DebitAmount  decimal.Decimal
+	CreditAmount decimal.Decimal
+	Currency     string
 }
 
-// PostTransaction posts a double-entry transaction to the ledger
-func (s *LedgerService) PostTransaction(ctx context.Context, transaction LedgerTransaction) error {
+// PostTransaction posts a double-entry transaction to the ledger within tx
+func (s *LedgerService) PostTransaction(ctx context.Context, tx *gorm.DB, transaction LedgerTransaction) error {
 	log := s.logger.WithFields(logrus.Fields{
 		"transaction_id": transaction.ID,
 		"description":    transaction.Description,
@@ -70,33 +104,99 @@ func (s *LedgerService) PostTransaction(ctx context.Context, transaction LedgerT
 		return fmt.Errorf("transaction validation failed: %w", err)
 	}
 
-	// Start database transaction
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Create ledger entries
-		for _, entryInput := range transaction.Entries {
-			entry := &models.LedgerEntry{
-				ID:            uuid.New(),
-				TransactionID: transaction.ID,
-				AccountID:     entryInput.AccountID,
-				AccountType:   string(entryInput.AccountType),
-				DebitAmount:   entryInput.DebitAmount,
-				CreditAmount:  entryInput.CreditAmount,
-				Currency:      entryInput.Currency,
-				Description:   transaction.Description,
-				ReferenceType: entryInput.ReferenceType,
-				ReferenceID:   entryInput.ReferenceID,
-				CreatedAt:     time.Now(),
-			}
-
-			if err := tx.Create(entry).Error; err != nil {
-				log.WithError(err).Error("Failed to create ledger entry")
-				return fmt.Errorf("failed to create ledger entry: %w", err)
-			}
+	now := time.Now()
+	journal := &models.JournalEntry{
+		ID:            transaction.ID,
+		Description:   transaction.Description,
+		ReferenceType: transaction.ReferenceType,
+		ReferenceID:   transaction.ReferenceID,
+		CreatedAt:     now,
+	}
+	result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(journal)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create journal entry: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		log.WithFields(logrus.Fields{
+			"reference_type": transaction.ReferenceType,
+			"reference_id":   transaction.ReferenceID,
+		}).Info("Journal entry already posted")
+		return nil
+	}
+
+	// Create ledger entries
+	for _, entryInput := range transaction.Entries {
+		account, err := s.account(tx.WithContext(ctx), entryInput.AccountCode, entryInput.AccountType, entryInput.MerchantID, entryInput.Currency)
+		if err != nil {
+			return err
 		}
 
-		log.Info("Double-entry transaction posted successfully")
-		return nil
-	})
+		entry := &models.LedgerEntry{
+			ID:            uuid.New(),
+			TransactionID: transaction.ID,
+			AccountID:     account.ID,
+			AccountType:   account.Type,
+			DebitAmount:   entryInput.DebitAmount,
+			CreditAmount:  entryInput.CreditAmount,
+			Currency:      entryInput.Currency,
+			Description:   transaction.Description,
+			ReferenceType: transaction.ReferenceType,
+			ReferenceID:   transaction.ReferenceID,
+			CreatedAt:     now,
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			log.WithError(err).Error("Failed to create ledger entry")
+			return fmt.Errorf("failed to create ledger entry: %w", err)
+		}
+
+		err = tx.Model(&models.LedgerAccount{}).
+			Where("id = ?", account.ID).
+			Updates(map[string]interface{}{
+				"balance":    gorm.Expr("balance + ?", signedAmount(account.Type, entry.DebitAmount, entry.CreditAmount)),
+				"updated_at": now,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update ledger account balance: %w", err)
+		}
+	}
+
+	log.Info("Double-entry transaction posted successfully")
+	return nil
+}
+
+// account returns the ledger account with code in currency, opening it if
+// it does not exist yet
+func (s *LedgerService) account(tx *gorm.DB, code string, accountType AccountType, merchantID *uuid.UUID, currency string) (*models.LedgerAccount, error) {
+	opened := &models.LedgerAccount{
+		ID:         uuid.New(),
+		Code:       code,
+		Type:       string(accountType),
+		MerchantID: merchantID,
+		Currency:   currency,
+		Balance:    decimal.Zero,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(opened).Error; err != nil {
+		return nil, fmt.Errorf("failed to open ledger account %s: %w", code, err)
+	}
+	var account models.LedgerAccount
+	if err := tx.Where("code = ? AND currency = ?", code, currency).First(&account).Error; err != nil {
+		return nil, fmt.Errorf("failed to get ledger account %s: %w", code, err)
+	}
+	return &account, nil
+}
+
+// signedAmount is the change a posting makes to the balance of an account
+// of accountType. For asset and expense accounts debits increase the
+// balance; for liability, equity and revenue accounts credits do.
+func signedAmount(accountType string, debit, credit decimal.Decimal) decimal.Decimal {
+	switch accountType {
+	case string(AccountTypeAsset), string(AccountTypeExpense):
+		return debit.Sub(credit)
+	default:
+		return credit.Sub(debit)
+	}
 }
 
 // validateTransaction validates that the transaction follows double-entry rules
@@ -104,11 +204,18 @@ func (s *LedgerService) validateTransaction(transaction LedgerTransaction) error
 	if len(transaction.Entries) < 2 {
 		return fmt.Errorf("transaction must have at least 2 entries")
 	}
+	if transaction.ReferenceType == "" || transaction.ReferenceID == uuid.Nil {
+		return fmt.Errorf("transaction must reference the event it records")
+	}
 
 	// Group by currency and validate balance
 	currencyTotals := make(map[string]decimal.Decimal)
 
 	for _, entry := range transaction.Entries {
+		if entry.AccountCode == "" {
+			return fmt.Errorf("entry must have an account")
+		}
+
 		// Validate that entry has either debit OR credit, not both
 		if (entry.DebitAmount.IsZero() && entry.CreditAmount.IsZero()) ||
 			(!entry.DebitAmount.IsZero() && !entry.CreditAmount.IsZero()) {
@@ -141,114 +248,280 @@ func (s *LedgerService) validateTransaction(transaction LedgerTransaction) error
 	return nil
 }
 
-// PostPaymentTransaction posts ledger entries for a successful payment
-func (s *LedgerService) PostPaymentTransaction(ctx context.Context, payment *models.Payment) error {
-	customerAccountID := uuid.New() // In practice, this would be retrieved
-	merchantAccountID := uuid.New() // In practice, this would be retrieved
-	platformAccountID := uuid.New() // Platform's revenue account
-
-	// Calculate platform fee (example: 2%)
-	feeRate := decimal.NewFromFloat(0.02)
-	feeAmount := payment.Amount.Mul(feeRate)
-	merchantAmount := payment.Amount.Sub(feeAmount)
+// PaymentFee returns the platform's fee on a payment of amount, rounded to
+// the minor unit of its currency
+func (s *LedgerService) PaymentFee(amount decimal.Decimal, currency string) decimal.Decimal {
+	return money.Round(amount.Mul(decimal.New(s.feeBPS, -4)), currency)
+}
 
-	transaction := LedgerTransaction{
-		ID:          uuid.New(),
-		Description: fmt.Sprintf("Payment for %s", payment.ID),
+// PostPaymentTransaction posts a captured payment within tx: the amount
+// received into clearing and owed to the merchant, and the platform's fee
+// charged to the merchant
+func (s *LedgerService) PostPaymentTransaction(ctx context.Context, tx *gorm.DB, payment *models.Payment, merchantID uuid.UUID) error {
+	merchantAccount := MerchantLedgerAccount(merchantID)
+
+	err := s.PostTransaction(ctx, tx, LedgerTransaction{
+		ID:            uuid.New(),
+		Description:   fmt.Sprintf("Payment %s", payment.ID),
+		ReferenceType: "payment",
+		ReferenceID:   payment.ID,
 		Entries: []LedgerEntryInput{
-			// Debit customer's account (asset decrease)
-			{
-				AccountID:     customerAccountID,
-				AccountType:   AccountTypeAsset,
-				DebitAmount:   payment.Amount,
-				CreditAmount:  decimal.Zero,
-				Currency:      payment.Currency,
-				ReferenceType: "payment",
-				ReferenceID:   payment.ID,
-			},
-			// Credit merchant's account (asset increase)
+			// Debit clearing (funds received over the rail)
 			{
-				AccountID:     merchantAccountID,
-				AccountType:   AccountTypeAsset,
-				DebitAmount:   decimal.Zero,
-				CreditAmount:  merchantAmount,
-				Currency:      payment.Currency,
-				ReferenceType: "payment",
-				ReferenceID:   payment.ID,
+				AccountCode: LedgerAccountClearing,
+				AccountType: AccountTypeAsset,
+				DebitAmount: payment.Amount,
+				Currency:    payment.Currency,
 			},
-			// Credit platform revenue (revenue increase)
+			// Credit the merchant (owed to them)
 			{
-				AccountID:     platformAccountID,
-				AccountType:   AccountTypeRevenue,
-				DebitAmount:   decimal.Zero,
-				CreditAmount:  feeAmount,
-				Currency:      payment.Currency,
-				ReferenceType: "payment_fee",
-				ReferenceID:   payment.ID,
+				AccountCode:  merchantAccount,
+				AccountType:  AccountTypeLiability,
+				MerchantID:   &merchantID,
+				CreditAmount: payment.Amount,
+				Currency:     payment.Currency,
 			},
 		},
+	})
+	if err != nil {
+		return err
 	}
 
-	return s.PostTransaction(ctx, transaction)
+	fee := s.PaymentFee(payment.Amount, payment.Currency)
+	if !fee.IsPositive() {
+		return nil
+	}
+	return s.PostTransaction(ctx, tx, LedgerTransaction{
+		ID:            uuid.New(),
+		Description:   fmt.Sprintf("Fee on payment %s", payment.ID),
+		ReferenceType: "payment_fee",
+		ReferenceID:   payment.ID,
+		Entries: []LedgerEntryInput{
+			// Debit the merchant
+			{
+				AccountCode: merchantAccount,
+				AccountType: AccountTypeLiability,
+				MerchantID:  &merchantID,
+				DebitAmount: fee,
+				Currency:    payment.Currency,
+			},
+			// Credit platform revenue
+			{
+				AccountCode:  LedgerAccountFees,
+				AccountType:  AccountTypeRevenue,
+				CreditAmount: fee,
+				Currency:     payment.Currency,
+			},
+		},
+	})
 }
 
-// PostRefundTransaction posts ledger entries for a refund
-func (s *LedgerService) PostRefundTransaction(ctx context.Context, refund *models.Refund, payment *models.Payment) error {
-	customerAccountID := uuid.New() // In practice, this would be retrieved
-	merchantAccountID := uuid.New() // In practice, this would be retrieved
-
-	transaction := LedgerTransaction{
-		ID:          uuid.New(),
-		Description: fmt.Sprintf("Refund for payment %s", payment.ID),
+// PostRefundTransaction posts a succeeded refund within tx: the amount
+// returned from clearing, charged to the merchant. The payment's fee is
+// not refunded.
+func (s *LedgerService) PostRefundTransaction(ctx context.Context, tx *gorm.DB, refund *models.Refund, merchantID uuid.UUID) error {
+	return s.PostTransaction(ctx, tx, LedgerTransaction{
+		ID:            uuid.New(),
+		Description:   fmt.Sprintf("Refund %s of payment %s", refund.ID, refund.PaymentID),
+		ReferenceType: "refund",
+		ReferenceID:   refund.ID,
 		Entries: []LedgerEntryInput{
-			// Credit customer's account (asset increase)
+			// Debit the merchant
 			{
-				AccountID:     customerAccountID,
-				AccountType:   AccountTypeAsset,
-				DebitAmount:   decimal.Zero,
-				CreditAmount:  refund.Amount,
-				Currency:      refund.Currency,
-				ReferenceType: "refund",
-				ReferenceID:   refund.ID,
+				AccountCode: MerchantLedgerAccount(merchantID),
+				AccountType: AccountTypeLiability,
+				MerchantID:  &merchantID,
+				DebitAmount: refund.Amount,
+				Currency:    refund.Currency,
 			},
-			// Debit merchant's account (asset decrease)
+			// Credit clearing (funds returned over the rail)
 			{
-				AccountID:     merchantAccountID,
-				AccountType:   AccountTypeAsset,
-				DebitAmount:   refund.Amount,
-				CreditAmount:  decimal.Zero,
-				Currency:      refund.Currency,
-				ReferenceType: "refund",
-				ReferenceID:   refund.ID,
+				AccountCode:  LedgerAccountClearing,
+				AccountType:  AccountTypeAsset,
+				CreditAmount: refund.Amount,
+				Currency:     refund.Currency,
 			},
 		},
+	})
+}
+
+// PayoutRequest is a payout of a merchant's balance to their bank account
+type PayoutRequest struct {
+	PayoutID   uuid.UUID       `json:"payout_id" binding:"required"` // Reposting an ID is a no-op
+	MerchantID uuid.UUID       `json:"merchant_id" binding:"required"`
+	Amount     decimal.Decimal `json:"amount" binding:"required"`
+	Currency   string          `json:"currency"`
+}
+
+// PostPayout posts a payout to a merchant, which may not exceed their
+// balance. The merchant's account is locked while its balance is checked.
+func (s *LedgerService) PostPayout(ctx context.Context, req PayoutRequest) error {
+	if req.Currency == "" {
+		req.Currency = money.INR
+	}
+	amount, err := ValidateAmount(req.Amount, req.Currency)
+	if err != nil {
+		return fmt.Errorf("payout %w", err)
 	}
+	req.Amount, req.Currency = amount.Decimal(), amount.Currency
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		merchantAccount := MerchantLedgerAccount(req.MerchantID)
+		query := tx
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		var account models.LedgerAccount
+		err := query.Where("code = ? AND currency = ?", merchantAccount, req.Currency).First(&account).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to get ledger account: %w", err)
+		}
 
-	return s.PostTransaction(ctx, transaction)
+		var posted int64
+		if err := tx.Model(&models.JournalEntry{}).Where("reference_type = ? AND reference_id = ?", "payout", req.PayoutID).Count(&posted).Error; err != nil {
+			return fmt.Errorf("failed to get journal entry: %w", err)
+		}
+		if posted > 0 {
+			return nil
+		}
+		if account.Balance.LessThan(req.Amount) {
+			return fmt.Errorf("%w: %s available", ErrInsufficientBalance, money.Format(account.Balance, req.Currency))
+		}
+
+		return s.PostTransaction(ctx, tx, LedgerTransaction{
+			ID:            uuid.New(),
+			Description:   fmt.Sprintf("Payout %s", req.PayoutID),
+			ReferenceType: "payout",
+			ReferenceID:   req.PayoutID,
+			Entries: []LedgerEntryInput{
+				// Debit the merchant
+				{
+					AccountCode: merchantAccount,
+					AccountType: AccountTypeLiability,
+					MerchantID:  &req.MerchantID,
+					DebitAmount: req.Amount,
+					Currency:    req.Currency,
+				},
+				// Credit clearing (funds paid out)
+				{
+					AccountCode:  LedgerAccountClearing,
+					AccountType:  AccountTypeAsset,
+					CreditAmount: req.Amount,
+					Currency:     req.Currency,
+				},
+			},
+		})
+	})
 }
 
-// GetAccountBalance calculates the balance for an account
+// GetAccount retrieves a ledger account by ID
+func (s *LedgerService) GetAccount(ctx context.Context, id uuid.UUID) (*models.LedgerAccount, error) {
+	var account models.LedgerAccount
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&account).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrLedgerAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get ledger account: %w", err)
+	}
+	// Merchants see their own accounts only, not the platform's
+	if merchantID, ok := MerchantFromContext(ctx); ok && (account.MerchantID == nil || *account.MerchantID != merchantID) {
+		return nil, ErrLedgerAccountNotFound
+	}
+	return &account, nil
+}
+
+// ListAccounts returns a merchant's ledger accounts, or the platform's for
+// a nil merchant
+func (s *LedgerService) ListAccounts(ctx context.Context, merchantID *uuid.UUID) ([]models.LedgerAccount, error) {
+	query := s.db.WithContext(ctx)
+	if merchantID != nil {
+		query = query.Where("merchant_id = ?", *merchantID)
+	} else {
+		query = query.Where("merchant_id IS NULL")
+	}
+
+	var accounts []models.LedgerAccount
+	if err := query.Order("code ASC, currency ASC").Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get ledger accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// StatementLine is a posting to an account with the balance after it
+type StatementLine struct {
+	models.LedgerEntry
+	Balance decimal.Decimal `json:"balance"`
+}
+
+// Statement is an account's postings over a period
+type Statement struct {
+	Account        models.LedgerAccount `json:"account"`
+	From           *time.Time           `json:"from,omitempty"`
+	To             *time.Time           `json:"to,omitempty"`
+	OpeningBalance decimal.Decimal      `json:"opening_balance"`
+	ClosingBalance decimal.Decimal      `json:"closing_balance"`
+	Lines          []StatementLine      `json:"lines"`
+}
+
+// GetStatement returns the postings to an account made in [from, to),
+// either bound optional, oldest first
+func (s *LedgerService) GetStatement(ctx context.Context, accountID uuid.UUID, from, to *time.Time) (*Statement, error) {
+	account, err := s.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &Statement{Account: *account, From: from, To: to, OpeningBalance: decimal.Zero}
+	if from != nil {
+		var before []models.LedgerEntry
+		err := s.db.WithContext(ctx).
+			Where("account_id = ? AND created_at < ?", accountID, *from).
+			Find(&before).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+		}
+		for _, entry := range before {
+			statement.OpeningBalance = statement.OpeningBalance.Add(signedAmount(account.Type, entry.DebitAmount, entry.CreditAmount))
+		}
+	}
+
+	query := s.db.WithContext(ctx).Where("account_id = ?", accountID)
+	if from != nil {
+		query = query.Where("created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("created_at < ?", *to)
+	}
+	var entries []models.LedgerEntry
+	if err := query.Order("created_at ASC, id ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+
+	balance := statement.OpeningBalance
+	statement.Lines = make([]StatementLine, 0, len(entries))
+	for _, entry := range entries {
+		balance = balance.Add(signedAmount(account.Type, entry.DebitAmount, entry.CreditAmount))
+		statement.Lines = append(statement.Lines, StatementLine{LedgerEntry: entry, Balance: balance})
+	}
+	statement.ClosingBalance = balance
+	return statement, nil
+}
+
+// GetAccountBalance calculates the balance for an account from its postings
 func (s *LedgerService) GetAccountBalance(ctx context.Context, accountID uuid.UUID, currency string) (decimal.Decimal, error) {
 	var entries []models.LedgerEntry
-	
+
 	err := s.db.WithContext(ctx).
 		Where("account_id = ? AND currency = ?", accountID, currency).
 		Find(&entries).Error
-	
+
 	if err != nil {
 		return decimal.Zero, fmt.Errorf("failed to fetch ledger entries: %w", err)
 	}
 
 	balance := decimal.Zero
 	for _, entry := range entries {
-		// For asset and expense accounts: debit increases balance
-		// For liability, equity, and revenue accounts: credit increases balance
-		switch entry.AccountType {
-		case string(AccountTypeAsset), string(AccountTypeExpense):
-			balance = balance.Add(entry.DebitAmount).Sub(entry.CreditAmount)
-		case string(AccountTypeLiability), string(AccountTypeEquity), string(AccountTypeRevenue):
-			balance = balance.Add(entry.CreditAmount).Sub(entry.DebitAmount)
-		}
+		balance = balance.Add(signedAmount(entry.AccountType, entry.DebitAmount, entry.CreditAmount))
 	}
 
 	return balance, nil
@@ -257,12 +530,12 @@ func (s *LedgerService) GetAccountBalance(ctx context.Context, accountID uuid.UU
 // GetTransactionEntries retrieves all entries for a transaction
 func (s *LedgerService) GetTransactionEntries(ctx context.Context, transactionID uuid.UUID) ([]models.LedgerEntry, error) {
 	var entries []models.LedgerEntry
-	
+
 	err := s.db.WithContext(ctx).
 		Where("transaction_id = ?", transactionID).
 		Order("created_at ASC").
 		Find(&entries).Error
-	
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch transaction entries: %w", err)
 	}
@@ -270,7 +543,8 @@ func (s *LedgerService) GetTransactionEntries(ctx context.Context, transactionID
 	return entries, nil
 }
 
-// ValidateLedgerIntegrity validates that all transactions in the ledger are balanced
+// ValidateLedgerIntegrity validates that all transactions in the ledger are
+// balanced, and that account balances match their postings
 func (s *LedgerService) ValidateLedgerIntegrity(ctx context.Context) error {
 	// Get all unique transaction IDs
 	var transactionIDs []uuid.UUID
@@ -278,7 +552,7 @@ func (s *LedgerService) ValidateLedgerIntegrity(ctx context.Context) error {
 		Model(&models.LedgerEntry{}).
 		Distinct("transaction_id").
 		Pluck("transaction_id", &transactionIDs).Error
-	
+
 	if err != nil {
 		return fmt.Errorf("failed to fetch transaction IDs: %w", err)
 	}
@@ -292,6 +566,20 @@ func (s *LedgerService) ValidateLedgerIntegrity(ctx context.Context) error {
 		}
 	}
 
+	var accounts []models.LedgerAccount
+	if err := s.db.WithContext(ctx).Find(&accounts).Error; err != nil {
+		return fmt.Errorf("failed to fetch ledger accounts: %w", err)
+	}
+	for _, account := range accounts {
+		balance, err := s.GetAccountBalance(ctx, account.ID, account.Currency)
+		if err != nil {
+			return err
+		}
+		if !balance.Equal(account.Balance) {
+			return fmt.Errorf("account %s balance %s does not match its postings: %s", account.Code, account.Balance.String(), balance.String())
+		}
+	}
+
 	s.logger.Info("Ledger integrity validation completed successfully")
 	return nil
 }
@@ -305,12 +593,12 @@ func (s *LedgerService) validateTransactionIntegrity(ctx context.Context, transa
 
 	// Group by currency and check balance
 	currencyTotals := make(map[string]decimal.Decimal)
-	
+
 	for _, entry := range entries {
 		if _, exists := currencyTotals[entry.Currency]; !exists {
 			currencyTotals[entry.Currency] = decimal.Zero
 		}
-		
+
 		currencyTotals[entry.Currency] = currencyTotals[entry.Currency].
 			Add(entry.DebitAmount).
 			Sub(entry.CreditAmount)
@@ -324,4 +612,4 @@ func (s *LedgerService) validateTransactionIntegrity(ctx context.Context, transa
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+func newLedgerTestService(t *testing.T) (*LedgerService, *gorm.DB) {
+	db := setupTestDB(t)
+	return NewLedgerService(db, testLogger(), 200), db
+}
+
+// ledgerBalance returns the balance of the account with code in INR
+func ledgerBalance(t *testing.T, db *gorm.DB, code string) decimal.Decimal {
+	var account models.LedgerAccount
+	require.NoError(t, db.Where("code = ? AND currency = ?", code, "INR").First(&account).Error)
+	return account.Balance
+}
+
+func capturedPayment(amount string) *models.Payment {
+	return &models.Payment{ID: uuid.New(), Amount: decimal.RequireFromString(amount), Currency: "INR"}
+}
+
+func TestLedger_PostingsBalance(t *testing.T) {
+	ledger, db := newLedgerTestService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	payment := capturedPayment("100.00")
+
+	require.NoError(t, ledger.PostPaymentTransaction(ctx, db, payment, merchantID))
+	require.NoError(t, ledger.PostRefundTransaction(ctx, db, &models.Refund{
+		ID: uuid.New(), PaymentID: payment.ID, Amount: decimal.RequireFromString("30.00"), Currency: "INR",
+	}, merchantID))
+
+	var entries []models.LedgerEntry
+	require.NoError(t, db.Find(&entries).Error)
+	require.Len(t, entries, 6, "payment, fee and refund are two postings each")
+	debits, credits := decimal.Zero, decimal.Zero
+	for _, entry := range entries {
+		debits, credits = debits.Add(entry.DebitAmount), credits.Add(entry.CreditAmount)
+	}
+	assert.True(t, debits.Equal(credits), "debits %s, credits %s", debits, credits)
+
+	// 100 received less 30 refunded, of which the merchant is owed what is
+	// left after the 2% fee
+	assert.True(t, ledgerBalance(t, db, LedgerAccountClearing).Equal(decimal.NewFromInt(70)))
+	assert.True(t, ledgerBalance(t, db, MerchantLedgerAccount(merchantID)).Equal(decimal.NewFromInt(68)))
+	assert.True(t, ledgerBalance(t, db, LedgerAccountFees).Equal(decimal.NewFromInt(2)))
+	assert.NoError(t, ledger.ValidateLedgerIntegrity(ctx))
+}
+
+func TestLedger_RejectsUnbalancedTransactions(t *testing.T) {
+	ledger, db := newLedgerTestService(t)
+	ten := decimal.NewFromInt(10)
+
+	for _, tc := range []struct {
+		name    string
+		entries []LedgerEntryInput
+	}{
+		{"one entry", []LedgerEntryInput{
+			{AccountCode: LedgerAccountClearing, AccountType: AccountTypeAsset, DebitAmount: ten, Currency: "INR"},
+		}},
+		{"unbalanced", []LedgerEntryInput{
+			{AccountCode: LedgerAccountClearing, AccountType: AccountTypeAsset, DebitAmount: ten, Currency: "INR"},
+			{AccountCode: LedgerAccountFees, AccountType: AccountTypeRevenue, CreditAmount: decimal.NewFromInt(9), Currency: "INR"},
+		}},
+		{"across currencies", []LedgerEntryInput{
+			{AccountCode: LedgerAccountClearing, AccountType: AccountTypeAsset, DebitAmount: ten, Currency: "INR"},
+			{AccountCode: LedgerAccountFees, AccountType: AccountTypeRevenue, CreditAmount: ten, Currency: "USD"},
+		}},
+		{"debit and credit", []LedgerEntryInput{
+			{AccountCode: LedgerAccountClearing, AccountType: AccountTypeAsset, DebitAmount: ten, CreditAmount: ten, Currency: "INR"},
+			{AccountCode: LedgerAccountFees, AccountType: AccountTypeRevenue, CreditAmount: ten, Currency: "INR"},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ledger.PostTransaction(context.Background(), db, LedgerTransaction{
+				ID: uuid.New(), ReferenceType: "test", ReferenceID: uuid.New(), Entries: tc.entries,
+			})
+			assert.Error(t, err)
+		})
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&models.JournalEntry{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestLedger_PostsEachEventOnce(t *testing.T) {
+	ledger, db := newLedgerTestService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	payment := capturedPayment("100.00")
+
+	// Posted again, e.g. by a redelivered capture; the journal entry's
+	// (reference_type, reference_id) index turns the repeat into a no-op
+	require.NoError(t, ledger.PostPaymentTransaction(ctx, db, payment, merchantID))
+	require.NoError(t, ledger.PostPaymentTransaction(ctx, db, payment, merchantID))
+
+	var journals, entries int64
+	require.NoError(t, db.Model(&models.JournalEntry{}).Where("reference_id = ?", payment.ID).Count(&journals).Error)
+	require.NoError(t, db.Model(&models.LedgerEntry{}).Where("reference_id = ?", payment.ID).Count(&entries).Error)
+	assert.EqualValues(t, 2, journals, "payment and fee journaled more than once")
+	assert.EqualValues(t, 4, entries)
+	assert.True(t, ledgerBalance(t, db, MerchantLedgerAccount(merchantID)).Equal(decimal.NewFromInt(98)))
+	assert.NoError(t, ledger.ValidateLedgerIntegrity(ctx))
+}
+
+func TestLedger_Payouts(t *testing.T) {
+	ledger, db := newLedgerTestService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	require.NoError(t, ledger.PostPaymentTransaction(ctx, db, capturedPayment("100.00"), merchantID))
+
+	// The merchant is owed 98 after the fee
+	err := ledger.PostPayout(ctx, PayoutRequest{PayoutID: uuid.New(), MerchantID: merchantID, Amount: decimal.RequireFromString("98.01")})
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+	err = ledger.PostPayout(ctx, PayoutRequest{PayoutID: uuid.New(), MerchantID: uuid.New(), Amount: decimal.NewFromInt(1)})
+	assert.ErrorIs(t, err, ErrInsufficientBalance, "payout to a merchant without an account")
+
+	payout := PayoutRequest{PayoutID: uuid.New(), MerchantID: merchantID, Amount: decimal.NewFromInt(90)}
+	require.NoError(t, ledger.PostPayout(ctx, payout))
+	// Reposting the payout is a no-op, not a second payout
+	require.NoError(t, ledger.PostPayout(ctx, payout))
+
+	assert.True(t, ledgerBalance(t, db, MerchantLedgerAccount(merchantID)).Equal(decimal.NewFromInt(8)))
+	assert.True(t, ledgerBalance(t, db, LedgerAccountClearing).Equal(decimal.NewFromInt(10)))
+	assert.NoError(t, ledger.ValidateLedgerIntegrity(ctx))
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,13 +12,23 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/money"
 )
 
+// ErrPaymentBlocked is returned for a payment the risk engine blocked
+var ErrPaymentBlocked = errors.New("payment blocked due to risk assessment")
+
 // PaymentService handles payment processing
 type PaymentService struct {
 	db            *gorm.DB
 	logger        *logrus.Logger
 	upiClient     *UPIClient
+	rails         *RailRouter
+	retryPolicy   *RetryPolicy
+	threeDS       *ThreeDSPolicy
+	upiDeadLetters *UPIDeadLetterPolicy
+	capture       *CapturePolicy
+	vault         *VaultService
 	ledgerService *LedgerService
 	riskService   *RiskService
 	webhookService *WebhookService
@@ -28,6 +39,12 @@ func NewPaymentService(
 	db *gorm.DB,
 	logger *logrus.Logger,
 	upiClient *UPIClient,
+	rails *RailRouter,
+	retryPolicy *RetryPolicy,
+	threeDS *ThreeDSPolicy,
+	upiDeadLetters *UPIDeadLetterPolicy,
+	capture *CapturePolicy,
+	vault *VaultService,
 	ledgerService *LedgerService,
 	riskService *RiskService,
 	webhookService *WebhookService,
@@ -36,6 +53,12 @@ func NewPaymentService(
 		db:            db,
 		logger:        logger,
 		upiClient:     upiClient,
+		rails:         rails,
+		retryPolicy:   retryPolicy,
+		threeDS:       threeDS,
+		upiDeadLetters: upiDeadLetters,
+		capture:       capture,
+		vault:         vault,
 		ledgerService: ledgerService,
 		riskService:   riskService,
 		webhookService: webhookService,
@@ -48,7 +71,8 @@ type CreatePaymentIntentRequest struct {
 	Amount        decimal.Decimal `json:"amount" binding:"required"`
 	Currency      string          `json:"currency"`
 	Description   string          `json:"description"`
-	PaymentMethod string          `json:"payment_method" binding:"required"`
+	PaymentMethod string          `json:"payment_method"` // Attached later if empty
+	CaptureMethod string          `json:"capture_method" binding:"omitempty,oneof=automatic manual"` // Automatic if empty
 	CustomerID    *uuid.UUID      `json:"customer_id"`
 	Metadata      map[string]interface{} `json:"metadata"`
 	ExpiresIn     *int            `json:"expires_in"` // Seconds from now
@@ -63,12 +87,12 @@ func (s *PaymentService) CreatePaymentIntent(ctx context.Context, req CreatePaym
 	})
 
 	// Validate input
-	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		return nil, fmt.Errorf("amount must be greater than zero")
-	}
-
 	if req.Currency == "" {
-		req.Currency = "INR"
+		req.Currency = money.INR
+	}
+	amount, err := ValidateAmount(req.Amount, req.Currency)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate expiration time
@@ -82,23 +106,47 @@ func (s *PaymentService) CreatePaymentIntent(ctx context.Context, req CreatePaym
 		expiresAt = &expTime
 	}
 
+	if req.CaptureMethod == "" {
+		req.CaptureMethod = models.CaptureMethodAutomatic
+	}
+
+	// Intents start out awaiting the payment method they were not given
+	status := models.PaymentIntentStatusRequiresConfirmation
+	if req.PaymentMethod == "" {
+		status = models.PaymentIntentStatusRequiresPaymentMethod
+	}
+
 	// Create payment intent
 	intent := &models.PaymentIntent{
 		ID:            uuid.New(),
 		MerchantID:    req.MerchantID,
-		Amount:        req.Amount,
-		Currency:      req.Currency,
+		Amount:        amount.Decimal(),
+		Currency:      amount.Currency,
 		Description:   req.Description,
-		Status:        models.PaymentIntentStatusCreated,
+		Status:        status,
 		PaymentMethod: req.PaymentMethod,
+		CaptureMethod: req.CaptureMethod,
 		CustomerID:    req.CustomerID,
 		Metadata:      req.Metadata,
 		ExpiresAt:     expiresAt,
+		Version:       1,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
 
-	err := s.db.WithContext(ctx).Create(intent).Error
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(intent).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.PaymentIntentTransition{
+			ID:              uuid.New(),
+			PaymentIntentID: intent.ID,
+			ToStatus:        intent.Status,
+			Reason:          "created",
+			Version:         intent.Version,
+			CreatedAt:       intent.CreatedAt,
+		}).Error
+	})
 	if err != nil {
 		log.WithError(err).Error("Failed to create payment intent")
 		return nil, fmt.Errorf("failed to create payment intent: %w", err)
@@ -126,14 +174,24 @@ func (s *PaymentService) GetPaymentIntent(ctx context.Context, id uuid.UUID) (*m
 	return &intent, nil
 }
 
-// CreatePaymentRequest represents a payment creation request
+// CreatePaymentRequest represents a payment creation request. Which
+// instrument fields are required depends on the intent's payment method.
+// A saved payment method of the intent's customer can stand in for the
+// payer's VPA or the card.
 type CreatePaymentRequest struct {
-	PaymentIntentID uuid.UUID `json:"payment_intent_id" binding:"required"`
-	PayerVPA        string    `json:"payer_vpa" binding:"required"`
-	PayeeVPA        string    `json:"payee_vpa" binding:"required"`
-	IPAddress       string    `json:"ip_address"`
-	UserAgent       string    `json:"user_agent"`
-	DeviceID        *string   `json:"device_id"`
+	PaymentIntentID    uuid.UUID `json:"payment_intent_id" binding:"required"`
+	PaymentMethodToken string    `json:"payment_method_token"`
+	PayerVPA           string    `json:"payer_vpa"`  // UPI
+	PayeeVPA           string    `json:"payee_vpa"`  // UPI
+	CardToken          string    `json:"card_token"` // Card
+	BankCode           string    `json:"bank_code"`  // Netbanking
+	IPAddress          string    `json:"ip_address"`
+	IPCountry          string    `json:"-"`               // Set from the edge's geo header
+	BillingCountry     string    `json:"billing_country"` // ISO 3166-1 alpha-2
+	UserAgent          string    `json:"user_agent"`
+	DeviceID           *string   `json:"device_id"`
+	Async              bool      `json:"async"` // Queue the payment rather than wait for it
+	ReturnURL          string    `json:"return_url" binding:"omitempty,url"` // Where the payer lands after a 3-D Secure challenge
 }
 
 // CreatePayment processes a payment
@@ -151,33 +209,43 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req CreatePaymentReq
 	}
 
 	// Check if intent is still valid
-	if intent.Status != models.PaymentIntentStatusCreated {
-		return nil, fmt.Errorf("payment intent is not in created status")
+	if !CanTransitionPaymentIntent(intent.Status, models.PaymentIntentStatusProcessing) {
+		return nil, fmt.Errorf("%w: payment intent is %s", ErrInvalidIntentTransition, intent.Status)
 	}
 
 	if intent.ExpiresAt != nil && time.Now().After(*intent.ExpiresAt) {
-		// Update intent status to expired
-		s.db.WithContext(ctx).Model(intent).Update("status", models.PaymentIntentStatusExpired)
+		if err := s.TransitionPaymentIntent(ctx, intent, models.PaymentIntentStatusCanceled, "expired"); err != nil {
+			log.WithError(err).Warn("Failed to cancel expired payment intent")
+		}
 		return nil, fmt.Errorf("payment intent has expired")
 	}
 
-	// Validate VPAs
-	payerValid, err := s.upiClient.ValidateVPA(ctx, req.PayerVPA)
+	// Pick the rail for the intent's payment method
+	rail, err := s.rails.Route(ctx, intent.MerchantID, intent.PaymentMethod)
 	if err != nil {
-		log.WithError(err).Error("Failed to validate payer VPA")
-		return nil, fmt.Errorf("failed to validate payer VPA: %w", err)
+		log.WithError(err).Warn("No rail for payment")
+		return nil, err
 	}
-	if !payerValid {
-		return nil, fmt.Errorf("invalid payer VPA")
+	log = log.WithField("rail", rail.Name())
+	captureManually := intent.CaptureMethod == models.CaptureMethodManual
+	if _, ok := rail.(CaptureRail); captureManually && !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCaptureUnsupported, rail.Name())
 	}
 
-	payeeValid, err := s.upiClient.ValidateVPA(ctx, req.PayeeVPA)
-	if err != nil {
-		log.WithError(err).Error("Failed to validate payee VPA")
-		return nil, fmt.Errorf("failed to validate payee VPA: %w", err)
+	// A saved payment method fills in the payer's side of the instrument
+	var card *CardDetails
+	if req.PaymentMethodToken != "" {
+		instrument, err := s.savedInstrument(ctx, intent, rail.Name(), req.PaymentMethodToken)
+		if err != nil {
+			log.WithError(err).Warn("Saved payment method cannot be used")
+			return nil, err
+		}
+		req.PayerVPA = instrument.VPA
+		card = instrument.Card
 	}
-	if !payeeValid {
-		return nil, fmt.Errorf("invalid payee VPA")
+	if err := s.validateInstrument(ctx, rail.Name(), req); err != nil {
+		log.WithError(err).Warn("Payment instrument validation failed")
+		return nil, err
 	}
 
 	// Perform risk assessment
@@ -189,6 +257,8 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req CreatePaymentReq
 		MerchantID:      intent.MerchantID,
 		CustomerID:      intent.CustomerID,
 		IPAddress:       req.IPAddress,
+		IPCountry:       req.IPCountry,
+		BillingCountry:  req.BillingCountry,
 		UserAgent:       req.UserAgent,
 		DeviceID:        req.DeviceID,
 	}
@@ -199,109 +269,332 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req CreatePaymentReq
 		return nil, fmt.Errorf("risk assessment failed: %w", err)
 	}
 
-	// Check risk decision
-	if riskResult.Decision == models.RiskDecisionBlock {
-		log.WithField("risk_score", riskResult.RiskScore).Warn("Payment blocked by risk assessment")
-		return nil, fmt.Errorf("payment blocked due to risk assessment")
+	// Claim the intent; of concurrent confirmations only one gets past this
+	if err := s.TransitionPaymentIntent(ctx, intent, models.PaymentIntentStatusProcessing, "payment confirmed"); err != nil {
+		return nil, err
 	}
 
-	// Create payment record
+	// Create payment record, with the risk engine's decision on it
 	payment := &models.Payment{
-		ID:              uuid.New(),
-		PaymentIntentID: intent.ID,
-		Amount:          intent.Amount,
-		Currency:        intent.Currency,
-		Status:          models.PaymentStatusPending,
-		PaymentMethod:   intent.PaymentMethod,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		ID:               uuid.New(),
+		PaymentIntentID:  intent.ID,
+		Amount:           intent.Amount,
+		Currency:         intent.Currency,
+		Status:           models.PaymentStatusPending,
+		PaymentMethod:    intent.PaymentMethod,
+		RiskScore:        &riskResult.RiskScore,
+		RiskDecision:     riskResult.Decision,
+		RiskAssessmentID: &riskResult.Assessment.ID,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+	if req.ReturnURL != "" {
+		payment.Metadata = map[string]interface{}{"return_url": req.ReturnURL}
 	}
 
-	// Start database transaction
-	return payment, s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	// Blocked payments are recorded as failed without reaching the rail
+	if riskResult.Decision == models.RiskDecisionBlock {
+		log.WithFields(logrus.Fields{
+			"risk_score": riskResult.RiskScore,
+			"rules":      riskResult.Rules,
+		}).Warn("Payment blocked by risk assessment")
+		return s.blockPayment(ctx, payment, intent)
+	}
+	if riskResult.Decision == models.RiskDecisionReview {
+		log.WithField("risk_score", riskResult.RiskScore).Info("Payment flagged for risk review")
+	}
+
+	// Start database transaction. A payment the rail failed is committed as
+	// failed, along with its intent, and the rail's error returned after.
+	var processErr error
+	var intentEvent *PaymentIntentEvent
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Create payment record
 		if err := tx.Create(payment).Error; err != nil {
 			log.WithError(err).Error("Failed to create payment record")
 			return fmt.Errorf("failed to create payment record: %w", err)
 		}
+		if err := appendPaymentEvent(tx, payment, PaymentEventCreated, paymentCreatedData(payment)); err != nil {
+			return err
+		}
 
 		// Update payment status to processing
 		payment.Status = models.PaymentStatusProcessing
+		if err := appendPaymentEvent(tx, payment, PaymentEventProcessing, PaymentEventData{}); err != nil {
+			return err
+		}
 		if err := tx.Save(payment).Error; err != nil {
 			return fmt.Errorf("failed to update payment status: %w", err)
 		}
 
-		// Process payment through UPI
-		upiReq := UPIPaymentRequest{
-			PaymentID:      payment.ID,
-			PayerVPA:       req.PayerVPA,
-			PayeeVPA:       req.PayeeVPA,
-			Amount:         payment.Amount,
-			Currency:       payment.Currency,
-			Description:    intent.Description,
-			MerchantID:     intent.MerchantID.String(),
-			TransactionRef: payment.ID.String(),
+		// Process payment through the rail, retrying and failing over as the
+		// retry policy says
+		railReq := RailPaymentRequest{
+			Amount:      payment.Amount,
+			Currency:    payment.Currency,
+			Description: intent.Description,
+			MerchantID:  intent.MerchantID.String(),
+			PayerVPA:    req.PayerVPA,
+			PayeeVPA:    req.PayeeVPA,
+			CardToken:   req.CardToken,
+			Card:        card,
+			BankCode:    req.BankCode,
+
+			PaymentMethodToken: req.PaymentMethodToken,
+			CaptureManually:    captureManually,
 		}
-
-		upiResp, err := s.upiClient.ProcessPayment(ctx, upiReq)
+		s.requestStepUp(&railReq, payment)
+		railResp, err := s.runAttempts(ctx, tx, payment, intent.MerchantID, rail, railReq)
 		if err != nil {
-			log.WithError(err).Error("UPI payment processing failed")
-			// Update payment status to failed
-			payment.Status = models.PaymentStatusFailed
-			failureMsg := err.Error()
-			payment.FailureMessage = &failureMsg
-			tx.Save(payment)
-			return fmt.Errorf("UPI payment processing failed: %w", err)
+			if railResp == nil {
+				return err
+			}
+			log.WithError(err).Error("Rail payment processing failed")
+			processErr = err
 		}
 
-		// Update payment with UPI response
-		if upiResp.Success {
-			payment.Status = models.PaymentStatusSucceeded
-			payment.RailTransactionID = upiResp.TransactionID
-			processedAt := upiResp.ProcessedAt
-			payment.ProcessedAt = &processedAt
-		} else {
-			payment.Status = models.PaymentStatusFailed
-			payment.FailureCode = upiResp.FailureCode
-			payment.FailureMessage = upiResp.FailureMessage
+		intentEvent, err = s.settlePayment(ctx, tx, payment, intent, railResp)
+		return err
+	})
+	if err != nil {
+		return payment, err
+	}
+
+	s.emitPaymentEvents(intent.MerchantID, payment, intentEvent)
+	return payment, processErr
+}
+
+// blockPayment records payment as failed by the risk engine, failing its
+// intent, and returns ErrPaymentBlocked
+func (s *PaymentService) blockPayment(ctx context.Context, payment *models.Payment, intent *models.PaymentIntent) (*models.Payment, error) {
+	failureCode := "RISK_BLOCKED"
+	failureMsg := "payment blocked by risk assessment"
+	payment.Status = models.PaymentStatusFailed
+	payment.FailureCode = &failureCode
+	payment.FailureMessage = &failureMsg
+
+	var intentEvent *PaymentIntentEvent
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payment).Error; err != nil {
+			return fmt.Errorf("failed to create payment record: %w", err)
+		}
+		if err := appendPaymentEvent(tx, payment, PaymentEventCreated, paymentCreatedData(payment)); err != nil {
+			return err
 		}
+		err := appendPaymentEvent(tx, payment, PaymentEventFailed, PaymentEventData{
+			FailureCode:    payment.FailureCode,
+			FailureMessage: payment.FailureMessage,
+		})
+		if err != nil {
+			return err
+		}
+		intentEvent, err = s.transitionIntent(tx, intent, models.PaymentIntentStatusFailed, failureMsg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if err := tx.Save(payment).Error; err != nil {
-			return fmt.Errorf("failed to update payment with UPI response: %w", err)
+	s.emitPaymentEvents(intent.MerchantID, payment, intentEvent)
+	return payment, ErrPaymentBlocked
+}
+
+// settlePayment updates payment, and its intent, with the rail's response
+// to its last attempt. A nil response is a retry scheduled for later; the
+// payment stays processing then, as it does while pending with the rail.
+// A payment the rail wants the payer to authenticate waits on a challenge,
+// with its intent requiring action, and one UPI Core could not be reached
+// for waits in the dead letter queue. A payment the rail only authorized
+// waits on the merchant to capture it, with nothing posted to the ledger.
+func (s *PaymentService) settlePayment(ctx context.Context, tx *gorm.DB, payment *models.Payment, intent *models.PaymentIntent, railResp *RailPaymentResponse) (*PaymentIntentEvent, error) {
+	log := s.logger.WithField("payment_id", payment.ID)
+	if railResp == nil {
+		log.Info("Payment retry scheduled")
+		return nil, nil
+	}
+	if !railResp.Success {
+		deadLettered, err := s.deadLetterPayment(tx, payment, intent, railResp)
+		if err != nil || deadLettered {
+			return nil, err
 		}
+	}
 
-		// If payment succeeded, post to ledger
-		if payment.Status == models.PaymentStatusSucceeded {
-			if err := s.ledgerService.PostPaymentTransaction(ctx, payment); err != nil {
-				log.WithError(err).Error("Failed to post payment to ledger")
-				// In a real system, you might want to handle this differently
-				// For now, we'll still consider the payment successful but log the ledger error
+	// Update payment with the rail's response. A pending payment waits
+	// for the payer, e.g. at their bank, with its intent processing.
+	var eventType string
+	var eventData PaymentEventData
+	switch {
+	case railResp.Success && railResp.Status == models.PaymentStatusAuthorized:
+		authorized := payment.Amount
+		expiresAt := time.Now().Add(s.authorizationWindow())
+		payment.Status = models.PaymentStatusAuthorized
+		payment.RailTransactionID = railResp.TransactionID
+		payment.AuthorizedAmount = &authorized
+		payment.AuthorizationExpiresAt = &expiresAt
+		eventType = PaymentEventAuthorized
+		eventData = PaymentEventData{RailTransactionID: payment.RailTransactionID, ExpiresAt: &expiresAt}
+	case railResp.Success:
+		payment.Status = models.PaymentStatusSucceeded
+		payment.RailTransactionID = railResp.TransactionID
+		processedAt := railResp.ProcessedAt
+		payment.ProcessedAt = &processedAt
+		eventType = PaymentEventCaptured
+		eventData = PaymentEventData{RailTransactionID: payment.RailTransactionID, ProcessedAt: payment.ProcessedAt}
+	case railResp.Status == models.PaymentStatusPending:
+		payment.RailTransactionID = railResp.TransactionID
+		if railResp.RedirectURL != "" {
+			if payment.Metadata == nil {
+				payment.Metadata = map[string]interface{}{}
 			}
+			payment.Metadata["redirect_url"] = railResp.RedirectURL
+		}
+		eventType = PaymentEventPending
+		eventData = PaymentEventData{RailTransactionID: payment.RailTransactionID, RedirectURL: railResp.RedirectURL}
+	case railResp.Status == models.PaymentStatusRequiresAction:
+		payment.Status = models.PaymentStatusRequiresAction
+		payment.RailTransactionID = railResp.TransactionID
+		if payment.Metadata == nil {
+			payment.Metadata = map[string]interface{}{}
+		}
+		payment.Metadata["redirect_url"] = railResp.RedirectURL
+		eventType = PaymentEventRequiresAction
+		eventData = PaymentEventData{RailTransactionID: payment.RailTransactionID, RedirectURL: railResp.RedirectURL}
+	default:
+		payment.Status = models.PaymentStatusFailed
+		payment.FailureCode = railResp.FailureCode
+		payment.FailureMessage = railResp.FailureMessage
+		eventType = PaymentEventFailed
+		eventData = PaymentEventData{FailureCode: payment.FailureCode, FailureMessage: payment.FailureMessage}
+	}
 
-			// Update payment intent status
-			intent.Status = models.PaymentIntentStatusSucceeded
-			if err := tx.Save(intent).Error; err != nil {
-				return fmt.Errorf("failed to update payment intent status: %w", err)
-			}
+	if err := appendPaymentEvent(tx, payment, eventType, eventData); err != nil {
+		return nil, err
+	}
+	if err := tx.Save(payment).Error; err != nil {
+		return nil, fmt.Errorf("failed to update payment with rail response: %w", err)
+	}
+	if payment.Status == models.PaymentStatusProcessing {
+		log.Info("Payment pending with rail")
+		return nil, nil
+	}
+	if payment.Status == models.PaymentStatusRequiresAction {
+		if err := s.startChallenge(tx, payment, railResp); err != nil {
+			return nil, err
+		}
+		log.Info("Payment waiting on payer authentication")
+		return s.transitionIntent(tx, intent, models.PaymentIntentStatusRequiresAction, "payer authentication required")
+	}
+	if payment.Status == models.PaymentStatusAuthorized {
+		log.WithField("expires_at", payment.AuthorizationExpiresAt).Info("Payment authorized for capture")
+		return s.transitionIntent(tx, intent, models.PaymentIntentStatusRequiresCapture, "payment authorized")
+	}
+
+	var event *PaymentIntentEvent
+	var err error
+	// If payment succeeded, post to ledger along with it
+	if payment.Status == models.PaymentStatusSucceeded {
+		if err := s.ledgerService.PostPaymentTransaction(ctx, tx, payment, intent.MerchantID); err != nil {
+			log.WithError(err).Error("Failed to post payment to ledger")
+			return nil, fmt.Errorf("failed to post payment to ledger: %w", err)
 		}
 
-		log.WithFields(logrus.Fields{
-			"payment_id":       payment.ID,
-			"status":           payment.Status,
-			"transaction_id":   payment.RailTransactionID,
-		}).Info("Payment processing completed")
-
-		// Trigger webhooks
-		go func() {
-			if payment.Status == models.PaymentStatusSucceeded {
-				s.webhookService.TriggerWebhook(context.Background(), intent.MerchantID, "payment.succeeded", payment)
-			} else {
-				s.webhookService.TriggerWebhook(context.Background(), intent.MerchantID, "payment.failed", payment)
+		// Update payment intent status
+		event, err = s.transitionIntent(tx, intent, models.PaymentIntentStatusSucceeded, "payment succeeded")
+	} else {
+		reason := "payment failed"
+		if payment.FailureMessage != nil {
+			reason = *payment.FailureMessage
+		}
+		event, err = s.transitionIntent(tx, intent, models.PaymentIntentStatusFailed, reason)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(logrus.Fields{
+		"status":         payment.Status,
+		"transaction_id": payment.RailTransactionID,
+	}).Info("Payment processing completed")
+	return event, nil
+}
+
+// paymentCreatedData is the data of a new payment's created event
+func paymentCreatedData(payment *models.Payment) PaymentEventData {
+	return PaymentEventData{
+		PaymentIntentID: &payment.PaymentIntentID,
+		Amount:          &payment.Amount,
+		Currency:        payment.Currency,
+		PaymentMethod:   payment.PaymentMethod,
+		RiskScore:       payment.RiskScore,
+		RiskDecision:    payment.RiskDecision,
+	}
+}
+
+// emitPaymentEvents sends the webhooks of a settled payment and its intent
+func (s *PaymentService) emitPaymentEvents(merchantID uuid.UUID, payment *models.Payment, intentEvent *PaymentIntentEvent) {
+	if payment.Status == models.PaymentStatusProcessing {
+		return
+	}
+	s.emitPaymentIntentEvents(merchantID, intentEvent)
+	go func() {
+		switch payment.Status {
+		case models.PaymentStatusSucceeded:
+			s.webhookService.TriggerWebhook(context.Background(), merchantID, "payment.succeeded", payment)
+		case models.PaymentStatusRequiresAction:
+			s.webhookService.TriggerWebhook(context.Background(), merchantID, "payment.requires_action", payment)
+		case models.PaymentStatusAuthorized:
+			s.webhookService.TriggerWebhook(context.Background(), merchantID, "payment.authorized", payment)
+		default:
+			s.webhookService.TriggerWebhook(context.Background(), merchantID, "payment.failed", payment)
+		}
+	}()
+}
+
+// validateInstrument checks the request carries a valid instrument for rail
+func (s *PaymentService) validateInstrument(ctx context.Context, rail string, req CreatePaymentRequest) error {
+	switch rail {
+	case RailUPI:
+		for _, vpa := range []struct{ role, value string }{{"payer", req.PayerVPA}, {"payee", req.PayeeVPA}} {
+			if vpa.value == "" {
+				return fmt.Errorf("%s VPA is required for UPI payments", vpa.role)
+			}
+			valid, err := s.upiClient.ValidateVPA(ctx, vpa.value)
+			if err != nil {
+				return fmt.Errorf("failed to validate %s VPA: %w", vpa.role, err)
+			}
+			if !valid {
+				return fmt.Errorf("invalid %s VPA", vpa.role)
 			}
-		}()
+		}
+	case RailCard:
+		if req.CardToken == "" && req.PaymentMethodToken == "" {
+			return fmt.Errorf("card token is required for card payments")
+		}
+	case RailNetbanking:
+		if req.BankCode == "" {
+			return fmt.Errorf("bank code is required for netbanking payments")
+		}
+	}
+	return nil
+}
 
-		return nil
-	})
+// savedInstrument opens a saved payment method to pay intent over rail
+// with. Only the intent's customer can pay with their saved methods.
+func (s *PaymentService) savedInstrument(ctx context.Context, intent *models.PaymentIntent, rail, token string) (*vaultInstrument, error) {
+	if s.vault == nil {
+		return nil, ErrPaymentMethodNotFound
+	}
+	method, instrument, err := s.vault.open(ctx, intent.MerchantID, token)
+	if err != nil {
+		return nil, err
+	}
+	if intent.CustomerID == nil || *intent.CustomerID != method.CustomerID {
+		return nil, ErrPaymentMethodNotFound
+	}
+	if methodRail, _ := RailForPaymentMethod(method.Type); methodRail != rail {
+		return nil, fmt.Errorf("%w: a %s method cannot pay over the %s rail", ErrPaymentMethodMismatch, method.Type, rail)
+	}
+	return instrument, nil
 }
 
 // GetPayment retrieves a payment by ID
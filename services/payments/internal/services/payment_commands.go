@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/kafka"
+)
+
+var (
+	// ErrAsyncPaymentsUnavailable is returned for an async payment when no
+	// command queue is configured
+	ErrAsyncPaymentsUnavailable = errors.New("async payments are not enabled")
+	// ErrPaymentCommandNotFound is returned for a payment command that does
+	// not exist
+	ErrPaymentCommandNotFound = errors.New("payment command not found")
+)
+
+// OutboxEventProcessPayment is the outbox event publishing a payment
+// command to the queue
+const OutboxEventProcessPayment = "payment.process"
+
+const (
+	// paymentCommandLease is how long a command may stay claimed, or queued
+	// without being claimed, before it is published again
+	paymentCommandLease = 5 * time.Minute
+	// maxPaymentCommandAttempts is how many times a command is claimed
+	// before it is given up on
+	maxPaymentCommandAttempts = 3
+	// outboxBatchSize is how many outbox events are published per pass
+	outboxBatchSize = 100
+)
+
+// PaymentCommandQueue carries payment commands from the API to the
+// workers processing them
+type PaymentCommandQueue interface {
+	Publish(ctx context.Context, key string, value []byte) error
+	Fetch(ctx context.Context) (kafka.Message, error)
+	Commit(ctx context.Context, msg kafka.Message) error
+}
+
+// paymentCommandRequest is the payment request a command carries
+type paymentCommandRequest struct {
+	CreatePaymentRequest
+	IPCountry string `json:"ip_country"`
+}
+
+// paymentCommandMessage is the message published for a command; the
+// command itself is read from the database
+type paymentCommandMessage struct {
+	CommandID uuid.UUID `json:"command_id"`
+}
+
+// PaymentCommandService processes payments asynchronously. A payment is
+// recorded as a command, published to the queue through the outbox in the
+// same transaction, and processed by a pool of workers. Commands are
+// claimed before they are processed, so redelivered messages are skipped.
+type PaymentCommandService struct {
+	db             *gorm.DB
+	logger         *logrus.Logger
+	queue          PaymentCommandQueue // nil when async payments are disabled
+	payments       *PaymentService
+	webhookService *WebhookService
+	workers        int
+	wake           chan struct{}
+}
+
+// NewPaymentCommandService creates a new payment command service. Without
+// a queue, async payments are rejected.
+func NewPaymentCommandService(db *gorm.DB, logger *logrus.Logger, queue PaymentCommandQueue, payments *PaymentService, webhookService *WebhookService, workers int) *PaymentCommandService {
+	return &PaymentCommandService{
+		db:             db,
+		logger:         logger,
+		queue:          queue,
+		payments:       payments,
+		webhookService: webhookService,
+		workers:        workers,
+		wake:           make(chan struct{}, 1),
+	}
+}
+
+// EnqueuePayment queues a payment of an intent to be processed
+// asynchronously. An intent with a command queued or processing already
+// gets that command back.
+func (s *PaymentCommandService) EnqueuePayment(ctx context.Context, req CreatePaymentRequest) (*models.PaymentCommand, error) {
+	if s.queue == nil {
+		return nil, ErrAsyncPaymentsUnavailable
+	}
+
+	intent, err := s.payments.GetPaymentIntent(ctx, req.PaymentIntentID)
+	if err != nil {
+		return nil, err
+	}
+	if !CanTransitionPaymentIntent(intent.Status, models.PaymentIntentStatusProcessing) {
+		return nil, fmt.Errorf("%w: payment intent is %s", ErrInvalidIntentTransition, intent.Status)
+	}
+	if intent.ExpiresAt != nil && time.Now().After(*intent.ExpiresAt) {
+		return nil, fmt.Errorf("payment intent has expired")
+	}
+
+	request, err := json.Marshal(paymentCommandRequest{CreatePaymentRequest: req, IPCountry: req.IPCountry})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payment command: %w", err)
+	}
+
+	var command models.PaymentCommand
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("payment_intent_id = ? AND status IN ?", intent.ID,
+			[]string{models.PaymentCommandStatusQueued, models.PaymentCommandStatusProcessing}).
+			First(&command).Error
+		if err == nil {
+			return nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return fmt.Errorf("failed to find payment command: %w", err)
+		}
+
+		command = models.PaymentCommand{
+			ID:              uuid.New(),
+			PaymentIntentID: intent.ID,
+			MerchantID:      intent.MerchantID,
+			Request:         request,
+			Status:          models.PaymentCommandStatusQueued,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+		if err := tx.Create(&command).Error; err != nil {
+			return fmt.Errorf("failed to create payment command: %w", err)
+		}
+		return s.addOutboxEvent(tx, &command)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"command_id":        command.ID,
+		"payment_intent_id": command.PaymentIntentID,
+	}).Info("Payment command queued")
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return &command, nil
+}
+
+// GetPaymentCommand retrieves a payment command by ID
+func (s *PaymentCommandService) GetPaymentCommand(ctx context.Context, id uuid.UUID) (*models.PaymentCommand, error) {
+	var command models.PaymentCommand
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&command).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPaymentCommandNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment command: %w", err)
+	}
+	return &command, nil
+}
+
+// addOutboxEvent records command to be published, keyed by its intent
+func (s *PaymentCommandService) addOutboxEvent(tx *gorm.DB, command *models.PaymentCommand) error {
+	data, err := json.Marshal(paymentCommandMessage{CommandID: command.ID})
+	if err != nil {
+		return fmt.Errorf("failed to encode payment command message: %w", err)
+	}
+	event := &models.OutboxEvent{
+		ID:          uuid.New(),
+		EventType:   OutboxEventProcessPayment,
+		EventData:   data,
+		AggregateID: command.PaymentIntentID,
+		Version:     int64(command.Attempts + 1),
+		CreatedAt:   time.Now(),
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+	return nil
+}
+
+// StartOutboxRelay publishes outbox events to the queue until ctx is done,
+// as commands are queued and every second. Commands left queued or claimed
+// past their lease are published again.
+func (s *PaymentCommandService) StartOutboxRelay(ctx context.Context) {
+	if s.queue == nil {
+		return
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	s.logger.Info("Starting outbox relay")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping outbox relay")
+			return
+		case <-ticker.C:
+			s.requeueStaleCommands(ctx)
+		case <-s.wake:
+		}
+		s.publishOutbox(ctx)
+	}
+}
+
+// publishOutbox publishes the unpublished outbox events, oldest first. An
+// event published twice, e.g. by two instances, is a redelivery the
+// workers skip.
+func (s *PaymentCommandService) publishOutbox(ctx context.Context) {
+	var events []models.OutboxEvent
+	err := s.db.WithContext(ctx).
+		Where("published = ? AND event_type = ?", false, OutboxEventProcessPayment).
+		Order("created_at ASC").
+		Limit(outboxBatchSize).
+		Find(&events).Error
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get outbox events")
+		return
+	}
+
+	for _, event := range events {
+		if err := s.queue.Publish(ctx, event.AggregateID.String(), event.EventData); err != nil {
+			s.logger.WithError(err).WithField("event_id", event.ID).Error("Failed to publish outbox event")
+			return
+		}
+		err := s.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+			Where("id = ?", event.ID).
+			Updates(map[string]interface{}{"published": true, "published_at": time.Now()}).Error
+		if err != nil {
+			s.logger.WithError(err).WithField("event_id", event.ID).Error("Failed to mark outbox event published")
+		}
+	}
+}
+
+// requeueStaleCommands publishes again the commands whose message was lost
+// or whose worker died, and gives up on those claimed too often
+func (s *PaymentCommandService) requeueStaleCommands(ctx context.Context) {
+	cutoff := time.Now().Add(-paymentCommandLease)
+	var stale []models.PaymentCommand
+	err := s.db.WithContext(ctx).
+		Where("(status = ? AND updated_at < ?) OR (status = ? AND claimed_at < ? AND updated_at < ?)",
+			models.PaymentCommandStatusQueued, cutoff,
+			models.PaymentCommandStatusProcessing, cutoff, cutoff).
+		Limit(outboxBatchSize).
+		Find(&stale).Error
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get stale payment commands")
+		return
+	}
+
+	for i := range stale {
+		command := &stale[i]
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if command.Attempts >= maxPaymentCommandAttempts {
+				return s.finishCommand(tx, command, nil, fmt.Errorf("payment command abandoned after %d attempts", command.Attempts))
+			}
+			if err := tx.Model(command).Update("updated_at", time.Now()).Error; err != nil {
+				return fmt.Errorf("failed to requeue payment command: %w", err)
+			}
+			return s.addOutboxEvent(tx, command)
+		})
+		if err != nil {
+			s.logger.WithError(err).WithField("command_id", command.ID).Error("Failed to requeue payment command")
+			continue
+		}
+		if command.Status == models.PaymentCommandStatusFailed {
+			s.emitCommandFailed(command)
+		}
+	}
+}
+
+// StartCommandWorkers processes the queue's payment commands with a pool
+// of workers until ctx is done. Messages are committed once their command
+// is processed or found processed already.
+func (s *PaymentCommandService) StartCommandWorkers(ctx context.Context) {
+	if s.queue == nil {
+		s.logger.Info("No payment command queue, async payments are disabled")
+		return
+	}
+	s.logger.WithField("workers", s.workers).Info("Starting payment command workers")
+
+	messages := make(chan kafka.Message)
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range messages {
+				s.handleMessage(ctx, msg)
+			}
+		}()
+	}
+
+	for {
+		msg, err := s.queue.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			s.logger.WithError(err).Error("Failed to fetch payment command")
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		messages <- msg
+	}
+
+	close(messages)
+	wg.Wait()
+	s.logger.Info("Stopping payment command workers")
+}
+
+// handleMessage processes a message's command and commits the message.
+// Messages whose command could not be recorded are left uncommitted, for
+// the relay to publish again.
+func (s *PaymentCommandService) handleMessage(ctx context.Context, msg kafka.Message) {
+	var message paymentCommandMessage
+	if err := json.Unmarshal(msg.Value, &message); err != nil {
+		s.logger.WithError(err).Error("Dropping malformed payment command message")
+	} else if err := s.processCommand(ctx, message.CommandID); err != nil {
+		s.logger.WithError(err).WithField("command_id", message.CommandID).Error("Failed to process payment command")
+		return
+	}
+	if err := s.queue.Commit(ctx, msg); err != nil {
+		s.logger.WithError(err).Warn("Failed to commit payment command message")
+	}
+}
+
+// processCommand claims a command and makes its payment. A command that is
+// processed, or claimed by a live worker, is skipped.
+func (s *PaymentCommandService) processCommand(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	claim := s.db.WithContext(ctx).Model(&models.PaymentCommand{}).
+		Where("id = ? AND (status = ? OR (status = ? AND claimed_at < ?))", id,
+			models.PaymentCommandStatusQueued, models.PaymentCommandStatusProcessing, now.Add(-paymentCommandLease)).
+		Updates(map[string]interface{}{
+			"status":     models.PaymentCommandStatusProcessing,
+			"claimed_at": now,
+			"attempts":   gorm.Expr("attempts + 1"),
+		})
+	if claim.Error != nil {
+		return fmt.Errorf("failed to claim payment command: %w", claim.Error)
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+
+	command, err := s.GetPaymentCommand(ctx, id)
+	if err != nil {
+		return err
+	}
+	log := s.logger.WithFields(logrus.Fields{
+		"command_id":        command.ID,
+		"payment_intent_id": command.PaymentIntentID,
+		"attempt":           command.Attempts,
+	})
+
+	// A worker that died after making the payment leaves it to be found,
+	// rather than made twice
+	var request paymentCommandRequest
+	var payment *models.Payment
+	var processErr error
+	var existing models.Payment
+	err = s.db.WithContext(ctx).
+		Where("payment_intent_id = ? AND created_at >= ?", command.PaymentIntentID, command.CreatedAt).
+		Order("created_at DESC").
+		First(&existing).Error
+	if err == nil {
+		payment = &existing
+	} else if err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to find payment: %w", err)
+	} else if err := json.Unmarshal(command.Request, &request); err != nil {
+		processErr = fmt.Errorf("malformed payment command: %w", err)
+	} else {
+		req := request.CreatePaymentRequest
+		req.IPCountry = request.IPCountry
+		payment, processErr = s.payments.CreatePayment(ctx, req)
+	}
+
+	// A payment the rail failed or the risk engine blocked is still made;
+	// its status is the payment's to tell. One whose transaction rolled
+	// back is not.
+	if payment != nil {
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&models.Payment{}).Where("id = ?", payment.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check payment: %w", err)
+		}
+		if count > 0 {
+			processErr = nil
+		} else {
+			payment = nil
+		}
+	}
+	if err := s.finishCommand(s.db.WithContext(ctx), command, payment, processErr); err != nil {
+		return err
+	}
+
+	if processErr != nil {
+		log.WithError(processErr).Warn("Payment command failed")
+		s.emitCommandFailed(command)
+		return nil
+	}
+	log.WithField("payment_id", payment.ID).Info("Payment command completed")
+	return nil
+}
+
+// finishCommand records how a command went
+func (s *PaymentCommandService) finishCommand(tx *gorm.DB, command *models.PaymentCommand, payment *models.Payment, processErr error) error {
+	now := time.Now()
+	command.Status = models.PaymentCommandStatusCompleted
+	command.CompletedAt = &now
+	if payment != nil {
+		command.PaymentID = &payment.ID
+	}
+	if processErr != nil {
+		command.Status = models.PaymentCommandStatusFailed
+		command.Error = processErr.Error()
+	}
+	err := tx.Model(&models.PaymentCommand{}).Where("id = ?", command.ID).Updates(map[string]interface{}{
+		"status":       command.Status,
+		"payment_id":   command.PaymentID,
+		"error":        command.Error,
+		"completed_at": command.CompletedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update payment command: %w", err)
+	}
+	return nil
+}
+
+// emitCommandFailed tells the merchant a command failed before it made a
+// payment, leaving its intent to be confirmed again
+func (s *PaymentCommandService) emitCommandFailed(command *models.PaymentCommand) {
+	go s.webhookService.TriggerWebhook(context.Background(), command.MerchantID, "payment_command.failed", command)
+}
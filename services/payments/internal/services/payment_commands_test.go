@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/kafka"
+)
+
+// fakeCommandQueue is an in-memory PaymentCommandQueue
+type fakeCommandQueue struct {
+	messages chan kafka.Message
+
+	mu        sync.Mutex
+	published []kafka.Message
+	committed []kafka.Message
+}
+
+func newFakeCommandQueue() *fakeCommandQueue {
+	return &fakeCommandQueue{messages: make(chan kafka.Message, 64)}
+}
+
+func (q *fakeCommandQueue) Publish(ctx context.Context, key string, value []byte) error {
+	msg := kafka.Message{Key: []byte(key), Value: value}
+	q.mu.Lock()
+	q.published = append(q.published, msg)
+	q.mu.Unlock()
+	q.messages <- msg
+	return nil
+}
+
+func (q *fakeCommandQueue) Fetch(ctx context.Context) (kafka.Message, error) {
+	select {
+	case msg := <-q.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func (q *fakeCommandQueue) Commit(ctx context.Context, msg kafka.Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.committed = append(q.committed, msg)
+	return nil
+}
+
+func (q *fakeCommandQueue) counts() (published, committed int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.published), len(q.committed)
+}
+
+func newCommandTestEnv(t *testing.T) (*testEnv, *PaymentCommandService, *fakeCommandQueue) {
+	env := newTestEnv(t)
+	queue := newFakeCommandQueue()
+	commands := NewPaymentCommandService(env.db, testLogger(), queue, env.payments, env.webhooks, 2)
+	return env, commands, queue
+}
+
+func (env *testEnv) cardPaymentRequest(t *testing.T, amount string) CreatePaymentRequest {
+	intent := env.createIntent(t, amount, "INR", RailCard, 15*time.Minute)
+	return CreatePaymentRequest{PaymentIntentID: intent.ID, CardToken: "tok_visa", IPAddress: "127.0.0.1", Async: true}
+}
+
+func storedCommand(t *testing.T, commands *PaymentCommandService, command *models.PaymentCommand) *models.PaymentCommand {
+	stored, err := commands.GetPaymentCommand(context.Background(), command.ID)
+	require.NoError(t, err)
+	return stored
+}
+
+func TestPaymentCommands_DisabledWithoutQueue(t *testing.T) {
+	env := newTestEnv(t)
+	commands := NewPaymentCommandService(env.db, testLogger(), nil, env.payments, env.webhooks, 1)
+	_, err := commands.EnqueuePayment(context.Background(), env.cardPaymentRequest(t, "10.00"))
+	assert.ErrorIs(t, err, ErrAsyncPaymentsUnavailable)
+}
+
+func TestPaymentCommands_EnqueueOncePerIntent(t *testing.T) {
+	env, commands, queue := newCommandTestEnv(t)
+	ctx := context.Background()
+	req := env.cardPaymentRequest(t, "10.00")
+
+	command, err := commands.EnqueuePayment(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentCommandStatusQueued, command.Status)
+	again, err := commands.EnqueuePayment(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, command.ID, again.ID, "second command queued for the intent")
+
+	// The command is published through the outbox, once
+	commands.publishOutbox(ctx)
+	commands.publishOutbox(ctx)
+	published, _ := queue.counts()
+	require.Equal(t, 1, published)
+	msg := queue.published[0]
+	assert.Equal(t, req.PaymentIntentID.String(), string(msg.Key), "command not keyed by its intent")
+	var message paymentCommandMessage
+	require.NoError(t, json.Unmarshal(msg.Value, &message))
+	assert.Equal(t, command.ID, message.CommandID)
+
+	var unpublished int64
+	require.NoError(t, env.db.Model(&models.OutboxEvent{}).Where("published = ?", false).Count(&unpublished).Error)
+	assert.Zero(t, unpublished)
+}
+
+func TestPaymentCommands_WorkersProcessQueuedPayments(t *testing.T) {
+	env, commands, queue := newCommandTestEnv(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); commands.StartOutboxRelay(ctx) }()
+	go func() { defer wg.Done(); commands.StartCommandWorkers(ctx) }()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	command, err := commands.EnqueuePayment(ctx, env.cardPaymentRequest(t, "42.00"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return storedCommand(t, commands, command).Status == models.PaymentCommandStatusCompleted
+	}, 5*time.Second, 20*time.Millisecond)
+	done := storedCommand(t, commands, command)
+	require.NotNil(t, done.PaymentID)
+	assert.Equal(t, 1, done.Attempts)
+	payment, err := env.payments.GetPayment(context.Background(), *done.PaymentID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusSucceeded, payment.Status)
+
+	// A redelivered message is committed without paying again
+	msg := queue.published[0]
+	require.NoError(t, queue.Publish(ctx, string(msg.Key), msg.Value))
+	require.Eventually(t, func() bool {
+		_, committed := queue.counts()
+		return committed == 2
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Len(t, env.card.payments, 1, "redelivered command paid twice")
+}
+
+func TestPaymentCommands_FindsPaymentOfDeadWorker(t *testing.T) {
+	env, commands, _ := newCommandTestEnv(t)
+	ctx := context.Background()
+	req := env.cardPaymentRequest(t, "15.00")
+	command, err := commands.EnqueuePayment(ctx, req)
+	require.NoError(t, err)
+
+	// A worker claimed the command and made the payment, then died
+	payment, err := env.payments.CreatePayment(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, env.db.Model(&models.PaymentCommand{}).Where("id = ?", command.ID).Updates(map[string]interface{}{
+		"status":     models.PaymentCommandStatusProcessing,
+		"claimed_at": time.Now().Add(-2 * paymentCommandLease),
+		"attempts":   1,
+	}).Error)
+
+	require.NoError(t, commands.processCommand(ctx, command.ID))
+	done := storedCommand(t, commands, command)
+	assert.Equal(t, models.PaymentCommandStatusCompleted, done.Status)
+	require.NotNil(t, done.PaymentID)
+	assert.Equal(t, payment.ID, *done.PaymentID)
+	assert.Len(t, env.card.payments, 1, "payment of a dead worker made again")
+}
+
+func TestPaymentCommands_FailedCommands(t *testing.T) {
+	env, commands, queue := newCommandTestEnv(t)
+	ctx := context.Background()
+
+	// The intent expired while the command was queued
+	req := env.cardPaymentRequest(t, "20.00")
+	command, err := commands.EnqueuePayment(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, env.db.Model(&models.PaymentIntent{}).Where("id = ?", req.PaymentIntentID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	require.NoError(t, commands.processCommand(ctx, command.ID))
+	failed := storedCommand(t, commands, command)
+	assert.Equal(t, models.PaymentCommandStatusFailed, failed.Status)
+	assert.Contains(t, failed.Error, "expired")
+	assert.Nil(t, failed.PaymentID)
+
+	// Malformed messages are dropped
+	msg := kafka.Message{Value: []byte("not json")}
+	commands.handleMessage(ctx, msg)
+	_, committed := queue.counts()
+	assert.Equal(t, 1, committed)
+}
+
+func TestPaymentCommands_RequeuesStaleCommands(t *testing.T) {
+	env, commands, queue := newCommandTestEnv(t)
+	ctx := context.Background()
+
+	lost, err := commands.EnqueuePayment(ctx, env.cardPaymentRequest(t, "10.00"))
+	require.NoError(t, err)
+	exhausted, err := commands.EnqueuePayment(ctx, env.cardPaymentRequest(t, "10.00"))
+	require.NoError(t, err)
+	commands.publishOutbox(ctx)
+
+	stale := time.Now().Add(-2 * paymentCommandLease)
+	require.NoError(t, env.db.Model(&models.PaymentCommand{}).Where("id = ?", lost.ID).
+		UpdateColumn("updated_at", stale).Error)
+	require.NoError(t, env.db.Model(&models.PaymentCommand{}).Where("id = ?", exhausted.ID).UpdateColumns(map[string]interface{}{
+		"status":     models.PaymentCommandStatusProcessing,
+		"claimed_at": stale,
+		"updated_at": stale,
+		"attempts":   maxPaymentCommandAttempts,
+	}).Error)
+
+	commands.requeueStaleCommands(ctx)
+	commands.publishOutbox(ctx)
+
+	published, _ := queue.counts()
+	assert.Equal(t, 3, published, "lost command not published again")
+	assert.Equal(t, lost.PaymentIntentID.String(), string(queue.published[2].Key))
+	assert.Equal(t, models.PaymentCommandStatusQueued, storedCommand(t, commands, lost).Status)
+	abandoned := storedCommand(t, commands, exhausted)
+	assert.Equal(t, models.PaymentCommandStatusFailed, abandoned.Status)
+	assert.Contains(t, abandoned.Error, "abandoned")
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// Payment event types. Every change to a payment is recorded as one, in
+// the transaction that makes it.
+const (
+	PaymentEventCreated         = "payment.created"
+	PaymentEventProcessing      = "payment.processing"
+	PaymentEventAttempted       = "payment.attempted"       // One per rail attempt
+	PaymentEventPending         = "payment.pending"         // Waiting on the payer, e.g. at their bank
+	PaymentEventRequiresAction  = "payment.requires_action" // Waiting on the payer to authenticate, e.g. with 3-D Secure
+	PaymentEventAuthorized      = "payment.authorized"      // Held for the merchant to capture
+	PaymentEventCaptured        = "payment.captured"
+	PaymentEventVoided          = "payment.voided" // An authorization released uncaptured
+	PaymentEventFailed          = "payment.failed"
+	PaymentEventRefundRequested = "payment.refund_requested"
+	PaymentEventRefunded        = "payment.refunded"
+	PaymentEventRefundReleased  = "payment.refund_released" // A refund failed or was canceled
+	PaymentEventSettled         = "payment.settled"         // Reconciled against a settlement report
+)
+
+// PaymentEventData is the data of a payment event. Each type sets the
+// fields it changes.
+type PaymentEventData struct {
+	PaymentIntentID   *uuid.UUID       `json:"payment_intent_id,omitempty"`
+	Amount            *decimal.Decimal `json:"amount,omitempty"`
+	Currency          string           `json:"currency,omitempty"`
+	PaymentMethod     string           `json:"payment_method,omitempty"`
+	RiskScore         *float64         `json:"risk_score,omitempty"`
+	RiskDecision      string           `json:"risk_decision,omitempty"`
+	AttemptNumber     int              `json:"attempt_number,omitempty"`
+	Rail              string           `json:"rail,omitempty"`
+	AttemptStatus     string           `json:"attempt_status,omitempty"`
+	FailureCategory   string           `json:"failure_category,omitempty"`
+	RetryAt           *time.Time       `json:"retry_at,omitempty"`
+	RailTransactionID string           `json:"rail_transaction_id,omitempty"`
+	RedirectURL       string           `json:"redirect_url,omitempty"`
+	ProcessedAt       *time.Time       `json:"processed_at,omitempty"`
+	ExpiresAt         *time.Time       `json:"expires_at,omitempty"` // Of an authorization
+	FailureCode       *string          `json:"failure_code,omitempty"`
+	FailureMessage    *string          `json:"failure_message,omitempty"`
+	RefundID          *uuid.UUID       `json:"refund_id,omitempty"`
+	RefundAmount      *decimal.Decimal `json:"refund_amount,omitempty"`
+	RefundStatus      string           `json:"refund_status,omitempty"`
+	SettlementID      string           `json:"settlement_id,omitempty"`
+	SettledAt         *time.Time       `json:"settled_at,omitempty"`
+}
+
+// appendPaymentEvent records an event of payment within tx. The payment's
+// version is bumped first, which locks its row, so its events are numbered
+// without gaps however many transactions append to it. payment only needs
+// its ID set; its Version is updated to the event's sequence.
+func appendPaymentEvent(tx *gorm.DB, payment *models.Payment, eventType string, data PaymentEventData) error {
+	result := tx.Model(&models.Payment{}).Where("id = ?", payment.ID).Update("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to bump payment version: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("payment %s not found for %s", payment.ID, eventType)
+	}
+	var version int64
+	if err := tx.Model(&models.Payment{}).Where("id = ?", payment.ID).Pluck("version", &version).Error; err != nil {
+		return fmt.Errorf("failed to read payment version: %w", err)
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", eventType, err)
+	}
+	event := &models.PaymentEvent{
+		ID:        uuid.New(),
+		PaymentID: payment.ID,
+		Sequence:  version,
+		EventType: eventType,
+		Data:      encoded,
+		CreatedAt: time.Now(),
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record %s: %w", eventType, err)
+	}
+	payment.Version = version
+	return nil
+}
+
+// PaymentAggregate is a payment as rebuilt from its events
+type PaymentAggregate struct {
+	ID                  uuid.UUID        `json:"id"`
+	PaymentIntentID     uuid.UUID        `json:"payment_intent_id"`
+	Amount              decimal.Decimal  `json:"amount"`
+	Currency            string           `json:"currency"`
+	PaymentMethod       string           `json:"payment_method"`
+	Status              string           `json:"status"`
+	AuthorizedAmount    *decimal.Decimal `json:"authorized_amount,omitempty"`
+	RiskScore           *float64         `json:"risk_score,omitempty"`
+	RiskDecision        string           `json:"risk_decision,omitempty"`
+	Attempts            int              `json:"attempts"`
+	Rail                string           `json:"rail,omitempty"` // Of the last attempt
+	RailTransactionID   string           `json:"rail_transaction_id,omitempty"`
+	FailureCode         *string          `json:"failure_code,omitempty"`
+	FailureMessage      *string          `json:"failure_message,omitempty"`
+	ProcessedAt         *time.Time       `json:"processed_at,omitempty"`
+	RefundedAmount      decimal.Decimal  `json:"refunded_amount"`
+	PendingRefundAmount decimal.Decimal  `json:"pending_refund_amount"`
+	SettlementID        string           `json:"settlement_id,omitempty"`
+	SettledAt           *time.Time       `json:"settled_at,omitempty"`
+	Version             int64            `json:"version"`
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+}
+
+// ReplayPaymentEvents folds a payment's events, in sequence order, into
+// the payment they describe
+func ReplayPaymentEvents(events []models.PaymentEvent) (*PaymentAggregate, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no events to replay")
+	}
+	aggregate := &PaymentAggregate{ID: events[0].PaymentID}
+	for _, event := range events {
+		if err := aggregate.apply(event); err != nil {
+			return nil, fmt.Errorf("event %d (%s): %w", event.Sequence, event.EventType, err)
+		}
+	}
+	return aggregate, nil
+}
+
+func (a *PaymentAggregate) apply(event models.PaymentEvent) error {
+	if event.PaymentID != a.ID {
+		return fmt.Errorf("belongs to payment %s", event.PaymentID)
+	}
+	if event.Sequence != a.Version+1 {
+		return fmt.Errorf("out of sequence after %d", a.Version)
+	}
+	var data PaymentEventData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return fmt.Errorf("invalid data: %w", err)
+	}
+	if a.Version == 0 && event.EventType != PaymentEventCreated {
+		return fmt.Errorf("first event is not %s", PaymentEventCreated)
+	}
+
+	switch event.EventType {
+	case PaymentEventCreated:
+		if a.Version != 0 {
+			return fmt.Errorf("payment already created")
+		}
+		if data.PaymentIntentID == nil || data.Amount == nil {
+			return fmt.Errorf("missing payment intent or amount")
+		}
+		a.PaymentIntentID = *data.PaymentIntentID
+		a.Amount = *data.Amount
+		a.Currency = data.Currency
+		a.PaymentMethod = data.PaymentMethod
+		a.RiskScore = data.RiskScore
+		a.RiskDecision = data.RiskDecision
+		a.Status = models.PaymentStatusPending
+		a.CreatedAt = event.CreatedAt
+	case PaymentEventProcessing:
+		a.Status = models.PaymentStatusProcessing
+	case PaymentEventAttempted:
+		a.Attempts++
+		a.Rail = data.Rail
+	case PaymentEventPending:
+		a.RailTransactionID = data.RailTransactionID
+	case PaymentEventRequiresAction:
+		a.Status = models.PaymentStatusRequiresAction
+		a.RailTransactionID = data.RailTransactionID
+	case PaymentEventAuthorized:
+		amount := a.Amount
+		a.Status = models.PaymentStatusAuthorized
+		a.AuthorizedAmount = &amount
+		a.RailTransactionID = data.RailTransactionID
+	case PaymentEventCaptured:
+		a.Status = models.PaymentStatusSucceeded
+		a.RailTransactionID = data.RailTransactionID
+		a.ProcessedAt = data.ProcessedAt
+		if data.Amount != nil {
+			a.Amount = *data.Amount // Less than authorized, for a partial capture
+		}
+	case PaymentEventVoided:
+		if a.Status != models.PaymentStatusAuthorized {
+			return fmt.Errorf("voids a %s payment", a.Status)
+		}
+		a.Status = models.PaymentStatusCanceled
+		a.FailureCode = data.FailureCode
+		a.FailureMessage = data.FailureMessage
+	case PaymentEventFailed:
+		a.Status = models.PaymentStatusFailed
+		a.FailureCode = data.FailureCode
+		a.FailureMessage = data.FailureMessage
+	case PaymentEventSettled:
+		if a.Status != models.PaymentStatusSucceeded {
+			return fmt.Errorf("settles a %s payment", a.Status)
+		}
+		a.SettlementID = data.SettlementID
+		a.SettledAt = data.SettledAt
+	case PaymentEventRefundRequested, PaymentEventRefunded, PaymentEventRefundReleased:
+		if data.RefundAmount == nil {
+			return fmt.Errorf("missing refund amount")
+		}
+		switch event.EventType {
+		case PaymentEventRefundRequested:
+			a.PendingRefundAmount = a.PendingRefundAmount.Add(*data.RefundAmount)
+		case PaymentEventRefunded:
+			a.PendingRefundAmount = a.PendingRefundAmount.Sub(*data.RefundAmount)
+			a.RefundedAmount = a.RefundedAmount.Add(*data.RefundAmount)
+		default:
+			a.PendingRefundAmount = a.PendingRefundAmount.Sub(*data.RefundAmount)
+		}
+	default:
+		return fmt.Errorf("unknown event type")
+	}
+	a.Version = event.Sequence
+	a.UpdatedAt = event.CreatedAt
+	return nil
+}
+
+// PaymentHistory is a payment's events, the payment rebuilt from them,
+// and where the stored payment differs from it
+type PaymentHistory struct {
+	Events    []models.PaymentEvent `json:"events"`
+	Aggregate *PaymentAggregate     `json:"aggregate"`
+	Drift     []string              `json:"drift"` // Fields of the stored payment that disagree
+}
+
+// GetPaymentHistory rebuilds a payment from its events. Payments made
+// before events were recorded have none, and no aggregate.
+func (s *PaymentService) GetPaymentHistory(ctx context.Context, id uuid.UUID) (*PaymentHistory, error) {
+	payment, err := s.GetPayment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &PaymentHistory{Drift: []string{}}
+	err = s.db.WithContext(ctx).
+		Where("payment_id = ?", id).
+		Order("sequence ASC").
+		Find(&history.Events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment events: %w", err)
+	}
+	if len(history.Events) == 0 {
+		return history, nil
+	}
+
+	history.Aggregate, err = ReplayPaymentEvents(history.Events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay payment events: %w", err)
+	}
+	history.Drift = paymentDrift(payment, history.Aggregate)
+	return history, nil
+}
+
+// paymentDrift lists the fields where payment differs from aggregate
+func paymentDrift(payment *models.Payment, aggregate *PaymentAggregate) []string {
+	drift := []string{}
+	check := func(field string, equal bool) {
+		if !equal {
+			drift = append(drift, field)
+		}
+	}
+	check("status", payment.Status == aggregate.Status)
+	check("amount", payment.Amount.Equal(aggregate.Amount))
+	check("rail_transaction_id", payment.RailTransactionID == aggregate.RailTransactionID)
+	check("failure_code", equalStringPtr(payment.FailureCode, aggregate.FailureCode))
+	check("settlement_id", equalStringPtr(payment.SettlementID, optionalString(aggregate.SettlementID)))
+	check("version", payment.Version == aggregate.Version)
+	return drift
+}
+
+// optionalString is nil for an empty s
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
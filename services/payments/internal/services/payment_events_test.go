@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+func paymentEvent(t *testing.T, paymentID uuid.UUID, sequence int64, eventType string, data PaymentEventData) models.PaymentEvent {
+	encoded, err := json.Marshal(data)
+	require.NoError(t, err)
+	return models.PaymentEvent{
+		ID:        uuid.New(),
+		PaymentID: paymentID,
+		Sequence:  sequence,
+		EventType: eventType,
+		Data:      encoded,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestPaymentEvents_HistoryRebuildsPayment(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	payment := env.pay(t, "100.00", "INR")
+	env.refund(t, payment, "30.00")
+	env.refunds.submitPendingRefunds(ctx)
+	canceled := env.refund(t, payment, "20.00")
+	_, err := env.refunds.CancelRefund(ctx, canceled.ID)
+	require.NoError(t, err)
+
+	history, err := env.payments.GetPaymentHistory(ctx, payment.ID)
+	require.NoError(t, err)
+
+	var types []string
+	for i, event := range history.Events {
+		assert.EqualValues(t, i+1, event.Sequence, "events not numbered without gaps")
+		types = append(types, event.EventType)
+	}
+	require.NotEmpty(t, types)
+	assert.Equal(t, []string{PaymentEventCreated, PaymentEventProcessing, PaymentEventAttempted}, types[:3])
+	assert.Equal(t, []string{
+		PaymentEventRefundRequested, PaymentEventRefunded,
+		PaymentEventRefundRequested, PaymentEventRefundReleased,
+	}, types[len(types)-4:])
+
+	aggregate := history.Aggregate
+	require.NotNil(t, aggregate)
+	assert.Equal(t, models.PaymentStatusSucceeded, aggregate.Status)
+	assert.True(t, aggregate.Amount.Equal(decimal.NewFromInt(100)))
+	assert.Equal(t, RailCard, aggregate.Rail)
+	assert.Equal(t, 1, aggregate.Attempts)
+	assert.True(t, aggregate.RefundedAmount.Equal(decimal.NewFromInt(30)), "refunded %s", aggregate.RefundedAmount)
+	assert.True(t, aggregate.PendingRefundAmount.IsZero(), "pending %s", aggregate.PendingRefundAmount)
+	assert.Empty(t, history.Drift)
+}
+
+func TestPaymentEvents_FailedPayment(t *testing.T) {
+	env := newTestEnv(t)
+	env.card.paymentStatus = models.PaymentStatusFailed
+	intent := env.createIntent(t, "75.00", "INR", RailCard, 15*time.Minute)
+	payment, _ := env.payments.CreatePayment(context.Background(), CreatePaymentRequest{PaymentIntentID: intent.ID, CardToken: "tok_visa"})
+	require.NotNil(t, payment)
+
+	history, err := env.payments.GetPaymentHistory(context.Background(), payment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, PaymentEventFailed, history.Events[len(history.Events)-1].EventType)
+	assert.Equal(t, models.PaymentStatusFailed, history.Aggregate.Status)
+	require.NotNil(t, history.Aggregate.FailureCode)
+	assert.Equal(t, "DECLINED", *history.Aggregate.FailureCode)
+	assert.Empty(t, history.Drift)
+}
+
+func TestPaymentEvents_DriftFromStoredPayment(t *testing.T) {
+	env := newTestEnv(t)
+	payment := env.pay(t, "100.00", "INR")
+
+	// A write that bypassed the events
+	require.NoError(t, env.db.Model(&models.Payment{}).Where("id = ?", payment.ID).
+		UpdateColumn("status", models.PaymentStatusFailed).Error)
+
+	history, err := env.payments.GetPaymentHistory(context.Background(), payment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status"}, history.Drift)
+}
+
+func TestReplayPaymentEvents_RejectsInvalidStreams(t *testing.T) {
+	id := uuid.New()
+	amount := decimal.NewFromInt(100)
+	created := PaymentEventData{PaymentIntentID: &id, Amount: &amount, Currency: "INR"}
+
+	for _, tc := range []struct {
+		name   string
+		events func(t *testing.T) []models.PaymentEvent
+		want   string
+	}{
+		{"no events", func(t *testing.T) []models.PaymentEvent { return nil }, "no events"},
+		{"not created first", func(t *testing.T) []models.PaymentEvent {
+			return []models.PaymentEvent{paymentEvent(t, id, 1, PaymentEventProcessing, PaymentEventData{})}
+		}, "first event"},
+		{"gap in sequence", func(t *testing.T) []models.PaymentEvent {
+			return []models.PaymentEvent{
+				paymentEvent(t, id, 1, PaymentEventCreated, created),
+				paymentEvent(t, id, 3, PaymentEventProcessing, PaymentEventData{}),
+			}
+		}, "out of sequence"},
+		{"another payment's event", func(t *testing.T) []models.PaymentEvent {
+			return []models.PaymentEvent{
+				paymentEvent(t, id, 1, PaymentEventCreated, created),
+				paymentEvent(t, uuid.New(), 2, PaymentEventProcessing, PaymentEventData{}),
+			}
+		}, "belongs to payment"},
+		{"void of a captured payment", func(t *testing.T) []models.PaymentEvent {
+			return []models.PaymentEvent{
+				paymentEvent(t, id, 1, PaymentEventCreated, created),
+				paymentEvent(t, id, 2, PaymentEventCaptured, PaymentEventData{}),
+				paymentEvent(t, id, 3, PaymentEventVoided, PaymentEventData{}),
+			}
+		}, "voids a succeeded payment"},
+		{"unknown type", func(t *testing.T) []models.PaymentEvent {
+			return []models.PaymentEvent{
+				paymentEvent(t, id, 1, PaymentEventCreated, created),
+				paymentEvent(t, id, 2, "payment.teleported", PaymentEventData{}),
+			}
+		}, "unknown event type"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReplayPaymentEvents(tc.events(t))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// Categories of rail failures. Only categories with a retry rule are
+// retried; declines never are.
+const (
+	FailureCategoryBankUnavailable = "bank_unavailable"
+	FailureCategoryTimeout         = "timeout"
+	FailureCategoryRailUnavailable = "rail_unavailable"
+	FailureCategoryDeclined        = "declined"
+)
+
+// failureCodeCategories maps failure codes to their category: UPI Core's
+// NPCI codes, and the gateways' own
+var failureCodeCategories = map[string]string{
+	"U78":              FailureCategoryBankUnavailable, // Bank is not available
+	"U29":              FailureCategoryBankUnavailable, // Address resolution failed
+	"BANK_UNAVAILABLE": FailureCategoryBankUnavailable,
+	"U67":              FailureCategoryTimeout, // Transaction timeout
+	"TIMEOUT":          FailureCategoryTimeout,
+	"GATEWAY_TIMEOUT":  FailureCategoryTimeout,
+	"U13":              FailureCategoryRailUnavailable, // System error at the switch
+}
+
+// ClassifyFailure returns the category of a rail failure code. Calls that
+// never reached the rail (the *_SERVICE_ERROR codes) are rail_unavailable;
+// anything unrecognized is a decline.
+func ClassifyFailure(code *string) string {
+	if code == nil {
+		return FailureCategoryDeclined
+	}
+	if category, ok := failureCodeCategories[strings.ToUpper(*code)]; ok {
+		return category
+	}
+	if strings.HasSuffix(*code, "_SERVICE_ERROR") {
+		return FailureCategoryRailUnavailable
+	}
+	return FailureCategoryDeclined
+}
+
+// RetryRule is how payments failing with a category are retried
+type RetryRule struct {
+	MaxAttempts int           // Attempts with the category before giving up, the first included
+	Delay       time.Duration // Wait before retrying on the same rail
+	Fallback    bool          // Fail over to a fallback rail first, if one is left
+}
+
+// RetryPolicy is how failed payments are retried
+type RetryPolicy struct {
+	Rules          map[string]RetryRule
+	FallbackRails  []string      // Rails failed over to, in order
+	MaxInlineDelay time.Duration // Longer delays are retried in the background
+}
+
+// ParseRetryRules parses rules written as category=attempts:delay[:fallback],
+// comma-separated, e.g. "bank_unavailable=3:30s:fallback,timeout=2:2s"
+func ParseRetryRules(spec string) (map[string]RetryRule, error) {
+	rules := make(map[string]RetryRule)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		category, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("retry rule %q: expected category=attempts:delay[:fallback]", entry)
+		}
+		parts := strings.Split(value, ":")
+		if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != "fallback") {
+			return nil, fmt.Errorf("retry rule %q: expected category=attempts:delay[:fallback]", entry)
+		}
+		attempts, err := strconv.Atoi(parts[0])
+		if err != nil || attempts < 1 {
+			return nil, fmt.Errorf("retry rule %q: invalid attempts %q", entry, parts[0])
+		}
+		delay, err := time.ParseDuration(parts[1])
+		if err != nil || delay < 0 {
+			return nil, fmt.Errorf("retry rule %q: invalid delay %q", entry, parts[1])
+		}
+		rules[strings.TrimSpace(category)] = RetryRule{
+			MaxAttempts: attempts,
+			Delay:       delay,
+			Fallback:    len(parts) == 3,
+		}
+	}
+	return rules, nil
+}
+
+// hasInstrument reports whether the request carries what rail needs
+func (r RailPaymentRequest) hasInstrument(rail string) bool {
+	switch rail {
+	case RailUPI:
+		return r.PayerVPA != "" && r.PayeeVPA != ""
+	case RailCard:
+		return r.CardToken != "" || r.Card != nil
+	case RailNetbanking:
+		return r.BankCode != ""
+	}
+	return false
+}
+
+// runAttempts sends a payment over rail, retrying and failing over as the
+// retry policy says, and records each attempt. It returns the response of
+// the last attempt, or nil if a retry was scheduled for later. A request
+// the rail refused to send is returned as a failed response along with the
+// rail's error; errors without a response are the database's.
+func (s *PaymentService) runAttempts(ctx context.Context, tx *gorm.DB, payment *models.Payment, merchantID uuid.UUID, rail PaymentRail, req RailPaymentRequest) (*RailPaymentResponse, error) {
+	var previous []models.PaymentAttempt
+	if err := tx.Where("payment_id = ?", payment.ID).Find(&previous).Error; err != nil {
+		return nil, fmt.Errorf("failed to get payment attempts: %w", err)
+	}
+	counts := make(map[string]int)
+	tried := make(map[string]bool)
+	for _, attempt := range previous {
+		if attempt.FailureCategory != "" {
+			counts[attempt.FailureCategory]++
+		}
+		tried[attempt.Rail] = true
+	}
+
+	for number := len(previous) + 1; ; number++ {
+		// The first attempt goes by the payment's ID, so the rail sees a
+		// plain payment; retries need IDs of their own
+		reference := payment.ID
+		if number > 1 {
+			reference = uuid.New()
+		}
+		req.PaymentID = reference
+		req.TransactionRef = reference.String()
+		tried[rail.Name()] = true
+
+		attempt := &models.PaymentAttempt{
+			ID:            uuid.New(),
+			PaymentID:     payment.ID,
+			AttemptNumber: number,
+			Rail:          rail.Name(),
+			RailReference: reference,
+			PayerVPA:      req.PayerVPA,
+			PayeeVPA:      req.PayeeVPA,
+			CardToken:     req.CardToken,
+			BankCode:      req.BankCode,
+			CreatedAt:     time.Now(),
+
+			PaymentMethodToken: req.PaymentMethodToken,
+		}
+		// A saved method's instrument stays in the vault; retries read it
+		// from there again
+		if req.PaymentMethodToken != "" {
+			attempt.PayerVPA = ""
+		}
+		log := s.logger.WithFields(logrus.Fields{
+			"payment_id": payment.ID,
+			"attempt":    number,
+			"rail":       rail.Name(),
+		})
+
+		resp, err := rail.ProcessPayment(ctx, req)
+		if err != nil {
+			attempt.Status = models.PaymentAttemptStatusFailed
+			failureMsg := err.Error()
+			attempt.FailureMessage = &failureMsg
+			if saveErr := s.recordAttempt(tx, payment, attempt); saveErr != nil {
+				return nil, fmt.Errorf("failed to record payment attempt: %w", saveErr)
+			}
+			failed := &RailPaymentResponse{Status: models.PaymentStatusFailed, FailureMessage: &failureMsg}
+			return failed, fmt.Errorf("%s payment processing failed: %w", rail.Name(), err)
+		}
+
+		attempt.RailTransactionID = resp.TransactionID
+		switch {
+		case resp.Success:
+			attempt.Status = models.PaymentAttemptStatusSucceeded
+		case resp.Status == models.PaymentStatusPending, resp.Status == models.PaymentStatusRequiresAction:
+			attempt.Status = models.PaymentAttemptStatusPending
+		default:
+			attempt.Status = models.PaymentAttemptStatusFailed
+			attempt.FailureCode = resp.FailureCode
+			attempt.FailureMessage = resp.FailureMessage
+			attempt.FailureCategory = ClassifyFailure(resp.FailureCode)
+		}
+		if attempt.Status != models.PaymentAttemptStatusFailed {
+			if err := s.recordAttempt(tx, payment, attempt); err != nil {
+				return nil, fmt.Errorf("failed to record payment attempt: %w", err)
+			}
+			return resp, nil
+		}
+
+		category := attempt.FailureCategory
+		counts[category]++
+		rule, retryable := s.retryRule(category)
+		if !retryable || counts[category] >= rule.MaxAttempts {
+			if err := s.recordAttempt(tx, payment, attempt); err != nil {
+				return nil, fmt.Errorf("failed to record payment attempt: %w", err)
+			}
+			log.WithField("failure_category", category).Info("Payment attempt failed, not retrying")
+			return resp, nil
+		}
+
+		next := rail
+		if rule.Fallback {
+			if fallback := s.fallbackRail(ctx, merchantID, tried, req); fallback != nil {
+				next = fallback
+			}
+		}
+		if next == rail && rule.Delay > s.retryPolicy.MaxInlineDelay {
+			retryAt := time.Now().Add(rule.Delay)
+			attempt.RetryAt = &retryAt
+		}
+		if err := s.recordAttempt(tx, payment, attempt); err != nil {
+			return nil, fmt.Errorf("failed to record payment attempt: %w", err)
+		}
+
+		switch {
+		case next != rail:
+			log.WithFields(logrus.Fields{
+				"failure_category": category,
+				"fallback_rail":    next.Name(),
+			}).Warn("Payment attempt failed, failing over")
+			rail = next
+		case attempt.RetryAt != nil:
+			log.WithFields(logrus.Fields{
+				"failure_category": category,
+				"retry_at":         attempt.RetryAt,
+			}).Warn("Payment attempt failed, retry scheduled")
+			return nil, nil
+		default:
+			log.WithFields(logrus.Fields{
+				"failure_category": category,
+				"delay":            rule.Delay,
+			}).Warn("Payment attempt failed, retrying")
+			select {
+			case <-ctx.Done():
+				return resp, nil
+			case <-time.After(rule.Delay):
+			}
+		}
+	}
+}
+
+// attemptRequest rebuilds the rail request of a payment's attempt, to send
+// it again. A saved method deleted since leaves the rail nothing to charge,
+// failing the retry.
+func (s *PaymentService) attemptRequest(ctx context.Context, payment *models.Payment, intent *models.PaymentIntent, attempt *models.PaymentAttempt) (RailPaymentRequest, error) {
+	req := RailPaymentRequest{
+		Amount:      payment.Amount,
+		Currency:    payment.Currency,
+		Description: intent.Description,
+		MerchantID:  intent.MerchantID.String(),
+		PayerVPA:    attempt.PayerVPA,
+		PayeeVPA:    attempt.PayeeVPA,
+		CardToken:   attempt.CardToken,
+		BankCode:    attempt.BankCode,
+
+		PaymentMethodToken: attempt.PaymentMethodToken,
+		CaptureManually:    intent.CaptureMethod == models.CaptureMethodManual,
+	}
+	if attempt.PaymentMethodToken != "" {
+		instrument, err := s.savedInstrument(ctx, intent, attempt.Rail, attempt.PaymentMethodToken)
+		switch {
+		case err == nil:
+			req.PayerVPA = instrument.VPA
+			req.Card = instrument.Card
+		case errors.Is(err, ErrPaymentMethodNotFound):
+			s.logger.WithField("payment_id", payment.ID).Warn("Saved payment method of payment retry is gone")
+		default:
+			return RailPaymentRequest{}, err
+		}
+	}
+	s.requestStepUp(&req, payment)
+	return req, nil
+}
+
+// recordAttempt saves attempt of payment, with its event
+func (s *PaymentService) recordAttempt(tx *gorm.DB, payment *models.Payment, attempt *models.PaymentAttempt) error {
+	if err := tx.Create(attempt).Error; err != nil {
+		return err
+	}
+	return appendPaymentEvent(tx, payment, PaymentEventAttempted, PaymentEventData{
+		AttemptNumber:     attempt.AttemptNumber,
+		Rail:              attempt.Rail,
+		AttemptStatus:     attempt.Status,
+		FailureCategory:   attempt.FailureCategory,
+		RetryAt:           attempt.RetryAt,
+		RailTransactionID: attempt.RailTransactionID,
+		FailureCode:       attempt.FailureCode,
+		FailureMessage:    attempt.FailureMessage,
+	})
+}
+
+func (s *PaymentService) retryRule(category string) (RetryRule, bool) {
+	if s.retryPolicy == nil {
+		return RetryRule{}, false
+	}
+	rule, ok := s.retryPolicy.Rules[category]
+	return rule, ok
+}
+
+// fallbackRail returns the first fallback rail not yet tried that the
+// merchant has enabled and the request has an instrument for, and that can
+// authorize without capturing if the request asks it to
+func (s *PaymentService) fallbackRail(ctx context.Context, merchantID uuid.UUID, tried map[string]bool, req RailPaymentRequest) PaymentRail {
+	enabled, err := s.rails.MerchantRails(ctx, merchantID)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to get merchant rails for failover")
+		return nil
+	}
+	for _, name := range s.retryPolicy.FallbackRails {
+		if tried[name] || !enabled[name] || !req.hasInstrument(name) {
+			continue
+		}
+		rail, err := s.rails.RailByName(name)
+		if err != nil {
+			continue
+		}
+		if _, ok := rail.(CaptureRail); req.CaptureManually && !ok {
+			continue
+		}
+		return rail
+	}
+	return nil
+}
+
+// GetPaymentAttempts returns the attempts of a payment, oldest first
+func (s *PaymentService) GetPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]models.PaymentAttempt, error) {
+	var attempts []models.PaymentAttempt
+	err := s.db.WithContext(ctx).
+		Where("payment_id = ?", paymentID).
+		Order("attempt_number ASC").
+		Find(&attempts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// StartRetryWorker retries payments whose retry is due, fails those whose
+// 3-D Secure challenge expired and voids authorizations not captured in
+// time, until ctx is done
+func (s *PaymentService) StartRetryWorker(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	s.logger.Info("Starting payment retry worker")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping payment retry worker")
+			return
+		case <-ticker.C:
+			if err := s.retryDuePayments(ctx); err != nil {
+				s.logger.WithError(err).Error("Failed to retry due payments")
+			}
+			if err := s.expireChallenges(ctx); err != nil {
+				s.logger.WithError(err).Error("Failed to expire 3-D Secure challenges")
+			}
+			if err := s.expireAuthorizations(ctx); err != nil {
+				s.logger.WithError(err).Error("Failed to void expired authorizations")
+			}
+		}
+	}
+}
+
+// retryDuePayments resumes the payments whose scheduled retry is due
+func (s *PaymentService) retryDuePayments(ctx context.Context) error {
+	var due []models.PaymentAttempt
+	err := s.db.WithContext(ctx).
+		Where("retry_at <= ?", time.Now()).
+		Order("retry_at ASC").
+		Limit(50).
+		Find(&due).Error
+	if err != nil {
+		return fmt.Errorf("failed to get due payment retries: %w", err)
+	}
+
+	for i := range due {
+		if err := s.resumePayment(ctx, &due[i]); err != nil {
+			s.logger.WithError(err).WithField("payment_id", due[i].PaymentID).Error("Failed to retry payment")
+		}
+	}
+	return nil
+}
+
+// resumePayment retries a payment from its failed attempt. The retry is
+// claimed by clearing the attempt's retry_at, so each is run once.
+func (s *PaymentService) resumePayment(ctx context.Context, failed *models.PaymentAttempt) error {
+	var payment models.Payment
+	var intentEvent *PaymentIntentEvent
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		claim := tx.Model(&models.PaymentAttempt{}).
+			Where("id = ? AND retry_at IS NOT NULL", failed.ID).
+			Update("retry_at", nil)
+		if claim.Error != nil {
+			return fmt.Errorf("failed to claim payment retry: %w", claim.Error)
+		}
+		if claim.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Preload("PaymentIntent").Where("id = ?", failed.PaymentID).First(&payment).Error; err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		if payment.Status != models.PaymentStatusProcessing || payment.PaymentIntent == nil {
+			return nil
+		}
+		intent := payment.PaymentIntent
+
+		rail, err := s.rails.RailByName(failed.Rail)
+		if err != nil {
+			return err
+		}
+		req, err := s.attemptRequest(ctx, &payment, intent, failed)
+		if err != nil {
+			return err
+		}
+		resp, err := s.runAttempts(ctx, tx, &payment, intent.MerchantID, rail, req)
+		if err != nil {
+			if resp == nil {
+				return err
+			}
+			s.logger.WithError(err).WithField("payment_id", payment.ID).Error("Rail payment processing failed")
+		}
+		intentEvent, err = s.settlePayment(ctx, tx, &payment, intent, resp)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if intentEvent != nil {
+		s.emitPaymentEvents(payment.PaymentIntent.MerchantID, &payment, intentEvent)
+	}
+	return nil
+}
@@ -2,6 +2,14 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,314 +17,413 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
 
 	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/money"
+	pb "github.com/suuupra/payments/proto/upi_core"
 )
 
-// MockUPIClient mocks the UPI client for testing
-type MockUPIClient struct {
-	mock.Mock
+// testModels are the models the services persist
+var testModels = []interface{}{
+	&models.PaymentIntent{},
+	&models.PaymentIntentTransition{},
+	&models.Payment{},
+	&models.PaymentEvent{},
+	&models.Refund{},
+	&models.LedgerEntry{},
+	&models.LedgerAccount{},
+	&models.JournalEntry{},
+	&models.IdempotencyKey{},
+	&models.WebhookEndpoint{},
+	&models.WebhookDelivery{},
+	&models.MerchantRail{},
+	&models.MerchantRateLimit{},
+	&models.PaymentAttempt{},
+	&models.ThreeDSChallenge{},
+	&models.UPIDeadLetter{},
+	&models.RiskAssessment{},
+	&models.RiskRule{},
+	&models.MerchantAPIKey{},
+	&models.ReconciliationException{},
+	&models.PaymentMethod{},
+	&models.PaymentCommand{},
+	&models.Report{},
+	&models.OutboxEvent{},
 }
 
-func (m *MockUPIClient) ProcessPayment(ctx context.Context, req UPIPaymentRequest) (*UPIPaymentResponse, error) {
-	args := m.Called(ctx, req)
-	return args.Get(0).(*UPIPaymentResponse), args.Error(1)
+// setupTestDB opens a SQLite database with every model migrated. SQLite
+// stands in for Postgres: the models' gen_random_uuid() defaults are
+// dropped, as the services set IDs themselves, and their maps and string
+// slices, jsonb and text[] in Postgres, are stored as JSON.
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := filepath.Join(t.TempDir(), "payments.db") + "?_busy_timeout=5000&_journal_mode=WAL"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	for _, model := range testModels {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(model))
+		adaptSchemaToSQLite(stmt.Schema)
+	}
+	require.NoError(t, db.AutoMigrate(testModels...))
+	return db
 }
 
-func (m *MockUPIClient) ProcessRefund(ctx context.Context, req UPIRefundRequest) (*UPIRefundResponse, error) {
-	args := m.Called(ctx, req)
-	return args.Get(0).(*UPIRefundResponse), args.Error(1)
+// adaptSchemaToSQLite adapts a parsed model, in the database's schema
+// cache, to what SQLite supports
+func adaptSchemaToSQLite(s *schema.Schema) {
+	for _, field := range s.Fields {
+		if strings.Contains(field.DefaultValue, "(") {
+			field.HasDefaultValue, field.DefaultValue = false, ""
+		}
+		kind := field.FieldType.Kind()
+		if kind == reflect.Map || (kind == reflect.Slice && field.FieldType.Elem().Kind() == reflect.String) {
+			storeAsJSON(field)
+		}
+	}
+	kept := s.FieldsWithDefaultDBValue[:0]
+	for _, field := range s.FieldsWithDefaultDBValue {
+		if field.HasDefaultValue {
+			kept = append(kept, field)
+		}
+	}
+	s.FieldsWithDefaultDBValue = kept
 }
 
-func (m *MockUPIClient) CheckPaymentStatus(ctx context.Context, transactionID string) (*UPIPaymentResponse, error) {
-	args := m.Called(ctx, transactionID)
-	return args.Get(0).(*UPIPaymentResponse), args.Error(1)
+// storeAsJSON writes field as JSON text and reads it back from JSON
+func storeAsJSON(field *schema.Field) {
+	valueOf, set := field.ValueOf, field.Set
+	field.ValueOf = func(ctx context.Context, v reflect.Value) (interface{}, bool) {
+		value, zero := valueOf(ctx, v)
+		if zero {
+			return nil, true
+		}
+		encoded, _ := json.Marshal(value)
+		return string(encoded), false
+	}
+	field.NewValuePool = &sync.Pool{New: func() interface{} { return new(sql.NullString) }}
+	field.Set = func(ctx context.Context, v reflect.Value, src interface{}) error {
+		raw, ok := src.(*sql.NullString)
+		if !ok {
+			return set(ctx, v, src)
+		}
+		target := field.ReflectValueOf(ctx, v)
+		target.Set(reflect.Zero(field.FieldType))
+		if !raw.Valid {
+			return nil
+		}
+		return json.Unmarshal([]byte(raw.String), target.Addr().Interface())
+	}
 }
 
-func (m *MockUPIClient) ValidateVPA(ctx context.Context, vpa string) (bool, error) {
-	args := m.Called(ctx, vpa)
-	return args.Bool(0), args.Error(1)
+func testLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
 }
 
-func (m *MockUPIClient) Close() error {
-	args := m.Called()
-	return args.Error(0)
+// fakeUPICore answers the UPI Core calls the services make; the calls it
+// does not implement panic
+type fakeUPICore struct {
+	pb.UpiCoreClient
+	vpas        map[string]bool        // VPAs and whether they are active; others do not exist
+	settlements []*pb.SettlementRecord // Every bank's settlement report, on one page
 }
 
-// MockWebhookService mocks the webhook service for testing
-type MockWebhookService struct {
-	mock.Mock
+func (f *fakeUPICore) ResolveVPA(ctx context.Context, in *pb.ResolveVPARequest, opts ...grpc.CallOption) (*pb.ResolveVPAResponse, error) {
+	active, exists := f.vpas[in.Vpa]
+	return &pb.ResolveVPAResponse{Exists: exists, IsActive: active}, nil
 }
 
-func (m *MockWebhookService) TriggerWebhook(ctx context.Context, merchantID uuid.UUID, eventType string, data interface{}) {
-	m.Called(ctx, merchantID, eventType, data)
+func (f *fakeUPICore) GetSettlementReport(ctx context.Context, in *pb.SettlementReportRequest, opts ...grpc.CallOption) (*pb.SettlementReportResponse, error) {
+	return &pb.SettlementReportResponse{BankCode: in.BankCode, Records: f.settlements}, nil
 }
 
-func (m *MockWebhookService) CreateWebhookEndpoint(ctx context.Context, req CreateWebhookEndpointRequest) (*models.WebhookEndpoint, error) {
-	args := m.Called(ctx, req)
-	return args.Get(0).(*models.WebhookEndpoint), args.Error(1)
+// fakeRail is a capture rail that answers with the statuses it is set to
+// and records what it was sent
+type fakeRail struct {
+	name          string
+	paymentStatus string                    // Succeeded if empty; authorized when capturing manually
+	refundStatus  string                    // Succeeded if empty
+	refundResult  *RailRefundStatusResponse // What CheckRefundStatus answers
+
+	mu       sync.Mutex
+	payments []RailPaymentRequest
+	refunds  []RailRefundRequest
+	captures []RailCaptureRequest
+	voids    []RailCaptureRequest
 }
 
-func (m *MockWebhookService) GetWebhookEndpoints(ctx context.Context, merchantID uuid.UUID) ([]models.WebhookEndpoint, error) {
-	args := m.Called(ctx, merchantID)
-	return args.Get(0).([]models.WebhookEndpoint), args.Error(1)
+func (r *fakeRail) Name() string {
+	return r.name
 }
 
-func (m *MockWebhookService) UpdateWebhookEndpoint(ctx context.Context, id uuid.UUID, updates map[string]interface{}) (*models.WebhookEndpoint, error) {
-	args := m.Called(ctx, id, updates)
-	return args.Get(0).(*models.WebhookEndpoint), args.Error(1)
+func (r *fakeRail) ProcessPayment(ctx context.Context, req RailPaymentRequest) (*RailPaymentResponse, error) {
+	r.mu.Lock()
+	r.payments = append(r.payments, req)
+	r.mu.Unlock()
+
+	status := r.paymentStatus
+	if status == "" {
+		status = models.PaymentStatusSucceeded
+	}
+	if status == models.PaymentStatusSucceeded && req.CaptureManually {
+		status = models.PaymentStatusAuthorized
+	}
+	resp := &RailPaymentResponse{
+		Success:       status != models.PaymentStatusFailed,
+		TransactionID: "TXN_" + req.PaymentID.String()[:8],
+		Status:        status,
+		ProcessedAt:   time.Now(),
+	}
+	if !resp.Success {
+		code, message := "DECLINED", "declined by the fake rail"
+		resp.FailureCode, resp.FailureMessage = &code, &message
+	}
+	return resp, nil
 }
 
-func (m *MockWebhookService) DeleteWebhookEndpoint(ctx context.Context, id uuid.UUID) error {
-	args := m.Called(ctx, id)
-	return args.Error(0)
+func (r *fakeRail) ProcessRefund(ctx context.Context, req RailRefundRequest) (*RailRefundResponse, error) {
+	r.mu.Lock()
+	r.refunds = append(r.refunds, req)
+	r.mu.Unlock()
+
+	status := r.refundStatus
+	if status == "" {
+		status = models.RefundStatusSucceeded
+	}
+	resp := &RailRefundResponse{
+		Success:         status == models.RefundStatusSucceeded,
+		RefundReference: "RRN_" + req.RefundID.String()[:8],
+		Status:          status,
+		ProcessedAt:     time.Now(),
+	}
+	if status == models.RefundStatusFailed {
+		code, message := "REFUND_DECLINED", "refund declined by the fake rail"
+		resp.FailureCode, resp.FailureMessage = &code, &message
+	}
+	return resp, nil
 }
 
-func (m *MockWebhookService) Start() {
-	m.Called()
+func (r *fakeRail) CheckRefundStatus(ctx context.Context, refundReference string, refundID uuid.UUID) (*RailRefundStatusResponse, error) {
+	if r.refundResult == nil {
+		return nil, fmt.Errorf("refund %s unknown to the fake rail", refundReference)
+	}
+	return r.refundResult, nil
 }
 
-func (m *MockWebhookService) Stop() {
-	m.Called()
+func (r *fakeRail) CapturePayment(ctx context.Context, req RailCaptureRequest) (*RailPaymentResponse, error) {
+	r.mu.Lock()
+	r.captures = append(r.captures, req)
+	r.mu.Unlock()
+	return &RailPaymentResponse{Success: true, TransactionID: req.TransactionID, Status: models.PaymentStatusSucceeded, ProcessedAt: time.Now()}, nil
 }
 
-func setupTestDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	require.NoError(t, err)
+func (r *fakeRail) VoidPayment(ctx context.Context, req RailCaptureRequest) (*RailPaymentResponse, error) {
+	r.mu.Lock()
+	r.voids = append(r.voids, req)
+	r.mu.Unlock()
+	return &RailPaymentResponse{Success: true, TransactionID: req.TransactionID, Status: models.PaymentStatusCanceled, ProcessedAt: time.Now()}, nil
+}
 
-	// Auto-migrate test schemas
-	err = db.AutoMigrate(
-		&models.PaymentIntent{},
-		&models.Payment{},
-		&models.Refund{},
-		&models.LedgerEntry{},
-		&models.IdempotencyKey{},
-		&models.WebhookEndpoint{},
-		&models.WebhookDelivery{},
-		&models.RiskAssessment{},
-		&models.OutboxEvent{},
-	)
-	require.NoError(t, err)
+// testEnv is the payment and refund services over a test database, with
+// the card rail faked and UPI over a fake UPI Core
+type testEnv struct {
+	db        *gorm.DB
+	card      *fakeRail
+	upiCore   *fakeUPICore
+	upiClient *UPIClient
+	ledger    *LedgerService
+	webhooks  *WebhookService
+	payments  *PaymentService
+	refunds   *RefundService
+}
 
-	return db
+func newTestEnv(t *testing.T) *testEnv {
+	db := setupTestDB(t)
+	log := testLogger()
+
+	env := &testEnv{
+		db:      db,
+		card:    &fakeRail{name: RailCard},
+		upiCore: &fakeUPICore{vpas: map[string]bool{"payer@upi": true, "payee@upi": true}},
+	}
+	env.upiClient = &UPIClient{client: env.upiCore, logger: log}
+	rails := NewRailRouter(db, log, []string{RailUPI, RailCard}, NewUPIRail(env.upiClient), env.card)
+	env.ledger = NewLedgerService(db, log, 200)
+	env.webhooks = NewWebhookService(db, log, "whsec_test", 3, 5, WebhookDeliveryPolicy{})
+	risk := NewRiskService(db, log, 50, 75)
+	env.payments = NewPaymentService(db, log, env.upiClient, rails, nil, nil, nil, nil, nil, env.ledger, risk, env.webhooks)
+	env.refunds = NewRefundService(db, log, rails, env.ledger, env.webhooks, 30)
+	return env
+}
+
+// createIntent creates an intent for amount over paymentMethod, expiring
+// in expiresIn
+func (env *testEnv) createIntent(t *testing.T, amount, currency, paymentMethod string, expiresIn time.Duration) *models.PaymentIntent {
+	expiresAt := time.Now().Add(expiresIn)
+	intent := &models.PaymentIntent{
+		ID:            uuid.New(),
+		MerchantID:    uuid.New(),
+		Amount:        decimal.RequireFromString(amount),
+		Currency:      currency,
+		Description:   "Test payment",
+		Status:        models.PaymentIntentStatusRequiresConfirmation,
+		PaymentMethod: paymentMethod,
+		CaptureMethod: models.CaptureMethodAutomatic,
+		ExpiresAt:     &expiresAt,
+		Version:       1,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	require.NoError(t, env.db.Create(intent).Error)
+	return intent
+}
+
+// pay creates a card payment of a new intent for amount
+func (env *testEnv) pay(t *testing.T, amount, currency string) *models.Payment {
+	intent := env.createIntent(t, amount, currency, RailCard, 15*time.Minute)
+	payment, err := env.payments.CreatePayment(context.Background(), CreatePaymentRequest{
+		PaymentIntentID: intent.ID,
+		CardToken:       "tok_visa",
+		IPAddress:       "127.0.0.1",
+	})
+	require.NoError(t, err)
+	return payment
 }
 
 func TestPaymentService_CreatePaymentIntent(t *testing.T) {
-	db := setupTestDB(t)
-	logger := logrus.New()
-	
-	mockUPIClient := &MockUPIClient{}
-	mockWebhookService := &MockWebhookService{}
-	
-	ledgerService := NewLedgerService(db, logger)
-	riskService := NewRiskService(db, logger)
-	
-	service := NewPaymentService(db, logger, mockUPIClient, ledgerService, riskService, mockWebhookService)
+	env := newTestEnv(t)
 
 	merchantID := uuid.New()
-	amount := decimal.NewFromFloat(100.50)
-
-	req := CreatePaymentIntentRequest{
+	amount := decimal.RequireFromString("100.50")
+	intent, err := env.payments.CreatePaymentIntent(context.Background(), CreatePaymentIntentRequest{
 		MerchantID:    merchantID,
 		Amount:        amount,
 		Currency:      "INR",
 		Description:   "Test payment",
 		PaymentMethod: "upi",
-	}
-
-	// Mock webhook trigger
-	mockWebhookService.On("TriggerWebhook", mock.Anything, merchantID, "payment_intent.created", mock.Anything).Return()
-
-	ctx := context.Background()
-	intent, err := service.CreatePaymentIntent(ctx, req)
+	})
+	require.NoError(t, err)
 
-	assert.NoError(t, err)
-	assert.NotNil(t, intent)
 	assert.Equal(t, merchantID, intent.MerchantID)
-	assert.Equal(t, amount, intent.Amount)
+	assert.True(t, amount.Equal(intent.Amount))
 	assert.Equal(t, "INR", intent.Currency)
 	assert.Equal(t, "Test payment", intent.Description)
 	assert.Equal(t, "upi", intent.PaymentMethod)
-	assert.Equal(t, models.PaymentIntentStatusCreated, intent.Status)
+	assert.Equal(t, models.PaymentIntentStatusRequiresConfirmation, intent.Status)
 	assert.NotNil(t, intent.ExpiresAt)
 
-	mockWebhookService.AssertExpectations(t)
+	stored, err := env.payments.GetPaymentIntent(context.Background(), intent.ID)
+	require.NoError(t, err)
+	assert.True(t, amount.Equal(stored.Amount))
+	assert.Equal(t, models.PaymentIntentStatusRequiresConfirmation, stored.Status)
 }
 
 func TestPaymentService_CreatePayment_Success(t *testing.T) {
-	db := setupTestDB(t)
-	logger := logrus.New()
-	
-	mockUPIClient := &MockUPIClient{}
-	mockWebhookService := &MockWebhookService{}
-	
-	ledgerService := NewLedgerService(db, logger)
-	riskService := NewRiskService(db, logger)
-	
-	service := NewPaymentService(db, logger, mockUPIClient, ledgerService, riskService, mockWebhookService)
-
-	// Create a payment intent first
-	merchantID := uuid.New()
-	amount := decimal.NewFromFloat(100.50)
-	
-	intent := &models.PaymentIntent{
-		ID:            uuid.New(),
-		MerchantID:    merchantID,
-		Amount:        amount,
-		Currency:      "INR",
-		Description:   "Test payment",
-		Status:        models.PaymentIntentStatusCreated,
-		PaymentMethod: "upi",
-		ExpiresAt:     timePtr(time.Now().Add(15 * time.Minute)),
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-	}
-	
-	err := db.Create(intent).Error
-	require.NoError(t, err)
+	env := newTestEnv(t)
+	intent := env.createIntent(t, "100.50", "INR", "upi", 15*time.Minute)
 
-	// Mock UPI client responses
-	mockUPIClient.On("ValidateVPA", mock.Anything, "payer@upi").Return(true, nil)
-	mockUPIClient.On("ValidateVPA", mock.Anything, "payee@upi").Return(true, nil)
-	mockUPIClient.On("ProcessPayment", mock.Anything, mock.AnythingOfType("UPIPaymentRequest")).Return(&UPIPaymentResponse{
-		Success:       true,
-		TransactionID: "UPI_TEST_123",
-		Status:        models.PaymentStatusSucceeded,
-		ProcessedAt:   time.Now(),
-	}, nil)
-
-	// Mock webhook trigger
-	mockWebhookService.On("TriggerWebhook", mock.Anything, merchantID, "payment.succeeded", mock.Anything).Return()
+	upi := &fakeRail{name: RailUPI}
+	env.payments.rails = NewRailRouter(env.db, testLogger(), []string{RailUPI}, upi)
 
-	req := CreatePaymentRequest{
+	payment, err := env.payments.CreatePayment(context.Background(), CreatePaymentRequest{
 		PaymentIntentID: intent.ID,
 		PayerVPA:        "payer@upi",
 		PayeeVPA:        "payee@upi",
 		IPAddress:       "127.0.0.1",
 		UserAgent:       "Test-Agent",
-	}
-
-	ctx := context.Background()
-	payment, err := service.CreatePayment(ctx, req)
+	})
+	require.NoError(t, err)
 
-	assert.NoError(t, err)
-	assert.NotNil(t, payment)
 	assert.Equal(t, intent.ID, payment.PaymentIntentID)
-	assert.Equal(t, amount, payment.Amount)
+	assert.True(t, intent.Amount.Equal(payment.Amount))
 	assert.Equal(t, "INR", payment.Currency)
 	assert.Equal(t, models.PaymentStatusSucceeded, payment.Status)
-	assert.Equal(t, "UPI_TEST_123", payment.RailTransactionID)
+	assert.NotEmpty(t, payment.RailTransactionID)
 	assert.NotNil(t, payment.ProcessedAt)
 
-	mockUPIClient.AssertExpectations(t)
-	mockWebhookService.AssertExpectations(t)
+	require.Len(t, upi.payments, 1)
+	assert.Equal(t, "payer@upi", upi.payments[0].PayerVPA)
+	assert.Equal(t, "payee@upi", upi.payments[0].PayeeVPA)
+
+	stored, err := env.payments.GetPaymentIntent(context.Background(), intent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentIntentStatusSucceeded, stored.Status)
 }
 
 func TestPaymentService_CreatePayment_ExpiredIntent(t *testing.T) {
-	db := setupTestDB(t)
-	logger := logrus.New()
-	
-	mockUPIClient := &MockUPIClient{}
-	mockWebhookService := &MockWebhookService{}
-	
-	ledgerService := NewLedgerService(db, logger)
-	riskService := NewRiskService(db, logger)
-	
-	service := NewPaymentService(db, logger, mockUPIClient, ledgerService, riskService, mockWebhookService)
-
-	// Create an expired payment intent
-	merchantID := uuid.New()
-	amount := decimal.NewFromFloat(100.50)
-	
-	intent := &models.PaymentIntent{
-		ID:            uuid.New(),
-		MerchantID:    merchantID,
-		Amount:        amount,
-		Currency:      "INR",
-		Description:   "Test payment",
-		Status:        models.PaymentIntentStatusCreated,
-		PaymentMethod: "upi",
-		ExpiresAt:     timePtr(time.Now().Add(-1 * time.Minute)), // Expired
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-	}
-	
-	err := db.Create(intent).Error
-	require.NoError(t, err)
+	env := newTestEnv(t)
+	intent := env.createIntent(t, "100.50", "INR", "upi", -time.Minute)
 
-	req := CreatePaymentRequest{
+	payment, err := env.payments.CreatePayment(context.Background(), CreatePaymentRequest{
 		PaymentIntentID: intent.ID,
 		PayerVPA:        "payer@upi",
 		PayeeVPA:        "payee@upi",
 		IPAddress:       "127.0.0.1",
 		UserAgent:       "Test-Agent",
-	}
-
-	ctx := context.Background()
-	payment, err := service.CreatePayment(ctx, req)
-
-	assert.Error(t, err)
+	})
+	require.Error(t, err)
 	assert.Nil(t, payment)
 	assert.Contains(t, err.Error(), "expired")
-}
 
-func TestPaymentService_CreatePayment_InvalidVPA(t *testing.T) {
-	db := setupTestDB(t)
-	logger := logrus.New()
-	
-	mockUPIClient := &MockUPIClient{}
-	mockWebhookService := &MockWebhookService{}
-	
-	ledgerService := NewLedgerService(db, logger)
-	riskService := NewRiskService(db, logger)
-	
-	service := NewPaymentService(db, logger, mockUPIClient, ledgerService, riskService, mockWebhookService)
-
-	// Create a payment intent
-	merchantID := uuid.New()
-	amount := decimal.NewFromFloat(100.50)
-	
-	intent := &models.PaymentIntent{
-		ID:            uuid.New(),
-		MerchantID:    merchantID,
-		Amount:        amount,
-		Currency:      "INR",
-		Description:   "Test payment",
-		Status:        models.PaymentIntentStatusCreated,
-		PaymentMethod: "upi",
-		ExpiresAt:     timePtr(time.Now().Add(15 * time.Minute)),
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-	}
-	
-	err := db.Create(intent).Error
+	stored, err := env.payments.GetPaymentIntent(context.Background(), intent.ID)
 	require.NoError(t, err)
+	assert.Equal(t, models.PaymentIntentStatusCanceled, stored.Status)
+}
 
-	// Mock invalid VPA validation
-	mockUPIClient.On("ValidateVPA", mock.Anything, "invalid-vpa").Return(false, nil)
+func TestPaymentService_CreatePayment_InvalidVPA(t *testing.T) {
+	env := newTestEnv(t)
+	intent := env.createIntent(t, "100.50", "INR", "upi", 15*time.Minute)
 
-	req := CreatePaymentRequest{
+	payment, err := env.payments.CreatePayment(context.Background(), CreatePaymentRequest{
 		PaymentIntentID: intent.ID,
-		PayerVPA:        "invalid-vpa",
+		PayerVPA:        "unknown@upi",
 		PayeeVPA:        "payee@upi",
 		IPAddress:       "127.0.0.1",
 		UserAgent:       "Test-Agent",
-	}
-
-	ctx := context.Background()
-	payment, err := service.CreatePayment(ctx, req)
-
-	assert.Error(t, err)
+	})
+	require.Error(t, err)
 	assert.Nil(t, payment)
 	assert.Contains(t, err.Error(), "invalid payer VPA")
 
-	mockUPIClient.AssertExpectations(t)
+	var count int64
+	require.NoError(t, env.db.Model(&models.Payment{}).Where("payment_intent_id = ?", intent.ID).Count(&count).Error)
+	assert.Zero(t, count, "payment recorded for an invalid VPA")
 }
 
-func timePtr(t time.Time) *time.Time {
-	return &t
-}
\ No newline at end of file
+func TestPaymentService_AmountsMarshalAsMoney(t *testing.T) {
+	env := newTestEnv(t)
+	payment := env.pay(t, "12.3", "KWD")
+
+	data, err := json.Marshal(payment)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, "12.300", fields["amount"])
+	assert.Equal(t, "KWD", fields["currency"])
+	assert.Equal(t, 12300.0, fields["minor_units"])
+	assert.Nil(t, fields["authorized_minor_units"])
+
+	// The payment reads back as the money.Money it holds
+	var amount money.Money
+	require.NoError(t, json.Unmarshal(data, &amount))
+	want, err := payment.Money()
+	require.NoError(t, err)
+	assert.Equal(t, want, amount)
+
+	authorized := decimal.RequireFromString("15")
+	payment.AuthorizedAmount = &authorized
+	data, err = json.Marshal(payment)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"authorized_amount":"15.000","authorized_minor_units":15000`)
+}
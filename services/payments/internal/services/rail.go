@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// Payment rails
+const (
+	RailUPI        = "upi"
+	RailCard       = "card"
+	RailNetbanking = "netbanking"
+)
+
+var (
+	// ErrUnsupportedPaymentMethod is returned for a payment method no rail
+	// carries
+	ErrUnsupportedPaymentMethod = errors.New("unsupported payment method")
+	// ErrRailNotEnabled is returned when the merchant has not enabled the
+	// rail of a payment method
+	ErrRailNotEnabled = errors.New("payment rail not enabled for merchant")
+	// ErrRailUnavailable is returned for a rail the service is not
+	// configured with
+	ErrRailUnavailable = errors.New("payment rail not configured")
+)
+
+// paymentMethodRails maps payment methods to the rail that carries them
+var paymentMethodRails = map[string]string{
+	"upi":         RailUPI,
+	"card":        RailCard,
+	"credit_card": RailCard,
+	"debit_card":  RailCard,
+	"netbanking":  RailNetbanking,
+}
+
+// RailForPaymentMethod returns the rail that carries paymentMethod
+func RailForPaymentMethod(paymentMethod string) (string, bool) {
+	rail, ok := paymentMethodRails[strings.ToLower(paymentMethod)]
+	return rail, ok
+}
+
+// RailPaymentRequest is a payment sent to a rail. Each rail reads the
+// instrument fields it needs.
+type RailPaymentRequest struct {
+	PaymentID      uuid.UUID
+	Amount         decimal.Decimal
+	Currency       string
+	Description    string
+	MerchantID     string
+	TransactionRef string
+	PayerVPA       string       // UPI
+	PayeeVPA       string       // UPI
+	CardToken      string       // Card
+	Card           *CardDetails // Card, from a saved payment method
+	BankCode       string       // Netbanking
+
+	// PaymentMethodToken is the saved payment method the instrument was
+	// read from, if any
+	PaymentMethodToken string
+
+	// ThreeDS asks a card rail to authenticate the payer before
+	// authorising. ReturnURL is where the rail sends the payer back to
+	// after the challenge.
+	ThreeDS   bool
+	ReturnURL string
+
+	// CaptureManually asks the rail to only authorize the payment, for it
+	// to be captured or voided later. Only a CaptureRail can.
+	CaptureManually bool
+}
+
+// RailPaymentResponse is a rail's answer to a payment. Status is
+// PaymentStatusPending while the payer still has to act, e.g. at RedirectURL,
+// and PaymentStatusRequiresAction when the payer has to authenticate at
+// RedirectURL before the rail authorises the payment. A payment authorized
+// for manual capture succeeds with PaymentStatusAuthorized.
+type RailPaymentResponse struct {
+	Success        bool
+	TransactionID  string
+	Status         string
+	FailureCode    *string
+	FailureMessage *string
+	RedirectURL    string
+	ProcessedAt    time.Time
+}
+
+// RailCaptureRequest captures or voids a payment the rail authorized
+type RailCaptureRequest struct {
+	PaymentID     uuid.UUID
+	TransactionID string          // The rail's ID of the authorized payment
+	Amount        decimal.Decimal // Up to the authorized amount; unused to void
+	Currency      string
+}
+
+// RailRefundRequest is a refund of a payment sent to the rail it was made on
+type RailRefundRequest struct {
+	RefundID          uuid.UUID
+	OriginalPaymentID uuid.UUID
+	TransactionID     string // The rail's ID of the original payment
+	Amount            decimal.Decimal
+	Currency          string
+	Reason            string
+}
+
+// RailRefundResponse is a rail's answer to a refund
+type RailRefundResponse struct {
+	Success         bool
+	RefundReference string
+	Status          string
+	FailureCode     *string
+	FailureMessage  *string
+	ProcessedAt     time.Time
+}
+
+// RailRefundStatusResponse is a rail's current status of a refund
+type RailRefundStatusResponse struct {
+	Status         string
+	FailureCode    *string
+	FailureMessage *string
+	ProcessedAt    *time.Time
+}
+
+// PaymentRail moves money over one rail. Rail failures are reported in
+// the response; errors are for requests that never reached the rail.
+type PaymentRail interface {
+	Name() string
+	ProcessPayment(ctx context.Context, req RailPaymentRequest) (*RailPaymentResponse, error)
+	ProcessRefund(ctx context.Context, req RailRefundRequest) (*RailRefundResponse, error)
+	CheckRefundStatus(ctx context.Context, refundReference string, refundID uuid.UUID) (*RailRefundStatusResponse, error)
+}
+
+// ChallengeResult is what the payer's authentication page sent back to the
+// callback after a step-up challenge
+type ChallengeResult struct {
+	PaymentID     uuid.UUID
+	TransactionID string // The rail's ID of the payment
+	Payload       map[string]string
+}
+
+// StepUpRail is a rail that can ask the payer to authenticate, e.g. with
+// 3-D Secure, before authorising a payment. CompleteChallenge verifies the
+// challenge's result and answers the payment as ProcessPayment does.
+type StepUpRail interface {
+	PaymentRail
+	CompleteChallenge(ctx context.Context, result ChallengeResult) (*RailPaymentResponse, error)
+}
+
+// CaptureRail is a rail that can authorize a payment without capturing it.
+// CapturePayment charges some or all of the authorized amount and answers
+// it succeeded; VoidPayment releases the hold and answers it canceled.
+type CaptureRail interface {
+	PaymentRail
+	CapturePayment(ctx context.Context, req RailCaptureRequest) (*RailPaymentResponse, error)
+	VoidPayment(ctx context.Context, req RailCaptureRequest) (*RailPaymentResponse, error)
+}
+
+// RailRouter picks the rail of a payment from its payment method. Merchants
+// use the default rails unless they enabled or disabled rails of their own.
+type RailRouter struct {
+	db             *gorm.DB
+	logger         *logrus.Logger
+	rails          map[string]PaymentRail
+	defaultEnabled map[string]bool
+}
+
+// NewRailRouter creates a router over rails, with defaultRails enabled for
+// merchants without settings of their own
+func NewRailRouter(db *gorm.DB, logger *logrus.Logger, defaultRails []string, rails ...PaymentRail) *RailRouter {
+	r := &RailRouter{
+		db:             db,
+		logger:         logger,
+		rails:          make(map[string]PaymentRail, len(rails)),
+		defaultEnabled: make(map[string]bool, len(defaultRails)),
+	}
+	for _, rail := range rails {
+		r.rails[rail.Name()] = rail
+	}
+	for _, rail := range defaultRails {
+		if rail = strings.TrimSpace(rail); rail != "" {
+			r.defaultEnabled[rail] = true
+		}
+	}
+	return r
+}
+
+// Rail returns the rail that carries paymentMethod, whether or not any
+// merchant has it enabled. Refunds go back over the payment's rail.
+func (r *RailRouter) Rail(paymentMethod string) (PaymentRail, error) {
+	name, ok := RailForPaymentMethod(paymentMethod)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedPaymentMethod, paymentMethod)
+	}
+	return r.RailByName(name)
+}
+
+// RailByName returns the rail called name
+func (r *RailRouter) RailByName(name string) (PaymentRail, error) {
+	rail, ok := r.rails[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRailUnavailable, name)
+	}
+	return rail, nil
+}
+
+// Route returns the rail a merchant's payment with paymentMethod goes over
+func (r *RailRouter) Route(ctx context.Context, merchantID uuid.UUID, paymentMethod string) (PaymentRail, error) {
+	rail, err := r.Rail(paymentMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := r.MerchantRails(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled[rail.Name()] {
+		return nil, fmt.Errorf("%w: %s", ErrRailNotEnabled, rail.Name())
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"merchant_id":    merchantID,
+		"payment_method": paymentMethod,
+		"rail":           rail.Name(),
+	}).Debug("Payment routed")
+	return rail, nil
+}
+
+// MerchantRails returns which rails a merchant has enabled: the default
+// rails, overridden by the merchant's own settings
+func (r *RailRouter) MerchantRails(ctx context.Context, merchantID uuid.UUID) (map[string]bool, error) {
+	var settings []models.MerchantRail
+	if err := r.db.WithContext(ctx).Where("merchant_id = ?", merchantID).Find(&settings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get merchant rails: %w", err)
+	}
+
+	enabled := make(map[string]bool, len(r.rails))
+	for name := range r.rails {
+		enabled[name] = r.defaultEnabled[name]
+	}
+	for _, setting := range settings {
+		if _, ok := r.rails[setting.Rail]; ok {
+			enabled[setting.Rail] = setting.Enabled
+		}
+	}
+	return enabled, nil
+}
+
+// SetMerchantRail enables or disables a rail for a merchant
+func (r *RailRouter) SetMerchantRail(ctx context.Context, merchantID uuid.UUID, rail string, enabled bool) (*models.MerchantRail, error) {
+	if _, ok := r.rails[rail]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRailUnavailable, rail)
+	}
+
+	setting := &models.MerchantRail{
+		ID:         uuid.New(),
+		MerchantID: merchantID,
+		Rail:       rail,
+		Enabled:    enabled,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "merchant_id"}, {Name: "rail"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(setting).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update merchant rail: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"merchant_id": merchantID,
+		"rail":        rail,
+		"enabled":     enabled,
+	}).Info("Merchant rail updated")
+	return setting, nil
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// gatewayClient calls the JSON API of a card or netbanking gateway:
+// POST /v1/payments, POST /v1/payments/{id}/authenticate, /capture and
+// /void, POST /v1/refunds and GET /v1/refunds/{reference}, authenticated with a bearer API key. Requests carry the payment or refund
+// ID as their Idempotency-Key, so retried calls are not charged twice.
+type gatewayClient struct {
+	rail    string
+	baseURL string
+	apiKey  string
+	client  *http.Client
+	logger  *logrus.Logger
+}
+
+func newGatewayClient(rail, baseURL, apiKey string, timeoutSeconds int, logger *logrus.Logger) *gatewayClient {
+	return &gatewayClient{
+		rail:    rail,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: time.Duration(timeoutSeconds) * time.Second},
+		logger:  logger,
+	}
+}
+
+// gatewayResponse is the gateway's representation of a payment or refund
+type gatewayResponse struct {
+	ID             string     `json:"id"`
+	Status         string     `json:"status"` // succeeded, authorized, pending, requires_action, canceled or failed
+	FailureCode    string     `json:"failure_code"`
+	FailureMessage string     `json:"failure_message"`
+	RedirectURL    string     `json:"redirect_url"`
+	ProcessedAt    *time.Time `json:"processed_at"`
+}
+
+func (c *gatewayClient) do(ctx context.Context, method, path, idempotencyKey string, body interface{}) (*gatewayResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s gateway request: %w", c.rail, err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s gateway request: %w", c.rail, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s gateway request failed: %w", c.rail, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s gateway response: %w", c.rail, err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s gateway returned %d: %s", c.rail, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var out gatewayResponse
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s gateway response: %w", c.rail, err)
+	}
+	return &out, nil
+}
+
+// processPayment sends a payment with the rail's instrument fields
+func (c *gatewayClient) processPayment(ctx context.Context, req RailPaymentRequest, instrument map[string]string) (*RailPaymentResponse, error) {
+	log := c.logger.WithFields(logrus.Fields{
+		"rail":       c.rail,
+		"payment_id": req.PaymentID,
+		"amount":     req.Amount.String(),
+	})
+	log.Info("Processing gateway payment")
+
+	body := map[string]interface{}{
+		"reference":    req.PaymentID.String(),
+		"amount_paisa": minorUnits(req.Amount, req.Currency), // In the currency's minor unit
+		"currency":     req.Currency,
+		"description":  req.Description,
+		"merchant_id":  req.MerchantID,
+	}
+	for field, value := range instrument {
+		body[field] = value
+	}
+	if req.CaptureManually {
+		body["capture_method"] = "manual"
+	}
+
+	return c.sendPayment(ctx, log, "/v1/payments", req.PaymentID.String(), body)
+}
+
+// sendPayment posts a payment request to path and maps the gateway's answer.
+// Requests that fail to reach the gateway are answered as failed payments.
+func (c *gatewayClient) sendPayment(ctx context.Context, log *logrus.Entry, path, idempotencyKey string, body interface{}) (*RailPaymentResponse, error) {
+	gwResp, err := c.do(ctx, http.MethodPost, path, idempotencyKey, body)
+	if err != nil {
+		log.WithError(err).Error("Failed to call payment gateway")
+		return &RailPaymentResponse{
+			Success:        false,
+			Status:         models.PaymentStatusFailed,
+			FailureCode:    func() *string { s := strings.ToUpper(c.rail) + "_SERVICE_ERROR"; return &s }(),
+			FailureMessage: func() *string { s := err.Error(); return &s }(),
+		}, nil
+	}
+
+	response := &RailPaymentResponse{
+		TransactionID: gwResp.ID,
+		RedirectURL:   gwResp.RedirectURL,
+		ProcessedAt:   time.Now(),
+	}
+	if gwResp.ProcessedAt != nil {
+		response.ProcessedAt = *gwResp.ProcessedAt
+	}
+
+	switch gwResp.Status {
+	case "succeeded":
+		response.Success = true
+		response.Status = models.PaymentStatusSucceeded
+	case "authorized":
+		response.Success = true
+		response.Status = models.PaymentStatusAuthorized
+	case "pending":
+		response.Status = models.PaymentStatusPending
+	case "requires_action":
+		response.Status = models.PaymentStatusRequiresAction
+	case "canceled":
+		response.Status = models.PaymentStatusCanceled
+	default:
+		response.Status = models.PaymentStatusFailed
+		if gwResp.FailureCode != "" {
+			response.FailureCode = &gwResp.FailureCode
+			response.FailureMessage = &gwResp.FailureMessage
+		}
+	}
+
+	log.WithFields(logrus.Fields{
+		"transaction_id": response.TransactionID,
+		"status":         response.Status,
+	}).Info("Gateway payment processed")
+	return response, nil
+}
+
+// ProcessRefund refunds a gateway payment
+func (c *gatewayClient) ProcessRefund(ctx context.Context, req RailRefundRequest) (*RailRefundResponse, error) {
+	log := c.logger.WithFields(logrus.Fields{
+		"rail":           c.rail,
+		"refund_id":      req.RefundID,
+		"transaction_id": req.TransactionID,
+		"amount":         req.Amount.String(),
+	})
+	log.Info("Processing gateway refund")
+
+	gwResp, err := c.do(ctx, http.MethodPost, "/v1/refunds", req.RefundID.String(), map[string]interface{}{
+		"reference":    req.RefundID.String(),
+		"payment_id":   req.TransactionID,
+		"amount_paisa": minorUnits(req.Amount, req.Currency),
+		"currency":     req.Currency,
+		"reason":       req.Reason,
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to call payment gateway for refund")
+		return &RailRefundResponse{
+			Success:        false,
+			Status:         models.RefundStatusFailed,
+			FailureCode:    func() *string { s := strings.ToUpper(c.rail) + "_REFUND_SERVICE_ERROR"; return &s }(),
+			FailureMessage: func() *string { s := err.Error(); return &s }(),
+			ProcessedAt:    time.Now(),
+		}, nil
+	}
+
+	response := &RailRefundResponse{
+		RefundReference: gwResp.ID,
+		ProcessedAt:     time.Now(),
+	}
+	if gwResp.ProcessedAt != nil {
+		response.ProcessedAt = *gwResp.ProcessedAt
+	}
+	response.Status = gatewayRefundStatus(gwResp)
+	response.Success = response.Status != models.RefundStatusFailed
+	if response.Status == models.RefundStatusFailed && gwResp.FailureCode != "" {
+		response.FailureCode = &gwResp.FailureCode
+		response.FailureMessage = &gwResp.FailureMessage
+	}
+
+	log.WithFields(logrus.Fields{
+		"refund_reference": response.RefundReference,
+		"status":           response.Status,
+	}).Info("Gateway refund processed")
+	return response, nil
+}
+
+// CheckRefundStatus returns the gateway's status of a refund
+func (c *gatewayClient) CheckRefundStatus(ctx context.Context, refundReference string, refundID uuid.UUID) (*RailRefundStatusResponse, error) {
+	gwResp, err := c.do(ctx, http.MethodGet, "/v1/refunds/"+url.PathEscape(refundReference), "", nil)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"rail":      c.rail,
+			"refund_id": refundID,
+		}).Error("Failed to check refund status with payment gateway")
+		return nil, err
+	}
+
+	response := &RailRefundStatusResponse{
+		Status:      gatewayRefundStatus(gwResp),
+		ProcessedAt: gwResp.ProcessedAt,
+	}
+	if response.Status == models.RefundStatusFailed && gwResp.FailureCode != "" {
+		response.FailureCode = &gwResp.FailureCode
+		response.FailureMessage = &gwResp.FailureMessage
+	}
+	return response, nil
+}
+
+func gatewayRefundStatus(gwResp *gatewayResponse) string {
+	switch gwResp.Status {
+	case "succeeded":
+		return models.RefundStatusSucceeded
+	case "pending":
+		return models.RefundStatusPending
+	default:
+		return models.RefundStatusFailed
+	}
+}
+
+// CardRail carries card payments over a card gateway, charging tokenized
+// cards
+type CardRail struct {
+	*gatewayClient
+}
+
+// NewCardRail creates the card rail over the gateway at baseURL
+func NewCardRail(baseURL, apiKey string, timeoutSeconds int, logger *logrus.Logger) *CardRail {
+	return &CardRail{gatewayClient: newGatewayClient(RailCard, baseURL, apiKey, timeoutSeconds, logger)}
+}
+
+// Name returns RailCard
+func (r *CardRail) Name() string {
+	return RailCard
+}
+
+// ProcessPayment charges the request's card token, or the card of its
+// saved payment method. The gateway answers requires_action when the payer
+// has to authenticate first, which it always does for ThreeDS requests.
+func (r *CardRail) ProcessPayment(ctx context.Context, req RailPaymentRequest) (*RailPaymentResponse, error) {
+	var instrument map[string]string
+	switch {
+	case req.Card != nil:
+		instrument = map[string]string{
+			"card_number":      req.Card.Number,
+			"card_exp_month":   strconv.Itoa(req.Card.ExpMonth),
+			"card_exp_year":    strconv.Itoa(req.Card.ExpYear),
+			"card_holder_name": req.Card.HolderName,
+		}
+	case req.CardToken != "":
+		instrument = map[string]string{"card_token": req.CardToken}
+	default:
+		return nil, fmt.Errorf("card token is required for card payments")
+	}
+	if req.ThreeDS {
+		instrument["three_ds"] = "required"
+	}
+	if req.ReturnURL != "" {
+		instrument["return_url"] = req.ReturnURL
+	}
+	return r.processPayment(ctx, req, instrument)
+}
+
+// CompleteChallenge sends the payer's 3-D Secure result to the gateway,
+// which verifies it and authorises or declines the payment
+func (r *CardRail) CompleteChallenge(ctx context.Context, result ChallengeResult) (*RailPaymentResponse, error) {
+	if result.TransactionID == "" {
+		return nil, fmt.Errorf("transaction ID is required to complete a challenge")
+	}
+	log := r.logger.WithFields(logrus.Fields{
+		"rail":           r.rail,
+		"payment_id":     result.PaymentID,
+		"transaction_id": result.TransactionID,
+	})
+	log.Info("Completing gateway 3-D Secure challenge")
+
+	path := "/v1/payments/" + url.PathEscape(result.TransactionID) + "/authenticate"
+	return r.sendPayment(ctx, log, path, result.TransactionID+"-authenticate", map[string]interface{}{
+		"reference":      result.PaymentID.String(),
+		"authentication": result.Payload,
+	})
+}
+
+// CapturePayment captures the request's amount of a payment the gateway
+// authorized
+func (r *CardRail) CapturePayment(ctx context.Context, req RailCaptureRequest) (*RailPaymentResponse, error) {
+	if req.TransactionID == "" {
+		return nil, fmt.Errorf("transaction ID is required to capture a payment")
+	}
+	log := r.logger.WithFields(logrus.Fields{
+		"rail":           r.rail,
+		"payment_id":     req.PaymentID,
+		"transaction_id": req.TransactionID,
+		"amount":         req.Amount.String(),
+	})
+	log.Info("Capturing gateway payment")
+
+	path := "/v1/payments/" + url.PathEscape(req.TransactionID) + "/capture"
+	return r.sendPayment(ctx, log, path, req.TransactionID+"-capture", map[string]interface{}{
+		"reference":    req.PaymentID.String(),
+		"amount_paisa": minorUnits(req.Amount, req.Currency),
+		"currency":     req.Currency,
+	})
+}
+
+// VoidPayment releases the hold of a payment the gateway authorized
+func (r *CardRail) VoidPayment(ctx context.Context, req RailCaptureRequest) (*RailPaymentResponse, error) {
+	if req.TransactionID == "" {
+		return nil, fmt.Errorf("transaction ID is required to void a payment")
+	}
+	log := r.logger.WithFields(logrus.Fields{
+		"rail":           r.rail,
+		"payment_id":     req.PaymentID,
+		"transaction_id": req.TransactionID,
+	})
+	log.Info("Voiding gateway payment")
+
+	path := "/v1/payments/" + url.PathEscape(req.TransactionID) + "/void"
+	return r.sendPayment(ctx, log, path, req.TransactionID+"-void", map[string]interface{}{
+		"reference": req.PaymentID.String(),
+	})
+}
+
+// NetbankingRail carries netbanking payments over a netbanking aggregator.
+// Payments are pending until the payer authorizes them at their bank's
+// redirect URL.
+type NetbankingRail struct {
+	*gatewayClient
+}
+
+// NewNetbankingRail creates the netbanking rail over the aggregator at
+// baseURL
+func NewNetbankingRail(baseURL, apiKey string, timeoutSeconds int, logger *logrus.Logger) *NetbankingRail {
+	return &NetbankingRail{gatewayClient: newGatewayClient(RailNetbanking, baseURL, apiKey, timeoutSeconds, logger)}
+}
+
+// Name returns RailNetbanking
+func (r *NetbankingRail) Name() string {
+	return RailNetbanking
+}
+
+// ProcessPayment starts a payment from an account at the request's bank
+func (r *NetbankingRail) ProcessPayment(ctx context.Context, req RailPaymentRequest) (*RailPaymentResponse, error) {
+	if req.BankCode == "" {
+		return nil, fmt.Errorf("bank code is required for netbanking payments")
+	}
+	return r.processPayment(ctx, req, map[string]string{"bank_code": req.BankCode})
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// UPIRail carries UPI payments over UPI Core
+type UPIRail struct {
+	client *UPIClient
+}
+
+// NewUPIRail creates the UPI rail over client
+func NewUPIRail(client *UPIClient) *UPIRail {
+	return &UPIRail{client: client}
+}
+
+// Name returns RailUPI
+func (r *UPIRail) Name() string {
+	return RailUPI
+}
+
+// ProcessPayment sends a payment between the request's VPAs
+func (r *UPIRail) ProcessPayment(ctx context.Context, req RailPaymentRequest) (*RailPaymentResponse, error) {
+	resp, err := r.client.ProcessPayment(ctx, UPIPaymentRequest{
+		PaymentID:      req.PaymentID,
+		PayerVPA:       req.PayerVPA,
+		PayeeVPA:       req.PayeeVPA,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		Description:    req.Description,
+		MerchantID:     req.MerchantID,
+		TransactionRef: req.TransactionRef,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RailPaymentResponse{
+		Success:        resp.Success,
+		TransactionID:  resp.TransactionID,
+		Status:         resp.Status,
+		FailureCode:    resp.FailureCode,
+		FailureMessage: resp.FailureMessage,
+		ProcessedAt:    resp.ProcessedAt,
+	}, nil
+}
+
+// ProcessRefund reverses the original UPI transaction
+func (r *UPIRail) ProcessRefund(ctx context.Context, req RailRefundRequest) (*RailRefundResponse, error) {
+	resp, err := r.client.ProcessRefund(ctx, UPIRefundRequest{
+		RefundID:          req.RefundID,
+		OriginalPaymentID: req.OriginalPaymentID,
+		TransactionID:     req.TransactionID,
+		Amount:            req.Amount,
+		Currency:          req.Currency,
+		Reason:            req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RailRefundResponse{
+		Success:         resp.Success,
+		RefundReference: resp.RefundReference,
+		Status:          resp.Status,
+		FailureCode:     resp.FailureCode,
+		FailureMessage:  resp.FailureMessage,
+		ProcessedAt:     resp.ProcessedAt,
+	}, nil
+}
+
+// CheckRefundStatus returns the status of the reversal
+func (r *UPIRail) CheckRefundStatus(ctx context.Context, refundReference string, refundID uuid.UUID) (*RailRefundStatusResponse, error) {
+	resp, err := r.client.CheckRefundStatus(ctx, UPIRefundStatusRequest{
+		RefundReference: refundReference,
+		RefundID:        refundID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RailRefundStatusResponse{
+		Status:         resp.Status,
+		FailureCode:    resp.FailureCode,
+		FailureMessage: resp.FailureMessage,
+		ProcessedAt:    resp.ProcessedAt,
+	}, nil
+}
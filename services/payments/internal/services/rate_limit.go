@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// Rate limit budgets. Reads and writes are limited separately, so a
+// merchant polling payments cannot starve its own payment creation.
+const (
+	RateLimitBudgetRead  = "read"
+	RateLimitBudgetWrite = "write"
+)
+
+// Rate limit scopes, saying which limit throttled a request
+const (
+	RateLimitScopeClient = "client"
+	RateLimitScopeGlobal = "global"
+)
+
+const (
+	// rateLimitWindow is the sliding window budgets are counted over
+	rateLimitWindow = time.Minute
+	// rateLimitCacheTTL is how long a merchant's limits are cached, so
+	// every request does not read them from the database
+	rateLimitCacheTTL = time.Minute
+)
+
+// rateLimitScript counts a request against a client's sliding window and,
+// with a global limit, the global one, adding it to both only if neither
+// is full. It returns whether the request is allowed, the scope that
+// decided, the requests counted in that scope's window and the
+// milliseconds until its oldest request leaves the window.
+var rateLimitScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local function count(key)
+	redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+	return redis.call('ZCARD', key)
+end
+local function reset(key)
+	local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+	if oldest[2] then
+		return tonumber(oldest[2]) + window - now
+	end
+	return window
+end
+local function add(key)
+	redis.call('ZADD', key, now, ARGV[5])
+	redis.call('PEXPIRE', key, window)
+end
+
+local clientCount = count(KEYS[1])
+if clientCount >= tonumber(ARGV[3]) then
+	return {0, 1, clientCount, reset(KEYS[1])}
+end
+local globalLimit = tonumber(ARGV[4])
+if globalLimit > 0 then
+	local globalCount = count(KEYS[2])
+	if globalCount >= globalLimit then
+		return {0, 2, globalCount, reset(KEYS[2])}
+	end
+	add(KEYS[2])
+end
+add(KEYS[1])
+return {1, 1, clientCount + 1, reset(KEYS[1])}
+`)
+
+// RateLimitPolicy holds the default budgets, per minute. A merchant's own
+// limits replace the defaults; GlobalPerMinute of 0 is no global limit.
+type RateLimitPolicy struct {
+	ReadsPerMinute  int
+	WritesPerMinute int
+	GlobalPerMinute int
+}
+
+// RateLimitDecision is the rate limiter's answer to a request
+type RateLimitDecision struct {
+	Allowed   bool
+	Scope     string // The limit that decided
+	Limit     int
+	Remaining int
+	Reset     time.Duration // Until the oldest counted request leaves the window
+}
+
+// MerchantRateLimits are a merchant's budgets, per minute
+type MerchantRateLimits struct {
+	MerchantID      uuid.UUID `json:"merchant_id"`
+	ReadsPerMinute  int       `json:"reads_per_minute"`
+	WritesPerMinute int       `json:"writes_per_minute"`
+	Custom          bool      `json:"custom"` // Whether the merchant has limits of its own
+}
+
+type cachedRateLimits struct {
+	limits    MerchantRateLimits
+	expiresAt time.Time
+}
+
+// RateLimitService throttles API clients over sliding windows kept in
+// Redis. Merchants get the policy's budgets unless they have limits of
+// their own in the database.
+type RateLimitService struct {
+	db     *gorm.DB
+	redis  *redis.Client
+	logger *logrus.Logger
+	policy RateLimitPolicy
+
+	mu     sync.Mutex
+	limits map[uuid.UUID]cachedRateLimits
+}
+
+// NewRateLimitService creates a new rate limit service
+func NewRateLimitService(db *gorm.DB, redisClient *redis.Client, logger *logrus.Logger, policy RateLimitPolicy) *RateLimitService {
+	return &RateLimitService{
+		db:     db,
+		redis:  redisClient,
+		logger: logger,
+		policy: policy,
+		limits: make(map[uuid.UUID]cachedRateLimits),
+	}
+}
+
+// Allow counts a request by client against its budget and the global
+// one. client is the merchant ID of an authenticated merchant, whose own
+// limits apply, or any other identifier, e.g. an IP, which gets the
+// policy's.
+func (s *RateLimitService) Allow(ctx context.Context, client, budget string) (*RateLimitDecision, error) {
+	limits := MerchantRateLimits{
+		ReadsPerMinute:  s.policy.ReadsPerMinute,
+		WritesPerMinute: s.policy.WritesPerMinute,
+	}
+	if merchantID, err := uuid.Parse(client); err == nil {
+		merchantLimits, err := s.merchantLimits(ctx, merchantID)
+		if err != nil {
+			return nil, err
+		}
+		limits = *merchantLimits
+	}
+	limit := limits.ReadsPerMinute
+	if budget == RateLimitBudgetWrite {
+		limit = limits.WritesPerMinute
+	}
+
+	now := time.Now().UnixMilli()
+	result, err := rateLimitScript.Run(ctx, s.redis,
+		[]string{"rate_limit:" + budget + ":" + client, "rate_limit:global"},
+		now, rateLimitWindow.Milliseconds(), limit, s.policy.GlobalPerMinute, uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count request: %w", err)
+	}
+
+	decision := &RateLimitDecision{
+		Allowed: result[0] == 1,
+		Scope:   RateLimitScopeClient,
+		Limit:   limit,
+		Reset:   time.Duration(result[3]) * time.Millisecond,
+	}
+	if result[1] == 2 {
+		decision.Scope = RateLimitScopeGlobal
+		decision.Limit = s.policy.GlobalPerMinute
+	}
+	if decision.Remaining = decision.Limit - int(result[2]); decision.Remaining < 0 || !decision.Allowed {
+		decision.Remaining = 0
+	}
+	return decision, nil
+}
+
+// merchantLimits returns a merchant's budgets, from the cache if fresh
+func (s *RateLimitService) merchantLimits(ctx context.Context, merchantID uuid.UUID) (*MerchantRateLimits, error) {
+	s.mu.Lock()
+	cached, ok := s.limits[merchantID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return &cached.limits, nil
+	}
+
+	limits, err := s.GetMerchantRateLimits(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	s.cache(*limits)
+	return limits, nil
+}
+
+func (s *RateLimitService) cache(limits MerchantRateLimits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits[limits.MerchantID] = cachedRateLimits{limits: limits, expiresAt: time.Now().Add(rateLimitCacheTTL)}
+}
+
+// GetMerchantRateLimits returns a merchant's budgets: its own where it has
+// them, the policy's otherwise
+func (s *RateLimitService) GetMerchantRateLimits(ctx context.Context, merchantID uuid.UUID) (*MerchantRateLimits, error) {
+	limits := &MerchantRateLimits{
+		MerchantID:      merchantID,
+		ReadsPerMinute:  s.policy.ReadsPerMinute,
+		WritesPerMinute: s.policy.WritesPerMinute,
+	}
+
+	var override models.MerchantRateLimit
+	err := s.db.WithContext(ctx).Where("merchant_id = ?", merchantID).First(&override).Error
+	if err == gorm.ErrRecordNotFound {
+		return limits, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant rate limits: %w", err)
+	}
+
+	if override.ReadsPerMinute != nil {
+		limits.ReadsPerMinute = *override.ReadsPerMinute
+		limits.Custom = true
+	}
+	if override.WritesPerMinute != nil {
+		limits.WritesPerMinute = *override.WritesPerMinute
+		limits.Custom = true
+	}
+	return limits, nil
+}
+
+// UpdateMerchantRateLimitsRequest sets a merchant's budgets. A budget left
+// out goes back to the default.
+type UpdateMerchantRateLimitsRequest struct {
+	ReadsPerMinute  *int `json:"reads_per_minute" binding:"omitempty,min=1"`
+	WritesPerMinute *int `json:"writes_per_minute" binding:"omitempty,min=1"`
+}
+
+// UpdateMerchantRateLimits sets a merchant's budgets. Other instances pick
+// them up once their cached limits expire.
+func (s *RateLimitService) UpdateMerchantRateLimits(ctx context.Context, merchantID uuid.UUID, req UpdateMerchantRateLimitsRequest) (*MerchantRateLimits, error) {
+	override := &models.MerchantRateLimit{
+		MerchantID:      merchantID,
+		ReadsPerMinute:  req.ReadsPerMinute,
+		WritesPerMinute: req.WritesPerMinute,
+	}
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "merchant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reads_per_minute", "writes_per_minute", "updated_at"}),
+	}).Create(override).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update merchant rate limits: %w", err)
+	}
+
+	limits, err := s.GetMerchantRateLimits(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	s.cache(*limits)
+
+	s.logger.WithFields(logrus.Fields{
+		"merchant_id":       merchantID,
+		"reads_per_minute":  limits.ReadsPerMinute,
+		"writes_per_minute": limits.WritesPerMinute,
+	}).Info("Merchant rate limits updated")
+	return limits, nil
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/money"
+)
+
+// Reasons a settlement record is queued as an exception
+const (
+	ReconReasonUnknownRRN         = "UNKNOWN_RRN"         // No payment has the record's RRN
+	ReconReasonNotCaptured        = "NOT_CAPTURED"        // The payment with the RRN did not succeed
+	ReconReasonAmountMismatch     = "AMOUNT_MISMATCH"     // The record settles another amount than was captured
+	ReconReasonSettlementConflict = "SETTLEMENT_CONFLICT" // The payment was settled under another settlement ID
+)
+
+// Resolutions of a reconciliation exception
+const (
+	// ReconResolutionSettled marks a payment settled under the record's
+	// settlement ID, e.g. once the amount difference is explained
+	ReconResolutionSettled = "settled"
+	// ReconResolutionWrittenOff closes the exception without settling any
+	// payment, e.g. for a record of a payment made outside the gateway
+	ReconResolutionWrittenOff = "written_off"
+)
+
+var (
+	// ErrReconExceptionNotFound is returned for an exception that does not
+	// exist
+	ErrReconExceptionNotFound = errors.New("reconciliation exception not found")
+	// ErrReconExceptionResolved is returned when resolving an exception
+	// again
+	ErrReconExceptionResolved = errors.New("reconciliation exception already resolved")
+	// ErrInvalidReconResolution is returned for a resolution that is
+	// unknown or cannot be applied
+	ErrInvalidReconResolution = errors.New("invalid reconciliation resolution")
+)
+
+// ReconciliationPolicy is which settlement reports are reconciled, and
+// how often
+type ReconciliationPolicy struct {
+	BankCodes []string      // Banks whose reports are pulled; none disables the worker
+	Interval  time.Duration // Between runs
+	Lookback  time.Duration // How far back each run pulls reports
+}
+
+// ReconciliationService matches UPI Core's settlement reports to captured
+// payments
+type ReconciliationService struct {
+	db             *gorm.DB
+	logger         *logrus.Logger
+	upiClient      *UPIClient
+	webhookService *WebhookService
+	policy         ReconciliationPolicy
+}
+
+// NewReconciliationService creates a new reconciliation service
+func NewReconciliationService(db *gorm.DB, logger *logrus.Logger, upiClient *UPIClient, webhookService *WebhookService, policy ReconciliationPolicy) *ReconciliationService {
+	if policy.Interval <= 0 {
+		policy.Interval = time.Hour
+	}
+	if policy.Lookback <= 0 {
+		policy.Lookback = 72 * time.Hour
+	}
+	return &ReconciliationService{
+		db:             db,
+		logger:         logger,
+		upiClient:      upiClient,
+		webhookService: webhookService,
+		policy:         policy,
+	}
+}
+
+// ReconciliationResult counts what a run did with the records it pulled
+type ReconciliationResult struct {
+	Records    int `json:"records"`
+	Matched    int `json:"matched"`   // Settling a payment, now or in an earlier run
+	Unsettled  int `json:"unsettled"` // Not yet settled by UPI Core
+	Exceptions int `json:"exceptions"`
+}
+
+// StartReconWorker reconciles the configured banks' settlement reports
+// every interval, until ctx is done. Reports overlap from run to run, and
+// records already reconciled are skipped, so several instances can run it.
+func (s *ReconciliationService) StartReconWorker(ctx context.Context) {
+	if len(s.policy.BankCodes) == 0 {
+		s.logger.Info("No settlement banks configured, not starting reconciliation worker")
+		return
+	}
+
+	ticker := time.NewTicker(s.policy.Interval)
+	defer ticker.Stop()
+
+	s.logger.WithField("bank_codes", s.policy.BankCodes).Info("Starting reconciliation worker")
+
+	for {
+		now := time.Now()
+		for _, bankCode := range s.policy.BankCodes {
+			result, err := s.Reconcile(ctx, bankCode, now.Add(-s.policy.Lookback), now)
+			log := s.logger.WithField("bank_code", bankCode)
+			if err != nil {
+				log.WithError(err).Error("Failed to reconcile settlement report")
+				continue
+			}
+			log.WithFields(logrus.Fields{
+				"records":    result.Records,
+				"matched":    result.Matched,
+				"exceptions": result.Exceptions,
+			}).Info("Reconciled settlement report")
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping reconciliation worker")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile pulls a bank's settlement report for [from, to) page by page,
+// marks the payments it settles, and queues the records that do not match
+// a captured payment as exceptions
+func (s *ReconciliationService) Reconcile(ctx context.Context, bankCode string, from, to time.Time) (*ReconciliationResult, error) {
+	result := &ReconciliationResult{}
+	pageToken := ""
+	for {
+		report, err := s.upiClient.GetSettlementReport(ctx, UPISettlementReportRequest{
+			BankCode:  bankCode,
+			From:      from,
+			To:        to,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		for i := range report.Records {
+			record := &report.Records[i]
+			result.Records++
+			if record.SettlementID == "" {
+				result.Unsettled++
+				continue
+			}
+			matched, err := s.reconcileRecord(ctx, bankCode, record)
+			if err != nil {
+				return result, fmt.Errorf("failed to reconcile RRN %s: %w", record.RRN, err)
+			}
+			if matched {
+				result.Matched++
+			} else {
+				result.Exceptions++
+			}
+		}
+
+		if report.NextPageToken == "" {
+			return result, nil
+		}
+		pageToken = report.NextPageToken
+	}
+}
+
+// reconcileRecord settles the payment a record matches, and reports
+// whether it matched one; a record that does not is queued as an
+// exception. A record already reconciled matches and changes nothing.
+func (s *ReconciliationService) reconcileRecord(ctx context.Context, bankCode string, record *UPISettlementRecord) (bool, error) {
+	var matched bool
+	var settled *models.Payment
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var payment models.Payment
+		query := tx.Preload("PaymentIntent").Where("rail_transaction_id = ?", record.RRN)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		err := query.First(&payment).Error
+		if err == gorm.ErrRecordNotFound {
+			return s.queueException(tx, bankCode, record, nil, ReconReasonUnknownRRN, "no payment has this RRN")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+
+		// The record is in the currency of the payment it settles
+		amount, _ := record.Amounts(payment.Currency)
+		switch {
+		case payment.SettlementID != nil && *payment.SettlementID == record.SettlementID:
+			matched = true
+			return nil
+		case payment.SettlementID != nil:
+			return s.queueException(tx, bankCode, record, &payment, ReconReasonSettlementConflict,
+				fmt.Sprintf("payment already settled under %s", *payment.SettlementID))
+		case payment.Status != models.PaymentStatusSucceeded:
+			return s.queueException(tx, bankCode, record, &payment, ReconReasonNotCaptured,
+				fmt.Sprintf("payment is %s", payment.Status))
+		case !payment.Amount.Equal(amount):
+			return s.queueException(tx, bankCode, record, &payment, ReconReasonAmountMismatch,
+				fmt.Sprintf("captured %s %s, settled %s %s",
+					money.Format(payment.Amount, payment.Currency), payment.Currency, money.Format(amount, payment.Currency), payment.Currency))
+		}
+
+		if err := settlePayment(tx, &payment, record.SettlementID); err != nil {
+			return err
+		}
+		matched = true
+		settled = &payment
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if settled != nil {
+		s.emitSettled(settled)
+	}
+	return matched, nil
+}
+
+// settlePayment marks payment settled under settlementID within tx
+func settlePayment(tx *gorm.DB, payment *models.Payment, settlementID string) error {
+	now := time.Now()
+	payment.SettlementID = &settlementID
+	payment.SettledAt = &now
+	if err := appendPaymentEvent(tx, payment, PaymentEventSettled, PaymentEventData{
+		SettlementID: settlementID,
+		SettledAt:    &now,
+	}); err != nil {
+		return err
+	}
+	err := tx.Model(&models.Payment{}).Where("id = ?", payment.ID).Updates(map[string]interface{}{
+		"settlement_id": settlementID,
+		"settled_at":    now,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark payment settled: %w", err)
+	}
+	return nil
+}
+
+// queueException records a record that did not match payment, or any
+// payment if nil. Its amounts are in the payment's currency, or in rupees
+// without one, UPI's own currency. The same record pulled again by a later
+// run is not queued twice.
+func (s *ReconciliationService) queueException(tx *gorm.DB, bankCode string, record *UPISettlementRecord, payment *models.Payment, reason, details string) error {
+	currency := money.INR
+	var paymentID *uuid.UUID
+	if payment != nil {
+		currency, paymentID = payment.Currency, &payment.ID
+	}
+	amount, fee := record.Amounts(currency)
+	exception := &models.ReconciliationException{
+		ID:            uuid.New(),
+		BankCode:      bankCode,
+		RRN:           record.RRN,
+		SettlementID:  record.SettlementID,
+		TransactionID: record.TransactionID,
+		Amount:        amount,
+		Fee:           fee,
+		Currency:      currency,
+		PaymentID:     paymentID,
+		Reason:        reason,
+		Details:       details,
+		Status:        models.ReconExceptionStatusOpen,
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(exception)
+	if result.Error != nil {
+		return fmt.Errorf("failed to queue reconciliation exception: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"bank_code":     bankCode,
+			"rrn":           record.RRN,
+			"settlement_id": record.SettlementID,
+			"reason":        reason,
+		}).Warn("Queued reconciliation exception")
+	}
+	return nil
+}
+
+// emitSettled sends the payment.settled webhook of a payment
+func (s *ReconciliationService) emitSettled(payment *models.Payment) {
+	if payment.PaymentIntent == nil {
+		return
+	}
+	merchantID := payment.PaymentIntent.MerchantID
+	go s.webhookService.TriggerWebhook(context.Background(), merchantID, "payment.settled", payment)
+}
+
+// ListReconciliationExceptions lists exceptions, newest first, optionally
+// only those in status
+func (s *ReconciliationService) ListReconciliationExceptions(ctx context.Context, status string, limit int) ([]models.ReconciliationException, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	query := s.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var exceptions []models.ReconciliationException
+	if err := query.Order("created_at DESC").Limit(limit).Find(&exceptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list reconciliation exceptions: %w", err)
+	}
+	return exceptions, nil
+}
+
+// ResolveReconExceptionRequest resolves an exception
+type ResolveReconExceptionRequest struct {
+	Resolution string     `json:"resolution" binding:"required"` // settled or written_off
+	PaymentID  *uuid.UUID `json:"payment_id"`                    // Payment to settle; defaults to the exception's
+	Note       string     `json:"note" binding:"required"`
+	ResolvedBy string     `json:"-"`
+}
+
+// ResolveReconciliationException closes an open exception. Resolving it
+// as settled marks the payment settled under the exception's settlement
+// ID, whatever the amounts, as long as the payment succeeded and is not
+// settled already.
+func (s *ReconciliationService) ResolveReconciliationException(ctx context.Context, id uuid.UUID, req ResolveReconExceptionRequest) (*models.ReconciliationException, error) {
+	if req.Resolution != ReconResolutionSettled && req.Resolution != ReconResolutionWrittenOff {
+		return nil, fmt.Errorf("%w: unknown resolution %q", ErrInvalidReconResolution, req.Resolution)
+	}
+
+	var exception models.ReconciliationException
+	var settled *models.Payment
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("id = ?", id)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.First(&exception).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrReconExceptionNotFound
+			}
+			return fmt.Errorf("failed to get reconciliation exception: %w", err)
+		}
+		if exception.Status != models.ReconExceptionStatusOpen {
+			return ErrReconExceptionResolved
+		}
+
+		if req.Resolution == ReconResolutionSettled {
+			paymentID := exception.PaymentID
+			if req.PaymentID != nil {
+				paymentID = req.PaymentID
+			}
+			if paymentID == nil {
+				return fmt.Errorf("%w: payment_id is required, no payment has RRN %s", ErrInvalidReconResolution, exception.RRN)
+			}
+			var payment models.Payment
+			query := tx.Preload("PaymentIntent").Where("id = ?", *paymentID)
+			if tx.Dialector.Name() == "postgres" {
+				query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+			}
+			if err := query.First(&payment).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return fmt.Errorf("%w: payment %s not found", ErrInvalidReconResolution, *paymentID)
+				}
+				return fmt.Errorf("failed to get payment: %w", err)
+			}
+			if payment.Status != models.PaymentStatusSucceeded {
+				return fmt.Errorf("%w: payment is %s", ErrInvalidReconResolution, payment.Status)
+			}
+			if payment.SettlementID != nil {
+				return fmt.Errorf("%w: payment already settled under %s", ErrInvalidReconResolution, *payment.SettlementID)
+			}
+			if err := settlePayment(tx, &payment, exception.SettlementID); err != nil {
+				return err
+			}
+			exception.PaymentID = &payment.ID
+			settled = &payment
+		}
+
+		now := time.Now()
+		exception.Status = models.ReconExceptionStatusResolved
+		exception.Resolution = req.Resolution
+		exception.ResolutionNote = req.Note
+		exception.ResolvedBy = req.ResolvedBy
+		exception.ResolvedAt = &now
+		if err := tx.Save(&exception).Error; err != nil {
+			return fmt.Errorf("failed to resolve reconciliation exception: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if settled != nil {
+		s.emitSettled(settled)
+	}
+	s.logger.WithFields(logrus.Fields{
+		"exception_id": exception.ID,
+		"resolution":   exception.Resolution,
+		"resolved_by":  exception.ResolvedBy,
+	}).Info("Reconciliation exception resolved")
+	return &exception, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/payments/internal/models"
+	pb "github.com/suuupra/payments/proto/upi_core"
+)
+
+// settles returns a settlement record of amountMinor settling payment
+func settles(payment *models.Payment, amountMinor int64) *pb.SettlementRecord {
+	return &pb.SettlementRecord{
+		TransactionId: "UPI_" + payment.ID.String()[:8],
+		Rrn:           payment.RailTransactionID,
+		AmountPaisa:   amountMinor,
+		FeePaisa:      amountMinor / 100,
+		SettlementId:  "SETL_001",
+	}
+}
+
+func reconExceptions(t *testing.T, env *testEnv) []models.ReconciliationException {
+	var exceptions []models.ReconciliationException
+	require.NoError(t, env.db.Order("rrn ASC").Find(&exceptions).Error)
+	return exceptions
+}
+
+func TestReconciliation_SettlesInThePaymentsCurrency(t *testing.T) {
+	env := newTestEnv(t)
+	recon := NewReconciliationService(env.db, testLogger(), env.upiClient, env.webhooks, ReconciliationPolicy{})
+	ctx := context.Background()
+
+	dinars := env.pay(t, "12.345", "KWD")
+	rupees := env.pay(t, "100.00", "INR")
+	env.upiCore.settlements = []*pb.SettlementRecord{settles(dinars, 12345), settles(rupees, 10000)}
+
+	result, err := recon.Reconcile(ctx, "ICICI", time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, ReconciliationResult{Records: 2, Matched: 2}, *result)
+	assert.Empty(t, reconExceptions(t, env), "3-decimal payment not matched to its settlement")
+
+	for _, payment := range []*models.Payment{dinars, rupees} {
+		settled, err := env.payments.GetPayment(ctx, payment.ID)
+		require.NoError(t, err)
+		require.NotNil(t, settled.SettlementID, payment.Currency)
+		assert.Equal(t, "SETL_001", *settled.SettlementID)
+	}
+
+	// Pulled again, nothing changes
+	result, err = recon.Reconcile(ctx, "ICICI", time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Matched)
+	assert.Empty(t, reconExceptions(t, env))
+}
+
+func TestReconciliation_QueuesExceptionsInThePaymentsCurrency(t *testing.T) {
+	env := newTestEnv(t)
+	recon := NewReconciliationService(env.db, testLogger(), env.upiClient, env.webhooks, ReconciliationPolicy{})
+
+	dinars := env.pay(t, "12.345", "KWD")
+	unknown := &pb.SettlementRecord{Rrn: "RRN_UNKNOWN", AmountPaisa: 50000, FeePaisa: 50, SettlementId: "SETL_001"}
+	env.upiCore.settlements = []*pb.SettlementRecord{settles(dinars, 12340), unknown}
+
+	result, err := recon.Reconcile(context.Background(), "ICICI", time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, ReconciliationResult{Records: 2, Exceptions: 2}, *result)
+
+	exceptions := reconExceptions(t, env)
+	require.Len(t, exceptions, 2)
+	unknownRRN, mismatch := exceptions[0], exceptions[1]
+
+	assert.Equal(t, ReconReasonAmountMismatch, mismatch.Reason)
+	assert.Equal(t, "captured 12.345 KWD, settled 12.340 KWD", mismatch.Details)
+	assert.Equal(t, "KWD", mismatch.Currency)
+	assert.True(t, mismatch.Amount.Equal(decimal.RequireFromString("12.34")), "amount %s", mismatch.Amount)
+	assert.True(t, mismatch.Fee.Equal(decimal.RequireFromString("0.123")), "fee %s", mismatch.Fee)
+	require.NotNil(t, mismatch.PaymentID)
+	assert.Equal(t, dinars.ID, *mismatch.PaymentID)
+
+	// Without a payment, the record is taken to be in rupees
+	assert.Equal(t, ReconReasonUnknownRRN, unknownRRN.Reason)
+	assert.Equal(t, "INR", unknownRRN.Currency)
+	assert.True(t, unknownRRN.Amount.Equal(decimal.NewFromInt(500)), "amount %s", unknownRRN.Amount)
+	assert.Nil(t, unknownRRN.PaymentID)
+}
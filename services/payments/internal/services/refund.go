@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,36 +10,55 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/money"
 )
 
-// RefundService handles refund processing
+// RefundService handles refund processing. Refunds are created pending and
+// sent to the payment's rail by the refund worker.
 type RefundService struct {
 	db             *gorm.DB
 	logger         *logrus.Logger
-	upiClient      *UPIClient
+	rails          *RailRouter
 	ledgerService  *LedgerService
 	webhookService *WebhookService
+	submitTimeout  time.Duration
+	wake           chan struct{}
 }
 
-// NewRefundService creates a new refund service
+// NewRefundService creates a new refund service. Refunds claimed by a
+// worker that did not get them to the rail within submitTimeoutSeconds are
+// sent again.
 func NewRefundService(
 	db *gorm.DB,
 	logger *logrus.Logger,
-	upiClient *UPIClient,
+	rails *RailRouter,
 	ledgerService *LedgerService,
 	webhookService *WebhookService,
+	submitTimeoutSeconds int,
 ) *RefundService {
 	return &RefundService{
 		db:             db,
 		logger:         logger,
-		upiClient:      upiClient,
+		rails:          rails,
 		ledgerService:  ledgerService,
 		webhookService: webhookService,
+		submitTimeout:  time.Duration(submitTimeoutSeconds) * time.Second,
+		wake:           make(chan struct{}, 1),
 	}
 }
 
+var (
+	// ErrPaymentNotRefundable is returned for refunds of payments that did
+	// not succeed
+	ErrPaymentNotRefundable = errors.New("can only refund successful payments")
+	// ErrRefundExceedsPayment is returned for a refund that would take the
+	// payment's refunds past its amount
+	ErrRefundExceedsPayment = errors.New("total refund amount would exceed payment amount")
+)
+
 // CreateRefundRequest represents a refund creation request
 type CreateRefundRequest struct {
 	PaymentID uuid.UUID              `json:"payment_id" binding:"required"`
@@ -47,7 +67,21 @@ type CreateRefundRequest struct {
 	Metadata  map[string]interface{} `json:"metadata"`
 }
 
-// CreateRefund creates and processes a refund
+// RefundSummary is how much of a payment is refunded. Pending refunds are
+// those not yet succeeded or failed; they count against the refundable
+// amount.
+type RefundSummary struct {
+	PaymentID        uuid.UUID       `json:"payment_id"`
+	Amount           decimal.Decimal `json:"amount"`
+	RefundedAmount   decimal.Decimal `json:"refunded_amount"`
+	PendingAmount    decimal.Decimal `json:"pending_amount"`
+	RefundableAmount decimal.Decimal `json:"refundable_amount"`
+}
+
+// CreateRefund creates a pending refund of part or all of a payment. A
+// payment can be refunded any number of times up to its amount; the
+// payment is locked while its refunds are totalled, so concurrent refunds
+// cannot together exceed it.
 func (s *RefundService) CreateRefund(ctx context.Context, req CreateRefundRequest) (*models.Refund, error) {
 	log := s.logger.WithFields(logrus.Fields{
 		"payment_id": req.PaymentID,
@@ -57,153 +91,120 @@ func (s *RefundService) CreateRefund(ctx context.Context, req CreateRefundReques
 
 	log.Info("Starting refund creation")
 
-	// Validate refund amount
-	if req.Amount.LessThanOrEqual(decimal.Zero) {
-		return nil, fmt.Errorf("refund amount must be greater than zero")
-	}
-
-	// Get original payment
 	var payment models.Payment
-	err := s.db.WithContext(ctx).
-		Preload("PaymentIntent").
-		Where("id = ?", req.PaymentID).
-		First(&payment).Error
-
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("payment not found")
+	var refund *models.Refund
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Get original payment
+		query := tx.Preload("PaymentIntent")
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.Where("id = ?", req.PaymentID).First(&payment).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("payment not found")
+			}
+			return fmt.Errorf("failed to fetch payment: %w", err)
 		}
-		log.WithError(err).Error("Failed to fetch payment")
-		return nil, fmt.Errorf("failed to fetch payment: %w", err)
-	}
 
-	// Validate payment status
-	if payment.Status != models.PaymentStatusSucceeded {
-		return nil, fmt.Errorf("can only refund successful payments")
-	}
+		// Refunds are in the payment's currency
+		amount, err := ValidateAmount(req.Amount, payment.Currency)
+		if err != nil {
+			return fmt.Errorf("refund %w", err)
+		}
 
-	// Check if refund amount is valid
-	if req.Amount.GreaterThan(payment.Amount) {
-		return nil, fmt.Errorf("refund amount cannot exceed payment amount")
-	}
+		// Validate payment status
+		if payment.Status != models.PaymentStatusSucceeded {
+			return ErrPaymentNotRefundable
+		}
 
-	// Check for existing refunds to ensure total doesn't exceed payment amount
-	var existingRefundsTotal decimal.Decimal
-	err = s.db.WithContext(ctx).
-		Model(&models.Refund{}).
-		Where("payment_id = ? AND status IN (?)", req.PaymentID, []string{
-			models.RefundStatusSucceeded,
-			models.RefundStatusPending,
-			models.RefundStatusProcessing,
-		}).
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&existingRefundsTotal).Error
+		// Check existing refunds to ensure the total doesn't exceed the payment
+		summary, err := s.refundSummary(tx, &payment)
+		if err != nil {
+			return err
+		}
+		if amount.Decimal().GreaterThan(summary.RefundableAmount) {
+			return fmt.Errorf("%w: %s of %s refundable", ErrRefundExceedsPayment,
+				money.Format(summary.RefundableAmount, payment.Currency), money.Format(payment.Amount, payment.Currency))
+		}
 
+		refund = &models.Refund{
+			ID:              uuid.New(),
+			PaymentID:       req.PaymentID,
+			Amount:          amount.Decimal(),
+			Currency:        amount.Currency,
+			Reason:          req.Reason,
+			Status:          models.RefundStatusPending,
+			RefundReference: s.generateRefundReference(),
+			Metadata:        req.Metadata,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+		if err := tx.Create(refund).Error; err != nil {
+			return fmt.Errorf("failed to create refund record: %w", err)
+		}
+		return appendPaymentEvent(tx, &payment, PaymentEventRefundRequested, refundEventData(refund))
+	})
 	if err != nil {
-		log.WithError(err).Error("Failed to calculate existing refunds")
-		return nil, fmt.Errorf("failed to calculate existing refunds: %w", err)
+		log.WithError(err).Warn("Failed to create refund")
+		return nil, err
 	}
 
-	totalRefundAmount := existingRefundsTotal.Add(req.Amount)
-	if totalRefundAmount.GreaterThan(payment.Amount) {
-		return nil, fmt.Errorf("total refund amount would exceed payment amount")
+	log.WithField("refund_id", refund.ID).Info("Refund created")
+	if payment.PaymentIntent != nil {
+		s.emitRefundEvent(payment.PaymentIntent.MerchantID, "refund.created", refund)
 	}
 
-	// Generate unique refund reference
-	refundReference := s.generateRefundReference()
-
-	// Create refund record
-	refund := &models.Refund{
-		ID:              uuid.New(),
-		PaymentID:       req.PaymentID,
-		Amount:          req.Amount,
-		Currency:        payment.Currency,
-		Reason:          req.Reason,
-		Status:          models.RefundStatusPending,
-		RefundReference: refundReference,
-		Metadata:        req.Metadata,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+	// Have the worker send it now rather than on its next tick
+	select {
+	case s.wake <- struct{}{}:
+	default:
 	}
+	return refund, nil
+}
 
-	// Start database transaction for refund processing
-	return refund, s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Create refund record
-		if err := tx.Create(refund).Error; err != nil {
-			log.WithError(err).Error("Failed to create refund record")
-			return fmt.Errorf("failed to create refund record: %w", err)
-		}
-
-		// Update status to processing
-		refund.Status = models.RefundStatusProcessing
-		if err := tx.Save(refund).Error; err != nil {
-			return fmt.Errorf("failed to update refund status: %w", err)
-		}
-
-		// Process refund through UPI
-		upiReq := UPIRefundRequest{
-			RefundID:          refund.ID,
-			OriginalPaymentID: payment.ID,
-			TransactionID:     payment.RailTransactionID,
-			Amount:            refund.Amount,
-			Currency:          refund.Currency,
-			Reason:            refund.Reason,
-		}
-
-		upiResp, err := s.upiClient.ProcessRefund(ctx, upiReq)
-		if err != nil {
-			log.WithError(err).Error("UPI refund processing failed")
-			// Update refund status to failed
-			refund.Status = models.RefundStatusFailed
-			failureMsg := err.Error()
-			refund.FailureMessage = &failureMsg
-			tx.Save(refund)
-			return fmt.Errorf("UPI refund processing failed: %w", err)
-		}
-
-		// Update refund with UPI response
-		if upiResp.Success {
-			refund.Status = models.RefundStatusSucceeded
-			refund.RefundReference = upiResp.RefundReference
-			processedAt := upiResp.ProcessedAt
-			refund.ProcessedAt = &processedAt
-		} else {
-			refund.Status = models.RefundStatusFailed
-			refund.FailureCode = upiResp.FailureCode
-			refund.FailureMessage = upiResp.FailureMessage
-		}
+// refundSummary totals the refunds of payment within tx
+func (s *RefundService) refundSummary(tx *gorm.DB, payment *models.Payment) (*RefundSummary, error) {
+	var totals []struct {
+		Status string
+		Total  decimal.Decimal
+	}
+	err := tx.Model(&models.Refund{}).
+		Select("status, COALESCE(SUM(amount), 0) AS total").
+		Where("payment_id = ?", payment.ID).
+		Group("status").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate existing refunds: %w", err)
+	}
 
-		if err := tx.Save(refund).Error; err != nil {
-			return fmt.Errorf("failed to update refund with UPI response: %w", err)
+	summary := &RefundSummary{
+		PaymentID:      payment.ID,
+		Amount:         payment.Amount,
+		RefundedAmount: decimal.Zero,
+		PendingAmount:  decimal.Zero,
+	}
+	for _, total := range totals {
+		switch total.Status {
+		case models.RefundStatusSucceeded:
+			summary.RefundedAmount = summary.RefundedAmount.Add(total.Total)
+		case models.RefundStatusPending, models.RefundStatusProcessing:
+			summary.PendingAmount = summary.PendingAmount.Add(total.Total)
 		}
+	}
+	summary.RefundableAmount = payment.Amount.Sub(summary.RefundedAmount).Sub(summary.PendingAmount)
+	return summary, nil
+}
 
-		// If refund succeeded, post to ledger
-		if refund.Status == models.RefundStatusSucceeded {
-			if err := s.ledgerService.PostRefundTransaction(ctx, refund, &payment); err != nil {
-				log.WithError(err).Error("Failed to post refund to ledger")
-				// In a real system, you might want to handle this differently
-				// For now, we'll still consider the refund successful but log the ledger error
-			}
+// GetRefundSummary returns how much of a payment is refunded
+func (s *RefundService) GetRefundSummary(ctx context.Context, paymentID uuid.UUID) (*RefundSummary, error) {
+	var payment models.Payment
+	if err := s.db.WithContext(ctx).Where("id = ?", paymentID).First(&payment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("payment not found")
 		}
-
-		log.WithFields(logrus.Fields{
-			"refund_id":        refund.ID,
-			"status":           refund.Status,
-			"refund_reference": refund.RefundReference,
-		}).Info("Refund processing completed")
-
-		// Trigger webhooks
-		go func() {
-			merchantID := payment.PaymentIntent.MerchantID
-			if refund.Status == models.RefundStatusSucceeded {
-				s.webhookService.TriggerWebhook(context.Background(), merchantID, "refund.succeeded", refund)
-			} else {
-				s.webhookService.TriggerWebhook(context.Background(), merchantID, "refund.failed", refund)
-			}
-		}()
-
-		return nil
-	})
+		return nil, fmt.Errorf("failed to fetch payment: %w", err)
+	}
+	return s.refundSummary(s.db.WithContext(ctx), &payment)
 }
 
 // GetRefund retrieves a refund by ID
@@ -240,7 +241,7 @@ func (s *RefundService) GetRefundsByPayment(ctx context.Context, paymentID uuid.
 	return refunds, nil
 }
 
-// CheckRefundStatus checks the status of a refund with the payment rail
+// CheckRefundStatus checks the status of a refund sent to the payment rail
 func (s *RefundService) CheckRefundStatus(ctx context.Context, refundID uuid.UUID) (*models.Refund, error) {
 	refund, err := s.GetRefund(ctx, refundID)
 	if err != nil {
@@ -253,95 +254,78 @@ func (s *RefundService) CheckRefundStatus(ctx context.Context, refundID uuid.UUI
 		"current_status":   refund.Status,
 	})
 
-	// Only check status for pending/processing refunds
-	if refund.Status != models.RefundStatusPending && refund.Status != models.RefundStatusProcessing {
-		log.Debug("Refund is not in pending/processing status, no need to check")
+	// Only refunds the rail has yet to settle can change
+	if refund.Status != models.RefundStatusProcessing || refund.SubmittedAt == nil || refund.Payment == nil {
+		log.Debug("Refund is not awaiting the rail, no need to check")
 		return refund, nil
 	}
 
 	log.Info("Checking refund status with payment rail")
 
-	// Check refund status with UPI Core service
-	if refund.RefundReference != "" {
-		// Create status check request for UPI Core
-		statusReq := UPIRefundStatusRequest{
-			RefundReference: refund.RefundReference,
-			RefundID:        refund.ID,
-		}
-
-		// Call UPI Core service to get current status
-		upiResp, err := s.upiClient.CheckRefundStatus(ctx, statusReq)
-		if err != nil {
-			log.WithError(err).Warn("Failed to check refund status with UPI Core, keeping current status")
-			return refund, nil
-		}
-
-		// Update refund status based on UPI Core response
-		statusChanged := false
-		if upiResp.Status != refund.Status {
-			log.WithFields(logrus.Fields{
-				"old_status": refund.Status,
-				"new_status": upiResp.Status,
-			}).Info("Refund status changed")
-
-			refund.Status = upiResp.Status
-			statusChanged = true
-
-			if upiResp.ProcessedAt != nil {
-				refund.ProcessedAt = upiResp.ProcessedAt
-			}
-
-			if upiResp.FailureCode != nil {
-				refund.FailureCode = upiResp.FailureCode
-			}
+	rail, _, err := s.paymentRail(ctx, refund.Payment)
+	if err != nil {
+		log.WithError(err).Warn("No rail for refund, keeping current status")
+		return refund, nil
+	}
 
-			if upiResp.FailureMessage != nil {
-				refund.FailureMessage = upiResp.FailureMessage
-			}
+	// Call the rail to get current status
+	railResp, err := rail.CheckRefundStatus(ctx, refund.RefundReference, refund.ID)
+	if err != nil {
+		log.WithError(err).Warn("Failed to check refund status with payment rail, keeping current status")
+		return refund, nil
+	}
 
-			refund.UpdatedAt = time.Now()
+	switch railResp.Status {
+	case models.RefundStatusSucceeded:
+		refund.ProcessedAt = railResp.ProcessedAt
+		if refund.ProcessedAt == nil {
+			now := time.Now()
+			refund.ProcessedAt = &now
 		}
+	case models.RefundStatusFailed:
+		refund.FailureCode = railResp.FailureCode
+		refund.FailureMessage = railResp.FailureMessage
+	default:
+		// Still in flight; bump it to the back of the worker's queue
+		s.db.WithContext(ctx).Model(&models.Refund{}).
+			Where("id = ? AND status = ?", refund.ID, refund.Status).
+			Update("updated_at", time.Now())
+		return refund, nil
+	}
 
-		// Save status changes if any
-		if statusChanged {
-			if err := s.db.WithContext(ctx).Save(refund).Error; err != nil {
-				log.WithError(err).Error("Failed to update refund status")
-				return nil, fmt.Errorf("failed to update refund status: %w", err)
-			}
+	if err := s.settleRefund(ctx, refund, railResp.Status); err != nil {
+		log.WithError(err).Error("Failed to update refund status")
+		return nil, fmt.Errorf("failed to update refund status: %w", err)
+	}
+	return refund, nil
+}
 
-			// Handle status change side effects
-			if refund.Status == models.RefundStatusSucceeded {
-				// Post to ledger if not already done
-				if refund.Payment != nil {
-					if err := s.ledgerService.PostRefundTransaction(ctx, refund, refund.Payment); err != nil {
-						log.WithError(err).Error("Failed to post refund to ledger")
-					}
-				}
-
-				// Trigger success webhook
-				if refund.Payment != nil && refund.Payment.PaymentIntent != nil {
-					go s.webhookService.TriggerWebhook(
-						context.Background(),
-						refund.Payment.PaymentIntent.MerchantID,
-						"refund.succeeded",
-						refund,
-					)
-				}
-			} else if refund.Status == models.RefundStatusFailed {
-				// Trigger failure webhook
-				if refund.Payment != nil && refund.Payment.PaymentIntent != nil {
-					go s.webhookService.TriggerWebhook(
-						context.Background(),
-						refund.Payment.PaymentIntent.MerchantID,
-						"refund.failed",
-						refund,
-					)
-				}
-			}
+// settleRefund moves a processing refund to its final status, posting a
+// succeeded refund to the ledger along with it, and sends its webhook
+func (s *RefundService) settleRefund(ctx context.Context, refund *models.Refund, status string) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.transitionRefund(tx, refund, status); err != nil {
+			return err
+		}
+		if refund.Status != models.RefundStatusSucceeded {
+			return nil
 		}
+		if refund.Payment.PaymentIntent == nil {
+			return fmt.Errorf("refund %s has no payment intent to post to the ledger", refund.ID)
+		}
+		if err := s.ledgerService.PostRefundTransaction(ctx, tx, refund, refund.Payment.PaymentIntent.MerchantID); err != nil {
+			return fmt.Errorf("failed to post refund to ledger: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return refund, nil
+	if refund.Payment.PaymentIntent != nil {
+		s.emitRefundEvent(refund.Payment.PaymentIntent.MerchantID, "refund."+refund.Status, refund)
+	}
+	return nil
 }
 
 // generateRefundReference generates a unique refund reference
@@ -349,50 +333,48 @@ func (s *RefundService) generateRefundReference() string {
 	return fmt.Sprintf("REF_%d_%s", time.Now().Unix(), uuid.New().String()[:8])
 }
 
-// CancelRefund cancels a pending refund
+// CancelRefund cancels a refund the worker has not yet picked up
 func (s *RefundService) CancelRefund(ctx context.Context, refundID uuid.UUID) (*models.Refund, error) {
 	log := s.logger.WithField("refund_id", refundID)
 
-	var refund models.Refund
-	err := s.db.WithContext(ctx).
-		Preload("Payment").
-		Preload("Payment.PaymentIntent").
-		Where("id = ?", refundID).
-		First(&refund).Error
-
+	refund, err := s.GetRefund(ctx, refundID)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("refund not found")
-		}
-		return nil, fmt.Errorf("failed to get refund: %w", err)
-	}
-
-	// Can only cancel pending refunds
-	if refund.Status != models.RefundStatusPending {
-		return nil, fmt.Errorf("can only cancel pending refunds")
+		return nil, err
 	}
 
-	// Update status to canceled
-	refund.Status = models.RefundStatusCanceled
-	refund.UpdatedAt = time.Now()
-
-	err = s.db.WithContext(ctx).Save(&refund).Error
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.transitionRefund(tx, refund, models.RefundStatusCanceled)
+	})
 	if err != nil {
-		log.WithError(err).Error("Failed to cancel refund")
-		return nil, fmt.Errorf("failed to cancel refund: %w", err)
+		log.WithError(err).Warn("Failed to cancel refund")
+		return nil, err
 	}
 
 	log.Info("Refund canceled successfully")
 
 	// Trigger webhook
 	if refund.Payment != nil && refund.Payment.PaymentIntent != nil {
-		go s.webhookService.TriggerWebhook(
-			context.Background(),
-			refund.Payment.PaymentIntent.MerchantID,
-			"refund.canceled",
-			&refund,
-		)
+		s.emitRefundEvent(refund.Payment.PaymentIntent.MerchantID, "refund.canceled", refund)
 	}
 
-	return &refund, nil
+	return refund, nil
+}
+
+// paymentRail returns the rail a payment succeeded on and the reference it
+// was sent to the rail with. Payments that failed over succeeded on a rail
+// other than their payment method's, under a reference of their attempt's.
+func (s *RefundService) paymentRail(ctx context.Context, payment *models.Payment) (PaymentRail, uuid.UUID, error) {
+	var attempt models.PaymentAttempt
+	err := s.db.WithContext(ctx).
+		Where("payment_id = ? AND status = ?", payment.ID, models.PaymentAttemptStatusSucceeded).
+		First(&attempt).Error
+	if err == gorm.ErrRecordNotFound {
+		rail, err := s.rails.Rail(payment.PaymentMethod)
+		return rail, payment.ID, err
+	}
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("failed to get payment attempt: %w", err)
+	}
+	rail, err := s.rails.RailByName(attempt.Rail)
+	return rail, attempt.RailReference, err
 }
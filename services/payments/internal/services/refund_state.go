@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+var (
+	// ErrInvalidRefundTransition is returned for a transition the refund
+	// state machine does not allow from the refund's status
+	ErrInvalidRefundTransition = errors.New("invalid refund transition")
+	// ErrRefundConflict is returned when the refund was changed by another
+	// request or the refund worker since it was read
+	ErrRefundConflict = errors.New("refund was modified concurrently")
+)
+
+// refundTransitions lists the statuses each refund status can move to.
+// Succeeded, failed and canceled refunds are final.
+var refundTransitions = map[string][]string{
+	models.RefundStatusPending: {
+		models.RefundStatusProcessing,
+		models.RefundStatusCanceled,
+	},
+	models.RefundStatusProcessing: {
+		models.RefundStatusSucceeded,
+		models.RefundStatusFailed,
+	},
+}
+
+// CanTransitionRefund reports whether a refund in status from can move to
+// status to
+func CanTransitionRefund(from, to string) bool {
+	for _, allowed := range refundTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionRefund moves refund to status to within tx, saving the rail
+// fields set on it. The update is conditional on the status the refund was
+// read in, so of two concurrent transitions only one succeeds.
+func (s *RefundService) transitionRefund(tx *gorm.DB, refund *models.Refund, to string) error {
+	if !CanTransitionRefund(refund.Status, to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidRefundTransition, refund.Status, to)
+	}
+
+	now := time.Now()
+	result := tx.Model(&models.Refund{}).
+		Where("id = ? AND status = ?", refund.ID, refund.Status).
+		Updates(map[string]interface{}{
+			"status":           to,
+			"refund_reference": refund.RefundReference,
+			"failure_code":     refund.FailureCode,
+			"failure_message":  refund.FailureMessage,
+			"processed_at":     refund.ProcessedAt,
+			"submitted_at":     refund.SubmittedAt,
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update refund status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRefundConflict
+	}
+
+	// A final refund settles the amount it held on its payment
+	var paymentEvent string
+	switch to {
+	case models.RefundStatusSucceeded:
+		paymentEvent = PaymentEventRefunded
+	case models.RefundStatusFailed, models.RefundStatusCanceled:
+		paymentEvent = PaymentEventRefundReleased
+	}
+	if paymentEvent != "" {
+		data := refundEventData(refund)
+		data.RefundStatus = to
+		if err := appendPaymentEvent(tx, &models.Payment{ID: refund.PaymentID}, paymentEvent, data); err != nil {
+			return err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"refund_id": refund.ID,
+		"from":      refund.Status,
+		"to":        to,
+	}).Info("Refund transitioned")
+	refund.Status = to
+	refund.UpdatedAt = now
+	return nil
+}
+
+// refundEventData is the data of a payment event about refund
+func refundEventData(refund *models.Refund) PaymentEventData {
+	return PaymentEventData{
+		RefundID:     &refund.ID,
+		RefundAmount: &refund.Amount,
+		RefundStatus: refund.Status,
+	}
+}
+
+// emitRefundEvent sends a webhook of a committed refund: refund.created,
+// or refund.<status> on every transition
+func (s *RefundService) emitRefundEvent(merchantID uuid.UUID, eventType string, refund *models.Refund) {
+	event := *refund
+	event.Payment = nil
+	go s.webhookService.TriggerWebhook(context.Background(), merchantID, eventType, &event)
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+func (env *testEnv) refund(t *testing.T, payment *models.Payment, amount string) *models.Refund {
+	refund, err := env.refunds.CreateRefund(context.Background(), CreateRefundRequest{
+		PaymentID: payment.ID,
+		Amount:    decimal.RequireFromString(amount),
+		Reason:    "requested_by_customer",
+	})
+	require.NoError(t, err)
+	return refund
+}
+
+func (env *testEnv) storedRefund(t *testing.T, refund *models.Refund) *models.Refund {
+	stored, err := env.refunds.GetRefund(context.Background(), refund.ID)
+	require.NoError(t, err)
+	return stored
+}
+
+func TestCanTransitionRefund(t *testing.T) {
+	for _, tc := range []struct {
+		from, to string
+		allowed  bool
+	}{
+		{models.RefundStatusPending, models.RefundStatusProcessing, true},
+		{models.RefundStatusPending, models.RefundStatusCanceled, true},
+		{models.RefundStatusPending, models.RefundStatusSucceeded, false},
+		{models.RefundStatusProcessing, models.RefundStatusSucceeded, true},
+		{models.RefundStatusProcessing, models.RefundStatusFailed, true},
+		{models.RefundStatusProcessing, models.RefundStatusCanceled, false},
+		{models.RefundStatusSucceeded, models.RefundStatusFailed, false},
+		{models.RefundStatusFailed, models.RefundStatusProcessing, false},
+		{models.RefundStatusCanceled, models.RefundStatusPending, false},
+	} {
+		assert.Equal(t, tc.allowed, CanTransitionRefund(tc.from, tc.to), "%s to %s", tc.from, tc.to)
+	}
+}
+
+func TestRefundService_PartialRefunds(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	payment := env.pay(t, "100.00", "INR")
+
+	first := env.refund(t, payment, "40.00")
+	assert.Equal(t, models.RefundStatusPending, first.Status)
+	env.refund(t, payment, "60.00")
+
+	// Pending refunds hold their amount, so nothing is left to refund
+	_, err := env.refunds.CreateRefund(ctx, CreateRefundRequest{PaymentID: payment.ID, Amount: decimal.RequireFromString("0.01")})
+	assert.ErrorIs(t, err, ErrRefundExceedsPayment)
+
+	summary, err := env.refunds.GetRefundSummary(ctx, payment.ID)
+	require.NoError(t, err)
+	assert.True(t, summary.PendingAmount.Equal(decimal.NewFromInt(100)), "pending %s", summary.PendingAmount)
+	assert.True(t, summary.RefundableAmount.IsZero(), "refundable %s", summary.RefundableAmount)
+
+	// Canceling a refund releases its amount
+	_, err = env.refunds.CancelRefund(ctx, first.ID)
+	require.NoError(t, err)
+	env.refund(t, payment, "40.00")
+}
+
+func TestRefundService_RejectsInvalidRefunds(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	payment := env.pay(t, "100.00", "INR")
+
+	_, err := env.refunds.CreateRefund(ctx, CreateRefundRequest{PaymentID: payment.ID, Amount: decimal.RequireFromString("100.01")})
+	assert.ErrorIs(t, err, ErrRefundExceedsPayment)
+	_, err = env.refunds.CreateRefund(ctx, CreateRefundRequest{PaymentID: payment.ID, Amount: decimal.RequireFromString("10.001")})
+	assert.Error(t, err, "refund finer than the currency's minor unit")
+
+	env.card.paymentStatus = models.PaymentStatusFailed
+	intent := env.createIntent(t, "50.00", "INR", RailCard, 15*time.Minute)
+	failed, _ := env.payments.CreatePayment(ctx, CreatePaymentRequest{PaymentIntentID: intent.ID, CardToken: "tok_visa"})
+	require.NotNil(t, failed)
+	require.Equal(t, models.PaymentStatusFailed, failed.Status)
+	_, err = env.refunds.CreateRefund(ctx, CreateRefundRequest{PaymentID: failed.ID, Amount: decimal.RequireFromString("10.00")})
+	assert.ErrorIs(t, err, ErrPaymentNotRefundable)
+}
+
+func TestRefundWorker_SubmitsPendingRefunds(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		railStatus string
+		want       string
+	}{
+		{"rail succeeds", models.RefundStatusSucceeded, models.RefundStatusSucceeded},
+		{"rail declines", models.RefundStatusFailed, models.RefundStatusFailed},
+		{"rail settles later", models.RefundStatusPending, models.RefundStatusProcessing},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.card.refundStatus = tc.railStatus
+			payment := env.pay(t, "100.00", "INR")
+			refund := env.refund(t, payment, "25.50")
+
+			env.refunds.submitPendingRefunds(context.Background())
+
+			require.Len(t, env.card.refunds, 1)
+			sent := env.card.refunds[0]
+			assert.Equal(t, refund.ID, sent.RefundID)
+			assert.Equal(t, payment.ID, sent.OriginalPaymentID)
+			assert.Equal(t, payment.RailTransactionID, sent.TransactionID)
+			assert.True(t, sent.Amount.Equal(decimal.RequireFromString("25.50")))
+
+			stored := env.storedRefund(t, refund)
+			assert.Equal(t, tc.want, stored.Status)
+			switch tc.want {
+			case models.RefundStatusSucceeded:
+				assert.NotNil(t, stored.ProcessedAt)
+				var posted int64
+				require.NoError(t, env.db.Model(&models.JournalEntry{}).
+					Where("reference_type = ? AND reference_id = ?", "refund", refund.ID).Count(&posted).Error)
+				assert.EqualValues(t, 1, posted, "refund not posted to the ledger")
+			case models.RefundStatusFailed:
+				require.NotNil(t, stored.FailureCode)
+				assert.Equal(t, "REFUND_DECLINED", *stored.FailureCode)
+			case models.RefundStatusProcessing:
+				assert.NotNil(t, stored.SubmittedAt)
+				assert.NotEmpty(t, stored.RefundReference)
+			}
+
+			// Submitted refunds are not sent again
+			env.refunds.submitPendingRefunds(context.Background())
+			assert.Len(t, env.card.refunds, 1)
+		})
+	}
+}
+
+func TestRefundWorker_PollsSubmittedRefunds(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	env.card.refundStatus = models.RefundStatusPending
+	payment := env.pay(t, "100.00", "INR")
+	refund := env.refund(t, payment, "30.00")
+	env.refunds.submitPendingRefunds(ctx)
+
+	// Still in flight at the rail
+	env.card.refundResult = &RailRefundStatusResponse{Status: models.RefundStatusPending}
+	env.refunds.pollSubmittedRefunds(ctx)
+	assert.Equal(t, models.RefundStatusProcessing, env.storedRefund(t, refund).Status)
+
+	env.card.refundResult = &RailRefundStatusResponse{Status: models.RefundStatusSucceeded}
+	env.refunds.pollSubmittedRefunds(ctx)
+	stored := env.storedRefund(t, refund)
+	assert.Equal(t, models.RefundStatusSucceeded, stored.Status)
+	assert.NotNil(t, stored.ProcessedAt)
+
+	summary, err := env.refunds.GetRefundSummary(ctx, payment.ID)
+	require.NoError(t, err)
+	assert.True(t, summary.RefundedAmount.Equal(decimal.NewFromInt(30)), "refunded %s", summary.RefundedAmount)
+	assert.True(t, summary.RefundableAmount.Equal(decimal.NewFromInt(70)), "refundable %s", summary.RefundableAmount)
+
+	// Settled refunds are no longer checked
+	env.card.refundResult = &RailRefundStatusResponse{Status: models.RefundStatusFailed}
+	checked, err := env.refunds.CheckRefundStatus(ctx, refund.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RefundStatusSucceeded, checked.Status)
+}
+
+func TestRefundService_CancelOnlyPending(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	payment := env.pay(t, "100.00", "INR")
+
+	pending := env.refund(t, payment, "10.00")
+	canceled, err := env.refunds.CancelRefund(ctx, pending.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RefundStatusCanceled, canceled.Status)
+	_, err = env.refunds.CancelRefund(ctx, pending.ID)
+	assert.ErrorIs(t, err, ErrInvalidRefundTransition)
+
+	env.card.refundStatus = models.RefundStatusPending
+	submitted := env.refund(t, payment, "10.00")
+	env.refunds.submitPendingRefunds(ctx)
+	_, err = env.refunds.CancelRefund(ctx, submitted.ID)
+	assert.ErrorIs(t, err, ErrInvalidRefundTransition)
+	assert.Equal(t, models.RefundStatusProcessing, env.storedRefund(t, submitted).Status)
+}
+
+func TestRefundService_ConcurrentTransitionConflicts(t *testing.T) {
+	env := newTestEnv(t)
+	payment := env.pay(t, "100.00", "INR")
+	refund := env.refund(t, payment, "10.00")
+
+	// A worker claims the refund after a cancel request read it
+	stale := env.storedRefund(t, refund)
+	require.NoError(t, env.db.Transaction(func(tx *gorm.DB) error {
+		return env.refunds.transitionRefund(tx, env.storedRefund(t, refund), models.RefundStatusProcessing)
+	}))
+
+	err := env.db.Transaction(func(tx *gorm.DB) error {
+		return env.refunds.transitionRefund(tx, stale, models.RefundStatusCanceled)
+	})
+	assert.ErrorIs(t, err, ErrRefundConflict)
+	assert.Equal(t, models.RefundStatusProcessing, env.storedRefund(t, refund).Status)
+}
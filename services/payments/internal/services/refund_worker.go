@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// StartRefundWorker sends pending refunds to their payment's rail and
+// polls the rail for refunds it has yet to settle, until ctx is done
+func (s *RefundService) StartRefundWorker(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	s.logger.Info("Starting refund worker")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping refund worker")
+			return
+		case <-ticker.C:
+			s.pollSubmittedRefunds(ctx)
+		case <-s.wake:
+		}
+		s.submitPendingRefunds(ctx)
+	}
+}
+
+// submitPendingRefunds sends the pending refunds, and those a worker
+// claimed but did not get to the rail in time, oldest first
+func (s *RefundService) submitPendingRefunds(ctx context.Context) {
+	var refunds []models.Refund
+	err := s.db.WithContext(ctx).
+		Preload("Payment").
+		Preload("Payment.PaymentIntent").
+		Where("status = ? OR (status = ? AND submitted_at IS NULL AND updated_at < ?)",
+			models.RefundStatusPending, models.RefundStatusProcessing, time.Now().Add(-s.submitTimeout)).
+		Order("created_at ASC").
+		Limit(50).
+		Find(&refunds).Error
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get pending refunds")
+		return
+	}
+
+	for i := range refunds {
+		if err := s.submitRefund(ctx, &refunds[i]); err != nil {
+			s.logger.WithError(err).WithField("refund_id", refunds[i].ID).Error("Failed to submit refund")
+		}
+	}
+}
+
+// submitRefund claims a refund and sends it to the payment's rail. Rails
+// dedupe refunds on their ID, so a refund sent again after its worker died
+// is not paid twice.
+func (s *RefundService) submitRefund(ctx context.Context, refund *models.Refund) error {
+	log := s.logger.WithFields(logrus.Fields{
+		"refund_id":  refund.ID,
+		"payment_id": refund.PaymentID,
+	})
+
+	// Claim the refund; of concurrent workers only one gets past this
+	claimed := true
+	fromPending := refund.Status == models.RefundStatusPending
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if fromPending {
+			err := s.transitionRefund(tx, refund, models.RefundStatusProcessing)
+			if err == ErrRefundConflict {
+				claimed = false
+				return nil
+			}
+			return err
+		}
+		result := tx.Model(&models.Refund{}).
+			Where("id = ? AND status = ? AND submitted_at IS NULL AND updated_at = ?", refund.ID, refund.Status, refund.UpdatedAt).
+			Update("updated_at", time.Now())
+		claimed = result.RowsAffected == 1
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to claim refund: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+	if fromPending && refund.Payment.PaymentIntent != nil {
+		s.emitRefundEvent(refund.Payment.PaymentIntent.MerchantID, "refund.processing", refund)
+	}
+
+	// Refunds go back over the rail the payment succeeded on
+	rail, railReference, err := s.paymentRail(ctx, refund.Payment)
+	if err != nil {
+		log.WithError(err).Error("No rail for refund")
+		failureMsg := err.Error()
+		refund.FailureMessage = &failureMsg
+		return s.settleRefund(ctx, refund, models.RefundStatusFailed)
+	}
+
+	railResp, err := rail.ProcessRefund(ctx, RailRefundRequest{
+		RefundID:          refund.ID,
+		OriginalPaymentID: railReference,
+		TransactionID:     refund.Payment.RailTransactionID,
+		Amount:            refund.Amount,
+		Currency:          refund.Currency,
+		Reason:            refund.Reason,
+	})
+	if err != nil {
+		log.WithError(err).Error("Rail refund processing failed")
+		failureMsg := fmt.Sprintf("%s refund processing failed: %s", rail.Name(), err)
+		refund.FailureMessage = &failureMsg
+		return s.settleRefund(ctx, refund, models.RefundStatusFailed)
+	}
+
+	// Update refund with the rail's response
+	switch {
+	case railResp.Status == models.RefundStatusPending:
+		now := time.Now()
+		refund.RefundReference = railResp.RefundReference
+		refund.SubmittedAt = &now
+		err := s.db.WithContext(ctx).Model(&models.Refund{}).
+			Where("id = ? AND status = ?", refund.ID, refund.Status).
+			Updates(map[string]interface{}{
+				"refund_reference": refund.RefundReference,
+				"submitted_at":     refund.SubmittedAt,
+				"updated_at":       now,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update refund with rail response: %w", err)
+		}
+		log.WithField("refund_reference", refund.RefundReference).Info("Refund pending with rail")
+		return nil
+	case railResp.Success:
+		refund.RefundReference = railResp.RefundReference
+		processedAt := railResp.ProcessedAt
+		refund.ProcessedAt = &processedAt
+		return s.settleRefund(ctx, refund, models.RefundStatusSucceeded)
+	default:
+		refund.FailureCode = railResp.FailureCode
+		refund.FailureMessage = railResp.FailureMessage
+		return s.settleRefund(ctx, refund, models.RefundStatusFailed)
+	}
+}
+
+// pollSubmittedRefunds checks the refunds the rail has yet to settle,
+// least recently checked first
+func (s *RefundService) pollSubmittedRefunds(ctx context.Context) {
+	var refunds []models.Refund
+	err := s.db.WithContext(ctx).
+		Select("id").
+		Where("status = ? AND submitted_at IS NOT NULL", models.RefundStatusProcessing).
+		Order("updated_at ASC").
+		Limit(50).
+		Find(&refunds).Error
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get submitted refunds")
+		return
+	}
+
+	for _, refund := range refunds {
+		if _, err := s.CheckRefundStatus(ctx, refund.ID); err != nil {
+			s.logger.WithError(err).WithField("refund_id", refund.ID).Error("Failed to check refund status")
+		}
+	}
+}
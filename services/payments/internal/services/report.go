@@ -0,0 +1,529 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/money"
+)
+
+var (
+	// ErrReportNotFound is returned for a report that does not exist
+	ErrReportNotFound = errors.New("report not found")
+	// ErrInvalidReport is returned for a report request with an unknown
+	// type or format, or a date range that is empty or too long
+	ErrInvalidReport = errors.New("invalid report")
+	// ErrReportNotReady is returned for the download of a report that is
+	// not generated yet, or failed
+	ErrReportNotReady = errors.New("report is not ready")
+)
+
+const (
+	// maxReportRange is the longest date range a report covers
+	maxReportRange = 366 * 24 * time.Hour
+	// maxReportRows is how many rows a report holds at most; a report that
+	// would hold more fails, for a shorter range to be asked for
+	maxReportRows = 100000
+	// reportBatchSize is how many rows are read from the database at a time
+	reportBatchSize = 1000
+	// reportGenerateTimeout is how long a report may be generating before
+	// another worker picks it up
+	reportGenerateTimeout = 10 * time.Minute
+)
+
+// ReportService exports merchants' payments, refunds and fees as CSV or
+// JSON. Reports are created pending and generated by the report worker,
+// which notifies the merchant with a report.ready or report.failed
+// webhook.
+type ReportService struct {
+	db             *gorm.DB
+	logger         *logrus.Logger
+	webhookService *WebhookService
+	wake           chan struct{}
+}
+
+// NewReportService creates a new report service
+func NewReportService(db *gorm.DB, logger *logrus.Logger, webhookService *WebhookService) *ReportService {
+	return &ReportService{
+		db:             db,
+		logger:         logger,
+		webhookService: webhookService,
+		wake:           make(chan struct{}, 1),
+	}
+}
+
+// CreateReportRequest asks for a report of a merchant's payments, refunds
+// or fees created in [From, To). Status filters payments and refunds.
+type CreateReportRequest struct {
+	MerchantID uuid.UUID `json:"merchant_id" binding:"required"`
+	Type       string    `json:"type" binding:"required"`
+	Format     string    `json:"format"` // Defaults to csv
+	From       time.Time `json:"from" binding:"required"`
+	To         time.Time `json:"to" binding:"required"`
+	Status     string    `json:"status"`
+}
+
+// CreateReport queues a report to be generated
+func (s *ReportService) CreateReport(ctx context.Context, req CreateReportRequest) (*models.Report, error) {
+	if req.Format == "" {
+		req.Format = models.ReportFormatCSV
+	}
+	switch req.Type {
+	case models.ReportTypePayments, models.ReportTypeRefunds:
+	case models.ReportTypeFees:
+		if req.Status != "" {
+			return nil, fmt.Errorf("%w: fees cannot be filtered by status", ErrInvalidReport)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidReport, req.Type)
+	}
+	if req.Format != models.ReportFormatCSV && req.Format != models.ReportFormatJSON {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidReport, req.Format)
+	}
+	if !req.To.After(req.From) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalidReport)
+	}
+	if req.To.Sub(req.From) > maxReportRange {
+		return nil, fmt.Errorf("%w: date range is longer than %d days", ErrInvalidReport, int(maxReportRange.Hours()/24))
+	}
+
+	report := &models.Report{
+		ID:           uuid.New(),
+		MerchantID:   req.MerchantID,
+		Type:         req.Type,
+		Format:       req.Format,
+		DateFrom:     req.From,
+		DateTo:       req.To,
+		FilterStatus: req.Status,
+		Status:       models.ReportStatusPending,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Create(report).Error; err != nil {
+		return nil, fmt.Errorf("failed to create report: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"report_id":   report.ID,
+		"merchant_id": report.MerchantID,
+		"type":        report.Type,
+	}).Info("Report queued")
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return report, nil
+}
+
+// GetReport retrieves a report by ID, without its content
+func (s *ReportService) GetReport(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+	var report models.Report
+	if err := s.db.WithContext(ctx).Omit("content").Where("id = ?", id).First(&report).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrReportNotFound
+		}
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	return &report, nil
+}
+
+// ListReports returns a merchant's reports, newest first, without their
+// content
+func (s *ReportService) ListReports(ctx context.Context, merchantID uuid.UUID) ([]models.Report, error) {
+	var reports []models.Report
+	err := s.db.WithContext(ctx).
+		Omit("content").
+		Where("merchant_id = ?", merchantID).
+		Order("created_at DESC").
+		Limit(100).
+		Find(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+	return reports, nil
+}
+
+// DownloadReport returns a ready report with its content
+func (s *ReportService) DownloadReport(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+	var report models.Report
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&report).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrReportNotFound
+		}
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	if report.Status != models.ReportStatusReady {
+		return nil, fmt.Errorf("%w: report is %s", ErrReportNotReady, report.Status)
+	}
+	return &report, nil
+}
+
+// StartReportWorker generates pending reports, as they are created and
+// every 5 seconds, until ctx is done
+func (s *ReportService) StartReportWorker(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	s.logger.Info("Starting report worker")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping report worker")
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.generatePendingReports(ctx)
+	}
+}
+
+// generatePendingReports generates the pending reports, and those a worker
+// claimed but did not finish in time, oldest first
+func (s *ReportService) generatePendingReports(ctx context.Context) {
+	var reports []models.Report
+	err := s.db.WithContext(ctx).
+		Omit("content").
+		Where("status = ? OR (status = ? AND updated_at < ?)",
+			models.ReportStatusPending, models.ReportStatusGenerating, time.Now().Add(-reportGenerateTimeout)).
+		Order("created_at ASC").
+		Limit(10).
+		Find(&reports).Error
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get pending reports")
+		return
+	}
+
+	for i := range reports {
+		if err := s.generateReport(ctx, &reports[i]); err != nil {
+			s.logger.WithError(err).WithField("report_id", reports[i].ID).Error("Failed to generate report")
+		}
+	}
+}
+
+// generateReport claims a report, writes its rows and stores the file
+func (s *ReportService) generateReport(ctx context.Context, report *models.Report) error {
+	// Claim the report; of concurrent workers only one gets past this
+	claim := s.db.WithContext(ctx).Model(&models.Report{}).
+		Where("id = ? AND (status = ? OR (status = ? AND updated_at < ?))", report.ID,
+			models.ReportStatusPending, models.ReportStatusGenerating, time.Now().Add(-reportGenerateTimeout)).
+		Updates(map[string]interface{}{"status": models.ReportStatusGenerating, "updated_at": time.Now()})
+	if claim.Error != nil {
+		return fmt.Errorf("failed to claim report: %w", claim.Error)
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+
+	log := s.logger.WithFields(logrus.Fields{
+		"report_id":   report.ID,
+		"merchant_id": report.MerchantID,
+		"type":        report.Type,
+	})
+
+	w := newReportWriter(report.Format)
+	var genErr error
+	switch report.Type {
+	case models.ReportTypePayments:
+		genErr = s.writePayments(ctx, report, w)
+	case models.ReportTypeRefunds:
+		genErr = s.writeRefunds(ctx, report, w)
+	case models.ReportTypeFees:
+		genErr = s.writeFees(ctx, report, w)
+	default:
+		genErr = fmt.Errorf("unknown report type %q", report.Type)
+	}
+	var content []byte
+	if genErr == nil {
+		content, genErr = w.finish()
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":       models.ReportStatusReady,
+		"row_count":    w.rows,
+		"content":      content,
+		"completed_at": now,
+	}
+	if genErr != nil {
+		updates["status"] = models.ReportStatusFailed
+		updates["row_count"] = 0
+		updates["content"] = nil
+		updates["error"] = genErr.Error()
+	}
+	if err := s.db.WithContext(ctx).Model(&models.Report{}).Where("id = ?", report.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update report: %w", err)
+	}
+
+	report.Status = updates["status"].(string)
+	report.RowCount = updates["row_count"].(int)
+	report.CompletedAt = &now
+	if genErr != nil {
+		report.Error = genErr.Error()
+		log.WithError(genErr).Warn("Report failed")
+	} else {
+		log.WithField("rows", report.RowCount).Info("Report ready")
+	}
+	go s.webhookService.TriggerWebhook(context.Background(), report.MerchantID, "report."+report.Status, report)
+	return nil
+}
+
+// reportPaymentRow is a row of a payments report
+type reportPaymentRow struct {
+	ID                uuid.UUID       `json:"id"`
+	PaymentIntentID   uuid.UUID       `json:"payment_intent_id"`
+	Amount            decimal.Decimal `json:"amount"`
+	Currency          string          `json:"currency"`
+	Status            string          `json:"status"`
+	PaymentMethod     string          `json:"payment_method"`
+	RailTransactionID string          `json:"rail_transaction_id"`
+	FailureCode       *string         `json:"failure_code"`
+	CreatedAt         time.Time       `json:"created_at"`
+	ProcessedAt       *time.Time      `json:"processed_at"`
+	SettledAt         *time.Time      `json:"settled_at"`
+}
+
+var reportPaymentColumns = []string{
+	"id", "payment_intent_id", "amount", "currency", "status", "payment_method",
+	"rail_transaction_id", "failure_code", "created_at", "processed_at", "settled_at",
+}
+
+func (r reportPaymentRow) record() []string {
+	return []string{
+		r.ID.String(), r.PaymentIntentID.String(), money.Format(r.Amount, r.Currency), r.Currency, r.Status, r.PaymentMethod,
+		r.RailTransactionID, reportString(r.FailureCode), reportTime(&r.CreatedAt), reportTime(r.ProcessedAt), reportTime(r.SettledAt),
+	}
+}
+
+// writePayments writes the payments of the report's merchant created in its
+// range
+func (s *ReportService) writePayments(ctx context.Context, report *models.Report, w *reportWriter) error {
+	w.header(reportPaymentColumns)
+	query := s.db.WithContext(ctx).
+		Table("payments").
+		Select("payments.id, payments.payment_intent_id, payments.amount, payments.currency, payments.status, "+
+			"payments.payment_method, payments.rail_transaction_id, payments.failure_code, payments.created_at, "+
+			"payments.processed_at, payments.settled_at").
+		Joins("JOIN payment_intents ON payment_intents.id = payments.payment_intent_id").
+		Where("payment_intents.merchant_id = ? AND payments.created_at >= ? AND payments.created_at < ?",
+			report.MerchantID, report.DateFrom, report.DateTo)
+	if report.FilterStatus != "" {
+		query = query.Where("payments.status = ?", report.FilterStatus)
+	}
+
+	var rows []reportPaymentRow
+	return writeBatches(query.Order("payments.created_at ASC, payments.id ASC"), &rows, func() error {
+		for _, row := range rows {
+			if err := w.write(row, row.record()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// reportRefundRow is a row of a refunds report
+type reportRefundRow struct {
+	ID              uuid.UUID       `json:"id"`
+	PaymentID       uuid.UUID       `json:"payment_id"`
+	Amount          decimal.Decimal `json:"amount"`
+	Currency        string          `json:"currency"`
+	Status          string          `json:"status"`
+	Reason          string          `json:"reason"`
+	RefundReference string          `json:"refund_reference"`
+	FailureCode     *string         `json:"failure_code"`
+	CreatedAt       time.Time       `json:"created_at"`
+	ProcessedAt     *time.Time      `json:"processed_at"`
+}
+
+var reportRefundColumns = []string{
+	"id", "payment_id", "amount", "currency", "status", "reason",
+	"refund_reference", "failure_code", "created_at", "processed_at",
+}
+
+func (r reportRefundRow) record() []string {
+	return []string{
+		r.ID.String(), r.PaymentID.String(), money.Format(r.Amount, r.Currency), r.Currency, r.Status, r.Reason,
+		r.RefundReference, reportString(r.FailureCode), reportTime(&r.CreatedAt), reportTime(r.ProcessedAt),
+	}
+}
+
+// writeRefunds writes the refunds of the report's merchant created in its
+// range
+func (s *ReportService) writeRefunds(ctx context.Context, report *models.Report, w *reportWriter) error {
+	w.header(reportRefundColumns)
+	query := s.db.WithContext(ctx).
+		Table("refunds").
+		Select("refunds.id, refunds.payment_id, refunds.amount, refunds.currency, refunds.status, refunds.reason, "+
+			"refunds.refund_reference, refunds.failure_code, refunds.created_at, refunds.processed_at").
+		Joins("JOIN payments ON payments.id = refunds.payment_id").
+		Joins("JOIN payment_intents ON payment_intents.id = payments.payment_intent_id").
+		Where("payment_intents.merchant_id = ? AND refunds.created_at >= ? AND refunds.created_at < ?",
+			report.MerchantID, report.DateFrom, report.DateTo)
+	if report.FilterStatus != "" {
+		query = query.Where("refunds.status = ?", report.FilterStatus)
+	}
+
+	var rows []reportRefundRow
+	return writeBatches(query.Order("refunds.created_at ASC, refunds.id ASC"), &rows, func() error {
+		for _, row := range rows {
+			if err := w.write(row, row.record()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// reportFeeRow is a row of a fees report: the platform's fee on a payment,
+// as charged to the merchant's ledger account
+type reportFeeRow struct {
+	PaymentID uuid.UUID       `json:"payment_id"`
+	Fee       decimal.Decimal `json:"fee"`
+	Currency  string          `json:"currency"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+var reportFeeColumns = []string{"payment_id", "fee", "currency", "created_at"}
+
+func (r reportFeeRow) record() []string {
+	return []string{r.PaymentID.String(), money.Format(r.Fee, r.Currency), r.Currency, reportTime(&r.CreatedAt)}
+}
+
+// writeFees writes the fees charged to the report's merchant in its range
+func (s *ReportService) writeFees(ctx context.Context, report *models.Report, w *reportWriter) error {
+	w.header(reportFeeColumns)
+	query := s.db.WithContext(ctx).
+		Table("ledger_entries").
+		Select("ledger_entries.reference_id AS payment_id, ledger_entries.debit_amount AS fee, "+
+			"ledger_entries.currency, ledger_entries.created_at").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_entries.account_id").
+		Where("ledger_accounts.merchant_id = ? AND ledger_entries.reference_type = ? AND ledger_entries.created_at >= ? AND ledger_entries.created_at < ?",
+			report.MerchantID, "payment_fee", report.DateFrom, report.DateTo).
+		Order("ledger_entries.created_at ASC, ledger_entries.id ASC")
+
+	var rows []reportFeeRow
+	return writeBatches(query, &rows, func() error {
+		for _, row := range rows {
+			if err := w.write(row, row.record()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeBatches reads query's rows into dest a batch at a time, calling
+// write after each
+func writeBatches(query *gorm.DB, dest interface{}, write func() error) error {
+	for offset := 0; ; offset += reportBatchSize {
+		result := query.Session(&gorm.Session{}).Offset(offset).Limit(reportBatchSize).Scan(dest)
+		if result.Error != nil {
+			return fmt.Errorf("failed to read report rows: %w", result.Error)
+		}
+		if err := write(); err != nil {
+			return err
+		}
+		if result.RowsAffected < reportBatchSize {
+			return nil
+		}
+	}
+}
+
+// reportWriter writes a report's rows as CSV, with a header row, or as a
+// JSON array
+type reportWriter struct {
+	format string
+	buf    bytes.Buffer
+	csv    *csv.Writer
+	rows   int
+}
+
+func newReportWriter(format string) *reportWriter {
+	w := &reportWriter{format: format}
+	if format == models.ReportFormatCSV {
+		w.csv = csv.NewWriter(&w.buf)
+	} else {
+		w.buf.WriteByte('[')
+	}
+	return w
+}
+
+func (w *reportWriter) header(columns []string) {
+	if w.csv != nil {
+		w.csv.Write(columns)
+	}
+}
+
+// write adds a row, as record in CSV and row in JSON
+func (w *reportWriter) write(row interface{}, record []string) error {
+	if w.rows >= maxReportRows {
+		return fmt.Errorf("report has more than %d rows, ask for a shorter date range", maxReportRows)
+	}
+	w.rows++
+	if w.csv != nil {
+		return w.csv.Write(record)
+	}
+	if w.rows > 1 {
+		w.buf.WriteByte(',')
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		return fmt.Errorf("failed to encode report row: %w", err)
+	}
+	w.buf.Write(data)
+	return nil
+}
+
+// finish returns the report's file
+func (w *reportWriter) finish() ([]byte, error) {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return nil, fmt.Errorf("failed to write report: %w", err)
+		}
+	} else {
+		w.buf.WriteByte(']')
+	}
+	return w.buf.Bytes(), nil
+}
+
+func reportString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func reportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ReportContentType returns the content type of a report's file
+func ReportContentType(format string) string {
+	if format == models.ReportFormatJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// ReportFilename returns the name a report's file is downloaded as
+func ReportFilename(report *models.Report) string {
+	return fmt.Sprintf("%s-%s-%s.%s", report.Type,
+		report.DateFrom.UTC().Format("20060102"), report.DateTo.UTC().Format("20060102"), report.Format)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// payAs makes a card payment of amount for merchantID
+func (env *testEnv) payAs(t *testing.T, merchantID uuid.UUID, amount string) *models.Payment {
+	intent := env.createIntent(t, amount, "INR", RailCard, 15*time.Minute)
+	require.NoError(t, env.db.Model(intent).Update("merchant_id", merchantID).Error)
+	payment, _ := env.payments.CreatePayment(context.Background(), CreatePaymentRequest{PaymentIntentID: intent.ID, CardToken: "tok_visa"})
+	require.NotNil(t, payment)
+	return payment
+}
+
+// generate creates a report over the last hour and generates it
+func generate(t *testing.T, reports *ReportService, req CreateReportRequest) *models.Report {
+	ctx := context.Background()
+	req.From, req.To = time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	report, err := reports.CreateReport(ctx, req)
+	require.NoError(t, err)
+
+	_, err = reports.DownloadReport(ctx, report.ID)
+	assert.ErrorIs(t, err, ErrReportNotReady)
+
+	reports.generatePendingReports(ctx)
+	ready, err := reports.DownloadReport(ctx, report.ID)
+	require.NoError(t, err)
+	return ready
+}
+
+func readCSV(t *testing.T, content []byte) [][]string {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestReportService_RejectsInvalidReports(t *testing.T) {
+	reports := NewReportService(setupTestDB(t), testLogger(), nil)
+	now := time.Now()
+	valid := CreateReportRequest{MerchantID: uuid.New(), Type: models.ReportTypePayments, From: now.Add(-time.Hour), To: now}
+
+	for _, tc := range []struct {
+		name   string
+		modify func(req *CreateReportRequest)
+	}{
+		{"unknown type", func(req *CreateReportRequest) { req.Type = "chargebacks" }},
+		{"unknown format", func(req *CreateReportRequest) { req.Format = "xlsx" }},
+		{"fees by status", func(req *CreateReportRequest) { req.Type, req.Status = models.ReportTypeFees, "succeeded" }},
+		{"empty range", func(req *CreateReportRequest) { req.To = req.From }},
+		{"range too long", func(req *CreateReportRequest) { req.From = req.To.Add(-maxReportRange - time.Hour) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := valid
+			tc.modify(&req)
+			_, err := reports.CreateReport(context.Background(), req)
+			assert.ErrorIs(t, err, ErrInvalidReport)
+		})
+	}
+}
+
+func TestReportService_PaymentsCSV(t *testing.T) {
+	env := newTestEnv(t)
+	reports := NewReportService(env.db, testLogger(), env.webhooks)
+	merchantID := uuid.New()
+
+	succeeded := env.payAs(t, merchantID, "100.00")
+	env.card.paymentStatus = models.PaymentStatusFailed
+	failed := env.payAs(t, merchantID, "12.50")
+	env.payAs(t, uuid.New(), "999.00") // Another merchant's
+
+	report := generate(t, reports, CreateReportRequest{MerchantID: merchantID, Type: models.ReportTypePayments})
+	assert.Equal(t, models.ReportStatusReady, report.Status)
+	assert.Equal(t, 2, report.RowCount)
+	records := readCSV(t, report.Content)
+	require.Len(t, records, 3)
+	assert.Equal(t, reportPaymentColumns, records[0])
+	assert.Equal(t, []string{succeeded.ID.String(), "100.00", models.PaymentStatusSucceeded},
+		[]string{records[1][0], records[1][2], records[1][4]})
+	assert.Equal(t, []string{failed.ID.String(), "12.50", models.PaymentStatusFailed, "DECLINED"},
+		[]string{records[2][0], records[2][2], records[2][4], records[2][7]})
+
+	filtered := generate(t, reports, CreateReportRequest{MerchantID: merchantID, Type: models.ReportTypePayments, Status: models.PaymentStatusFailed})
+	records = readCSV(t, filtered.Content)
+	require.Len(t, records, 2)
+	assert.Equal(t, failed.ID.String(), records[1][0])
+}
+
+func TestReportService_RefundsJSON(t *testing.T) {
+	env := newTestEnv(t)
+	reports := NewReportService(env.db, testLogger(), env.webhooks)
+	merchantID := uuid.New()
+	payment := env.payAs(t, merchantID, "100.00")
+	refund := env.refund(t, payment, "25.00")
+	env.refunds.submitPendingRefunds(context.Background())
+
+	report := generate(t, reports, CreateReportRequest{MerchantID: merchantID, Type: models.ReportTypeRefunds, Format: models.ReportFormatJSON})
+	var rows []map[string]interface{}
+	require.NoError(t, json.Unmarshal(report.Content, &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, refund.ID.String(), rows[0]["id"])
+	assert.Equal(t, payment.ID.String(), rows[0]["payment_id"])
+	assert.Equal(t, "25", rows[0]["amount"])
+	assert.Equal(t, models.RefundStatusSucceeded, rows[0]["status"])
+	assert.Equal(t, "application/json", ReportContentType(report.Format))
+}
+
+func TestReportService_FeesCSV(t *testing.T) {
+	env := newTestEnv(t)
+	reports := NewReportService(env.db, testLogger(), env.webhooks)
+	merchantID := uuid.New()
+	payment := env.payAs(t, merchantID, "100.00")
+
+	// 200 basis points of the payment
+	report := generate(t, reports, CreateReportRequest{MerchantID: merchantID, Type: models.ReportTypeFees})
+	records := readCSV(t, report.Content)
+	require.Len(t, records, 2)
+	assert.Equal(t, reportFeeColumns, records[0])
+	assert.Equal(t, []string{payment.ID.String(), "2.00", "INR"}, records[1][:3])
+}
+
+func TestReportService_EmptyReport(t *testing.T) {
+	env := newTestEnv(t)
+	reports := NewReportService(env.db, testLogger(), env.webhooks)
+
+	report := generate(t, reports, CreateReportRequest{MerchantID: uuid.New(), Type: models.ReportTypePayments, Format: models.ReportFormatJSON})
+	assert.Equal(t, models.ReportStatusReady, report.Status)
+	assert.Zero(t, report.RowCount)
+	assert.JSONEq(t, "[]", string(report.Content))
+
+	// A generated report is not generated again
+	reports.generatePendingReports(context.Background())
+	again, err := reports.GetReport(context.Background(), report.ID)
+	require.NoError(t, err)
+	assert.Equal(t, report.CompletedAt.Unix(), again.CompletedAt.Unix())
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,17 +16,28 @@ import (
 	"github.com/suuupra/payments/internal/models"
 )
 
-// RiskService handles risk assessment for payments
+// RiskService assesses payments with the risk engine: it extracts the
+// features of a payment, evaluates the enabled risk rules over them, and
+// decides from the matched rules whether to allow, review or block it
 type RiskService struct {
-	db     *gorm.DB
-	logger *logrus.Logger
+	db              *gorm.DB
+	logger          *logrus.Logger
+	reviewThreshold float64
+	blockThreshold  float64
+
+	mu          sync.Mutex
+	expressions map[string]*RiskExpression // Compiled expressions of the enabled rules, by source
 }
 
-// NewRiskService creates a new risk service
-func NewRiskService(db *gorm.DB, logger *logrus.Logger) *RiskService {
+// NewRiskService creates a new risk service. Payments scoring at least
+// reviewThreshold, or blockThreshold, percent are reviewed, or blocked.
+func NewRiskService(db *gorm.DB, logger *logrus.Logger, reviewThreshold, blockThreshold int) *RiskService {
 	return &RiskService{
-		db:     db,
-		logger: logger,
+		db:              db,
+		logger:          logger,
+		reviewThreshold: float64(reviewThreshold) / 100,
+		blockThreshold:  float64(blockThreshold) / 100,
+		expressions:     make(map[string]*RiskExpression),
 	}
 }
 
@@ -37,6 +50,8 @@ type RiskAssessmentRequest struct {
 	MerchantID      uuid.UUID       `json:"merchant_id"`
 	CustomerID      *uuid.UUID      `json:"customer_id"`
 	IPAddress       string          `json:"ip_address"`
+	IPCountry       string          `json:"ip_country"`
+	BillingCountry  string          `json:"billing_country"`
 	UserAgent       string          `json:"user_agent"`
 	DeviceID        *string         `json:"device_id"`
 }
@@ -51,6 +66,19 @@ type RiskAssessmentResult struct {
 	Rules      []string
 }
 
+// compiledRiskRule is a risk rule with its compiled expression
+type compiledRiskRule struct {
+	models.RiskRule
+	expression *RiskExpression
+}
+
+// riskEvaluation is the outcome of evaluating rules over one payment
+type riskEvaluation struct {
+	score    float64
+	decision string
+	matched  []string
+}
+
 // AssessRisk performs risk assessment on a payment
 func (s *RiskService) AssessRisk(ctx context.Context, req RiskAssessmentRequest) (*RiskAssessmentResult, error) {
 	log := s.logger.WithFields(logrus.Fields{
@@ -62,77 +90,31 @@ func (s *RiskService) AssessRisk(ctx context.Context, req RiskAssessmentRequest)
 
 	log.Info("Starting risk assessment")
 
-	// Initialize risk factors
-	factors := make(map[string]interface{})
-	rules := make([]string, 0)
-	riskScore := 0.0
-
-	// Amount-based risk assessment
-	amountRisk := s.assessAmountRisk(req.Amount)
-	factors["amount_risk"] = amountRisk
-	riskScore += amountRisk
-
-	// Velocity risk assessment
-	velocityRisk, err := s.assessVelocityRisk(ctx, req)
+	rules, err := s.activeRules(ctx)
 	if err != nil {
-		log.WithError(err).Warn("Failed to assess velocity risk, defaulting to medium risk")
-		velocityRisk = 0.5
-	}
-	factors["velocity_risk"] = velocityRisk
-	riskScore += velocityRisk
-
-	// Device risk assessment
-	deviceRisk := s.assessDeviceRisk(req.DeviceID)
-	factors["device_risk"] = deviceRisk
-	riskScore += deviceRisk
-
-	// IP address risk assessment
-	ipRisk := s.assessIPRisk(req.IPAddress)
-	factors["ip_risk"] = ipRisk
-	riskScore += ipRisk
-
-	// Time-based risk assessment
-	timeRisk := s.assessTimeRisk()
-	factors["time_risk"] = timeRisk
-	riskScore += timeRisk
-
-	// Merchant risk assessment
-	merchantRisk, err := s.assessMerchantRisk(ctx, req.MerchantID)
-	if err != nil {
-		log.WithError(err).Warn("Failed to assess merchant risk, defaulting to low risk")
-		merchantRisk = 0.1
-	}
-	factors["merchant_risk"] = merchantRisk
-	riskScore += merchantRisk
-
-	// Normalize risk score (0.0 to 1.0)
-	riskScore = riskScore / 6.0 // We have 6 risk factors
-
-	// Apply risk rules
-	if riskScore, rules = s.applyRiskRules(riskScore, factors, req); riskScore > 1.0 {
-		riskScore = 1.0
+		return nil, err
 	}
-
-	// Determine risk level and decision
-	riskLevel, decision := s.determineRiskDecision(riskScore)
+	features := s.extractFeatures(ctx, req)
+	evaluation := s.evaluate(rules, features, req.MerchantID)
+	riskLevel := s.riskLevel(evaluation.score)
 
 	log.WithFields(logrus.Fields{
-		"risk_score": riskScore,
+		"risk_score": evaluation.score,
 		"risk_level": riskLevel,
-		"decision":   decision,
-		"factors":    factors,
-		"rules":      rules,
+		"decision":   evaluation.decision,
+		"rules":      evaluation.matched,
 	}).Info("Risk assessment completed")
 
-	// Create risk assessment record
+	// Create risk assessment record. The features are kept with it, for
+	// rules to be tested against.
 	assessment := &models.RiskAssessment{
 		ID:              uuid.New(),
 		PaymentIntentID: req.PaymentIntentID,
-		RiskScore:       riskScore,
+		RiskScore:       evaluation.score,
 		RiskLevel:       riskLevel,
-		Decision:        decision,
-		Factors:         factors,
-		Rules:           rules,
+		Decision:        evaluation.decision,
+		Factors:         features,
+		Rules:           evaluation.matched,
 		DeviceID:        req.DeviceID,
 		IPAddress:       req.IPAddress,
 		UserAgent:       req.UserAgent,
@@ -147,225 +129,228 @@ func (s *RiskService) AssessRisk(ctx context.Context, req RiskAssessmentRequest)
 
 	return &RiskAssessmentResult{
 		Assessment: assessment,
-		RiskScore:  riskScore,
+		RiskScore:  evaluation.score,
 		RiskLevel:  riskLevel,
-		Decision:   decision,
-		Factors:    factors,
-		Rules:      rules,
+		Decision:   evaluation.decision,
+		Factors:    features,
+		Rules:      evaluation.matched,
 	}, nil
 }
 
-// assessAmountRisk assesses risk based on transaction amount
-func (s *RiskService) assessAmountRisk(amount decimal.Decimal) float64 {
-	// Higher amounts carry higher risk
-	if amount.GreaterThan(decimal.NewFromInt(100000)) { // > 1 lakh
-		return 0.9
-	} else if amount.GreaterThan(decimal.NewFromInt(50000)) { // > 50k
-		return 0.7
-	} else if amount.GreaterThan(decimal.NewFromInt(10000)) { // > 10k
-		return 0.4
-	} else if amount.GreaterThan(decimal.NewFromInt(1000)) { // > 1k
-		return 0.2
+// activeRules returns the enabled risk rules, compiled. Expressions are
+// compiled once, when a rule is first loaded or its expression changes. A
+// rule that does not compile, e.g. one saved before its expression was
+// checked as it is now, is skipped; it must not fail payments.
+func (s *RiskService) activeRules(ctx context.Context) ([]compiledRiskRule, error) {
+	var rules []models.RiskRule
+	err := s.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Order("name ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk rules: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expressions := make(map[string]*RiskExpression, len(rules))
+	compiled := make([]compiledRiskRule, 0, len(rules))
+	for _, rule := range rules {
+		expression, ok := s.expressions[rule.Expression]
+		if !ok {
+			if expression, err = CompileRiskExpression(rule.Expression); err != nil {
+				s.logger.WithError(err).WithField("rule", rule.Name).Warn("Skipping risk rule that does not compile")
+				continue
+			}
+		}
+		expressions[rule.Expression] = expression
+		compiled = append(compiled, compiledRiskRule{RiskRule: rule, expression: expression})
 	}
-	return 0.1
+	// Expressions of rules no longer enabled are dropped
+	s.expressions = expressions
+	return compiled, nil
 }
 
-// assessVelocityRisk assesses risk based on transaction velocity
-func (s *RiskService) assessVelocityRisk(ctx context.Context, req RiskAssessmentRequest) (float64, error) {
-	// Count transactions in the last hour for this customer/merchant
-	since := time.Now().Add(-1 * time.Hour)
+// evaluate matches rules that apply to merchantID against features. The
+// score is the sum of the matched rules' weights, capped at 1; a matched
+// rule with a review or block action raises the decision to at least that.
+func (s *RiskService) evaluate(rules []compiledRiskRule, features map[string]interface{}, merchantID uuid.UUID) riskEvaluation {
+	evaluation := riskEvaluation{matched: make([]string, 0)}
+	reviewed, blocked := false, false
 
-	var count int64
-	query := s.db.WithContext(ctx).Model(&models.Payment{}).
-		Joins("JOIN payment_intents ON payments.payment_intent_id = payment_intents.id").
-		Where("payment_intents.created_at > ?", since)
+	for _, rule := range rules {
+		if rule.MerchantID != nil && *rule.MerchantID != merchantID {
+			continue
+		}
+		matched, err := rule.expression.Match(features)
+		if err != nil {
+			// A broken rule must not fail payments; it is skipped
+			s.logger.WithError(err).WithField("rule", rule.Name).Warn("Failed to evaluate risk rule")
+			continue
+		}
+		if !matched {
+			continue
+		}
 
-	if req.CustomerID != nil {
-		query = query.Where("payment_intents.customer_id = ?", *req.CustomerID)
-	} else {
-		query = query.Where("payment_intents.merchant_id = ?", req.MerchantID)
+		evaluation.matched = append(evaluation.matched, rule.Name)
+		evaluation.score += rule.Weight
+		switch rule.Action {
+		case models.RiskRuleActionReview:
+			reviewed = true
+		case models.RiskRuleActionBlock:
+			blocked = true
+		}
 	}
-
-	err := query.Count(&count).Error
-	if err != nil {
-		return 0.5, err
+	if evaluation.score > 1.0 {
+		evaluation.score = 1.0
 	}
 
-	// High velocity = higher risk
-	if count > 10 {
-		return 1.0, nil
-	} else if count > 5 {
-		return 0.8, nil
-	} else if count > 3 {
-		return 0.5, nil
-	} else if count > 1 {
-		return 0.3, nil
+	switch {
+	case blocked || evaluation.score >= s.blockThreshold:
+		evaluation.decision = models.RiskDecisionBlock
+	case reviewed || evaluation.score >= s.reviewThreshold:
+		evaluation.decision = models.RiskDecisionReview
+	default:
+		evaluation.decision = models.RiskDecisionAllow
 	}
-	return 0.1, nil
+	return evaluation
 }
 
-// assessDeviceRisk assesses risk based on device information
-func (s *RiskService) assessDeviceRisk(deviceID *string) float64 {
-	if deviceID == nil {
-		// No device ID = higher risk
-		return 0.6
+// riskLevel determines the risk level of a score
+func (s *RiskService) riskLevel(riskScore float64) string {
+	if riskScore >= s.blockThreshold {
+		return models.RiskLevelHigh
+	} else if riskScore >= s.reviewThreshold {
+		return models.RiskLevelMedium
 	}
+	return models.RiskLevelLow
+}
 
-	// Device fingerprinting and history analysis
-	ctx := context.Background()
-
-	// Check device reputation in our database
-	var deviceHistory struct {
-		SuccessfulTransactions int64
-		FailedTransactions     int64
-		FirstSeen              time.Time
-		LastSeen               time.Time
+// extractFeatures extracts the features of RiskFeatures from req and the
+// payment history. A feature whose history cannot be read is left out, and
+// so evaluates to its zero value.
+func (s *RiskService) extractFeatures(ctx context.Context, req RiskAssessmentRequest) map[string]interface{} {
+	now := time.Now()
+	amount, _ := req.Amount.Float64()
+	ipCountry := strings.ToUpper(req.IPCountry)
+	billingCountry := strings.ToUpper(req.BillingCountry)
+
+	features := map[string]interface{}{
+		"amount":           amount,
+		"currency":         req.Currency,
+		"payment_method":   req.PaymentMethod,
+		"customer_present": req.CustomerID != nil,
+		"device_present":   req.DeviceID != nil && *req.DeviceID != "",
+		"ip_present":       req.IPAddress != "",
+		"ip_private":       false,
+		"ip_country":       ipCountry,
+		"billing_country":  billingCountry,
+		"geo_mismatch":     ipCountry != "" && billingCountry != "" && ipCountry != billingCountry,
+		"hour":             float64(now.Hour()),
+		"weekend":          now.Weekday() == time.Saturday || now.Weekday() == time.Sunday,
+	}
+
+	if err := s.velocityFeatures(ctx, req, features); err != nil {
+		s.logger.WithError(err).Warn("Failed to extract velocity features")
+	}
+	if features["device_present"].(bool) {
+		if err := s.deviceFeatures(ctx, *req.DeviceID, features); err != nil {
+			s.logger.WithError(err).Warn("Failed to extract device features")
+		}
 	}
-
-	// Query device transaction history from the last 30 days
-	since := time.Now().Add(-30 * 24 * time.Hour)
-
-	err := s.db.WithContext(ctx).Raw(`
-		SELECT 
-			COUNT(CASE WHEN p.status = 'succeeded' THEN 1 END) as successful_transactions,
-			COUNT(CASE WHEN p.status = 'failed' THEN 1 END) as failed_transactions,
-			MIN(ra.created_at) as first_seen,
-			MAX(ra.created_at) as last_seen
-		FROM risk_assessments ra
-		JOIN payments p ON ra.payment_intent_id = p.payment_intent_id
-		WHERE ra.device_id = ? AND ra.created_at > ?
-	`, *deviceID, since).Scan(&deviceHistory).Error
-
-	if err != nil {
-		s.logger.WithError(err).Warn("Failed to query device history, using medium risk")
-		return 0.5
+	if req.IPAddress != "" {
+		ip := net.ParseIP(req.IPAddress)
+		features["ip_private"] = ip == nil || isPrivateIP(ip)
+		if err := s.ipFeatures(ctx, req.IPAddress, features); err != nil {
+			s.logger.WithError(err).Warn("Failed to extract IP features")
+		}
+	}
+	if err := s.merchantFeatures(ctx, req.MerchantID, features); err != nil {
+		s.logger.WithError(err).Warn("Failed to extract merchant features")
 	}
 
-	totalTransactions := deviceHistory.SuccessfulTransactions + deviceHistory.FailedTransactions
+	return features
+}
 
-	// New device (no history) = medium-high risk
-	if totalTransactions == 0 {
-		return 0.6
+// velocityFeatures counts the payments of the last hour and day, and
+// totals those of the day, for the customer, or the merchant without one
+func (s *RiskService) velocityFeatures(ctx context.Context, req RiskAssessmentRequest, features map[string]interface{}) error {
+	now := time.Now()
+	var velocity struct {
+		Count1h   int64
+		Count24h  int64
+		Amount24h float64
 	}
 
-	// Calculate failure rate
-	failureRate := float64(deviceHistory.FailedTransactions) / float64(totalTransactions)
-
-	// Device age factor (newer devices are riskier)
-	daysSinceFirstSeen := time.Since(deviceHistory.FirstSeen).Hours() / 24
-	ageFactor := 1.0
-	if daysSinceFirstSeen < 1 {
-		ageFactor = 0.8 // Very new device
-	} else if daysSinceFirstSeen < 7 {
-		ageFactor = 0.6 // New device
-	} else if daysSinceFirstSeen < 30 {
-		ageFactor = 0.4 // Established device
+	query := s.db.WithContext(ctx).Model(&models.Payment{}).
+		Select("COUNT(CASE WHEN payments.created_at > ? THEN 1 END) AS count1h, COUNT(*) AS count24h, COALESCE(SUM(payments.amount), 0) AS amount24h", now.Add(-time.Hour)).
+		Joins("JOIN payment_intents ON payments.payment_intent_id = payment_intents.id").
+		Where("payments.created_at > ?", now.Add(-24*time.Hour))
+	if req.CustomerID != nil {
+		query = query.Where("payment_intents.customer_id = ?", *req.CustomerID)
 	} else {
-		ageFactor = 0.2 // Trusted device
-	}
-
-	// Frequency factor (regular usage is good)
-	daysSinceLastSeen := time.Since(deviceHistory.LastSeen).Hours() / 24
-	frequencyFactor := 0.2
-	if daysSinceLastSeen > 30 {
-		frequencyFactor = 0.6 // Dormant device
-	} else if daysSinceLastSeen > 7 {
-		frequencyFactor = 0.4 // Infrequent usage
+		query = query.Where("payment_intents.merchant_id = ?", req.MerchantID)
 	}
-
-	// Combine factors
-	baseRisk := failureRate * 0.7 // Failure rate has 70% weight
-	riskScore := baseRisk + (ageFactor * 0.2) + (frequencyFactor * 0.1)
-
-	// Cap at 1.0
-	if riskScore > 1.0 {
-		riskScore = 1.0
+	if err := query.Scan(&velocity).Error; err != nil {
+		return err
 	}
 
-	return riskScore
+	features["velocity_1h"] = float64(velocity.Count1h)
+	features["velocity_24h"] = float64(velocity.Count24h)
+	features["amount_24h"] = velocity.Amount24h
+	return nil
 }
 
-// assessIPRisk assesses risk based on IP address
-func (s *RiskService) assessIPRisk(ipAddress string) float64 {
-	if ipAddress == "" {
-		return 0.8
-	}
-
-	// Check for localhost/private IPs (higher risk in production)
-	if ipAddress == "127.0.0.1" || ipAddress == "localhost" {
-		return 0.9
-	}
-
-	// Parse IP for geolocation and reputation checking
-	ip := net.ParseIP(ipAddress)
-	if ip == nil {
-		return 0.8 // Invalid IP
+// deviceFeatures reads the device's payments of the last 30 days
+func (s *RiskService) deviceFeatures(ctx context.Context, deviceID string, features map[string]interface{}) error {
+	var history struct {
+		SuccessfulTransactions int64
+		FailedTransactions     int64
 	}
 
-	// Check if IP is in private ranges (RFC 1918)
-	if isPrivateIP(ip) {
-		return 0.7 // Private IPs are medium risk
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT 
+			COUNT(CASE WHEN p.status = ? THEN 1 END) as successful_transactions,
+			COUNT(CASE WHEN p.status = ? THEN 1 END) as failed_transactions
+		FROM risk_assessments ra
+		JOIN payments p ON ra.payment_intent_id = p.payment_intent_id
+		WHERE ra.device_id = ? AND ra.created_at > ?
+	`, models.PaymentStatusSucceeded, models.PaymentStatusFailed, deviceID, time.Now().Add(-30*24*time.Hour)).Scan(&history).Error
+	if err != nil {
+		return err
 	}
 
-	ctx := context.Background()
+	total := history.SuccessfulTransactions + history.FailedTransactions
+	features["device_new"] = total == 0
+	features["device_failure_rate"] = rate(history.FailedTransactions, total)
+	return nil
+}
 
-	// Check IP reputation in our database
-	var ipHistory struct {
+// ipFeatures reads the IP address's payments of the last 7 days
+func (s *RiskService) ipFeatures(ctx context.Context, ipAddress string, features map[string]interface{}) error {
+	var history struct {
 		SuccessfulTransactions int64
 		FailedTransactions     int64
-		FirstSeen              time.Time
-		LastSeen               time.Time
 		BlockedCount           int64
 	}
 
-	since := time.Now().Add(-7 * 24 * time.Hour) // Last 7 days
-
 	err := s.db.WithContext(ctx).Raw(`
 		SELECT 
-			COUNT(CASE WHEN p.status = 'succeeded' THEN 1 END) as successful_transactions,
-			COUNT(CASE WHEN p.status = 'failed' THEN 1 END) as failed_transactions,
-			MIN(ra.created_at) as first_seen,
-			MAX(ra.created_at) as last_seen,
-			COUNT(CASE WHEN ra.decision = 'block' THEN 1 END) as blocked_count
+			COUNT(CASE WHEN p.status = ? THEN 1 END) as successful_transactions,
+			COUNT(CASE WHEN p.status = ? THEN 1 END) as failed_transactions,
+			COUNT(CASE WHEN ra.decision = ? THEN 1 END) as blocked_count
 		FROM risk_assessments ra
 		JOIN payments p ON ra.payment_intent_id = p.payment_intent_id
 		WHERE ra.ip_address = ? AND ra.created_at > ?
-	`, ipAddress, since).Scan(&ipHistory).Error
-
+	`, models.PaymentStatusSucceeded, models.PaymentStatusFailed, models.RiskDecisionBlock, ipAddress, time.Now().Add(-7*24*time.Hour)).Scan(&history).Error
 	if err != nil {
-		s.logger.WithError(err).Warn("Failed to query IP history, using default risk")
-		return 0.4
-	}
-
-	totalTransactions := ipHistory.SuccessfulTransactions + ipHistory.FailedTransactions
-
-	// New IP (no history) = medium risk
-	if totalTransactions == 0 {
-		return 0.5
+		return err
 	}
 
-	// Calculate failure and block rates
-	failureRate := float64(ipHistory.FailedTransactions) / float64(totalTransactions)
-	blockRate := float64(ipHistory.BlockedCount) / float64(totalTransactions)
-
-	// High failure or block rate = high risk
-	if failureRate > 0.5 || blockRate > 0.3 {
-		return 0.9
-	} else if failureRate > 0.2 || blockRate > 0.1 {
-		return 0.6
-	}
-
-	// Geolocation-based risk assessment
-	geoRisk := s.assessGeolocationRisk(ipAddress)
-
-	// Combine factors: 50% history, 30% failure rate, 20% geolocation
-	riskScore := (failureRate * 0.3) + (blockRate * 0.5) + (geoRisk * 0.2)
-
-	if riskScore > 1.0 {
-		riskScore = 1.0
-	}
-
-	return riskScore
+	total := history.SuccessfulTransactions + history.FailedTransactions
+	features["ip_failure_rate"] = rate(history.FailedTransactions, total)
+	features["ip_block_rate"] = rate(history.BlockedCount, total)
+	return nil
 }
 
 // isPrivateIP checks if an IP address is in private ranges
@@ -389,136 +374,32 @@ func isPrivateIP(ip net.IP) bool {
 	return false
 }
 
-// assessGeolocationRisk assesses risk based on IP geolocation
-func (s *RiskService) assessGeolocationRisk(ipAddress string) float64 {
-	// In a production system, you would use a geolocation service like MaxMind GeoIP2
-	// For now, we'll implement basic country-based risk assessment
-
-	// This is a simplified implementation
-	// You would typically use a proper GeoIP database or service
-
-	// High-risk countries/regions (simplified list)
-	highRiskCountries := map[string]bool{
-		"suspicious_country_1": true,
-		"suspicious_country_2": true,
-		// Add actual high-risk country codes based on your business rules
-	}
-
-	// Medium-risk countries
-	mediumRiskCountries := map[string]bool{
-		"medium_risk_country_1": true,
-		"medium_risk_country_2": true,
-		// Add actual medium-risk country codes
-	}
-
-	// For demonstration, return low risk for most IPs
-	// In production, you would:
-	// 1. Use MaxMind GeoIP2 or similar service
-	// 2. Check IP against threat intelligence feeds
-	// 3. Implement VPN/proxy detection
-	// 4. Consider time zone vs transaction time patterns
-
-	return 0.2 // Default low risk for geographic factors
-}
-
-// assessTimeRisk assesses risk based on transaction time
-func (s *RiskService) assessTimeRisk() float64 {
-	now := time.Now()
-	hour := now.Hour()
-
-	// Higher risk during unusual hours (11 PM to 6 AM)
-	if hour >= 23 || hour <= 6 {
-		return 0.6
+// merchantFeatures reads the merchant's failure rate over the last day
+func (s *RiskService) merchantFeatures(ctx context.Context, merchantID uuid.UUID, features map[string]interface{}) error {
+	var counts struct {
+		Total  int64
+		Failed int64
 	}
 
-	// Medium risk during early morning (6 AM to 9 AM)
-	if hour >= 6 && hour <= 9 {
-		return 0.3
-	}
-
-	// Lower risk during business hours
-	return 0.1
-}
-
-// assessMerchantRisk assesses risk based on merchant history
-func (s *RiskService) assessMerchantRisk(ctx context.Context, merchantID uuid.UUID) (float64, error) {
-	// Calculate merchant's recent failure rate
-	since := time.Now().Add(-24 * time.Hour)
-
-	var totalCount, failedCount int64
-
-	// Get total transactions
 	err := s.db.WithContext(ctx).Model(&models.Payment{}).
+		Select("COUNT(*) AS total, COUNT(CASE WHEN payments.status = ? THEN 1 END) AS failed", models.PaymentStatusFailed).
 		Joins("JOIN payment_intents ON payments.payment_intent_id = payment_intents.id").
-		Where("payment_intents.merchant_id = ? AND payment_intents.created_at > ?", merchantID, since).
-		Count(&totalCount).Error
+		Where("payment_intents.merchant_id = ? AND payment_intents.created_at > ?", merchantID, time.Now().Add(-24*time.Hour)).
+		Scan(&counts).Error
 	if err != nil {
-		return 0.3, err
-	}
-
-	if totalCount == 0 {
-		// New merchant = medium risk
-		return 0.5, nil
+		return err
 	}
 
-	// Get failed transactions
-	err = s.db.WithContext(ctx).Model(&models.Payment{}).
-		Joins("JOIN payment_intents ON payments.payment_intent_id = payment_intents.id").
-		Where("payment_intents.merchant_id = ? AND payment_intents.created_at > ? AND payments.status = ?",
-			merchantID, since, models.PaymentStatusFailed).
-		Count(&failedCount).Error
-	if err != nil {
-		return 0.3, err
-	}
-
-	failureRate := float64(failedCount) / float64(totalCount)
-
-	// Higher failure rate = higher risk
-	if failureRate > 0.5 {
-		return 1.0, nil
-	} else if failureRate > 0.3 {
-		return 0.8, nil
-	} else if failureRate > 0.1 {
-		return 0.4, nil
-	}
-
-	return 0.1, nil
-}
-
-// applyRiskRules applies business rules to adjust risk score
-func (s *RiskService) applyRiskRules(riskScore float64, factors map[string]interface{}, req RiskAssessmentRequest) (float64, []string) {
-	rules := make([]string, 0)
-
-	// Rule: High amount transactions
-	if req.Amount.GreaterThan(decimal.NewFromInt(100000)) {
-		riskScore += 0.3
-		rules = append(rules, "HIGH_AMOUNT_TRANSACTION")
-	}
-
-	// Rule: Weekend/holiday transactions (simplified)
-	if time.Now().Weekday() == time.Saturday || time.Now().Weekday() == time.Sunday {
-		riskScore += 0.1
-		rules = append(rules, "WEEKEND_TRANSACTION")
-	}
-
-	// Rule: First-time customer with high amount
-	if req.CustomerID == nil && req.Amount.GreaterThan(decimal.NewFromInt(10000)) {
-		riskScore += 0.2
-		rules = append(rules, "FIRST_TIME_HIGH_AMOUNT")
-	}
-
-	return riskScore, rules
+	features["merchant_failure_rate_24h"] = rate(counts.Failed, counts.Total)
+	return nil
 }
 
-// determineRiskDecision determines the risk level and decision based on score
-func (s *RiskService) determineRiskDecision(riskScore float64) (string, string) {
-	if riskScore >= 0.8 {
-		return models.RiskLevelHigh, models.RiskDecisionBlock
-	} else if riskScore >= 0.5 {
-		return models.RiskLevelMedium, models.RiskDecisionChallenge
-	} else {
-		return models.RiskLevelLow, models.RiskDecisionPass
+// rate returns n out of total, or 0 without a total
+func rate(n, total int64) float64 {
+	if total == 0 {
+		return 0
 	}
+	return float64(n) / float64(total)
 }
 
 // GetRiskAssessment retrieves a risk assessment by payment intent ID
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+var (
+	// ErrRiskRuleNotFound is returned for a risk rule that does not exist
+	ErrRiskRuleNotFound = errors.New("risk rule not found")
+	// ErrRiskRuleExists is returned for a risk rule named like another
+	ErrRiskRuleExists = errors.New("risk rule with this name already exists")
+)
+
+// DefaultRiskRules are created when there are no risk rules, so a new
+// deployment scores payments as the risk service always has
+var DefaultRiskRules = []models.RiskRule{
+	{Name: "HIGH_AMOUNT_TRANSACTION", Expression: "amount > 100000", Weight: 0.3},
+	{Name: "FIRST_TIME_HIGH_AMOUNT", Expression: "!customer_present && amount > 10000", Weight: 0.2},
+	{Name: "HIGH_VELOCITY", Expression: "velocity_1h > 10", Weight: 0.5},
+	{Name: "ELEVATED_VELOCITY", Expression: "velocity_1h > 5 && velocity_1h <= 10", Weight: 0.2},
+	{Name: "MISSING_DEVICE", Expression: "!device_present", Weight: 0.15},
+	{Name: "NEW_DEVICE", Expression: "device_present && device_new", Weight: 0.1},
+	{Name: "RISKY_DEVICE", Expression: "device_failure_rate > 0.5", Weight: 0.3},
+	{Name: "RISKY_IP", Expression: "ip_failure_rate > 0.5 || ip_block_rate > 0.3", Weight: 0.4},
+	{Name: "PRIVATE_OR_MISSING_IP", Expression: "!ip_present || ip_private", Weight: 0.1},
+	{Name: "GEO_MISMATCH", Expression: "geo_mismatch", Weight: 0.3},
+	{Name: "MERCHANT_FAILURES", Expression: "merchant_failure_rate_24h > 0.5", Weight: 0.3},
+	{Name: "NIGHT_TRANSACTION", Expression: "hour >= 23 || hour < 6", Weight: 0.1},
+	{Name: "WEEKEND_TRANSACTION", Expression: "weekend", Weight: 0.05},
+}
+
+// EnsureDefaultRiskRules creates DefaultRiskRules if there are no rules.
+// Rules no longer wanted should be disabled rather than all deleted.
+func (s *RiskService) EnsureDefaultRiskRules(ctx context.Context) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.RiskRule{}).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count risk rules: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	rules := make([]models.RiskRule, len(DefaultRiskRules))
+	for i, rule := range DefaultRiskRules {
+		rule.ID = uuid.New()
+		rule.Action = models.RiskRuleActionScore
+		rule.Enabled = true
+		rules[i] = rule
+	}
+	if err := s.db.WithContext(ctx).Create(&rules).Error; err != nil {
+		return fmt.Errorf("failed to create default risk rules: %w", err)
+	}
+
+	s.logger.WithField("count", len(rules)).Info("Created default risk rules")
+	return nil
+}
+
+// RiskRuleRequest creates a risk rule, or updates the fields set on it
+type RiskRuleRequest struct {
+	Name        *string    `json:"name"`
+	Description *string    `json:"description"`
+	Expression  *string    `json:"expression"`
+	Weight      *float64   `json:"weight"`
+	Action      *string    `json:"action"`
+	MerchantID  *uuid.UUID `json:"merchant_id"`
+	Enabled     *bool      `json:"enabled"`
+}
+
+// apply sets the fields of req on rule
+func (req RiskRuleRequest) apply(rule *models.RiskRule) {
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.Description != nil {
+		rule.Description = *req.Description
+	}
+	if req.Expression != nil {
+		rule.Expression = *req.Expression
+	}
+	if req.Weight != nil {
+		rule.Weight = *req.Weight
+	}
+	if req.Action != nil {
+		rule.Action = *req.Action
+	}
+	if req.MerchantID != nil {
+		rule.MerchantID = req.MerchantID
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+}
+
+// ValidateRiskRule checks rule has a name, a weight between 0 and 1, a
+// known action and an expression that compiles: one that only uses known
+// features, compares each with values of its type, and is a bool
+func ValidateRiskRule(rule *models.RiskRule) error {
+	if err := validateRiskRuleFields(rule); err != nil {
+		return err
+	}
+	_, err := CompileRiskExpression(rule.Expression)
+	return err
+}
+
+// validateRiskRuleFields checks the fields of rule other than its
+// expression
+func validateRiskRuleFields(rule *models.RiskRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRiskRule)
+	}
+	if rule.Weight < 0 || rule.Weight > 1 {
+		return fmt.Errorf("%w: weight must be between 0 and 1", ErrInvalidRiskRule)
+	}
+	switch rule.Action {
+	case models.RiskRuleActionScore, models.RiskRuleActionReview, models.RiskRuleActionBlock:
+	default:
+		return fmt.Errorf("%w: action must be score, review or block", ErrInvalidRiskRule)
+	}
+	return nil
+}
+
+// ListRiskRules lists the risk rules by name
+func (s *RiskService) ListRiskRules(ctx context.Context) ([]models.RiskRule, error) {
+	var rules []models.RiskRule
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list risk rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetRiskRule retrieves a risk rule by ID
+func (s *RiskService) GetRiskRule(ctx context.Context, id uuid.UUID) (*models.RiskRule, error) {
+	var rule models.RiskRule
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&rule).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrRiskRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get risk rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// CreateRiskRule creates a risk rule, scoring and enabled unless req says
+// otherwise
+func (s *RiskService) CreateRiskRule(ctx context.Context, req RiskRuleRequest) (*models.RiskRule, error) {
+	rule := &models.RiskRule{
+		ID:      uuid.New(),
+		Action:  models.RiskRuleActionScore,
+		Enabled: true,
+	}
+	req.apply(rule)
+	if err := s.saveRiskRule(ctx, rule, true); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("rule", rule.Name).Info("Risk rule created")
+	return rule, nil
+}
+
+// UpdateRiskRule updates the fields set in req on a risk rule
+func (s *RiskService) UpdateRiskRule(ctx context.Context, id uuid.UUID, req RiskRuleRequest) (*models.RiskRule, error) {
+	rule, err := s.GetRiskRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.apply(rule)
+	if err := s.saveRiskRule(ctx, rule, false); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("rule", rule.Name).Info("Risk rule updated")
+	return rule, nil
+}
+
+// saveRiskRule validates and creates or saves rule
+func (s *RiskService) saveRiskRule(ctx context.Context, rule *models.RiskRule, create bool) error {
+	if err := ValidateRiskRule(rule); err != nil {
+		return err
+	}
+
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.RiskRule{}).
+		Where("name = ? AND id <> ?", rule.Name, rule.ID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("failed to check risk rule name: %w", err)
+	}
+	if count > 0 {
+		return ErrRiskRuleExists
+	}
+
+	if create {
+		err = s.db.WithContext(ctx).Create(rule).Error
+	} else {
+		rule.UpdatedAt = time.Now()
+		err = s.db.WithContext(ctx).Save(rule).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save risk rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteRiskRule deletes a risk rule
+func (s *RiskService) DeleteRiskRule(ctx context.Context, id uuid.UUID) error {
+	result := s.db.WithContext(ctx).Delete(&models.RiskRule{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete risk rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRiskRuleNotFound
+	}
+	return nil
+}
+
+// TestRiskRuleRequest tests a rule, new or changed, against the payments
+// assessed between From and To, by default the last 30 days
+type TestRiskRuleRequest struct {
+	RuleID     *uuid.UUID      `json:"rule_id"` // Tests the rule's changes in Rule, or the rule as is
+	Rule       RiskRuleRequest `json:"rule"`
+	MerchantID *uuid.UUID      `json:"merchant_id"` // Only tests that merchant's payments
+	From       *time.Time      `json:"from"`
+	To         *time.Time      `json:"to"`
+	Limit      int             `json:"limit"` // Most recent assessments tested, by default 1000
+}
+
+// RiskRuleTestResult reports how a rule would have treated past payments
+type RiskRuleTestResult struct {
+	Rule            models.RiskRule     `json:"rule"`
+	Evaluated       int                 `json:"evaluated"`
+	Matched         int                 `json:"matched"`
+	MatchedFailed   int                 `json:"matched_failed"`   // Matched payments that failed
+	Errors          int                 `json:"errors"`           // Assessments the rule did not evaluate on
+	DecisionChanges int                 `json:"decision_changes"` // Matched payments whose decision it changes
+	Matches         []RiskRuleTestMatch `json:"matches"`          // Up to 100 of the matched payments
+}
+
+// RiskRuleTestMatch is a past payment a tested rule matched
+type RiskRuleTestMatch struct {
+	AssessmentID    uuid.UUID  `json:"assessment_id"`
+	PaymentIntentID uuid.UUID  `json:"payment_intent_id"`
+	PaymentID       *uuid.UUID `json:"payment_id"`
+	PaymentStatus   *string    `json:"payment_status"`
+	Decision        string     `json:"decision"`
+	NewDecision     string     `json:"new_decision"`
+	AssessedAt      time.Time  `json:"assessed_at"`
+}
+
+// TestRiskRule evaluates a rule over the features stored with past risk
+// assessments, and the enabled rules with it to see which decisions it
+// would change. Nothing is saved.
+func (s *RiskService) TestRiskRule(ctx context.Context, req TestRiskRuleRequest) (*RiskRuleTestResult, error) {
+	rule := &models.RiskRule{ID: uuid.New(), Action: models.RiskRuleActionScore, Enabled: true}
+	if req.RuleID != nil {
+		existing, err := s.GetRiskRule(ctx, *req.RuleID)
+		if err != nil {
+			return nil, err
+		}
+		rule = existing
+		rule.Enabled = true
+	}
+	req.Rule.apply(rule)
+	if rule.Name == "" {
+		rule.Name = "TEST_RULE"
+	}
+	if err := validateRiskRuleFields(rule); err != nil {
+		return nil, err
+	}
+	expression, err := CompileRiskExpression(rule.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	// The enabled rules with the tested one in place of its saved version
+	active, err := s.activeRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	withRule := []compiledRiskRule{{RiskRule: *rule, expression: expression}}
+	for _, r := range active {
+		if r.ID != rule.ID {
+			withRule = append(withRule, r)
+		}
+	}
+
+	to := time.Now()
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.Add(-30 * 24 * time.Hour)
+	if req.From != nil {
+		from = *req.From
+	}
+	if req.Limit <= 0 || req.Limit > 10000 {
+		req.Limit = 1000
+	}
+
+	var rows []struct {
+		ID              uuid.UUID
+		PaymentIntentID uuid.UUID
+		MerchantID      uuid.UUID
+		Factors         string
+		Decision        string
+		CreatedAt       time.Time
+		PaymentID       *uuid.UUID
+		PaymentStatus   *string
+	}
+	query := s.db.WithContext(ctx).
+		Table("risk_assessments ra").
+		Select(`ra.id, ra.payment_intent_id, pi.merchant_id, CAST(ra.factors AS TEXT) AS factors,
+			ra.decision, ra.created_at, p.id AS payment_id, p.status AS payment_status`).
+		Joins("JOIN payment_intents pi ON pi.id = ra.payment_intent_id").
+		Joins("LEFT JOIN payments p ON p.risk_assessment_id = ra.id").
+		Where("ra.created_at >= ? AND ra.created_at < ?", from, to)
+	if req.MerchantID != nil {
+		query = query.Where("pi.merchant_id = ?", *req.MerchantID)
+	}
+	if err := query.Order("ra.created_at DESC").Limit(req.Limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get risk assessments: %w", err)
+	}
+
+	result := &RiskRuleTestResult{Rule: *rule, Matches: make([]RiskRuleTestMatch, 0)}
+	for _, row := range rows {
+		result.Evaluated++
+		if rule.MerchantID != nil && *rule.MerchantID != row.MerchantID {
+			continue
+		}
+
+		var features map[string]interface{}
+		if err := json.Unmarshal([]byte(row.Factors), &features); err != nil {
+			result.Errors++
+			continue
+		}
+		matched, err := expression.Match(features)
+		if err != nil {
+			result.Errors++
+			continue
+		}
+		if !matched {
+			continue
+		}
+
+		result.Matched++
+		if row.PaymentStatus != nil && *row.PaymentStatus == models.PaymentStatusFailed {
+			result.MatchedFailed++
+		}
+		newDecision := s.evaluate(withRule, features, row.MerchantID).decision
+		if newDecision != row.Decision {
+			result.DecisionChanges++
+		}
+		if len(result.Matches) < 100 {
+			result.Matches = append(result.Matches, RiskRuleTestMatch{
+				AssessmentID:    row.ID,
+				PaymentIntentID: row.PaymentIntentID,
+				PaymentID:       row.PaymentID,
+				PaymentStatus:   row.PaymentStatus,
+				Decision:        row.Decision,
+				NewDecision:     newDecision,
+				AssessedAt:      row.CreatedAt,
+			})
+		}
+	}
+
+	return result, nil
+}
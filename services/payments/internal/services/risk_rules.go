@@ -0,0 +1,543 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidRiskRule is returned for a rule expression that does not parse,
+// uses features the risk engine does not extract, or compares values of
+// different types
+var ErrInvalidRiskRule = errors.New("invalid risk rule")
+
+// RiskFeatures lists the features a rule expression can use, with their
+// type. They are extracted for every assessment and stored with it, so
+// rules can be tested against past payments.
+var RiskFeatures = map[string]string{
+	"amount":                    "number",
+	"currency":                  "string",
+	"payment_method":            "string",
+	"customer_present":          "bool",
+	"velocity_1h":               "number", // Payments by the customer, or merchant without one
+	"velocity_24h":              "number",
+	"amount_24h":                "number", // Their total amount
+	"device_present":            "bool",
+	"device_new":                "bool", // No payments from the device in 30 days
+	"device_failure_rate":       "number",
+	"ip_present":                "bool",
+	"ip_private":                "bool",
+	"ip_failure_rate":           "number",
+	"ip_block_rate":             "number",
+	"ip_country":                "string", // From the edge's geo header
+	"billing_country":           "string",
+	"geo_mismatch":              "bool", // Both countries known and different
+	"merchant_failure_rate_24h": "number",
+	"hour":                      "number", // Hour of day, server time
+	"weekend":                   "bool",
+}
+
+// RiskExpression is a compiled rule expression. The language has number,
+// string and boolean literals, lists ([1, 2]), the features in RiskFeatures,
+// comparisons (== != > >= < <=), membership (in), and ! && || with
+// parentheses, e.g.
+//
+//	amount > 50000 && (velocity_1h >= 5 || device_new)
+//	payment_method in ["card", "netbanking"] && geo_mismatch
+type RiskExpression struct {
+	source string
+	root   riskNode
+}
+
+// CompileRiskExpression parses source, checking it only uses known features,
+// compares them with values of their type from RiskFeatures, and evaluates
+// to a boolean
+func CompileRiskExpression(source string) (*RiskExpression, error) {
+	p := &riskParser{}
+	if err := p.lex(source); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRiskRule, err)
+	}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRiskRule, err)
+	}
+	typ, err := root.check()
+	if err == nil && typ != riskTypeBool {
+		err = fmt.Errorf("rule is a %s, not a bool", typ)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRiskRule, err)
+	}
+	return &RiskExpression{source: source, root: root}, nil
+}
+
+// String returns the expression's source
+func (e *RiskExpression) String() string {
+	return e.source
+}
+
+// Match evaluates the expression over features. An expression that does
+// not evaluate to a boolean does not match.
+func (e *RiskExpression) Match(features map[string]interface{}) (bool, error) {
+	value, err := e.root.eval(features)
+	if err != nil {
+		return false, err
+	}
+	matched, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("rule evaluates to %T, not a boolean", value)
+	}
+	return matched, nil
+}
+
+type riskTokenKind int
+
+const (
+	riskTokenNumber riskTokenKind = iota
+	riskTokenString
+	riskTokenIdent
+	riskTokenOp
+)
+
+type riskToken struct {
+	kind riskTokenKind
+	text string
+}
+
+type riskParser struct {
+	tokens []riskToken
+	pos    int
+}
+
+func (p *riskParser) lex(source string) error {
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			p.tokens = append(p.tokens, riskToken{riskTokenNumber, string(runes[start:i])})
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return fmt.Errorf("unterminated string")
+			}
+			p.tokens = append(p.tokens, riskToken{riskTokenString, string(runes[i+1 : end])})
+			i = end + 1
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			p.tokens = append(p.tokens, riskToken{riskTokenIdent, string(runes[start:i])})
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", ">=", "<=", "&&", "||":
+					op = two
+				}
+			}
+			switch op {
+			case "==", "!=", ">=", "<=", "&&", "||", ">", "<", "!", "(", ")", "[", "]", ",":
+			default:
+				return fmt.Errorf("unexpected character %q", r)
+			}
+			p.tokens = append(p.tokens, riskToken{riskTokenOp, op})
+			i += len([]rune(op))
+		}
+	}
+	return nil
+}
+
+func (p *riskParser) peek(text string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind != riskTokenString && p.tokens[p.pos].text == text
+}
+
+func (p *riskParser) expect(text string) error {
+	if !p.peek(text) {
+		if p.pos < len(p.tokens) {
+			return fmt.Errorf("expected %q, found %q", text, p.tokens[p.pos].text)
+		}
+		return fmt.Errorf("expected %q at end of rule", text)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *riskParser) parseOr() (riskNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.peek("||") {
+		p.pos++
+		var right riskNode
+		if right, err = p.parseAnd(); err == nil {
+			left = riskLogical{op: "||", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *riskParser) parseAnd() (riskNode, error) {
+	left, err := p.parseNot()
+	for err == nil && p.peek("&&") {
+		p.pos++
+		var right riskNode
+		if right, err = p.parseNot(); err == nil {
+			left = riskLogical{op: "&&", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *riskParser) parseNot() (riskNode, error) {
+	if p.peek("!") {
+		p.pos++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return riskNot{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *riskParser) parseComparison() (riskNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", ">=", "<=", ">", "<", "in"} {
+		if p.peek(op) {
+			p.pos++
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return riskComparison{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *riskParser) parseOperand() (riskNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of rule")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token.kind {
+	case riskTokenNumber:
+		n, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", token.text)
+		}
+		return riskLiteral{value: n}, nil
+	case riskTokenString:
+		return riskLiteral{value: token.text}, nil
+	case riskTokenIdent:
+		switch token.text {
+		case "true", "false":
+			return riskLiteral{value: token.text == "true"}, nil
+		}
+		if _, ok := RiskFeatures[token.text]; !ok {
+			return nil, fmt.Errorf("unknown feature %q", token.text)
+		}
+		return riskFeature{name: token.text}, nil
+	}
+
+	switch token.text {
+	case "(":
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case "[":
+		var list riskList
+		for !p.peek("]") {
+			if len(list) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			item, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		p.pos++
+		return list, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", token.text)
+}
+
+// Types of rule values: those of RiskFeatures, and lists of them
+const (
+	riskTypeNumber = "number"
+	riskTypeString = "string"
+	riskTypeBool   = "bool"
+	riskTypeList   = "list" // Of no items yet; a list of numbers is "list of number"
+)
+
+// riskListOf is the type of a list of typ items
+func riskListOf(typ string) string {
+	return riskTypeList + " of " + typ
+}
+
+type riskNode interface {
+	// check returns the type the node evaluates to, or why it cannot be
+	// evaluated
+	check() (string, error)
+	eval(features map[string]interface{}) (interface{}, error)
+}
+
+type riskLiteral struct{ value interface{} }
+
+func (n riskLiteral) check() (string, error) {
+	switch n.value.(type) {
+	case float64:
+		return riskTypeNumber, nil
+	case bool:
+		return riskTypeBool, nil
+	default:
+		return riskTypeString, nil
+	}
+}
+
+func (n riskLiteral) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type riskFeature struct{ name string }
+
+func (n riskFeature) check() (string, error) {
+	return RiskFeatures[n.name], nil
+}
+
+// eval returns the feature's value, or its type's zero value if it was not
+// extracted, e.g. for assessments made before the feature existed
+func (n riskFeature) eval(features map[string]interface{}) (interface{}, error) {
+	value, ok := features[n.name]
+	if !ok || value == nil {
+		switch RiskFeatures[n.name] {
+		case riskTypeNumber:
+			return 0.0, nil
+		case riskTypeBool:
+			return false, nil
+		default:
+			return "", nil
+		}
+	}
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+	return value, nil
+}
+
+type riskList []riskNode
+
+// check returns the type of a list of items of one type, which are not
+// themselves lists
+func (n riskList) check() (string, error) {
+	typ := ""
+	for _, item := range n {
+		itemType, err := item.check()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case strings.HasPrefix(itemType, riskTypeList):
+			return "", fmt.Errorf("lists cannot hold lists")
+		case typ != "" && itemType != typ:
+			return "", fmt.Errorf("list mixes %s and %s", typ, itemType)
+		}
+		typ = itemType
+	}
+	if typ == "" {
+		return riskTypeList, nil
+	}
+	return riskListOf(typ), nil
+}
+
+func (n riskList) eval(features map[string]interface{}) (interface{}, error) {
+	values := make([]interface{}, 0, len(n))
+	for _, item := range n {
+		value, err := item.eval(features)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+type riskNot struct{ operand riskNode }
+
+func (n riskNot) check() (string, error) {
+	typ, err := n.operand.check()
+	if err == nil && typ != riskTypeBool {
+		err = fmt.Errorf("! needs a bool, not a %s", typ)
+	}
+	return riskTypeBool, err
+}
+
+func (n riskNot) eval(features map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(features)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf("! of %T", value)
+	}
+	return !b, nil
+}
+
+type riskLogical struct {
+	op          string
+	left, right riskNode
+}
+
+func (n riskLogical) check() (string, error) {
+	for _, side := range []riskNode{n.left, n.right} {
+		typ, err := side.check()
+		if err != nil {
+			return "", err
+		}
+		if typ != riskTypeBool {
+			return "", fmt.Errorf("%s needs bools, not a %s", n.op, typ)
+		}
+	}
+	return riskTypeBool, nil
+}
+
+func (n riskLogical) eval(features map[string]interface{}) (interface{}, error) {
+	for i, side := range []riskNode{n.left, n.right} {
+		value, err := side.eval(features)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s of %T", n.op, value)
+		}
+		// Short-circuit
+		if i == 0 && b == (n.op == "||") {
+			return b, nil
+		}
+		if i == 1 {
+			return b, nil
+		}
+	}
+	return false, nil
+}
+
+type riskComparison struct {
+	op          string
+	left, right riskNode
+}
+
+func (n riskComparison) check() (string, error) {
+	left, err := n.left.check()
+	if err != nil {
+		return "", err
+	}
+	right, err := n.right.check()
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(left, riskTypeList) {
+		return "", fmt.Errorf("%s cannot compare a list", n.op)
+	}
+
+	switch n.op {
+	case "in":
+		if right != riskTypeList && right != riskListOf(left) {
+			return "", fmt.Errorf("in needs a list of %s, not a %s", left, right)
+		}
+	case "==", "!=":
+		if left != right {
+			return "", fmt.Errorf("%s compares a %s with a %s", n.op, left, right)
+		}
+	default:
+		if left != riskTypeNumber || right != riskTypeNumber {
+			return "", fmt.Errorf("%s compares numbers, not a %s and a %s", n.op, left, right)
+		}
+	}
+	return riskTypeBool, nil
+}
+
+func (n riskComparison) eval(features map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(features)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(features)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==", "!=":
+		equal, err := riskEqual(left, right)
+		return equal == (n.op == "=="), err
+	case "in":
+		list, ok := right.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("in needs a list, not %T", right)
+		}
+		for _, item := range list {
+			equal, err := riskEqual(left, item)
+			if err != nil || equal {
+				return equal, err
+			}
+		}
+		return false, nil
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("%s compares numbers, not %T and %T", n.op, left, right)
+	}
+	switch n.op {
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "<":
+		return l < r, nil
+	default:
+		return l <= r, nil
+	}
+}
+
+// riskEqual compares two numbers, strings or booleans
+func riskEqual(left, right interface{}) (bool, error) {
+	for _, value := range []interface{}{left, right} {
+		switch value.(type) {
+		case float64, string, bool:
+		default:
+			return false, fmt.Errorf("== compares numbers, strings or booleans, not %T", value)
+		}
+	}
+	return left == right, nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+func matchRisk(t *testing.T, source string, features map[string]interface{}) bool {
+	expression, err := CompileRiskExpression(source)
+	require.NoError(t, err, source)
+	matched, err := expression.Match(features)
+	require.NoError(t, err, source)
+	return matched
+}
+
+func TestRiskExpression_Precedence(t *testing.T) {
+	features := map[string]interface{}{
+		"amount":         60000.0,
+		"velocity_1h":    1.0,
+		"device_new":     true,
+		"geo_mismatch":   false,
+		"payment_method": "card",
+	}
+	for _, tc := range []struct {
+		source string
+		want   bool
+	}{
+		// && binds tighter than ||
+		{"geo_mismatch && device_new || amount > 50000", true},
+		{"geo_mismatch && (device_new || amount > 50000)", false},
+		{"amount > 50000 || geo_mismatch && false", true},
+		// ! binds tighter than && and comparisons are operands
+		{"!geo_mismatch && device_new", true},
+		{"!(geo_mismatch || device_new)", false},
+		{"!!device_new", true},
+		{"amount > 50000 && (velocity_1h >= 5 || device_new)", true},
+		{"amount >= 60000 && amount <= 60000 && amount != 1", true},
+		{"amount < .5", false},
+		{`payment_method == 'card' && currency == ""`, true}, // Missing features are their zero value
+	} {
+		assert.Equal(t, tc.want, matchRisk(t, tc.source, features), tc.source)
+	}
+}
+
+func TestRiskExpression_InLists(t *testing.T) {
+	features := map[string]interface{}{"payment_method": "card", "hour": 3.0, "ip_country": "IN"}
+	for _, tc := range []struct {
+		source string
+		want   bool
+	}{
+		{`payment_method in ["card", "netbanking"]`, true},
+		{`payment_method in ["upi"]`, false},
+		{`payment_method in []`, false},
+		{`hour in [0, 1, 2, 3]`, true},
+		{`!(ip_country in ["US", "GB"]) && hour in [3]`, true},
+	} {
+		assert.Equal(t, tc.want, matchRisk(t, tc.source, features), tc.source)
+	}
+}
+
+func TestCompileRiskExpression_Rejects(t *testing.T) {
+	for _, tc := range []struct {
+		source, want string
+	}{
+		// Unknown features
+		{"amount_usd > 10", `unknown feature "amount_usd"`},
+		{"device_new && is_fraud", `unknown feature "is_fraud"`},
+		// Type errors
+		{`amount > "1000"`, "compares numbers"},
+		{`currency > 5`, "compares numbers"},
+		{`amount == "1000"`, "compares a number with a string"},
+		{`device_new == 1`, "compares a bool with a number"},
+		{`payment_method in [1, 2]`, "in needs a list of string"},
+		{`payment_method in ["card", 2]`, "list mixes string and number"},
+		{`amount in "card"`, "in needs a list of number"},
+		{`[1] == [1]`, "cannot compare a list"},
+		{`hour in [[1]]`, "lists cannot hold lists"},
+		{`!amount`, "! needs a bool"},
+		{`device_new && velocity_1h`, "&& needs bools"},
+		{`amount`, "rule is a number"},
+		{`"card"`, "rule is a string"},
+		// Syntax errors
+		{"", "unexpected end"},
+		{"amount >", "unexpected end"},
+		{"(amount > 1", `expected ")"`},
+		{"amount > 1 1", `unexpected "1"`},
+		{"amount # 1", "unexpected character"},
+		{`currency == "INR`, "unterminated string"},
+		{"amount > 1.2.3", "invalid number"},
+	} {
+		_, err := CompileRiskExpression(tc.source)
+		require.Error(t, err, tc.source)
+		assert.ErrorIs(t, err, ErrInvalidRiskRule, tc.source)
+		assert.Contains(t, err.Error(), tc.want, tc.source)
+	}
+}
+
+func TestDefaultRiskRules_Compile(t *testing.T) {
+	for _, rule := range DefaultRiskRules {
+		_, err := CompileRiskExpression(rule.Expression)
+		assert.NoError(t, err, rule.Name)
+	}
+}
+
+func TestRiskService_RejectsMistypedRulesOnSave(t *testing.T) {
+	risk := NewRiskService(setupTestDB(t), testLogger(), 50, 75)
+	ctx := context.Background()
+	name, weight, action := "BIG_CARD", 0.5, models.RiskRuleActionReview
+
+	expression := `amount > "50000"`
+	_, err := risk.CreateRiskRule(ctx, RiskRuleRequest{Name: &name, Expression: &expression, Weight: &weight, Action: &action})
+	assert.ErrorIs(t, err, ErrInvalidRiskRule)
+
+	expression = `amount > 50000 && payment_method == "card"`
+	rule, err := risk.CreateRiskRule(ctx, RiskRuleRequest{Name: &name, Expression: &expression, Weight: &weight, Action: &action})
+	require.NoError(t, err)
+
+	mistyped := `payment_method in [1]`
+	_, err = risk.UpdateRiskRule(ctx, rule.ID, RiskRuleRequest{Expression: &mistyped})
+	assert.ErrorIs(t, err, ErrInvalidRiskRule)
+	_, err = risk.TestRiskRule(ctx, TestRiskRuleRequest{RuleID: &rule.ID, Rule: RiskRuleRequest{Expression: &mistyped}})
+	assert.ErrorIs(t, err, ErrInvalidRiskRule)
+	saved, err := risk.GetRiskRule(ctx, rule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, expression, saved.Expression)
+}
+
+func TestRiskService_CompilesRulesOnLoad(t *testing.T) {
+	db := setupTestDB(t)
+	risk := NewRiskService(db, testLogger(), 50, 75)
+	ctx := context.Background()
+
+	rules := []models.RiskRule{
+		{ID: uuid.New(), Name: "A_BIG", Expression: "amount > 1000", Weight: 0.4, Action: models.RiskRuleActionScore, Enabled: true},
+		// Saved before expressions were type-checked
+		{ID: uuid.New(), Name: "B_BROKEN", Expression: `amount > "1000"`, Weight: 1, Action: models.RiskRuleActionBlock, Enabled: true},
+		{ID: uuid.New(), Name: "C_DISABLED", Expression: "amount > 0", Weight: 1, Action: models.RiskRuleActionBlock},
+	}
+	require.NoError(t, db.Create(&rules).Error)
+	require.NoError(t, db.Model(&rules[2]).Update("enabled", false).Error)
+
+	active, err := risk.activeRules(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 1, "broken or disabled rule loaded")
+	assert.Equal(t, "A_BIG", active[0].Name)
+	compiled := active[0].expression
+
+	evaluation := risk.evaluate(active, map[string]interface{}{"amount": 5000.0}, uuid.New())
+	assert.Equal(t, []string{"A_BIG"}, evaluation.matched)
+	assert.Equal(t, models.RiskDecisionAllow, evaluation.decision)
+
+	// Loading again reuses the compiled expression until it changes
+	active, err = risk.activeRules(ctx)
+	require.NoError(t, err)
+	assert.Same(t, compiled, active[0].expression)
+
+	require.NoError(t, db.Model(&rules[0]).Update("expression", "amount > 10000").Error)
+	active, err = risk.activeRules(ctx)
+	require.NoError(t, err)
+	assert.NotSame(t, compiled, active[0].expression)
+	assert.Equal(t, "amount > 10000", active[0].expression.String())
+	assert.Len(t, risk.expressions, 1, "expression of a changed rule kept")
+}
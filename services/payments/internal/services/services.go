@@ -1,6 +1,10 @@
 package services
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"github.com/suuupra/payments/internal/config"
@@ -11,40 +15,136 @@ import (
 type Services struct {
 	Payment      *PaymentService
 	Refund       *RefundService
+	Commands     *PaymentCommandService
 	Ledger       *LedgerService
 	Risk         *RiskService
 	Webhook      *WebhookService
 	Idempotency  *IdempotencyService
+	APIKeys      *APIKeyService
+	Recon        *ReconciliationService
+	Reports      *ReportService
+	Vault        *VaultService
+	RateLimits   *RateLimitService
+	Rails        *RailRouter
 	UPIClient    *UPIClient
 }
 
 // Dependencies contains all dependencies needed to create services
 type Dependencies struct {
-	Repos     *repository.Repositories
-	Redis     *redis.Client
-	UPIClient *UPIClient
-	Logger    *logrus.Logger
-	Config    *config.Config
+	Repos        *repository.Repositories
+	Redis        *redis.Client
+	UPIClient    *UPIClient
+	CommandQueue PaymentCommandQueue // nil when async payments are disabled
+	Logger       *logrus.Logger
+	Config       *config.Config
 }
 
 // NewServices creates all services with their dependencies
 func NewServices(deps Dependencies) *Services {
 	// Create individual services
-	ledgerService := NewLedgerService(deps.Repos.DB, deps.Logger)
-	idempotencyService := NewIdempotencyService(deps.Repos.DB, deps.Logger, deps.Config.IdempotencyTTLHours)
-	riskService := NewRiskService(deps.Repos.DB, deps.Logger)
+	ledgerService := NewLedgerService(deps.Repos.DB, deps.Logger, deps.Config.PlatformFeeBPS)
+	idempotencyService := NewIdempotencyService(
+		deps.Repos.DB,
+		deps.Logger,
+		deps.Config.IdempotencyTTLHours,
+		deps.Config.IdempotencyLockTimeoutSeconds,
+		deps.Config.IdempotencyWaitTimeoutSeconds,
+	)
+	riskService := NewRiskService(
+		deps.Repos.DB,
+		deps.Logger,
+		deps.Config.RiskMediumThreshold,
+		deps.Config.RiskHighThreshold,
+	)
 	webhookService := NewWebhookService(
 		deps.Repos.DB,
 		deps.Logger,
 		deps.Config.WebhookSigningSecret,
 		deps.Config.MaxWebhookRetries,
 		deps.Config.WebhookTimeoutSeconds,
+		WebhookDeliveryPolicy{
+			Workers:              deps.Config.WebhookWorkers,
+			BackoffBase:          time.Duration(deps.Config.WebhookBackoffBaseSeconds) * time.Second,
+			BackoffMax:           time.Duration(deps.Config.WebhookBackoffMaxSeconds) * time.Second,
+			DisableAfterFailures: deps.Config.WebhookDisableAfterFailures,
+			DisableAfter:         time.Duration(deps.Config.WebhookDisableAfterHours) * time.Hour,
+			SecretOverlap:        time.Duration(deps.Config.WebhookSecretOverlapHours) * time.Hour,
+		},
 	)
 
+	// UPI is always available; card and netbanking when their gateways
+	// are configured
+	rails := []PaymentRail{NewUPIRail(deps.UPIClient)}
+	if deps.Config.CardGatewayURL != "" {
+		rails = append(rails, NewCardRail(
+			deps.Config.CardGatewayURL,
+			deps.Config.CardGatewayAPIKey,
+			deps.Config.RailGatewayTimeoutSeconds,
+			deps.Logger,
+		))
+	}
+	if deps.Config.NetbankingGatewayURL != "" {
+		rails = append(rails, NewNetbankingRail(
+			deps.Config.NetbankingGatewayURL,
+			deps.Config.NetbankingGatewayAPIKey,
+			deps.Config.RailGatewayTimeoutSeconds,
+			deps.Logger,
+		))
+	}
+	railRouter := NewRailRouter(deps.Repos.DB, deps.Logger, strings.Split(deps.Config.DefaultRails, ","), rails...)
+
+	// Payments are sent once if the retry policy does not parse
+	var retryPolicy *RetryPolicy
+	if rules, err := ParseRetryRules(deps.Config.PaymentRetryPolicy); err != nil {
+		deps.Logger.WithError(err).Error("Invalid payment retry policy, payments will not be retried")
+	} else {
+		retryPolicy = &RetryPolicy{
+			Rules:          rules,
+			MaxInlineDelay: time.Duration(deps.Config.PaymentRetryMaxInlineDelaySeconds) * time.Second,
+		}
+		for _, rail := range strings.Split(deps.Config.PaymentFallbackRails, ",") {
+			if rail = strings.TrimSpace(rail); rail != "" {
+				retryPolicy.FallbackRails = append(retryPolicy.FallbackRails, rail)
+			}
+		}
+	}
+
+	// Payments UPI Core could not be reached for fail unless dead-lettered
+	var upiDeadLetters *UPIDeadLetterPolicy
+	if deps.Config.UPIDeadLetterEnabled {
+		upiDeadLetters = &UPIDeadLetterPolicy{
+			ReplayInterval: time.Duration(deps.Config.UPIDeadLetterReplayIntervalSeconds) * time.Second,
+			MaxReplays:     deps.Config.UPIDeadLetterMaxReplays,
+		}
+	}
+
+	// Methods sealed with a previous key cannot be opened if the previous
+	// keys do not parse
+	previousVaultKeys, err := ParseVaultKeys(deps.Config.FieldEncryptionPreviousKeys)
+	if err != nil {
+		deps.Logger.WithError(err).Error("Invalid previous field encryption keys, ignoring them")
+	}
+	vaultService := NewVaultService(deps.Repos.DB, deps.Logger, VaultKey{
+		Version: deps.Config.FieldEncryptionKeyVersion,
+		Secret:  deps.Config.FieldEncryptionKey,
+	}, previousVaultKeys...)
+
 	paymentService := NewPaymentService(
 		deps.Repos.DB,
 		deps.Logger,
 		deps.UPIClient,
+		railRouter,
+		retryPolicy,
+		&ThreeDSPolicy{
+			CallbackURL:      strings.TrimRight(deps.Config.ThreeDSCallbackURL, "/"),
+			ChallengeTimeout: time.Duration(deps.Config.ThreeDSChallengeTimeoutMinutes) * time.Minute,
+			StepUpOnReview:   deps.Config.ThreeDSOnRiskReview,
+		},
+		upiDeadLetters,
+		&CapturePolicy{
+			AuthorizationWindow: time.Duration(deps.Config.AuthCaptureWindowHours) * time.Hour,
+		},
+		vaultService,
 		ledgerService,
 		riskService,
 		webhookService,
@@ -53,21 +153,60 @@ func NewServices(deps Dependencies) *Services {
 	refundService := NewRefundService(
 		deps.Repos.DB,
 		deps.Logger,
-		deps.UPIClient,
+		railRouter,
 		ledgerService,
 		webhookService,
+		deps.Config.RefundSubmitTimeoutSeconds,
 	)
 
-	// Start webhook service
-	webhookService.Start()
+	var reconBankCodes []string
+	for _, bankCode := range strings.Split(deps.Config.ReconBankCodes, ",") {
+		if bankCode = strings.TrimSpace(bankCode); bankCode != "" {
+			reconBankCodes = append(reconBankCodes, bankCode)
+		}
+	}
+	reconService := NewReconciliationService(
+		deps.Repos.DB,
+		deps.Logger,
+		deps.UPIClient,
+		webhookService,
+		ReconciliationPolicy{
+			BankCodes: reconBankCodes,
+			Interval:  time.Duration(deps.Config.ReconIntervalMinutes) * time.Minute,
+			Lookback:  time.Duration(deps.Config.ReconLookbackHours) * time.Hour,
+		},
+	)
+
+	// A fresh database starts out with the default risk rules
+	if err := riskService.EnsureDefaultRiskRules(context.Background()); err != nil {
+		deps.Logger.WithError(err).Error("Failed to create default risk rules")
+	}
 
 	return &Services{
 		Payment:     paymentService,
 		Refund:      refundService,
+		Commands: NewPaymentCommandService(
+			deps.Repos.DB,
+			deps.Logger,
+			deps.CommandQueue,
+			paymentService,
+			webhookService,
+			deps.Config.PaymentCommandWorkers,
+		),
 		Ledger:      ledgerService,
 		Risk:        riskService,
 		Webhook:     webhookService,
 		Idempotency: idempotencyService,
+		APIKeys:     NewAPIKeyService(deps.Repos.DB, deps.Logger, deps.Config.Environment, deps.Config.APIKeyRotationGraceHours),
+		Recon:       reconService,
+		Reports:     NewReportService(deps.Repos.DB, deps.Logger, webhookService),
+		Vault:       vaultService,
+		RateLimits: NewRateLimitService(deps.Repos.DB, deps.Redis, deps.Logger, RateLimitPolicy{
+			ReadsPerMinute:  deps.Config.RateLimitReadsPerMinute,
+			WritesPerMinute: deps.Config.RateLimitWritesPerMinute,
+			GlobalPerMinute: deps.Config.RateLimitGlobalPerMinute,
+		}),
+		Rails:       railRouter,
 		UPIClient:   deps.UPIClient,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantKey is the context key of the merchant a request is scoped to
+type tenantKey struct{}
+
+// WithMerchant scopes the queries run with ctx to merchantID's rows
+func WithMerchant(ctx context.Context, merchantID uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantKey{}, merchantID)
+}
+
+// MerchantFromContext returns the merchant ctx is scoped to, if any.
+// Requests of operators, and background workers, are not scoped.
+func MerchantFromContext(ctx context.Context) (uuid.UUID, bool) {
+	if ctx == nil {
+		return uuid.Nil, false
+	}
+	merchantID, ok := ctx.Value(tenantKey{}).(uuid.UUID)
+	return merchantID, ok
+}
+
+// tenantFilters are the conditions keeping each merchant-owned table to
+// one merchant's rows. Payments and refunds belong to the merchant of
+// their intent, deliveries to that of their endpoint.
+var tenantFilters = map[string]string{
+	"payment_intents":    "payment_intents.merchant_id = ?",
+	"payments":           "payments.payment_intent_id IN (SELECT id FROM payment_intents WHERE merchant_id = ?)",
+	"refunds":            "refunds.payment_id IN (SELECT payments.id FROM payments JOIN payment_intents ON payment_intents.id = payments.payment_intent_id WHERE payment_intents.merchant_id = ?)",
+	"payment_commands":   "payment_commands.merchant_id = ?",
+	"webhook_endpoints":  "webhook_endpoints.merchant_id = ?",
+	"webhook_deliveries": "webhook_deliveries.endpoint_id IN (SELECT id FROM webhook_endpoints WHERE merchant_id = ?)",
+	"reports":            "reports.merchant_id = ?",
+}
+
+// RegisterTenancy makes queries, updates and deletes of merchant-owned
+// tables run with a merchant's context see only that merchant's rows, so
+// another merchant's are not found. Aggregates read with Scan and raw SQL
+// are not scoped.
+func RegisterTenancy(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register("tenancy:query", scopeTenant); err != nil {
+		return fmt.Errorf("failed to register tenancy query callback: %w", err)
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("tenancy:update", scopeTenant); err != nil {
+		return fmt.Errorf("failed to register tenancy update callback: %w", err)
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("tenancy:delete", scopeTenant); err != nil {
+		return fmt.Errorf("failed to register tenancy delete callback: %w", err)
+	}
+	return nil
+}
+
+// scopeTenant adds the filter of the statement's table for the merchant
+// of its context
+func scopeTenant(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	merchantID, ok := MerchantFromContext(db.Statement.Context)
+	if !ok {
+		return
+	}
+	filter, ok := tenantFilters[db.Statement.Table]
+	if !ok {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Expr{SQL: filter, Vars: []interface{}{merchantID}},
+	}})
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+// ErrThreeDSChallengeNotFound is returned for a callback of a payment that
+// never had a challenge
+var ErrThreeDSChallengeNotFound = errors.New("3-D Secure challenge not found")
+
+// defaultChallengeTimeout is how long a payer has to authenticate when no
+// policy says otherwise
+const defaultChallengeTimeout = 15 * time.Minute
+
+// ThreeDSPolicy is how card payments are stepped up to 3-D Secure
+type ThreeDSPolicy struct {
+	CallbackURL      string        // Payers return to CallbackURL/{payment_id}
+	ChallengeTimeout time.Duration // Challenges not answered by then fail the payment
+	StepUpOnReview   bool          // Ask for 3-D Secure on payments flagged for risk review
+}
+
+// requestStepUp sets where rails send the payer back to after a challenge,
+// and asks for one if the policy steps up payment
+func (s *PaymentService) requestStepUp(req *RailPaymentRequest, payment *models.Payment) {
+	if s.threeDS == nil {
+		return
+	}
+	if s.threeDS.CallbackURL != "" {
+		req.ReturnURL = s.threeDS.CallbackURL + "/" + payment.ID.String()
+	}
+	req.ThreeDS = s.steppedUp(payment)
+}
+
+// steppedUp reports whether the policy asks payment to authenticate
+func (s *PaymentService) steppedUp(payment *models.Payment) bool {
+	return s.threeDS != nil && s.threeDS.StepUpOnReview && payment.RiskDecision == models.RiskDecisionReview
+}
+
+// startChallenge records the challenge payment waits on after its last
+// attempt came back requiring action
+func (s *PaymentService) startChallenge(tx *gorm.DB, payment *models.Payment, railResp *RailPaymentResponse) error {
+	var attempt models.PaymentAttempt
+	if err := tx.Where("payment_id = ?", payment.ID).Order("attempt_number DESC").First(&attempt).Error; err != nil {
+		return fmt.Errorf("failed to get payment attempt: %w", err)
+	}
+
+	timeout := defaultChallengeTimeout
+	if s.threeDS != nil && s.threeDS.ChallengeTimeout > 0 {
+		timeout = s.threeDS.ChallengeTimeout
+	}
+	reason := models.ThreeDSReasonRail
+	if s.steppedUp(payment) {
+		reason = models.ThreeDSReasonRiskReview
+	}
+	challenge := &models.ThreeDSChallenge{
+		ID:                uuid.New(),
+		PaymentID:         payment.ID,
+		PaymentIntentID:   payment.PaymentIntentID,
+		Rail:              attempt.Rail,
+		RailTransactionID: railResp.TransactionID,
+		RedirectURL:       railResp.RedirectURL,
+		Reason:            reason,
+		Status:            models.ThreeDSChallengeStatusPending,
+		ExpiresAt:         time.Now().Add(timeout),
+	}
+	if returnURL, ok := payment.Metadata["return_url"].(string); ok {
+		challenge.ReturnURL = returnURL
+	}
+	if err := tx.Create(challenge).Error; err != nil {
+		return fmt.Errorf("failed to create 3-D Secure challenge: %w", err)
+	}
+	return nil
+}
+
+// CompleteChallenge resumes the payment waiting on a challenge with the
+// result the payer's authentication page posted to the callback. The rail
+// verifies the result and authorises or declines the payment; a challenge
+// answered after it expired fails it. Callbacks of an answered challenge
+// return it as it is.
+func (s *PaymentService) CompleteChallenge(ctx context.Context, paymentID uuid.UUID, payload map[string]string) (*models.ThreeDSChallenge, *models.Payment, error) {
+	return s.completeChallenge(ctx, paymentID, func(challenge *models.ThreeDSChallenge) (*RailPaymentResponse, error) {
+		rail, err := s.rails.RailByName(challenge.Rail)
+		if err != nil {
+			return nil, err
+		}
+		stepUp, ok := rail.(StepUpRail)
+		if !ok {
+			return nil, fmt.Errorf("%s rail does not support step-up authentication", challenge.Rail)
+		}
+		return stepUp.CompleteChallenge(ctx, ChallengeResult{
+			PaymentID:     challenge.PaymentID,
+			TransactionID: challenge.RailTransactionID,
+			Payload:       payload,
+		})
+	})
+}
+
+// completeChallenge answers the pending challenge of a payment with the
+// response verify returns, or fails it if it expired, and settles the
+// payment with the response
+func (s *PaymentService) completeChallenge(ctx context.Context, paymentID uuid.UUID, verify func(*models.ThreeDSChallenge) (*RailPaymentResponse, error)) (*models.ThreeDSChallenge, *models.Payment, error) {
+	log := s.logger.WithField("payment_id", paymentID)
+
+	var challenge models.ThreeDSChallenge
+	var payment models.Payment
+	var resumeEvent, intentEvent *PaymentIntentEvent
+	var processErr error
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("payment_id = ?", paymentID).Order("created_at DESC")
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.First(&challenge).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrThreeDSChallengeNotFound
+			}
+			return fmt.Errorf("failed to get 3-D Secure challenge: %w", err)
+		}
+		if err := tx.Preload("PaymentIntent").Where("id = ?", paymentID).First(&payment).Error; err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		if challenge.Status != models.ThreeDSChallengeStatusPending ||
+			payment.Status != models.PaymentStatusRequiresAction || payment.PaymentIntent == nil {
+			return nil
+		}
+		intent := payment.PaymentIntent
+
+		var resp *RailPaymentResponse
+		if time.Now().After(challenge.ExpiresAt) {
+			challenge.Status = models.ThreeDSChallengeStatusExpired
+			failureCode := "AUTHENTICATION_TIMEOUT"
+			failureMsg := "payer did not complete authentication in time"
+			resp = &RailPaymentResponse{Status: models.PaymentStatusFailed, FailureCode: &failureCode, FailureMessage: &failureMsg}
+		} else {
+			var err error
+			resp, err = verify(&challenge)
+			if err != nil {
+				log.WithError(err).Error("Failed to verify 3-D Secure challenge")
+				processErr = err
+				failureMsg := err.Error()
+				resp = &RailPaymentResponse{Status: models.PaymentStatusFailed, FailureMessage: &failureMsg}
+			}
+			challenge.Status = models.ThreeDSChallengeStatusSucceeded
+			if resp.Status == models.PaymentStatusFailed {
+				challenge.Status = models.ThreeDSChallengeStatusFailed
+			}
+		}
+		now := time.Now()
+		challenge.CompletedAt = &now
+		challenge.FailureMessage = resp.FailureMessage
+		if err := tx.Save(&challenge).Error; err != nil {
+			return fmt.Errorf("failed to update 3-D Secure challenge: %w", err)
+		}
+
+		// The attempt that asked for the challenge ends with it, unless the
+		// rail is still pending
+		var attemptStatus string
+		switch {
+		case resp.Success:
+			attemptStatus = models.PaymentAttemptStatusSucceeded
+		case resp.Status == models.PaymentStatusFailed:
+			attemptStatus = models.PaymentAttemptStatusFailed
+		}
+		if attemptStatus != "" {
+			err := tx.Model(&models.PaymentAttempt{}).
+				Where("payment_id = ? AND rail_transaction_id = ? AND status = ?", payment.ID, challenge.RailTransactionID, models.PaymentAttemptStatusPending).
+				Updates(map[string]interface{}{
+					"status":          attemptStatus,
+					"failure_code":    resp.FailureCode,
+					"failure_message": resp.FailureMessage,
+				}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update payment attempt: %w", err)
+			}
+		}
+
+		// Back to processing, then settled as any rail response
+		payment.Status = models.PaymentStatusProcessing
+		if err := appendPaymentEvent(tx, &payment, PaymentEventProcessing, PaymentEventData{}); err != nil {
+			return err
+		}
+		if err := tx.Save(&payment).Error; err != nil {
+			return fmt.Errorf("failed to update payment status: %w", err)
+		}
+		var err error
+		resumeEvent, err = s.transitionIntent(tx, intent, models.PaymentIntentStatusProcessing, "payer authentication "+challenge.Status)
+		if err != nil {
+			return err
+		}
+		intentEvent, err = s.settlePayment(ctx, tx, &payment, intent, resp)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resumeEvent != nil {
+		log.WithField("challenge_status", challenge.Status).Info("3-D Secure challenge completed")
+		merchantID := payment.PaymentIntent.MerchantID
+		s.emitPaymentIntentEvents(merchantID, resumeEvent)
+		s.emitPaymentEvents(merchantID, &payment, intentEvent)
+	}
+	return &challenge, &payment, processErr
+}
+
+// expireChallenges fails the payments whose challenge was not answered in
+// time
+func (s *PaymentService) expireChallenges(ctx context.Context) error {
+	var expired []models.ThreeDSChallenge
+	err := s.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", models.ThreeDSChallengeStatusPending, time.Now()).
+		Order("expires_at ASC").
+		Limit(50).
+		Find(&expired).Error
+	if err != nil {
+		return fmt.Errorf("failed to get expired 3-D Secure challenges: %w", err)
+	}
+
+	for _, challenge := range expired {
+		_, _, err := s.completeChallenge(ctx, challenge.PaymentID, func(*models.ThreeDSChallenge) (*RailPaymentResponse, error) {
+			return nil, fmt.Errorf("challenge expired")
+		})
+		if err != nil {
+			s.logger.WithError(err).WithField("payment_id", challenge.PaymentID).Error("Failed to expire 3-D Secure challenge")
+		}
+	}
+	return nil
+}
@@ -9,10 +9,13 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/money"
 	pb "github.com/suuupra/payments/proto/upi_core"
 )
 
@@ -77,18 +80,21 @@ func (c *UPIClient) ProcessPayment(ctx context.Context, req UPIPaymentRequest) (
 
 	log.Info("Processing UPI payment")
 
-	// Create gRPC request
+	// Create gRPC request. UPI Core resolves both banks from the VPAs.
 	grpcReq := &pb.TransactionRequest{
 		TransactionId: req.PaymentID.String(),
 		PayerVpa:      req.PayerVPA,
 		PayeeVpa:      req.PayeeVPA,
-		AmountPaisa:   req.Amount.IntPart() * 100, // Convert to paisa
+		AmountPaisa:   minorUnits(req.Amount, req.Currency),
+		Currency:      req.Currency,
 		Type:          pb.TransactionType_TRANSACTION_TYPE_P2M,
-		Reference:     req.Description,
-		PayerBankCode: "HDFC",  // This should be resolved from VPA
-		PayeeBankCode: "ICICI", // This should be resolved from VPA
+		Description:   req.Description,
+		Reference:     req.TransactionRef,
 		InitiatedAt:   timestamppb.Now(),
 	}
+	if req.MerchantID != "" {
+		grpcReq.Metadata = map[string]string{"merchant_id": req.MerchantID}
+	}
 
 	// Call UPI Core service
 	grpcResp, err := c.client.ProcessTransaction(ctx, grpcReq)
@@ -108,6 +114,9 @@ func (c *UPIClient) ProcessPayment(ctx context.Context, req UPIPaymentRequest) (
 		TransactionID: grpcResp.Rrn,
 		ProcessedAt:   time.Now(),
 	}
+	if grpcResp.ProcessedAt != nil {
+		response.ProcessedAt = grpcResp.ProcessedAt.AsTime()
+	}
 
 	if response.Success {
 		response.Status = models.PaymentStatusSucceeded
@@ -166,7 +175,9 @@ type UPIRefundStatusResponse struct {
 	ProcessedAt    *time.Time
 }
 
-// ProcessRefund processes a refund through UPI Core
+// ProcessRefund processes a refund through UPI Core by reversing the
+// original transaction. ReverseTransaction carries no amount, so UPI Core
+// always reverses the transaction in full.
 func (c *UPIClient) ProcessRefund(ctx context.Context, req UPIRefundRequest) (*UPIRefundResponse, error) {
 	log := c.logger.WithFields(logrus.Fields{
 		"refund_id":           req.RefundID,
@@ -178,17 +189,16 @@ func (c *UPIClient) ProcessRefund(ctx context.Context, req UPIRefundRequest) (*U
 
 	log.Info("Processing UPI refund")
 
-	// Create gRPC refund request
-	grpcReq := &pb.RefundRequest{
-		RefundId:      req.RefundID.String(),
-		TransactionId: req.TransactionID,
-		AmountPaisa:   req.Amount.IntPart() * 100, // Convert to paisa
-		Reason:        req.Reason,
-		InitiatedAt:   timestamppb.Now(),
+	// The original transaction is the payment ID we sent as transaction_id;
+	// the refund ID becomes the reversal's own transaction ID
+	grpcReq := &pb.ReverseTransactionRequest{
+		OriginalTransactionId: req.OriginalPaymentID.String(),
+		ReversalTransactionId: req.RefundID.String(),
+		Reason:                req.Reason,
 	}
 
 	// Call UPI Core service for refund processing
-	grpcResp, err := c.client.ProcessRefund(ctx, grpcReq)
+	grpcResp, err := c.client.ReverseTransaction(ctx, grpcReq)
 	if err != nil {
 		log.WithError(err).Error("Failed to call UPI Core service for refund")
 		return &UPIRefundResponse{
@@ -202,9 +212,12 @@ func (c *UPIClient) ProcessRefund(ctx context.Context, req UPIRefundRequest) (*U
 
 	// Convert gRPC response to our response format
 	response := &UPIRefundResponse{
-		Success:         grpcResp.Status == pb.RefundStatus_REFUND_STATUS_SUCCESS,
-		RefundReference: grpcResp.RefundRrn,
-		ProcessedAt:     grpcResp.ProcessedAt.AsTime(),
+		Success:         grpcResp.Success,
+		RefundReference: grpcResp.ReversalTransactionId,
+		ProcessedAt:     time.Now(),
+	}
+	if grpcResp.ReversedAt != nil {
+		response.ProcessedAt = grpcResp.ReversedAt.AsTime()
 	}
 
 	if response.Success {
@@ -229,18 +242,19 @@ func (c *UPIClient) ProcessRefund(ctx context.Context, req UPIRefundRequest) (*U
 	return response, nil
 }
 
-// CheckPaymentStatus checks the status of a UPI payment
+// CheckPaymentStatus checks the status of a UPI payment. transactionID is
+// the payment ID sent to UPI Core as transaction_id.
 func (c *UPIClient) CheckPaymentStatus(ctx context.Context, transactionID string) (*UPIPaymentResponse, error) {
 	log := c.logger.WithField("transaction_id", transactionID)
 	log.Info("Checking UPI payment status")
 
 	// Create gRPC status check request
-	grpcReq := &pb.StatusCheckRequest{
+	grpcReq := &pb.TransactionStatusRequest{
 		TransactionId: transactionID,
 	}
 
 	// Call UPI Core service for status check
-	grpcResp, err := c.client.CheckTransactionStatus(ctx, grpcReq)
+	grpcResp, err := c.client.GetTransactionStatus(ctx, grpcReq)
 	if err != nil {
 		log.WithError(err).Error("Failed to call UPI Core service for status check")
 		return &UPIPaymentResponse{
@@ -251,11 +265,41 @@ func (c *UPIClient) CheckPaymentStatus(ctx context.Context, transactionID string
 		}, nil
 	}
 
-	// Convert gRPC response to our response format
+	response := transactionStatusResponse(grpcResp)
+
+	log.WithFields(logrus.Fields{
+		"status":  response.Status,
+		"success": response.Success,
+	}).Info("UPI payment status retrieved")
+
+	return response, nil
+}
+
+// LookupPayment returns UPI Core's status of the payment it was sent as
+// transactionID, or nil if it never got it. Unlike CheckPaymentStatus, it
+// returns an error when UPI Core cannot be reached.
+func (c *UPIClient) LookupPayment(ctx context.Context, transactionID string) (*UPIPaymentResponse, error) {
+	grpcResp, err := c.client.GetTransactionStatus(ctx, &pb.TransactionStatusRequest{
+		TransactionId: transactionID,
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to call UPI Core service for status check: %w", err)
+	}
+	return transactionStatusResponse(grpcResp), nil
+}
+
+// transactionStatusResponse converts UPI Core's status of a transaction to
+// our response format
+func transactionStatusResponse(grpcResp *pb.TransactionStatusResponse) *UPIPaymentResponse {
 	response := &UPIPaymentResponse{
 		Success:       grpcResp.Status == pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS,
-		TransactionID: transactionID,
-		ProcessedAt:   grpcResp.ProcessedAt.AsTime(),
+		TransactionID: grpcResp.Rrn,
+	}
+	if grpcResp.ProcessedAt != nil {
+		response.ProcessedAt = grpcResp.ProcessedAt.AsTime()
 	}
 
 	// Map UPI Core status to our internal status
@@ -264,26 +308,21 @@ func (c *UPIClient) CheckPaymentStatus(ctx context.Context, transactionID string
 		response.Status = models.PaymentStatusSucceeded
 	case pb.TransactionStatus_TRANSACTION_STATUS_PENDING:
 		response.Status = models.PaymentStatusPending
-	case pb.TransactionStatus_TRANSACTION_STATUS_FAILED:
+	case pb.TransactionStatus_TRANSACTION_STATUS_FAILED, pb.TransactionStatus_TRANSACTION_STATUS_TIMEOUT:
 		response.Status = models.PaymentStatusFailed
 		if grpcResp.ErrorCode != "" {
 			response.FailureCode = &grpcResp.ErrorCode
 			response.FailureMessage = &grpcResp.ErrorMessage
 		}
-	case pb.TransactionStatus_TRANSACTION_STATUS_EXPIRED:
-		response.Status = models.PaymentStatusExpired
+	case pb.TransactionStatus_TRANSACTION_STATUS_CANCELLED:
+		response.Status = models.PaymentStatusCanceled
 	default:
 		response.Status = models.PaymentStatusFailed
 		failureMsg := "Unknown transaction status"
 		response.FailureMessage = &failureMsg
 	}
 
-	log.WithFields(logrus.Fields{
-		"status":  response.Status,
-		"success": response.Success,
-	}).Info("UPI payment status retrieved")
-
-	return response, nil
+	return response
 }
 
 // ValidateVPA validates a Virtual Payment Address
@@ -292,32 +331,29 @@ func (c *UPIClient) ValidateVPA(ctx context.Context, vpa string) (bool, error) {
 	log.Info("Validating VPA")
 
 	// Basic format validation first
-	if len(vpa) < 6 || !contains(vpa, "@") {
+	if err := ValidateVPAFormat(vpa); err != nil {
 		log.Warn("VPA validation failed: invalid format")
-		return false, fmt.Errorf("invalid VPA format")
+		return false, err
 	}
 
-	// Create gRPC VPA validation request
-	grpcReq := &pb.VpaValidationRequest{
+	// Create gRPC VPA resolution request
+	grpcReq := &pb.ResolveVPARequest{
 		Vpa: vpa,
 	}
 
 	// Call UPI Core service for VPA validation
-	grpcResp, err := c.client.ValidateVpa(ctx, grpcReq)
+	grpcResp, err := c.client.ResolveVPA(ctx, grpcReq)
 	if err != nil {
 		log.WithError(err).Error("Failed to call UPI Core service for VPA validation")
-		// Fall back to basic validation if service is unavailable
-		return len(vpa) >= 6 && contains(vpa, "@"), nil
+		// Fall back to the format check above if service is unavailable
+		return true, nil
 	}
 
-	// Check validation result
-	isValid := grpcResp.IsValid
+	// A VPA is only usable while its mapping is active
+	isValid := grpcResp.Exists && grpcResp.IsActive
 
 	if isValid {
-		log.WithFields(logrus.Fields{
-			"bank_code": grpcResp.BankCode,
-			"bank_name": grpcResp.BankName,
-		}).Info("VPA validation successful")
+		log.WithField("bank_code", grpcResp.BankCode).Info("VPA validation successful")
 	} else {
 		log.WithField("error_message", grpcResp.ErrorMessage).Warn("VPA validation failed")
 	}
@@ -334,13 +370,13 @@ func (c *UPIClient) CheckRefundStatus(ctx context.Context, req UPIRefundStatusRe
 
 	log.Info("Checking UPI refund status")
 
-	// Create gRPC refund status check request
-	grpcReq := &pb.RefundStatusRequest{
-		RefundReference: req.RefundReference,
+	// The refund reference is the reversal's transaction ID
+	grpcReq := &pb.TransactionStatusRequest{
+		TransactionId: req.RefundReference,
 	}
 
 	// Call UPI Core service for refund status check
-	grpcResp, err := c.client.CheckRefundStatus(ctx, grpcReq)
+	grpcResp, err := c.client.GetTransactionStatus(ctx, grpcReq)
 	if err != nil {
 		log.WithError(err).Error("Failed to call UPI Core service for refund status check")
 		return &UPIRefundStatusResponse{
@@ -353,22 +389,23 @@ func (c *UPIClient) CheckRefundStatus(ctx context.Context, req UPIRefundStatusRe
 
 	// Convert gRPC response to our response format
 	response := &UPIRefundStatusResponse{
-		Success: grpcResp.Status != pb.RefundStatus_REFUND_STATUS_FAILED,
+		Success: grpcResp.Status == pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS ||
+			grpcResp.Status == pb.TransactionStatus_TRANSACTION_STATUS_PENDING,
 	}
 
-	// Map UPI Core refund status to our internal status
+	// Map UPI Core transaction status to our internal refund status
 	switch grpcResp.Status {
-	case pb.RefundStatus_REFUND_STATUS_SUCCESS:
+	case pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS:
 		response.Status = models.RefundStatusSucceeded
 		if grpcResp.ProcessedAt != nil {
 			processedAt := grpcResp.ProcessedAt.AsTime()
 			response.ProcessedAt = &processedAt
 		}
-	case pb.RefundStatus_REFUND_STATUS_PENDING:
+	case pb.TransactionStatus_TRANSACTION_STATUS_PENDING:
 		response.Status = models.RefundStatusPending
-	case pb.RefundStatus_REFUND_STATUS_PROCESSING:
-		response.Status = models.RefundStatusProcessing
-	case pb.RefundStatus_REFUND_STATUS_FAILED:
+	case pb.TransactionStatus_TRANSACTION_STATUS_FAILED,
+		pb.TransactionStatus_TRANSACTION_STATUS_TIMEOUT,
+		pb.TransactionStatus_TRANSACTION_STATUS_CANCELLED:
 		response.Status = models.RefundStatusFailed
 		if grpcResp.ErrorCode != "" {
 			response.FailureCode = &grpcResp.ErrorCode
@@ -388,12 +425,95 @@ func (c *UPIClient) CheckRefundStatus(ctx context.Context, req UPIRefundStatusRe
 	return response, nil
 }
 
-// contains checks if a string contains a substring
-func contains(str, substr string) bool {
-	for i := 0; i <= len(str)-len(substr); i++ {
-		if str[i:i+len(substr)] == substr {
-			return true
+// UPISettlementReportRequest asks for a page of a bank's settlement report
+type UPISettlementReportRequest struct {
+	BankCode  string
+	From      time.Time
+	To        time.Time
+	PageSize  int
+	PageToken string // NextPageToken of the previous page
+}
+
+// UPISettlementRecord is a transaction in a settlement report. Its amounts
+// are in minor units of the transaction's currency, which the record does
+// not carry; Amounts converts them once the currency is known.
+type UPISettlementRecord struct {
+	TransactionID string
+	RRN           string
+	AmountMinor   int64
+	FeeMinor      int64
+	SettlementID  string // Empty until UPI Core settles the transaction
+	ProcessedAt   *time.Time
+}
+
+// Amounts returns the record's amount and fee in major units of currency
+func (r *UPISettlementRecord) Amounts(currency string) (amount, fee decimal.Decimal) {
+	return fromMinorUnits(r.AmountMinor, currency), fromMinorUnits(r.FeeMinor, currency)
+}
+
+// UPISettlementReport is a page of a bank's settlement report
+type UPISettlementReport struct {
+	BankCode      string
+	Records       []UPISettlementRecord
+	NextPageToken string // Empty on the last page
+}
+
+// GetSettlementReport fetches a page of a bank's settlement report. Unlike
+// payments, a failed call is returned as an error, for the caller to try
+// again later.
+func (c *UPIClient) GetSettlementReport(ctx context.Context, req UPISettlementReportRequest) (*UPISettlementReport, error) {
+	grpcReq := &pb.SettlementReportRequest{
+		BankCode:  req.BankCode,
+		FromDate:  timestamppb.New(req.From),
+		ToDate:    timestamppb.New(req.To),
+		PageSize:  int32(req.PageSize),
+		PageToken: req.PageToken,
+	}
+
+	grpcResp, err := c.client.GetSettlementReport(ctx, grpcReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settlement report of %s: %w", req.BankCode, err)
+	}
+
+	report := &UPISettlementReport{
+		BankCode:      grpcResp.BankCode,
+		Records:       make([]UPISettlementRecord, 0, len(grpcResp.Records)),
+		NextPageToken: grpcResp.NextPageToken,
+	}
+	for _, record := range grpcResp.Records {
+		settlementRecord := UPISettlementRecord{
+			TransactionID: record.TransactionId,
+			RRN:           record.Rrn,
+			AmountMinor:   record.AmountPaisa,
+			FeeMinor:      record.FeePaisa,
+			SettlementID:  record.SettlementId,
 		}
+		if record.ProcessedAt != nil {
+			processedAt := record.ProcessedAt.AsTime()
+			settlementRecord.ProcessedAt = &processedAt
+		}
+		report.Records = append(report.Records, settlementRecord)
+	}
+	return report, nil
+}
+
+// minorUnits converts an amount in major units of currency to the minor
+// units UPI Core and the gateways take: paisa for rupees, yen for yen.
+// Amounts are validated to be whole minor units when they are created.
+func minorUnits(amount decimal.Decimal, currency string) int64 {
+	exponent, err := money.Exponent(currency)
+	if err != nil {
+		exponent = 2
+	}
+	return amount.Shift(exponent).Round(0).IntPart()
+}
+
+// fromMinorUnits converts an amount in minor units of currency to major
+// units
+func fromMinorUnits(minor int64, currency string) decimal.Decimal {
+	exponent, err := money.Exponent(currency)
+	if err != nil {
+		exponent = 2
 	}
-	return false
+	return decimal.New(minor, -exponent)
 }
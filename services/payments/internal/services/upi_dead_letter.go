@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+var (
+	// ErrUPIDeadLetterNotFound is returned for a dead letter that does not
+	// exist
+	ErrUPIDeadLetterNotFound = errors.New("UPI dead letter not found")
+	// ErrUPIDeadLetterNotPending is returned when replaying or discarding a
+	// dead letter that was resolved already, or is being replayed
+	ErrUPIDeadLetterNotPending = errors.New("UPI dead letter is not pending")
+	// ErrUPIUnavailable is returned when replaying while UPI Core still
+	// cannot be reached
+	ErrUPIUnavailable = errors.New("UPI Core is unavailable")
+)
+
+// upiDeadLetterReplayTimeout is how long a replay can hold a dead letter
+// before it is given to another
+const upiDeadLetterReplayTimeout = 5 * time.Minute
+
+// UPIDeadLetterPolicy is how payments UPI Core could not be reached for
+// are dead-lettered and replayed
+type UPIDeadLetterPolicy struct {
+	ReplayInterval time.Duration // How often the worker replays the queue
+	MaxReplays     int           // Replays before the payment fails
+}
+
+// upiDeadLetterRequest is the call a dead letter keeps, as it was sent
+type upiDeadLetterRequest struct {
+	Rail               string          `json:"rail"`
+	AttemptNumber      int             `json:"attempt_number"`
+	RailReference      uuid.UUID       `json:"rail_reference"`
+	Amount             decimal.Decimal `json:"amount"`
+	Currency           string          `json:"currency"`
+	Description        string          `json:"description"`
+	MerchantID         string          `json:"merchant_id"`
+	PayerVPA           string          `json:"payer_vpa,omitempty"`
+	PayeeVPA           string          `json:"payee_vpa,omitempty"`
+	PaymentMethodToken string          `json:"payment_method_token,omitempty"`
+}
+
+// deadLetterPayment keeps payment processing in the dead letter queue if
+// its last attempt failed because UPI Core could not be reached, and
+// reports whether it did. A replay that failed the same way goes back in
+// the queue, until it ran out of replays.
+func (s *PaymentService) deadLetterPayment(tx *gorm.DB, payment *models.Payment, intent *models.PaymentIntent, railResp *RailPaymentResponse) (bool, error) {
+	if s.upiDeadLetters == nil || ClassifyFailure(railResp.FailureCode) != FailureCategoryRailUnavailable {
+		return false, nil
+	}
+	var attempt models.PaymentAttempt
+	if err := tx.Where("payment_id = ?", payment.ID).Order("attempt_number DESC").First(&attempt).Error; err != nil {
+		return false, fmt.Errorf("failed to get payment attempt: %w", err)
+	}
+	if attempt.Rail != RailUPI {
+		return false, nil
+	}
+
+	request, err := json.Marshal(upiDeadLetterRequest{
+		Rail:               attempt.Rail,
+		AttemptNumber:      attempt.AttemptNumber,
+		RailReference:      attempt.RailReference,
+		Amount:             payment.Amount,
+		Currency:           payment.Currency,
+		Description:        intent.Description,
+		MerchantID:         intent.MerchantID.String(),
+		PayerVPA:           attempt.PayerVPA,
+		PayeeVPA:           attempt.PayeeVPA,
+		PaymentMethodToken: attempt.PaymentMethodToken,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal UPI dead letter request: %w", err)
+	}
+
+	var letter models.UPIDeadLetter
+	err = tx.Where("payment_id = ? AND status IN ?", payment.ID, []string{
+		models.UPIDeadLetterStatusPending, models.UPIDeadLetterStatusReplaying,
+	}).First(&letter).Error
+	switch {
+	case err == nil:
+		if letter.Replays >= s.upiDeadLetters.MaxReplays {
+			now := time.Now()
+			letter.Status = models.UPIDeadLetterStatusExhausted
+			letter.ResolvedAt = &now
+			if err := tx.Save(&letter).Error; err != nil {
+				return false, fmt.Errorf("failed to update UPI dead letter: %w", err)
+			}
+			s.logger.WithField("payment_id", payment.ID).Warn("UPI dead letter ran out of replays")
+			return false, nil
+		}
+		letter.Status = models.UPIDeadLetterStatusPending
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		letter = models.UPIDeadLetter{
+			ID:              uuid.New(),
+			PaymentID:       payment.ID,
+			PaymentIntentID: intent.ID,
+			MerchantID:      intent.MerchantID,
+			Status:          models.UPIDeadLetterStatusPending,
+		}
+	default:
+		return false, fmt.Errorf("failed to get UPI dead letter: %w", err)
+	}
+	letter.AttemptID = attempt.ID
+	letter.Request = request
+	letter.FailureCode = railResp.FailureCode
+	letter.FailureMessage = railResp.FailureMessage
+	if err := tx.Save(&letter).Error; err != nil {
+		return false, fmt.Errorf("failed to save UPI dead letter: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"payment_id":     payment.ID,
+		"dead_letter_id": letter.ID,
+		"replays":        letter.Replays,
+	}).Warn("UPI call dead-lettered")
+	return true, nil
+}
+
+// ListUPIDeadLetters lists dead letters, oldest first, optionally only
+// those in status
+func (s *PaymentService) ListUPIDeadLetters(ctx context.Context, status string, limit int) ([]models.UPIDeadLetter, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	query := s.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var letters []models.UPIDeadLetter
+	if err := query.Order("created_at ASC").Limit(limit).Find(&letters).Error; err != nil {
+		return nil, fmt.Errorf("failed to list UPI dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+// GetUPIDeadLetter retrieves a dead letter by ID
+func (s *PaymentService) GetUPIDeadLetter(ctx context.Context, id uuid.UUID) (*models.UPIDeadLetter, error) {
+	var letter models.UPIDeadLetter
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&letter).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUPIDeadLetterNotFound
+		}
+		return nil, fmt.Errorf("failed to get UPI dead letter: %w", err)
+	}
+	return &letter, nil
+}
+
+// ReplayUPIDeadLetter sends a dead letter's payment to UPI Core again.
+// UPI Core is asked about the dead-lettered call first: if it got the call
+// after all, the payment is settled with its outcome rather than sent
+// twice, and if it still cannot be reached the dead letter stays pending
+// and ErrUPIUnavailable is returned. A payment that moved on since, e.g.
+// retried by other means, is not sent again. operator is empty for the
+// worker.
+func (s *PaymentService) ReplayUPIDeadLetter(ctx context.Context, id uuid.UUID, operator string) (*models.UPIDeadLetter, error) {
+	// Claim the dead letter, so each is replayed by one caller at a time
+	now := time.Now()
+	claim := s.db.WithContext(ctx).Model(&models.UPIDeadLetter{}).
+		Where("id = ? AND (status = ? OR (status = ? AND updated_at < ?))", id,
+			models.UPIDeadLetterStatusPending, models.UPIDeadLetterStatusReplaying, now.Add(-upiDeadLetterReplayTimeout)).
+		Updates(map[string]interface{}{
+			"status":           models.UPIDeadLetterStatusReplaying,
+			"replays":          gorm.Expr("replays + 1"),
+			"last_replayed_at": now,
+			"updated_at":       now,
+		})
+	if claim.Error != nil {
+		return nil, fmt.Errorf("failed to claim UPI dead letter: %w", claim.Error)
+	}
+	letter, err := s.GetUPIDeadLetter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if claim.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUPIDeadLetterNotPending, letter.Status)
+	}
+	log := s.logger.WithFields(logrus.Fields{
+		"payment_id":     letter.PaymentID,
+		"dead_letter_id": letter.ID,
+		"replays":        letter.Replays,
+	})
+
+	var attempt models.PaymentAttempt
+	if err := s.db.WithContext(ctx).Where("id = ?", letter.AttemptID).First(&attempt).Error; err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to get payment attempt: %w", err), s.releaseUPIDeadLetter(ctx, letter))
+	}
+	found, err := s.upiClient.LookupPayment(ctx, attempt.RailReference.String())
+	if err != nil {
+		log.WithError(err).Warn("UPI Core still unavailable, dead letter kept")
+		return nil, errors.Join(fmt.Errorf("%w: %v", ErrUPIUnavailable, err), s.releaseUPIDeadLetter(ctx, letter))
+	}
+
+	var payment models.Payment
+	var intentEvent *PaymentIntentEvent
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("PaymentIntent").Where("id = ?", letter.PaymentID).First(&payment).Error; err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		var last models.PaymentAttempt
+		if err := tx.Where("payment_id = ?", payment.ID).Order("attempt_number DESC").First(&last).Error; err != nil {
+			return fmt.Errorf("failed to get payment attempt: %w", err)
+		}
+		intent := payment.PaymentIntent
+
+		switch {
+		case payment.Status != models.PaymentStatusProcessing || intent == nil || last.ID != attempt.ID:
+			log.Info("Payment of UPI dead letter moved on, not replaying")
+		case found != nil:
+			// UPI Core got the call after all; settle with its outcome
+			log.WithField("status", found.Status).Info("UPI Core has the dead-lettered call, settling with it")
+			var err error
+			intentEvent, err = s.settlePayment(ctx, tx, &payment, intent, &RailPaymentResponse{
+				Success:        found.Success,
+				TransactionID:  found.TransactionID,
+				Status:         found.Status,
+				FailureCode:    found.FailureCode,
+				FailureMessage: found.FailureMessage,
+				ProcessedAt:    found.ProcessedAt,
+			})
+			if err != nil {
+				return err
+			}
+		default:
+			rail, err := s.rails.RailByName(attempt.Rail)
+			if err != nil {
+				return err
+			}
+			req, err := s.attemptRequest(ctx, &payment, intent, &attempt)
+			if err != nil {
+				return err
+			}
+			resp, err := s.runAttempts(ctx, tx, &payment, intent.MerchantID, rail, req)
+			if err != nil {
+				if resp == nil {
+					return err
+				}
+				log.WithError(err).Error("Rail payment processing failed")
+			}
+			intentEvent, err = s.settlePayment(ctx, tx, &payment, intent, resp)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Dead-lettered again, the letter is pending or exhausted by now
+		if err := tx.Where("id = ?", letter.ID).First(letter).Error; err != nil {
+			return fmt.Errorf("failed to get UPI dead letter: %w", err)
+		}
+		if letter.Status != models.UPIDeadLetterStatusReplaying {
+			return nil
+		}
+		resolvedAt := time.Now()
+		letter.Status = models.UPIDeadLetterStatusReplayed
+		letter.ResolvedBy = operator
+		letter.ResolvedAt = &resolvedAt
+		if err := tx.Save(letter).Error; err != nil {
+			return fmt.Errorf("failed to update UPI dead letter: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Join(err, s.releaseUPIDeadLetter(ctx, letter))
+	}
+
+	if intentEvent != nil {
+		s.emitPaymentEvents(payment.PaymentIntent.MerchantID, &payment, intentEvent)
+	}
+	if letter.Status == models.UPIDeadLetterStatusPending {
+		return letter, fmt.Errorf("%w: replay failed again", ErrUPIUnavailable)
+	}
+	log.WithField("status", letter.Status).Info("UPI dead letter replayed")
+	return letter, nil
+}
+
+// releaseUPIDeadLetter puts back a dead letter whose replay did not get to
+// UPI Core, without counting the replay
+func (s *PaymentService) releaseUPIDeadLetter(ctx context.Context, letter *models.UPIDeadLetter) error {
+	err := s.db.WithContext(ctx).Model(&models.UPIDeadLetter{}).
+		Where("id = ? AND status = ?", letter.ID, models.UPIDeadLetterStatusReplaying).
+		Updates(map[string]interface{}{
+			"status":  models.UPIDeadLetterStatusPending,
+			"replays": gorm.Expr("replays - 1"),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release UPI dead letter: %w", err)
+	}
+	return nil
+}
+
+// ReplayUPIDeadLetters replays the pending dead letters, oldest first. It
+// stops at the first UPI Core cannot be reached for, leaving the rest
+// queued, and returns how many were replayed.
+func (s *PaymentService) ReplayUPIDeadLetters(ctx context.Context, operator string) (int, error) {
+	letters, err := s.ListUPIDeadLetters(ctx, models.UPIDeadLetterStatusPending, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, letter := range letters {
+		_, err := s.ReplayUPIDeadLetter(ctx, letter.ID, operator)
+		switch {
+		case err == nil:
+			replayed++
+		case errors.Is(err, ErrUPIUnavailable):
+			return replayed, err
+		case errors.Is(err, ErrUPIDeadLetterNotPending):
+			// Replayed or discarded by someone else meanwhile
+		default:
+			s.logger.WithError(err).WithField("dead_letter_id", letter.ID).Error("Failed to replay UPI dead letter")
+		}
+	}
+	return replayed, nil
+}
+
+// DiscardUPIDeadLetter gives up on a pending dead letter, failing its
+// payment as UPI Core being unavailable
+func (s *PaymentService) DiscardUPIDeadLetter(ctx context.Context, id uuid.UUID, operator string) (*models.UPIDeadLetter, error) {
+	var letter models.UPIDeadLetter
+	var payment models.Payment
+	var intentEvent *PaymentIntentEvent
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("id = ?", id)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.First(&letter).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUPIDeadLetterNotFound
+			}
+			return fmt.Errorf("failed to get UPI dead letter: %w", err)
+		}
+		if letter.Status != models.UPIDeadLetterStatusPending {
+			return fmt.Errorf("%w: %s", ErrUPIDeadLetterNotPending, letter.Status)
+		}
+
+		now := time.Now()
+		letter.Status = models.UPIDeadLetterStatusDiscarded
+		letter.ResolvedBy = operator
+		letter.ResolvedAt = &now
+		if err := tx.Save(&letter).Error; err != nil {
+			return fmt.Errorf("failed to update UPI dead letter: %w", err)
+		}
+
+		if err := tx.Preload("PaymentIntent").Where("id = ?", letter.PaymentID).First(&payment).Error; err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		if payment.Status != models.PaymentStatusProcessing || payment.PaymentIntent == nil {
+			return nil
+		}
+		// Failed as a decline, so it is not dead-lettered again
+		failureCode := "UPI_UNAVAILABLE"
+		failureMsg := "UPI Core could not be reached"
+		var err error
+		intentEvent, err = s.settlePayment(ctx, tx, &payment, payment.PaymentIntent, &RailPaymentResponse{
+			Status:         models.PaymentStatusFailed,
+			FailureCode:    &failureCode,
+			FailureMessage: &failureMsg,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if intentEvent != nil {
+		s.emitPaymentEvents(payment.PaymentIntent.MerchantID, &payment, intentEvent)
+	}
+	s.logger.WithFields(logrus.Fields{
+		"payment_id":     letter.PaymentID,
+		"dead_letter_id": letter.ID,
+		"operator":       operator,
+	}).Info("UPI dead letter discarded")
+	return &letter, nil
+}
+
+// StartUPIDeadLetterWorker replays the dead letter queue every replay
+// interval until ctx is done
+func (s *PaymentService) StartUPIDeadLetterWorker(ctx context.Context) {
+	if s.upiDeadLetters == nil || s.upiDeadLetters.ReplayInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.upiDeadLetters.ReplayInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("Starting UPI dead letter worker")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping UPI dead letter worker")
+			return
+		case <-ticker.C:
+			replayed, err := s.ReplayUPIDeadLetters(ctx, "")
+			if errors.Is(err, ErrUPIUnavailable) {
+				s.logger.Debug("UPI Core still unavailable, dead letters kept")
+			} else if err != nil {
+				s.logger.WithError(err).Error("Failed to replay UPI dead letters")
+			}
+			if replayed > 0 {
+				s.logger.WithField("replayed", replayed).Info("Replayed UPI dead letters")
+			}
+		}
+	}
+}
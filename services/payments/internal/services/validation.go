@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/suuupra/payments/pkg/money"
+)
+
+// MaxAmount is ₹5,00,000, the highest per-transaction limit NPCI sets for
+// any UPI category. Larger rupee amounts can never settle.
+var MaxAmount = decimal.NewFromInt(500000)
+
+// ValidateAmount checks an amount in major units of currency is positive,
+// within MaxAmount for rupees and in whole minor units of the currency, and
+// returns it as Money. Fractions of a minor unit are rejected rather than
+// rounded, so what is charged is what was asked for.
+func ValidateAmount(amount decimal.Decimal, currency string) (money.Money, error) {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return money.Money{}, fmt.Errorf("amount must be greater than zero")
+	}
+	m, err := money.New(amount, currency)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("amount: %w", err)
+	}
+	if m.Currency == money.INR && amount.GreaterThan(MaxAmount) {
+		return money.Money{}, fmt.Errorf("amount must not exceed %s", MaxAmount.StringFixed(2))
+	}
+	return m, nil
+}
+
+// ValidateVPAFormat checks vpa is handle@psp: a 1-64 character handle of
+// ASCII letters, digits, dots, hyphens and underscores starting with a
+// letter or digit, and a 2-64 character PSP of ASCII letters and digits.
+// These are the rules UPI Core validates transactions with.
+func ValidateVPAFormat(vpa string) error {
+	handle, psp, ok := strings.Cut(vpa, "@")
+	if !ok || handle == "" || len(handle) > 64 || len(psp) < 2 || len(psp) > 64 {
+		return fmt.Errorf("invalid VPA format")
+	}
+	if !isAlnum(handle[0]) {
+		return fmt.Errorf("invalid VPA format")
+	}
+	for i := 0; i < len(handle); i++ {
+		if c := handle[i]; !isAlnum(c) && c != '.' && c != '-' && c != '_' {
+			return fmt.Errorf("invalid VPA format")
+		}
+	}
+	for i := 0; i < len(psp); i++ {
+		if !isAlnum(psp[i]) {
+			return fmt.Errorf("invalid VPA format")
+		}
+	}
+	return nil
+}
+
+func isAlnum(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// ValidateCardNumber checks number is a 12-19 digit card number passing
+// the Luhn check
+func ValidateCardNumber(number string) error {
+	if len(number) < 12 || len(number) > 19 {
+		return fmt.Errorf("invalid card number")
+	}
+	sum := 0
+	for i := 0; i < len(number); i++ {
+		c := number[len(number)-1-i]
+		if c < '0' || c > '9' {
+			return fmt.Errorf("invalid card number")
+		}
+		digit := int(c - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	if sum%10 != 0 {
+		return fmt.Errorf("invalid card number")
+	}
+	return nil
+}
+
+// CardBrand returns the network of a card number by its prefix, or
+// "unknown"
+func CardBrand(number string) string {
+	prefix := func(n int) int {
+		value := 0
+		for i := 0; i < n && i < len(number); i++ {
+			value = value*10 + int(number[i]-'0')
+		}
+		return value
+	}
+	switch {
+	case strings.HasPrefix(number, "4"):
+		return "visa"
+	case prefix(2) >= 51 && prefix(2) <= 55, prefix(4) >= 2221 && prefix(4) <= 2720:
+		return "mastercard"
+	case strings.HasPrefix(number, "34"), strings.HasPrefix(number, "37"):
+		return "amex"
+	case strings.HasPrefix(number, "60"), strings.HasPrefix(number, "65"),
+		strings.HasPrefix(number, "81"), strings.HasPrefix(number, "82"),
+		strings.HasPrefix(number, "508"):
+		return "rupay"
+	}
+	return "unknown"
+}
@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/hkdf"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+var (
+	// ErrPaymentMethodNotFound is returned for a payment method token that
+	// does not exist or belongs to another customer
+	ErrPaymentMethodNotFound = errors.New("payment method not found")
+	// ErrInvalidPaymentMethod is returned for card details or a VPA that
+	// cannot be saved
+	ErrInvalidPaymentMethod = errors.New("invalid payment method")
+	// ErrPaymentMethodMismatch is returned when paying with a saved method
+	// the intent's rail cannot carry, e.g. a card for a UPI intent
+	ErrPaymentMethodMismatch = errors.New("payment method does not match the payment")
+)
+
+const (
+	// paymentMethodTokenPrefix starts every payment method token
+	paymentMethodTokenPrefix = "pm_"
+	// paymentMethodTokenLength is the number of random base62 characters
+	// in a token
+	paymentMethodTokenLength = 24
+)
+
+// CardDetails are the card details a payment method is saved from. They
+// are only ever stored encrypted.
+type CardDetails struct {
+	Number     string `json:"number" binding:"required"`
+	ExpMonth   int    `json:"exp_month" binding:"required,min=1,max=12"`
+	ExpYear    int    `json:"exp_year" binding:"required"`
+	HolderName string `json:"holder_name"`
+}
+
+// vaultInstrument is the plaintext sealed in a payment method
+type vaultInstrument struct {
+	Card *CardDetails `json:"card,omitempty"`
+	VPA  string       `json:"vpa,omitempty"`
+}
+
+// vaultKeySalt salts the derivation of the vault's keys from
+// FIELD_ENCRYPTION_KEY. It is not secret; it keeps them apart from keys
+// derived from the same secret for other uses.
+var vaultKeySalt = []byte("suuupra-payments-vault")
+
+// VaultKey is a version of FIELD_ENCRYPTION_KEY. Payment methods record the
+// version their data key is wrapped with, so the key can be rotated: new
+// methods are sealed with the current version, and older ones are opened
+// with the version they were sealed with while it is still configured.
+type VaultKey struct {
+	Version int
+	Secret  string
+}
+
+// ParseVaultKeys parses vault keys written as version:secret,
+// comma-separated, e.g. "1:old-secret,2:older-secret"
+func ParseVaultKeys(spec string) ([]VaultKey, error) {
+	var keys []VaultKey
+	for i, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		// Errors do not quote the entry, which holds a secret
+		version, secret, ok := strings.Cut(entry, ":")
+		if !ok || secret == "" {
+			return nil, fmt.Errorf("vault key %d: expected version:secret", i+1)
+		}
+		v, err := strconv.Atoi(version)
+		if err != nil || v < 1 {
+			return nil, fmt.Errorf("vault key %d: invalid version", i+1)
+		}
+		keys = append(keys, VaultKey{Version: v, Secret: secret})
+	}
+	return keys, nil
+}
+
+// VaultService saves customers' cards and VPAs as payment method tokens.
+// Each instrument is encrypted with a data key of its own, and the data key
+// with the vault's key, so neither PANs nor VPAs are stored in plaintext.
+type VaultService struct {
+	db             *gorm.DB
+	logger         *logrus.Logger
+	keyID          string            // Identifies the vault key new data keys are wrapped with
+	keys           map[string][]byte // Vault keys by ID, the current and previous versions
+	fingerprintKey []byte
+}
+
+// NewVaultService creates a new vault service sealing payment methods with
+// the current version of FIELD_ENCRYPTION_KEY, and opening ones sealed with
+// a previous version. Its keys are derived from the secrets with
+// HKDF-SHA256. Fingerprints use the current version, so a method saved
+// before a rotation is not recognised when saved again after it.
+func NewVaultService(db *gorm.DB, logger *logrus.Logger, current VaultKey, previous ...VaultKey) *VaultService {
+	s := &VaultService{
+		db:             db,
+		logger:         logger,
+		keys:           make(map[string][]byte),
+		fingerprintKey: deriveVaultKey(current.Secret, "vault:fingerprint"),
+	}
+	for _, key := range previous {
+		keyEncryption := deriveVaultKey(key.Secret, fmt.Sprintf("vault:kek:v%d", key.Version))
+		s.keys[vaultKeyID(key.Version, keyEncryption)] = keyEncryption
+	}
+	keyEncryption := deriveVaultKey(current.Secret, fmt.Sprintf("vault:kek:v%d", current.Version))
+	s.keyID = vaultKeyID(current.Version, keyEncryption)
+	s.keys[s.keyID] = keyEncryption
+	return s
+}
+
+// deriveVaultKey derives a 256-bit key for info from secret
+func deriveVaultKey(secret, info string) []byte {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(secret), vaultKeySalt, []byte(info)), key); err != nil {
+		// HKDF-SHA256 yields up to 8160 bytes
+		panic(fmt.Sprintf("vault: deriving key: %v", err))
+	}
+	return key
+}
+
+// vaultKeyID identifies a vault key by its version and a check of the key,
+// so a version configured with the wrong secret is told apart from it
+func vaultKeyID(version int, keyEncryption []byte) string {
+	check := sha256.Sum256(keyEncryption)
+	return fmt.Sprintf("v%d:%s", version, hex.EncodeToString(check[:4]))
+}
+
+// CreatePaymentMethodRequest represents a request to save a payment
+// method. Card is required for card methods and VPA for UPI methods.
+type CreatePaymentMethodRequest struct {
+	MerchantID uuid.UUID    `json:"merchant_id" binding:"required"`
+	CustomerID uuid.UUID    `json:"-"` // Set from the path
+	Type       string       `json:"type" binding:"required,oneof=card upi"`
+	Card       *CardDetails `json:"card"`
+	VPA        string       `json:"vpa"`
+}
+
+// CreatePaymentMethod saves a customer's card or VPA. Saving one the
+// customer already has returns the saved method.
+func (s *VaultService) CreatePaymentMethod(ctx context.Context, req CreatePaymentMethodRequest) (*models.PaymentMethod, error) {
+	method := &models.PaymentMethod{
+		ID:         uuid.New(),
+		MerchantID: req.MerchantID,
+		CustomerID: req.CustomerID,
+		Type:       req.Type,
+		KeyID:      s.keyID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	var instrument vaultInstrument
+	switch req.Type {
+	case models.PaymentMethodTypeCard:
+		if req.Card == nil {
+			return nil, fmt.Errorf("%w: card details are required for card methods", ErrInvalidPaymentMethod)
+		}
+		card := *req.Card
+		card.Number = strings.ReplaceAll(strings.ReplaceAll(card.Number, " ", ""), "-", "")
+		if card.ExpYear < 100 {
+			card.ExpYear += 2000
+		}
+		if err := ValidateCardNumber(card.Number); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPaymentMethod, err)
+		}
+		if err := validateCardExpiry(card.ExpMonth, card.ExpYear, time.Now()); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPaymentMethod, err)
+		}
+		instrument.Card = &card
+		method.Brand = CardBrand(card.Number)
+		method.Last4 = card.Number[len(card.Number)-4:]
+		method.ExpMonth = card.ExpMonth
+		method.ExpYear = card.ExpYear
+		method.Fingerprint = s.fingerprint(card.Number)
+	case models.PaymentMethodTypeUPI:
+		vpa := strings.ToLower(strings.TrimSpace(req.VPA))
+		if err := ValidateVPAFormat(vpa); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPaymentMethod, err)
+		}
+		instrument.VPA = vpa
+		method.VPA = maskVPA(vpa)
+		method.Fingerprint = s.fingerprint(vpa)
+	}
+
+	var existing models.PaymentMethod
+	err := s.db.WithContext(ctx).
+		Where("merchant_id = ? AND customer_id = ? AND fingerprint = ?", req.MerchantID, req.CustomerID, method.Fingerprint).
+		First(&existing).Error
+	if err == nil {
+		return &existing, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to find payment method: %w", err)
+	}
+
+	token, err := randomBase62(paymentMethodTokenLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate payment method token: %w", err)
+	}
+	method.Token = paymentMethodTokenPrefix + token
+	if method.EncryptedData, method.EncryptedKey, err = s.seal(method, instrument); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(method).Error; err != nil {
+		return nil, fmt.Errorf("failed to create payment method: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"payment_method": method.Token,
+		"merchant_id":    method.MerchantID,
+		"customer_id":    method.CustomerID,
+		"type":           method.Type,
+	}).Info("Payment method saved")
+	return method, nil
+}
+
+// ListPaymentMethods lists a customer's saved payment methods, newest
+// first
+func (s *VaultService) ListPaymentMethods(ctx context.Context, merchantID, customerID uuid.UUID) ([]models.PaymentMethod, error) {
+	var methods []models.PaymentMethod
+	err := s.db.WithContext(ctx).
+		Where("merchant_id = ? AND customer_id = ?", merchantID, customerID).
+		Order("created_at DESC").
+		Find(&methods).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment methods: %w", err)
+	}
+	return methods, nil
+}
+
+// DeletePaymentMethod deletes a customer's saved payment method, along
+// with its encrypted instrument
+func (s *VaultService) DeletePaymentMethod(ctx context.Context, merchantID, customerID uuid.UUID, token string) error {
+	result := s.db.WithContext(ctx).
+		Where("token = ? AND merchant_id = ? AND customer_id = ?", token, merchantID, customerID).
+		Delete(&models.PaymentMethod{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete payment method: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrPaymentMethodNotFound
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"payment_method": token,
+		"merchant_id":    merchantID,
+		"customer_id":    customerID,
+	}).Info("Payment method deleted")
+	return nil
+}
+
+// open returns a merchant's saved payment method by token, with its
+// decrypted instrument
+func (s *VaultService) open(ctx context.Context, merchantID uuid.UUID, token string) (*models.PaymentMethod, *vaultInstrument, error) {
+	var method models.PaymentMethod
+	err := s.db.WithContext(ctx).
+		Where("token = ? AND merchant_id = ?", token, merchantID).
+		First(&method).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, ErrPaymentMethodNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to find payment method: %w", err)
+	}
+
+	instrument, err := s.unseal(&method)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &method, instrument, nil
+}
+
+// seal encrypts method's instrument under a new data key, returning the
+// ciphertext and the data key encrypted with the vault key. Both are bound
+// to the method's token, so neither can be moved to another method.
+func (s *VaultService) seal(method *models.PaymentMethod, instrument vaultInstrument) ([]byte, []byte, error) {
+	plaintext, err := json.Marshal(instrument)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode payment method: %w", err)
+	}
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	data, err := sealAESGCM(dataKey, plaintext, []byte(method.Token))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encrypt payment method: %w", err)
+	}
+	wrappedKey, err := sealAESGCM(s.keys[s.keyID], dataKey, []byte(method.Token))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encrypt data key: %w", err)
+	}
+	return data, wrappedKey, nil
+}
+
+// unseal decrypts a payment method's instrument
+func (s *VaultService) unseal(method *models.PaymentMethod) (*vaultInstrument, error) {
+	keyEncryption, ok := s.keys[method.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("payment method %s is encrypted with unknown key %s", method.Token, method.KeyID)
+	}
+	dataKey, err := openAESGCM(keyEncryption, method.EncryptedKey, []byte(method.Token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	plaintext, err := openAESGCM(dataKey, method.EncryptedData, []byte(method.Token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt payment method: %w", err)
+	}
+	var instrument vaultInstrument
+	if err := json.Unmarshal(plaintext, &instrument); err != nil {
+		return nil, fmt.Errorf("failed to decode payment method: %w", err)
+	}
+	return &instrument, nil
+}
+
+// fingerprint identifies an instrument without revealing it, so a
+// customer's saved methods can be told apart
+func (s *VaultService) fingerprint(value string) string {
+	mac := hmac.New(sha256.New, s.fingerprintKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// sealAESGCM encrypts plaintext with AES-256-GCM, authenticating
+// additionalData with it, and prefixes the nonce
+func sealAESGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// openAESGCM decrypts what sealAESGCM encrypted
+func openAESGCM(key, ciphertext, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, additionalData)
+}
+
+// maskVPA hides all but the first two characters of a VPA's handle
+func maskVPA(vpa string) string {
+	handle, psp, _ := strings.Cut(vpa, "@")
+	if len(handle) > 2 {
+		handle = handle[:2] + strings.Repeat("*", len(handle)-2)
+	}
+	return handle + "@" + psp
+}
+
+// validateCardExpiry checks a card has not expired by now
+func validateCardExpiry(month, year int, now time.Time) error {
+	if month < 1 || month > 12 {
+		return fmt.Errorf("invalid expiry month")
+	}
+	if year < now.Year() || year == now.Year() && month < int(now.Month()) {
+		return fmt.Errorf("card has expired")
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+)
+
+var testVaultKey = VaultKey{Version: 1, Secret: "vault-test-secret"}
+
+func saveCard(t *testing.T, vault *VaultService, merchantID, customerID uuid.UUID, number string) *models.PaymentMethod {
+	method, err := vault.CreatePaymentMethod(context.Background(), CreatePaymentMethodRequest{
+		MerchantID: merchantID,
+		CustomerID: customerID,
+		Type:       models.PaymentMethodTypeCard,
+		Card:       &CardDetails{Number: number, ExpMonth: 12, ExpYear: time.Now().Year() + 2, HolderName: "Test Holder"},
+	})
+	require.NoError(t, err)
+	return method
+}
+
+// storedMethod reads method back as stored
+func storedMethod(t *testing.T, db *gorm.DB, method *models.PaymentMethod) *models.PaymentMethod {
+	var stored models.PaymentMethod
+	require.NoError(t, db.Where("id = ?", method.ID).First(&stored).Error)
+	return &stored
+}
+
+func TestVault_RoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	vault := NewVaultService(db, testLogger(), testVaultKey)
+	ctx := context.Background()
+	merchantID, customerID := uuid.New(), uuid.New()
+
+	card := saveCard(t, vault, merchantID, customerID, "4242 4242 4242 4242")
+	assert.Regexp(t, `^pm_[0-9A-Za-z]{24}$`, card.Token)
+	assert.Equal(t, "4242", card.Last4)
+	assert.NotContains(t, string(storedMethod(t, db, card).EncryptedData), "4242424242424242")
+
+	method, instrument, err := vault.open(ctx, merchantID, card.Token)
+	require.NoError(t, err)
+	assert.Equal(t, card.ID, method.ID)
+	require.NotNil(t, instrument.Card)
+	assert.Equal(t, "4242424242424242", instrument.Card.Number)
+	assert.Equal(t, "Test Holder", instrument.Card.HolderName)
+
+	upi, err := vault.CreatePaymentMethod(ctx, CreatePaymentMethodRequest{
+		MerchantID: merchantID, CustomerID: customerID, Type: models.PaymentMethodTypeUPI, VPA: " Payer@UPI ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "pa***@upi", upi.VPA)
+	_, instrument, err = vault.open(ctx, merchantID, upi.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "payer@upi", instrument.VPA)
+
+	// The same card is found rather than saved again
+	again := saveCard(t, vault, merchantID, customerID, "4242-4242-4242-4242")
+	assert.Equal(t, card.ID, again.ID)
+
+	_, _, err = vault.open(ctx, uuid.New(), card.Token)
+	assert.ErrorIs(t, err, ErrPaymentMethodNotFound, "another merchant opened the method")
+}
+
+func TestVault_DetectsTampering(t *testing.T) {
+	db := setupTestDB(t)
+	vault := NewVaultService(db, testLogger(), testVaultKey)
+	merchantID := uuid.New()
+	method := storedMethod(t, db, saveCard(t, vault, merchantID, uuid.New(), "4242424242424242"))
+
+	flip := func(b []byte) []byte {
+		tampered := append([]byte(nil), b...)
+		tampered[len(tampered)-1] ^= 0x01
+		return tampered
+	}
+	for _, tc := range []struct {
+		name   string
+		tamper func(m *models.PaymentMethod)
+	}{
+		{"ciphertext", func(m *models.PaymentMethod) { m.EncryptedData = flip(m.EncryptedData) }},
+		{"wrapped data key", func(m *models.PaymentMethod) { m.EncryptedKey = flip(m.EncryptedKey) }},
+		{"truncated", func(m *models.PaymentMethod) { m.EncryptedData = m.EncryptedData[:8] }},
+		{"token", func(m *models.PaymentMethod) { m.Token = "pm_" + m.Token[3:len(m.Token)-1] + "x" }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tampered := *method
+			tc.tamper(&tampered)
+			_, err := vault.unseal(&tampered)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := vault.unseal(method)
+	assert.NoError(t, err)
+}
+
+func TestVault_WrongKey(t *testing.T) {
+	db := setupTestDB(t)
+	vault := NewVaultService(db, testLogger(), testVaultKey)
+	merchantID := uuid.New()
+	card := saveCard(t, vault, merchantID, uuid.New(), "4242424242424242")
+	method := storedMethod(t, db, card)
+
+	// The same version with another secret is another key
+	other := NewVaultService(db, testLogger(), VaultKey{Version: 1, Secret: "another-secret"})
+	_, _, err := other.open(context.Background(), merchantID, card.Token)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown key")
+
+	// Even were it to claim the key's ID, it cannot unwrap the data key
+	other.keys[method.KeyID] = other.keys[other.keyID]
+	_, err = other.unseal(method)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decrypt data key")
+}
+
+func TestVault_KeyRotation(t *testing.T) {
+	db := setupTestDB(t)
+	merchantID := uuid.New()
+	v1 := NewVaultService(db, testLogger(), testVaultKey)
+	old := saveCard(t, v1, merchantID, uuid.New(), "4242424242424242")
+
+	v2Key := VaultKey{Version: 2, Secret: "vault-test-secret-2"}
+	v2 := NewVaultService(db, testLogger(), v2Key, testVaultKey)
+	_, instrument, err := v2.open(context.Background(), merchantID, old.Token)
+	require.NoError(t, err, "method sealed with the previous key not opened")
+	assert.Equal(t, "4242424242424242", instrument.Card.Number)
+
+	rotated := saveCard(t, v2, merchantID, uuid.New(), "5555555555554444")
+	assert.Regexp(t, `^v2:`, rotated.KeyID)
+	assert.NotEqual(t, old.KeyID, rotated.KeyID)
+
+	// Once the previous key is dropped its methods no longer open
+	_, _, err = NewVaultService(db, testLogger(), v2Key).open(context.Background(), merchantID, old.Token)
+	assert.Error(t, err)
+}
+
+func TestVault_DataKeyPerMethod(t *testing.T) {
+	db := setupTestDB(t)
+	vault := NewVaultService(db, testLogger(), testVaultKey)
+	merchantID := uuid.New()
+
+	// The same card, saved for two customers
+	a := storedMethod(t, db, saveCard(t, vault, merchantID, uuid.New(), "4242424242424242"))
+	b := storedMethod(t, db, saveCard(t, vault, merchantID, uuid.New(), "4242424242424242"))
+	assert.Equal(t, a.Fingerprint, b.Fingerprint)
+	assert.NotEqual(t, a.EncryptedData, b.EncryptedData)
+
+	kek := vault.keys[vault.keyID]
+	keyA, err := openAESGCM(kek, a.EncryptedKey, []byte(a.Token))
+	require.NoError(t, err)
+	keyB, err := openAESGCM(kek, b.EncryptedKey, []byte(b.Token))
+	require.NoError(t, err)
+	assert.NotEqual(t, keyA, keyB, "methods share a data key")
+
+	_, err = openAESGCM(keyA, b.EncryptedData, []byte(b.Token))
+	assert.Error(t, err, "one method's data key opened another's instrument")
+
+	// Nor can a method's sealed instrument and key be moved to another
+	moved := *b
+	moved.EncryptedData, moved.EncryptedKey = a.EncryptedData, a.EncryptedKey
+	_, err = vault.unseal(&moved)
+	assert.Error(t, err)
+}
+
+func TestParseVaultKeys(t *testing.T) {
+	keys, err := ParseVaultKeys(" 1:first-secret , 2:second:secret ,")
+	require.NoError(t, err)
+	assert.Equal(t, []VaultKey{{1, "first-secret"}, {2, "second:secret"}}, keys)
+
+	for _, spec := range []string{"hunter2", "0:hunter2", "x:hunter2", "3:"} {
+		_, err := ParseVaultKeys(spec)
+		require.Error(t, err, spec)
+		assert.NotContains(t, err.Error(), "hunter2", "error leaks the secret")
+	}
+
+	keys, err = ParseVaultKeys("")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
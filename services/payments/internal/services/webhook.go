@@ -1,69 +1,96 @@
 package services
 
 import (
-	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/webhooksig"
 )
 
+var (
+	// ErrWebhookEndpointNotFound is returned for a webhook endpoint that
+	// does not exist
+	ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+	// ErrWebhookEndpointDisabled is returned when redelivering to an
+	// endpoint that is not active
+	ErrWebhookEndpointDisabled = errors.New("webhook endpoint is disabled")
+	// ErrWebhookDeliveryNotFound is returned for a webhook delivery that
+	// does not exist
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrWebhookDeliveryInFlight is returned when redelivering a delivery a
+	// worker is attempting
+	ErrWebhookDeliveryInFlight = errors.New("webhook delivery is being attempted")
+	// ErrInvalidWebhookSecret is returned when rotating to a secret that is
+	// too short, or the one in use
+	ErrInvalidWebhookSecret = errors.New("invalid webhook secret")
+	// ErrInvalidWebhookSignature wraps the webhooksig error a received
+	// delivery failed verification with
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+)
+
+// minWebhookSecretLength is the shortest secret an endpoint can rotate to
+const minWebhookSecretLength = 16
+
+// WebhookDeliveryPolicy configures webhook delivery workers, the backoff
+// and disablement of endpoints that fail, and secret rotation
+type WebhookDeliveryPolicy struct {
+	Workers              int
+	BackoffBase          time.Duration // Doubled on every failure, with jitter
+	BackoffMax           time.Duration
+	DisableAfterFailures int           // Consecutive failures disabling an endpoint
+	DisableAfter         time.Duration // that has been failing for this long
+	SecretOverlap        time.Duration // Default time a rotated secret still signs
+}
+
 // WebhookService handles webhook management and delivery
 type WebhookService struct {
-	db              *gorm.DB
-	logger          *logrus.Logger
-	httpClient      *http.Client
-	signingSecret   string
-	maxRetries      int
-	timeoutSeconds  int
-	cron            *cron.Cron
+	db             *gorm.DB
+	logger         *logrus.Logger
+	httpClient     *http.Client
+	signingSecret  string
+	maxRetries     int
+	timeoutSeconds int
+	policy         WebhookDeliveryPolicy
+
+	// queue feeds delivery IDs to the workers; queued holds those in it
+	queue    chan uuid.UUID
+	queuedMu sync.Mutex
+	queued   map[uuid.UUID]struct{}
+
+	// nonces holds those of deliveries VerifyWebhook has accepted
+	nonces webhooksig.NonceStore
 }
 
 // NewWebhookService creates a new webhook service
-func NewWebhookService(db *gorm.DB, logger *logrus.Logger, signingSecret string, maxRetries, timeoutSeconds int) *WebhookService {
+func NewWebhookService(db *gorm.DB, logger *logrus.Logger, signingSecret string, maxRetries, timeoutSeconds int, policy WebhookDeliveryPolicy) *WebhookService {
+	if policy.Workers <= 0 {
+		policy.Workers = 1
+	}
 	return &WebhookService{
-		db:              db,
-		logger:          logger,
-		httpClient:      &http.Client{Timeout: time.Duration(timeoutSeconds) * time.Second},
-		signingSecret:   signingSecret,
-		maxRetries:      maxRetries,
-		timeoutSeconds:  timeoutSeconds,
-		cron:            cron.New(),
+		db:             db,
+		logger:         logger,
+		httpClient:     &http.Client{Timeout: time.Duration(timeoutSeconds) * time.Second},
+		signingSecret:  signingSecret,
+		maxRetries:     maxRetries,
+		timeoutSeconds: timeoutSeconds,
+		policy:         policy,
+		queue:          make(chan uuid.UUID, policy.Workers*16),
+		queued:         make(map[uuid.UUID]struct{}),
+		nonces:         webhooksig.NewMemoryNonceStore(),
 	}
 }
 
-// Start starts the webhook service and retry scheduler
-func (s *WebhookService) Start() {
-	s.logger.Info("Starting webhook service")
-	
-	// Schedule retry job every minute
-	s.cron.AddFunc("@every 1m", func() {
-		ctx := context.Background()
-		if err := s.retryFailedDeliveries(ctx); err != nil {
-			s.logger.WithError(err).Error("Failed to retry webhook deliveries")
-		}
-	})
-	
-	s.cron.Start()
-}
-
-// Stop stops the webhook service
-func (s *WebhookService) Stop() {
-	s.logger.Info("Stopping webhook service")
-	s.cron.Stop()
-}
-
 // CreateWebhookEndpointRequest represents a webhook endpoint creation request
 type CreateWebhookEndpointRequest struct {
 	MerchantID  uuid.UUID `json:"merchant_id" binding:"required"`
@@ -135,11 +162,19 @@ func (s *WebhookService) UpdateWebhookEndpoint(ctx context.Context, id uuid.UUID
 	err := s.db.WithContext(ctx).Where("id = ?", id).First(&endpoint).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("webhook endpoint not found")
+			return nil, ErrWebhookEndpointNotFound
 		}
 		return nil, fmt.Errorf("failed to find webhook endpoint: %w", err)
 	}
 
+	// Reactivating an endpoint clears its failures
+	if active, ok := updates["active"].(bool); ok && active {
+		updates["consecutive_failures"] = 0
+		updates["failing_since"] = nil
+		updates["backoff_until"] = nil
+		updates["disabled_at"] = nil
+		updates["disabled_reason"] = nil
+	}
 	updates["updated_at"] = time.Now()
 	err = s.db.WithContext(ctx).Model(&endpoint).Updates(updates).Error
 	if err != nil {
@@ -157,7 +192,7 @@ func (s *WebhookService) DeleteWebhookEndpoint(ctx context.Context, id uuid.UUID
 	}
 	
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("webhook endpoint not found")
+		return ErrWebhookEndpointNotFound
 	}
 
 	return nil
@@ -229,7 +264,7 @@ func (s *WebhookService) TriggerWebhook(ctx context.Context, merchantID uuid.UUI
 			EventType:     eventType,
 			EventID:       event.ID,
 			Payload:       eventPayload,
-			Status:        "pending",
+			Status:        models.WebhookDeliveryStatusPending,
 			AttemptCount:  0,
 			MaxAttempts:   s.maxRetries,
 			NextAttemptAt: timePtr(time.Now()),
@@ -237,8 +272,7 @@ func (s *WebhookService) TriggerWebhook(ctx context.Context, merchantID uuid.UUI
 			UpdatedAt:     time.Now(),
 		}
 
-		// Generate HMAC signature
-		delivery.Signature = s.generateSignature(eventPayload, endpoint.Secret)
+		// Deliveries are signed per attempt, see post
 
 		err := s.db.WithContext(ctx).Create(delivery).Error
 		if err != nil {
@@ -246,163 +280,109 @@ func (s *WebhookService) TriggerWebhook(ctx context.Context, merchantID uuid.UUI
 			continue
 		}
 
-		// Attempt immediate delivery
-		go s.attemptDelivery(delivery, &endpoint)
+		// Hand the delivery to the workers straight away
+		s.enqueue(delivery.ID)
 	}
 
 	log.WithField("endpoint_count", len(relevantEndpoints)).Info("Webhook triggered for endpoints")
 }
 
-// attemptDelivery attempts to deliver a webhook
-func (s *WebhookService) attemptDelivery(delivery *models.WebhookDelivery, endpoint *models.WebhookEndpoint) {
-	log := s.logger.WithFields(logrus.Fields{
-		"delivery_id":   delivery.ID,
-		"endpoint_id":   delivery.EndpointID,
-		"event_type":    delivery.EventType,
-		"attempt_count": delivery.AttemptCount + 1,
-		"url":           endpoint.URL,
-	})
-
-	log.Info("Attempting webhook delivery")
-
-	// Increment attempt count
-	delivery.AttemptCount++
-	delivery.UpdatedAt = time.Now()
-
-	// Create HTTP request
-	req, err := http.NewRequest("POST", endpoint.URL, bytes.NewReader(delivery.Payload))
-	if err != nil {
-		log.WithError(err).Error("Failed to create HTTP request")
-		s.markDeliveryFailed(delivery, fmt.Sprintf("Failed to create request: %v", err))
-		return
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Webhook-Signature", delivery.Signature)
-	req.Header.Set("X-Webhook-Event-Type", delivery.EventType)
-	req.Header.Set("X-Webhook-Event-ID", delivery.EventID.String())
-	req.Header.Set("X-Webhook-Delivery-ID", delivery.ID.String())
-	req.Header.Set("User-Agent", "Suuupra-Webhooks/1.0")
-
-	// Send request
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		log.WithError(err).Error("HTTP request failed")
-		s.scheduleRetry(delivery, fmt.Sprintf("Request failed: %v", err))
-		return
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	var responseBody bytes.Buffer
-	responseBody.ReadFrom(resp.Body)
-	responseBodyStr := responseBody.String()
-
-	delivery.ResponseStatus = &resp.StatusCode
-	delivery.ResponseBody = &responseBodyStr
-
-	// Check if delivery was successful (2xx status codes)
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		log.WithField("status_code", resp.StatusCode).Info("Webhook delivered successfully")
-		s.markDeliverySuccessful(delivery)
-	} else {
-		log.WithFields(logrus.Fields{
-			"status_code":    resp.StatusCode,
-			"response_body":  responseBodyStr,
-		}).Warn("Webhook delivery failed with non-2xx status")
-		s.scheduleRetry(delivery, fmt.Sprintf("HTTP %d: %s", resp.StatusCode, responseBodyStr))
-	}
+// RotateWebhookSecretRequest rotates a webhook endpoint's secret
+type RotateWebhookSecretRequest struct {
+	Secret         string `json:"secret"`          // Generated if empty
+	OverlapSeconds *int   `json:"overlap_seconds"` // Defaults to the policy's overlap
 }
 
-// markDeliverySuccessful marks a delivery as successful
-func (s *WebhookService) markDeliverySuccessful(delivery *models.WebhookDelivery) {
-	now := time.Now()
-	delivery.Status = "delivered"
-	delivery.DeliveredAt = &now
-	delivery.NextAttemptAt = nil
-	delivery.UpdatedAt = now
-
-	err := s.db.Save(delivery).Error
-	if err != nil {
-		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to update delivery status")
+// RotateWebhookSecret replaces an endpoint's secret. Deliveries are signed
+// with both the old and new secret until the overlap ends, so the merchant
+// can switch their verification over without rejecting any.
+func (s *WebhookService) RotateWebhookSecret(ctx context.Context, id uuid.UUID, req RotateWebhookSecretRequest) (*models.WebhookEndpoint, error) {
+	overlap := s.policy.SecretOverlap
+	if req.OverlapSeconds != nil {
+		if *req.OverlapSeconds < 0 {
+			return nil, fmt.Errorf("%w: overlap_seconds must not be negative", ErrInvalidWebhookSecret)
+		}
+		overlap = time.Duration(*req.OverlapSeconds) * time.Second
 	}
-}
-
-// markDeliveryFailed marks a delivery as permanently failed
-func (s *WebhookService) markDeliveryFailed(delivery *models.WebhookDelivery, reason string) {
-	delivery.Status = "failed"
-	delivery.FailureReason = &reason
-	delivery.NextAttemptAt = nil
-	delivery.UpdatedAt = time.Now()
-
-	err := s.db.Save(delivery).Error
-	if err != nil {
-		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to update delivery status")
+	if req.Secret == "" {
+		req.Secret = s.generateSecret()
 	}
-}
-
-// scheduleRetry schedules a retry for a failed delivery
-func (s *WebhookService) scheduleRetry(delivery *models.WebhookDelivery, reason string) {
-	if delivery.AttemptCount >= delivery.MaxAttempts {
-		s.markDeliveryFailed(delivery, reason)
-		return
+	if len(req.Secret) < minWebhookSecretLength {
+		return nil, fmt.Errorf("%w: must be at least %d characters", ErrInvalidWebhookSecret, minWebhookSecretLength)
 	}
 
-	// Exponential backoff: 1min, 2min, 4min, 8min, 16min
-	backoffMinutes := 1 << (delivery.AttemptCount - 1)
-	nextAttempt := time.Now().Add(time.Duration(backoffMinutes) * time.Minute)
-
-	delivery.Status = "retrying"
-	delivery.FailureReason = &reason
-	delivery.NextAttemptAt = &nextAttempt
-	delivery.UpdatedAt = time.Now()
+	var endpoint models.WebhookEndpoint
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("id = ?", id)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.First(&endpoint).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrWebhookEndpointNotFound
+			}
+			return fmt.Errorf("failed to find webhook endpoint: %w", err)
+		}
+		if req.Secret == endpoint.Secret {
+			return fmt.Errorf("%w: same as the current secret", ErrInvalidWebhookSecret)
+		}
 
-	err := s.db.Save(delivery).Error
+		// A secret rotated out during an earlier overlap stops signing now
+		now := time.Now()
+		endpoint.PreviousSecret = nil
+		endpoint.PreviousSecretUntil = nil
+		if overlap > 0 {
+			previous, until := endpoint.Secret, now.Add(overlap)
+			endpoint.PreviousSecret = &previous
+			endpoint.PreviousSecretUntil = &until
+		}
+		endpoint.Secret = req.Secret
+		endpoint.UpdatedAt = now
+		return tx.Model(&endpoint).Select("secret", "previous_secret", "previous_secret_until", "updated_at").Updates(&endpoint).Error
+	})
 	if err != nil {
-		s.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("Failed to schedule retry")
+		return nil, err
 	}
 
 	s.logger.WithFields(logrus.Fields{
-		"delivery_id":     delivery.ID,
-		"attempt_count":   delivery.AttemptCount,
-		"next_attempt_at": nextAttempt,
-		"reason":          reason,
-	}).Info("Webhook delivery scheduled for retry")
+		"endpoint_id":           endpoint.ID,
+		"previous_secret_until": endpoint.PreviousSecretUntil,
+	}).Info("Webhook endpoint secret rotated")
+	return &endpoint, nil
 }
 
-// retryFailedDeliveries retries failed webhook deliveries
-func (s *WebhookService) retryFailedDeliveries(ctx context.Context) error {
-	var deliveries []models.WebhookDelivery
-	err := s.db.WithContext(ctx).
-		Preload("Endpoint").
-		Where("status IN ('pending', 'retrying') AND next_attempt_at <= ? AND attempt_count < max_attempts", time.Now()).
-		Find(&deliveries).Error
-	
-	if err != nil {
-		return fmt.Errorf("failed to fetch deliveries for retry: %w", err)
-	}
-
-	if len(deliveries) == 0 {
-		return nil
+// signingSecrets returns the secrets endpoint's deliveries are signed with
+// at now: its secret, and the previous one during a rotation's overlap
+func signingSecrets(endpoint *models.WebhookEndpoint, now time.Time) []string {
+	secrets := []string{endpoint.Secret}
+	if endpoint.PreviousSecret != nil && endpoint.PreviousSecretUntil != nil && now.Before(*endpoint.PreviousSecretUntil) {
+		secrets = append(secrets, *endpoint.PreviousSecret)
 	}
+	return secrets
+}
 
-	s.logger.WithField("delivery_count", len(deliveries)).Info("Retrying webhook deliveries")
-
-	for _, delivery := range deliveries {
-		if delivery.Endpoint != nil {
-			go s.attemptDelivery(&delivery, delivery.Endpoint)
+// VerifyWebhook verifies a delivery received for an endpoint the way a
+// merchant would with webhooksig, rejecting it if it is older than
+// tolerance or has been verified before
+func (s *WebhookService) VerifyWebhook(ctx context.Context, endpointID uuid.UUID, payload []byte, signature string, tolerance time.Duration) (*webhooksig.Signature, error) {
+	var endpoint models.WebhookEndpoint
+	if err := s.db.WithContext(ctx).Where("id = ?", endpointID).First(&endpoint).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrWebhookEndpointNotFound
 		}
+		return nil, fmt.Errorf("failed to find webhook endpoint: %w", err)
 	}
 
-	return nil
-}
-
-// generateSignature generates HMAC-SHA256 signature for webhook payload
-func (s *WebhookService) generateSignature(payload []byte, secret string) string {
-	mac := hmac.New(sha256.New, []byte(secret))
-	mac.Write(payload)
-	return hex.EncodeToString(mac.Sum(nil))
+	verifier := &webhooksig.Verifier{
+		Secrets:   signingSecrets(&endpoint, time.Now()),
+		Tolerance: tolerance,
+		Nonces:    s.nonces,
+	}
+	sig, err := verifier.Verify(payload, signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidWebhookSignature, err)
+	}
+	return sig, nil
 }
 
 // generateSecret generates a random secret for webhook endpoints
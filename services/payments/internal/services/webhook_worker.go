@@ -0,0 +1,511 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/webhooksig"
+)
+
+// StartDeliveryWorkers delivers webhooks with a pool of workers until ctx
+// is done. New deliveries are queued as they are triggered; a dispatcher
+// queues retries as they come due, and deliveries whose worker died.
+func (s *WebhookService) StartDeliveryWorkers(ctx context.Context) {
+	s.logger.WithField("workers", s.policy.Workers).Info("Starting webhook delivery workers")
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.policy.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliveryWorker(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			s.logger.Info("Stopping webhook delivery workers")
+			return
+		case <-ticker.C:
+			s.enqueueDueDeliveries(ctx)
+		}
+	}
+}
+
+// deliveryWorker attempts the deliveries it takes off the queue
+func (s *WebhookService) deliveryWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			s.queuedMu.Lock()
+			delete(s.queued, id)
+			s.queuedMu.Unlock()
+
+			if err := s.deliver(ctx, id); err != nil {
+				s.logger.WithError(err).WithField("delivery_id", id).Error("Failed to deliver webhook")
+			}
+		}
+	}
+}
+
+// enqueue queues a delivery unless it is queued already. When the queue is
+// full the delivery is left to the dispatcher.
+func (s *WebhookService) enqueue(id uuid.UUID) bool {
+	s.queuedMu.Lock()
+	defer s.queuedMu.Unlock()
+	if _, ok := s.queued[id]; ok {
+		return true
+	}
+	select {
+	case s.queue <- id:
+		s.queued[id] = struct{}{}
+		return true
+	default:
+		return false
+	}
+}
+
+// enqueueDueDeliveries queues the deliveries due an attempt, to endpoints
+// that are active and not backing off, oldest first
+func (s *WebhookService) enqueueDueDeliveries(ctx context.Context) {
+	now := time.Now()
+	var ids []uuid.UUID
+	err := s.db.WithContext(ctx).
+		Table("webhook_deliveries").
+		Joins("JOIN webhook_endpoints ON webhook_endpoints.id = webhook_deliveries.endpoint_id").
+		Where("webhook_deliveries.status IN ? AND webhook_deliveries.next_attempt_at <= ?", []string{
+			models.WebhookDeliveryStatusPending,
+			models.WebhookDeliveryStatusRetrying,
+			models.WebhookDeliveryStatusDelivering,
+		}, now).
+		Where("webhook_endpoints.active = ? AND (webhook_endpoints.backoff_until IS NULL OR webhook_endpoints.backoff_until <= ?)", true, now).
+		Order("webhook_deliveries.next_attempt_at ASC").
+		Limit(cap(s.queue)).
+		Pluck("webhook_deliveries.id", &ids).Error
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get due webhook deliveries")
+		return
+	}
+
+	for _, id := range ids {
+		if !s.enqueue(id) {
+			return
+		}
+	}
+}
+
+// claimDelivery claims a due delivery for one attempt, leasing it until the
+// attempt has timed out; a delivery whose worker died is attempted again
+// after. Of concurrent workers only one claims it.
+func (s *WebhookService) claimDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	now := time.Now()
+	lease := now.Add(s.httpClient.Timeout + 30*time.Second)
+	result := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status IN ? AND next_attempt_at <= ?", id, []string{
+			models.WebhookDeliveryStatusPending,
+			models.WebhookDeliveryStatusRetrying,
+			models.WebhookDeliveryStatusDelivering,
+		}, now).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookDeliveryStatusDelivering,
+			"next_attempt_at": lease,
+			"updated_at":      now,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	var delivery models.WebhookDelivery
+	if err := s.db.WithContext(ctx).Preload("Endpoint").Where("id = ?", id).First(&delivery).Error; err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// deliver makes one attempt at a delivery, if it is still due
+func (s *WebhookService) deliver(ctx context.Context, id uuid.UUID) error {
+	delivery, err := s.claimDelivery(ctx, id)
+	if err != nil || delivery == nil {
+		return err
+	}
+	endpoint := delivery.Endpoint
+
+	switch {
+	case endpoint == nil:
+		return s.deadLetterDelivery(ctx, delivery, "webhook endpoint deleted")
+	case !endpoint.Active:
+		return s.deadLetterDelivery(ctx, delivery, "webhook endpoint disabled")
+	case endpoint.BackoffUntil != nil && endpoint.BackoffUntil.After(time.Now()):
+		// The endpoint is backing off; wait for it without using an attempt
+		delivery.Status = models.WebhookDeliveryStatusRetrying
+		delivery.NextAttemptAt = endpoint.BackoffUntil
+		return s.saveDelivery(ctx, delivery)
+	}
+
+	log := s.logger.WithFields(logrus.Fields{
+		"delivery_id":   delivery.ID,
+		"endpoint_id":   delivery.EndpointID,
+		"event_type":    delivery.EventType,
+		"attempt_count": delivery.AttemptCount + 1,
+		"url":           endpoint.URL,
+	})
+	log.Info("Attempting webhook delivery")
+
+	delivery.AttemptCount++
+	reason := s.post(ctx, delivery, endpoint)
+	if reason == "" {
+		log.WithField("status_code", *delivery.ResponseStatus).Info("Webhook delivered successfully")
+		now := time.Now()
+		delivery.Status = models.WebhookDeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.FailureReason = nil
+		if err := s.saveDelivery(ctx, delivery); err != nil {
+			return err
+		}
+		return s.endpointSucceeded(ctx, endpoint)
+	}
+
+	if ctx.Err() != nil {
+		// Shutting down; the lease runs out and the attempt is made again
+		return nil
+	}
+	log.WithField("reason", reason).Warn("Webhook delivery failed")
+	delivery.FailureReason = &reason
+	if endpoint, err = s.endpointFailed(ctx, endpoint.ID, reason); err != nil {
+		return err
+	}
+
+	switch {
+	case !endpoint.Active:
+		return s.deadLetterDelivery(ctx, delivery, "webhook endpoint disabled: "+reason)
+	case delivery.AttemptCount >= delivery.MaxAttempts:
+		return s.deadLetterDelivery(ctx, delivery, reason)
+	}
+
+	// Retry after the delivery's own backoff, or the endpoint's if later
+	nextAttempt := time.Now().Add(s.policy.backoff(delivery.AttemptCount))
+	if endpoint.BackoffUntil != nil && endpoint.BackoffUntil.After(nextAttempt) {
+		nextAttempt = *endpoint.BackoffUntil
+	}
+	delivery.Status = models.WebhookDeliveryStatusRetrying
+	delivery.NextAttemptAt = &nextAttempt
+	log.WithField("next_attempt_at", nextAttempt).Info("Webhook delivery scheduled for retry")
+	return s.saveDelivery(ctx, delivery)
+}
+
+// post sends delivery to endpoint, recording the response on it. It
+// returns why the attempt failed, or "" if the endpoint accepted it.
+func (s *WebhookService) post(ctx context.Context, delivery *models.WebhookDelivery, endpoint *models.WebhookEndpoint) string {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Sprintf("Failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	// Sign every attempt afresh, so its timestamp is within the merchant's
+	// tolerance however late the retry
+	now := time.Now()
+	delivery.Signature = webhooksig.Sign(delivery.Payload, now, webhooksig.NewNonce(), signingSecrets(endpoint, now)...)
+	req.Header.Set(webhooksig.Header, delivery.Signature)
+	req.Header.Set("X-Webhook-Event-Type", delivery.EventType)
+	req.Header.Set("X-Webhook-Event-ID", delivery.EventID.String())
+	req.Header.Set("X-Webhook-Delivery-ID", delivery.ID.String())
+	req.Header.Set("User-Agent", "Suuupra-Webhooks/1.0")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Sprintf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Keep up to 4KB of the response for debugging
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	responseBody := string(body)
+	delivery.ResponseStatus = &resp.StatusCode
+	delivery.ResponseBody = &responseBody
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Sprintf("HTTP %d: %s", resp.StatusCode, responseBody)
+	}
+	return ""
+}
+
+// saveDelivery saves delivery without its endpoint
+func (s *WebhookService) saveDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	if err := s.db.WithContext(ctx).Omit(clause.Associations).Save(delivery).Error; err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// deadLetterDelivery stops attempting delivery until it is redelivered
+func (s *WebhookService) deadLetterDelivery(ctx context.Context, delivery *models.WebhookDelivery, reason string) error {
+	now := time.Now()
+	delivery.Status = models.WebhookDeliveryStatusDeadLettered
+	delivery.FailureReason = &reason
+	delivery.NextAttemptAt = nil
+	delivery.DeadLetteredAt = &now
+
+	s.logger.WithFields(logrus.Fields{
+		"delivery_id":   delivery.ID,
+		"endpoint_id":   delivery.EndpointID,
+		"attempt_count": delivery.AttemptCount,
+		"reason":        reason,
+	}).Warn("Webhook delivery dead-lettered")
+	return s.saveDelivery(ctx, delivery)
+}
+
+// endpointSucceeded clears the failures of an endpoint that accepted a
+// delivery
+func (s *WebhookService) endpointSucceeded(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	if endpoint.ConsecutiveFailures == 0 && endpoint.BackoffUntil == nil {
+		return nil
+	}
+	err := s.db.WithContext(ctx).Model(&models.WebhookEndpoint{}).
+		Where("id = ?", endpoint.ID).
+		Updates(map[string]interface{}{
+			"consecutive_failures": 0,
+			"failing_since":        nil,
+			"backoff_until":        nil,
+			"updated_at":           time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reset webhook endpoint failures: %w", err)
+	}
+	return nil
+}
+
+// endpointFailed counts a failed delivery against an endpoint and backs it
+// off. An endpoint failing DisableAfterFailures times in a row, for at
+// least DisableAfter, is disabled and its outstanding deliveries are
+// dead-lettered. It returns the endpoint as updated.
+func (s *WebhookService) endpointFailed(ctx context.Context, endpointID uuid.UUID, reason string) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("id = ?", endpointID)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.First(&endpoint).Error; err != nil {
+			return fmt.Errorf("failed to get webhook endpoint: %w", err)
+		}
+
+		now := time.Now()
+		backoffUntil := now.Add(s.policy.backoff(endpoint.ConsecutiveFailures + 1))
+		endpoint.ConsecutiveFailures++
+		if endpoint.FailingSince == nil {
+			endpoint.FailingSince = &now
+		}
+		endpoint.BackoffUntil = &backoffUntil
+		updates := map[string]interface{}{
+			"consecutive_failures": endpoint.ConsecutiveFailures,
+			"failing_since":        endpoint.FailingSince,
+			"backoff_until":        endpoint.BackoffUntil,
+			"updated_at":           now,
+		}
+
+		disable := endpoint.Active &&
+			s.policy.DisableAfterFailures > 0 &&
+			endpoint.ConsecutiveFailures >= s.policy.DisableAfterFailures &&
+			now.Sub(*endpoint.FailingSince) >= s.policy.DisableAfter
+		if disable {
+			disabledReason := fmt.Sprintf("%d consecutive failures since %s, last: %s",
+				endpoint.ConsecutiveFailures, endpoint.FailingSince.Format(time.RFC3339), reason)
+			endpoint.Active = false
+			endpoint.DisabledAt = &now
+			endpoint.DisabledReason = &disabledReason
+			updates["active"] = false
+			updates["disabled_at"] = now
+			updates["disabled_reason"] = disabledReason
+		}
+
+		if err := tx.Model(&models.WebhookEndpoint{}).Where("id = ?", endpoint.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update webhook endpoint: %w", err)
+		}
+		if !disable {
+			return nil
+		}
+
+		s.logger.WithFields(logrus.Fields{
+			"endpoint_id":          endpoint.ID,
+			"merchant_id":          endpoint.MerchantID,
+			"consecutive_failures": endpoint.ConsecutiveFailures,
+		}).Warn("Webhook endpoint disabled after sustained failures")
+		err := tx.Model(&models.WebhookDelivery{}).
+			Where("endpoint_id = ? AND status IN ?", endpoint.ID, []string{
+				models.WebhookDeliveryStatusPending,
+				models.WebhookDeliveryStatusRetrying,
+			}).
+			Updates(map[string]interface{}{
+				"status":           models.WebhookDeliveryStatusDeadLettered,
+				"failure_reason":   "webhook endpoint disabled",
+				"next_attempt_at":  nil,
+				"dead_lettered_at": now,
+				"updated_at":       now,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to dead-letter webhook deliveries: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// backoff returns the delay after the nth consecutive failure: the base
+// doubled n-1 times, capped at the maximum, of which the second half is
+// random so that retries to an endpoint spread out
+func (p WebhookDeliveryPolicy) backoff(n int) time.Duration {
+	delay := p.BackoffMax
+	if n < 1 {
+		n = 1
+	}
+	if n <= 32 && p.BackoffBase<<(n-1) < p.BackoffMax {
+		delay = p.BackoffBase << (n - 1)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// ListWebhookDeliveries lists an endpoint's deliveries, newest first,
+// optionally only those in status
+func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, endpointID uuid.UUID, status string, limit int) ([]models.WebhookDelivery, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	var endpoint models.WebhookEndpoint
+	if err := s.db.WithContext(ctx).Where("id = ?", endpointID).First(&endpoint).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrWebhookEndpointNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	query := s.db.WithContext(ctx).Where("endpoint_id = ?", endpointID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("created_at DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RedeliverWebhook sends a delivery again with a fresh set of attempts,
+// whether it was dead-lettered, delivered or is waiting for a retry
+func (s *WebhookService) RedeliverWebhook(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := s.db.WithContext(ctx).Preload("Endpoint").Where("id = ?", id).First(&delivery).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery.Endpoint == nil || !delivery.Endpoint.Active {
+		return nil, ErrWebhookEndpointDisabled
+	}
+	if delivery.Status == models.WebhookDeliveryStatusDelivering {
+		return nil, ErrWebhookDeliveryInFlight
+	}
+
+	now := time.Now()
+	result := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ?", delivery.ID, delivery.Status).
+		Updates(map[string]interface{}{
+			"status":           models.WebhookDeliveryStatusPending,
+			"attempt_count":    0,
+			"next_attempt_at":  now,
+			"failure_reason":   nil,
+			"delivered_at":     nil,
+			"dead_lettered_at": nil,
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to redeliver webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrWebhookDeliveryInFlight
+	}
+
+	s.logger.WithField("delivery_id", delivery.ID).Info("Webhook redelivery requested")
+	s.enqueue(delivery.ID)
+
+	delivery.Endpoint = nil
+	delivery.Status = models.WebhookDeliveryStatusPending
+	delivery.AttemptCount = 0
+	delivery.NextAttemptAt = &now
+	delivery.FailureReason = nil
+	delivery.DeliveredAt = nil
+	delivery.DeadLetteredAt = nil
+	delivery.UpdatedAt = now
+	return &delivery, nil
+}
+
+// RedeliverDeadLetters sends all of an endpoint's dead-lettered deliveries
+// again, e.g. once it is reactivated, and returns how many there were
+func (s *WebhookService) RedeliverDeadLetters(ctx context.Context, endpointID uuid.UUID) (int64, error) {
+	var endpoint models.WebhookEndpoint
+	err := s.db.WithContext(ctx).Where("id = ?", endpointID).First(&endpoint).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, ErrWebhookEndpointNotFound
+		}
+		return 0, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+	if !endpoint.Active {
+		return 0, ErrWebhookEndpointDisabled
+	}
+
+	now := time.Now()
+	result := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("endpoint_id = ? AND status = ?", endpointID, models.WebhookDeliveryStatusDeadLettered).
+		Updates(map[string]interface{}{
+			"status":           models.WebhookDeliveryStatusPending,
+			"attempt_count":    0,
+			"next_attempt_at":  now,
+			"failure_reason":   nil,
+			"dead_lettered_at": nil,
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to redeliver webhooks: %w", result.Error)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"endpoint_id": endpointID,
+		"count":       result.RowsAffected,
+	}).Info("Dead-lettered webhook deliveries requeued")
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/pkg/webhooksig"
+)
+
+// webhookReceiver is a merchant's webhook endpoint answering with status
+type webhookReceiver struct {
+	*httptest.Server
+	status atomic.Int32
+
+	mu       sync.Mutex
+	received []*http.Request
+	bodies   [][]byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	r := &webhookReceiver{}
+	r.status.Store(http.StatusOK)
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.received = append(r.received, req)
+		r.bodies = append(r.bodies, body)
+		r.mu.Unlock()
+		w.WriteHeader(int(r.status.Load()))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *webhookReceiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.received)
+}
+
+func newWebhookTestService(t *testing.T, policy WebhookDeliveryPolicy) (*WebhookService, *gorm.DB) {
+	db := setupTestDB(t)
+	return NewWebhookService(db, testLogger(), "whsec_test", 3, 5, policy), db
+}
+
+// triggerOne triggers an event for endpoint's merchant and returns the one
+// delivery it created
+func triggerOne(t *testing.T, s *WebhookService, db *gorm.DB, endpoint *models.WebhookEndpoint) *models.WebhookDelivery {
+	eventID := uuid.New()
+	s.TriggerWebhook(context.Background(), endpoint.MerchantID, "payment.succeeded", map[string]string{"event": eventID.String()})
+
+	var deliveries []models.WebhookDelivery
+	require.NoError(t, db.Where("endpoint_id = ?", endpoint.ID).Order("created_at DESC").Find(&deliveries).Error)
+	require.NotEmpty(t, deliveries)
+	return &deliveries[0]
+}
+
+func storedDelivery(t *testing.T, db *gorm.DB, id uuid.UUID) *models.WebhookDelivery {
+	var delivery models.WebhookDelivery
+	require.NoError(t, db.Where("id = ?", id).First(&delivery).Error)
+	return &delivery
+}
+
+func storedEndpoint(t *testing.T, db *gorm.DB, id uuid.UUID) *models.WebhookEndpoint {
+	var endpoint models.WebhookEndpoint
+	require.NoError(t, db.Where("id = ?", id).First(&endpoint).Error)
+	return &endpoint
+}
+
+// makeDue lets a delivery's next attempt go ahead now
+func makeDue(t *testing.T, db *gorm.DB, delivery *models.WebhookDelivery) {
+	past := time.Now().Add(-time.Second)
+	require.NoError(t, db.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Update("next_attempt_at", past).Error)
+	require.NoError(t, db.Model(&models.WebhookEndpoint{}).Where("id = ?", delivery.EndpointID).Update("backoff_until", nil).Error)
+}
+
+func TestWebhookDelivery_SignedAndDelivered(t *testing.T) {
+	s, db := newWebhookTestService(t, WebhookDeliveryPolicy{})
+	receiver := newWebhookReceiver(t)
+	ctx := context.Background()
+
+	endpoint, err := s.CreateWebhookEndpoint(ctx, CreateWebhookEndpointRequest{
+		MerchantID: uuid.New(),
+		URL:        receiver.URL,
+		Events:     []string{"payment.succeeded"},
+		Secret:     "whsec_endpoint_secret",
+	})
+	require.NoError(t, err)
+
+	// Events the endpoint does not subscribe to are not delivered
+	s.TriggerWebhook(ctx, endpoint.MerchantID, "refund.created", map[string]string{})
+	var count int64
+	require.NoError(t, db.Model(&models.WebhookDelivery{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	delivery := triggerOne(t, s, db, endpoint)
+	assert.Equal(t, models.WebhookDeliveryStatusPending, delivery.Status)
+	require.NoError(t, s.deliver(ctx, delivery.ID))
+
+	require.Equal(t, 1, receiver.count())
+	req, body := receiver.received[0], receiver.bodies[0]
+	assert.Equal(t, "payment.succeeded", req.Header.Get("X-Webhook-Event-Type"))
+	assert.Equal(t, delivery.ID.String(), req.Header.Get("X-Webhook-Delivery-ID"))
+	_, err = webhooksig.Verify(body, req.Header.Get(webhooksig.Header), "whsec_endpoint_secret", webhooksig.DefaultTolerance)
+	assert.NoError(t, err, "delivery not signed with the endpoint's secret")
+
+	stored := storedDelivery(t, db, delivery.ID)
+	assert.Equal(t, models.WebhookDeliveryStatusDelivered, stored.Status)
+	assert.Equal(t, 1, stored.AttemptCount)
+	assert.NotNil(t, stored.DeliveredAt)
+	assert.Nil(t, stored.NextAttemptAt)
+
+	// A delivered delivery is not attempted again
+	require.NoError(t, s.deliver(ctx, delivery.ID))
+	assert.Equal(t, 1, receiver.count())
+}
+
+func TestWebhookDelivery_RetriesThenDeadLetters(t *testing.T) {
+	s, db := newWebhookTestService(t, WebhookDeliveryPolicy{BackoffBase: time.Minute, BackoffMax: time.Hour})
+	receiver := newWebhookReceiver(t)
+	receiver.status.Store(http.StatusInternalServerError)
+	ctx := context.Background()
+
+	endpoint, err := s.CreateWebhookEndpoint(ctx, CreateWebhookEndpointRequest{
+		MerchantID: uuid.New(), URL: receiver.URL, Events: []string{"*"},
+	})
+	require.NoError(t, err)
+	delivery := triggerOne(t, s, db, endpoint)
+
+	require.NoError(t, s.deliver(ctx, delivery.ID))
+	stored := storedDelivery(t, db, delivery.ID)
+	assert.Equal(t, models.WebhookDeliveryStatusRetrying, stored.Status)
+	assert.Equal(t, 1, stored.AttemptCount)
+	require.NotNil(t, stored.NextAttemptAt)
+	assert.True(t, stored.NextAttemptAt.After(time.Now().Add(29*time.Second)), "retry not backed off: %v", stored.NextAttemptAt)
+	require.NotNil(t, stored.FailureReason)
+	assert.Contains(t, *stored.FailureReason, "HTTP 500")
+
+	failing := storedEndpoint(t, db, endpoint.ID)
+	assert.Equal(t, 1, failing.ConsecutiveFailures)
+	assert.NotNil(t, failing.BackoffUntil)
+
+	// Not due yet
+	require.NoError(t, s.deliver(ctx, delivery.ID))
+	assert.Equal(t, 1, receiver.count())
+
+	for attempt := 2; attempt <= 3; attempt++ {
+		makeDue(t, db, delivery)
+		require.NoError(t, s.deliver(ctx, delivery.ID))
+	}
+	assert.Equal(t, 3, receiver.count())
+	stored = storedDelivery(t, db, delivery.ID)
+	assert.Equal(t, models.WebhookDeliveryStatusDeadLettered, stored.Status, "delivery not dead-lettered after its max attempts")
+	assert.NotNil(t, stored.DeadLetteredAt)
+
+	// Redelivered once the endpoint recovers, which clears its failures
+	receiver.status.Store(http.StatusNoContent)
+	require.NoError(t, db.Model(&models.WebhookEndpoint{}).Where("id = ?", endpoint.ID).Update("backoff_until", nil).Error)
+	redelivered, err := s.RedeliverWebhook(ctx, delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, redelivered.AttemptCount)
+	require.NoError(t, s.deliver(ctx, delivery.ID))
+	assert.Equal(t, models.WebhookDeliveryStatusDelivered, storedDelivery(t, db, delivery.ID).Status)
+	recovered := storedEndpoint(t, db, endpoint.ID)
+	assert.Zero(t, recovered.ConsecutiveFailures)
+	assert.Nil(t, recovered.BackoffUntil)
+}
+
+func TestWebhookDelivery_DisablesFailingEndpoint(t *testing.T) {
+	s, db := newWebhookTestService(t, WebhookDeliveryPolicy{
+		BackoffBase:          time.Minute,
+		BackoffMax:           time.Hour,
+		DisableAfterFailures: 2,
+	})
+	receiver := newWebhookReceiver(t)
+	receiver.status.Store(http.StatusBadGateway)
+	ctx := context.Background()
+
+	endpoint, err := s.CreateWebhookEndpoint(ctx, CreateWebhookEndpointRequest{
+		MerchantID: uuid.New(), URL: receiver.URL, Events: []string{"*"},
+	})
+	require.NoError(t, err)
+	first := triggerOne(t, s, db, endpoint)
+	waiting := triggerOne(t, s, db, endpoint)
+
+	require.NoError(t, s.deliver(ctx, first.ID))
+	assert.True(t, storedEndpoint(t, db, endpoint.ID).Active)
+
+	// The endpoint backs off, so the other delivery waits for it without
+	// using an attempt
+	require.NoError(t, s.deliver(ctx, waiting.ID))
+	assert.Equal(t, 1, receiver.count())
+	assert.Equal(t, 0, storedDelivery(t, db, waiting.ID).AttemptCount)
+
+	makeDue(t, db, first)
+	require.NoError(t, s.deliver(ctx, first.ID))
+
+	disabled := storedEndpoint(t, db, endpoint.ID)
+	assert.False(t, disabled.Active, "endpoint not disabled after sustained failures")
+	assert.NotNil(t, disabled.DisabledAt)
+	assert.Equal(t, models.WebhookDeliveryStatusDeadLettered, storedDelivery(t, db, first.ID).Status)
+	assert.Equal(t, models.WebhookDeliveryStatusDeadLettered, storedDelivery(t, db, waiting.ID).Status,
+		"outstanding delivery not dead-lettered with its endpoint")
+
+	_, err = s.RedeliverWebhook(ctx, first.ID)
+	assert.ErrorIs(t, err, ErrWebhookEndpointDisabled)
+}
+
+func TestWebhookDelivery_WorkersDeliverTriggeredEvents(t *testing.T) {
+	s, db := newWebhookTestService(t, WebhookDeliveryPolicy{Workers: 2})
+	receiver := newWebhookReceiver(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		s.StartDeliveryWorkers(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	endpoint, err := s.CreateWebhookEndpoint(ctx, CreateWebhookEndpointRequest{
+		MerchantID: uuid.New(), URL: receiver.URL, Events: []string{"payment.succeeded"},
+	})
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		s.TriggerWebhook(ctx, endpoint.MerchantID, "payment.succeeded", map[string]int{"n": i})
+	}
+
+	require.Eventually(t, func() bool {
+		var delivered int64
+		db.Model(&models.WebhookDelivery{}).Where("status = ?", models.WebhookDeliveryStatusDelivered).Count(&delivered)
+		return delivered == 5
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, 5, receiver.count(), "a delivery was attempted more than once")
+}
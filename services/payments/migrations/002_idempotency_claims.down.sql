@@ -0,0 +1,3 @@
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS response_headers;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS state;
@@ -0,0 +1,4 @@
+-- Idempotency keys are claimed IN_PROGRESS before their request is processed
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS state VARCHAR(20) NOT NULL DEFAULT 'COMPLETED';
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS response_headers BYTEA;
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE;
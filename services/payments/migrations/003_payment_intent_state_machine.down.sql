@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_payment_intent_transitions_payment_intent_id;
+DROP TABLE IF EXISTS payment_intent_transitions;
+
+UPDATE payment_intents SET status = 'created' WHERE status IN ('requires_payment_method', 'requires_confirmation');
+ALTER TABLE payment_intents ALTER COLUMN status SET DEFAULT 'created';
+ALTER TABLE payment_intents DROP COLUMN IF EXISTS version;
@@ -0,0 +1,19 @@
+-- Payment intents move through a state machine, versioned for optimistic locking
+ALTER TABLE payment_intents ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE payment_intents ALTER COLUMN status SET DEFAULT 'requires_payment_method';
+UPDATE payment_intents SET status = 'requires_confirmation' WHERE status = 'created';
+UPDATE payment_intents SET status = 'canceled' WHERE status = 'expired';
+
+-- Payment Intent Transitions table
+CREATE TABLE IF NOT EXISTS payment_intent_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_intent_id UUID NOT NULL REFERENCES payment_intents(id),
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    reason TEXT,
+    version BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (payment_intent_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_intent_transitions_payment_intent_id ON payment_intent_transitions(payment_intent_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_merchant_rails_merchant_rail;
+DROP TABLE IF EXISTS merchant_rails;
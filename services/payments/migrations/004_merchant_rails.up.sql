@@ -0,0 +1,11 @@
+-- Merchant Rails table: per-merchant overrides of the default payment rails
+CREATE TABLE IF NOT EXISTS merchant_rails (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    merchant_id UUID NOT NULL,
+    rail VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merchant_rails_merchant_rail ON merchant_rails(merchant_id, rail);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_payment_attempts_retry_at;
+DROP INDEX IF EXISTS idx_payment_attempts_payment_number;
+DROP TABLE IF EXISTS payment_attempts;
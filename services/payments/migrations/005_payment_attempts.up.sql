@@ -0,0 +1,24 @@
+-- Payment Attempts table: each attempt to send a payment over a rail,
+-- including retries and failovers
+CREATE TABLE IF NOT EXISTS payment_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id UUID NOT NULL REFERENCES payments(id),
+    attempt_number INTEGER NOT NULL,
+    rail VARCHAR(20) NOT NULL,
+    rail_reference UUID NOT NULL,
+    rail_transaction_id VARCHAR(255),
+    status VARCHAR(20) NOT NULL,
+    failure_category VARCHAR(50),
+    failure_code TEXT,
+    failure_message TEXT,
+    retry_at TIMESTAMP WITH TIME ZONE,
+    payer_vpa VARCHAR(255),
+    payee_vpa VARCHAR(255),
+    card_token VARCHAR(255),
+    bank_code VARCHAR(20),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_attempts_payment_number ON payment_attempts(payment_id, attempt_number);
+CREATE INDEX IF NOT EXISTS idx_payment_attempts_retry_at ON payment_attempts(retry_at) WHERE retry_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_refunds_status_updated_at;
+ALTER TABLE refunds DROP COLUMN IF EXISTS submitted_at;
@@ -0,0 +1,5 @@
+-- Refunds are sent to the rail by the refund worker; submitted_at is set
+-- once the rail accepted one
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_refunds_status_updated_at ON refunds(status, updated_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_ledger_entries_account_created_at;
+DROP INDEX IF EXISTS idx_journal_entries_reference;
+DROP TABLE IF EXISTS journal_entries;
+DROP INDEX IF EXISTS idx_ledger_accounts_merchant_id;
+DROP INDEX IF EXISTS idx_ledger_accounts_code_currency;
+DROP TABLE IF EXISTS ledger_accounts;
@@ -0,0 +1,28 @@
+-- Ledger Accounts table: platform clearing and fee accounts, and an
+-- account per merchant, with balances kept in step with their postings
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(100) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    merchant_id UUID,
+    currency VARCHAR(3) NOT NULL,
+    balance DECIMAL(20,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_accounts_code_currency ON ledger_accounts(code, currency);
+CREATE INDEX IF NOT EXISTS idx_ledger_accounts_merchant_id ON ledger_accounts(merchant_id);
+
+-- Journal Entries table: the balanced ledger entries of one event, each
+-- event journaled once
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id UUID PRIMARY KEY,
+    description TEXT,
+    reference_type VARCHAR(50) NOT NULL,
+    reference_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_reference ON journal_entries(reference_type, reference_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account_created_at ON ledger_entries(account_id, created_at);
@@ -0,0 +1,11 @@
+UPDATE risk_assessments SET decision = 'CHALLENGE' WHERE decision = 'REVIEW';
+UPDATE risk_assessments SET decision = 'PASS' WHERE decision = 'ALLOW';
+
+DROP INDEX IF EXISTS idx_payments_risk_decision;
+ALTER TABLE payments DROP COLUMN IF EXISTS risk_assessment_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS risk_decision;
+ALTER TABLE payments DROP COLUMN IF EXISTS risk_score;
+
+DROP INDEX IF EXISTS idx_risk_rules_merchant_id;
+DROP INDEX IF EXISTS idx_risk_rules_name;
+DROP TABLE IF EXISTS risk_rules;
@@ -0,0 +1,28 @@
+-- Risk Rules table: the risk engine's rules, expressions over the features
+-- of a payment with the weight and action of a match
+CREATE TABLE IF NOT EXISTS risk_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    expression TEXT NOT NULL,
+    weight DECIMAL(5,4) NOT NULL DEFAULT 0,
+    action VARCHAR(20) NOT NULL DEFAULT 'score',
+    merchant_id UUID,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_rules_name ON risk_rules(name);
+CREATE INDEX IF NOT EXISTS idx_risk_rules_merchant_id ON risk_rules(merchant_id);
+
+-- The risk outcome of each payment
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS risk_score DECIMAL(5,4);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS risk_decision VARCHAR(20);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS risk_assessment_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_payments_risk_decision ON payments(risk_decision);
+
+-- Decisions are now allow, review or block
+UPDATE risk_assessments SET decision = 'ALLOW' WHERE decision = 'PASS';
+UPDATE risk_assessments SET decision = 'REVIEW' WHERE decision = 'CHALLENGE';
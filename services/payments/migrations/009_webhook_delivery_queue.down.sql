@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+
+UPDATE webhook_deliveries SET status = 'failed' WHERE status = 'dead_lettered';
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS dead_lettered_at;
+
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS disabled_reason;
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS disabled_at;
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS backoff_until;
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS failing_since;
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS consecutive_failures;
@@ -0,0 +1,13 @@
+-- Endpoint health: consecutive failures back off deliveries to the
+-- endpoint, and sustained failures disable it
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS consecutive_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS failing_since TIMESTAMP WITH TIME ZONE;
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS backoff_until TIMESTAMP WITH TIME ZONE;
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS disabled_reason TEXT;
+
+-- Deliveries out of attempts are dead-lettered until redelivered
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMP WITH TIME ZONE;
+UPDATE webhook_deliveries SET status = 'dead_lettered', dead_lettered_at = updated_at WHERE status = 'failed';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
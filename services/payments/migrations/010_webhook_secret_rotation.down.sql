@@ -0,0 +1,2 @@
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS previous_secret_until;
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS previous_secret;
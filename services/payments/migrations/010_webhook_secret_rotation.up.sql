@@ -0,0 +1,4 @@
+-- While an endpoint's secret is rotated, deliveries are signed with the
+-- previous secret too, until previous_secret_until
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS previous_secret VARCHAR(255);
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS previous_secret_until TIMESTAMP WITH TIME ZONE;
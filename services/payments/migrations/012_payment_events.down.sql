@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS payment_events_immutable ON payment_events;
+DROP FUNCTION IF EXISTS payment_events_immutable();
+DROP TABLE IF EXISTS payment_events;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS version;
@@ -0,0 +1,27 @@
+-- Every change to a payment is recorded as an event, numbered by the
+-- payment's version. Payments made before this have no events.
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS payment_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id UUID NOT NULL REFERENCES payments(id),
+    sequence BIGINT NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    data JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_events_sequence ON payment_events(payment_id, sequence);
+CREATE INDEX IF NOT EXISTS idx_payment_events_event_type ON payment_events(event_type);
+
+-- Events are immutable
+CREATE OR REPLACE FUNCTION payment_events_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'payment events are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS payment_events_immutable ON payment_events;
+CREATE TRIGGER payment_events_immutable
+    BEFORE UPDATE OR DELETE ON payment_events
+    FOR EACH ROW EXECUTE FUNCTION payment_events_immutable();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS reconciliation_exceptions;
+
+DROP INDEX IF EXISTS idx_payments_settlement_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS settlement_id;
@@ -0,0 +1,30 @@
+-- Payments are marked settled as UPI Core's settlement reports are
+-- reconciled against them
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS settlement_id VARCHAR(100);
+CREATE INDEX IF NOT EXISTS idx_payments_settlement_id ON payments(settlement_id);
+
+-- Report records that do not match a captured payment wait here for an
+-- operator
+CREATE TABLE IF NOT EXISTS reconciliation_exceptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bank_code VARCHAR(20) NOT NULL,
+    rrn VARCHAR(50) NOT NULL,
+    settlement_id VARCHAR(100) NOT NULL,
+    transaction_id VARCHAR(100),
+    amount DECIMAL(20,2) NOT NULL,
+    fee DECIMAL(20,2) NOT NULL DEFAULT 0,
+    payment_id UUID REFERENCES payments(id),
+    reason VARCHAR(50) NOT NULL,
+    details TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    resolution VARCHAR(20),
+    resolution_note TEXT,
+    resolved_by VARCHAR(255),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reconciliation_exceptions_record ON reconciliation_exceptions(rrn, settlement_id);
+CREATE INDEX IF NOT EXISTS idx_reconciliation_exceptions_status ON reconciliation_exceptions(status);
+CREATE INDEX IF NOT EXISTS idx_reconciliation_exceptions_payment_id ON reconciliation_exceptions(payment_id);
@@ -0,0 +1,3 @@
+ALTER TABLE payment_attempts DROP COLUMN IF EXISTS payment_method_token;
+
+DROP TABLE IF EXISTS payment_methods;
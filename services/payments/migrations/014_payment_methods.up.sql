@@ -0,0 +1,27 @@
+-- Customers' saved cards and VPAs. The instrument is encrypted under a
+-- data key of its own, which is stored encrypted with the vault key.
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token VARCHAR(40) NOT NULL,
+    merchant_id UUID NOT NULL,
+    customer_id UUID NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    brand VARCHAR(20),
+    last4 VARCHAR(4),
+    exp_month INTEGER,
+    exp_year INTEGER,
+    vpa VARCHAR(255),
+    fingerprint VARCHAR(64) NOT NULL,
+    encrypted_data BYTEA NOT NULL,
+    encrypted_key BYTEA NOT NULL,
+    key_id VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_methods_token ON payment_methods(token);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_methods_fingerprint ON payment_methods(merchant_id, customer_id, fingerprint);
+
+-- Attempts paid with a saved method record its token instead of the
+-- instrument
+ALTER TABLE payment_attempts ADD COLUMN IF NOT EXISTS payment_method_token VARCHAR(40);
@@ -0,0 +1,9 @@
+-- Merchants' own API budgets, per minute, overriding the defaults. A NULL
+-- budget is the default.
+CREATE TABLE IF NOT EXISTS merchant_rate_limits (
+    merchant_id UUID PRIMARY KEY,
+    reads_per_minute INTEGER CHECK (reads_per_minute > 0),
+    writes_per_minute INTEGER CHECK (writes_per_minute > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,24 @@
+-- Payments queued to be processed asynchronously, published to Kafka
+-- through the outbox
+CREATE TABLE IF NOT EXISTS payment_commands (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_intent_id UUID NOT NULL REFERENCES payment_intents(id),
+    merchant_id UUID NOT NULL,
+    request JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    payment_id UUID REFERENCES payments(id),
+    error TEXT,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_commands_payment_intent_id ON payment_commands(payment_intent_id);
+CREATE INDEX IF NOT EXISTS idx_payment_commands_merchant_id ON payment_commands(merchant_id);
+CREATE INDEX IF NOT EXISTS idx_payment_commands_status ON payment_commands(status);
+
+-- An intent has at most one command waiting or running
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_commands_active ON payment_commands(payment_intent_id)
+    WHERE status IN ('queued', 'processing');
@@ -0,0 +1,21 @@
+-- Merchants' exports of payments, refunds and fees, generated in the
+-- background and downloaded once ready
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    merchant_id UUID NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    date_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    date_to TIMESTAMP WITH TIME ZONE NOT NULL,
+    filter_status VARCHAR(50),
+    status VARCHAR(20) NOT NULL,
+    row_count INTEGER NOT NULL DEFAULT 0,
+    content BYTEA,
+    error TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_merchant_id ON reports(merchant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status);
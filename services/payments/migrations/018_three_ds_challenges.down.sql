@@ -0,0 +1 @@
+DROP TABLE IF EXISTS three_ds_challenges;
@@ -0,0 +1,26 @@
+-- Step-up authentications, e.g. 3-D Secure, payments are waiting on
+CREATE TABLE IF NOT EXISTS three_ds_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id UUID NOT NULL REFERENCES payments(id),
+    payment_intent_id UUID NOT NULL REFERENCES payment_intents(id),
+    rail VARCHAR(20) NOT NULL,
+    rail_transaction_id VARCHAR(255),
+    redirect_url TEXT NOT NULL,
+    return_url TEXT,
+    reason VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    failure_message TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_three_ds_challenges_payment_id ON three_ds_challenges(payment_id);
+CREATE INDEX IF NOT EXISTS idx_three_ds_challenges_payment_intent_id ON three_ds_challenges(payment_intent_id);
+CREATE INDEX IF NOT EXISTS idx_three_ds_challenges_status ON three_ds_challenges(status);
+CREATE INDEX IF NOT EXISTS idx_three_ds_challenges_expires_at ON three_ds_challenges(expires_at);
+
+-- A payment waits on one challenge at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_three_ds_challenges_pending ON three_ds_challenges(payment_id)
+    WHERE status = 'pending';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS upi_dead_letters;
@@ -0,0 +1,27 @@
+-- Payments' calls to UPI Core that failed because it could not be reached,
+-- replayed once it recovers
+CREATE TABLE IF NOT EXISTS upi_dead_letters (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id UUID NOT NULL REFERENCES payments(id),
+    payment_intent_id UUID NOT NULL REFERENCES payment_intents(id),
+    merchant_id UUID NOT NULL,
+    attempt_id UUID NOT NULL REFERENCES payment_attempts(id),
+    request JSONB,
+    failure_code VARCHAR(100),
+    failure_message TEXT,
+    status VARCHAR(20) NOT NULL,
+    replays INTEGER NOT NULL DEFAULT 0,
+    last_replayed_at TIMESTAMP WITH TIME ZONE,
+    resolved_by VARCHAR(255),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_upi_dead_letters_payment_id ON upi_dead_letters(payment_id);
+CREATE INDEX IF NOT EXISTS idx_upi_dead_letters_merchant_id ON upi_dead_letters(merchant_id);
+CREATE INDEX IF NOT EXISTS idx_upi_dead_letters_status ON upi_dead_letters(status, created_at);
+
+-- A payment has one dead letter waiting at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_upi_dead_letters_open ON upi_dead_letters(payment_id)
+    WHERE status IN ('pending', 'replaying');
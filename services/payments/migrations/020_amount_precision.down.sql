@@ -0,0 +1,6 @@
+ALTER TABLE ledger_accounts ALTER COLUMN balance TYPE DECIMAL(20,2);
+ALTER TABLE ledger_entries ALTER COLUMN credit_amount TYPE DECIMAL(20,2);
+ALTER TABLE ledger_entries ALTER COLUMN debit_amount TYPE DECIMAL(20,2);
+ALTER TABLE refunds ALTER COLUMN amount TYPE DECIMAL(20,2);
+ALTER TABLE payments ALTER COLUMN amount TYPE DECIMAL(20,2);
+ALTER TABLE payment_intents ALTER COLUMN amount TYPE DECIMAL(20,2);
@@ -0,0 +1,8 @@
+-- Amounts are kept to the exponent of their currency, up to three decimal
+-- places for dinars (BHD, KWD, OMR)
+ALTER TABLE payment_intents ALTER COLUMN amount TYPE DECIMAL(20,3);
+ALTER TABLE payments ALTER COLUMN amount TYPE DECIMAL(20,3);
+ALTER TABLE refunds ALTER COLUMN amount TYPE DECIMAL(20,3);
+ALTER TABLE ledger_entries ALTER COLUMN debit_amount TYPE DECIMAL(20,3);
+ALTER TABLE ledger_entries ALTER COLUMN credit_amount TYPE DECIMAL(20,3);
+ALTER TABLE ledger_accounts ALTER COLUMN balance TYPE DECIMAL(20,3);
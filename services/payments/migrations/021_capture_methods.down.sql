@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_payments_authorization_expires_at;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS captured_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS authorization_expires_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS authorized_amount;
+
+ALTER TABLE payment_intents DROP COLUMN IF EXISTS capture_method;
@@ -0,0 +1,10 @@
+-- Payment intents are captured automatically, or authorized for the
+-- merchant to capture later
+ALTER TABLE payment_intents ADD COLUMN IF NOT EXISTS capture_method VARCHAR(20) NOT NULL DEFAULT 'automatic';
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS authorized_amount DECIMAL(20,3);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS authorization_expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS captured_at TIMESTAMP WITH TIME ZONE;
+
+-- The expiry worker looks for authorizations past their window
+CREATE INDEX IF NOT EXISTS idx_payments_authorization_expires_at ON payments(authorization_expires_at) WHERE status = 'authorized';
@@ -0,0 +1,3 @@
+ALTER TABLE reconciliation_exceptions ALTER COLUMN fee TYPE DECIMAL(20,2);
+ALTER TABLE reconciliation_exceptions ALTER COLUMN amount TYPE DECIMAL(20,2);
+ALTER TABLE reconciliation_exceptions DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,5 @@
+-- Settlement records are in the currency of the payment they settle, up to
+-- three decimal places
+ALTER TABLE reconciliation_exceptions ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'INR';
+ALTER TABLE reconciliation_exceptions ALTER COLUMN amount TYPE DECIMAL(20,3);
+ALTER TABLE reconciliation_exceptions ALTER COLUMN fee TYPE DECIMAL(20,3);
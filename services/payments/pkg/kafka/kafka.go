@@ -0,0 +1,77 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Message is a message read from a queue, committed once handled
+type Message struct {
+	Key   []byte
+	Value []byte
+
+	raw kafka.Message
+}
+
+// Queue publishes to and consumes a topic as a member of a consumer group.
+// Messages with the same key go to the same partition, so they are consumed
+// in order.
+type Queue struct {
+	writer *kafka.Writer
+	reader *kafka.Reader
+}
+
+// NewQueue creates a queue over topic on brokers, consumed by group
+func NewQueue(brokers []string, topic, group string) (*Queue, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("no Kafka brokers configured")
+	}
+	return &Queue{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:        brokers,
+			Topic:          topic,
+			GroupID:        group,
+			MaxWait:        time.Second,
+			CommitInterval: 0, // Commits are synchronous
+		}),
+	}, nil
+}
+
+// Publish writes a message to the topic
+func (q *Queue) Publish(ctx context.Context, key string, value []byte) error {
+	return q.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(key),
+		Value: value,
+		Time:  time.Now(),
+	})
+}
+
+// Fetch blocks until the next message of the group's partitions, or ctx
+// is done
+func (q *Queue) Fetch(ctx context.Context) (Message, error) {
+	msg, err := q.reader.FetchMessage(ctx)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Key: msg.Key, Value: msg.Value, raw: msg}, nil
+}
+
+// Commit marks msg as handled, so the group does not read it again
+func (q *Queue) Commit(ctx context.Context, msg Message) error {
+	return q.reader.CommitMessages(ctx, msg.raw)
+}
+
+// Close closes the queue's writer and reader
+func (q *Queue) Close() error {
+	return errors.Join(q.writer.Close(), q.reader.Close())
+}
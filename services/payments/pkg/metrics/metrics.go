@@ -126,6 +126,15 @@ var (
 		},
 		[]string{"account_id", "account_type", "currency"},
 	)
+
+	// Rate limiting metrics
+	RateLimitedRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limited_requests_total",
+			Help: "Total number of requests throttled by the rate limiter",
+		},
+		[]string{"scope", "budget"},
+	)
 )
 
 // InitMetrics initializes and registers all metrics
@@ -145,6 +154,7 @@ func InitMetrics() {
 		IdempotencyMissesTotal,
 		LedgerEntriesTotal,
 		LedgerBalance,
+		RateLimitedRequestsTotal,
 	)
 }
 
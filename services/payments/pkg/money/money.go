@@ -0,0 +1,214 @@
+// Package money represents amounts as whole minor units of their currency,
+// so a rupee amount is never read as paisa or a yen amount as hundredths.
+// How many decimal places a currency has is its ISO 4217 exponent: 2 for
+// INR, 0 for JPY, 3 for BHD.
+//
+// A Money marshals to JSON as
+//
+//	{"amount":"10.50","currency":"INR","minor_units":1050}
+//
+// with the amount written to its currency's exponent.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// INR is the currency amounts are in when none is given
+const INR = "INR"
+
+var (
+	// ErrUnknownCurrency is returned for currencies without a known exponent
+	ErrUnknownCurrency = errors.New("unknown currency")
+
+	// ErrPrecision is returned for amounts finer than their currency's
+	// minor unit
+	ErrPrecision = errors.New("finer than the currency's minor unit")
+
+	// ErrOverflow is returned for amounts with more minor units than fit in
+	// an int64
+	ErrOverflow = errors.New("too large")
+
+	// ErrCurrencyMismatch is returned for arithmetic on amounts in
+	// different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// exponents are the ISO 4217 minor unit exponents of the currencies
+// payments can be made in
+var exponents = map[string]int32{
+	"AED": 2, "AUD": 2, "BHD": 3, "CAD": 2, "CHF": 2, "CNY": 2, "EUR": 2,
+	"GBP": 2, "HKD": 2, "IDR": 2, "INR": 2, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "LKR": 2, "MYR": 2, "NPR": 2, "NZD": 2, "OMR": 3, "QAR": 2,
+	"SAR": 2, "SGD": 2, "THB": 2, "TND": 3, "USD": 2, "VND": 0, "ZAR": 2,
+}
+
+// Exponent returns how many decimal places currency has
+func Exponent(currency string) (int32, error) {
+	exponent, ok := exponents[strings.ToUpper(currency)]
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+	return exponent, nil
+}
+
+// Money is an amount in whole minor units of its currency
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// New returns amount, in major units of currency, as Money. Amounts finer
+// than the currency's minor unit are rejected rather than rounded.
+func New(amount decimal.Decimal, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	exponent, err := Exponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	minor := amount.Shift(exponent)
+	if !minor.IsInteger() {
+		return Money{}, fmt.Errorf("%s %s is %w", amount, currency, ErrPrecision)
+	}
+	if minor.GreaterThan(decimal.NewFromInt(math.MaxInt64)) || minor.LessThan(decimal.NewFromInt(math.MinInt64)) {
+		return Money{}, fmt.Errorf("%s %s is %w", amount, currency, ErrOverflow)
+	}
+	return Money{Minor: minor.IntPart(), Currency: currency}, nil
+}
+
+// FromMinor returns minor units of currency as Money
+func FromMinor(minor int64, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if _, err := Exponent(currency); err != nil {
+		return Money{}, err
+	}
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// Decimal returns m in major units
+func (m Money) Decimal() decimal.Decimal {
+	exponent, _ := Exponent(m.Currency)
+	return decimal.New(m.Minor, -exponent)
+}
+
+// String returns m in major units to its currency's exponent, e.g. 10.50
+func (m Money) String() string {
+	exponent, _ := Exponent(m.Currency)
+	return m.Decimal().StringFixed(exponent)
+}
+
+// IsPositive reports whether m is more than zero
+func (m Money) IsPositive() bool {
+	return m.Minor > 0
+}
+
+// Add returns m plus o, which must be in the same currency
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	if (o.Minor > 0 && m.Minor > math.MaxInt64-o.Minor) || (o.Minor < 0 && m.Minor < math.MinInt64-o.Minor) {
+		return Money{}, fmt.Errorf("%s + %s %s is %w", m, o, m.Currency, ErrOverflow)
+	}
+	return Money{Minor: m.Minor + o.Minor, Currency: m.Currency}, nil
+}
+
+// Sub returns m minus o, which must be in the same currency
+func (m Money) Sub(o Money) (Money, error) {
+	if o.Minor == math.MinInt64 {
+		return Money{}, fmt.Errorf("%s - %s %s is %w", m, o, m.Currency, ErrOverflow)
+	}
+	return m.Add(Money{Minor: -o.Minor, Currency: o.Currency})
+}
+
+// Cmp compares m with o, which must be in the same currency: -1 if m is
+// less, 0 if they are equal and 1 if m is more
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	switch {
+	case m.Minor < o.Minor:
+		return -1, nil
+	case m.Minor > o.Minor:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Format returns amount, in major units of currency, to the currency's
+// exponent. Amounts of unknown currencies keep two decimal places.
+func Format(amount decimal.Decimal, currency string) string {
+	exponent, err := Exponent(currency)
+	if err != nil {
+		exponent = 2
+	}
+	return amount.StringFixed(exponent)
+}
+
+// Round returns amount, in major units of currency, rounded to its minor
+// unit. Amounts of unknown currencies are rounded to two decimal places.
+func Round(amount decimal.Decimal, currency string) decimal.Decimal {
+	exponent, err := Exponent(currency)
+	if err != nil {
+		exponent = 2
+	}
+	return amount.Round(exponent)
+}
+
+// moneyJSON is how Money is written to and read from JSON
+type moneyJSON struct {
+	Amount     *decimal.Decimal `json:"amount"`
+	Currency   string           `json:"currency"`
+	MinorUnits *int64           `json:"minor_units"`
+}
+
+// MarshalJSON writes m with its amount in major units and minor units
+func (m Money) MarshalJSON() ([]byte, error) {
+	exponent, err := Exponent(m.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Amount     string `json:"amount"`
+		Currency   string `json:"currency"`
+		MinorUnits int64  `json:"minor_units"`
+	}{m.Decimal().StringFixed(exponent), m.Currency, m.Minor})
+}
+
+// UnmarshalJSON reads Money given by its amount in major units, its minor
+// units, or both if they agree. The currency defaults to INR.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Currency == "" {
+		v.Currency = INR
+	}
+
+	var parsed Money
+	var err error
+	switch {
+	case v.Amount != nil:
+		parsed, err = New(*v.Amount, v.Currency)
+		if err == nil && v.MinorUnits != nil && *v.MinorUnits != parsed.Minor {
+			err = fmt.Errorf("amount %s is not %d minor units of %s", v.Amount, *v.MinorUnits, parsed.Currency)
+		}
+	case v.MinorUnits != nil:
+		parsed, err = FromMinor(*v.MinorUnits, v.Currency)
+	default:
+		err = errors.New("amount or minor_units is required")
+	}
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
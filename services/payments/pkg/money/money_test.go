@@ -0,0 +1,168 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_MinorUnits(t *testing.T) {
+	for _, tc := range []struct {
+		amount, currency string
+		minor            int64
+		formatted        string
+	}{
+		{"1000", "JPY", 1000, "1000"},
+		{"10.5", "INR", 1050, "10.50"},
+		{"0.01", "usd", 1, "0.01"},
+		{"1.25", "BHD", 1250, "1.250"},
+		{"12.345", "KWD", 12345, "12.345"},
+		{"-3.5", "INR", -350, "-3.50"},
+		{"0", "KWD", 0, "0.000"},
+	} {
+		m, err := New(decimal.RequireFromString(tc.amount), tc.currency)
+		require.NoError(t, err, "%s %s", tc.amount, tc.currency)
+		assert.Equal(t, tc.minor, m.Minor, "%s %s", tc.amount, tc.currency)
+		assert.Equal(t, tc.formatted, m.String(), "%s %s", tc.amount, tc.currency)
+		assert.True(t, m.Decimal().Equal(decimal.RequireFromString(tc.amount)), "%s %s", tc.amount, tc.currency)
+	}
+}
+
+func TestNew_Rejects(t *testing.T) {
+	for _, tc := range []struct {
+		amount, currency string
+		want             error
+	}{
+		// Finer than the minor unit
+		{"1000.5", "JPY", ErrPrecision},
+		{"10.505", "INR", ErrPrecision},
+		{"1.2345", "BHD", ErrPrecision},
+		{"92233720368547758.08", "INR", ErrOverflow},
+		{"10", "XYZ", ErrUnknownCurrency},
+		{"10", "", ErrUnknownCurrency},
+	} {
+		_, err := New(decimal.RequireFromString(tc.amount), tc.currency)
+		assert.ErrorIs(t, err, tc.want, "%s %s", tc.amount, tc.currency)
+	}
+
+	_, err := FromMinor(100, "XYZ")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestFormatAndRound(t *testing.T) {
+	for _, tc := range []struct {
+		amount, currency, formatted, rounded string
+	}{
+		{"10.5", "INR", "10.50", "10.5"},
+		{"10.005", "INR", "10.01", "10.01"},
+		{"10.004", "INR", "10.00", "10"},
+		{"999.5", "JPY", "1000", "1000"},
+		{"1.2345", "BHD", "1.235", "1.235"},
+		{"-1.005", "INR", "-1.01", "-1.01"}, // Half away from zero
+		// Unknown currencies keep two decimal places
+		{"1.2345", "XYZ", "1.23", "1.23"},
+	} {
+		amount := decimal.RequireFromString(tc.amount)
+		assert.Equal(t, tc.formatted, Format(amount, tc.currency), "%s %s", tc.amount, tc.currency)
+		rounded := Round(amount, tc.currency)
+		assert.True(t, rounded.Equal(decimal.RequireFromString(tc.rounded)), "%s %s rounded to %s", tc.amount, tc.currency, rounded)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	inr := func(minor int64) Money { return Money{Minor: minor, Currency: INR} }
+
+	sum, err := inr(1050).Add(inr(25))
+	require.NoError(t, err)
+	assert.Equal(t, inr(1075), sum)
+	diff, err := inr(1050).Sub(inr(2000))
+	require.NoError(t, err)
+	assert.Equal(t, inr(-950), diff)
+	assert.False(t, diff.IsPositive())
+
+	for _, tc := range []struct {
+		m, o Money
+		want int
+	}{
+		{inr(1), inr(2), -1},
+		{inr(2), inr(2), 0},
+		{inr(3), inr(2), 1},
+	} {
+		cmp, err := tc.m.Cmp(tc.o)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, cmp, "%s against %s", tc.m, tc.o)
+	}
+
+	_, err = inr(math.MaxInt64).Add(inr(1))
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = inr(0).Sub(inr(math.MinInt64))
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestArithmetic_CurrencyMismatch(t *testing.T) {
+	// 10.50 INR and 10.500 KWD are not the same amount, nor can they be summed
+	rupees := Money{Minor: 1050, Currency: INR}
+	dinars := Money{Minor: 10500, Currency: "KWD"}
+
+	_, err := rupees.Add(dinars)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = rupees.Sub(dinars)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = rupees.Cmp(dinars)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestJSON_RoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		m    Money
+		json string
+	}{
+		{Money{Minor: 1000, Currency: "JPY"}, `{"amount":"1000","currency":"JPY","minor_units":1000}`},
+		{Money{Minor: 1050, Currency: "INR"}, `{"amount":"10.50","currency":"INR","minor_units":1050}`},
+		{Money{Minor: 1250, Currency: "BHD"}, `{"amount":"1.250","currency":"BHD","minor_units":1250}`},
+		{Money{Minor: 0, Currency: "KWD"}, `{"amount":"0.000","currency":"KWD","minor_units":0}`},
+	} {
+		data, err := json.Marshal(tc.m)
+		require.NoError(t, err)
+		assert.JSONEq(t, tc.json, string(data))
+
+		var parsed Money
+		require.NoError(t, json.Unmarshal(data, &parsed))
+		assert.Equal(t, tc.m, parsed)
+	}
+
+	_, err := json.Marshal(Money{Minor: 1, Currency: "XYZ"})
+	assert.Error(t, err)
+}
+
+func TestJSON_Unmarshal(t *testing.T) {
+	for _, tc := range []struct {
+		json string
+		want Money
+	}{
+		{`{"amount":"10.5"}`, Money{Minor: 1050, Currency: INR}}, // INR by default
+		{`{"amount":10.5,"currency":"inr"}`, Money{Minor: 1050, Currency: INR}},
+		{`{"minor_units":1250,"currency":"BHD"}`, Money{Minor: 1250, Currency: "BHD"}},
+		{`{"amount":"1.25","currency":"BHD","minor_units":1250}`, Money{Minor: 1250, Currency: "BHD"}},
+	} {
+		var m Money
+		require.NoError(t, json.Unmarshal([]byte(tc.json), &m), tc.json)
+		assert.Equal(t, tc.want, m, tc.json)
+	}
+
+	for _, data := range []string{
+		`{}`,
+		`{"currency":"INR"}`,
+		`{"amount":"1000.5","currency":"JPY"}`,
+		`{"amount":"1.25","currency":"BHD","minor_units":125}`, // Disagree
+		`{"minor_units":100,"currency":"XYZ"}`,
+		`{"amount":"ten"}`,
+	} {
+		var m Money
+		assert.Error(t, json.Unmarshal([]byte(data), &m), data)
+	}
+}
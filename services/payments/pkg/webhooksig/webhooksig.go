@@ -0,0 +1,221 @@
+// Package webhooksig signs webhook deliveries and verifies them. Merchants
+// can import it to check that a delivery came from us and is not a replay.
+//
+// A signature header looks like
+//
+//	t=1718000000,n=4f1c2b...,v1=9a8b7c...,v1=1d2e3f...
+//
+// where t is the Unix time it was signed at, n a random nonce, and each v1
+// the hex HMAC-SHA256 of "<t>.<n>.<payload>" with one of the endpoint's
+// secrets. While a secret is being rotated, deliveries carry a v1 for both
+// the old and the new one.
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Header is the HTTP header deliveries carry their signature in
+const Header = "X-Webhook-Signature"
+
+// DefaultTolerance is how old a delivery Verify accepts by default
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidHeader is returned for a signature header that does not
+	// parse
+	ErrInvalidHeader = errors.New("invalid webhook signature header")
+	// ErrTimestampOutOfTolerance is returned for a delivery signed longer
+	// ago, or further in the future, than the tolerance
+	ErrTimestampOutOfTolerance = errors.New("webhook signature timestamp outside tolerance")
+	// ErrSignatureMismatch is returned when no v1 matches any secret
+	ErrSignatureMismatch = errors.New("webhook signature does not match")
+	// ErrReplayed is returned for a nonce that has already been verified
+	ErrReplayed = errors.New("webhook delivery replayed")
+)
+
+// Signature is a parsed signature header
+type Signature struct {
+	Timestamp  time.Time
+	Nonce      string
+	Signatures []string
+}
+
+// Sign signs payload at timestamp with nonce, once with each secret, and
+// returns the header value
+func Sign(payload []byte, timestamp time.Time, nonce string, secrets ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "t=%d,n=%s", timestamp.Unix(), nonce)
+	for _, secret := range secrets {
+		b.WriteString(",v1=")
+		b.WriteString(compute(payload, timestamp.Unix(), nonce, secret))
+	}
+	return b.String()
+}
+
+// NewNonce returns a random nonce
+func NewNonce() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(fmt.Sprintf("webhooksig: reading random nonce: %v", err))
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Parse parses a signature header
+func Parse(header string) (*Signature, error) {
+	sig := &Signature{}
+	var haveTimestamp bool
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidHeader, part)
+		}
+		switch key {
+		case "t":
+			unix, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || haveTimestamp {
+				return nil, fmt.Errorf("%w: bad timestamp", ErrInvalidHeader)
+			}
+			sig.Timestamp = time.Unix(unix, 0)
+			haveTimestamp = true
+		case "n":
+			if sig.Nonce != "" {
+				return nil, fmt.Errorf("%w: more than one nonce", ErrInvalidHeader)
+			}
+			sig.Nonce = value
+		case "v1":
+			sig.Signatures = append(sig.Signatures, value)
+		}
+		// Other keys are later schemes, ignored so they can be added
+	}
+	if !haveTimestamp || sig.Nonce == "" || len(sig.Signatures) == 0 {
+		return nil, fmt.Errorf("%w: needs t, n and v1", ErrInvalidHeader)
+	}
+	return sig, nil
+}
+
+// NonceStore remembers nonces that have been verified. Nonces only need
+// keeping until their delivery falls outside the tolerance.
+type NonceStore interface {
+	// Seen records nonce until expires, and reports whether it was already
+	// recorded
+	Seen(nonce string, expires time.Time) bool
+}
+
+// Verifier verifies deliveries to one endpoint
+type Verifier struct {
+	// Secrets accepted, e.g. the old and new one while rotating
+	Secrets []string
+	// Tolerance is how far the signing time may be from now; zero means
+	// DefaultTolerance
+	Tolerance time.Duration
+	// Nonces rejects deliveries seen before, if set. Without it, a
+	// captured delivery can be replayed within the tolerance.
+	Nonces NonceStore
+	// Now returns the current time; nil means time.Now
+	Now func() time.Time
+}
+
+// Verify checks header is a valid signature of payload with one of the
+// secrets, made within the tolerance, and not replayed
+func (v *Verifier) Verify(payload []byte, header string) (*Signature, error) {
+	sig, err := Parse(header)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	age := now().Sub(sig.Timestamp)
+	if age > tolerance || age < -tolerance {
+		return nil, fmt.Errorf("%w: signed %s ago", ErrTimestampOutOfTolerance, age.Round(time.Second))
+	}
+
+	if !v.matches(payload, sig) {
+		return nil, ErrSignatureMismatch
+	}
+	// Only remember nonces of authentic deliveries, so forged ones cannot
+	// fill the store
+	if v.Nonces != nil && v.Nonces.Seen(sig.Nonce, sig.Timestamp.Add(tolerance)) {
+		return nil, ErrReplayed
+	}
+	return sig, nil
+}
+
+func (v *Verifier) matches(payload []byte, sig *Signature) bool {
+	for _, secret := range v.Secrets {
+		expected := compute(payload, sig.Timestamp.Unix(), sig.Nonce, secret)
+		for _, candidate := range sig.Signatures {
+			if hmac.Equal([]byte(expected), []byte(candidate)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Verify verifies header against payload with secret, rejecting deliveries
+// older than tolerance. It does not detect replays within the tolerance;
+// use a Verifier with a NonceStore for that.
+func Verify(payload []byte, header, secret string, tolerance time.Duration) (*Signature, error) {
+	v := &Verifier{Secrets: []string{secret}, Tolerance: tolerance}
+	return v.Verify(payload, header)
+}
+
+func compute(payload []byte, unix int64, nonce, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s.", unix, nonce)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// MemoryNonceStore is a NonceStore for a single process
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	nonces    map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryNonceStore creates an empty MemoryNonceStore
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: make(map[string]time.Time), now: time.Now}
+}
+
+// Seen implements NonceStore, dropping expired nonces every minute
+func (s *MemoryNonceStore) Seen(nonce string, expires time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if until, ok := s.nonces[nonce]; ok && until.After(now) {
+		return true
+	}
+	if now.Sub(s.lastSweep) >= time.Minute {
+		for n, until := range s.nonces {
+			if !until.After(now) {
+				delete(s.nonces, n)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.nonces[nonce] = expires
+	return false
+}
@@ -0,0 +1,152 @@
+package webhooksig
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var payload = []byte(`{"type":"payment.succeeded","data":{"id":"pay_123"}}`)
+
+func TestSignVerify_RoundTrip(t *testing.T) {
+	now := time.Now()
+	nonce := NewNonce()
+	header := Sign(payload, now, nonce, "whsec_a")
+
+	sig, err := Verify(payload, header, "whsec_a", DefaultTolerance)
+	require.NoError(t, err)
+	assert.Equal(t, now.Unix(), sig.Timestamp.Unix())
+	assert.Equal(t, nonce, sig.Nonce)
+	assert.Len(t, sig.Signatures, 1)
+
+	_, err = Verify(payload, header, "whsec_b", DefaultTolerance)
+	assert.ErrorIs(t, err, ErrSignatureMismatch, "verified with another secret")
+	assert.NotEqual(t, nonce, NewNonce())
+}
+
+func TestVerify_ModifiedDelivery(t *testing.T) {
+	now := time.Now()
+	header := Sign(payload, now, "nonce1", "whsec_a")
+
+	modified := []byte(strings.Replace(string(payload), "pay_123", "pay_456", 1))
+	_, err := Verify(modified, header, "whsec_a", DefaultTolerance)
+	assert.ErrorIs(t, err, ErrSignatureMismatch, "modified body verified")
+
+	// The timestamp and nonce are signed too
+	for _, tampered := range []string{
+		strings.Replace(header, "n=nonce1", "n=nonce2", 1),
+		strings.Replace(header, "t="+strconv.FormatInt(now.Unix(), 10), "t="+strconv.FormatInt(now.Unix()-1, 10), 1),
+	} {
+		_, err = Verify(payload, tampered, "whsec_a", DefaultTolerance)
+		assert.ErrorIs(t, err, ErrSignatureMismatch, "tampered header %q verified", tampered)
+	}
+}
+
+func TestVerify_Tolerance(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		name     string
+		signedAt time.Time
+		ok       bool
+	}{
+		{"within tolerance", now.Add(-4 * time.Minute), true},
+		{"too old", now.Add(-6 * time.Minute), false},
+		{"too far in the future", now.Add(6 * time.Minute), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &Verifier{Secrets: []string{"whsec_a"}, Now: func() time.Time { return now }}
+			_, err := v.Verify(payload, Sign(payload, tc.signedAt, NewNonce(), "whsec_a"))
+			if tc.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrTimestampOutOfTolerance)
+			}
+		})
+	}
+
+	// A shorter tolerance is honoured
+	_, err := Verify(payload, Sign(payload, now.Add(-2*time.Minute), NewNonce(), "whsec_a"), "whsec_a", time.Minute)
+	assert.ErrorIs(t, err, ErrTimestampOutOfTolerance)
+}
+
+func TestVerifier_RejectsReplays(t *testing.T) {
+	v := &Verifier{Secrets: []string{"whsec_a"}, Nonces: NewMemoryNonceStore()}
+	header := Sign(payload, time.Now(), NewNonce(), "whsec_a")
+
+	_, err := v.Verify(payload, header)
+	require.NoError(t, err)
+	_, err = v.Verify(payload, header)
+	assert.ErrorIs(t, err, ErrReplayed)
+
+	// A forged delivery does not use up its nonce
+	nonce := NewNonce()
+	_, err = v.Verify(payload, Sign(payload, time.Now(), nonce, "whsec_forged"))
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+	_, err = v.Verify(payload, Sign(payload, time.Now(), nonce, "whsec_a"))
+	assert.NoError(t, err)
+}
+
+func TestMemoryNonceStore_ForgetsExpiredNonces(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryNonceStore()
+	store.now = func() time.Time { return now }
+
+	assert.False(t, store.Seen("n1", now.Add(time.Minute)))
+	assert.True(t, store.Seen("n1", now.Add(time.Minute)))
+
+	now = now.Add(2 * time.Minute)
+	assert.False(t, store.Seen("n1", now.Add(time.Minute)), "expired nonce still remembered")
+	store.Seen("n2", now.Add(time.Minute))
+	now = now.Add(2 * time.Minute)
+	store.Seen("n3", now.Add(time.Minute))
+	assert.NotContains(t, store.nonces, "n2", "expired nonce not swept")
+}
+
+func TestParse_MalformedHeaders(t *testing.T) {
+	for _, header := range []string{
+		"",
+		"garbage",
+		"t=1718000000,n=abc",
+		"t=1718000000,v1=abc",
+		"n=abc,v1=abc",
+		"t=soon,n=abc,v1=abc",
+		"t=1718000000,t=1718000001,n=abc,v1=abc",
+		"t=1718000000,n=abc,n=def,v1=abc",
+		"t=1718000000,n=abc,v1=",
+	} {
+		_, err := Parse(header)
+		assert.ErrorIs(t, err, ErrInvalidHeader, "header %q", header)
+		_, err = Verify(payload, header, "whsec_a", DefaultTolerance)
+		assert.ErrorIs(t, err, ErrInvalidHeader, "header %q", header)
+	}
+
+	// Schemes added later are ignored
+	sig, err := Parse("t=1718000000,n=abc,v1=def,v2=ghi")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"def"}, sig.Signatures)
+}
+
+func TestVerify_SecretRotation(t *testing.T) {
+	now := time.Now()
+	// Deliveries are signed with both secrets while rotating
+	header := Sign(payload, now, NewNonce(), "whsec_old", "whsec_new")
+	sig, err := Parse(header)
+	require.NoError(t, err)
+	assert.Len(t, sig.Signatures, 2)
+
+	for _, secret := range []string{"whsec_old", "whsec_new"} {
+		_, err := Verify(payload, header, secret, DefaultTolerance)
+		assert.NoError(t, err, "merchant still on %s rejected the delivery", secret)
+	}
+
+	// A merchant accepting both verifies deliveries signed with either
+	v := &Verifier{Secrets: []string{"whsec_old", "whsec_new"}}
+	_, err = v.Verify(payload, Sign(payload, now, NewNonce(), "whsec_new"))
+	assert.NoError(t, err)
+	_, err = v.Verify(payload, Sign(payload, now, NewNonce(), "whsec_retired"))
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+}
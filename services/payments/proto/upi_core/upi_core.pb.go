@@ -1,8 +1,11 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.7
+// 	protoc-gen-go v1.31.0
 // 	protoc        v6.32.0
-// source: proto/upi_core.proto
+// source: upi_core.proto
+
+// Copied from services/upi-core/proto/upi_core.proto by
+// scripts/generate-proto.sh. DO NOT EDIT; change it in UPI Core.
 
 package upi_core
 
@@ -12,7 +15,6 @@ import (
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
-	unsafe "unsafe"
 )
 
 const (
@@ -22,14 +24,14 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Enums
+// Supporting Types
 type TransactionType int32
 
 const (
 	TransactionType_TRANSACTION_TYPE_UNSPECIFIED TransactionType = 0
-	TransactionType_TRANSACTION_TYPE_P2P         TransactionType = 1
-	TransactionType_TRANSACTION_TYPE_P2M         TransactionType = 2
-	TransactionType_TRANSACTION_TYPE_M2P         TransactionType = 3
+	TransactionType_TRANSACTION_TYPE_P2P         TransactionType = 1 // Person to Person
+	TransactionType_TRANSACTION_TYPE_P2M         TransactionType = 2 // Person to Merchant
+	TransactionType_TRANSACTION_TYPE_M2P         TransactionType = 3 // Merchant to Person
 	TransactionType_TRANSACTION_TYPE_REFUND      TransactionType = 4
 )
 
@@ -62,11 +64,11 @@ func (x TransactionType) String() string {
 }
 
 func (TransactionType) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_upi_core_proto_enumTypes[0].Descriptor()
+	return file_upi_core_proto_enumTypes[0].Descriptor()
 }
 
 func (TransactionType) Type() protoreflect.EnumType {
-	return &file_proto_upi_core_proto_enumTypes[0]
+	return &file_upi_core_proto_enumTypes[0]
 }
 
 func (x TransactionType) Number() protoreflect.EnumNumber {
@@ -75,7 +77,7 @@ func (x TransactionType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use TransactionType.Descriptor instead.
 func (TransactionType) EnumDescriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{0}
+	return file_upi_core_proto_rawDescGZIP(), []int{0}
 }
 
 type TransactionStatus int32
@@ -123,11 +125,11 @@ func (x TransactionStatus) String() string {
 }
 
 func (TransactionStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_upi_core_proto_enumTypes[1].Descriptor()
+	return file_upi_core_proto_enumTypes[1].Descriptor()
 }
 
 func (TransactionStatus) Type() protoreflect.EnumType {
-	return &file_proto_upi_core_proto_enumTypes[1]
+	return &file_upi_core_proto_enumTypes[1]
 }
 
 func (x TransactionStatus) Number() protoreflect.EnumNumber {
@@ -136,31 +138,424 @@ func (x TransactionStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use TransactionStatus.Descriptor instead.
 func (TransactionStatus) EnumDescriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{1}
+	return file_upi_core_proto_rawDescGZIP(), []int{1}
+}
+
+type MandateFrequency int32
+
+const (
+	MandateFrequency_MANDATE_FREQUENCY_UNSPECIFIED MandateFrequency = 0
+	MandateFrequency_MANDATE_FREQUENCY_ONE_TIME    MandateFrequency = 1
+	MandateFrequency_MANDATE_FREQUENCY_DAILY       MandateFrequency = 2
+	MandateFrequency_MANDATE_FREQUENCY_WEEKLY      MandateFrequency = 3
+	MandateFrequency_MANDATE_FREQUENCY_MONTHLY     MandateFrequency = 4
+	MandateFrequency_MANDATE_FREQUENCY_QUARTERLY   MandateFrequency = 5
+	MandateFrequency_MANDATE_FREQUENCY_YEARLY      MandateFrequency = 6
+)
+
+// Enum value maps for MandateFrequency.
+var (
+	MandateFrequency_name = map[int32]string{
+		0: "MANDATE_FREQUENCY_UNSPECIFIED",
+		1: "MANDATE_FREQUENCY_ONE_TIME",
+		2: "MANDATE_FREQUENCY_DAILY",
+		3: "MANDATE_FREQUENCY_WEEKLY",
+		4: "MANDATE_FREQUENCY_MONTHLY",
+		5: "MANDATE_FREQUENCY_QUARTERLY",
+		6: "MANDATE_FREQUENCY_YEARLY",
+	}
+	MandateFrequency_value = map[string]int32{
+		"MANDATE_FREQUENCY_UNSPECIFIED": 0,
+		"MANDATE_FREQUENCY_ONE_TIME":    1,
+		"MANDATE_FREQUENCY_DAILY":       2,
+		"MANDATE_FREQUENCY_WEEKLY":      3,
+		"MANDATE_FREQUENCY_MONTHLY":     4,
+		"MANDATE_FREQUENCY_QUARTERLY":   5,
+		"MANDATE_FREQUENCY_YEARLY":      6,
+	}
+)
+
+func (x MandateFrequency) Enum() *MandateFrequency {
+	p := new(MandateFrequency)
+	*p = x
+	return p
+}
+
+func (x MandateFrequency) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MandateFrequency) Descriptor() protoreflect.EnumDescriptor {
+	return file_upi_core_proto_enumTypes[2].Descriptor()
+}
+
+func (MandateFrequency) Type() protoreflect.EnumType {
+	return &file_upi_core_proto_enumTypes[2]
+}
+
+func (x MandateFrequency) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MandateFrequency.Descriptor instead.
+func (MandateFrequency) EnumDescriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{2}
+}
+
+type MandateStatus int32
+
+const (
+	MandateStatus_MANDATE_STATUS_UNSPECIFIED MandateStatus = 0
+	MandateStatus_MANDATE_STATUS_ACTIVE      MandateStatus = 1
+	MandateStatus_MANDATE_STATUS_REVOKED     MandateStatus = 2
+	MandateStatus_MANDATE_STATUS_COMPLETED   MandateStatus = 3
+)
+
+// Enum value maps for MandateStatus.
+var (
+	MandateStatus_name = map[int32]string{
+		0: "MANDATE_STATUS_UNSPECIFIED",
+		1: "MANDATE_STATUS_ACTIVE",
+		2: "MANDATE_STATUS_REVOKED",
+		3: "MANDATE_STATUS_COMPLETED",
+	}
+	MandateStatus_value = map[string]int32{
+		"MANDATE_STATUS_UNSPECIFIED": 0,
+		"MANDATE_STATUS_ACTIVE":      1,
+		"MANDATE_STATUS_REVOKED":     2,
+		"MANDATE_STATUS_COMPLETED":   3,
+	}
+)
+
+func (x MandateStatus) Enum() *MandateStatus {
+	p := new(MandateStatus)
+	*p = x
+	return p
+}
+
+func (x MandateStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MandateStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_upi_core_proto_enumTypes[3].Descriptor()
+}
+
+func (MandateStatus) Type() protoreflect.EnumType {
+	return &file_upi_core_proto_enumTypes[3]
+}
+
+func (x MandateStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MandateStatus.Descriptor instead.
+func (MandateStatus) EnumDescriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{3}
+}
+
+type CollectStatus int32
+
+const (
+	CollectStatus_COLLECT_STATUS_UNSPECIFIED CollectStatus = 0
+	CollectStatus_COLLECT_STATUS_PENDING     CollectStatus = 1
+	CollectStatus_COLLECT_STATUS_APPROVED    CollectStatus = 2
+	CollectStatus_COLLECT_STATUS_DECLINED    CollectStatus = 3
+	CollectStatus_COLLECT_STATUS_FAILED      CollectStatus = 4
+	CollectStatus_COLLECT_STATUS_EXPIRED     CollectStatus = 5
+)
+
+// Enum value maps for CollectStatus.
+var (
+	CollectStatus_name = map[int32]string{
+		0: "COLLECT_STATUS_UNSPECIFIED",
+		1: "COLLECT_STATUS_PENDING",
+		2: "COLLECT_STATUS_APPROVED",
+		3: "COLLECT_STATUS_DECLINED",
+		4: "COLLECT_STATUS_FAILED",
+		5: "COLLECT_STATUS_EXPIRED",
+	}
+	CollectStatus_value = map[string]int32{
+		"COLLECT_STATUS_UNSPECIFIED": 0,
+		"COLLECT_STATUS_PENDING":     1,
+		"COLLECT_STATUS_APPROVED":    2,
+		"COLLECT_STATUS_DECLINED":    3,
+		"COLLECT_STATUS_FAILED":      4,
+		"COLLECT_STATUS_EXPIRED":     5,
+	}
+)
+
+func (x CollectStatus) Enum() *CollectStatus {
+	p := new(CollectStatus)
+	*p = x
+	return p
+}
+
+func (x CollectStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CollectStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_upi_core_proto_enumTypes[4].Descriptor()
+}
+
+func (CollectStatus) Type() protoreflect.EnumType {
+	return &file_upi_core_proto_enumTypes[4]
+}
+
+func (x CollectStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CollectStatus.Descriptor instead.
+func (CollectStatus) EnumDescriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{4}
+}
+
+type FeeType int32
+
+const (
+	FeeType_FEE_TYPE_UNSPECIFIED FeeType = 0
+	FeeType_FEE_TYPE_SWITCH      FeeType = 1
+	FeeType_FEE_TYPE_BANK        FeeType = 2
+)
+
+// Enum value maps for FeeType.
+var (
+	FeeType_name = map[int32]string{
+		0: "FEE_TYPE_UNSPECIFIED",
+		1: "FEE_TYPE_SWITCH",
+		2: "FEE_TYPE_BANK",
+	}
+	FeeType_value = map[string]int32{
+		"FEE_TYPE_UNSPECIFIED": 0,
+		"FEE_TYPE_SWITCH":      1,
+		"FEE_TYPE_BANK":        2,
+	}
+)
+
+func (x FeeType) Enum() *FeeType {
+	p := new(FeeType)
+	*p = x
+	return p
+}
+
+func (x FeeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FeeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_upi_core_proto_enumTypes[5].Descriptor()
+}
+
+func (FeeType) Type() protoreflect.EnumType {
+	return &file_upi_core_proto_enumTypes[5]
+}
+
+func (x FeeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FeeType.Descriptor instead.
+func (FeeType) EnumDescriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{5}
+}
+
+type BankStatus int32
+
+const (
+	BankStatus_BANK_STATUS_UNSPECIFIED      BankStatus = 0
+	BankStatus_BANK_STATUS_ACTIVE           BankStatus = 1
+	BankStatus_BANK_STATUS_INACTIVE         BankStatus = 2
+	BankStatus_BANK_STATUS_MAINTENANCE      BankStatus = 3
+	BankStatus_BANK_STATUS_SUSPENDED        BankStatus = 4
+	BankStatus_BANK_STATUS_PENDING_APPROVAL BankStatus = 5 // Registered, awaiting another operator's approval
+	BankStatus_BANK_STATUS_REJECTED         BankStatus = 6
+)
+
+// Enum value maps for BankStatus.
+var (
+	BankStatus_name = map[int32]string{
+		0: "BANK_STATUS_UNSPECIFIED",
+		1: "BANK_STATUS_ACTIVE",
+		2: "BANK_STATUS_INACTIVE",
+		3: "BANK_STATUS_MAINTENANCE",
+		4: "BANK_STATUS_SUSPENDED",
+		5: "BANK_STATUS_PENDING_APPROVAL",
+		6: "BANK_STATUS_REJECTED",
+	}
+	BankStatus_value = map[string]int32{
+		"BANK_STATUS_UNSPECIFIED":      0,
+		"BANK_STATUS_ACTIVE":           1,
+		"BANK_STATUS_INACTIVE":         2,
+		"BANK_STATUS_MAINTENANCE":      3,
+		"BANK_STATUS_SUSPENDED":        4,
+		"BANK_STATUS_PENDING_APPROVAL": 5,
+		"BANK_STATUS_REJECTED":         6,
+	}
+)
+
+func (x BankStatus) Enum() *BankStatus {
+	p := new(BankStatus)
+	*p = x
+	return p
+}
+
+func (x BankStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BankStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_upi_core_proto_enumTypes[6].Descriptor()
+}
+
+func (BankStatus) Type() protoreflect.EnumType {
+	return &file_upi_core_proto_enumTypes[6]
+}
+
+func (x BankStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BankStatus.Descriptor instead.
+func (BankStatus) EnumDescriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{6}
+}
+
+type SettlementStatus int32
+
+const (
+	SettlementStatus_SETTLEMENT_STATUS_UNSPECIFIED SettlementStatus = 0
+	SettlementStatus_SETTLEMENT_STATUS_PENDING     SettlementStatus = 1
+	SettlementStatus_SETTLEMENT_STATUS_PROCESSING  SettlementStatus = 2
+	SettlementStatus_SETTLEMENT_STATUS_COMPLETED   SettlementStatus = 3
+	SettlementStatus_SETTLEMENT_STATUS_FAILED      SettlementStatus = 4
+)
+
+// Enum value maps for SettlementStatus.
+var (
+	SettlementStatus_name = map[int32]string{
+		0: "SETTLEMENT_STATUS_UNSPECIFIED",
+		1: "SETTLEMENT_STATUS_PENDING",
+		2: "SETTLEMENT_STATUS_PROCESSING",
+		3: "SETTLEMENT_STATUS_COMPLETED",
+		4: "SETTLEMENT_STATUS_FAILED",
+	}
+	SettlementStatus_value = map[string]int32{
+		"SETTLEMENT_STATUS_UNSPECIFIED": 0,
+		"SETTLEMENT_STATUS_PENDING":     1,
+		"SETTLEMENT_STATUS_PROCESSING":  2,
+		"SETTLEMENT_STATUS_COMPLETED":   3,
+		"SETTLEMENT_STATUS_FAILED":      4,
+	}
+)
+
+func (x SettlementStatus) Enum() *SettlementStatus {
+	p := new(SettlementStatus)
+	*p = x
+	return p
+}
+
+func (x SettlementStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SettlementStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_upi_core_proto_enumTypes[7].Descriptor()
+}
+
+func (SettlementStatus) Type() protoreflect.EnumType {
+	return &file_upi_core_proto_enumTypes[7]
+}
+
+func (x SettlementStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SettlementStatus.Descriptor instead.
+func (SettlementStatus) EnumDescriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{7}
+}
+
+type HealthStatus int32
+
+const (
+	HealthStatus_HEALTH_STATUS_UNSPECIFIED HealthStatus = 0
+	HealthStatus_HEALTH_STATUS_SERVING     HealthStatus = 1
+	HealthStatus_HEALTH_STATUS_NOT_SERVING HealthStatus = 2
+	HealthStatus_HEALTH_STATUS_UNKNOWN     HealthStatus = 3
+)
+
+// Enum value maps for HealthStatus.
+var (
+	HealthStatus_name = map[int32]string{
+		0: "HEALTH_STATUS_UNSPECIFIED",
+		1: "HEALTH_STATUS_SERVING",
+		2: "HEALTH_STATUS_NOT_SERVING",
+		3: "HEALTH_STATUS_UNKNOWN",
+	}
+	HealthStatus_value = map[string]int32{
+		"HEALTH_STATUS_UNSPECIFIED": 0,
+		"HEALTH_STATUS_SERVING":     1,
+		"HEALTH_STATUS_NOT_SERVING": 2,
+		"HEALTH_STATUS_UNKNOWN":     3,
+	}
+)
+
+func (x HealthStatus) Enum() *HealthStatus {
+	p := new(HealthStatus)
+	*p = x
+	return p
+}
+
+func (x HealthStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HealthStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_upi_core_proto_enumTypes[8].Descriptor()
+}
+
+func (HealthStatus) Type() protoreflect.EnumType {
+	return &file_upi_core_proto_enumTypes[8]
+}
+
+func (x HealthStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
 }
 
-// Transaction Processing Messages
+// Deprecated: Use HealthStatus.Descriptor instead.
+func (HealthStatus) EnumDescriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{8}
+}
+
+// Transaction Messages
 type TransactionRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	TransactionId    string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	PayerVpa         string                 `protobuf:"bytes,2,opt,name=payer_vpa,json=payerVpa,proto3" json:"payer_vpa,omitempty"`
-	PayeeVpa         string                 `protobuf:"bytes,3,opt,name=payee_vpa,json=payeeVpa,proto3" json:"payee_vpa,omitempty"`
-	AmountPaisa      int64                  `protobuf:"varint,4,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`
-	Type             TransactionType        `protobuf:"varint,5,opt,name=type,proto3,enum=upi.core.TransactionType" json:"type,omitempty"`
-	Reference        string                 `protobuf:"bytes,6,opt,name=reference,proto3" json:"reference,omitempty"`
-	PayerBankCode    string                 `protobuf:"bytes,7,opt,name=payer_bank_code,json=payerBankCode,proto3" json:"payer_bank_code,omitempty"`
-	PayeeBankCode    string                 `protobuf:"bytes,8,opt,name=payee_bank_code,json=payeeBankCode,proto3" json:"payee_bank_code,omitempty"`
-	DigitalSignature string                 `protobuf:"bytes,9,opt,name=digital_signature,json=digitalSignature,proto3" json:"digital_signature,omitempty"`
-	InitiatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=initiated_at,json=initiatedAt,proto3" json:"initiated_at,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Rrn           string                 `protobuf:"bytes,2,opt,name=rrn,proto3" json:"rrn,omitempty"` // Retrieval Reference Number
+	PayerVpa      string                 `protobuf:"bytes,3,opt,name=payer_vpa,json=payerVpa,proto3" json:"payer_vpa,omitempty"`
+	PayeeVpa      string                 `protobuf:"bytes,4,opt,name=payee_vpa,json=payeeVpa,proto3" json:"payee_vpa,omitempty"`
+	AmountPaisa   int64                  `protobuf:"varint,5,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`
+	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"` // ISO 4217 code; amount_paisa is in its minor unit. Default: INR
+	Type          TransactionType        `protobuf:"varint,7,opt,name=type,proto3,enum=upi_core.TransactionType" json:"type,omitempty"`
+	Description   string                 `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	Reference     string                 `protobuf:"bytes,9,opt,name=reference,proto3" json:"reference,omitempty"`
+	Signature     string                 `protobuf:"bytes,10,opt,name=signature,proto3" json:"signature,omitempty"` // Digital signature
+	InitiatedAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=initiated_at,json=initiatedAt,proto3" json:"initiated_at,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *TransactionRequest) Reset() {
 	*x = TransactionRequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[0]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *TransactionRequest) String() string {
@@ -170,8 +565,8 @@ func (x *TransactionRequest) String() string {
 func (*TransactionRequest) ProtoMessage() {}
 
 func (x *TransactionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[0]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -183,7 +578,7 @@ func (x *TransactionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransactionRequest.ProtoReflect.Descriptor instead.
 func (*TransactionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{0}
+	return file_upi_core_proto_rawDescGZIP(), []int{0}
 }
 
 func (x *TransactionRequest) GetTransactionId() string {
@@ -193,6 +588,13 @@ func (x *TransactionRequest) GetTransactionId() string {
 	return ""
 }
 
+func (x *TransactionRequest) GetRrn() string {
+	if x != nil {
+		return x.Rrn
+	}
+	return ""
+}
+
 func (x *TransactionRequest) GetPayerVpa() string {
 	if x != nil {
 		return x.PayerVpa
@@ -214,6 +616,13 @@ func (x *TransactionRequest) GetAmountPaisa() int64 {
 	return 0
 }
 
+func (x *TransactionRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 func (x *TransactionRequest) GetType() TransactionType {
 	if x != nil {
 		return x.Type
@@ -221,62 +630,68 @@ func (x *TransactionRequest) GetType() TransactionType {
 	return TransactionType_TRANSACTION_TYPE_UNSPECIFIED
 }
 
-func (x *TransactionRequest) GetReference() string {
+func (x *TransactionRequest) GetDescription() string {
 	if x != nil {
-		return x.Reference
+		return x.Description
 	}
 	return ""
 }
 
-func (x *TransactionRequest) GetPayerBankCode() string {
+func (x *TransactionRequest) GetReference() string {
 	if x != nil {
-		return x.PayerBankCode
+		return x.Reference
 	}
 	return ""
 }
 
-func (x *TransactionRequest) GetPayeeBankCode() string {
+func (x *TransactionRequest) GetSignature() string {
 	if x != nil {
-		return x.PayeeBankCode
+		return x.Signature
 	}
 	return ""
 }
 
-func (x *TransactionRequest) GetDigitalSignature() string {
+func (x *TransactionRequest) GetInitiatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.DigitalSignature
+		return x.InitiatedAt
 	}
-	return ""
+	return nil
 }
 
-func (x *TransactionRequest) GetInitiatedAt() *timestamppb.Timestamp {
+func (x *TransactionRequest) GetMetadata() map[string]string {
 	if x != nil {
-		return x.InitiatedAt
+		return x.Metadata
 	}
 	return nil
 }
 
 type TransactionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	Rrn           string                 `protobuf:"bytes,2,opt,name=rrn,proto3" json:"rrn,omitempty"`
-	Status        TransactionStatus      `protobuf:"varint,3,opt,name=status,proto3,enum=upi.core.TransactionStatus" json:"status,omitempty"`
-	PayerBankCode string                 `protobuf:"bytes,4,opt,name=payer_bank_code,json=payerBankCode,proto3" json:"payer_bank_code,omitempty"`
-	PayeeBankCode string                 `protobuf:"bytes,5,opt,name=payee_bank_code,json=payeeBankCode,proto3" json:"payee_bank_code,omitempty"`
-	Fees          *TransactionFees       `protobuf:"bytes,6,opt,name=fees,proto3" json:"fees,omitempty"`
-	SettlementId  string                 `protobuf:"bytes,7,opt,name=settlement_id,json=settlementId,proto3" json:"settlement_id,omitempty"`
-	ErrorCode     string                 `protobuf:"bytes,8,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,9,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	ProcessedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionId      string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Rrn                string                 `protobuf:"bytes,2,opt,name=rrn,proto3" json:"rrn,omitempty"`
+	Status             TransactionStatus      `protobuf:"varint,3,opt,name=status,proto3,enum=upi_core.TransactionStatus" json:"status,omitempty"`
+	ErrorCode          string                 `protobuf:"bytes,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // Code of the error catalog, e.g. U30; see README "Error Codes"
+	ErrorMessage       string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	PayerBankCode      string                 `protobuf:"bytes,6,opt,name=payer_bank_code,json=payerBankCode,proto3" json:"payer_bank_code,omitempty"`
+	PayeeBankCode      string                 `protobuf:"bytes,7,opt,name=payee_bank_code,json=payeeBankCode,proto3" json:"payee_bank_code,omitempty"`
+	ProcessedAt        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+	Fees               *TransactionFees       `protobuf:"bytes,9,opt,name=fees,proto3" json:"fees,omitempty"`
+	SettlementId       string                 `protobuf:"bytes,10,opt,name=settlement_id,json=settlementId,proto3" json:"settlement_id,omitempty"`
+	SettledAmountPaisa int64                  `protobuf:"varint,11,opt,name=settled_amount_paisa,json=settledAmountPaisa,proto3" json:"settled_amount_paisa,omitempty"` // Amount debited and credited, in settled_currency
+	SettledCurrency    string                 `protobuf:"bytes,12,opt,name=settled_currency,json=settledCurrency,proto3" json:"settled_currency,omitempty"`
+	FxRate             string                 `protobuf:"bytes,13,opt,name=fx_rate,json=fxRate,proto3" json:"fx_rate,omitempty"` // Rate the amount was converted at; empty if it was not
 }
 
 func (x *TransactionResponse) Reset() {
 	*x = TransactionResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[1]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *TransactionResponse) String() string {
@@ -286,8 +701,8 @@ func (x *TransactionResponse) String() string {
 func (*TransactionResponse) ProtoMessage() {}
 
 func (x *TransactionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[1]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -299,7 +714,7 @@ func (x *TransactionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransactionResponse.ProtoReflect.Descriptor instead.
 func (*TransactionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{1}
+	return file_upi_core_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *TransactionResponse) GetTransactionId() string {
@@ -323,6 +738,20 @@ func (x *TransactionResponse) GetStatus() TransactionStatus {
 	return TransactionStatus_TRANSACTION_STATUS_UNSPECIFIED
 }
 
+func (x *TransactionResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
 func (x *TransactionResponse) GetPayerBankCode() string {
 	if x != nil {
 		return x.PayerBankCode
@@ -337,6 +766,13 @@ func (x *TransactionResponse) GetPayeeBankCode() string {
 	return ""
 }
 
+func (x *TransactionResponse) GetProcessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProcessedAt
+	}
+	return nil
+}
+
 func (x *TransactionResponse) GetFees() *TransactionFees {
 	if x != nil {
 		return x.Fees
@@ -351,111 +787,54 @@ func (x *TransactionResponse) GetSettlementId() string {
 	return ""
 }
 
-func (x *TransactionResponse) GetErrorCode() string {
+func (x *TransactionResponse) GetSettledAmountPaisa() int64 {
 	if x != nil {
-		return x.ErrorCode
+		return x.SettledAmountPaisa
 	}
-	return ""
+	return 0
 }
 
-func (x *TransactionResponse) GetErrorMessage() string {
+func (x *TransactionResponse) GetSettledCurrency() string {
 	if x != nil {
-		return x.ErrorMessage
+		return x.SettledCurrency
 	}
 	return ""
 }
 
-func (x *TransactionResponse) GetProcessedAt() *timestamppb.Timestamp {
+func (x *TransactionResponse) GetFxRate() string {
 	if x != nil {
-		return x.ProcessedAt
+		return x.FxRate
 	}
-	return nil
+	return ""
 }
 
-type TransactionFees struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	ProcessingFeePaisa int64                  `protobuf:"varint,1,opt,name=processing_fee_paisa,json=processingFeePaisa,proto3" json:"processing_fee_paisa,omitempty"`
-	GstPaisa           int64                  `protobuf:"varint,2,opt,name=gst_paisa,json=gstPaisa,proto3" json:"gst_paisa,omitempty"`
-	TotalFeePaisa      int64                  `protobuf:"varint,3,opt,name=total_fee_paisa,json=totalFeePaisa,proto3" json:"total_fee_paisa,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+type TransactionStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionId string `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Rrn           string `protobuf:"bytes,2,opt,name=rrn,proto3" json:"rrn,omitempty"`
 }
 
-func (x *TransactionFees) Reset() {
-	*x = TransactionFees{}
-	mi := &file_proto_upi_core_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *TransactionStatusRequest) Reset() {
+	*x = TransactionStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *TransactionFees) String() string {
+func (x *TransactionStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransactionFees) ProtoMessage() {}
-
-func (x *TransactionFees) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[2]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use TransactionFees.ProtoReflect.Descriptor instead.
-func (*TransactionFees) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *TransactionFees) GetProcessingFeePaisa() int64 {
-	if x != nil {
-		return x.ProcessingFeePaisa
-	}
-	return 0
-}
-
-func (x *TransactionFees) GetGstPaisa() int64 {
-	if x != nil {
-		return x.GstPaisa
-	}
-	return 0
-}
-
-func (x *TransactionFees) GetTotalFeePaisa() int64 {
-	if x != nil {
-		return x.TotalFeePaisa
-	}
-	return 0
-}
-
-type TransactionStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	BankCode      string                 `protobuf:"bytes,2,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *TransactionStatusRequest) Reset() {
-	*x = TransactionStatusRequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *TransactionStatusRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*TransactionStatusRequest) ProtoMessage() {}
+func (*TransactionStatusRequest) ProtoMessage() {}
 
 func (x *TransactionStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[3]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -467,7 +846,7 @@ func (x *TransactionStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransactionStatusRequest.ProtoReflect.Descriptor instead.
 func (*TransactionStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{3}
+	return file_upi_core_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *TransactionStatusRequest) GetTransactionId() string {
@@ -477,33 +856,44 @@ func (x *TransactionStatusRequest) GetTransactionId() string {
 	return ""
 }
 
-func (x *TransactionStatusRequest) GetBankCode() string {
+func (x *TransactionStatusRequest) GetRrn() string {
 	if x != nil {
-		return x.BankCode
+		return x.Rrn
 	}
 	return ""
 }
 
 type TransactionStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	Rrn           string                 `protobuf:"bytes,2,opt,name=rrn,proto3" json:"rrn,omitempty"`
-	Status        TransactionStatus      `protobuf:"varint,3,opt,name=status,proto3,enum=upi.core.TransactionStatus" json:"status,omitempty"`
-	Events        []*TransactionEvent    `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
-	AmountPaisa   int64                  `protobuf:"varint,5,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`
-	PayerVpa      string                 `protobuf:"bytes,6,opt,name=payer_vpa,json=payerVpa,proto3" json:"payer_vpa,omitempty"`
-	PayeeVpa      string                 `protobuf:"bytes,7,opt,name=payee_vpa,json=payeeVpa,proto3" json:"payee_vpa,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionId      string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Rrn                string                 `protobuf:"bytes,2,opt,name=rrn,proto3" json:"rrn,omitempty"`
+	Status             TransactionStatus      `protobuf:"varint,3,opt,name=status,proto3,enum=upi_core.TransactionStatus" json:"status,omitempty"`
+	AmountPaisa        int64                  `protobuf:"varint,4,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`
+	PayerVpa           string                 `protobuf:"bytes,5,opt,name=payer_vpa,json=payerVpa,proto3" json:"payer_vpa,omitempty"`
+	PayeeVpa           string                 `protobuf:"bytes,6,opt,name=payee_vpa,json=payeeVpa,proto3" json:"payee_vpa,omitempty"`
+	PayerBankCode      string                 `protobuf:"bytes,7,opt,name=payer_bank_code,json=payerBankCode,proto3" json:"payer_bank_code,omitempty"`
+	PayeeBankCode      string                 `protobuf:"bytes,8,opt,name=payee_bank_code,json=payeeBankCode,proto3" json:"payee_bank_code,omitempty"`
+	InitiatedAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=initiated_at,json=initiatedAt,proto3" json:"initiated_at,omitempty"`
+	ProcessedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+	ErrorCode          string                 `protobuf:"bytes,11,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage       string                 `protobuf:"bytes,12,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Events             []*TransactionEvent    `protobuf:"bytes,13,rep,name=events,proto3" json:"events,omitempty"`
+	Currency           string                 `protobuf:"bytes,14,opt,name=currency,proto3" json:"currency,omitempty"`
+	SettledAmountPaisa int64                  `protobuf:"varint,15,opt,name=settled_amount_paisa,json=settledAmountPaisa,proto3" json:"settled_amount_paisa,omitempty"`
+	SettledCurrency    string                 `protobuf:"bytes,16,opt,name=settled_currency,json=settledCurrency,proto3" json:"settled_currency,omitempty"`
+	FxRate             string                 `protobuf:"bytes,17,opt,name=fx_rate,json=fxRate,proto3" json:"fx_rate,omitempty"`
 }
 
 func (x *TransactionStatusResponse) Reset() {
 	*x = TransactionStatusResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *TransactionStatusResponse) String() string {
@@ -513,8 +903,8 @@ func (x *TransactionStatusResponse) String() string {
 func (*TransactionStatusResponse) ProtoMessage() {}
 
 func (x *TransactionStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[4]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -526,7 +916,7 @@ func (x *TransactionStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransactionStatusResponse.ProtoReflect.Descriptor instead.
 func (*TransactionStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{4}
+	return file_upi_core_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *TransactionStatusResponse) GetTransactionId() string {
@@ -550,13 +940,6 @@ func (x *TransactionStatusResponse) GetStatus() TransactionStatus {
 	return TransactionStatus_TRANSACTION_STATUS_UNSPECIFIED
 }
 
-func (x *TransactionStatusResponse) GetEvents() []*TransactionEvent {
-	if x != nil {
-		return x.Events
-	}
-	return nil
-}
-
 func (x *TransactionStatusResponse) GetAmountPaisa() int64 {
 	if x != nil {
 		return x.AmountPaisa
@@ -578,45 +961,161 @@ func (x *TransactionStatusResponse) GetPayeeVpa() string {
 	return ""
 }
 
-func (x *TransactionStatusResponse) GetCreatedAt() *timestamppb.Timestamp {
+func (x *TransactionStatusResponse) GetPayerBankCode() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.PayerBankCode
+	}
+	return ""
+}
+
+func (x *TransactionStatusResponse) GetPayeeBankCode() string {
+	if x != nil {
+		return x.PayeeBankCode
+	}
+	return ""
+}
+
+func (x *TransactionStatusResponse) GetInitiatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InitiatedAt
 	}
 	return nil
 }
 
-func (x *TransactionStatusResponse) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *TransactionStatusResponse) GetProcessedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.UpdatedAt
+		return x.ProcessedAt
 	}
 	return nil
 }
 
-type TransactionEvent struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Status        TransactionStatus      `protobuf:"varint,2,opt,name=status,proto3,enum=upi.core.TransactionStatus" json:"status,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	unknownFields protoimpl.UnknownFields
+func (x *TransactionStatusResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *TransactionStatusResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *TransactionStatusResponse) GetEvents() []*TransactionEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *TransactionStatusResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *TransactionStatusResponse) GetSettledAmountPaisa() int64 {
+	if x != nil {
+		return x.SettledAmountPaisa
+	}
+	return 0
+}
+
+func (x *TransactionStatusResponse) GetSettledCurrency() string {
+	if x != nil {
+		return x.SettledCurrency
+	}
+	return ""
+}
+
+func (x *TransactionStatusResponse) GetFxRate() string {
+	if x != nil {
+		return x.FxRate
+	}
+	return ""
+}
+
+type SubscribeTransactionStatusRequest struct {
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionId string `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
 }
 
-func (x *TransactionEvent) Reset() {
-	*x = TransactionEvent{}
-	mi := &file_proto_upi_core_proto_msgTypes[5]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *SubscribeTransactionStatusRequest) Reset() {
+	*x = SubscribeTransactionStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *TransactionEvent) String() string {
+func (x *SubscribeTransactionStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransactionEvent) ProtoMessage() {}
+func (*SubscribeTransactionStatusRequest) ProtoMessage() {}
 
-func (x *TransactionEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[5]
+func (x *SubscribeTransactionStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeTransactionStatusRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeTransactionStatusRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SubscribeTransactionStatusRequest) GetTransactionId() string {
 	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type TransactionStatusUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Status        TransactionStatus      `protobuf:"varint,2,opt,name=status,proto3,enum=upi_core.TransactionStatus" json:"status,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Final         bool                   `protobuf:"varint,6,opt,name=final,proto3" json:"final,omitempty"` // No further updates follow
+}
+
+func (x *TransactionStatusUpdate) Reset() {
+	*x = TransactionStatusUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionStatusUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionStatusUpdate) ProtoMessage() {}
+
+func (x *TransactionStatusUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -626,47 +1125,70 @@ func (x *TransactionEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransactionEvent.ProtoReflect.Descriptor instead.
-func (*TransactionEvent) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use TransactionStatusUpdate.ProtoReflect.Descriptor instead.
+func (*TransactionStatusUpdate) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *TransactionEvent) GetTimestamp() *timestamppb.Timestamp {
+func (x *TransactionStatusUpdate) GetTransactionId() string {
 	if x != nil {
-		return x.Timestamp
+		return x.TransactionId
 	}
-	return nil
+	return ""
 }
 
-func (x *TransactionEvent) GetStatus() TransactionStatus {
+func (x *TransactionStatusUpdate) GetStatus() TransactionStatus {
 	if x != nil {
 		return x.Status
 	}
 	return TransactionStatus_TRANSACTION_STATUS_UNSPECIFIED
 }
 
-func (x *TransactionEvent) GetDescription() string {
+func (x *TransactionStatusUpdate) GetErrorCode() string {
 	if x != nil {
-		return x.Description
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *TransactionStatusUpdate) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
 	}
 	return ""
 }
 
+func (x *TransactionStatusUpdate) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *TransactionStatusUpdate) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
 type CancelTransactionRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	TransactionId    string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	BankCode         string                 `protobuf:"bytes,2,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
-	Reason           string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
-	DigitalSignature string                 `protobuf:"bytes,4,opt,name=digital_signature,json=digitalSignature,proto3" json:"digital_signature,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionId string `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Signature     string `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
 func (x *CancelTransactionRequest) Reset() {
 	*x = CancelTransactionRequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[6]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *CancelTransactionRequest) String() string {
@@ -676,8 +1198,8 @@ func (x *CancelTransactionRequest) String() string {
 func (*CancelTransactionRequest) ProtoMessage() {}
 
 func (x *CancelTransactionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[6]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -689,7 +1211,7 @@ func (x *CancelTransactionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelTransactionRequest.ProtoReflect.Descriptor instead.
 func (*CancelTransactionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{6}
+	return file_upi_core_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CancelTransactionRequest) GetTransactionId() string {
@@ -699,13 +1221,6 @@ func (x *CancelTransactionRequest) GetTransactionId() string {
 	return ""
 }
 
-func (x *CancelTransactionRequest) GetBankCode() string {
-	if x != nil {
-		return x.BankCode
-	}
-	return ""
-}
-
 func (x *CancelTransactionRequest) GetReason() string {
 	if x != nil {
 		return x.Reason
@@ -713,28 +1228,31 @@ func (x *CancelTransactionRequest) GetReason() string {
 	return ""
 }
 
-func (x *CancelTransactionRequest) GetDigitalSignature() string {
+func (x *CancelTransactionRequest) GetSignature() string {
 	if x != nil {
-		return x.DigitalSignature
+		return x.Signature
 	}
 	return ""
 }
 
 type CancelTransactionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	ErrorCode     string                 `protobuf:"bytes,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	CancelledAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success      bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorCode    string                 `protobuf:"bytes,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	CancelledAt  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
 }
 
 func (x *CancelTransactionResponse) Reset() {
 	*x = CancelTransactionResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[7]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *CancelTransactionResponse) String() string {
@@ -744,8 +1262,8 @@ func (x *CancelTransactionResponse) String() string {
 func (*CancelTransactionResponse) ProtoMessage() {}
 
 func (x *CancelTransactionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[7]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -757,7 +1275,7 @@ func (x *CancelTransactionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelTransactionResponse.ProtoReflect.Descriptor instead.
 func (*CancelTransactionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{7}
+	return file_upi_core_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CancelTransactionResponse) GetSuccess() bool {
@@ -789,21 +1307,23 @@ func (x *CancelTransactionResponse) GetCancelledAt() *timestamppb.Timestamp {
 }
 
 type ReverseTransactionRequest struct {
-	state                 protoimpl.MessageState `protogen:"open.v1"`
-	OriginalTransactionId string                 `protobuf:"bytes,1,opt,name=original_transaction_id,json=originalTransactionId,proto3" json:"original_transaction_id,omitempty"`
-	ReversalTransactionId string                 `protobuf:"bytes,2,opt,name=reversal_transaction_id,json=reversalTransactionId,proto3" json:"reversal_transaction_id,omitempty"`
-	BankCode              string                 `protobuf:"bytes,3,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
-	Reason                string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
-	DigitalSignature      string                 `protobuf:"bytes,5,opt,name=digital_signature,json=digitalSignature,proto3" json:"digital_signature,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OriginalTransactionId string `protobuf:"bytes,1,opt,name=original_transaction_id,json=originalTransactionId,proto3" json:"original_transaction_id,omitempty"`
+	ReversalTransactionId string `protobuf:"bytes,2,opt,name=reversal_transaction_id,json=reversalTransactionId,proto3" json:"reversal_transaction_id,omitempty"`
+	Reason                string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Signature             string `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
 func (x *ReverseTransactionRequest) Reset() {
 	*x = ReverseTransactionRequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[8]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *ReverseTransactionRequest) String() string {
@@ -813,8 +1333,8 @@ func (x *ReverseTransactionRequest) String() string {
 func (*ReverseTransactionRequest) ProtoMessage() {}
 
 func (x *ReverseTransactionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[8]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -826,7 +1346,7 @@ func (x *ReverseTransactionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReverseTransactionRequest.ProtoReflect.Descriptor instead.
 func (*ReverseTransactionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{8}
+	return file_upi_core_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ReverseTransactionRequest) GetOriginalTransactionId() string {
@@ -843,13 +1363,6 @@ func (x *ReverseTransactionRequest) GetReversalTransactionId() string {
 	return ""
 }
 
-func (x *ReverseTransactionRequest) GetBankCode() string {
-	if x != nil {
-		return x.BankCode
-	}
-	return ""
-}
-
 func (x *ReverseTransactionRequest) GetReason() string {
 	if x != nil {
 		return x.Reason
@@ -857,29 +1370,32 @@ func (x *ReverseTransactionRequest) GetReason() string {
 	return ""
 }
 
-func (x *ReverseTransactionRequest) GetDigitalSignature() string {
+func (x *ReverseTransactionRequest) GetSignature() string {
 	if x != nil {
-		return x.DigitalSignature
+		return x.Signature
 	}
 	return ""
 }
 
 type ReverseTransactionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	ReversalRrn   string                 `protobuf:"bytes,2,opt,name=reversal_rrn,json=reversalRrn,proto3" json:"reversal_rrn,omitempty"`
-	ErrorCode     string                 `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	ReversedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=reversed_at,json=reversedAt,proto3" json:"reversed_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success               bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ReversalTransactionId string                 `protobuf:"bytes,2,opt,name=reversal_transaction_id,json=reversalTransactionId,proto3" json:"reversal_transaction_id,omitempty"`
+	ErrorCode             string                 `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage          string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	ReversedAt            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=reversed_at,json=reversedAt,proto3" json:"reversed_at,omitempty"`
 }
 
 func (x *ReverseTransactionResponse) Reset() {
 	*x = ReverseTransactionResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[9]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *ReverseTransactionResponse) String() string {
@@ -889,8 +1405,8 @@ func (x *ReverseTransactionResponse) String() string {
 func (*ReverseTransactionResponse) ProtoMessage() {}
 
 func (x *ReverseTransactionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[9]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -902,7 +1418,7 @@ func (x *ReverseTransactionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReverseTransactionResponse.ProtoReflect.Descriptor instead.
 func (*ReverseTransactionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{9}
+	return file_upi_core_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ReverseTransactionResponse) GetSuccess() bool {
@@ -912,9 +1428,9 @@ func (x *ReverseTransactionResponse) GetSuccess() bool {
 	return false
 }
 
-func (x *ReverseTransactionResponse) GetReversalRrn() string {
+func (x *ReverseTransactionResponse) GetReversalTransactionId() string {
 	if x != nil {
-		return x.ReversalRrn
+		return x.ReversalTransactionId
 	}
 	return ""
 }
@@ -940,20 +1456,22 @@ func (x *ReverseTransactionResponse) GetReversedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-// VPA Management Messages
+// VPA Messages
 type ResolveVPARequest struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	Vpa                string                 `protobuf:"bytes,1,opt,name=vpa,proto3" json:"vpa,omitempty"`
-	RequestingBankCode string                 `protobuf:"bytes,2,opt,name=requesting_bank_code,json=requestingBankCode,proto3" json:"requesting_bank_code,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vpa string `protobuf:"bytes,1,opt,name=vpa,proto3" json:"vpa,omitempty"`
 }
 
 func (x *ResolveVPARequest) Reset() {
 	*x = ResolveVPARequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[10]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *ResolveVPARequest) String() string {
@@ -963,8 +1481,8 @@ func (x *ResolveVPARequest) String() string {
 func (*ResolveVPARequest) ProtoMessage() {}
 
 func (x *ResolveVPARequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[10]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -976,7 +1494,7 @@ func (x *ResolveVPARequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResolveVPARequest.ProtoReflect.Descriptor instead.
 func (*ResolveVPARequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{10}
+	return file_upi_core_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ResolveVPARequest) GetVpa() string {
@@ -986,31 +1504,27 @@ func (x *ResolveVPARequest) GetVpa() string {
 	return ""
 }
 
-func (x *ResolveVPARequest) GetRequestingBankCode() string {
-	if x != nil {
-		return x.RequestingBankCode
-	}
-	return ""
-}
-
 type ResolveVPAResponse struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Exists            bool                   `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"`
-	BankCode          string                 `protobuf:"bytes,2,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
-	AccountNumber     string                 `protobuf:"bytes,3,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
-	AccountHolderName string                 `protobuf:"bytes,4,opt,name=account_holder_name,json=accountHolderName,proto3" json:"account_holder_name,omitempty"`
-	IsActive          bool                   `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	ErrorCode         string                 `protobuf:"bytes,6,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
-	ErrorMessage      string                 `protobuf:"bytes,7,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Exists            bool   `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"`
+	BankCode          string `protobuf:"bytes,2,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	AccountNumber     string `protobuf:"bytes,3,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
+	AccountHolderName string `protobuf:"bytes,4,opt,name=account_holder_name,json=accountHolderName,proto3" json:"account_holder_name,omitempty"`
+	IsActive          bool   `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	ErrorCode         string `protobuf:"bytes,6,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage      string `protobuf:"bytes,7,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
 }
 
 func (x *ResolveVPAResponse) Reset() {
 	*x = ResolveVPAResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[11]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *ResolveVPAResponse) String() string {
@@ -1020,8 +1534,8 @@ func (x *ResolveVPAResponse) String() string {
 func (*ResolveVPAResponse) ProtoMessage() {}
 
 func (x *ResolveVPAResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[11]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1033,7 +1547,7 @@ func (x *ResolveVPAResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResolveVPAResponse.ProtoReflect.Descriptor instead.
 func (*ResolveVPAResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{11}
+	return file_upi_core_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ResolveVPAResponse) GetExists() bool {
@@ -1086,22 +1600,25 @@ func (x *ResolveVPAResponse) GetErrorMessage() string {
 }
 
 type RegisterVPARequest struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Vpa               string                 `protobuf:"bytes,1,opt,name=vpa,proto3" json:"vpa,omitempty"`
-	BankCode          string                 `protobuf:"bytes,2,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
-	AccountNumber     string                 `protobuf:"bytes,3,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
-	AccountHolderName string                 `protobuf:"bytes,4,opt,name=account_holder_name,json=accountHolderName,proto3" json:"account_holder_name,omitempty"`
-	MobileNumber      string                 `protobuf:"bytes,5,opt,name=mobile_number,json=mobileNumber,proto3" json:"mobile_number,omitempty"`
-	DigitalSignature  string                 `protobuf:"bytes,6,opt,name=digital_signature,json=digitalSignature,proto3" json:"digital_signature,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
-}
-
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vpa               string `protobuf:"bytes,1,opt,name=vpa,proto3" json:"vpa,omitempty"`
+	BankCode          string `protobuf:"bytes,2,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	AccountNumber     string `protobuf:"bytes,3,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
+	AccountHolderName string `protobuf:"bytes,4,opt,name=account_holder_name,json=accountHolderName,proto3" json:"account_holder_name,omitempty"`
+	MobileNumber      string `protobuf:"bytes,5,opt,name=mobile_number,json=mobileNumber,proto3" json:"mobile_number,omitempty"`
+	Signature         string `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
 func (x *RegisterVPARequest) Reset() {
 	*x = RegisterVPARequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[12]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *RegisterVPARequest) String() string {
@@ -1111,8 +1628,8 @@ func (x *RegisterVPARequest) String() string {
 func (*RegisterVPARequest) ProtoMessage() {}
 
 func (x *RegisterVPARequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[12]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1124,7 +1641,7 @@ func (x *RegisterVPARequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterVPARequest.ProtoReflect.Descriptor instead.
 func (*RegisterVPARequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{12}
+	return file_upi_core_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *RegisterVPARequest) GetVpa() string {
@@ -1162,29 +1679,31 @@ func (x *RegisterVPARequest) GetMobileNumber() string {
 	return ""
 }
 
-func (x *RegisterVPARequest) GetDigitalSignature() string {
+func (x *RegisterVPARequest) GetSignature() string {
 	if x != nil {
-		return x.DigitalSignature
+		return x.Signature
 	}
 	return ""
 }
 
 type RegisterVPAResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	VpaId         string                 `protobuf:"bytes,2,opt,name=vpa_id,json=vpaId,proto3" json:"vpa_id,omitempty"`
-	ErrorCode     string                 `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	RegisteredAt  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success      bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorCode    string                 `protobuf:"bytes,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	RegisteredAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
 }
 
 func (x *RegisterVPAResponse) Reset() {
 	*x = RegisterVPAResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[13]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *RegisterVPAResponse) String() string {
@@ -1194,8 +1713,8 @@ func (x *RegisterVPAResponse) String() string {
 func (*RegisterVPAResponse) ProtoMessage() {}
 
 func (x *RegisterVPAResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[13]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1207,7 +1726,7 @@ func (x *RegisterVPAResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterVPAResponse.ProtoReflect.Descriptor instead.
 func (*RegisterVPAResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{13}
+	return file_upi_core_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *RegisterVPAResponse) GetSuccess() bool {
@@ -1217,13 +1736,6 @@ func (x *RegisterVPAResponse) GetSuccess() bool {
 	return false
 }
 
-func (x *RegisterVPAResponse) GetVpaId() string {
-	if x != nil {
-		return x.VpaId
-	}
-	return ""
-}
-
 func (x *RegisterVPAResponse) GetErrorCode() string {
 	if x != nil {
 		return x.ErrorCode
@@ -1246,20 +1758,22 @@ func (x *RegisterVPAResponse) GetRegisteredAt() *timestamppb.Timestamp {
 }
 
 type UpdateVPARequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Vpa              string                 `protobuf:"bytes,1,opt,name=vpa,proto3" json:"vpa,omitempty"`
-	BankCode         string                 `protobuf:"bytes,2,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
-	NewAccountNumber string                 `protobuf:"bytes,3,opt,name=new_account_number,json=newAccountNumber,proto3" json:"new_account_number,omitempty"`
-	DigitalSignature string                 `protobuf:"bytes,4,opt,name=digital_signature,json=digitalSignature,proto3" json:"digital_signature,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vpa              string `protobuf:"bytes,1,opt,name=vpa,proto3" json:"vpa,omitempty"`
+	NewAccountNumber string `protobuf:"bytes,2,opt,name=new_account_number,json=newAccountNumber,proto3" json:"new_account_number,omitempty"`
+	Signature        string `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
 func (x *UpdateVPARequest) Reset() {
 	*x = UpdateVPARequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[14]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *UpdateVPARequest) String() string {
@@ -1269,8 +1783,8 @@ func (x *UpdateVPARequest) String() string {
 func (*UpdateVPARequest) ProtoMessage() {}
 
 func (x *UpdateVPARequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[14]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1282,7 +1796,7 @@ func (x *UpdateVPARequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateVPARequest.ProtoReflect.Descriptor instead.
 func (*UpdateVPARequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{14}
+	return file_upi_core_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *UpdateVPARequest) GetVpa() string {
@@ -1292,13 +1806,6 @@ func (x *UpdateVPARequest) GetVpa() string {
 	return ""
 }
 
-func (x *UpdateVPARequest) GetBankCode() string {
-	if x != nil {
-		return x.BankCode
-	}
-	return ""
-}
-
 func (x *UpdateVPARequest) GetNewAccountNumber() string {
 	if x != nil {
 		return x.NewAccountNumber
@@ -1306,28 +1813,31 @@ func (x *UpdateVPARequest) GetNewAccountNumber() string {
 	return ""
 }
 
-func (x *UpdateVPARequest) GetDigitalSignature() string {
+func (x *UpdateVPARequest) GetSignature() string {
 	if x != nil {
-		return x.DigitalSignature
+		return x.Signature
 	}
 	return ""
 }
 
 type UpdateVPAResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	ErrorCode     string                 `protobuf:"bytes,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success      bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorCode    string                 `protobuf:"bytes,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 }
 
 func (x *UpdateVPAResponse) Reset() {
 	*x = UpdateVPAResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[15]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *UpdateVPAResponse) String() string {
@@ -1337,8 +1847,8 @@ func (x *UpdateVPAResponse) String() string {
 func (*UpdateVPAResponse) ProtoMessage() {}
 
 func (x *UpdateVPAResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[15]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1350,7 +1860,7 @@ func (x *UpdateVPAResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateVPAResponse.ProtoReflect.Descriptor instead.
 func (*UpdateVPAResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{15}
+	return file_upi_core_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *UpdateVPAResponse) GetSuccess() bool {
@@ -1382,19 +1892,22 @@ func (x *UpdateVPAResponse) GetUpdatedAt() *timestamppb.Timestamp {
 }
 
 type DeactivateVPARequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Vpa              string                 `protobuf:"bytes,1,opt,name=vpa,proto3" json:"vpa,omitempty"`
-	BankCode         string                 `protobuf:"bytes,2,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
-	DigitalSignature string                 `protobuf:"bytes,3,opt,name=digital_signature,json=digitalSignature,proto3" json:"digital_signature,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vpa       string `protobuf:"bytes,1,opt,name=vpa,proto3" json:"vpa,omitempty"`
+	Reason    string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Signature string `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
 func (x *DeactivateVPARequest) Reset() {
 	*x = DeactivateVPARequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[16]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *DeactivateVPARequest) String() string {
@@ -1404,8 +1917,8 @@ func (x *DeactivateVPARequest) String() string {
 func (*DeactivateVPARequest) ProtoMessage() {}
 
 func (x *DeactivateVPARequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[16]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1417,7 +1930,7 @@ func (x *DeactivateVPARequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeactivateVPARequest.ProtoReflect.Descriptor instead.
 func (*DeactivateVPARequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{16}
+	return file_upi_core_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *DeactivateVPARequest) GetVpa() string {
@@ -1427,35 +1940,38 @@ func (x *DeactivateVPARequest) GetVpa() string {
 	return ""
 }
 
-func (x *DeactivateVPARequest) GetBankCode() string {
+func (x *DeactivateVPARequest) GetReason() string {
 	if x != nil {
-		return x.BankCode
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *DeactivateVPARequest) GetDigitalSignature() string {
+func (x *DeactivateVPARequest) GetSignature() string {
 	if x != nil {
-		return x.DigitalSignature
+		return x.Signature
 	}
 	return ""
 }
 
 type DeactivateVPAResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	ErrorCode     string                 `protobuf:"bytes,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
 	ErrorMessage  string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
 	DeactivatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=deactivated_at,json=deactivatedAt,proto3" json:"deactivated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeactivateVPAResponse) Reset() {
 	*x = DeactivateVPAResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[17]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
 func (x *DeactivateVPAResponse) String() string {
@@ -1465,8 +1981,8 @@ func (x *DeactivateVPAResponse) String() string {
 func (*DeactivateVPAResponse) ProtoMessage() {}
 
 func (x *DeactivateVPAResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[17]
-	if x != nil {
+	mi := &file_upi_core_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1478,7 +1994,7 @@ func (x *DeactivateVPAResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeactivateVPAResponse.ProtoReflect.Descriptor instead.
 func (*DeactivateVPAResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{17}
+	return file_upi_core_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *DeactivateVPAResponse) GetSuccess() bool {
@@ -1509,29 +2025,42 @@ func (x *DeactivateVPAResponse) GetDeactivatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-// Health and Monitoring Messages
-type HealthCheckRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
+// Mandate Messages
+type CreateMandateRequest struct {
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
-}
+	unknownFields protoimpl.UnknownFields
 
-func (x *HealthCheckRequest) Reset() {
-	*x = HealthCheckRequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[18]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	MandateId      string           `protobuf:"bytes,1,opt,name=mandate_id,json=mandateId,proto3" json:"mandate_id,omitempty"` // Chosen by the payer's PSP
+	PayerVpa       string           `protobuf:"bytes,2,opt,name=payer_vpa,json=payerVpa,proto3" json:"payer_vpa,omitempty"`
+	PayeeVpa       string           `protobuf:"bytes,3,opt,name=payee_vpa,json=payeeVpa,proto3" json:"payee_vpa,omitempty"`
+	AmountPaisa    int64            `protobuf:"varint,4,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`            // Debited on each due date
+	MaxAmountPaisa int64            `protobuf:"varint,5,opt,name=max_amount_paisa,json=maxAmountPaisa,proto3" json:"max_amount_paisa,omitempty"` // Cap on any one execution
+	Frequency      MandateFrequency `protobuf:"varint,6,opt,name=frequency,proto3,enum=upi_core.MandateFrequency" json:"frequency,omitempty"`
+	StartDate      string           `protobuf:"bytes,7,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"` // YYYY-MM-DD format
+	EndDate        string           `protobuf:"bytes,8,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`       // YYYY-MM-DD format, empty for no end
+	Description    string           `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	Signature      string           `protobuf:"bytes,10,opt,name=signature,proto3" json:"signature,omitempty"` // Payer bank's signature
+}
+
+func (x *CreateMandateRequest) Reset() {
+	*x = CreateMandateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *HealthCheckRequest) String() string {
+func (x *CreateMandateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckRequest) ProtoMessage() {}
+func (*CreateMandateRequest) ProtoMessage() {}
 
-func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[18]
-	if x != nil {
+func (x *CreateMandateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1541,38 +2070,107 @@ func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
-func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use CreateMandateRequest.ProtoReflect.Descriptor instead.
+func (*CreateMandateRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{18}
 }
 
-type HealthCheckResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
-	Uptime        string                 `protobuf:"bytes,4,opt,name=uptime,proto3" json:"uptime,omitempty"`
-	Dependencies  map[string]string      `protobuf:"bytes,5,rep,name=dependencies,proto3" json:"dependencies,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields protoimpl.UnknownFields
+func (x *CreateMandateRequest) GetMandateId() string {
+	if x != nil {
+		return x.MandateId
+	}
+	return ""
+}
+
+func (x *CreateMandateRequest) GetPayerVpa() string {
+	if x != nil {
+		return x.PayerVpa
+	}
+	return ""
+}
+
+func (x *CreateMandateRequest) GetPayeeVpa() string {
+	if x != nil {
+		return x.PayeeVpa
+	}
+	return ""
+}
+
+func (x *CreateMandateRequest) GetAmountPaisa() int64 {
+	if x != nil {
+		return x.AmountPaisa
+	}
+	return 0
+}
+
+func (x *CreateMandateRequest) GetMaxAmountPaisa() int64 {
+	if x != nil {
+		return x.MaxAmountPaisa
+	}
+	return 0
+}
+
+func (x *CreateMandateRequest) GetFrequency() MandateFrequency {
+	if x != nil {
+		return x.Frequency
+	}
+	return MandateFrequency_MANDATE_FREQUENCY_UNSPECIFIED
+}
+
+func (x *CreateMandateRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *CreateMandateRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *CreateMandateRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateMandateRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type CreateMandateResponse struct {
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mandate *Mandate `protobuf:"bytes,1,opt,name=mandate,proto3" json:"mandate,omitempty"`
 }
 
-func (x *HealthCheckResponse) Reset() {
-	*x = HealthCheckResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[19]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *CreateMandateResponse) Reset() {
+	*x = CreateMandateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *HealthCheckResponse) String() string {
+func (x *CreateMandateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckResponse) ProtoMessage() {}
+func (*CreateMandateResponse) ProtoMessage() {}
 
-func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[19]
-	if x != nil {
+func (x *CreateMandateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1582,68 +2180,115 @@ func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
-func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use CreateMandateResponse.ProtoReflect.Descriptor instead.
+func (*CreateMandateResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *HealthCheckResponse) GetStatus() string {
+func (x *CreateMandateResponse) GetMandate() *Mandate {
 	if x != nil {
-		return x.Status
+		return x.Mandate
 	}
-	return ""
+	return nil
 }
 
-func (x *HealthCheckResponse) GetTimestamp() *timestamppb.Timestamp {
-	if x != nil {
-		return x.Timestamp
+type ModifyMandateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MandateId      string `protobuf:"bytes,1,opt,name=mandate_id,json=mandateId,proto3" json:"mandate_id,omitempty"`
+	AmountPaisa    int64  `protobuf:"varint,2,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`            // 0 leaves the amount unchanged
+	MaxAmountPaisa int64  `protobuf:"varint,3,opt,name=max_amount_paisa,json=maxAmountPaisa,proto3" json:"max_amount_paisa,omitempty"` // 0 leaves the cap unchanged
+	EndDate        string `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`                         // YYYY-MM-DD format, empty leaves it unchanged
+}
+
+func (x *ModifyMandateRequest) Reset() {
+	*x = ModifyMandateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *HealthCheckResponse) GetVersion() string {
+func (x *ModifyMandateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModifyMandateRequest) ProtoMessage() {}
+
+func (x *ModifyMandateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModifyMandateRequest.ProtoReflect.Descriptor instead.
+func (*ModifyMandateRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ModifyMandateRequest) GetMandateId() string {
 	if x != nil {
-		return x.Version
+		return x.MandateId
 	}
 	return ""
 }
 
-func (x *HealthCheckResponse) GetUptime() string {
+func (x *ModifyMandateRequest) GetAmountPaisa() int64 {
 	if x != nil {
-		return x.Uptime
+		return x.AmountPaisa
 	}
-	return ""
+	return 0
 }
 
-func (x *HealthCheckResponse) GetDependencies() map[string]string {
+func (x *ModifyMandateRequest) GetMaxAmountPaisa() int64 {
 	if x != nil {
-		return x.Dependencies
+		return x.MaxAmountPaisa
 	}
-	return nil
+	return 0
 }
 
-type MetricsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
+func (x *ModifyMandateRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+type ModifyMandateResponse struct {
+	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mandate *Mandate `protobuf:"bytes,1,opt,name=mandate,proto3" json:"mandate,omitempty"`
 }
 
-func (x *MetricsRequest) Reset() {
-	*x = MetricsRequest{}
-	mi := &file_proto_upi_core_proto_msgTypes[20]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ModifyMandateResponse) Reset() {
+	*x = ModifyMandateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *MetricsRequest) String() string {
+func (x *ModifyMandateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MetricsRequest) ProtoMessage() {}
+func (*ModifyMandateResponse) ProtoMessage() {}
 
-func (x *MetricsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[20]
-	if x != nil {
+func (x *ModifyMandateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1653,39 +2298,45 @@ func (x *MetricsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MetricsRequest.ProtoReflect.Descriptor instead.
-func (*MetricsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use ModifyMandateResponse.ProtoReflect.Descriptor instead.
+func (*ModifyMandateResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{21}
 }
 
-type MetricsResponse struct {
-	state                  protoimpl.MessageState `protogen:"open.v1"`
-	TotalTransactions      int64                  `protobuf:"varint,1,opt,name=total_transactions,json=totalTransactions,proto3" json:"total_transactions,omitempty"`
-	SuccessfulTransactions int64                  `protobuf:"varint,2,opt,name=successful_transactions,json=successfulTransactions,proto3" json:"successful_transactions,omitempty"`
-	FailedTransactions     int64                  `protobuf:"varint,3,opt,name=failed_transactions,json=failedTransactions,proto3" json:"failed_transactions,omitempty"`
-	SuccessRatePercent     float64                `protobuf:"fixed64,4,opt,name=success_rate_percent,json=successRatePercent,proto3" json:"success_rate_percent,omitempty"`
-	AvgProcessingTimeMs    int64                  `protobuf:"varint,5,opt,name=avg_processing_time_ms,json=avgProcessingTimeMs,proto3" json:"avg_processing_time_ms,omitempty"`
-	BankHealthScores       map[string]int64       `protobuf:"bytes,6,rep,name=bank_health_scores,json=bankHealthScores,proto3" json:"bank_health_scores,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+func (x *ModifyMandateResponse) GetMandate() *Mandate {
+	if x != nil {
+		return x.Mandate
+	}
+	return nil
 }
 
-func (x *MetricsResponse) Reset() {
-	*x = MetricsResponse{}
-	mi := &file_proto_upi_core_proto_msgTypes[21]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+type RevokeMandateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MandateId string `protobuf:"bytes,1,opt,name=mandate_id,json=mandateId,proto3" json:"mandate_id,omitempty"`
+	Reason    string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 }
 
-func (x *MetricsResponse) String() string {
+func (x *RevokeMandateRequest) Reset() {
+	*x = RevokeMandateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokeMandateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MetricsResponse) ProtoMessage() {}
+func (*RevokeMandateRequest) ProtoMessage() {}
 
-func (x *MetricsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_upi_core_proto_msgTypes[21]
-	if x != nil {
+func (x *RevokeMandateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -1695,339 +2346,7043 @@ func (x *MetricsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MetricsResponse.ProtoReflect.Descriptor instead.
-func (*MetricsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_upi_core_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use RevokeMandateRequest.ProtoReflect.Descriptor instead.
+func (*RevokeMandateRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *MetricsResponse) GetTotalTransactions() int64 {
+func (x *RevokeMandateRequest) GetMandateId() string {
 	if x != nil {
-		return x.TotalTransactions
+		return x.MandateId
 	}
-	return 0
+	return ""
 }
 
-func (x *MetricsResponse) GetSuccessfulTransactions() int64 {
+func (x *RevokeMandateRequest) GetReason() string {
 	if x != nil {
-		return x.SuccessfulTransactions
+		return x.Reason
 	}
-	return 0
+	return ""
 }
 
-func (x *MetricsResponse) GetFailedTransactions() int64 {
-	if x != nil {
-		return x.FailedTransactions
+type RevokeMandateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mandate *Mandate `protobuf:"bytes,1,opt,name=mandate,proto3" json:"mandate,omitempty"`
+}
+
+func (x *RevokeMandateResponse) Reset() {
+	*x = RevokeMandateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *MetricsResponse) GetSuccessRatePercent() float64 {
-	if x != nil {
-		return x.SuccessRatePercent
+func (x *RevokeMandateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeMandateResponse) ProtoMessage() {}
+
+func (x *RevokeMandateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeMandateResponse.ProtoReflect.Descriptor instead.
+func (*RevokeMandateResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *MetricsResponse) GetAvgProcessingTimeMs() int64 {
+func (x *RevokeMandateResponse) GetMandate() *Mandate {
 	if x != nil {
-		return x.AvgProcessingTimeMs
+		return x.Mandate
 	}
-	return 0
+	return nil
 }
 
-func (x *MetricsResponse) GetBankHealthScores() map[string]int64 {
-	if x != nil {
-		return x.BankHealthScores
-	}
-	return nil
-}
-
-var File_proto_upi_core_proto protoreflect.FileDescriptor
-
-const file_proto_upi_core_proto_rawDesc = "" +
-	"\n" +
-	"\x14proto/upi_core.proto\x12\bupi.core\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa1\x03\n" +
-	"\x12TransactionRequest\x12%\n" +
-	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x1b\n" +
-	"\tpayer_vpa\x18\x02 \x01(\tR\bpayerVpa\x12\x1b\n" +
-	"\tpayee_vpa\x18\x03 \x01(\tR\bpayeeVpa\x12!\n" +
-	"\famount_paisa\x18\x04 \x01(\x03R\vamountPaisa\x12-\n" +
-	"\x04type\x18\x05 \x01(\x0e2\x19.upi.core.TransactionTypeR\x04type\x12\x1c\n" +
-	"\treference\x18\x06 \x01(\tR\treference\x12&\n" +
-	"\x0fpayer_bank_code\x18\a \x01(\tR\rpayerBankCode\x12&\n" +
-	"\x0fpayee_bank_code\x18\b \x01(\tR\rpayeeBankCode\x12+\n" +
-	"\x11digital_signature\x18\t \x01(\tR\x10digitalSignature\x12=\n" +
-	"\finitiated_at\x18\n" +
-	" \x01(\v2\x1a.google.protobuf.TimestampR\vinitiatedAt\"\xaa\x03\n" +
-	"\x13TransactionResponse\x12%\n" +
-	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x10\n" +
-	"\x03rrn\x18\x02 \x01(\tR\x03rrn\x123\n" +
-	"\x06status\x18\x03 \x01(\x0e2\x1b.upi.core.TransactionStatusR\x06status\x12&\n" +
-	"\x0fpayer_bank_code\x18\x04 \x01(\tR\rpayerBankCode\x12&\n" +
-	"\x0fpayee_bank_code\x18\x05 \x01(\tR\rpayeeBankCode\x12-\n" +
-	"\x04fees\x18\x06 \x01(\v2\x19.upi.core.TransactionFeesR\x04fees\x12#\n" +
-	"\rsettlement_id\x18\a \x01(\tR\fsettlementId\x12\x1d\n" +
-	"\n" +
-	"error_code\x18\b \x01(\tR\terrorCode\x12#\n" +
-	"\rerror_message\x18\t \x01(\tR\ferrorMessage\x12=\n" +
-	"\fprocessed_at\x18\n" +
-	" \x01(\v2\x1a.google.protobuf.TimestampR\vprocessedAt\"\x88\x01\n" +
-	"\x0fTransactionFees\x120\n" +
-	"\x14processing_fee_paisa\x18\x01 \x01(\x03R\x12processingFeePaisa\x12\x1b\n" +
-	"\tgst_paisa\x18\x02 \x01(\x03R\bgstPaisa\x12&\n" +
-	"\x0ftotal_fee_paisa\x18\x03 \x01(\x03R\rtotalFeePaisa\"^\n" +
-	"\x18TransactionStatusRequest\x12%\n" +
-	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x1b\n" +
-	"\tbank_code\x18\x02 \x01(\tR\bbankCode\"\x90\x03\n" +
-	"\x19TransactionStatusResponse\x12%\n" +
-	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x10\n" +
-	"\x03rrn\x18\x02 \x01(\tR\x03rrn\x123\n" +
-	"\x06status\x18\x03 \x01(\x0e2\x1b.upi.core.TransactionStatusR\x06status\x122\n" +
-	"\x06events\x18\x04 \x03(\v2\x1a.upi.core.TransactionEventR\x06events\x12!\n" +
-	"\famount_paisa\x18\x05 \x01(\x03R\vamountPaisa\x12\x1b\n" +
-	"\tpayer_vpa\x18\x06 \x01(\tR\bpayerVpa\x12\x1b\n" +
-	"\tpayee_vpa\x18\a \x01(\tR\bpayeeVpa\x129\n" +
-	"\n" +
-	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xa3\x01\n" +
-	"\x10TransactionEvent\x128\n" +
-	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x123\n" +
-	"\x06status\x18\x02 \x01(\x0e2\x1b.upi.core.TransactionStatusR\x06status\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\"\xa3\x01\n" +
-	"\x18CancelTransactionRequest\x12%\n" +
-	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x1b\n" +
-	"\tbank_code\x18\x02 \x01(\tR\bbankCode\x12\x16\n" +
-	"\x06reason\x18\x03 \x01(\tR\x06reason\x12+\n" +
-	"\x11digital_signature\x18\x04 \x01(\tR\x10digitalSignature\"\xb8\x01\n" +
-	"\x19CancelTransactionResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1d\n" +
-	"\n" +
-	"error_code\x18\x02 \x01(\tR\terrorCode\x12#\n" +
-	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\x12=\n" +
-	"\fcancelled_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\"\xed\x01\n" +
-	"\x19ReverseTransactionRequest\x126\n" +
-	"\x17original_transaction_id\x18\x01 \x01(\tR\x15originalTransactionId\x126\n" +
-	"\x17reversal_transaction_id\x18\x02 \x01(\tR\x15reversalTransactionId\x12\x1b\n" +
-	"\tbank_code\x18\x03 \x01(\tR\bbankCode\x12\x16\n" +
-	"\x06reason\x18\x04 \x01(\tR\x06reason\x12+\n" +
-	"\x11digital_signature\x18\x05 \x01(\tR\x10digitalSignature\"\xda\x01\n" +
-	"\x1aReverseTransactionResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12!\n" +
-	"\freversal_rrn\x18\x02 \x01(\tR\vreversalRrn\x12\x1d\n" +
-	"\n" +
-	"error_code\x18\x03 \x01(\tR\terrorCode\x12#\n" +
-	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\x12;\n" +
-	"\vreversed_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"reversedAt\"W\n" +
-	"\x11ResolveVPARequest\x12\x10\n" +
-	"\x03vpa\x18\x01 \x01(\tR\x03vpa\x120\n" +
-	"\x14requesting_bank_code\x18\x02 \x01(\tR\x12requestingBankCode\"\x81\x02\n" +
-	"\x12ResolveVPAResponse\x12\x16\n" +
-	"\x06exists\x18\x01 \x01(\bR\x06exists\x12\x1b\n" +
-	"\tbank_code\x18\x02 \x01(\tR\bbankCode\x12%\n" +
-	"\x0eaccount_number\x18\x03 \x01(\tR\raccountNumber\x12.\n" +
-	"\x13account_holder_name\x18\x04 \x01(\tR\x11accountHolderName\x12\x1b\n" +
-	"\tis_active\x18\x05 \x01(\bR\bisActive\x12\x1d\n" +
-	"\n" +
-	"error_code\x18\x06 \x01(\tR\terrorCode\x12#\n" +
-	"\rerror_message\x18\a \x01(\tR\ferrorMessage\"\xec\x01\n" +
-	"\x12RegisterVPARequest\x12\x10\n" +
-	"\x03vpa\x18\x01 \x01(\tR\x03vpa\x12\x1b\n" +
-	"\tbank_code\x18\x02 \x01(\tR\bbankCode\x12%\n" +
-	"\x0eaccount_number\x18\x03 \x01(\tR\raccountNumber\x12.\n" +
-	"\x13account_holder_name\x18\x04 \x01(\tR\x11accountHolderName\x12#\n" +
-	"\rmobile_number\x18\x05 \x01(\tR\fmobileNumber\x12+\n" +
-	"\x11digital_signature\x18\x06 \x01(\tR\x10digitalSignature\"\xcb\x01\n" +
-	"\x13RegisterVPAResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x15\n" +
-	"\x06vpa_id\x18\x02 \x01(\tR\x05vpaId\x12\x1d\n" +
-	"\n" +
-	"error_code\x18\x03 \x01(\tR\terrorCode\x12#\n" +
-	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\x12?\n" +
-	"\rregistered_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fregisteredAt\"\x9c\x01\n" +
-	"\x10UpdateVPARequest\x12\x10\n" +
-	"\x03vpa\x18\x01 \x01(\tR\x03vpa\x12\x1b\n" +
-	"\tbank_code\x18\x02 \x01(\tR\bbankCode\x12,\n" +
-	"\x12new_account_number\x18\x03 \x01(\tR\x10newAccountNumber\x12+\n" +
-	"\x11digital_signature\x18\x04 \x01(\tR\x10digitalSignature\"\xac\x01\n" +
-	"\x11UpdateVPAResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1d\n" +
-	"\n" +
-	"error_code\x18\x02 \x01(\tR\terrorCode\x12#\n" +
-	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\x129\n" +
-	"\n" +
-	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"r\n" +
-	"\x14DeactivateVPARequest\x12\x10\n" +
-	"\x03vpa\x18\x01 \x01(\tR\x03vpa\x12\x1b\n" +
-	"\tbank_code\x18\x02 \x01(\tR\bbankCode\x12+\n" +
-	"\x11digital_signature\x18\x03 \x01(\tR\x10digitalSignature\"\xb8\x01\n" +
-	"\x15DeactivateVPAResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1d\n" +
-	"\n" +
-	"error_code\x18\x02 \x01(\tR\terrorCode\x12#\n" +
-	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\x12A\n" +
-	"\x0edeactivated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rdeactivatedAt\"\x14\n" +
-	"\x12HealthCheckRequest\"\xaf\x02\n" +
-	"\x13HealthCheckResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\x128\n" +
-	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x18\n" +
-	"\aversion\x18\x03 \x01(\tR\aversion\x12\x16\n" +
-	"\x06uptime\x18\x04 \x01(\tR\x06uptime\x12S\n" +
-	"\fdependencies\x18\x05 \x03(\v2/.upi.core.HealthCheckResponse.DependenciesEntryR\fdependencies\x1a?\n" +
-	"\x11DependenciesEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x10\n" +
-	"\x0eMetricsRequest\"\xb5\x03\n" +
-	"\x0fMetricsResponse\x12-\n" +
-	"\x12total_transactions\x18\x01 \x01(\x03R\x11totalTransactions\x127\n" +
-	"\x17successful_transactions\x18\x02 \x01(\x03R\x16successfulTransactions\x12/\n" +
-	"\x13failed_transactions\x18\x03 \x01(\x03R\x12failedTransactions\x120\n" +
-	"\x14success_rate_percent\x18\x04 \x01(\x01R\x12successRatePercent\x123\n" +
-	"\x16avg_processing_time_ms\x18\x05 \x01(\x03R\x13avgProcessingTimeMs\x12]\n" +
-	"\x12bank_health_scores\x18\x06 \x03(\v2/.upi.core.MetricsResponse.BankHealthScoresEntryR\x10bankHealthScores\x1aC\n" +
-	"\x15BankHealthScoresEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01*\x9e\x01\n" +
-	"\x0fTransactionType\x12 \n" +
-	"\x1cTRANSACTION_TYPE_UNSPECIFIED\x10\x00\x12\x18\n" +
-	"\x14TRANSACTION_TYPE_P2P\x10\x01\x12\x18\n" +
-	"\x14TRANSACTION_TYPE_P2M\x10\x02\x12\x18\n" +
-	"\x14TRANSACTION_TYPE_M2P\x10\x03\x12\x1b\n" +
-	"\x17TRANSACTION_TYPE_REFUND\x10\x04*\xf9\x01\n" +
-	"\x11TransactionStatus\x12\"\n" +
-	"\x1eTRANSACTION_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
-	"\x1aTRANSACTION_STATUS_PENDING\x10\x01\x12\x1e\n" +
-	"\x1aTRANSACTION_STATUS_SUCCESS\x10\x02\x12\x1d\n" +
-	"\x19TRANSACTION_STATUS_FAILED\x10\x03\x12\x1e\n" +
-	"\x1aTRANSACTION_STATUS_TIMEOUT\x10\x04\x12 \n" +
-	"\x1cTRANSACTION_STATUS_CANCELLED\x10\x05\x12\x1f\n" +
-	"\x1bTRANSACTION_STATUS_REVERSED\x10\x062\xb8\x06\n" +
-	"\aUpiCore\x12Q\n" +
-	"\x12ProcessTransaction\x12\x1c.upi.core.TransactionRequest\x1a\x1d.upi.core.TransactionResponse\x12_\n" +
-	"\x14GetTransactionStatus\x12\".upi.core.TransactionStatusRequest\x1a#.upi.core.TransactionStatusResponse\x12\\\n" +
-	"\x11CancelTransaction\x12\".upi.core.CancelTransactionRequest\x1a#.upi.core.CancelTransactionResponse\x12_\n" +
-	"\x12ReverseTransaction\x12#.upi.core.ReverseTransactionRequest\x1a$.upi.core.ReverseTransactionResponse\x12G\n" +
-	"\n" +
-	"ResolveVPA\x12\x1b.upi.core.ResolveVPARequest\x1a\x1c.upi.core.ResolveVPAResponse\x12J\n" +
-	"\vRegisterVPA\x12\x1c.upi.core.RegisterVPARequest\x1a\x1d.upi.core.RegisterVPAResponse\x12D\n" +
-	"\tUpdateVPA\x12\x1a.upi.core.UpdateVPARequest\x1a\x1b.upi.core.UpdateVPAResponse\x12P\n" +
-	"\rDeactivateVPA\x12\x1e.upi.core.DeactivateVPARequest\x1a\x1f.upi.core.DeactivateVPAResponse\x12J\n" +
-	"\vHealthCheck\x12\x1c.upi.core.HealthCheckRequest\x1a\x1d.upi.core.HealthCheckResponse\x12A\n" +
-	"\n" +
-	"GetMetrics\x12\x18.upi.core.MetricsRequest\x1a\x19.upi.core.MetricsResponseB,Z*github.com/suuupra/payments/proto/upi_coreb\x06proto3"
+type GetMandateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var (
-	file_proto_upi_core_proto_rawDescOnce sync.Once
-	file_proto_upi_core_proto_rawDescData []byte
-)
+	MandateId string `protobuf:"bytes,1,opt,name=mandate_id,json=mandateId,proto3" json:"mandate_id,omitempty"`
+}
 
-func file_proto_upi_core_proto_rawDescGZIP() []byte {
-	file_proto_upi_core_proto_rawDescOnce.Do(func() {
-		file_proto_upi_core_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_upi_core_proto_rawDesc), len(file_proto_upi_core_proto_rawDesc)))
-	})
-	return file_proto_upi_core_proto_rawDescData
-}
-
-var file_proto_upi_core_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_proto_upi_core_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
-var file_proto_upi_core_proto_goTypes = []any{
-	(TransactionType)(0),               // 0: upi.core.TransactionType
-	(TransactionStatus)(0),             // 1: upi.core.TransactionStatus
-	(*TransactionRequest)(nil),         // 2: upi.core.TransactionRequest
-	(*TransactionResponse)(nil),        // 3: upi.core.TransactionResponse
-	(*TransactionFees)(nil),            // 4: upi.core.TransactionFees
-	(*TransactionStatusRequest)(nil),   // 5: upi.core.TransactionStatusRequest
-	(*TransactionStatusResponse)(nil),  // 6: upi.core.TransactionStatusResponse
-	(*TransactionEvent)(nil),           // 7: upi.core.TransactionEvent
-	(*CancelTransactionRequest)(nil),   // 8: upi.core.CancelTransactionRequest
-	(*CancelTransactionResponse)(nil),  // 9: upi.core.CancelTransactionResponse
-	(*ReverseTransactionRequest)(nil),  // 10: upi.core.ReverseTransactionRequest
-	(*ReverseTransactionResponse)(nil), // 11: upi.core.ReverseTransactionResponse
-	(*ResolveVPARequest)(nil),          // 12: upi.core.ResolveVPARequest
-	(*ResolveVPAResponse)(nil),         // 13: upi.core.ResolveVPAResponse
-	(*RegisterVPARequest)(nil),         // 14: upi.core.RegisterVPARequest
-	(*RegisterVPAResponse)(nil),        // 15: upi.core.RegisterVPAResponse
-	(*UpdateVPARequest)(nil),           // 16: upi.core.UpdateVPARequest
-	(*UpdateVPAResponse)(nil),          // 17: upi.core.UpdateVPAResponse
-	(*DeactivateVPARequest)(nil),       // 18: upi.core.DeactivateVPARequest
-	(*DeactivateVPAResponse)(nil),      // 19: upi.core.DeactivateVPAResponse
-	(*HealthCheckRequest)(nil),         // 20: upi.core.HealthCheckRequest
-	(*HealthCheckResponse)(nil),        // 21: upi.core.HealthCheckResponse
-	(*MetricsRequest)(nil),             // 22: upi.core.MetricsRequest
-	(*MetricsResponse)(nil),            // 23: upi.core.MetricsResponse
-	nil,                                // 24: upi.core.HealthCheckResponse.DependenciesEntry
-	nil,                                // 25: upi.core.MetricsResponse.BankHealthScoresEntry
-	(*timestamppb.Timestamp)(nil),      // 26: google.protobuf.Timestamp
-}
-var file_proto_upi_core_proto_depIdxs = []int32{
-	0,  // 0: upi.core.TransactionRequest.type:type_name -> upi.core.TransactionType
-	26, // 1: upi.core.TransactionRequest.initiated_at:type_name -> google.protobuf.Timestamp
-	1,  // 2: upi.core.TransactionResponse.status:type_name -> upi.core.TransactionStatus
-	4,  // 3: upi.core.TransactionResponse.fees:type_name -> upi.core.TransactionFees
-	26, // 4: upi.core.TransactionResponse.processed_at:type_name -> google.protobuf.Timestamp
-	1,  // 5: upi.core.TransactionStatusResponse.status:type_name -> upi.core.TransactionStatus
-	7,  // 6: upi.core.TransactionStatusResponse.events:type_name -> upi.core.TransactionEvent
-	26, // 7: upi.core.TransactionStatusResponse.created_at:type_name -> google.protobuf.Timestamp
-	26, // 8: upi.core.TransactionStatusResponse.updated_at:type_name -> google.protobuf.Timestamp
-	26, // 9: upi.core.TransactionEvent.timestamp:type_name -> google.protobuf.Timestamp
-	1,  // 10: upi.core.TransactionEvent.status:type_name -> upi.core.TransactionStatus
-	26, // 11: upi.core.CancelTransactionResponse.cancelled_at:type_name -> google.protobuf.Timestamp
-	26, // 12: upi.core.ReverseTransactionResponse.reversed_at:type_name -> google.protobuf.Timestamp
-	26, // 13: upi.core.RegisterVPAResponse.registered_at:type_name -> google.protobuf.Timestamp
-	26, // 14: upi.core.UpdateVPAResponse.updated_at:type_name -> google.protobuf.Timestamp
-	26, // 15: upi.core.DeactivateVPAResponse.deactivated_at:type_name -> google.protobuf.Timestamp
-	26, // 16: upi.core.HealthCheckResponse.timestamp:type_name -> google.protobuf.Timestamp
-	24, // 17: upi.core.HealthCheckResponse.dependencies:type_name -> upi.core.HealthCheckResponse.DependenciesEntry
-	25, // 18: upi.core.MetricsResponse.bank_health_scores:type_name -> upi.core.MetricsResponse.BankHealthScoresEntry
-	2,  // 19: upi.core.UpiCore.ProcessTransaction:input_type -> upi.core.TransactionRequest
-	5,  // 20: upi.core.UpiCore.GetTransactionStatus:input_type -> upi.core.TransactionStatusRequest
-	8,  // 21: upi.core.UpiCore.CancelTransaction:input_type -> upi.core.CancelTransactionRequest
-	10, // 22: upi.core.UpiCore.ReverseTransaction:input_type -> upi.core.ReverseTransactionRequest
-	12, // 23: upi.core.UpiCore.ResolveVPA:input_type -> upi.core.ResolveVPARequest
-	14, // 24: upi.core.UpiCore.RegisterVPA:input_type -> upi.core.RegisterVPARequest
-	16, // 25: upi.core.UpiCore.UpdateVPA:input_type -> upi.core.UpdateVPARequest
-	18, // 26: upi.core.UpiCore.DeactivateVPA:input_type -> upi.core.DeactivateVPARequest
-	20, // 27: upi.core.UpiCore.HealthCheck:input_type -> upi.core.HealthCheckRequest
-	22, // 28: upi.core.UpiCore.GetMetrics:input_type -> upi.core.MetricsRequest
-	3,  // 29: upi.core.UpiCore.ProcessTransaction:output_type -> upi.core.TransactionResponse
-	6,  // 30: upi.core.UpiCore.GetTransactionStatus:output_type -> upi.core.TransactionStatusResponse
-	9,  // 31: upi.core.UpiCore.CancelTransaction:output_type -> upi.core.CancelTransactionResponse
-	11, // 32: upi.core.UpiCore.ReverseTransaction:output_type -> upi.core.ReverseTransactionResponse
-	13, // 33: upi.core.UpiCore.ResolveVPA:output_type -> upi.core.ResolveVPAResponse
-	15, // 34: upi.core.UpiCore.RegisterVPA:output_type -> upi.core.RegisterVPAResponse
-	17, // 35: upi.core.UpiCore.UpdateVPA:output_type -> upi.core.UpdateVPAResponse
-	19, // 36: upi.core.UpiCore.DeactivateVPA:output_type -> upi.core.DeactivateVPAResponse
-	21, // 37: upi.core.UpiCore.HealthCheck:output_type -> upi.core.HealthCheckResponse
-	23, // 38: upi.core.UpiCore.GetMetrics:output_type -> upi.core.MetricsResponse
-	29, // [29:39] is the sub-list for method output_type
-	19, // [19:29] is the sub-list for method input_type
-	19, // [19:19] is the sub-list for extension type_name
-	19, // [19:19] is the sub-list for extension extendee
-	0,  // [0:19] is the sub-list for field type_name
-}
-
-func init() { file_proto_upi_core_proto_init() }
-func file_proto_upi_core_proto_init() {
-	if File_proto_upi_core_proto != nil {
-		return
+func (x *GetMandateRequest) Reset() {
+	*x = GetMandateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMandateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMandateRequest) ProtoMessage() {}
+
+func (x *GetMandateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMandateRequest.ProtoReflect.Descriptor instead.
+func (*GetMandateRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetMandateRequest) GetMandateId() string {
+	if x != nil {
+		return x.MandateId
+	}
+	return ""
+}
+
+type GetMandateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mandate    *Mandate            `protobuf:"bytes,1,opt,name=mandate,proto3" json:"mandate,omitempty"`
+	Executions []*MandateExecution `protobuf:"bytes,2,rep,name=executions,proto3" json:"executions,omitempty"` // Latest first
+}
+
+func (x *GetMandateResponse) Reset() {
+	*x = GetMandateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetMandateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMandateResponse) ProtoMessage() {}
+
+func (x *GetMandateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMandateResponse.ProtoReflect.Descriptor instead.
+func (*GetMandateResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetMandateResponse) GetMandate() *Mandate {
+	if x != nil {
+		return x.Mandate
+	}
+	return nil
+}
+
+func (x *GetMandateResponse) GetExecutions() []*MandateExecution {
+	if x != nil {
+		return x.Executions
+	}
+	return nil
+}
+
+// Collect Messages
+type CreateCollectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CollectId        string          `protobuf:"bytes,1,opt,name=collect_id,json=collectId,proto3" json:"collect_id,omitempty"` // Chosen by the payee's PSP
+	PayeeVpa         string          `protobuf:"bytes,2,opt,name=payee_vpa,json=payeeVpa,proto3" json:"payee_vpa,omitempty"`
+	PayerVpa         string          `protobuf:"bytes,3,opt,name=payer_vpa,json=payerVpa,proto3" json:"payer_vpa,omitempty"`
+	AmountPaisa      int64           `protobuf:"varint,4,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`
+	Type             TransactionType `protobuf:"varint,5,opt,name=type,proto3,enum=upi_core.TransactionType" json:"type,omitempty"` // P2P or P2M, P2P if unspecified
+	Description      string          `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	ExpiresInSeconds int64           `protobuf:"varint,7,opt,name=expires_in_seconds,json=expiresInSeconds,proto3" json:"expires_in_seconds,omitempty"` // 0 for the default expiry
+}
+
+func (x *CreateCollectRequest) Reset() {
+	*x = CreateCollectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateCollectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCollectRequest) ProtoMessage() {}
+
+func (x *CreateCollectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCollectRequest.ProtoReflect.Descriptor instead.
+func (*CreateCollectRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CreateCollectRequest) GetCollectId() string {
+	if x != nil {
+		return x.CollectId
+	}
+	return ""
+}
+
+func (x *CreateCollectRequest) GetPayeeVpa() string {
+	if x != nil {
+		return x.PayeeVpa
+	}
+	return ""
+}
+
+func (x *CreateCollectRequest) GetPayerVpa() string {
+	if x != nil {
+		return x.PayerVpa
+	}
+	return ""
+}
+
+func (x *CreateCollectRequest) GetAmountPaisa() int64 {
+	if x != nil {
+		return x.AmountPaisa
+	}
+	return 0
+}
+
+func (x *CreateCollectRequest) GetType() TransactionType {
+	if x != nil {
+		return x.Type
+	}
+	return TransactionType_TRANSACTION_TYPE_UNSPECIFIED
+}
+
+func (x *CreateCollectRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateCollectRequest) GetExpiresInSeconds() int64 {
+	if x != nil {
+		return x.ExpiresInSeconds
+	}
+	return 0
+}
+
+type CreateCollectResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Collect *Collect `protobuf:"bytes,1,opt,name=collect,proto3" json:"collect,omitempty"`
+}
+
+func (x *CreateCollectResponse) Reset() {
+	*x = CreateCollectResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateCollectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCollectResponse) ProtoMessage() {}
+
+func (x *CreateCollectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCollectResponse.ProtoReflect.Descriptor instead.
+func (*CreateCollectResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateCollectResponse) GetCollect() *Collect {
+	if x != nil {
+		return x.Collect
+	}
+	return nil
+}
+
+type RespondCollectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CollectId string `protobuf:"bytes,1,opt,name=collect_id,json=collectId,proto3" json:"collect_id,omitempty"`
+	Approve   bool   `protobuf:"varint,2,opt,name=approve,proto3" json:"approve,omitempty"`
+	Reason    string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Why it was declined
+	// Payer bank's signature of the transaction paying the request, whose
+	// transaction_id and reference are the collect ID
+	Signature   string                 `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	InitiatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=initiated_at,json=initiatedAt,proto3" json:"initiated_at,omitempty"`
+}
+
+func (x *RespondCollectRequest) Reset() {
+	*x = RespondCollectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RespondCollectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondCollectRequest) ProtoMessage() {}
+
+func (x *RespondCollectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondCollectRequest.ProtoReflect.Descriptor instead.
+func (*RespondCollectRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RespondCollectRequest) GetCollectId() string {
+	if x != nil {
+		return x.CollectId
+	}
+	return ""
+}
+
+func (x *RespondCollectRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+func (x *RespondCollectRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RespondCollectRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *RespondCollectRequest) GetInitiatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InitiatedAt
+	}
+	return nil
+}
+
+type RespondCollectResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Collect     *Collect             `protobuf:"bytes,1,opt,name=collect,proto3" json:"collect,omitempty"`
+	Transaction *TransactionResponse `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"` // Set if approved
+}
+
+func (x *RespondCollectResponse) Reset() {
+	*x = RespondCollectResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RespondCollectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondCollectResponse) ProtoMessage() {}
+
+func (x *RespondCollectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondCollectResponse.ProtoReflect.Descriptor instead.
+func (*RespondCollectResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RespondCollectResponse) GetCollect() *Collect {
+	if x != nil {
+		return x.Collect
+	}
+	return nil
+}
+
+func (x *RespondCollectResponse) GetTransaction() *TransactionResponse {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type GetCollectRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CollectId string `protobuf:"bytes,1,opt,name=collect_id,json=collectId,proto3" json:"collect_id,omitempty"`
+}
+
+func (x *GetCollectRequest) Reset() {
+	*x = GetCollectRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCollectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCollectRequest) ProtoMessage() {}
+
+func (x *GetCollectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCollectRequest.ProtoReflect.Descriptor instead.
+func (*GetCollectRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetCollectRequest) GetCollectId() string {
+	if x != nil {
+		return x.CollectId
+	}
+	return ""
+}
+
+type GetCollectResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Collect *Collect `protobuf:"bytes,1,opt,name=collect,proto3" json:"collect,omitempty"`
+}
+
+func (x *GetCollectResponse) Reset() {
+	*x = GetCollectResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCollectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCollectResponse) ProtoMessage() {}
+
+func (x *GetCollectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCollectResponse.ProtoReflect.Descriptor instead.
+func (*GetCollectResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetCollectResponse) GetCollect() *Collect {
+	if x != nil {
+		return x.Collect
+	}
+	return nil
+}
+
+// Fee Messages
+type CreateFeeRuleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rule *FeeRule `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"` // rule_id is assigned
+}
+
+func (x *CreateFeeRuleRequest) Reset() {
+	*x = CreateFeeRuleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFeeRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFeeRuleRequest) ProtoMessage() {}
+
+func (x *CreateFeeRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFeeRuleRequest.ProtoReflect.Descriptor instead.
+func (*CreateFeeRuleRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CreateFeeRuleRequest) GetRule() *FeeRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type CreateFeeRuleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rule *FeeRule `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+}
+
+func (x *CreateFeeRuleResponse) Reset() {
+	*x = CreateFeeRuleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFeeRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFeeRuleResponse) ProtoMessage() {}
+
+func (x *CreateFeeRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFeeRuleResponse.ProtoReflect.Descriptor instead.
+func (*CreateFeeRuleResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CreateFeeRuleResponse) GetRule() *FeeRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type UpdateFeeRuleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rule *FeeRule `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"` // Replaces every field of the rule with rule_id
+}
+
+func (x *UpdateFeeRuleRequest) Reset() {
+	*x = UpdateFeeRuleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFeeRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFeeRuleRequest) ProtoMessage() {}
+
+func (x *UpdateFeeRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFeeRuleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFeeRuleRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *UpdateFeeRuleRequest) GetRule() *FeeRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type UpdateFeeRuleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rule *FeeRule `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+}
+
+func (x *UpdateFeeRuleResponse) Reset() {
+	*x = UpdateFeeRuleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFeeRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFeeRuleResponse) ProtoMessage() {}
+
+func (x *UpdateFeeRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFeeRuleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFeeRuleResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UpdateFeeRuleResponse) GetRule() *FeeRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type DeleteFeeRuleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuleId int64 `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+}
+
+func (x *DeleteFeeRuleRequest) Reset() {
+	*x = DeleteFeeRuleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteFeeRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFeeRuleRequest) ProtoMessage() {}
+
+func (x *DeleteFeeRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFeeRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFeeRuleRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DeleteFeeRuleRequest) GetRuleId() int64 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+type DeleteFeeRuleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteFeeRuleResponse) Reset() {
+	*x = DeleteFeeRuleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteFeeRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFeeRuleResponse) ProtoMessage() {}
+
+func (x *DeleteFeeRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFeeRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFeeRuleResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{37}
+}
+
+type ListFeeRulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListFeeRulesRequest) Reset() {
+	*x = ListFeeRulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFeeRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeeRulesRequest) ProtoMessage() {}
+
+func (x *ListFeeRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeeRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListFeeRulesRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{38}
+}
+
+type ListFeeRulesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rules []*FeeRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *ListFeeRulesResponse) Reset() {
+	*x = ListFeeRulesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFeeRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeeRulesResponse) ProtoMessage() {}
+
+func (x *ListFeeRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeeRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListFeeRulesResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListFeeRulesResponse) GetRules() []*FeeRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// Reconciliation Messages
+type RunReconciliationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BusinessDate string `protobuf:"bytes,1,opt,name=business_date,json=businessDate,proto3" json:"business_date,omitempty"` // YYYY-MM-DD format, a day that has ended
+}
+
+func (x *RunReconciliationRequest) Reset() {
+	*x = RunReconciliationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunReconciliationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunReconciliationRequest) ProtoMessage() {}
+
+func (x *RunReconciliationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunReconciliationRequest.ProtoReflect.Descriptor instead.
+func (*RunReconciliationRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RunReconciliationRequest) GetBusinessDate() string {
+	if x != nil {
+		return x.BusinessDate
+	}
+	return ""
+}
+
+type RunReconciliationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Run *ReconciliationRun `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+}
+
+func (x *RunReconciliationResponse) Reset() {
+	*x = RunReconciliationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunReconciliationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunReconciliationResponse) ProtoMessage() {}
+
+func (x *RunReconciliationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunReconciliationResponse.ProtoReflect.Descriptor instead.
+func (*RunReconciliationResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RunReconciliationResponse) GetRun() *ReconciliationRun {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+type ReconciliationReportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BusinessDate string `protobuf:"bytes,1,opt,name=business_date,json=businessDate,proto3" json:"business_date,omitempty"` // YYYY-MM-DD format
+}
+
+func (x *ReconciliationReportRequest) Reset() {
+	*x = ReconciliationReportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconciliationReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationReportRequest) ProtoMessage() {}
+
+func (x *ReconciliationReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationReportRequest.ProtoReflect.Descriptor instead.
+func (*ReconciliationReportRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ReconciliationReportRequest) GetBusinessDate() string {
+	if x != nil {
+		return x.BusinessDate
+	}
+	return ""
+}
+
+type ReconciliationReportResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Run        *ReconciliationRun         `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	Exceptions []*ReconciliationException `protobuf:"bytes,2,rep,name=exceptions,proto3" json:"exceptions,omitempty"`
+}
+
+func (x *ReconciliationReportResponse) Reset() {
+	*x = ReconciliationReportResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconciliationReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationReportResponse) ProtoMessage() {}
+
+func (x *ReconciliationReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationReportResponse.ProtoReflect.Descriptor instead.
+func (*ReconciliationReportResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ReconciliationReportResponse) GetRun() *ReconciliationRun {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+func (x *ReconciliationReportResponse) GetExceptions() []*ReconciliationException {
+	if x != nil {
+		return x.Exceptions
+	}
+	return nil
+}
+
+// Bank Messages
+type RegisterBankRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode          string   `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	BankName          string   `protobuf:"bytes,2,opt,name=bank_name,json=bankName,proto3" json:"bank_name,omitempty"`
+	IfscPrefix        string   `protobuf:"bytes,3,opt,name=ifsc_prefix,json=ifscPrefix,proto3" json:"ifsc_prefix,omitempty"`
+	EndpointUrl       string   `protobuf:"bytes,4,opt,name=endpoint_url,json=endpointUrl,proto3" json:"endpoint_url,omitempty"`
+	PublicKey         string   `protobuf:"bytes,5,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	SupportedFeatures []string `protobuf:"bytes,6,rep,name=supported_features,json=supportedFeatures,proto3" json:"supported_features,omitempty"`
+	CallbackUrl       string   `protobuf:"bytes,7,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"` // Where its PSPs are sent collect requests
+}
+
+func (x *RegisterBankRequest) Reset() {
+	*x = RegisterBankRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterBankRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterBankRequest) ProtoMessage() {}
+
+func (x *RegisterBankRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterBankRequest.ProtoReflect.Descriptor instead.
+func (*RegisterBankRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *RegisterBankRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *RegisterBankRequest) GetBankName() string {
+	if x != nil {
+		return x.BankName
+	}
+	return ""
+}
+
+func (x *RegisterBankRequest) GetIfscPrefix() string {
+	if x != nil {
+		return x.IfscPrefix
+	}
+	return ""
+}
+
+func (x *RegisterBankRequest) GetEndpointUrl() string {
+	if x != nil {
+		return x.EndpointUrl
+	}
+	return ""
+}
+
+func (x *RegisterBankRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *RegisterBankRequest) GetSupportedFeatures() []string {
+	if x != nil {
+		return x.SupportedFeatures
+	}
+	return nil
+}
+
+func (x *RegisterBankRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+type RegisterBankResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success      bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	BankId       string                 `protobuf:"bytes,2,opt,name=bank_id,json=bankId,proto3" json:"bank_id,omitempty"`
+	ErrorCode    string                 `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	RegisteredAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
+}
+
+func (x *RegisterBankResponse) Reset() {
+	*x = RegisterBankResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterBankResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterBankResponse) ProtoMessage() {}
+
+func (x *RegisterBankResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterBankResponse.ProtoReflect.Descriptor instead.
+func (*RegisterBankResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RegisterBankResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterBankResponse) GetBankId() string {
+	if x != nil {
+		return x.BankId
+	}
+	return ""
+}
+
+func (x *RegisterBankResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *RegisterBankResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *RegisterBankResponse) GetRegisteredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return nil
+}
+
+type UpdateBankStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode string     `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	Status   BankStatus `protobuf:"varint,2,opt,name=status,proto3,enum=upi_core.BankStatus" json:"status,omitempty"`
+	Reason   string     `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *UpdateBankStatusRequest) Reset() {
+	*x = UpdateBankStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateBankStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBankStatusRequest) ProtoMessage() {}
+
+func (x *UpdateBankStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBankStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBankStatusRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *UpdateBankStatusRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *UpdateBankStatusRequest) GetStatus() BankStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BankStatus_BANK_STATUS_UNSPECIFIED
+}
+
+func (x *UpdateBankStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type UpdateBankStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success      bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorCode    string                 `protobuf:"bytes,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage string                 `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *UpdateBankStatusResponse) Reset() {
+	*x = UpdateBankStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateBankStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBankStatusResponse) ProtoMessage() {}
+
+func (x *UpdateBankStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBankStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateBankStatusResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *UpdateBankStatusResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateBankStatusResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *UpdateBankStatusResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *UpdateBankStatusResponse) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type BankStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode string `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+}
+
+func (x *BankStatusRequest) Reset() {
+	*x = BankStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankStatusRequest) ProtoMessage() {}
+
+func (x *BankStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankStatusRequest.ProtoReflect.Descriptor instead.
+func (*BankStatusRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *BankStatusRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+type BankStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode           string                 `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	BankName           string                 `protobuf:"bytes,2,opt,name=bank_name,json=bankName,proto3" json:"bank_name,omitempty"`
+	Status             BankStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=upi_core.BankStatus" json:"status,omitempty"`
+	SuccessRatePercent int32                  `protobuf:"varint,4,opt,name=success_rate_percent,json=successRatePercent,proto3" json:"success_rate_percent,omitempty"`
+	AvgResponseTimeMs  int32                  `protobuf:"varint,5,opt,name=avg_response_time_ms,json=avgResponseTimeMs,proto3" json:"avg_response_time_ms,omitempty"`
+	LastHeartbeat      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_heartbeat,json=lastHeartbeat,proto3" json:"last_heartbeat,omitempty"`
+	SupportedFeatures  []string               `protobuf:"bytes,7,rep,name=supported_features,json=supportedFeatures,proto3" json:"supported_features,omitempty"`
+}
+
+func (x *BankStatusResponse) Reset() {
+	*x = BankStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankStatusResponse) ProtoMessage() {}
+
+func (x *BankStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankStatusResponse.ProtoReflect.Descriptor instead.
+func (*BankStatusResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *BankStatusResponse) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *BankStatusResponse) GetBankName() string {
+	if x != nil {
+		return x.BankName
+	}
+	return ""
+}
+
+func (x *BankStatusResponse) GetStatus() BankStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BankStatus_BANK_STATUS_UNSPECIFIED
+}
+
+func (x *BankStatusResponse) GetSuccessRatePercent() int32 {
+	if x != nil {
+		return x.SuccessRatePercent
+	}
+	return 0
+}
+
+func (x *BankStatusResponse) GetAvgResponseTimeMs() int32 {
+	if x != nil {
+		return x.AvgResponseTimeMs
+	}
+	return 0
+}
+
+func (x *BankStatusResponse) GetLastHeartbeat() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastHeartbeat
+	}
+	return nil
+}
+
+func (x *BankStatusResponse) GetSupportedFeatures() []string {
+	if x != nil {
+		return x.SupportedFeatures
+	}
+	return nil
+}
+
+type ListBanksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StatusFilter BankStatus `protobuf:"varint,1,opt,name=status_filter,json=statusFilter,proto3,enum=upi_core.BankStatus" json:"status_filter,omitempty"`
+	PageSize     int32      `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken    string     `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (x *ListBanksRequest) Reset() {
+	*x = ListBanksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBanksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBanksRequest) ProtoMessage() {}
+
+func (x *ListBanksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBanksRequest.ProtoReflect.Descriptor instead.
+func (*ListBanksRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListBanksRequest) GetStatusFilter() BankStatus {
+	if x != nil {
+		return x.StatusFilter
+	}
+	return BankStatus_BANK_STATUS_UNSPECIFIED
+}
+
+func (x *ListBanksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListBanksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListBanksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Banks         []*BankInfo `protobuf:"bytes,1,rep,name=banks,proto3" json:"banks,omitempty"`
+	NextPageToken string      `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalCount    int32       `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+}
+
+func (x *ListBanksResponse) Reset() {
+	*x = ListBanksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBanksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBanksResponse) ProtoMessage() {}
+
+func (x *ListBanksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBanksResponse.ProtoReflect.Descriptor instead.
+func (*ListBanksResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ListBanksResponse) GetBanks() []*BankInfo {
+	if x != nil {
+		return x.Banks
+	}
+	return nil
+}
+
+func (x *ListBanksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListBanksResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type BankAdminResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bank *BankInfo `protobuf:"bytes,1,opt,name=bank,proto3" json:"bank,omitempty"`
+}
+
+func (x *BankAdminResponse) Reset() {
+	*x = BankAdminResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankAdminResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankAdminResponse) ProtoMessage() {}
+
+func (x *BankAdminResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankAdminResponse.ProtoReflect.Descriptor instead.
+func (*BankAdminResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *BankAdminResponse) GetBank() *BankInfo {
+	if x != nil {
+		return x.Bank
+	}
+	return nil
+}
+
+type ApproveBankRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode string `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+}
+
+func (x *ApproveBankRequest) Reset() {
+	*x = ApproveBankRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApproveBankRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveBankRequest) ProtoMessage() {}
+
+func (x *ApproveBankRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveBankRequest.ProtoReflect.Descriptor instead.
+func (*ApproveBankRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ApproveBankRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+type RejectBankRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode string `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	Reason   string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *RejectBankRequest) Reset() {
+	*x = RejectBankRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RejectBankRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectBankRequest) ProtoMessage() {}
+
+func (x *RejectBankRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectBankRequest.ProtoReflect.Descriptor instead.
+func (*RejectBankRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *RejectBankRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *RejectBankRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type BankStatusChangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode string `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	Reason   string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // Required to suspend
+}
+
+func (x *BankStatusChangeRequest) Reset() {
+	*x = BankStatusChangeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankStatusChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankStatusChangeRequest) ProtoMessage() {}
+
+func (x *BankStatusChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankStatusChangeRequest.ProtoReflect.Descriptor instead.
+func (*BankStatusChangeRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *BankStatusChangeRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *BankStatusChangeRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ScheduleMaintenanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode string                 `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	StartsAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	Reason   string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *ScheduleMaintenanceRequest) Reset() {
+	*x = ScheduleMaintenanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScheduleMaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMaintenanceRequest) ProtoMessage() {}
+
+func (x *ScheduleMaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ScheduleMaintenanceRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *ScheduleMaintenanceRequest) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *ScheduleMaintenanceRequest) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *ScheduleMaintenanceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CancelMaintenanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WindowId int64 `protobuf:"varint,1,opt,name=window_id,json=windowId,proto3" json:"window_id,omitempty"`
+}
+
+func (x *CancelMaintenanceRequest) Reset() {
+	*x = CancelMaintenanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelMaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelMaintenanceRequest) ProtoMessage() {}
+
+func (x *CancelMaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelMaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*CancelMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *CancelMaintenanceRequest) GetWindowId() int64 {
+	if x != nil {
+		return x.WindowId
+	}
+	return 0
+}
+
+type ListMaintenanceWindowsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode string `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+}
+
+func (x *ListMaintenanceWindowsRequest) Reset() {
+	*x = ListMaintenanceWindowsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListMaintenanceWindowsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMaintenanceWindowsRequest) ProtoMessage() {}
+
+func (x *ListMaintenanceWindowsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMaintenanceWindowsRequest.ProtoReflect.Descriptor instead.
+func (*ListMaintenanceWindowsRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListMaintenanceWindowsRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+type ListMaintenanceWindowsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Windows []*MaintenanceWindow `protobuf:"bytes,1,rep,name=windows,proto3" json:"windows,omitempty"` // Under way or to come, earliest first
+}
+
+func (x *ListMaintenanceWindowsResponse) Reset() {
+	*x = ListMaintenanceWindowsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListMaintenanceWindowsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMaintenanceWindowsResponse) ProtoMessage() {}
+
+func (x *ListMaintenanceWindowsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMaintenanceWindowsResponse.ProtoReflect.Descriptor instead.
+func (*ListMaintenanceWindowsResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ListMaintenanceWindowsResponse) GetWindows() []*MaintenanceWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+type MaintenanceWindow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WindowId    int64                  `protobuf:"varint,1,opt,name=window_id,json=windowId,proto3" json:"window_id,omitempty"`
+	BankCode    string                 `protobuf:"bytes,2,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	StartsAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	Reason      string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedBy   string                 `protobuf:"bytes,6,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CancelledAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+}
+
+func (x *MaintenanceWindow) Reset() {
+	*x = MaintenanceWindow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaintenanceWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceWindow) ProtoMessage() {}
+
+func (x *MaintenanceWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceWindow.ProtoReflect.Descriptor instead.
+func (*MaintenanceWindow) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *MaintenanceWindow) GetWindowId() int64 {
+	if x != nil {
+		return x.WindowId
+	}
+	return 0
+}
+
+func (x *MaintenanceWindow) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *MaintenanceWindow) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *MaintenanceWindow) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *MaintenanceWindow) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *MaintenanceWindow) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *MaintenanceWindow) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+type GetBankRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode string `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+}
+
+func (x *GetBankRequest) Reset() {
+	*x = GetBankRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBankRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBankRequest) ProtoMessage() {}
+
+func (x *GetBankRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBankRequest.ProtoReflect.Descriptor instead.
+func (*GetBankRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetBankRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+// Settlement Messages
+type InitiateSettlementRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchId        string                 `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	BankCodes      []string               `protobuf:"bytes,2,rep,name=bank_codes,json=bankCodes,proto3" json:"bank_codes,omitempty"`
+	SettlementDate *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=settlement_date,json=settlementDate,proto3" json:"settlement_date,omitempty"`
+}
+
+func (x *InitiateSettlementRequest) Reset() {
+	*x = InitiateSettlementRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InitiateSettlementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateSettlementRequest) ProtoMessage() {}
+
+func (x *InitiateSettlementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateSettlementRequest.ProtoReflect.Descriptor instead.
+func (*InitiateSettlementRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *InitiateSettlementRequest) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *InitiateSettlementRequest) GetBankCodes() []string {
+	if x != nil {
+		return x.BankCodes
+	}
+	return nil
+}
+
+func (x *InitiateSettlementRequest) GetSettlementDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SettlementDate
+	}
+	return nil
+}
+
+type InitiateSettlementResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success      bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	SettlementId string                 `protobuf:"bytes,2,opt,name=settlement_id,json=settlementId,proto3" json:"settlement_id,omitempty"`
+	ErrorCode    string                 `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage string                 `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	InitiatedAt  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=initiated_at,json=initiatedAt,proto3" json:"initiated_at,omitempty"`
+}
+
+func (x *InitiateSettlementResponse) Reset() {
+	*x = InitiateSettlementResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InitiateSettlementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateSettlementResponse) ProtoMessage() {}
+
+func (x *InitiateSettlementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateSettlementResponse.ProtoReflect.Descriptor instead.
+func (*InitiateSettlementResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *InitiateSettlementResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *InitiateSettlementResponse) GetSettlementId() string {
+	if x != nil {
+		return x.SettlementId
+	}
+	return ""
+}
+
+func (x *InitiateSettlementResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *InitiateSettlementResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *InitiateSettlementResponse) GetInitiatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InitiatedAt
+	}
+	return nil
+}
+
+type SettlementStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SettlementId string `protobuf:"bytes,1,opt,name=settlement_id,json=settlementId,proto3" json:"settlement_id,omitempty"`
+}
+
+func (x *SettlementStatusRequest) Reset() {
+	*x = SettlementStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SettlementStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettlementStatusRequest) ProtoMessage() {}
+
+func (x *SettlementStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettlementStatusRequest.ProtoReflect.Descriptor instead.
+func (*SettlementStatusRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *SettlementStatusRequest) GetSettlementId() string {
+	if x != nil {
+		return x.SettlementId
+	}
+	return ""
+}
+
+type SettlementStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SettlementId    string                 `protobuf:"bytes,1,opt,name=settlement_id,json=settlementId,proto3" json:"settlement_id,omitempty"`
+	Status          SettlementStatus       `protobuf:"varint,2,opt,name=status,proto3,enum=upi_core.SettlementStatus" json:"status,omitempty"`
+	BankSettlements []*BankSettlement      `protobuf:"bytes,3,rep,name=bank_settlements,json=bankSettlements,proto3" json:"bank_settlements,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CompletedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+}
+
+func (x *SettlementStatusResponse) Reset() {
+	*x = SettlementStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SettlementStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettlementStatusResponse) ProtoMessage() {}
+
+func (x *SettlementStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettlementStatusResponse.ProtoReflect.Descriptor instead.
+func (*SettlementStatusResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *SettlementStatusResponse) GetSettlementId() string {
+	if x != nil {
+		return x.SettlementId
+	}
+	return ""
+}
+
+func (x *SettlementStatusResponse) GetStatus() SettlementStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SettlementStatus_SETTLEMENT_STATUS_UNSPECIFIED
+}
+
+func (x *SettlementStatusResponse) GetBankSettlements() []*BankSettlement {
+	if x != nil {
+		return x.BankSettlements
+	}
+	return nil
+}
+
+func (x *SettlementStatusResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SettlementStatusResponse) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+type SettlementReportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode  string                 `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	FromDate  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"`
+	ToDate    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`
+	PageSize  int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Records per page, at most and by default 100
+	PageToken string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page
+}
+
+func (x *SettlementReportRequest) Reset() {
+	*x = SettlementReportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SettlementReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettlementReportRequest) ProtoMessage() {}
+
+func (x *SettlementReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettlementReportRequest.ProtoReflect.Descriptor instead.
+func (*SettlementReportRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *SettlementReportRequest) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *SettlementReportRequest) GetFromDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FromDate
+	}
+	return nil
+}
+
+func (x *SettlementReportRequest) GetToDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ToDate
+	}
+	return nil
+}
+
+func (x *SettlementReportRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SettlementReportRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type SettlementReportResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode           string              `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	TotalCreditPaisa   int64               `protobuf:"varint,2,opt,name=total_credit_paisa,json=totalCreditPaisa,proto3" json:"total_credit_paisa,omitempty"`
+	TotalDebitPaisa    int64               `protobuf:"varint,3,opt,name=total_debit_paisa,json=totalDebitPaisa,proto3" json:"total_debit_paisa,omitempty"`
+	NetSettlementPaisa int64               `protobuf:"varint,4,opt,name=net_settlement_paisa,json=netSettlementPaisa,proto3" json:"net_settlement_paisa,omitempty"`
+	TransactionCount   int32               `protobuf:"varint,5,opt,name=transaction_count,json=transactionCount,proto3" json:"transaction_count,omitempty"`
+	DailySettlements   []*DailySettlement  `protobuf:"bytes,6,rep,name=daily_settlements,json=dailySettlements,proto3" json:"daily_settlements,omitempty"`
+	Records            []*SettlementRecord `protobuf:"bytes,7,rep,name=records,proto3" json:"records,omitempty"`                                    // The bank's successful transactions, a page at a time
+	NextPageToken      string              `protobuf:"bytes,8,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+}
+
+func (x *SettlementReportResponse) Reset() {
+	*x = SettlementReportResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SettlementReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettlementReportResponse) ProtoMessage() {}
+
+func (x *SettlementReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettlementReportResponse.ProtoReflect.Descriptor instead.
+func (*SettlementReportResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *SettlementReportResponse) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *SettlementReportResponse) GetTotalCreditPaisa() int64 {
+	if x != nil {
+		return x.TotalCreditPaisa
+	}
+	return 0
+}
+
+func (x *SettlementReportResponse) GetTotalDebitPaisa() int64 {
+	if x != nil {
+		return x.TotalDebitPaisa
+	}
+	return 0
+}
+
+func (x *SettlementReportResponse) GetNetSettlementPaisa() int64 {
+	if x != nil {
+		return x.NetSettlementPaisa
+	}
+	return 0
+}
+
+func (x *SettlementReportResponse) GetTransactionCount() int32 {
+	if x != nil {
+		return x.TransactionCount
+	}
+	return 0
+}
+
+func (x *SettlementReportResponse) GetDailySettlements() []*DailySettlement {
+	if x != nil {
+		return x.DailySettlements
+	}
+	return nil
+}
+
+func (x *SettlementReportResponse) GetRecords() []*SettlementRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *SettlementReportResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Health and Monitoring Messages
+type HealthCheckRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Service string `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+}
+
+func (x *HealthCheckRequest) Reset() {
+	*x = HealthCheckRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HealthCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckRequest) ProtoMessage() {}
+
+func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
+func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *HealthCheckRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+type HealthCheckResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status    HealthStatus           `protobuf:"varint,1,opt,name=status,proto3,enum=upi_core.HealthStatus" json:"status,omitempty"`
+	Details   map[string]string      `protobuf:"bytes,2,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *HealthCheckResponse) Reset() {
+	*x = HealthCheckResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HealthCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResponse) ProtoMessage() {}
+
+func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
+func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *HealthCheckResponse) GetStatus() HealthStatus {
+	if x != nil {
+		return x.Status
+	}
+	return HealthStatus_HEALTH_STATUS_UNSPECIFIED
+}
+
+func (x *HealthCheckResponse) GetDetails() map[string]string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *HealthCheckResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type MetricsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MetricNames []string               `protobuf:"bytes,1,rep,name=metric_names,json=metricNames,proto3" json:"metric_names,omitempty"`
+	FromTime    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from_time,json=fromTime,proto3" json:"from_time,omitempty"`
+	ToTime      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to_time,json=toTime,proto3" json:"to_time,omitempty"`
+}
+
+func (x *MetricsRequest) Reset() {
+	*x = MetricsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsRequest) ProtoMessage() {}
+
+func (x *MetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsRequest.ProtoReflect.Descriptor instead.
+func (*MetricsRequest) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *MetricsRequest) GetMetricNames() []string {
+	if x != nil {
+		return x.MetricNames
+	}
+	return nil
+}
+
+func (x *MetricsRequest) GetFromTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FromTime
+	}
+	return nil
+}
+
+func (x *MetricsRequest) GetToTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ToTime
+	}
+	return nil
+}
+
+type MetricsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metrics     []*Metric              `protobuf:"bytes,1,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	GeneratedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+}
+
+func (x *MetricsResponse) Reset() {
+	*x = MetricsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsResponse) ProtoMessage() {}
+
+func (x *MetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsResponse.ProtoReflect.Descriptor instead.
+func (*MetricsResponse) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *MetricsResponse) GetMetrics() []*Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *MetricsResponse) GetGeneratedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return nil
+}
+
+type TransactionFees struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SwitchFeePaisa int64 `protobuf:"varint,1,opt,name=switch_fee_paisa,json=switchFeePaisa,proto3" json:"switch_fee_paisa,omitempty"`
+	BankFeePaisa   int64 `protobuf:"varint,2,opt,name=bank_fee_paisa,json=bankFeePaisa,proto3" json:"bank_fee_paisa,omitempty"`
+	TotalFeePaisa  int64 `protobuf:"varint,3,opt,name=total_fee_paisa,json=totalFeePaisa,proto3" json:"total_fee_paisa,omitempty"`
+	FxFeePaisa     int64 `protobuf:"varint,4,opt,name=fx_fee_paisa,json=fxFeePaisa,proto3" json:"fx_fee_paisa,omitempty"` // Cross-currency fee, in the settled currency
+}
+
+func (x *TransactionFees) Reset() {
+	*x = TransactionFees{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionFees) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionFees) ProtoMessage() {}
+
+func (x *TransactionFees) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionFees.ProtoReflect.Descriptor instead.
+func (*TransactionFees) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *TransactionFees) GetSwitchFeePaisa() int64 {
+	if x != nil {
+		return x.SwitchFeePaisa
+	}
+	return 0
+}
+
+func (x *TransactionFees) GetBankFeePaisa() int64 {
+	if x != nil {
+		return x.BankFeePaisa
+	}
+	return 0
+}
+
+func (x *TransactionFees) GetTotalFeePaisa() int64 {
+	if x != nil {
+		return x.TotalFeePaisa
+	}
+	return 0
+}
+
+func (x *TransactionFees) GetFxFeePaisa() int64 {
+	if x != nil {
+		return x.FxFeePaisa
+	}
+	return 0
+}
+
+type TransactionEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EventType   string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Timestamp   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Details     map[string]string      `protobuf:"bytes,4,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TransactionEvent) Reset() {
+	*x = TransactionEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionEvent) ProtoMessage() {}
+
+func (x *TransactionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionEvent.ProtoReflect.Descriptor instead.
+func (*TransactionEvent) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *TransactionEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *TransactionEvent) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TransactionEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *TransactionEvent) GetDetails() map[string]string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type BankInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode            string                 `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	BankName            string                 `protobuf:"bytes,2,opt,name=bank_name,json=bankName,proto3" json:"bank_name,omitempty"`
+	IfscPrefix          string                 `protobuf:"bytes,3,opt,name=ifsc_prefix,json=ifscPrefix,proto3" json:"ifsc_prefix,omitempty"`
+	Status              BankStatus             `protobuf:"varint,4,opt,name=status,proto3,enum=upi_core.BankStatus" json:"status,omitempty"`
+	EndpointUrl         string                 `protobuf:"bytes,5,opt,name=endpoint_url,json=endpointUrl,proto3" json:"endpoint_url,omitempty"`
+	SupportedFeatures   []string               `protobuf:"bytes,6,rep,name=supported_features,json=supportedFeatures,proto3" json:"supported_features,omitempty"`
+	RegisteredAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
+	StatusReason        string                 `protobuf:"bytes,8,opt,name=status_reason,json=statusReason,proto3" json:"status_reason,omitempty"`
+	RegisteredBy        string                 `protobuf:"bytes,9,opt,name=registered_by,json=registeredBy,proto3" json:"registered_by,omitempty"`
+	ApprovedBy          string                 `protobuf:"bytes,10,opt,name=approved_by,json=approvedBy,proto3" json:"approved_by,omitempty"`
+	ApprovedAt          *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=approved_at,json=approvedAt,proto3" json:"approved_at,omitempty"`
+	MaintenanceWindowId int64                  `protobuf:"varint,12,opt,name=maintenance_window_id,json=maintenanceWindowId,proto3" json:"maintenance_window_id,omitempty"` // The window the bank is in MAINTENANCE for, if any
+	CallbackUrl         string                 `protobuf:"bytes,13,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+}
+
+func (x *BankInfo) Reset() {
+	*x = BankInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[74]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankInfo) ProtoMessage() {}
+
+func (x *BankInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[74]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankInfo.ProtoReflect.Descriptor instead.
+func (*BankInfo) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *BankInfo) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *BankInfo) GetBankName() string {
+	if x != nil {
+		return x.BankName
+	}
+	return ""
+}
+
+func (x *BankInfo) GetIfscPrefix() string {
+	if x != nil {
+		return x.IfscPrefix
+	}
+	return ""
+}
+
+func (x *BankInfo) GetStatus() BankStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BankStatus_BANK_STATUS_UNSPECIFIED
+}
+
+func (x *BankInfo) GetEndpointUrl() string {
+	if x != nil {
+		return x.EndpointUrl
+	}
+	return ""
+}
+
+func (x *BankInfo) GetSupportedFeatures() []string {
+	if x != nil {
+		return x.SupportedFeatures
+	}
+	return nil
+}
+
+func (x *BankInfo) GetRegisteredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return nil
+}
+
+func (x *BankInfo) GetStatusReason() string {
+	if x != nil {
+		return x.StatusReason
+	}
+	return ""
+}
+
+func (x *BankInfo) GetRegisteredBy() string {
+	if x != nil {
+		return x.RegisteredBy
+	}
+	return ""
+}
+
+func (x *BankInfo) GetApprovedBy() string {
+	if x != nil {
+		return x.ApprovedBy
+	}
+	return ""
+}
+
+func (x *BankInfo) GetApprovedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ApprovedAt
+	}
+	return nil
+}
+
+func (x *BankInfo) GetMaintenanceWindowId() int64 {
+	if x != nil {
+		return x.MaintenanceWindowId
+	}
+	return 0
+}
+
+func (x *BankInfo) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+type BankSettlement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BankCode          string           `protobuf:"bytes,1,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	CreditAmountPaisa int64            `protobuf:"varint,2,opt,name=credit_amount_paisa,json=creditAmountPaisa,proto3" json:"credit_amount_paisa,omitempty"`
+	DebitAmountPaisa  int64            `protobuf:"varint,3,opt,name=debit_amount_paisa,json=debitAmountPaisa,proto3" json:"debit_amount_paisa,omitempty"`
+	NetAmountPaisa    int64            `protobuf:"varint,4,opt,name=net_amount_paisa,json=netAmountPaisa,proto3" json:"net_amount_paisa,omitempty"`
+	TransactionCount  int32            `protobuf:"varint,5,opt,name=transaction_count,json=transactionCount,proto3" json:"transaction_count,omitempty"`
+	Status            SettlementStatus `protobuf:"varint,6,opt,name=status,proto3,enum=upi_core.SettlementStatus" json:"status,omitempty"`
+}
+
+func (x *BankSettlement) Reset() {
+	*x = BankSettlement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[75]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BankSettlement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankSettlement) ProtoMessage() {}
+
+func (x *BankSettlement) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[75]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankSettlement.ProtoReflect.Descriptor instead.
+func (*BankSettlement) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *BankSettlement) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *BankSettlement) GetCreditAmountPaisa() int64 {
+	if x != nil {
+		return x.CreditAmountPaisa
+	}
+	return 0
+}
+
+func (x *BankSettlement) GetDebitAmountPaisa() int64 {
+	if x != nil {
+		return x.DebitAmountPaisa
+	}
+	return 0
+}
+
+func (x *BankSettlement) GetNetAmountPaisa() int64 {
+	if x != nil {
+		return x.NetAmountPaisa
+	}
+	return 0
+}
+
+func (x *BankSettlement) GetTransactionCount() int32 {
+	if x != nil {
+		return x.TransactionCount
+	}
+	return 0
+}
+
+func (x *BankSettlement) GetStatus() SettlementStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SettlementStatus_SETTLEMENT_STATUS_UNSPECIFIED
+}
+
+type DailySettlement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Date              string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD format
+	CreditAmountPaisa int64  `protobuf:"varint,2,opt,name=credit_amount_paisa,json=creditAmountPaisa,proto3" json:"credit_amount_paisa,omitempty"`
+	DebitAmountPaisa  int64  `protobuf:"varint,3,opt,name=debit_amount_paisa,json=debitAmountPaisa,proto3" json:"debit_amount_paisa,omitempty"`
+	NetAmountPaisa    int64  `protobuf:"varint,4,opt,name=net_amount_paisa,json=netAmountPaisa,proto3" json:"net_amount_paisa,omitempty"`
+	TransactionCount  int32  `protobuf:"varint,5,opt,name=transaction_count,json=transactionCount,proto3" json:"transaction_count,omitempty"`
+}
+
+func (x *DailySettlement) Reset() {
+	*x = DailySettlement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[76]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DailySettlement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailySettlement) ProtoMessage() {}
+
+func (x *DailySettlement) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[76]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailySettlement.ProtoReflect.Descriptor instead.
+func (*DailySettlement) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *DailySettlement) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DailySettlement) GetCreditAmountPaisa() int64 {
+	if x != nil {
+		return x.CreditAmountPaisa
+	}
+	return 0
+}
+
+func (x *DailySettlement) GetDebitAmountPaisa() int64 {
+	if x != nil {
+		return x.DebitAmountPaisa
+	}
+	return 0
+}
+
+func (x *DailySettlement) GetNetAmountPaisa() int64 {
+	if x != nil {
+		return x.NetAmountPaisa
+	}
+	return 0
+}
+
+func (x *DailySettlement) GetTransactionCount() int32 {
+	if x != nil {
+		return x.TransactionCount
+	}
+	return 0
+}
+
+// SettlementRecord is one transaction in a settlement report
+type SettlementRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Rrn           string                 `protobuf:"bytes,2,opt,name=rrn,proto3" json:"rrn,omitempty"`
+	PayerBankCode string                 `protobuf:"bytes,3,opt,name=payer_bank_code,json=payerBankCode,proto3" json:"payer_bank_code,omitempty"`
+	PayeeBankCode string                 `protobuf:"bytes,4,opt,name=payee_bank_code,json=payeeBankCode,proto3" json:"payee_bank_code,omitempty"`
+	AmountPaisa   int64                  `protobuf:"varint,5,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"` // In the currency the transaction was made in
+	FeePaisa      int64                  `protobuf:"varint,6,opt,name=fee_paisa,json=feePaisa,proto3" json:"fee_paisa,omitempty"`
+	SettlementId  string                 `protobuf:"bytes,7,opt,name=settlement_id,json=settlementId,proto3" json:"settlement_id,omitempty"` // Empty until the transaction is settled
+	ProcessedAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+}
+
+func (x *SettlementRecord) Reset() {
+	*x = SettlementRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[77]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SettlementRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettlementRecord) ProtoMessage() {}
+
+func (x *SettlementRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[77]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettlementRecord.ProtoReflect.Descriptor instead.
+func (*SettlementRecord) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *SettlementRecord) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *SettlementRecord) GetRrn() string {
+	if x != nil {
+		return x.Rrn
+	}
+	return ""
+}
+
+func (x *SettlementRecord) GetPayerBankCode() string {
+	if x != nil {
+		return x.PayerBankCode
+	}
+	return ""
+}
+
+func (x *SettlementRecord) GetPayeeBankCode() string {
+	if x != nil {
+		return x.PayeeBankCode
+	}
+	return ""
+}
+
+func (x *SettlementRecord) GetAmountPaisa() int64 {
+	if x != nil {
+		return x.AmountPaisa
+	}
+	return 0
+}
+
+func (x *SettlementRecord) GetFeePaisa() int64 {
+	if x != nil {
+		return x.FeePaisa
+	}
+	return 0
+}
+
+func (x *SettlementRecord) GetSettlementId() string {
+	if x != nil {
+		return x.SettlementId
+	}
+	return ""
+}
+
+func (x *SettlementRecord) GetProcessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProcessedAt
+	}
+	return nil
+}
+
+type Metric struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value     string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Unit      string                 `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	Labels    map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *Metric) Reset() {
+	*x = Metric{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[78]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Metric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metric) ProtoMessage() {}
+
+func (x *Metric) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[78]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metric.ProtoReflect.Descriptor instead.
+func (*Metric) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *Metric) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Metric) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Metric) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Metric) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Metric) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type Mandate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MandateId      string                 `protobuf:"bytes,1,opt,name=mandate_id,json=mandateId,proto3" json:"mandate_id,omitempty"`
+	PayerVpa       string                 `protobuf:"bytes,2,opt,name=payer_vpa,json=payerVpa,proto3" json:"payer_vpa,omitempty"`
+	PayeeVpa       string                 `protobuf:"bytes,3,opt,name=payee_vpa,json=payeeVpa,proto3" json:"payee_vpa,omitempty"`
+	PayerBankCode  string                 `protobuf:"bytes,4,opt,name=payer_bank_code,json=payerBankCode,proto3" json:"payer_bank_code,omitempty"`
+	PayeeBankCode  string                 `protobuf:"bytes,5,opt,name=payee_bank_code,json=payeeBankCode,proto3" json:"payee_bank_code,omitempty"`
+	AmountPaisa    int64                  `protobuf:"varint,6,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`
+	MaxAmountPaisa int64                  `protobuf:"varint,7,opt,name=max_amount_paisa,json=maxAmountPaisa,proto3" json:"max_amount_paisa,omitempty"`
+	Frequency      MandateFrequency       `protobuf:"varint,8,opt,name=frequency,proto3,enum=upi_core.MandateFrequency" json:"frequency,omitempty"`
+	StartDate      string                 `protobuf:"bytes,9,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`          // YYYY-MM-DD format
+	EndDate        string                 `protobuf:"bytes,10,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`               // YYYY-MM-DD format
+	NextDueDate    string                 `protobuf:"bytes,11,opt,name=next_due_date,json=nextDueDate,proto3" json:"next_due_date,omitempty"` // YYYY-MM-DD format, empty once not active
+	Status         MandateStatus          `protobuf:"varint,12,opt,name=status,proto3,enum=upi_core.MandateStatus" json:"status,omitempty"`
+	Description    string                 `protobuf:"bytes,13,opt,name=description,proto3" json:"description,omitempty"`
+	RevokeReason   string                 `protobuf:"bytes,14,opt,name=revoke_reason,json=revokeReason,proto3" json:"revoke_reason,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *Mandate) Reset() {
+	*x = Mandate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[79]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Mandate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Mandate) ProtoMessage() {}
+
+func (x *Mandate) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[79]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Mandate.ProtoReflect.Descriptor instead.
+func (*Mandate) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *Mandate) GetMandateId() string {
+	if x != nil {
+		return x.MandateId
+	}
+	return ""
+}
+
+func (x *Mandate) GetPayerVpa() string {
+	if x != nil {
+		return x.PayerVpa
+	}
+	return ""
+}
+
+func (x *Mandate) GetPayeeVpa() string {
+	if x != nil {
+		return x.PayeeVpa
+	}
+	return ""
+}
+
+func (x *Mandate) GetPayerBankCode() string {
+	if x != nil {
+		return x.PayerBankCode
+	}
+	return ""
+}
+
+func (x *Mandate) GetPayeeBankCode() string {
+	if x != nil {
+		return x.PayeeBankCode
+	}
+	return ""
+}
+
+func (x *Mandate) GetAmountPaisa() int64 {
+	if x != nil {
+		return x.AmountPaisa
+	}
+	return 0
+}
+
+func (x *Mandate) GetMaxAmountPaisa() int64 {
+	if x != nil {
+		return x.MaxAmountPaisa
+	}
+	return 0
+}
+
+func (x *Mandate) GetFrequency() MandateFrequency {
+	if x != nil {
+		return x.Frequency
+	}
+	return MandateFrequency_MANDATE_FREQUENCY_UNSPECIFIED
+}
+
+func (x *Mandate) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *Mandate) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *Mandate) GetNextDueDate() string {
+	if x != nil {
+		return x.NextDueDate
+	}
+	return ""
+}
+
+func (x *Mandate) GetStatus() MandateStatus {
+	if x != nil {
+		return x.Status
+	}
+	return MandateStatus_MANDATE_STATUS_UNSPECIFIED
+}
+
+func (x *Mandate) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Mandate) GetRevokeReason() string {
+	if x != nil {
+		return x.RevokeReason
+	}
+	return ""
+}
+
+func (x *Mandate) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Mandate) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Collect struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CollectId     string                 `protobuf:"bytes,1,opt,name=collect_id,json=collectId,proto3" json:"collect_id,omitempty"`
+	PayeeVpa      string                 `protobuf:"bytes,2,opt,name=payee_vpa,json=payeeVpa,proto3" json:"payee_vpa,omitempty"`
+	PayerVpa      string                 `protobuf:"bytes,3,opt,name=payer_vpa,json=payerVpa,proto3" json:"payer_vpa,omitempty"`
+	PayeeBankCode string                 `protobuf:"bytes,4,opt,name=payee_bank_code,json=payeeBankCode,proto3" json:"payee_bank_code,omitempty"`
+	PayerBankCode string                 `protobuf:"bytes,5,opt,name=payer_bank_code,json=payerBankCode,proto3" json:"payer_bank_code,omitempty"`
+	AmountPaisa   int64                  `protobuf:"varint,6,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`
+	Type          TransactionType        `protobuf:"varint,7,opt,name=type,proto3,enum=upi_core.TransactionType" json:"type,omitempty"`
+	Description   string                 `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	Status        CollectStatus          `protobuf:"varint,9,opt,name=status,proto3,enum=upi_core.CollectStatus" json:"status,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,10,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,11,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"` // Decline reason, or why the payment failed
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	DeliveredAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	RespondedAt   *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=responded_at,json=respondedAt,proto3" json:"responded_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Collect) Reset() {
+	*x = Collect{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[80]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Collect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Collect) ProtoMessage() {}
+
+func (x *Collect) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[80]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Collect.ProtoReflect.Descriptor instead.
+func (*Collect) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *Collect) GetCollectId() string {
+	if x != nil {
+		return x.CollectId
+	}
+	return ""
+}
+
+func (x *Collect) GetPayeeVpa() string {
+	if x != nil {
+		return x.PayeeVpa
+	}
+	return ""
+}
+
+func (x *Collect) GetPayerVpa() string {
+	if x != nil {
+		return x.PayerVpa
+	}
+	return ""
+}
+
+func (x *Collect) GetPayeeBankCode() string {
+	if x != nil {
+		return x.PayeeBankCode
+	}
+	return ""
+}
+
+func (x *Collect) GetPayerBankCode() string {
+	if x != nil {
+		return x.PayerBankCode
+	}
+	return ""
+}
+
+func (x *Collect) GetAmountPaisa() int64 {
+	if x != nil {
+		return x.AmountPaisa
+	}
+	return 0
+}
+
+func (x *Collect) GetType() TransactionType {
+	if x != nil {
+		return x.Type
+	}
+	return TransactionType_TRANSACTION_TYPE_UNSPECIFIED
+}
+
+func (x *Collect) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Collect) GetStatus() CollectStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CollectStatus_COLLECT_STATUS_UNSPECIFIED
+}
+
+func (x *Collect) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *Collect) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *Collect) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Collect) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+func (x *Collect) GetRespondedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RespondedAt
+	}
+	return nil
+}
+
+func (x *Collect) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type MandateExecution struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DueDate       string                 `protobuf:"bytes,1,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"` // YYYY-MM-DD format
+	TransactionId string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	AmountPaisa   int64                  `protobuf:"varint,3,opt,name=amount_paisa,json=amountPaisa,proto3" json:"amount_paisa,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // PENDING, SUCCESS or FAILED
+	ErrorCode     string                 `protobuf:"bytes,5,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage  string                 `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	ExecutedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=executed_at,json=executedAt,proto3" json:"executed_at,omitempty"`
+}
+
+func (x *MandateExecution) Reset() {
+	*x = MandateExecution{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[81]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MandateExecution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MandateExecution) ProtoMessage() {}
+
+func (x *MandateExecution) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[81]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MandateExecution.ProtoReflect.Descriptor instead.
+func (*MandateExecution) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *MandateExecution) GetDueDate() string {
+	if x != nil {
+		return x.DueDate
+	}
+	return ""
+}
+
+func (x *MandateExecution) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *MandateExecution) GetAmountPaisa() int64 {
+	if x != nil {
+		return x.AmountPaisa
+	}
+	return 0
+}
+
+func (x *MandateExecution) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MandateExecution) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *MandateExecution) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *MandateExecution) GetExecutedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExecutedAt
+	}
+	return nil
+}
+
+// A fee rule sets one fee of the transactions it matches: fixed_paisa plus
+// percent_bps basis points of the settled amount, held between
+// min_fee_paisa and max_fee_paisa. The most specific matching rule applies.
+type FeeRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuleId          int64                  `protobuf:"varint,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	FeeType         FeeType                `protobuf:"varint,2,opt,name=fee_type,json=feeType,proto3,enum=upi_core.FeeType" json:"fee_type,omitempty"`
+	BankCode        string                 `protobuf:"bytes,3,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`                                                     // Payer's bank; empty matches every bank
+	TransactionType TransactionType        `protobuf:"varint,4,opt,name=transaction_type,json=transactionType,proto3,enum=upi_core.TransactionType" json:"transaction_type,omitempty"` // UNSPECIFIED matches every type
+	MinAmountPaisa  int64                  `protobuf:"varint,5,opt,name=min_amount_paisa,json=minAmountPaisa,proto3" json:"min_amount_paisa,omitempty"`
+	MaxAmountPaisa  int64                  `protobuf:"varint,6,opt,name=max_amount_paisa,json=maxAmountPaisa,proto3" json:"max_amount_paisa,omitempty"` // Exclusive; 0 has no upper bound
+	FixedPaisa      int64                  `protobuf:"varint,7,opt,name=fixed_paisa,json=fixedPaisa,proto3" json:"fixed_paisa,omitempty"`
+	PercentBps      int64                  `protobuf:"varint,8,opt,name=percent_bps,json=percentBps,proto3" json:"percent_bps,omitempty"`
+	MinFeePaisa     int64                  `protobuf:"varint,9,opt,name=min_fee_paisa,json=minFeePaisa,proto3" json:"min_fee_paisa,omitempty"`
+	MaxFeePaisa     int64                  `protobuf:"varint,10,opt,name=max_fee_paisa,json=maxFeePaisa,proto3" json:"max_fee_paisa,omitempty"` // 0 has no cap
+	Description     string                 `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *FeeRule) Reset() {
+	*x = FeeRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[82]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FeeRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeeRule) ProtoMessage() {}
+
+func (x *FeeRule) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[82]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeeRule.ProtoReflect.Descriptor instead.
+func (*FeeRule) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *FeeRule) GetRuleId() int64 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
+}
+
+func (x *FeeRule) GetFeeType() FeeType {
+	if x != nil {
+		return x.FeeType
+	}
+	return FeeType_FEE_TYPE_UNSPECIFIED
+}
+
+func (x *FeeRule) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *FeeRule) GetTransactionType() TransactionType {
+	if x != nil {
+		return x.TransactionType
+	}
+	return TransactionType_TRANSACTION_TYPE_UNSPECIFIED
+}
+
+func (x *FeeRule) GetMinAmountPaisa() int64 {
+	if x != nil {
+		return x.MinAmountPaisa
+	}
+	return 0
+}
+
+func (x *FeeRule) GetMaxAmountPaisa() int64 {
+	if x != nil {
+		return x.MaxAmountPaisa
+	}
+	return 0
+}
+
+func (x *FeeRule) GetFixedPaisa() int64 {
+	if x != nil {
+		return x.FixedPaisa
+	}
+	return 0
+}
+
+func (x *FeeRule) GetPercentBps() int64 {
+	if x != nil {
+		return x.PercentBps
+	}
+	return 0
+}
+
+func (x *FeeRule) GetMinFeePaisa() int64 {
+	if x != nil {
+		return x.MinFeePaisa
+	}
+	return 0
+}
+
+func (x *FeeRule) GetMaxFeePaisa() int64 {
+	if x != nil {
+		return x.MaxFeePaisa
+	}
+	return 0
+}
+
+func (x *FeeRule) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *FeeRule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *FeeRule) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// The reconciliation of the transactions of a business day, a day in
+// India, against the banks' records
+type ReconciliationRun struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BusinessDate           string                 `protobuf:"bytes,1,opt,name=business_date,json=businessDate,proto3" json:"business_date,omitempty"` // YYYY-MM-DD format
+	Status                 string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                                 // RUNNING, COMPLETED or FAILED
+	TransactionsChecked    int32                  `protobuf:"varint,3,opt,name=transactions_checked,json=transactionsChecked,proto3" json:"transactions_checked,omitempty"`
+	TransactionsUnverified int32                  `protobuf:"varint,4,opt,name=transactions_unverified,json=transactionsUnverified,proto3" json:"transactions_unverified,omitempty"` // A bank could not be asked about them
+	ExceptionsFound        int32                  `protobuf:"varint,5,opt,name=exceptions_found,json=exceptionsFound,proto3" json:"exceptions_found,omitempty"`
+	ErrorMessage           string                 `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	StartedAt              *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt            *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+}
+
+func (x *ReconciliationRun) Reset() {
+	*x = ReconciliationRun{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[83]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconciliationRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationRun) ProtoMessage() {}
+
+func (x *ReconciliationRun) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[83]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationRun.ProtoReflect.Descriptor instead.
+func (*ReconciliationRun) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *ReconciliationRun) GetBusinessDate() string {
+	if x != nil {
+		return x.BusinessDate
+	}
+	return ""
+}
+
+func (x *ReconciliationRun) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ReconciliationRun) GetTransactionsChecked() int32 {
+	if x != nil {
+		return x.TransactionsChecked
+	}
+	return 0
+}
+
+func (x *ReconciliationRun) GetTransactionsUnverified() int32 {
+	if x != nil {
+		return x.TransactionsUnverified
+	}
+	return 0
+}
+
+func (x *ReconciliationRun) GetExceptionsFound() int32 {
+	if x != nil {
+		return x.ExceptionsFound
+	}
+	return 0
+}
+
+func (x *ReconciliationRun) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ReconciliationRun) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *ReconciliationRun) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+// A transaction a bank's record disagrees with the switch's on
+type ReconciliationException struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ExceptionId       int64                  `protobuf:"varint,1,opt,name=exception_id,json=exceptionId,proto3" json:"exception_id,omitempty"`
+	TransactionId     string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ExceptionType     string                 `protobuf:"bytes,3,opt,name=exception_type,json=exceptionType,proto3" json:"exception_type,omitempty"` // MISSING_DEBIT, MISSING_CREDIT, ORPHAN_DEBIT or AMOUNT_MISMATCH
+	BankCode          string                 `protobuf:"bytes,4,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	SwitchStatus      TransactionStatus      `protobuf:"varint,5,opt,name=switch_status,json=switchStatus,proto3,enum=upi_core.TransactionStatus" json:"switch_status,omitempty"`
+	BankStatus        string                 `protobuf:"bytes,6,opt,name=bank_status,json=bankStatus,proto3" json:"bank_status,omitempty"` // NOT_FOUND if the bank has no record
+	SwitchAmountPaisa int64                  `protobuf:"varint,7,opt,name=switch_amount_paisa,json=switchAmountPaisa,proto3" json:"switch_amount_paisa,omitempty"`
+	BankAmountPaisa   int64                  `protobuf:"varint,8,opt,name=bank_amount_paisa,json=bankAmountPaisa,proto3" json:"bank_amount_paisa,omitempty"`
+	Details           string                 `protobuf:"bytes,9,opt,name=details,proto3" json:"details,omitempty"`
+	DetectedAt        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=detected_at,json=detectedAt,proto3" json:"detected_at,omitempty"`
+}
+
+func (x *ReconciliationException) Reset() {
+	*x = ReconciliationException{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upi_core_proto_msgTypes[84]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconciliationException) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconciliationException) ProtoMessage() {}
+
+func (x *ReconciliationException) ProtoReflect() protoreflect.Message {
+	mi := &file_upi_core_proto_msgTypes[84]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconciliationException.ProtoReflect.Descriptor instead.
+func (*ReconciliationException) Descriptor() ([]byte, []int) {
+	return file_upi_core_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *ReconciliationException) GetExceptionId() int64 {
+	if x != nil {
+		return x.ExceptionId
+	}
+	return 0
+}
+
+func (x *ReconciliationException) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *ReconciliationException) GetExceptionType() string {
+	if x != nil {
+		return x.ExceptionType
+	}
+	return ""
+}
+
+func (x *ReconciliationException) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *ReconciliationException) GetSwitchStatus() TransactionStatus {
+	if x != nil {
+		return x.SwitchStatus
+	}
+	return TransactionStatus_TRANSACTION_STATUS_UNSPECIFIED
+}
+
+func (x *ReconciliationException) GetBankStatus() string {
+	if x != nil {
+		return x.BankStatus
+	}
+	return ""
+}
+
+func (x *ReconciliationException) GetSwitchAmountPaisa() int64 {
+	if x != nil {
+		return x.SwitchAmountPaisa
+	}
+	return 0
+}
+
+func (x *ReconciliationException) GetBankAmountPaisa() int64 {
+	if x != nil {
+		return x.BankAmountPaisa
+	}
+	return 0
+}
+
+func (x *ReconciliationException) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *ReconciliationException) GetDetectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DetectedAt
+	}
+	return nil
+}
+
+var File_upi_core_proto protoreflect.FileDescriptor
+
+var file_upi_core_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x08, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x97, 0x04, 0x0a, 0x12,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x72, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x72, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x61, 0x79, 0x65, 0x72, 0x5f, 0x76, 0x70, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x61, 0x79, 0x65, 0x72, 0x56, 0x70, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x79, 0x65,
+	0x65, 0x5f, 0x76, 0x70, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x79,
+	0x65, 0x65, 0x56, 0x70, 0x61, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f,
+	0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x2d, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x19, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x12, 0x46, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0c, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa0, 0x04, 0x0a, 0x13, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a,
+	0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x72, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x72, 0x72, 0x6e, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x26, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x65, 0x72, 0x42,
+	0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x65, 0x65,
+	0x5f, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x70, 0x61, 0x79, 0x65, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x3d, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x41, 0x74, 0x12, 0x2d,
+	0x0a, 0x04, 0x66, 0x65, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x75,
+	0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x46, 0x65, 0x65, 0x73, 0x52, 0x04, 0x66, 0x65, 0x65, 0x73, 0x12, 0x23, 0x0a,
+	0x0d, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x5f, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x12, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50,
+	0x61, 0x69, 0x73, 0x61, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x5f,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f,
+	0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12,
+	0x17, 0x0a, 0x07, 0x66, 0x78, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x66, 0x78, 0x52, 0x61, 0x74, 0x65, 0x22, 0x53, 0x0a, 0x18, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x72,
+	0x72, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x72, 0x6e, 0x22, 0xbe, 0x05,
+	0x0a, 0x19, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x72, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x72, 0x72, 0x6e, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x1b, 0x0a, 0x09,
+	0x70, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x76, 0x70, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x61, 0x79, 0x65, 0x72, 0x56, 0x70, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x79,
+	0x65, 0x65, 0x5f, 0x76, 0x70, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61,
+	0x79, 0x65, 0x65, 0x56, 0x70, 0x61, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x65, 0x72, 0x5f,
+	0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x70, 0x61, 0x79, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x26,
+	0x0a, 0x0f, 0x70, 0x61, 0x79, 0x65, 0x65, 0x5f, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x65, 0x65, 0x42, 0x61,
+	0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3d, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x65, 0x74, 0x74,
+	0x6c, 0x65, 0x64, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x65,
+	0x74, 0x74, 0x6c, 0x65, 0x64, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x43, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x66, 0x78, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x78, 0x52, 0x61, 0x74, 0x65, 0x22, 0x4a,
+	0x0a, 0x21, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x8a, 0x02, 0x0a, 0x17, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x33, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e,
+	0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x22, 0x77, 0x0a, 0x18, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x22, 0xb8, 0x01, 0x0a, 0x19, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3d, 0x0a, 0x0c,
+	0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b,
+	0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x41, 0x74, 0x22, 0xc1, 0x01, 0x0a, 0x19,
+	0x52, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x36, 0x0a, 0x17, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x6f, 0x72, 0x69, 0x67,
+	0x69, 0x6e, 0x61, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x12, 0x36, 0x0a, 0x17, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x61, 0x6c, 0x5f, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x15, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x61, 0x6c, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22,
+	0xef, 0x01, 0x0a, 0x1a, 0x52, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x72, 0x65, 0x76, 0x65,
+	0x72, 0x73, 0x61, 0x6c, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x72, 0x65, 0x76, 0x65, 0x72,
+	0x73, 0x61, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x64, 0x41,
+	0x74, 0x22, 0x25, 0x0a, 0x11, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x56, 0x50, 0x41, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x70, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x76, 0x70, 0x61, 0x22, 0x81, 0x02, 0x0a, 0x12, 0x52, 0x65, 0x73,
+	0x6f, 0x6c, 0x76, 0x65, 0x56, 0x50, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x65, 0x78, 0x69, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x65, 0x78, 0x69, 0x73, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x13, 0x61,
+	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x48, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x69,
+	0x73, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x69, 0x73, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xdd, 0x01, 0x0a,
+	0x12, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x56, 0x50, 0x41, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x70, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x76, 0x70, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x13, 0x61, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x48,
+	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x6f, 0x62,
+	0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x6d, 0x6f, 0x62, 0x69, 0x6c, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xb4, 0x01, 0x0a,
+	0x13, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x56, 0x50, 0x41, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x1d,
+	0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a,
+	0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x3f, 0x0a, 0x0d, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65,
+	0x64, 0x41, 0x74, 0x22, 0x70, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x50, 0x41,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x70, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x76, 0x70, 0x61, 0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x65, 0x77,
+	0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6e, 0x65, 0x77, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xac, 0x01, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x56, 0x50, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x22, 0x5e, 0x0a, 0x14, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61,
+	0x74, 0x65, 0x56, 0x50, 0x41, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x76, 0x70, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x76, 0x70, 0x61, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x22, 0xb8, 0x01, 0x0a, 0x15, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x61, 0x74, 0x65, 0x56, 0x50, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x41, 0x0a, 0x0e,
+	0x64, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0d, 0x64, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22,
+	0xf0, 0x02, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x6e, 0x64,
+	0x61, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x61,
+	0x6e, 0x64, 0x61, 0x74, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x79, 0x65, 0x72,
+	0x5f, 0x76, 0x70, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x79, 0x65,
+	0x72, 0x56, 0x70, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x79, 0x65, 0x65, 0x5f, 0x76, 0x70,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x79, 0x65, 0x65, 0x56, 0x70,
+	0x61, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73,
+	0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50,
+	0x61, 0x69, 0x73, 0x61, 0x12, 0x28, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e,
+	0x6d, 0x61, 0x78, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x38,
+	0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1a, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x6e,
+	0x64, 0x61, 0x74, 0x65, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x09, 0x66,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x44, 0x61,
+	0x74, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x22, 0x44, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x6e, 0x64,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x6d,
+	0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x75,
+	0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52,
+	0x07, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x22, 0x9d, 0x01, 0x0a, 0x14, 0x4d, 0x6f, 0x64,
+	0x69, 0x66, 0x79, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x49, 0x64,
+	0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61,
+	0x69, 0x73, 0x61, 0x12, 0x28, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6d,
+	0x61, 0x78, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x19, 0x0a,
+	0x08, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x65, 0x6e, 0x64, 0x44, 0x61, 0x74, 0x65, 0x22, 0x44, 0x0a, 0x15, 0x4d, 0x6f, 0x64, 0x69,
+	0x66, 0x79, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2b, 0x0a, 0x07, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61,
+	0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x07, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x22, 0x4d,
+	0x0a, 0x14, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x61, 0x6e, 0x64,
+	0x61, 0x74, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x44, 0x0a,
+	0x15, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x07, 0x6d, 0x61, 0x6e, 0x64,
+	0x61, 0x74, 0x65, 0x22, 0x32, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x6e, 0x64,
+	0x61, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x61,
+	0x6e, 0x64, 0x61, 0x74, 0x65, 0x49, 0x64, 0x22, 0x7d, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x4d, 0x61,
+	0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a,
+	0x07, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74,
+	0x65, 0x52, 0x07, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x12, 0x3a, 0x0a, 0x0a, 0x65, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74,
+	0x65, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x65, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x91, 0x02, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x49, 0x64, 0x12, 0x1b,
+	0x0a, 0x09, 0x70, 0x61, 0x79, 0x65, 0x65, 0x5f, 0x76, 0x70, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x70, 0x61, 0x79, 0x65, 0x65, 0x56, 0x70, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x61, 0x79, 0x65, 0x72, 0x5f, 0x76, 0x70, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x61, 0x79, 0x65, 0x72, 0x56, 0x70, 0x61, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x2d, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x12,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x73, 0x49, 0x6e, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x44, 0x0a, 0x15, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x22, 0xc5, 0x01, 0x0a, 0x15, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64, 0x43, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x70, 0x70,
+	0x72, 0x6f, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x61, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x86, 0x01, 0x0a, 0x16, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x64, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x12, 0x3f, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0x32, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x49, 0x64, 0x22, 0x41, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x63,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x75,
+	0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52,
+	0x07, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x22, 0x3d, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x25, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c,
+	0x65, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x22, 0x3e, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x25, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c,
+	0x65, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x22, 0x3d, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x25, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65,
+	0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x22, 0x3e, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x25, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65,
+	0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x22, 0x2f, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17,
+	0x0a, 0x07, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x72, 0x75, 0x6c, 0x65, 0x49, 0x64, 0x22, 0x17, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x15, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3f, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x46,
+	0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x27, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c,
+	0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x3f, 0x0a, 0x18, 0x52, 0x75, 0x6e, 0x52,
+	0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x75, 0x73, 0x69, 0x6e, 0x65, 0x73, 0x73,
+	0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x75, 0x73,
+	0x69, 0x6e, 0x65, 0x73, 0x73, 0x44, 0x61, 0x74, 0x65, 0x22, 0x4a, 0x0a, 0x19, 0x52, 0x75, 0x6e,
+	0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x03, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52,
+	0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x75, 0x6e,
+	0x52, 0x03, 0x72, 0x75, 0x6e, 0x22, 0x42, 0x0a, 0x1b, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69,
+	0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x75, 0x73, 0x69, 0x6e, 0x65, 0x73, 0x73,
+	0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x75, 0x73,
+	0x69, 0x6e, 0x65, 0x73, 0x73, 0x44, 0x61, 0x74, 0x65, 0x22, 0x90, 0x01, 0x0a, 0x1c, 0x52, 0x65,
+	0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x03, 0x72, 0x75,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x75, 0x6e, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x12, 0x41, 0x0a, 0x0a, 0x65, 0x78, 0x63,
+	0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e,
+	0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69,
+	0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x0a, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x84, 0x02, 0x0a,
+	0x13, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f,
+	0x0a, 0x0b, 0x69, 0x66, 0x73, 0x63, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x66, 0x73, 0x63, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12,
+	0x21, 0x0a, 0x0c, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x55,
+	0x72, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65,
+	0x79, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x66,
+	0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x11, 0x73,
+	0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73,
+	0x12, 0x21, 0x0a, 0x0c, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x55, 0x72, 0x6c, 0x22, 0xce, 0x01, 0x0a, 0x14, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x61, 0x6e, 0x6b, 0x49, 0x64, 0x12,
+	0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23,
+	0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x3f, 0x0a, 0x0d, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x65, 0x64, 0x41, 0x74, 0x22, 0x7c, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x61,
+	0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2c, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x75,
+	0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x22, 0xb3, 0x01, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x61, 0x6e,
+	0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a,
+	0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x30, 0x0a, 0x11, 0x42, 0x61, 0x6e, 0x6b,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x22, 0xd1, 0x02, 0x0a, 0x12, 0x42,
+	0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x75, 0x70,
+	0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x75, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x12, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x52, 0x61, 0x74, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x14, 0x61,
+	0x76, 0x67, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x61, 0x76, 0x67, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x12, 0x41, 0x0a, 0x0e,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12,
+	0x2d, 0x0a, 0x12, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x65, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x11, 0x73, 0x75, 0x70,
+	0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0x89,
+	0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x66, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x75, 0x70, 0x69,
+	0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1b,
+	0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x86, 0x01, 0x0a, 0x11, 0x4c,
+	0x69, 0x73, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x28, 0x0a, 0x05, 0x62, 0x61, 0x6e, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x05, 0x62, 0x61, 0x6e, 0x6b, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65,
+	0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0x3b, 0x0a, 0x11, 0x42, 0x61, 0x6e, 0x6b, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x62, 0x61, 0x6e, 0x6b,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x62, 0x61, 0x6e, 0x6b,
+	0x22, 0x31, 0x0a, 0x12, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43,
+	0x6f, 0x64, 0x65, 0x22, 0x48, 0x0a, 0x11, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x61, 0x6e,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b,
+	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e,
+	0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x4e, 0x0a,
+	0x17, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b,
+	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e,
+	0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0xbf, 0x01,
+	0x0a, 0x1a, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09,
+	0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x73, 0x74, 0x61, 0x72, 0x74, 0x73,
+	0x41, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x65, 0x6e, 0x64, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x06, 0x65, 0x6e, 0x64, 0x73, 0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22,
+	0x37, 0x0a, 0x18, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x77,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x49, 0x64, 0x22, 0x3c, 0x0a, 0x1d, 0x4c, 0x69, 0x73, 0x74,
+	0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e,
+	0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61,
+	0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x57, 0x0a, 0x1e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x07, 0x77, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65,
+	0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x07, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x22,
+	0xb1, 0x02, 0x0a, 0x11, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x1b, 0x0a, 0x09, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x37, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x73, 0x41, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x65, 0x6e, 0x64, 0x73,
+	0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x65, 0x6e, 0x64, 0x73, 0x41, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x62, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x42, 0x79, 0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65,
+	0x64, 0x41, 0x74, 0x22, 0x2d, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f,
+	0x64, 0x65, 0x22, 0x9a, 0x01, 0x0a, 0x19, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x53,
+	0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x62,
+	0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x09, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x43, 0x0a, 0x0f, 0x73, 0x65,
+	0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x0e, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x65, 0x22,
+	0xde, 0x01, 0x0a, 0x1a, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x53, 0x65, 0x74, 0x74,
+	0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x74, 0x74,
+	0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a,
+	0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0x3e, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73,
+	0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x22, 0xb2, 0x02, 0x0a, 0x18, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a,
+	0x0d, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x65,
+	0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x43, 0x0a, 0x10, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x73,
+	0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b,
+	0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0f, 0x62, 0x61, 0x6e, 0x6b,
+	0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xe0, 0x01, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x37,
+	0x0a, 0x09, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x66,
+	0x72, 0x6f, 0x6d, 0x44, 0x61, 0x74, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x74, 0x6f, 0x5f, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x74, 0x6f, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67,
+	0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70,
+	0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x96, 0x03, 0x0a, 0x18, 0x53, 0x65, 0x74,
+	0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x72, 0x65, 0x64,
+	0x69, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x72, 0x65, 0x64, 0x69, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61,
+	0x12, 0x2a, 0x0a, 0x11, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x64, 0x65, 0x62, 0x69, 0x74, 0x5f,
+	0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x44, 0x65, 0x62, 0x69, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x30, 0x0a, 0x14,
+	0x6e, 0x65, 0x74, 0x5f, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x70,
+	0x61, 0x69, 0x73, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x6e, 0x65, 0x74, 0x53,
+	0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x2b,
+	0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x46, 0x0a, 0x11, 0x64,
+	0x61, 0x69, 0x6c, 0x79, 0x5f, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x10, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x07,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78,
+	0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x22, 0x2e, 0x0a, 0x12, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x22, 0x81, 0x02, 0x0a, 0x13, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x44, 0x0a, 0x07, 0x64, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x75, 0x70, 0x69,
+	0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12,
+	0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x1a, 0x3a, 0x0a, 0x0c, 0x44, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xa1, 0x01, 0x0a, 0x0e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x37, 0x0a, 0x09, 0x66,
+	0x72, 0x6f, 0x6d, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x66, 0x72, 0x6f, 0x6d,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x74, 0x6f, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x06, 0x74, 0x6f, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x7c, 0x0a, 0x0f, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x07,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52,
+	0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x3d, 0x0a, 0x0c, 0x67, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x67, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xab, 0x01, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x65, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x73,
+	0x77, 0x69, 0x74, 0x63, 0x68, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x46, 0x65, 0x65,
+	0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x24, 0x0a, 0x0e, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x66, 0x65,
+	0x65, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x62,
+	0x61, 0x6e, 0x6b, 0x46, 0x65, 0x65, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x26, 0x0a, 0x0f, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x46, 0x65, 0x65, 0x50, 0x61,
+	0x69, 0x73, 0x61, 0x12, 0x20, 0x0a, 0x0c, 0x66, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x70, 0x61,
+	0x69, 0x73, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x66, 0x78, 0x46, 0x65, 0x65,
+	0x50, 0x61, 0x69, 0x73, 0x61, 0x22, 0x8c, 0x02, 0x0a, 0x10, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x41, 0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x2e, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x44, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0xa5, 0x04, 0x0a, 0x08, 0x42, 0x61, 0x6e, 0x6b, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x69,
+	0x66, 0x73, 0x63, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x69, 0x66, 0x73, 0x63, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x2c, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x75,
+	0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x6e,
+	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x2d, 0x0a,
+	0x12, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x66, 0x65, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x11, 0x73, 0x75, 0x70, 0x70, 0x6f,
+	0x72, 0x74, 0x65, 0x64, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x3f, 0x0a, 0x0d,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x0c, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x41, 0x74, 0x12, 0x23, 0x0a,
+	0x0d, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64,
+	0x5f, 0x62, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x65, 0x64, 0x42, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x72, 0x6f,
+	0x76, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70,
+	0x70, 0x72, 0x6f, 0x76, 0x65, 0x64, 0x42, 0x79, 0x12, 0x3b, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x61, 0x70, 0x70, 0x72, 0x6f,
+	0x76, 0x65, 0x64, 0x41, 0x74, 0x12, 0x32, 0x0a, 0x15, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x61, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x22, 0x96, 0x02, 0x0a,
+	0x0e, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2e, 0x0a, 0x13,
+	0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61,
+	0x69, 0x73, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x63, 0x72, 0x65, 0x64, 0x69,
+	0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x2c, 0x0a, 0x12,
+	0x64, 0x65, 0x62, 0x69, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69,
+	0x73, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x64, 0x65, 0x62, 0x69, 0x74, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x28, 0x0a, 0x10, 0x6e, 0x65,
+	0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6e, 0x65, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50,
+	0x61, 0x69, 0x73, 0x61, 0x12, 0x2b, 0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1a, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x65, 0x74,
+	0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xda, 0x01, 0x0a, 0x0f, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x53,
+	0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a,
+	0x13, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70,
+	0x61, 0x69, 0x73, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x63, 0x72, 0x65, 0x64,
+	0x69, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x2c, 0x0a,
+	0x12, 0x64, 0x65, 0x62, 0x69, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61,
+	0x69, 0x73, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x64, 0x65, 0x62, 0x69, 0x74,
+	0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x28, 0x0a, 0x10, 0x6e,
+	0x65, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6e, 0x65, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x2b, 0x0a, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x22, 0xbf, 0x02, 0x0a, 0x10, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10,
+	0x0a, 0x03, 0x72, 0x72, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x72, 0x6e,
+	0x12, 0x26, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x65, 0x72,
+	0x42, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x65,
+	0x65, 0x5f, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x65, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61,
+	0x69, 0x73, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x65, 0x65, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x65, 0x65, 0x50, 0x61, 0x69, 0x73, 0x61,
+	0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x3d, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x65, 0x64, 0x41, 0x74, 0x22, 0xf1, 0x01, 0x0a, 0x06, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x12, 0x34, 0x0a,
+	0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x2e,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62,
+	0x65, 0x6c, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x1a, 0x39, 0x0a,
+	0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x85, 0x05, 0x0a, 0x07, 0x4d, 0x61, 0x6e,
+	0x64, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x61, 0x6e, 0x64, 0x61, 0x74,
+	0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x76, 0x70, 0x61,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x79, 0x65, 0x72, 0x56, 0x70, 0x61,
+	0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x79, 0x65, 0x65, 0x5f, 0x76, 0x70, 0x61, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x79, 0x65, 0x65, 0x56, 0x70, 0x61, 0x12, 0x26, 0x0a,
+	0x0f, 0x70, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x65, 0x72, 0x42, 0x61, 0x6e,
+	0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x65, 0x65, 0x5f, 0x62,
+	0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x70, 0x61, 0x79, 0x65, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61,
+	0x12, 0x28, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70,
+	0x61, 0x69, 0x73, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x38, 0x0a, 0x09, 0x66, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e,
+	0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65,
+	0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x44,
+	0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12, 0x22,
+	0x0a, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x64, 0x75, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x44, 0x75, 0x65, 0x44, 0x61,
+	0x74, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x17, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61,
+	0x6e, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x5f,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65,
+	0x76, 0x6f, 0x6b, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0x8f, 0x05, 0x0a, 0x07, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x61, 0x79, 0x65, 0x65, 0x5f, 0x76, 0x70, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x61, 0x79, 0x65, 0x65, 0x56, 0x70, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x79, 0x65,
+	0x72, 0x5f, 0x76, 0x70, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x79,
+	0x65, 0x72, 0x56, 0x70, 0x61, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x65, 0x65, 0x5f, 0x62,
+	0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x70, 0x61, 0x79, 0x65, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x26, 0x0a,
+	0x0f, 0x70, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x65, 0x72, 0x42, 0x61, 0x6e,
+	0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f,
+	0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x2d, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x39,
+	0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x3d, 0x0a, 0x0c, 0x64, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x64, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3d, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x64, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x41, 0x74, 0x22, 0x90, 0x02, 0x0a, 0x10, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x75, 0x65, 0x5f, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x75, 0x65, 0x44, 0x61,
+	0x74, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x65, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xa9, 0x04, 0x0a, 0x07, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c,
+	0x65, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x06, 0x72, 0x75, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x08, 0x66, 0x65,
+	0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x75,
+	0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x07, 0x66, 0x65, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b,
+	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e,
+	0x6b, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x44, 0x0a, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x19, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0f, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x6d,
+	0x69, 0x6e, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6d, 0x69, 0x6e, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x28, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0e, 0x6d, 0x61, 0x78, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12,
+	0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x78, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x66, 0x69, 0x78, 0x65, 0x64, 0x50, 0x61, 0x69, 0x73, 0x61,
+	0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x70, 0x73, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x42, 0x70,
+	0x73, 0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x70, 0x61, 0x69,
+	0x73, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x46, 0x65, 0x65,
+	0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x66, 0x65, 0x65,
+	0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x61,
+	0x78, 0x46, 0x65, 0x65, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x22, 0x86, 0x03, 0x0a, 0x11, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x75, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x75, 0x73, 0x69, 0x6e,
+	0x65, 0x73, 0x73, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x62, 0x75, 0x73, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x44, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x31, 0x0a, 0x14, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x13, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x12, 0x37, 0x0a, 0x17, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x75, 0x6e, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69,
+	0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x16, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x55, 0x6e, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64,
+	0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x5f, 0x66,
+	0x6f, 0x75, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x65, 0x78, 0x63, 0x65,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x3d, 0x0a, 0x0c, 0x63,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xbd, 0x03, 0x0a, 0x17, 0x52,
+	0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x78, 0x63,
+	0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x65, 0x78,
+	0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x12, 0x25, 0x0a, 0x0e, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x65, 0x78, 0x63, 0x65, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x6e, 0x6b, 0x5f,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x61, 0x6e, 0x6b,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x40, 0x0a, 0x0d, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x75, 0x70,
+	0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0c, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x61, 0x6e, 0x6b, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x61, 0x6e,
+	0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x77, 0x69, 0x74, 0x63,
+	0x68, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x50, 0x61, 0x69, 0x73, 0x61, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x6e, 0x6b, 0x5f,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61, 0x69, 0x73, 0x61, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0f, 0x62, 0x61, 0x6e, 0x6b, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61,
+	0x69, 0x73, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x3b, 0x0a,
+	0x0b, 0x64, 0x65, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a,
+	0x64, 0x65, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x74, 0x2a, 0x9e, 0x01, 0x0a, 0x0f, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20,
+	0x0a, 0x1c, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x18, 0x0a, 0x14, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x32, 0x50, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x54, 0x52,
+	0x41, 0x4e, 0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50,
+	0x32, 0x4d, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x32, 0x50, 0x10, 0x03, 0x12, 0x1b,
+	0x0a, 0x17, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54, 0x59,
+	0x50, 0x45, 0x5f, 0x52, 0x45, 0x46, 0x55, 0x4e, 0x44, 0x10, 0x04, 0x2a, 0xf9, 0x01, 0x0a, 0x11,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x22, 0x0a, 0x1e, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45, 0x4e, 0x44,
+	0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x55, 0x43, 0x43,
+	0x45, 0x53, 0x53, 0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c,
+	0x45, 0x44, 0x10, 0x03, 0x12, 0x1e, 0x0a, 0x1a, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f,
+	0x55, 0x54, 0x10, 0x04, 0x12, 0x20, 0x0a, 0x1c, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54,
+	0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45,
+	0x4c, 0x4c, 0x45, 0x44, 0x10, 0x05, 0x12, 0x1f, 0x0a, 0x1b, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41,
+	0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52, 0x45, 0x56,
+	0x45, 0x52, 0x53, 0x45, 0x44, 0x10, 0x06, 0x2a, 0xee, 0x01, 0x0a, 0x10, 0x4d, 0x61, 0x6e, 0x64,
+	0x61, 0x74, 0x65, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x1d,
+	0x4d, 0x41, 0x4e, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x52, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43,
+	0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x1e, 0x0a, 0x1a, 0x4d, 0x41, 0x4e, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x52, 0x45, 0x51, 0x55,
+	0x45, 0x4e, 0x43, 0x59, 0x5f, 0x4f, 0x4e, 0x45, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x10, 0x01, 0x12,
+	0x1b, 0x0a, 0x17, 0x4d, 0x41, 0x4e, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x52, 0x45, 0x51, 0x55,
+	0x45, 0x4e, 0x43, 0x59, 0x5f, 0x44, 0x41, 0x49, 0x4c, 0x59, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18,
+	0x4d, 0x41, 0x4e, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x52, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43,
+	0x59, 0x5f, 0x57, 0x45, 0x45, 0x4b, 0x4c, 0x59, 0x10, 0x03, 0x12, 0x1d, 0x0a, 0x19, 0x4d, 0x41,
+	0x4e, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x52, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43, 0x59, 0x5f,
+	0x4d, 0x4f, 0x4e, 0x54, 0x48, 0x4c, 0x59, 0x10, 0x04, 0x12, 0x1f, 0x0a, 0x1b, 0x4d, 0x41, 0x4e,
+	0x44, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x52, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x51,
+	0x55, 0x41, 0x52, 0x54, 0x45, 0x52, 0x4c, 0x59, 0x10, 0x05, 0x12, 0x1c, 0x0a, 0x18, 0x4d, 0x41,
+	0x4e, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x52, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43, 0x59, 0x5f,
+	0x59, 0x45, 0x41, 0x52, 0x4c, 0x59, 0x10, 0x06, 0x2a, 0x84, 0x01, 0x0a, 0x0d, 0x4d, 0x61, 0x6e,
+	0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x1a, 0x4d, 0x41,
+	0x4e, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x4d, 0x41,
+	0x4e, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x43, 0x54,
+	0x49, 0x56, 0x45, 0x10, 0x01, 0x12, 0x1a, 0x0a, 0x16, 0x4d, 0x41, 0x4e, 0x44, 0x41, 0x54, 0x45,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52, 0x45, 0x56, 0x4f, 0x4b, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x1c, 0x0a, 0x18, 0x4d, 0x41, 0x4e, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03, 0x2a,
+	0xbc, 0x01, 0x0a, 0x0d, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x1e, 0x0a, 0x1a, 0x43, 0x4f, 0x4c, 0x4c, 0x45, 0x43, 0x54, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x4f, 0x4c, 0x4c, 0x45, 0x43, 0x54, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x1b, 0x0a,
+	0x17, 0x43, 0x4f, 0x4c, 0x4c, 0x45, 0x43, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x41, 0x50, 0x50, 0x52, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x02, 0x12, 0x1b, 0x0a, 0x17, 0x43, 0x4f,
+	0x4c, 0x4c, 0x45, 0x43, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x44, 0x45, 0x43,
+	0x4c, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x4f, 0x4c, 0x4c, 0x45,
+	0x43, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44,
+	0x10, 0x04, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x4f, 0x4c, 0x4c, 0x45, 0x43, 0x54, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x10, 0x05, 0x2a, 0x4b,
+	0x0a, 0x07, 0x46, 0x65, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x14, 0x46, 0x45, 0x45,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x45, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x53, 0x57, 0x49, 0x54, 0x43, 0x48, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x46, 0x45, 0x45, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x42, 0x41, 0x4e, 0x4b, 0x10, 0x02, 0x2a, 0xcf, 0x01, 0x0a, 0x0a,
+	0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x17, 0x42, 0x41,
+	0x4e, 0x4b, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x42, 0x41, 0x4e, 0x4b, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x01, 0x12,
+	0x18, 0x0a, 0x14, 0x42, 0x41, 0x4e, 0x4b, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x49,
+	0x4e, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x1b, 0x0a, 0x17, 0x42, 0x41, 0x4e,
+	0x4b, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4d, 0x41, 0x49, 0x4e, 0x54, 0x45, 0x4e,
+	0x41, 0x4e, 0x43, 0x45, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x42, 0x41, 0x4e, 0x4b, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x55, 0x53, 0x50, 0x45, 0x4e, 0x44, 0x45, 0x44, 0x10,
+	0x04, 0x12, 0x20, 0x0a, 0x1c, 0x42, 0x41, 0x4e, 0x4b, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x41, 0x50, 0x50, 0x52, 0x4f, 0x56, 0x41,
+	0x4c, 0x10, 0x05, 0x12, 0x18, 0x0a, 0x14, 0x42, 0x41, 0x4e, 0x4b, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x06, 0x2a, 0xb5, 0x01,
+	0x0a, 0x10, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x21, 0x0a, 0x1d, 0x53, 0x45, 0x54, 0x54, 0x4c, 0x45, 0x4d, 0x45, 0x4e, 0x54,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x53, 0x45, 0x54, 0x54, 0x4c, 0x45, 0x4d,
+	0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49,
+	0x4e, 0x47, 0x10, 0x01, 0x12, 0x20, 0x0a, 0x1c, 0x53, 0x45, 0x54, 0x54, 0x4c, 0x45, 0x4d, 0x45,
+	0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x43, 0x45, 0x53,
+	0x53, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x1f, 0x0a, 0x1b, 0x53, 0x45, 0x54, 0x54, 0x4c, 0x45,
+	0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x4f, 0x4d, 0x50,
+	0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x45, 0x54, 0x54, 0x4c,
+	0x45, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x46, 0x41, 0x49,
+	0x4c, 0x45, 0x44, 0x10, 0x04, 0x2a, 0x82, 0x01, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x19, 0x48, 0x45, 0x41, 0x4c, 0x54, 0x48,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x48, 0x45, 0x41, 0x4c, 0x54, 0x48, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x4e, 0x47, 0x10, 0x01,
+	0x12, 0x1d, 0x0a, 0x19, 0x48, 0x45, 0x41, 0x4c, 0x54, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12,
+	0x19, 0x0a, 0x15, 0x48, 0x45, 0x41, 0x4c, 0x54, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x03, 0x32, 0xbd, 0x14, 0x0a, 0x07, 0x55,
+	0x70, 0x69, 0x43, 0x6f, 0x72, 0x65, 0x12, 0x51, 0x0a, 0x12, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x2e, 0x75,
+	0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x75, 0x70, 0x69,
+	0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x14, 0x47, 0x65, 0x74,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x22, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6e, 0x0a, 0x1a, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x30, 0x01, 0x12, 0x5c, 0x0a, 0x11, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x22, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x12, 0x52, 0x65, 0x76, 0x65,
+	0x72, 0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23,
+	0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x76, 0x65, 0x72, 0x73,
+	0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52,
+	0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0a, 0x52, 0x65, 0x73,
+	0x6f, 0x6c, 0x76, 0x65, 0x56, 0x50, 0x41, 0x12, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x56, 0x50, 0x41, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x56, 0x50, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x56, 0x50,
+	0x41, 0x12, 0x1c, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x65, 0x72, 0x56, 0x50, 0x41, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x56, 0x50, 0x41, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44,
+	0x0a, 0x09, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x50, 0x41, 0x12, 0x1a, 0x2e, 0x75, 0x70,
+	0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x50, 0x41,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x50, 0x41, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61,
+	0x74, 0x65, 0x56, 0x50, 0x41, 0x12, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x56, 0x50, 0x41, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x56, 0x50, 0x41, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x12, 0x1d, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42,
+	0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x75,
+	0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x61,
+	0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x4a, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e,
+	0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x09,
+	0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x73, 0x12, 0x1a, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x6e, 0x64,
+	0x61, 0x74, 0x65, 0x12, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x79, 0x4d, 0x61,
+	0x6e, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x79, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x4d, 0x6f, 0x64, 0x69, 0x66, 0x79, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
+	0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x4d,
+	0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47,
+	0x65, 0x74, 0x4d, 0x61, 0x6e, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x50, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65,
+	0x63, 0x74, 0x12, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64, 0x43, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x12, 0x1f, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x43,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x12, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47,
+	0x65, 0x74, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x50, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x75,
+	0x6c, 0x65, 0x12, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x65, 0x65,
+	0x52, 0x75, 0x6c, 0x65, 0x12, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46,
+	0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x46,
+	0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x11, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x63,
+	0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x2e, 0x75, 0x70,
+	0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63,
+	0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x23, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65,
+	0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x68, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x6e,
+	0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12,
+	0x25, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e,
+	0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f,
+	0x0a, 0x12, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x53, 0x65, 0x74,
+	0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x5c, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a,
+	0x13, 0x47, 0x65, 0x74, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x12, 0x21, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x48,
+	0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x1c, 0x2e, 0x75, 0x70, 0x69,
+	0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x18, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xff, 0x06, 0x0a, 0x09, 0x42,
+	0x61, 0x6e, 0x6b, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x4a, 0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b, 0x12, 0x1d, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x6b,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0b, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x42,
+	0x61, 0x6e, 0x6b, 0x12, 0x1c, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x41,
+	0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e,
+	0x6b, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46,
+	0x0a, 0x0a, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x12, 0x1b, 0x2e, 0x75,
+	0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x61,
+	0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0c, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61,
+	0x74, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x12, 0x21, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0e, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69,
+	0x76, 0x61, 0x74, 0x65, 0x42, 0x61, 0x6e, 0x6b, 0x12, 0x21, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70,
+	0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0b, 0x53, 0x75, 0x73, 0x70,
+	0x65, 0x6e, 0x64, 0x42, 0x61, 0x6e, 0x6b, 0x12, 0x21, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69,
+	0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x13, 0x53, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x24,
+	0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x12, 0x54, 0x0a, 0x11, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4d, 0x61, 0x69, 0x6e, 0x74,
+	0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x22, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69,
+	0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x6b, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x4d,
+	0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x73, 0x12, 0x27, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x75, 0x70, 0x69,
+	0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65,
+	0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x12,
+	0x18, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61,
+	0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6e, 0x6b, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61,
+	0x6e, 0x6b, 0x73, 0x12, 0x1a, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x42, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42,
+	0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2c, 0x5a, 0x2a,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x75, 0x75, 0x75, 0x70,
+	0x72, 0x61, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x75, 0x70, 0x69, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_upi_core_proto_rawDescOnce sync.Once
+	file_upi_core_proto_rawDescData = file_upi_core_proto_rawDesc
+)
+
+func file_upi_core_proto_rawDescGZIP() []byte {
+	file_upi_core_proto_rawDescOnce.Do(func() {
+		file_upi_core_proto_rawDescData = protoimpl.X.CompressGZIP(file_upi_core_proto_rawDescData)
+	})
+	return file_upi_core_proto_rawDescData
+}
+
+var file_upi_core_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_upi_core_proto_msgTypes = make([]protoimpl.MessageInfo, 89)
+var file_upi_core_proto_goTypes = []interface{}{
+	(TransactionType)(0),                      // 0: upi_core.TransactionType
+	(TransactionStatus)(0),                    // 1: upi_core.TransactionStatus
+	(MandateFrequency)(0),                     // 2: upi_core.MandateFrequency
+	(MandateStatus)(0),                        // 3: upi_core.MandateStatus
+	(CollectStatus)(0),                        // 4: upi_core.CollectStatus
+	(FeeType)(0),                              // 5: upi_core.FeeType
+	(BankStatus)(0),                           // 6: upi_core.BankStatus
+	(SettlementStatus)(0),                     // 7: upi_core.SettlementStatus
+	(HealthStatus)(0),                         // 8: upi_core.HealthStatus
+	(*TransactionRequest)(nil),                // 9: upi_core.TransactionRequest
+	(*TransactionResponse)(nil),               // 10: upi_core.TransactionResponse
+	(*TransactionStatusRequest)(nil),          // 11: upi_core.TransactionStatusRequest
+	(*TransactionStatusResponse)(nil),         // 12: upi_core.TransactionStatusResponse
+	(*SubscribeTransactionStatusRequest)(nil), // 13: upi_core.SubscribeTransactionStatusRequest
+	(*TransactionStatusUpdate)(nil),           // 14: upi_core.TransactionStatusUpdate
+	(*CancelTransactionRequest)(nil),          // 15: upi_core.CancelTransactionRequest
+	(*CancelTransactionResponse)(nil),         // 16: upi_core.CancelTransactionResponse
+	(*ReverseTransactionRequest)(nil),         // 17: upi_core.ReverseTransactionRequest
+	(*ReverseTransactionResponse)(nil),        // 18: upi_core.ReverseTransactionResponse
+	(*ResolveVPARequest)(nil),                 // 19: upi_core.ResolveVPARequest
+	(*ResolveVPAResponse)(nil),                // 20: upi_core.ResolveVPAResponse
+	(*RegisterVPARequest)(nil),                // 21: upi_core.RegisterVPARequest
+	(*RegisterVPAResponse)(nil),               // 22: upi_core.RegisterVPAResponse
+	(*UpdateVPARequest)(nil),                  // 23: upi_core.UpdateVPARequest
+	(*UpdateVPAResponse)(nil),                 // 24: upi_core.UpdateVPAResponse
+	(*DeactivateVPARequest)(nil),              // 25: upi_core.DeactivateVPARequest
+	(*DeactivateVPAResponse)(nil),             // 26: upi_core.DeactivateVPAResponse
+	(*CreateMandateRequest)(nil),              // 27: upi_core.CreateMandateRequest
+	(*CreateMandateResponse)(nil),             // 28: upi_core.CreateMandateResponse
+	(*ModifyMandateRequest)(nil),              // 29: upi_core.ModifyMandateRequest
+	(*ModifyMandateResponse)(nil),             // 30: upi_core.ModifyMandateResponse
+	(*RevokeMandateRequest)(nil),              // 31: upi_core.RevokeMandateRequest
+	(*RevokeMandateResponse)(nil),             // 32: upi_core.RevokeMandateResponse
+	(*GetMandateRequest)(nil),                 // 33: upi_core.GetMandateRequest
+	(*GetMandateResponse)(nil),                // 34: upi_core.GetMandateResponse
+	(*CreateCollectRequest)(nil),              // 35: upi_core.CreateCollectRequest
+	(*CreateCollectResponse)(nil),             // 36: upi_core.CreateCollectResponse
+	(*RespondCollectRequest)(nil),             // 37: upi_core.RespondCollectRequest
+	(*RespondCollectResponse)(nil),            // 38: upi_core.RespondCollectResponse
+	(*GetCollectRequest)(nil),                 // 39: upi_core.GetCollectRequest
+	(*GetCollectResponse)(nil),                // 40: upi_core.GetCollectResponse
+	(*CreateFeeRuleRequest)(nil),              // 41: upi_core.CreateFeeRuleRequest
+	(*CreateFeeRuleResponse)(nil),             // 42: upi_core.CreateFeeRuleResponse
+	(*UpdateFeeRuleRequest)(nil),              // 43: upi_core.UpdateFeeRuleRequest
+	(*UpdateFeeRuleResponse)(nil),             // 44: upi_core.UpdateFeeRuleResponse
+	(*DeleteFeeRuleRequest)(nil),              // 45: upi_core.DeleteFeeRuleRequest
+	(*DeleteFeeRuleResponse)(nil),             // 46: upi_core.DeleteFeeRuleResponse
+	(*ListFeeRulesRequest)(nil),               // 47: upi_core.ListFeeRulesRequest
+	(*ListFeeRulesResponse)(nil),              // 48: upi_core.ListFeeRulesResponse
+	(*RunReconciliationRequest)(nil),          // 49: upi_core.RunReconciliationRequest
+	(*RunReconciliationResponse)(nil),         // 50: upi_core.RunReconciliationResponse
+	(*ReconciliationReportRequest)(nil),       // 51: upi_core.ReconciliationReportRequest
+	(*ReconciliationReportResponse)(nil),      // 52: upi_core.ReconciliationReportResponse
+	(*RegisterBankRequest)(nil),               // 53: upi_core.RegisterBankRequest
+	(*RegisterBankResponse)(nil),              // 54: upi_core.RegisterBankResponse
+	(*UpdateBankStatusRequest)(nil),           // 55: upi_core.UpdateBankStatusRequest
+	(*UpdateBankStatusResponse)(nil),          // 56: upi_core.UpdateBankStatusResponse
+	(*BankStatusRequest)(nil),                 // 57: upi_core.BankStatusRequest
+	(*BankStatusResponse)(nil),                // 58: upi_core.BankStatusResponse
+	(*ListBanksRequest)(nil),                  // 59: upi_core.ListBanksRequest
+	(*ListBanksResponse)(nil),                 // 60: upi_core.ListBanksResponse
+	(*BankAdminResponse)(nil),                 // 61: upi_core.BankAdminResponse
+	(*ApproveBankRequest)(nil),                // 62: upi_core.ApproveBankRequest
+	(*RejectBankRequest)(nil),                 // 63: upi_core.RejectBankRequest
+	(*BankStatusChangeRequest)(nil),           // 64: upi_core.BankStatusChangeRequest
+	(*ScheduleMaintenanceRequest)(nil),        // 65: upi_core.ScheduleMaintenanceRequest
+	(*CancelMaintenanceRequest)(nil),          // 66: upi_core.CancelMaintenanceRequest
+	(*ListMaintenanceWindowsRequest)(nil),     // 67: upi_core.ListMaintenanceWindowsRequest
+	(*ListMaintenanceWindowsResponse)(nil),    // 68: upi_core.ListMaintenanceWindowsResponse
+	(*MaintenanceWindow)(nil),                 // 69: upi_core.MaintenanceWindow
+	(*GetBankRequest)(nil),                    // 70: upi_core.GetBankRequest
+	(*InitiateSettlementRequest)(nil),         // 71: upi_core.InitiateSettlementRequest
+	(*InitiateSettlementResponse)(nil),        // 72: upi_core.InitiateSettlementResponse
+	(*SettlementStatusRequest)(nil),           // 73: upi_core.SettlementStatusRequest
+	(*SettlementStatusResponse)(nil),          // 74: upi_core.SettlementStatusResponse
+	(*SettlementReportRequest)(nil),           // 75: upi_core.SettlementReportRequest
+	(*SettlementReportResponse)(nil),          // 76: upi_core.SettlementReportResponse
+	(*HealthCheckRequest)(nil),                // 77: upi_core.HealthCheckRequest
+	(*HealthCheckResponse)(nil),               // 78: upi_core.HealthCheckResponse
+	(*MetricsRequest)(nil),                    // 79: upi_core.MetricsRequest
+	(*MetricsResponse)(nil),                   // 80: upi_core.MetricsResponse
+	(*TransactionFees)(nil),                   // 81: upi_core.TransactionFees
+	(*TransactionEvent)(nil),                  // 82: upi_core.TransactionEvent
+	(*BankInfo)(nil),                          // 83: upi_core.BankInfo
+	(*BankSettlement)(nil),                    // 84: upi_core.BankSettlement
+	(*DailySettlement)(nil),                   // 85: upi_core.DailySettlement
+	(*SettlementRecord)(nil),                  // 86: upi_core.SettlementRecord
+	(*Metric)(nil),                            // 87: upi_core.Metric
+	(*Mandate)(nil),                           // 88: upi_core.Mandate
+	(*Collect)(nil),                           // 89: upi_core.Collect
+	(*MandateExecution)(nil),                  // 90: upi_core.MandateExecution
+	(*FeeRule)(nil),                           // 91: upi_core.FeeRule
+	(*ReconciliationRun)(nil),                 // 92: upi_core.ReconciliationRun
+	(*ReconciliationException)(nil),           // 93: upi_core.ReconciliationException
+	nil,                                       // 94: upi_core.TransactionRequest.MetadataEntry
+	nil,                                       // 95: upi_core.HealthCheckResponse.DetailsEntry
+	nil,                                       // 96: upi_core.TransactionEvent.DetailsEntry
+	nil,                                       // 97: upi_core.Metric.LabelsEntry
+	(*timestamppb.Timestamp)(nil),             // 98: google.protobuf.Timestamp
+}
+var file_upi_core_proto_depIdxs = []int32{
+	0,   // 0: upi_core.TransactionRequest.type:type_name -> upi_core.TransactionType
+	98,  // 1: upi_core.TransactionRequest.initiated_at:type_name -> google.protobuf.Timestamp
+	94,  // 2: upi_core.TransactionRequest.metadata:type_name -> upi_core.TransactionRequest.MetadataEntry
+	1,   // 3: upi_core.TransactionResponse.status:type_name -> upi_core.TransactionStatus
+	98,  // 4: upi_core.TransactionResponse.processed_at:type_name -> google.protobuf.Timestamp
+	81,  // 5: upi_core.TransactionResponse.fees:type_name -> upi_core.TransactionFees
+	1,   // 6: upi_core.TransactionStatusResponse.status:type_name -> upi_core.TransactionStatus
+	98,  // 7: upi_core.TransactionStatusResponse.initiated_at:type_name -> google.protobuf.Timestamp
+	98,  // 8: upi_core.TransactionStatusResponse.processed_at:type_name -> google.protobuf.Timestamp
+	82,  // 9: upi_core.TransactionStatusResponse.events:type_name -> upi_core.TransactionEvent
+	1,   // 10: upi_core.TransactionStatusUpdate.status:type_name -> upi_core.TransactionStatus
+	98,  // 11: upi_core.TransactionStatusUpdate.updated_at:type_name -> google.protobuf.Timestamp
+	98,  // 12: upi_core.CancelTransactionResponse.cancelled_at:type_name -> google.protobuf.Timestamp
+	98,  // 13: upi_core.ReverseTransactionResponse.reversed_at:type_name -> google.protobuf.Timestamp
+	98,  // 14: upi_core.RegisterVPAResponse.registered_at:type_name -> google.protobuf.Timestamp
+	98,  // 15: upi_core.UpdateVPAResponse.updated_at:type_name -> google.protobuf.Timestamp
+	98,  // 16: upi_core.DeactivateVPAResponse.deactivated_at:type_name -> google.protobuf.Timestamp
+	2,   // 17: upi_core.CreateMandateRequest.frequency:type_name -> upi_core.MandateFrequency
+	88,  // 18: upi_core.CreateMandateResponse.mandate:type_name -> upi_core.Mandate
+	88,  // 19: upi_core.ModifyMandateResponse.mandate:type_name -> upi_core.Mandate
+	88,  // 20: upi_core.RevokeMandateResponse.mandate:type_name -> upi_core.Mandate
+	88,  // 21: upi_core.GetMandateResponse.mandate:type_name -> upi_core.Mandate
+	90,  // 22: upi_core.GetMandateResponse.executions:type_name -> upi_core.MandateExecution
+	0,   // 23: upi_core.CreateCollectRequest.type:type_name -> upi_core.TransactionType
+	89,  // 24: upi_core.CreateCollectResponse.collect:type_name -> upi_core.Collect
+	98,  // 25: upi_core.RespondCollectRequest.initiated_at:type_name -> google.protobuf.Timestamp
+	89,  // 26: upi_core.RespondCollectResponse.collect:type_name -> upi_core.Collect
+	10,  // 27: upi_core.RespondCollectResponse.transaction:type_name -> upi_core.TransactionResponse
+	89,  // 28: upi_core.GetCollectResponse.collect:type_name -> upi_core.Collect
+	91,  // 29: upi_core.CreateFeeRuleRequest.rule:type_name -> upi_core.FeeRule
+	91,  // 30: upi_core.CreateFeeRuleResponse.rule:type_name -> upi_core.FeeRule
+	91,  // 31: upi_core.UpdateFeeRuleRequest.rule:type_name -> upi_core.FeeRule
+	91,  // 32: upi_core.UpdateFeeRuleResponse.rule:type_name -> upi_core.FeeRule
+	91,  // 33: upi_core.ListFeeRulesResponse.rules:type_name -> upi_core.FeeRule
+	92,  // 34: upi_core.RunReconciliationResponse.run:type_name -> upi_core.ReconciliationRun
+	92,  // 35: upi_core.ReconciliationReportResponse.run:type_name -> upi_core.ReconciliationRun
+	93,  // 36: upi_core.ReconciliationReportResponse.exceptions:type_name -> upi_core.ReconciliationException
+	98,  // 37: upi_core.RegisterBankResponse.registered_at:type_name -> google.protobuf.Timestamp
+	6,   // 38: upi_core.UpdateBankStatusRequest.status:type_name -> upi_core.BankStatus
+	98,  // 39: upi_core.UpdateBankStatusResponse.updated_at:type_name -> google.protobuf.Timestamp
+	6,   // 40: upi_core.BankStatusResponse.status:type_name -> upi_core.BankStatus
+	98,  // 41: upi_core.BankStatusResponse.last_heartbeat:type_name -> google.protobuf.Timestamp
+	6,   // 42: upi_core.ListBanksRequest.status_filter:type_name -> upi_core.BankStatus
+	83,  // 43: upi_core.ListBanksResponse.banks:type_name -> upi_core.BankInfo
+	83,  // 44: upi_core.BankAdminResponse.bank:type_name -> upi_core.BankInfo
+	98,  // 45: upi_core.ScheduleMaintenanceRequest.starts_at:type_name -> google.protobuf.Timestamp
+	98,  // 46: upi_core.ScheduleMaintenanceRequest.ends_at:type_name -> google.protobuf.Timestamp
+	69,  // 47: upi_core.ListMaintenanceWindowsResponse.windows:type_name -> upi_core.MaintenanceWindow
+	98,  // 48: upi_core.MaintenanceWindow.starts_at:type_name -> google.protobuf.Timestamp
+	98,  // 49: upi_core.MaintenanceWindow.ends_at:type_name -> google.protobuf.Timestamp
+	98,  // 50: upi_core.MaintenanceWindow.cancelled_at:type_name -> google.protobuf.Timestamp
+	98,  // 51: upi_core.InitiateSettlementRequest.settlement_date:type_name -> google.protobuf.Timestamp
+	98,  // 52: upi_core.InitiateSettlementResponse.initiated_at:type_name -> google.protobuf.Timestamp
+	7,   // 53: upi_core.SettlementStatusResponse.status:type_name -> upi_core.SettlementStatus
+	84,  // 54: upi_core.SettlementStatusResponse.bank_settlements:type_name -> upi_core.BankSettlement
+	98,  // 55: upi_core.SettlementStatusResponse.created_at:type_name -> google.protobuf.Timestamp
+	98,  // 56: upi_core.SettlementStatusResponse.completed_at:type_name -> google.protobuf.Timestamp
+	98,  // 57: upi_core.SettlementReportRequest.from_date:type_name -> google.protobuf.Timestamp
+	98,  // 58: upi_core.SettlementReportRequest.to_date:type_name -> google.protobuf.Timestamp
+	85,  // 59: upi_core.SettlementReportResponse.daily_settlements:type_name -> upi_core.DailySettlement
+	86,  // 60: upi_core.SettlementReportResponse.records:type_name -> upi_core.SettlementRecord
+	8,   // 61: upi_core.HealthCheckResponse.status:type_name -> upi_core.HealthStatus
+	95,  // 62: upi_core.HealthCheckResponse.details:type_name -> upi_core.HealthCheckResponse.DetailsEntry
+	98,  // 63: upi_core.HealthCheckResponse.timestamp:type_name -> google.protobuf.Timestamp
+	98,  // 64: upi_core.MetricsRequest.from_time:type_name -> google.protobuf.Timestamp
+	98,  // 65: upi_core.MetricsRequest.to_time:type_name -> google.protobuf.Timestamp
+	87,  // 66: upi_core.MetricsResponse.metrics:type_name -> upi_core.Metric
+	98,  // 67: upi_core.MetricsResponse.generated_at:type_name -> google.protobuf.Timestamp
+	98,  // 68: upi_core.TransactionEvent.timestamp:type_name -> google.protobuf.Timestamp
+	96,  // 69: upi_core.TransactionEvent.details:type_name -> upi_core.TransactionEvent.DetailsEntry
+	6,   // 70: upi_core.BankInfo.status:type_name -> upi_core.BankStatus
+	98,  // 71: upi_core.BankInfo.registered_at:type_name -> google.protobuf.Timestamp
+	98,  // 72: upi_core.BankInfo.approved_at:type_name -> google.protobuf.Timestamp
+	7,   // 73: upi_core.BankSettlement.status:type_name -> upi_core.SettlementStatus
+	98,  // 74: upi_core.SettlementRecord.processed_at:type_name -> google.protobuf.Timestamp
+	97,  // 75: upi_core.Metric.labels:type_name -> upi_core.Metric.LabelsEntry
+	98,  // 76: upi_core.Metric.timestamp:type_name -> google.protobuf.Timestamp
+	2,   // 77: upi_core.Mandate.frequency:type_name -> upi_core.MandateFrequency
+	3,   // 78: upi_core.Mandate.status:type_name -> upi_core.MandateStatus
+	98,  // 79: upi_core.Mandate.created_at:type_name -> google.protobuf.Timestamp
+	98,  // 80: upi_core.Mandate.updated_at:type_name -> google.protobuf.Timestamp
+	0,   // 81: upi_core.Collect.type:type_name -> upi_core.TransactionType
+	4,   // 82: upi_core.Collect.status:type_name -> upi_core.CollectStatus
+	98,  // 83: upi_core.Collect.expires_at:type_name -> google.protobuf.Timestamp
+	98,  // 84: upi_core.Collect.delivered_at:type_name -> google.protobuf.Timestamp
+	98,  // 85: upi_core.Collect.responded_at:type_name -> google.protobuf.Timestamp
+	98,  // 86: upi_core.Collect.created_at:type_name -> google.protobuf.Timestamp
+	98,  // 87: upi_core.MandateExecution.executed_at:type_name -> google.protobuf.Timestamp
+	5,   // 88: upi_core.FeeRule.fee_type:type_name -> upi_core.FeeType
+	0,   // 89: upi_core.FeeRule.transaction_type:type_name -> upi_core.TransactionType
+	98,  // 90: upi_core.FeeRule.created_at:type_name -> google.protobuf.Timestamp
+	98,  // 91: upi_core.FeeRule.updated_at:type_name -> google.protobuf.Timestamp
+	98,  // 92: upi_core.ReconciliationRun.started_at:type_name -> google.protobuf.Timestamp
+	98,  // 93: upi_core.ReconciliationRun.completed_at:type_name -> google.protobuf.Timestamp
+	1,   // 94: upi_core.ReconciliationException.switch_status:type_name -> upi_core.TransactionStatus
+	98,  // 95: upi_core.ReconciliationException.detected_at:type_name -> google.protobuf.Timestamp
+	9,   // 96: upi_core.UpiCore.ProcessTransaction:input_type -> upi_core.TransactionRequest
+	11,  // 97: upi_core.UpiCore.GetTransactionStatus:input_type -> upi_core.TransactionStatusRequest
+	13,  // 98: upi_core.UpiCore.SubscribeTransactionStatus:input_type -> upi_core.SubscribeTransactionStatusRequest
+	15,  // 99: upi_core.UpiCore.CancelTransaction:input_type -> upi_core.CancelTransactionRequest
+	17,  // 100: upi_core.UpiCore.ReverseTransaction:input_type -> upi_core.ReverseTransactionRequest
+	19,  // 101: upi_core.UpiCore.ResolveVPA:input_type -> upi_core.ResolveVPARequest
+	21,  // 102: upi_core.UpiCore.RegisterVPA:input_type -> upi_core.RegisterVPARequest
+	23,  // 103: upi_core.UpiCore.UpdateVPA:input_type -> upi_core.UpdateVPARequest
+	25,  // 104: upi_core.UpiCore.DeactivateVPA:input_type -> upi_core.DeactivateVPARequest
+	53,  // 105: upi_core.UpiCore.RegisterBank:input_type -> upi_core.RegisterBankRequest
+	55,  // 106: upi_core.UpiCore.UpdateBankStatus:input_type -> upi_core.UpdateBankStatusRequest
+	57,  // 107: upi_core.UpiCore.GetBankStatus:input_type -> upi_core.BankStatusRequest
+	59,  // 108: upi_core.UpiCore.ListBanks:input_type -> upi_core.ListBanksRequest
+	27,  // 109: upi_core.UpiCore.CreateMandate:input_type -> upi_core.CreateMandateRequest
+	29,  // 110: upi_core.UpiCore.ModifyMandate:input_type -> upi_core.ModifyMandateRequest
+	31,  // 111: upi_core.UpiCore.RevokeMandate:input_type -> upi_core.RevokeMandateRequest
+	33,  // 112: upi_core.UpiCore.GetMandate:input_type -> upi_core.GetMandateRequest
+	35,  // 113: upi_core.UpiCore.CreateCollect:input_type -> upi_core.CreateCollectRequest
+	37,  // 114: upi_core.UpiCore.RespondCollect:input_type -> upi_core.RespondCollectRequest
+	39,  // 115: upi_core.UpiCore.GetCollect:input_type -> upi_core.GetCollectRequest
+	41,  // 116: upi_core.UpiCore.CreateFeeRule:input_type -> upi_core.CreateFeeRuleRequest
+	43,  // 117: upi_core.UpiCore.UpdateFeeRule:input_type -> upi_core.UpdateFeeRuleRequest
+	45,  // 118: upi_core.UpiCore.DeleteFeeRule:input_type -> upi_core.DeleteFeeRuleRequest
+	47,  // 119: upi_core.UpiCore.ListFeeRules:input_type -> upi_core.ListFeeRulesRequest
+	49,  // 120: upi_core.UpiCore.RunReconciliation:input_type -> upi_core.RunReconciliationRequest
+	51,  // 121: upi_core.UpiCore.GetReconciliationReport:input_type -> upi_core.ReconciliationReportRequest
+	71,  // 122: upi_core.UpiCore.InitiateSettlement:input_type -> upi_core.InitiateSettlementRequest
+	73,  // 123: upi_core.UpiCore.GetSettlementStatus:input_type -> upi_core.SettlementStatusRequest
+	75,  // 124: upi_core.UpiCore.GetSettlementReport:input_type -> upi_core.SettlementReportRequest
+	77,  // 125: upi_core.UpiCore.HealthCheck:input_type -> upi_core.HealthCheckRequest
+	79,  // 126: upi_core.UpiCore.GetMetrics:input_type -> upi_core.MetricsRequest
+	53,  // 127: upi_core.BankAdmin.RegisterBank:input_type -> upi_core.RegisterBankRequest
+	62,  // 128: upi_core.BankAdmin.ApproveBank:input_type -> upi_core.ApproveBankRequest
+	63,  // 129: upi_core.BankAdmin.RejectBank:input_type -> upi_core.RejectBankRequest
+	64,  // 130: upi_core.BankAdmin.ActivateBank:input_type -> upi_core.BankStatusChangeRequest
+	64,  // 131: upi_core.BankAdmin.DeactivateBank:input_type -> upi_core.BankStatusChangeRequest
+	64,  // 132: upi_core.BankAdmin.SuspendBank:input_type -> upi_core.BankStatusChangeRequest
+	65,  // 133: upi_core.BankAdmin.ScheduleMaintenance:input_type -> upi_core.ScheduleMaintenanceRequest
+	66,  // 134: upi_core.BankAdmin.CancelMaintenance:input_type -> upi_core.CancelMaintenanceRequest
+	67,  // 135: upi_core.BankAdmin.ListMaintenanceWindows:input_type -> upi_core.ListMaintenanceWindowsRequest
+	70,  // 136: upi_core.BankAdmin.GetBank:input_type -> upi_core.GetBankRequest
+	59,  // 137: upi_core.BankAdmin.ListBanks:input_type -> upi_core.ListBanksRequest
+	10,  // 138: upi_core.UpiCore.ProcessTransaction:output_type -> upi_core.TransactionResponse
+	12,  // 139: upi_core.UpiCore.GetTransactionStatus:output_type -> upi_core.TransactionStatusResponse
+	14,  // 140: upi_core.UpiCore.SubscribeTransactionStatus:output_type -> upi_core.TransactionStatusUpdate
+	16,  // 141: upi_core.UpiCore.CancelTransaction:output_type -> upi_core.CancelTransactionResponse
+	18,  // 142: upi_core.UpiCore.ReverseTransaction:output_type -> upi_core.ReverseTransactionResponse
+	20,  // 143: upi_core.UpiCore.ResolveVPA:output_type -> upi_core.ResolveVPAResponse
+	22,  // 144: upi_core.UpiCore.RegisterVPA:output_type -> upi_core.RegisterVPAResponse
+	24,  // 145: upi_core.UpiCore.UpdateVPA:output_type -> upi_core.UpdateVPAResponse
+	26,  // 146: upi_core.UpiCore.DeactivateVPA:output_type -> upi_core.DeactivateVPAResponse
+	54,  // 147: upi_core.UpiCore.RegisterBank:output_type -> upi_core.RegisterBankResponse
+	56,  // 148: upi_core.UpiCore.UpdateBankStatus:output_type -> upi_core.UpdateBankStatusResponse
+	58,  // 149: upi_core.UpiCore.GetBankStatus:output_type -> upi_core.BankStatusResponse
+	60,  // 150: upi_core.UpiCore.ListBanks:output_type -> upi_core.ListBanksResponse
+	28,  // 151: upi_core.UpiCore.CreateMandate:output_type -> upi_core.CreateMandateResponse
+	30,  // 152: upi_core.UpiCore.ModifyMandate:output_type -> upi_core.ModifyMandateResponse
+	32,  // 153: upi_core.UpiCore.RevokeMandate:output_type -> upi_core.RevokeMandateResponse
+	34,  // 154: upi_core.UpiCore.GetMandate:output_type -> upi_core.GetMandateResponse
+	36,  // 155: upi_core.UpiCore.CreateCollect:output_type -> upi_core.CreateCollectResponse
+	38,  // 156: upi_core.UpiCore.RespondCollect:output_type -> upi_core.RespondCollectResponse
+	40,  // 157: upi_core.UpiCore.GetCollect:output_type -> upi_core.GetCollectResponse
+	42,  // 158: upi_core.UpiCore.CreateFeeRule:output_type -> upi_core.CreateFeeRuleResponse
+	44,  // 159: upi_core.UpiCore.UpdateFeeRule:output_type -> upi_core.UpdateFeeRuleResponse
+	46,  // 160: upi_core.UpiCore.DeleteFeeRule:output_type -> upi_core.DeleteFeeRuleResponse
+	48,  // 161: upi_core.UpiCore.ListFeeRules:output_type -> upi_core.ListFeeRulesResponse
+	50,  // 162: upi_core.UpiCore.RunReconciliation:output_type -> upi_core.RunReconciliationResponse
+	52,  // 163: upi_core.UpiCore.GetReconciliationReport:output_type -> upi_core.ReconciliationReportResponse
+	72,  // 164: upi_core.UpiCore.InitiateSettlement:output_type -> upi_core.InitiateSettlementResponse
+	74,  // 165: upi_core.UpiCore.GetSettlementStatus:output_type -> upi_core.SettlementStatusResponse
+	76,  // 166: upi_core.UpiCore.GetSettlementReport:output_type -> upi_core.SettlementReportResponse
+	78,  // 167: upi_core.UpiCore.HealthCheck:output_type -> upi_core.HealthCheckResponse
+	80,  // 168: upi_core.UpiCore.GetMetrics:output_type -> upi_core.MetricsResponse
+	61,  // 169: upi_core.BankAdmin.RegisterBank:output_type -> upi_core.BankAdminResponse
+	61,  // 170: upi_core.BankAdmin.ApproveBank:output_type -> upi_core.BankAdminResponse
+	61,  // 171: upi_core.BankAdmin.RejectBank:output_type -> upi_core.BankAdminResponse
+	61,  // 172: upi_core.BankAdmin.ActivateBank:output_type -> upi_core.BankAdminResponse
+	61,  // 173: upi_core.BankAdmin.DeactivateBank:output_type -> upi_core.BankAdminResponse
+	61,  // 174: upi_core.BankAdmin.SuspendBank:output_type -> upi_core.BankAdminResponse
+	69,  // 175: upi_core.BankAdmin.ScheduleMaintenance:output_type -> upi_core.MaintenanceWindow
+	69,  // 176: upi_core.BankAdmin.CancelMaintenance:output_type -> upi_core.MaintenanceWindow
+	68,  // 177: upi_core.BankAdmin.ListMaintenanceWindows:output_type -> upi_core.ListMaintenanceWindowsResponse
+	61,  // 178: upi_core.BankAdmin.GetBank:output_type -> upi_core.BankAdminResponse
+	60,  // 179: upi_core.BankAdmin.ListBanks:output_type -> upi_core.ListBanksResponse
+	138, // [138:180] is the sub-list for method output_type
+	96,  // [96:138] is the sub-list for method input_type
+	96,  // [96:96] is the sub-list for extension type_name
+	96,  // [96:96] is the sub-list for extension extendee
+	0,   // [0:96] is the sub-list for field type_name
+}
+
+func init() { file_upi_core_proto_init() }
+func file_upi_core_proto_init() {
+	if File_upi_core_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_upi_core_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeTransactionStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionStatusUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelTransactionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelTransactionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReverseTransactionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReverseTransactionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolveVPARequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolveVPAResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterVPARequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterVPAResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateVPARequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateVPAResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeactivateVPARequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeactivateVPAResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateMandateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateMandateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModifyMandateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModifyMandateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeMandateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeMandateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMandateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMandateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateCollectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateCollectResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RespondCollectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RespondCollectResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCollectRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCollectResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateFeeRuleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateFeeRuleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFeeRuleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateFeeRuleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteFeeRuleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteFeeRuleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFeeRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFeeRulesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunReconciliationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunReconciliationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconciliationReportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconciliationReportResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterBankRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterBankResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateBankStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateBankStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBanksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBanksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankAdminResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApproveBankRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RejectBankRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankStatusChangeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScheduleMaintenanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelMaintenanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListMaintenanceWindowsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListMaintenanceWindowsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaintenanceWindow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBankRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InitiateSettlementRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InitiateSettlementResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SettlementStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SettlementStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SettlementReportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SettlementReportResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HealthCheckRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HealthCheckResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetricsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetricsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionFees); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BankSettlement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DailySettlement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[77].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SettlementRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[78].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Metric); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[79].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Mandate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[80].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Collect); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[81].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MandateExecution); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[82].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FeeRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[83].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconciliationRun); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upi_core_proto_msgTypes[84].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconciliationException); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_upi_core_proto_rawDesc), len(file_proto_upi_core_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   24,
+			RawDescriptor: file_upi_core_proto_rawDesc,
+			NumEnums:      9,
+			NumMessages:   89,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
-		GoTypes:           file_proto_upi_core_proto_goTypes,
-		DependencyIndexes: file_proto_upi_core_proto_depIdxs,
-		EnumInfos:         file_proto_upi_core_proto_enumTypes,
-		MessageInfos:      file_proto_upi_core_proto_msgTypes,
+		GoTypes:           file_upi_core_proto_goTypes,
+		DependencyIndexes: file_upi_core_proto_depIdxs,
+		EnumInfos:         file_upi_core_proto_enumTypes,
+		MessageInfos:      file_upi_core_proto_msgTypes,
 	}.Build()
-	File_proto_upi_core_proto = out.File
-	file_proto_upi_core_proto_goTypes = nil
-	file_proto_upi_core_proto_depIdxs = nil
+	File_upi_core_proto = out.File
+	file_upi_core_proto_rawDesc = nil
+	file_upi_core_proto_goTypes = nil
+	file_upi_core_proto_depIdxs = nil
 }
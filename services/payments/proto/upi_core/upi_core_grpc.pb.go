@@ -1,8 +1,11 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
-// - protoc-gen-go-grpc v1.5.1
+// - protoc-gen-go-grpc v1.3.0
 // - protoc             v6.32.0
-// source: proto/upi_core.proto
+// source: upi_core.proto
+
+// Copied from services/upi-core/proto/upi_core.proto by
+// scripts/generate-proto.sh. DO NOT EDIT; change it in UPI Core.
 
 package upi_core
 
@@ -15,31 +18,52 @@ import (
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.64.0 or later.
-const _ = grpc.SupportPackageIsVersion9
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
 
 const (
-	UpiCore_ProcessTransaction_FullMethodName   = "/upi.core.UpiCore/ProcessTransaction"
-	UpiCore_GetTransactionStatus_FullMethodName = "/upi.core.UpiCore/GetTransactionStatus"
-	UpiCore_CancelTransaction_FullMethodName    = "/upi.core.UpiCore/CancelTransaction"
-	UpiCore_ReverseTransaction_FullMethodName   = "/upi.core.UpiCore/ReverseTransaction"
-	UpiCore_ResolveVPA_FullMethodName           = "/upi.core.UpiCore/ResolveVPA"
-	UpiCore_RegisterVPA_FullMethodName          = "/upi.core.UpiCore/RegisterVPA"
-	UpiCore_UpdateVPA_FullMethodName            = "/upi.core.UpiCore/UpdateVPA"
-	UpiCore_DeactivateVPA_FullMethodName        = "/upi.core.UpiCore/DeactivateVPA"
-	UpiCore_HealthCheck_FullMethodName          = "/upi.core.UpiCore/HealthCheck"
-	UpiCore_GetMetrics_FullMethodName           = "/upi.core.UpiCore/GetMetrics"
+	UpiCore_ProcessTransaction_FullMethodName         = "/upi_core.UpiCore/ProcessTransaction"
+	UpiCore_GetTransactionStatus_FullMethodName       = "/upi_core.UpiCore/GetTransactionStatus"
+	UpiCore_SubscribeTransactionStatus_FullMethodName = "/upi_core.UpiCore/SubscribeTransactionStatus"
+	UpiCore_CancelTransaction_FullMethodName          = "/upi_core.UpiCore/CancelTransaction"
+	UpiCore_ReverseTransaction_FullMethodName         = "/upi_core.UpiCore/ReverseTransaction"
+	UpiCore_ResolveVPA_FullMethodName                 = "/upi_core.UpiCore/ResolveVPA"
+	UpiCore_RegisterVPA_FullMethodName                = "/upi_core.UpiCore/RegisterVPA"
+	UpiCore_UpdateVPA_FullMethodName                  = "/upi_core.UpiCore/UpdateVPA"
+	UpiCore_DeactivateVPA_FullMethodName              = "/upi_core.UpiCore/DeactivateVPA"
+	UpiCore_RegisterBank_FullMethodName               = "/upi_core.UpiCore/RegisterBank"
+	UpiCore_UpdateBankStatus_FullMethodName           = "/upi_core.UpiCore/UpdateBankStatus"
+	UpiCore_GetBankStatus_FullMethodName              = "/upi_core.UpiCore/GetBankStatus"
+	UpiCore_ListBanks_FullMethodName                  = "/upi_core.UpiCore/ListBanks"
+	UpiCore_CreateMandate_FullMethodName              = "/upi_core.UpiCore/CreateMandate"
+	UpiCore_ModifyMandate_FullMethodName              = "/upi_core.UpiCore/ModifyMandate"
+	UpiCore_RevokeMandate_FullMethodName              = "/upi_core.UpiCore/RevokeMandate"
+	UpiCore_GetMandate_FullMethodName                 = "/upi_core.UpiCore/GetMandate"
+	UpiCore_CreateCollect_FullMethodName              = "/upi_core.UpiCore/CreateCollect"
+	UpiCore_RespondCollect_FullMethodName             = "/upi_core.UpiCore/RespondCollect"
+	UpiCore_GetCollect_FullMethodName                 = "/upi_core.UpiCore/GetCollect"
+	UpiCore_CreateFeeRule_FullMethodName              = "/upi_core.UpiCore/CreateFeeRule"
+	UpiCore_UpdateFeeRule_FullMethodName              = "/upi_core.UpiCore/UpdateFeeRule"
+	UpiCore_DeleteFeeRule_FullMethodName              = "/upi_core.UpiCore/DeleteFeeRule"
+	UpiCore_ListFeeRules_FullMethodName               = "/upi_core.UpiCore/ListFeeRules"
+	UpiCore_RunReconciliation_FullMethodName          = "/upi_core.UpiCore/RunReconciliation"
+	UpiCore_GetReconciliationReport_FullMethodName    = "/upi_core.UpiCore/GetReconciliationReport"
+	UpiCore_InitiateSettlement_FullMethodName         = "/upi_core.UpiCore/InitiateSettlement"
+	UpiCore_GetSettlementStatus_FullMethodName        = "/upi_core.UpiCore/GetSettlementStatus"
+	UpiCore_GetSettlementReport_FullMethodName        = "/upi_core.UpiCore/GetSettlementReport"
+	UpiCore_HealthCheck_FullMethodName                = "/upi_core.UpiCore/HealthCheck"
+	UpiCore_GetMetrics_FullMethodName                 = "/upi_core.UpiCore/GetMetrics"
 )
 
 // UpiCoreClient is the client API for UpiCore service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-//
-// UPI Core Service Definition
 type UpiCoreClient interface {
 	// Transaction Processing
 	ProcessTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
 	GetTransactionStatus(ctx context.Context, in *TransactionStatusRequest, opts ...grpc.CallOption) (*TransactionStatusResponse, error)
+	// Streams the transaction's current status, then each change of it, ending once it is final
+	SubscribeTransactionStatus(ctx context.Context, in *SubscribeTransactionStatusRequest, opts ...grpc.CallOption) (UpiCore_SubscribeTransactionStatusClient, error)
 	CancelTransaction(ctx context.Context, in *CancelTransactionRequest, opts ...grpc.CallOption) (*CancelTransactionResponse, error)
 	ReverseTransaction(ctx context.Context, in *ReverseTransactionRequest, opts ...grpc.CallOption) (*ReverseTransactionResponse, error)
 	// VPA Management
@@ -47,6 +71,32 @@ type UpiCoreClient interface {
 	RegisterVPA(ctx context.Context, in *RegisterVPARequest, opts ...grpc.CallOption) (*RegisterVPAResponse, error)
 	UpdateVPA(ctx context.Context, in *UpdateVPARequest, opts ...grpc.CallOption) (*UpdateVPAResponse, error)
 	DeactivateVPA(ctx context.Context, in *DeactivateVPARequest, opts ...grpc.CallOption) (*DeactivateVPAResponse, error)
+	// Bank Operations
+	RegisterBank(ctx context.Context, in *RegisterBankRequest, opts ...grpc.CallOption) (*RegisterBankResponse, error)
+	UpdateBankStatus(ctx context.Context, in *UpdateBankStatusRequest, opts ...grpc.CallOption) (*UpdateBankStatusResponse, error)
+	GetBankStatus(ctx context.Context, in *BankStatusRequest, opts ...grpc.CallOption) (*BankStatusResponse, error)
+	ListBanks(ctx context.Context, in *ListBanksRequest, opts ...grpc.CallOption) (*ListBanksResponse, error)
+	// Mandate Management
+	CreateMandate(ctx context.Context, in *CreateMandateRequest, opts ...grpc.CallOption) (*CreateMandateResponse, error)
+	ModifyMandate(ctx context.Context, in *ModifyMandateRequest, opts ...grpc.CallOption) (*ModifyMandateResponse, error)
+	RevokeMandate(ctx context.Context, in *RevokeMandateRequest, opts ...grpc.CallOption) (*RevokeMandateResponse, error)
+	GetMandate(ctx context.Context, in *GetMandateRequest, opts ...grpc.CallOption) (*GetMandateResponse, error)
+	// Collect Requests
+	CreateCollect(ctx context.Context, in *CreateCollectRequest, opts ...grpc.CallOption) (*CreateCollectResponse, error)
+	RespondCollect(ctx context.Context, in *RespondCollectRequest, opts ...grpc.CallOption) (*RespondCollectResponse, error)
+	GetCollect(ctx context.Context, in *GetCollectRequest, opts ...grpc.CallOption) (*GetCollectResponse, error)
+	// Fee Administration
+	CreateFeeRule(ctx context.Context, in *CreateFeeRuleRequest, opts ...grpc.CallOption) (*CreateFeeRuleResponse, error)
+	UpdateFeeRule(ctx context.Context, in *UpdateFeeRuleRequest, opts ...grpc.CallOption) (*UpdateFeeRuleResponse, error)
+	DeleteFeeRule(ctx context.Context, in *DeleteFeeRuleRequest, opts ...grpc.CallOption) (*DeleteFeeRuleResponse, error)
+	ListFeeRules(ctx context.Context, in *ListFeeRulesRequest, opts ...grpc.CallOption) (*ListFeeRulesResponse, error)
+	// Reconciliation
+	RunReconciliation(ctx context.Context, in *RunReconciliationRequest, opts ...grpc.CallOption) (*RunReconciliationResponse, error)
+	GetReconciliationReport(ctx context.Context, in *ReconciliationReportRequest, opts ...grpc.CallOption) (*ReconciliationReportResponse, error)
+	// Settlement Operations
+	InitiateSettlement(ctx context.Context, in *InitiateSettlementRequest, opts ...grpc.CallOption) (*InitiateSettlementResponse, error)
+	GetSettlementStatus(ctx context.Context, in *SettlementStatusRequest, opts ...grpc.CallOption) (*SettlementStatusResponse, error)
+	GetSettlementReport(ctx context.Context, in *SettlementReportRequest, opts ...grpc.CallOption) (*SettlementReportResponse, error)
 	// Health and Monitoring
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
 	GetMetrics(ctx context.Context, in *MetricsRequest, opts ...grpc.CallOption) (*MetricsResponse, error)
@@ -61,9 +111,8 @@ func NewUpiCoreClient(cc grpc.ClientConnInterface) UpiCoreClient {
 }
 
 func (c *upiCoreClient) ProcessTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TransactionResponse)
-	err := c.cc.Invoke(ctx, UpiCore_ProcessTransaction_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_ProcessTransaction_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -71,19 +120,49 @@ func (c *upiCoreClient) ProcessTransaction(ctx context.Context, in *TransactionR
 }
 
 func (c *upiCoreClient) GetTransactionStatus(ctx context.Context, in *TransactionStatusRequest, opts ...grpc.CallOption) (*TransactionStatusResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TransactionStatusResponse)
-	err := c.cc.Invoke(ctx, UpiCore_GetTransactionStatus_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_GetTransactionStatus_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
+func (c *upiCoreClient) SubscribeTransactionStatus(ctx context.Context, in *SubscribeTransactionStatusRequest, opts ...grpc.CallOption) (UpiCore_SubscribeTransactionStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &UpiCore_ServiceDesc.Streams[0], UpiCore_SubscribeTransactionStatus_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &upiCoreSubscribeTransactionStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UpiCore_SubscribeTransactionStatusClient interface {
+	Recv() (*TransactionStatusUpdate, error)
+	grpc.ClientStream
+}
+
+type upiCoreSubscribeTransactionStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *upiCoreSubscribeTransactionStatusClient) Recv() (*TransactionStatusUpdate, error) {
+	m := new(TransactionStatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *upiCoreClient) CancelTransaction(ctx context.Context, in *CancelTransactionRequest, opts ...grpc.CallOption) (*CancelTransactionResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CancelTransactionResponse)
-	err := c.cc.Invoke(ctx, UpiCore_CancelTransaction_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_CancelTransaction_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -91,9 +170,8 @@ func (c *upiCoreClient) CancelTransaction(ctx context.Context, in *CancelTransac
 }
 
 func (c *upiCoreClient) ReverseTransaction(ctx context.Context, in *ReverseTransactionRequest, opts ...grpc.CallOption) (*ReverseTransactionResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ReverseTransactionResponse)
-	err := c.cc.Invoke(ctx, UpiCore_ReverseTransaction_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_ReverseTransaction_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -101,9 +179,8 @@ func (c *upiCoreClient) ReverseTransaction(ctx context.Context, in *ReverseTrans
 }
 
 func (c *upiCoreClient) ResolveVPA(ctx context.Context, in *ResolveVPARequest, opts ...grpc.CallOption) (*ResolveVPAResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ResolveVPAResponse)
-	err := c.cc.Invoke(ctx, UpiCore_ResolveVPA_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_ResolveVPA_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -111,9 +188,8 @@ func (c *upiCoreClient) ResolveVPA(ctx context.Context, in *ResolveVPARequest, o
 }
 
 func (c *upiCoreClient) RegisterVPA(ctx context.Context, in *RegisterVPARequest, opts ...grpc.CallOption) (*RegisterVPAResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RegisterVPAResponse)
-	err := c.cc.Invoke(ctx, UpiCore_RegisterVPA_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_RegisterVPA_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -121,9 +197,8 @@ func (c *upiCoreClient) RegisterVPA(ctx context.Context, in *RegisterVPARequest,
 }
 
 func (c *upiCoreClient) UpdateVPA(ctx context.Context, in *UpdateVPARequest, opts ...grpc.CallOption) (*UpdateVPAResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateVPAResponse)
-	err := c.cc.Invoke(ctx, UpiCore_UpdateVPA_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_UpdateVPA_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -131,9 +206,188 @@ func (c *upiCoreClient) UpdateVPA(ctx context.Context, in *UpdateVPARequest, opt
 }
 
 func (c *upiCoreClient) DeactivateVPA(ctx context.Context, in *DeactivateVPARequest, opts ...grpc.CallOption) (*DeactivateVPAResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeactivateVPAResponse)
-	err := c.cc.Invoke(ctx, UpiCore_DeactivateVPA_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_DeactivateVPA_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) RegisterBank(ctx context.Context, in *RegisterBankRequest, opts ...grpc.CallOption) (*RegisterBankResponse, error) {
+	out := new(RegisterBankResponse)
+	err := c.cc.Invoke(ctx, UpiCore_RegisterBank_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) UpdateBankStatus(ctx context.Context, in *UpdateBankStatusRequest, opts ...grpc.CallOption) (*UpdateBankStatusResponse, error) {
+	out := new(UpdateBankStatusResponse)
+	err := c.cc.Invoke(ctx, UpiCore_UpdateBankStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) GetBankStatus(ctx context.Context, in *BankStatusRequest, opts ...grpc.CallOption) (*BankStatusResponse, error) {
+	out := new(BankStatusResponse)
+	err := c.cc.Invoke(ctx, UpiCore_GetBankStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) ListBanks(ctx context.Context, in *ListBanksRequest, opts ...grpc.CallOption) (*ListBanksResponse, error) {
+	out := new(ListBanksResponse)
+	err := c.cc.Invoke(ctx, UpiCore_ListBanks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) CreateMandate(ctx context.Context, in *CreateMandateRequest, opts ...grpc.CallOption) (*CreateMandateResponse, error) {
+	out := new(CreateMandateResponse)
+	err := c.cc.Invoke(ctx, UpiCore_CreateMandate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) ModifyMandate(ctx context.Context, in *ModifyMandateRequest, opts ...grpc.CallOption) (*ModifyMandateResponse, error) {
+	out := new(ModifyMandateResponse)
+	err := c.cc.Invoke(ctx, UpiCore_ModifyMandate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) RevokeMandate(ctx context.Context, in *RevokeMandateRequest, opts ...grpc.CallOption) (*RevokeMandateResponse, error) {
+	out := new(RevokeMandateResponse)
+	err := c.cc.Invoke(ctx, UpiCore_RevokeMandate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) GetMandate(ctx context.Context, in *GetMandateRequest, opts ...grpc.CallOption) (*GetMandateResponse, error) {
+	out := new(GetMandateResponse)
+	err := c.cc.Invoke(ctx, UpiCore_GetMandate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) CreateCollect(ctx context.Context, in *CreateCollectRequest, opts ...grpc.CallOption) (*CreateCollectResponse, error) {
+	out := new(CreateCollectResponse)
+	err := c.cc.Invoke(ctx, UpiCore_CreateCollect_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) RespondCollect(ctx context.Context, in *RespondCollectRequest, opts ...grpc.CallOption) (*RespondCollectResponse, error) {
+	out := new(RespondCollectResponse)
+	err := c.cc.Invoke(ctx, UpiCore_RespondCollect_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) GetCollect(ctx context.Context, in *GetCollectRequest, opts ...grpc.CallOption) (*GetCollectResponse, error) {
+	out := new(GetCollectResponse)
+	err := c.cc.Invoke(ctx, UpiCore_GetCollect_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) CreateFeeRule(ctx context.Context, in *CreateFeeRuleRequest, opts ...grpc.CallOption) (*CreateFeeRuleResponse, error) {
+	out := new(CreateFeeRuleResponse)
+	err := c.cc.Invoke(ctx, UpiCore_CreateFeeRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) UpdateFeeRule(ctx context.Context, in *UpdateFeeRuleRequest, opts ...grpc.CallOption) (*UpdateFeeRuleResponse, error) {
+	out := new(UpdateFeeRuleResponse)
+	err := c.cc.Invoke(ctx, UpiCore_UpdateFeeRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) DeleteFeeRule(ctx context.Context, in *DeleteFeeRuleRequest, opts ...grpc.CallOption) (*DeleteFeeRuleResponse, error) {
+	out := new(DeleteFeeRuleResponse)
+	err := c.cc.Invoke(ctx, UpiCore_DeleteFeeRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) ListFeeRules(ctx context.Context, in *ListFeeRulesRequest, opts ...grpc.CallOption) (*ListFeeRulesResponse, error) {
+	out := new(ListFeeRulesResponse)
+	err := c.cc.Invoke(ctx, UpiCore_ListFeeRules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) RunReconciliation(ctx context.Context, in *RunReconciliationRequest, opts ...grpc.CallOption) (*RunReconciliationResponse, error) {
+	out := new(RunReconciliationResponse)
+	err := c.cc.Invoke(ctx, UpiCore_RunReconciliation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) GetReconciliationReport(ctx context.Context, in *ReconciliationReportRequest, opts ...grpc.CallOption) (*ReconciliationReportResponse, error) {
+	out := new(ReconciliationReportResponse)
+	err := c.cc.Invoke(ctx, UpiCore_GetReconciliationReport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) InitiateSettlement(ctx context.Context, in *InitiateSettlementRequest, opts ...grpc.CallOption) (*InitiateSettlementResponse, error) {
+	out := new(InitiateSettlementResponse)
+	err := c.cc.Invoke(ctx, UpiCore_InitiateSettlement_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) GetSettlementStatus(ctx context.Context, in *SettlementStatusRequest, opts ...grpc.CallOption) (*SettlementStatusResponse, error) {
+	out := new(SettlementStatusResponse)
+	err := c.cc.Invoke(ctx, UpiCore_GetSettlementStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *upiCoreClient) GetSettlementReport(ctx context.Context, in *SettlementReportRequest, opts ...grpc.CallOption) (*SettlementReportResponse, error) {
+	out := new(SettlementReportResponse)
+	err := c.cc.Invoke(ctx, UpiCore_GetSettlementReport_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -141,9 +395,8 @@ func (c *upiCoreClient) DeactivateVPA(ctx context.Context, in *DeactivateVPARequ
 }
 
 func (c *upiCoreClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HealthCheckResponse)
-	err := c.cc.Invoke(ctx, UpiCore_HealthCheck_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_HealthCheck_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -151,9 +404,8 @@ func (c *upiCoreClient) HealthCheck(ctx context.Context, in *HealthCheckRequest,
 }
 
 func (c *upiCoreClient) GetMetrics(ctx context.Context, in *MetricsRequest, opts ...grpc.CallOption) (*MetricsResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(MetricsResponse)
-	err := c.cc.Invoke(ctx, UpiCore_GetMetrics_FullMethodName, in, out, cOpts...)
+	err := c.cc.Invoke(ctx, UpiCore_GetMetrics_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -162,13 +414,13 @@ func (c *upiCoreClient) GetMetrics(ctx context.Context, in *MetricsRequest, opts
 
 // UpiCoreServer is the server API for UpiCore service.
 // All implementations must embed UnimplementedUpiCoreServer
-// for forward compatibility.
-//
-// UPI Core Service Definition
+// for forward compatibility
 type UpiCoreServer interface {
 	// Transaction Processing
 	ProcessTransaction(context.Context, *TransactionRequest) (*TransactionResponse, error)
 	GetTransactionStatus(context.Context, *TransactionStatusRequest) (*TransactionStatusResponse, error)
+	// Streams the transaction's current status, then each change of it, ending once it is final
+	SubscribeTransactionStatus(*SubscribeTransactionStatusRequest, UpiCore_SubscribeTransactionStatusServer) error
 	CancelTransaction(context.Context, *CancelTransactionRequest) (*CancelTransactionResponse, error)
 	ReverseTransaction(context.Context, *ReverseTransactionRequest) (*ReverseTransactionResponse, error)
 	// VPA Management
@@ -176,18 +428,41 @@ type UpiCoreServer interface {
 	RegisterVPA(context.Context, *RegisterVPARequest) (*RegisterVPAResponse, error)
 	UpdateVPA(context.Context, *UpdateVPARequest) (*UpdateVPAResponse, error)
 	DeactivateVPA(context.Context, *DeactivateVPARequest) (*DeactivateVPAResponse, error)
+	// Bank Operations
+	RegisterBank(context.Context, *RegisterBankRequest) (*RegisterBankResponse, error)
+	UpdateBankStatus(context.Context, *UpdateBankStatusRequest) (*UpdateBankStatusResponse, error)
+	GetBankStatus(context.Context, *BankStatusRequest) (*BankStatusResponse, error)
+	ListBanks(context.Context, *ListBanksRequest) (*ListBanksResponse, error)
+	// Mandate Management
+	CreateMandate(context.Context, *CreateMandateRequest) (*CreateMandateResponse, error)
+	ModifyMandate(context.Context, *ModifyMandateRequest) (*ModifyMandateResponse, error)
+	RevokeMandate(context.Context, *RevokeMandateRequest) (*RevokeMandateResponse, error)
+	GetMandate(context.Context, *GetMandateRequest) (*GetMandateResponse, error)
+	// Collect Requests
+	CreateCollect(context.Context, *CreateCollectRequest) (*CreateCollectResponse, error)
+	RespondCollect(context.Context, *RespondCollectRequest) (*RespondCollectResponse, error)
+	GetCollect(context.Context, *GetCollectRequest) (*GetCollectResponse, error)
+	// Fee Administration
+	CreateFeeRule(context.Context, *CreateFeeRuleRequest) (*CreateFeeRuleResponse, error)
+	UpdateFeeRule(context.Context, *UpdateFeeRuleRequest) (*UpdateFeeRuleResponse, error)
+	DeleteFeeRule(context.Context, *DeleteFeeRuleRequest) (*DeleteFeeRuleResponse, error)
+	ListFeeRules(context.Context, *ListFeeRulesRequest) (*ListFeeRulesResponse, error)
+	// Reconciliation
+	RunReconciliation(context.Context, *RunReconciliationRequest) (*RunReconciliationResponse, error)
+	GetReconciliationReport(context.Context, *ReconciliationReportRequest) (*ReconciliationReportResponse, error)
+	// Settlement Operations
+	InitiateSettlement(context.Context, *InitiateSettlementRequest) (*InitiateSettlementResponse, error)
+	GetSettlementStatus(context.Context, *SettlementStatusRequest) (*SettlementStatusResponse, error)
+	GetSettlementReport(context.Context, *SettlementReportRequest) (*SettlementReportResponse, error)
 	// Health and Monitoring
 	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
 	GetMetrics(context.Context, *MetricsRequest) (*MetricsResponse, error)
 	mustEmbedUnimplementedUpiCoreServer()
 }
 
-// UnimplementedUpiCoreServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedUpiCoreServer struct{}
+// UnimplementedUpiCoreServer must be embedded to have forward compatible implementations.
+type UnimplementedUpiCoreServer struct {
+}
 
 func (UnimplementedUpiCoreServer) ProcessTransaction(context.Context, *TransactionRequest) (*TransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ProcessTransaction not implemented")
@@ -195,6 +470,9 @@ func (UnimplementedUpiCoreServer) ProcessTransaction(context.Context, *Transacti
 func (UnimplementedUpiCoreServer) GetTransactionStatus(context.Context, *TransactionStatusRequest) (*TransactionStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionStatus not implemented")
 }
+func (UnimplementedUpiCoreServer) SubscribeTransactionStatus(*SubscribeTransactionStatusRequest, UpiCore_SubscribeTransactionStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeTransactionStatus not implemented")
+}
 func (UnimplementedUpiCoreServer) CancelTransaction(context.Context, *CancelTransactionRequest) (*CancelTransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelTransaction not implemented")
 }
@@ -213,6 +491,66 @@ func (UnimplementedUpiCoreServer) UpdateVPA(context.Context, *UpdateVPARequest)
 func (UnimplementedUpiCoreServer) DeactivateVPA(context.Context, *DeactivateVPARequest) (*DeactivateVPAResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeactivateVPA not implemented")
 }
+func (UnimplementedUpiCoreServer) RegisterBank(context.Context, *RegisterBankRequest) (*RegisterBankResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterBank not implemented")
+}
+func (UnimplementedUpiCoreServer) UpdateBankStatus(context.Context, *UpdateBankStatusRequest) (*UpdateBankStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBankStatus not implemented")
+}
+func (UnimplementedUpiCoreServer) GetBankStatus(context.Context, *BankStatusRequest) (*BankStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBankStatus not implemented")
+}
+func (UnimplementedUpiCoreServer) ListBanks(context.Context, *ListBanksRequest) (*ListBanksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBanks not implemented")
+}
+func (UnimplementedUpiCoreServer) CreateMandate(context.Context, *CreateMandateRequest) (*CreateMandateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMandate not implemented")
+}
+func (UnimplementedUpiCoreServer) ModifyMandate(context.Context, *ModifyMandateRequest) (*ModifyMandateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModifyMandate not implemented")
+}
+func (UnimplementedUpiCoreServer) RevokeMandate(context.Context, *RevokeMandateRequest) (*RevokeMandateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeMandate not implemented")
+}
+func (UnimplementedUpiCoreServer) GetMandate(context.Context, *GetMandateRequest) (*GetMandateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMandate not implemented")
+}
+func (UnimplementedUpiCoreServer) CreateCollect(context.Context, *CreateCollectRequest) (*CreateCollectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCollect not implemented")
+}
+func (UnimplementedUpiCoreServer) RespondCollect(context.Context, *RespondCollectRequest) (*RespondCollectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondCollect not implemented")
+}
+func (UnimplementedUpiCoreServer) GetCollect(context.Context, *GetCollectRequest) (*GetCollectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCollect not implemented")
+}
+func (UnimplementedUpiCoreServer) CreateFeeRule(context.Context, *CreateFeeRuleRequest) (*CreateFeeRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFeeRule not implemented")
+}
+func (UnimplementedUpiCoreServer) UpdateFeeRule(context.Context, *UpdateFeeRuleRequest) (*UpdateFeeRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFeeRule not implemented")
+}
+func (UnimplementedUpiCoreServer) DeleteFeeRule(context.Context, *DeleteFeeRuleRequest) (*DeleteFeeRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFeeRule not implemented")
+}
+func (UnimplementedUpiCoreServer) ListFeeRules(context.Context, *ListFeeRulesRequest) (*ListFeeRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFeeRules not implemented")
+}
+func (UnimplementedUpiCoreServer) RunReconciliation(context.Context, *RunReconciliationRequest) (*RunReconciliationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunReconciliation not implemented")
+}
+func (UnimplementedUpiCoreServer) GetReconciliationReport(context.Context, *ReconciliationReportRequest) (*ReconciliationReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReconciliationReport not implemented")
+}
+func (UnimplementedUpiCoreServer) InitiateSettlement(context.Context, *InitiateSettlementRequest) (*InitiateSettlementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InitiateSettlement not implemented")
+}
+func (UnimplementedUpiCoreServer) GetSettlementStatus(context.Context, *SettlementStatusRequest) (*SettlementStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSettlementStatus not implemented")
+}
+func (UnimplementedUpiCoreServer) GetSettlementReport(context.Context, *SettlementReportRequest) (*SettlementReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSettlementReport not implemented")
+}
 func (UnimplementedUpiCoreServer) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
@@ -220,7 +558,6 @@ func (UnimplementedUpiCoreServer) GetMetrics(context.Context, *MetricsRequest) (
 	return nil, status.Errorf(codes.Unimplemented, "method GetMetrics not implemented")
 }
 func (UnimplementedUpiCoreServer) mustEmbedUnimplementedUpiCoreServer() {}
-func (UnimplementedUpiCoreServer) testEmbeddedByValue()                 {}
 
 // UnsafeUpiCoreServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to UpiCoreServer will
@@ -230,13 +567,6 @@ type UnsafeUpiCoreServer interface {
 }
 
 func RegisterUpiCoreServer(s grpc.ServiceRegistrar, srv UpiCoreServer) {
-	// If the following call pancis, it indicates UnimplementedUpiCoreServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
-	}
 	s.RegisterService(&UpiCore_ServiceDesc, srv)
 }
 
@@ -276,6 +606,27 @@ func _UpiCore_GetTransactionStatus_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UpiCore_SubscribeTransactionStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeTransactionStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UpiCoreServer).SubscribeTransactionStatus(m, &upiCoreSubscribeTransactionStatusServer{stream})
+}
+
+type UpiCore_SubscribeTransactionStatusServer interface {
+	Send(*TransactionStatusUpdate) error
+	grpc.ServerStream
+}
+
+type upiCoreSubscribeTransactionStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *upiCoreSubscribeTransactionStatusServer) Send(m *TransactionStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _UpiCore_CancelTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CancelTransactionRequest)
 	if err := dec(in); err != nil {
@@ -384,90 +735,1004 @@ func _UpiCore_DeactivateVPA_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UpiCore_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HealthCheckRequest)
+func _UpiCore_RegisterBank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterBankRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UpiCoreServer).HealthCheck(ctx, in)
+		return srv.(UpiCoreServer).RegisterBank(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UpiCore_HealthCheck_FullMethodName,
+		FullMethod: UpiCore_RegisterBank_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UpiCoreServer).HealthCheck(ctx, req.(*HealthCheckRequest))
+		return srv.(UpiCoreServer).RegisterBank(ctx, req.(*RegisterBankRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _UpiCore_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MetricsRequest)
+func _UpiCore_UpdateBankStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBankStatusRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UpiCoreServer).GetMetrics(ctx, in)
+		return srv.(UpiCoreServer).UpdateBankStatus(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: UpiCore_GetMetrics_FullMethodName,
+		FullMethod: UpiCore_UpdateBankStatus_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UpiCoreServer).GetMetrics(ctx, req.(*MetricsRequest))
+		return srv.(UpiCoreServer).UpdateBankStatus(ctx, req.(*UpdateBankStatusRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// UpiCore_ServiceDesc is the grpc.ServiceDesc for UpiCore service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var UpiCore_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "upi.core.UpiCore",
-	HandlerType: (*UpiCoreServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "ProcessTransaction",
-			Handler:    _UpiCore_ProcessTransaction_Handler,
-		},
-		{
-			MethodName: "GetTransactionStatus",
-			Handler:    _UpiCore_GetTransactionStatus_Handler,
-		},
-		{
-			MethodName: "CancelTransaction",
-			Handler:    _UpiCore_CancelTransaction_Handler,
-		},
-		{
-			MethodName: "ReverseTransaction",
-			Handler:    _UpiCore_ReverseTransaction_Handler,
-		},
-		{
-			MethodName: "ResolveVPA",
-			Handler:    _UpiCore_ResolveVPA_Handler,
-		},
-		{
-			MethodName: "RegisterVPA",
-			Handler:    _UpiCore_RegisterVPA_Handler,
-		},
-		{
-			MethodName: "UpdateVPA",
-			Handler:    _UpiCore_UpdateVPA_Handler,
-		},
-		{
-			MethodName: "DeactivateVPA",
-			Handler:    _UpiCore_DeactivateVPA_Handler,
-		},
-		{
-			MethodName: "HealthCheck",
-			Handler:    _UpiCore_HealthCheck_Handler,
-		},
-		{
-			MethodName: "GetMetrics",
-			Handler:    _UpiCore_GetMetrics_Handler,
+func _UpiCore_GetBankStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BankStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).GetBankStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_GetBankStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).GetBankStatus(ctx, req.(*BankStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_ListBanks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBanksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).ListBanks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_ListBanks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).ListBanks(ctx, req.(*ListBanksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_CreateMandate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMandateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).CreateMandate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_CreateMandate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).CreateMandate(ctx, req.(*CreateMandateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_ModifyMandate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModifyMandateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).ModifyMandate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_ModifyMandate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).ModifyMandate(ctx, req.(*ModifyMandateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_RevokeMandate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeMandateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).RevokeMandate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_RevokeMandate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).RevokeMandate(ctx, req.(*RevokeMandateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_GetMandate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMandateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).GetMandate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_GetMandate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).GetMandate(ctx, req.(*GetMandateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_CreateCollect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCollectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).CreateCollect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_CreateCollect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).CreateCollect(ctx, req.(*CreateCollectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_RespondCollect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondCollectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).RespondCollect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_RespondCollect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).RespondCollect(ctx, req.(*RespondCollectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_GetCollect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCollectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).GetCollect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_GetCollect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).GetCollect(ctx, req.(*GetCollectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_CreateFeeRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFeeRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).CreateFeeRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_CreateFeeRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).CreateFeeRule(ctx, req.(*CreateFeeRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_UpdateFeeRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFeeRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).UpdateFeeRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_UpdateFeeRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).UpdateFeeRule(ctx, req.(*UpdateFeeRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_DeleteFeeRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFeeRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).DeleteFeeRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_DeleteFeeRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).DeleteFeeRule(ctx, req.(*DeleteFeeRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_ListFeeRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeeRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).ListFeeRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_ListFeeRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).ListFeeRules(ctx, req.(*ListFeeRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_RunReconciliation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunReconciliationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).RunReconciliation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_RunReconciliation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).RunReconciliation(ctx, req.(*RunReconciliationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_GetReconciliationReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconciliationReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).GetReconciliationReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_GetReconciliationReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).GetReconciliationReport(ctx, req.(*ReconciliationReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_InitiateSettlement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitiateSettlementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).InitiateSettlement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_InitiateSettlement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).InitiateSettlement(ctx, req.(*InitiateSettlementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_GetSettlementStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettlementStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).GetSettlementStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_GetSettlementStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).GetSettlementStatus(ctx, req.(*SettlementStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_GetSettlementReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettlementReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).GetSettlementReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_GetSettlementReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).GetSettlementReport(ctx, req.(*SettlementReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).HealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_HealthCheck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).HealthCheck(ctx, req.(*HealthCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UpiCore_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UpiCoreServer).GetMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UpiCore_GetMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UpiCoreServer).GetMetrics(ctx, req.(*MetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UpiCore_ServiceDesc is the grpc.ServiceDesc for UpiCore service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UpiCore_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "upi_core.UpiCore",
+	HandlerType: (*UpiCoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ProcessTransaction",
+			Handler:    _UpiCore_ProcessTransaction_Handler,
+		},
+		{
+			MethodName: "GetTransactionStatus",
+			Handler:    _UpiCore_GetTransactionStatus_Handler,
+		},
+		{
+			MethodName: "CancelTransaction",
+			Handler:    _UpiCore_CancelTransaction_Handler,
+		},
+		{
+			MethodName: "ReverseTransaction",
+			Handler:    _UpiCore_ReverseTransaction_Handler,
+		},
+		{
+			MethodName: "ResolveVPA",
+			Handler:    _UpiCore_ResolveVPA_Handler,
+		},
+		{
+			MethodName: "RegisterVPA",
+			Handler:    _UpiCore_RegisterVPA_Handler,
+		},
+		{
+			MethodName: "UpdateVPA",
+			Handler:    _UpiCore_UpdateVPA_Handler,
+		},
+		{
+			MethodName: "DeactivateVPA",
+			Handler:    _UpiCore_DeactivateVPA_Handler,
+		},
+		{
+			MethodName: "RegisterBank",
+			Handler:    _UpiCore_RegisterBank_Handler,
+		},
+		{
+			MethodName: "UpdateBankStatus",
+			Handler:    _UpiCore_UpdateBankStatus_Handler,
+		},
+		{
+			MethodName: "GetBankStatus",
+			Handler:    _UpiCore_GetBankStatus_Handler,
+		},
+		{
+			MethodName: "ListBanks",
+			Handler:    _UpiCore_ListBanks_Handler,
+		},
+		{
+			MethodName: "CreateMandate",
+			Handler:    _UpiCore_CreateMandate_Handler,
+		},
+		{
+			MethodName: "ModifyMandate",
+			Handler:    _UpiCore_ModifyMandate_Handler,
+		},
+		{
+			MethodName: "RevokeMandate",
+			Handler:    _UpiCore_RevokeMandate_Handler,
+		},
+		{
+			MethodName: "GetMandate",
+			Handler:    _UpiCore_GetMandate_Handler,
+		},
+		{
+			MethodName: "CreateCollect",
+			Handler:    _UpiCore_CreateCollect_Handler,
+		},
+		{
+			MethodName: "RespondCollect",
+			Handler:    _UpiCore_RespondCollect_Handler,
+		},
+		{
+			MethodName: "GetCollect",
+			Handler:    _UpiCore_GetCollect_Handler,
+		},
+		{
+			MethodName: "CreateFeeRule",
+			Handler:    _UpiCore_CreateFeeRule_Handler,
+		},
+		{
+			MethodName: "UpdateFeeRule",
+			Handler:    _UpiCore_UpdateFeeRule_Handler,
+		},
+		{
+			MethodName: "DeleteFeeRule",
+			Handler:    _UpiCore_DeleteFeeRule_Handler,
+		},
+		{
+			MethodName: "ListFeeRules",
+			Handler:    _UpiCore_ListFeeRules_Handler,
+		},
+		{
+			MethodName: "RunReconciliation",
+			Handler:    _UpiCore_RunReconciliation_Handler,
+		},
+		{
+			MethodName: "GetReconciliationReport",
+			Handler:    _UpiCore_GetReconciliationReport_Handler,
+		},
+		{
+			MethodName: "InitiateSettlement",
+			Handler:    _UpiCore_InitiateSettlement_Handler,
+		},
+		{
+			MethodName: "GetSettlementStatus",
+			Handler:    _UpiCore_GetSettlementStatus_Handler,
+		},
+		{
+			MethodName: "GetSettlementReport",
+			Handler:    _UpiCore_GetSettlementReport_Handler,
+		},
+		{
+			MethodName: "HealthCheck",
+			Handler:    _UpiCore_HealthCheck_Handler,
+		},
+		{
+			MethodName: "GetMetrics",
+			Handler:    _UpiCore_GetMetrics_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeTransactionStatus",
+			Handler:       _UpiCore_SubscribeTransactionStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "upi_core.proto",
+}
+
+const (
+	BankAdmin_RegisterBank_FullMethodName           = "/upi_core.BankAdmin/RegisterBank"
+	BankAdmin_ApproveBank_FullMethodName            = "/upi_core.BankAdmin/ApproveBank"
+	BankAdmin_RejectBank_FullMethodName             = "/upi_core.BankAdmin/RejectBank"
+	BankAdmin_ActivateBank_FullMethodName           = "/upi_core.BankAdmin/ActivateBank"
+	BankAdmin_DeactivateBank_FullMethodName         = "/upi_core.BankAdmin/DeactivateBank"
+	BankAdmin_SuspendBank_FullMethodName            = "/upi_core.BankAdmin/SuspendBank"
+	BankAdmin_ScheduleMaintenance_FullMethodName    = "/upi_core.BankAdmin/ScheduleMaintenance"
+	BankAdmin_CancelMaintenance_FullMethodName      = "/upi_core.BankAdmin/CancelMaintenance"
+	BankAdmin_ListMaintenanceWindows_FullMethodName = "/upi_core.BankAdmin/ListMaintenanceWindows"
+	BankAdmin_GetBank_FullMethodName                = "/upi_core.BankAdmin/GetBank"
+	BankAdmin_ListBanks_FullMethodName              = "/upi_core.BankAdmin/ListBanks"
+)
+
+// BankAdminClient is the client API for BankAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BankAdminClient interface {
+	// Register a bank, pending approval by another operator, or update the
+	// details of a registered one
+	RegisterBank(ctx context.Context, in *RegisterBankRequest, opts ...grpc.CallOption) (*BankAdminResponse, error)
+	ApproveBank(ctx context.Context, in *ApproveBankRequest, opts ...grpc.CallOption) (*BankAdminResponse, error)
+	RejectBank(ctx context.Context, in *RejectBankRequest, opts ...grpc.CallOption) (*BankAdminResponse, error)
+	// Move an approved bank between ACTIVE, INACTIVE, MAINTENANCE and SUSPENDED
+	ActivateBank(ctx context.Context, in *BankStatusChangeRequest, opts ...grpc.CallOption) (*BankAdminResponse, error)
+	DeactivateBank(ctx context.Context, in *BankStatusChangeRequest, opts ...grpc.CallOption) (*BankAdminResponse, error)
+	SuspendBank(ctx context.Context, in *BankStatusChangeRequest, opts ...grpc.CallOption) (*BankAdminResponse, error)
+	// Windows during which a bank is in MAINTENANCE
+	ScheduleMaintenance(ctx context.Context, in *ScheduleMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceWindow, error)
+	CancelMaintenance(ctx context.Context, in *CancelMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceWindow, error)
+	ListMaintenanceWindows(ctx context.Context, in *ListMaintenanceWindowsRequest, opts ...grpc.CallOption) (*ListMaintenanceWindowsResponse, error)
+	GetBank(ctx context.Context, in *GetBankRequest, opts ...grpc.CallOption) (*BankAdminResponse, error)
+	ListBanks(ctx context.Context, in *ListBanksRequest, opts ...grpc.CallOption) (*ListBanksResponse, error)
+}
+
+type bankAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBankAdminClient(cc grpc.ClientConnInterface) BankAdminClient {
+	return &bankAdminClient{cc}
+}
+
+func (c *bankAdminClient) RegisterBank(ctx context.Context, in *RegisterBankRequest, opts ...grpc.CallOption) (*BankAdminResponse, error) {
+	out := new(BankAdminResponse)
+	err := c.cc.Invoke(ctx, BankAdmin_RegisterBank_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) ApproveBank(ctx context.Context, in *ApproveBankRequest, opts ...grpc.CallOption) (*BankAdminResponse, error) {
+	out := new(BankAdminResponse)
+	err := c.cc.Invoke(ctx, BankAdmin_ApproveBank_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) RejectBank(ctx context.Context, in *RejectBankRequest, opts ...grpc.CallOption) (*BankAdminResponse, error) {
+	out := new(BankAdminResponse)
+	err := c.cc.Invoke(ctx, BankAdmin_RejectBank_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) ActivateBank(ctx context.Context, in *BankStatusChangeRequest, opts ...grpc.CallOption) (*BankAdminResponse, error) {
+	out := new(BankAdminResponse)
+	err := c.cc.Invoke(ctx, BankAdmin_ActivateBank_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) DeactivateBank(ctx context.Context, in *BankStatusChangeRequest, opts ...grpc.CallOption) (*BankAdminResponse, error) {
+	out := new(BankAdminResponse)
+	err := c.cc.Invoke(ctx, BankAdmin_DeactivateBank_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) SuspendBank(ctx context.Context, in *BankStatusChangeRequest, opts ...grpc.CallOption) (*BankAdminResponse, error) {
+	out := new(BankAdminResponse)
+	err := c.cc.Invoke(ctx, BankAdmin_SuspendBank_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) ScheduleMaintenance(ctx context.Context, in *ScheduleMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceWindow, error) {
+	out := new(MaintenanceWindow)
+	err := c.cc.Invoke(ctx, BankAdmin_ScheduleMaintenance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) CancelMaintenance(ctx context.Context, in *CancelMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceWindow, error) {
+	out := new(MaintenanceWindow)
+	err := c.cc.Invoke(ctx, BankAdmin_CancelMaintenance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) ListMaintenanceWindows(ctx context.Context, in *ListMaintenanceWindowsRequest, opts ...grpc.CallOption) (*ListMaintenanceWindowsResponse, error) {
+	out := new(ListMaintenanceWindowsResponse)
+	err := c.cc.Invoke(ctx, BankAdmin_ListMaintenanceWindows_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) GetBank(ctx context.Context, in *GetBankRequest, opts ...grpc.CallOption) (*BankAdminResponse, error) {
+	out := new(BankAdminResponse)
+	err := c.cc.Invoke(ctx, BankAdmin_GetBank_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankAdminClient) ListBanks(ctx context.Context, in *ListBanksRequest, opts ...grpc.CallOption) (*ListBanksResponse, error) {
+	out := new(ListBanksResponse)
+	err := c.cc.Invoke(ctx, BankAdmin_ListBanks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BankAdminServer is the server API for BankAdmin service.
+// All implementations must embed UnimplementedBankAdminServer
+// for forward compatibility
+type BankAdminServer interface {
+	// Register a bank, pending approval by another operator, or update the
+	// details of a registered one
+	RegisterBank(context.Context, *RegisterBankRequest) (*BankAdminResponse, error)
+	ApproveBank(context.Context, *ApproveBankRequest) (*BankAdminResponse, error)
+	RejectBank(context.Context, *RejectBankRequest) (*BankAdminResponse, error)
+	// Move an approved bank between ACTIVE, INACTIVE, MAINTENANCE and SUSPENDED
+	ActivateBank(context.Context, *BankStatusChangeRequest) (*BankAdminResponse, error)
+	DeactivateBank(context.Context, *BankStatusChangeRequest) (*BankAdminResponse, error)
+	SuspendBank(context.Context, *BankStatusChangeRequest) (*BankAdminResponse, error)
+	// Windows during which a bank is in MAINTENANCE
+	ScheduleMaintenance(context.Context, *ScheduleMaintenanceRequest) (*MaintenanceWindow, error)
+	CancelMaintenance(context.Context, *CancelMaintenanceRequest) (*MaintenanceWindow, error)
+	ListMaintenanceWindows(context.Context, *ListMaintenanceWindowsRequest) (*ListMaintenanceWindowsResponse, error)
+	GetBank(context.Context, *GetBankRequest) (*BankAdminResponse, error)
+	ListBanks(context.Context, *ListBanksRequest) (*ListBanksResponse, error)
+	mustEmbedUnimplementedBankAdminServer()
+}
+
+// UnimplementedBankAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedBankAdminServer struct {
+}
+
+func (UnimplementedBankAdminServer) RegisterBank(context.Context, *RegisterBankRequest) (*BankAdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterBank not implemented")
+}
+func (UnimplementedBankAdminServer) ApproveBank(context.Context, *ApproveBankRequest) (*BankAdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveBank not implemented")
+}
+func (UnimplementedBankAdminServer) RejectBank(context.Context, *RejectBankRequest) (*BankAdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectBank not implemented")
+}
+func (UnimplementedBankAdminServer) ActivateBank(context.Context, *BankStatusChangeRequest) (*BankAdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActivateBank not implemented")
+}
+func (UnimplementedBankAdminServer) DeactivateBank(context.Context, *BankStatusChangeRequest) (*BankAdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateBank not implemented")
+}
+func (UnimplementedBankAdminServer) SuspendBank(context.Context, *BankStatusChangeRequest) (*BankAdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuspendBank not implemented")
+}
+func (UnimplementedBankAdminServer) ScheduleMaintenance(context.Context, *ScheduleMaintenanceRequest) (*MaintenanceWindow, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleMaintenance not implemented")
+}
+func (UnimplementedBankAdminServer) CancelMaintenance(context.Context, *CancelMaintenanceRequest) (*MaintenanceWindow, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelMaintenance not implemented")
+}
+func (UnimplementedBankAdminServer) ListMaintenanceWindows(context.Context, *ListMaintenanceWindowsRequest) (*ListMaintenanceWindowsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMaintenanceWindows not implemented")
+}
+func (UnimplementedBankAdminServer) GetBank(context.Context, *GetBankRequest) (*BankAdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBank not implemented")
+}
+func (UnimplementedBankAdminServer) ListBanks(context.Context, *ListBanksRequest) (*ListBanksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBanks not implemented")
+}
+func (UnimplementedBankAdminServer) mustEmbedUnimplementedBankAdminServer() {}
+
+// UnsafeBankAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BankAdminServer will
+// result in compilation errors.
+type UnsafeBankAdminServer interface {
+	mustEmbedUnimplementedBankAdminServer()
+}
+
+func RegisterBankAdminServer(s grpc.ServiceRegistrar, srv BankAdminServer) {
+	s.RegisterService(&BankAdmin_ServiceDesc, srv)
+}
+
+func _BankAdmin_RegisterBank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterBankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).RegisterBank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_RegisterBank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).RegisterBank(ctx, req.(*RegisterBankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_ApproveBank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveBankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).ApproveBank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_ApproveBank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).ApproveBank(ctx, req.(*ApproveBankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_RejectBank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectBankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).RejectBank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_RejectBank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).RejectBank(ctx, req.(*RejectBankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_ActivateBank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BankStatusChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).ActivateBank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_ActivateBank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).ActivateBank(ctx, req.(*BankStatusChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_DeactivateBank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BankStatusChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).DeactivateBank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_DeactivateBank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).DeactivateBank(ctx, req.(*BankStatusChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_SuspendBank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BankStatusChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).SuspendBank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_SuspendBank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).SuspendBank(ctx, req.(*BankStatusChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_ScheduleMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).ScheduleMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_ScheduleMaintenance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).ScheduleMaintenance(ctx, req.(*ScheduleMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_CancelMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).CancelMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_CancelMaintenance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).CancelMaintenance(ctx, req.(*CancelMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_ListMaintenanceWindows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMaintenanceWindowsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).ListMaintenanceWindows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_ListMaintenanceWindows_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).ListMaintenanceWindows(ctx, req.(*ListMaintenanceWindowsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_GetBank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).GetBank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_GetBank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).GetBank(ctx, req.(*GetBankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankAdmin_ListBanks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBanksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankAdminServer).ListBanks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankAdmin_ListBanks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankAdminServer).ListBanks(ctx, req.(*ListBanksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BankAdmin_ServiceDesc is the grpc.ServiceDesc for BankAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BankAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "upi_core.BankAdmin",
+	HandlerType: (*BankAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterBank",
+			Handler:    _BankAdmin_RegisterBank_Handler,
+		},
+		{
+			MethodName: "ApproveBank",
+			Handler:    _BankAdmin_ApproveBank_Handler,
+		},
+		{
+			MethodName: "RejectBank",
+			Handler:    _BankAdmin_RejectBank_Handler,
+		},
+		{
+			MethodName: "ActivateBank",
+			Handler:    _BankAdmin_ActivateBank_Handler,
+		},
+		{
+			MethodName: "DeactivateBank",
+			Handler:    _BankAdmin_DeactivateBank_Handler,
+		},
+		{
+			MethodName: "SuspendBank",
+			Handler:    _BankAdmin_SuspendBank_Handler,
+		},
+		{
+			MethodName: "ScheduleMaintenance",
+			Handler:    _BankAdmin_ScheduleMaintenance_Handler,
+		},
+		{
+			MethodName: "CancelMaintenance",
+			Handler:    _BankAdmin_CancelMaintenance_Handler,
+		},
+		{
+			MethodName: "ListMaintenanceWindows",
+			Handler:    _BankAdmin_ListMaintenanceWindows_Handler,
+		},
+		{
+			MethodName: "GetBank",
+			Handler:    _BankAdmin_GetBank_Handler,
+		},
+		{
+			MethodName: "ListBanks",
+			Handler:    _BankAdmin_ListBanks_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
-	Metadata: "proto/upi_core.proto",
+	Metadata: "upi_core.proto",
 }
@@ -1,8 +1,17 @@
 #!/bin/bash
 
 # Generate protobuf code for Payment Gateway service
+#
+# UPI Core owns upi_core.proto. This copies it from services/upi-core so the
+# gateway always builds against the contract UPI Core actually serves; the
+# contract tests in internal/services fail if the two drift apart.
 set -e
 
+cd "$(dirname "$0")/.."
+
+UPSTREAM_PROTO="../upi-core/proto/upi_core.proto"
+GO_PACKAGE="github.com/suuupra/payments/proto/upi_core"
+
 echo "🔧 Generating protobuf code..."
 
 # Check if protoc is installed
@@ -13,34 +22,40 @@ if ! command -v protoc &> /dev/null; then
     exit 1
 fi
 
-# Check if protoc-gen-go is installed
+# Generator versions match google.golang.org/protobuf and grpc in go.mod
 if ! command -v protoc-gen-go &> /dev/null; then
     echo "🔧 Installing protoc-gen-go..."
-    go install google.golang.org/protobuf/cmd/protoc-gen-go@latest
+    go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.31.0
 fi
 
-# Check if protoc-gen-go-grpc is installed
 if ! command -v protoc-gen-go-grpc &> /dev/null; then
     echo "🔧 Installing protoc-gen-go-grpc..."
-    go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest
+    go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.3.0
 fi
 
-# Create output directory
-mkdir -p proto/
+# Take UPI Core's definition, retargeting only the Go package
+sed -e "s#^option go_package = .*#option go_package = \"$GO_PACKAGE\";#" \
+    -e '/^syntax = /a\
+\
+// Copied from services/upi-core/proto/upi_core.proto by\
+// scripts/generate-proto.sh. DO NOT EDIT; change it in UPI Core.' \
+    "$UPSTREAM_PROTO" > proto/upi_core.proto
 
-# Generate Go code from protobuf
-protoc --go_out=. --go_opt=paths=source_relative \
-    --go-grpc_out=. --go-grpc_opt=paths=source_relative \
-    proto/upi_core.proto
+mkdir -p proto/upi_core
+
+protoc -I proto \
+    --go_out=proto/upi_core --go_opt=paths=source_relative \
+    --go-grpc_out=proto/upi_core --go-grpc_opt=paths=source_relative \
+    upi_core.proto
 
 echo "✅ Protobuf code generated successfully!"
 
 # Verify generated files
-if [ -f "proto/upi_core.pb.go" ] && [ -f "proto/upi_core_grpc.pb.go" ]; then
+if [ -f "proto/upi_core/upi_core.pb.go" ] && [ -f "proto/upi_core/upi_core_grpc.pb.go" ]; then
     echo "✅ Generated files:"
-    echo "   - proto/upi_core.pb.go"
-    echo "   - proto/upi_core_grpc.pb.go"
+    echo "   - proto/upi_core/upi_core.pb.go"
+    echo "   - proto/upi_core/upi_core_grpc.pb.go"
 else
     echo "❌ Failed to generate protobuf files"
     exit 1
-fi
\ No newline at end of file
+fi
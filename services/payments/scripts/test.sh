@@ -20,6 +20,13 @@ go mod download
 echo "=, Running unit tests..."
 go test ./internal/... -v -race -cover -coverprofile=coverage.out
 
+# Fuzz request binding and validation with malformed input, FUZZTIME per target
+echo "Running fuzz targets..."
+FUZZTIME=${FUZZTIME:-10s}
+for target in $(go test -list '^Fuzz' ./tests/fuzz | grep '^Fuzz'); do
+    go test ./tests/fuzz -run '^$' -fuzz "^${target}\$" -fuzztime "$FUZZTIME"
+done
+
 # Run integration tests (if any)
 echo "= Running integration tests..."
 go test ./tests/integration/... -v -tags=integration || echo "9  No integration tests found"
@@ -37,7 +44,8 @@ echo "=
 go tool cover -func=coverage.out | tail -1
 
 # Run go vet for static analysis
-echo "= Running static analysis (go vet)..."
+echo "=
+ Running static analysis (go vet)..."
 go vet ./...
 
 # Run golint if available
@@ -0,0 +1,319 @@
+// Package contract checks the gateway against the contracts it shares with
+// other services.
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/suuupra/payments/internal/models"
+	"github.com/suuupra/payments/internal/services"
+	pb "github.com/suuupra/payments/proto/upi_core"
+)
+
+// The contract pins every UPI Core call the gateway makes. UPI Core runs the
+// same file against its own proto in tests/contract, so a change on either
+// side that breaks the other fails that side's pipeline.
+const upiCoreContractPath = "../../../../shared/contracts/payments-upi-core/contract.json"
+
+var updateContract = flag.Bool("update-contract", false, "rewrite the wire encodings in the UPI Core contract")
+
+type upiContract struct {
+	Consumer     string           `json:"consumer"`
+	Provider     string           `json:"provider"`
+	Service      string           `json:"service"`
+	Interactions []upiInteraction `json:"interactions"`
+}
+
+type upiInteraction struct {
+	Description string          `json:"description"`
+	Method      string          `json:"method"`
+	Request     json.RawMessage `json:"request"`
+	Response    json.RawMessage `json:"response"`
+	Wire        struct {
+		Request  []byte `json:"request"`
+		Response []byte `json:"response"`
+	} `json:"wire"`
+}
+
+func loadUPIContract(t *testing.T) (*upiContract, protoreflect.ServiceDescriptor) {
+	t.Helper()
+	raw, err := os.ReadFile(upiCoreContractPath)
+	require.NoError(t, err)
+	var contract upiContract
+	require.NoError(t, json.Unmarshal(raw, &contract))
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(contract.Service))
+	require.NoError(t, err, "service %s is not in the generated UPI Core client", contract.Service)
+	return &contract, desc.(protoreflect.ServiceDescriptor)
+}
+
+// decodeFixture parses a JSON fixture strictly, so fields the schema lacks
+// fail instead of being dropped.
+func decodeFixture(t *testing.T, desc protoreflect.MessageDescriptor, raw json.RawMessage) proto.Message {
+	t.Helper()
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	require.NoError(t, err)
+	msg := mt.New().Interface()
+	require.NoError(t, protojson.Unmarshal(raw, msg), "fixture does not match %s", desc.FullName())
+	return msg
+}
+
+// TestUPICoreContractSchema checks that every interaction still matches the
+// generated client: the method exists, the fixtures parse strictly, and the
+// recorded wire encoding decodes to the same message, which pins field
+// numbers and types as well as names.
+func TestUPICoreContractSchema(t *testing.T) {
+	contract, service := loadUPIContract(t)
+	require.Equal(t, "payments", contract.Consumer)
+
+	for i := range contract.Interactions {
+		interaction := &contract.Interactions[i]
+		t.Run(interaction.Description, func(t *testing.T) {
+			method := service.Methods().ByName(protoreflect.Name(interaction.Method))
+			require.NotNil(t, method, "UPI Core no longer has %s", interaction.Method)
+
+			request := decodeFixture(t, method.Input(), interaction.Request)
+			response := decodeFixture(t, method.Output(), interaction.Response)
+
+			if *updateContract {
+				opts := proto.MarshalOptions{Deterministic: true}
+				var err error
+				interaction.Wire.Request, err = opts.Marshal(request)
+				require.NoError(t, err)
+				interaction.Wire.Response, err = opts.Marshal(response)
+				require.NoError(t, err)
+				return
+			}
+			for _, wire := range []struct {
+				name  string
+				bytes []byte
+				want  proto.Message
+			}{
+				{"request", interaction.Wire.Request, request},
+				{"response", interaction.Wire.Response, response},
+			} {
+				got := wire.want.ProtoReflect().New().Interface()
+				require.NoError(t, proto.Unmarshal(wire.bytes, got))
+				assert.True(t, proto.Equal(wire.want, got),
+					"%s wire encoding decodes to %v, want %v; rerun with -update-contract if the change is intended",
+					wire.name, got, wire.want)
+			}
+		})
+	}
+
+	if *updateContract {
+		raw, err := json.MarshalIndent(contract, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(upiCoreContractPath, append(raw, '\n'), 0o644))
+	}
+}
+
+// contractServer is a UPI Core stand-in that records each request and
+// answers with the response fixture for its method.
+type contractServer struct {
+	client   *services.UPIClient
+	requests map[string]proto.Message // By method name
+	replies  map[string]proto.Message
+}
+
+func newContractServer(t *testing.T) *contractServer {
+	t.Helper()
+	cs := &contractServer{
+		requests: make(map[string]proto.Message),
+		replies:  make(map[string]proto.Message),
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, _ grpc.UnaryHandler) (interface{}, error) {
+		name := info.FullMethod[len("/upi_core.UpiCore/"):]
+		cs.requests[name] = req.(proto.Message)
+		return cs.replies[name], nil
+	}))
+	pb.RegisterUpiCoreServer(srv, pb.UnimplementedUpiCoreServer{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	cs.client, err = services.NewUPIClient(lis.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { cs.client.Close() })
+	return cs
+}
+
+// TestUPICoreContractConsumer drives UPIClient through every interaction:
+// it must send exactly the request fixture and read the response fixture
+// into the gateway's own result.
+func TestUPICoreContractConsumer(t *testing.T) {
+	contract, service := loadUPIContract(t)
+	at := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return ts
+	}
+
+	tests := map[string]func(ctx context.Context, c *services.UPIClient) (interface{}, interface{}){
+		"a merchant payment that succeeds": func(ctx context.Context, c *services.UPIClient) (interface{}, interface{}) {
+			resp, err := c.ProcessPayment(ctx, services.UPIPaymentRequest{
+				PaymentID:      uuid.MustParse("5b0f9a2e-8c1d-4e6f-9a3b-2d7c4e1f0a9b"),
+				PayerVPA:       "alice@hdfc",
+				PayeeVPA:       "store@icici",
+				Amount:         decimal.RequireFromString("1250.50"),
+				Currency:       "INR",
+				Description:    "Order #1001",
+				MerchantID:     "merchant_123",
+				TransactionRef: "ORD-1001",
+			})
+			require.NoError(t, err)
+			return &services.UPIPaymentResponse{
+				Success:       true,
+				TransactionID: "401510300001",
+				Status:        models.PaymentStatusSucceeded,
+				ProcessedAt:   at("2024-01-15T10:30:01Z"),
+			}, resp
+		},
+		"a merchant payment the payer's bank declines": func(ctx context.Context, c *services.UPIClient) (interface{}, interface{}) {
+			resp, err := c.ProcessPayment(ctx, services.UPIPaymentRequest{
+				PaymentID:      uuid.MustParse("0c6e2d7a-3f4b-4a1e-8d2c-9b5a7e3f1c6d"),
+				PayerVPA:       "bob@sbi",
+				PayeeVPA:       "store@icici",
+				Amount:         decimal.RequireFromString("999"),
+				Currency:       "INR",
+				Description:    "Order #1002",
+				TransactionRef: "ORD-1002",
+			})
+			require.NoError(t, err)
+			code, message := "INSUFFICIENT_FUNDS", "Insufficient balance in payer account"
+			return &services.UPIPaymentResponse{
+				Success:        false,
+				TransactionID:  "401510310002",
+				Status:         models.PaymentStatusFailed,
+				FailureCode:    &code,
+				FailureMessage: &message,
+				ProcessedAt:    at("2024-01-15T10:31:01Z"),
+			}, resp
+		},
+		"the status of a pending payment": func(ctx context.Context, c *services.UPIClient) (interface{}, interface{}) {
+			resp, err := c.CheckPaymentStatus(ctx, "5b0f9a2e-8c1d-4e6f-9a3b-2d7c4e1f0a9b")
+			require.NoError(t, err)
+			return &services.UPIPaymentResponse{
+				TransactionID: "401510300001",
+				Status:        models.PaymentStatusPending,
+			}, resp
+		},
+		"a full refund of a payment": func(ctx context.Context, c *services.UPIClient) (interface{}, interface{}) {
+			resp, err := c.ProcessRefund(ctx, services.UPIRefundRequest{
+				RefundID:          uuid.MustParse("e2a4c6d8-1b3f-4e5a-9c7d-0f2b4d6e8a1c"),
+				OriginalPaymentID: uuid.MustParse("5b0f9a2e-8c1d-4e6f-9a3b-2d7c4e1f0a9b"),
+				TransactionID:     "401510300001",
+				Amount:            decimal.RequireFromString("1250.50"),
+				Currency:          "INR",
+				Reason:            "requested_by_customer",
+			})
+			require.NoError(t, err)
+			return &services.UPIRefundResponse{
+				Success:         true,
+				RefundReference: "e2a4c6d8-1b3f-4e5a-9c7d-0f2b4d6e8a1c",
+				Status:          models.RefundStatusSucceeded,
+				ProcessedAt:     at("2024-01-16T09:00:00Z"),
+			}, resp
+		},
+		"the status of a completed refund": func(ctx context.Context, c *services.UPIClient) (interface{}, interface{}) {
+			resp, err := c.CheckRefundStatus(ctx, services.UPIRefundStatusRequest{
+				RefundReference: "e2a4c6d8-1b3f-4e5a-9c7d-0f2b4d6e8a1c",
+				RefundID:        uuid.MustParse("e2a4c6d8-1b3f-4e5a-9c7d-0f2b4d6e8a1c"),
+			})
+			require.NoError(t, err)
+			processedAt := at("2024-01-16T09:00:02Z")
+			return &services.UPIRefundStatusResponse{
+				Success:     true,
+				Status:      models.RefundStatusSucceeded,
+				ProcessedAt: &processedAt,
+			}, resp
+		},
+		"a page of a bank's settlement report": func(ctx context.Context, c *services.UPIClient) (interface{}, interface{}) {
+			report, err := c.GetSettlementReport(ctx, services.UPISettlementReportRequest{
+				BankCode:  "ICICI",
+				From:      at("2024-01-15T00:00:00Z"),
+				To:        at("2024-01-16T00:00:00Z"),
+				PageToken: "MjAyNC0wMS0xNVQxMDozMDowMVp8dHhuXzE",
+			})
+			require.NoError(t, err)
+			settledAt, pendingAt := at("2024-01-15T10:30:01Z"), at("2024-01-15T10:40:02Z")
+			return &services.UPISettlementReport{
+				BankCode: "ICICI",
+				Records: []services.UPISettlementRecord{
+					{
+						TransactionID: "5b0f9a2e-8c1d-4e6f-9a3b-2d7c4e1f0a9b",
+						RRN:           "401510300001",
+						AmountMinor:   125050,
+						FeeMinor:      150,
+						SettlementID:  "SETL_20240115_001",
+						ProcessedAt:   &settledAt,
+					},
+					{
+						TransactionID: "7d3e1b9c-2a4f-4c6e-8b1d-5f9a3c7e2d4b",
+						RRN:           "401510400003",
+						AmountMinor:   49900,
+						FeeMinor:      60,
+						ProcessedAt:   &pendingAt,
+					},
+				},
+				NextPageToken: "MjAyNC0wMS0xNVQxMDo0MDowMlp8dHhuXzM",
+			}, report
+		},
+		"validating an active VPA": func(ctx context.Context, c *services.UPIClient) (interface{}, interface{}) {
+			valid, err := c.ValidateVPA(ctx, "alice@hdfc")
+			require.NoError(t, err)
+			return true, valid
+		},
+	}
+
+	for _, interaction := range contract.Interactions {
+		interaction := interaction
+		t.Run(interaction.Description, func(t *testing.T) {
+			call, ok := tests[interaction.Description]
+			require.True(t, ok, "no consumer test for this interaction")
+			method := service.Methods().ByName(protoreflect.Name(interaction.Method))
+			require.NotNil(t, method)
+
+			cs := newContractServer(t)
+			cs.replies[interaction.Method] = decodeFixture(t, method.Output(), interaction.Response)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			want, got := call(ctx, cs.client)
+			assert.Equal(t, want, got)
+
+			sent := cs.requests[interaction.Method]
+			require.NotNil(t, sent, "UPIClient did not call %s", interaction.Method)
+			wantReq := decodeFixture(t, method.Input(), interaction.Request)
+			clearInitiatedAt(sent)
+			clearInitiatedAt(wantReq)
+			assert.True(t, proto.Equal(wantReq, sent), "sent %v, contract says %v", sent, wantReq)
+		})
+	}
+}
+
+// clearInitiatedAt drops the client's send time, the one field it sets
+// from the clock.
+func clearInitiatedAt(msg proto.Message) {
+	m := msg.ProtoReflect()
+	if fd := m.Descriptor().Fields().ByName("initiated_at"); fd != nil {
+		m.Clear(fd)
+	}
+}
@@ -0,0 +1,160 @@
+// Package fuzz feeds malformed request bodies through the gateway's JSON
+// binding and validation, the path every API request takes before it
+// reaches a service. Run a target with
+//
+//	go test ./tests/fuzz -run '^$' -fuzz '^FuzzCreatePaymentRequest$'
+//
+// or all of them with scripts/test.sh; plain go test runs the seeds only.
+package fuzz
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/shopspring/decimal"
+
+	"github.com/suuupra/payments/internal/services"
+	"github.com/suuupra/payments/pkg/money"
+)
+
+var amountSeeds = []string{
+	`"amount":"100.50"`,
+	`"amount":100.5`,
+	`"amount":"0.01"`,
+	`"amount":"500000"`,
+	`"amount":"500000.01"`,
+	`"amount":0`,
+	`"amount":"-0.01"`,
+	`"amount":-100`,
+	`"amount":"100.005"`,
+	`"amount":1e30`,
+	`"amount":"1e400"`,
+	`"amount":92233720368547758.07`,
+	`"amount":"NaN"`,
+	`"amount":"१००"`,
+}
+
+// checkAmount fails when an amount passes validation but would not reach
+// UPI Core as exactly that many minor units of its currency.
+func checkAmount(t *testing.T, amount decimal.Decimal, currency string) {
+	t.Helper()
+	m, err := services.ValidateAmount(amount, currency)
+	if err != nil {
+		return
+	}
+	exponent, err := money.Exponent(currency)
+	if err != nil {
+		t.Fatalf("accepted %s in unknown currency %q", amount, currency)
+	}
+	minor := amount.Shift(exponent)
+	if !decimal.NewFromInt(m.Minor).Equal(minor) || !m.Decimal().Equal(amount) {
+		t.Fatalf("accepted %s %s as %d minor units", amount, currency, m.Minor)
+	}
+	if m.Minor <= 0 || (m.Currency == money.INR && amount.GreaterThan(services.MaxAmount)) {
+		t.Fatalf("accepted %s %s, outside the limits", amount, currency)
+	}
+}
+
+func FuzzCreatePaymentIntentRequest(f *testing.F) {
+	for _, amount := range amountSeeds {
+		f.Add(`{"merchant_id":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","payment_method":"upi",` + amount + `}`)
+	}
+	f.Add(`{"merchant_id":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","payment_method":"card","amount":"1000.5","currency":"JPY"}`)
+	f.Add(`{"merchant_id":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","payment_method":"card","amount":"10.125","currency":"BHD"}`)
+	f.Add(`{"merchant_id":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","payment_method":"card","amount":"10","currency":"XXX"}`)
+	f.Add(`{"merchant_id":"not-a-uuid","amount":"1","payment_method":"upi"}`)
+	f.Add(`{"merchant_id":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","amount":"1","payment_method":"upi","expires_in":-1,"metadata":{"\u0000":["\ud800"]}}`)
+	f.Fuzz(func(t *testing.T, body string) {
+		var req services.CreatePaymentIntentRequest
+		if err := binding.JSON.BindBody([]byte(body), &req); err != nil {
+			return
+		}
+		if req.Currency == "" {
+			req.Currency = money.INR
+		}
+		checkAmount(t, req.Amount, req.Currency)
+	})
+}
+
+func FuzzCreateRefundRequest(f *testing.F) {
+	for _, amount := range amountSeeds {
+		f.Add(`{"payment_id":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b",` + amount + `}`)
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		var req services.CreateRefundRequest
+		if err := binding.JSON.BindBody([]byte(body), &req); err != nil {
+			return
+		}
+		checkAmount(t, req.Amount, money.INR)
+	})
+}
+
+func FuzzCreatePaymentRequest(f *testing.F) {
+	for _, vpas := range [][2]string{
+		{"alice@hdfc", "shop@sbi"},
+		{"ålice@hdfc", "shop@ｓｂｉ"},
+		{"alice@@hdfc", "@sbi"},
+		{"alice\u0000@hdfc", "shop@sbi‮"},
+		{"a@b", ".shop@sbi"},
+	} {
+		f.Add(`{"payment_intent_id":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","payer_vpa":"` + vpas[0] + `","payee_vpa":"` + vpas[1] + `"}`)
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		var req services.CreatePaymentRequest
+		if err := binding.JSON.BindBody([]byte(body), &req); err != nil {
+			return
+		}
+		for _, vpa := range []string{req.PayerVPA, req.PayeeVPA} {
+			if services.ValidateVPAFormat(vpa) != nil {
+				continue
+			}
+			at := 0
+			for _, r := range vpa {
+				if r >= utf8.RuneSelf {
+					t.Fatalf("accepted non-ASCII VPA %q", vpa)
+				}
+				if r == '@' {
+					at++
+				}
+			}
+			if at != 1 {
+				t.Fatalf("accepted VPA %q with %d @", vpa, at)
+			}
+		}
+	})
+}
+
+func FuzzRiskRuleRequest(f *testing.F) {
+	for _, expression := range []string{
+		`amount > 50000 && (velocity_1h >= 5 || device_new)`,
+		`payment_method in [\"card\", \"netbanking\"] && geo_mismatch`,
+		`!(hour < 6) || weekend`,
+		`amount > 'x'`,
+		`amount in amount`,
+		`((((amount`,
+		`[1, [2, [3]]] == [1]`,
+		`ip_country == \"\u0000\"`,
+	} {
+		f.Add(`{"name":"RULE","action":"score","weight":0.5,"expression":"` + expression + `"}`)
+	}
+	features := map[string]interface{}{
+		"amount":         100.0,
+		"payment_method": "upi",
+		"geo_mismatch":   true,
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		var req services.RiskRuleRequest
+		if err := binding.JSON.BindBody([]byte(body), &req); err != nil || req.Expression == nil {
+			return
+		}
+		expression, err := services.CompileRiskExpression(*req.Expression)
+		if err != nil {
+			return
+		}
+		// Rules that compile are evaluated on every payment; they may
+		// fail to evaluate, but must not panic
+		_, _ = expression.Match(features)
+		_, _ = expression.Match(nil)
+	})
+}
@@ -19,12 +19,17 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"upi-core/internal/config"
+	"upi-core/internal/crypto"
 	"upi-core/internal/domain/repository"
 	"upi-core/internal/domain/service"
+	"upi-core/internal/fx"
 	"upi-core/internal/http"
+	"upi-core/internal/infrastructure/bankclient"
+	"upi-core/internal/infrastructure/callback"
 	"upi-core/internal/infrastructure/database"
 	"upi-core/internal/infrastructure/kafka"
 	"upi-core/internal/infrastructure/redis"
+	"upi-core/internal/ratelimit"
 	"upi-core/internal/server"
 	"upi-core/pkg/logger"
 	"upi-core/pkg/telemetry"
@@ -97,6 +102,14 @@ func runServer(cmd *cobra.Command, args []string) error {
 	defer db.Close()
 	log.Info("Database connection established")
 
+	// Reads that may lag behind writes go to the read replicas, if any
+	replicas, err := database.NewReplicas(cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize read replicas: %w", err)
+	}
+	replicas.Start()
+	defer replicas.Close()
+
 	// Initialize Redis
 	redisClient, err := redis.New(cfg.Redis)
 	if err != nil {
@@ -113,9 +126,17 @@ func runServer(cmd *cobra.Command, args []string) error {
 	defer kafkaProducer.Close()
 	log.Info("Kafka producer initialized")
 
+	// Cap each bank's and PSP's requests a second
+	var limiter *ratelimit.Limiter
+	unaryInterceptors := []grpc.UnaryServerInterceptor{server.LoggingUnaryInterceptor(log)}
+	if cfg.RateLimit.Enabled {
+		limiter = ratelimit.New(redisClient, cfg.RateLimit, log)
+		unaryInterceptors = append(unaryInterceptors, server.RateLimitUnaryInterceptor(limiter))
+	}
+
 	// Create gRPC server
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(server.LoggingUnaryInterceptor(log)),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
 		grpc.StreamInterceptor(server.LoggingStreamInterceptor(log)),
 	)
 
@@ -125,15 +146,111 @@ func runServer(cmd *cobra.Command, args []string) error {
 	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
 
 	// Create repository and service layers
-	repo := repository.NewPostgreSQLTransactionRepository(db.DB)
-	transactionService := service.NewTransactionService(repo, redisClient, kafkaProducer, log)
+	repo := repository.NewPostgreSQLTransactionRepositoryWithReplicas(db.DB, replicas)
+
+	// Requests to banks are signed with the switch's private key
+	var signer *crypto.Signer
+	if cfg.Security.PrivateKeyPath != "" {
+		if signer, err = crypto.LoadSigner(cfg.Security.PrivateKeyPath); err != nil {
+			return fmt.Errorf("failed to load signing key: %w", err)
+		}
+	} else {
+		log.Warn("No security.private_key_path set, requests to banks will be unsigned")
+	}
+
+	// Connect to the registered banks, over mutual TLS if configured
+	var bankTLS *bankclient.TLSCredentials
+	if cfg.Banks.TLS.Enabled {
+		if bankTLS, err = bankclient.LoadTLS(cfg.Banks.TLS); err != nil {
+			return fmt.Errorf("failed to load bank TLS certificates: %w", err)
+		}
+	} else {
+		log.Warn("banks.tls.enabled is off, connections to banks will be in plaintext")
+	}
+	bankClients := bankclient.NewManager(repo, cfg.Banks, signer, bankTLS, log)
+	if err := bankClients.Start(context.Background()); err != nil {
+		return fmt.Errorf("failed to connect to banks: %w", err)
+	}
+	defer bankClients.Close()
+	log.Info("Bank connections established")
+
+	// Amounts in other currencies are converted at the configured rates
+	rates, err := fx.NewStaticRates(cfg.FX.SettlementCurrency, cfg.FX.Rates)
+	if err != nil {
+		return fmt.Errorf("failed to load FX rates: %w", err)
+	}
+	converter, err := fx.NewConverter(rates, cfg.FX)
+	if err != nil {
+		return fmt.Errorf("invalid FX configuration: %w", err)
+	}
+
+	// Fees are worked out from the rules in fee_rules
+	feeService := service.NewFeeService(repo, cfg.Fees, log)
+	if err := feeService.Start(context.Background()); err != nil {
+		return fmt.Errorf("failed to load fee rules: %w", err)
+	}
+	defer feeService.Close()
+
+	// Resolved VPAs are cached, in Redis unless configured otherwise
+	var vpaStore service.VPACacheStore
+	switch cfg.VPACache.Backend {
+	case "redis":
+		vpaStore = redisClient
+	case "memory":
+		vpaStore = service.NewMemoryVPACacheStore()
+	case "none":
+	default:
+		return fmt.Errorf("vpa_cache.backend %q is not one of redis, memory or none", cfg.VPACache.Backend)
+	}
+	vpaCache := service.NewVPACache(repo, vpaStore, cfg.VPACache, log)
+
+	// Hold payer VPAs to their per-transaction and daily limits
+	var limits *service.TransactionLimits
+	if cfg.Limits.Enabled {
+		limits = service.NewTransactionLimits(redisClient, cfg.Limits, log)
+	}
+	transactionService := service.NewTransactionService(repo, redisClient, kafkaProducer, bankClients, vpaCache, converter, feeService, limits, cfg.Security, log)
+	vpaService := service.NewVPAService(repo, vpaCache, log)
+	mandateService := service.NewMandateService(repo, cfg.Security, log)
+	collectService := service.NewCollectService(repo, transactionService, callback.New(cfg.Collect, signer), cfg.Collect, log)
+	defer collectService.Close()
+
+	// Time out transactions left PENDING past their expiry
+	reaper := service.NewReaper(repo, bankClients, kafkaProducer, redisClient, cfg.Reaper, log)
+	reaper.Start()
+	defer reaper.Close()
+
+	// Resume transactions left midway by an instance that stopped
+	sagaRecovery := service.NewSagaRecovery(repo, bankClients, kafkaProducer, redisClient, cfg.Saga, log)
+	sagaRecovery.Start()
+	defer sagaRecovery.Close()
+
+	// Debit mandates on their due dates
+	mandateScheduler := service.NewMandateScheduler(repo, transactionService, cfg.Mandates, log)
+	mandateScheduler.Start()
+	defer mandateScheduler.Close()
+
+	// Reconcile each day's transactions against the banks' records
+	reconciler, err := service.NewReconciler(repo, bankClients, cfg.Recon, log)
+	if err != nil {
+		return fmt.Errorf("invalid reconciliation configuration: %w", err)
+	}
+	reconciler.Start()
+	defer reconciler.Close()
+
+	// Onboard banks, and put them in and out of maintenance as their
+	// windows start and end
+	bankService := service.NewBankService(repo, bankClients, cfg.Banks, log)
+	bankService.Start()
+	defer bankService.Close()
 
 	// Register UPI Core service
-	upiCoreService := server.NewUpiCoreService(db, redisClient, kafkaProducer, log)
+	upiCoreService := server.NewUpiCoreService(db, redisClient, kafkaProducer, transactionService, vpaService, mandateService, collectService, feeService, reconciler, bankService, log)
 	server.RegisterUpiCoreServer(grpcServer, upiCoreService)
+	server.RegisterBankAdminServer(grpcServer, server.NewBankAdminService(bankService, log))
 
 	// Create HTTP server for REST API (matching frontend expectations)
-	httpServer := http.NewHTTPServer(transactionService, log, "8080")
+	httpServer := http.NewHTTPServer(transactionService, vpaService, reconciler, limiter, log, "8080")
 
 	// Enable reflection in development
 	if cfg.App.Environment == "development" {
@@ -174,13 +291,17 @@ func runServer(cmd *cobra.Command, args []string) error {
 
 	log.Info("Shutting down server...")
 
+	// Set health status to not serving
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	// Let in-flight transactions finish, or checkpoint their sagas, before
+	// the servers stop their handlers
+	transactionService.Drain(cfg.Server.DrainTimeout)
+
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Set health status to not serving
-	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
-
 	// Stop HTTP server
 	if err := httpServer.Stop(ctx); err != nil {
 		log.WithError(err).Error("Error stopping HTTP server")
@@ -235,6 +356,7 @@ func initConfig() (*config.Config, error) {
 	viper.SetDefault("server.read_timeout", "30s")
 	viper.SetDefault("server.write_timeout", "30s")
 	viper.SetDefault("server.idle_timeout", "120s")
+	viper.SetDefault("server.drain_timeout", "15s")
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", 5432)
 	viper.SetDefault("database.username", "postgres")
@@ -244,16 +366,66 @@ func initConfig() (*config.Config, error) {
 	viper.SetDefault("database.max_open_conns", 25)
 	viper.SetDefault("database.max_idle_conns", 5)
 	viper.SetDefault("database.conn_timeout", "30s")
+	viper.SetDefault("database.read_replicas", []string{})
+	viper.SetDefault("database.replica_check_interval", "5s")
 	viper.SetDefault("redis.host", "localhost")
 	viper.SetDefault("redis.port", 6379)
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("redis.pool_size", 10)
+	viper.SetDefault("vpa_cache.backend", "redis")
+	viper.SetDefault("vpa_cache.ttl", "1h")
+	viper.SetDefault("vpa_cache.negative_ttl", "30s")
+	viper.SetDefault("vpa_cache.jitter", 0.1)
 	viper.SetDefault("kafka.brokers", []string{"localhost:9092"})
 	viper.SetDefault("kafka.group_id", "upi-core")
 	viper.SetDefault("kafka.topics.transactions", "upi.transactions")
 	viper.SetDefault("kafka.topics.settlements", "upi.settlements")
 	viper.SetDefault("kafka.topics.events", "upi.events")
+	viper.SetDefault("banks.pool_size", 4)
+	viper.SetDefault("banks.request_timeout", "10s")
+	viper.SetDefault("banks.health_check_interval", "10s")
+	viper.SetDefault("banks.refresh_interval", "1m")
+	viper.SetDefault("banks.unhealthy_threshold", 3)
+	viper.SetDefault("banks.maintenance_check_interval", "30s")
+	viper.SetDefault("banks.max_attempts", 3)
+	viper.SetDefault("banks.retry_base_delay", "100ms")
+	viper.SetDefault("banks.retry_max_delay", "2s")
+	viper.SetDefault("banks.breaker_threshold", 5)
+	viper.SetDefault("banks.breaker_open_timeout", "30s")
+	viper.SetDefault("banks.tls.enabled", false)
+	viper.SetDefault("banks.tls.ca_file", "")
+	viper.SetDefault("banks.tls.cert_file", "")
+	viper.SetDefault("banks.tls.key_file", "")
+	viper.SetDefault("security.require_signatures", false)
+	viper.SetDefault("reaper.scan_interval", "30s")
+	viper.SetDefault("reaper.batch_size", 100)
+	viper.SetDefault("reaper.grace_period", "30s")
+	viper.SetDefault("saga.scan_interval", "30s")
+	viper.SetDefault("saga.batch_size", 100)
+	viper.SetDefault("saga.stale_after", "1m")
+	viper.SetDefault("mandates.scan_interval", "1m")
+	viper.SetDefault("mandates.batch_size", 100)
+	viper.SetDefault("mandates.retry_after", "10m")
+	viper.SetDefault("collect.default_ttl", "30m")
+	viper.SetDefault("collect.max_ttl", "1080h")
+	viper.SetDefault("collect.callback_timeout", "5s")
+	viper.SetDefault("collect.callback_attempts", 3)
+	viper.SetDefault("fx.settlement_currency", "INR")
+	viper.SetDefault("fx.currencies", []string{})
+	viper.SetDefault("fx.markup_bps", 0)
+	viper.SetDefault("fees.refresh_interval", "1m")
+	viper.SetDefault("recon.run_at", "02:00")
+	viper.SetDefault("recon.check_interval", "5m")
+	viper.SetDefault("recon.batch_size", 200)
+	viper.SetDefault("recon.stale_after", "2h")
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.default_tps", 50)
+	viper.SetDefault("rate_limit.default_burst", 100)
+	viper.SetDefault("limits.enabled", true)
+	viper.SetDefault("limits.default.max_transaction_paisa", 10000000)
+	viper.SetDefault("limits.default.daily_amount_paisa", 10000000)
+	viper.SetDefault("limits.default.daily_count", 20)
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.format", "text")
 	viper.SetDefault("telemetry.enabled", false)
@@ -11,7 +11,18 @@ type Config struct {
 	Server    ServerConfig    `mapstructure:"server"`
 	Database  DatabaseConfig  `mapstructure:"database"`
 	Redis     RedisConfig     `mapstructure:"redis"`
+	VPACache  VPACacheConfig  `mapstructure:"vpa_cache"`
 	Kafka     KafkaConfig     `mapstructure:"kafka"`
+	Banks     BanksConfig     `mapstructure:"banks"`
+	Reaper    ReaperConfig    `mapstructure:"reaper"`
+	Saga      SagaConfig      `mapstructure:"saga"`
+	Mandates  MandatesConfig  `mapstructure:"mandates"`
+	Collect   CollectConfig   `mapstructure:"collect"`
+	FX        FXConfig        `mapstructure:"fx"`
+	Fees      FeesConfig      `mapstructure:"fees"`
+	Recon     ReconConfig     `mapstructure:"recon"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Limits    LimitsConfig    `mapstructure:"limits"`
 	Security  SecurityConfig  `mapstructure:"security"`
 	Logging   LoggingConfig   `mapstructure:"logging"`
 	Telemetry TelemetryConfig `mapstructure:"telemetry"`
@@ -31,6 +42,9 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	// How long shutdown waits for in-flight transactions before
+	// checkpointing their sagas for another instance to resume
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
 }
 
 // DatabaseConfig contains database configuration
@@ -44,6 +58,14 @@ type DatabaseConfig struct {
 	MaxOpenConns int           `mapstructure:"max_open_conns"`
 	MaxIdleConns int           `mapstructure:"max_idle_conns"`
 	ConnTimeout  time.Duration `mapstructure:"conn_timeout"`
+
+	// ReadReplicas are the host:port addresses of read-only copies of the
+	// database, which take the reads that may lag behind writes. They share
+	// the primary's credentials and pool settings.
+	ReadReplicas []string `mapstructure:"read_replicas"`
+	// ReplicaCheckInterval is how often replicas are pinged; reads go to the
+	// primary while none is healthy
+	ReplicaCheckInterval time.Duration `mapstructure:"replica_check_interval"`
 }
 
 // RedisConfig contains Redis configuration
@@ -55,6 +77,20 @@ type RedisConfig struct {
 	PoolSize int    `mapstructure:"pool_size"`
 }
 
+// VPACacheConfig contains the settings of the cache of resolved VPAs
+type VPACacheConfig struct {
+	// Backend is where resolved VPAs are cached: redis, memory (this
+	// instance only, so another instance's VPA writes do not invalidate
+	// it), or none
+	Backend string        `mapstructure:"backend"`
+	TTL     time.Duration `mapstructure:"ttl"`
+	// NegativeTTL is how long a VPA is remembered as unknown
+	NegativeTTL time.Duration `mapstructure:"negative_ttl"`
+	// Jitter is the fraction of a TTL by which entries expire early at
+	// random, so that entries cached together do not all expire together
+	Jitter float64 `mapstructure:"jitter"`
+}
+
 // KafkaConfig contains Kafka configuration
 type KafkaConfig struct {
 	Brokers []string          `mapstructure:"brokers"`
@@ -69,6 +105,175 @@ type KafkaTopicsConfig struct {
 	Events       string `mapstructure:"events"`
 }
 
+// BanksConfig contains configuration for the gRPC connections to member banks
+type BanksConfig struct {
+	PoolSize            int           `mapstructure:"pool_size"`
+	RequestTimeout      time.Duration `mapstructure:"request_timeout"`
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval"`
+	RefreshInterval     time.Duration `mapstructure:"refresh_interval"`
+	UnhealthyThreshold  int           `mapstructure:"unhealthy_threshold"`
+	// MaintenanceCheckInterval is how often banks are put in and taken out
+	// of maintenance as their scheduled windows start and end
+	MaintenanceCheckInterval time.Duration `mapstructure:"maintenance_check_interval"`
+
+	// MaxAttempts is how many times a call failing with a transient error is
+	// tried in all, waiting RetryBaseDelay, doubling up to RetryMaxDelay,
+	// with jitter, between tries
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	RetryBaseDelay time.Duration `mapstructure:"retry_base_delay"`
+	RetryMaxDelay  time.Duration `mapstructure:"retry_max_delay"`
+	// A bank's circuit breaker opens after BreakerThreshold failed calls in
+	// a row and lets a probe call through after BreakerOpenTimeout
+	BreakerThreshold   int           `mapstructure:"breaker_threshold"`
+	BreakerOpenTimeout time.Duration `mapstructure:"breaker_open_timeout"`
+
+	TLS BankTLSConfig `mapstructure:"tls"`
+}
+
+// BankTLSConfig contains the mutual TLS settings of the connections to
+// banks. The files are reread every refresh interval, so certificates can
+// be rotated without a restart.
+type BankTLSConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// CAFile is the PEM bundle bank server certificates are verified
+	// against; the system roots if empty
+	CAFile string `mapstructure:"ca_file"`
+	// CertFile and KeyFile are the client certificate presented to banks
+	// without one of their own in Banks
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// Banks holds per-bank settings by bank code
+	Banks map[string]BankCertConfig `mapstructure:"banks"`
+}
+
+// BankCertConfig is the client certificate presented to one bank, and the
+// name its server certificate must carry if not its endpoint's host
+type BankCertConfig struct {
+	CertFile   string `mapstructure:"cert_file"`
+	KeyFile    string `mapstructure:"key_file"`
+	ServerName string `mapstructure:"server_name"`
+}
+
+// ReaperConfig contains the settings of the worker that times out expired
+// transactions
+type ReaperConfig struct {
+	ScanInterval time.Duration `mapstructure:"scan_interval"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	// GracePeriod is how long past its expiry a transaction is left to its
+	// processor before being timed out
+	GracePeriod time.Duration `mapstructure:"grace_period"`
+}
+
+// SagaConfig contains the settings of the worker that resumes transactions
+// whose processor stopped midway
+type SagaConfig struct {
+	ScanInterval time.Duration `mapstructure:"scan_interval"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	// StaleAfter is how long a saga step may go unrecorded before the saga
+	// is taken over. It must be longer than any one bank call, retries
+	// included.
+	StaleAfter time.Duration `mapstructure:"stale_after"`
+}
+
+// MandatesConfig contains the settings of the scheduler that executes due
+// mandates
+type MandatesConfig struct {
+	ScanInterval time.Duration `mapstructure:"scan_interval"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	// RetryAfter is how long an execution may stay PENDING, e.g. because
+	// the instance making it crashed, before it is made again
+	RetryAfter time.Duration `mapstructure:"retry_after"`
+}
+
+// CollectConfig contains the settings of collect requests and their
+// delivery to payers' PSPs
+type CollectConfig struct {
+	// DefaultTTL is how long a collect request waits for the payer when the
+	// payee asks for no expiry of its own, which may be at most MaxTTL
+	DefaultTTL time.Duration `mapstructure:"default_ttl"`
+	MaxTTL     time.Duration `mapstructure:"max_ttl"`
+	// A collect request is POSTed to the payer bank's callback URL, tried
+	// up to CallbackAttempts times with CallbackTimeout each
+	CallbackTimeout  time.Duration `mapstructure:"callback_timeout"`
+	CallbackAttempts int           `mapstructure:"callback_attempts"`
+}
+
+// FXConfig contains the currencies transactions may be made in and how
+// their amounts are converted to the settlement currency
+type FXConfig struct {
+	// SettlementCurrency is what banks are debited and credited in
+	SettlementCurrency string `mapstructure:"settlement_currency"`
+	// Currencies are accepted besides the settlement currency
+	Currencies []string `mapstructure:"currencies"`
+	// Rates are the units of the settlement currency one unit of each
+	// currency buys, as decimals such as "83.25"
+	Rates map[string]string `mapstructure:"rates"`
+	// MarkupBps is the cross-currency fee, in basis points of the settled
+	// amount
+	MarkupBps int64 `mapstructure:"markup_bps"`
+}
+
+// FeesConfig contains the settings of the fee rules cache
+type FeesConfig struct {
+	// RefreshInterval is how often the fee rules are reloaded, picking up
+	// changes made through other instances
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// ReconConfig contains the settings of the nightly reconciliation of
+// transactions against the banks' records
+type ReconConfig struct {
+	// RunAt is the time of day in India, as HH:MM, from which the previous
+	// day is reconciled
+	RunAt string `mapstructure:"run_at"`
+	// CheckInterval is how often instances check whether a day is due, or
+	// its run failed and is to be retried
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	// StaleAfter is how long a run may go unfinished, e.g. because the
+	// instance making it crashed, before another instance starts it over
+	StaleAfter time.Duration `mapstructure:"stale_after"`
+}
+
+// RateLimitConfig contains the per-caller request limits, enforced with a
+// token bucket per caller in Redis
+type RateLimitConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// DefaultTPS and DefaultBurst apply to callers without a quota of
+	// their own
+	DefaultTPS   float64 `mapstructure:"default_tps"`
+	DefaultBurst int     `mapstructure:"default_burst"`
+	// Quotas are keyed by the bank or PSP code a caller identifies as
+	Quotas map[string]QuotaConfig `mapstructure:"quotas"`
+}
+
+// QuotaConfig is the sustained requests a second and the burst a caller
+// is allowed
+type QuotaConfig struct {
+	TPS   float64 `mapstructure:"tps"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// LimitsConfig contains the limits on the amounts payer VPAs send, counted
+// per day in India in Redis
+type LimitsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Default applies to VPAs without limits of their own
+	Default VPALimitConfig `mapstructure:"default"`
+	// VPAs are the VPAs with limits of their own. They are a list, not a map
+	// keyed by VPA, as VPAs may contain dots.
+	VPAs []VPALimitConfig `mapstructure:"vpas"`
+}
+
+// VPALimitConfig is what a payer VPA may send. Amounts are in paisa of the
+// settlement currency; a limit of zero is not enforced.
+type VPALimitConfig struct {
+	VPA                 string `mapstructure:"vpa"`
+	MaxTransactionPaisa int64  `mapstructure:"max_transaction_paisa"`
+	DailyAmountPaisa    int64  `mapstructure:"daily_amount_paisa"`
+	DailyCount          int64  `mapstructure:"daily_count"`
+}
+
 // SecurityConfig contains security configuration
 type SecurityConfig struct {
 	PrivateKeyPath string `mapstructure:"private_key_path"`
@@ -76,6 +281,10 @@ type SecurityConfig struct {
 	EnableTLS      bool   `mapstructure:"enable_tls"`
 	TLSCertFile    string `mapstructure:"tls_cert_file"`
 	TLSKeyFile     string `mapstructure:"tls_key_file"`
+
+	// RequireSignatures rejects unsigned transaction requests; signed ones
+	// are always verified
+	RequireSignatures bool `mapstructure:"require_signatures"`
 }
 
 // LoggingConfig contains logging configuration
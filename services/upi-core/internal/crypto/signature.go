@@ -0,0 +1,139 @@
+// Package crypto signs the switch's requests to banks and verifies the
+// signatures banks and PSPs put on theirs. Keys are PEM encoded RSA or
+// Ed25519 keys; signatures are base64 encoded, RSA ones PKCS #1 v1.5 over
+// the SHA-256 of the payload.
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrInvalidSignature is returned when a signature does not match its
+// payload and key
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Signer signs payloads with a private key
+type Signer struct {
+	key stdcrypto.Signer
+}
+
+// LoadSigner reads a PEM encoded private key from a file
+func LoadSigner(path string) (*Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	return ParseSigner(data)
+}
+
+// ParseSigner parses a PEM encoded PKCS #8 private key, or a PKCS #1 RSA
+// private key
+func ParseSigner(data []byte) (*Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return &Signer{key: key}, nil
+	case ed25519.PrivateKey:
+		return &Signer{key: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// Sign returns the base64 encoded signature of payload
+func (s *Signer) Sign(payload []byte) (string, error) {
+	var signature []byte
+	var err error
+	switch key := s.key.(type) {
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256(payload)
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, stdcrypto.SHA256, digest[:])
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, payload)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to sign payload: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// ParsePublicKey parses a PEM encoded PKIX public key, or a PKCS #1 RSA
+// public key
+func ParsePublicKey(data string) (stdcrypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// Verify checks a base64 encoded signature of payload against a PEM encoded
+// public key. It returns an error wrapping ErrInvalidSignature if the
+// signature, or the key it is checked against, is not valid.
+func Verify(publicKey string, payload []byte, signature string) error {
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not base64 encoded", ErrInvalidSignature)
+	}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		if err := rsa.VerifyPKCS1v15(key, stdcrypto.SHA256, digest[:], decoded); err != nil {
+			return ErrInvalidSignature
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, decoded) {
+			return ErrInvalidSignature
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+)
+
+// keyPair returns a PEM encoded private and public key
+func keyPair(t *testing.T, algorithm string) ([]byte, string) {
+	t.Helper()
+	var private, public interface{}
+	switch algorithm {
+	case "rsa":
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = key, &key.PublicKey
+	case "ed25519":
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = key, pub
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+}
+
+func TestSignAndVerify(t *testing.T) {
+	for _, algorithm := range []string{"rsa", "ed25519"} {
+		t.Run(algorithm, func(t *testing.T) {
+			privateKey, publicKey := keyPair(t, algorithm)
+			signer, err := ParseSigner(privateKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			payload := []byte("TXN1|alice@hdfc|bob@sbi|10000")
+			signature, err := signer.Sign(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := Verify(publicKey, payload, signature); err != nil {
+				t.Errorf("valid signature rejected: %v", err)
+			}
+			if err := Verify(publicKey, []byte("TXN1|alice@hdfc|bob@sbi|99999"), signature); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("tampered payload: err = %v, want ErrInvalidSignature", err)
+			}
+			_, otherKey := keyPair(t, algorithm)
+			if err := Verify(otherKey, payload, signature); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("another key: err = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsMalformedInput(t *testing.T) {
+	_, publicKey := keyPair(t, "ed25519")
+	for name, tc := range map[string]struct{ key, signature string }{
+		"key not PEM":          {"mock_public_key_hdfc", "c2lnbmF0dXJl"},
+		"signature not base64": {publicKey, "not base64!"},
+		"empty signature":      {publicKey, ""},
+	} {
+		if err := Verify(tc.key, []byte("payload"), tc.signature); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: err = %v, want ErrInvalidSignature", name, err)
+		}
+	}
+}
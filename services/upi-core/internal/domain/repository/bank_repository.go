@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Bank statuses; see migrations/012_bank_onboarding.sql
+const (
+	BankPendingApproval = "PENDING_APPROVAL"
+	BankActive          = "ACTIVE"
+	BankInactive        = "INACTIVE"
+	BankMaintenance     = "MAINTENANCE"
+	BankSuspended       = "SUSPENDED"
+	BankRejected        = "REJECTED"
+)
+
+// ErrBankAlreadyRegistered is returned by CreateBank for a bank code that
+// is taken
+var ErrBankAlreadyRegistered = errors.New("bank is already registered")
+
+// CreateBank registers a new bank, PENDING_APPROVAL. Its ID and timestamps
+// are set from the stored row.
+func (r *PostgreSQLTransactionRepository) CreateBank(ctx context.Context, tx *sql.Tx, bank *Bank) error {
+	query := `
+		INSERT INTO banks (bank_code, bank_name, ifsc_prefix, endpoint_url, public_key, features, vpa_handles,
+			callback_url, status, registered_by)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, ARRAY['UPI', 'IMPS', 'NEFT', 'RTGS']), COALESCE($7, ARRAY[lower($1)]),
+			NULLIF($8, ''), 'PENDING_APPROVAL', $9)
+		ON CONFLICT (bank_code) DO NOTHING
+		RETURNING id, created_at, updated_at
+	`
+
+	var features, handles interface{}
+	if len(bank.Features) > 0 {
+		features = pq.Array(bank.Features)
+	}
+	if len(bank.VPAHandles) > 0 {
+		handles = pq.Array(bank.VPAHandles)
+	}
+	err := r.conn(tx).QueryRowContext(ctx, query,
+		bank.BankCode,
+		bank.BankName,
+		bank.IFSCPrefix,
+		bank.EndpointURL,
+		bank.PublicKey,
+		features,
+		handles,
+		bank.CallbackURL,
+		bank.RegisteredBy,
+	).Scan(&bank.ID, &bank.CreatedAt, &bank.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrBankAlreadyRegistered
+	}
+	if err == nil {
+		bank.Status = BankPendingApproval
+	}
+	return err
+}
+
+// LockBank reads a bank and locks its row until tx ends
+func (r *PostgreSQLTransactionRepository) LockBank(ctx context.Context, tx *sql.Tx, bankCode string) (*Bank, error) {
+	query := `SELECT ` + bankColumns + ` FROM banks WHERE bank_code = $1 FOR UPDATE`
+
+	return scanBank(tx.QueryRowContext(ctx, query, bankCode))
+}
+
+// UpdateBankLifecycle stores a bank's status, the reason for it, its
+// approval and its maintenance window
+func (r *PostgreSQLTransactionRepository) UpdateBankLifecycle(ctx context.Context, tx *sql.Tx, bank *Bank) error {
+	query := `
+		UPDATE banks SET
+			status = $2,
+			status_reason = NULLIF($3, ''),
+			registered_by = NULLIF($4, ''),
+			approved_by = NULLIF($5, ''),
+			approved_at = $6,
+			maintenance_window_id = $7,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE bank_code = $1
+	`
+
+	result, err := r.conn(tx).ExecContext(ctx, query,
+		bank.BankCode,
+		bank.Status,
+		bank.StatusReason,
+		bank.RegisteredBy,
+		bank.ApprovedBy,
+		bank.ApprovedAt,
+		bank.MaintenanceWindowID,
+	)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// BankFilter selects the banks ListBanks returns
+type BankFilter struct {
+	Status string // Empty matches every status
+	After  string // Bank code the previous page ended with
+	Limit  int
+}
+
+// ListBanks lists up to filter.Limit banks by bank code
+func (r *PostgreSQLTransactionRepository) ListBanks(ctx context.Context, filter BankFilter) ([]*Bank, error) {
+	query := `
+		SELECT ` + bankColumns + ` FROM banks
+		WHERE ($1 = '' OR status = $1) AND bank_code > $2
+		ORDER BY bank_code
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.Status, filter.After, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var banks []*Bank
+	for rows.Next() {
+		bank, err := scanBank(rows)
+		if err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank)
+	}
+	return banks, rows.Err()
+}
+
+// MaintenanceWindow is a period a bank is taken out of service for
+type MaintenanceWindow struct {
+	WindowID    int64      `db:"window_id"`
+	BankCode    string     `db:"bank_code"`
+	StartsAt    time.Time  `db:"starts_at"`
+	EndsAt      time.Time  `db:"ends_at"`
+	Reason      string     `db:"reason"`
+	CreatedBy   string     `db:"created_by"`
+	CreatedAt   time.Time  `db:"created_at"`
+	CancelledAt *time.Time `db:"cancelled_at"`
+}
+
+// ErrMaintenanceWindowOverlaps is returned by CreateMaintenanceWindow when
+// the bank has another window in the same period
+var ErrMaintenanceWindowOverlaps = errors.New("maintenance window overlaps another of the bank's windows")
+
+// maintenanceWindowColumns is the column list scanMaintenanceWindow reads
+const maintenanceWindowColumns = `
+	window_id, bank_code, starts_at, ends_at, reason, created_by, created_at, cancelled_at
+`
+
+// scanMaintenanceWindow reads a row selected with maintenanceWindowColumns
+func scanMaintenanceWindow(row interface{ Scan(...interface{}) error }) (*MaintenanceWindow, error) {
+	var window MaintenanceWindow
+	err := row.Scan(
+		&window.WindowID,
+		&window.BankCode,
+		&window.StartsAt,
+		&window.EndsAt,
+		&window.Reason,
+		&window.CreatedBy,
+		&window.CreatedAt,
+		&window.CancelledAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+// CreateMaintenanceWindow schedules a maintenance window; its ID and
+// creation time are set from the stored row. Callers lock the bank first,
+// so that windows of it are never created side by side.
+func (r *PostgreSQLTransactionRepository) CreateMaintenanceWindow(ctx context.Context, tx *sql.Tx, window *MaintenanceWindow) error {
+	overlap := `
+		SELECT EXISTS (
+			SELECT 1 FROM bank_maintenance_windows
+			WHERE bank_code = $1 AND cancelled_at IS NULL AND starts_at < $3 AND ends_at > $2
+		)
+	`
+	insert := `
+		INSERT INTO bank_maintenance_windows (bank_code, starts_at, ends_at, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING window_id, created_at
+	`
+
+	var overlaps bool
+	if err := r.conn(tx).QueryRowContext(ctx, overlap, window.BankCode, window.StartsAt, window.EndsAt).Scan(&overlaps); err != nil {
+		return err
+	}
+	if overlaps {
+		return ErrMaintenanceWindowOverlaps
+	}
+	return r.conn(tx).QueryRowContext(ctx, insert,
+		window.BankCode,
+		window.StartsAt,
+		window.EndsAt,
+		window.Reason,
+		window.CreatedBy,
+	).Scan(&window.WindowID, &window.CreatedAt)
+}
+
+// GetMaintenanceWindow retrieves a maintenance window by its ID
+func (r *PostgreSQLTransactionRepository) GetMaintenanceWindow(ctx context.Context, windowID int64) (*MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceWindowColumns + ` FROM bank_maintenance_windows WHERE window_id = $1`
+
+	return scanMaintenanceWindow(r.db.QueryRowContext(ctx, query, windowID))
+}
+
+// CancelMaintenanceWindow cancels a window that has not ended, or reports
+// sql.ErrNoRows
+func (r *PostgreSQLTransactionRepository) CancelMaintenanceWindow(ctx context.Context, tx *sql.Tx, windowID int64) error {
+	query := `
+		UPDATE bank_maintenance_windows SET cancelled_at = CURRENT_TIMESTAMP
+		WHERE window_id = $1 AND cancelled_at IS NULL AND ends_at > CURRENT_TIMESTAMP
+	`
+
+	result, err := r.conn(tx).ExecContext(ctx, query, windowID)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// ListMaintenanceWindows lists a bank's windows that are not cancelled and
+// end after endsAfter, earliest first
+func (r *PostgreSQLTransactionRepository) ListMaintenanceWindows(ctx context.Context, bankCode string, endsAfter time.Time) ([]*MaintenanceWindow, error) {
+	query := `
+		SELECT ` + maintenanceWindowColumns + ` FROM bank_maintenance_windows
+		WHERE bank_code = $1 AND cancelled_at IS NULL AND ends_at > $2
+		ORDER BY starts_at
+	`
+
+	return r.queryMaintenanceWindows(ctx, query, bankCode, endsAfter)
+}
+
+// ListStartedMaintenanceWindows lists the windows under way at now whose
+// bank is ACTIVE, i.e. still to be put in MAINTENANCE
+func (r *PostgreSQLTransactionRepository) ListStartedMaintenanceWindows(ctx context.Context, now time.Time) ([]*MaintenanceWindow, error) {
+	query := `
+		SELECT w.window_id, w.bank_code, w.starts_at, w.ends_at, w.reason, w.created_by, w.created_at, w.cancelled_at
+		FROM bank_maintenance_windows w
+		JOIN banks b ON b.bank_code = w.bank_code
+		WHERE w.cancelled_at IS NULL AND w.starts_at <= $1 AND w.ends_at > $1 AND b.status = 'ACTIVE'
+		ORDER BY w.starts_at
+	`
+
+	return r.queryMaintenanceWindows(ctx, query, now)
+}
+
+// ListBanksLeavingMaintenance lists the banks in MAINTENANCE for a window
+// that has ended or was cancelled, i.e. to be made ACTIVE again
+func (r *PostgreSQLTransactionRepository) ListBanksLeavingMaintenance(ctx context.Context, now time.Time) ([]string, error) {
+	query := `
+		SELECT b.bank_code
+		FROM banks b
+		JOIN bank_maintenance_windows w ON w.window_id = b.maintenance_window_id
+		WHERE b.status = 'MAINTENANCE' AND (w.cancelled_at IS NOT NULL OR w.ends_at <= $1)
+		ORDER BY b.bank_code
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bankCodes []string
+	for rows.Next() {
+		var bankCode string
+		if err := rows.Scan(&bankCode); err != nil {
+			return nil, err
+		}
+		bankCodes = append(bankCodes, bankCode)
+	}
+	return bankCodes, rows.Err()
+}
+
+func (r *PostgreSQLTransactionRepository) queryMaintenanceWindows(ctx context.Context, query string, args ...interface{}) ([]*MaintenanceWindow, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var windows []*MaintenanceWindow
+	for rows.Next() {
+		window, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, rows.Err()
+}
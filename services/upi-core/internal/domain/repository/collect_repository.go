@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// CollectStatus represents the status of a collect request
+type CollectStatus string
+
+const (
+	CollectPending  CollectStatus = "PENDING"
+	CollectApproved CollectStatus = "APPROVED" // Approved and paid
+	CollectDeclined CollectStatus = "DECLINED"
+	CollectFailed   CollectStatus = "FAILED" // Approved, but the payment failed
+	CollectExpired  CollectStatus = "EXPIRED"
+)
+
+// CollectRequest is a payee's request for money from a payer. Once approved
+// it is paid with the transaction of the same ID.
+type CollectRequest struct {
+	CollectID     string          `db:"collect_id"`
+	PayeeVPA      string          `db:"payee_vpa"`
+	PayerVPA      string          `db:"payer_vpa"`
+	PayeeBankCode string          `db:"payee_bank_code"`
+	PayerBankCode string          `db:"payer_bank_code"`
+	AmountPaisa   int64           `db:"amount_paisa"`
+	Type          TransactionType `db:"transaction_type"`
+	Description   string          `db:"description"`
+	Status        CollectStatus   `db:"status"`
+	ErrorCode     string          `db:"error_code"`
+	ErrorMessage  string          `db:"error_message"`
+	ExpiresAt     time.Time       `db:"expires_at"`
+	DeliveredAt   *time.Time      `db:"delivered_at"` // When the payer's PSP acknowledged the request
+	RespondedAt   *time.Time      `db:"responded_at"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+}
+
+// collectColumns is the column list scanCollectRequest reads
+const collectColumns = `
+	collect_id, payee_vpa, payer_vpa, payee_bank_code, payer_bank_code,
+	amount_paisa, transaction_type, COALESCE(description, ''), status,
+	COALESCE(error_code, ''), COALESCE(error_message, ''), expires_at,
+	delivered_at, responded_at, created_at, updated_at
+`
+
+// scanCollectRequest reads a row selected with collectColumns
+func scanCollectRequest(row interface{ Scan(...interface{}) error }) (*CollectRequest, error) {
+	var c CollectRequest
+	err := row.Scan(
+		&c.CollectID,
+		&c.PayeeVPA,
+		&c.PayerVPA,
+		&c.PayeeBankCode,
+		&c.PayerBankCode,
+		&c.AmountPaisa,
+		&c.Type,
+		&c.Description,
+		&c.Status,
+		&c.ErrorCode,
+		&c.ErrorMessage,
+		&c.ExpiresAt,
+		&c.DeliveredAt,
+		&c.RespondedAt,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ErrCollectRequestExists is returned by CreateCollectRequest when the
+// collect ID is taken
+var ErrCollectRequestExists = errors.New("collect request already exists")
+
+// CreateCollectRequest stores a new PENDING collect request. Its
+// timestamps are set from the stored row.
+func (r *PostgreSQLTransactionRepository) CreateCollectRequest(ctx context.Context, tx *sql.Tx, request *CollectRequest) error {
+	query := `
+		INSERT INTO collect_requests (
+			collect_id, payee_vpa, payer_vpa, payee_bank_code, payer_bank_code,
+			amount_paisa, transaction_type, description, status, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), 'PENDING', $9)
+		ON CONFLICT (collect_id) DO NOTHING
+		RETURNING created_at, updated_at
+	`
+
+	err := r.conn(tx).QueryRowContext(ctx, query,
+		request.CollectID,
+		request.PayeeVPA,
+		request.PayerVPA,
+		request.PayeeBankCode,
+		request.PayerBankCode,
+		request.AmountPaisa,
+		request.Type,
+		request.Description,
+		request.ExpiresAt,
+	).Scan(&request.CreatedAt, &request.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCollectRequestExists
+	}
+	if err != nil {
+		return err
+	}
+	request.Status = CollectPending
+	return nil
+}
+
+// GetCollectRequest retrieves a collect request by its ID
+func (r *PostgreSQLTransactionRepository) GetCollectRequest(ctx context.Context, collectID string) (*CollectRequest, error) {
+	query := `SELECT ` + collectColumns + ` FROM collect_requests WHERE collect_id = $1`
+
+	return scanCollectRequest(r.db.QueryRowContext(ctx, query, collectID))
+}
+
+// LockCollectRequest reads a collect request and locks it until tx ends,
+// so it is answered, and expired, once
+func (r *PostgreSQLTransactionRepository) LockCollectRequest(ctx context.Context, tx *sql.Tx, collectID string) (*CollectRequest, error) {
+	query := `SELECT ` + collectColumns + ` FROM collect_requests WHERE collect_id = $1 FOR UPDATE`
+
+	return scanCollectRequest(tx.QueryRowContext(ctx, query, collectID))
+}
+
+// FinishCollectRequest records the payer's answer to a PENDING collect
+// request, or its outcome
+func (r *PostgreSQLTransactionRepository) FinishCollectRequest(ctx context.Context, tx *sql.Tx, collectID string, status CollectStatus, errorCode, errorMessage string) error {
+	query := `
+		UPDATE collect_requests SET
+			status = $2,
+			error_code = NULLIF($3, ''),
+			error_message = NULLIF($4, ''),
+			responded_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE collect_id = $1 AND status = 'PENDING'
+	`
+
+	result, err := r.conn(tx).ExecContext(ctx, query, collectID, status, errorCode, errorMessage)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// MarkCollectRequestDelivered records that the payer's PSP received a
+// collect request
+func (r *PostgreSQLTransactionRepository) MarkCollectRequestDelivered(ctx context.Context, collectID string) error {
+	query := `
+		UPDATE collect_requests SET delivered_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE collect_id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, collectID)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// ExpireCollectRequests marks the collect requests still PENDING at before
+// EXPIRED and returns how many it expired. Requests being answered are
+// locked and wait for the answer.
+func (r *PostgreSQLTransactionRepository) ExpireCollectRequests(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		UPDATE collect_requests SET status = 'EXPIRED', updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'PENDING' AND expires_at <= $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
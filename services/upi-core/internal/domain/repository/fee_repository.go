@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// FeeType is which of a transaction's fees a rule sets
+type FeeType string
+
+const (
+	FeeSwitch FeeType = "SWITCH"
+	FeeBank   FeeType = "BANK"
+)
+
+// FeeRule works out one fee for the transactions it matches; see
+// migrations/010_fee_rules.sql
+type FeeRule struct {
+	RuleID          int64           `db:"rule_id"`
+	FeeType         FeeType         `db:"fee_type"`
+	BankCode        string          `db:"bank_code"`        // Payer's bank; empty matches every bank
+	TransactionType TransactionType `db:"transaction_type"` // Empty matches every type
+	MinAmountPaisa  int64           `db:"min_amount_paisa"`
+	MaxAmountPaisa  *int64          `db:"max_amount_paisa"` // Exclusive; nil has no upper bound
+	FixedPaisa      int64           `db:"fixed_paisa"`
+	PercentBps      int64           `db:"percent_bps"`
+	MinFeePaisa     int64           `db:"min_fee_paisa"`
+	MaxFeePaisa     *int64          `db:"max_fee_paisa"` // nil has no cap
+	Description     string          `db:"description"`
+	CreatedAt       time.Time       `db:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at"`
+}
+
+// feeRuleColumns is the column list scanFeeRule reads
+const feeRuleColumns = `
+	rule_id, fee_type, COALESCE(bank_code, ''), COALESCE(transaction_type, ''),
+	min_amount_paisa, max_amount_paisa, fixed_paisa, percent_bps, min_fee_paisa,
+	max_fee_paisa, COALESCE(description, ''), created_at, updated_at
+`
+
+// scanFeeRule reads a row selected with feeRuleColumns
+func scanFeeRule(row interface{ Scan(...interface{}) error }) (*FeeRule, error) {
+	var rule FeeRule
+	err := row.Scan(
+		&rule.RuleID,
+		&rule.FeeType,
+		&rule.BankCode,
+		&rule.TransactionType,
+		&rule.MinAmountPaisa,
+		&rule.MaxAmountPaisa,
+		&rule.FixedPaisa,
+		&rule.PercentBps,
+		&rule.MinFeePaisa,
+		&rule.MaxFeePaisa,
+		&rule.Description,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// CreateFeeRule stores a new fee rule. Its ID and timestamps are set from
+// the stored row.
+func (r *PostgreSQLTransactionRepository) CreateFeeRule(ctx context.Context, rule *FeeRule) error {
+	query := `
+		INSERT INTO fee_rules (
+			fee_type, bank_code, transaction_type, min_amount_paisa, max_amount_paisa,
+			fixed_paisa, percent_bps, min_fee_paisa, max_fee_paisa, description
+		) VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7, $8, $9, NULLIF($10, ''))
+		RETURNING rule_id, created_at, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		rule.FeeType,
+		rule.BankCode,
+		rule.TransactionType,
+		rule.MinAmountPaisa,
+		rule.MaxAmountPaisa,
+		rule.FixedPaisa,
+		rule.PercentBps,
+		rule.MinFeePaisa,
+		rule.MaxFeePaisa,
+		rule.Description,
+	).Scan(&rule.RuleID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// UpdateFeeRule stores every field of an existing fee rule. It returns
+// sql.ErrNoRows if there is no rule with its ID.
+func (r *PostgreSQLTransactionRepository) UpdateFeeRule(ctx context.Context, rule *FeeRule) error {
+	query := `
+		UPDATE fee_rules SET
+			fee_type = $2,
+			bank_code = NULLIF($3, ''),
+			transaction_type = NULLIF($4, ''),
+			min_amount_paisa = $5,
+			max_amount_paisa = $6,
+			fixed_paisa = $7,
+			percent_bps = $8,
+			min_fee_paisa = $9,
+			max_fee_paisa = $10,
+			description = NULLIF($11, ''),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE rule_id = $1
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		rule.RuleID,
+		rule.FeeType,
+		rule.BankCode,
+		rule.TransactionType,
+		rule.MinAmountPaisa,
+		rule.MaxAmountPaisa,
+		rule.FixedPaisa,
+		rule.PercentBps,
+		rule.MinFeePaisa,
+		rule.MaxFeePaisa,
+		rule.Description,
+	).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// DeleteFeeRule removes a fee rule. It returns sql.ErrNoRows if there is
+// no rule with the ID.
+func (r *PostgreSQLTransactionRepository) DeleteFeeRule(ctx context.Context, ruleID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM fee_rules WHERE rule_id = $1`, ruleID)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// GetFeeRule retrieves a fee rule by its ID
+func (r *PostgreSQLTransactionRepository) GetFeeRule(ctx context.Context, ruleID int64) (*FeeRule, error) {
+	query := `SELECT ` + feeRuleColumns + ` FROM fee_rules WHERE rule_id = $1`
+
+	return scanFeeRule(r.db.QueryRowContext(ctx, query, ruleID))
+}
+
+// ListFeeRules returns every fee rule, in ID order
+func (r *PostgreSQLTransactionRepository) ListFeeRules(ctx context.Context) ([]*FeeRule, error) {
+	query := `SELECT ` + feeRuleColumns + ` FROM fee_rules ORDER BY rule_id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*FeeRule
+	for rows.Next() {
+		rule, err := scanFeeRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testRepository connects to the database in UPI_CORE_TEST_DATABASE_DSN,
+// migrated with migrations/, e.g. the one docker-compose.yml starts:
+//
+//	UPI_CORE_TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=upi_core sslmode=disable" go test ./internal/domain/repository/
+func testRepository(t *testing.T) (*PostgreSQLTransactionRepository, *sql.DB) {
+	t.Helper()
+	dsn := os.Getenv("UPI_CORE_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("UPI_CORE_TEST_DATABASE_DSN not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(50)
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatalf("database unreachable: %v", err)
+	}
+	return &PostgreSQLTransactionRepository{db: db}, db
+}
+
+func testKey(t *testing.T, db *sql.DB) string {
+	t.Helper()
+	key := fmt.Sprintf("test_%s_%d", t.Name(), time.Now().UnixNano())
+	t.Cleanup(func() { db.Exec(`DELETE FROM idempotency_keys WHERE key_hash = $1`, key) })
+	return key
+}
+
+type claimResult struct {
+	claimed  bool
+	response string
+	err      error
+}
+
+// claimConcurrently has n goroutines claim key at once
+func claimConcurrently(repo *PostgreSQLTransactionRepository, key string, n int) []claimResult {
+	results := make([]claimResult, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			claimed, response, err := repo.ClaimIdempotencyKey(context.Background(), key, "transaction", "TXN1", time.Now().Add(time.Minute))
+			results[i] = claimResult{claimed, response, err}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return results
+}
+
+func TestClaimIdempotencyKeyUnderConcurrency(t *testing.T) {
+	repo, db := testRepository(t)
+	key := testKey(t, db)
+
+	claims, inFlight := 0, 0
+	for _, r := range claimConcurrently(repo, key, 100) {
+		switch {
+		case r.err == nil && r.claimed:
+			claims++
+		case errors.Is(r.err, ErrIdempotencyKeyInFlight):
+			inFlight++
+		default:
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+	if claims != 1 || inFlight != 99 {
+		t.Fatalf("%d claimed and %d turned away, want 1 and 99", claims, inFlight)
+	}
+
+	// Once the winner completes, every duplicate gets its response
+	if err := repo.CompleteIdempotencyKey(context.Background(), nil, key, []byte(`{"transaction_id":"TXN1"}`), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range claimConcurrently(repo, key, 100) {
+		if r.err != nil || r.claimed || r.response != `{"transaction_id": "TXN1"}` {
+			t.Fatalf("after completion: %+v", r)
+		}
+	}
+}
+
+func TestReleasedIdempotencyKeyCanBeClaimedAgain(t *testing.T) {
+	repo, db := testRepository(t)
+	key := testKey(t, db)
+	ctx := context.Background()
+
+	if claimed, _, err := repo.ClaimIdempotencyKey(ctx, key, "transaction", "TXN1", time.Now().Add(time.Minute)); err != nil || !claimed {
+		t.Fatalf("first claim: %v, %v", claimed, err)
+	}
+	if err := repo.ReleaseIdempotencyKey(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if claimed, _, err := repo.ClaimIdempotencyKey(ctx, key, "transaction", "TXN1", time.Now().Add(time.Minute)); err != nil || !claimed {
+		t.Fatalf("claim after release: %v, %v", claimed, err)
+	}
+}
+
+func TestExpiredPendingIdempotencyKeyIsTakenOver(t *testing.T) {
+	repo, db := testRepository(t)
+	key := testKey(t, db)
+	ctx := context.Background()
+
+	if claimed, _, err := repo.ClaimIdempotencyKey(ctx, key, "transaction", "TXN1", time.Now().Add(500*time.Millisecond)); err != nil || !claimed {
+		t.Fatalf("first claim: %v, %v", claimed, err)
+	}
+	time.Sleep(time.Second)
+	if claimed, _, err := repo.ClaimIdempotencyKey(ctx, key, "transaction", "TXN1", time.Now().Add(time.Minute)); err != nil || !claimed {
+		t.Fatalf("claim after the holder expired: %v, %v", claimed, err)
+	}
+}
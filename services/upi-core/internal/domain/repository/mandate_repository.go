@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// MandateStatus represents the status of a mandate
+type MandateStatus string
+
+const (
+	MandateActive    MandateStatus = "ACTIVE"
+	MandateRevoked   MandateStatus = "REVOKED"
+	MandateCompleted MandateStatus = "COMPLETED"
+)
+
+// MandateFrequency is how often a mandate is executed
+type MandateFrequency string
+
+const (
+	FrequencyOneTime   MandateFrequency = "ONE_TIME"
+	FrequencyDaily     MandateFrequency = "DAILY"
+	FrequencyWeekly    MandateFrequency = "WEEKLY"
+	FrequencyMonthly   MandateFrequency = "MONTHLY"
+	FrequencyQuarterly MandateFrequency = "QUARTERLY"
+	FrequencyYearly    MandateFrequency = "YEARLY"
+)
+
+// ExecutionStatus represents the status of a mandate execution
+type ExecutionStatus string
+
+const (
+	ExecutionPending ExecutionStatus = "PENDING"
+	ExecutionSuccess ExecutionStatus = "SUCCESS"
+	ExecutionFailed  ExecutionStatus = "FAILED"
+)
+
+// Mandate authorizes a payee to debit the payer on a schedule. Dates are
+// days, held as midnight UTC.
+type Mandate struct {
+	MandateID      string           `db:"mandate_id"`
+	PayerVPA       string           `db:"payer_vpa"`
+	PayeeVPA       string           `db:"payee_vpa"`
+	PayerBankCode  string           `db:"payer_bank_code"`
+	PayeeBankCode  string           `db:"payee_bank_code"`
+	AmountPaisa    int64            `db:"amount_paisa"`     // Debited on each due date
+	MaxAmountPaisa int64            `db:"max_amount_paisa"` // Cap on any one execution
+	Frequency      MandateFrequency `db:"frequency"`
+	StartDate      time.Time        `db:"start_date"`
+	EndDate        *time.Time       `db:"end_date"`
+	NextDueDate    *time.Time       `db:"next_due_date"` // Nil once the mandate is no longer active
+	Status         MandateStatus    `db:"status"`
+	Description    string           `db:"description"`
+	RevokeReason   string           `db:"revoke_reason"`
+	CreatedAt      time.Time        `db:"created_at"`
+	UpdatedAt      time.Time        `db:"updated_at"`
+}
+
+// MandateExecution is the debit of a mandate on one due date
+type MandateExecution struct {
+	MandateID     string          `db:"mandate_id"`
+	DueDate       time.Time       `db:"due_date"`
+	TransactionID string          `db:"transaction_id"`
+	AmountPaisa   int64           `db:"amount_paisa"`
+	Status        ExecutionStatus `db:"status"`
+	ErrorCode     string          `db:"error_code"`
+	ErrorMessage  string          `db:"error_message"`
+	CreatedAt     time.Time       `db:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at"`
+}
+
+// mandateColumns is the column list scanMandate reads
+const mandateColumns = `
+	mandate_id, payer_vpa, payee_vpa, payer_bank_code, payee_bank_code,
+	amount_paisa, max_amount_paisa, frequency, start_date, end_date,
+	next_due_date, status, COALESCE(description, ''), COALESCE(revoke_reason, ''),
+	created_at, updated_at
+`
+
+// scanMandate reads a row selected with mandateColumns
+func scanMandate(row interface{ Scan(...interface{}) error }) (*Mandate, error) {
+	var m Mandate
+	err := row.Scan(
+		&m.MandateID,
+		&m.PayerVPA,
+		&m.PayeeVPA,
+		&m.PayerBankCode,
+		&m.PayeeBankCode,
+		&m.AmountPaisa,
+		&m.MaxAmountPaisa,
+		&m.Frequency,
+		&m.StartDate,
+		&m.EndDate,
+		&m.NextDueDate,
+		&m.Status,
+		&m.Description,
+		&m.RevokeReason,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// executionColumns is the column list scanExecution reads
+const executionColumns = `
+	mandate_id, due_date, transaction_id, amount_paisa, status,
+	COALESCE(error_code, ''), COALESCE(error_message, ''), created_at, updated_at
+`
+
+// scanExecution reads a row selected with executionColumns
+func scanExecution(row interface{ Scan(...interface{}) error }) (*MandateExecution, error) {
+	var e MandateExecution
+	err := row.Scan(
+		&e.MandateID,
+		&e.DueDate,
+		&e.TransactionID,
+		&e.AmountPaisa,
+		&e.Status,
+		&e.ErrorCode,
+		&e.ErrorMessage,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ErrMandateExists is returned by CreateMandate when the mandate ID is
+// taken
+var ErrMandateExists = errors.New("mandate already exists")
+
+// CreateMandate stores a new mandate. Its timestamps are set from the
+// stored row.
+func (r *PostgreSQLTransactionRepository) CreateMandate(ctx context.Context, tx *sql.Tx, mandate *Mandate) error {
+	query := `
+		INSERT INTO mandates (
+			mandate_id, payer_vpa, payee_vpa, payer_bank_code, payee_bank_code,
+			amount_paisa, max_amount_paisa, frequency, start_date, end_date,
+			next_due_date, status, description
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''))
+		ON CONFLICT (mandate_id) DO NOTHING
+		RETURNING created_at, updated_at
+	`
+
+	err := r.conn(tx).QueryRowContext(ctx, query,
+		mandate.MandateID,
+		mandate.PayerVPA,
+		mandate.PayeeVPA,
+		mandate.PayerBankCode,
+		mandate.PayeeBankCode,
+		mandate.AmountPaisa,
+		mandate.MaxAmountPaisa,
+		mandate.Frequency,
+		mandate.StartDate,
+		mandate.EndDate,
+		mandate.NextDueDate,
+		mandate.Status,
+		mandate.Description,
+	).Scan(&mandate.CreatedAt, &mandate.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMandateExists
+	}
+	return err
+}
+
+// GetMandate retrieves a mandate by its ID
+func (r *PostgreSQLTransactionRepository) GetMandate(ctx context.Context, mandateID string) (*Mandate, error) {
+	query := `SELECT ` + mandateColumns + ` FROM mandates WHERE mandate_id = $1`
+
+	return scanMandate(r.db.QueryRowContext(ctx, query, mandateID))
+}
+
+// LockMandate reads a mandate and locks it until tx ends, so changes to
+// it and its executions are made one at a time
+func (r *PostgreSQLTransactionRepository) LockMandate(ctx context.Context, tx *sql.Tx, mandateID string) (*Mandate, error) {
+	query := `SELECT ` + mandateColumns + ` FROM mandates WHERE mandate_id = $1 FOR UPDATE`
+
+	return scanMandate(tx.QueryRowContext(ctx, query, mandateID))
+}
+
+// UpdateMandate stores a mandate's amounts, end date, schedule and status
+func (r *PostgreSQLTransactionRepository) UpdateMandate(ctx context.Context, tx *sql.Tx, mandate *Mandate) error {
+	query := `
+		UPDATE mandates SET
+			amount_paisa = $2,
+			max_amount_paisa = $3,
+			end_date = $4,
+			next_due_date = $5,
+			status = $6,
+			revoke_reason = NULLIF($7, ''),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE mandate_id = $1
+	`
+
+	result, err := r.conn(tx).ExecContext(ctx, query,
+		mandate.MandateID,
+		mandate.AmountPaisa,
+		mandate.MaxAmountPaisa,
+		mandate.EndDate,
+		mandate.NextDueDate,
+		mandate.Status,
+		mandate.RevokeReason,
+	)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// ListDueMandates returns the IDs of up to limit active mandates due on or
+// before asOf, earliest due first
+func (r *PostgreSQLTransactionRepository) ListDueMandates(ctx context.Context, asOf time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT mandate_id FROM mandates
+		WHERE status = 'ACTIVE' AND next_due_date <= $1
+		ORDER BY next_due_date
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, asOf, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CreateMandateExecution records a PENDING execution. It reports false,
+// recording nothing, if the mandate already has one for the due date.
+func (r *PostgreSQLTransactionRepository) CreateMandateExecution(ctx context.Context, tx *sql.Tx, execution *MandateExecution) (bool, error) {
+	query := `
+		INSERT INTO mandate_executions (mandate_id, due_date, transaction_id, amount_paisa, status)
+		VALUES ($1, $2, $3, $4, 'PENDING')
+		ON CONFLICT (mandate_id, due_date) DO NOTHING
+		RETURNING created_at, updated_at
+	`
+
+	err := r.conn(tx).QueryRowContext(ctx, query,
+		execution.MandateID,
+		execution.DueDate,
+		execution.TransactionID,
+		execution.AmountPaisa,
+	).Scan(&execution.CreatedAt, &execution.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	execution.Status = ExecutionPending
+	return true, nil
+}
+
+// FinishMandateExecution records the outcome of a PENDING execution
+func (r *PostgreSQLTransactionRepository) FinishMandateExecution(ctx context.Context, mandateID string, dueDate time.Time, status ExecutionStatus, errorCode, errorMessage string) error {
+	query := `
+		UPDATE mandate_executions SET
+			status = $3,
+			error_code = NULLIF($4, ''),
+			error_message = NULLIF($5, ''),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE mandate_id = $1 AND due_date = $2 AND status = 'PENDING'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, mandateID, dueDate, status, errorCode, errorMessage)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// ListPendingMandateExecutions returns up to limit executions left PENDING
+// since before, e.g. because the instance making them crashed
+func (r *PostgreSQLTransactionRepository) ListPendingMandateExecutions(ctx context.Context, before time.Time, limit int) ([]*MandateExecution, error) {
+	query := `
+		SELECT ` + executionColumns + ` FROM mandate_executions
+		WHERE status = 'PENDING' AND updated_at < $1
+		ORDER BY updated_at
+		LIMIT $2
+	`
+
+	return r.queryExecutions(ctx, query, before, limit)
+}
+
+// ListMandateExecutions returns a mandate's latest executions, latest first
+func (r *PostgreSQLTransactionRepository) ListMandateExecutions(ctx context.Context, mandateID string, limit int) ([]*MandateExecution, error) {
+	query := `
+		SELECT ` + executionColumns + ` FROM mandate_executions
+		WHERE mandate_id = $1
+		ORDER BY due_date DESC
+		LIMIT $2
+	`
+
+	return r.queryExecutions(ctx, query, mandateID, limit)
+}
+
+func (r *PostgreSQLTransactionRepository) queryExecutions(ctx context.Context, query string, args ...interface{}) ([]*MandateExecution, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var executions []*MandateExecution
+	for rows.Next() {
+		execution, err := scanExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		executions = append(executions, execution)
+	}
+	return executions, rows.Err()
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMandateExecutionsAreRecordedOnce(t *testing.T) {
+	repo, db := testRepository(t)
+	ctx := context.Background()
+	mandateID := fmt.Sprintf("TEST%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM mandate_executions WHERE mandate_id = $1`, mandateID)
+		db.Exec(`DELETE FROM mandates WHERE mandate_id = $1`, mandateID)
+	})
+
+	due := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	mandate := &Mandate{
+		MandateID:      mandateID,
+		PayerVPA:       "alice@hdfc",
+		PayeeVPA:       "bob@sbi",
+		PayerBankCode:  "HDFC",
+		PayeeBankCode:  "SBI",
+		AmountPaisa:    100,
+		MaxAmountPaisa: 200,
+		Frequency:      FrequencyMonthly,
+		StartDate:      due,
+		NextDueDate:    &due,
+		Status:         MandateActive,
+	}
+	if err := repo.CreateMandate(ctx, nil, mandate); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateMandate(ctx, nil, mandate); !errors.Is(err, ErrMandateExists) {
+		t.Errorf("duplicate mandate: err = %v", err)
+	}
+
+	ids, err := repo.ListDueMandates(ctx, due, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, id := range ids {
+		found = found || id == mandateID
+	}
+	if !found {
+		t.Errorf("due mandate not listed")
+	}
+
+	execution := &MandateExecution{MandateID: mandateID, DueDate: due, TransactionID: mandateID + "-20240510", AmountPaisa: 100}
+	for i, want := range []bool{true, false} {
+		created, err := repo.CreateMandateExecution(ctx, nil, execution)
+		if err != nil || created != want {
+			t.Fatalf("CreateMandateExecution %d = %v, %v; want %v", i+1, created, err, want)
+		}
+	}
+
+	if err := repo.FinishMandateExecution(ctx, mandateID, due, ExecutionSuccess, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	executions, err := repo.ListMandateExecutions(ctx, mandateID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(executions) != 1 || executions[0].Status != ExecutionSuccess || !executions[0].DueDate.Equal(due) {
+		t.Errorf("executions = %+v", executions)
+	}
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// ReconStatus is the state of a reconciliation run
+type ReconStatus string
+
+const (
+	ReconRunning   ReconStatus = "RUNNING"
+	ReconCompleted ReconStatus = "COMPLETED"
+	ReconFailed    ReconStatus = "FAILED"
+)
+
+// ReconExceptionType is how a bank's record of a transaction disagrees
+// with the switch's; see migrations/011_reconciliation.sql
+type ReconExceptionType string
+
+const (
+	ExceptionMissingDebit   ReconExceptionType = "MISSING_DEBIT"
+	ExceptionMissingCredit  ReconExceptionType = "MISSING_CREDIT"
+	ExceptionOrphanDebit    ReconExceptionType = "ORPHAN_DEBIT"
+	ExceptionAmountMismatch ReconExceptionType = "AMOUNT_MISMATCH"
+)
+
+// ReconRun is the reconciliation of one business day's transactions
+type ReconRun struct {
+	BusinessDate           time.Time   `db:"business_date"`
+	Status                 ReconStatus `db:"status"`
+	TransactionsChecked    int         `db:"transactions_checked"`
+	TransactionsUnverified int         `db:"transactions_unverified"`
+	ExceptionsFound        int         `db:"exceptions_found"`
+	ErrorMessage           string      `db:"error_message"`
+	StartedAt              time.Time   `db:"started_at"`
+	CompletedAt            *time.Time  `db:"completed_at"`
+}
+
+// ReconException is a transaction a bank's record disagrees with
+type ReconException struct {
+	ExceptionID       int64              `db:"exception_id"`
+	BusinessDate      time.Time          `db:"business_date"`
+	TransactionID     string             `db:"transaction_id"`
+	Type              ReconExceptionType `db:"exception_type"`
+	BankCode          string             `db:"bank_code"`
+	SwitchStatus      TransactionStatus  `db:"switch_status"`
+	BankStatus        string             `db:"bank_status"` // NOT_FOUND if the bank has no record
+	SwitchAmountPaisa int64              `db:"switch_amount_paisa"`
+	BankAmountPaisa   int64              `db:"bank_amount_paisa"`
+	Details           string             `db:"details"`
+	DetectedAt        time.Time          `db:"detected_at"`
+}
+
+// ClaimReconRun starts the run of a business day, clearing the exceptions
+// of any earlier run of it. It reports false if another run of the day is
+// in progress, i.e. RUNNING and started after staleBefore.
+func (r *PostgreSQLTransactionRepository) ClaimReconRun(ctx context.Context, businessDate time.Time, staleBefore time.Time) (bool, error) {
+	tx, err := r.BeginTransaction(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO recon_runs (business_date, status) VALUES ($1, 'RUNNING')
+		ON CONFLICT (business_date) DO UPDATE SET
+			status = 'RUNNING',
+			transactions_checked = 0,
+			transactions_unverified = 0,
+			exceptions_found = 0,
+			error_message = NULL,
+			started_at = CURRENT_TIMESTAMP,
+			completed_at = NULL
+		WHERE recon_runs.status <> 'RUNNING' OR recon_runs.started_at < $2
+	`
+	result, err := tx.ExecContext(ctx, query, businessDate, staleBefore)
+	if err != nil {
+		return false, err
+	}
+	if claimed, err := result.RowsAffected(); err != nil || claimed == 0 {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recon_exceptions WHERE business_date = $1`, businessDate); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// FinishReconRun records the outcome and counts of a run. Its start and
+// completion times are set from the stored row.
+func (r *PostgreSQLTransactionRepository) FinishReconRun(ctx context.Context, run *ReconRun) error {
+	query := `
+		UPDATE recon_runs SET
+			status = $2,
+			transactions_checked = $3,
+			transactions_unverified = $4,
+			exceptions_found = $5,
+			error_message = NULLIF($6, ''),
+			completed_at = CURRENT_TIMESTAMP
+		WHERE business_date = $1
+		RETURNING started_at, completed_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		run.BusinessDate,
+		run.Status,
+		run.TransactionsChecked,
+		run.TransactionsUnverified,
+		run.ExceptionsFound,
+		run.ErrorMessage,
+	).Scan(&run.StartedAt, &run.CompletedAt)
+}
+
+// GetReconRun retrieves the latest run of a business day
+func (r *PostgreSQLTransactionRepository) GetReconRun(ctx context.Context, businessDate time.Time) (*ReconRun, error) {
+	query := `
+		SELECT business_date, status, transactions_checked, transactions_unverified,
+			exceptions_found, COALESCE(error_message, ''), started_at, completed_at
+		FROM recon_runs WHERE business_date = $1
+	`
+
+	var run ReconRun
+	err := r.db.QueryRowContext(ctx, query, businessDate).Scan(
+		&run.BusinessDate,
+		&run.Status,
+		&run.TransactionsChecked,
+		&run.TransactionsUnverified,
+		&run.ExceptionsFound,
+		&run.ErrorMessage,
+		&run.StartedAt,
+		&run.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// CreateReconException records an exception found by a run. Recording the
+// same exception twice is not an error.
+func (r *PostgreSQLTransactionRepository) CreateReconException(ctx context.Context, exception *ReconException) error {
+	query := `
+		INSERT INTO recon_exceptions (
+			business_date, transaction_id, exception_type, bank_code, switch_status,
+			bank_status, switch_amount_paisa, bank_amount_paisa, details
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''))
+		ON CONFLICT (business_date, transaction_id, exception_type, bank_code) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		exception.BusinessDate,
+		exception.TransactionID,
+		exception.Type,
+		exception.BankCode,
+		exception.SwitchStatus,
+		exception.BankStatus,
+		exception.SwitchAmountPaisa,
+		exception.BankAmountPaisa,
+		exception.Details,
+	)
+	return err
+}
+
+// ListReconExceptions returns the exceptions found for a business day, in
+// the order they were found
+func (r *PostgreSQLTransactionRepository) ListReconExceptions(ctx context.Context, businessDate time.Time) ([]*ReconException, error) {
+	query := `
+		SELECT exception_id, business_date, transaction_id, exception_type, bank_code,
+			switch_status, bank_status, switch_amount_paisa, bank_amount_paisa,
+			COALESCE(details, ''), detected_at
+		FROM recon_exceptions WHERE business_date = $1
+		ORDER BY exception_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, businessDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exceptions []*ReconException
+	for rows.Next() {
+		var exception ReconException
+		err := rows.Scan(
+			&exception.ExceptionID,
+			&exception.BusinessDate,
+			&exception.TransactionID,
+			&exception.Type,
+			&exception.BankCode,
+			&exception.SwitchStatus,
+			&exception.BankStatus,
+			&exception.SwitchAmountPaisa,
+			&exception.BankAmountPaisa,
+			&exception.Details,
+			&exception.DetectedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, &exception)
+	}
+	return exceptions, rows.Err()
+}
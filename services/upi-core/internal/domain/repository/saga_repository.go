@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// SagaStep is how far a transaction's saga has got
+type SagaStep string
+
+const (
+	SagaStarted       SagaStep = "STARTED"       // No bank called yet
+	SagaDebitPending  SagaStep = "DEBIT_PENDING" // Debit sent, outcome unknown
+	SagaDebitDone     SagaStep = "DEBIT_DONE"
+	SagaCreditPending SagaStep = "CREDIT_PENDING" // Credit sent, outcome unknown
+	SagaCreditDone    SagaStep = "CREDIT_DONE"
+	SagaCompensating  SagaStep = "COMPENSATING" // Credit failed, debit to reverse
+	SagaCompleted     SagaStep = "COMPLETED"
+	SagaCompensated   SagaStep = "COMPENSATED"
+	SagaFailed        SagaStep = "FAILED"
+)
+
+// SagaState records the progress of a transaction's saga
+type SagaState struct {
+	TransactionID string    `db:"transaction_id"`
+	Step          SagaStep  `db:"step"`
+	LastError     string    `db:"last_error"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// CreateSagaState records the saga of a new transaction as STARTED
+func (r *PostgreSQLTransactionRepository) CreateSagaState(ctx context.Context, tx *sql.Tx, transactionID string) error {
+	query := `INSERT INTO saga_state (transaction_id, step) VALUES ($1, 'STARTED')`
+
+	_, err := r.conn(tx).ExecContext(ctx, query, transactionID)
+	return err
+}
+
+// UpdateSagaStep records the step a transaction's saga has reached. A
+// transaction created before sagas were recorded has no state to update,
+// which is not an error.
+func (r *PostgreSQLTransactionRepository) UpdateSagaStep(ctx context.Context, tx *sql.Tx, transactionID string, step SagaStep, lastError string) error {
+	query := `
+		UPDATE saga_state SET step = $2, last_error = NULLIF($3, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE transaction_id = $1
+	`
+
+	_, err := r.conn(tx).ExecContext(ctx, query, transactionID, step, lastError)
+	return err
+}
+
+// ListStaleSagas returns the IDs of up to limit PENDING transactions whose
+// saga is in flight and was last updated before the given time, oldest
+// first
+func (r *PostgreSQLTransactionRepository) ListStaleSagas(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT s.transaction_id FROM saga_state s
+		JOIN transactions t ON t.transaction_id = s.transaction_id
+		WHERE s.step NOT IN ('COMPLETED', 'COMPENSATED', 'FAILED')
+		  AND s.updated_at <= $1 AND t.status = 'PENDING'
+		ORDER BY s.updated_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ClaimSaga takes over a saga in flight that was last updated before the
+// given time, refreshing its updated_at so no one else claims it meanwhile.
+// It returns sql.ErrNoRows if the saga has since moved on or been claimed.
+func (r *PostgreSQLTransactionRepository) ClaimSaga(ctx context.Context, transactionID string, before time.Time) (*SagaState, error) {
+	query := `
+		UPDATE saga_state SET updated_at = CURRENT_TIMESTAMP
+		WHERE transaction_id = $1 AND updated_at <= $2
+		  AND step NOT IN ('COMPLETED', 'COMPENSATED', 'FAILED')
+		RETURNING transaction_id, step, COALESCE(last_error, ''), created_at, updated_at
+	`
+
+	var state SagaState
+	err := r.db.QueryRowContext(ctx, query, transactionID, before).Scan(
+		&state.TransactionID,
+		&state.Step,
+		&state.LastError,
+		&state.CreatedAt,
+		&state.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
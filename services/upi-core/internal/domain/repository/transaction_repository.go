@@ -3,7 +3,15 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // TransactionStatus represents the status of a transaction
@@ -30,32 +38,37 @@ const (
 
 // Transaction represents a UPI transaction
 type Transaction struct {
-	ID             string            `db:"id"`
-	TransactionID  string            `db:"transaction_id"`
-	RRN            string            `db:"rrn"`
-	PayerVPA       string            `db:"payer_vpa"`
-	PayeeVPA       string            `db:"payee_vpa"`
-	AmountPaisa    int64             `db:"amount_paisa"`
-	Currency       string            `db:"currency"`
-	Type           TransactionType   `db:"transaction_type"`
-	Status         TransactionStatus `db:"status"`
-	Description    string            `db:"description"`
-	Reference      string            `db:"reference"`
-	PayerBankCode  string            `db:"payer_bank_code"`
-	PayeeBankCode  string            `db:"payee_bank_code"`
-	SwitchFeePaisa int64             `db:"switch_fee_paisa"`
-	BankFeePaisa   int64             `db:"bank_fee_paisa"`
-	TotalFeePaisa  int64             `db:"total_fee_paisa"`
-	SettlementID   string            `db:"settlement_id"`
-	ErrorCode      string            `db:"error_code"`
-	ErrorMessage   string            `db:"error_message"`
-	Signature      string            `db:"signature"`
-	Metadata       map[string]string `db:"metadata"`
-	InitiatedAt    time.Time         `db:"initiated_at"`
-	ProcessedAt    *time.Time        `db:"processed_at"`
-	ExpiresAt      *time.Time        `db:"expires_at"`
-	CreatedAt      time.Time         `db:"created_at"`
-	UpdatedAt      time.Time         `db:"updated_at"`
+	ID                 string            `db:"id"`
+	TransactionID      string            `db:"transaction_id"`
+	RRN                string            `db:"rrn"`
+	PayerVPA           string            `db:"payer_vpa"`
+	PayeeVPA           string            `db:"payee_vpa"`
+	AmountPaisa        int64             `db:"amount_paisa"`
+	Currency           string            `db:"currency"`
+	SettledAmountPaisa int64             `db:"settled_amount_paisa"` // AmountPaisa in SettledCurrency, which banks are debited and credited
+	SettledCurrency    string            `db:"settled_currency"`
+	FXRate             string            `db:"fx_rate"` // Rate AmountPaisa was converted at; empty if it was not
+	Type               TransactionType   `db:"transaction_type"`
+	Status             TransactionStatus `db:"status"`
+	Description        string            `db:"description"`
+	Reference          string            `db:"reference"`
+	PayerBankCode      string            `db:"payer_bank_code"`
+	PayeeBankCode      string            `db:"payee_bank_code"`
+	SwitchFeePaisa     int64             `db:"switch_fee_paisa"`
+	BankFeePaisa       int64             `db:"bank_fee_paisa"`
+	FXFeePaisa         int64             `db:"fx_fee_paisa"`
+	TotalFeePaisa      int64             `db:"total_fee_paisa"`
+	SettlementID       string            `db:"settlement_id"`
+	ErrorCode          string            `db:"error_code"`
+	ErrorMessage       string            `db:"error_message"`
+	Signature          string            `db:"signature"`
+	Metadata           map[string]string `db:"metadata"`
+	InitiatedAt        time.Time         `db:"initiated_at"`
+	ProcessedAt        *time.Time        `db:"processed_at"`
+	ExpiresAt          *time.Time        `db:"expires_at"`
+	DebitReference     string            `db:"debit_reference"` // Payer bank's reference once the debit succeeded
+	CreatedAt          time.Time         `db:"created_at"`
+	UpdatedAt          time.Time         `db:"updated_at"`
 }
 
 // VPAMapping represents a Virtual Payment Address mapping
@@ -84,8 +97,18 @@ type Bank struct {
 	SuccessRate       int        `db:"success_rate"`
 	AvgResponseTimeMS int        `db:"avg_response_time_ms"`
 	Features          []string   `db:"features"`
-	CreatedAt         time.Time  `db:"created_at"`
-	UpdatedAt         time.Time  `db:"updated_at"`
+	VPAHandles        []string   `db:"vpa_handles"` // PSP parts of the VPAs the bank may register
+	CircuitState      string     `db:"circuit_state"`
+	CallbackURL       string     `db:"callback_url"` // Where collect requests to the bank's customers are delivered
+	StatusReason      string     `db:"status_reason"`
+	RegisteredBy      string     `db:"registered_by"` // Operator who registered the bank
+	ApprovedBy        string     `db:"approved_by"`
+	ApprovedAt        *time.Time `db:"approved_at"`
+	// MaintenanceWindowID is the window the bank is in MAINTENANCE for,
+	// nil if it is not or was put there by hand
+	MaintenanceWindowID *int64    `db:"maintenance_window_id"`
+	CreatedAt           time.Time `db:"created_at"`
+	UpdatedAt           time.Time `db:"updated_at"`
 }
 
 // TransactionRepository defines the interface for transaction operations
@@ -102,6 +125,10 @@ type TransactionRepository interface {
 	UpdateTransactionStatus(ctx context.Context, tx *sql.Tx, transactionID string, status TransactionStatus, reason string, errorCode string, errorMessage string) error
 	ListTransactionsByStatus(ctx context.Context, status TransactionStatus, limit int) ([]*Transaction, error)
 	ListTransactionsByVPA(ctx context.Context, vpa string, limit int) ([]*Transaction, error)
+	ListTransactions(ctx context.Context, filter TransactionFilter) ([]*Transaction, error)
+	RecordDebit(ctx context.Context, tx *sql.Tx, transactionID string, bankReferenceID string) error
+	ListExpiredTransactions(ctx context.Context, before time.Time, limit int) ([]string, error)
+	LockPendingTransaction(ctx context.Context, tx *sql.Tx, transactionID string) (*Transaction, error)
 
 	// VPA operations
 	GetVPAMapping(ctx context.Context, vpa string) (*VPAMapping, error)
@@ -111,13 +138,65 @@ type TransactionRepository interface {
 
 	// Bank operations
 	GetBankByCode(ctx context.Context, bankCode string) (*Bank, error)
+	UpsertBank(ctx context.Context, tx *sql.Tx, bank *Bank) error
 	ListActiveBanks(ctx context.Context) ([]*Bank, error)
 	UpdateBankStatus(ctx context.Context, tx *sql.Tx, bankCode string, status string) error
 	UpdateBankHealth(ctx context.Context, tx *sql.Tx, bankCode string, successRate int, avgResponseTime int) error
+	UpdateBankCircuitState(ctx context.Context, bankCode string, state string) error
+	CreateBank(ctx context.Context, tx *sql.Tx, bank *Bank) error
+	LockBank(ctx context.Context, tx *sql.Tx, bankCode string) (*Bank, error)
+	UpdateBankLifecycle(ctx context.Context, tx *sql.Tx, bank *Bank) error
+	ListBanks(ctx context.Context, filter BankFilter) ([]*Bank, error)
+	CreateMaintenanceWindow(ctx context.Context, tx *sql.Tx, window *MaintenanceWindow) error
+	GetMaintenanceWindow(ctx context.Context, windowID int64) (*MaintenanceWindow, error)
+	CancelMaintenanceWindow(ctx context.Context, tx *sql.Tx, windowID int64) error
+	ListMaintenanceWindows(ctx context.Context, bankCode string, endsAfter time.Time) ([]*MaintenanceWindow, error)
+	ListStartedMaintenanceWindows(ctx context.Context, now time.Time) ([]*MaintenanceWindow, error)
+	ListBanksLeavingMaintenance(ctx context.Context, now time.Time) ([]string, error)
+
+	// Mandate operations
+	CreateMandate(ctx context.Context, tx *sql.Tx, mandate *Mandate) error
+	GetMandate(ctx context.Context, mandateID string) (*Mandate, error)
+	LockMandate(ctx context.Context, tx *sql.Tx, mandateID string) (*Mandate, error)
+	UpdateMandate(ctx context.Context, tx *sql.Tx, mandate *Mandate) error
+	ListDueMandates(ctx context.Context, asOf time.Time, limit int) ([]string, error)
+	CreateMandateExecution(ctx context.Context, tx *sql.Tx, execution *MandateExecution) (bool, error)
+	FinishMandateExecution(ctx context.Context, mandateID string, dueDate time.Time, status ExecutionStatus, errorCode, errorMessage string) error
+	ListPendingMandateExecutions(ctx context.Context, before time.Time, limit int) ([]*MandateExecution, error)
+	ListMandateExecutions(ctx context.Context, mandateID string, limit int) ([]*MandateExecution, error)
+
+	// Collect request operations
+	CreateCollectRequest(ctx context.Context, tx *sql.Tx, request *CollectRequest) error
+	GetCollectRequest(ctx context.Context, collectID string) (*CollectRequest, error)
+	LockCollectRequest(ctx context.Context, tx *sql.Tx, collectID string) (*CollectRequest, error)
+	FinishCollectRequest(ctx context.Context, tx *sql.Tx, collectID string, status CollectStatus, errorCode, errorMessage string) error
+	MarkCollectRequestDelivered(ctx context.Context, collectID string) error
+	ExpireCollectRequests(ctx context.Context, before time.Time) (int64, error)
+
+	// Saga operations
+	CreateSagaState(ctx context.Context, tx *sql.Tx, transactionID string) error
+	UpdateSagaStep(ctx context.Context, tx *sql.Tx, transactionID string, step SagaStep, lastError string) error
+	ListStaleSagas(ctx context.Context, before time.Time, limit int) ([]string, error)
+	ClaimSaga(ctx context.Context, transactionID string, before time.Time) (*SagaState, error)
+
+	// Fee rule operations
+	CreateFeeRule(ctx context.Context, rule *FeeRule) error
+	UpdateFeeRule(ctx context.Context, rule *FeeRule) error
+	DeleteFeeRule(ctx context.Context, ruleID int64) error
+	GetFeeRule(ctx context.Context, ruleID int64) (*FeeRule, error)
+	ListFeeRules(ctx context.Context) ([]*FeeRule, error)
+
+	// Reconciliation operations
+	ClaimReconRun(ctx context.Context, businessDate time.Time, staleBefore time.Time) (bool, error)
+	FinishReconRun(ctx context.Context, run *ReconRun) error
+	GetReconRun(ctx context.Context, businessDate time.Time) (*ReconRun, error)
+	CreateReconException(ctx context.Context, exception *ReconException) error
+	ListReconExceptions(ctx context.Context, businessDate time.Time) ([]*ReconException, error)
 
 	// Idempotency operations
-	CheckIdempotencyKey(ctx context.Context, keyHash string) (bool, string, error)
-	StoreIdempotencyKey(ctx context.Context, tx *sql.Tx, keyHash string, entityType string, entityID string, responseData []byte, expiresAt time.Time) error
+	ClaimIdempotencyKey(ctx context.Context, keyHash string, entityType string, entityID string, expiresAt time.Time) (bool, string, error)
+	CompleteIdempotencyKey(ctx context.Context, tx *sql.Tx, keyHash string, responseData []byte, expiresAt time.Time) error
+	ReleaseIdempotencyKey(ctx context.Context, keyHash string) error
 
 	// Audit operations
 	LogAudit(ctx context.Context, tx *sql.Tx, entityType string, entityID string, action string, actor string, oldValues map[string]interface{}, newValues map[string]interface{}, correlationID string) error
@@ -129,7 +208,17 @@ type TransactionRepository interface {
 
 // PostgreSQLTransactionRepository implements TransactionRepository for PostgreSQL
 type PostgreSQLTransactionRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	replicas ReadReplicas // nil when every read goes to db
+}
+
+// ReadReplicas pick the read replica a read that may lag behind writes is
+// sent to
+type ReadReplicas interface {
+	// Reader returns a healthy replica, or nil if there is none
+	Reader() *sql.DB
+	// Failed reports a replica a read failed on
+	Failed(replica *sql.DB)
 }
 
 // NewPostgreSQLTransactionRepository creates a new PostgreSQL transaction repository
@@ -139,6 +228,45 @@ func NewPostgreSQLTransactionRepository(db *sql.DB) TransactionRepository {
 	}
 }
 
+// NewPostgreSQLTransactionRepositoryWithReplicas creates a PostgreSQL
+// transaction repository that sends lookups of transactions and VPAs to
+// read replicas, and to db while none is healthy or for contexts made by
+// ReadPrimary
+func NewPostgreSQLTransactionRepositoryWithReplicas(db *sql.DB, replicas ReadReplicas) TransactionRepository {
+	return &PostgreSQLTransactionRepository{
+		db:       db,
+		replicas: replicas,
+	}
+}
+
+type readPrimaryKey struct{}
+
+// ReadPrimary returns a context whose reads go to the primary, for callers
+// that act on what they read and so cannot take a replica lagging behind
+// writes
+func ReadPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readPrimaryKey{}, true)
+}
+
+// read runs a lookup on a read replica when there is a healthy one. A
+// lookup failing on the replica for any reason but a missing row is run
+// again on the primary.
+func (r *PostgreSQLTransactionRepository) read(ctx context.Context, lookup func(db *sql.DB) error) error {
+	if r.replicas == nil || ctx.Value(readPrimaryKey{}) != nil {
+		return lookup(r.db)
+	}
+	replica := r.replicas.Reader()
+	if replica == nil {
+		return lookup(r.db)
+	}
+	err := lookup(replica)
+	if err == nil || errors.Is(err, sql.ErrNoRows) || ctx.Err() != nil {
+		return err
+	}
+	r.replicas.Failed(replica)
+	return lookup(r.db)
+}
+
 // BeginTransaction starts a new database transaction
 func (r *PostgreSQLTransactionRepository) BeginTransaction(ctx context.Context) (*sql.Tx, error) {
 	return r.db.BeginTx(ctx, &sql.TxOptions{
@@ -163,12 +291,22 @@ func (r *PostgreSQLTransactionRepository) CreateTransaction(ctx context.Context,
 			transaction_id, rrn, payer_vpa, payee_vpa, amount_paisa, currency,
 			transaction_type, status, description, reference, payer_bank_code, payee_bank_code,
 			switch_fee_paisa, bank_fee_paisa, total_fee_paisa, signature, metadata,
-			initiated_at, expires_at
+			initiated_at, expires_at, settled_amount_paisa, settled_currency, fx_rate, fx_fee_paisa
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+			$1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
+			$20, $21, NULLIF($22, '')::NUMERIC, $23
 		)
 	`
 
+	var metadata interface{}
+	if transaction.Metadata != nil {
+		data, err := json.Marshal(transaction.Metadata)
+		if err != nil {
+			return err
+		}
+		metadata = string(data)
+	}
+
 	_, err := tx.ExecContext(ctx, query,
 		transaction.TransactionID,
 		transaction.RRN,
@@ -186,27 +324,33 @@ func (r *PostgreSQLTransactionRepository) CreateTransaction(ctx context.Context,
 		transaction.BankFeePaisa,
 		transaction.TotalFeePaisa,
 		transaction.Signature,
-		transaction.Metadata,
+		metadata,
 		transaction.InitiatedAt,
 		transaction.ExpiresAt,
+		transaction.SettledAmountPaisa,
+		transaction.SettledCurrency,
+		transaction.FXRate,
+		transaction.FXFeePaisa,
 	)
 
 	return err
 }
 
-// GetTransactionByID retrieves a transaction by its ID
-func (r *PostgreSQLTransactionRepository) GetTransactionByID(ctx context.Context, transactionID string) (*Transaction, error) {
-	query := `
-		SELECT id, transaction_id, rrn, payer_vpa, payee_vpa, amount_paisa, currency,
-			   transaction_type, status, description, reference, payer_bank_code, payee_bank_code,
-			   switch_fee_paisa, bank_fee_paisa, total_fee_paisa, settlement_id, error_code, error_message,
-			   signature, metadata, initiated_at, processed_at, expires_at, created_at, updated_at
-		FROM transactions
-		WHERE transaction_id = $1
-	`
-
+// transactionColumns is the column list scanTransaction reads
+const transactionColumns = `
+	id, transaction_id, COALESCE(rrn, ''), payer_vpa, payee_vpa, amount_paisa, COALESCE(currency, 'INR'),
+	transaction_type, status, COALESCE(description, ''), COALESCE(reference, ''), payer_bank_code, payee_bank_code,
+	switch_fee_paisa, bank_fee_paisa, total_fee_paisa, COALESCE(settlement_id, ''), COALESCE(error_code, ''),
+	COALESCE(error_message, ''), COALESCE(signature, ''), metadata, initiated_at, processed_at, expires_at,
+	COALESCE(debit_reference, ''), created_at, updated_at, settled_amount_paisa, settled_currency,
+	COALESCE(trim_scale(fx_rate)::TEXT, ''), fx_fee_paisa
+`
+
+// scanTransaction reads a row selected with transactionColumns
+func scanTransaction(row interface{ Scan(...interface{}) error }) (*Transaction, error) {
 	var transaction Transaction
-	err := r.db.QueryRowContext(ctx, query, transactionID).Scan(
+	var metadata []byte
+	err := row.Scan(
 		&transaction.ID,
 		&transaction.TransactionID,
 		&transaction.RRN,
@@ -227,19 +371,165 @@ func (r *PostgreSQLTransactionRepository) GetTransactionByID(ctx context.Context
 		&transaction.ErrorCode,
 		&transaction.ErrorMessage,
 		&transaction.Signature,
-		&transaction.Metadata,
+		&metadata,
 		&transaction.InitiatedAt,
 		&transaction.ProcessedAt,
 		&transaction.ExpiresAt,
+		&transaction.DebitReference,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
+		&transaction.SettledAmountPaisa,
+		&transaction.SettledCurrency,
+		&transaction.FXRate,
+		&transaction.FXFeePaisa,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if metadata != nil {
+		if err := json.Unmarshal(metadata, &transaction.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return &transaction, nil
+}
+
+// GetTransactionByID retrieves a transaction by its ID
+func (r *PostgreSQLTransactionRepository) GetTransactionByID(ctx context.Context, transactionID string) (*Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE transaction_id = $1`
+
+	var transaction *Transaction
+	err := r.read(ctx, func(db *sql.DB) (err error) {
+		transaction, err = scanTransaction(db.QueryRowContext(ctx, query, transactionID))
+		return err
+	})
+	return transaction, err
+}
+
+// GetTransactionByRRN retrieves a transaction by its retrieval reference
+// number
+func (r *PostgreSQLTransactionRepository) GetTransactionByRRN(ctx context.Context, rrn string) (*Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE rrn = $1`
 
+	var transaction *Transaction
+	err := r.read(ctx, func(db *sql.DB) (err error) {
+		transaction, err = scanTransaction(db.QueryRowContext(ctx, query, rrn))
+		return err
+	})
+	return transaction, err
+}
+
+// TransactionCursor is the position of the last transaction of a page
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Token makes an opaque page token of the cursor
+func (c *TransactionCursor) Token() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+// ParseTransactionCursor reads a cursor back from its Token
+func ParseTransactionCursor(token string) (*TransactionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
 	if err != nil {
 		return nil, err
 	}
+	createdAt, id, ok := strings.Cut(string(data), "|")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("malformed cursor %q", data)
+	}
+	cursor := &TransactionCursor{ID: id}
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
 
-	return &transaction, nil
+// TransactionFilter selects the transactions ListTransactions returns. Zero
+// fields match every transaction.
+type TransactionFilter struct {
+	VPA      string // Payer or payee
+	BankCode string // Payer's or payee's bank
+	Status   TransactionStatus
+	From     time.Time // Created at or after
+	To       time.Time // Created before
+	After    *TransactionCursor
+	Limit    int
+}
+
+// ListTransactions lists up to filter.Limit transactions, newest first.
+// Passing the cursor of the last transaction of a page as filter.After
+// returns the next page.
+func (r *PostgreSQLTransactionRepository) ListTransactions(ctx context.Context, filter TransactionFilter) ([]*Transaction, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.VPA != "" {
+		vpa := arg(filter.VPA)
+		conditions = append(conditions, "(payer_vpa = "+vpa+" OR payee_vpa = "+vpa+")")
+	}
+	if filter.BankCode != "" {
+		bankCode := arg(filter.BankCode)
+		conditions = append(conditions, "(payer_bank_code = "+bankCode+" OR payee_bank_code = "+bankCode+")")
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+arg(filter.Status))
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(filter.To))
+	}
+	if filter.After != nil {
+		conditions = append(conditions, "(created_at, id) < ("+arg(filter.After.CreatedAt)+", "+arg(filter.After.ID)+")")
+	}
+
+	query := `SELECT ` + transactionColumns + ` FROM transactions`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ` + arg(filter.Limit)
+
+	var transactions []*Transaction
+	err := r.read(ctx, func(db *sql.DB) error {
+		transactions = nil
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			transaction, err := scanTransaction(rows)
+			if err != nil {
+				return err
+			}
+			transactions = append(transactions, transaction)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// ListTransactionsByStatus lists the latest transactions in a status
+func (r *PostgreSQLTransactionRepository) ListTransactionsByStatus(ctx context.Context, status TransactionStatus, limit int) ([]*Transaction, error) {
+	return r.ListTransactions(ctx, TransactionFilter{Status: status, Limit: limit})
+}
+
+// ListTransactionsByVPA lists the latest transactions a VPA paid or was
+// paid in
+func (r *PostgreSQLTransactionRepository) ListTransactionsByVPA(ctx context.Context, vpa string, limit int) ([]*Transaction, error) {
+	return r.ListTransactions(ctx, TransactionFilter{VPA: vpa, Limit: limit})
 }
 
 // UpdateTransactionStatus updates transaction status using the stored function
@@ -260,28 +550,104 @@ func (r *PostgreSQLTransactionRepository) UpdateTransactionStatus(ctx context.Co
 	return nil
 }
 
+// RecordDebit stores the payer bank's reference for a successful debit, so
+// the debit can be reversed if the transaction never completes
+func (r *PostgreSQLTransactionRepository) RecordDebit(ctx context.Context, tx *sql.Tx, transactionID string, bankReferenceID string) error {
+	query := `UPDATE transactions SET debit_reference = $2 WHERE transaction_id = $1`
+
+	result, err := r.conn(tx).ExecContext(ctx, query, transactionID, bankReferenceID)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// ListExpiredTransactions returns the IDs of up to limit PENDING
+// transactions that expired before the given time, oldest first
+func (r *PostgreSQLTransactionRepository) ListExpiredTransactions(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT transaction_id FROM transactions
+		WHERE status = 'PENDING' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// LockPendingTransaction locks a PENDING transaction for the rest of tx, so
+// only one of the processor and the reaper decides how it ends. It returns
+// sql.ErrNoRows if the transaction is no longer PENDING or is locked by
+// someone else.
+func (r *PostgreSQLTransactionRepository) LockPendingTransaction(ctx context.Context, tx *sql.Tx, transactionID string) (*Transaction, error) {
+	query := `
+		SELECT transaction_id, payer_vpa, payee_vpa, amount_paisa, settled_amount_paisa, status,
+			   COALESCE(description, ''), COALESCE(reference, ''), payer_bank_code, payee_bank_code,
+			   initiated_at, expires_at, COALESCE(debit_reference, '')
+		FROM transactions
+		WHERE transaction_id = $1 AND status = 'PENDING'
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var transaction Transaction
+	err := tx.QueryRowContext(ctx, query, transactionID).Scan(
+		&transaction.TransactionID,
+		&transaction.PayerVPA,
+		&transaction.PayeeVPA,
+		&transaction.AmountPaisa,
+		&transaction.SettledAmountPaisa,
+		&transaction.Status,
+		&transaction.Description,
+		&transaction.Reference,
+		&transaction.PayerBankCode,
+		&transaction.PayeeBankCode,
+		&transaction.InitiatedAt,
+		&transaction.ExpiresAt,
+		&transaction.DebitReference,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
 // GetVPAMapping retrieves VPA mapping information
 func (r *PostgreSQLTransactionRepository) GetVPAMapping(ctx context.Context, vpa string) (*VPAMapping, error) {
 	query := `
-		SELECT id, vpa, bank_code, account_number, account_holder_name, mobile_number,
+		SELECT id, vpa, bank_code, account_number, account_holder_name, COALESCE(mobile_number, ''),
 			   is_active, created_at, updated_at
 		FROM vpa_mappings
 		WHERE vpa = $1 AND is_active = true
 	`
 
 	var mapping VPAMapping
-	err := r.db.QueryRowContext(ctx, query, vpa).Scan(
-		&mapping.ID,
-		&mapping.VPA,
-		&mapping.BankCode,
-		&mapping.AccountNumber,
-		&mapping.AccountHolderName,
-		&mapping.MobileNumber,
-		&mapping.IsActive,
-		&mapping.CreatedAt,
-		&mapping.UpdatedAt,
-	)
-
+	err := r.read(ctx, func(db *sql.DB) error {
+		return db.QueryRowContext(ctx, query, vpa).Scan(
+			&mapping.ID,
+			&mapping.VPA,
+			&mapping.BankCode,
+			&mapping.AccountNumber,
+			&mapping.AccountHolderName,
+			&mapping.MobileNumber,
+			&mapping.IsActive,
+			&mapping.CreatedAt,
+			&mapping.UpdatedAt,
+		)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -289,18 +655,19 @@ func (r *PostgreSQLTransactionRepository) GetVPAMapping(ctx context.Context, vpa
 	return &mapping, nil
 }
 
-// GetBankByCode retrieves bank information by bank code
-func (r *PostgreSQLTransactionRepository) GetBankByCode(ctx context.Context, bankCode string) (*Bank, error) {
-	query := `
-		SELECT id, bank_code, bank_name, ifsc_prefix, endpoint_url, public_key,
-			   status, last_heartbeat, success_rate, avg_response_time_ms, features,
-			   created_at, updated_at
-		FROM banks
-		WHERE bank_code = $1
-	`
-
+// bankColumns is the column list scanBank reads
+const bankColumns = `
+	id, bank_code, bank_name, ifsc_prefix, endpoint_url, public_key,
+	status, last_heartbeat, success_rate, avg_response_time_ms, features,
+	vpa_handles, circuit_state, COALESCE(callback_url, ''), COALESCE(status_reason, ''),
+	COALESCE(registered_by, ''), COALESCE(approved_by, ''), approved_at,
+	maintenance_window_id, created_at, updated_at
+`
+
+// scanBank reads a row selected with bankColumns
+func scanBank(row interface{ Scan(...interface{}) error }) (*Bank, error) {
 	var bank Bank
-	err := r.db.QueryRowContext(ctx, query, bankCode).Scan(
+	err := row.Scan(
 		&bank.ID,
 		&bank.BankCode,
 		&bank.BankName,
@@ -311,52 +678,152 @@ func (r *PostgreSQLTransactionRepository) GetBankByCode(ctx context.Context, ban
 		&bank.LastHeartbeat,
 		&bank.SuccessRate,
 		&bank.AvgResponseTimeMS,
-		&bank.Features,
+		pq.Array(&bank.Features),
+		pq.Array(&bank.VPAHandles),
+		&bank.CircuitState,
+		&bank.CallbackURL,
+		&bank.StatusReason,
+		&bank.RegisteredBy,
+		&bank.ApprovedBy,
+		&bank.ApprovedAt,
+		&bank.MaintenanceWindowID,
 		&bank.CreatedAt,
 		&bank.UpdatedAt,
 	)
-
 	if err != nil {
 		return nil, err
 	}
-
 	return &bank, nil
 }
 
-// CheckIdempotencyKey checks if an idempotency key exists and returns the cached response
-func (r *PostgreSQLTransactionRepository) CheckIdempotencyKey(ctx context.Context, keyHash string) (bool, string, error) {
+// GetBankByCode retrieves bank information by bank code
+func (r *PostgreSQLTransactionRepository) GetBankByCode(ctx context.Context, bankCode string) (*Bank, error) {
+	query := `SELECT ` + bankColumns + ` FROM banks WHERE bank_code = $1`
+
+	return scanBank(r.db.QueryRowContext(ctx, query, bankCode))
+}
+
+// UpsertBank registers a bank, or updates its details and endpoint if it
+// is already registered. The bank's ID is set from the stored row. A new
+// bank without VPA handles gets its bank code, lower-cased; an existing one
+// keeps its handles.
+func (r *PostgreSQLTransactionRepository) UpsertBank(ctx context.Context, tx *sql.Tx, bank *Bank) error {
 	query := `
-		SELECT entity_id, response_data
-		FROM idempotency_keys
-		WHERE key_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+		INSERT INTO banks (bank_code, bank_name, ifsc_prefix, endpoint_url, public_key, features, vpa_handles, callback_url)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, ARRAY['UPI', 'IMPS', 'NEFT', 'RTGS']), COALESCE($7, ARRAY[lower($1)]), NULLIF($8, ''))
+		ON CONFLICT (bank_code) DO UPDATE SET
+			bank_name = EXCLUDED.bank_name,
+			ifsc_prefix = EXCLUDED.ifsc_prefix,
+			endpoint_url = EXCLUDED.endpoint_url,
+			public_key = EXCLUDED.public_key,
+			features = EXCLUDED.features,
+			vpa_handles = COALESCE($7, banks.vpa_handles),
+			callback_url = EXCLUDED.callback_url,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id
 	`
 
-	var entityID string
-	var responseData []byte
-	err := r.db.QueryRowContext(ctx, query, keyHash).Scan(&entityID, &responseData)
-
-	if err == sql.ErrNoRows {
-		return false, "", nil
+	var features, handles interface{}
+	if len(bank.Features) > 0 {
+		features = pq.Array(bank.Features)
 	}
-
-	if err != nil {
-		return false, "", err
+	if len(bank.VPAHandles) > 0 {
+		handles = pq.Array(bank.VPAHandles)
 	}
-
-	return true, string(responseData), nil
+	return r.conn(tx).QueryRowContext(ctx, query,
+		bank.BankCode,
+		bank.BankName,
+		bank.IFSCPrefix,
+		bank.EndpointURL,
+		bank.PublicKey,
+		features,
+		handles,
+		bank.CallbackURL,
+	).Scan(&bank.ID)
 }
 
-// StoreIdempotencyKey stores an idempotency key with cached response
-func (r *PostgreSQLTransactionRepository) StoreIdempotencyKey(ctx context.Context, tx *sql.Tx, keyHash string, entityType string, entityID string, responseData []byte, expiresAt time.Time) error {
-	query := `
-		INSERT INTO idempotency_keys (key_hash, entity_type, entity_id, response_data, expires_at)
-		VALUES ($1, $2, $3, $4, $5)
+// ErrIdempotencyKeyInFlight is returned by ClaimIdempotencyKey when another
+// request holding the same key is still being processed
+var ErrIdempotencyKeyInFlight = errors.New("a request with the same idempotency key is already in progress")
+
+// ClaimIdempotencyKey claims a key for the request about to be processed.
+// It reports true if the caller now holds the key and must finish with
+// CompleteIdempotencyKey or ReleaseIdempotencyKey. Otherwise it returns the
+// cached response of the request that completed with the key, or
+// ErrIdempotencyKeyInFlight if that request is still running.
+//
+// The claim is a single INSERT on the unique key_hash, so of any number of
+// concurrent duplicates exactly one wins; the others wait on its row lock
+// and then see the PENDING marker. A key left PENDING by a crashed request
+// can be claimed again once expiresAt passes.
+func (r *PostgreSQLTransactionRepository) ClaimIdempotencyKey(ctx context.Context, keyHash string, entityType string, entityID string, expiresAt time.Time) (bool, string, error) {
+	claim := `
+		INSERT INTO idempotency_keys (key_hash, entity_type, entity_id, status, expires_at)
+		VALUES ($1, $2, $3, 'PENDING', $4)
 		ON CONFLICT (key_hash) DO UPDATE SET
-			response_data = EXCLUDED.response_data,
+			entity_type = EXCLUDED.entity_type,
+			entity_id = EXCLUDED.entity_id,
+			status = 'PENDING',
+			response_data = NULL,
+			created_at = CURRENT_TIMESTAMP,
 			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= CURRENT_TIMESTAMP
+		RETURNING id
 	`
+	existing := `SELECT status, response_data FROM idempotency_keys WHERE key_hash = $1`
+
+	// A key released between the two statements is claimed on the next try
+	for attempt := 0; attempt < 3; attempt++ {
+		var id string
+		err := r.db.QueryRowContext(ctx, claim, keyHash, entityType, entityID, expiresAt).Scan(&id)
+		if err == nil {
+			return true, "", nil
+		}
+		if err != sql.ErrNoRows {
+			return false, "", err
+		}
+
+		var status string
+		var responseData []byte
+		err = r.db.QueryRowContext(ctx, existing, keyHash).Scan(&status, &responseData)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return false, "", err
+		}
+		if status == "PENDING" {
+			return false, "", ErrIdempotencyKeyInFlight
+		}
+		return false, string(responseData), nil
+	}
+	return false, "", ErrIdempotencyKeyInFlight
+}
 
-	_, err := tx.ExecContext(ctx, query, keyHash, entityType, entityID, responseData, expiresAt)
+// CompleteIdempotencyKey caches the response of the request holding a key,
+// for duplicates to be answered with until expiresAt
+func (r *PostgreSQLTransactionRepository) CompleteIdempotencyKey(ctx context.Context, tx *sql.Tx, keyHash string, responseData []byte, expiresAt time.Time) error {
+	query := `
+		UPDATE idempotency_keys SET
+			status = 'COMPLETED',
+			response_data = $2,
+			expires_at = $3
+		WHERE key_hash = $1 AND status = 'PENDING'
+	`
+
+	result, err := r.conn(tx).ExecContext(ctx, query, keyHash, responseData, expiresAt)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// ReleaseIdempotencyKey gives up a claimed key without caching a response,
+// so the request can be retried with it
+func (r *PostgreSQLTransactionRepository) ReleaseIdempotencyKey(ctx context.Context, keyHash string) error {
+	query := `DELETE FROM idempotency_keys WHERE key_hash = $1 AND status = 'PENDING'`
+
+	_, err := r.db.ExecContext(ctx, query, keyHash)
 	return err
 }
 
@@ -367,10 +834,30 @@ func (r *PostgreSQLTransactionRepository) LogAudit(ctx context.Context, tx *sql.
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := tx.ExecContext(ctx, query, entityType, entityID, action, actor, oldValues, newValues, correlationID)
+	oldJSON, err := jsonb(oldValues)
+	if err != nil {
+		return err
+	}
+	newJSON, err := jsonb(newValues)
+	if err != nil {
+		return err
+	}
+	_, err = r.conn(tx).ExecContext(ctx, query, entityType, entityID, action, actor, oldJSON, newJSON, correlationID)
 	return err
 }
 
+// jsonb encodes values for a JSONB column, nil as NULL
+func jsonb(values map[string]interface{}) (interface{}, error) {
+	if values == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
 // AcquireLock attempts to acquire a distributed lock
 func (r *PostgreSQLTransactionRepository) AcquireLock(ctx context.Context, lockName string, ownerID string, duration time.Duration) (bool, error) {
 	expiresAt := time.Now().Add(duration)
@@ -406,48 +893,156 @@ func (r *PostgreSQLTransactionRepository) ReleaseLock(ctx context.Context, lockN
 	return err
 }
 
-// Placeholder implementations for remaining methods
-func (r *PostgreSQLTransactionRepository) GetTransactionByRRN(ctx context.Context, rrn string) (*Transaction, error) {
-	// Implementation similar to GetTransactionByID but filtering by RRN
-	return nil, nil
-}
-
-func (r *PostgreSQLTransactionRepository) ListTransactionsByStatus(ctx context.Context, status TransactionStatus, limit int) ([]*Transaction, error) {
-	// Implementation to list transactions by status
-	return nil, nil
-}
-
-func (r *PostgreSQLTransactionRepository) ListTransactionsByVPA(ctx context.Context, vpa string, limit int) ([]*Transaction, error) {
-	// Implementation to list transactions by VPA
-	return nil, nil
-}
+// ErrVPAAlreadyRegistered is returned by CreateVPAMapping when the VPA is
+// already mapped to an account
+var ErrVPAAlreadyRegistered = errors.New("VPA is already registered")
 
+// CreateVPAMapping maps a VPA to an account. A VPA that was deactivated can
+// be registered again; an active one cannot. The mapping's ID and
+// timestamps are set from the stored row.
 func (r *PostgreSQLTransactionRepository) CreateVPAMapping(ctx context.Context, tx *sql.Tx, mapping *VPAMapping) error {
-	// Implementation to create VPA mapping
-	return nil
+	query := `
+		INSERT INTO vpa_mappings (vpa, bank_code, account_number, account_holder_name, mobile_number)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		ON CONFLICT (vpa) DO UPDATE SET
+			bank_code = EXCLUDED.bank_code,
+			account_number = EXCLUDED.account_number,
+			account_holder_name = EXCLUDED.account_holder_name,
+			mobile_number = EXCLUDED.mobile_number,
+			is_active = true,
+			created_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE vpa_mappings.is_active = false
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.conn(tx).QueryRowContext(ctx, query,
+		mapping.VPA,
+		mapping.BankCode,
+		mapping.AccountNumber,
+		mapping.AccountHolderName,
+		mapping.MobileNumber,
+	).Scan(&mapping.ID, &mapping.CreatedAt, &mapping.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrVPAAlreadyRegistered
+	}
+	mapping.IsActive = err == nil
+	return err
 }
 
+// UpdateVPAMapping points an active VPA at a new account. Empty holder name
+// and mobile number fields are left as they are.
 func (r *PostgreSQLTransactionRepository) UpdateVPAMapping(ctx context.Context, tx *sql.Tx, vpa string, mapping *VPAMapping) error {
-	// Implementation to update VPA mapping
-	return nil
+	query := `
+		UPDATE vpa_mappings SET
+			account_number = $2,
+			account_holder_name = COALESCE(NULLIF($3, ''), account_holder_name),
+			mobile_number = COALESCE(NULLIF($4, ''), mobile_number)
+		WHERE vpa = $1 AND is_active = true
+	`
+
+	result, err := r.conn(tx).ExecContext(ctx, query, vpa, mapping.AccountNumber, mapping.AccountHolderName, mapping.MobileNumber)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
 }
 
+// DeactivateVPA stops a VPA resolving; it can be registered again later
 func (r *PostgreSQLTransactionRepository) DeactivateVPA(ctx context.Context, tx *sql.Tx, vpa string) error {
-	// Implementation to deactivate VPA
-	return nil
+	query := `UPDATE vpa_mappings SET is_active = false WHERE vpa = $1 AND is_active = true`
+
+	result, err := r.conn(tx).ExecContext(ctx, query, vpa)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
 }
 
+// ListActiveBanks lists the banks currently accepting transactions
 func (r *PostgreSQLTransactionRepository) ListActiveBanks(ctx context.Context) ([]*Bank, error) {
-	// Implementation to list active banks
-	return nil, nil
+	query := `SELECT ` + bankColumns + ` FROM banks WHERE status = 'ACTIVE' ORDER BY bank_code`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var banks []*Bank
+	for rows.Next() {
+		bank, err := scanBank(rows)
+		if err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank)
+	}
+	return banks, rows.Err()
 }
 
+// UpdateBankStatus changes a bank's status, e.g. to take it out of routing
+// for maintenance
 func (r *PostgreSQLTransactionRepository) UpdateBankStatus(ctx context.Context, tx *sql.Tx, bankCode string, status string) error {
-	// Implementation to update bank status
-	return nil
+	query := `UPDATE banks SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE bank_code = $1`
+
+	result, err := r.conn(tx).ExecContext(ctx, query, bankCode, status)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
 }
 
+// UpdateBankHealth records a successful health check of a bank as its
+// latest heartbeat
 func (r *PostgreSQLTransactionRepository) UpdateBankHealth(ctx context.Context, tx *sql.Tx, bankCode string, successRate int, avgResponseTime int) error {
-	// Implementation to update bank health metrics
+	query := `
+		UPDATE banks SET
+			success_rate = $2,
+			avg_response_time_ms = $3,
+			last_heartbeat = CURRENT_TIMESTAMP
+		WHERE bank_code = $1
+	`
+
+	result, err := r.conn(tx).ExecContext(ctx, query, bankCode, successRate, avgResponseTime)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// UpdateBankCircuitState records the state a bank's circuit breaker moved to
+func (r *PostgreSQLTransactionRepository) UpdateBankCircuitState(ctx context.Context, bankCode string, state string) error {
+	query := `UPDATE banks SET circuit_state = $2, circuit_changed_at = CURRENT_TIMESTAMP WHERE bank_code = $1`
+
+	result, err := r.db.ExecContext(ctx, query, bankCode, state)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// queryer is what both *sql.DB and *sql.Tx run statements with
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn runs statements in tx when there is one, and on their own otherwise
+func (r *PostgreSQLTransactionRepository) conn(tx *sql.Tx) queryer {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// requireRow reports sql.ErrNoRows when an update matched nothing
+func requireRow(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
 	return nil
 }
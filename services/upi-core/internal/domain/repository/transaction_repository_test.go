@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCreateAndListTransactions(t *testing.T) {
+	repo, db := testRepository(t)
+	ctx := context.Background()
+	payer := fmt.Sprintf("list%d@hdfc", time.Now().UnixNano())
+	t.Cleanup(func() { db.Exec(`DELETE FROM transactions WHERE payer_vpa = $1`, payer) })
+
+	for i := 0; i < 3; i++ {
+		expiresAt := time.Now().Add(time.Minute)
+		transaction := &Transaction{
+			TransactionID: fmt.Sprintf("%s_%d", t.Name(), time.Now().UnixNano()),
+			RRN:           fmt.Sprintf("%012d", time.Now().UnixNano()%1e12),
+			PayerVPA:      payer,
+			PayeeVPA:      "bob@sbi",
+			AmountPaisa:   int64(100 * (i + 1)),
+			Currency:      "INR",
+			Type:          TypeP2P,
+			Status:        StatusPending,
+			PayerBankCode: "HDFC",
+			PayeeBankCode: "SBI",
+			Metadata:      map[string]string{"order": fmt.Sprint(i)},
+			InitiatedAt:   time.Now(),
+			ExpiresAt:     &expiresAt,
+		}
+		tx, err := repo.BeginTransaction(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.CreateTransaction(ctx, tx, transaction); err != nil {
+			tx.Rollback()
+			t.Fatal(err)
+		}
+		if err := repo.CommitTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+
+		stored, err := repo.GetTransactionByRRN(ctx, transaction.RRN)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.TransactionID != transaction.TransactionID || stored.Metadata["order"] != fmt.Sprint(i) {
+			t.Errorf("stored %+v", stored)
+		}
+	}
+
+	first, err := repo.ListTransactions(ctx, TransactionFilter{VPA: payer, Status: StatusPending, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := first[len(first)-1]
+	rest, err := repo.ListTransactions(ctx, TransactionFilter{VPA: payer, Limit: 2, After: &TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 2 || len(rest) != 1 || first[0].AmountPaisa != 300 || rest[0].AmountPaisa != 100 {
+		t.Errorf("pages of %d and %d transactions", len(first), len(rest))
+	}
+
+	for bankCode, want := range map[string]int{"SBI": 3, "ICICI": 0} {
+		byBank, err := repo.ListTransactions(ctx, TransactionFilter{VPA: payer, BankCode: bankCode, Limit: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(byBank) != want {
+			t.Errorf("%d transactions of bank %s, want %d", len(byBank), bankCode, want)
+		}
+	}
+}
+
+type fakeReplicas struct {
+	replica *sql.DB
+	failed  []*sql.DB
+}
+
+func (f *fakeReplicas) Reader() *sql.DB { return f.replica }
+
+func (f *fakeReplicas) Failed(replica *sql.DB) { f.failed = append(f.failed, replica) }
+
+func TestReadsFailBackToPrimary(t *testing.T) {
+	// Opening connects to nothing; the lookups below never query
+	primary, _ := sql.Open("postgres", "host=primary")
+	replica, _ := sql.Open("postgres", "host=replica")
+	replicas := &fakeReplicas{replica: replica}
+	repo := &PostgreSQLTransactionRepository{db: primary, replicas: replicas}
+	ctx := context.Background()
+
+	var used []*sql.DB
+	lookup := func(err error) func(db *sql.DB) error {
+		return func(db *sql.DB) error {
+			used = append(used, db)
+			if db == replica {
+				return err
+			}
+			return nil
+		}
+	}
+
+	if repo.read(ctx, lookup(nil)); len(used) != 1 || used[0] != replica {
+		t.Errorf("healthy replica: read from %v, want the replica", used)
+	}
+
+	// A missing row may just be replication lag, but is no failure
+	used = nil
+	if err := repo.read(ctx, lookup(sql.ErrNoRows)); !errors.Is(err, sql.ErrNoRows) || len(used) != 1 || len(replicas.failed) != 0 {
+		t.Errorf("missing row: err = %v after reads %v, failed %v", err, used, replicas.failed)
+	}
+
+	used = nil
+	if err := repo.read(ctx, lookup(errors.New("connection refused"))); err != nil || len(used) != 2 || used[1] != primary {
+		t.Errorf("failing replica: err = %v after reads %v, want a retry on the primary", err, used)
+	}
+	if len(replicas.failed) != 1 || replicas.failed[0] != replica {
+		t.Errorf("failed replicas = %v, want the replica reported", replicas.failed)
+	}
+
+	used = nil
+	if repo.read(ReadPrimary(ctx), lookup(nil)); len(used) != 1 || used[0] != primary {
+		t.Errorf("ReadPrimary: read from %v, want the primary", used)
+	}
+
+	replicas.replica = nil
+	used = nil
+	if repo.read(ctx, lookup(nil)); len(used) != 1 || used[0] != primary {
+		t.Errorf("no healthy replica: read from %v, want the primary", used)
+	}
+}
@@ -0,0 +1,541 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/repository"
+)
+
+var (
+	// ErrInvalidBankRequest wraps the reason a bank request was rejected
+	// before reaching the database
+	ErrInvalidBankRequest = errors.New("invalid bank request")
+	// ErrBankNotFound is returned for an unknown bank code
+	ErrBankNotFound = errors.New("bank not found")
+	// ErrBankStatusChange is returned for a change a bank's status does not
+	// allow, e.g. suspending a bank awaiting approval
+	ErrBankStatusChange = errors.New("bank status change not allowed")
+	// ErrSelfApproval is returned when an operator approves a bank they
+	// registered themselves
+	ErrSelfApproval = errors.New("a bank must be approved by another operator than the one who registered it")
+	// ErrMaintenanceWindowNotFound is returned for an unknown maintenance
+	// window, or one that has ended or was cancelled
+	ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+)
+
+const (
+	// defaultBankPageSize and maxBankPageSize bound the banks listed at a time
+	defaultBankPageSize = 50
+	maxBankPageSize     = 200
+	// systemActor is the actor of changes made by the switch itself
+	systemActor = "SYSTEM"
+)
+
+var (
+	bankCodePattern   = regexp.MustCompile(`^[A-Z0-9]{2,10}$`)
+	ifscPrefixPattern = regexp.MustCompile(`^[A-Z]{4}$`)
+)
+
+// bankTransitions are the statuses operators can move a bank to, and from
+// which. Banks leave PENDING_APPROVAL only through Approve or Reject.
+var bankTransitions = map[string][]string{
+	repository.BankActive:      {repository.BankInactive, repository.BankMaintenance, repository.BankSuspended},
+	repository.BankInactive:    {repository.BankActive, repository.BankMaintenance, repository.BankSuspended},
+	repository.BankMaintenance: {repository.BankActive, repository.BankInactive, repository.BankSuspended},
+	repository.BankSuspended:   {repository.BankActive, repository.BankInactive, repository.BankMaintenance},
+}
+
+// BankReloader brings connections to a bank in line with its registration,
+// e.g. the bank client manager
+type BankReloader interface {
+	Reload(ctx context.Context, bankCode string) error
+}
+
+// BankService onboards banks and manages their lifecycle. A bank registered
+// by one operator is approved, or rejected, by another; operators then
+// suspend it, take it out of service and schedule its maintenance windows.
+// Every change is audited with the operator who made it. In the
+// background, banks are put in MAINTENANCE when a window of theirs starts
+// and made ACTIVE again when it ends.
+type BankService struct {
+	repo   repository.TransactionRepository
+	banks  BankReloader // nil to leave connections to the periodic refresh
+	cfg    config.BanksConfig
+	logger *logrus.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewBankService creates a bank service; Start runs its maintenance
+// scheduler in the background
+func NewBankService(repo repository.TransactionRepository, banks BankReloader, cfg config.BanksConfig, logger *logrus.Logger) *BankService {
+	if cfg.MaintenanceCheckInterval <= 0 {
+		cfg.MaintenanceCheckInterval = 30 * time.Second
+	}
+	return &BankService{repo: repo, banks: banks, cfg: cfg, logger: logger}
+}
+
+// Register registers a new bank, PENDING_APPROVAL until another operator
+// approves it. For a registered bank it updates the details instead; a
+// rejected bank's application is made again, pending approval.
+func (s *BankService) Register(ctx context.Context, bank *repository.Bank, actor string) (*repository.Bank, error) {
+	bank.BankCode = strings.ToUpper(strings.TrimSpace(bank.BankCode))
+	bank.IFSCPrefix = strings.ToUpper(strings.TrimSpace(bank.IFSCPrefix))
+	if err := validateBank(bank); err != nil {
+		return nil, err
+	}
+	bank.RegisteredBy = actor
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.repo.RollbackTransaction(tx)
+
+	existing, err := s.repo.LockBank(ctx, tx, bank.BankCode)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if err := s.repo.CreateBank(ctx, tx, bank); err != nil {
+			return nil, fmt.Errorf("failed to register bank: %w", err)
+		}
+		if err := s.repo.LogAudit(ctx, tx, "bank", bank.BankCode, "REGISTER", actor, nil, bankDetails(bank), ""); err != nil {
+			return nil, fmt.Errorf("failed to log audit entry: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to load bank: %w", err)
+	default:
+		if err := s.repo.UpsertBank(ctx, tx, bank); err != nil {
+			return nil, fmt.Errorf("failed to update bank: %w", err)
+		}
+		action := "UPDATE"
+		bank.Status = existing.Status
+		bank.RegisteredBy = existing.RegisteredBy
+		if existing.Status == repository.BankRejected {
+			action = "REREGISTER"
+		}
+		// Whoever last changed an application awaiting approval counts as
+		// registering it, so that they cannot approve their own changes
+		if existing.Status == repository.BankPendingApproval || existing.Status == repository.BankRejected {
+			bank.Status = repository.BankPendingApproval
+			bank.RegisteredBy = actor
+			if err := s.repo.UpdateBankLifecycle(ctx, tx, bank); err != nil {
+				return nil, fmt.Errorf("failed to update bank: %w", err)
+			}
+		}
+		if err := s.repo.LogAudit(ctx, tx, "bank", bank.BankCode, action, actor, bankDetails(existing), bankDetails(bank), ""); err != nil {
+			return nil, fmt.Errorf("failed to log audit entry: %w", err)
+		}
+	}
+
+	if err := s.repo.CommitTransaction(tx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	s.reload(ctx, bank.BankCode)
+	return s.Get(ctx, bank.BankCode)
+}
+
+// Approve makes a bank awaiting approval ACTIVE. The approver must not be
+// the operator who registered it.
+func (s *BankService) Approve(ctx context.Context, bankCode, actor string) (*repository.Bank, error) {
+	return s.change(ctx, bankCode, actor, "APPROVE", func(bank *repository.Bank) error {
+		if bank.Status != repository.BankPendingApproval {
+			return fmt.Errorf("%w: bank %s is %s, not awaiting approval", ErrBankStatusChange, bankCode, bank.Status)
+		}
+		if bank.RegisteredBy == actor {
+			return ErrSelfApproval
+		}
+		now := time.Now()
+		bank.Status = repository.BankActive
+		bank.StatusReason = ""
+		bank.ApprovedBy = actor
+		bank.ApprovedAt = &now
+		return nil
+	})
+}
+
+// Reject turns down a bank awaiting approval; it can be registered again
+func (s *BankService) Reject(ctx context.Context, bankCode, actor, reason string) (*repository.Bank, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidBankRequest)
+	}
+	return s.change(ctx, bankCode, actor, "REJECT", func(bank *repository.Bank) error {
+		if bank.Status != repository.BankPendingApproval {
+			return fmt.Errorf("%w: bank %s is %s, not awaiting approval", ErrBankStatusChange, bankCode, bank.Status)
+		}
+		bank.Status = repository.BankRejected
+		bank.StatusReason = reason
+		return nil
+	})
+}
+
+// SetStatus moves an approved bank to ACTIVE, INACTIVE, MAINTENANCE or
+// SUSPENDED. Suspending takes a reason. A bank in MAINTENANCE for a window
+// leaves it when the window ends or is cancelled, unless suspended or taken
+// out of service meanwhile.
+func (s *BankService) SetStatus(ctx context.Context, bankCode, status, actor, reason string) (*repository.Bank, error) {
+	if _, ok := bankTransitions[status]; !ok {
+		return nil, fmt.Errorf("%w: status %s cannot be set", ErrInvalidBankRequest, status)
+	}
+	if status == repository.BankSuspended && strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("%w: reason is required to suspend a bank", ErrInvalidBankRequest)
+	}
+	return s.change(ctx, bankCode, actor, "SET_STATUS", func(bank *repository.Bank) error {
+		if !allowedTransition(bank.Status, status) {
+			return fmt.Errorf("%w: bank %s is %s", ErrBankStatusChange, bankCode, bank.Status)
+		}
+		if status == repository.BankActive && bank.MaintenanceWindowID != nil {
+			return fmt.Errorf("%w: bank %s is in maintenance window %d; cancel the window instead", ErrBankStatusChange, bankCode, *bank.MaintenanceWindowID)
+		}
+		bank.Status = status
+		bank.StatusReason = reason
+		bank.MaintenanceWindowID = nil
+		return nil
+	})
+}
+
+func allowedTransition(from, to string) bool {
+	for _, status := range bankTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduleMaintenance schedules a maintenance window of an approved bank.
+// The bank is put in MAINTENANCE within a check interval of the window
+// starting.
+func (s *BankService) ScheduleMaintenance(ctx context.Context, window *repository.MaintenanceWindow, actor string) error {
+	window.BankCode = strings.ToUpper(strings.TrimSpace(window.BankCode))
+	switch {
+	case window.BankCode == "":
+		return fmt.Errorf("%w: bank code is required", ErrInvalidBankRequest)
+	case !window.EndsAt.After(window.StartsAt):
+		return fmt.Errorf("%w: maintenance must end after it starts", ErrInvalidBankRequest)
+	case !window.EndsAt.After(time.Now()):
+		return fmt.Errorf("%w: maintenance must end in the future", ErrInvalidBankRequest)
+	case strings.TrimSpace(window.Reason) == "":
+		return fmt.Errorf("%w: reason is required", ErrInvalidBankRequest)
+	}
+	window.CreatedBy = actor
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.repo.RollbackTransaction(tx)
+
+	bank, err := s.repo.LockBank(ctx, tx, window.BankCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrBankNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load bank: %w", err)
+	}
+	if _, ok := bankTransitions[bank.Status]; !ok {
+		return fmt.Errorf("%w: bank %s is %s", ErrBankStatusChange, bank.BankCode, bank.Status)
+	}
+	err = s.repo.CreateMaintenanceWindow(ctx, tx, window)
+	if errors.Is(err, repository.ErrMaintenanceWindowOverlaps) {
+		return fmt.Errorf("%w: %v", ErrInvalidBankRequest, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to schedule maintenance: %w", err)
+	}
+	values := map[string]interface{}{
+		"window_id": window.WindowID,
+		"starts_at": window.StartsAt,
+		"ends_at":   window.EndsAt,
+		"reason":    window.Reason,
+	}
+	if err := s.repo.LogAudit(ctx, tx, "bank", bank.BankCode, "SCHEDULE_MAINTENANCE", actor, nil, values, ""); err != nil {
+		return fmt.Errorf("failed to log audit entry: %w", err)
+	}
+	if err := s.repo.CommitTransaction(tx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// CancelMaintenance cancels a maintenance window that has not ended. A bank
+// in MAINTENANCE for it is made ACTIVE again within a check interval.
+func (s *BankService) CancelMaintenance(ctx context.Context, windowID int64, actor string) (*repository.MaintenanceWindow, error) {
+	window, err := s.repo.GetMaintenanceWindow(ctx, windowID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMaintenanceWindowNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load maintenance window: %w", err)
+	}
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.repo.RollbackTransaction(tx)
+
+	err = s.repo.CancelMaintenanceWindow(ctx, tx, windowID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMaintenanceWindowNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel maintenance window: %w", err)
+	}
+	values := map[string]interface{}{"window_id": windowID}
+	if err := s.repo.LogAudit(ctx, tx, "bank", window.BankCode, "CANCEL_MAINTENANCE", actor, nil, values, ""); err != nil {
+		return nil, fmt.Errorf("failed to log audit entry: %w", err)
+	}
+	if err := s.repo.CommitTransaction(tx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	now := time.Now()
+	window.CancelledAt = &now
+	return window, nil
+}
+
+// ListMaintenanceWindows lists a bank's windows that are under way or to
+// come, earliest first
+func (s *BankService) ListMaintenanceWindows(ctx context.Context, bankCode string) ([]*repository.MaintenanceWindow, error) {
+	windows, err := s.repo.ListMaintenanceWindows(ctx, strings.ToUpper(bankCode), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	return windows, nil
+}
+
+// Get returns a bank
+func (s *BankService) Get(ctx context.Context, bankCode string) (*repository.Bank, error) {
+	bank, err := s.repo.GetBankByCode(ctx, strings.ToUpper(bankCode))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrBankNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bank: %w", err)
+	}
+	return bank, nil
+}
+
+// List lists banks by bank code, in a status if one is given. Passing the
+// code of the last bank of a page as after returns the next page, which is
+// empty at the end.
+func (s *BankService) List(ctx context.Context, status, after string, pageSize int) ([]*repository.Bank, error) {
+	if pageSize <= 0 {
+		pageSize = defaultBankPageSize
+	}
+	if pageSize > maxBankPageSize {
+		pageSize = maxBankPageSize
+	}
+	banks, err := s.repo.ListBanks(ctx, repository.BankFilter{Status: status, After: after, Limit: pageSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list banks: %w", err)
+	}
+	return banks, nil
+}
+
+// errBankUnchanged makes change leave a bank as it is, without an error
+var errBankUnchanged = errors.New("bank unchanged")
+
+// change applies a lifecycle change to a bank locked meanwhile, audits it
+// in the same database transaction, and then brings the connections to the
+// bank in line
+func (s *BankService) change(ctx context.Context, bankCode, actor, action string, apply func(*repository.Bank) error) (*repository.Bank, error) {
+	bankCode = strings.ToUpper(bankCode)
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.repo.RollbackTransaction(tx)
+
+	bank, err := s.repo.LockBank(ctx, tx, bankCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrBankNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bank: %w", err)
+	}
+	before := bankLifecycle(bank)
+	if err := apply(bank); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateBankLifecycle(ctx, tx, bank); err != nil {
+		return nil, fmt.Errorf("failed to update bank: %w", err)
+	}
+	if err := s.repo.LogAudit(ctx, tx, "bank", bankCode, action, actor, before, bankLifecycle(bank), ""); err != nil {
+		return nil, fmt.Errorf("failed to log audit entry: %w", err)
+	}
+	if err := s.repo.CommitTransaction(tx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"bank_code": bankCode,
+		"action":    action,
+		"status":    bank.Status,
+		"actor":     actor,
+	}).Info("Bank lifecycle changed")
+	s.reload(ctx, bankCode)
+	return bank, nil
+}
+
+// reload brings the bank connections in line with a change. A failure
+// only delays it until the next periodic refresh.
+func (s *BankService) reload(ctx context.Context, bankCode string) {
+	if s.banks == nil {
+		return
+	}
+	if err := s.banks.Reload(ctx, bankCode); err != nil {
+		s.logger.WithError(err).WithField("bank_code", bankCode).Warn("Failed to reload bank connections")
+	}
+}
+
+// CheckMaintenance puts ACTIVE banks whose maintenance window has started
+// in MAINTENANCE, and makes banks whose window has ended or was cancelled
+// ACTIVE again. It returns how many banks it changed. Any number of
+// instances can run it side by side.
+func (s *BankService) CheckMaintenance(ctx context.Context) (int, error) {
+	now := time.Now()
+	started, err := s.repo.ListStartedMaintenanceWindows(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list started maintenance windows: %w", err)
+	}
+	ended, err := s.repo.ListBanksLeavingMaintenance(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list banks leaving maintenance: %w", err)
+	}
+
+	changed := 0
+	for _, window := range started {
+		window := window
+		_, err := s.change(ctx, window.BankCode, systemActor, "START_MAINTENANCE", func(bank *repository.Bank) error {
+			// Changed by an operator or another instance since it was listed
+			if bank.Status != repository.BankActive {
+				return errBankUnchanged
+			}
+			bank.Status = repository.BankMaintenance
+			bank.StatusReason = window.Reason
+			bank.MaintenanceWindowID = &window.WindowID
+			return nil
+		})
+		if errors.Is(err, errBankUnchanged) {
+			continue
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("bank_code", window.BankCode).Error("Failed to start bank maintenance")
+			continue
+		}
+		changed++
+	}
+	for _, bankCode := range ended {
+		_, err := s.change(ctx, bankCode, systemActor, "END_MAINTENANCE", func(bank *repository.Bank) error {
+			if bank.Status != repository.BankMaintenance || bank.MaintenanceWindowID == nil {
+				return errBankUnchanged
+			}
+			bank.Status = repository.BankActive
+			bank.StatusReason = ""
+			bank.MaintenanceWindowID = nil
+			return nil
+		})
+		if errors.Is(err, errBankUnchanged) {
+			continue
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("bank_code", bankCode).Error("Failed to end bank maintenance")
+			continue
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// Start runs the maintenance scheduler in the background
+func (s *BankService) Start() {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+}
+
+func (s *BankService) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.MaintenanceCheckInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.stop
+		cancel()
+	}()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if _, err := s.CheckMaintenance(ctx); err != nil {
+				s.logger.WithError(err).Error("Failed to check bank maintenance windows")
+			}
+		}
+	}
+}
+
+// Close stops the maintenance scheduler
+func (s *BankService) Close() {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+	}
+}
+
+func validateBank(bank *repository.Bank) error {
+	switch {
+	case !bankCodePattern.MatchString(bank.BankCode):
+		return fmt.Errorf("%w: bank code must be 2 to 10 letters or digits", ErrInvalidBankRequest)
+	case strings.TrimSpace(bank.BankName) == "":
+		return fmt.Errorf("%w: bank name is required", ErrInvalidBankRequest)
+	case !ifscPrefixPattern.MatchString(bank.IFSCPrefix):
+		return fmt.Errorf("%w: IFSC prefix must be 4 letters", ErrInvalidBankRequest)
+	case bank.EndpointURL == "":
+		return fmt.Errorf("%w: endpoint URL is required", ErrInvalidBankRequest)
+	}
+	if _, err := crypto.ParsePublicKey(bank.PublicKey); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBankRequest, err)
+	}
+	return nil
+}
+
+// bankDetails are the registration details of a bank, for audit entries
+func bankDetails(bank *repository.Bank) map[string]interface{} {
+	return map[string]interface{}{
+		"bank_name":    bank.BankName,
+		"ifsc_prefix":  bank.IFSCPrefix,
+		"endpoint_url": bank.EndpointURL,
+		"public_key":   bank.PublicKey,
+		"callback_url": bank.CallbackURL,
+		"features":     bank.Features,
+		"status":       bank.Status,
+	}
+}
+
+// bankLifecycle is the lifecycle state of a bank, for audit entries
+func bankLifecycle(bank *repository.Bank) map[string]interface{} {
+	values := map[string]interface{}{
+		"status":        bank.Status,
+		"status_reason": bank.StatusReason,
+		"approved_by":   bank.ApprovedBy,
+	}
+	if bank.MaintenanceWindowID != nil {
+		values["maintenance_window_id"] = strconv.FormatInt(*bank.MaintenanceWindowID, 10)
+	}
+	return values
+}
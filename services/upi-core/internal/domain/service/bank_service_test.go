@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+type fakeReloader struct{ reloaded []string }
+
+func (f *fakeReloader) Reload(ctx context.Context, bankCode string) error {
+	f.reloaded = append(f.reloaded, bankCode)
+	return nil
+}
+
+func newTestBankService(t *testing.T, banks ...*repository.Bank) (*BankService, *fakeRepository, *fakeReloader) {
+	t.Helper()
+	repo := newFakeRepository()
+	repo.banks = make(map[string]*repository.Bank)
+	for _, bank := range banks {
+		repo.banks[bank.BankCode] = bank
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	reloader := &fakeReloader{}
+	return NewBankService(repo, reloader, config.BanksConfig{}, logger), repo, reloader
+}
+
+func TestBankOnboardingNeedsAnotherOperatorsApproval(t *testing.T) {
+	s, repo, reloader := newTestBankService(t)
+	ctx := context.Background()
+	public, _, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(public)
+
+	bank, err := s.Register(ctx, &repository.Bank{
+		BankCode:    "kotak",
+		BankName:    "Kotak Mahindra Bank",
+		IFSCPrefix:  "kkbk",
+		EndpointURL: "kotak:50051",
+		PublicKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bank.BankCode != "KOTAK" || bank.Status != repository.BankPendingApproval {
+		t.Fatalf("registered %s as %s, want KOTAK awaiting approval", bank.BankCode, bank.Status)
+	}
+
+	if _, err := s.Approve(ctx, "KOTAK", "alice"); !errors.Is(err, ErrSelfApproval) {
+		t.Errorf("approval by the registering operator: err = %v, want ErrSelfApproval", err)
+	}
+	if _, err := s.SetStatus(ctx, "KOTAK", repository.BankActive, "bob", ""); !errors.Is(err, ErrBankStatusChange) {
+		t.Errorf("activation before approval: err = %v, want ErrBankStatusChange", err)
+	}
+	bank, err = s.Approve(ctx, "KOTAK", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored := repo.banks["KOTAK"]; stored.Status != repository.BankActive || stored.ApprovedBy != "bob" {
+		t.Errorf("approved bank is %s, approved by %q", stored.Status, stored.ApprovedBy)
+	}
+	if len(reloader.reloaded) == 0 || reloader.reloaded[len(reloader.reloaded)-1] != "KOTAK" {
+		t.Errorf("reloaded %v, want the approved bank reloaded", reloader.reloaded)
+	}
+
+	if _, err := s.SetStatus(ctx, "KOTAK", repository.BankSuspended, "bob", ""); !errors.Is(err, ErrInvalidBankRequest) {
+		t.Errorf("suspension without a reason: err = %v, want ErrInvalidBankRequest", err)
+	}
+	if _, err := s.SetStatus(ctx, "KOTAK", repository.BankSuspended, "bob", "settlement default"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"REGISTER KOTAK", "APPROVE KOTAK", "SET_STATUS KOTAK"}
+	if len(repo.audits) != len(want) {
+		t.Fatalf("audits = %v, want %v", repo.audits, want)
+	}
+	for i := range want {
+		if repo.audits[i] != want[i] {
+			t.Errorf("audit %d = %q, want %q", i, repo.audits[i], want[i])
+		}
+	}
+}
+
+func TestMaintenanceWindowTakesBankOutOfService(t *testing.T) {
+	s, repo, _ := newTestBankService(t, &repository.Bank{BankCode: "HDFC", Status: repository.BankActive})
+	ctx := context.Background()
+
+	window := &repository.MaintenanceWindow{
+		BankCode: "HDFC",
+		StartsAt: time.Now().Add(-time.Minute),
+		EndsAt:   time.Now().Add(time.Hour),
+		Reason:   "core banking upgrade",
+	}
+	if err := s.ScheduleMaintenance(ctx, window, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := s.CheckMaintenance(ctx); err != nil || changed != 1 {
+		t.Fatalf("started %d windows, %v; want 1", changed, err)
+	}
+	bank := repo.banks["HDFC"]
+	if bank.Status != repository.BankMaintenance || bank.MaintenanceWindowID == nil || *bank.MaintenanceWindowID != window.WindowID {
+		t.Fatalf("bank is %s for window %v, want MAINTENANCE for window %d", bank.Status, bank.MaintenanceWindowID, window.WindowID)
+	}
+
+	// The window, not an operator, ends it
+	if _, err := s.SetStatus(ctx, "HDFC", repository.BankActive, "bob", ""); !errors.Is(err, ErrBankStatusChange) {
+		t.Errorf("activation during a window: err = %v, want ErrBankStatusChange", err)
+	}
+	if changed, _ := s.CheckMaintenance(ctx); changed != 0 {
+		t.Errorf("changed %d banks mid-window, want none", changed)
+	}
+
+	repo.windows[window.WindowID].EndsAt = time.Now()
+	if changed, err := s.CheckMaintenance(ctx); err != nil || changed != 1 {
+		t.Fatalf("ended %d windows, %v; want 1", changed, err)
+	}
+	if bank := repo.banks["HDFC"]; bank.Status != repository.BankActive || bank.MaintenanceWindowID != nil {
+		t.Errorf("bank is %s for window %v after its window, want ACTIVE", bank.Status, bank.MaintenanceWindowID)
+	}
+}
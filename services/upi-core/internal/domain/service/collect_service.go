@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/upierr"
+	pb "upi-core/pkg/pb"
+	"upi-core/pkg/upi"
+)
+
+var (
+	// ErrInvalidCollectRequest wraps the reason a collect request was
+	// rejected before reaching the database
+	ErrInvalidCollectRequest = errors.New("invalid collect request")
+	// ErrCollectNotFound is returned for an unknown collect ID
+	ErrCollectNotFound = errors.New("collect request not found")
+	// ErrCollectNotPending is returned when answering a collect request that
+	// was already answered or has expired
+	ErrCollectNotPending = errors.New("collect request is not pending")
+	// ErrCollectExpired is returned when a collect request is answered
+	// after its expiry
+	ErrCollectExpired = errors.New("collect request expired")
+)
+
+// maxCollectIDLen is the width of the collect_id column
+const maxCollectIDLen = 35
+
+// TransactionProcessor processes transactions as requested by banks
+type TransactionProcessor interface {
+	ProcessTransaction(ctx context.Context, req *pb.TransactionRequest) (*pb.TransactionResponse, error)
+}
+
+// CallbackNotifier delivers notifications to PSPs' callback URLs
+type CallbackNotifier interface {
+	Deliver(ctx context.Context, url string, payload interface{}) error
+}
+
+// CollectNotification is what the payer's PSP is sent for a new collect
+// request
+type CollectNotification struct {
+	Type        string    `json:"type"`
+	CollectID   string    `json:"collectId"`
+	PayeeVPA    string    `json:"payeeVpa"`
+	PayerVPA    string    `json:"payerVpa"`
+	AmountPaisa int64     `json:"amountPaisa"`
+	Description string    `json:"description,omitempty"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// CollectService handles collect requests: payees asking payers for money
+type CollectService struct {
+	repo         repository.TransactionRepository
+	transactions TransactionProcessor
+	notifier     CallbackNotifier
+	cfg          config.CollectConfig
+	logger       *logrus.Logger
+	now          func() time.Time
+
+	deliveries sync.WaitGroup
+}
+
+// NewCollectService creates a new collect service
+func NewCollectService(repo repository.TransactionRepository, transactions TransactionProcessor, notifier CallbackNotifier, cfg config.CollectConfig, logger *logrus.Logger) *CollectService {
+	if cfg.DefaultTTL <= 0 {
+		cfg.DefaultTTL = 30 * time.Minute
+	}
+	if cfg.MaxTTL < cfg.DefaultTTL {
+		cfg.MaxTTL = cfg.DefaultTTL
+	}
+	return &CollectService{
+		repo:         repo,
+		transactions: transactions,
+		notifier:     notifier,
+		cfg:          cfg,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Create stores a PENDING collect request, expiring after ttl or the default
+// TTL if ttl is 0, and delivers it to the payer's PSP in the background
+func (s *CollectService) Create(ctx context.Context, request *repository.CollectRequest, ttl time.Duration) error {
+	if request.CollectID == "" || len(request.CollectID) > maxCollectIDLen || !isAlnum(request.CollectID) {
+		return fmt.Errorf("%w: collect ID must be 1 to %d letters and digits", ErrInvalidCollectRequest, maxCollectIDLen)
+	}
+	if _, err := upi.ParseVPA(request.PayeeVPA); err != nil {
+		return fmt.Errorf("%w: payee %v", ErrInvalidCollectRequest, err)
+	}
+	if _, err := upi.ParseVPA(request.PayerVPA); err != nil {
+		return fmt.Errorf("%w: payer %v", ErrInvalidCollectRequest, err)
+	}
+	if request.PayerVPA == request.PayeeVPA {
+		return fmt.Errorf("%w: payer and payee VPA cannot be the same", ErrInvalidCollectRequest)
+	}
+	if err := upi.ValidateAmountPaisa(request.AmountPaisa); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCollectRequest, err)
+	}
+	switch request.Type {
+	case "":
+		request.Type = repository.TypeP2P
+	case repository.TypeP2P, repository.TypeP2M:
+	default:
+		return fmt.Errorf("%w: a collect request must be P2P or P2M", ErrInvalidCollectRequest)
+	}
+	if ttl == 0 {
+		ttl = s.cfg.DefaultTTL
+	}
+	if ttl < 0 || ttl > s.cfg.MaxTTL {
+		return fmt.Errorf("%w: expiry must be within %v", ErrInvalidCollectRequest, s.cfg.MaxTTL)
+	}
+
+	payee, err := s.repo.GetVPAMapping(ctx, request.PayeeVPA)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: payee VPA %s is not registered", ErrInvalidCollectRequest, request.PayeeVPA)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve payee VPA: %w", err)
+	}
+	payer, err := s.repo.GetVPAMapping(ctx, request.PayerVPA)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: payer VPA %s is not registered", ErrInvalidCollectRequest, request.PayerVPA)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve payer VPA: %w", err)
+	}
+	request.PayeeBankCode = payee.BankCode
+	request.PayerBankCode = payer.BankCode
+	request.ExpiresAt = s.now().Add(ttl)
+
+	if err := s.repo.CreateCollectRequest(ctx, nil, request); err != nil {
+		if errors.Is(err, repository.ErrCollectRequestExists) {
+			return err
+		}
+		return fmt.Errorf("failed to create collect request: %w", err)
+	}
+	s.audit(ctx, request.CollectID, "CREATE", map[string]interface{}{
+		"payee_vpa":    request.PayeeVPA,
+		"payer_vpa":    request.PayerVPA,
+		"amount_paisa": request.AmountPaisa,
+		"expires_at":   request.ExpiresAt,
+	})
+
+	delivery := *request
+	s.deliveries.Add(1)
+	go func() {
+		defer s.deliveries.Done()
+		s.deliver(context.WithoutCancel(ctx), &delivery)
+	}()
+	return nil
+}
+
+// Close waits for collect requests being delivered
+func (s *CollectService) Close() {
+	s.deliveries.Wait()
+}
+
+// deliver sends a collect request to the payer bank's callback URL. A
+// request that cannot be delivered is left for the payer's PSP to find
+// with GetCollect.
+func (s *CollectService) deliver(ctx context.Context, request *repository.CollectRequest) {
+	logger := s.logger.WithFields(logrus.Fields{
+		"collect_id": request.CollectID,
+		"bank_code":  request.PayerBankCode,
+	})
+
+	bank, err := s.repo.GetBankByCode(ctx, request.PayerBankCode)
+	if err != nil {
+		logger.WithError(err).Error("Failed to load payer bank of collect request")
+		return
+	}
+	if bank.CallbackURL == "" {
+		logger.Warn("Payer bank has no callback URL, collect request not delivered")
+		return
+	}
+
+	err = s.notifier.Deliver(ctx, bank.CallbackURL, &CollectNotification{
+		Type:        "COLLECT_REQUEST",
+		CollectID:   request.CollectID,
+		PayeeVPA:    request.PayeeVPA,
+		PayerVPA:    request.PayerVPA,
+		AmountPaisa: request.AmountPaisa,
+		Description: request.Description,
+		ExpiresAt:   request.ExpiresAt,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to deliver collect request")
+		return
+	}
+	if err := s.repo.MarkCollectRequestDelivered(ctx, request.CollectID); err != nil {
+		logger.WithError(err).Warn("Failed to record collect request delivery")
+	}
+}
+
+// CollectResponse is the payer's answer to a collect request. An approval
+// carries the payer bank's signature of the transaction paying the request,
+// as for any transaction it initiates, and the initiation time signed.
+type CollectResponse struct {
+	Approve     bool
+	Reason      string
+	Signature   string
+	InitiatedAt time.Time
+}
+
+// Respond records the payer's answer to a pending collect request. On
+// approval the request is paid with a transaction of the same ID, whose
+// response is returned. A request whose approval has an invalid signature
+// stays pending.
+func (s *CollectService) Respond(ctx context.Context, collectID string, response CollectResponse) (*repository.CollectRequest, *pb.TransactionResponse, error) {
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.repo.RollbackTransaction(tx)
+
+	request, err := s.repo.LockCollectRequest(ctx, tx, collectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrCollectNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load collect request: %w", err)
+	}
+	if request.Status != repository.CollectPending {
+		return nil, nil, fmt.Errorf("%w: collect request %s is %s", ErrCollectNotPending, collectID, request.Status)
+	}
+
+	if !s.now().Before(request.ExpiresAt) {
+		if err := s.finish(ctx, tx, request, repository.CollectExpired, string(upierr.CollectExpired), ""); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrCollectExpired
+	}
+
+	if !response.Approve {
+		if err := s.finish(ctx, tx, request, repository.CollectDeclined, string(upierr.DeclinedByCustomer), response.Reason); err != nil {
+			return nil, nil, err
+		}
+		s.audit(ctx, collectID, "DECLINE", map[string]interface{}{"reason": response.Reason})
+		return request, nil, nil
+	}
+
+	// The row stays locked while the transaction runs, so the request can't
+	// expire or be answered twice meanwhile
+	transaction, err := s.transactions.ProcessTransaction(ctx, &pb.TransactionRequest{
+		TransactionId: request.CollectID,
+		PayerVpa:      request.PayerVPA,
+		PayeeVpa:      request.PayeeVPA,
+		AmountPaisa:   request.AmountPaisa,
+		Currency:      "INR",
+		Type:          pb.TransactionType(pb.TransactionType_value["TRANSACTION_TYPE_"+string(request.Type)]),
+		Description:   request.Description,
+		Reference:     request.CollectID,
+		Signature:     response.Signature,
+		InitiatedAt:   timestamppb.New(response.InitiatedAt),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to process transaction: %w", err)
+	}
+
+	switch {
+	case transaction.Status == pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS:
+		err = s.finish(ctx, tx, request, repository.CollectApproved, "", "")
+	case transaction.ErrorCode == string(upierr.SignatureInvalid):
+		return nil, nil, fmt.Errorf("%w: %s", crypto.ErrInvalidSignature, transaction.ErrorMessage)
+	case transaction.ErrorCode == string(upierr.TransactionPending):
+		// Its outcome is decided later; the request is left unanswered
+		// rather than recorded as failed
+		return nil, nil, fmt.Errorf("%w: transaction of collect request %s was interrupted", ErrShuttingDown, collectID)
+	default:
+		err = s.finish(ctx, tx, request, repository.CollectFailed, transaction.ErrorCode, transaction.ErrorMessage)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	s.audit(ctx, collectID, "APPROVE", map[string]interface{}{"status": request.Status})
+	return request, transaction, nil
+}
+
+// finish records a collect request's outcome and commits tx
+func (s *CollectService) finish(ctx context.Context, tx *sql.Tx, request *repository.CollectRequest, status repository.CollectStatus, errorCode, errorMessage string) error {
+	if err := s.repo.FinishCollectRequest(ctx, tx, request.CollectID, status, errorCode, errorMessage); err != nil {
+		return fmt.Errorf("failed to update collect request: %w", err)
+	}
+	if err := s.repo.CommitTransaction(tx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	now := s.now()
+	request.Status = status
+	request.ErrorCode, request.ErrorMessage = errorCode, errorMessage
+	request.RespondedAt = &now
+	return nil
+}
+
+// Get returns a collect request
+func (s *CollectService) Get(ctx context.Context, collectID string) (*repository.CollectRequest, error) {
+	request, err := s.repo.GetCollectRequest(ctx, collectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCollectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load collect request: %w", err)
+	}
+	return request, nil
+}
+
+func (s *CollectService) audit(ctx context.Context, collectID, action string, values map[string]interface{}) {
+	if err := s.repo.LogAudit(ctx, nil, "collect_request", collectID, action, "SYSTEM", nil, values, ""); err != nil {
+		s.logger.WithError(err).WithField("collect_id", collectID).Warn("Failed to log collect request audit entry")
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/upierr"
+	pb "upi-core/pkg/pb"
+)
+
+// ProcessTransaction lets fakeProcessor pay collect requests too
+func (p *fakeProcessor) ProcessTransaction(ctx context.Context, req *pb.TransactionRequest) (*pb.TransactionResponse, error) {
+	return p.ProcessPreauthorizedTransaction(ctx, req)
+}
+
+// fakeNotifier records the callbacks it is asked to deliver
+type fakeNotifier struct {
+	err      error
+	urls     []string
+	payloads []interface{}
+}
+
+func (n *fakeNotifier) Deliver(ctx context.Context, url string, payload interface{}) error {
+	n.urls = append(n.urls, url)
+	n.payloads = append(n.payloads, payload)
+	return n.err
+}
+
+var collectNow = time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+
+func newTestCollectService(repo *fakeRepository, processor *fakeProcessor, notifier *fakeNotifier) *CollectService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repo.banks = map[string]*repository.Bank{
+		"HDFC": {BankCode: "HDFC", CallbackURL: "https://psp.example.com/collect"},
+	}
+	s := NewCollectService(repo, processor, notifier, config.CollectConfig{DefaultTTL: 30 * time.Minute, MaxTTL: 24 * time.Hour}, logger)
+	s.now = func() time.Time { return collectNow }
+	return s
+}
+
+func newCollectRequest(id string) *repository.CollectRequest {
+	return &repository.CollectRequest{
+		CollectID:   id,
+		PayeeVPA:    "shop@icici",
+		PayerVPA:    "alice@hdfc",
+		AmountPaisa: 25000,
+		Description: "Order 42",
+	}
+}
+
+func TestCollectRequestIsDeliveredAndPaid(t *testing.T) {
+	repo := newFakeRepository()
+	processor := &fakeProcessor{}
+	notifier := &fakeNotifier{}
+	s := newTestCollectService(repo, processor, notifier)
+	ctx := context.Background()
+
+	for name, change := range map[string]func(*repository.CollectRequest){
+		"bad ID":    func(c *repository.CollectRequest) { c.CollectID = "COL-1" },
+		"same VPAs": func(c *repository.CollectRequest) { c.PayerVPA = c.PayeeVPA },
+		"no amount": func(c *repository.CollectRequest) { c.AmountPaisa = 0 },
+		"refund":    func(c *repository.CollectRequest) { c.Type = repository.TypeRefund },
+		"bad payer": func(c *repository.CollectRequest) { c.PayerVPA = "alice" },
+		"bad payee": func(c *repository.CollectRequest) { c.PayeeVPA = "@icici" },
+	} {
+		request := newCollectRequest("COL1")
+		change(request)
+		if err := s.Create(ctx, request, 0); !errors.Is(err, ErrInvalidCollectRequest) {
+			t.Errorf("%s: err = %v, want ErrInvalidCollectRequest", name, err)
+		}
+	}
+	if err := s.Create(ctx, newCollectRequest("COL1"), 48*time.Hour); !errors.Is(err, ErrInvalidCollectRequest) {
+		t.Errorf("TTL over the maximum: err = %v", err)
+	}
+
+	request := newCollectRequest("COL1")
+	if err := s.Create(ctx, request, 0); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if request.Type != repository.TypeP2P || !request.ExpiresAt.Equal(collectNow.Add(30*time.Minute)) || request.PayerBankCode != "HDFC" {
+		t.Errorf("created collect request = %+v", request)
+	}
+	if err := s.Create(ctx, newCollectRequest("COL1"), 0); !errors.Is(err, repository.ErrCollectRequestExists) {
+		t.Errorf("duplicate collect ID: err = %v", err)
+	}
+
+	s.Close()
+	if len(notifier.urls) != 1 || notifier.urls[0] != "https://psp.example.com/collect" {
+		t.Fatalf("callbacks = %v", notifier.urls)
+	}
+	if n := notifier.payloads[0].(*CollectNotification); n.CollectID != "COL1" || n.PayerVPA != "alice@hdfc" || n.AmountPaisa != 25000 {
+		t.Errorf("notification = %+v", n)
+	}
+	if repo.collects["COL1"].DeliveredAt == nil {
+		t.Errorf("delivery not recorded")
+	}
+
+	answered, transaction, err := s.Respond(ctx, "COL1", CollectResponse{Approve: true, Signature: "sig", InitiatedAt: collectNow})
+	if err != nil || answered.Status != repository.CollectApproved || transaction.Status != pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
+		t.Fatalf("Respond = %+v, %+v, %v", answered, transaction, err)
+	}
+	if req := processor.requests[0]; req.TransactionId != "COL1" || req.PayerVpa != "alice@hdfc" || req.PayeeVpa != "shop@icici" || req.Type != pb.TransactionType_TRANSACTION_TYPE_P2P || req.Signature != "sig" {
+		t.Errorf("transaction = %+v", req)
+	}
+	if got := repo.collects["COL1"].Status; got != repository.CollectApproved {
+		t.Errorf("stored status = %s, want APPROVED", got)
+	}
+	if _, _, err := s.Respond(ctx, "COL1", CollectResponse{}); !errors.Is(err, ErrCollectNotPending) {
+		t.Errorf("answering twice: err = %v", err)
+	}
+	if _, _, err := s.Respond(ctx, "COL2", CollectResponse{}); !errors.Is(err, ErrCollectNotFound) {
+		t.Errorf("answering an unknown request: err = %v", err)
+	}
+}
+
+func TestCollectRequestOutcomes(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		response  CollectResponse
+		processed *pb.TransactionResponse
+		later     time.Duration
+		want      repository.CollectStatus
+		wantErr   error
+	}{
+		{name: "declined", response: CollectResponse{Reason: "not mine"}, want: repository.CollectDeclined},
+		{name: "expired", response: CollectResponse{Approve: true}, later: 30 * time.Minute, want: repository.CollectExpired, wantErr: ErrCollectExpired},
+		{
+			name:      "payment failed",
+			response:  CollectResponse{Approve: true},
+			processed: &pb.TransactionResponse{Status: pb.TransactionStatus_TRANSACTION_STATUS_FAILED, ErrorCode: "INSUFFICIENT_FUNDS"},
+			want:      repository.CollectFailed,
+		},
+		{
+			name:      "bad signature",
+			response:  CollectResponse{Approve: true},
+			processed: &pb.TransactionResponse{Status: pb.TransactionStatus_TRANSACTION_STATUS_FAILED, ErrorCode: string(upierr.SignatureInvalid)},
+			want:      repository.CollectPending,
+			wantErr:   crypto.ErrInvalidSignature,
+		},
+	} {
+		repo := newFakeRepository()
+		s := newTestCollectService(repo, &fakeProcessor{response: tc.processed}, &fakeNotifier{})
+		if err := s.Create(context.Background(), newCollectRequest("COL1"), 0); err != nil {
+			t.Fatal(err)
+		}
+		s.Close()
+		s.now = func() time.Time { return collectNow.Add(tc.later) }
+
+		if _, _, err := s.Respond(context.Background(), "COL1", tc.response); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.wantErr)
+		}
+		if got := repo.collects["COL1"]; got.Status != tc.want {
+			t.Errorf("%s: status = %s, want %s", tc.name, got.Status, tc.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// checkpointTimeout is how long Drain waits for checkpointed sagas to stop.
+// A saga stops once its bank call in progress, bounded by the bank client's
+// timeouts, returns.
+const checkpointTimeout = 10 * time.Second
+
+// ErrShuttingDown is returned for a transaction submitted while the service
+// drains for shutdown; it can be retried against another instance
+var ErrShuttingDown = errors.New("service is shutting down")
+
+// errSagaCheckpointed is returned by a saga stopped at a step boundary for
+// shutdown; its transaction stays PENDING for the SagaRecovery to resume
+var errSagaCheckpointed = errors.New("saga checkpointed for shutdown")
+
+// inFlight tracks the transactions being processed, so that shutdown can
+// wait for them
+type inFlight struct {
+	mu         sync.Mutex
+	active     int
+	draining   bool
+	idle       chan struct{} // Closed once draining with none active
+	checkpoint chan struct{} // Closed to stop sagas at their next step
+}
+
+func newInFlight() *inFlight {
+	return &inFlight{idle: make(chan struct{}), checkpoint: make(chan struct{})}
+}
+
+// enter counts a transaction in, unless draining has begun
+func (f *inFlight) enter() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.draining {
+		return false
+	}
+	f.active++
+	return true
+}
+
+func (f *inFlight) leave() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active--
+	if f.draining && f.active == 0 {
+		close(f.idle)
+	}
+}
+
+// drain turns new transactions away; it returns how many are in flight
+func (f *inFlight) drain() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.draining {
+		f.draining = true
+		if f.active == 0 {
+			close(f.idle)
+		}
+	}
+	return f.active
+}
+
+// wait reports whether the transactions in flight finished within timeout
+func (f *inFlight) wait(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-f.idle:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (f *inFlight) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// forceCheckpoint has sagas stop before their next bank call
+func (f *inFlight) forceCheckpoint() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	select {
+	case <-f.checkpoint:
+	default:
+		close(f.checkpoint)
+	}
+}
+
+func (f *inFlight) checkpointing() bool {
+	select {
+	case <-f.checkpoint:
+		return true
+	default:
+		return false
+	}
+}
+
+// Drain prepares the service for shutdown. It turns new transactions away
+// with ErrShuttingDown and waits up to timeout for those in flight to
+// finish. Sagas still running then are checkpointed: each stops before its
+// next bank call with its step recorded, leaving its transaction PENDING
+// for the SagaRecovery of another instance to resume.
+func (s *TransactionService) Drain(timeout time.Duration) {
+	active := s.inFlight.drain()
+	if active == 0 {
+		return
+	}
+	s.logger.WithField("in_flight", active).Info("Waiting for in-flight transactions to finish")
+	if s.inFlight.wait(timeout) {
+		s.logger.Info("In-flight transactions finished")
+		return
+	}
+
+	s.logger.WithField("in_flight", s.inFlight.count()).Warn("Drain timeout exceeded, checkpointing in-flight sagas")
+	s.inFlight.forceCheckpoint()
+	if !s.inFlight.wait(checkpointTimeout) {
+		s.logger.WithField("in_flight", s.inFlight.count()).Error("Sagas still running at shutdown; their last recorded steps are resumed by the SagaRecovery")
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/domain/repository"
+)
+
+func TestDrainWaitsForInFlightTransactions(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &TransactionService{inFlight: newInFlight(), logger: logger}
+
+	if !s.inFlight.enter() {
+		t.Fatal("transaction turned away before draining")
+	}
+	drained := make(chan struct{})
+	go func() {
+		s.Drain(time.Minute)
+		close(drained)
+	}()
+
+	// Draining turns new transactions away at once
+	deadline := time.Now().Add(time.Second)
+	for s.inFlight.enter() {
+		s.inFlight.leave()
+		if time.Now().After(deadline) {
+			t.Fatal("transactions still accepted while draining")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := s.ProcessTransaction(context.Background(), nil); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("transaction while draining: err = %v, want ErrShuttingDown", err)
+	}
+
+	select {
+	case <-drained:
+		t.Fatal("drain returned with a transaction in flight")
+	case <-time.After(10 * time.Millisecond):
+	}
+	s.inFlight.leave()
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("drain did not return once the transaction finished")
+	}
+	if s.inFlight.checkpointing() {
+		t.Error("sagas checkpointed although they finished in time")
+	}
+}
+
+func TestCheckpointedSagaStopsBeforeBankCall(t *testing.T) {
+	for _, step := range []repository.SagaStep{repository.SagaDebitDone, repository.SagaCompensating} {
+		repo := interruptedTransaction(step, "HDFC123")
+		banks := &fakeBankClients{}
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		tracker := newInFlight()
+		tracker.forceCheckpoint()
+		s := &saga{repo: repo, bankClients: banks, inFlight: tracker, logger: logger}
+
+		result := &TransactionResult{Transaction: repo.transactions["TXN1"]}
+		payer := &repository.VPAMapping{VPA: "alice@hdfc", BankCode: "HDFC", AccountNumber: "1"}
+		payee := &repository.VPAMapping{VPA: "bob@sbi", BankCode: "SBI", AccountNumber: "2"}
+		err := s.run(context.Background(), result, payer, payee, repo.sagas["TXN1"])
+		if !errors.Is(err, errSagaCheckpointed) {
+			t.Fatalf("%s: err = %v, want errSagaCheckpointed", step, err)
+		}
+		if len(banks.requests) != 0 {
+			t.Errorf("%s: bank calls = %d, want none", step, len(banks.requests))
+		}
+		if got := repo.transactions["TXN1"].Status; got != repository.StatusPending {
+			t.Errorf("%s: transaction is %s, want it left PENDING", step, got)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+var (
+	// ErrInvalidFeeRule wraps the reason a fee rule was rejected
+	ErrInvalidFeeRule = errors.New("invalid fee rule")
+	// ErrFeeRuleNotFound is returned for an unknown fee rule ID
+	ErrFeeRuleNotFound = errors.New("fee rule not found")
+)
+
+// Fees are the fees charged on a transaction, in paisa
+type Fees struct {
+	SwitchPaisa int64
+	BankPaisa   int64
+}
+
+// FeeService manages the fee rules and works out the fees of transactions
+// from them. The rules are cached: reloaded on every change made through
+// this service, and every refresh interval for changes made by other
+// instances.
+type FeeService struct {
+	repo   repository.TransactionRepository
+	cfg    config.FeesConfig
+	logger *logrus.Logger
+
+	mu    sync.RWMutex
+	rules []*repository.FeeRule
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewFeeService creates a fee service with no rules; Refresh or Start
+// loads them
+func NewFeeService(repo repository.TransactionRepository, cfg config.FeesConfig, logger *logrus.Logger) *FeeService {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = time.Minute
+	}
+	return &FeeService{repo: repo, cfg: cfg, logger: logger}
+}
+
+// Calculate works out the fees of a transaction of amountPaisa, debited at
+// payerBankCode, from the most specific rule of each fee type that matches
+// it. A fee without a matching rule is nothing.
+func (s *FeeService) Calculate(payerBankCode string, transactionType repository.TransactionType, amountPaisa int64) Fees {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Fees{
+		SwitchPaisa: feeFor(s.rules, repository.FeeSwitch, payerBankCode, transactionType, amountPaisa),
+		BankPaisa:   feeFor(s.rules, repository.FeeBank, payerBankCode, transactionType, amountPaisa),
+	}
+}
+
+// feeFor applies the most specific matching rule of a fee type: one for
+// the bank beats one for every bank, then one for the transaction type
+// beats one for every type. Of equally specific rules, the oldest wins.
+func feeFor(rules []*repository.FeeRule, feeType repository.FeeType, bankCode string, transactionType repository.TransactionType, amountPaisa int64) int64 {
+	var best *repository.FeeRule
+	bestScore := -1
+	for _, rule := range rules {
+		if rule.FeeType != feeType || !ruleMatches(rule, bankCode, transactionType, amountPaisa) {
+			continue
+		}
+		score := 0
+		if rule.BankCode != "" {
+			score += 2
+		}
+		if rule.TransactionType != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	if best == nil {
+		return 0
+	}
+	return ruleFee(best, amountPaisa)
+}
+
+func ruleMatches(rule *repository.FeeRule, bankCode string, transactionType repository.TransactionType, amountPaisa int64) bool {
+	if rule.BankCode != "" && rule.BankCode != bankCode {
+		return false
+	}
+	if rule.TransactionType != "" && rule.TransactionType != transactionType {
+		return false
+	}
+	return amountPaisa >= rule.MinAmountPaisa && (rule.MaxAmountPaisa == nil || amountPaisa < *rule.MaxAmountPaisa)
+}
+
+// ruleFee is a rule's fixed fee plus its percentage of the amount, rounded
+// down, held between its minimum and maximum fees
+func ruleFee(rule *repository.FeeRule, amountPaisa int64) int64 {
+	fee := rule.FixedPaisa + amountPaisa*rule.PercentBps/10000
+	if rule.MaxFeePaisa != nil && fee > *rule.MaxFeePaisa {
+		fee = *rule.MaxFeePaisa
+	}
+	if fee < rule.MinFeePaisa {
+		fee = rule.MinFeePaisa
+	}
+	return fee
+}
+
+// Refresh reloads the rules from the database
+func (s *FeeService) Refresh(ctx context.Context) error {
+	rules, err := s.repo.ListFeeRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list fee rules: %w", err)
+	}
+	s.mu.Lock()
+	s.rules = rules
+	s.mu.Unlock()
+	return nil
+}
+
+// List returns every fee rule, in ID order
+func (s *FeeService) List(ctx context.Context) ([]*repository.FeeRule, error) {
+	rules, err := s.repo.ListFeeRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fee rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Create stores a new fee rule, setting its ID
+func (s *FeeService) Create(ctx context.Context, rule *repository.FeeRule) error {
+	if err := s.validate(ctx, rule); err != nil {
+		return err
+	}
+	if err := s.repo.CreateFeeRule(ctx, rule); err != nil {
+		return fmt.Errorf("failed to create fee rule: %w", err)
+	}
+
+	s.audit(ctx, rule.RuleID, "CREATE", nil, feeRuleValues(rule))
+	s.reload(ctx)
+	return nil
+}
+
+// Update replaces an existing fee rule
+func (s *FeeService) Update(ctx context.Context, rule *repository.FeeRule) error {
+	old, err := s.get(ctx, rule.RuleID)
+	if err != nil {
+		return err
+	}
+	if err := s.validate(ctx, rule); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateFeeRule(ctx, rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %d", ErrFeeRuleNotFound, rule.RuleID)
+		}
+		return fmt.Errorf("failed to update fee rule: %w", err)
+	}
+
+	s.audit(ctx, rule.RuleID, "UPDATE", feeRuleValues(old), feeRuleValues(rule))
+	s.reload(ctx)
+	return nil
+}
+
+// Delete removes a fee rule
+func (s *FeeService) Delete(ctx context.Context, ruleID int64) error {
+	old, err := s.get(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteFeeRule(ctx, ruleID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %d", ErrFeeRuleNotFound, ruleID)
+		}
+		return fmt.Errorf("failed to delete fee rule: %w", err)
+	}
+
+	s.audit(ctx, ruleID, "DELETE", feeRuleValues(old), nil)
+	s.reload(ctx)
+	return nil
+}
+
+func (s *FeeService) get(ctx context.Context, ruleID int64) (*repository.FeeRule, error) {
+	rule, err := s.repo.GetFeeRule(ctx, ruleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %d", ErrFeeRuleNotFound, ruleID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fee rule: %w", err)
+	}
+	return rule, nil
+}
+
+// validate checks a rule is well formed, and that its slab does not
+// overlap that of another rule for the same fee, bank and transaction type,
+// which would leave it unclear which applies
+func (s *FeeService) validate(ctx context.Context, rule *repository.FeeRule) error {
+	switch rule.FeeType {
+	case repository.FeeSwitch, repository.FeeBank:
+	default:
+		return fmt.Errorf("%w: unknown fee type %q", ErrInvalidFeeRule, rule.FeeType)
+	}
+	switch rule.TransactionType {
+	case "", repository.TypeP2P, repository.TypeP2M, repository.TypeM2P, repository.TypeRefund:
+	default:
+		return fmt.Errorf("%w: unknown transaction type %q", ErrInvalidFeeRule, rule.TransactionType)
+	}
+	if rule.MinAmountPaisa < 0 || (rule.MaxAmountPaisa != nil && *rule.MaxAmountPaisa <= rule.MinAmountPaisa) {
+		return fmt.Errorf("%w: slab must run from a non-negative amount up to a higher one", ErrInvalidFeeRule)
+	}
+	if rule.FixedPaisa < 0 || rule.MinFeePaisa < 0 {
+		return fmt.Errorf("%w: fees cannot be negative", ErrInvalidFeeRule)
+	}
+	if rule.PercentBps < 0 || rule.PercentBps > 10000 {
+		return fmt.Errorf("%w: percentage must be 0 to 10000 basis points", ErrInvalidFeeRule)
+	}
+	if rule.MaxFeePaisa != nil && *rule.MaxFeePaisa < rule.MinFeePaisa {
+		return fmt.Errorf("%w: maximum fee is below the minimum fee", ErrInvalidFeeRule)
+	}
+
+	if rule.BankCode != "" {
+		if _, err := s.repo.GetBankByCode(ctx, rule.BankCode); errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: bank %s is not registered", ErrInvalidFeeRule, rule.BankCode)
+		} else if err != nil {
+			return fmt.Errorf("failed to load bank %s: %w", rule.BankCode, err)
+		}
+	}
+
+	rules, err := s.repo.ListFeeRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list fee rules: %w", err)
+	}
+	for _, other := range rules {
+		if other.RuleID == rule.RuleID || other.FeeType != rule.FeeType || other.BankCode != rule.BankCode || other.TransactionType != rule.TransactionType {
+			continue
+		}
+		if slabsOverlap(rule, other) {
+			return fmt.Errorf("%w: slab overlaps that of rule %d", ErrInvalidFeeRule, other.RuleID)
+		}
+	}
+	return nil
+}
+
+func slabsOverlap(a, b *repository.FeeRule) bool {
+	aBelowB := a.MaxAmountPaisa != nil && *a.MaxAmountPaisa <= b.MinAmountPaisa
+	bBelowA := b.MaxAmountPaisa != nil && *b.MaxAmountPaisa <= a.MinAmountPaisa
+	return !aBelowB && !bBelowA
+}
+
+// reload refreshes the rules after a change. A failure is logged; the
+// change is picked up by the next periodic refresh.
+func (s *FeeService) reload(ctx context.Context) {
+	if err := s.Refresh(context.WithoutCancel(ctx)); err != nil {
+		s.logger.WithError(err).Warn("Failed to reload fee rules after a change")
+	}
+}
+
+func (s *FeeService) audit(ctx context.Context, ruleID int64, action string, oldValues, newValues map[string]interface{}) {
+	if err := s.repo.LogAudit(ctx, nil, "fee_rule", strconv.FormatInt(ruleID, 10), action, "SYSTEM", oldValues, newValues, ""); err != nil {
+		s.logger.WithError(err).WithField("rule_id", ruleID).Warn("Failed to audit fee rule change")
+	}
+}
+
+func feeRuleValues(rule *repository.FeeRule) map[string]interface{} {
+	return map[string]interface{}{
+		"fee_type":         rule.FeeType,
+		"bank_code":        rule.BankCode,
+		"transaction_type": rule.TransactionType,
+		"min_amount_paisa": rule.MinAmountPaisa,
+		"max_amount_paisa": rule.MaxAmountPaisa,
+		"fixed_paisa":      rule.FixedPaisa,
+		"percent_bps":      rule.PercentBps,
+		"min_fee_paisa":    rule.MinFeePaisa,
+		"max_fee_paisa":    rule.MaxFeePaisa,
+	}
+}
+
+// Start loads the rules and keeps reloading them every refresh interval
+// until Close
+func (s *FeeService) Start(ctx context.Context) error {
+	if err := s.Refresh(ctx); err != nil {
+		return err
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+	return nil
+}
+
+func (s *FeeService) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.stop
+		cancel()
+	}()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				s.logger.WithError(err).Error("Failed to refresh fee rules")
+			}
+		}
+	}
+}
+
+// Close stops the background refreshes
+func (s *FeeService) Close() {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+func newTestFeeService(t *testing.T, repo *fakeRepository, rules ...*repository.FeeRule) *FeeService {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := NewFeeService(repo, config.FeesConfig{}, logger)
+	for _, rule := range rules {
+		if err := s.Create(context.Background(), rule); err != nil {
+			t.Fatalf("create %+v: %v", rule, err)
+		}
+	}
+	return s
+}
+
+func paisa(p int64) *int64 {
+	return &p
+}
+
+func TestFeeServiceAppliesMostSpecificRule(t *testing.T) {
+	repo := newFakeRepository()
+	repo.banks = map[string]*repository.Bank{"HDFC": {BankCode: "HDFC"}, "SBI": {BankCode: "SBI"}}
+	s := newTestFeeService(t, repo,
+		// Default: 0.1%, at least a paisa
+		&repository.FeeRule{FeeType: repository.FeeSwitch, PercentBps: 10, MinFeePaisa: 1},
+		// Merchant payments: ₹2 flat up to ₹2,000, then 0.2% capped at ₹50
+		&repository.FeeRule{FeeType: repository.FeeSwitch, TransactionType: repository.TypeP2M, MaxAmountPaisa: paisa(200000), FixedPaisa: 200},
+		&repository.FeeRule{FeeType: repository.FeeSwitch, TransactionType: repository.TypeP2M, MinAmountPaisa: 200000, PercentBps: 20, MaxFeePaisa: paisa(5000)},
+		// HDFC payers pay no switch fee on anything
+		&repository.FeeRule{FeeType: repository.FeeSwitch, BankCode: "HDFC"},
+		&repository.FeeRule{FeeType: repository.FeeBank, PercentBps: 5, MinFeePaisa: 1},
+	)
+
+	for _, tc := range []struct {
+		bank   string
+		typ    repository.TransactionType
+		amount int64
+		want   Fees
+	}{
+		{"SBI", repository.TypeP2P, 100000, Fees{SwitchPaisa: 100, BankPaisa: 50}},
+		{"SBI", repository.TypeP2P, 500, Fees{SwitchPaisa: 1, BankPaisa: 1}},
+		{"SBI", repository.TypeP2M, 199999, Fees{SwitchPaisa: 200, BankPaisa: 99}},
+		{"SBI", repository.TypeP2M, 200000, Fees{SwitchPaisa: 400, BankPaisa: 100}},
+		{"SBI", repository.TypeP2M, 10000000, Fees{SwitchPaisa: 5000, BankPaisa: 5000}},
+		{"HDFC", repository.TypeP2M, 200000, Fees{SwitchPaisa: 0, BankPaisa: 100}},
+	} {
+		if got := s.Calculate(tc.bank, tc.typ, tc.amount); got != tc.want {
+			t.Errorf("%s %s %d: fees = %+v, want %+v", tc.bank, tc.typ, tc.amount, got, tc.want)
+		}
+	}
+}
+
+func TestFeeServiceValidatesRules(t *testing.T) {
+	repo := newFakeRepository()
+	repo.banks = map[string]*repository.Bank{"HDFC": {BankCode: "HDFC"}}
+	s := newTestFeeService(t, repo,
+		&repository.FeeRule{FeeType: repository.FeeSwitch, BankCode: "HDFC", MaxAmountPaisa: paisa(100000), PercentBps: 10},
+	)
+
+	for name, rule := range map[string]*repository.FeeRule{
+		"unknown fee type":   {FeeType: "OTHER"},
+		"empty slab":         {FeeType: repository.FeeBank, MinAmountPaisa: 100, MaxAmountPaisa: paisa(100)},
+		"percentage over 1":  {FeeType: repository.FeeBank, PercentBps: 10001},
+		"cap below minimum":  {FeeType: repository.FeeBank, MinFeePaisa: 10, MaxFeePaisa: paisa(5)},
+		"unregistered bank":  {FeeType: repository.FeeBank, BankCode: "NOPE"},
+		"overlapping slab":   {FeeType: repository.FeeSwitch, BankCode: "HDFC", MinAmountPaisa: 50000},
+		"unknown trans type": {FeeType: repository.FeeBank, TransactionType: "GIFT"},
+	} {
+		if err := s.Create(context.Background(), rule); !errors.Is(err, ErrInvalidFeeRule) {
+			t.Errorf("%s: err = %v, want ErrInvalidFeeRule", name, err)
+		}
+	}
+
+	// Adjacent slabs do not overlap, and a rule may be moved within its own
+	next := &repository.FeeRule{FeeType: repository.FeeSwitch, BankCode: "HDFC", MinAmountPaisa: 100000}
+	if err := s.Create(context.Background(), next); err != nil {
+		t.Fatalf("adjacent slab rejected: %v", err)
+	}
+	next.MinAmountPaisa = 100001
+	if err := s.Update(context.Background(), next); err != nil {
+		t.Fatalf("update rejected: %v", err)
+	}
+}
+
+func TestFeeServiceReloadsAfterChanges(t *testing.T) {
+	repo := newFakeRepository()
+	rule := &repository.FeeRule{FeeType: repository.FeeSwitch, PercentBps: 10}
+	s := newTestFeeService(t, repo, rule)
+	if got := s.Calculate("HDFC", repository.TypeP2P, 10000); got.SwitchPaisa != 10 {
+		t.Fatalf("switch fee = %d, want 10", got.SwitchPaisa)
+	}
+
+	rule.PercentBps = 20
+	if err := s.Update(context.Background(), rule); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Calculate("HDFC", repository.TypeP2P, 10000); got.SwitchPaisa != 20 {
+		t.Errorf("switch fee after update = %d, want 20", got.SwitchPaisa)
+	}
+
+	if err := s.Delete(context.Background(), rule.RuleID); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Calculate("HDFC", repository.TypeP2P, 10000); got.SwitchPaisa != 0 {
+		t.Errorf("switch fee after delete = %d, want 0", got.SwitchPaisa)
+	}
+	if err := s.Delete(context.Background(), rule.RuleID); !errors.Is(err, ErrFeeRuleNotFound) {
+		t.Errorf("second delete: err = %v, want ErrFeeRuleNotFound", err)
+	}
+	if len(repo.audits) != 3 {
+		t.Errorf("audits = %v, want create, update and delete", repo.audits)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/upierr"
+)
+
+// The limits a payer VPA's transactions are held to
+const (
+	LimitTransactionAmount = "TRANSACTION_AMOUNT"
+	LimitDailyAmount       = "DAILY_AMOUNT"
+	LimitDailyCount        = "DAILY_COUNT"
+)
+
+// limitDayTTL is how long a day's totals are kept, long enough to outlast
+// the day in every time zone
+const limitDayTTL = 48 * time.Hour
+
+// LimitExceededError reports the limit a transaction would exceed
+type LimitExceededError struct {
+	Limit     string // One of the Limit constants
+	Max       int64  // The limit, in paisa or transactions
+	Used      int64  // What the payer used of it today before the transaction
+	Requested int64  // What the transaction would have used of it
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.Limit == LimitDailyCount {
+		return fmt.Sprintf("%s limit of %d transactions exceeded: %d made today", e.Limit, e.Max, e.Used)
+	}
+	if e.Limit == LimitTransactionAmount {
+		return fmt.Sprintf("%s limit of %d paisa exceeded: %d paisa requested", e.Limit, e.Max, e.Requested)
+	}
+	return fmt.Sprintf("%s limit of %d paisa exceeded: %d paisa sent today, %d paisa requested", e.Limit, e.Max, e.Used, e.Requested)
+}
+
+// Code returns the error code of a transaction over the limit
+func (e *LimitExceededError) Code() upierr.Code {
+	switch e.Limit {
+	case LimitDailyAmount:
+		return upierr.DailyAmountExceeded
+	case LimitDailyCount:
+		return upierr.DailyCountExceeded
+	default:
+		return upierr.TransactionLimitExceeded
+	}
+}
+
+// LimitStore keeps each payer VPA's daily totals
+type LimitStore interface {
+	ReserveDailyLimit(ctx context.Context, vpa, day string, amountPaisa, maxAmountPaisa, maxCount int64, ttl time.Duration) (bool, int64, int64, error)
+	ReleaseDailyLimit(ctx context.Context, vpa, day string, amountPaisa int64) error
+}
+
+// TransactionLimits holds payer VPAs to the amount of a transaction and the
+// amount and number of transactions a day, days being days in India. The
+// daily totals are counted in Redis, shared by all instances of the switch.
+type TransactionLimits struct {
+	store  LimitStore
+	limits map[string]config.VPALimitConfig
+	def    config.VPALimitConfig
+	logger *logrus.Logger
+}
+
+// NewTransactionLimits creates the limits of cfg. VPAs are matched without
+// regard to case.
+func NewTransactionLimits(store LimitStore, cfg config.LimitsConfig, logger *logrus.Logger) *TransactionLimits {
+	l := &TransactionLimits{
+		store:  store,
+		limits: make(map[string]config.VPALimitConfig, len(cfg.VPAs)),
+		def:    cfg.Default,
+		logger: logger,
+	}
+	for _, limit := range cfg.VPAs {
+		l.limits[strings.ToLower(limit.VPA)] = limit
+	}
+	return l
+}
+
+// LimitReservation is a transaction counted against its payer's daily
+// totals
+type LimitReservation struct {
+	vpa         string
+	day         string
+	amountPaisa int64
+}
+
+// Reserve counts a transaction of amountPaisa made at now against vpa's
+// daily totals, or returns a *LimitExceededError for the limit it would
+// exceed. The reservation is to be released if the payer is not debited;
+// it is nil if nothing was counted. Transactions are let through the daily
+// limits when Redis cannot be reached, like rate limited requests.
+func (l *TransactionLimits) Reserve(ctx context.Context, vpa string, amountPaisa int64, now time.Time) (*LimitReservation, error) {
+	vpa = strings.ToLower(vpa)
+	limit, ok := l.limits[vpa]
+	if !ok {
+		limit = l.def
+	}
+
+	if limit.MaxTransactionPaisa > 0 && amountPaisa > limit.MaxTransactionPaisa {
+		return nil, &LimitExceededError{Limit: LimitTransactionAmount, Max: limit.MaxTransactionPaisa, Requested: amountPaisa}
+	}
+	if limit.DailyAmountPaisa <= 0 && limit.DailyCount <= 0 {
+		return nil, nil
+	}
+
+	day := mandateDay(now).Format("20060102")
+	counted, used, count, err := l.store.ReserveDailyLimit(ctx, vpa, day, amountPaisa, limit.DailyAmountPaisa, limit.DailyCount, limitDayTTL)
+	if err != nil {
+		l.logger.WithError(err).WithField("payer_vpa", vpa).Warn("Transaction limits unavailable, allowing transaction")
+		return nil, nil
+	}
+	if !counted {
+		if limit.DailyAmountPaisa > 0 && used+amountPaisa > limit.DailyAmountPaisa {
+			return nil, &LimitExceededError{Limit: LimitDailyAmount, Max: limit.DailyAmountPaisa, Used: used, Requested: amountPaisa}
+		}
+		return nil, &LimitExceededError{Limit: LimitDailyCount, Max: limit.DailyCount, Used: count, Requested: 1}
+	}
+	return &LimitReservation{vpa: vpa, day: day, amountPaisa: amountPaisa}, nil
+}
+
+// Release takes back a reservation of a transaction its payer was not
+// debited for. A nil reservation is ignored.
+func (l *TransactionLimits) Release(ctx context.Context, reservation *LimitReservation) {
+	if reservation == nil {
+		return
+	}
+	if err := l.store.ReleaseDailyLimit(ctx, reservation.vpa, reservation.day, reservation.amountPaisa); err != nil {
+		l.logger.WithError(err).WithField("payer_vpa", reservation.vpa).Warn("Failed to release transaction limit reservation")
+	}
+}
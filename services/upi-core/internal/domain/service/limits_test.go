@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/infrastructure/redis"
+)
+
+func newTestLimits(t *testing.T) (*TransactionLimits, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := &redis.Client{Client: goredis.NewClient(&goredis.Options{Addr: mr.Addr()})}
+	t.Cleanup(func() { client.Close() })
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewTransactionLimits(client, config.LimitsConfig{
+		Default: config.VPALimitConfig{MaxTransactionPaisa: 2000, DailyAmountPaisa: 3000, DailyCount: 3},
+		VPAs: []config.VPALimitConfig{
+			{VPA: "Shop.Counter@hdfc", MaxTransactionPaisa: 100000},
+		},
+	}, logger), mr
+}
+
+func exceededLimit(err error) string {
+	var exceeded *LimitExceededError
+	if errors.As(err, &exceeded) {
+		return exceeded.Limit
+	}
+	return ""
+}
+
+func TestLimitsCountPayersDailyTransactions(t *testing.T) {
+	limits, _ := newTestLimits(t)
+	ctx := context.Background()
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	if _, err := limits.Reserve(ctx, "alice@okbank", 2500, now); exceededLimit(err) != LimitTransactionAmount {
+		t.Errorf("2500 paisa transaction: err = %v, want the transaction amount limit exceeded", err)
+	}
+	if _, err := limits.Reserve(ctx, "alice@okbank", 1000, now); err != nil {
+		t.Fatal(err)
+	}
+	second, err := limits.Reserve(ctx, "ALICE@okbank", 1500, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = limits.Reserve(ctx, "alice@okbank", 1000, now)
+	if exceededLimit(err) != LimitDailyAmount {
+		t.Fatalf("third transaction: err = %v, want the daily amount limit exceeded", err)
+	}
+	if exceeded := err.(*LimitExceededError); exceeded.Used != 2500 || exceeded.Max != 3000 || exceeded.Requested != 1000 {
+		t.Errorf("exceeded = %+v, want 2500 of 3000 used and 1000 requested", exceeded)
+	}
+
+	// A failed transaction no longer counts
+	limits.Release(ctx, second)
+	for _, amount := range []int64{1000, 500} {
+		if _, err := limits.Reserve(ctx, "alice@okbank", amount, now); err != nil {
+			t.Fatalf("%d paisa after release: %v", amount, err)
+		}
+	}
+	if _, err := limits.Reserve(ctx, "alice@okbank", 100, now); exceededLimit(err) != LimitDailyCount {
+		t.Errorf("fourth transaction of the day: err = %v, want the daily count limit exceeded", err)
+	}
+
+	// Days are days in India: 18:30 UTC starts the next one
+	if _, err := limits.Reserve(ctx, "alice@okbank", 1000, time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC)); err != nil {
+		t.Errorf("first transaction of the next day: %v", err)
+	}
+	if _, err := limits.Reserve(ctx, "shop.counter@HDFC", 50000, now); err != nil {
+		t.Errorf("transaction within the VPA's own limits: %v", err)
+	}
+}
+
+func TestLimitsLetTransactionsThroughWithoutRedis(t *testing.T) {
+	limits, mr := newTestLimits(t)
+	mr.Close()
+	ctx := context.Background()
+
+	reservation, err := limits.Reserve(ctx, "alice@okbank", 2000, time.Now())
+	if err != nil || reservation != nil {
+		t.Errorf("Reserve = %v, %v; want the transaction let through uncounted", reservation, err)
+	}
+	if _, err := limits.Reserve(ctx, "alice@okbank", 2001, time.Now()); exceededLimit(err) != LimitTransactionAmount {
+		t.Errorf("2001 paisa transaction: err = %v, want the transaction amount limit exceeded", err)
+	}
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/upierr"
+	pb "upi-core/pkg/pb"
+)
+
+// scheduleStep is the time between two due dates of a recurring mandate
+type scheduleStep struct {
+	days, months int
+}
+
+// nextScheduleStep holds the step of each recurring frequency
+var nextScheduleStep = map[repository.MandateFrequency]scheduleStep{
+	repository.FrequencyDaily:     {days: 1},
+	repository.FrequencyWeekly:    {days: 7},
+	repository.FrequencyMonthly:   {months: 1},
+	repository.FrequencyQuarterly: {months: 3},
+	repository.FrequencyYearly:    {months: 12},
+}
+
+// scheduleDate returns the nth due date of a mandate starting on start.
+// Monthly dates keep to the start's day of the month, or the month's last
+// day in shorter months.
+func scheduleDate(start time.Time, step scheduleStep, n int) time.Time {
+	if step.days > 0 {
+		return start.AddDate(0, 0, n*step.days)
+	}
+	y, m, d := start.Date()
+	first := time.Date(y, m+time.Month(n*step.months), 1, 0, 0, 0, 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
+
+// nextDueDate returns a mandate's first due date after after, or nil if it
+// has none left
+func nextDueDate(mandate *repository.Mandate, after time.Time) *time.Time {
+	step, ok := nextScheduleStep[mandate.Frequency]
+	if !ok {
+		return nil
+	}
+
+	// Start from an estimate a step short of the answer
+	n := 0
+	if step.days > 0 {
+		n = int(after.Sub(mandate.StartDate).Hours()/24) / step.days
+	} else {
+		n = ((after.Year()-mandate.StartDate.Year())*12 + int(after.Month()-mandate.StartDate.Month())) / step.months
+	}
+	if n > 0 {
+		n--
+	} else {
+		n = 0
+	}
+
+	for ; ; n++ {
+		due := scheduleDate(mandate.StartDate, step, n)
+		if !due.After(after) {
+			continue
+		}
+		if mandate.EndDate != nil && due.After(*mandate.EndDate) {
+			return nil
+		}
+		return &due
+	}
+}
+
+// mandateTransactionID is the ID of the transaction executing a mandate on
+// a due date. Being derived from both, a retried execution is recognised
+// as a duplicate of the original.
+func mandateTransactionID(mandateID string, dueDate time.Time) string {
+	return mandateID + "-" + dueDate.Format("20060102")
+}
+
+// PreauthorizedProcessor processes transactions the switch makes itself
+type PreauthorizedProcessor interface {
+	ProcessPreauthorizedTransaction(ctx context.Context, req *pb.TransactionRequest) (*pb.TransactionResponse, error)
+}
+
+// MandateScheduler debits active mandates on their due dates. Any number of
+// instances can run it side by side.
+type MandateScheduler struct {
+	repo         repository.TransactionRepository
+	transactions PreauthorizedProcessor
+	cfg          config.MandatesConfig
+	logger       *logrus.Logger
+	now          func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewMandateScheduler creates a scheduler; Start runs it in the background
+func NewMandateScheduler(repo repository.TransactionRepository, transactions PreauthorizedProcessor, cfg config.MandatesConfig, logger *logrus.Logger) *MandateScheduler {
+	if cfg.ScanInterval <= 0 {
+		cfg.ScanInterval = time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = 2 * transactionTTL
+	}
+	return &MandateScheduler{
+		repo:         repo,
+		transactions: transactions,
+		cfg:          cfg,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Run executes up to one batch of due mandates, after retrying executions
+// left PENDING, and returns how many due mandates it executed
+func (s *MandateScheduler) Run(ctx context.Context) (int, error) {
+	stuck, err := s.repo.ListPendingMandateExecutions(ctx, s.now().Add(-s.cfg.RetryAfter), s.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending mandate executions: %w", err)
+	}
+	for _, execution := range stuck {
+		mandate, err := s.repo.GetMandate(ctx, execution.MandateID)
+		if err != nil {
+			s.logger.WithError(err).WithField("mandate_id", execution.MandateID).Error("Failed to load mandate of pending execution")
+			continue
+		}
+		s.execute(ctx, mandate, execution)
+	}
+
+	ids, err := s.repo.ListDueMandates(ctx, mandateDay(s.now()), s.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due mandates: %w", err)
+	}
+
+	executed := 0
+	for _, id := range ids {
+		ok, err := s.executeDue(ctx, id)
+		if err != nil {
+			s.logger.WithError(err).WithField("mandate_id", id).Error("Failed to execute due mandate, retrying on the next scan")
+			continue
+		}
+		if ok {
+			executed++
+		}
+	}
+	return executed, nil
+}
+
+// executeDue records the execution of a mandate on its due date and moves
+// the mandate on to its next due date, then makes the debit. A mandate
+// overdue by more than one step, e.g. after an outage, is debited once for
+// the missed dates and once for today's, if it is due today.
+func (s *MandateScheduler) executeDue(ctx context.Context, mandateID string) (bool, error) {
+	today := mandateDay(s.now())
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.repo.RollbackTransaction(tx)
+
+	mandate, err := s.repo.LockMandate(ctx, tx, mandateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to lock mandate: %w", err)
+	}
+	if mandate.Status != repository.MandateActive || mandate.NextDueDate == nil || mandate.NextDueDate.After(today) {
+		// Revoked, or executed by another instance, since it was listed
+		return false, nil
+	}
+
+	due := *mandate.NextDueDate
+	execution := &repository.MandateExecution{
+		MandateID:     mandate.MandateID,
+		DueDate:       due,
+		TransactionID: mandateTransactionID(mandate.MandateID, due),
+		AmountPaisa:   mandate.AmountPaisa,
+	}
+	created, err := s.repo.CreateMandateExecution(ctx, tx, execution)
+	if err != nil {
+		return false, fmt.Errorf("failed to record mandate execution: %w", err)
+	}
+
+	after := due
+	if yesterday := today.AddDate(0, 0, -1); after.Before(yesterday) {
+		after = yesterday
+	}
+	mandate.NextDueDate = nextDueDate(mandate, after)
+	if mandate.NextDueDate == nil {
+		mandate.Status = repository.MandateCompleted
+	}
+	if err := s.repo.UpdateMandate(ctx, tx, mandate); err != nil {
+		return false, fmt.Errorf("failed to update mandate: %w", err)
+	}
+	if err := s.repo.CommitTransaction(tx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if !created {
+		return false, nil
+	}
+	s.execute(ctx, mandate, execution)
+	return true, nil
+}
+
+// execute debits the payer for a PENDING execution and records the outcome.
+// An execution whose transaction cannot be completed now is left PENDING to
+// be retried.
+func (s *MandateScheduler) execute(ctx context.Context, mandate *repository.Mandate, execution *repository.MandateExecution) {
+	logger := s.logger.WithFields(logrus.Fields{
+		"mandate_id":     mandate.MandateID,
+		"due_date":       execution.DueDate.Format(time.DateOnly),
+		"transaction_id": execution.TransactionID,
+	})
+
+	if execution.AmountPaisa > mandate.MaxAmountPaisa {
+		logger.Warn("Mandate execution exceeds the mandate's cap")
+		s.finish(ctx, logger, execution, repository.ExecutionFailed, string(upierr.AmountCapExceeded),
+			fmt.Sprintf("amount %d paisa exceeds the mandate's cap of %d paisa", execution.AmountPaisa, mandate.MaxAmountPaisa))
+		return
+	}
+
+	description := mandate.Description
+	if description == "" {
+		description = "Mandate " + mandate.MandateID
+	}
+	response, err := s.transactions.ProcessPreauthorizedTransaction(ctx, &pb.TransactionRequest{
+		TransactionId: execution.TransactionID,
+		PayerVpa:      mandate.PayerVPA,
+		PayeeVpa:      mandate.PayeeVPA,
+		AmountPaisa:   execution.AmountPaisa,
+		Currency:      "INR",
+		Type:          pb.TransactionType_TRANSACTION_TYPE_P2M,
+		Description:   description,
+		Reference:     mandate.MandateID,
+		InitiatedAt:   timestamppb.New(s.now()),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Mandate execution failed, retrying later")
+		return
+	}
+
+	switch {
+	case response.Status == pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS:
+		logger.Info("Mandate executed")
+		s.finish(ctx, logger, execution, repository.ExecutionSuccess, "", "")
+	case response.ErrorCode == string(upierr.DuplicateInProgress), response.ErrorCode == string(upierr.TransactionPending):
+		logger.Info("Mandate execution still in progress")
+	default:
+		logger.WithField("error_code", response.ErrorCode).Warn("Mandate execution declined")
+		s.finish(ctx, logger, execution, repository.ExecutionFailed, response.ErrorCode, response.ErrorMessage)
+	}
+}
+
+func (s *MandateScheduler) finish(ctx context.Context, logger *logrus.Entry, execution *repository.MandateExecution, status repository.ExecutionStatus, errorCode, errorMessage string) {
+	err := s.repo.FinishMandateExecution(ctx, execution.MandateID, execution.DueDate, status, errorCode, errorMessage)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logger.WithError(err).Error("Failed to record mandate execution outcome")
+	}
+}
+
+// Start executes due mandates every scan interval until Close
+func (s *MandateScheduler) Start() {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+}
+
+func (s *MandateScheduler) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.ScanInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.stop
+		cancel()
+	}()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			// A full batch means there may be more waiting
+			for {
+				executed, err := s.Run(ctx)
+				if err != nil {
+					s.logger.WithError(err).Error("Failed to execute due mandates")
+				}
+				if err != nil || executed < s.cfg.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Close stops the background scans, waiting for one in progress to finish
+func (s *MandateScheduler) Close() {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+	}
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/repository"
+	"upi-core/pkg/upi"
+)
+
+var (
+	// ErrInvalidMandateRequest wraps the reason a mandate request was
+	// rejected before reaching the database
+	ErrInvalidMandateRequest = errors.New("invalid mandate request")
+	// ErrMandateNotFound is returned for an unknown mandate ID
+	ErrMandateNotFound = errors.New("mandate not found")
+	// ErrMandateNotActive is returned when modifying or revoking a mandate
+	// that was revoked or has completed
+	ErrMandateNotActive = errors.New("mandate is not active")
+)
+
+const (
+	// maxMandateIDLen is the width of the mandate_id column
+	maxMandateIDLen = 35
+	// mandateHistoryLimit is how many of its latest executions come with a
+	// mandate
+	mandateHistoryLimit = 12
+)
+
+// mandateZone is the time zone mandate due dates are days in
+var mandateZone = time.FixedZone("IST", 5*60*60+30*60)
+
+// mandateDay returns the day t falls on in India, as midnight UTC
+func mandateDay(t time.Time) time.Time {
+	y, m, d := t.In(mandateZone).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// MandateService creates, modifies and revokes mandates. Due mandates are
+// executed by the MandateScheduler.
+type MandateService struct {
+	repo     repository.TransactionRepository
+	security config.SecurityConfig
+	logger   *logrus.Logger
+	now      func() time.Time
+}
+
+// NewMandateService creates a new mandate service
+func NewMandateService(repo repository.TransactionRepository, security config.SecurityConfig, logger *logrus.Logger) *MandateService {
+	return &MandateService{
+		repo:     repo,
+		security: security,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Create stores a new ACTIVE mandate, first due on its start date. The
+// mandate ID is chosen by the payer's PSP; signature is the payer bank's
+// signature of MandateSigningPayload.
+func (s *MandateService) Create(ctx context.Context, mandate *repository.Mandate, signature string) error {
+	if err := s.validateMandate(mandate); err != nil {
+		return err
+	}
+
+	payer, err := s.repo.GetVPAMapping(ctx, mandate.PayerVPA)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: payer VPA %s is not registered", ErrInvalidMandateRequest, mandate.PayerVPA)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve payer VPA: %w", err)
+	}
+	payee, err := s.repo.GetVPAMapping(ctx, mandate.PayeeVPA)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: payee VPA %s is not registered", ErrInvalidMandateRequest, mandate.PayeeVPA)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve payee VPA: %w", err)
+	}
+	mandate.PayerBankCode = payer.BankCode
+	mandate.PayeeBankCode = payee.BankCode
+
+	if err := s.verifySignature(ctx, mandate, signature); err != nil {
+		return err
+	}
+
+	due := mandate.StartDate
+	mandate.NextDueDate = &due
+	mandate.Status = repository.MandateActive
+	if err := s.repo.CreateMandate(ctx, nil, mandate); err != nil {
+		if errors.Is(err, repository.ErrMandateExists) {
+			return err
+		}
+		return fmt.Errorf("failed to create mandate: %w", err)
+	}
+
+	s.audit(ctx, mandate.MandateID, "CREATE", map[string]interface{}{
+		"payer_vpa":        mandate.PayerVPA,
+		"payee_vpa":        mandate.PayeeVPA,
+		"amount_paisa":     mandate.AmountPaisa,
+		"max_amount_paisa": mandate.MaxAmountPaisa,
+		"frequency":        mandate.Frequency,
+	})
+	return nil
+}
+
+func (s *MandateService) validateMandate(mandate *repository.Mandate) error {
+	if mandate.MandateID == "" || len(mandate.MandateID) > maxMandateIDLen || !isAlnum(mandate.MandateID) {
+		return fmt.Errorf("%w: mandate ID must be 1 to %d letters and digits", ErrInvalidMandateRequest, maxMandateIDLen)
+	}
+	if _, err := upi.ParseVPA(mandate.PayerVPA); err != nil {
+		return fmt.Errorf("%w: payer %v", ErrInvalidMandateRequest, err)
+	}
+	if _, err := upi.ParseVPA(mandate.PayeeVPA); err != nil {
+		return fmt.Errorf("%w: payee %v", ErrInvalidMandateRequest, err)
+	}
+	if mandate.PayerVPA == mandate.PayeeVPA {
+		return fmt.Errorf("%w: payer and payee VPA cannot be the same", ErrInvalidMandateRequest)
+	}
+	if _, ok := nextScheduleStep[mandate.Frequency]; !ok && mandate.Frequency != repository.FrequencyOneTime {
+		return fmt.Errorf("%w: unknown frequency %q", ErrInvalidMandateRequest, mandate.Frequency)
+	}
+	if err := validateMandateAmounts(mandate.AmountPaisa, mandate.MaxAmountPaisa); err != nil {
+		return err
+	}
+	if mandate.StartDate.Before(mandateDay(s.now())) {
+		return fmt.Errorf("%w: start date is in the past", ErrInvalidMandateRequest)
+	}
+	if mandate.EndDate != nil && mandate.EndDate.Before(mandate.StartDate) {
+		return fmt.Errorf("%w: end date is before the start date", ErrInvalidMandateRequest)
+	}
+	return nil
+}
+
+// validateMandateAmounts checks the amount debited on each due date is
+// within the per-execution cap, and the cap within UPI's limits
+func validateMandateAmounts(amountPaisa, maxAmountPaisa int64) error {
+	if err := upi.ValidateAmountPaisa(maxAmountPaisa); err != nil {
+		return fmt.Errorf("%w: max amount: %v", ErrInvalidMandateRequest, err)
+	}
+	if err := upi.ValidateAmountPaisa(amountPaisa); err != nil {
+		return fmt.Errorf("%w: amount: %v", ErrInvalidMandateRequest, err)
+	}
+	if amountPaisa > maxAmountPaisa {
+		return fmt.Errorf("%w: amount exceeds the mandate's max amount", ErrInvalidMandateRequest)
+	}
+	return nil
+}
+
+func isAlnum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// MandateSigningPayload is what the payer's bank signs to create a mandate:
+// its fields joined by "|", dates as YYYY-MM-DD and no end date as "".
+func MandateSigningPayload(mandate *repository.Mandate) []byte {
+	endDate := ""
+	if mandate.EndDate != nil {
+		endDate = mandate.EndDate.Format(time.DateOnly)
+	}
+	return []byte(strings.Join([]string{
+		mandate.MandateID,
+		mandate.PayerVPA,
+		mandate.PayeeVPA,
+		strconv.FormatInt(mandate.AmountPaisa, 10),
+		strconv.FormatInt(mandate.MaxAmountPaisa, 10),
+		string(mandate.Frequency),
+		mandate.StartDate.Format(time.DateOnly),
+		endDate,
+		mandate.Description,
+	}, "|"))
+}
+
+// verifySignature checks a new mandate was signed by the payer's bank.
+// Unsigned mandates pass unless signatures are required.
+func (s *MandateService) verifySignature(ctx context.Context, mandate *repository.Mandate, signature string) error {
+	if signature == "" {
+		if s.security.RequireSignatures {
+			return fmt.Errorf("%w: mandate is not signed", crypto.ErrInvalidSignature)
+		}
+		return nil
+	}
+
+	bank, err := s.repo.GetBankByCode(ctx, mandate.PayerBankCode)
+	if err != nil {
+		return fmt.Errorf("failed to load public key of bank %s: %w", mandate.PayerBankCode, err)
+	}
+	return crypto.Verify(bank.PublicKey, MandateSigningPayload(mandate), signature)
+}
+
+// MandateChanges are the changes Modify makes to a mandate; zero fields
+// are left as they are
+type MandateChanges struct {
+	AmountPaisa    int64
+	MaxAmountPaisa int64
+	EndDate        *time.Time
+}
+
+// Modify changes an active mandate's amounts or end date. A mandate whose
+// next due date falls after its new end date is completed.
+func (s *MandateService) Modify(ctx context.Context, mandateID string, changes MandateChanges) (*repository.Mandate, error) {
+	var mandate *repository.Mandate
+	err := s.update(ctx, mandateID, func(m *repository.Mandate) error {
+		amount, maxAmount := m.AmountPaisa, m.MaxAmountPaisa
+		if changes.AmountPaisa != 0 {
+			amount = changes.AmountPaisa
+		}
+		if changes.MaxAmountPaisa != 0 {
+			maxAmount = changes.MaxAmountPaisa
+		}
+		if err := validateMandateAmounts(amount, maxAmount); err != nil {
+			return err
+		}
+		if changes.EndDate != nil {
+			if changes.EndDate.Before(m.StartDate) {
+				return fmt.Errorf("%w: end date is before the start date", ErrInvalidMandateRequest)
+			}
+			if changes.EndDate.Before(mandateDay(s.now())) {
+				return fmt.Errorf("%w: end date is in the past", ErrInvalidMandateRequest)
+			}
+			m.EndDate = changes.EndDate
+			if m.NextDueDate.After(*m.EndDate) {
+				m.NextDueDate = nil
+				m.Status = repository.MandateCompleted
+			}
+		}
+		m.AmountPaisa, m.MaxAmountPaisa = amount, maxAmount
+		mandate = m
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, mandateID, "MODIFY", map[string]interface{}{
+		"amount_paisa":     mandate.AmountPaisa,
+		"max_amount_paisa": mandate.MaxAmountPaisa,
+		"end_date":         mandate.EndDate,
+	})
+	return mandate, nil
+}
+
+// Revoke stops an active mandate being executed. An execution already
+// under way completes.
+func (s *MandateService) Revoke(ctx context.Context, mandateID, reason string) (*repository.Mandate, error) {
+	var mandate *repository.Mandate
+	err := s.update(ctx, mandateID, func(m *repository.Mandate) error {
+		m.Status = repository.MandateRevoked
+		m.NextDueDate = nil
+		m.RevokeReason = reason
+		mandate = m
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, mandateID, "REVOKE", map[string]interface{}{
+		"reason": reason,
+	})
+	return mandate, nil
+}
+
+// update applies change to an active mandate, locked against the scheduler
+// executing it meanwhile
+func (s *MandateService) update(ctx context.Context, mandateID string, change func(*repository.Mandate) error) error {
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.repo.RollbackTransaction(tx)
+
+	mandate, err := s.repo.LockMandate(ctx, tx, mandateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMandateNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load mandate: %w", err)
+	}
+	if mandate.Status != repository.MandateActive {
+		return fmt.Errorf("%w: mandate %s is %s", ErrMandateNotActive, mandateID, mandate.Status)
+	}
+
+	if err := change(mandate); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateMandate(ctx, tx, mandate); err != nil {
+		return fmt.Errorf("failed to update mandate: %w", err)
+	}
+	if err := s.repo.CommitTransaction(tx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Get returns a mandate with its latest executions, latest first
+func (s *MandateService) Get(ctx context.Context, mandateID string) (*repository.Mandate, []*repository.MandateExecution, error) {
+	mandate, err := s.repo.GetMandate(ctx, mandateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrMandateNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load mandate: %w", err)
+	}
+
+	executions, err := s.repo.ListMandateExecutions(ctx, mandateID, mandateHistoryLimit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load mandate executions: %w", err)
+	}
+	return mandate, executions, nil
+}
+
+func (s *MandateService) audit(ctx context.Context, mandateID, action string, values map[string]interface{}) {
+	if err := s.repo.LogAudit(ctx, nil, "mandate", mandateID, action, "SYSTEM", nil, values, ""); err != nil {
+		s.logger.WithError(err).WithField("mandate_id", mandateID).Warn("Failed to log mandate audit entry")
+	}
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/upierr"
+	pb "upi-core/pkg/pb"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func datePtr(s string) *time.Time {
+	d := date(s)
+	return &d
+}
+
+func TestNextDueDate(t *testing.T) {
+	for _, tc := range []struct {
+		frequency repository.MandateFrequency
+		start     string
+		end       string
+		after     string
+		want      string
+	}{
+		{repository.FrequencyDaily, "2024-01-01", "", "2024-01-01", "2024-01-02"},
+		{repository.FrequencyWeekly, "2024-01-01", "", "2024-03-01", "2024-03-04"},
+		// Month ends are kept to, in months as short as they come
+		{repository.FrequencyMonthly, "2024-01-31", "", "2024-01-31", "2024-02-29"},
+		{repository.FrequencyMonthly, "2024-01-31", "", "2024-02-29", "2024-03-31"},
+		{repository.FrequencyQuarterly, "2024-11-30", "", "2024-12-15", "2025-02-28"},
+		{repository.FrequencyYearly, "2024-02-29", "", "2024-03-01", "2025-02-28"},
+		// Due dates are a schedule, not steps from the last execution
+		{repository.FrequencyMonthly, "2024-01-15", "", "2024-06-20", "2024-07-15"},
+		{repository.FrequencyMonthly, "2024-01-15", "2024-03-31", "2024-03-15", ""},
+		{repository.FrequencyOneTime, "2024-01-15", "", "2024-01-15", ""},
+	} {
+		mandate := &repository.Mandate{Frequency: tc.frequency, StartDate: date(tc.start)}
+		if tc.end != "" {
+			mandate.EndDate = datePtr(tc.end)
+		}
+
+		got := ""
+		if due := nextDueDate(mandate, date(tc.after)); due != nil {
+			got = due.Format(time.DateOnly)
+		}
+		if got != tc.want {
+			t.Errorf("%s from %s, after %s: next due %q, want %q", tc.frequency, tc.start, tc.after, got, tc.want)
+		}
+	}
+}
+
+var mandateToday = time.Date(2024, 5, 10, 9, 0, 0, 0, mandateZone)
+
+func newTestMandateService(repo *fakeRepository) *MandateService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := NewMandateService(repo, config.SecurityConfig{}, logger)
+	s.now = func() time.Time { return mandateToday }
+	return s
+}
+
+func newMandate(id string) *repository.Mandate {
+	return &repository.Mandate{
+		MandateID:      id,
+		PayerVPA:       "alice@hdfc",
+		PayeeVPA:       "netflix@icici",
+		AmountPaisa:    49900,
+		MaxAmountPaisa: 64900,
+		Frequency:      repository.FrequencyMonthly,
+		StartDate:      date("2024-05-10"),
+	}
+}
+
+func TestMandateLifecycle(t *testing.T) {
+	repo := newFakeRepository()
+	s := newTestMandateService(repo)
+	ctx := context.Background()
+
+	for name, change := range map[string]func(*repository.Mandate){
+		"amount over cap": func(m *repository.Mandate) { m.AmountPaisa = m.MaxAmountPaisa + 1 },
+		"past start":      func(m *repository.Mandate) { m.StartDate = date("2024-05-09") },
+		"end before start": func(m *repository.Mandate) {
+			m.EndDate = datePtr("2024-05-01")
+		},
+		"bad frequency": func(m *repository.Mandate) { m.Frequency = "HOURLY" },
+		"bad ID":        func(m *repository.Mandate) { m.MandateID = "UMN-1" },
+	} {
+		mandate := newMandate("UMN1")
+		change(mandate)
+		if err := s.Create(ctx, mandate, ""); !errors.Is(err, ErrInvalidMandateRequest) {
+			t.Errorf("%s: err = %v, want ErrInvalidMandateRequest", name, err)
+		}
+	}
+
+	mandate := newMandate("UMN1")
+	if err := s.Create(ctx, mandate, ""); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if mandate.Status != repository.MandateActive || !mandate.NextDueDate.Equal(mandate.StartDate) || mandate.PayerBankCode != "HDFC" {
+		t.Errorf("created mandate = %+v", mandate)
+	}
+	if err := s.Create(ctx, newMandate("UMN1"), ""); !errors.Is(err, repository.ErrMandateExists) {
+		t.Errorf("duplicate mandate ID: err = %v", err)
+	}
+
+	if _, err := s.Modify(ctx, "UMN1", MandateChanges{MaxAmountPaisa: 40000}); !errors.Is(err, ErrInvalidMandateRequest) {
+		t.Errorf("cap below amount: err = %v", err)
+	}
+	modified, err := s.Modify(ctx, "UMN1", MandateChanges{AmountPaisa: 64900})
+	if err != nil || modified.AmountPaisa != 64900 || modified.Status != repository.MandateActive {
+		t.Fatalf("Modify = %+v, %v", modified, err)
+	}
+
+	revoked, err := s.Revoke(ctx, "UMN1", "cancelled subscription")
+	if err != nil || revoked.Status != repository.MandateRevoked || revoked.NextDueDate != nil {
+		t.Fatalf("Revoke = %+v, %v", revoked, err)
+	}
+	if _, err := s.Revoke(ctx, "UMN1", ""); !errors.Is(err, ErrMandateNotActive) {
+		t.Errorf("revoking a revoked mandate: err = %v", err)
+	}
+	if _, err := s.Modify(ctx, "UMN2", MandateChanges{AmountPaisa: 100}); !errors.Is(err, ErrMandateNotFound) {
+		t.Errorf("modifying an unknown mandate: err = %v", err)
+	}
+}
+
+// fakeProcessor answers every transaction with the configured response
+type fakeProcessor struct {
+	response *pb.TransactionResponse
+	err      error
+	requests []*pb.TransactionRequest
+}
+
+func (p *fakeProcessor) ProcessPreauthorizedTransaction(ctx context.Context, req *pb.TransactionRequest) (*pb.TransactionResponse, error) {
+	p.requests = append(p.requests, req)
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.response != nil {
+		return p.response, nil
+	}
+	return &pb.TransactionResponse{TransactionId: req.TransactionId, Status: pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS}, nil
+}
+
+func newTestScheduler(repo *fakeRepository, processor *fakeProcessor) *MandateScheduler {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := NewMandateScheduler(repo, processor, config.MandatesConfig{BatchSize: 10}, logger)
+	s.now = func() time.Time { return mandateToday }
+	return s
+}
+
+func TestMandateSchedulerExecutesDueMandates(t *testing.T) {
+	repo := newFakeRepository()
+	if err := newTestMandateService(repo).Create(context.Background(), newMandate("UMN1"), ""); err != nil {
+		t.Fatal(err)
+	}
+	processor := &fakeProcessor{}
+	scheduler := newTestScheduler(repo, processor)
+
+	if executed, err := scheduler.Run(context.Background()); err != nil || executed != 1 {
+		t.Fatalf("executed %d, %v; want 1", executed, err)
+	}
+	if len(processor.requests) != 1 {
+		t.Fatalf("%d transactions made, want 1", len(processor.requests))
+	}
+	if req := processor.requests[0]; req.TransactionId != "UMN1-20240510" || req.AmountPaisa != 49900 || req.PayerVpa != "alice@hdfc" {
+		t.Errorf("transaction = %+v", req)
+	}
+	if got := repo.executions; len(got) != 1 || got[0].Status != repository.ExecutionSuccess {
+		t.Errorf("executions = %+v", got)
+	}
+	if due := repo.mandates["UMN1"].NextDueDate; due == nil || !due.Equal(date("2024-06-10")) {
+		t.Errorf("next due date = %v, want 2024-06-10", due)
+	}
+
+	// Nothing is due until next month
+	if executed, _ := scheduler.Run(context.Background()); executed != 0 || len(processor.requests) != 1 {
+		t.Errorf("second run executed %d, made %d transactions", executed, len(processor.requests))
+	}
+}
+
+func TestMandateSchedulerRecordsDeclinedExecution(t *testing.T) {
+	repo := newFakeRepository()
+	mandate := newMandate("UMN1")
+	mandate.Frequency = repository.FrequencyOneTime
+	if err := newTestMandateService(repo).Create(context.Background(), mandate, ""); err != nil {
+		t.Fatal(err)
+	}
+	processor := &fakeProcessor{response: &pb.TransactionResponse{
+		Status:    pb.TransactionStatus_TRANSACTION_STATUS_FAILED,
+		ErrorCode: "INSUFFICIENT_FUNDS",
+	}}
+
+	if _, err := newTestScheduler(repo, processor).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.executions; len(got) != 1 || got[0].Status != repository.ExecutionFailed || got[0].ErrorCode != "INSUFFICIENT_FUNDS" {
+		t.Errorf("executions = %+v", got)
+	}
+	if got := repo.mandates["UMN1"]; got.Status != repository.MandateCompleted || got.NextDueDate != nil {
+		t.Errorf("one-time mandate after execution = %+v", got)
+	}
+}
+
+func TestMandateSchedulerRetriesPendingExecution(t *testing.T) {
+	repo := newFakeRepository()
+	if err := newTestMandateService(repo).Create(context.Background(), newMandate("UMN1"), ""); err != nil {
+		t.Fatal(err)
+	}
+	processor := &fakeProcessor{err: errors.New("context deadline exceeded")}
+	scheduler := newTestScheduler(repo, processor)
+
+	if _, err := scheduler.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.executions; len(got) != 1 || got[0].Status != repository.ExecutionPending {
+		t.Fatalf("executions after failed transaction = %+v", got)
+	}
+
+	processor.err = nil
+	if _, err := scheduler.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.executions; len(got) != 1 || got[0].Status != repository.ExecutionSuccess {
+		t.Errorf("executions after retry = %+v", got)
+	}
+	if len(processor.requests) != 2 || processor.requests[1].TransactionId != processor.requests[0].TransactionId {
+		t.Errorf("retry did not reuse the transaction ID: %+v", processor.requests)
+	}
+}
+
+func TestMandateSchedulerEnforcesCap(t *testing.T) {
+	repo := newFakeRepository()
+	mandate := newMandate("UMN1")
+	if err := newTestMandateService(repo).Create(context.Background(), mandate, ""); err != nil {
+		t.Fatal(err)
+	}
+	// A cap lowered below the amount directly in the database
+	repo.mandates["UMN1"].MaxAmountPaisa = 100
+	processor := &fakeProcessor{}
+
+	if _, err := newTestScheduler(repo, processor).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(processor.requests) != 0 {
+		t.Errorf("payer debited over the mandate's cap")
+	}
+	if got := repo.executions; len(got) != 1 || got[0].ErrorCode != string(upierr.AmountCapExceeded) {
+		t.Errorf("executions = %+v", got)
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	pb "upi-core/pkg/pb"
+)
+
+// bankWindowSize is how many of a bank's latest debits and credits its
+// success rate is worked out over
+const bankWindowSize = 100
+
+// Stages of a transaction that are timed
+const (
+	stageVPAResolve = "vpa_resolve"
+	stageDebit      = "debit"
+	stageCredit     = "credit"
+	stageReversal   = "reversal"
+)
+
+// pipeline returns the transaction pipeline instruments, created on first
+// use so that they come from the meter provider telemetry.Init installs
+var pipeline = sync.OnceValue(func() *pipelineMetrics {
+	return newPipelineMetrics(otel.Meter("upi-core/transactions"))
+})
+
+// pipelineMetrics are the transaction pipeline instruments, exported to
+// Prometheus as:
+//
+//	transaction_duration_seconds{type,status} (end to end)
+//	transaction_stage_duration_seconds{stage,outcome} (vpa_resolve, debit, credit, reversal)
+//	transaction_bank_legs_total{bank_code,leg,outcome}
+//	transaction_bank_success_rate{bank_code} (of the last 100 debits and credits)
+//	transaction_in_flight
+type pipelineMetrics struct {
+	duration      metric.Float64Histogram
+	stageDuration metric.Float64Histogram
+	bankLegs      metric.Int64Counter
+	inFlight      metric.Int64UpDownCounter
+
+	mu    sync.Mutex
+	banks map[string]*bankWindow
+}
+
+// bankWindow holds whether each of a bank's latest legs succeeded
+type bankWindow struct {
+	outcomes  [bankWindowSize]bool
+	next      int
+	count     int
+	successes int
+}
+
+func (w *bankWindow) add(success bool) {
+	if w.count == bankWindowSize {
+		if w.outcomes[w.next] {
+			w.successes--
+		}
+	} else {
+		w.count++
+	}
+	w.outcomes[w.next] = success
+	if success {
+		w.successes++
+	}
+	w.next = (w.next + 1) % bankWindowSize
+}
+
+func (w *bankWindow) rate() float64 {
+	return float64(w.successes) / float64(w.count)
+}
+
+func newPipelineMetrics(meter metric.Meter) *pipelineMetrics {
+	m := &pipelineMetrics{banks: make(map[string]*bankWindow)}
+	// Instrument errors only come from invalid names, so the no-op
+	// instruments returned alongside them are used as they are
+	m.duration, _ = meter.Float64Histogram("transaction.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("End to end time to process a transaction"))
+	m.stageDuration, _ = meter.Float64Histogram("transaction.stage.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Time taken by each stage of a transaction"))
+	m.bankLegs, _ = meter.Int64Counter("transaction.bank_legs",
+		metric.WithDescription("Debits, credits and reversals sent to banks by outcome"))
+	m.inFlight, _ = meter.Int64UpDownCounter("transaction.in_flight",
+		metric.WithDescription("Transactions being processed"))
+	meter.Float64ObservableGauge("transaction.bank_success_rate",
+		metric.WithDescription("Share of a bank's last 100 debits and credits that succeeded"),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			for code, rate := range m.successRates() {
+				o.Observe(rate, metric.WithAttributes(attribute.String("bank_code", code)))
+			}
+			return nil
+		}),
+	)
+	return m
+}
+
+// begin counts a transaction in flight; the returned func records its
+// outcome and takes it out of flight
+func (m *pipelineMetrics) begin(ctx context.Context, transactionType pb.TransactionType) func(*pb.TransactionResponse) {
+	start := time.Now()
+	m.inFlight.Add(ctx, 1)
+	return func(response *pb.TransactionResponse) {
+		m.inFlight.Add(ctx, -1)
+		status := "ERROR"
+		if response != nil {
+			status = strings.TrimPrefix(response.Status.String(), "TRANSACTION_STATUS_")
+		}
+		m.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+			attribute.String("type", strings.TrimPrefix(transactionType.String(), "TRANSACTION_TYPE_")),
+			attribute.String("status", status),
+		))
+	}
+}
+
+// stage records how long a stage started at start took
+func (m *pipelineMetrics) stage(ctx context.Context, stage string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	m.stageDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("stage", stage),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// bankLeg records a debit, credit or reversal sent to a bank at start. A
+// leg the bank answered other than SUCCESS is declined; one it did not
+// answer failed. Reversals are left out of the bank's success rate, which
+// is of the legs transactions depend on.
+func (m *pipelineMetrics) bankLeg(ctx context.Context, leg, bankCode string, start time.Time, response *BankTransactionResponse, err error) {
+	outcome := "success"
+	switch {
+	case err != nil:
+		outcome = "failure"
+	case response.Status != "SUCCESS":
+		outcome = "declined"
+	}
+	m.stageDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("stage", leg),
+		attribute.String("outcome", outcome),
+	))
+	m.bankLegs.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("bank_code", bankCode),
+		attribute.String("leg", leg),
+		attribute.String("outcome", outcome),
+	))
+	if leg == stageReversal {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	window, ok := m.banks[bankCode]
+	if !ok {
+		window = &bankWindow{}
+		m.banks[bankCode] = window
+	}
+	window.add(outcome == "success")
+}
+
+func (m *pipelineMetrics) successRates() map[string]float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rates := make(map[string]float64, len(m.banks))
+	for code, window := range m.banks {
+		rates[code] = window.rate()
+	}
+	return rates
+}
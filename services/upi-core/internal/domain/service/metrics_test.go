@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestBankSuccessRateCoversLatestLegs(t *testing.T) {
+	m := newPipelineMetrics(noop.NewMeterProvider().Meter("test"))
+	ctx := context.Background()
+	declined := &BankTransactionResponse{Status: "FAILED"}
+	succeeded := &BankTransactionResponse{Status: "SUCCESS"}
+
+	m.bankLeg(ctx, stageDebit, "HDFC", time.Now(), nil, errors.New("bank down"))
+	for i := 0; i < 3; i++ {
+		m.bankLeg(ctx, stageCredit, "HDFC", time.Now(), declined, nil)
+	}
+	m.bankLeg(ctx, stageDebit, "SBI", time.Now(), succeeded, nil)
+	// Reversals are not counted
+	m.bankLeg(ctx, stageReversal, "SBI", time.Now(), declined, nil)
+
+	rates := m.successRates()
+	if rates["HDFC"] != 0 || rates["SBI"] != 1 {
+		t.Fatalf("rates = %v, want HDFC 0 and SBI 1", rates)
+	}
+
+	// Once the window is full the oldest legs drop out of it, leaving one
+	// of the declined credits
+	for i := 0; i < bankWindowSize-1; i++ {
+		m.bankLeg(ctx, stageDebit, "HDFC", time.Now(), succeeded, nil)
+	}
+	if rate := m.successRates()["HDFC"]; rate != 0.99 {
+		t.Errorf("HDFC rate = %v, want 0.99", rate)
+	}
+}
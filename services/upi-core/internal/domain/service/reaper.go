@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/upierr"
+)
+
+// EventPublisher publishes transaction events, e.g. to Kafka
+type EventPublisher interface {
+	PublishTransactionEvent(ctx context.Context, transactionID string, event []byte) error
+}
+
+// Reaper times out transactions left PENDING past their expiry, e.g. because
+// the instance processing them crashed, and reverses their debit if one was
+// made. It also expires collect requests left unanswered. Any number of
+// instances can run it side by side.
+type Reaper struct {
+	repo        repository.TransactionRepository
+	bankClients BankClients
+	events      EventPublisher
+	statuses    StatusBus
+	cfg         config.ReaperConfig
+	logger      *logrus.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewReaper creates a reaper; Start runs it in the background
+func NewReaper(repo repository.TransactionRepository, bankClients BankClients, events EventPublisher, statuses StatusBus, cfg config.ReaperConfig, logger *logrus.Logger) *Reaper {
+	if cfg.ScanInterval <= 0 {
+		cfg.ScanInterval = 30 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.GracePeriod < 0 {
+		cfg.GracePeriod = 0
+	}
+	return &Reaper{
+		repo:        repo,
+		bankClients: bankClients,
+		events:      events,
+		statuses:    statuses,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Reap times out up to one batch of expired transactions and returns how
+// many it timed out
+func (r *Reaper) Reap(ctx context.Context) (int, error) {
+	ids, err := r.repo.ListExpiredTransactions(ctx, time.Now().Add(-r.cfg.GracePeriod), r.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired transactions: %w", err)
+	}
+
+	reaped := 0
+	for _, id := range ids {
+		expired, err := r.expire(ctx, id)
+		if err != nil {
+			r.logger.WithError(err).WithField("transaction_id", id).Error("Failed to time out expired transaction, retrying on the next scan")
+			continue
+		}
+		if expired {
+			reaped++
+		}
+	}
+	return reaped, nil
+}
+
+// expire times out one transaction, reversing its debit first if it had
+// one. If the reversal fails the transaction is left PENDING for the next
+// scan to try again.
+func (r *Reaper) expire(ctx context.Context, transactionID string) (bool, error) {
+	tx, err := r.repo.BeginTransaction(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer r.repo.RollbackTransaction(tx)
+
+	transaction, err := r.repo.LockPendingTransaction(ctx, tx, transactionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Completed, or taken by another reaper, since it was listed
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to lock transaction: %w", err)
+	}
+
+	result := &TransactionResult{Transaction: transaction}
+	if err := r.repo.UpdateTransactionStatus(ctx, tx, transactionID, repository.StatusTimeout, "Transaction expired before completing", string(upierr.TransactionTimeout), ErrTransactionExpired.Error()); err != nil {
+		return false, fmt.Errorf("failed to update transaction status: %w", err)
+	}
+	result.addEvent("TRANSACTION_TIMEOUT", "Transaction expired before completing", map[string]interface{}{
+		"expires_at": transaction.ExpiresAt,
+	})
+	transaction.Status = repository.StatusTimeout
+	transaction.ErrorCode = string(upierr.TransactionTimeout)
+
+	if transaction.DebitReference != "" {
+		payerMapping, err := r.repo.GetVPAMapping(repository.ReadPrimary(ctx), transaction.PayerVPA)
+		if err != nil {
+			return false, fmt.Errorf("failed to resolve payer VPA %s: %w", transaction.PayerVPA, err)
+		}
+		// Banks reject a repeated transaction ID, so if the commit below
+		// fails the retried reversal cannot credit the payer twice
+		if err := reverseDebit(ctx, r.bankClients, transaction, payerMapping, transaction.DebitReference); err != nil {
+			return false, err
+		}
+		if err := r.repo.UpdateTransactionStatus(ctx, tx, transactionID, repository.StatusReversed, "Transaction expired, debit reversed", string(upierr.TransactionTimeout), ""); err != nil {
+			return false, fmt.Errorf("failed to update transaction status: %w", err)
+		}
+		result.addEvent("REVERSAL_SUCCESS", "Debit of expired transaction reversed", map[string]interface{}{
+			"bank_reference_id": transaction.DebitReference,
+		})
+		transaction.Status = repository.StatusReversed
+	}
+
+	step := repository.SagaFailed
+	if transaction.Status == repository.StatusReversed {
+		step = repository.SagaCompensated
+	}
+	if err := r.repo.UpdateSagaStep(ctx, tx, transactionID, step, ErrTransactionExpired.Error()); err != nil {
+		return false, fmt.Errorf("failed to update saga step: %w", err)
+	}
+
+	if err := r.repo.CommitTransaction(tx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"transaction_id": transactionID,
+		"status":         transaction.Status,
+	}).Warn("Timed out expired transaction")
+	update := &TransactionStatusUpdate{
+		TransactionID: transactionID,
+		Status:        transaction.Status,
+		ErrorCode:     string(upierr.TransactionTimeout),
+		UpdatedAt:     time.Now(),
+	}
+	if transaction.Status == repository.StatusTimeout {
+		update.ErrorMessage = ErrTransactionExpired.Error()
+	}
+	publishStatus(ctx, r.statuses, r.logger, update)
+	publishTransactionEvents(ctx, r.events, result)
+	return true, nil
+}
+
+// expireCollectRequests marks the collect requests past their expiry EXPIRED
+func (r *Reaper) expireCollectRequests(ctx context.Context) {
+	expired, err := r.repo.ExpireCollectRequests(ctx, time.Now())
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to expire collect requests")
+		return
+	}
+	if expired > 0 {
+		r.logger.WithField("count", expired).Info("Expired unanswered collect requests")
+	}
+}
+
+// Start scans for expired transactions every scan interval until Close
+func (r *Reaper) Start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run()
+}
+
+func (r *Reaper) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.cfg.ScanInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-r.stop
+		cancel()
+	}()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			// A full batch means there may be more waiting
+			for {
+				reaped, err := r.Reap(ctx)
+				if err != nil {
+					r.logger.WithError(err).Error("Failed to reap expired transactions")
+				}
+				if err != nil || reaped < r.cfg.BatchSize {
+					break
+				}
+			}
+			r.expireCollectRequests(ctx)
+		}
+	}
+}
+
+// Close stops the background scans, waiting for one in progress to finish
+func (r *Reaper) Close() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+	}
+}
@@ -0,0 +1,710 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+// fakeRepository keeps transactions in memory. Status changes made in a
+// database transaction only apply on commit.
+type fakeRepository struct {
+	repository.TransactionRepository
+
+	transactions map[string]*repository.Transaction
+	history      map[string][]repository.TransactionStatus
+	banks        map[string]*repository.Bank
+	vpas         map[string]*repository.VPAMapping
+	mandates     map[string]*repository.Mandate
+	executions   []*repository.MandateExecution
+	collects     map[string]*repository.CollectRequest
+	sagas        map[string]*repository.SagaState
+	feeRules     map[int64]*repository.FeeRule
+	windows      map[int64]*repository.MaintenanceWindow
+	reconRuns    map[string]*repository.ReconRun
+	exceptions   []*repository.ReconException
+	audits       []string
+	staged       []func()
+}
+
+func newFakeRepository(transactions ...*repository.Transaction) *fakeRepository {
+	r := &fakeRepository{
+		transactions: make(map[string]*repository.Transaction),
+		history:      make(map[string][]repository.TransactionStatus),
+		vpas:         make(map[string]*repository.VPAMapping),
+		mandates:     make(map[string]*repository.Mandate),
+		collects:     make(map[string]*repository.CollectRequest),
+		sagas:        make(map[string]*repository.SagaState),
+		feeRules:     make(map[int64]*repository.FeeRule),
+		windows:      make(map[int64]*repository.MaintenanceWindow),
+		reconRuns:    make(map[string]*repository.ReconRun),
+	}
+	for _, t := range transactions {
+		r.transactions[t.TransactionID] = t
+	}
+	return r
+}
+
+func (r *fakeRepository) BeginTransaction(ctx context.Context) (*sql.Tx, error) {
+	r.staged = nil
+	return nil, nil
+}
+
+func (r *fakeRepository) CommitTransaction(tx *sql.Tx) error {
+	for _, apply := range r.staged {
+		apply()
+	}
+	r.staged = nil
+	return nil
+}
+
+func (r *fakeRepository) RollbackTransaction(tx *sql.Tx) error {
+	r.staged = nil
+	return nil
+}
+
+func (r *fakeRepository) ListExpiredTransactions(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	var ids []string
+	for id, t := range r.transactions {
+		if t.Status == repository.StatusPending && !t.ExpiresAt.After(before) && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeRepository) LockPendingTransaction(ctx context.Context, tx *sql.Tx, transactionID string) (*repository.Transaction, error) {
+	t, ok := r.transactions[transactionID]
+	if !ok || t.Status != repository.StatusPending {
+		return nil, sql.ErrNoRows
+	}
+	copied := *t
+	return &copied, nil
+}
+
+// ListTransactions filters by status and creation time, and pages by
+// creation time only
+func (r *fakeRepository) ListTransactions(ctx context.Context, filter repository.TransactionFilter) ([]*repository.Transaction, error) {
+	var matched []*repository.Transaction
+	for _, t := range r.transactions {
+		if filter.Status != "" && t.Status != filter.Status {
+			continue
+		}
+		if (!filter.From.IsZero() && t.CreatedAt.Before(filter.From)) || (!filter.To.IsZero() && !t.CreatedAt.Before(filter.To)) {
+			continue
+		}
+		if filter.After != nil && !t.CreatedAt.Before(filter.After.CreatedAt) {
+			continue
+		}
+		matched = append(matched, t)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].CreatedAt.After(matched[j].CreatedAt) })
+	if len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, nil
+}
+
+func (r *fakeRepository) GetTransactionByID(ctx context.Context, transactionID string) (*repository.Transaction, error) {
+	t, ok := r.transactions[transactionID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (r *fakeRepository) RecordDebit(ctx context.Context, tx *sql.Tx, transactionID string, bankReferenceID string) error {
+	r.transactions[transactionID].DebitReference = bankReferenceID
+	return nil
+}
+
+func (r *fakeRepository) UpdateTransactionStatus(ctx context.Context, tx *sql.Tx, transactionID string, status repository.TransactionStatus, reason string, errorCode string, errorMessage string) error {
+	r.staged = append(r.staged, func() {
+		r.transactions[transactionID].Status = status
+		r.history[transactionID] = append(r.history[transactionID], status)
+	})
+	return nil
+}
+
+func (r *fakeRepository) GetBankByCode(ctx context.Context, bankCode string) (*repository.Bank, error) {
+	bank, ok := r.banks[bankCode]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return bank, nil
+}
+
+func (r *fakeRepository) LockBank(ctx context.Context, tx *sql.Tx, bankCode string) (*repository.Bank, error) {
+	bank, err := r.GetBankByCode(ctx, bankCode)
+	if err != nil {
+		return nil, err
+	}
+	copied := *bank
+	return &copied, nil
+}
+
+func (r *fakeRepository) CreateBank(ctx context.Context, tx *sql.Tx, bank *repository.Bank) error {
+	bank.Status = repository.BankPendingApproval
+	copied := *bank
+	r.staged = append(r.staged, func() { r.banks[bank.BankCode] = &copied })
+	return nil
+}
+
+func (r *fakeRepository) UpsertBank(ctx context.Context, tx *sql.Tx, bank *repository.Bank) error {
+	details := *bank
+	r.staged = append(r.staged, func() {
+		stored := r.banks[details.BankCode]
+		stored.BankName, stored.EndpointURL, stored.PublicKey = details.BankName, details.EndpointURL, details.PublicKey
+	})
+	return nil
+}
+
+func (r *fakeRepository) UpdateBankLifecycle(ctx context.Context, tx *sql.Tx, bank *repository.Bank) error {
+	lifecycle := *bank
+	r.staged = append(r.staged, func() {
+		stored := r.banks[lifecycle.BankCode]
+		stored.Status, stored.StatusReason = lifecycle.Status, lifecycle.StatusReason
+		stored.RegisteredBy, stored.ApprovedBy, stored.ApprovedAt = lifecycle.RegisteredBy, lifecycle.ApprovedBy, lifecycle.ApprovedAt
+		stored.MaintenanceWindowID = lifecycle.MaintenanceWindowID
+	})
+	return nil
+}
+
+func (r *fakeRepository) CreateMaintenanceWindow(ctx context.Context, tx *sql.Tx, window *repository.MaintenanceWindow) error {
+	window.WindowID = int64(len(r.windows) + 1)
+	copied := *window
+	r.staged = append(r.staged, func() { r.windows[copied.WindowID] = &copied })
+	return nil
+}
+
+func (r *fakeRepository) ListStartedMaintenanceWindows(ctx context.Context, now time.Time) ([]*repository.MaintenanceWindow, error) {
+	var started []*repository.MaintenanceWindow
+	for _, window := range r.windows {
+		bank := r.banks[window.BankCode]
+		if window.CancelledAt == nil && !window.StartsAt.After(now) && window.EndsAt.After(now) && bank.Status == repository.BankActive {
+			started = append(started, window)
+		}
+	}
+	return started, nil
+}
+
+func (r *fakeRepository) ListBanksLeavingMaintenance(ctx context.Context, now time.Time) ([]string, error) {
+	var bankCodes []string
+	for _, bank := range r.banks {
+		if bank.Status != repository.BankMaintenance || bank.MaintenanceWindowID == nil {
+			continue
+		}
+		if window := r.windows[*bank.MaintenanceWindowID]; window.CancelledAt != nil || !window.EndsAt.After(now) {
+			bankCodes = append(bankCodes, bank.BankCode)
+		}
+	}
+	return bankCodes, nil
+}
+
+// GetVPAMapping resolves the registered VPAs, and maps any other to an
+// HDFC account
+func (r *fakeRepository) GetVPAMapping(ctx context.Context, vpa string) (*repository.VPAMapping, error) {
+	if mapping, ok := r.vpas[vpa]; ok {
+		if !mapping.IsActive {
+			return nil, sql.ErrNoRows
+		}
+		return mapping, nil
+	}
+	return &repository.VPAMapping{VPA: vpa, BankCode: "HDFC", AccountNumber: "1234567890"}, nil
+}
+
+func (r *fakeRepository) CreateVPAMapping(ctx context.Context, tx *sql.Tx, mapping *repository.VPAMapping) error {
+	if existing, ok := r.vpas[mapping.VPA]; ok && existing.IsActive {
+		return repository.ErrVPAAlreadyRegistered
+	}
+	mapping.IsActive = true
+	copied := *mapping
+	r.vpas[mapping.VPA] = &copied
+	return nil
+}
+
+func (r *fakeRepository) UpdateVPAMapping(ctx context.Context, tx *sql.Tx, vpa string, mapping *repository.VPAMapping) error {
+	existing, ok := r.vpas[vpa]
+	if !ok || !existing.IsActive {
+		return sql.ErrNoRows
+	}
+	existing.AccountNumber = mapping.AccountNumber
+	return nil
+}
+
+func (r *fakeRepository) DeactivateVPA(ctx context.Context, tx *sql.Tx, vpa string) error {
+	existing, ok := r.vpas[vpa]
+	if !ok || !existing.IsActive {
+		return sql.ErrNoRows
+	}
+	existing.IsActive = false
+	return nil
+}
+
+func (r *fakeRepository) CreateMandate(ctx context.Context, tx *sql.Tx, mandate *repository.Mandate) error {
+	if _, ok := r.mandates[mandate.MandateID]; ok {
+		return repository.ErrMandateExists
+	}
+	copied := *mandate
+	r.mandates[mandate.MandateID] = &copied
+	return nil
+}
+
+func (r *fakeRepository) GetMandate(ctx context.Context, mandateID string) (*repository.Mandate, error) {
+	mandate, ok := r.mandates[mandateID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *mandate
+	return &copied, nil
+}
+
+func (r *fakeRepository) LockMandate(ctx context.Context, tx *sql.Tx, mandateID string) (*repository.Mandate, error) {
+	return r.GetMandate(ctx, mandateID)
+}
+
+func (r *fakeRepository) UpdateMandate(ctx context.Context, tx *sql.Tx, mandate *repository.Mandate) error {
+	copied := *mandate
+	r.staged = append(r.staged, func() { r.mandates[mandate.MandateID] = &copied })
+	return nil
+}
+
+func (r *fakeRepository) ListDueMandates(ctx context.Context, asOf time.Time, limit int) ([]string, error) {
+	var ids []string
+	for id, m := range r.mandates {
+		if m.Status == repository.MandateActive && !m.NextDueDate.After(asOf) && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeRepository) CreateMandateExecution(ctx context.Context, tx *sql.Tx, execution *repository.MandateExecution) (bool, error) {
+	for _, e := range r.executions {
+		if e.MandateID == execution.MandateID && e.DueDate.Equal(execution.DueDate) {
+			return false, nil
+		}
+	}
+	execution.Status = repository.ExecutionPending
+	copied := *execution
+	r.staged = append(r.staged, func() { r.executions = append(r.executions, &copied) })
+	return true, nil
+}
+
+func (r *fakeRepository) FinishMandateExecution(ctx context.Context, mandateID string, dueDate time.Time, status repository.ExecutionStatus, errorCode, errorMessage string) error {
+	for _, e := range r.executions {
+		if e.MandateID == mandateID && e.DueDate.Equal(dueDate) && e.Status == repository.ExecutionPending {
+			e.Status, e.ErrorCode = status, errorCode
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// ListPendingMandateExecutions treats every PENDING execution as stale
+func (r *fakeRepository) ListPendingMandateExecutions(ctx context.Context, before time.Time, limit int) ([]*repository.MandateExecution, error) {
+	var pending []*repository.MandateExecution
+	for _, e := range r.executions {
+		if e.Status == repository.ExecutionPending && len(pending) < limit {
+			copied := *e
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (r *fakeRepository) ListMandateExecutions(ctx context.Context, mandateID string, limit int) ([]*repository.MandateExecution, error) {
+	var executions []*repository.MandateExecution
+	for i := len(r.executions) - 1; i >= 0 && len(executions) < limit; i-- {
+		if r.executions[i].MandateID == mandateID {
+			executions = append(executions, r.executions[i])
+		}
+	}
+	return executions, nil
+}
+
+func (r *fakeRepository) CreateCollectRequest(ctx context.Context, tx *sql.Tx, request *repository.CollectRequest) error {
+	if _, ok := r.collects[request.CollectID]; ok {
+		return repository.ErrCollectRequestExists
+	}
+	request.Status = repository.CollectPending
+	copied := *request
+	r.collects[request.CollectID] = &copied
+	return nil
+}
+
+func (r *fakeRepository) GetCollectRequest(ctx context.Context, collectID string) (*repository.CollectRequest, error) {
+	request, ok := r.collects[collectID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *request
+	return &copied, nil
+}
+
+func (r *fakeRepository) LockCollectRequest(ctx context.Context, tx *sql.Tx, collectID string) (*repository.CollectRequest, error) {
+	return r.GetCollectRequest(ctx, collectID)
+}
+
+func (r *fakeRepository) FinishCollectRequest(ctx context.Context, tx *sql.Tx, collectID string, status repository.CollectStatus, errorCode, errorMessage string) error {
+	r.staged = append(r.staged, func() {
+		request := r.collects[collectID]
+		request.Status = status
+		request.ErrorCode, request.ErrorMessage = errorCode, errorMessage
+	})
+	return nil
+}
+
+func (r *fakeRepository) MarkCollectRequestDelivered(ctx context.Context, collectID string) error {
+	now := time.Now()
+	r.collects[collectID].DeliveredAt = &now
+	return nil
+}
+
+func (r *fakeRepository) CreateSagaState(ctx context.Context, tx *sql.Tx, transactionID string) error {
+	r.sagas[transactionID] = &repository.SagaState{TransactionID: transactionID, Step: repository.SagaStarted, UpdatedAt: time.Now()}
+	return nil
+}
+
+// UpdateSagaStep applies at once, even in a database transaction
+func (r *fakeRepository) UpdateSagaStep(ctx context.Context, tx *sql.Tx, transactionID string, step repository.SagaStep, lastError string) error {
+	if state, ok := r.sagas[transactionID]; ok {
+		state.Step, state.LastError, state.UpdatedAt = step, lastError, time.Now()
+	}
+	return nil
+}
+
+func sagaInFlight(state *repository.SagaState) bool {
+	switch state.Step {
+	case repository.SagaCompleted, repository.SagaCompensated, repository.SagaFailed:
+		return false
+	}
+	return true
+}
+
+func (r *fakeRepository) ListStaleSagas(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	var ids []string
+	for id, state := range r.sagas {
+		if sagaInFlight(state) && !state.UpdatedAt.After(before) && r.transactions[id].Status == repository.StatusPending && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeRepository) ClaimSaga(ctx context.Context, transactionID string, before time.Time) (*repository.SagaState, error) {
+	state, ok := r.sagas[transactionID]
+	if !ok || !sagaInFlight(state) || state.UpdatedAt.After(before) {
+		return nil, sql.ErrNoRows
+	}
+	state.UpdatedAt = time.Now()
+	copied := *state
+	return &copied, nil
+}
+
+func (r *fakeRepository) CreateFeeRule(ctx context.Context, rule *repository.FeeRule) error {
+	rule.RuleID = int64(len(r.feeRules) + 1)
+	stored := *rule
+	r.feeRules[rule.RuleID] = &stored
+	return nil
+}
+
+func (r *fakeRepository) UpdateFeeRule(ctx context.Context, rule *repository.FeeRule) error {
+	if _, ok := r.feeRules[rule.RuleID]; !ok {
+		return sql.ErrNoRows
+	}
+	stored := *rule
+	r.feeRules[rule.RuleID] = &stored
+	return nil
+}
+
+func (r *fakeRepository) DeleteFeeRule(ctx context.Context, ruleID int64) error {
+	if _, ok := r.feeRules[ruleID]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.feeRules, ruleID)
+	return nil
+}
+
+func (r *fakeRepository) GetFeeRule(ctx context.Context, ruleID int64) (*repository.FeeRule, error) {
+	rule, ok := r.feeRules[ruleID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *rule
+	return &copied, nil
+}
+
+func (r *fakeRepository) ListFeeRules(ctx context.Context) ([]*repository.FeeRule, error) {
+	var rules []*repository.FeeRule
+	for _, rule := range r.feeRules {
+		copied := *rule
+		rules = append(rules, &copied)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].RuleID < rules[j].RuleID })
+	return rules, nil
+}
+
+func (r *fakeRepository) ClaimReconRun(ctx context.Context, businessDate time.Time, staleBefore time.Time) (bool, error) {
+	key := businessDate.Format(time.DateOnly)
+	if run, ok := r.reconRuns[key]; ok && run.Status == repository.ReconRunning && !run.StartedAt.Before(staleBefore) {
+		return false, nil
+	}
+	r.reconRuns[key] = &repository.ReconRun{BusinessDate: businessDate, Status: repository.ReconRunning, StartedAt: time.Now()}
+	var kept []*repository.ReconException
+	for _, e := range r.exceptions {
+		if !e.BusinessDate.Equal(businessDate) {
+			kept = append(kept, e)
+		}
+	}
+	r.exceptions = kept
+	return true, nil
+}
+
+func (r *fakeRepository) FinishReconRun(ctx context.Context, run *repository.ReconRun) error {
+	now := time.Now()
+	run.StartedAt = r.reconRuns[run.BusinessDate.Format(time.DateOnly)].StartedAt
+	run.CompletedAt = &now
+	copied := *run
+	r.reconRuns[run.BusinessDate.Format(time.DateOnly)] = &copied
+	return nil
+}
+
+func (r *fakeRepository) GetReconRun(ctx context.Context, businessDate time.Time) (*repository.ReconRun, error) {
+	run, ok := r.reconRuns[businessDate.Format(time.DateOnly)]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *run
+	return &copied, nil
+}
+
+func (r *fakeRepository) CreateReconException(ctx context.Context, exception *repository.ReconException) error {
+	r.exceptions = append(r.exceptions, exception)
+	return nil
+}
+
+func (r *fakeRepository) ListReconExceptions(ctx context.Context, businessDate time.Time) ([]*repository.ReconException, error) {
+	var exceptions []*repository.ReconException
+	for _, e := range r.exceptions {
+		if e.BusinessDate.Equal(businessDate) {
+			exceptions = append(exceptions, e)
+		}
+	}
+	return exceptions, nil
+}
+
+func (r *fakeRepository) LogAudit(ctx context.Context, tx *sql.Tx, entityType string, entityID string, action string, actor string, oldValues map[string]interface{}, newValues map[string]interface{}, correlationID string) error {
+	r.audits = append(r.audits, action+" "+entityID)
+	return nil
+}
+
+// fakeBankClients answers every call with the configured error, or rejects
+// it if reject says so, recording the requests. Status queries are answered
+// from records, keyed by bank code and transaction ID joined by "/".
+type fakeBankClients struct {
+	err      error
+	reject   func(*BankTransactionRequest) bool
+	requests []*BankTransactionRequest
+	records  map[string]*BankTransactionStatus
+}
+
+func (b *fakeBankClients) Client(bankCode string) (BankClient, error) {
+	return b, nil
+}
+
+func (b *fakeBankClients) ProcessTransaction(ctx context.Context, req *BankTransactionRequest) (*BankTransactionResponse, error) {
+	b.requests = append(b.requests, req)
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.reject != nil && b.reject(req) {
+		return &BankTransactionResponse{TransactionID: req.TransactionID, Status: "FAILED", ErrorCode: "ACCOUNT_CLOSED"}, nil
+	}
+	return &BankTransactionResponse{TransactionID: req.TransactionID, BankReferenceID: req.BankCode + "REF", Status: "SUCCESS"}, nil
+}
+
+func (b *fakeBankClients) GetAccountBalance(ctx context.Context, bankCode, accountNumber string) (int64, error) {
+	return 0, nil
+}
+
+func (b *fakeBankClients) CheckAccountStatus(ctx context.Context, bankCode, accountNumber string) (string, error) {
+	return "ACTIVE", nil
+}
+
+func (b *fakeBankClients) GetTransactionStatus(ctx context.Context, bankCode, transactionID string) (*BankTransactionStatus, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	record, ok := b.records[bankCode+"/"+transactionID]
+	if !ok {
+		return nil, ErrBankTransactionNotFound
+	}
+	return record, nil
+}
+
+// fakePublisher records the event types it is given per transaction
+type fakePublisher struct {
+	events map[string][]string
+}
+
+func (p *fakePublisher) PublishTransactionEvent(ctx context.Context, transactionID string, event []byte) error {
+	var decoded struct {
+		EventType string `json:"event_type"`
+	}
+	json.Unmarshal(event, &decoded)
+	if p.events == nil {
+		p.events = make(map[string][]string)
+	}
+	p.events[transactionID] = append(p.events[transactionID], decoded.EventType)
+	return nil
+}
+
+// fakeStatusBus passes status updates to the subscribers of a transaction
+// in memory
+type fakeStatusBus struct {
+	mu          sync.Mutex
+	subscribers map[string][]chan []byte
+}
+
+func (b *fakeStatusBus) PublishTransactionStatus(ctx context.Context, transactionID string, update []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, subscriber := range b.subscribers[transactionID] {
+		subscriber <- update
+	}
+	return nil
+}
+
+func (b *fakeStatusBus) SubscribeTransactionStatus(ctx context.Context, transactionID string) (<-chan []byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[string][]chan []byte)
+	}
+	updates := make(chan []byte, 10)
+	b.subscribers[transactionID] = append(b.subscribers[transactionID], updates)
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		subscribers := b.subscribers[transactionID]
+		for i, subscriber := range subscribers {
+			if subscriber == updates {
+				b.subscribers[transactionID] = append(subscribers[:i], subscribers[i+1:]...)
+				break
+			}
+		}
+		close(updates)
+	}()
+	return updates, nil
+}
+
+func pendingTransaction(id string, expiresIn time.Duration, debitReference string) *repository.Transaction {
+	expiresAt := time.Now().Add(expiresIn)
+	return &repository.Transaction{
+		TransactionID:      id,
+		PayerVPA:           "alice@hdfc",
+		PayerBankCode:      "HDFC",
+		AmountPaisa:        500,
+		SettledAmountPaisa: 500,
+		Status:             repository.StatusPending,
+		ExpiresAt:          &expiresAt,
+		DebitReference:     debitReference,
+	}
+}
+
+func newTestReaper(repo *fakeRepository, banks *fakeBankClients, events *fakePublisher) *Reaper {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewReaper(repo, banks, events, nil, config.ReaperConfig{BatchSize: 10, GracePeriod: time.Second}, logger)
+}
+
+func TestReaperTimesOutExpiredTransactions(t *testing.T) {
+	repo := newFakeRepository(
+		pendingTransaction("EXPIRED", -time.Minute, ""),
+		pendingTransaction("WITHIN_GRACE", -time.Millisecond, ""),
+		pendingTransaction("LIVE", time.Minute, ""),
+	)
+	banks := &fakeBankClients{}
+	events := &fakePublisher{}
+
+	reaped, err := newTestReaper(repo, banks, events).Reap(context.Background())
+	if err != nil || reaped != 1 {
+		t.Fatalf("reaped %d, %v; want 1", reaped, err)
+	}
+	for id, want := range map[string]repository.TransactionStatus{
+		"EXPIRED":      repository.StatusTimeout,
+		"WITHIN_GRACE": repository.StatusPending,
+		"LIVE":         repository.StatusPending,
+	} {
+		if got := repo.transactions[id].Status; got != want {
+			t.Errorf("%s is %s, want %s", id, got, want)
+		}
+	}
+	if len(banks.requests) != 0 {
+		t.Errorf("banks called %d times for a transaction that was never debited", len(banks.requests))
+	}
+	if got := events.events["EXPIRED"]; len(got) != 1 || got[0] != "TRANSACTION_TIMEOUT" {
+		t.Errorf("events = %v", got)
+	}
+}
+
+func TestReaperReversesDebitOfExpiredTransaction(t *testing.T) {
+	repo := newFakeRepository(pendingTransaction("DEBITED", -time.Minute, "HDFC123"))
+	banks := &fakeBankClients{}
+	events := &fakePublisher{}
+
+	if reaped, err := newTestReaper(repo, banks, events).Reap(context.Background()); err != nil || reaped != 1 {
+		t.Fatalf("reaped %d, %v; want 1", reaped, err)
+	}
+	if len(banks.requests) != 1 {
+		t.Fatalf("banks called %d times, want one reversal", len(banks.requests))
+	}
+	if req := banks.requests[0]; req.Type != "CREDIT" || req.AccountNumber != "1234567890" || req.AmountPaisa != 500 || req.Reference != "REVERSAL_HDFC123" {
+		t.Errorf("reversal request = %+v", req)
+	}
+	if got := repo.history["DEBITED"]; len(got) != 2 || got[0] != repository.StatusTimeout || got[1] != repository.StatusReversed {
+		t.Errorf("status history = %v, want TIMEOUT then REVERSED", got)
+	}
+	if got := events.events["DEBITED"]; len(got) != 2 || got[1] != "REVERSAL_SUCCESS" {
+		t.Errorf("events = %v", got)
+	}
+}
+
+func TestReaperRetriesFailedReversal(t *testing.T) {
+	repo := newFakeRepository(pendingTransaction("DEBITED", -time.Minute, "HDFC123"))
+	banks := &fakeBankClients{err: errors.New("bank unreachable")}
+	events := &fakePublisher{}
+	reaper := newTestReaper(repo, banks, events)
+
+	if reaped, err := reaper.Reap(context.Background()); err != nil || reaped != 0 {
+		t.Fatalf("reaped %d, %v; want 0", reaped, err)
+	}
+	if got := repo.transactions["DEBITED"].Status; got != repository.StatusPending {
+		t.Fatalf("status after failed reversal = %s, want PENDING", got)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("events published for a rolled back timeout: %v", events.events)
+	}
+
+	banks.err = nil
+	if reaped, err := reaper.Reap(context.Background()); err != nil || reaped != 1 {
+		t.Fatalf("retry reaped %d, %v; want 1", reaped, err)
+	}
+	if got := repo.transactions["DEBITED"].Status; got != repository.StatusReversed {
+		t.Errorf("status after retry = %s, want REVERSED", got)
+	}
+}
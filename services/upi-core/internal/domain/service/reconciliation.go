@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+var (
+	// ErrInvalidReconRequest wraps the reason a reconciliation request was
+	// rejected
+	ErrInvalidReconRequest = errors.New("invalid reconciliation request")
+	// ErrReconInProgress is returned when the day is being reconciled
+	// already
+	ErrReconInProgress = errors.New("reconciliation of the day is in progress")
+	// ErrReconRunNotFound is returned for a day that has not been
+	// reconciled
+	ErrReconRunNotFound = errors.New("day has not been reconciled")
+)
+
+// bankNotFound is the bank status of an exception where the bank has no
+// record of the transaction
+const bankNotFound = "NOT_FOUND"
+
+// businessDayBounds returns when a business day, given as midnight UTC,
+// starts and ends. Business days are days in India, like mandate due dates.
+func businessDayBounds(day time.Time) (time.Time, time.Time) {
+	y, m, d := day.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, mandateZone)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// Reconciler compares the switch's record of each finished transaction of
+// a business day with the records of the payer's and payee's banks, and
+// records the transactions they disagree on as exceptions. It reconciles
+// the previous day every night; any number of instances can run it side by
+// side.
+type Reconciler struct {
+	repo        repository.TransactionRepository
+	bankClients BankClients
+	cfg         config.ReconConfig
+	runAt       time.Duration // Into the day
+	logger      *logrus.Logger
+	now         func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewReconciler creates a reconciler; Start runs it in the background
+func NewReconciler(repo repository.TransactionRepository, bankClients BankClients, cfg config.ReconConfig, logger *logrus.Logger) (*Reconciler, error) {
+	if cfg.RunAt == "" {
+		cfg.RunAt = "02:00"
+	}
+	runAt, err := time.Parse("15:04", cfg.RunAt)
+	if err != nil {
+		return nil, fmt.Errorf("recon.run_at %q is not a HH:MM time", cfg.RunAt)
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 5 * time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 200
+	}
+	if cfg.StaleAfter <= 0 {
+		cfg.StaleAfter = 2 * time.Hour
+	}
+	return &Reconciler{
+		repo:        repo,
+		bankClients: bankClients,
+		cfg:         cfg,
+		runAt:       time.Duration(runAt.Hour())*time.Hour + time.Duration(runAt.Minute())*time.Minute,
+		logger:      logger,
+		now:         time.Now,
+	}, nil
+}
+
+// Run reconciles the transactions created on a business day that has
+// ended, replacing the exceptions of any earlier run of the day. A failed
+// run is recorded as FAILED and returned with its error.
+func (r *Reconciler) Run(ctx context.Context, day time.Time) (*repository.ReconRun, error) {
+	y, m, d := day.Date()
+	day = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if !day.Before(mandateDay(r.now())) {
+		return nil, fmt.Errorf("%w: %s has not ended", ErrInvalidReconRequest, day.Format(time.DateOnly))
+	}
+
+	claimed, err := r.repo.ClaimReconRun(ctx, day, r.now().Add(-r.cfg.StaleAfter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start reconciliation: %w", err)
+	}
+	if !claimed {
+		return nil, ErrReconInProgress
+	}
+
+	logger := r.logger.WithField("business_date", day.Format(time.DateOnly))
+	logger.Info("Reconciling transactions against the banks")
+
+	run := &repository.ReconRun{BusinessDate: day, Status: repository.ReconCompleted}
+	runErr := r.reconcileDay(ctx, run)
+	if runErr != nil {
+		run.Status = repository.ReconFailed
+		run.ErrorMessage = runErr.Error()
+	}
+	if err := r.repo.FinishReconRun(context.WithoutCancel(ctx), run); err != nil {
+		logger.WithError(err).Error("Failed to record the outcome of reconciliation")
+	}
+	if runErr != nil {
+		return run, runErr
+	}
+
+	logger = logger.WithFields(logrus.Fields{
+		"checked":    run.TransactionsChecked,
+		"unverified": run.TransactionsUnverified,
+		"exceptions": run.ExceptionsFound,
+	})
+	if run.ExceptionsFound > 0 || run.TransactionsUnverified > 0 {
+		logger.Warn("Reconciliation found transactions the banks disagree on or could not confirm")
+	} else {
+		logger.Info("Reconciliation found no exceptions")
+	}
+	return run, nil
+}
+
+// reconcileDay checks the day's finished transactions a page at a time.
+// Transactions still PENDING are left to the reaper.
+func (r *Reconciler) reconcileDay(ctx context.Context, run *repository.ReconRun) error {
+	from, to := businessDayBounds(run.BusinessDate)
+	filter := repository.TransactionFilter{From: from, To: to, Limit: r.cfg.BatchSize}
+	for {
+		transactions, err := r.repo.ListTransactions(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list transactions: %w", err)
+		}
+
+		for _, transaction := range transactions {
+			if transaction.Status == repository.StatusPending {
+				continue
+			}
+			exceptions, err := r.check(ctx, transaction)
+			if err != nil {
+				r.logger.WithError(err).WithField("transaction_id", transaction.TransactionID).Warn("Could not reconcile transaction")
+				run.TransactionsUnverified++
+				continue
+			}
+			run.TransactionsChecked++
+
+			for _, exception := range exceptions {
+				exception.BusinessDate = run.BusinessDate
+				if err := r.repo.CreateReconException(ctx, exception); err != nil {
+					return fmt.Errorf("failed to record exception of %s: %w", transaction.TransactionID, err)
+				}
+				run.ExceptionsFound++
+			}
+		}
+
+		if len(transactions) < filter.Limit {
+			return nil
+		}
+		last := transactions[len(transactions)-1]
+		filter.After = &repository.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// check compares a finished transaction with its banks' records. A
+// successful transaction must have been debited and credited for its
+// settled amount; an unsuccessful one must not have been debited, or must
+// have had the debit reversed.
+func (r *Reconciler) check(ctx context.Context, transaction *repository.Transaction) ([]*repository.ReconException, error) {
+	debit, err := r.bankRecord(ctx, transaction.PayerBankCode, transaction.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var exceptions []*repository.ReconException
+	exception := func(exceptionType repository.ReconExceptionType, bankCode string, record *BankTransactionStatus, details string) {
+		e := &repository.ReconException{
+			TransactionID:     transaction.TransactionID,
+			Type:              exceptionType,
+			BankCode:          bankCode,
+			SwitchStatus:      transaction.Status,
+			BankStatus:        bankNotFound,
+			SwitchAmountPaisa: transaction.SettledAmountPaisa,
+			Details:           details,
+		}
+		if record != nil {
+			e.BankStatus = record.Status
+			e.BankAmountPaisa = record.AmountPaisa
+		}
+		exceptions = append(exceptions, e)
+	}
+
+	if transaction.Status != repository.StatusSuccess {
+		if !succeeded(debit) {
+			return nil, nil
+		}
+		reversal, err := r.bankRecord(ctx, transaction.PayerBankCode, reversalTransactionID(transaction.TransactionID))
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !succeeded(reversal):
+			exception(repository.ExceptionOrphanDebit, transaction.PayerBankCode, debit, "Payer was debited for a transaction that did not succeed and the debit was not reversed")
+		case reversal.AmountPaisa != debit.AmountPaisa:
+			exception(repository.ExceptionAmountMismatch, transaction.PayerBankCode, reversal,
+				fmt.Sprintf("Reversal of %d paisa does not match the debit of %d paisa", reversal.AmountPaisa, debit.AmountPaisa))
+		}
+		return exceptions, nil
+	}
+
+	switch {
+	case !succeeded(debit):
+		exception(repository.ExceptionMissingDebit, transaction.PayerBankCode, debit, "Payer bank has no successful debit for a successful transaction")
+	case debit.AmountPaisa != transaction.SettledAmountPaisa:
+		exception(repository.ExceptionAmountMismatch, transaction.PayerBankCode, debit, "Payer bank debited another amount than was settled")
+	}
+
+	credit, err := r.bankRecord(ctx, transaction.PayeeBankCode, transaction.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !succeeded(credit):
+		exception(repository.ExceptionMissingCredit, transaction.PayeeBankCode, credit, "Payee bank has no successful credit for a successful transaction")
+	case credit.AmountPaisa != transaction.SettledAmountPaisa:
+		exception(repository.ExceptionAmountMismatch, transaction.PayeeBankCode, credit, "Payee bank credited another amount than was settled")
+	}
+	return exceptions, nil
+}
+
+// bankRecord returns a bank's record of a transaction ID, or nil if it has
+// none
+func (r *Reconciler) bankRecord(ctx context.Context, bankCode, transactionID string) (*BankTransactionStatus, error) {
+	bankClient, err := r.bankClients.Client(bankCode)
+	if err != nil {
+		return nil, fmt.Errorf("no client for bank %s: %w", bankCode, err)
+	}
+	record, err := bankClient.GetTransactionStatus(ctx, bankCode, transactionID)
+	if errors.Is(err, ErrBankTransactionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status of %s from bank %s: %w", transactionID, bankCode, err)
+	}
+	return record, nil
+}
+
+func succeeded(record *BankTransactionStatus) bool {
+	return record != nil && record.Status == "SUCCESS"
+}
+
+// Report returns the latest run of a business day and the exceptions it
+// found
+func (r *Reconciler) Report(ctx context.Context, day time.Time) (*repository.ReconRun, []*repository.ReconException, error) {
+	y, m, d := day.Date()
+	day = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	run, err := r.repo.GetReconRun(ctx, day)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrReconRunNotFound, day.Format(time.DateOnly))
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load reconciliation run: %w", err)
+	}
+	exceptions, err := r.repo.ListReconExceptions(ctx, day)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list reconciliation exceptions: %w", err)
+	}
+	return run, exceptions, nil
+}
+
+// runDue reconciles the previous day once it is past the run time, unless
+// it has been already. A failed run is retried on every check.
+func (r *Reconciler) runDue(ctx context.Context) {
+	now := r.now()
+	today := mandateDay(now)
+	if start, _ := businessDayBounds(today); now.Before(start.Add(r.runAt)) {
+		return
+	}
+
+	day := today.AddDate(0, 0, -1)
+	run, err := r.repo.GetReconRun(ctx, day)
+	if err == nil && run.Status == repository.ReconCompleted {
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		r.logger.WithError(err).Error("Failed to load reconciliation run")
+		return
+	}
+
+	if _, err := r.Run(ctx, day); err != nil && !errors.Is(err, ErrReconInProgress) {
+		r.logger.WithError(err).WithField("business_date", day.Format(time.DateOnly)).Error("Reconciliation failed, retrying on the next check")
+	}
+}
+
+// Start reconciles the previous day every night until Close
+func (r *Reconciler) Start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run()
+}
+
+func (r *Reconciler) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-r.stop
+		cancel()
+	}()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.runDue(ctx)
+		}
+	}
+}
+
+// Close stops the nightly runs, waiting for one in progress to finish
+func (r *Reconciler) Close() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+	}
+}
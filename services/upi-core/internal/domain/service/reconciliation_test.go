@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+// reconNow is 03:00 on 10 March 2026 in India, after the nightly run time
+var reconNow = time.Date(2026, 3, 10, 3, 0, 0, 0, mandateZone)
+
+var reconDay = time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+func newTestReconciler(t *testing.T, repo *fakeRepository, banks *fakeBankClients) *Reconciler {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	r, err := NewReconciler(repo, banks, config.ReconConfig{RunAt: "02:00", BatchSize: 2}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now = func() time.Time { return reconNow }
+	return r
+}
+
+// finishedTransaction is a transaction of 500 paisa from an HDFC payer to
+// an SBI payee, created at the given hour of 9 March 2026 in India
+func finishedTransaction(id string, status repository.TransactionStatus, hour int) *repository.Transaction {
+	return &repository.Transaction{
+		ID:                 id,
+		TransactionID:      id,
+		PayerBankCode:      "HDFC",
+		PayeeBankCode:      "SBI",
+		AmountPaisa:        500,
+		SettledAmountPaisa: 500,
+		Status:             status,
+		CreatedAt:          time.Date(2026, 3, 9, hour, 0, 0, 0, mandateZone),
+	}
+}
+
+func bankRecord(status string, amountPaisa int64) *BankTransactionStatus {
+	return &BankTransactionStatus{Status: status, AmountPaisa: amountPaisa}
+}
+
+func TestReconcilerFlagsMismatches(t *testing.T) {
+	repo := newFakeRepository(
+		finishedTransaction("MATCHED", repository.StatusSuccess, 1),
+		finishedTransaction("NO_CREDIT", repository.StatusSuccess, 2),
+		finishedTransaction("SHORT_CREDIT", repository.StatusSuccess, 3),
+		finishedTransaction("NO_DEBIT", repository.StatusSuccess, 4),
+		finishedTransaction("DECLINED", repository.StatusFailed, 5),
+		finishedTransaction("ORPHAN", repository.StatusTimeout, 6),
+		finishedTransaction("REVERSED", repository.StatusReversed, 7),
+		finishedTransaction("UNREVERSED", repository.StatusReversed, 8),
+		finishedTransaction("IN_FLIGHT", repository.StatusPending, 9),
+		// Created just after the day ended in India
+		finishedTransaction("NEXT_DAY", repository.StatusSuccess, 24),
+	)
+	banks := &fakeBankClients{records: map[string]*BankTransactionStatus{
+		"HDFC/MATCHED":            bankRecord("SUCCESS", 500),
+		"SBI/MATCHED":             bankRecord("SUCCESS", 500),
+		"HDFC/NO_CREDIT":          bankRecord("SUCCESS", 500),
+		"SBI/NO_CREDIT":           bankRecord("FAILED", 500),
+		"HDFC/SHORT_CREDIT":       bankRecord("SUCCESS", 500),
+		"SBI/SHORT_CREDIT":        bankRecord("SUCCESS", 50),
+		"SBI/NO_DEBIT":            bankRecord("SUCCESS", 500),
+		"HDFC/DECLINED":           bankRecord("FAILED", 500),
+		"HDFC/ORPHAN":             bankRecord("SUCCESS", 500),
+		"HDFC/REVERSED":           bankRecord("SUCCESS", 500),
+		"HDFC/REVERSED_REVERSE":   bankRecord("SUCCESS", 500),
+		"HDFC/UNREVERSED":         bankRecord("SUCCESS", 500),
+		"HDFC/UNREVERSED_REVERSE": bankRecord("FAILED", 500),
+	}}
+	r := newTestReconciler(t, repo, banks)
+
+	run, err := r.Run(context.Background(), reconDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != repository.ReconCompleted || run.TransactionsChecked != 8 || run.TransactionsUnverified != 0 || run.ExceptionsFound != 5 {
+		t.Errorf("run = %+v, want COMPLETED with 8 checked and 5 exceptions", run)
+	}
+
+	var got []string
+	for _, e := range repo.exceptions {
+		got = append(got, e.TransactionID+" "+string(e.Type)+" "+e.BankCode+" "+e.BankStatus)
+	}
+	sort.Strings(got)
+	want := []string{
+		"NO_CREDIT MISSING_CREDIT SBI FAILED",
+		"NO_DEBIT MISSING_DEBIT HDFC NOT_FOUND",
+		"ORPHAN ORPHAN_DEBIT HDFC SUCCESS",
+		"SHORT_CREDIT AMOUNT_MISMATCH SBI SUCCESS",
+		"UNREVERSED ORPHAN_DEBIT HDFC SUCCESS",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("exceptions = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("exceptions = %q, want %q", got, want)
+			break
+		}
+	}
+}
+
+func TestReconcilerCountsUnreachableBanksAsUnverified(t *testing.T) {
+	repo := newFakeRepository(finishedTransaction("TXN1", repository.StatusSuccess, 10))
+	banks := &fakeBankClients{err: status.Error(codes.Unavailable, "bank down")}
+	r := newTestReconciler(t, repo, banks)
+
+	run, err := r.Run(context.Background(), reconDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.TransactionsChecked != 0 || run.TransactionsUnverified != 1 || len(repo.exceptions) != 0 {
+		t.Errorf("run = %+v with exceptions %v, want the transaction unverified and no exceptions", run, repo.exceptions)
+	}
+}
+
+func TestReconcilerRunsPreviousDayOnceDue(t *testing.T) {
+	repo := newFakeRepository(finishedTransaction("TXN1", repository.StatusSuccess, 10))
+	banks := &fakeBankClients{records: map[string]*BankTransactionStatus{"HDFC/TXN1": bankRecord("SUCCESS", 500)}}
+	r := newTestReconciler(t, repo, banks)
+
+	if _, err := r.Run(context.Background(), reconNow); !errors.Is(err, ErrInvalidReconRequest) {
+		t.Errorf("reconciling today: err = %v, want ErrInvalidReconRequest", err)
+	}
+
+	r.now = func() time.Time { return reconNow.Add(-2 * time.Hour) }
+	r.runDue(context.Background())
+	if _, _, err := r.Report(context.Background(), reconDay); !errors.Is(err, ErrReconRunNotFound) {
+		t.Fatalf("report before the run time: err = %v, want ErrReconRunNotFound", err)
+	}
+
+	r.now = func() time.Time { return reconNow }
+	r.runDue(context.Background())
+	run, exceptions, err := r.Report(context.Background(), reconDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != repository.ReconCompleted || len(exceptions) != 1 || exceptions[0].Type != repository.ExceptionMissingCredit {
+		t.Fatalf("run = %+v with exceptions %v, want COMPLETED with a missing credit", run, exceptions)
+	}
+
+	// A completed day is not reconciled again by the nightly check
+	banks.records["SBI/TXN1"] = bankRecord("SUCCESS", 500)
+	r.runDue(context.Background())
+	if _, exceptions, _ := r.Report(context.Background(), reconDay); len(exceptions) != 1 {
+		t.Errorf("exceptions after a second check = %v, want the first run's", exceptions)
+	}
+
+	// but can be on request, replacing its exceptions
+	if _, err := r.Run(context.Background(), reconDay); err != nil {
+		t.Fatal(err)
+	}
+	if _, exceptions, _ := r.Report(context.Background(), reconDay); len(exceptions) != 0 {
+		t.Errorf("exceptions after a rerun = %v, want none", exceptions)
+	}
+}
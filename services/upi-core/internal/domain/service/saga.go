@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/upierr"
+)
+
+// saga takes transactions through their steps: debit the payer, credit the
+// payee, and reverse the debit if the credit fails. Each step is recorded
+// in saga_state, so the SagaRecovery can pick up a transaction whose
+// processor stopped midway.
+type saga struct {
+	repo        repository.TransactionRepository
+	bankClients BankClients
+	statuses    StatusBus
+	inFlight    *inFlight // Of the TransactionService; nil for the SagaRecovery
+	logger      *logrus.Logger
+}
+
+// run takes a PENDING transaction's saga on from state to its end. A
+// transaction past its debit must have its DebitReference set. A failed
+// reversal leaves the saga COMPENSATING and the transaction PENDING, for
+// the SagaRecovery or the reaper to reverse the debit later.
+func (s *saga) run(ctx context.Context, result *TransactionResult, payerMapping, payeeMapping *repository.VPAMapping, state *repository.SagaState) error {
+	transaction := result.Transaction
+	transactionID := transaction.TransactionID
+
+	switch state.Step {
+	case repository.SagaStarted, repository.SagaDebitPending:
+		// A debit sent again keeps its transaction ID, which banks process
+		// at most once
+		s.record(ctx, transactionID, repository.SagaDebitPending, "")
+		result.addEvent("DEBIT_INITIATED", "Initiating debit from payer account", map[string]interface{}{
+			"bank_code": payerMapping.BankCode,
+			"account":   payerMapping.AccountNumber,
+			"amount":    transaction.SettledAmountPaisa,
+		})
+
+		if s.checkpointed(transactionID) {
+			return errSagaCheckpointed
+		}
+		payerResponse, err := sendDebit(ctx, s.bankClients, transaction, payerMapping)
+		if err != nil {
+			code := upierr.CodeOf(err, upierr.DebitFailed)
+			if s.finish(ctx, transactionID, repository.StatusFailed, repository.SagaFailed, "Debit failed", code, err.Error()) == nil {
+				transaction.Status = repository.StatusFailed
+				transaction.ErrorCode, transaction.ErrorMessage = string(code), err.Error()
+			}
+			result.addEvent("DEBIT_FAILED", "Debit processing failed", map[string]interface{}{
+				"error":      err.Error(),
+				"error_code": string(code),
+			})
+			return upierr.Wrap(code, fmt.Errorf("debit processing failed: %w", err))
+		}
+
+		result.PayerResponse = payerResponse
+		result.addEvent("DEBIT_SUCCESS", "Debit processed successfully", map[string]interface{}{
+			"bank_reference_id": payerResponse.BankReferenceID,
+			"new_balance":       payerResponse.AccountBalancePaisa,
+		})
+
+		// Record the debit so it can be reversed if we go no further
+		transaction.DebitReference = payerResponse.BankReferenceID
+		if err := s.repo.RecordDebit(ctx, nil, transactionID, payerResponse.BankReferenceID); err != nil {
+			s.logger.WithError(err).WithField("transaction_id", transactionID).Error("Failed to record debit")
+		}
+		s.record(ctx, transactionID, repository.SagaDebitDone, "")
+		fallthrough
+
+	case repository.SagaDebitDone, repository.SagaCreditPending:
+		s.record(ctx, transactionID, repository.SagaCreditPending, "")
+		result.addEvent("CREDIT_INITIATED", "Initiating credit to payee account", map[string]interface{}{
+			"bank_code": payeeMapping.BankCode,
+			"account":   payeeMapping.AccountNumber,
+			"amount":    transaction.SettledAmountPaisa,
+		})
+
+		if s.checkpointed(transactionID) {
+			return errSagaCheckpointed
+		}
+		payeeResponse, err := sendCredit(ctx, s.bankClients, transaction, payeeMapping)
+		if err != nil {
+			// Credit failed - reverse the debit (compensating transaction)
+			result.addEvent("CREDIT_FAILED", "Credit processing failed, initiating reversal", map[string]interface{}{
+				"error": err.Error(),
+			})
+			s.record(ctx, transactionID, repository.SagaCompensating, err.Error())
+			return s.compensate(ctx, result, payerMapping, err)
+		}
+
+		result.PayeeResponse = payeeResponse
+		result.addEvent("CREDIT_SUCCESS", "Credit processed successfully", map[string]interface{}{
+			"bank_reference_id": payeeResponse.BankReferenceID,
+			"new_balance":       payeeResponse.AccountBalancePaisa,
+		})
+		s.record(ctx, transactionID, repository.SagaCreditDone, "")
+		fallthrough
+
+	case repository.SagaCreditDone:
+		if err := s.finish(ctx, transactionID, repository.StatusSuccess, repository.SagaCompleted, "Transaction completed successfully", "", ""); err != nil {
+			return err
+		}
+		result.addEvent("TRANSACTION_SUCCESS", "Transaction completed successfully", map[string]interface{}{
+			"final_status": "SUCCESS",
+		})
+		transaction.Status = repository.StatusSuccess
+		transaction.ProcessedAt = &[]time.Time{time.Now()}[0]
+		return nil
+
+	case repository.SagaCompensating:
+		return s.compensate(ctx, result, payerMapping, errors.New(state.LastError))
+	}
+
+	return fmt.Errorf("saga of transaction %s cannot go on from %s", transactionID, state.Step)
+}
+
+// compensate reverses the debit of a transaction whose credit failed with
+// creditErr
+func (s *saga) compensate(ctx context.Context, result *TransactionResult, payerMapping *repository.VPAMapping, creditErr error) error {
+	transaction := result.Transaction
+
+	if s.checkpointed(transaction.TransactionID) {
+		return errSagaCheckpointed
+	}
+	// The reversal has a transaction ID of its own, so one sent again
+	// cannot credit the payer twice
+	if err := reverseDebit(ctx, s.bankClients, transaction, payerMapping, transaction.DebitReference); err != nil {
+		result.addEvent("REVERSAL_FAILED", "Failed to reverse debit, retrying later", map[string]interface{}{
+			"reversal_error": err.Error(),
+		})
+		return fmt.Errorf("credit failed and reversal failed, retrying later: %w", err)
+	}
+
+	if err := s.finish(ctx, transaction.TransactionID, repository.StatusReversed, repository.SagaCompensated, "Credit failed, debit reversed", upierr.CreditFailed, creditErr.Error()); err != nil {
+		return err
+	}
+	result.addEvent("REVERSAL_SUCCESS", "Debit successfully reversed", nil)
+	transaction.Status = repository.StatusReversed
+	transaction.ErrorCode, transaction.ErrorMessage = string(upierr.CreditFailed), creditErr.Error()
+	return upierr.Wrap(upierr.CreditFailed, fmt.Errorf("credit processing failed, transaction reversed: %w", creditErr))
+}
+
+// checkpointed reports whether the saga is to stop before its next bank
+// call because the service is shutting down. The step it stops at is
+// recorded already, so the SagaRecovery resumes it once it is stale.
+func (s *saga) checkpointed(transactionID string) bool {
+	if s.inFlight == nil || !s.inFlight.checkpointing() {
+		return false
+	}
+	s.logger.WithField("transaction_id", transactionID).Warn("Checkpointing saga for shutdown")
+	return true
+}
+
+// record stores the step a saga has reached. A failure is logged; the saga
+// goes on, and at worst is resumed from an earlier step.
+func (s *saga) record(ctx context.Context, transactionID string, step repository.SagaStep, lastError string) {
+	if err := s.repo.UpdateSagaStep(context.WithoutCancel(ctx), nil, transactionID, step, lastError); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"transaction_id": transactionID,
+			"step":           step,
+		}).Error("Failed to record saga step")
+	}
+}
+
+// finish moves a PENDING transaction to its final status and its saga to
+// its final step. It fails with ErrTransactionExpired if the reaper has
+// already timed the transaction out.
+func (s *saga) finish(ctx context.Context, transactionID string, status repository.TransactionStatus, step repository.SagaStep, reason string, errorCode upierr.Code, errorMessage string) error {
+	// The outcome is recorded even if the bank calls ran into the deadline
+	ctx = context.WithoutCancel(ctx)
+	logger := s.logger.WithFields(logrus.Fields{
+		"transaction_id": transactionID,
+		"status":         status,
+	})
+
+	tx, err := s.repo.BeginTransaction(ctx)
+	if err != nil {
+		logger.WithError(err).Error("Failed to record transaction outcome")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.repo.RollbackTransaction(tx)
+
+	if _, err := s.repo.LockPendingTransaction(ctx, tx, transactionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Error("Transaction was timed out by the reaper before it completed")
+			return ErrTransactionExpired
+		}
+		logger.WithError(err).Error("Failed to record transaction outcome")
+		return fmt.Errorf("failed to lock transaction: %w", err)
+	}
+	if err := s.repo.UpdateTransactionStatus(ctx, tx, transactionID, status, reason, string(errorCode), errorMessage); err != nil {
+		logger.WithError(err).Error("Failed to record transaction outcome")
+		return fmt.Errorf("failed to update transaction status: %w", err)
+	}
+	if err := s.repo.UpdateSagaStep(ctx, tx, transactionID, step, errorMessage); err != nil {
+		logger.WithError(err).Error("Failed to record transaction outcome")
+		return fmt.Errorf("failed to update saga step: %w", err)
+	}
+	if err := s.repo.CommitTransaction(tx); err != nil {
+		logger.WithError(err).Error("Failed to record transaction outcome")
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	publishStatus(ctx, s.statuses, s.logger, &TransactionStatusUpdate{
+		TransactionID: transactionID,
+		Status:        status,
+		ErrorCode:     string(errorCode),
+		ErrorMessage:  errorMessage,
+		UpdatedAt:     time.Now(),
+	})
+	return nil
+}
+
+// debitDeclineCodes are the codes of the statuses a payer's bank declines a
+// debit with; other declines are DEBIT_FAILED
+var debitDeclineCodes = map[string]upierr.Code{
+	"INSUFFICIENT_FUNDS": upierr.InsufficientFunds,
+	"LIMIT_EXCEEDED":     upierr.TransactionLimitExceeded,
+	"ACCOUNT_FROZEN":     upierr.AccountFrozen,
+	"INVALID_ACCOUNT":    upierr.AccountDoesNotExist,
+}
+
+// sendDebit debits the payer at their bank
+func sendDebit(ctx context.Context, bankClients BankClients, transaction *repository.Transaction, payerMapping *repository.VPAMapping) (*BankTransactionResponse, error) {
+	bankClient, err := bankClients.Client(payerMapping.BankCode)
+	if err != nil {
+		return nil, err
+	}
+
+	debitRequest := &BankTransactionRequest{
+		TransactionID: transaction.TransactionID,
+		BankCode:      payerMapping.BankCode,
+		AccountNumber: payerMapping.AccountNumber,
+		AmountPaisa:   transaction.SettledAmountPaisa,
+		Type:          "DEBIT",
+		Reference:     transaction.Reference,
+		Description:   transaction.Description,
+		Signature:     transaction.Signature,
+		InitiatedAt:   transaction.InitiatedAt,
+	}
+
+	start := time.Now()
+	response, err := bankClient.ProcessTransaction(ctx, debitRequest)
+	pipeline().bankLeg(ctx, stageDebit, payerMapping.BankCode, start, response, err)
+	if err != nil {
+		return nil, fmt.Errorf("debit request failed: %w", err)
+	}
+
+	if response.Status != "SUCCESS" {
+		code, ok := debitDeclineCodes[response.Status]
+		if !ok {
+			code = upierr.DebitFailed
+		}
+		return nil, upierr.Wrap(code, fmt.Errorf("debit rejected by bank: %s - %s", response.ErrorCode, response.ErrorMessage))
+	}
+
+	return response, nil
+}
+
+// sendCredit credits the payee at their bank
+func sendCredit(ctx context.Context, bankClients BankClients, transaction *repository.Transaction, payeeMapping *repository.VPAMapping) (*BankTransactionResponse, error) {
+	bankClient, err := bankClients.Client(payeeMapping.BankCode)
+	if err != nil {
+		return nil, err
+	}
+
+	creditRequest := &BankTransactionRequest{
+		TransactionID: transaction.TransactionID,
+		BankCode:      payeeMapping.BankCode,
+		AccountNumber: payeeMapping.AccountNumber,
+		AmountPaisa:   transaction.SettledAmountPaisa,
+		Type:          "CREDIT",
+		Reference:     transaction.Reference,
+		Description:   transaction.Description,
+		Signature:     transaction.Signature,
+		InitiatedAt:   transaction.InitiatedAt,
+	}
+
+	start := time.Now()
+	response, err := bankClient.ProcessTransaction(ctx, creditRequest)
+	pipeline().bankLeg(ctx, stageCredit, payeeMapping.BankCode, start, response, err)
+	if err != nil {
+		return nil, fmt.Errorf("credit request failed: %w", err)
+	}
+
+	if response.Status != "SUCCESS" {
+		return nil, fmt.Errorf("credit rejected by bank: %s - %s", response.ErrorCode, response.ErrorMessage)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+// SagaRecovery resumes the sagas of transactions whose processor stopped
+// midway, e.g. because its instance crashed, from the last step recorded.
+// A transaction that has expired is left to the reaper. Any number of
+// instances can run it side by side.
+type SagaRecovery struct {
+	repo   repository.TransactionRepository
+	saga   *saga
+	events EventPublisher
+	cfg    config.SagaConfig
+	logger *logrus.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSagaRecovery creates a saga recovery worker; Start runs it in the
+// background
+func NewSagaRecovery(repo repository.TransactionRepository, bankClients BankClients, events EventPublisher, statuses StatusBus, cfg config.SagaConfig, logger *logrus.Logger) *SagaRecovery {
+	if cfg.ScanInterval <= 0 {
+		cfg.ScanInterval = 30 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.StaleAfter <= 0 {
+		cfg.StaleAfter = time.Minute
+	}
+	return &SagaRecovery{
+		repo:   repo,
+		saga:   &saga{repo: repo, bankClients: bankClients, statuses: statuses, logger: logger},
+		events: events,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Recover resumes up to one batch of stale sagas and returns how many
+// transactions it took to their end
+func (r *SagaRecovery) Recover(ctx context.Context) (int, error) {
+	ids, err := r.repo.ListStaleSagas(ctx, time.Now().Add(-r.cfg.StaleAfter), r.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list stale sagas: %w", err)
+	}
+
+	recovered := 0
+	for _, id := range ids {
+		ended, err := r.resume(ctx, id)
+		if err != nil {
+			r.logger.WithError(err).WithField("transaction_id", id).Error("Failed to resume saga, retrying on the next scan")
+			continue
+		}
+		if ended {
+			recovered++
+		}
+	}
+	return recovered, nil
+}
+
+// resume claims a stale saga and takes it on from its last step. It reports
+// whether the transaction ended; one still PENDING, e.g. because its
+// reversal failed again, is an error to retry.
+func (r *SagaRecovery) resume(ctx context.Context, transactionID string) (bool, error) {
+	state, err := r.repo.ClaimSaga(ctx, transactionID, time.Now().Add(-r.cfg.StaleAfter))
+	if errors.Is(err, sql.ErrNoRows) {
+		// Moved on, or claimed by another instance, since it was listed
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim saga: %w", err)
+	}
+
+	// A replica may not have the saga's latest writes yet
+	ctx = repository.ReadPrimary(ctx)
+	transaction, err := r.repo.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load transaction: %w", err)
+	}
+	if transaction.Status != repository.StatusPending || transaction.ExpiresAt == nil || !time.Now().Before(*transaction.ExpiresAt) {
+		return false, nil
+	}
+
+	payerMapping, err := r.repo.GetVPAMapping(ctx, transaction.PayerVPA)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve payer VPA %s: %w", transaction.PayerVPA, err)
+	}
+	payeeMapping, err := r.repo.GetVPAMapping(ctx, transaction.PayeeVPA)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve payee VPA %s: %w", transaction.PayeeVPA, err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"transaction_id": transactionID,
+		"step":           state.Step,
+	}).Warn("Resuming interrupted transaction")
+
+	// As for the processor, bank calls stop at the expiry
+	ctx, cancel := context.WithDeadline(ctx, *transaction.ExpiresAt)
+	defer cancel()
+
+	result := &TransactionResult{Transaction: transaction}
+	err = r.saga.run(ctx, result, payerMapping, payeeMapping, state)
+	publishTransactionEvents(ctx, r.events, result)
+	if transaction.Status == repository.StatusPending {
+		return false, err
+	}
+	return true, nil
+}
+
+// Start resumes stale sagas at once, then every scan interval until Close
+func (r *SagaRecovery) Start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run()
+}
+
+func (r *SagaRecovery) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.cfg.ScanInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-r.stop
+		cancel()
+	}()
+	for {
+		// A full batch means there may be more waiting
+		for {
+			recovered, err := r.Recover(ctx)
+			if err != nil {
+				r.logger.WithError(err).Error("Failed to recover sagas")
+			}
+			if err != nil || recovered < r.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops the background scans, waiting for one in progress to finish
+func (r *SagaRecovery) Close() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+// interruptedTransaction returns a repository holding a PENDING
+// transaction whose saga stopped at step a while ago
+func interruptedTransaction(step repository.SagaStep, debitReference string) *fakeRepository {
+	transaction := pendingTransaction("TXN1", time.Minute, debitReference)
+	transaction.PayeeVPA = "bob@sbi"
+	repo := newFakeRepository(transaction)
+	repo.sagas["TXN1"] = &repository.SagaState{
+		TransactionID: "TXN1",
+		Step:          step,
+		LastError:     "credit rejected by bank: ACCOUNT_CLOSED - ",
+		UpdatedAt:     time.Now().Add(-time.Hour),
+	}
+	return repo
+}
+
+func newTestSagaRecovery(repo *fakeRepository, banks *fakeBankClients, events *fakePublisher) *SagaRecovery {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewSagaRecovery(repo, banks, events, nil, config.SagaConfig{BatchSize: 10, StaleAfter: time.Minute}, logger)
+}
+
+func TestSagaRecoveryResumesFromLastStep(t *testing.T) {
+	for _, tc := range []struct {
+		step  repository.SagaStep
+		calls []string
+	}{
+		{repository.SagaStarted, []string{"DEBIT", "CREDIT"}},
+		{repository.SagaDebitPending, []string{"DEBIT", "CREDIT"}},
+		{repository.SagaDebitDone, []string{"CREDIT"}},
+		{repository.SagaCreditPending, []string{"CREDIT"}},
+		{repository.SagaCreditDone, nil},
+	} {
+		debitReference := "HDFC123"
+		if tc.step == repository.SagaStarted || tc.step == repository.SagaDebitPending {
+			debitReference = ""
+		}
+		repo := interruptedTransaction(tc.step, debitReference)
+		banks := &fakeBankClients{}
+		events := &fakePublisher{}
+
+		if recovered, err := newTestSagaRecovery(repo, banks, events).Recover(context.Background()); err != nil || recovered != 1 {
+			t.Fatalf("%s: recovered %d, %v; want 1", tc.step, recovered, err)
+		}
+		var calls []string
+		for _, req := range banks.requests {
+			if req.TransactionID != "TXN1" {
+				t.Errorf("%s: bank called for %s", tc.step, req.TransactionID)
+			}
+			calls = append(calls, req.Type)
+		}
+		if len(calls) != len(tc.calls) || (len(calls) > 0 && calls[len(calls)-1] != tc.calls[len(tc.calls)-1]) {
+			t.Errorf("%s: bank calls %v, want %v", tc.step, calls, tc.calls)
+		}
+		if got := repo.transactions["TXN1"].Status; got != repository.StatusSuccess {
+			t.Errorf("%s: transaction is %s, want SUCCESS", tc.step, got)
+		}
+		if got := repo.sagas["TXN1"].Step; got != repository.SagaCompleted {
+			t.Errorf("%s: saga at %s, want COMPLETED", tc.step, got)
+		}
+		if got := events.events["TXN1"]; len(got) == 0 || got[len(got)-1] != "TRANSACTION_SUCCESS" {
+			t.Errorf("%s: events = %v", tc.step, got)
+		}
+	}
+}
+
+func TestSagaRecoveryCompensatesRejectedCredit(t *testing.T) {
+	repo := interruptedTransaction(repository.SagaCreditPending, "HDFC123")
+	banks := &fakeBankClients{reject: func(req *BankTransactionRequest) bool {
+		return req.TransactionID == "TXN1" && req.Type == "CREDIT"
+	}}
+
+	if recovered, err := newTestSagaRecovery(repo, banks, &fakePublisher{}).Recover(context.Background()); err != nil || recovered != 1 {
+		t.Fatalf("recovered %d, %v; want 1", recovered, err)
+	}
+	if len(banks.requests) != 2 {
+		t.Fatalf("bank calls = %d, want the credit and its reversal", len(banks.requests))
+	}
+	if req := banks.requests[1]; req.TransactionID != "TXN1_REVERSE" || req.Reference != "REVERSAL_HDFC123" {
+		t.Errorf("reversal request = %+v", req)
+	}
+	if got := repo.transactions["TXN1"].Status; got != repository.StatusReversed {
+		t.Errorf("transaction is %s, want REVERSED", got)
+	}
+	if got := repo.sagas["TXN1"].Step; got != repository.SagaCompensated {
+		t.Errorf("saga at %s, want COMPENSATED", got)
+	}
+}
+
+func TestSagaRecoveryRetriesFailedReversal(t *testing.T) {
+	repo := interruptedTransaction(repository.SagaCompensating, "HDFC123")
+	banks := &fakeBankClients{err: errors.New("bank unreachable")}
+	recovery := newTestSagaRecovery(repo, banks, &fakePublisher{})
+
+	if recovered, err := recovery.Recover(context.Background()); err != nil || recovered != 0 {
+		t.Fatalf("recovered %d, %v; want 0", recovered, err)
+	}
+	if got := repo.transactions["TXN1"].Status; got != repository.StatusPending {
+		t.Fatalf("transaction after failed reversal is %s, want PENDING", got)
+	}
+
+	// Claimed sagas are left alone until they go stale again
+	banks.err = nil
+	if recovered, _ := recovery.Recover(context.Background()); recovered != 0 || len(banks.requests) != 1 {
+		t.Fatalf("fresh saga resumed: recovered %d, %d bank calls", recovered, len(banks.requests))
+	}
+	repo.sagas["TXN1"].UpdatedAt = time.Now().Add(-time.Hour)
+	if recovered, err := recovery.Recover(context.Background()); err != nil || recovered != 1 {
+		t.Fatalf("retry recovered %d, %v; want 1", recovered, err)
+	}
+	if got := repo.transactions["TXN1"]; got.Status != repository.StatusReversed {
+		t.Errorf("transaction after retry is %s, want REVERSED", got.Status)
+	}
+}
+
+func TestSagaRecoveryLeavesExpiredTransactionsToReaper(t *testing.T) {
+	repo := interruptedTransaction(repository.SagaDebitDone, "HDFC123")
+	expired := time.Now().Add(-time.Second)
+	repo.transactions["TXN1"].ExpiresAt = &expired
+	banks := &fakeBankClients{}
+
+	if recovered, err := newTestSagaRecovery(repo, banks, &fakePublisher{}).Recover(context.Background()); err != nil || recovered != 0 {
+		t.Fatalf("recovered %d, %v; want 0", recovered, err)
+	}
+	if len(banks.requests) != 0 || repo.transactions["TXN1"].Status != repository.StatusPending {
+		t.Errorf("expired transaction resumed")
+	}
+}
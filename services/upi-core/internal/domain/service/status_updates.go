@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/domain/repository"
+)
+
+// StatusBus carries changes of transactions' statuses to the instances
+// streaming them to clients; implemented by the Redis client
+type StatusBus interface {
+	PublishTransactionStatus(ctx context.Context, transactionID string, update []byte) error
+	SubscribeTransactionStatus(ctx context.Context, transactionID string) (<-chan []byte, error)
+}
+
+// TransactionStatusUpdate is the status of a transaction as of a change
+type TransactionStatusUpdate struct {
+	TransactionID string                       `json:"transaction_id"`
+	Status        repository.TransactionStatus `json:"status"`
+	ErrorCode     string                       `json:"error_code,omitempty"`
+	ErrorMessage  string                       `json:"error_message,omitempty"`
+	UpdatedAt     time.Time                    `json:"updated_at"`
+}
+
+// Final reports whether the transaction's status will not change again
+func (u *TransactionStatusUpdate) Final() bool {
+	return u.Status != repository.StatusPending
+}
+
+// publishStatus tells subscribers of a committed status change. Updates
+// are best effort: a subscriber that misses one still has
+// GetTransactionStatus.
+func publishStatus(ctx context.Context, statuses StatusBus, logger *logrus.Logger, update *TransactionStatusUpdate) {
+	if statuses == nil {
+		return
+	}
+	message, _ := json.Marshal(update)
+	if err := statuses.PublishTransactionStatus(context.WithoutCancel(ctx), update.TransactionID, message); err != nil {
+		logger.WithError(err).WithField("transaction_id", update.TransactionID).Warn("Failed to publish transaction status update")
+	}
+}
+
+// SubscribeTransactionStatus streams the status of a transaction: its
+// current status first, then each change of it. The channel is closed once
+// the status is final or ctx is done.
+func (s *TransactionService) SubscribeTransactionStatus(ctx context.Context, transactionID string) (<-chan *TransactionStatusUpdate, error) {
+	if s.statuses == nil {
+		return nil, errors.New("transaction status updates are not configured")
+	}
+
+	// Subscribe before reading the current status so that a change made in
+	// between is not missed
+	ctx, cancel := context.WithCancel(ctx)
+	messages, err := s.statuses.SubscribeTransactionStatus(ctx, transactionID)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to subscribe to status updates: %w", err)
+	}
+	transaction, err := s.GetTransaction(ctx, transactionID)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	updates := make(chan *TransactionStatusUpdate, 1)
+	current := &TransactionStatusUpdate{
+		TransactionID: transaction.TransactionID,
+		Status:        transaction.Status,
+		ErrorCode:     transaction.ErrorCode,
+		ErrorMessage:  transaction.ErrorMessage,
+		UpdatedAt:     transaction.UpdatedAt,
+	}
+	updates <- current
+	go func() {
+		defer close(updates)
+		defer cancel()
+		if current.Final() {
+			return
+		}
+		for message := range messages {
+			var update TransactionStatusUpdate
+			if err := json.Unmarshal(message, &update); err != nil {
+				s.logger.WithError(err).WithField("transaction_id", transactionID).Warn("Ignoring malformed transaction status update")
+				continue
+			}
+			select {
+			case updates <- &update:
+			case <-ctx.Done():
+				return
+			}
+			if update.Final() {
+				return
+			}
+		}
+	}()
+	return updates, nil
+}
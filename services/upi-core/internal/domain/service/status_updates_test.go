@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/domain/repository"
+)
+
+func newTestStatusService(repo *fakeRepository, bus *fakeStatusBus) (*TransactionService, *saga) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := &TransactionService{repo: repo, statuses: bus, logger: logger}
+	return s, &saga{repo: repo, statuses: bus, logger: logger}
+}
+
+func receiveStatuses(t *testing.T, updates <-chan *TransactionStatusUpdate) []repository.TransactionStatus {
+	t.Helper()
+	var statuses []repository.TransactionStatus
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return statuses
+			}
+			statuses = append(statuses, update.Status)
+		case <-time.After(time.Second):
+			t.Fatalf("stream not closed after %v", statuses)
+		}
+	}
+}
+
+func TestSubscribeTransactionStatusStreamsUntilFinal(t *testing.T) {
+	repo := newFakeRepository(pendingTransaction("TXN1", time.Minute, ""))
+	bus := &fakeStatusBus{}
+	s, saga := newTestStatusService(repo, bus)
+
+	updates, err := s.SubscribeTransactionStatus(context.Background(), "TXN1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := saga.finish(context.Background(), "TXN1", repository.StatusSuccess, repository.SagaCompleted, "Transaction completed", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	got := receiveStatuses(t, updates)
+	if len(got) != 2 || got[0] != repository.StatusPending || got[1] != repository.StatusSuccess {
+		t.Errorf("statuses = %v, want [PENDING SUCCESS]", got)
+	}
+}
+
+func TestSubscribeTransactionStatusOfFinishedTransaction(t *testing.T) {
+	transaction := pendingTransaction("TXN1", time.Minute, "")
+	transaction.Status = repository.StatusFailed
+	s, _ := newTestStatusService(newFakeRepository(transaction), &fakeStatusBus{})
+
+	updates, err := s.SubscribeTransactionStatus(context.Background(), "TXN1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := receiveStatuses(t, updates); len(got) != 1 || got[0] != repository.StatusFailed {
+		t.Errorf("statuses = %v, want [FAILED]", got)
+	}
+
+	if _, err := s.SubscribeTransactionStatus(context.Background(), "UNKNOWN"); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("unknown transaction: err = %v, want ErrTransactionNotFound", err)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"upi-core/internal/domain/repository"
+)
+
+var (
+	// ErrTransactionNotFound is returned for an unknown transaction ID or RRN
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrInvalidTransactionFilter wraps the reason a transaction listing was
+	// rejected
+	ErrInvalidTransactionFilter = errors.New("invalid transaction filter")
+)
+
+const (
+	defaultTransactionPageSize = 20
+	maxTransactionPageSize     = 100
+)
+
+// GetTransaction looks a transaction up by its ID
+func (s *TransactionService) GetTransaction(ctx context.Context, transactionID string) (*repository.Transaction, error) {
+	transaction, err := s.repo.GetTransactionByID(ctx, transactionID)
+	return foundTransaction(transaction, err)
+}
+
+// GetTransactionByRRN looks a transaction up by its retrieval reference
+// number
+func (s *TransactionService) GetTransactionByRRN(ctx context.Context, rrn string) (*repository.Transaction, error) {
+	transaction, err := s.repo.GetTransactionByRRN(ctx, rrn)
+	return foundTransaction(transaction, err)
+}
+
+func foundTransaction(transaction *repository.Transaction, err error) (*repository.Transaction, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+// ListTransactions returns a page of the transactions matching filter,
+// newest first, and the cursor of the next page, nil on the last one. The
+// page size defaults to 20 and is capped at 100.
+func (s *TransactionService) ListTransactions(ctx context.Context, filter repository.TransactionFilter) ([]*repository.Transaction, *repository.TransactionCursor, error) {
+	switch filter.Status {
+	case "", repository.StatusPending, repository.StatusSuccess, repository.StatusFailed,
+		repository.StatusTimeout, repository.StatusCancelled, repository.StatusReversed:
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown status %q", ErrInvalidTransactionFilter, filter.Status)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, nil, fmt.Errorf("%w: from must be before to", ErrInvalidTransactionFilter)
+	}
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = defaultTransactionPageSize
+	case filter.Limit > maxTransactionPageSize:
+		filter.Limit = maxTransactionPageSize
+	}
+
+	// One more than the page tells whether there is a next page
+	pageSize := filter.Limit
+	filter.Limit++
+	transactions, err := s.repo.ListTransactions(ctx, filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	if len(transactions) <= pageSize {
+		return transactions, nil, nil
+	}
+	transactions = transactions[:pageSize]
+	last := transactions[pageSize-1]
+	return transactions, &repository.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
+}
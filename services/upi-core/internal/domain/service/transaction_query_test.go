@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"upi-core/internal/domain/repository"
+)
+
+func TestListTransactionsPages(t *testing.T) {
+	repo := newFakeRepository()
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("TXN%d", i)
+		repo.transactions[id] = &repository.Transaction{
+			ID:            id,
+			TransactionID: id,
+			Status:        repository.StatusSuccess,
+			CreatedAt:     start.Add(time.Duration(i) * time.Second),
+		}
+	}
+	s := &TransactionService{repo: repo}
+	ctx := context.Background()
+
+	var pages [][]string
+	filter := repository.TransactionFilter{Limit: 2}
+	for {
+		transactions, next, err := s.ListTransactions(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var page []string
+		for _, transaction := range transactions {
+			page = append(page, transaction.TransactionID)
+		}
+		pages = append(pages, page)
+		if next == nil {
+			break
+		}
+		filter.After = next
+	}
+
+	if got := fmt.Sprint(pages); got != "[[TXN4 TXN3] [TXN2 TXN1] [TXN0]]" {
+		t.Errorf("pages = %s", got)
+	}
+}
+
+func TestListTransactionsRejectsInvalidFilters(t *testing.T) {
+	s := &TransactionService{repo: newFakeRepository()}
+	now := time.Now()
+	for name, filter := range map[string]repository.TransactionFilter{
+		"unknown status": {Status: "SETTLED"},
+		"empty range":    {From: now, To: now},
+	} {
+		if _, _, err := s.ListTransactions(context.Background(), filter); !errors.Is(err, ErrInvalidTransactionFilter) {
+			t.Errorf("%s: err = %v, want ErrInvalidTransactionFilter", name, err)
+		}
+	}
+}
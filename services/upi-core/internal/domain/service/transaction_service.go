@@ -2,18 +2,30 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
 	"upi-core/internal/domain/repository"
+	"upi-core/internal/fx"
 	"upi-core/internal/infrastructure/kafka"
 	"upi-core/internal/infrastructure/redis"
+	"upi-core/internal/upierr"
 	pb "upi-core/pkg/pb"
+	"upi-core/pkg/upi"
 )
 
 // TransactionService handles all transaction-related business logic with ACID guarantees
@@ -22,7 +34,37 @@ type TransactionService struct {
 	redis       *redis.Client
 	kafka       *kafka.Producer
 	logger      *logrus.Logger
-	bankClients map[string]BankClient // gRPC clients for each bank
+	bankClients BankClients // gRPC clients for each bank
+	vpas        *VPACache
+	fx          *fx.Converter
+	fees        *FeeService
+	security    config.SecurityConfig
+	statuses    StatusBus
+	inFlight    *inFlight
+	saga        *saga
+	limits      *TransactionLimits // nil if payers are not held to limits
+}
+
+// ErrTransactionExpired is returned when a transaction's outcome comes in
+// after the reaper has timed it out
+var ErrTransactionExpired = errors.New("transaction expired before it completed")
+
+// transactionTTL is how long a transaction has to complete before the
+// reaper times it out
+const transactionTTL = 5 * time.Minute
+
+const (
+	// idempotencyClaimTTL is how long a claimed key blocks duplicates if its
+	// request never finishes, e.g. because the process crashed. It matches
+	// the transaction expiry.
+	idempotencyClaimTTL = transactionTTL
+	// idempotencyResponseTTL is how long duplicates get the cached response
+	idempotencyResponseTTL = 24 * time.Hour
+)
+
+// BankClients hands out the client for a member bank
+type BankClients interface {
+	Client(bankCode string) (BankClient, error)
 }
 
 // BankClient interface for communicating with banks
@@ -30,8 +72,13 @@ type BankClient interface {
 	ProcessTransaction(ctx context.Context, req *BankTransactionRequest) (*BankTransactionResponse, error)
 	GetAccountBalance(ctx context.Context, bankCode, accountNumber string) (int64, error)
 	CheckAccountStatus(ctx context.Context, bankCode, accountNumber string) (string, error)
+	GetTransactionStatus(ctx context.Context, bankCode, transactionID string) (*BankTransactionStatus, error)
 }
 
+// ErrBankTransactionNotFound is returned by GetTransactionStatus when the
+// bank has no record of the transaction
+var ErrBankTransactionNotFound = errors.New("bank has no record of the transaction")
+
 // BankTransactionRequest represents a request to a bank
 type BankTransactionRequest struct {
 	TransactionID string
@@ -57,6 +104,15 @@ type BankTransactionResponse struct {
 	Fees                *pb.TransactionFees
 }
 
+// BankTransactionStatus is a bank's record of a debit or credit
+type BankTransactionStatus struct {
+	TransactionID   string
+	BankReferenceID string
+	Status          string // SUCCESS, FAILED, ...
+	AmountPaisa     int64
+	ProcessedAt     time.Time
+}
+
 // TransactionResult represents the result of transaction processing
 type TransactionResult struct {
 	Transaction   *repository.Transaction
@@ -78,19 +134,57 @@ func NewTransactionService(
 	repo repository.TransactionRepository,
 	redis *redis.Client,
 	kafka *kafka.Producer,
+	bankClients BankClients,
+	vpas *VPACache,
+	converter *fx.Converter,
+	fees *FeeService,
+	limits *TransactionLimits,
+	security config.SecurityConfig,
 	logger *logrus.Logger,
 ) *TransactionService {
+	tracker := newInFlight()
 	return &TransactionService{
 		repo:        repo,
 		redis:       redis,
 		kafka:       kafka,
 		logger:      logger,
-		bankClients: make(map[string]BankClient),
+		bankClients: bankClients,
+		vpas:        vpas,
+		fx:          converter,
+		fees:        fees,
+		limits:      limits,
+		security:    security,
+		statuses:    redis,
+		inFlight:    tracker,
+		saga:        &saga{repo: repo, bankClients: bankClients, statuses: redis, inFlight: tracker, logger: logger},
 	}
 }
 
 // ProcessTransaction handles the complete transaction processing with ACID guarantees
 func (s *TransactionService) ProcessTransaction(ctx context.Context, req *pb.TransactionRequest) (*pb.TransactionResponse, error) {
+	return s.processTransaction(ctx, req, false)
+}
+
+// ProcessPreauthorizedTransaction processes a transaction the switch makes
+// itself under an authorization already verified, such as the execution of
+// a mandate. Its request carries no signature to verify.
+func (s *TransactionService) ProcessPreauthorizedTransaction(ctx context.Context, req *pb.TransactionRequest) (*pb.TransactionResponse, error) {
+	return s.processTransaction(ctx, req, true)
+}
+
+func (s *TransactionService) processTransaction(ctx context.Context, req *pb.TransactionRequest, preauthorized bool) (*pb.TransactionResponse, error) {
+	if !s.inFlight.enter() {
+		return nil, ErrShuttingDown
+	}
+	defer s.inFlight.leave()
+
+	done := pipeline().begin(ctx, req.Type)
+	response, err := s.runTransaction(ctx, req, preauthorized)
+	done(response)
+	return response, err
+}
+
+func (s *TransactionService) runTransaction(ctx context.Context, req *pb.TransactionRequest, preauthorized bool) (*pb.TransactionResponse, error) {
 	// Generate correlation ID for tracing
 	correlationID := s.generateCorrelationID()
 
@@ -104,56 +198,121 @@ func (s *TransactionService) ProcessTransaction(ctx context.Context, req *pb.Tra
 
 	logger.Info("Starting transaction processing")
 
-	// Step 1: Check idempotency
-	idempotencyKey := s.generateIdempotencyKey(req)
-	exists, cachedResponse, err := s.repo.CheckIdempotencyKey(ctx, idempotencyKey)
+	// Step 1: Validate request
+	if err := ValidateTransactionRequest(req); err != nil {
+		logger.WithError(err).Error("Transaction validation failed")
+		return s.createErrorResponse(req.TransactionId, upierr.ValidationError, err.Error()), nil
+	}
+	conversion, err := s.convertAmount(ctx, req)
+	if errors.Is(err, fx.ErrRateUnavailable) {
+		logger.WithError(err).Error("Currency conversion failed")
+		return s.createErrorResponse(req.TransactionId, upierr.FXRateUnavailable, err.Error()), nil
+	}
 	if err != nil {
-		logger.WithError(err).Error("Failed to check idempotency key")
-		return s.createErrorResponse(req.TransactionId, "SYSTEM_ERROR", "Internal system error"), nil
+		logger.WithError(err).Error("Transaction validation failed")
+		return s.createErrorResponse(req.TransactionId, upierr.ValidationError, err.Error()), nil
 	}
 
-	if exists {
+	// Step 2: Claim the idempotency key. Of concurrent duplicates exactly one
+	// gets it; the others are answered from its cached response, or turned
+	// away while it is still in progress.
+	idempotencyKey := s.generateIdempotencyKey(req)
+	claimed, cachedResponse, err := s.repo.ClaimIdempotencyKey(ctx, idempotencyKey, "transaction", req.TransactionId, time.Now().Add(idempotencyClaimTTL))
+	if errors.Is(err, repository.ErrIdempotencyKeyInFlight) {
+		logger.Warn("Rejecting duplicate of a transaction still in progress")
+		return s.createErrorResponse(req.TransactionId, upierr.DuplicateInProgress, err.Error()), nil
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to claim idempotency key")
+		return s.createErrorResponse(req.TransactionId, upierr.SystemError, "Internal system error"), nil
+	}
+	if !claimed {
 		logger.Info("Returning cached response for idempotent request")
 		var response pb.TransactionResponse
-		json.Unmarshal([]byte(cachedResponse), &response)
+		if err := json.Unmarshal([]byte(cachedResponse), &response); err != nil {
+			logger.WithError(err).Error("Failed to decode cached response")
+			return s.createErrorResponse(req.TransactionId, upierr.SystemError, "Internal system error"), nil
+		}
 		return &response, nil
 	}
 
-	// Step 2: Validate request
-	if err := s.validateTransactionRequest(req); err != nil {
-		logger.WithError(err).Error("Transaction validation failed")
-		return s.createErrorResponse(req.TransactionId, "VALIDATION_ERROR", err.Error()), nil
-	}
-
 	// Step 3: Resolve VPAs to bank accounts
+	resolveStart := time.Now()
 	payerMapping, payeeMapping, err := s.resolveVPAs(ctx, req.PayerVpa, req.PayeeVpa)
+	pipeline().stage(ctx, stageVPAResolve, resolveStart, err)
 	if err != nil {
 		logger.WithError(err).Error("VPA resolution failed")
-		return s.createErrorResponse(req.TransactionId, "VPA_RESOLUTION_ERROR", err.Error()), nil
+		s.releaseIdempotencyKey(ctx, idempotencyKey)
+		return s.createErrorResponse(req.TransactionId, upierr.CodeOf(err, upierr.AddressResolutionFailed), err.Error()), nil
 	}
 
 	// Step 4: Check bank availability
 	if err := s.checkBankAvailability(ctx, payerMapping.BankCode, payeeMapping.BankCode); err != nil {
 		logger.WithError(err).Error("Bank availability check failed")
-		return s.createErrorResponse(req.TransactionId, "BANK_UNAVAILABLE", err.Error()), nil
+		s.releaseIdempotencyKey(ctx, idempotencyKey)
+		return s.createErrorResponse(req.TransactionId, upierr.BankUnavailable, err.Error()), nil
+	}
+
+	// Step 5: Verify the request was signed by the payer's bank
+	if preauthorized {
+		logger.Debug("Skipping signature verification of preauthorized transaction")
+	} else if err := s.verifySignature(ctx, req, payerMapping.BankCode); err != nil {
+		s.releaseIdempotencyKey(ctx, idempotencyKey)
+		if errors.Is(err, crypto.ErrInvalidSignature) {
+			logger.WithError(err).Warn("Rejecting request with an invalid signature")
+			return s.createErrorResponse(req.TransactionId, upierr.SignatureInvalid, err.Error()), nil
+		}
+		logger.WithError(err).Error("Signature verification failed")
+		return s.createErrorResponse(req.TransactionId, upierr.SystemError, "Internal system error"), nil
+	}
+
+	// Step 6: Count the transaction against the payer's limits before it
+	// is debited
+	var reservation *LimitReservation
+	if s.limits != nil {
+		reservation, err = s.limits.Reserve(ctx, req.PayerVpa, conversion.SettledAmount, time.Now())
+		var exceeded *LimitExceededError
+		if errors.As(err, &exceeded) {
+			logger.WithField("limit", exceeded.Limit).Warn("Rejecting transaction over the payer's limit")
+			s.releaseIdempotencyKey(ctx, idempotencyKey)
+			return s.createErrorResponse(req.TransactionId, exceeded.Code(), err.Error()), nil
+		}
 	}
 
-	// Step 5: Process transaction with ACID guarantees
-	result, err := s.processTransactionWithACID(ctx, req, payerMapping, payeeMapping, correlationID)
+	// Step 7: Process transaction with ACID guarantees. From here on a bank
+	// may have been called, so the outcome is cached whatever it is.
+	result, err := s.processTransactionWithACID(ctx, req, conversion, payerMapping, payeeMapping, correlationID)
+	if errors.Is(err, errSagaCheckpointed) {
+		// Like a crashed instance's, the idempotency key stays claimed while
+		// the SagaRecovery takes the transaction on
+		logger.Warn("Transaction interrupted by shutdown, its saga is resumed later")
+		response := s.createErrorResponse(req.TransactionId, upierr.TransactionPending, err.Error())
+		response.Status = pb.TransactionStatus_TRANSACTION_STATUS_PENDING
+		return response, nil
+	}
 	if err != nil {
 		logger.WithError(err).Error("Transaction processing failed")
-		return s.createErrorResponse(req.TransactionId, "PROCESSING_ERROR", err.Error()), nil
+		s.releaseLimitUnlessDebited(ctx, req.TransactionId, reservation)
+		errorCode := upierr.CodeOf(err, upierr.SystemError)
+		if errors.Is(err, ErrTransactionExpired) {
+			errorCode = upierr.TransactionTimeout
+		}
+		response := s.createErrorResponse(req.TransactionId, errorCode, err.Error())
+		s.completeIdempotencyKey(ctx, idempotencyKey, response)
+		if result != nil {
+			go publishTransactionEvents(ctx, s.kafka, result)
+		}
+		return response, nil
 	}
 
-	// Step 6: Create response
+	// Step 8: Create response
 	response := s.createSuccessResponse(result)
 
-	// Step 7: Cache response for idempotency
-	responseData, _ := json.Marshal(response)
-	s.repo.StoreIdempotencyKey(ctx, nil, idempotencyKey, "transaction", req.TransactionId, responseData, time.Now().Add(24*time.Hour))
+	// Step 9: Cache response for idempotency
+	s.completeIdempotencyKey(ctx, idempotencyKey, response)
 
-	// Step 8: Publish events asynchronously
-	go s.publishTransactionEvents(ctx, result)
+	// Step 10: Publish events asynchronously
+	go publishTransactionEvents(ctx, s.kafka, result)
 
 	logger.Info("Transaction processing completed successfully")
 	return response, nil
@@ -163,257 +322,145 @@ func (s *TransactionService) ProcessTransaction(ctx context.Context, req *pb.Tra
 func (s *TransactionService) processTransactionWithACID(
 	ctx context.Context,
 	req *pb.TransactionRequest,
+	conversion *fx.Conversion,
 	payerMapping *repository.VPAMapping,
 	payeeMapping *repository.VPAMapping,
 	correlationID string,
 ) (*TransactionResult, error) {
-	// Start database transaction for ACID guarantees
-	tx, err := s.repo.BeginTransaction(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	// Ensure transaction is rolled back if anything fails
-	defer func() {
-		if err != nil {
-			s.repo.RollbackTransaction(tx)
-		}
-	}()
+	transactionType := repository.TransactionType(strings.TrimPrefix(req.Type.String(), "TRANSACTION_TYPE_"))
+	fees := s.fees.Calculate(payerMapping.BankCode, transactionType, conversion.SettledAmount)
 
 	// Create transaction record
 	transaction := &repository.Transaction{
-		TransactionID:  req.TransactionId,
-		RRN:            s.generateRRN(),
-		PayerVPA:       req.PayerVpa,
-		PayeeVPA:       req.PayeeVpa,
-		AmountPaisa:    req.AmountPaisa,
-		Currency:       "INR",
-		Type:           repository.TransactionType(req.Type.String()),
-		Status:         repository.StatusPending,
-		Description:    req.Description,
-		Reference:      req.Reference,
-		PayerBankCode:  payerMapping.BankCode,
-		PayeeBankCode:  payeeMapping.BankCode,
-		SwitchFeePaisa: s.calculateSwitchFee(req.AmountPaisa),
-		BankFeePaisa:   s.calculateBankFee(req.AmountPaisa),
-		Signature:      req.Signature,
-		Metadata:       req.Metadata,
-		InitiatedAt:    req.InitiatedAt.AsTime(),
-		ExpiresAt:      &[]time.Time{time.Now().Add(5 * time.Minute)}[0], // 5-minute timeout
+		TransactionID:      req.TransactionId,
+		RRN:                s.generateRRN(),
+		PayerVPA:           req.PayerVpa,
+		PayeeVPA:           req.PayeeVpa,
+		AmountPaisa:        req.AmountPaisa,
+		Currency:           conversion.SourceCurrency,
+		SettledAmountPaisa: conversion.SettledAmount,
+		SettledCurrency:    conversion.SettledCurrency,
+		FXRate:             conversion.Rate,
+		Type:               transactionType,
+		Status:             repository.StatusPending,
+		Description:        req.Description,
+		Reference:          req.Reference,
+		PayerBankCode:      payerMapping.BankCode,
+		PayeeBankCode:      payeeMapping.BankCode,
+		SwitchFeePaisa:     fees.SwitchPaisa,
+		BankFeePaisa:       fees.BankPaisa,
+		FXFeePaisa:         conversion.FeeAmount,
+		Signature:          req.Signature,
+		Metadata:           req.Metadata,
+		InitiatedAt:        req.InitiatedAt.AsTime(),
+		ExpiresAt:          &[]time.Time{time.Now().Add(transactionTTL)}[0],
 	}
 
 	// Calculate total fees
-	transaction.TotalFeePaisa = transaction.SwitchFeePaisa + transaction.BankFeePaisa
+	transaction.TotalFeePaisa = transaction.SwitchFeePaisa + transaction.BankFeePaisa + transaction.FXFeePaisa
 
-	// Insert transaction record
-	if err = s.repo.CreateTransaction(ctx, tx, transaction); err != nil {
-		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+	// Commit the record as PENDING before any bank is called, so that a
+	// transaction interrupted midway is still there for the reaper to time
+	// out and compensate
+	if err := s.createTransaction(ctx, transaction, correlationID); err != nil {
+		return nil, err
 	}
 
-	// Log audit trail
-	s.repo.LogAudit(ctx, tx, "transaction", req.TransactionId, "CREATE", "SYSTEM", nil, map[string]interface{}{
-		"status":       string(transaction.Status),
-		"amount_paisa": transaction.AmountPaisa,
-		"payer_vpa":    transaction.PayerVPA,
-		"payee_vpa":    transaction.PayeeVPA,
-		"payer_bank":   transaction.PayerBankCode,
-		"payee_bank":   transaction.PayeeBankCode,
-	}, correlationID)
+	// Bank calls stop at the expiry; past it the reaper takes over
+	ctx, cancel := context.WithDeadline(ctx, *transaction.ExpiresAt)
+	defer cancel()
 
 	result := &TransactionResult{
 		Transaction: transaction,
 		Events:      []TransactionEvent{},
 	}
+	state := &repository.SagaState{TransactionID: transaction.TransactionID, Step: repository.SagaStarted}
+	return result, s.saga.run(ctx, result, payerMapping, payeeMapping, state)
+}
 
-	// Step 1: Process debit at payer's bank
-	s.addEvent(result, "DEBIT_INITIATED", "Initiating debit from payer account", map[string]interface{}{
-		"bank_code": payerMapping.BankCode,
-		"account":   payerMapping.AccountNumber,
-		"amount":    req.AmountPaisa,
-	})
-
-	payerResponse, err := s.processDebit(ctx, transaction, payerMapping)
-	if err != nil {
-		// Update transaction status to failed
-		s.repo.UpdateTransactionStatus(ctx, tx, req.TransactionId, repository.StatusFailed, "Debit failed", "DEBIT_FAILED", err.Error())
-		s.addEvent(result, "DEBIT_FAILED", "Debit processing failed", map[string]interface{}{
-			"error": err.Error(),
-		})
-		return result, fmt.Errorf("debit processing failed: %w", err)
-	}
-
-	result.PayerResponse = payerResponse
-	s.addEvent(result, "DEBIT_SUCCESS", "Debit processed successfully", map[string]interface{}{
-		"bank_reference_id": payerResponse.BankReferenceID,
-		"new_balance":       payerResponse.AccountBalancePaisa,
-	})
-
-	// Step 2: Process credit at payee's bank
-	s.addEvent(result, "CREDIT_INITIATED", "Initiating credit to payee account", map[string]interface{}{
-		"bank_code": payeeMapping.BankCode,
-		"account":   payeeMapping.AccountNumber,
-		"amount":    req.AmountPaisa,
-	})
-
-	payeeResponse, err := s.processCredit(ctx, transaction, payeeMapping)
+// createTransaction inserts a new PENDING transaction with its audit entry
+func (s *TransactionService) createTransaction(ctx context.Context, transaction *repository.Transaction, correlationID string) error {
+	tx, err := s.repo.BeginTransaction(ctx)
 	if err != nil {
-		// Credit failed - need to reverse the debit (compensating transaction)
-		s.addEvent(result, "CREDIT_FAILED", "Credit processing failed, initiating reversal", map[string]interface{}{
-			"error": err.Error(),
-		})
-
-		// Attempt to reverse the debit
-		if reverseErr := s.reverseDebit(ctx, transaction, payerMapping, payerResponse.BankReferenceID); reverseErr != nil {
-			// Critical error - both debit and reversal failed
-			s.repo.UpdateTransactionStatus(ctx, tx, req.TransactionId, repository.StatusFailed, "Credit failed and reversal failed", "CRITICAL_ERROR", fmt.Sprintf("Credit error: %s, Reversal error: %s", err.Error(), reverseErr.Error()))
-			s.addEvent(result, "REVERSAL_FAILED", "Failed to reverse debit", map[string]interface{}{
-				"reversal_error": reverseErr.Error(),
-			})
-			return result, fmt.Errorf("critical error: credit failed and reversal failed: %w", reverseErr)
-		}
-
-		// Reversal successful
-		s.repo.UpdateTransactionStatus(ctx, tx, req.TransactionId, repository.StatusReversed, "Credit failed, debit reversed", "CREDIT_FAILED", err.Error())
-		s.addEvent(result, "REVERSAL_SUCCESS", "Debit successfully reversed", nil)
-		return result, fmt.Errorf("credit processing failed, transaction reversed: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
-	result.PayeeResponse = payeeResponse
-	s.addEvent(result, "CREDIT_SUCCESS", "Credit processed successfully", map[string]interface{}{
-		"bank_reference_id": payeeResponse.BankReferenceID,
-		"new_balance":       payeeResponse.AccountBalancePaisa,
-	})
-
-	// Step 3: Update transaction to success
-	if err = s.repo.UpdateTransactionStatus(ctx, tx, req.TransactionId, repository.StatusSuccess, "Transaction completed successfully", "", ""); err != nil {
-		return result, fmt.Errorf("failed to update transaction status: %w", err)
+	if err := s.repo.CreateTransaction(ctx, tx, transaction); err != nil {
+		s.repo.RollbackTransaction(tx)
+		return fmt.Errorf("failed to create transaction record: %w", err)
 	}
-
-	// Step 4: Commit the database transaction
-	if err = s.repo.CommitTransaction(tx); err != nil {
-		return result, fmt.Errorf("failed to commit transaction: %w", err)
+	if err := s.repo.CreateSagaState(ctx, tx, transaction.TransactionID); err != nil {
+		s.repo.RollbackTransaction(tx)
+		return fmt.Errorf("failed to create saga state: %w", err)
 	}
 
-	s.addEvent(result, "TRANSACTION_SUCCESS", "Transaction completed successfully", map[string]interface{}{
-		"final_status": "SUCCESS",
-	})
-
-	// Update transaction status in result
-	transaction.Status = repository.StatusSuccess
-	transaction.ProcessedAt = &[]time.Time{time.Now()}[0]
+	// Log audit trail
+	s.repo.LogAudit(ctx, tx, "transaction", transaction.TransactionID, "CREATE", "SYSTEM", nil, map[string]interface{}{
+		"status":               string(transaction.Status),
+		"amount_paisa":         transaction.AmountPaisa,
+		"currency":             transaction.Currency,
+		"settled_amount_paisa": transaction.SettledAmountPaisa,
+		"fx_rate":              transaction.FXRate,
+		"payer_vpa":            transaction.PayerVPA,
+		"payee_vpa":            transaction.PayeeVPA,
+		"payer_bank":           transaction.PayerBankCode,
+		"payee_bank":           transaction.PayeeBankCode,
+	}, correlationID)
 
-	return result, nil
+	if err := s.repo.CommitTransaction(tx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
 // resolveVPAs resolves both payer and payee VPAs to bank account information
 func (s *TransactionService) resolveVPAs(ctx context.Context, payerVPA, payeeVPA string) (*repository.VPAMapping, *repository.VPAMapping, error) {
-	// Try Redis cache first
-	payerMapping, err := s.getVPAFromCache(ctx, payerVPA)
-	if err != nil {
-		// Cache miss - get from database
-		payerMapping, err = s.repo.GetVPAMapping(ctx, payerVPA)
-		if err != nil {
-			return nil, nil, fmt.Errorf("payer VPA not found: %s", payerVPA)
-		}
-		// Cache for future use
-		s.cacheVPAMapping(ctx, payerMapping)
+	payerMapping, err := s.vpas.Resolve(ctx, payerVPA)
+	if errors.Is(err, ErrVPANotFound) {
+		return nil, nil, upierr.Newf(upierr.InvalidVPA, "payer VPA not found: %s", payerVPA)
 	}
-
-	payeeMapping, err := s.getVPAFromCache(ctx, payeeVPA)
 	if err != nil {
-		// Cache miss - get from database
-		payeeMapping, err = s.repo.GetVPAMapping(ctx, payeeVPA)
-		if err != nil {
-			return nil, nil, fmt.Errorf("payee VPA not found: %s", payeeVPA)
-		}
-		// Cache for future use
-		s.cacheVPAMapping(ctx, payeeMapping)
-	}
-
-	return payerMapping, payeeMapping, nil
-}
-
-// processDebit processes debit transaction at payer's bank
-func (s *TransactionService) processDebit(ctx context.Context, transaction *repository.Transaction, payerMapping *repository.VPAMapping) (*BankTransactionResponse, error) {
-	bankClient, exists := s.bankClients[payerMapping.BankCode]
-	if !exists {
-		return nil, fmt.Errorf("bank client not found for bank: %s", payerMapping.BankCode)
+		return nil, nil, fmt.Errorf("payer VPA %s: %w", payerVPA, err)
 	}
 
-	debitRequest := &BankTransactionRequest{
-		TransactionID: transaction.TransactionID,
-		BankCode:      payerMapping.BankCode,
-		AccountNumber: payerMapping.AccountNumber,
-		AmountPaisa:   transaction.AmountPaisa,
-		Type:          "DEBIT",
-		Reference:     transaction.Reference,
-		Description:   transaction.Description,
-		Signature:     transaction.Signature,
-		InitiatedAt:   transaction.InitiatedAt,
+	payeeMapping, err := s.vpas.Resolve(ctx, payeeVPA)
+	if errors.Is(err, ErrVPANotFound) {
+		return nil, nil, upierr.Newf(upierr.InvalidVPA, "payee VPA not found: %s", payeeVPA)
 	}
-
-	response, err := bankClient.ProcessTransaction(ctx, debitRequest)
 	if err != nil {
-		return nil, fmt.Errorf("debit request failed: %w", err)
-	}
-
-	if response.Status != "SUCCESS" {
-		return nil, fmt.Errorf("debit rejected by bank: %s - %s", response.ErrorCode, response.ErrorMessage)
+		return nil, nil, fmt.Errorf("payee VPA %s: %w", payeeVPA, err)
 	}
 
-	return response, nil
+	return payerMapping, payeeMapping, nil
 }
 
-// processCredit processes credit transaction at payee's bank
-func (s *TransactionService) processCredit(ctx context.Context, transaction *repository.Transaction, payeeMapping *repository.VPAMapping) (*BankTransactionResponse, error) {
-	bankClient, exists := s.bankClients[payeeMapping.BankCode]
-	if !exists {
-		return nil, fmt.Errorf("bank client not found for bank: %s", payeeMapping.BankCode)
-	}
-
-	creditRequest := &BankTransactionRequest{
-		TransactionID: transaction.TransactionID,
-		BankCode:      payeeMapping.BankCode,
-		AccountNumber: payeeMapping.AccountNumber,
-		AmountPaisa:   transaction.AmountPaisa,
-		Type:          "CREDIT",
-		Reference:     transaction.Reference,
-		Description:   transaction.Description,
-		Signature:     transaction.Signature,
-		InitiatedAt:   transaction.InitiatedAt,
-	}
-
-	response, err := bankClient.ProcessTransaction(ctx, creditRequest)
-	if err != nil {
-		return nil, fmt.Errorf("credit request failed: %w", err)
-	}
-
-	if response.Status != "SUCCESS" {
-		return nil, fmt.Errorf("credit rejected by bank: %s - %s", response.ErrorCode, response.ErrorMessage)
-	}
-
-	return response, nil
+// reversalTransactionID is the ID a debit is reversed under at the payer's
+// bank
+func reversalTransactionID(transactionID string) string {
+	return transactionID + "_REVERSE"
 }
 
 // reverseDebit reverses a debit transaction (compensating transaction)
-func (s *TransactionService) reverseDebit(ctx context.Context, transaction *repository.Transaction, payerMapping *repository.VPAMapping, bankReferenceID string) error {
-	bankClient, exists := s.bankClients[payerMapping.BankCode]
-	if !exists {
-		return fmt.Errorf("bank client not found for bank: %s", payerMapping.BankCode)
+func reverseDebit(ctx context.Context, bankClients BankClients, transaction *repository.Transaction, payerMapping *repository.VPAMapping, bankReferenceID string) error {
+	bankClient, err := bankClients.Client(payerMapping.BankCode)
+	if err != nil {
+		return err
 	}
 
 	reverseRequest := &BankTransactionRequest{
-		TransactionID: transaction.TransactionID + "_REVERSE",
+		TransactionID: reversalTransactionID(transaction.TransactionID),
 		BankCode:      payerMapping.BankCode,
 		AccountNumber: payerMapping.AccountNumber,
-		AmountPaisa:   transaction.AmountPaisa,
+		AmountPaisa:   transaction.SettledAmountPaisa,
 		Type:          "CREDIT", // Reverse debit = credit
 		Reference:     "REVERSAL_" + bankReferenceID,
 		Description:   "Reversal: " + transaction.Description,
 		InitiatedAt:   time.Now(),
 	}
 
+	start := time.Now()
 	response, err := bankClient.ProcessTransaction(ctx, reverseRequest)
+	pipeline().bankLeg(ctx, stageReversal, payerMapping.BankCode, start, response, err)
 	if err != nil {
 		return fmt.Errorf("reversal request failed: %w", err)
 	}
@@ -425,8 +472,9 @@ func (s *TransactionService) reverseDebit(ctx context.Context, transaction *repo
 	return nil
 }
 
-// Helper methods
-func (s *TransactionService) validateTransactionRequest(req *pb.TransactionRequest) error {
+// ValidateTransactionRequest checks a transaction request is well formed
+// before any VPA is resolved or bank is called.
+func ValidateTransactionRequest(req *pb.TransactionRequest) error {
 	if req.TransactionId == "" {
 		return fmt.Errorf("transaction ID is required")
 	}
@@ -436,45 +484,144 @@ func (s *TransactionService) validateTransactionRequest(req *pb.TransactionReque
 	if req.PayeeVpa == "" {
 		return fmt.Errorf("payee VPA is required")
 	}
-	if req.AmountPaisa <= 0 {
-		return fmt.Errorf("amount must be positive")
+	if _, err := upi.ParseVPA(req.PayerVpa); err != nil {
+		return fmt.Errorf("payer %w", err)
+	}
+	if _, err := upi.ParseVPA(req.PayeeVpa); err != nil {
+		return fmt.Errorf("payee %w", err)
+	}
+	if req.Currency != "" && !currencyCode.MatchString(req.Currency) {
+		return fmt.Errorf("currency must be a three letter ISO 4217 code")
+	}
+	// Held to the UPI limit as is, and once more once converted to rupees
+	if err := upi.ValidateAmountPaisa(req.AmountPaisa); err != nil {
+		return err
 	}
 	if req.PayerVpa == req.PayeeVpa {
 		return fmt.Errorf("payer and payee VPA cannot be the same")
 	}
+	if _, ok := pb.TransactionType_name[int32(req.Type)]; !ok || req.Type == pb.TransactionType_TRANSACTION_TYPE_UNSPECIFIED {
+		return fmt.Errorf("transaction type is required")
+	}
 	return nil
 }
 
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// convertAmount converts a request's amount to the settlement currency. A
+// request without a currency is in the settlement currency.
+func (s *TransactionService) convertAmount(ctx context.Context, req *pb.TransactionRequest) (*fx.Conversion, error) {
+	currency := req.Currency
+	if currency == "" {
+		currency = s.fx.SettlementCurrency()
+	}
+	conversion, err := s.fx.Convert(ctx, req.AmountPaisa, currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := upi.ValidateAmountPaisa(conversion.SettledAmount); err != nil {
+		return nil, fmt.Errorf("converted %w", err)
+	}
+	return conversion, nil
+}
+
+// TransactionSigningPayload is what the payer's bank signs for a
+// transaction request: its fields joined by "|", the type as its enum name
+// and the initiation time in RFC 3339 UTC.
+func TransactionSigningPayload(req *pb.TransactionRequest) []byte {
+	return []byte(strings.Join([]string{
+		req.TransactionId,
+		req.PayerVpa,
+		req.PayeeVpa,
+		strconv.FormatInt(req.AmountPaisa, 10),
+		req.Currency,
+		req.Type.String(),
+		req.Description,
+		req.Reference,
+		req.InitiatedAt.AsTime().UTC().Format(time.RFC3339Nano),
+	}, "|"))
+}
+
+// verifySignature checks the request's signature against the public key of
+// the bank it claims to come from. Unsigned requests pass unless signatures
+// are required.
+func (s *TransactionService) verifySignature(ctx context.Context, req *pb.TransactionRequest, bankCode string) error {
+	if req.Signature == "" {
+		if s.security.RequireSignatures {
+			return fmt.Errorf("%w: request is not signed", crypto.ErrInvalidSignature)
+		}
+		return nil
+	}
+
+	bank, err := s.repo.GetBankByCode(ctx, bankCode)
+	if err != nil {
+		return fmt.Errorf("failed to load public key of bank %s: %w", bankCode, err)
+	}
+	return crypto.Verify(bank.PublicKey, TransactionSigningPayload(req), req.Signature)
+}
+
+// Helper methods
 func (s *TransactionService) generateCorrelationID() string {
 	return fmt.Sprintf("CORR_%d", time.Now().UnixNano())
 }
 
+// generateIdempotencyKey identifies a transaction by what it does, so a
+// retry is recognised even if the client restamped initiated_at
 func (s *TransactionService) generateIdempotencyKey(req *pb.TransactionRequest) string {
-	data := fmt.Sprintf("%s_%s_%s_%d_%s", req.TransactionId, req.PayerVpa, req.PayeeVpa, req.AmountPaisa, req.InitiatedAt.String())
+	data := fmt.Sprintf("%s_%s_%s_%d", req.TransactionId, req.PayerVpa, req.PayeeVpa, req.AmountPaisa)
 	hash := sha256.Sum256([]byte(data))
 	return fmt.Sprintf("%x", hash)
 }
 
-func (s *TransactionService) generateRRN() string {
-	return fmt.Sprintf("RRN%d", time.Now().UnixNano())
+// completeIdempotencyKey caches a response for duplicates of its request.
+// It runs even if the caller has gone away, so the key is not left pending.
+func (s *TransactionService) completeIdempotencyKey(ctx context.Context, key string, response *pb.TransactionResponse) {
+	responseData, err := json.Marshal(response)
+	if err == nil {
+		err = s.repo.CompleteIdempotencyKey(context.WithoutCancel(ctx), nil, key, responseData, time.Now().Add(idempotencyResponseTTL))
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("transaction_id", response.TransactionId).Error("Failed to cache idempotent response")
+	}
 }
 
-func (s *TransactionService) calculateSwitchFee(amountPaisa int64) int64 {
-	// Simple fee calculation - 0.1% with minimum of 1 paisa
-	fee := amountPaisa / 1000
-	if fee < 1 {
-		fee = 1
+// releaseIdempotencyKey lets a request that failed before calling any bank
+// be retried with the same key
+func (s *TransactionService) releaseIdempotencyKey(ctx context.Context, key string) {
+	if err := s.repo.ReleaseIdempotencyKey(context.WithoutCancel(ctx), key); err != nil {
+		s.logger.WithError(err).Error("Failed to release idempotency key")
 	}
-	return fee
 }
 
-func (s *TransactionService) calculateBankFee(amountPaisa int64) int64 {
-	// Simple bank fee calculation - 0.05% with minimum of 1 paisa
-	fee := amountPaisa / 2000
-	if fee < 1 {
-		fee = 1
+// releaseLimitUnlessDebited releases the limit reservation of a transaction
+// that failed, unless its payer may still be out of pocket: one that is
+// neither FAILED nor REVERSED, e.g. whose debit is yet to be reversed,
+// stays counted. One that was never stored was never debited.
+func (s *TransactionService) releaseLimitUnlessDebited(ctx context.Context, transactionID string, reservation *LimitReservation) {
+	if reservation == nil {
+		return
+	}
+	transaction, err := s.repo.GetTransactionByID(repository.ReadPrimary(ctx), transactionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.logger.WithError(err).WithField("transaction_id", transactionID).Warn("Failed to look up failed transaction, keeping its limit reservation")
+		return
 	}
-	return fee
+	if err == nil && transaction.Status != repository.StatusFailed && transaction.Status != repository.StatusReversed {
+		return
+	}
+	s.limits.Release(ctx, reservation)
+}
+
+// generateRRN returns a 12 digit retrieval reference number in the NPCI
+// layout: the last digit of the year, the day of the year and the hour,
+// followed by a random 6 digit sequence number
+func (s *TransactionService) generateRRN() string {
+	now := time.Now().UTC()
+	sequence, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		sequence = big.NewInt(now.UnixNano() % 1000000)
+	}
+	return fmt.Sprintf("%d%03d%02d%06d", now.Year()%10, now.YearDay(), now.Hour(), sequence.Int64())
 }
 
 func (s *TransactionService) checkBankAvailability(ctx context.Context, payerBankCode, payeeBankCode string) error {
@@ -495,24 +642,18 @@ func (s *TransactionService) checkBankAvailability(ctx context.Context, payerBan
 		return fmt.Errorf("payee bank is not active: %s", payeeBankCode)
 	}
 
-	return nil
-}
-
-func (s *TransactionService) getVPAFromCache(ctx context.Context, vpa string) (*repository.VPAMapping, error) {
-	_, _, err := s.redis.GetVPAMapping(ctx, vpa)
-	if err != nil {
-		return nil, err
+	// Fail fast, before the transaction is recorded, if either bank is
+	// failing its health checks or has its circuit breaker open
+	for _, bankCode := range []string{payerBankCode, payeeBankCode} {
+		if _, err := s.bankClients.Client(bankCode); err != nil {
+			return err
+		}
 	}
 
-	// Get additional details from database (this could be cached too)
-	return s.repo.GetVPAMapping(ctx, vpa)
-}
-
-func (s *TransactionService) cacheVPAMapping(ctx context.Context, mapping *repository.VPAMapping) {
-	s.redis.SetVPAMapping(ctx, mapping.VPA, mapping.BankCode, mapping.AccountNumber, 24*time.Hour)
+	return nil
 }
 
-func (s *TransactionService) addEvent(result *TransactionResult, eventType, description string, details map[string]interface{}) {
+func (result *TransactionResult) addEvent(eventType, description string, details map[string]interface{}) {
 	result.Events = append(result.Events, TransactionEvent{
 		Type:        eventType,
 		Description: description,
@@ -521,11 +662,11 @@ func (s *TransactionService) addEvent(result *TransactionResult, eventType, desc
 	})
 }
 
-func (s *TransactionService) createErrorResponse(transactionID, errorCode, errorMessage string) *pb.TransactionResponse {
+func (s *TransactionService) createErrorResponse(transactionID string, errorCode upierr.Code, errorMessage string) *pb.TransactionResponse {
 	return &pb.TransactionResponse{
 		TransactionId: transactionID,
 		Status:        pb.TransactionStatus_TRANSACTION_STATUS_FAILED,
-		ErrorCode:     errorCode,
+		ErrorCode:     string(errorCode),
 		ErrorMessage:  errorMessage,
 		ProcessedAt:   timestamppb.Now(),
 	}
@@ -542,23 +683,33 @@ func (s *TransactionService) createSuccessResponse(result *TransactionResult) *p
 		Fees: &pb.TransactionFees{
 			SwitchFeePaisa: result.Transaction.SwitchFeePaisa,
 			BankFeePaisa:   result.Transaction.BankFeePaisa,
+			FxFeePaisa:     result.Transaction.FXFeePaisa,
 			TotalFeePaisa:  result.Transaction.TotalFeePaisa,
 		},
-		SettlementId: result.Transaction.SettlementID,
+		SettlementId:       result.Transaction.SettlementID,
+		SettledAmountPaisa: result.Transaction.SettledAmountPaisa,
+		SettledCurrency:    result.Transaction.SettledCurrency,
+		FxRate:             result.Transaction.FXRate,
 	}
 }
 
-func (s *TransactionService) publishTransactionEvents(ctx context.Context, result *TransactionResult) {
+func publishTransactionEvents(ctx context.Context, events EventPublisher, result *TransactionResult) {
+	transaction := result.Transaction
 	for _, event := range result.Events {
 		eventData := map[string]interface{}{
-			"transaction_id": result.Transaction.TransactionID,
+			"transaction_id": transaction.TransactionID,
 			"event_type":     event.Type,
 			"description":    event.Description,
 			"timestamp":      event.Timestamp,
 			"details":        event.Details,
+			"status":         string(transaction.Status),
+		}
+		if transaction.ErrorCode != "" {
+			eventData["error_code"] = transaction.ErrorCode
+			eventData["error_name"] = upierr.Code(transaction.ErrorCode).Name()
 		}
 
 		eventBytes, _ := json.Marshal(eventData)
-		s.kafka.PublishTransactionEvent(ctx, result.Transaction.TransactionID, eventBytes)
+		events.PublishTransactionEvent(ctx, result.Transaction.TransactionID, eventBytes)
 	}
 }
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/repository"
+	pb "upi-core/pkg/pb"
+)
+
+func TestVerifySignature(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	repo := newFakeRepository()
+	repo.banks = map[string]*repository.Bank{
+		"HDFC": {BankCode: "HDFC", PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))},
+		"SBI":  {BankCode: "SBI", PublicKey: "mock_public_key_sbi"},
+	}
+
+	signed := func() *pb.TransactionRequest {
+		req := &pb.TransactionRequest{
+			TransactionId: "TXN1",
+			PayerVpa:      "alice@hdfc",
+			PayeeVpa:      "bob@sbi",
+			AmountPaisa:   10000,
+			Currency:      "INR",
+			Type:          pb.TransactionType_TRANSACTION_TYPE_P2P,
+			InitiatedAt:   timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC)),
+		}
+		req.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, TransactionSigningPayload(req)))
+		return req
+	}
+
+	s := &TransactionService{repo: repo}
+	if err := s.verifySignature(context.Background(), signed(), "HDFC"); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+
+	tampered := signed()
+	tampered.AmountPaisa = 99999
+	if err := s.verifySignature(context.Background(), tampered, "HDFC"); !errors.Is(err, crypto.ErrInvalidSignature) {
+		t.Errorf("tampered amount: err = %v, want ErrInvalidSignature", err)
+	}
+	if err := s.verifySignature(context.Background(), signed(), "SBI"); !errors.Is(err, crypto.ErrInvalidSignature) {
+		t.Errorf("signed by another bank: err = %v, want ErrInvalidSignature", err)
+	}
+
+	unsigned := signed()
+	unsigned.Signature = ""
+	if err := s.verifySignature(context.Background(), unsigned, "HDFC"); err != nil {
+		t.Errorf("unsigned request rejected while signatures are optional: %v", err)
+	}
+	s.security = config.SecurityConfig{RequireSignatures: true}
+	if err := s.verifySignature(context.Background(), unsigned, "HDFC"); !errors.Is(err, crypto.ErrInvalidSignature) {
+		t.Errorf("unsigned request: err = %v, want ErrInvalidSignature", err)
+	}
+}
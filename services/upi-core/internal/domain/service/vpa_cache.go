@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+// VPACacheStore holds cache entries of resolved VPAs; implemented by the
+// Redis client and by NewMemoryVPACacheStore
+type VPACacheStore interface {
+	// GetVPAMapping returns the entry of a VPA, or nil if there is none
+	GetVPAMapping(ctx context.Context, vpa string) ([]byte, error)
+	SetVPAMapping(ctx context.Context, vpa string, entry []byte, ttl time.Duration) error
+	DeleteVPAMapping(ctx context.Context, vpa string) error
+}
+
+// vpaCacheEntry is a cached resolution of a VPA: its mapping, or nil for a
+// VPA that is unknown or inactive
+type vpaCacheEntry struct {
+	Mapping *repository.VPAMapping `json:"mapping"`
+}
+
+// VPACache resolves VPAs through a cache in front of the database. It
+// caches mappings for the TTL and unknown VPAs for the negative TTL, each
+// shortened by up to the jitter at random. Writes to a VPA invalidate its
+// entry.
+type VPACache struct {
+	repo   repository.TransactionRepository
+	store  VPACacheStore // nil when caching is off
+	cfg    config.VPACacheConfig
+	logger *logrus.Logger
+}
+
+// NewVPACache creates a VPA cache over store, which may be nil to resolve
+// every VPA from the database
+func NewVPACache(repo repository.TransactionRepository, store VPACacheStore, cfg config.VPACacheConfig, logger *logrus.Logger) *VPACache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Hour
+	}
+	if cfg.NegativeTTL <= 0 {
+		cfg.NegativeTTL = 30 * time.Second
+	}
+	if cfg.Jitter < 0 || cfg.Jitter >= 1 {
+		cfg.Jitter = 0
+	}
+	return &VPACache{repo: repo, store: store, cfg: cfg, logger: logger}
+}
+
+// Resolve returns the account an active VPA maps to, or ErrVPANotFound.
+// A cache that fails is bypassed.
+func (c *VPACache) Resolve(ctx context.Context, vpa string) (*repository.VPAMapping, error) {
+	if entry, ok := c.get(ctx, vpa); ok {
+		if entry.Mapping == nil {
+			return nil, ErrVPANotFound
+		}
+		return entry.Mapping, nil
+	}
+
+	// Not from a replica, which may still hold a mapping written just before
+	// its entry was invalidated, to be cached for the whole TTL
+	mapping, err := c.repo.GetVPAMapping(repository.ReadPrimary(ctx), vpa)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.set(ctx, vpa, &vpaCacheEntry{}, c.cfg.NegativeTTL)
+		return nil, ErrVPANotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to resolve VPA: %w", err)
+	}
+	c.set(ctx, vpa, &vpaCacheEntry{Mapping: mapping}, c.cfg.TTL)
+	return mapping, nil
+}
+
+// Invalidate drops the entry of a VPA that was written. A failure is only
+// logged; the entry then lasts until it expires.
+func (c *VPACache) Invalidate(ctx context.Context, vpa string) {
+	if c.store == nil {
+		return
+	}
+	if err := c.store.DeleteVPAMapping(context.WithoutCancel(ctx), vpa); err != nil {
+		c.logger.WithError(err).WithField("vpa", vpa).Warn("Failed to invalidate cached VPA mapping")
+	}
+}
+
+func (c *VPACache) get(ctx context.Context, vpa string) (*vpaCacheEntry, bool) {
+	if c.store == nil {
+		return nil, false
+	}
+	data, err := c.store.GetVPAMapping(ctx, vpa)
+	if err != nil {
+		c.logger.WithError(err).WithField("vpa", vpa).Warn("Failed to read cached VPA mapping")
+		return nil, false
+	}
+	if data == nil {
+		return nil, false
+	}
+	var entry vpaCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// E.g. an entry in an older format; it is overwritten
+		return nil, false
+	}
+	return &entry, true
+}
+
+func (c *VPACache) set(ctx context.Context, vpa string, entry *vpaCacheEntry, ttl time.Duration) {
+	if c.store == nil {
+		return
+	}
+	data, _ := json.Marshal(entry)
+	if err := c.store.SetVPAMapping(ctx, vpa, data, c.jitter(ttl)); err != nil {
+		c.logger.WithError(err).WithField("vpa", vpa).Warn("Failed to cache VPA mapping")
+	}
+}
+
+// jitter shortens ttl by up to the configured fraction of it, so that
+// entries cached at once, e.g. after a restart, expire spread out rather
+// than sending their lookups to the database together
+func (c *VPACache) jitter(ttl time.Duration) time.Duration {
+	if c.cfg.Jitter == 0 {
+		return ttl
+	}
+	return ttl - time.Duration(rand.Float64()*c.cfg.Jitter*float64(ttl))
+}
+
+// maxMemoryVPAEntries bounds the memory store, e.g. against lookups of
+// many unknown VPAs
+const maxMemoryVPAEntries = 100000
+
+// memoryVPACacheStore keeps cache entries in the memory of this instance
+type memoryVPACacheStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryVPAEntry
+	now     func() time.Time
+}
+
+type memoryVPAEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewMemoryVPACacheStore creates a VPA cache store local to this instance.
+// VPA writes made through other instances do not invalidate it, so it
+// suits a single instance.
+func NewMemoryVPACacheStore() VPACacheStore {
+	return &memoryVPACacheStore{entries: make(map[string]memoryVPAEntry), now: time.Now}
+}
+
+func (m *memoryVPACacheStore) GetVPAMapping(ctx context.Context, vpa string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[vpa]
+	if !ok {
+		return nil, nil
+	}
+	if !m.now().Before(entry.expiresAt) {
+		delete(m.entries, vpa)
+		return nil, nil
+	}
+	return entry.data, nil
+}
+
+func (m *memoryVPACacheStore) SetVPAMapping(ctx context.Context, vpa string, entry []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if len(m.entries) >= maxMemoryVPAEntries {
+		for key, cached := range m.entries {
+			if !now.Before(cached.expiresAt) {
+				delete(m.entries, key)
+			}
+		}
+		if len(m.entries) >= maxMemoryVPAEntries {
+			// Full of live entries; this one goes uncached
+			return nil
+		}
+	}
+	m.entries[vpa] = memoryVPAEntry{data: entry, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (m *memoryVPACacheStore) DeleteVPAMapping(ctx context.Context, vpa string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, vpa)
+	return nil
+}
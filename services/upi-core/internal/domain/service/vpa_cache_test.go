@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+func TestVPACacheCachesUntilInvalidated(t *testing.T) {
+	repo := newFakeRepository()
+	repo.vpas["bob@sbi"] = &repository.VPAMapping{VPA: "bob@sbi", BankCode: "SBI", AccountNumber: "1", IsActive: false}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	cache := NewVPACache(repo, NewMemoryVPACacheStore(), config.VPACacheConfig{}, logger)
+	ctx := context.Background()
+
+	// An unknown VPA is cached as unknown
+	if _, err := cache.Resolve(ctx, "bob@sbi"); !errors.Is(err, ErrVPANotFound) {
+		t.Fatalf("inactive VPA: err = %v, want ErrVPANotFound", err)
+	}
+	repo.vpas["bob@sbi"].IsActive = true
+	if _, err := cache.Resolve(ctx, "bob@sbi"); !errors.Is(err, ErrVPANotFound) {
+		t.Errorf("VPA activated behind the cache's back: err = %v, want the cached ErrVPANotFound", err)
+	}
+	cache.Invalidate(ctx, "bob@sbi")
+	if mapping, err := cache.Resolve(ctx, "bob@sbi"); err != nil || mapping.AccountNumber != "1" {
+		t.Fatalf("after invalidation: resolved %+v, %v", mapping, err)
+	}
+
+	// So is the full mapping of a known one
+	repo.vpas["bob@sbi"].AccountNumber = "2"
+	if mapping, _ := cache.Resolve(ctx, "bob@sbi"); mapping.AccountNumber != "1" || mapping.BankCode != "SBI" {
+		t.Errorf("resolved %+v, want the cached mapping", mapping)
+	}
+	cache.Invalidate(ctx, "bob@sbi")
+	if mapping, _ := cache.Resolve(ctx, "bob@sbi"); mapping.AccountNumber != "2" {
+		t.Errorf("after invalidation: resolved %+v, want account 2", mapping)
+	}
+}
+
+func TestVPACacheJittersTTLs(t *testing.T) {
+	cache := NewVPACache(nil, nil, config.VPACacheConfig{Jitter: 0.1}, logrus.New())
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		ttl := cache.jitter(time.Hour)
+		if ttl > time.Hour || ttl < 54*time.Minute {
+			t.Fatalf("jittered TTL %v outside [54m, 1h]", ttl)
+		}
+		seen[ttl] = true
+	}
+	if len(seen) < 2 {
+		t.Error("TTLs are not jittered")
+	}
+}
+
+func TestMemoryVPACacheStoreExpiresEntries(t *testing.T) {
+	now := time.Now()
+	store := &memoryVPACacheStore{entries: make(map[string]memoryVPAEntry), now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	store.SetVPAMapping(ctx, "alice@hdfc", []byte("entry"), time.Minute)
+	if entry, _ := store.GetVPAMapping(ctx, "alice@hdfc"); string(entry) != "entry" {
+		t.Fatalf("entry = %q", entry)
+	}
+	now = now.Add(time.Minute)
+	if entry, _ := store.GetVPAMapping(ctx, "alice@hdfc"); entry != nil {
+		t.Errorf("expired entry = %q, want none", entry)
+	}
+}
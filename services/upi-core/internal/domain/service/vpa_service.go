@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/domain/repository"
+	"upi-core/pkg/upi"
+)
+
+var (
+	// ErrInvalidVPARequest wraps the reason a VPA request was rejected
+	// before reaching the database
+	ErrInvalidVPARequest = errors.New("invalid VPA request")
+	// ErrVPANotFound is returned for a VPA that is not registered or has
+	// been deactivated
+	ErrVPANotFound = errors.New("VPA not found")
+	// ErrVPAOutsideNamespace is returned when a bank registers a VPA under
+	// a handle it does not own
+	ErrVPAOutsideNamespace = errors.New("VPA is outside the bank's namespace")
+)
+
+const (
+	// maxVPALen and maxAccountNumberLen are the widths of their
+	// vpa_mappings columns
+	maxVPALen           = 100
+	maxAccountNumberLen = 20
+)
+
+// VPAService resolves VPAs and manages their registration by banks
+type VPAService struct {
+	repo   repository.TransactionRepository
+	cache  *VPACache
+	logger *logrus.Logger
+}
+
+// NewVPAService creates a new VPA service
+func NewVPAService(repo repository.TransactionRepository, cache *VPACache, logger *logrus.Logger) *VPAService {
+	return &VPAService{
+		repo:   repo,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// Resolve returns the account an active VPA maps to
+func (s *VPAService) Resolve(ctx context.Context, vpa string) (*repository.VPAMapping, error) {
+	return s.cache.Resolve(ctx, vpa)
+}
+
+// Register maps a new VPA to an account at its bank. The VPA's PSP part
+// must be one of the bank's VPA handles.
+func (s *VPAService) Register(ctx context.Context, mapping *repository.VPAMapping) error {
+	vpa, err := upi.ParseVPA(mapping.VPA)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidVPARequest, err)
+	}
+	if len(mapping.VPA) > maxVPALen {
+		return fmt.Errorf("%w: VPA must be at most %d characters", ErrInvalidVPARequest, maxVPALen)
+	}
+	if err := validateAccountNumber(mapping.AccountNumber); err != nil {
+		return err
+	}
+	if mapping.AccountHolderName == "" {
+		return fmt.Errorf("%w: account holder name is required", ErrInvalidVPARequest)
+	}
+
+	bank, err := s.repo.GetBankByCode(ctx, mapping.BankCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: bank %s is not registered", ErrInvalidVPARequest, mapping.BankCode)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load bank: %w", err)
+	}
+	if bank.Status != "ACTIVE" {
+		return fmt.Errorf("%w: bank %s is %s", ErrInvalidVPARequest, bank.BankCode, bank.Status)
+	}
+	if !ownsHandle(bank, vpa.PSP) {
+		return fmt.Errorf("%w: @%s does not belong to %s", ErrVPAOutsideNamespace, vpa.PSP, bank.BankCode)
+	}
+
+	if err := s.repo.CreateVPAMapping(ctx, nil, mapping); err != nil {
+		if errors.Is(err, repository.ErrVPAAlreadyRegistered) {
+			return err
+		}
+		return fmt.Errorf("failed to register VPA: %w", err)
+	}
+	s.cache.Invalidate(ctx, mapping.VPA)
+	s.audit(ctx, mapping.VPA, "REGISTER", map[string]interface{}{
+		"bank_code":      mapping.BankCode,
+		"account_number": mapping.AccountNumber,
+	})
+	return nil
+}
+
+// Update points an active VPA at another account at the same bank
+func (s *VPAService) Update(ctx context.Context, vpa, accountNumber string) error {
+	if err := validateAccountNumber(accountNumber); err != nil {
+		return err
+	}
+
+	err := s.repo.UpdateVPAMapping(ctx, nil, vpa, &repository.VPAMapping{AccountNumber: accountNumber})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrVPANotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update VPA: %w", err)
+	}
+	s.cache.Invalidate(ctx, vpa)
+	s.audit(ctx, vpa, "UPDATE", map[string]interface{}{
+		"account_number": accountNumber,
+	})
+	return nil
+}
+
+// Deactivate stops a VPA resolving
+func (s *VPAService) Deactivate(ctx context.Context, vpa, reason string) error {
+	err := s.repo.DeactivateVPA(ctx, nil, vpa)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrVPANotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to deactivate VPA: %w", err)
+	}
+	s.cache.Invalidate(ctx, vpa)
+	s.audit(ctx, vpa, "DEACTIVATE", map[string]interface{}{
+		"reason": reason,
+	})
+	return nil
+}
+
+func validateAccountNumber(accountNumber string) error {
+	if accountNumber == "" {
+		return fmt.Errorf("%w: account number is required", ErrInvalidVPARequest)
+	}
+	if len(accountNumber) > maxAccountNumberLen {
+		return fmt.Errorf("%w: account number must be at most %d characters", ErrInvalidVPARequest, maxAccountNumberLen)
+	}
+	return nil
+}
+
+// ownsHandle reports whether bank may register VPAs @psp
+func ownsHandle(bank *repository.Bank, psp string) bool {
+	for _, handle := range bank.VPAHandles {
+		if strings.EqualFold(handle, psp) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *VPAService) audit(ctx context.Context, vpa, action string, values map[string]interface{}) {
+	if err := s.repo.LogAudit(ctx, nil, "vpa", vpa, action, "SYSTEM", nil, values, ""); err != nil {
+		s.logger.WithError(err).WithField("vpa", vpa).Warn("Failed to log VPA audit entry")
+	}
+}
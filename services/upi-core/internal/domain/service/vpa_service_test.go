@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+)
+
+// fakeCache caches nothing and records the VPAs invalidated
+type fakeCache struct {
+	deleted []string
+}
+
+func (c *fakeCache) GetVPAMapping(ctx context.Context, vpa string) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *fakeCache) SetVPAMapping(ctx context.Context, vpa string, entry []byte, ttl time.Duration) error {
+	return nil
+}
+
+func (c *fakeCache) DeleteVPAMapping(ctx context.Context, vpa string) error {
+	c.deleted = append(c.deleted, vpa)
+	return nil
+}
+
+func newTestVPAService() (*VPAService, *fakeRepository, *fakeCache) {
+	repo := newFakeRepository()
+	repo.banks = map[string]*repository.Bank{
+		"HDFC": {BankCode: "HDFC", Status: "ACTIVE", VPAHandles: []string{"hdfc", "okhdfcbank"}},
+		"SBI":  {BankCode: "SBI", Status: "SUSPENDED", VPAHandles: []string{"sbi"}},
+	}
+	cache := &fakeCache{}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewVPAService(repo, NewVPACache(repo, cache, config.VPACacheConfig{}, logger), logger), repo, cache
+}
+
+func alice(vpa, bankCode string) *repository.VPAMapping {
+	return &repository.VPAMapping{VPA: vpa, BankCode: bankCode, AccountNumber: "1234567890", AccountHolderName: "Alice"}
+}
+
+func TestVPALifecycle(t *testing.T) {
+	s, repo, cache := newTestVPAService()
+	ctx := context.Background()
+
+	if err := s.Register(ctx, alice("alice@okhdfcbank", "HDFC")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register(ctx, alice("alice@okhdfcbank", "HDFC")); !errors.Is(err, repository.ErrVPAAlreadyRegistered) {
+		t.Errorf("second registration: err = %v, want ErrVPAAlreadyRegistered", err)
+	}
+
+	if err := s.Update(ctx, "alice@okhdfcbank", "5555"); err != nil {
+		t.Fatal(err)
+	}
+	if mapping, err := s.Resolve(ctx, "alice@okhdfcbank"); err != nil || mapping.AccountNumber != "5555" {
+		t.Fatalf("resolved %+v, %v after update", mapping, err)
+	}
+
+	if err := s.Deactivate(ctx, "alice@okhdfcbank", "closed account"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Resolve(ctx, "alice@okhdfcbank"); !errors.Is(err, ErrVPANotFound) {
+		t.Errorf("resolve after deactivation: err = %v, want ErrVPANotFound", err)
+	}
+	if err := s.Update(ctx, "alice@okhdfcbank", "6666"); !errors.Is(err, ErrVPANotFound) {
+		t.Errorf("update after deactivation: err = %v, want ErrVPANotFound", err)
+	}
+
+	// A deactivated VPA can be registered again
+	if err := s.Register(ctx, alice("alice@okhdfcbank", "HDFC")); err != nil {
+		t.Errorf("registration after deactivation: %v", err)
+	}
+
+	if len(cache.deleted) != 4 {
+		t.Errorf("cache invalidated %d times, want once per write: %v", len(cache.deleted), cache.deleted)
+	}
+	if len(repo.audits) != 4 {
+		t.Errorf("audit log = %v, want one entry per write", repo.audits)
+	}
+}
+
+func TestRegisterVPAEnforcesBankNamespace(t *testing.T) {
+	s, _, cache := newTestVPAService()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		mapping *repository.VPAMapping
+		want    error
+	}{
+		{alice("alice@HDFC", "HDFC"), nil},
+		{alice("alice@sbi", "HDFC"), ErrVPAOutsideNamespace},
+		{alice("bob@sbi", "SBI"), ErrInvalidVPARequest},
+		{alice("carol@icici", "ICICI"), ErrInvalidVPARequest},
+		{alice("not-a-vpa", "HDFC"), ErrInvalidVPARequest},
+		{&repository.VPAMapping{VPA: "dave@hdfc", BankCode: "HDFC", AccountHolderName: "Dave"}, ErrInvalidVPARequest},
+	} {
+		err := s.Register(ctx, tc.mapping)
+		if tc.want == nil && err != nil || tc.want != nil && !errors.Is(err, tc.want) {
+			t.Errorf("register %s at %s: err = %v, want %v", tc.mapping.VPA, tc.mapping.BankCode, err, tc.want)
+		}
+	}
+	if len(cache.deleted) != 1 {
+		t.Errorf("cache invalidated for rejected registrations: %v", cache.deleted)
+	}
+}
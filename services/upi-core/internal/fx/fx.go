@@ -0,0 +1,213 @@
+// Package fx converts transaction amounts from the currency they are made
+// in to the currency the switch settles in, through a pluggable source of
+// exchange rates.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"upi-core/internal/config"
+)
+
+// ErrUnsupportedCurrency is returned for a currency transactions may not be
+// made in
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// ErrRateUnavailable is returned when no exchange rate can be had for a
+// currency pair
+var ErrRateUnavailable = errors.New("exchange rate unavailable")
+
+// minorUnits is the number of decimal places of the minor unit of each
+// currency the switch knows, per ISO 4217. Amounts are always carried in
+// minor units: paise for INR, cents for USD, yen for JPY.
+var minorUnits = map[string]int{
+	"INR": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"AED": 2,
+	"SGD": 2,
+	"AUD": 2,
+	"CAD": 2,
+	"JPY": 0,
+}
+
+// RateProvider quotes exchange rates
+type RateProvider interface {
+	// Rate returns how many units of to one unit of from buys
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+// Conversion is an amount converted to the settlement currency
+type Conversion struct {
+	SourceAmount    int64 // In minor units of SourceCurrency
+	SourceCurrency  string
+	SettledAmount   int64 // In minor units of SettledCurrency
+	SettledCurrency string
+	// Rate is the exchange rate applied, as a decimal; empty if the source
+	// is the settlement currency
+	Rate string
+	// FeeAmount is the cross-currency fee, in minor units of SettledCurrency
+	FeeAmount int64
+}
+
+// Converter validates transaction currencies and converts amounts to the
+// settlement currency
+type Converter struct {
+	provider   RateProvider
+	settlement string
+	accepted   map[string]bool
+	markupBps  int64
+}
+
+// NewConverter creates a converter quoting rates from provider. The
+// settlement currency is always accepted.
+func NewConverter(provider RateProvider, cfg config.FXConfig) (*Converter, error) {
+	settlement := strings.ToUpper(cfg.SettlementCurrency)
+	if settlement == "" {
+		settlement = "INR"
+	}
+	if _, ok := minorUnits[settlement]; !ok {
+		return nil, fmt.Errorf("%w: settlement currency %s", ErrUnsupportedCurrency, settlement)
+	}
+	if cfg.MarkupBps < 0 {
+		return nil, fmt.Errorf("negative FX markup of %d basis points", cfg.MarkupBps)
+	}
+
+	accepted := map[string]bool{settlement: true}
+	for _, currency := range cfg.Currencies {
+		currency = strings.ToUpper(currency)
+		if _, ok := minorUnits[currency]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+		}
+		accepted[currency] = true
+	}
+	return &Converter{provider: provider, settlement: settlement, accepted: accepted, markupBps: cfg.MarkupBps}, nil
+}
+
+// SettlementCurrency returns the currency amounts are converted to
+func (c *Converter) SettlementCurrency() string {
+	return c.settlement
+}
+
+// Validate checks transactions may be made in currency
+func (c *Converter) Validate(currency string) error {
+	if !c.accepted[currency] {
+		return fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+	return nil
+}
+
+// Convert converts amount, in minor units of currency, to the settlement
+// currency, rounding half up, and works out the cross-currency fee on it.
+// An amount already in the settlement currency is returned as is, free of
+// that fee.
+func (c *Converter) Convert(ctx context.Context, amount int64, currency string) (*Conversion, error) {
+	if err := c.Validate(currency); err != nil {
+		return nil, err
+	}
+	conversion := &Conversion{
+		SourceAmount:    amount,
+		SourceCurrency:  currency,
+		SettledAmount:   amount,
+		SettledCurrency: c.settlement,
+	}
+	if currency == c.settlement {
+		return conversion, nil
+	}
+
+	rate, err := c.provider.Rate(ctx, currency, c.settlement)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s to %s: %v", ErrRateUnavailable, currency, c.settlement, err)
+	}
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %s to %s quoted at %s", ErrRateUnavailable, currency, c.settlement, rate.FloatString(6))
+	}
+
+	// settled = amount / 10^source units * rate * 10^settlement units
+	settled := new(big.Rat).SetInt64(amount)
+	settled.Mul(settled, rate)
+	settled.Mul(settled, scale(minorUnits[c.settlement]-minorUnits[currency]))
+	conversion.SettledAmount = roundHalfUp(settled)
+	conversion.Rate = formatRate(rate)
+
+	fee := new(big.Rat).SetInt64(conversion.SettledAmount)
+	fee.Mul(fee, big.NewRat(c.markupBps, 10000))
+	conversion.FeeAmount = roundHalfUp(fee)
+	return conversion, nil
+}
+
+// scale returns 10^exp, which may be negative
+func scale(exp int) *big.Rat {
+	if exp < 0 {
+		return new(big.Rat).Inv(scale(-exp))
+	}
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+}
+
+// roundHalfUp rounds a non-negative amount to the nearest whole minor unit
+func roundHalfUp(r *big.Rat) int64 {
+	half := new(big.Rat).Add(r, big.NewRat(1, 2))
+	return new(big.Int).Quo(half.Num(), half.Denom()).Int64()
+}
+
+// formatRate formats a rate with up to 10 decimal places and no trailing
+// zeros, as stored in transactions.fx_rate
+func formatRate(rate *big.Rat) string {
+	s := rate.FloatString(10)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// StaticRates quotes fixed exchange rates against a base currency, such as
+// ones set in configuration. Pairs of other currencies are crossed through
+// the base.
+type StaticRates struct {
+	base  string
+	rates map[string]*big.Rat // Units of base one unit of the currency buys
+}
+
+var _ RateProvider = (*StaticRates)(nil)
+
+// NewStaticRates creates a provider from decimal rates, such as "83.25",
+// giving the units of base one unit of each currency buys
+func NewStaticRates(base string, rates map[string]string) (*StaticRates, error) {
+	s := &StaticRates{base: strings.ToUpper(base), rates: make(map[string]*big.Rat, len(rates))}
+	for currency, value := range rates {
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate %q for %s", value, currency)
+		}
+		// Config keys are case-insensitive; currency codes are upper case
+		s.rates[strings.ToUpper(currency)] = rate
+	}
+	return s, nil
+}
+
+// Rate returns the configured rate of a pair
+func (s *StaticRates) Rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	fromBase, err := s.toBase(from)
+	if err != nil {
+		return nil, err
+	}
+	toBase, err := s.toBase(to)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).Quo(fromBase, toBase), nil
+}
+
+func (s *StaticRates) toBase(currency string) (*big.Rat, error) {
+	if currency == s.base {
+		return big.NewRat(1, 1), nil
+	}
+	rate, ok := s.rates[currency]
+	if !ok {
+		return nil, fmt.Errorf("no rate configured for %s", currency)
+	}
+	return rate, nil
+}
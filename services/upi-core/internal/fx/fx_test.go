@@ -0,0 +1,90 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"upi-core/internal/config"
+)
+
+func newTestConverter(t *testing.T, markupBps int64) *Converter {
+	t.Helper()
+	rates, err := NewStaticRates("INR", map[string]string{"usd": "83.25", "jpy": "0.5575"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	converter, err := NewConverter(rates, config.FXConfig{
+		SettlementCurrency: "INR",
+		Currencies:         []string{"usd", "JPY", "EUR"},
+		MarkupBps:          markupBps,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return converter
+}
+
+func TestConvert(t *testing.T) {
+	converter := newTestConverter(t, 150)
+	for _, tc := range []struct {
+		amount   int64
+		currency string
+		settled  int64
+		rate     string
+		fee      int64
+	}{
+		{10000, "INR", 10000, "", 0},
+		// $10.00 at 83.25 is ₹832.50, with a 1.5% fee of ₹12.49 (12.4875)
+		{1000, "USD", 83250, "83.25", 1249},
+		// One cent is 83.25 paise, rounded half up to 83
+		{1, "USD", 83, "83.25", 1},
+		// ¥1,000 has no minor unit: ₹557.50
+		{1000, "JPY", 55750, "0.5575", 836},
+	} {
+		conversion, err := converter.Convert(context.Background(), tc.amount, tc.currency)
+		if err != nil {
+			t.Fatalf("%d %s: %v", tc.amount, tc.currency, err)
+		}
+		if conversion.SettledAmount != tc.settled || conversion.SettledCurrency != "INR" || conversion.Rate != tc.rate || conversion.FeeAmount != tc.fee {
+			t.Errorf("%d %s converted to %+v, want %d INR at %q with fee %d", tc.amount, tc.currency, conversion, tc.settled, tc.rate, tc.fee)
+		}
+	}
+}
+
+func TestConvertRejectsUnsupportedCurrencies(t *testing.T) {
+	converter := newTestConverter(t, 0)
+	for _, currency := range []string{"GBP", "XYZ", "usd", ""} {
+		if _, err := converter.Convert(context.Background(), 100, currency); !errors.Is(err, ErrUnsupportedCurrency) {
+			t.Errorf("%q: err = %v, want ErrUnsupportedCurrency", currency, err)
+		}
+	}
+
+	// Accepted, but no rate configured
+	if _, err := converter.Convert(context.Background(), 100, "EUR"); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("EUR: err = %v, want ErrRateUnavailable", err)
+	}
+
+	if _, err := NewConverter(nil, config.FXConfig{Currencies: []string{"XYZ"}}); !errors.Is(err, ErrUnsupportedCurrency) {
+		t.Errorf("unknown accepted currency: err = %v, want ErrUnsupportedCurrency", err)
+	}
+}
+
+func TestStaticRatesCrossThroughBase(t *testing.T) {
+	rates, err := NewStaticRates("INR", map[string]string{"USD": "80", "EUR": "90"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rate, err := rates.Rate(context.Background(), "EUR", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Cmp(big.NewRat(9, 8)) != 0 {
+		t.Errorf("EUR/USD = %s, want 1.125", rate.FloatString(3))
+	}
+
+	if _, err := NewStaticRates("INR", map[string]string{"USD": "-1"}); err == nil {
+		t.Error("negative rate accepted")
+	}
+}
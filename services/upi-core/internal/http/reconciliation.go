@@ -0,0 +1,68 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+)
+
+// reconExceptionsHeader is the header row of the exceptions CSV
+var reconExceptionsHeader = []string{
+	"business_date", "transaction_id", "exception_type", "bank_code", "switch_status",
+	"bank_status", "switch_amount_paisa", "bank_amount_paisa", "details", "detected_at",
+}
+
+// exportReconExceptions writes the exceptions the latest reconciliation of
+// a business day found as CSV, one row per exception after a header row
+func (s *HTTPServer) exportReconExceptions(w http.ResponseWriter, r *http.Request) {
+	date := mux.Vars(r)["date"]
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		http.Error(w, "date must be a YYYY-MM-DD date", http.StatusBadRequest)
+		return
+	}
+
+	run, exceptions, err := s.reconciler.Report(r.Context(), day)
+	switch {
+	case errors.Is(err, service.ErrReconRunNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		s.logger.WithError(err).Error("Failed to load reconciliation report")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	case run.Status == repository.ReconRunning:
+		http.Error(w, "reconciliation of "+date+" is in progress", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="recon-exceptions-`+date+`.csv"`)
+	out := csv.NewWriter(w)
+	out.Write(reconExceptionsHeader)
+	for _, exception := range exceptions {
+		out.Write([]string{
+			date,
+			exception.TransactionID,
+			string(exception.Type),
+			exception.BankCode,
+			string(exception.SwitchStatus),
+			exception.BankStatus,
+			strconv.FormatInt(exception.SwitchAmountPaisa, 10),
+			strconv.FormatInt(exception.BankAmountPaisa, 10),
+			exception.Details,
+			exception.DetectedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		s.logger.WithError(err).Warn("Failed to write reconciliation exceptions")
+	}
+}
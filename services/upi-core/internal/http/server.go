@@ -3,7 +3,9 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,11 +15,17 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"upi-core/internal/domain/service"
+	"upi-core/internal/ratelimit"
+	"upi-core/internal/upierr"
 	pb "upi-core/pkg/pb"
+	"upi-core/pkg/upi"
 )
 
 type HTTPServer struct {
 	transactionService *service.TransactionService
+	vpaService         *service.VPAService
+	reconciler         *service.Reconciler
+	limiter            *ratelimit.Limiter
 	logger             *logrus.Logger
 	server             *http.Server
 }
@@ -32,6 +40,10 @@ type TransactionRequest struct {
 	Description   string            `json:"description"`
 	Reference     string            `json:"reference"`
 	Metadata      map[string]string `json:"metadata"`
+	// Signature is the payer bank's signature; a signed request carries the
+	// initiation time it was signed with
+	Signature   string     `json:"signature,omitempty"`
+	InitiatedAt *time.Time `json:"initiatedAt,omitempty"`
 }
 
 type TransactionResponse struct {
@@ -45,11 +57,16 @@ type TransactionResponse struct {
 	ProcessedAt   time.Time `json:"processedAt"`
 	Fees          *Fees     `json:"fees,omitempty"`
 	SettlementID  string    `json:"settlementId,omitempty"`
+
+	SettledAmountPaisa int64  `json:"settledAmountPaisa,omitempty"`
+	SettledCurrency    string `json:"settledCurrency,omitempty"`
+	FXRate             string `json:"fxRate,omitempty"`
 }
 
 type Fees struct {
 	SwitchFeePaisa int64 `json:"switchFeePaisa"`
 	BankFeePaisa   int64 `json:"bankFeePaisa"`
+	FXFeePaisa     int64 `json:"fxFeePaisa,omitempty"`
 	TotalFeePaisa  int64 `json:"totalFeePaisa"`
 }
 
@@ -93,24 +110,43 @@ type ProcessPaymentResponse struct {
 	TransactionId   string `json:"transactionId"`   // UPI transaction ID
 }
 
-func NewHTTPServer(transactionService *service.TransactionService, logger *logrus.Logger, port string) *HTTPServer {
+// NewHTTPServer creates the REST API server. Requests are rate limited per
+// caller unless limiter is nil.
+func NewHTTPServer(transactionService *service.TransactionService, vpaService *service.VPAService, reconciler *service.Reconciler, limiter *ratelimit.Limiter, logger *logrus.Logger, port string) *HTTPServer {
 	router := mux.NewRouter()
 
 	server := &HTTPServer{
 		transactionService: transactionService,
+		vpaService:         vpaService,
+		reconciler:         reconciler,
+		limiter:            limiter,
 		logger:             logger,
 	}
 
 	// Middleware
 	router.Use(server.loggingMiddleware)
 	router.Use(server.corsMiddleware)
+	if limiter != nil {
+		router.Use(server.rateLimitMiddleware)
+	}
 
 	// Routes
 	router.HandleFunc("/health", server.healthCheck).Methods("GET")
 
 	// Original UPI transaction routes
 	router.HandleFunc("/upi/transactions", server.processTransaction).Methods("POST")
+	router.HandleFunc("/upi/transactions", server.listTransactions).Methods("GET")
 	router.HandleFunc("/upi/transactions/{transactionId}", server.getTransactionStatus).Methods("GET")
+	router.HandleFunc("/upi/transactions/rrn/{rrn}", server.getTransactionByRRN).Methods("GET")
+
+	// VPA routes
+	router.HandleFunc("/upi/vpa", server.registerVPA).Methods("POST")
+	router.HandleFunc("/upi/vpa/{vpa}", server.resolveVPA).Methods("GET")
+	router.HandleFunc("/upi/vpa/{vpa}", server.updateVPA).Methods("PUT")
+	router.HandleFunc("/upi/vpa/{vpa}", server.deactivateVPA).Methods("DELETE")
+
+	// Reconciliation routes
+	router.HandleFunc("/upi/reconciliation/{date}/exceptions.csv", server.exportReconExceptions).Methods("GET")
 
 	// Payment API routes (matching frontend expectations)
 	router.HandleFunc("/payments/api/v1/intents", server.createPaymentIntent).Methods("POST")
@@ -164,7 +200,7 @@ func (s *HTTPServer) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, X-Caller-ID")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -175,6 +211,27 @@ func (s *HTTPServer) corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// rateLimitMiddleware answers requests of callers over their quota with 429
+// and a Retry-After header. Callers identify themselves with X-Caller-ID;
+// health checks are never limited.
+func (s *HTTPServer) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		caller := s.limiter.Caller(r.Header.Get("X-Caller-ID"), r.RemoteAddr)
+		if allowed, wait := s.limiter.Allow(r.Context(), caller); !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ratelimit.RetryAfterSeconds(wait)))
+			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (s *HTTPServer) healthCheck(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
 		"status":    "healthy",
@@ -187,32 +244,22 @@ func (s *HTTPServer) healthCheck(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *HTTPServer) processTransaction(w http.ResponseWriter, r *http.Request) {
-	var req TransactionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	grpcReq, err := s.decodeTransactionRequest(r.Body)
+	if err != nil {
 		s.logger.WithError(err).Error("Failed to decode transaction request")
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	// Convert HTTP request to gRPC request
-	grpcReq := &pb.TransactionRequest{
-		TransactionId: req.TransactionID,
-		PayerVpa:      req.PayerVPA,
-		PayeeVpa:      req.PayeeVPA,
-		AmountPaisa:   req.AmountPaisa,
-		Currency:      req.Currency,
-		Type:          s.parseTransactionType(req.Type),
-		Description:   req.Description,
-		Reference:     req.Reference,
-		InitiatedAt:   timestamppb.Now(),
-		Metadata:      req.Metadata,
-	}
-
 	// Process transaction
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	grpcResp, err := s.transactionService.ProcessTransaction(ctx, grpcReq)
+	if errors.Is(err, service.ErrShuttingDown) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to process transaction")
 		http.Error(w, "Transaction processing failed", http.StatusInternalServerError)
@@ -230,12 +277,17 @@ func (s *HTTPServer) processTransaction(w http.ResponseWriter, r *http.Request)
 		PayeeBankCode: grpcResp.PayeeBankCode,
 		ProcessedAt:   grpcResp.ProcessedAt.AsTime(),
 		SettlementID:  grpcResp.SettlementId,
+
+		SettledAmountPaisa: grpcResp.SettledAmountPaisa,
+		SettledCurrency:    grpcResp.SettledCurrency,
+		FXRate:             grpcResp.FxRate,
 	}
 
 	if grpcResp.Fees != nil {
 		httpResp.Fees = &Fees{
 			SwitchFeePaisa: grpcResp.Fees.SwitchFeePaisa,
 			BankFeePaisa:   grpcResp.Fees.BankFeePaisa,
+			FXFeePaisa:     grpcResp.Fees.FxFeePaisa,
 			TotalFeePaisa:  grpcResp.Fees.TotalFeePaisa,
 		}
 	}
@@ -244,31 +296,37 @@ func (s *HTTPServer) processTransaction(w http.ResponseWriter, r *http.Request)
 	if grpcResp.Status == pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
 		w.WriteHeader(http.StatusOK)
 	} else {
-		w.WriteHeader(http.StatusBadRequest)
+		w.WriteHeader(upierr.Code(grpcResp.ErrorCode).HTTPStatus())
 	}
 
 	json.NewEncoder(w).Encode(httpResp)
 }
 
-func (s *HTTPServer) getTransactionStatus(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	transactionID := vars["transactionId"]
-
-	if transactionID == "" {
-		http.Error(w, "Transaction ID is required", http.StatusBadRequest)
-		return
+// decodeTransactionRequest converts a JSON transaction request to the gRPC
+// one. Validation is left to the transaction service, which reports it in
+// the response like any other failed transaction.
+func (s *HTTPServer) decodeTransactionRequest(body io.Reader) (*pb.TransactionRequest, error) {
+	var req TransactionRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return nil, err
 	}
-
-	// For now, return a mock response
-	// In a real implementation, you'd query the database
-	response := &TransactionResponse{
-		TransactionID: transactionID,
-		Status:        "SUCCESS",
-		ProcessedAt:   time.Now(),
+	initiatedAt := timestamppb.Now()
+	if req.InitiatedAt != nil {
+		initiatedAt = timestamppb.New(*req.InitiatedAt)
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	return &pb.TransactionRequest{
+		TransactionId: req.TransactionID,
+		PayerVpa:      req.PayerVPA,
+		PayeeVpa:      req.PayeeVPA,
+		AmountPaisa:   req.AmountPaisa,
+		Currency:      req.Currency,
+		Type:          s.parseTransactionType(req.Type),
+		Description:   req.Description,
+		Reference:     req.Reference,
+		Signature:     req.Signature,
+		InitiatedAt:   initiatedAt,
+		Metadata:      req.Metadata,
+	}, nil
 }
 
 func (s *HTTPServer) parseTransactionType(typeStr string) pb.TransactionType {
@@ -290,23 +348,13 @@ func (s *HTTPServer) parseTransactionType(typeStr string) pb.TransactionType {
 
 // createPaymentIntent creates a payment intent (similar to Stripe's payment intents)
 func (s *HTTPServer) createPaymentIntent(w http.ResponseWriter, r *http.Request) {
-	var req PaymentIntentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	req, err := decodePaymentIntentRequest(r.Body)
+	if err != nil {
 		s.logger.WithError(err).Error("Failed to decode payment intent request")
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Validate request
-	if req.Amount <= 0 {
-		http.Error(w, "Amount must be greater than 0", http.StatusBadRequest)
-		return
-	}
-
-	if req.Currency == "" {
-		req.Currency = "INR" // Default to INR
-	}
-
 	// Generate a payment intent ID
 	paymentIntentID := fmt.Sprintf("pi_%d", time.Now().UnixNano())
 
@@ -333,6 +381,25 @@ func (s *HTTPServer) createPaymentIntent(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
+// decodePaymentIntentRequest reads and validates a payment intent request,
+// defaulting the currency to INR.
+func decodePaymentIntentRequest(body io.Reader) (*PaymentIntentRequest, error) {
+	var req PaymentIntentRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return nil, errors.New("Invalid request body")
+	}
+	if req.Amount <= 0 {
+		return nil, errors.New("Amount must be greater than 0")
+	}
+	if int64(req.Amount) > upi.MaxAmountPaisa {
+		return nil, fmt.Errorf("Amount exceeds the UPI limit of %d paisa", upi.MaxAmountPaisa)
+	}
+	if req.Currency == "" {
+		req.Currency = "INR" // Default to INR
+	}
+	return &req, nil
+}
+
 // processPayment processes a payment using UPI Core
 func (s *HTTPServer) processPayment(w http.ResponseWriter, r *http.Request) {
 	var req ProcessPaymentRequest
@@ -409,6 +476,10 @@ func (s *HTTPServer) processPayment(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	upiResp, err := s.transactionService.ProcessTransaction(ctx, upiReq)
+	if errors.Is(err, service.ErrShuttingDown) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
 	if err != nil {
 		s.logger.WithError(err).WithFields(logrus.Fields{
 			"payment_intent_id": req.PaymentIntentId,
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"strings"
+	"testing"
+
+	"upi-core/internal/domain/service"
+	"upi-core/pkg/upi"
+)
+
+func FuzzDecodeTransactionRequest(f *testing.F) {
+	for _, seed := range []string{
+		`{"transactionId":"T1","payerVpa":"alice@hdfc","payeeVpa":"bob@sbi","amountPaisa":10000,"currency":"INR","type":"P2P"}`,
+		`{"transactionId":"T1","payerVpa":"ålice@hdfc","payeeVpa":"bob@ｓｂｉ","amountPaisa":100}`,
+		`{"transactionId":"T1","payerVpa":"alice@hdfc","payeeVpa":"bob@sbi","amountPaisa":-100}`,
+		`{"transactionId":"T1","payerVpa":"alice@hdfc","payeeVpa":"bob@sbi","amountPaisa":9223372036854775807}`,
+		`{"transactionId":"T1","payerVpa":"alice@hdfc","payeeVpa":"bob@sbi","amountPaisa":1e30}`,
+		`{"transactionId":"T1","payerVpa":"alice@hdfc","payeeVpa":"bob@sbi","amountPaisa":100.5}`,
+		`{"metadata":{"\u0000":"\ud800"}}`,
+		`[]`,
+		``,
+	} {
+		f.Add(seed)
+	}
+	s := &HTTPServer{}
+	f.Fuzz(func(t *testing.T, body string) {
+		req, err := s.decodeTransactionRequest(strings.NewReader(body))
+		if err != nil {
+			return
+		}
+		if service.ValidateTransactionRequest(req) != nil {
+			return
+		}
+		if _, err := upi.ParseVPA(req.PayerVpa); err != nil {
+			t.Fatalf("accepted payer %q: %v", req.PayerVpa, err)
+		}
+		if _, err := upi.ParseVPA(req.PayeeVpa); err != nil {
+			t.Fatalf("accepted payee %q: %v", req.PayeeVpa, err)
+		}
+		if req.AmountPaisa <= 0 || req.AmountPaisa > upi.MaxAmountPaisa {
+			t.Fatalf("accepted amount %d paisa", req.AmountPaisa)
+		}
+	})
+}
+
+func FuzzDecodePaymentIntentRequest(f *testing.F) {
+	for _, seed := range []string{
+		`{"amount":2900,"currency":"INR","description":"Pro plan"}`,
+		`{"amount":0}`,
+		`{"amount":-2900}`,
+		`{"amount":9223372036854775807}`,
+		`{"amount":1e30}`,
+		`{"amount":29.5}`,
+		`{"amount":2900,"description":"कृपया"}`,
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		req, err := decodePaymentIntentRequest(strings.NewReader(body))
+		if err != nil {
+			return
+		}
+		if req.Amount <= 0 || int64(req.Amount) > upi.MaxAmountPaisa {
+			t.Fatalf("accepted amount %d", req.Amount)
+		}
+		if req.Currency == "" {
+			t.Fatal("currency not defaulted")
+		}
+	})
+}
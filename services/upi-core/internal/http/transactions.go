@@ -0,0 +1,165 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+)
+
+type TransactionDetails struct {
+	TransactionID      string     `json:"transactionId"`
+	RRN                string     `json:"rrn,omitempty"`
+	Status             string     `json:"status"`
+	Type               string     `json:"type"`
+	AmountPaisa        int64      `json:"amountPaisa"`
+	Currency           string     `json:"currency"`
+	SettledAmountPaisa int64      `json:"settledAmountPaisa"`
+	SettledCurrency    string     `json:"settledCurrency"`
+	FXRate             string     `json:"fxRate,omitempty"`
+	PayerVPA           string     `json:"payerVpa"`
+	PayeeVPA           string     `json:"payeeVpa"`
+	PayerBankCode      string     `json:"payerBankCode"`
+	PayeeBankCode      string     `json:"payeeBankCode"`
+	Description        string     `json:"description,omitempty"`
+	Reference          string     `json:"reference,omitempty"`
+	ErrorCode          string     `json:"errorCode,omitempty"`
+	ErrorMessage       string     `json:"errorMessage,omitempty"`
+	Fees               *Fees      `json:"fees"`
+	SettlementID       string     `json:"settlementId,omitempty"`
+	InitiatedAt        time.Time  `json:"initiatedAt"`
+	ProcessedAt        *time.Time `json:"processedAt,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+}
+
+type TransactionListResponse struct {
+	Transactions []*TransactionDetails `json:"transactions"`
+	NextCursor   string                `json:"nextCursor,omitempty"`
+}
+
+func newTransactionDetails(transaction *repository.Transaction) *TransactionDetails {
+	return &TransactionDetails{
+		TransactionID:      transaction.TransactionID,
+		RRN:                transaction.RRN,
+		Status:             string(transaction.Status),
+		Type:               string(transaction.Type),
+		AmountPaisa:        transaction.AmountPaisa,
+		Currency:           transaction.Currency,
+		SettledAmountPaisa: transaction.SettledAmountPaisa,
+		SettledCurrency:    transaction.SettledCurrency,
+		FXRate:             transaction.FXRate,
+		PayerVPA:           transaction.PayerVPA,
+		PayeeVPA:           transaction.PayeeVPA,
+		PayerBankCode:      transaction.PayerBankCode,
+		PayeeBankCode:      transaction.PayeeBankCode,
+		Description:        transaction.Description,
+		Reference:          transaction.Reference,
+		ErrorCode:          transaction.ErrorCode,
+		ErrorMessage:       transaction.ErrorMessage,
+		Fees: &Fees{
+			SwitchFeePaisa: transaction.SwitchFeePaisa,
+			BankFeePaisa:   transaction.BankFeePaisa,
+			FXFeePaisa:     transaction.FXFeePaisa,
+			TotalFeePaisa:  transaction.TotalFeePaisa,
+		},
+		SettlementID: transaction.SettlementID,
+		InitiatedAt:  transaction.InitiatedAt,
+		ProcessedAt:  transaction.ProcessedAt,
+		CreatedAt:    transaction.CreatedAt,
+	}
+}
+
+func (s *HTTPServer) getTransactionStatus(w http.ResponseWriter, r *http.Request) {
+	transaction, err := s.transactionService.GetTransaction(r.Context(), mux.Vars(r)["transactionId"])
+	s.writeTransaction(w, transaction, err)
+}
+
+func (s *HTTPServer) getTransactionByRRN(w http.ResponseWriter, r *http.Request) {
+	transaction, err := s.transactionService.GetTransactionByRRN(r.Context(), mux.Vars(r)["rrn"])
+	s.writeTransaction(w, transaction, err)
+}
+
+func (s *HTTPServer) writeTransaction(w http.ResponseWriter, transaction *repository.Transaction, err error) {
+	if errors.Is(err, service.ErrTransactionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get transaction")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newTransactionDetails(transaction))
+}
+
+// listTransactions serves a page of transactions, filtered by the vpa,
+// status, from and to (RFC 3339) query parameters. The nextCursor of a
+// response, passed as cursor, gets the next page.
+func (s *HTTPServer) listTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := decodeTransactionFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transactions, next, err := s.transactionService.ListTransactions(r.Context(), filter)
+	if errors.Is(err, service.ErrInvalidTransactionFilter) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list transactions")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	response := &TransactionListResponse{Transactions: make([]*TransactionDetails, 0, len(transactions))}
+	for _, transaction := range transactions {
+		response.Transactions = append(response.Transactions, newTransactionDetails(transaction))
+	}
+	if next != nil {
+		response.NextCursor = next.Token()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func decodeTransactionFilter(query url.Values) (repository.TransactionFilter, error) {
+	filter := repository.TransactionFilter{
+		VPA:    query.Get("vpa"),
+		Status: repository.TransactionStatus(strings.ToUpper(query.Get("status"))),
+	}
+	var err error
+	if from := query.Get("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return filter, errors.New("from must be an RFC 3339 time")
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return filter, errors.New("to must be an RFC 3339 time")
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 {
+			return filter, errors.New("limit must be a positive number")
+		}
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		if filter.After, err = repository.ParseTransactionCursor(cursor); err != nil {
+			return filter, errors.New("invalid cursor")
+		}
+	}
+	return filter, nil
+}
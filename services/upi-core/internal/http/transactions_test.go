@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"upi-core/internal/domain/repository"
+)
+
+func TestDecodeTransactionFilter(t *testing.T) {
+	cursor := &repository.TransactionCursor{
+		CreatedAt: time.Date(2026, 3, 1, 10, 30, 0, 123456000, time.UTC),
+		ID:        "5f0c6d1e-7a41-4c55-9d1f-2a3b4c5d6e7f",
+	}
+	query := url.Values{
+		"vpa":    {"alice@hdfc"},
+		"status": {"success"},
+		"from":   {"2026-03-01T00:00:00Z"},
+		"to":     {"2026-03-02T00:00:00+05:30"},
+		"limit":  {"50"},
+		"cursor": {cursor.Token()},
+	}
+
+	filter, err := decodeTransactionFilter(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.VPA != "alice@hdfc" || filter.Status != repository.StatusSuccess || filter.Limit != 50 {
+		t.Errorf("filter = %+v", filter)
+	}
+	if !filter.From.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || !filter.To.Equal(time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("range = %s - %s", filter.From, filter.To)
+	}
+	if filter.After == nil || !filter.After.CreatedAt.Equal(cursor.CreatedAt) || filter.After.ID != cursor.ID {
+		t.Errorf("cursor = %+v, want %+v", filter.After, cursor)
+	}
+}
+
+func TestDecodeTransactionFilterRejectsMalformedParameters(t *testing.T) {
+	for _, query := range []url.Values{
+		{"from": {"yesterday"}},
+		{"to": {"2026-03-01"}},
+		{"limit": {"0"}},
+		{"limit": {"ten"}},
+		{"cursor": {"not a cursor"}},
+		{"cursor": {(&repository.TransactionCursor{CreatedAt: time.Now()}).Token()}},
+	} {
+		if _, err := decodeTransactionFilter(query); err == nil {
+			t.Errorf("accepted %v", query)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+)
+
+type RegisterVPARequest struct {
+	VPA               string `json:"vpa"`
+	BankCode          string `json:"bankCode"`
+	AccountNumber     string `json:"accountNumber"`
+	AccountHolderName string `json:"accountHolderName"`
+	MobileNumber      string `json:"mobileNumber,omitempty"`
+}
+
+type UpdateVPARequest struct {
+	AccountNumber string `json:"accountNumber"`
+}
+
+type DeactivateVPARequest struct {
+	Reason string `json:"reason"`
+}
+
+type VPAResponse struct {
+	VPA               string    `json:"vpa"`
+	BankCode          string    `json:"bankCode"`
+	AccountNumber     string    `json:"accountNumber"`
+	AccountHolderName string    `json:"accountHolderName"`
+	IsActive          bool      `json:"isActive"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+func newVPAResponse(mapping *repository.VPAMapping) *VPAResponse {
+	return &VPAResponse{
+		VPA:               mapping.VPA,
+		BankCode:          mapping.BankCode,
+		AccountNumber:     mapping.AccountNumber,
+		AccountHolderName: mapping.AccountHolderName,
+		IsActive:          mapping.IsActive,
+		CreatedAt:         mapping.CreatedAt,
+	}
+}
+
+func (s *HTTPServer) resolveVPA(w http.ResponseWriter, r *http.Request) {
+	mapping, err := s.vpaService.Resolve(r.Context(), mux.Vars(r)["vpa"])
+	if err != nil {
+		s.writeVPAError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newVPAResponse(mapping))
+}
+
+func (s *HTTPServer) registerVPA(w http.ResponseWriter, r *http.Request) {
+	var req RegisterVPARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	mapping := &repository.VPAMapping{
+		VPA:               req.VPA,
+		BankCode:          req.BankCode,
+		AccountNumber:     req.AccountNumber,
+		AccountHolderName: req.AccountHolderName,
+		MobileNumber:      req.MobileNumber,
+	}
+	if err := s.vpaService.Register(r.Context(), mapping); err != nil {
+		s.writeVPAError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newVPAResponse(mapping))
+}
+
+func (s *HTTPServer) updateVPA(w http.ResponseWriter, r *http.Request) {
+	var req UpdateVPARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.vpaService.Update(r.Context(), mux.Vars(r)["vpa"], req.AccountNumber); err != nil {
+		s.writeVPAError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *HTTPServer) deactivateVPA(w http.ResponseWriter, r *http.Request) {
+	// The reason is optional, and so is the body
+	var req DeactivateVPARequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := s.vpaService.Deactivate(r.Context(), mux.Vars(r)["vpa"], req.Reason); err != nil {
+		s.writeVPAError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeVPAError answers with the status a VPA service error stands for
+func (s *HTTPServer) writeVPAError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidVPARequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrVPANotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrVPAOutsideNamespace):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, repository.ErrVPAAlreadyRegistered):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		s.logger.WithError(err).Error("VPA operation failed")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
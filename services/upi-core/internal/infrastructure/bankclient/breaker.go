@@ -0,0 +1,156 @@
+package bankclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCircuitOpen is returned for calls to a bank whose circuit breaker is
+// open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// circuitState is the state of a bank's circuit breaker, as recorded in the
+// banks table and reported by the circuit state gauge
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "OPEN"
+	case circuitHalfOpen:
+		return "HALF_OPEN"
+	default:
+		return "CLOSED"
+	}
+}
+
+// breaker stops calls to a bank after threshold failed calls in a row.
+// Once openTimeout has passed it lets a single probe call through: if that
+// succeeds the circuit closes again, if it fails it stays open for another
+// openTimeout.
+type breaker struct {
+	threshold   int
+	openTimeout time.Duration
+	onChange    func(circuitState) // Called outside the lock on every transition
+	now         func() time.Time
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(threshold int, openTimeout time.Duration, onChange func(circuitState)) *breaker {
+	return &breaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		onChange:    onChange,
+		now:         time.Now,
+	}
+}
+
+// available reports whether a call would be let through, without taking
+// the probe slot
+func (b *breaker) available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitOpen:
+		return b.now().Sub(b.openedAt) >= b.openTimeout
+	case circuitHalfOpen:
+		return !b.probing
+	default:
+		return true
+	}
+}
+
+// allow returns ErrCircuitOpen if a call may not go through now. A call
+// allowed while the circuit is half open is the probe, and must be followed
+// by record.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	changed := false
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		b.state = circuitHalfOpen
+		b.probing = true
+		changed = true
+	case circuitHalfOpen:
+		if b.probing {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	b.mu.Unlock()
+
+	if changed {
+		b.onChange(circuitHalfOpen)
+	}
+	return nil
+}
+
+// record counts the outcome of an allowed call
+func (b *breaker) record(err error) {
+	failed := isBankFailure(err)
+
+	b.mu.Lock()
+	previous := b.state
+	b.probing = false
+	switch {
+	case !failed:
+		b.failures = 0
+		b.state = circuitClosed
+	case b.state == circuitHalfOpen:
+		b.state = circuitOpen
+		b.openedAt = b.now()
+	default:
+		b.failures++
+		if b.state == circuitClosed && b.failures >= b.threshold {
+			b.state = circuitOpen
+			b.openedAt = b.now()
+		}
+	}
+	state := b.state
+	b.mu.Unlock()
+
+	if state != previous {
+		b.onChange(state)
+	}
+}
+
+func (b *breaker) current() circuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// isBankFailure reports whether an error says the bank is unreachable or
+// struggling, as opposed to the bank rejecting the request itself
+func isBankFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return errors.Is(err, context.DeadlineExceeded)
+	}
+}
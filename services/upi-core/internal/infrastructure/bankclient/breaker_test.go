@@ -0,0 +1,85 @@
+package bankclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	now := time.Now()
+	var transitions []circuitState
+	b := newBreaker(1, time.Minute, func(s circuitState) { transitions = append(transitions, s) })
+	b.now = func() time.Time { return now }
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	b.allow()
+	b.record(unavailable)
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("open circuit let a call through: %v", err)
+	}
+
+	// Past the timeout exactly one probe is let through
+	now = now.Add(time.Minute)
+	if !b.available() {
+		t.Fatal("circuit not available for a probe after the timeout")
+	}
+	if err := b.allow(); err != nil {
+		t.Fatalf("probe refused: %v", err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("second call let through while probing")
+	}
+
+	// A failed probe opens the circuit for another timeout
+	b.record(unavailable)
+	if b.available() {
+		t.Fatal("circuit available right after a failed probe")
+	}
+	now = now.Add(time.Minute)
+	b.allow()
+	b.record(nil)
+	if b.current() != circuitClosed {
+		t.Errorf("state after a successful probe = %s", b.current())
+	}
+
+	want := []circuitState{circuitOpen, circuitHalfOpen, circuitOpen, circuitHalfOpen, circuitClosed}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", transitions, want)
+		}
+	}
+}
+
+func TestBreakerIgnoresRejections(t *testing.T) {
+	b := newBreaker(2, time.Minute, func(circuitState) {})
+	for _, err := range []error{
+		status.Error(codes.FailedPrecondition, "insufficient funds"),
+		status.Error(codes.InvalidArgument, "unknown account"),
+		context.Canceled,
+	} {
+		b.allow()
+		b.record(err)
+	}
+	if b.current() != circuitClosed {
+		t.Errorf("rejections opened the circuit")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := retryPolicy{attempts: 5, baseDelay: 100 * time.Millisecond, maxDelay: time.Second}
+	for n, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 6: time.Second} {
+		for i := 0; i < 20; i++ {
+			if d := p.backoff(n); d < max/2 || d > max {
+				t.Errorf("backoff(%d) = %s, want within [%s, %s]", n, d, max/2, max)
+			}
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package bankclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/service"
+	pb "upi-core/pkg/pb"
+	bankpb "upi-core/pkg/pb/bank"
+)
+
+// pool is a fixed set of connections to one bank endpoint, used in turn
+type pool struct {
+	endpoint string
+	conns    []*grpc.ClientConn
+	clients  []bankpb.BankSimulatorClient
+	next     atomic.Uint32
+}
+
+func dialPool(endpoint string, size int, opts ...grpc.DialOption) (*pool, error) {
+	p := &pool{endpoint: endpoint}
+	for i := 0; i < size; i++ {
+		conn, err := grpc.Dial(target(endpoint), opts...)
+		if err != nil {
+			p.close()
+			return nil, fmt.Errorf("failed to dial %s: %w", endpoint, err)
+		}
+		p.conns = append(p.conns, conn)
+		p.clients = append(p.clients, bankpb.NewBankSimulatorClient(conn))
+	}
+	return p, nil
+}
+
+// client returns the next connection's client in round-robin order
+func (p *pool) client() bankpb.BankSimulatorClient {
+	return p.clients[int(p.next.Add(1)-1)%len(p.clients)]
+}
+
+func (p *pool) close() {
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+}
+
+// target turns a bank's registered endpoint into a gRPC dial target. Banks
+// register either host:port or a URL such as grpc://bank.example:50050.
+func target(endpoint string) string {
+	for _, scheme := range []string{"grpc://", "grpcs://", "http://", "https://"} {
+		if strings.HasPrefix(endpoint, scheme) {
+			return strings.TrimSuffix(strings.TrimPrefix(endpoint, scheme), "/")
+		}
+	}
+	return endpoint
+}
+
+// client talks to one bank over its connection pool
+type client struct {
+	bankCode string
+	pool     *pool
+	timeout  time.Duration
+	signer   *crypto.Signer // Signs transaction requests; nil sends them unsigned
+	breaker  *breaker
+	retry    retryPolicy
+	metrics  *metrics
+}
+
+// call runs fn, with the request timeout, through the bank's circuit
+// breaker, retrying the failures retryable accepts
+func (c *client) call(ctx context.Context, method string, retryable func(error) bool, fn func(ctx context.Context) error) error {
+	return c.retry.do(ctx, retryable, func() { c.metrics.retry(ctx, c.bankCode, method) }, func() error {
+		err := c.breaker.allow()
+		if err == nil {
+			callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			err = fn(callCtx)
+			cancel()
+			c.breaker.record(err)
+		}
+		c.metrics.call(ctx, c.bankCode, method, err)
+		return err
+	})
+}
+
+// ProcessTransaction debits or credits an account at the bank
+func (c *client) ProcessTransaction(ctx context.Context, req *service.BankTransactionRequest) (*service.BankTransactionResponse, error) {
+	txnType := bankpb.TransactionType_TRANSACTION_TYPE_DEBIT
+	if req.Type == "CREDIT" {
+		txnType = bankpb.TransactionType_TRANSACTION_TYPE_CREDIT
+	}
+	bankReq := &bankpb.TransactionRequest{
+		TransactionId: req.TransactionID,
+		BankCode:      req.BankCode,
+		AccountNumber: req.AccountNumber,
+		AmountPaisa:   req.AmountPaisa,
+		Type:          txnType,
+		Reference:     req.Reference,
+		Description:   req.Description,
+		InitiatedAt:   timestamppb.New(req.InitiatedAt),
+	}
+	if c.signer != nil {
+		signature, err := c.signer.Sign(SigningPayload(bankReq))
+		if err != nil {
+			return nil, err
+		}
+		bankReq.Metadata = map[string]string{"signature": signature}
+	}
+
+	var resp *bankpb.TransactionResponse
+	err := c.call(ctx, "ProcessTransaction", retryableWrite, func(ctx context.Context) error {
+		var err error
+		resp, err = c.pool.client().ProcessTransaction(ctx, bankReq)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &service.BankTransactionResponse{
+		TransactionID:       resp.TransactionId,
+		BankReferenceID:     resp.BankReferenceId,
+		Status:              strings.TrimPrefix(resp.Status.String(), "TRANSACTION_STATUS_"),
+		AccountBalancePaisa: resp.AccountBalancePaisa,
+		ErrorCode:           resp.ErrorCode,
+		ErrorMessage:        resp.ErrorMessage,
+		ProcessedAt:         resp.ProcessedAt.AsTime(),
+	}
+	if resp.Fees != nil {
+		response.Fees = &pb.TransactionFees{
+			BankFeePaisa:  resp.Fees.TotalFeePaisa,
+			TotalFeePaisa: resp.Fees.TotalFeePaisa,
+		}
+	}
+	return response, nil
+}
+
+// SigningPayload is what the switch signs for a request to a bank: its
+// fields joined by "|", the type as its enum name and the initiation time in
+// RFC 3339 UTC. The signature goes in the request's metadata as "signature".
+func SigningPayload(req *bankpb.TransactionRequest) []byte {
+	return []byte(strings.Join([]string{
+		req.TransactionId,
+		req.BankCode,
+		req.AccountNumber,
+		strconv.FormatInt(req.AmountPaisa, 10),
+		req.Type.String(),
+		req.Reference,
+		req.Description,
+		req.InitiatedAt.AsTime().UTC().Format(time.RFC3339Nano),
+	}, "|"))
+}
+
+// GetAccountBalance returns an account's available balance
+func (c *client) GetAccountBalance(ctx context.Context, bankCode, accountNumber string) (int64, error) {
+	var resp *bankpb.AccountBalanceResponse
+	err := c.call(ctx, "GetAccountBalance", retryableRead, func(ctx context.Context) error {
+		var err error
+		resp, err = c.pool.client().GetAccountBalance(ctx, &bankpb.AccountBalanceRequest{
+			BankCode:      bankCode,
+			AccountNumber: accountNumber,
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.AvailableBalancePaisa, nil
+}
+
+// CheckAccountStatus returns an account's status, e.g. ACTIVE or FROZEN
+func (c *client) CheckAccountStatus(ctx context.Context, bankCode, accountNumber string) (string, error) {
+	var resp *bankpb.AccountDetailsResponse
+	err := c.call(ctx, "CheckAccountStatus", retryableRead, func(ctx context.Context) error {
+		var err error
+		resp, err = c.pool.client().GetAccountDetails(ctx, &bankpb.AccountDetailsRequest{
+			BankCode:      bankCode,
+			AccountNumber: accountNumber,
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(resp.Status.String(), "ACCOUNT_STATUS_"), nil
+}
+
+// GetTransactionStatus returns the bank's record of a debit or credit made
+// under a transaction ID
+func (c *client) GetTransactionStatus(ctx context.Context, bankCode, transactionID string) (*service.BankTransactionStatus, error) {
+	var resp *bankpb.TransactionStatusResponse
+	err := c.call(ctx, "GetTransactionStatus", retryableRead, func(ctx context.Context) error {
+		var err error
+		resp, err = c.pool.client().GetTransactionStatus(ctx, &bankpb.TransactionStatusRequest{
+			TransactionId: transactionID,
+			BankCode:      bankCode,
+		})
+		return err
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("%w: %s at %s", service.ErrBankTransactionNotFound, transactionID, bankCode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &service.BankTransactionStatus{
+		TransactionID:   resp.TransactionId,
+		BankReferenceID: resp.BankReferenceId,
+		Status:          strings.TrimPrefix(resp.Status.String(), "TRANSACTION_STATUS_"),
+		AmountPaisa:     resp.AmountPaisa,
+		ProcessedAt:     resp.ProcessedAt.AsTime(),
+	}, nil
+}
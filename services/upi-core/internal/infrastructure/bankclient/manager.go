@@ -0,0 +1,448 @@
+// Package bankclient connects UPI Core to the member banks registered in
+// the banks table, and keeps those connections in line with the table and
+// with each bank's health.
+package bankclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+	bankpb "upi-core/pkg/pb/bank"
+)
+
+// Store is the part of the repository the manager reads banks from and
+// records their heartbeats and circuit breaker states in
+type Store interface {
+	GetBankByCode(ctx context.Context, bankCode string) (*repository.Bank, error)
+	ListActiveBanks(ctx context.Context) ([]*repository.Bank, error)
+	UpdateBankHealth(ctx context.Context, tx *sql.Tx, bankCode string, successRate int, avgResponseTime int) error
+	UpdateBankCircuitState(ctx context.Context, bankCode string, state string) error
+}
+
+// bankState is a registered bank's connections and health
+type bankState struct {
+	pool     *pool
+	breaker  *breaker
+	failures int // Consecutive failed health checks
+	healthy  bool
+}
+
+// Manager dials every active bank, routes calls over a pool of connections
+// per bank, and stops routing to banks that fail their health checks until
+// they recover. Calls failing with transient errors are retried, and a
+// circuit breaker per bank stops calls to a bank that keeps failing them.
+type Manager struct {
+	store    Store
+	cfg      config.BanksConfig
+	signer   *crypto.Signer
+	tls      *TLSCredentials
+	logger   *logrus.Logger
+	dialOpts []grpc.DialOption
+	metrics  *metrics
+
+	mu    sync.RWMutex
+	banks map[string]*bankState
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+var _ service.BankClients = (*Manager)(nil)
+
+// NewManager creates a manager with no banks; Refresh or Start loads them.
+// Transaction requests are signed with signer, if not nil. Banks are dialed
+// over mutual TLS with tlsCreds, if not nil, and in plaintext otherwise.
+func NewManager(store Store, cfg config.BanksConfig, signer *crypto.Signer, tlsCreds *TLSCredentials, logger *logrus.Logger, dialOpts ...grpc.DialOption) *Manager {
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = 1
+	}
+	if cfg.UnhealthyThreshold <= 0 {
+		cfg.UnhealthyThreshold = 1
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 10 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.RetryMaxDelay < cfg.RetryBaseDelay {
+		cfg.RetryMaxDelay = cfg.RetryBaseDelay
+	}
+	if cfg.BreakerThreshold <= 0 {
+		cfg.BreakerThreshold = 5
+	}
+	if cfg.BreakerOpenTimeout <= 0 {
+		cfg.BreakerOpenTimeout = 30 * time.Second
+	}
+	if len(dialOpts) == 0 && tlsCreds == nil {
+		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	m := &Manager{
+		store:    store,
+		cfg:      cfg,
+		signer:   signer,
+		tls:      tlsCreds,
+		logger:   logger,
+		dialOpts: dialOpts,
+		banks:    make(map[string]*bankState),
+	}
+	m.metrics = newMetrics(m)
+	return m
+}
+
+// Client returns the client for a bank, or an error if the bank is not
+// registered and active, is failing its health checks or has its circuit
+// breaker open
+func (m *Manager) Client(bankCode string) (service.BankClient, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	state, ok := m.banks[bankCode]
+	if !ok {
+		return nil, fmt.Errorf("bank client not found for bank: %s", bankCode)
+	}
+	if !state.healthy {
+		return nil, fmt.Errorf("bank %s is unavailable: failing health checks", bankCode)
+	}
+	if !state.breaker.available() {
+		return nil, fmt.Errorf("bank %s is unavailable: %w", bankCode, ErrCircuitOpen)
+	}
+	return &client{
+		bankCode: bankCode,
+		pool:     state.pool,
+		timeout:  m.cfg.RequestTimeout,
+		signer:   m.signer,
+		breaker:  state.breaker,
+		retry: retryPolicy{
+			attempts:  m.cfg.MaxAttempts,
+			baseDelay: m.cfg.RetryBaseDelay,
+			maxDelay:  m.cfg.RetryMaxDelay,
+		},
+		metrics: m.metrics,
+	}, nil
+}
+
+// newBreaker creates a bank's circuit breaker, which logs, counts and
+// records in the store each of its transitions
+func (m *Manager) newBreaker(bankCode string) *breaker {
+	return newBreaker(m.cfg.BreakerThreshold, m.cfg.BreakerOpenTimeout, func(state circuitState) {
+		logger := m.logger.WithFields(logrus.Fields{
+			"bank_code": bankCode,
+			"circuit":   state,
+		})
+		if state == circuitOpen {
+			logger.Warn("Bank circuit breaker opened, failing calls to it fast")
+		} else {
+			logger.Info("Bank circuit breaker state changed")
+		}
+		m.metrics.transition(bankCode, state)
+
+		ctx, cancel := context.WithTimeout(context.Background(), m.cfg.RequestTimeout)
+		defer cancel()
+		if err := m.store.UpdateBankCircuitState(ctx, bankCode, state.String()); err != nil {
+			logger.WithError(err).Warn("Failed to record bank circuit state")
+		}
+	})
+}
+
+// circuitStates returns the circuit breaker state of every connected bank
+func (m *Manager) circuitStates() map[string]circuitState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	states := make(map[string]circuitState, len(m.banks))
+	for code, state := range m.banks {
+		states[code] = state.breaker.current()
+	}
+	return states
+}
+
+// Refresh reconciles the connections with every active bank in the store:
+// new banks are dialed, changed endpoints redialed, and banks that are gone
+// or no longer active dropped.
+func (m *Manager) Refresh(ctx context.Context) error {
+	banks, err := m.store.ListActiveBanks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list active banks: %w", err)
+	}
+	active := make(map[string]bool, len(banks))
+	for _, bank := range banks {
+		active[bank.BankCode] = true
+		if err := m.apply(bank); err != nil {
+			m.logger.WithError(err).WithField("bank_code", bank.BankCode).Error("Failed to connect to bank")
+		}
+	}
+
+	m.mu.Lock()
+	for code, state := range m.banks {
+		if !active[code] {
+			delete(m.banks, code)
+			m.retire(code, state.pool)
+		}
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// Reload rereads one bank from the store, for when its registration or
+// status has just changed
+func (m *Manager) Reload(ctx context.Context, bankCode string) error {
+	bank, err := m.store.GetBankByCode(ctx, bankCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		bank, err = &repository.Bank{BankCode: bankCode}, nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load bank %s: %w", bankCode, err)
+	}
+	return m.apply(bank)
+}
+
+// apply brings one bank's connections in line with its registration
+func (m *Manager) apply(bank *repository.Bank) error {
+	m.mu.Lock()
+	state, ok := m.banks[bank.BankCode]
+	if bank.Status != "ACTIVE" || bank.EndpointURL == "" {
+		if ok {
+			delete(m.banks, bank.BankCode)
+			m.retire(bank.BankCode, state.pool)
+		}
+		m.mu.Unlock()
+		return nil
+	}
+	if ok && state.pool.endpoint == bank.EndpointURL {
+		m.mu.Unlock()
+		return nil
+	}
+	m.mu.Unlock()
+
+	// Dial outside the lock; grpc.Dial does not block, but stays cheap to
+	// retry if two reloads race
+	p, err := dialPool(bank.EndpointURL, m.cfg.PoolSize, m.dialOptions(bank.BankCode)...)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// A new endpoint is the same bank, so its circuit breaker carries over
+	b := m.newBreaker(bank.BankCode)
+	if current, ok := m.banks[bank.BankCode]; ok {
+		if current.pool.endpoint == bank.EndpointURL {
+			p.close()
+			return nil
+		}
+		m.retire(bank.BankCode, current.pool)
+		b = current.breaker
+	}
+	// A bank is routable as soon as it is registered; the health checks
+	// take it out if it does not answer
+	m.banks[bank.BankCode] = &bankState{pool: p, breaker: b, healthy: true}
+	m.logger.WithFields(logrus.Fields{
+		"bank_code": bank.BankCode,
+		"endpoint":  bank.EndpointURL,
+		"pool_size": m.cfg.PoolSize,
+	}).Info("Connected to bank")
+	return nil
+}
+
+// dialOptions returns the options connections to a bank are dialed with
+func (m *Manager) dialOptions(bankCode string) []grpc.DialOption {
+	if m.tls == nil {
+		return m.dialOpts
+	}
+	opts := append([]grpc.DialOption{}, m.dialOpts...)
+	return append(opts, grpc.WithTransportCredentials(m.tls.forBank(bankCode)))
+}
+
+// reloadTLS rereads the TLS certificates and, if they changed, redials
+// every bank so that no connection stays on the ones replaced
+func (m *Manager) reloadTLS() {
+	if m.tls == nil {
+		return
+	}
+	changed, err := m.tls.Reload()
+	if err != nil {
+		m.logger.WithError(err).Error("Failed to reload bank TLS certificates, keeping the current ones")
+		return
+	}
+	if !changed {
+		return
+	}
+	m.logger.Info("Bank TLS certificates changed, reconnecting to banks")
+	m.redial()
+}
+
+// redial replaces the connection pool of every bank, keeping its health
+// and circuit breaker
+func (m *Manager) redial() {
+	m.mu.RLock()
+	endpoints := make(map[string]string, len(m.banks))
+	for code, state := range m.banks {
+		endpoints[code] = state.pool.endpoint
+	}
+	m.mu.RUnlock()
+
+	for code, endpoint := range endpoints {
+		p, err := dialPool(endpoint, m.cfg.PoolSize, m.dialOptions(code)...)
+		if err != nil {
+			m.logger.WithError(err).WithField("bank_code", code).Error("Failed to reconnect to bank")
+			continue
+		}
+		m.mu.Lock()
+		state, ok := m.banks[code]
+		if !ok || state.pool.endpoint != endpoint {
+			// Reloaded meanwhile, which dialed with the new certificates
+			m.mu.Unlock()
+			p.close()
+			continue
+		}
+		m.retire(code, state.pool)
+		state.pool = p
+		m.mu.Unlock()
+	}
+}
+
+// retire closes a pool once calls already using it have had time to
+// finish. The caller holds m.mu.
+func (m *Manager) retire(bankCode string, p *pool) {
+	m.logger.WithFields(logrus.Fields{
+		"bank_code": bankCode,
+		"endpoint":  p.endpoint,
+	}).Info("Disconnecting from bank")
+	time.AfterFunc(m.cfg.RequestTimeout, p.close)
+}
+
+// CheckHealth asks every connected bank for its health. A bank that fails
+// UnhealthyThreshold checks in a row stops receiving calls; one success
+// brings it back. Healthy banks have their heartbeat recorded in the store.
+func (m *Manager) CheckHealth(ctx context.Context) {
+	m.mu.RLock()
+	pools := make(map[string]*pool, len(m.banks))
+	for code, state := range m.banks {
+		pools[code] = state.pool
+	}
+	m.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for code, p := range pools {
+		wg.Add(1)
+		go func(code string, p *pool) {
+			defer wg.Done()
+			m.checkBank(ctx, code, p)
+		}(code, p)
+	}
+	wg.Wait()
+}
+
+func (m *Manager) checkBank(ctx context.Context, bankCode string, p *pool) {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.RequestTimeout)
+	defer cancel()
+	resp, err := p.client().CheckBankHealth(ctx, &bankpb.BankHealthRequest{BankCode: bankCode})
+	if err == nil {
+		switch resp.HealthStatus {
+		case bankpb.HealthStatus_HEALTH_STATUS_HEALTHY, bankpb.HealthStatus_HEALTH_STATUS_DEGRADED:
+		default:
+			err = fmt.Errorf("bank reports %s", resp.HealthStatus)
+		}
+	}
+
+	m.mu.Lock()
+	state, ok := m.banks[bankCode]
+	if !ok || state.pool != p {
+		// Reloaded while the check was in flight
+		m.mu.Unlock()
+		return
+	}
+	logger := m.logger.WithField("bank_code", bankCode)
+	if err != nil {
+		state.failures++
+		if state.healthy && state.failures >= m.cfg.UnhealthyThreshold {
+			state.healthy = false
+			logger.WithError(err).Warn("Bank failed its health checks, no longer routing to it")
+		}
+	} else {
+		if !state.healthy {
+			logger.Info("Bank recovered, routing to it again")
+		}
+		state.failures = 0
+		state.healthy = true
+	}
+	m.mu.Unlock()
+
+	if err == nil {
+		if err := m.store.UpdateBankHealth(ctx, nil, bankCode, int(resp.SuccessRatePercent), int(resp.AvgResponseTimeMs)); err != nil {
+			logger.WithError(err).Warn("Failed to record bank heartbeat")
+		}
+	}
+}
+
+// Start loads the banks and keeps checking their health and reloading
+// them, and the TLS certificates, until Close
+func (m *Manager) Start(ctx context.Context) error {
+	if err := m.Refresh(ctx); err != nil {
+		return err
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.run()
+	return nil
+}
+
+func (m *Manager) run() {
+	defer close(m.done)
+	health := time.NewTicker(interval(m.cfg.HealthCheckInterval, 10*time.Second))
+	defer health.Stop()
+	refresh := time.NewTicker(interval(m.cfg.RefreshInterval, time.Minute))
+	defer refresh.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-m.stop
+		cancel()
+	}()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-health.C:
+			m.CheckHealth(ctx)
+		case <-refresh.C:
+			m.reloadTLS()
+			if err := m.Refresh(ctx); err != nil {
+				m.logger.WithError(err).Error("Failed to refresh banks")
+			}
+		}
+	}
+}
+
+func interval(d, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// Close stops the background checks and closes every connection
+func (m *Manager) Close() {
+	if m.stop != nil {
+		close(m.stop)
+		<-m.done
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for code, state := range m.banks {
+		state.pool.close()
+		delete(m.banks, code)
+	}
+}
@@ -0,0 +1,387 @@
+package bankclient
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+	bankpb "upi-core/pkg/pb/bank"
+)
+
+// fakeBank answers transactions with its name as the reference, and health
+// checks as told. While unavailable is positive, transactions fail with
+// Unavailable, each taking one off.
+type fakeBank struct {
+	bankpb.UnimplementedBankSimulatorServer
+	name        string
+	healthy     atomic.Bool
+	unavailable atomic.Int32
+	calls       atomic.Int32
+	last        atomic.Pointer[bankpb.TransactionRequest]
+}
+
+func (b *fakeBank) ProcessTransaction(ctx context.Context, req *bankpb.TransactionRequest) (*bankpb.TransactionResponse, error) {
+	b.calls.Add(1)
+	if b.unavailable.Add(-1) >= 0 {
+		return nil, status.Error(codes.Unavailable, "bank down")
+	}
+	b.unavailable.Store(0)
+	b.last.Store(req)
+	return &bankpb.TransactionResponse{
+		TransactionId:       req.TransactionId,
+		Status:              bankpb.TransactionStatus_TRANSACTION_STATUS_SUCCESS,
+		BankReferenceId:     b.name + "-" + req.Type.String(),
+		AccountBalancePaisa: 1000,
+	}, nil
+}
+
+func (b *fakeBank) CheckBankHealth(ctx context.Context, req *bankpb.BankHealthRequest) (*bankpb.BankHealthResponse, error) {
+	status := bankpb.HealthStatus_HEALTH_STATUS_UNHEALTHY
+	if b.healthy.Load() {
+		status = bankpb.HealthStatus_HEALTH_STATUS_HEALTHY
+	}
+	return &bankpb.BankHealthResponse{BankCode: req.BankCode, HealthStatus: status, SuccessRatePercent: 99, AvgResponseTimeMs: 12}, nil
+}
+
+func startBank(t *testing.T, name string) (*fakeBank, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank := &fakeBank{name: name}
+	bank.healthy.Store(true)
+	srv := grpc.NewServer()
+	bankpb.RegisterBankSimulatorServer(srv, bank)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return bank, lis.Addr().String()
+}
+
+// fakeStore is a banks table in memory
+type fakeStore struct {
+	mu         sync.Mutex
+	banks      map[string]*repository.Bank
+	heartbeats map[string]int
+	circuits   []string
+}
+
+func newFakeStore(banks ...*repository.Bank) *fakeStore {
+	s := &fakeStore{banks: make(map[string]*repository.Bank), heartbeats: make(map[string]int)}
+	for _, b := range banks {
+		s.put(b)
+	}
+	return s
+}
+
+func (s *fakeStore) put(b *repository.Bank) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *b
+	s.banks[b.BankCode] = &copied
+}
+
+func (s *fakeStore) GetBankByCode(ctx context.Context, bankCode string) (*repository.Bank, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.banks[bankCode]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *b
+	return &copied, nil
+}
+
+func (s *fakeStore) ListActiveBanks(ctx context.Context) ([]*repository.Bank, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var banks []*repository.Bank
+	for _, b := range s.banks {
+		if b.Status == "ACTIVE" {
+			copied := *b
+			banks = append(banks, &copied)
+		}
+	}
+	return banks, nil
+}
+
+func (s *fakeStore) UpdateBankHealth(ctx context.Context, tx *sql.Tx, bankCode string, successRate int, avgResponseTime int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heartbeats[bankCode]++
+	return nil
+}
+
+// UpdateBankCircuitState records each transition as "BANK:STATE"
+func (s *fakeStore) UpdateBankCircuitState(ctx context.Context, bankCode string, state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.circuits = append(s.circuits, bankCode+":"+state)
+	return nil
+}
+
+func newManager(t *testing.T, store Store, signer *crypto.Signer) *Manager {
+	return newManagerWith(t, store, signer, config.BanksConfig{PoolSize: 2, RequestTimeout: time.Second, UnhealthyThreshold: 2})
+}
+
+func newManagerWith(t *testing.T, store Store, signer *crypto.Signer, cfg config.BanksConfig) *Manager {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	m := NewManager(store, cfg, signer, nil, logger)
+	t.Cleanup(m.Close)
+	return m
+}
+
+// debit sends a debit through the manager's client for bankCode and returns
+// the reference the bank answered with
+func debit(t *testing.T, m *Manager, bankCode string) (string, error) {
+	t.Helper()
+	c, err := m.Client(bankCode)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.ProcessTransaction(context.Background(), &service.BankTransactionRequest{
+		TransactionID: "TXN1",
+		BankCode:      bankCode,
+		AccountNumber: "1234567890",
+		AmountPaisa:   100,
+		Type:          "DEBIT",
+		InitiatedAt:   time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Status != "SUCCESS" {
+		t.Fatalf("status = %s", resp.Status)
+	}
+	return resp.BankReferenceID, nil
+}
+
+func TestManagerRoutesToRegisteredBanks(t *testing.T) {
+	_, hdfc := startBank(t, "hdfc")
+	_, sbi := startBank(t, "sbi")
+	store := newFakeStore(
+		&repository.Bank{BankCode: "HDFC", EndpointURL: hdfc, Status: "ACTIVE"},
+		&repository.Bank{BankCode: "SBI", EndpointURL: "grpc://" + sbi, Status: "ACTIVE"},
+		&repository.Bank{BankCode: "AXIS", EndpointURL: sbi, Status: "MAINTENANCE"},
+	)
+	m := newManager(t, store, nil)
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for code, want := range map[string]string{"HDFC": "hdfc-TRANSACTION_TYPE_DEBIT", "SBI": "sbi-TRANSACTION_TYPE_DEBIT"} {
+		if ref, err := debit(t, m, code); err != nil || ref != want {
+			t.Errorf("%s: ref = %q, err = %v", code, ref, err)
+		}
+	}
+	if _, err := m.Client("AXIS"); err == nil {
+		t.Error("inactive bank has a client")
+	}
+	if _, err := m.Client("ICICI"); err == nil {
+		t.Error("unregistered bank has a client")
+	}
+}
+
+func TestManagerReloadsChangedRegistrations(t *testing.T) {
+	_, first := startBank(t, "first")
+	_, second := startBank(t, "second")
+	store := newFakeStore(&repository.Bank{BankCode: "HDFC", EndpointURL: first, Status: "ACTIVE"})
+	m := newManager(t, store, nil)
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new endpoint is picked up on reload
+	store.put(&repository.Bank{BankCode: "HDFC", EndpointURL: second, Status: "ACTIVE"})
+	if err := m.Reload(context.Background(), "HDFC"); err != nil {
+		t.Fatal(err)
+	}
+	if ref, err := debit(t, m, "HDFC"); err != nil || ref != "second-TRANSACTION_TYPE_DEBIT" {
+		t.Errorf("after endpoint change: ref = %q, err = %v", ref, err)
+	}
+
+	// Suspending the bank stops routing to it
+	store.put(&repository.Bank{BankCode: "HDFC", EndpointURL: second, Status: "SUSPENDED"})
+	if err := m.Reload(context.Background(), "HDFC"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Client("HDFC"); err == nil {
+		t.Error("suspended bank still has a client")
+	}
+
+	// A bank registered after start is picked up by the next refresh
+	store.put(&repository.Bank{BankCode: "SBI", EndpointURL: first, Status: "ACTIVE"})
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := debit(t, m, "SBI"); err != nil {
+		t.Errorf("newly registered bank: %v", err)
+	}
+}
+
+func TestManagerStopsRoutingToUnhealthyBanks(t *testing.T) {
+	bank, addr := startBank(t, "hdfc")
+	store := newFakeStore(&repository.Bank{BankCode: "HDFC", EndpointURL: addr, Status: "ACTIVE"})
+	m := newManager(t, store, nil)
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	m.CheckHealth(context.Background())
+	if store.heartbeats["HDFC"] != 1 {
+		t.Errorf("heartbeats = %d, want 1", store.heartbeats["HDFC"])
+	}
+
+	// One failed check is tolerated, the second takes the bank out
+	bank.healthy.Store(false)
+	m.CheckHealth(context.Background())
+	if _, err := m.Client("HDFC"); err != nil {
+		t.Fatalf("bank taken out after one failed check: %v", err)
+	}
+	m.CheckHealth(context.Background())
+	if _, err := m.Client("HDFC"); err == nil {
+		t.Fatal("bank still routable after two failed checks")
+	}
+
+	// One passing check brings it back
+	bank.healthy.Store(true)
+	m.CheckHealth(context.Background())
+	if _, err := debit(t, m, "HDFC"); err != nil {
+		t.Errorf("recovered bank: %v", err)
+	}
+	if store.heartbeats["HDFC"] != 2 {
+		t.Errorf("heartbeats = %d, want 2", store.heartbeats["HDFC"])
+	}
+}
+
+func TestManagerRetriesUnavailableBanks(t *testing.T) {
+	bank, addr := startBank(t, "hdfc")
+	m := newManagerWith(t, newFakeStore(&repository.Bank{BankCode: "HDFC", EndpointURL: addr, Status: "ACTIVE"}), nil, config.BanksConfig{
+		RequestTimeout: time.Second,
+		MaxAttempts:    3,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  5 * time.Millisecond,
+	})
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	bank.unavailable.Store(2)
+	if _, err := debit(t, m, "HDFC"); err != nil {
+		t.Fatalf("debit failed despite retries: %v", err)
+	}
+	if calls := bank.calls.Load(); calls != 3 {
+		t.Errorf("bank called %d times, want 3", calls)
+	}
+
+	bank.calls.Store(0)
+	bank.unavailable.Store(5)
+	if _, err := debit(t, m, "HDFC"); status.Code(err) != codes.Unavailable {
+		t.Errorf("err = %v, want Unavailable once attempts run out", err)
+	}
+	if calls := bank.calls.Load(); calls != 3 {
+		t.Errorf("bank called %d times, want 3", calls)
+	}
+}
+
+func TestManagerBreaksCircuitToFailingBank(t *testing.T) {
+	bank, addr := startBank(t, "hdfc")
+	store := newFakeStore(&repository.Bank{BankCode: "HDFC", EndpointURL: addr, Status: "ACTIVE"})
+	m := newManagerWith(t, store, nil, config.BanksConfig{
+		RequestTimeout:     time.Second,
+		BreakerThreshold:   2,
+		BreakerOpenTimeout: 50 * time.Millisecond,
+	})
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	bank.unavailable.Store(100)
+	for i := 0; i < 2; i++ {
+		if _, err := debit(t, m, "HDFC"); err == nil {
+			t.Fatal("debit succeeded at a failing bank")
+		}
+	}
+	if _, err := m.Client("HDFC"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen after two failures", err)
+	}
+	if calls := bank.calls.Load(); calls != 2 {
+		t.Errorf("bank called %d times, want 2", calls)
+	}
+
+	// Once the open timeout passes a probe goes through and closes the
+	// circuit
+	bank.unavailable.Store(0)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := debit(t, m, "HDFC"); err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if _, err := debit(t, m, "HDFC"); err != nil {
+		t.Errorf("circuit not closed after a successful probe: %v", err)
+	}
+	if got := fmt.Sprint(store.circuits); got != "[HDFC:OPEN HDFC:HALF_OPEN HDFC:CLOSED]" {
+		t.Errorf("recorded circuit states = %s", got)
+	}
+}
+
+func TestManagerSignsTransactionRequests(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	signer, err := crypto.ParseSigner(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, _ := x509.MarshalPKIXPublicKey(pub)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+
+	bank, addr := startBank(t, "hdfc")
+	m := newManager(t, newFakeStore(&repository.Bank{BankCode: "HDFC", EndpointURL: addr, Status: "ACTIVE"}), signer)
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := debit(t, m, "HDFC"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := bank.last.Load()
+	if err := crypto.Verify(publicKey, SigningPayload(req), req.Metadata["signature"]); err != nil {
+		t.Errorf("bank received a request whose signature does not verify: %v", err)
+	}
+}
+
+func TestTarget(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"bank-simulator:50050":        "bank-simulator:50050",
+		"grpc://bank-simulator:50050": "bank-simulator:50050",
+		"https://api.hdfc.com:443/":   "api.hdfc.com:443",
+	} {
+		if got := target(endpoint); got != want {
+			t.Errorf("target(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package bankclient
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// metrics are the bank call instruments, exported to Prometheus by the
+// meter provider telemetry.Init installs:
+//
+//	bank_client_calls_total{bank_code,method,outcome}
+//	bank_client_retries_total{bank_code,method}
+//	bank_client_circuit_transitions_total{bank_code,state}
+//	bank_client_circuit_state{bank_code} (0 closed, 1 open, 2 half open)
+type metrics struct {
+	calls       metric.Int64Counter
+	retries     metric.Int64Counter
+	transitions metric.Int64Counter
+}
+
+func newMetrics(m *Manager) *metrics {
+	meter := otel.Meter("upi-core/bankclient")
+	// Instrument errors only come from invalid names, so the no-op
+	// instruments returned alongside them are used as they are
+	calls, _ := meter.Int64Counter("bank_client.calls", metric.WithDescription("Calls to member banks by outcome"))
+	retries, _ := meter.Int64Counter("bank_client.retries", metric.WithDescription("Retried calls to member banks"))
+	transitions, _ := meter.Int64Counter("bank_client.circuit_transitions", metric.WithDescription("Circuit breaker state changes per bank"))
+	meter.Int64ObservableGauge("bank_client.circuit_state",
+		metric.WithDescription("Circuit breaker state per bank: 0 closed, 1 open, 2 half open"),
+		metric.WithInt64Callback(func(ctx context.Context, o metric.Int64Observer) error {
+			for code, state := range m.circuitStates() {
+				o.Observe(int64(state), metric.WithAttributes(attribute.String("bank_code", code)))
+			}
+			return nil
+		}),
+	)
+	return &metrics{calls: calls, retries: retries, transitions: transitions}
+}
+
+func (m *metrics) call(ctx context.Context, bankCode, method string, err error) {
+	outcome := "success"
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		outcome = "circuit_open"
+	case isBankFailure(err):
+		outcome = "failure"
+	case err != nil:
+		outcome = "rejected"
+	}
+	m.calls.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("bank_code", bankCode),
+		attribute.String("method", method),
+		attribute.String("outcome", outcome),
+	))
+}
+
+func (m *metrics) retry(ctx context.Context, bankCode, method string) {
+	m.retries.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("bank_code", bankCode),
+		attribute.String("method", method),
+	))
+}
+
+func (m *metrics) transition(bankCode string, state circuitState) {
+	m.transitions.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("bank_code", bankCode),
+		attribute.String("state", state.String()),
+	))
+}
@@ -0,0 +1,72 @@
+package bankclient
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// retryPolicy retries calls that failed with a transient error, backing off
+// exponentially with jitter
+type retryPolicy struct {
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// backoff returns the wait before retry n (from 1): baseDelay doubled n-1
+// times, capped at maxDelay, of which a random half is waited
+func (p retryPolicy) backoff(n int) time.Duration {
+	delay := p.baseDelay
+	for i := 1; i < n && delay < p.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// do calls fn until it succeeds, fails with an error retryable does not
+// accept, or runs out of attempts or ctx. onRetry is called before every
+// retry.
+func (p retryPolicy) do(ctx context.Context, retryable func(error) bool, onRetry func(), fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || attempt >= p.attempts || !retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		onRetry()
+	}
+}
+
+// retryableWrite accepts the errors a transaction request can safely be
+// sent again after: the bank was not reached, or turned the request away
+// unprocessed
+func retryableWrite(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryableRead also retries reads that timed out
+func retryableRead(err error) bool {
+	return retryableWrite(err) || status.Code(err) == codes.DeadlineExceeded
+}
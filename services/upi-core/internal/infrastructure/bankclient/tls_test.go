@@ -0,0 +1,223 @@
+package bankclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"upi-core/internal/config"
+	"upi-core/internal/domain/repository"
+	bankpb "upi-core/pkg/pb/bank"
+)
+
+// testCA issues certificates for the TLS tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for name, valid for 127.0.0.1
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startTLSBank serves a fake bank that requires a client certificate issued
+// by ca, recording the name of the last one presented
+func startTLSBank(t *testing.T, ca *testCA) (string, *atomic.Value) {
+	t.Helper()
+	certPEM, keyPEM := ca.issue(t, "bank", x509.ExtKeyUsageServerAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	var client atomic.Value
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			client.Store(cs.PeerCertificates[0].Subject.CommonName)
+			return nil
+		},
+	})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank := &fakeBank{name: "HDFC"}
+	bank.healthy.Store(true)
+	srv := grpc.NewServer(grpc.Creds(creds))
+	bankpb.RegisterBankSimulatorServer(srv, bank)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String(), &client
+}
+
+func TestManagerRotatesClientCertificates(t *testing.T) {
+	ca := newTestCA(t)
+	addr, presented := startTLSBank(t, ca)
+
+	dir := t.TempDir()
+	cfg := config.BankTLSConfig{
+		Enabled: true,
+		CAFile:  filepath.Join(dir, "ca.pem"),
+		Banks: map[string]config.BankCertConfig{
+			"hdfc": {CertFile: filepath.Join(dir, "hdfc.pem"), KeyFile: filepath.Join(dir, "hdfc.key")},
+		},
+	}
+	writeFile(t, cfg.CAFile, ca.pem)
+	certPEM, keyPEM := ca.issue(t, "switch-1", x509.ExtKeyUsageClientAuth)
+	writeFile(t, cfg.Banks["hdfc"].CertFile, certPEM)
+	writeFile(t, cfg.Banks["hdfc"].KeyFile, keyPEM)
+
+	creds, err := LoadTLS(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	store := newFakeStore(&repository.Bank{BankCode: "HDFC", EndpointURL: addr, Status: "ACTIVE"})
+	m := NewManager(store, config.BanksConfig{PoolSize: 1, RequestTimeout: time.Second}, nil, creds, logger)
+	t.Cleanup(m.Close)
+	if err := m.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := debit(t, m, "HDFC"); err != nil {
+		t.Fatalf("debit over mTLS: %v", err)
+	}
+	if got := presented.Load(); got != "switch-1" {
+		t.Fatalf("bank saw client certificate %v, want switch-1", got)
+	}
+
+	// Rotate the certificate on disk; the next reload reconnects with it
+	certPEM, keyPEM = ca.issue(t, "switch-2", x509.ExtKeyUsageClientAuth)
+	writeFile(t, cfg.Banks["hdfc"].CertFile, certPEM)
+	writeFile(t, cfg.Banks["hdfc"].KeyFile, keyPEM)
+	m.reloadTLS()
+
+	if _, err := debit(t, m, "HDFC"); err != nil {
+		t.Fatalf("debit after rotation: %v", err)
+	}
+	if got := presented.Load(); got != "switch-2" {
+		t.Errorf("bank saw client certificate %v after rotation, want switch-2", got)
+	}
+}
+
+func TestTLSCredentialsKeepCertificatesOnBadReload(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	cfg := config.BankTLSConfig{
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client.key"),
+	}
+	writeFile(t, cfg.CAFile, ca.pem)
+	certPEM, keyPEM := ca.issue(t, "switch-1", x509.ExtKeyUsageClientAuth)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+
+	creds, err := LoadTLS(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := creds.Reload(); changed || err != nil {
+		t.Fatalf("reload of unchanged files = %v, %v; want false, nil", changed, err)
+	}
+
+	// A certificate written before its key does not match it yet
+	newCertPEM, _ := ca.issue(t, "switch-2", x509.ExtKeyUsageClientAuth)
+	writeFile(t, cfg.CertFile, newCertPEM)
+	if _, err := creds.Reload(); err == nil {
+		t.Fatal("reload of mismatched certificate and key succeeded")
+	}
+	cert, err := x509.ParseCertificate(creds.certs[cfg.CertFile].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Subject.CommonName != "switch-1" {
+		t.Errorf("client certificate after failed reload is %s, want switch-1", cert.Subject.CommonName)
+	}
+}
@@ -0,0 +1,103 @@
+// Package callback delivers notifications to PSPs by POSTing them as JSON
+// to the callback URLs their banks registered. Bodies are signed with the
+// switch's private key, so PSPs can tell them from forgeries.
+package callback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
+)
+
+// SignatureHeader carries the switch's base64 encoded signature of the body
+const SignatureHeader = "X-UPI-Signature"
+
+// Client delivers callbacks
+type Client struct {
+	http     *http.Client
+	signer   *crypto.Signer
+	attempts int
+	backoff  time.Duration
+}
+
+// New creates a callback client. Callbacks are unsigned if signer is nil.
+func New(cfg config.CollectConfig, signer *crypto.Signer) *Client {
+	if cfg.CallbackTimeout <= 0 {
+		cfg.CallbackTimeout = 5 * time.Second
+	}
+	if cfg.CallbackAttempts <= 0 {
+		cfg.CallbackAttempts = 1
+	}
+	return &Client{
+		http:     &http.Client{Timeout: cfg.CallbackTimeout},
+		signer:   signer,
+		attempts: cfg.CallbackAttempts,
+		backoff:  time.Second,
+	}
+}
+
+// Deliver POSTs payload to url. Failed attempts, network errors or 5xx
+// answers, are retried with a doubling wait; a 4xx answer is final.
+func (c *Client) Deliver(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode callback: %w", err)
+	}
+	var signature string
+	if c.signer != nil {
+		if signature, err = c.signer.Sign(body); err != nil {
+			return err
+		}
+	}
+
+	wait := c.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := c.post(ctx, url, body, signature)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == c.attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// post makes one attempt, reporting whether a failure is worth retrying
+func (c *Client) post(ctx context.Context, url string, body []byte, signature string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("invalid callback URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("callback to %s failed: %w", url, err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500:
+		return true, fmt.Errorf("callback to %s failed: %s", url, resp.Status)
+	default:
+		return false, fmt.Errorf("callback to %s rejected: %s", url, resp.Status)
+	}
+}
@@ -0,0 +1,74 @@
+package callback
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"upi-core/internal/config"
+	"upi-core/internal/crypto"
+)
+
+func newTestClient(t *testing.T) (*Client, string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(private)
+	publicDER, _ := x509.MarshalPKIXPublicKey(public)
+	signer, err := crypto.ParseSigner(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := New(config.CollectConfig{CallbackAttempts: 3}, signer)
+	client.backoff = 0
+	return client, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+}
+
+func TestDeliverSignsAndRetries(t *testing.T) {
+	client, publicKey := newTestClient(t)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if err := crypto.Verify(publicKey, body, r.Header.Get(SignatureHeader)); err != nil {
+			t.Errorf("callback signature: %v", err)
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	if err := client.Deliver(context.Background(), srv.URL, map[string]string{"collectId": "COL1"}); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("%d attempts, want 2", calls)
+	}
+}
+
+func TestDeliverDoesNotRetryRejection(t *testing.T) {
+	client, _ := newTestClient(t)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	if err := client.Deliver(context.Background(), srv.URL, map[string]string{}); err == nil {
+		t.Fatal("rejected callback reported delivered")
+	}
+	if calls != 1 {
+		t.Errorf("%d attempts, want 1", calls)
+	}
+}
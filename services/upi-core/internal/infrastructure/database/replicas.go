@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+)
+
+// Replicas are read-only copies of the database that reads which may lag
+// behind writes are sent to. Reads go round-robin to the replicas that
+// passed their last health check, and to the primary while none has.
+type Replicas struct {
+	replicas []*replica
+	next     atomic.Uint64
+	interval time.Duration
+	logger   *logrus.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type replica struct {
+	addr    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// NewReplicas opens the configured read replicas, which share the primary's
+// credentials and pool settings. A replica that is down is not an error;
+// it takes reads once a health check passes. Start runs the health checks
+// in the background.
+func NewReplicas(cfg config.DatabaseConfig, logger *logrus.Logger) (*Replicas, error) {
+	interval := cfg.ReplicaCheckInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	r := &Replicas{interval: interval, logger: logger}
+	for _, addr := range cfg.ReadReplicas {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("invalid read replica address %q: %w", addr, err)
+		}
+		replicaCfg := cfg
+		replicaCfg.Host = host
+		if replicaCfg.Port, err = strconv.Atoi(port); err != nil {
+			r.Close()
+			return nil, fmt.Errorf("invalid read replica address %q: %w", addr, err)
+		}
+
+		db, err := sql.Open("postgres", replicaCfg.GetDSN())
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to open read replica %s: %w", addr, err)
+		}
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+		db.SetConnMaxLifetime(5 * time.Minute)
+		r.replicas = append(r.replicas, &replica{addr: addr, db: db})
+	}
+	r.check()
+	return r, nil
+}
+
+// Reader returns the next healthy replica, or nil if there is none
+func (r *Replicas) Reader() *sql.DB {
+	if r == nil || len(r.replicas) == 0 {
+		return nil
+	}
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		replica := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if replica.healthy.Load() {
+			return replica.db
+		}
+	}
+	return nil
+}
+
+// Failed takes a replica a read failed on out of rotation until its next
+// health check passes
+func (r *Replicas) Failed(db *sql.DB) {
+	for _, replica := range r.replicas {
+		if replica.db == db && replica.healthy.Swap(false) {
+			r.logger.WithField("replica", replica.addr).Warn("Read replica failed, sending its reads elsewhere")
+		}
+	}
+}
+
+// Start runs the replicas' health checks in the background
+func (r *Replicas) Start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run()
+}
+
+func (r *Replicas) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.check()
+		}
+	}
+}
+
+// check pings every replica, marking it healthy or not
+func (r *Replicas) check() {
+	for _, replica := range r.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+		err := replica.db.PingContext(ctx)
+		cancel()
+
+		healthy := err == nil
+		if replica.healthy.Swap(healthy) == healthy {
+			continue
+		}
+		if healthy {
+			r.logger.WithField("replica", replica.addr).Info("Read replica healthy, sending it reads")
+		} else {
+			r.logger.WithError(err).WithField("replica", replica.addr).Warn("Read replica unhealthy, sending its reads elsewhere")
+		}
+	}
+}
+
+// Close stops the health checks and closes the replicas' connections
+func (r *Replicas) Close() error {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+		r.stop = nil
+	}
+	var firstErr error
+	for _, replica := range r.replicas {
+		if err := replica.db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
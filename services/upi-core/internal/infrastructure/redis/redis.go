@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -47,37 +48,30 @@ func (c *Client) Health() error {
 	return nil
 }
 
-// SetVPAMapping caches VPA to bank account mapping
-func (c *Client) SetVPAMapping(ctx context.Context, vpa, bankCode, accountNumber string, ttl time.Duration) error {
+// SetVPAMapping caches the resolution of a VPA
+func (c *Client) SetVPAMapping(ctx context.Context, vpa string, entry []byte, ttl time.Duration) error {
 	key := fmt.Sprintf("vpa:%s", vpa)
-	value := fmt.Sprintf("%s:%s", bankCode, accountNumber)
 
-	return c.Set(ctx, key, value, ttl).Err()
+	return c.Set(ctx, key, entry, ttl).Err()
 }
 
-// GetVPAMapping retrieves VPA to bank account mapping from cache
-func (c *Client) GetVPAMapping(ctx context.Context, vpa string) (bankCode, accountNumber string, err error) {
+// GetVPAMapping retrieves the cached resolution of a VPA, or nil if there
+// is none
+func (c *Client) GetVPAMapping(ctx context.Context, vpa string) ([]byte, error) {
 	key := fmt.Sprintf("vpa:%s", vpa)
 
-	value, err := c.Get(ctx, key).Result()
-	if err != nil {
-		return "", "", err
-	}
-
-	// Parse value (format: bankCode:accountNumber)
-	var parts []string
-	for i, r := range value {
-		if r == ':' {
-			parts = []string{value[:i], value[i+1:]}
-			break
-		}
+	entry, err := c.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
 	}
+	return entry, err
+}
 
-	if len(parts) != 2 {
-		return "", "", fmt.Errorf("invalid VPA mapping format: %s", value)
-	}
+// DeleteVPAMapping drops a cached VPA mapping, e.g. after the VPA changed
+func (c *Client) DeleteVPAMapping(ctx context.Context, vpa string) error {
+	key := fmt.Sprintf("vpa:%s", vpa)
 
-	return parts[0], parts[1], nil
+	return c.Del(ctx, key).Err()
 }
 
 // SetBankHealth caches bank health status
@@ -103,6 +97,153 @@ func (c *Client) GetBankHealth(ctx context.Context, bankCode string) (bool, erro
 	return value == "1", nil
 }
 
+// tokenBucketScript refills the bucket at KEYS[1] for the time since it was
+// last touched and takes a token from it if one is left. It returns whether
+// a token was taken and, if not, the milliseconds until one will be.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed, wait = 0, 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, wait}
+`)
+
+// TakeToken takes a token from the caller's bucket, which holds up to burst
+// tokens and refills at rate tokens a second. When the bucket is empty it
+// returns how long until the next token.
+func (c *Client) TakeToken(ctx context.Context, caller string, rate float64, burst int) (bool, time.Duration, error) {
+	key := fmt.Sprintf("ratelimit:%s", caller)
+
+	result, err := tokenBucketScript.Run(ctx, c.Client, []string{key}, rate, burst, time.Now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// dailyLimitScript adds ARGV[1] paisa and one transaction to the day's
+// totals at KEYS[1], unless that takes them over ARGV[2] paisa or ARGV[3]
+// transactions; a limit of zero is not enforced. It returns whether they
+// were added, and the totals.
+var dailyLimitScript = redis.NewScript(`
+local amount = tonumber(ARGV[1])
+local max_amount = tonumber(ARGV[2])
+local max_count = tonumber(ARGV[3])
+
+local totals = redis.call('HMGET', KEYS[1], 'amount', 'count')
+local used = tonumber(totals[1]) or 0
+local count = tonumber(totals[2]) or 0
+
+if (max_amount > 0 and used + amount > max_amount) or (max_count > 0 and count + 1 > max_count) then
+  return {0, used, count}
+end
+
+used = redis.call('HINCRBY', KEYS[1], 'amount', amount)
+count = redis.call('HINCRBY', KEYS[1], 'count', 1)
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return {1, used, count}
+`)
+
+// releaseLimitScript takes back ARGV[1] paisa and one transaction from the
+// day's totals at KEYS[1]. Totals that have expired are left alone.
+var releaseLimitScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return 0
+end
+if tonumber(redis.call('HINCRBY', KEYS[1], 'count', -1)) < 0 then
+  redis.call('HSET', KEYS[1], 'count', 0)
+end
+if tonumber(redis.call('HINCRBY', KEYS[1], 'amount', -tonumber(ARGV[1]))) < 0 then
+  redis.call('HSET', KEYS[1], 'amount', 0)
+end
+return 1
+`)
+
+// ReserveDailyLimit counts a transaction of amountPaisa against what vpa
+// sent on day, unless it takes the day over maxAmountPaisa or maxCount
+// transactions. It returns whether it was counted and the day's amount and
+// count, including it if it was. The day's totals expire after ttl.
+func (c *Client) ReserveDailyLimit(ctx context.Context, vpa, day string, amountPaisa, maxAmountPaisa, maxCount int64, ttl time.Duration) (bool, int64, int64, error) {
+	key := fmt.Sprintf("limits:%s:%s", vpa, day)
+
+	result, err := dailyLimitScript.Run(ctx, c.Client, []string{key}, amountPaisa, maxAmountPaisa, maxCount, ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	return result[0] == 1, result[1], result[2], nil
+}
+
+// ReleaseDailyLimit takes back a transaction ReserveDailyLimit counted,
+// e.g. because it failed
+func (c *Client) ReleaseDailyLimit(ctx context.Context, vpa, day string, amountPaisa int64) error {
+	key := fmt.Sprintf("limits:%s:%s", vpa, day)
+
+	return releaseLimitScript.Run(ctx, c.Client, []string{key}, amountPaisa).Err()
+}
+
+// PublishTransactionStatus publishes a change of a transaction's status to
+// the instances subscribed to it
+func (c *Client) PublishTransactionStatus(ctx context.Context, transactionID string, update []byte) error {
+	channel := fmt.Sprintf("txn:status:%s", transactionID)
+
+	return c.Publish(ctx, channel, update).Err()
+}
+
+// SubscribeTransactionStatus delivers the status changes of a transaction
+// published from the time it returns until ctx is done, when the returned
+// channel is closed
+func (c *Client) SubscribeTransactionStatus(ctx context.Context, transactionID string) (<-chan []byte, error) {
+	channel := fmt.Sprintf("txn:status:%s", transactionID)
+
+	pubsub := c.Subscribe(ctx, channel)
+	// Wait for the subscription to be confirmed so that no update published
+	// after this returns is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	updates := make(chan []byte)
+	go func() {
+		defer close(updates)
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case updates <- []byte(message.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return updates, nil
+}
+
 // Close closes the Redis connection
 func (c *Client) Close() error {
 	return c.Client.Close()
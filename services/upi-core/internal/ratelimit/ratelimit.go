@@ -0,0 +1,108 @@
+// Package ratelimit caps the requests each caller of the switch may make a
+// second, so one misbehaving bank or PSP cannot starve the others. Each
+// caller has a token bucket in Redis, shared by all instances of the switch.
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+)
+
+// Bucket takes tokens from per-caller token buckets
+type Bucket interface {
+	TakeToken(ctx context.Context, caller string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+// Limiter enforces the configured quota of each caller
+type Limiter struct {
+	bucket Bucket
+	quotas map[string]config.QuotaConfig
+	def    config.QuotaConfig
+	logger *logrus.Logger
+}
+
+// New creates a limiter. Quota keys are bank or PSP codes, matched without
+// regard to case.
+func New(bucket Bucket, cfg config.RateLimitConfig, logger *logrus.Logger) *Limiter {
+	l := &Limiter{
+		bucket: bucket,
+		quotas: make(map[string]config.QuotaConfig, len(cfg.Quotas)),
+		def:    normalize(config.QuotaConfig{TPS: cfg.DefaultTPS, Burst: cfg.DefaultBurst}),
+		logger: logger,
+	}
+	for code, quota := range cfg.Quotas {
+		l.quotas[strings.ToUpper(code)] = normalize(quota)
+	}
+	return l
+}
+
+// normalize gives a quota missing a rate or burst a usable one: a burst of
+// one second's requests, and a rate of one request a second
+func normalize(quota config.QuotaConfig) config.QuotaConfig {
+	if quota.TPS <= 0 {
+		quota.TPS = 1
+	}
+	if quota.Burst < 1 {
+		quota.Burst = int(quota.TPS)
+		if quota.Burst < 1 {
+			quota.Burst = 1
+		}
+	}
+	return quota
+}
+
+// Caller names the bucket a request is counted against. A request declaring
+// a caller with a quota of its own is counted against that caller; any other
+// is counted against its remote IP, so rotating made-up caller IDs does not
+// escape the default quota.
+func (l *Limiter) Caller(declared, remoteAddr string) string {
+	if code := strings.ToUpper(strings.TrimSpace(declared)); code != "" {
+		if _, ok := l.quotas[code]; ok {
+			return code
+		}
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return "ip:" + host
+}
+
+// Allow reports whether caller may make a request now and, if not, how long
+// it should wait before retrying. Requests are let through when Redis cannot
+// be reached: failing closed would take the whole switch down with it.
+func (l *Limiter) Allow(ctx context.Context, caller string) (bool, time.Duration) {
+	quota, ok := l.quotas[caller]
+	if !ok {
+		quota = l.def
+	}
+
+	allowed, retryAfter, err := l.bucket.TakeToken(ctx, caller, quota.TPS, quota.Burst)
+	if err != nil {
+		l.logger.WithError(err).WithField("caller", caller).Warn("Rate limiter unavailable, allowing request")
+		return true, 0
+	}
+	if !allowed {
+		l.logger.WithFields(logrus.Fields{
+			"caller":      caller,
+			"retry_after": retryAfter,
+		}).Debug("Request rate limited")
+	}
+	return allowed, retryAfter
+}
+
+// RetryAfterSeconds rounds a wait up to the whole seconds of a Retry-After
+// header
+func RetryAfterSeconds(wait time.Duration) int {
+	seconds := int((wait + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"upi-core/internal/config"
+	"upi-core/internal/infrastructure/redis"
+)
+
+func newTestLimiter(t *testing.T, bucket Bucket) *Limiter {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return New(bucket, config.RateLimitConfig{
+		DefaultTPS:   1,
+		DefaultBurst: 2,
+		Quotas: map[string]config.QuotaConfig{
+			"hdfc": {TPS: 10, Burst: 5},
+		},
+	}, logger)
+}
+
+func newRedisBucket(t *testing.T) *redis.Client {
+	mr := miniredis.RunT(t)
+	client := &redis.Client{Client: goredis.NewClient(&goredis.Options{Addr: mr.Addr()})}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestLimiterEnforcesQuotas(t *testing.T) {
+	limiter := newTestLimiter(t, newRedisBucket(t))
+	ctx := context.Background()
+
+	for caller, burst := range map[string]int{"HDFC": 5, "ip:10.0.0.1": 2} {
+		for i := 0; i < burst; i++ {
+			if allowed, _ := limiter.Allow(ctx, caller); !allowed {
+				t.Fatalf("%s: request %d of its burst of %d limited", caller, i+1, burst)
+			}
+		}
+		allowed, wait := limiter.Allow(ctx, caller)
+		if allowed {
+			t.Fatalf("%s: request past its burst allowed", caller)
+		}
+		if wait <= 0 || wait > time.Second {
+			t.Errorf("%s: retry after %v", caller, wait)
+		}
+	}
+}
+
+func TestLimiterRefillsBucket(t *testing.T) {
+	limiter := newTestLimiter(t, newRedisBucket(t))
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		limiter.Allow(ctx, "HDFC")
+	}
+	allowed, wait := limiter.Allow(ctx, "HDFC")
+	if allowed {
+		t.Fatal("request past the burst allowed")
+	}
+
+	time.Sleep(wait)
+	if allowed, _ := limiter.Allow(ctx, "HDFC"); !allowed {
+		t.Errorf("request limited after waiting the %v asked for", wait)
+	}
+}
+
+func TestLimiterIdentifiesCallers(t *testing.T) {
+	limiter := newTestLimiter(t, newRedisBucket(t))
+
+	for _, tc := range []struct {
+		declared, remoteAddr, want string
+	}{
+		{"hdfc", "10.0.0.1:5000", "HDFC"},
+		{"", "10.0.0.1:5000", "ip:10.0.0.1"},
+		// Callers without a quota cannot pick their bucket
+		{"MADEUP", "10.0.0.1:5000", "ip:10.0.0.1"},
+		{"", "10.0.0.2", "ip:10.0.0.2"},
+	} {
+		if got := limiter.Caller(tc.declared, tc.remoteAddr); got != tc.want {
+			t.Errorf("Caller(%q, %q) = %q, want %q", tc.declared, tc.remoteAddr, got, tc.want)
+		}
+	}
+}
+
+type failingBucket struct{}
+
+func (failingBucket) TakeToken(ctx context.Context, caller string, rate float64, burst int) (bool, time.Duration, error) {
+	return false, 0, errors.New("connection refused")
+}
+
+func TestLimiterFailsOpen(t *testing.T) {
+	limiter := newTestLimiter(t, failingBucket{})
+	if allowed, _ := limiter.Allow(context.Background(), "HDFC"); !allowed {
+		t.Error("request limited while Redis was unreachable")
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	for wait, want := range map[time.Duration]int{
+		0:                       1,
+		100 * time.Millisecond:  1,
+		time.Second:             1,
+		1500 * time.Millisecond: 2,
+	} {
+		if got := RetryAfterSeconds(wait); got != want {
+			t.Errorf("RetryAfterSeconds(%v) = %d, want %d", wait, got, want)
+		}
+	}
+}
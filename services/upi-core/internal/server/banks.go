@@ -0,0 +1,374 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+	pb "upi-core/pkg/pb"
+)
+
+// operatorIDHeader is the metadata key operators of the switch identify
+// themselves with
+const operatorIDHeader = "x-operator-id"
+
+// operatorID returns the operator a request was made by, or "" if it
+// names none
+func operatorID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(operatorIDHeader); len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+	}
+	return ""
+}
+
+// BankAdminService implements the BankAdmin gRPC service
+type BankAdminService struct {
+	pb.UnimplementedBankAdminServer
+	registry *service.BankService
+	logger   *logrus.Logger
+}
+
+// NewBankAdminService creates the bank administration service
+func NewBankAdminService(registry *service.BankService, logger *logrus.Logger) *BankAdminService {
+	return &BankAdminService{registry: registry, logger: logger}
+}
+
+// RegisterBankAdminServer registers the bank administration service with
+// the gRPC server
+func RegisterBankAdminServer(s *grpc.Server, srv *BankAdminService) {
+	pb.RegisterBankAdminServer(s, srv)
+}
+
+// operator returns the operator a request was made by; every call of the
+// service must name one
+func (s *BankAdminService) operator(ctx context.Context) (string, error) {
+	operator := operatorID(ctx)
+	if operator == "" {
+		return "", status.Errorf(codes.Unauthenticated, "%s metadata is required", operatorIDHeader)
+	}
+	return operator, nil
+}
+
+// RegisterBank registers a bank, pending approval by another operator, or
+// updates the details of a registered one
+func (s *BankAdminService) RegisterBank(ctx context.Context, req *pb.RegisterBankRequest) (*pb.BankAdminResponse, error) {
+	operator, err := s.operator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bank, err := s.registry.Register(ctx, bankFromProto(req), operator)
+	if err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+	return &pb.BankAdminResponse{Bank: bankToProto(bank)}, nil
+}
+
+// ApproveBank makes a bank awaiting approval ACTIVE
+func (s *BankAdminService) ApproveBank(ctx context.Context, req *pb.ApproveBankRequest) (*pb.BankAdminResponse, error) {
+	operator, err := s.operator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bank, err := s.registry.Approve(ctx, req.BankCode, operator)
+	if err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+	return &pb.BankAdminResponse{Bank: bankToProto(bank)}, nil
+}
+
+// RejectBank turns down a bank awaiting approval
+func (s *BankAdminService) RejectBank(ctx context.Context, req *pb.RejectBankRequest) (*pb.BankAdminResponse, error) {
+	operator, err := s.operator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bank, err := s.registry.Reject(ctx, req.BankCode, operator, req.Reason)
+	if err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+	return &pb.BankAdminResponse{Bank: bankToProto(bank)}, nil
+}
+
+// ActivateBank makes an approved bank ACTIVE
+func (s *BankAdminService) ActivateBank(ctx context.Context, req *pb.BankStatusChangeRequest) (*pb.BankAdminResponse, error) {
+	return s.setStatus(ctx, req, repository.BankActive)
+}
+
+// DeactivateBank takes an approved bank out of service
+func (s *BankAdminService) DeactivateBank(ctx context.Context, req *pb.BankStatusChangeRequest) (*pb.BankAdminResponse, error) {
+	return s.setStatus(ctx, req, repository.BankInactive)
+}
+
+// SuspendBank suspends an approved bank
+func (s *BankAdminService) SuspendBank(ctx context.Context, req *pb.BankStatusChangeRequest) (*pb.BankAdminResponse, error) {
+	return s.setStatus(ctx, req, repository.BankSuspended)
+}
+
+func (s *BankAdminService) setStatus(ctx context.Context, req *pb.BankStatusChangeRequest, bankStatus string) (*pb.BankAdminResponse, error) {
+	operator, err := s.operator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bank, err := s.registry.SetStatus(ctx, req.BankCode, bankStatus, operator, req.Reason)
+	if err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+	return &pb.BankAdminResponse{Bank: bankToProto(bank)}, nil
+}
+
+// ScheduleMaintenance schedules a window during which a bank is in
+// MAINTENANCE
+func (s *BankAdminService) ScheduleMaintenance(ctx context.Context, req *pb.ScheduleMaintenanceRequest) (*pb.MaintenanceWindow, error) {
+	operator, err := s.operator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.StartsAt == nil || req.EndsAt == nil {
+		return nil, status.Error(codes.InvalidArgument, "starts_at and ends_at are required")
+	}
+
+	window := &repository.MaintenanceWindow{
+		BankCode: req.BankCode,
+		StartsAt: req.StartsAt.AsTime(),
+		EndsAt:   req.EndsAt.AsTime(),
+		Reason:   req.Reason,
+	}
+	if err := s.registry.ScheduleMaintenance(ctx, window, operator); err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+	return maintenanceWindowToProto(window), nil
+}
+
+// CancelMaintenance cancels a maintenance window that has not ended
+func (s *BankAdminService) CancelMaintenance(ctx context.Context, req *pb.CancelMaintenanceRequest) (*pb.MaintenanceWindow, error) {
+	operator, err := s.operator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	window, err := s.registry.CancelMaintenance(ctx, req.WindowId, operator)
+	if errors.Is(err, service.ErrMaintenanceWindowNotFound) {
+		return nil, status.Errorf(codes.NotFound, "maintenance window %d not found, or over", req.WindowId)
+	}
+	if err != nil {
+		return nil, bankError(s.logger, err, "")
+	}
+	return maintenanceWindowToProto(window), nil
+}
+
+// ListMaintenanceWindows lists a bank's maintenance windows that are under
+// way or to come
+func (s *BankAdminService) ListMaintenanceWindows(ctx context.Context, req *pb.ListMaintenanceWindowsRequest) (*pb.ListMaintenanceWindowsResponse, error) {
+	if _, err := s.operator(ctx); err != nil {
+		return nil, err
+	}
+	if req.BankCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "bank_code is required")
+	}
+	windows, err := s.registry.ListMaintenanceWindows(ctx, req.BankCode)
+	if err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+
+	response := &pb.ListMaintenanceWindowsResponse{}
+	for _, window := range windows {
+		response.Windows = append(response.Windows, maintenanceWindowToProto(window))
+	}
+	return response, nil
+}
+
+// GetBank returns a bank
+func (s *BankAdminService) GetBank(ctx context.Context, req *pb.GetBankRequest) (*pb.BankAdminResponse, error) {
+	if _, err := s.operator(ctx); err != nil {
+		return nil, err
+	}
+	bank, err := s.registry.Get(ctx, req.BankCode)
+	if err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+	return &pb.BankAdminResponse{Bank: bankToProto(bank)}, nil
+}
+
+// ListBanks lists banks by bank code
+func (s *BankAdminService) ListBanks(ctx context.Context, req *pb.ListBanksRequest) (*pb.ListBanksResponse, error) {
+	if _, err := s.operator(ctx); err != nil {
+		return nil, err
+	}
+	return listBanks(ctx, s.registry, s.logger, req)
+}
+
+// RegisterBank registers a new bank in the network, pending approval
+// through BankAdmin, or updates the details of a registered one
+func (s *UpiCoreService) RegisterBank(ctx context.Context, req *pb.RegisterBankRequest) (*pb.RegisterBankResponse, error) {
+	bank, err := s.registry.Register(ctx, bankFromProto(req), s.operator(ctx))
+	if err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+
+	return &pb.RegisterBankResponse{
+		Success:      true,
+		BankId:       bank.ID,
+		RegisteredAt: timestamppb.New(bank.CreatedAt),
+	}, nil
+}
+
+// UpdateBankStatus moves an approved bank between ACTIVE, INACTIVE,
+// MAINTENANCE and SUSPENDED, connecting to or disconnecting from it to
+// match
+func (s *UpiCoreService) UpdateBankStatus(ctx context.Context, req *pb.UpdateBankStatusRequest) (*pb.UpdateBankStatusResponse, error) {
+	if req.Status == pb.BankStatus_BANK_STATUS_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+
+	bankStatus := strings.TrimPrefix(req.Status.String(), "BANK_STATUS_")
+	bank, err := s.registry.SetStatus(ctx, req.BankCode, bankStatus, s.operator(ctx), req.Reason)
+	if err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+
+	return &pb.UpdateBankStatusResponse{
+		Success:   true,
+		UpdatedAt: timestamppb.New(bank.UpdatedAt),
+	}, nil
+}
+
+// GetBankStatus retrieves a bank's status and its latest health check
+func (s *UpiCoreService) GetBankStatus(ctx context.Context, req *pb.BankStatusRequest) (*pb.BankStatusResponse, error) {
+	if req.BankCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "bank_code is required")
+	}
+
+	bank, err := s.registry.Get(ctx, req.BankCode)
+	if err != nil {
+		return nil, bankError(s.logger, err, req.BankCode)
+	}
+	response := &pb.BankStatusResponse{
+		BankCode:           bank.BankCode,
+		BankName:           bank.BankName,
+		Status:             bankStatusToProto(bank.Status),
+		SuccessRatePercent: int32(bank.SuccessRate),
+		AvgResponseTimeMs:  int32(bank.AvgResponseTimeMS),
+		SupportedFeatures:  bank.Features,
+	}
+	if bank.LastHeartbeat != nil {
+		response.LastHeartbeat = timestamppb.New(*bank.LastHeartbeat)
+	}
+	return response, nil
+}
+
+// ListBanks lists registered banks by bank code
+func (s *UpiCoreService) ListBanks(ctx context.Context, req *pb.ListBanksRequest) (*pb.ListBanksResponse, error) {
+	return listBanks(ctx, s.registry, s.logger, req)
+}
+
+// operator returns the operator a request was made by. Unlike BankAdmin,
+// the bank operations of UpiCore do not require one.
+func (s *UpiCoreService) operator(ctx context.Context) string {
+	if operator := operatorID(ctx); operator != "" {
+		return operator
+	}
+	return "SYSTEM"
+}
+
+// listBanks lists a page of banks; the page token is the code of the last
+// bank of the previous page
+func listBanks(ctx context.Context, registry *service.BankService, logger *logrus.Logger, req *pb.ListBanksRequest) (*pb.ListBanksResponse, error) {
+	var bankStatus string
+	if req.StatusFilter != pb.BankStatus_BANK_STATUS_UNSPECIFIED {
+		bankStatus = strings.TrimPrefix(req.StatusFilter.String(), "BANK_STATUS_")
+	}
+	banks, err := registry.List(ctx, bankStatus, req.PageToken, int(req.PageSize))
+	if err != nil {
+		return nil, bankError(logger, err, "")
+	}
+
+	response := &pb.ListBanksResponse{}
+	for _, bank := range banks {
+		response.Banks = append(response.Banks, bankToProto(bank))
+	}
+	if len(banks) > 0 && (req.PageSize <= 0 || len(banks) == int(req.PageSize)) {
+		response.NextPageToken = banks[len(banks)-1].BankCode
+	}
+	return response, nil
+}
+
+// bankError maps a bank service error to a gRPC status
+func bankError(logger *logrus.Logger, err error, bankCode string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidBankRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrBankNotFound):
+		return status.Errorf(codes.NotFound, "bank %s is not registered", bankCode)
+	case errors.Is(err, service.ErrBankStatusChange):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrSelfApproval):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		logger.WithError(err).WithField("bank_code", bankCode).Error("Bank operation failed")
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func bankFromProto(req *pb.RegisterBankRequest) *repository.Bank {
+	return &repository.Bank{
+		BankCode:    req.BankCode,
+		BankName:    req.BankName,
+		IFSCPrefix:  req.IfscPrefix,
+		EndpointURL: req.EndpointUrl,
+		PublicKey:   req.PublicKey,
+		Features:    req.SupportedFeatures,
+		CallbackURL: req.CallbackUrl,
+	}
+}
+
+func bankToProto(bank *repository.Bank) *pb.BankInfo {
+	info := &pb.BankInfo{
+		BankCode:          bank.BankCode,
+		BankName:          bank.BankName,
+		IfscPrefix:        bank.IFSCPrefix,
+		Status:            bankStatusToProto(bank.Status),
+		EndpointUrl:       bank.EndpointURL,
+		SupportedFeatures: bank.Features,
+		RegisteredAt:      timestamppb.New(bank.CreatedAt),
+		StatusReason:      bank.StatusReason,
+		RegisteredBy:      bank.RegisteredBy,
+		ApprovedBy:        bank.ApprovedBy,
+		CallbackUrl:       bank.CallbackURL,
+	}
+	if bank.ApprovedAt != nil {
+		info.ApprovedAt = timestamppb.New(*bank.ApprovedAt)
+	}
+	if bank.MaintenanceWindowID != nil {
+		info.MaintenanceWindowId = *bank.MaintenanceWindowID
+	}
+	return info
+}
+
+func bankStatusToProto(bankStatus string) pb.BankStatus {
+	return pb.BankStatus(pb.BankStatus_value["BANK_STATUS_"+bankStatus])
+}
+
+func maintenanceWindowToProto(window *repository.MaintenanceWindow) *pb.MaintenanceWindow {
+	message := &pb.MaintenanceWindow{
+		WindowId:  window.WindowID,
+		BankCode:  window.BankCode,
+		StartsAt:  timestamppb.New(window.StartsAt),
+		EndsAt:    timestamppb.New(window.EndsAt),
+		Reason:    window.Reason,
+		CreatedBy: window.CreatedBy,
+	}
+	if window.CancelledAt != nil {
+		message.CancelledAt = timestamppb.New(*window.CancelledAt)
+	}
+	return message
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+	pb "upi-core/pkg/pb"
+)
+
+// CreateCollect records a payee's request for money and sends it to the
+// payer's PSP
+func (s *UpiCoreService) CreateCollect(ctx context.Context, req *pb.CreateCollectRequest) (*pb.CreateCollectResponse, error) {
+	if req.CollectId == "" {
+		return nil, status.Error(codes.InvalidArgument, "collect_id is required")
+	}
+	if req.ExpiresInSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "expires_in_seconds cannot be negative")
+	}
+
+	request := &repository.CollectRequest{
+		CollectID:   req.CollectId,
+		PayeeVPA:    req.PayeeVpa,
+		PayerVPA:    req.PayerVpa,
+		AmountPaisa: req.AmountPaisa,
+		Description: req.Description,
+	}
+	if req.Type != pb.TransactionType_TRANSACTION_TYPE_UNSPECIFIED {
+		request.Type = repository.TransactionType(strings.TrimPrefix(req.Type.String(), "TRANSACTION_TYPE_"))
+	}
+	if err := s.collects.Create(ctx, request, time.Duration(req.ExpiresInSeconds)*time.Second); err != nil {
+		return nil, s.collectError(err, req.CollectId)
+	}
+
+	return &pb.CreateCollectResponse{Collect: collectToProto(request)}, nil
+}
+
+// RespondCollect approves or declines a pending collect request on the
+// payer's behalf. An approval pays the request.
+func (s *UpiCoreService) RespondCollect(ctx context.Context, req *pb.RespondCollectRequest) (*pb.RespondCollectResponse, error) {
+	if req.CollectId == "" {
+		return nil, status.Error(codes.InvalidArgument, "collect_id is required")
+	}
+	if req.Approve && (req.Signature == "" || req.InitiatedAt == nil) {
+		return nil, status.Error(codes.InvalidArgument, "an approval needs signature and initiated_at")
+	}
+
+	request, transaction, err := s.collects.Respond(ctx, req.CollectId, service.CollectResponse{
+		Approve:     req.Approve,
+		Reason:      req.Reason,
+		Signature:   req.Signature,
+		InitiatedAt: req.InitiatedAt.AsTime(),
+	})
+	if err != nil {
+		return nil, s.collectError(err, req.CollectId)
+	}
+
+	return &pb.RespondCollectResponse{Collect: collectToProto(request), Transaction: transaction}, nil
+}
+
+// GetCollect returns a collect request, e.g. for a payer's PSP that missed
+// its callback
+func (s *UpiCoreService) GetCollect(ctx context.Context, req *pb.GetCollectRequest) (*pb.GetCollectResponse, error) {
+	if req.CollectId == "" {
+		return nil, status.Error(codes.InvalidArgument, "collect_id is required")
+	}
+
+	request, err := s.collects.Get(ctx, req.CollectId)
+	if err != nil {
+		return nil, s.collectError(err, req.CollectId)
+	}
+
+	return &pb.GetCollectResponse{Collect: collectToProto(request)}, nil
+}
+
+// collectError maps a collect service error to a gRPC status
+func (s *UpiCoreService) collectError(err error, collectID string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidCollectRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, crypto.ErrInvalidSignature):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrCollectNotFound):
+		return status.Errorf(codes.NotFound, "collect request %s not found", collectID)
+	case errors.Is(err, service.ErrCollectNotPending), errors.Is(err, service.ErrCollectExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, repository.ErrCollectRequestExists):
+		return status.Errorf(codes.AlreadyExists, "collect request %s already exists", collectID)
+	case errors.Is(err, service.ErrShuttingDown):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		s.logger.WithError(err).WithField("collect_id", collectID).Error("Collect request operation failed")
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// optionalTimestamp converts a time that may be unset
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func collectToProto(request *repository.CollectRequest) *pb.Collect {
+	return &pb.Collect{
+		CollectId:     request.CollectID,
+		PayeeVpa:      request.PayeeVPA,
+		PayerVpa:      request.PayerVPA,
+		PayeeBankCode: request.PayeeBankCode,
+		PayerBankCode: request.PayerBankCode,
+		AmountPaisa:   request.AmountPaisa,
+		Type:          pb.TransactionType(pb.TransactionType_value["TRANSACTION_TYPE_"+string(request.Type)]),
+		Description:   request.Description,
+		Status:        pb.CollectStatus(pb.CollectStatus_value["COLLECT_STATUS_"+string(request.Status)]),
+		ErrorCode:     request.ErrorCode,
+		ErrorMessage:  request.ErrorMessage,
+		ExpiresAt:     timestamppb.New(request.ExpiresAt),
+		DeliveredAt:   optionalTimestamp(request.DeliveredAt),
+		RespondedAt:   optionalTimestamp(request.RespondedAt),
+		CreatedAt:     timestamppb.New(request.CreatedAt),
+	}
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+	pb "upi-core/pkg/pb"
+)
+
+// CreateFeeRule adds a fee rule, which applies to transactions from then on
+func (s *UpiCoreService) CreateFeeRule(ctx context.Context, req *pb.CreateFeeRuleRequest) (*pb.CreateFeeRuleResponse, error) {
+	if req.Rule == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+
+	rule := feeRuleFromProto(req.Rule)
+	if err := s.fees.Create(ctx, rule); err != nil {
+		return nil, s.feeError(err, rule.RuleID)
+	}
+
+	return &pb.CreateFeeRuleResponse{Rule: feeRuleToProto(rule)}, nil
+}
+
+// UpdateFeeRule replaces a fee rule
+func (s *UpiCoreService) UpdateFeeRule(ctx context.Context, req *pb.UpdateFeeRuleRequest) (*pb.UpdateFeeRuleResponse, error) {
+	if req.Rule == nil || req.Rule.RuleId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "rule.rule_id is required")
+	}
+
+	rule := feeRuleFromProto(req.Rule)
+	if err := s.fees.Update(ctx, rule); err != nil {
+		return nil, s.feeError(err, rule.RuleID)
+	}
+
+	return &pb.UpdateFeeRuleResponse{Rule: feeRuleToProto(rule)}, nil
+}
+
+// DeleteFeeRule removes a fee rule
+func (s *UpiCoreService) DeleteFeeRule(ctx context.Context, req *pb.DeleteFeeRuleRequest) (*pb.DeleteFeeRuleResponse, error) {
+	if req.RuleId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "rule_id is required")
+	}
+
+	if err := s.fees.Delete(ctx, req.RuleId); err != nil {
+		return nil, s.feeError(err, req.RuleId)
+	}
+
+	return &pb.DeleteFeeRuleResponse{}, nil
+}
+
+// ListFeeRules returns every fee rule
+func (s *UpiCoreService) ListFeeRules(ctx context.Context, req *pb.ListFeeRulesRequest) (*pb.ListFeeRulesResponse, error) {
+	rules, err := s.fees.List(ctx)
+	if err != nil {
+		return nil, s.feeError(err, 0)
+	}
+
+	response := &pb.ListFeeRulesResponse{}
+	for _, rule := range rules {
+		response.Rules = append(response.Rules, feeRuleToProto(rule))
+	}
+	return response, nil
+}
+
+// feeError maps a fee service error to a gRPC status
+func (s *UpiCoreService) feeError(err error, ruleID int64) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidFeeRule):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrFeeRuleNotFound):
+		return status.Errorf(codes.NotFound, "fee rule %d not found", ruleID)
+	default:
+		s.logger.WithError(err).WithField("rule_id", ruleID).Error("Fee rule operation failed")
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// optionalPaisa is an amount where 0 means none
+func optionalPaisa(paisa int64) *int64 {
+	if paisa == 0 {
+		return nil
+	}
+	return &paisa
+}
+
+func paisaOrZero(paisa *int64) int64 {
+	if paisa == nil {
+		return 0
+	}
+	return *paisa
+}
+
+func feeRuleFromProto(rule *pb.FeeRule) *repository.FeeRule {
+	transactionType := repository.TransactionType("")
+	if rule.TransactionType != pb.TransactionType_TRANSACTION_TYPE_UNSPECIFIED {
+		transactionType = repository.TransactionType(strings.TrimPrefix(rule.TransactionType.String(), "TRANSACTION_TYPE_"))
+	}
+	return &repository.FeeRule{
+		RuleID:          rule.RuleId,
+		FeeType:         repository.FeeType(strings.TrimPrefix(rule.FeeType.String(), "FEE_TYPE_")),
+		BankCode:        strings.ToUpper(rule.BankCode),
+		TransactionType: transactionType,
+		MinAmountPaisa:  rule.MinAmountPaisa,
+		MaxAmountPaisa:  optionalPaisa(rule.MaxAmountPaisa),
+		FixedPaisa:      rule.FixedPaisa,
+		PercentBps:      rule.PercentBps,
+		MinFeePaisa:     rule.MinFeePaisa,
+		MaxFeePaisa:     optionalPaisa(rule.MaxFeePaisa),
+		Description:     rule.Description,
+	}
+}
+
+func feeRuleToProto(rule *repository.FeeRule) *pb.FeeRule {
+	proto := &pb.FeeRule{
+		RuleId:         rule.RuleID,
+		FeeType:        pb.FeeType(pb.FeeType_value["FEE_TYPE_"+string(rule.FeeType)]),
+		BankCode:       rule.BankCode,
+		MinAmountPaisa: rule.MinAmountPaisa,
+		MaxAmountPaisa: paisaOrZero(rule.MaxAmountPaisa),
+		FixedPaisa:     rule.FixedPaisa,
+		PercentBps:     rule.PercentBps,
+		MinFeePaisa:    rule.MinFeePaisa,
+		MaxFeePaisa:    paisaOrZero(rule.MaxFeePaisa),
+		Description:    rule.Description,
+		CreatedAt:      timestamppb.New(rule.CreatedAt),
+		UpdatedAt:      timestamppb.New(rule.UpdatedAt),
+	}
+	if rule.TransactionType != "" {
+		proto.TransactionType = pb.TransactionType(pb.TransactionType_value["TRANSACTION_TYPE_"+string(rule.TransactionType)])
+	}
+	return proto
+}
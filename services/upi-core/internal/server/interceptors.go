@@ -2,12 +2,18 @@ package server
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+
+	"upi-core/internal/ratelimit"
 )
 
 // LoggingUnaryInterceptor logs gRPC unary requests and responses
@@ -131,3 +137,41 @@ func RecoveryStreamInterceptor(logger *logrus.Logger) grpc.StreamServerIntercept
 		return handler(srv, stream)
 	}
 }
+
+// callerIDHeader is the metadata key banks and PSPs identify themselves with
+const callerIDHeader = "x-caller-id"
+
+// RateLimitUnaryInterceptor rejects requests of callers over their quota
+// with RESOURCE_EXHAUSTED, telling them when to retry in the retry-after
+// trailer. Health checks are never limited.
+func RateLimitUnaryInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, "/grpc.health.v1.Health/") {
+			return handler(ctx, req)
+		}
+
+		var declared, remoteAddr string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(callerIDHeader); len(values) > 0 {
+				declared = values[0]
+			}
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			remoteAddr = p.Addr.String()
+		}
+
+		caller := limiter.Caller(declared, remoteAddr)
+		if allowed, wait := limiter.Allow(ctx, caller); !allowed {
+			seconds := ratelimit.RetryAfterSeconds(wait)
+			grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s, retry in %ds", caller, seconds)
+		}
+
+		return handler(ctx, req)
+	}
+}
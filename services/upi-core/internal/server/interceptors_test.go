@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"upi-core/internal/config"
+	"upi-core/internal/ratelimit"
+)
+
+// countingBucket allows the first burst calls of each caller
+type countingBucket struct {
+	calls map[string]int
+}
+
+func (b *countingBucket) TakeToken(ctx context.Context, caller string, rate float64, burst int) (bool, time.Duration, error) {
+	b.calls[caller]++
+	return b.calls[caller] <= burst, 1500 * time.Millisecond, nil
+}
+
+func TestRateLimitUnaryInterceptor(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	bucket := &countingBucket{calls: make(map[string]int)}
+	limiter := ratelimit.New(bucket, config.RateLimitConfig{
+		DefaultTPS:   1,
+		DefaultBurst: 1,
+		Quotas:       map[string]config.QuotaConfig{"SBI": {TPS: 1, Burst: 2}},
+	}, logger)
+	interceptor := RateLimitUnaryInterceptor(limiter)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	call := func(method, callerID string) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+		if callerID != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-caller-id", callerID))
+		}
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	const method = "/upi_core.UpiCore/ProcessTransaction"
+	for i, callerID := range []string{"SBI", "SBI", ""} {
+		if err := call(method, callerID); err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+	}
+	for _, callerID := range []string{"SBI", ""} {
+		if err := call(method, callerID); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("caller %q over quota: got %v, want RESOURCE_EXHAUSTED", callerID, err)
+		}
+	}
+	if err := call("/grpc.health.v1.Health/Check", ""); err != nil {
+		t.Errorf("health check limited: %v", err)
+	}
+}
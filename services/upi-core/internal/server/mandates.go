@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/crypto"
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+	pb "upi-core/pkg/pb"
+)
+
+// CreateMandate registers a mandate authorized by the payer's bank
+func (s *UpiCoreService) CreateMandate(ctx context.Context, req *pb.CreateMandateRequest) (*pb.CreateMandateResponse, error) {
+	if req.MandateId == "" {
+		return nil, status.Error(codes.InvalidArgument, "mandate_id is required")
+	}
+	if req.Frequency == pb.MandateFrequency_MANDATE_FREQUENCY_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "frequency is required")
+	}
+	startDate, err := parseDate(req.StartDate)
+	if err != nil || startDate == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_date must be a YYYY-MM-DD date")
+	}
+	endDate, err := parseDate(req.EndDate)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "end_date must be a YYYY-MM-DD date")
+	}
+
+	mandate := &repository.Mandate{
+		MandateID:      req.MandateId,
+		PayerVPA:       req.PayerVpa,
+		PayeeVPA:       req.PayeeVpa,
+		AmountPaisa:    req.AmountPaisa,
+		MaxAmountPaisa: req.MaxAmountPaisa,
+		Frequency:      repository.MandateFrequency(strings.TrimPrefix(req.Frequency.String(), "MANDATE_FREQUENCY_")),
+		StartDate:      *startDate,
+		EndDate:        endDate,
+		Description:    req.Description,
+	}
+	if err := s.mandates.Create(ctx, mandate, req.Signature); err != nil {
+		return nil, s.mandateError(err, req.MandateId)
+	}
+
+	return &pb.CreateMandateResponse{Mandate: mandateToProto(mandate)}, nil
+}
+
+// ModifyMandate changes an active mandate's amounts or end date
+func (s *UpiCoreService) ModifyMandate(ctx context.Context, req *pb.ModifyMandateRequest) (*pb.ModifyMandateResponse, error) {
+	if req.MandateId == "" {
+		return nil, status.Error(codes.InvalidArgument, "mandate_id is required")
+	}
+	endDate, err := parseDate(req.EndDate)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "end_date must be a YYYY-MM-DD date")
+	}
+
+	mandate, err := s.mandates.Modify(ctx, req.MandateId, service.MandateChanges{
+		AmountPaisa:    req.AmountPaisa,
+		MaxAmountPaisa: req.MaxAmountPaisa,
+		EndDate:        endDate,
+	})
+	if err != nil {
+		return nil, s.mandateError(err, req.MandateId)
+	}
+
+	return &pb.ModifyMandateResponse{Mandate: mandateToProto(mandate)}, nil
+}
+
+// RevokeMandate stops an active mandate being executed
+func (s *UpiCoreService) RevokeMandate(ctx context.Context, req *pb.RevokeMandateRequest) (*pb.RevokeMandateResponse, error) {
+	if req.MandateId == "" {
+		return nil, status.Error(codes.InvalidArgument, "mandate_id is required")
+	}
+
+	mandate, err := s.mandates.Revoke(ctx, req.MandateId, req.Reason)
+	if err != nil {
+		return nil, s.mandateError(err, req.MandateId)
+	}
+
+	return &pb.RevokeMandateResponse{Mandate: mandateToProto(mandate)}, nil
+}
+
+// GetMandate returns a mandate and its latest executions
+func (s *UpiCoreService) GetMandate(ctx context.Context, req *pb.GetMandateRequest) (*pb.GetMandateResponse, error) {
+	if req.MandateId == "" {
+		return nil, status.Error(codes.InvalidArgument, "mandate_id is required")
+	}
+
+	mandate, executions, err := s.mandates.Get(ctx, req.MandateId)
+	if err != nil {
+		return nil, s.mandateError(err, req.MandateId)
+	}
+
+	response := &pb.GetMandateResponse{Mandate: mandateToProto(mandate)}
+	for _, execution := range executions {
+		response.Executions = append(response.Executions, &pb.MandateExecution{
+			DueDate:       execution.DueDate.Format(time.DateOnly),
+			TransactionId: execution.TransactionID,
+			AmountPaisa:   execution.AmountPaisa,
+			Status:        string(execution.Status),
+			ErrorCode:     execution.ErrorCode,
+			ErrorMessage:  execution.ErrorMessage,
+			ExecutedAt:    timestamppb.New(execution.CreatedAt),
+		})
+	}
+	return response, nil
+}
+
+// mandateError maps a mandate service error to a gRPC status
+func (s *UpiCoreService) mandateError(err error, mandateID string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidMandateRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, crypto.ErrInvalidSignature):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrMandateNotFound):
+		return status.Errorf(codes.NotFound, "mandate %s not found", mandateID)
+	case errors.Is(err, service.ErrMandateNotActive):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, repository.ErrMandateExists):
+		return status.Errorf(codes.AlreadyExists, "mandate %s already exists", mandateID)
+	default:
+		s.logger.WithError(err).WithField("mandate_id", mandateID).Error("Mandate operation failed")
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// parseDate parses a YYYY-MM-DD date; an empty one is nil
+func parseDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(time.DateOnly)
+}
+
+func mandateToProto(mandate *repository.Mandate) *pb.Mandate {
+	return &pb.Mandate{
+		MandateId:      mandate.MandateID,
+		PayerVpa:       mandate.PayerVPA,
+		PayeeVpa:       mandate.PayeeVPA,
+		PayerBankCode:  mandate.PayerBankCode,
+		PayeeBankCode:  mandate.PayeeBankCode,
+		AmountPaisa:    mandate.AmountPaisa,
+		MaxAmountPaisa: mandate.MaxAmountPaisa,
+		Frequency:      pb.MandateFrequency(pb.MandateFrequency_value["MANDATE_FREQUENCY_"+string(mandate.Frequency)]),
+		StartDate:      mandate.StartDate.Format(time.DateOnly),
+		EndDate:        formatDate(mandate.EndDate),
+		NextDueDate:    formatDate(mandate.NextDueDate),
+		Status:         pb.MandateStatus(pb.MandateStatus_value["MANDATE_STATUS_"+string(mandate.Status)]),
+		Description:    mandate.Description,
+		RevokeReason:   mandate.RevokeReason,
+		CreatedAt:      timestamppb.New(mandate.CreatedAt),
+		UpdatedAt:      timestamppb.New(mandate.UpdatedAt),
+	}
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
+	pb "upi-core/pkg/pb"
+)
+
+// RunReconciliation reconciles a business day's transactions against the
+// banks now, e.g. again after the banks fixed their records, and returns
+// the run
+func (s *UpiCoreService) RunReconciliation(ctx context.Context, req *pb.RunReconciliationRequest) (*pb.RunReconciliationResponse, error) {
+	day, err := parseDate(req.BusinessDate)
+	if err != nil || day == nil {
+		return nil, status.Error(codes.InvalidArgument, "business_date must be a YYYY-MM-DD date")
+	}
+
+	// A day can take longer to reconcile than the caller waits; the run
+	// goes on regardless and its outcome is in the report
+	run, err := s.recon.Run(context.WithoutCancel(ctx), *day)
+	if err != nil {
+		return nil, s.reconError(err, req.BusinessDate)
+	}
+	return &pb.RunReconciliationResponse{Run: reconRunToProto(run)}, nil
+}
+
+// GetReconciliationReport returns the latest run of a business day and the
+// exceptions it found
+func (s *UpiCoreService) GetReconciliationReport(ctx context.Context, req *pb.ReconciliationReportRequest) (*pb.ReconciliationReportResponse, error) {
+	day, err := parseDate(req.BusinessDate)
+	if err != nil || day == nil {
+		return nil, status.Error(codes.InvalidArgument, "business_date must be a YYYY-MM-DD date")
+	}
+
+	run, exceptions, err := s.recon.Report(ctx, *day)
+	if err != nil {
+		return nil, s.reconError(err, req.BusinessDate)
+	}
+
+	response := &pb.ReconciliationReportResponse{Run: reconRunToProto(run)}
+	for _, exception := range exceptions {
+		response.Exceptions = append(response.Exceptions, reconExceptionToProto(exception))
+	}
+	return response, nil
+}
+
+// reconError maps a reconciler error to a gRPC status
+func (s *UpiCoreService) reconError(err error, businessDate string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidReconRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrReconInProgress):
+		return status.Errorf(codes.FailedPrecondition, "reconciliation of %s is in progress", businessDate)
+	case errors.Is(err, service.ErrReconRunNotFound):
+		return status.Errorf(codes.NotFound, "%s has not been reconciled", businessDate)
+	default:
+		s.logger.WithError(err).WithField("business_date", businessDate).Error("Reconciliation failed")
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func reconRunToProto(run *repository.ReconRun) *pb.ReconciliationRun {
+	return &pb.ReconciliationRun{
+		BusinessDate:           run.BusinessDate.Format(time.DateOnly),
+		Status:                 string(run.Status),
+		TransactionsChecked:    int32(run.TransactionsChecked),
+		TransactionsUnverified: int32(run.TransactionsUnverified),
+		ExceptionsFound:        int32(run.ExceptionsFound),
+		ErrorMessage:           run.ErrorMessage,
+		StartedAt:              timestamppb.New(run.StartedAt),
+		CompletedAt:            optionalTimestamp(run.CompletedAt),
+	}
+}
+
+func reconExceptionToProto(exception *repository.ReconException) *pb.ReconciliationException {
+	return &pb.ReconciliationException{
+		ExceptionId:       exception.ExceptionID,
+		TransactionId:     exception.TransactionID,
+		ExceptionType:     string(exception.Type),
+		BankCode:          exception.BankCode,
+		SwitchStatus:      pb.TransactionStatus(pb.TransactionStatus_value["TRANSACTION_STATUS_"+string(exception.SwitchStatus)]),
+		BankStatus:        exception.BankStatus,
+		SwitchAmountPaisa: exception.SwitchAmountPaisa,
+		BankAmountPaisa:   exception.BankAmountPaisa,
+		Details:           exception.Details,
+		DetectedAt:        timestamppb.New(exception.DetectedAt),
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,9 +12,12 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"upi-core/internal/domain/repository"
+	"upi-core/internal/domain/service"
 	"upi-core/internal/infrastructure/database"
 	"upi-core/internal/infrastructure/kafka"
 	"upi-core/internal/infrastructure/redis"
+	"upi-core/internal/upierr"
 	pb "upi-core/pkg/pb"
 )
 
@@ -24,6 +28,14 @@ type UpiCoreService struct {
 	redis  *redis.Client
 	kafka  *kafka.Producer
 	logger *logrus.Logger
+
+	transactions *service.TransactionService
+	vpas         *service.VPAService
+	mandates     *service.MandateService
+	collects     *service.CollectService
+	fees         *service.FeeService
+	recon        *service.Reconciler
+	registry     *service.BankService
 }
 
 // NewUpiCoreService creates a new UPI Core service instance
@@ -31,13 +43,27 @@ func NewUpiCoreService(
 	db *database.Database,
 	redis *redis.Client,
 	kafka *kafka.Producer,
+	transactions *service.TransactionService,
+	vpas *service.VPAService,
+	mandates *service.MandateService,
+	collects *service.CollectService,
+	fees *service.FeeService,
+	recon *service.Reconciler,
+	registry *service.BankService,
 	logger *logrus.Logger,
 ) *UpiCoreService {
 	return &UpiCoreService{
-		db:     db,
-		redis:  redis,
-		kafka:  kafka,
-		logger: logger,
+		db:           db,
+		redis:        redis,
+		kafka:        kafka,
+		logger:       logger,
+		transactions: transactions,
+		vpas:         vpas,
+		mandates:     mandates,
+		collects:     collects,
+		fees:         fees,
+		recon:        recon,
+		registry:     registry,
 	}
 }
 
@@ -69,29 +95,11 @@ func (s *UpiCoreService) ProcessTransaction(ctx context.Context, req *pb.Transac
 		return nil, status.Error(codes.InvalidArgument, "amount_paisa must be positive")
 	}
 
-	// Mock response for now
-	response := &pb.TransactionResponse{
-		TransactionId: req.TransactionId,
-		Rrn:           generateRRN(),
-		Status:        pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS,
-		PayerBankCode: "HDFC", // Mock
-		PayeeBankCode: "SBI",  // Mock
-		ProcessedAt:   timestamppb.Now(),
-		Fees: &pb.TransactionFees{
-			SwitchFeePaisa: 100,
-			BankFeePaisa:   50,
-			TotalFeePaisa:  150,
-		},
-		SettlementId: generateSettlementID(),
+	response, err := s.transactions.ProcessTransaction(ctx, req)
+	if errors.Is(err, service.ErrShuttingDown) {
+		return nil, status.Error(codes.Unavailable, err.Error())
 	}
-
-	s.logger.WithFields(logrus.Fields{
-		"transaction_id": response.TransactionId,
-		"rrn":            response.Rrn,
-		"status":         response.Status,
-	}).Info("Transaction processed successfully")
-
-	return response, nil
+	return response, err
 }
 
 // GetTransactionStatus retrieves transaction status
@@ -100,19 +108,43 @@ func (s *UpiCoreService) GetTransactionStatus(ctx context.Context, req *pb.Trans
 		return nil, status.Error(codes.InvalidArgument, "either transaction_id or rrn is required")
 	}
 
-	// Mock response
-	return &pb.TransactionStatusResponse{
-		TransactionId: req.TransactionId,
-		Rrn:           req.Rrn,
-		Status:        pb.TransactionStatus_TRANSACTION_STATUS_SUCCESS,
-		AmountPaisa:   10000,
-		PayerVpa:      "user@hdfc",
-		PayeeVpa:      "merchant@sbi",
-		PayerBankCode: "HDFC",
-		PayeeBankCode: "SBI",
-		InitiatedAt:   timestamppb.Now(),
-		ProcessedAt:   timestamppb.Now(),
-	}, nil
+	var transaction *repository.Transaction
+	var err error
+	if req.TransactionId != "" {
+		transaction, err = s.transactions.GetTransaction(ctx, req.TransactionId)
+	} else {
+		transaction, err = s.transactions.GetTransactionByRRN(ctx, req.Rrn)
+	}
+	if errors.Is(err, service.ErrTransactionNotFound) {
+		return nil, status.Error(codes.NotFound, "transaction not found")
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get transaction status")
+		return nil, status.Error(codes.Internal, "failed to get transaction status")
+	}
+
+	response := &pb.TransactionStatusResponse{
+		TransactionId: transaction.TransactionID,
+		Rrn:           transaction.RRN,
+		Status:        pb.TransactionStatus(pb.TransactionStatus_value["TRANSACTION_STATUS_"+string(transaction.Status)]),
+		AmountPaisa:   transaction.AmountPaisa,
+		PayerVpa:      transaction.PayerVPA,
+		PayeeVpa:      transaction.PayeeVPA,
+		PayerBankCode: transaction.PayerBankCode,
+		PayeeBankCode: transaction.PayeeBankCode,
+		InitiatedAt:   timestamppb.New(transaction.InitiatedAt),
+		ErrorCode:     transaction.ErrorCode,
+		ErrorMessage:  transaction.ErrorMessage,
+
+		Currency:           transaction.Currency,
+		SettledAmountPaisa: transaction.SettledAmountPaisa,
+		SettledCurrency:    transaction.SettledCurrency,
+		FxRate:             transaction.FXRate,
+	}
+	if transaction.ProcessedAt != nil {
+		response.ProcessedAt = timestamppb.New(*transaction.ProcessedAt)
+	}
+	return response, nil
 }
 
 // CancelTransaction cancels a pending transaction
@@ -151,33 +183,24 @@ func (s *UpiCoreService) ResolveVPA(ctx context.Context, req *pb.ResolveVPAReque
 		return nil, status.Error(codes.InvalidArgument, "vpa is required")
 	}
 
-	s.logger.WithField("vpa", req.Vpa).Info("Resolving VPA")
-
-	// Try cache first
-	bankCode, accountNumber, err := s.redis.GetVPAMapping(ctx, req.Vpa)
-	if err == nil {
-		s.logger.WithFields(logrus.Fields{
-			"vpa":            req.Vpa,
-			"bank_code":      bankCode,
-			"account_number": accountNumber,
-		}).Info("VPA resolved from cache")
-
+	mapping, err := s.vpas.Resolve(ctx, req.Vpa)
+	if errors.Is(err, service.ErrVPANotFound) {
 		return &pb.ResolveVPAResponse{
-			Exists:            true,
-			BankCode:          bankCode,
-			AccountNumber:     accountNumber,
-			AccountHolderName: "Mock User", // Would fetch from database
-			IsActive:          true,
+			Exists:       false,
+			ErrorCode:    string(upierr.InvalidVPA),
+			ErrorMessage: err.Error(),
 		}, nil
 	}
+	if err != nil {
+		return nil, s.vpaError(err, req.Vpa)
+	}
 
-	// Mock response for database lookup
 	return &pb.ResolveVPAResponse{
 		Exists:            true,
-		BankCode:          "HDFC",
-		AccountNumber:     "1234567890",
-		AccountHolderName: "Mock User",
-		IsActive:          true,
+		BankCode:          mapping.BankCode,
+		AccountNumber:     mapping.AccountNumber,
+		AccountHolderName: mapping.AccountHolderName,
+		IsActive:          mapping.IsActive,
 	}, nil
 }
 
@@ -193,15 +216,20 @@ func (s *UpiCoreService) RegisterVPA(ctx context.Context, req *pb.RegisterVPAReq
 		return nil, status.Error(codes.InvalidArgument, "account_number is required")
 	}
 
-	// Cache the VPA mapping
-	err := s.redis.SetVPAMapping(ctx, req.Vpa, req.BankCode, req.AccountNumber, 24*time.Hour)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to cache VPA mapping")
+	mapping := &repository.VPAMapping{
+		VPA:               req.Vpa,
+		BankCode:          req.BankCode,
+		AccountNumber:     req.AccountNumber,
+		AccountHolderName: req.AccountHolderName,
+		MobileNumber:      req.MobileNumber,
+	}
+	if err := s.vpas.Register(ctx, mapping); err != nil {
+		return nil, s.vpaError(err, req.Vpa)
 	}
 
 	return &pb.RegisterVPAResponse{
 		Success:      true,
-		RegisteredAt: timestamppb.Now(),
+		RegisteredAt: timestamppb.New(mapping.CreatedAt),
 	}, nil
 }
 
@@ -210,6 +238,13 @@ func (s *UpiCoreService) UpdateVPA(ctx context.Context, req *pb.UpdateVPARequest
 	if req.Vpa == "" {
 		return nil, status.Error(codes.InvalidArgument, "vpa is required")
 	}
+	if req.NewAccountNumber == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_account_number is required")
+	}
+
+	if err := s.vpas.Update(ctx, req.Vpa, req.NewAccountNumber); err != nil {
+		return nil, s.vpaError(err, req.Vpa)
+	}
 
 	return &pb.UpdateVPAResponse{
 		Success:   true,
@@ -223,70 +258,31 @@ func (s *UpiCoreService) DeactivateVPA(ctx context.Context, req *pb.DeactivateVP
 		return nil, status.Error(codes.InvalidArgument, "vpa is required")
 	}
 
+	if err := s.vpas.Deactivate(ctx, req.Vpa, req.Reason); err != nil {
+		return nil, s.vpaError(err, req.Vpa)
+	}
+
 	return &pb.DeactivateVPAResponse{
 		Success:       true,
 		DeactivatedAt: timestamppb.Now(),
 	}, nil
 }
 
-// RegisterBank registers a new bank in the network
-func (s *UpiCoreService) RegisterBank(ctx context.Context, req *pb.RegisterBankRequest) (*pb.RegisterBankResponse, error) {
-	if req.BankCode == "" {
-		return nil, status.Error(codes.InvalidArgument, "bank_code is required")
-	}
-
-	return &pb.RegisterBankResponse{
-		Success:      true,
-		BankId:       generateBankID(),
-		RegisteredAt: timestamppb.Now(),
-	}, nil
-}
-
-// UpdateBankStatus updates bank status
-func (s *UpiCoreService) UpdateBankStatus(ctx context.Context, req *pb.UpdateBankStatusRequest) (*pb.UpdateBankStatusResponse, error) {
-	if req.BankCode == "" {
-		return nil, status.Error(codes.InvalidArgument, "bank_code is required")
-	}
-
-	return &pb.UpdateBankStatusResponse{
-		Success:   true,
-		UpdatedAt: timestamppb.Now(),
-	}, nil
-}
-
-// GetBankStatus retrieves bank status information
-func (s *UpiCoreService) GetBankStatus(ctx context.Context, req *pb.BankStatusRequest) (*pb.BankStatusResponse, error) {
-	if req.BankCode == "" {
-		return nil, status.Error(codes.InvalidArgument, "bank_code is required")
-	}
-
-	return &pb.BankStatusResponse{
-		BankCode:           req.BankCode,
-		BankName:           "Mock Bank",
-		Status:             "ACTIVE",
-		SuccessRatePercent: 99,
-		AvgResponseTimeMs:  50,
-		LastHeartbeat:      timestamppb.Now(),
-		SupportedFeatures:  []string{"UPI", "IMPS", "NEFT"},
-	}, nil
-}
-
-// ListBanks lists all registered banks
-func (s *UpiCoreService) ListBanks(ctx context.Context, req *pb.ListBanksRequest) (*pb.ListBanksResponse, error) {
-	// Mock response
-	banks := []*pb.BankInfo{
-		{
-			BankCode:    "HDFC",
-			BankName:    "HDFC Bank",
-			IfscPrefix:  "HDFC",
-			Status:      "ACTIVE",
-			EndpointUrl: "https://api.hdfc.com/upi",
-		},
+// vpaError maps a VPA service error to a gRPC status
+func (s *UpiCoreService) vpaError(err error, vpa string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidVPARequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrVPANotFound):
+		return upierr.Newf(upierr.InvalidVPA, "VPA %s not found", vpa)
+	case errors.Is(err, service.ErrVPAOutsideNamespace):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, repository.ErrVPAAlreadyRegistered):
+		return status.Errorf(codes.AlreadyExists, "VPA %s is already registered", vpa)
+	default:
+		s.logger.WithError(err).WithField("vpa", vpa).Error("VPA operation failed")
+		return upierr.New(upierr.SystemError, "internal error")
 	}
-
-	return &pb.ListBanksResponse{
-		Banks: banks,
-	}, nil
 }
 
 // InitiateSettlement initiates settlement process
@@ -310,7 +306,7 @@ func (s *UpiCoreService) GetSettlementStatus(ctx context.Context, req *pb.Settle
 
 	return &pb.SettlementStatusResponse{
 		SettlementId: req.SettlementId,
-		Status:       "COMPLETED",
+		Status:       pb.SettlementStatus_SETTLEMENT_STATUS_COMPLETED,
 		CreatedAt:    timestamppb.Now(),
 		CompletedAt:  timestamppb.Now(),
 	}, nil
@@ -322,15 +318,68 @@ func (s *UpiCoreService) GetSettlementReport(ctx context.Context, req *pb.Settle
 		return nil, status.Error(codes.InvalidArgument, "bank_code is required")
 	}
 
-	return &pb.SettlementReportResponse{
-		BankCode:         req.BankCode,
-		TotalCreditPaisa: 1000000000,
-		TotalDebitPaisa:  900000000,
-		NetAmountPaisa:   100000000,
-		TransactionCount: 10000,
-	}, nil
+	response := &pb.SettlementReportResponse{
+		BankCode:           req.BankCode,
+		TotalCreditPaisa:   1000000000,
+		TotalDebitPaisa:    900000000,
+		NetSettlementPaisa: 100000000,
+		TransactionCount:   10000,
+	}
+
+	// The bank's successful transactions, for the PSPs to reconcile
+	filter := repository.TransactionFilter{
+		BankCode: req.BankCode,
+		Status:   repository.StatusSuccess,
+		Limit:    int(req.PageSize),
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = maxSettlementPageSize
+	}
+	if req.FromDate != nil {
+		filter.From = req.FromDate.AsTime()
+	}
+	if req.ToDate != nil {
+		filter.To = req.ToDate.AsTime()
+	}
+	if req.PageToken != "" {
+		var err error
+		if filter.After, err = repository.ParseTransactionCursor(req.PageToken); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	transactions, next, err := s.transactions.ListTransactions(ctx, filter)
+	if errors.Is(err, service.ErrInvalidTransactionFilter) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("bank_code", req.BankCode).Error("Failed to list settlement records")
+		return nil, status.Error(codes.Internal, "failed to list settlement records")
+	}
+	for _, transaction := range transactions {
+		record := &pb.SettlementRecord{
+			TransactionId: transaction.TransactionID,
+			Rrn:           transaction.RRN,
+			PayerBankCode: transaction.PayerBankCode,
+			PayeeBankCode: transaction.PayeeBankCode,
+			AmountPaisa:   transaction.AmountPaisa,
+			FeePaisa:      transaction.TotalFeePaisa,
+			SettlementId:  transaction.SettlementID,
+		}
+		if transaction.ProcessedAt != nil {
+			record.ProcessedAt = timestamppb.New(*transaction.ProcessedAt)
+		}
+		response.Records = append(response.Records, record)
+	}
+	if next != nil {
+		response.NextPageToken = next.Token()
+	}
+	return response, nil
 }
 
+// maxSettlementPageSize is the most records a settlement report page has
+const maxSettlementPageSize = 100
+
 // HealthCheck provides health status
 func (s *UpiCoreService) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
 	details := make(map[string]string)
@@ -392,14 +441,6 @@ func (s *UpiCoreService) GetMetrics(ctx context.Context, req *pb.MetricsRequest)
 }
 
 // Helper functions
-func generateRRN() string {
-	return fmt.Sprintf("RRN%d", time.Now().UnixNano())
-}
-
 func generateSettlementID() string {
 	return fmt.Sprintf("SETT%d", time.Now().UnixNano())
 }
-
-func generateBankID() string {
-	return fmt.Sprintf("BANK%d", time.Now().UnixNano())
-}
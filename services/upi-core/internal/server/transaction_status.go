@@ -0,0 +1,48 @@
+package server
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"upi-core/internal/domain/service"
+	pb "upi-core/pkg/pb"
+)
+
+// SubscribeTransactionStatus streams a transaction's status as it changes,
+// so that clients need not poll GetTransactionStatus
+func (s *UpiCoreService) SubscribeTransactionStatus(req *pb.SubscribeTransactionStatusRequest, stream pb.UpiCore_SubscribeTransactionStatusServer) error {
+	if req.TransactionId == "" {
+		return status.Error(codes.InvalidArgument, "transaction_id is required")
+	}
+
+	updates, err := s.transactions.SubscribeTransactionStatus(stream.Context(), req.TransactionId)
+	if errors.Is(err, service.ErrTransactionNotFound) {
+		return status.Error(codes.NotFound, "transaction not found")
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to subscribe to transaction status")
+		return status.Error(codes.Internal, "failed to subscribe to transaction status")
+	}
+
+	for update := range updates {
+		if err := stream.Send(statusUpdateToProto(update)); err != nil {
+			return err
+		}
+	}
+	// The updates end early only if the client went away
+	return stream.Context().Err()
+}
+
+func statusUpdateToProto(update *service.TransactionStatusUpdate) *pb.TransactionStatusUpdate {
+	return &pb.TransactionStatusUpdate{
+		TransactionId: update.TransactionID,
+		Status:        pb.TransactionStatus(pb.TransactionStatus_value["TRANSACTION_STATUS_"+string(update.Status)]),
+		ErrorCode:     update.ErrorCode,
+		ErrorMessage:  update.ErrorMessage,
+		UpdatedAt:     timestamppb.New(update.UpdatedAt),
+		Final:         update.Final(),
+	}
+}
@@ -0,0 +1,164 @@
+// Package upierr is the catalog of the error codes transactions fail with.
+// Codes follow NPCI's UPI response codes where NPCI has one for the
+// failure; the S series is the switch's own. Each code maps to one gRPC
+// and one HTTP status, so callers see the same failure the same way over
+// either API and in Kafka events.
+package upierr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the domain of the google.rpc.ErrorInfo of gRPC errors
+const Domain = "upi-core"
+
+// Code is an error code of the catalog
+type Code string
+
+// The catalog's codes
+const (
+	DuplicateInProgress      Code = "U01"
+	AmountCapExceeded        Code = "U02"
+	SystemError              Code = "U13"
+	DailyAmountExceeded      Code = "U16"
+	AddressResolutionFailed  Code = "U29"
+	DebitFailed              Code = "U30"
+	CreditFailed             Code = "U31"
+	SignatureInvalid         Code = "U66"
+	TransactionTimeout       Code = "U67"
+	CollectExpired           Code = "U69"
+	BankUnavailable          Code = "U78"
+	AccountDoesNotExist      Code = "XH"
+	AccountFrozen            Code = "YE"
+	DeclinedByCustomer       Code = "ZA"
+	ValidationError          Code = "ZD"
+	InvalidVPA               Code = "ZH"
+	DailyCountExceeded       Code = "Z7"
+	TransactionLimitExceeded Code = "Z8"
+	InsufficientFunds        Code = "Z9"
+	FXRateUnavailable        Code = "S01"
+	TransactionPending       Code = "S02"
+)
+
+// Definition is what a code means and how it is reported
+type Definition struct {
+	Name        string // Stable symbolic name, e.g. DEBIT_FAILED
+	Description string
+	GRPC        codes.Code
+	HTTP        int
+}
+
+var catalog = map[Code]Definition{
+	DuplicateInProgress:      {"DUPLICATE_IN_PROGRESS", "Duplicate of a request still in progress", codes.Aborted, http.StatusConflict},
+	AmountCapExceeded:        {"AMOUNT_CAP_EXCEEDED", "Amount cap of the mandate exceeded", codes.FailedPrecondition, http.StatusUnprocessableEntity},
+	SystemError:              {"SYSTEM_ERROR", "Internal error at the switch", codes.Internal, http.StatusInternalServerError},
+	DailyAmountExceeded:      {"DAILY_AMOUNT_EXCEEDED", "Daily amount limit of the payer exceeded", codes.ResourceExhausted, http.StatusTooManyRequests},
+	AddressResolutionFailed:  {"ADDRESS_RESOLUTION_FAILED", "Address resolution failed", codes.Unavailable, http.StatusServiceUnavailable},
+	DebitFailed:              {"DEBIT_FAILED", "Debit has failed", codes.FailedPrecondition, http.StatusUnprocessableEntity},
+	CreditFailed:             {"CREDIT_FAILED", "Credit has failed, debit reversed", codes.FailedPrecondition, http.StatusUnprocessableEntity},
+	SignatureInvalid:         {"SIGNATURE_INVALID", "Signature does not verify against the bank's key", codes.Unauthenticated, http.StatusUnauthorized},
+	TransactionTimeout:       {"TRANSACTION_TIMEOUT", "Transaction expired before it completed", codes.DeadlineExceeded, http.StatusGatewayTimeout},
+	CollectExpired:           {"COLLECT_EXPIRED", "Collect request expired", codes.FailedPrecondition, http.StatusGone},
+	BankUnavailable:          {"BANK_UNAVAILABLE", "Bank is not available", codes.Unavailable, http.StatusServiceUnavailable},
+	AccountDoesNotExist:      {"ACCOUNT_DOES_NOT_EXIST", "Account does not exist", codes.FailedPrecondition, http.StatusUnprocessableEntity},
+	AccountFrozen:            {"ACCOUNT_FROZEN", "Remitting account is blocked or frozen", codes.FailedPrecondition, http.StatusUnprocessableEntity},
+	DeclinedByCustomer:       {"DECLINED_BY_CUSTOMER", "Transaction declined by customer", codes.FailedPrecondition, http.StatusUnprocessableEntity},
+	ValidationError:          {"VALIDATION_ERROR", "Validation error", codes.InvalidArgument, http.StatusBadRequest},
+	InvalidVPA:               {"INVALID_VPA", "Invalid virtual address", codes.NotFound, http.StatusNotFound},
+	DailyCountExceeded:       {"DAILY_COUNT_EXCEEDED", "Transaction frequency limit exceeded", codes.ResourceExhausted, http.StatusTooManyRequests},
+	TransactionLimitExceeded: {"TRANSACTION_LIMIT_EXCEEDED", "Per transaction limit exceeded", codes.FailedPrecondition, http.StatusUnprocessableEntity},
+	InsufficientFunds:        {"INSUFFICIENT_FUNDS", "Insufficient funds in customer account", codes.FailedPrecondition, http.StatusUnprocessableEntity},
+	FXRateUnavailable:        {"FX_RATE_UNAVAILABLE", "Amount could not be converted to the settlement currency", codes.Unavailable, http.StatusServiceUnavailable},
+	TransactionPending:       {"TRANSACTION_PENDING", "Transaction is pending, its outcome is not yet known", codes.Unavailable, http.StatusAccepted},
+}
+
+// Lookup returns the definition of code. Codes not in the catalog, e.g.
+// ones stored before it, are reported as SYSTEM_ERROR is.
+func Lookup(code Code) (Definition, bool) {
+	definition, ok := catalog[code]
+	if !ok {
+		definition = catalog[SystemError]
+		definition.Name = string(code)
+	}
+	return definition, ok
+}
+
+// Name returns the symbolic name of the code
+func (c Code) Name() string {
+	definition, _ := Lookup(c)
+	return definition.Name
+}
+
+// GRPCCode returns the gRPC status code the code is reported with
+func (c Code) GRPCCode() codes.Code {
+	definition, _ := Lookup(c)
+	return definition.GRPC
+}
+
+// HTTPStatus returns the HTTP status the code is reported with
+func (c Code) HTTPStatus() int {
+	definition, _ := Lookup(c)
+	return definition.HTTP
+}
+
+// Error is an error with a code of the catalog
+type Error struct {
+	Code    Code
+	Message string
+	Err     error // Cause, if any
+}
+
+// New returns an error with code and message
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf returns an error with code and a formatted message
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap gives err a code, keeping its message
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Message: err.Error(), Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus returns the status a gRPC handler returning the error fails
+// with: the code's gRPC code, and a google.rpc.ErrorInfo whose reason is
+// the code itself
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.Code.GRPCCode(), e.Message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   string(e.Code),
+		Domain:   Domain,
+		Metadata: map[string]string{"name": e.Code.Name()},
+	})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// CodeOf returns the code of the first error in err's chain that has one,
+// or fallback if none has
+func CodeOf(err error, fallback Code) Code {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return fallback
+}
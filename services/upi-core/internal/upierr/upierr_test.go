@@ -0,0 +1,51 @@
+package upierr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorsCarryTheirCodeToGRPC(t *testing.T) {
+	err := fmt.Errorf("resolving payee: %w", Newf(InvalidVPA, "VPA %s not found", "bob@okbank"))
+
+	if code := CodeOf(err, SystemError); code != InvalidVPA {
+		t.Errorf("CodeOf = %s, want %s", code, InvalidVPA)
+	}
+	if code := CodeOf(errors.New("connection refused"), SystemError); code != SystemError {
+		t.Errorf("CodeOf an uncoded error = %s, want the fallback", code)
+	}
+
+	st, ok := status.FromError(New(DailyCountExceeded, "DAILY_COUNT limit of 20 transactions exceeded"))
+	if !ok || st.Code() != codes.ResourceExhausted {
+		t.Fatalf("status = %v, want RESOURCE_EXHAUSTED", st)
+	}
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("details = %v, want an ErrorInfo", details)
+	}
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok || info.Reason != "Z7" || info.Domain != Domain || info.Metadata["name"] != "DAILY_COUNT_EXCEEDED" {
+		t.Errorf("detail = %v, want the Z7 ErrorInfo", details[0])
+	}
+}
+
+func TestUnknownCodesAreReportedAsSystemErrors(t *testing.T) {
+	if _, ok := Lookup("PROCESSING_ERROR"); ok {
+		t.Fatal("PROCESSING_ERROR is in the catalog")
+	}
+	code := Code("PROCESSING_ERROR")
+	if code.HTTPStatus() != http.StatusInternalServerError || code.GRPCCode() != codes.Internal || code.Name() != "PROCESSING_ERROR" {
+		t.Errorf("PROCESSING_ERROR maps to %d, %s, %s", code.HTTPStatus(), code.GRPCCode(), code.Name())
+	}
+	for code, definition := range catalog {
+		if definition.Name == "" || definition.HTTP == 0 || definition.GRPC == codes.OK {
+			t.Errorf("%s is missing its name or statuses: %+v", code, definition)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+-- Idempotency keys are claimed before a request is processed
+-- Migration: 002_idempotency_claims.sql
+--
+-- A request inserts its key as PENDING before doing any work, so a
+-- concurrent duplicate finds the row and is turned away instead of being
+-- processed a second time. The key becomes COMPLETED with the cached
+-- response once the request finishes. Rows written before this migration
+-- already hold a response, hence the default.
+
+ALTER TABLE idempotency_keys
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'COMPLETED'
+        CHECK (status IN ('PENDING', 'COMPLETED'));
@@ -0,0 +1,13 @@
+-- Expired transactions are timed out by the reaper
+-- Migration: 003_transaction_expiry.sql
+--
+-- A transaction row is committed as PENDING before any bank is called, and
+-- records the payer bank's reference once the debit succeeds. A PENDING row
+-- past its expires_at was interrupted midway: the reaper marks it TIMEOUT
+-- and, if debit_reference is set, reverses the debit.
+
+ALTER TABLE transactions
+    ADD COLUMN debit_reference VARCHAR(100);
+
+CREATE INDEX idx_transactions_pending_expiry ON transactions (expires_at)
+    WHERE status = 'PENDING';
@@ -0,0 +1,12 @@
+-- Banks own the VPA handles they issue
+-- Migration: 004_vpa_handles.sql
+--
+-- A VPA is handle@psp; the PSP part names the bank that issued it, e.g.
+-- alice@hdfc. vpa_handles lists the PSP parts a bank may register VPAs
+-- under, so one bank cannot register VPAs in another's namespace. Banks
+-- start with their bank code, lower-cased.
+
+ALTER TABLE banks
+    ADD COLUMN vpa_handles TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE banks SET vpa_handles = ARRAY[lower(bank_code)];
@@ -0,0 +1,11 @@
+-- Bank circuit breaker state
+-- Migration: 005_bank_circuit_state.sql
+--
+-- UPI Core stops calling a bank that keeps failing calls until a probe call
+-- succeeds. circuit_state is the last state any instance moved the bank's
+-- circuit breaker to, for operators; each instance keeps its own breaker.
+
+ALTER TABLE banks
+    ADD COLUMN circuit_state VARCHAR(10) NOT NULL DEFAULT 'CLOSED'
+        CHECK (circuit_state IN ('CLOSED', 'OPEN', 'HALF_OPEN')),
+    ADD COLUMN circuit_changed_at TIMESTAMP;
@@ -0,0 +1,51 @@
+-- UPI mandates (autopay)
+-- Migration: 006_mandates.sql
+--
+-- A mandate authorizes a payee to debit the payer's account on a schedule.
+-- The mandate scheduler debits each ACTIVE mandate on its next_due_date and
+-- moves next_due_date on; mandates past their end_date are COMPLETED.
+--
+-- Each execution is recorded in mandate_executions before the payer is
+-- debited. There is one row per mandate and due date, so an execution is
+-- never made twice, and its transaction_id is derived from both, so a retried
+-- execution reuses the original transaction's idempotency key.
+
+CREATE TABLE mandates (
+    mandate_id VARCHAR(35) PRIMARY KEY,
+    payer_vpa VARCHAR(100) NOT NULL,
+    payee_vpa VARCHAR(100) NOT NULL,
+    payer_bank_code VARCHAR(10) NOT NULL REFERENCES banks(bank_code),
+    payee_bank_code VARCHAR(10) NOT NULL REFERENCES banks(bank_code),
+    amount_paisa BIGINT NOT NULL CHECK (amount_paisa > 0),
+    max_amount_paisa BIGINT NOT NULL CHECK (max_amount_paisa > 0),
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('ONE_TIME', 'DAILY', 'WEEKLY', 'MONTHLY', 'QUARTERLY', 'YEARLY')),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    next_due_date DATE,
+    status VARCHAR(10) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'REVOKED', 'COMPLETED')),
+    description TEXT,
+    revoke_reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_mandate_period CHECK (end_date IS NULL OR end_date >= start_date),
+    CONSTRAINT active_mandate_due CHECK (status <> 'ACTIVE' OR next_due_date IS NOT NULL)
+);
+
+CREATE TABLE mandate_executions (
+    mandate_id VARCHAR(35) NOT NULL REFERENCES mandates(mandate_id),
+    due_date DATE NOT NULL,
+    transaction_id VARCHAR(50) UNIQUE NOT NULL,
+    amount_paisa BIGINT NOT NULL CHECK (amount_paisa > 0),
+    status VARCHAR(10) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SUCCESS', 'FAILED')),
+    error_code VARCHAR(50),
+    error_message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (mandate_id, due_date)
+);
+
+CREATE INDEX idx_mandates_due ON mandates (next_due_date) WHERE status = 'ACTIVE';
+CREATE INDEX idx_mandates_payer_vpa ON mandates (payer_vpa);
+CREATE INDEX idx_mandate_executions_pending ON mandate_executions (updated_at) WHERE status = 'PENDING';
@@ -0,0 +1,34 @@
+-- Collect requests (pull payments)
+-- Migration: 007_collect_requests.sql
+--
+-- A payee asks a payer for money with a collect request. The switch
+-- delivers it to the payer's PSP at the callback_url of the payer's bank,
+-- and the payer approves or declines it before expires_at. An approved
+-- request is paid with a transaction whose ID is the collect_id; a request
+-- left PENDING past its expiry is EXPIRED by the reaper.
+
+ALTER TABLE banks
+    ADD COLUMN callback_url TEXT;
+
+CREATE TABLE collect_requests (
+    collect_id VARCHAR(35) PRIMARY KEY,
+    payee_vpa VARCHAR(100) NOT NULL,
+    payer_vpa VARCHAR(100) NOT NULL,
+    payee_bank_code VARCHAR(10) NOT NULL REFERENCES banks(bank_code),
+    payer_bank_code VARCHAR(10) NOT NULL REFERENCES banks(bank_code),
+    amount_paisa BIGINT NOT NULL CHECK (amount_paisa > 0),
+    transaction_type VARCHAR(20) NOT NULL CHECK (transaction_type IN ('P2P', 'P2M')),
+    description TEXT,
+    status VARCHAR(10) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'APPROVED', 'DECLINED', 'FAILED', 'EXPIRED')),
+    error_code VARCHAR(50),
+    error_message TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    delivered_at TIMESTAMP,
+    responded_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_collect_requests_pending_expiry ON collect_requests (expires_at)
+    WHERE status = 'PENDING';
+CREATE INDEX idx_collect_requests_payer_vpa ON collect_requests (payer_vpa);
@@ -0,0 +1,23 @@
+-- Persisted transaction saga progress
+-- Migration: 008_saga_state.sql
+--
+-- A transaction is a saga: debit the payer, credit the payee, or reverse the
+-- debit if the credit fails. Each step is recorded before and after its bank
+-- call, so a saga interrupted by a crash can be picked up by another
+-- instance. The saga recovery worker resumes sagas not updated for a while:
+-- it sends an unconfirmed bank call again under the same transaction ID,
+-- which banks process at most once, and goes on from there.
+
+CREATE TABLE saga_state (
+    transaction_id VARCHAR(50) PRIMARY KEY REFERENCES transactions(transaction_id),
+    step VARCHAR(20) NOT NULL CHECK (step IN (
+        'STARTED', 'DEBIT_PENDING', 'DEBIT_DONE', 'CREDIT_PENDING', 'CREDIT_DONE',
+        'COMPENSATING', 'COMPLETED', 'COMPENSATED', 'FAILED'
+    )),
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_saga_state_in_flight ON saga_state (updated_at)
+    WHERE step NOT IN ('COMPLETED', 'COMPENSATED', 'FAILED');
@@ -0,0 +1,30 @@
+-- Multi-currency transactions
+-- Migration: 009_multi_currency.sql
+--
+-- A transaction may be made in a currency other than the one banks settle
+-- in. amount_paisa and currency stay the amount as requested, in minor units
+-- of its currency; settled_amount_paisa and settled_currency are what the
+-- payer is debited and the payee credited, converted at fx_rate. The
+-- cross-currency fee, fx_fee_paisa, is in the settled currency and counts
+-- towards total_fee_paisa. Transactions made before this migration were all
+-- in INR and settled as requested.
+
+ALTER TABLE transactions
+    ADD COLUMN settled_amount_paisa BIGINT CHECK (settled_amount_paisa > 0),
+    ADD COLUMN settled_currency VARCHAR(3),
+    ADD COLUMN fx_rate NUMERIC(20, 10) CHECK (fx_rate > 0),
+    ADD COLUMN fx_fee_paisa BIGINT NOT NULL DEFAULT 0 CHECK (fx_fee_paisa >= 0);
+
+UPDATE transactions
+SET currency = COALESCE(currency, 'INR'),
+    settled_amount_paisa = amount_paisa,
+    settled_currency = COALESCE(currency, 'INR');
+
+ALTER TABLE transactions
+    ALTER COLUMN currency SET NOT NULL,
+    ALTER COLUMN settled_amount_paisa SET NOT NULL,
+    ALTER COLUMN settled_currency SET NOT NULL,
+    ADD CONSTRAINT valid_currency CHECK (currency ~ '^[A-Z]{3}$' AND settled_currency ~ '^[A-Z]{3}$'),
+    ADD CONSTRAINT valid_fx_rate CHECK ((currency = settled_currency) = (fx_rate IS NULL)),
+    DROP CONSTRAINT valid_fee_calculation,
+    ADD CONSTRAINT valid_fee_calculation CHECK (total_fee_paisa = switch_fee_paisa + bank_fee_paisa + fx_fee_paisa);
@@ -0,0 +1,38 @@
+-- Configurable fee rules
+-- Migration: 010_fee_rules.sql
+--
+-- Each transaction is charged a switch fee and a bank fee, each worked out
+-- from the most specific rule of its fee_type that matches it:
+--   * bank_code, if set, must be the payer's bank; NULL matches every bank
+--   * transaction_type, if set, must be the transaction's; NULL matches all
+--   * the settled amount must fall in the slab [min_amount_paisa,
+--     max_amount_paisa); a NULL max_amount_paisa has no upper bound
+-- A rule for the payer's bank beats one for any bank, and then one for the
+-- transaction type beats one for any type. The fee is fixed_paisa plus
+-- percent_bps basis points of the amount, held between min_fee_paisa and
+-- max_fee_paisa. Without a matching rule the fee is nothing.
+--
+-- The seed rules charge what the switch charged before fees were
+-- configurable: 0.1% switch fee and 0.05% bank fee, at least a paisa each.
+
+CREATE TABLE fee_rules (
+    rule_id BIGSERIAL PRIMARY KEY,
+    fee_type VARCHAR(10) NOT NULL CHECK (fee_type IN ('SWITCH', 'BANK')),
+    bank_code VARCHAR(10) REFERENCES banks(bank_code),
+    transaction_type VARCHAR(20) CHECK (transaction_type IN ('P2P', 'P2M', 'M2P', 'REFUND')),
+    min_amount_paisa BIGINT NOT NULL DEFAULT 0 CHECK (min_amount_paisa >= 0),
+    max_amount_paisa BIGINT CHECK (max_amount_paisa > min_amount_paisa),
+    fixed_paisa BIGINT NOT NULL DEFAULT 0 CHECK (fixed_paisa >= 0),
+    percent_bps BIGINT NOT NULL DEFAULT 0 CHECK (percent_bps >= 0 AND percent_bps <= 10000),
+    min_fee_paisa BIGINT NOT NULL DEFAULT 0 CHECK (min_fee_paisa >= 0),
+    max_fee_paisa BIGINT CHECK (max_fee_paisa >= min_fee_paisa),
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_fee_rules_scope ON fee_rules (fee_type, bank_code, transaction_type);
+
+INSERT INTO fee_rules (fee_type, percent_bps, min_fee_paisa, description) VALUES
+    ('SWITCH', 10, 1, 'Default switch fee'),
+    ('BANK', 5, 1, 'Default bank fee');
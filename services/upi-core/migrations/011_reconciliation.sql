@@ -0,0 +1,48 @@
+-- Reconciliation of switch records against the banks'
+-- Migration: 011_reconciliation.sql
+--
+-- Every night the switch asks the banks for their record of each of the
+-- previous day's finished transactions and compares them with its own. A
+-- business day is a day in India. Where the two disagree it records an
+-- exception:
+--   * MISSING_DEBIT: the switch has the transaction as SUCCESS but the
+--     payer's bank has no successful debit
+--   * MISSING_CREDIT: the switch has the transaction as SUCCESS but the
+--     payee's bank has no successful credit
+--   * ORPHAN_DEBIT: the payer's bank debited a transaction the switch has
+--     as unsuccessful, and has no successful reversal of it
+--   * AMOUNT_MISMATCH: a bank debited or credited another amount than the
+--     switch settled
+-- A day can be reconciled again, which replaces its exceptions. A run
+-- claims its day by setting it RUNNING, so instances never reconcile the
+-- same day at once.
+
+CREATE TABLE recon_runs (
+    business_date DATE PRIMARY KEY,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED')),
+    transactions_checked INTEGER NOT NULL DEFAULT 0,
+    -- Transactions a bank could not be asked about, e.g. because it was down
+    transactions_unverified INTEGER NOT NULL DEFAULT 0,
+    exceptions_found INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE TABLE recon_exceptions (
+    exception_id BIGSERIAL PRIMARY KEY,
+    business_date DATE NOT NULL REFERENCES recon_runs(business_date),
+    transaction_id VARCHAR(50) NOT NULL REFERENCES transactions(transaction_id),
+    exception_type VARCHAR(20) NOT NULL CHECK (exception_type IN (
+        'MISSING_DEBIT', 'MISSING_CREDIT', 'ORPHAN_DEBIT', 'AMOUNT_MISMATCH'
+    )),
+    bank_code VARCHAR(10) NOT NULL,
+    switch_status VARCHAR(20) NOT NULL,
+    -- NOT_FOUND if the bank has no record of the transaction
+    bank_status VARCHAR(20) NOT NULL,
+    switch_amount_paisa BIGINT NOT NULL,
+    bank_amount_paisa BIGINT NOT NULL DEFAULT 0,
+    details TEXT,
+    detected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (business_date, transaction_id, exception_type, bank_code)
+);
@@ -0,0 +1,36 @@
+-- Bank onboarding and lifecycle
+-- Migration: 012_bank_onboarding.sql
+--
+-- A bank registered by one operator waits in PENDING_APPROVAL until another
+-- operator approves it, making it ACTIVE, or rejects it. Operators can then
+-- suspend it, take it out of service, or schedule maintenance windows: a
+-- bank is put in MAINTENANCE when one of its windows starts and made ACTIVE
+-- again when it ends. maintenance_window_id is the window a bank is in
+-- MAINTENANCE for, NULL if it was put there by hand. Every change is
+-- recorded in audit_logs.
+
+ALTER TABLE banks DROP CONSTRAINT IF EXISTS banks_status_check;
+ALTER TABLE banks
+    ADD CONSTRAINT banks_status_check CHECK (status IN (
+        'PENDING_APPROVAL', 'ACTIVE', 'INACTIVE', 'MAINTENANCE', 'SUSPENDED', 'REJECTED'
+    )),
+    ADD COLUMN status_reason TEXT,
+    ADD COLUMN registered_by VARCHAR(100),
+    ADD COLUMN approved_by VARCHAR(100),
+    ADD COLUMN approved_at TIMESTAMP,
+    ADD COLUMN maintenance_window_id BIGINT;
+
+CREATE TABLE bank_maintenance_windows (
+    window_id BIGSERIAL PRIMARY KEY,
+    bank_code VARCHAR(10) NOT NULL REFERENCES banks(bank_code),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    reason TEXT NOT NULL,
+    created_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_bank_maintenance_windows_bank ON bank_maintenance_windows (bank_code, starts_at)
+    WHERE cancelled_at IS NULL;
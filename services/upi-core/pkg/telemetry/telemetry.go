@@ -2,7 +2,13 @@ package telemetry
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/jaeger"
@@ -96,7 +102,25 @@ func initMetrics(res *resource.Resource, cfg config.TelemetryConfig) (func() err
 	// Set global meter provider
 	otel.SetMeterProvider(mp)
 
+	// Serve the metrics for scraping. The exporter registers with the
+	// default Prometheus registry, which promhttp serves.
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.MetricsPort))
+	if err != nil {
+		mp.Shutdown(context.Background())
+		return nil, fmt.Errorf("failed to listen on metrics port: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Metrics server stopped: %v\n", err)
+		}
+	}()
+
 	return func() error {
-		return mp.Shutdown(context.Background())
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return errors.Join(server.Shutdown(ctx), mp.Shutdown(ctx))
 	}, nil
 }
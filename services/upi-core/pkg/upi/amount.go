@@ -0,0 +1,73 @@
+package upi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxAmountPaisa is ₹5,00,000, the highest per-transaction limit NPCI sets
+// for any UPI category. Lower per-category limits are the banks' to apply.
+const MaxAmountPaisa int64 = 50_000_000
+
+// ValidateAmountPaisa checks an amount is positive and within
+// MaxAmountPaisa.
+func ValidateAmountPaisa(paisa int64) error {
+	if paisa <= 0 {
+		return errors.New("amount must be positive")
+	}
+	if paisa > MaxAmountPaisa {
+		return fmt.Errorf("amount exceeds the UPI limit of %s rupees", FormatRupees(MaxAmountPaisa))
+	}
+	return nil
+}
+
+// ParseRupees parses a rupee amount such as "150", "150.5" or "150.50"
+// into paisa. Signs, exponents, grouping and more than two decimal places
+// are rejected rather than rounded, and the result must pass
+// ValidateAmountPaisa.
+func ParseRupees(s string) (int64, error) {
+	rupees, fraction, hasPoint := strings.Cut(s, ".")
+	if rupees == "" || !isDigits(rupees) {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if hasPoint && (fraction == "" || len(fraction) > 2 || !isDigits(fraction)) {
+		return 0, fmt.Errorf("invalid amount %q: at most two decimal places", s)
+	}
+	// Anything longer overflows int64 paisa and is far past the limit anyway
+	if len(strings.TrimLeft(rupees, "0")) > 12 {
+		return 0, fmt.Errorf("amount %q exceeds the UPI limit", s)
+	}
+
+	var paisa int64
+	for i := 0; i < len(rupees); i++ {
+		paisa = paisa*10 + int64(rupees[i]-'0')
+	}
+	paisa *= 100
+	for i, scale := 0, int64(10); i < len(fraction); i, scale = i+1, scale/10 {
+		paisa += int64(fraction[i]-'0') * scale
+	}
+	if err := ValidateAmountPaisa(paisa); err != nil {
+		return 0, err
+	}
+	return paisa, nil
+}
+
+// FormatRupees formats paisa as rupees with two decimal places, the form
+// ParseRupees reads back.
+func FormatRupees(paisa int64) string {
+	sign, magnitude := "", uint64(paisa)
+	if paisa < 0 {
+		sign, magnitude = "-", -magnitude
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, magnitude/100, magnitude%100)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
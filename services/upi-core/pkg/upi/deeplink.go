@@ -0,0 +1,105 @@
+package upi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DeepLink is a upi://pay link, the payload of UPI QR codes and intents:
+//
+//	upi://pay?pa=shop@hdfc&pn=Corner%20Shop&am=150.00&cu=INR&tn=Order%2042
+type DeepLink struct {
+	// PayeeVPA is pa, the only required parameter.
+	PayeeVPA VPA
+	// PayeeName is pn.
+	PayeeName string
+	// AmountPaisa is am; zero leaves the amount to the payer.
+	AmountPaisa int64
+	// Currency is cu, empty or INR.
+	Currency string
+	// Note is tn, the transaction note shown to the payer.
+	Note string
+	// TransactionRef is tr, the payee's reference for the payment.
+	TransactionRef string
+	// MerchantCode is mc, the payee's four-digit merchant category code.
+	MerchantCode string
+}
+
+// deepLinkParams are the parameters DeepLink understands, in the order
+// String writes them. Others are ignored, as UPI apps ignore them.
+var deepLinkParams = []string{"pa", "pn", "am", "cu", "tn", "tr", "mc"}
+
+// ParseDeepLink parses a upi://pay link. A parameter given twice is an
+// error rather than first-wins, since apps disagree on which one they pay.
+func ParseDeepLink(s string) (*DeepLink, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UPI link: %w", err)
+	}
+	if !strings.EqualFold(u.Scheme, "upi") || !strings.EqualFold(u.Host, "pay") || strings.Trim(u.Path, "/") != "" {
+		return nil, errors.New("UPI link must be upi://pay")
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UPI link query: %w", err)
+	}
+	for _, name := range deepLinkParams {
+		if len(query[name]) > 1 {
+			return nil, fmt.Errorf("UPI link has %s more than once", name)
+		}
+	}
+
+	if query.Get("pa") == "" {
+		return nil, errors.New("UPI link has no payee VPA (pa)")
+	}
+	payee, err := ParseVPA(query.Get("pa"))
+	if err != nil {
+		return nil, fmt.Errorf("UPI link payee: %w", err)
+	}
+	link := &DeepLink{
+		PayeeVPA:       payee,
+		PayeeName:      query.Get("pn"),
+		Currency:       query.Get("cu"),
+		Note:           query.Get("tn"),
+		TransactionRef: query.Get("tr"),
+		MerchantCode:   query.Get("mc"),
+	}
+	if am := query.Get("am"); am != "" {
+		if link.AmountPaisa, err = ParseRupees(am); err != nil {
+			return nil, fmt.Errorf("UPI link amount: %w", err)
+		}
+	}
+	if link.Currency != "" && link.Currency != "INR" {
+		return nil, fmt.Errorf("UPI link currency %q is not INR", link.Currency)
+	}
+	if link.MerchantCode != "" && (len(link.MerchantCode) != 4 || !isDigits(link.MerchantCode)) {
+		return nil, fmt.Errorf("UPI link merchant code %q is not four digits", link.MerchantCode)
+	}
+	return link, nil
+}
+
+// String formats the link with its parameters in a fixed order, so
+// ParseDeepLink(l.String()) gives l back.
+func (l *DeepLink) String() string {
+	values := map[string]string{
+		"pa": l.PayeeVPA.String(),
+		"pn": l.PayeeName,
+		"cu": l.Currency,
+		"tn": l.Note,
+		"tr": l.TransactionRef,
+		"mc": l.MerchantCode,
+	}
+	if l.AmountPaisa != 0 {
+		values["am"] = FormatRupees(l.AmountPaisa)
+	}
+
+	var query []string
+	for _, name := range deepLinkParams {
+		if v := values[name]; v != "" {
+			query = append(query, name+"="+url.QueryEscape(v))
+		}
+	}
+	return "upi://pay?" + strings.Join(query, "&")
+}
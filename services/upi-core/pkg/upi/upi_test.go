@@ -0,0 +1,184 @@
+package upi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseVPA(t *testing.T) {
+	tests := []struct {
+		in    string
+		valid bool
+	}{
+		{"alice@hdfc", true},
+		{"a@sbi", true},
+		{"alice.99_x-y@okhdfcbank", true},
+		{"9876543210@ybl", true},
+		{"", false},
+		{"alice", false},
+		{"@hdfc", false},
+		{"alice@", false},
+		{"alice@h", false},
+		{"alice@hd@fc", false},
+		{".alice@hdfc", false},
+		{"al ice@hdfc", false},
+		{"alice@hdfc.bank", false},
+		{"ålice@hdfc", false},
+		{"alice@ｈｄｆｃ", false},
+		{strings.Repeat("a", 64) + "@hdfc", true},
+		{strings.Repeat("a", 65) + "@hdfc", false},
+	}
+	for _, tt := range tests {
+		v, err := ParseVPA(tt.in)
+		if (err == nil) != tt.valid {
+			t.Errorf("ParseVPA(%q) error = %v, want valid %v", tt.in, err, tt.valid)
+			continue
+		}
+		if tt.valid && v.String() != tt.in {
+			t.Errorf("ParseVPA(%q).String() = %q", tt.in, v.String())
+		}
+	}
+}
+
+func TestParseRupees(t *testing.T) {
+	tests := []struct {
+		in    string
+		paisa int64
+		valid bool
+	}{
+		{"150", 15000, true},
+		{"150.5", 15050, true},
+		{"150.05", 15005, true},
+		{"0.01", 1, true},
+		{"500000", MaxAmountPaisa, true},
+		{"500000.01", 0, false},
+		{"0", 0, false},
+		{"0.00", 0, false},
+		{"-1", 0, false},
+		{"+1", 0, false},
+		{"1.001", 0, false},
+		{"1.", 0, false},
+		{".5", 0, false},
+		{"1e3", 0, false},
+		{"1,000", 0, false},
+		{"١٢٣", 0, false},
+		{"99999999999999999999", 0, false},
+		{"000000000000000000001", 100, true},
+	}
+	for _, tt := range tests {
+		paisa, err := ParseRupees(tt.in)
+		if (err == nil) != tt.valid || paisa != tt.paisa {
+			t.Errorf("ParseRupees(%q) = %d, %v; want %d, valid %v", tt.in, paisa, err, tt.paisa, tt.valid)
+		}
+	}
+}
+
+func TestParseDeepLink(t *testing.T) {
+	link, err := ParseDeepLink("upi://pay?pa=shop@hdfc&pn=Corner%20Shop&am=150.50&cu=INR&tn=Order+42&tr=ORD42&mc=5411&mode=02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &DeepLink{
+		PayeeVPA:       VPA{Handle: "shop", PSP: "hdfc"},
+		PayeeName:      "Corner Shop",
+		AmountPaisa:    15050,
+		Currency:       "INR",
+		Note:           "Order 42",
+		TransactionRef: "ORD42",
+		MerchantCode:   "5411",
+	}
+	if !reflect.DeepEqual(link, want) {
+		t.Fatalf("ParseDeepLink = %+v, want %+v", link, want)
+	}
+	if got := link.String(); got != "upi://pay?pa=shop%40hdfc&pn=Corner+Shop&am=150.50&cu=INR&tn=Order+42&tr=ORD42&mc=5411" {
+		t.Fatalf("String = %s", got)
+	}
+
+	for _, bad := range []string{
+		"",
+		"https://pay?pa=shop@hdfc",
+		"upi://collect?pa=shop@hdfc",
+		"upi://pay",
+		"upi://pay?pn=Shop",
+		"upi://pay?pa=shop",
+		"upi://pay?pa=shop@hdfc&pa=thief@sbi",
+		"upi://pay?pa=shop@hdfc&am=-10",
+		"upi://pay?pa=shop@hdfc&am=10.001",
+		"upi://pay?pa=shop@hdfc&am=1e9",
+		"upi://pay?pa=shop@hdfc&cu=USD",
+		"upi://pay?pa=shop@hdfc&mc=54",
+		"upi://pay?pa=shop@hdfc;am=10",
+		"upi://pay?pa=%zz",
+	} {
+		if link, err := ParseDeepLink(bad); err == nil {
+			t.Errorf("ParseDeepLink(%q) = %+v, want error", bad, link)
+		}
+	}
+}
+
+func FuzzParseVPA(f *testing.F) {
+	for _, seed := range []string{"alice@hdfc", "a.b-c_d@okaxis", "", "@", "a@@b", "ålice@hdfc", "alice@ｈｄｆｃ", "alice\x00@hdfc", strings.Repeat("a", 100) + "@hdfc"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseVPA(s)
+		if err != nil {
+			return
+		}
+		if v.String() != s {
+			t.Fatalf("ParseVPA(%q).String() = %q", s, v.String())
+		}
+		for _, r := range s {
+			if r >= utf8.RuneSelf {
+				t.Fatalf("ParseVPA accepted non-ASCII %q", s)
+			}
+		}
+	})
+}
+
+func FuzzParseRupees(f *testing.F) {
+	for _, seed := range []string{"1", "0.01", "150.50", "500000", "500000.01", "-1", "1e308", "NaN", "9223372036854775807", "१००"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		paisa, err := ParseRupees(s)
+		if err != nil {
+			return
+		}
+		if ValidateAmountPaisa(paisa) != nil {
+			t.Fatalf("ParseRupees(%q) = %d, outside the UPI limits", s, paisa)
+		}
+		if again, err := ParseRupees(FormatRupees(paisa)); err != nil || again != paisa {
+			t.Fatalf("ParseRupees(FormatRupees(%d)) = %d, %v", paisa, again, err)
+		}
+	})
+}
+
+func FuzzParseDeepLink(f *testing.F) {
+	for _, seed := range []string{
+		"upi://pay?pa=shop@hdfc&pn=Corner%20Shop&am=150.50&cu=INR",
+		"upi://pay?pa=shop@hdfc&pn=%E0%A4%A6%E0%A5%81%E0%A4%95%E0%A4%BE%E0%A4%A8&tn=%F0%9F%8D%95",
+		"UPI://PAY?pa=shop@hdfc&am=0.01&mc=5411&tr=x",
+		"upi://pay?pa=shop@hdfc&am=-100",
+		"upi://pay?pa=shop@hdfc&am=99999999999999999999",
+		"upi://pay?pa=a@b&pa=c@d",
+		"upi://pay?pa=shop%40hdfc&pn=%ff%fe",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		link, err := ParseDeepLink(s)
+		if err != nil {
+			return
+		}
+		again, err := ParseDeepLink(link.String())
+		if err != nil {
+			t.Fatalf("ParseDeepLink(%q) of %q: %v", link.String(), s, err)
+		}
+		if !reflect.DeepEqual(again, link) {
+			t.Fatalf("round trip of %q = %+v, want %+v", s, again, link)
+		}
+	})
+}
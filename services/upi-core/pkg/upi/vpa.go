@@ -0,0 +1,58 @@
+// Package upi parses the strings UPI identifies payees and amounts with:
+// virtual payment addresses, rupee amounts and upi://pay deep links.
+package upi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	maxHandleLen = 64
+	maxPSPLen    = 64
+)
+
+// VPA is a virtual payment address, handle@psp, e.g. alice.99@hdfc.
+type VPA struct {
+	Handle string
+	PSP    string
+}
+
+// ParseVPA validates s as a VPA. The handle is 1-64 ASCII letters, digits,
+// dots, hyphens or underscores starting with a letter or digit; the PSP
+// is 2-64 ASCII letters or digits. Case is kept as given.
+func ParseVPA(s string) (VPA, error) {
+	handle, psp, ok := strings.Cut(s, "@")
+	if !ok {
+		return VPA{}, errors.New("VPA must be handle@psp")
+	}
+	if handle == "" || len(handle) > maxHandleLen {
+		return VPA{}, fmt.Errorf("VPA handle must be 1-%d characters", maxHandleLen)
+	}
+	if !isAlnum(handle[0]) {
+		return VPA{}, errors.New("VPA handle must start with a letter or digit")
+	}
+	for i := 0; i < len(handle); i++ {
+		if c := handle[i]; !isAlnum(c) && c != '.' && c != '-' && c != '_' {
+			return VPA{}, fmt.Errorf("VPA handle has invalid character %q", c)
+		}
+	}
+	if len(psp) < 2 || len(psp) > maxPSPLen {
+		return VPA{}, fmt.Errorf("VPA PSP must be 2-%d characters", maxPSPLen)
+	}
+	for i := 0; i < len(psp); i++ {
+		if !isAlnum(psp[i]) {
+			return VPA{}, fmt.Errorf("VPA PSP has invalid character %q", psp[i])
+		}
+	}
+	return VPA{Handle: handle, PSP: psp}, nil
+}
+
+func (v VPA) String() string {
+	return v.Handle + "@" + v.PSP
+}
+
+func isAlnum(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
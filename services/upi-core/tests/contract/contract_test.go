@@ -0,0 +1,109 @@
+// Package contract checks upi-core's proto against the contracts its
+// consumers record in shared/contracts.
+package contract
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	_ "upi-core/pkg/pb"
+)
+
+const contractsDir = "../../../../shared/contracts"
+
+type contract struct {
+	Consumer     string        `json:"consumer"`
+	Provider     string        `json:"provider"`
+	Service      string        `json:"service"`
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Description string          `json:"description"`
+	Method      string          `json:"method"`
+	Request     json.RawMessage `json:"request"`
+	Response    json.RawMessage `json:"response"`
+	Wire        struct {
+		Request  []byte `json:"request"`
+		Response []byte `json:"response"`
+	} `json:"wire"`
+}
+
+// TestConsumerContracts fails when a change to upi_core.proto breaks a call a
+// consumer relies on: a removed or renamed RPC or field, or a field whose
+// number or type changed under the recorded wire encoding.
+func TestConsumerContracts(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(contractsDir, "*", "contract.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var c contract
+		if err := json.Unmarshal(raw, &c); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if c.Provider != "upi-core" {
+			continue
+		}
+		found = true
+		t.Run(c.Consumer, func(t *testing.T) {
+			verify(t, &c)
+		})
+	}
+	if !found {
+		t.Fatalf("no contracts for upi-core under %s", contractsDir)
+	}
+}
+
+func verify(t *testing.T, c *contract) {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(c.Service))
+	if err != nil {
+		t.Fatalf("upi-core does not serve %s: %v", c.Service, err)
+	}
+	service := desc.(protoreflect.ServiceDescriptor)
+
+	for _, in := range c.Interactions {
+		in := in
+		t.Run(in.Description, func(t *testing.T) {
+			method := service.Methods().ByName(protoreflect.Name(in.Method))
+			if method == nil {
+				t.Fatalf("%s has no RPC %s", c.Service, in.Method)
+			}
+			checkMessage(t, "request", method.Input(), in.Request, in.Wire.Request)
+			checkMessage(t, "response", method.Output(), in.Response, in.Wire.Response)
+		})
+	}
+}
+
+// checkMessage decodes the JSON fixture strictly and checks the consumer's
+// binary encoding decodes to the same message.
+func checkMessage(t *testing.T, what string, desc protoreflect.MessageDescriptor, fixture json.RawMessage, wire []byte) {
+	t.Helper()
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := mt.New().Interface()
+	if err := protojson.Unmarshal(fixture, want); err != nil {
+		t.Fatalf("%s does not match %s: %v", what, desc.FullName(), err)
+	}
+	got := mt.New().Interface()
+	if err := proto.Unmarshal(wire, got); err != nil {
+		t.Fatalf("%s wire encoding: %v", what, err)
+	}
+	if !proto.Equal(want, got) {
+		t.Errorf("%s wire encoding decodes to %v, want %v", what, got, want)
+	}
+}
@@ -0,0 +1,183 @@
+package logging
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultBufferSize    = 1000
+	defaultFlushInterval = 5 * time.Second
+)
+
+// droppedTotal counts log entries that were never written, by reason:
+// "buffer_full" when the async buffer overflowed, "sampled" when sampling
+// dropped them. It is not registered anywhere by this package; see
+// DroppedCollector.
+var droppedTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "logger_dropped_total",
+		Help: "Total number of log entries dropped before being written",
+	},
+	[]string{"reason"},
+)
+
+// DroppedCollector returns the collector counting dropped log entries as
+// logger_dropped_total. Services using the metrics facade expose it with
+// metrics.Registry.RegisterLogging, which adds the service prefix and
+// labels; others register it with their own registry.
+func DroppedCollector() prometheus.Collector {
+	return droppedTotal
+}
+
+// asyncCore puts a bounded buffer in front of a zap core so callers never
+// wait on encoding or I/O. When the buffer is full, entries are dropped
+// rather than blocking. Entries above error level bypass the buffer, after
+// flushing it, because the process may be about to exit.
+type asyncCore struct {
+	zapcore.Core
+	sink *asyncSink
+}
+
+type asyncSink struct {
+	queue   chan asyncItem
+	stop    chan struct{}
+	done    chan struct{}
+	mu      sync.RWMutex
+	closed  bool
+	dropped atomic.Uint64
+}
+
+type asyncItem struct {
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zapcore.Field
+	// flushed is set on sync markers instead of an entry.
+	flushed chan error
+}
+
+// newAsyncCore wraps inner and starts the writer goroutine, which also
+// syncs inner every flushInterval.
+func newAsyncCore(inner zapcore.Core, bufferSize int, flushInterval time.Duration) *asyncCore {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+
+	sink := &asyncSink{
+		queue: make(chan asyncItem, bufferSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go sink.run(inner, flushInterval)
+	return &asyncCore{Core: inner, sink: sink}
+}
+
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &asyncCore{Core: c.Core.With(fields), sink: c.sink}
+}
+
+func (c *asyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *asyncCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level > zapcore.ErrorLevel {
+		c.Sync()
+		return c.Core.Write(ent, fields)
+	}
+
+	s := c.sink
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return c.Core.Write(ent, fields)
+	}
+
+	select {
+	// The caller may reuse fields once Write returns
+	case s.queue <- asyncItem{core: c.Core, entry: ent, fields: append([]zapcore.Field(nil), fields...)}:
+	default:
+		s.dropped.Add(1)
+		droppedTotal.WithLabelValues("buffer_full").Inc()
+	}
+	return nil
+}
+
+// Sync waits until everything buffered so far has been written and synced.
+func (c *asyncCore) Sync() error {
+	s := c.sink
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return c.Core.Sync()
+	}
+	flushed := make(chan error, 1)
+	s.queue <- asyncItem{core: c.Core, flushed: flushed}
+	s.mu.RUnlock()
+	return <-flushed
+}
+
+// Close flushes the buffer and stops the writer goroutine. Later writes go
+// straight to the wrapped core.
+func (c *asyncCore) Close() error {
+	s := c.sink
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+	return c.Core.Sync()
+}
+
+// Dropped returns how many entries were dropped because the buffer was full.
+func (c *asyncCore) Dropped() uint64 {
+	return c.sink.dropped.Load()
+}
+
+func (s *asyncSink) run(inner zapcore.Core, flushInterval time.Duration) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case item := <-s.queue:
+			s.write(item)
+		case <-ticker.C:
+			_ = inner.Sync()
+		case <-s.stop:
+			for {
+				select {
+				case item := <-s.queue:
+					s.write(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *asyncSink) write(item asyncItem) {
+	if item.flushed != nil {
+		item.flushed <- item.core.Sync()
+		return
+	}
+	_ = item.core.Write(item.entry, item.fields)
+}
@@ -0,0 +1,185 @@
+package logging
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// recordingCore records writes and syncs in the order they reach it. If gate
+// is set, writes signal on entered and then wait for gate to be closed.
+type recordingCore struct {
+	zapcore.LevelEnabler
+	gate    chan struct{}
+	entered chan struct{}
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func newRecordingCore() *recordingCore {
+	return &recordingCore{LevelEnabler: zapcore.DebugLevel}
+}
+
+// newGatedCore returns a recording core whose writes block until the
+// returned function is called.
+func newGatedCore() (*recordingCore, func()) {
+	c := newRecordingCore()
+	c.gate = make(chan struct{})
+	c.entered = make(chan struct{}, 100)
+	return c, func() { close(c.gate) }
+}
+
+func (c *recordingCore) With([]zapcore.Field) zapcore.Core { return c }
+
+func (c *recordingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *recordingCore) Write(ent zapcore.Entry, _ []zapcore.Field) error {
+	if c.gate != nil {
+		c.entered <- struct{}{}
+		<-c.gate
+	}
+	c.record(ent.Message)
+	return nil
+}
+
+func (c *recordingCore) Sync() error {
+	c.record("sync")
+	return nil
+}
+
+func (c *recordingCore) record(call string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, call)
+}
+
+func (c *recordingCore) Calls() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.calls...)
+}
+
+func writeEntry(t *testing.T, c zapcore.Core, level zapcore.Level, msg string) {
+	t.Helper()
+	if err := c.Write(zapcore.Entry{Level: level, Message: msg}, nil); err != nil {
+		t.Fatalf("Write(%q): %v", msg, err)
+	}
+}
+
+func TestAsyncCoreDropsWhenFull(t *testing.T) {
+	inner, release := newGatedCore()
+	core := newAsyncCore(inner, 2, time.Hour)
+
+	// The writer goroutine takes the first entry and blocks in inner, so
+	// the next two fill the buffer and the rest are dropped
+	writeEntry(t, core, zapcore.InfoLevel, "held")
+	<-inner.entered
+
+	tests := []struct {
+		msg     string
+		dropped uint64
+	}{
+		{"buffered 1", 0},
+		{"buffered 2", 0},
+		{"dropped 1", 1},
+		{"dropped 2", 2},
+	}
+	for _, tt := range tests {
+		writeEntry(t, core, zapcore.InfoLevel, tt.msg)
+		if got := core.Dropped(); got != tt.dropped {
+			t.Fatalf("after %q: Dropped() = %d, want %d", tt.msg, got, tt.dropped)
+		}
+	}
+
+	release()
+	if err := core.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	want := []string{"held", "buffered 1", "buffered 2", "sync"}
+	if got := inner.Calls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("inner calls = %q, want %q", got, want)
+	}
+}
+
+func TestAsyncCoreOrdering(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps func(t *testing.T, core *asyncCore)
+		want  []string
+	}{
+		{
+			name: "sync after buffered writes",
+			steps: func(t *testing.T, core *asyncCore) {
+				writeEntry(t, core, zapcore.InfoLevel, "a")
+				writeEntry(t, core, zapcore.WarnLevel, "b")
+				if err := core.Sync(); err != nil {
+					t.Fatalf("Sync: %v", err)
+				}
+			},
+			want: []string{"a", "b", "sync"},
+		},
+		{
+			name: "derived core shares buffer",
+			steps: func(t *testing.T, core *asyncCore) {
+				writeEntry(t, core, zapcore.InfoLevel, "a")
+				writeEntry(t, core.With(nil), zapcore.InfoLevel, "b")
+				if err := core.Sync(); err != nil {
+					t.Fatalf("Sync: %v", err)
+				}
+			},
+			want: []string{"a", "b", "sync"},
+		},
+		{
+			name: "above error flushes first",
+			steps: func(t *testing.T, core *asyncCore) {
+				writeEntry(t, core, zapcore.InfoLevel, "a")
+				writeEntry(t, core, zapcore.DPanicLevel, "panic")
+			},
+			want: []string{"a", "sync", "panic"},
+		},
+		{
+			name: "close drains buffer",
+			steps: func(t *testing.T, core *asyncCore) {
+				writeEntry(t, core, zapcore.InfoLevel, "a")
+				writeEntry(t, core, zapcore.InfoLevel, "b")
+				if err := core.Close(); err != nil {
+					t.Fatalf("Close: %v", err)
+				}
+			},
+			want: []string{"a", "b", "sync"},
+		},
+		{
+			name: "writes after close go straight through",
+			steps: func(t *testing.T, core *asyncCore) {
+				if err := core.Close(); err != nil {
+					t.Fatalf("Close: %v", err)
+				}
+				writeEntry(t, core, zapcore.InfoLevel, "late")
+				if err := core.Sync(); err != nil {
+					t.Fatalf("Sync: %v", err)
+				}
+				if err := core.Close(); err != nil {
+					t.Fatalf("second Close: %v", err)
+				}
+			},
+			want: []string{"sync", "late", "sync"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := newRecordingCore()
+			core := newAsyncCore(inner, 10, time.Hour)
+			tt.steps(t, core)
+			if got := inner.Calls(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("inner calls = %q, want %q", got, tt.want)
+			}
+			core.Close()
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// genesisHash is the PrevHash of the first record in a chain.
+var genesisHash = strings.Repeat("0", 64)
+
+// ErrAuditChainBroken is returned by VerifyAuditLog when a record was
+// modified, removed, reordered or inserted, or a line is not exactly the
+// record it decodes to.
+var ErrAuditChainBroken = errors.New("audit chain broken")
+
+// AuditEvent is what the caller records: who did what to which resource.
+type AuditEvent struct {
+	Actor    string                 `json:"actor"`
+	Action   string                 `json:"action"`
+	Resource string                 `json:"resource"`
+	Outcome  string                 `json:"outcome"` // e.g. "success", "denied", "failed"
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// AuditRecord is one link of the hash chain. Hash covers every other field,
+// including PrevHash, so changing any record breaks every later link.
+type AuditRecord struct {
+	Seq       uint64 `json:"seq"`
+	Timestamp string `json:"timestamp"`
+	Service   string `json:"service"`
+	RequestID string `json:"request_id,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
+	Actor     string `json:"actor"`
+	Action    string `json:"action"`
+	Resource  string `json:"resource"`
+	Outcome   string `json:"outcome"`
+	// Details is kept as raw JSON so verification rehashes exactly the bytes
+	// that were written, whatever numbers they contain.
+	Details  json.RawMessage `json:"details,omitempty"`
+	PrevHash string          `json:"prev_hash"`
+	Hash     string          `json:"hash"`
+}
+
+// computeHash hashes the record's JSON form with Hash cleared. Struct fields
+// and map keys marshal in a fixed order, so the encoding is canonical.
+func (r AuditRecord) computeHash() (string, error) {
+	r.Hash = ""
+	raw, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditLogger writes hash-chained audit records to an append-only file,
+// fsyncing each one before Record returns. It is separate from the regular
+// logger: audit records are never sampled, buffered or dropped.
+type AuditLogger struct {
+	mu       sync.Mutex
+	f        *os.File
+	size     int64 // End of the last complete record
+	broken   error // Set when a failed write could not be rolled back
+	service  string
+	seq      uint64
+	lastHash string
+	now      func() time.Time
+}
+
+// NewAuditLogger opens, or creates, the audit file at path and resumes the
+// chain from its last record. A partial record left at the end by a crash
+// mid-write was never acknowledged and is truncated away. The existing file
+// is not otherwise verified; run VerifyAuditLog for that.
+func NewAuditLogger(path, service string) (*AuditLogger, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+
+	a := &AuditLogger{f: f, service: service, lastHash: genesisHash, now: time.Now}
+	last, size, err := lastAuditRecord(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	a.size = size
+	if last != nil {
+		a.seq = last.Seq
+		a.lastHash = last.Hash
+	}
+	return a, nil
+}
+
+// Record appends event to the chain, with request context from ctx, and
+// returns the written record.
+func (a *AuditLogger) Record(ctx context.Context, event AuditEvent) (AuditRecord, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.broken != nil {
+		return AuditRecord{}, a.broken
+	}
+
+	record := AuditRecord{
+		Seq:       a.seq + 1,
+		Timestamp: a.now().UTC().Format(time.RFC3339Nano),
+		Service:   a.service,
+		Actor:     event.Actor,
+		Action:    event.Action,
+		Resource:  event.Resource,
+		Outcome:   event.Outcome,
+		PrevHash:  a.lastHash,
+	}
+	if len(event.Details) > 0 {
+		details, err := json.Marshal(event.Details)
+		if err != nil {
+			return AuditRecord{}, fmt.Errorf("encode audit details: %w", err)
+		}
+		record.Details = details
+	}
+	if logCtx, ok := FromContext(ctx); ok {
+		record.RequestID = logCtx.RequestID
+		record.TraceID = logCtx.TraceID
+	}
+
+	hash, err := record.computeHash()
+	if err != nil {
+		return AuditRecord{}, fmt.Errorf("hash audit record: %w", err)
+	}
+	record.Hash = hash
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return AuditRecord{}, fmt.Errorf("encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+	if _, err := a.f.Write(line); err != nil {
+		return AuditRecord{}, a.rollback(fmt.Errorf("write audit record: %w", err))
+	}
+	if err := a.f.Sync(); err != nil {
+		return AuditRecord{}, a.rollback(fmt.Errorf("sync audit record: %w", err))
+	}
+
+	a.size += int64(len(line))
+	a.seq = record.Seq
+	a.lastHash = record.Hash
+	return record, nil
+}
+
+// rollback truncates whatever part of a failed record reached the file, so
+// the next record does not follow a partial line or repeat a sequence
+// number already written. If that fails too the logger refuses further
+// records rather than corrupt the chain.
+func (a *AuditLogger) rollback(err error) error {
+	if terr := a.f.Truncate(a.size); terr != nil {
+		a.broken = fmt.Errorf("audit log unusable after failed write: %w", terr)
+		return errors.Join(err, a.broken)
+	}
+	return err
+}
+
+// Head returns the sequence number and hash of the last record, which can
+// be published elsewhere to detect truncation of the file.
+func (a *AuditLogger) Head() (uint64, string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.seq, a.lastHash
+}
+
+// Close closes the audit file.
+func (a *AuditLogger) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.f.Close()
+}
+
+// lastAuditRecord reads the final record of an audit file, or nil if it is
+// empty, and returns the offset just past it. Bytes after the last newline
+// are a record torn by a crash and are truncated.
+func lastAuditRecord(f *os.File) (*AuditRecord, int64, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, fmt.Errorf("read audit log: %w", err)
+	}
+	var (
+		last []byte
+		size int64
+		torn bool
+	)
+	reader := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			torn = len(line) > 0
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("read audit log: %w", err)
+		}
+		size += int64(len(line))
+		if line := bytes.TrimSpace(line); len(line) > 0 {
+			last = append(last[:0], line...)
+		}
+	}
+	if torn {
+		if err := f.Truncate(size); err != nil {
+			return nil, 0, fmt.Errorf("truncate partial audit record: %w", err)
+		}
+	}
+	if last == nil {
+		return nil, size, nil
+	}
+
+	var record AuditRecord
+	if err := json.Unmarshal(last, &record); err != nil {
+		return nil, 0, fmt.Errorf("parse last audit record: %w", err)
+	}
+	return &record, size, nil
+}
+
+// VerifyAuditLog checks every link of the chain in r and returns the number
+// of records and the head hash. On failure the error wraps
+// ErrAuditChainBroken and names the first bad record.
+func VerifyAuditLog(r io.Reader) (int, string, error) {
+	prevHash := genesisHash
+	var expectSeq uint64 = 1
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	count := 0
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var record AuditRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return count, prevHash, fmt.Errorf("%w: line %d: %v", ErrAuditChainBroken, line, err)
+		}
+		// The hash covers the record as re-encoded, so the line must be
+		// exactly that encoding: anything else, such as an extra or
+		// duplicated key, would go unnoticed by the hash.
+		canonical, err := json.Marshal(record)
+		if err != nil {
+			return count, prevHash, err
+		}
+		if !bytes.Equal(canonical, raw) {
+			return count, prevHash, fmt.Errorf("%w: line %d: not in canonical form", ErrAuditChainBroken, line)
+		}
+		if record.Seq != expectSeq {
+			return count, prevHash, fmt.Errorf("%w: line %d: seq %d, want %d", ErrAuditChainBroken, line, record.Seq, expectSeq)
+		}
+		if record.PrevHash != prevHash {
+			return count, prevHash, fmt.Errorf("%w: seq %d: prev_hash does not match previous record", ErrAuditChainBroken, record.Seq)
+		}
+		hash, err := record.computeHash()
+		if err != nil {
+			return count, prevHash, err
+		}
+		if hash != record.Hash {
+			return count, prevHash, fmt.Errorf("%w: seq %d: content does not match hash", ErrAuditChainBroken, record.Seq)
+		}
+
+		prevHash = record.Hash
+		expectSeq++
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, prevHash, fmt.Errorf("read audit log: %w", err)
+	}
+	return count, prevHash, nil
+}
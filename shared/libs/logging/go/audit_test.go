@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeAuditLog records n events in a fresh file and returns its lines.
+func writeAuditLog(t *testing.T, n int) (string, []string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	a, err := NewAuditLogger(path, "upi-core")
+	if err != nil {
+		t.Fatalf("NewAuditLogger: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		_, err := a.Record(context.Background(), AuditEvent{
+			Actor: "ops@suuupra", Action: "refund", Resource: "txn/42", Outcome: "success",
+			Details: map[string]interface{}{"amount_paise": 125000, "note": "<b>&</b>"},
+		})
+		if err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return path, strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+}
+
+func TestVerifyAuditLogDetectsTampering(t *testing.T) {
+	_, lines := writeAuditLog(t, 3)
+
+	tests := []struct {
+		name   string
+		tamper func([]string) []string
+	}{
+		{"modified field", func(l []string) []string {
+			l[1] = strings.Replace(l[1], `"outcome":"success"`, `"outcome":"denied"`, 1)
+			return l
+		}},
+		{"unknown key", func(l []string) []string {
+			l[1] = strings.Replace(l[1], `{"seq"`, `{"approved_by":"mallory","seq"`, 1)
+			return l
+		}},
+		{"duplicate key", func(l []string) []string {
+			l[1] = strings.Replace(l[1], `{"seq"`, `{"outcome":"denied","seq"`, 1)
+			return l
+		}},
+		{"reformatted details", func(l []string) []string {
+			l[1] = strings.Replace(l[1], `"details":{`, `"details":{ `, 1)
+			return l
+		}},
+		{"removed record", func(l []string) []string { return append(l[:1], l[2:]...) }},
+		{"reordered records", func(l []string) []string {
+			l[1], l[2] = l[2], l[1]
+			return l
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := tt.tamper(append([]string(nil), lines...))
+			_, _, err := VerifyAuditLog(strings.NewReader(strings.Join(tampered, "\n") + "\n"))
+			if !errors.Is(err, ErrAuditChainBroken) {
+				t.Fatalf("err = %v, want ErrAuditChainBroken", err)
+			}
+		})
+	}
+
+	count, _, err := VerifyAuditLog(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+	if err != nil || count != 3 {
+		t.Fatalf("untouched log: count=%d err=%v, want 3 records", count, err)
+	}
+}
+
+func TestAuditLoggerTruncatesTornRecord(t *testing.T) {
+	path, lines := writeAuditLog(t, 2)
+
+	// A crash halfway through writing the third record
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	f.WriteString(`{"seq":3,"timestamp":"2026-`)
+	f.Close()
+
+	a, err := NewAuditLogger(path, "upi-core")
+	if err != nil {
+		t.Fatalf("NewAuditLogger on a torn file: %v", err)
+	}
+	if seq, _ := a.Head(); seq != 2 {
+		t.Fatalf("resumed at seq %d, want 2", seq)
+	}
+	if _, err := a.Record(context.Background(), AuditEvent{Actor: "ops", Action: "refund", Outcome: "success"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	a.Close()
+
+	raw, _ := os.ReadFile(path)
+	if !bytes.HasPrefix(raw, []byte(strings.Join(lines, "\n")+"\n")) {
+		t.Fatal("truncation removed complete records")
+	}
+	if count, _, err := VerifyAuditLog(bytes.NewReader(raw)); err != nil || count != 3 {
+		t.Fatalf("after resume: count=%d err=%v, want 3 valid records", count, err)
+	}
+}
+
+func TestAuditLoggerRefusesRecordsAfterFailedRollback(t *testing.T) {
+	path, _ := writeAuditLog(t, 1)
+	a, err := NewAuditLogger(path, "upi-core")
+	if err != nil {
+		t.Fatalf("NewAuditLogger: %v", err)
+	}
+	// Neither the write nor the truncate can succeed on a closed file
+	a.f.Close()
+
+	event := AuditEvent{Actor: "ops", Action: "refund", Outcome: "success"}
+	if _, err := a.Record(context.Background(), event); err == nil {
+		t.Fatal("Record succeeded on a closed file")
+	}
+	if seq, _ := a.Head(); seq != 1 {
+		t.Fatalf("failed record advanced the chain to seq %d", seq)
+	}
+	if _, err := a.Record(context.Background(), event); err == nil || !strings.Contains(err.Error(), "unusable") {
+		t.Fatalf("second Record err = %v, want the logger marked unusable", err)
+	}
+}
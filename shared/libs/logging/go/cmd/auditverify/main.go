@@ -0,0 +1,43 @@
+// Command auditverify checks the hash chain of an audit log written by
+// logging.AuditLogger.
+//
+//	auditverify [-head <hash>] <file>
+//
+// It exits non-zero if any record was altered, removed or inserted. Passing
+// the head hash recorded elsewhere also detects truncation.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	logging "github.com/suuupra/logging/go"
+)
+
+func main() {
+	head := flag.String("head", "", "expected hash of the last record")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: auditverify [-head <hash>] <file>")
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer f.Close()
+
+	count, last, err := logging.VerifyAuditLog(f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "FAIL after %d valid records: %v\n", count, err)
+		os.Exit(1)
+	}
+	if *head != "" && *head != last {
+		fmt.Fprintf(os.Stderr, "FAIL: head is %s, want %s (log truncated or rewritten)\n", last, *head)
+		os.Exit(1)
+	}
+	fmt.Printf("OK: %d records, head %s\n", count, last)
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpanEvent attaches an error entry to the active span as a "log"
+// event, so the trace view shows the error without a trip to the logs.
+func recordSpanEvent(ctx context.Context, entry *LogEntry) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(entry.Data)+2)
+	attrs = append(attrs,
+		attribute.String("log.severity", entry.Level),
+		attribute.String("log.message", entry.Message),
+	)
+	for k, v := range entry.Data {
+		attrs = append(attrs, spanAttribute("log."+k, v))
+	}
+	if entry.Error != nil {
+		attrs = append(attrs,
+			attribute.String("exception.type", entry.Error.Type),
+			attribute.String("exception.message", entry.Error.Message),
+		)
+	}
+	span.AddEvent("log", trace.WithAttributes(attrs...))
+}
+
+func spanAttribute(key string, v interface{}) attribute.KeyValue {
+	switch val := v.(type) {
+	case string:
+		return attribute.String(key, val)
+	case bool:
+		return attribute.Bool(key, val)
+	case int:
+		return attribute.Int(key, val)
+	case int64:
+		return attribute.Int64(key, val)
+	case float64:
+		return attribute.Float64(key, val)
+	default:
+		return attribute.String(key, fmt.Sprint(val))
+	}
+}
+
+// spanContextFromIDs rebuilds a span context from the hex IDs carried in an
+// entry, so exported log records link to their trace.
+func spanContextFromIDs(traceID, spanID string) (trace.SpanContext, bool) {
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: tid, SpanID: sid, Remote: true}), true
+}
@@ -0,0 +1,61 @@
+// Package echologging provides Echo middleware for the Suuupra logger.
+package echologging
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	logging "github.com/suuupra/logging/go"
+)
+
+const loggerKey = "suuupra_logger"
+
+// Middleware creates a request-scoped logger for every request, echoes the
+// request ID back in X-Request-ID, and emits one wide event per request with
+// its status and latency. Handlers get the logger with Logger(c), and the
+// request context carries it for InfoCtx and friends.
+func Middleware(logger logging.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			ctx, reqLogger, logCtx := logging.StartHTTPRequest(req.Context(), logger, req.Header)
+			c.SetRequest(req.WithContext(ctx))
+			c.Set(loggerKey, reqLogger)
+			c.Response().Header().Set(logging.HeaderRequestID, logCtx.RequestID)
+
+			err := next(c)
+			if err != nil {
+				// Let Echo write the error response now so the logged
+				// status matches what the client sees.
+				c.Error(err)
+			}
+
+			url := c.Path()
+			if url == "" {
+				url = req.URL.Path
+			}
+			reqLogger.LogRequest(&logging.HTTPRequest{
+				Method:    req.Method,
+				URL:       url,
+				IP:        c.RealIP(),
+				UserAgent: req.UserAgent(),
+			}, &logging.HTTPResponse{
+				StatusCode: c.Response().Status,
+				Duration:   time.Since(start),
+			})
+			return nil
+		}
+	}
+}
+
+// Logger returns the request-scoped logger set by Middleware. It panics if
+// the middleware is not installed, as ginlogging.Logger does.
+func Logger(c echo.Context) logging.Logger {
+	logger, ok := c.Get(loggerKey).(logging.Logger)
+	if !ok {
+		panic("echologging: Logger called without Middleware installed")
+	}
+	return logger
+}
@@ -0,0 +1,33 @@
+package echologging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	logging "github.com/suuupra/logging/go"
+)
+
+func TestLogger(t *testing.T) {
+	logger := logging.New(logging.Config{Service: "upi-core", Level: logging.ErrorLevel})
+
+	e := echo.New()
+	e.Use(Middleware(logger))
+	var got logging.Logger
+	e.GET("/", func(c echo.Context) error {
+		got = Logger(c)
+		return nil
+	})
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got == nil {
+		t.Fatal("no request-scoped logger with the middleware installed")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Logger did not panic without the middleware")
+		}
+	}()
+	Logger(e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder()))
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Interfaces that classified errors (see the errs package) implement. They
+// are matched anywhere in the error chain, so wrapping with fmt.Errorf's %w
+// keeps the classification.
+type (
+	codedError     interface{ ErrorCode() string }
+	categorized    interface{ ErrorCategory() string }
+	retryableError interface{ Retryable() bool }
+	stackTracer    interface{ StackTrace() string }
+)
+
+// newErrorInfo describes err for a log entry.
+func newErrorInfo(err error) *ErrorInfo {
+	info := &ErrorInfo{
+		Type:    fmt.Sprintf("%T", err),
+		Message: err.Error(),
+	}
+	var coded codedError
+	if errors.As(err, &coded) {
+		info.Code = coded.ErrorCode()
+	}
+	var cat categorized
+	if errors.As(err, &cat) {
+		info.Category = cat.ErrorCategory()
+	}
+	var retry retryableError
+	if errors.As(err, &retry) {
+		info.Retryable = retry.Retryable()
+	}
+	var st stackTracer
+	if errors.As(err, &st) {
+		info.Stack = st.StackTrace()
+	}
+	return info
+}
+
+// errorCode returns the code of a classified error, or "".
+func errorCode(err error) string {
+	var coded codedError
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return ""
+}
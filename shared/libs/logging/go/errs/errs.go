@@ -0,0 +1,153 @@
+// Package errs provides classified errors: each carries a machine-readable
+// code, a category, a retryable flag and the stack where it was first
+// created or wrapped. The logger renders them into ErrorInfo automatically
+// when passed with logging.Error.
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Category groups error codes by how callers should react.
+type Category string
+
+const (
+	CategoryValidation   Category = "validation"   // Bad input; do not retry
+	CategoryNotFound     Category = "not_found"    // Missing resource
+	CategoryConflict     Category = "conflict"     // State conflict, e.g. duplicate
+	CategoryUnauthorized Category = "unauthorized" // Authentication or authorization failed
+	CategoryDependency   Category = "dependency"   // A downstream service or store failed
+	CategoryTimeout      Category = "timeout"      // Deadline exceeded
+	CategoryInternal     Category = "internal"     // Bug or unexpected state
+)
+
+const maxStackDepth = 32
+
+// Error is a classified error. Create it with New or Wrap.
+type Error struct {
+	code      string
+	category  Category
+	retryable bool
+	message   string
+	cause     error
+	stack     []uintptr
+}
+
+// Option adjusts an Error at creation.
+type Option func(*Error)
+
+// Retryable marks the error as safe to retry.
+func Retryable() Option {
+	return func(e *Error) { e.retryable = true }
+}
+
+// Message sets the message shown before the cause.
+func Message(format string, args ...interface{}) Option {
+	return func(e *Error) { e.message = fmt.Sprintf(format, args...) }
+}
+
+// New creates an error and captures the caller's stack.
+func New(code string, category Category, message string, opts ...Option) error {
+	e := &Error{code: code, category: category, message: message, stack: callers()}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Wrap classifies err. The stack is captured only if no error in the chain
+// already carries one, so it always points at the first wrap. Wrap returns
+// nil if err is nil.
+func Wrap(err error, code string, category Category, opts ...Option) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{code: code, category: category, cause: err}
+	var inner *Error
+	if errors.As(err, &inner) {
+		e.stack = inner.stack
+	} else {
+		e.stack = callers()
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.message != "" && e.cause != nil:
+		return e.message + ": " + e.cause.Error()
+	case e.cause != nil:
+		return e.cause.Error()
+	default:
+		return e.message
+	}
+}
+
+func (e *Error) Unwrap() error { return e.cause }
+
+// ErrorCode returns the error's code.
+func (e *Error) ErrorCode() string { return e.code }
+
+// ErrorCategory returns the error's category.
+func (e *Error) ErrorCategory() string { return string(e.category) }
+
+// Retryable reports whether the operation may be retried.
+func (e *Error) Retryable() bool { return e.retryable }
+
+// StackTrace formats the captured stack, one "function\n\tfile:line" pair
+// per frame.
+func (e *Error) StackTrace() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// Code returns the code of the outermost classified error in err's chain,
+// or "" if there is none.
+func Code(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return ""
+}
+
+// CategoryOf returns the category of the outermost classified error in
+// err's chain, or CategoryInternal if there is none.
+func CategoryOf(err error) Category {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.category
+	}
+	return CategoryInternal
+}
+
+// IsRetryable reports whether the outermost classified error in err's chain
+// is retryable.
+func IsRetryable(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.retryable
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers, callers, and New or Wrap
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
@@ -0,0 +1,208 @@
+package logging
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldKind records which member of a Field holds its value.
+type fieldKind uint8
+
+const (
+	anyField fieldKind = iota // Value; also fields built as literals
+	stringField
+	intField
+	int64Field
+	float64Field
+	boolField
+)
+
+// value returns the field's value boxed, as stored in LogEntry.Data.
+func (f Field) value() interface{} {
+	switch f.kind {
+	case stringField:
+		return f.str
+	case intField:
+		return int(f.num)
+	case int64Field:
+		return f.num
+	case float64Field:
+		return math.Float64frombits(uint64(f.num))
+	case boolField:
+		return f.num == 1
+	default:
+		return f.Value
+	}
+}
+
+// zapField converts f without boxing typed values.
+func (f Field) zapField() zap.Field {
+	switch f.kind {
+	case stringField:
+		return zap.String(f.Key, f.str)
+	case intField, int64Field:
+		return zap.Int64(f.Key, f.num)
+	case float64Field:
+		return zap.Float64(f.Key, math.Float64frombits(uint64(f.num)))
+	case boolField:
+		return zap.Bool(f.Key, f.num == 1)
+	default:
+		return zap.Any(f.Key, f.Value)
+	}
+}
+
+// maxPooledFields keeps unusually large field buffers out of the pool.
+const maxPooledFields = 64
+
+var (
+	zapFieldPool = sync.Pool{New: func() interface{} {
+		buf := make([]zap.Field, 0, 16)
+		return &buf
+	}}
+	logEntryPool = sync.Pool{New: func() interface{} {
+		return &LogEntry{Data: make(map[string]interface{})}
+	}}
+)
+
+func getZapFields() *[]zap.Field {
+	return zapFieldPool.Get().(*[]zap.Field)
+}
+
+func putZapFields(buf *[]zap.Field) {
+	if cap(*buf) > maxPooledFields {
+		return
+	}
+	clear(*buf)
+	*buf = (*buf)[:0]
+	zapFieldPool.Put(buf)
+}
+
+// getLogEntry returns a reset entry whose Data map is empty but allocated.
+func getLogEntry() *LogEntry {
+	return logEntryPool.Get().(*LogEntry)
+}
+
+// releaseLogEntry returns entry to the pool once it has been written;
+// processors must not keep a reference to it.
+func releaseLogEntry(entry *LogEntry) {
+	data := entry.Data
+	if len(data) > maxPooledFields {
+		data = nil
+	}
+	clear(data)
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	*entry = LogEntry{Data: data}
+	logEntryPool.Put(entry)
+}
+
+// appendContextFields appends the context fields written with every entry.
+func appendContextFields(fields []zap.Field, lc *LogContext) []zap.Field {
+	fields = append(fields,
+		zap.String("service", lc.Service),
+		zap.String("environment", lc.Environment),
+		zap.String("request_id", lc.RequestID),
+		zap.String("trace_id", lc.TraceID),
+		zap.String("user_id", lc.UserID),
+	)
+	if lc.SpanID != "" {
+		fields = append(fields, zap.String("span_id", lc.SpanID))
+	}
+	return fields
+}
+
+// withContextFields returns z with the logger's context fields encoded once,
+// so the fast path only encodes the fields of each call.
+func withContextFields(z *zap.Logger, lc LogContext) *zap.Logger {
+	return z.With(appendContextFields(nil, &lc)...)
+}
+
+func zapLevel(level LogLevel) zapcore.Level {
+	switch level {
+	case TraceLevel, DebugLevel:
+		return zapcore.DebugLevel
+	case InfoLevel:
+		return zapcore.InfoLevel
+	case WarnLevel:
+		return zapcore.WarnLevel
+	case ErrorLevel:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.FatalLevel
+	}
+}
+
+// canWriteFast reports whether an entry can skip building a LogEntry: nothing
+// needs to see or rewrite it (masking, processors), no span changes its
+// context fields, and no field carries an error to classify. Request
+// context is handled by fastZap.
+func (l *SuuupraLogger) canWriteFast(ctx context.Context, fields []Field) bool {
+	if l.config.MaskPII || !l.procs.empty() {
+		return false
+	}
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return false
+	}
+	for i := range fields {
+		if fields[i].kind == anyField {
+			if _, ok := fields[i].Value.(error); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fastZap returns a zap logger whose pre-encoded context fields are what
+// the entry would be written with, or nil if there is none. Request
+// middleware stores the request's LogContext and a logger built from it in
+// ctx, so usually either this logger already carries the request's fields
+// or the stored one does.
+func (l *SuuupraLogger) fastZap(ctx context.Context, req *LogContext) *zap.Logger {
+	if req == nil || encodesAs(&l.context, req, &l.context) {
+		return l.zapCtx
+	}
+	if rl, ok := ctx.Value(requestLoggerKey).(*SuuupraLogger); ok && rl.zap == l.zap && encodesAs(&l.context, req, &rl.context) {
+		return rl.zapCtx
+	}
+	return nil
+}
+
+// encodesAs reports whether enc has the context fields written for req
+// merged onto base, per mergeLogContext and appendContextFields.
+func encodesAs(base, req, enc *LogContext) bool {
+	return enc.Service == base.Service &&
+		enc.Environment == base.Environment &&
+		enc.RequestID == overlay(base.RequestID, req.RequestID) &&
+		enc.TraceID == overlay(base.TraceID, req.TraceID) &&
+		enc.SpanID == overlay(base.SpanID, req.SpanID) &&
+		enc.UserID == overlay(base.UserID, req.UserID)
+}
+
+func overlay(base, req string) string {
+	if req != "" {
+		return req
+	}
+	return base
+}
+
+// writeFast writes an entry straight to z, which carries the pre-encoded
+// context fields. Nothing is allocated for typed fields.
+func (l *SuuupraLogger) writeFast(z *zap.Logger, level LogLevel, message string, fields []Field) {
+	ce := z.Check(zapLevel(level), message)
+	if ce == nil {
+		return
+	}
+	buf := getZapFields()
+	for i := range fields {
+		*buf = append(*buf, fields[i].zapField())
+	}
+	ce.Write(*buf...)
+	putZapFields(buf)
+}
@@ -0,0 +1,53 @@
+// Package ginlogging provides Gin middleware for the Suuupra logger.
+package ginlogging
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	logging "github.com/suuupra/logging/go"
+)
+
+const loggerKey = "suuupra_logger"
+
+// Middleware creates a request-scoped logger for every request, echoes the
+// request ID back in X-Request-ID, and emits one wide event per request with
+// its status and latency. Handlers get the logger with Logger(c), and the
+// request context carries it for InfoCtx and friends.
+func Middleware(logger logging.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		ctx, reqLogger, logCtx := logging.StartHTTPRequest(c.Request.Context(), logger, c.Request.Header)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(loggerKey, reqLogger)
+		c.Header(logging.HeaderRequestID, logCtx.RequestID)
+
+		c.Next()
+
+		url := c.FullPath()
+		if url == "" {
+			url = c.Request.URL.Path
+		}
+		reqLogger.LogRequest(&logging.HTTPRequest{
+			Method:    c.Request.Method,
+			URL:       url,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}, &logging.HTTPResponse{
+			StatusCode: c.Writer.Status(),
+			Duration:   time.Since(start),
+		})
+	}
+}
+
+// Logger returns the request-scoped logger set by Middleware. It panics if
+// the middleware is not installed, as echologging.Logger does.
+func Logger(c *gin.Context) logging.Logger {
+	value, _ := c.Get(loggerKey)
+	logger, ok := value.(logging.Logger)
+	if !ok {
+		panic("ginlogging: Logger called without Middleware installed")
+	}
+	return logger
+}
@@ -0,0 +1,32 @@
+package ginlogging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	logging "github.com/suuupra/logging/go"
+)
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logging.New(logging.Config{Service: "upi-core", Level: logging.ErrorLevel})
+
+	router := gin.New()
+	router.Use(Middleware(logger))
+	var got logging.Logger
+	router.GET("/", func(c *gin.Context) { got = Logger(c) })
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got == nil {
+		t.Fatal("no request-scoped logger with the middleware installed")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Logger did not panic without the middleware")
+		}
+	}()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	Logger(c)
+}
@@ -0,0 +1,122 @@
+// Package grpclogging provides gRPC server interceptors for the Suuupra
+// logger.
+package grpclogging
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	logging "github.com/suuupra/logging/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor creates a request-scoped logger for every call,
+// returns the request ID in the x-request-id response header, and emits one
+// wide event per call with its gRPC code and latency. Handlers get the
+// logger with logging.LoggerFromContext.
+func UnaryServerInterceptor(logger logging.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx, reqLogger := startCall(ctx, logger)
+
+		resp, err := handler(ctx, req)
+
+		logCall(ctx, reqLogger, info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls. The
+// wide event is emitted when the stream ends.
+func StreamServerInterceptor(logger logging.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, reqLogger := startCall(ss.Context(), logger)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+
+		logCall(ctx, reqLogger, info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func startCall(ctx context.Context, logger logging.Logger) (context.Context, logging.Logger) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	ctx, reqLogger, logCtx := logging.StartRequest(ctx, logger, metadataCarrier(md), get)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(logging.HeaderRequestID), logCtx.RequestID))
+	return ctx, reqLogger
+}
+
+// logCall records the call as an HTTP-shaped wide event: the method is
+// "GRPC" and the URL the full method name. The gRPC code goes in its own
+// grpc_code dimension, by name, because the HTTP status field is omitted
+// when zero and OK is code 0.
+func logCall(ctx context.Context, logger logging.Logger, method string, err error, duration time.Duration) {
+	code := status.Code(err)
+	info := logging.HTTPInfo{
+		Method:     "GRPC",
+		URL:        method,
+		StatusCode: int(code),
+		Duration:   float64(duration.Milliseconds()),
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			info.UserAgent = ua[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		info.IP = p.Addr.String()
+	}
+
+	logger.EmitWideEvent(ctx, logging.WideEvent{
+		EventType: "http_request",
+		Dimensions: map[string]interface{}{
+			"http":      info,
+			"grpc_code": code.String(),
+		},
+	})
+}
+
+// serverStream overrides the context of a stream so handlers see the
+// request-scoped one.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// metadataCarrier adapts incoming metadata for trace context extraction.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
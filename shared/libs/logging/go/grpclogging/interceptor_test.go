@@ -0,0 +1,57 @@
+package grpclogging
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	logging "github.com/suuupra/logging/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type captureSink struct {
+	mu     sync.Mutex
+	events []logging.WideEvent
+}
+
+func (s *captureSink) Write(_ context.Context, events []logging.WideEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *captureSink) Close() error { return nil }
+
+func TestUnaryInterceptorRecordsGRPCCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, "OK"},
+		{status.Error(codes.NotFound, "missing"), "NotFound"},
+	}
+	for _, tt := range tests {
+		sink := &captureSink{}
+		logger := logging.New(logging.Config{
+			Service:    "upi-core",
+			Level:      logging.InfoLevel,
+			WideEvents: logging.WideEventConfig{Sinks: []logging.WideEventSink{sink}},
+		})
+		interceptor := UnaryServerInterceptor(logger)
+
+		_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/upi.v1.Payments/Pay"},
+			func(ctx context.Context, req interface{}) (interface{}, error) { return nil, tt.err })
+		// Delivers the buffered event; syncing stderr may fail under test
+		_ = logger.(*logging.SuuupraLogger).Close()
+
+		if len(sink.events) != 1 {
+			t.Fatalf("%s: %d wide events, want 1", tt.want, len(sink.events))
+		}
+		if got := sink.events[0].Dimensions["grpc_code"]; got != tt.want {
+			t.Fatalf("grpc_code = %v, want %s", got, tt.want)
+		}
+	}
+}
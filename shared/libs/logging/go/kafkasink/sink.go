@@ -0,0 +1,62 @@
+// Package kafkasink delivers Suuupra wide events to a Kafka topic.
+package kafkasink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	logging "github.com/suuupra/logging/go"
+)
+
+// Sink writes each wide event as one JSON message, keyed by request ID so a
+// request's events stay in order on one partition. The schema version
+// travels in the schema_version header.
+type Sink struct {
+	writer *kafka.Writer
+}
+
+// New creates a sink producing to topic on brokers.
+func New(brokers []string, topic string) *Sink {
+	return &Sink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireOne,
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}
+}
+
+func (s *Sink) Write(ctx context.Context, events []logging.WideEvent) error {
+	messages := make([]kafka.Message, len(events))
+	for i, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("encode wide event: %w", err)
+		}
+		key := event.RequestID
+		if key == "" {
+			key = event.EventID
+		}
+		messages[i] = kafka.Message{
+			Key:   []byte(key),
+			Value: value,
+			Headers: []kafka.Header{
+				{Key: "schema_version", Value: []byte(event.SchemaVersion)},
+				{Key: "event_type", Value: []byte(event.EventType)},
+			},
+		}
+	}
+	if err := s.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("produce wide events: %w", err)
+	}
+	return nil
+}
+
+func (s *Sink) Close() error {
+	return s.writer.Close()
+}
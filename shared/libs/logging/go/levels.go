@@ -0,0 +1,287 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// ParseLevel parses a level name such as "debug" or "WARN".
+func ParseLevel(s string) (LogLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "TRACE":
+		return TraceLevel, nil
+	case "DEBUG":
+		return DebugLevel, nil
+	case "INFO":
+		return InfoLevel, nil
+	case "WARN", "WARNING":
+		return WarnLevel, nil
+	case "ERROR":
+		return ErrorLevel, nil
+	case "FATAL":
+		return FatalLevel, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler so levels read as names in
+// JSON.
+func (l LogLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// LevelController holds the minimum level of a logger and everything derived
+// from it, with optional overrides per LogContext.Component. Changes take
+// effect immediately.
+type LevelController struct {
+	level      atomic.Int32
+	mu         sync.Mutex
+	components atomic.Pointer[map[string]LogLevel]
+}
+
+// LevelState is the JSON form of a LevelController's settings.
+type LevelState struct {
+	Level      LogLevel            `json:"level"`
+	Components map[string]LogLevel `json:"components"`
+}
+
+// NewLevelController creates a controller with the given default level and
+// no overrides.
+func NewLevelController(level LogLevel) *LevelController {
+	c := &LevelController{}
+	c.level.Store(int32(level))
+	c.components.Store(&map[string]LogLevel{})
+	return c
+}
+
+// Enabled reports whether an entry at level from component should be logged.
+func (c *LevelController) Enabled(component string, level LogLevel) bool {
+	return level >= c.Level(component)
+}
+
+// Level returns the effective level for component.
+func (c *LevelController) Level(component string) LogLevel {
+	if component != "" {
+		if level, ok := (*c.components.Load())[component]; ok {
+			return level
+		}
+	}
+	return LogLevel(c.level.Load())
+}
+
+// SetLevel changes the default level.
+func (c *LevelController) SetLevel(level LogLevel) {
+	c.level.Store(int32(level))
+}
+
+// SetComponentLevel overrides the level for one component.
+func (c *LevelController) SetComponentLevel(component string, level LogLevel) {
+	c.update(func(m map[string]LogLevel) { m[component] = level })
+}
+
+// ClearComponentLevel removes a component override.
+func (c *LevelController) ClearComponentLevel(component string) {
+	c.update(func(m map[string]LogLevel) { delete(m, component) })
+}
+
+// State returns the current default level and overrides.
+func (c *LevelController) State() LevelState {
+	components := make(map[string]LogLevel)
+	for name, level := range *c.components.Load() {
+		components[name] = level
+	}
+	return LevelState{Level: LogLevel(c.level.Load()), Components: components}
+}
+
+// Apply replaces the default level and all overrides at once.
+func (c *LevelController) Apply(state LevelState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	components := make(map[string]LogLevel, len(state.Components))
+	for name, level := range state.Components {
+		components[name] = level
+	}
+	c.level.Store(int32(state.Level))
+	c.components.Store(&components)
+}
+
+// update copies the override map, so readers never take a lock.
+func (c *LevelController) update(fn func(map[string]LogLevel)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current := *c.components.Load()
+	next := make(map[string]LogLevel, len(current)+1)
+	for name, level := range current {
+		next[name] = level
+	}
+	fn(next)
+	c.components.Store(&next)
+}
+
+// ServeHTTP exposes the controller for operators. GET returns the current
+// state. PUT replaces the default level and, when "components" is present,
+// all overrides:
+//
+//	{"level": "DEBUG", "components": {"payments": "DEBUG"}}
+//
+// The handler performs no authentication; mount it on an internal admin
+// port or behind the service's admin auth.
+func (c *LevelController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var req struct {
+			Level      *LogLevel           `json:"level"`
+			Components map[string]LogLevel `json:"components"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		state := c.State()
+		if req.Level != nil {
+			state.Level = *req.Level
+		}
+		if req.Components != nil {
+			state.Components = req.Components
+		}
+		c.Apply(state)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c.State())
+}
+
+// Environment variables read by ReloadFromEnv.
+const (
+	EnvLogLevel          = "LOG_LEVEL"           // e.g. "DEBUG"
+	EnvLogLevelOverrides = "LOG_LEVEL_OVERRIDES" // e.g. "payments=DEBUG,db=WARN"
+	EnvLogLevelFile      = "LOG_LEVEL_FILE"      // KEY=VALUE file with the two variables above
+)
+
+// ReloadFromEnv applies LOG_LEVEL and LOG_LEVEL_OVERRIDES. When
+// LOG_LEVEL_FILE is set, the values are read from that file instead, which
+// lets a mounted ConfigMap change levels without restarting the process.
+// Variables that are unset leave the current setting alone.
+func (c *LevelController) ReloadFromEnv() error {
+	vars := map[string]string{
+		EnvLogLevel:          os.Getenv(EnvLogLevel),
+		EnvLogLevelOverrides: os.Getenv(EnvLogLevelOverrides),
+	}
+	if path := os.Getenv(EnvLogLevelFile); path != "" {
+		fileVars, err := readEnvFile(path)
+		if err != nil {
+			return err
+		}
+		vars = fileVars
+	}
+
+	state := c.State()
+	if v := vars[EnvLogLevel]; v != "" {
+		level, err := ParseLevel(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvLogLevel, err)
+		}
+		state.Level = level
+	}
+	if v, ok := vars[EnvLogLevelOverrides]; ok && v != "" {
+		components, err := parseOverrides(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvLogLevelOverrides, err)
+		}
+		state.Components = components
+	}
+	c.Apply(state)
+	return nil
+}
+
+// WatchSignals reloads levels from the environment on every SIGHUP until ctx
+// is done. Reload errors are passed to onError, which may be nil.
+func (c *LevelController) WatchSignals(ctx context.Context, onError func(error)) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(sighup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sighup:
+				if err := c.ReloadFromEnv(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// parseOverrides parses "component=LEVEL" pairs separated by commas.
+func parseOverrides(s string) (map[string]LogLevel, error) {
+	components := make(map[string]LogLevel)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid override %q, want component=LEVEL", pair)
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, err
+		}
+		components[strings.TrimSpace(name)] = level
+	}
+	return components, nil
+}
+
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read level file: %w", err)
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		vars[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read level file: %w", err)
+	}
+	return vars, nil
+}
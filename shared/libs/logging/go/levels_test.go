@@ -0,0 +1,224 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want LogLevel
+		ok   bool
+	}{
+		{"trace", TraceLevel, true},
+		{"DEBUG", DebugLevel, true},
+		{" Info ", InfoLevel, true},
+		{"warn", WarnLevel, true},
+		{"WARNING", WarnLevel, true},
+		{"error", ErrorLevel, true},
+		{"fatal", FatalLevel, true},
+		{"", 0, false},
+		{"verbose", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLevelControllerHTTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		want   LevelState
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			want:   LevelState{Level: InfoLevel, Components: map[string]LogLevel{"db": WarnLevel}},
+		},
+		{
+			name:   "put level keeps overrides",
+			method: http.MethodPut,
+			body:   `{"level": "debug"}`,
+			status: http.StatusOK,
+			want:   LevelState{Level: DebugLevel, Components: map[string]LogLevel{"db": WarnLevel}},
+		},
+		{
+			name:   "put components replaces overrides",
+			method: http.MethodPut,
+			body:   `{"components": {"payments": "DEBUG"}}`,
+			status: http.StatusOK,
+			want:   LevelState{Level: InfoLevel, Components: map[string]LogLevel{"payments": DebugLevel}},
+		},
+		{
+			name:   "post",
+			method: http.MethodPost,
+			body:   `{"level": "ERROR", "components": {}}`,
+			status: http.StatusOK,
+			want:   LevelState{Level: ErrorLevel, Components: map[string]LogLevel{}},
+		},
+		{
+			name:   "unknown level",
+			method: http.MethodPut,
+			body:   `{"level": "loud"}`,
+			status: http.StatusBadRequest,
+			want:   LevelState{Level: InfoLevel, Components: map[string]LogLevel{"db": WarnLevel}},
+		},
+		{
+			name:   "malformed",
+			method: http.MethodPut,
+			body:   `{"level":`,
+			status: http.StatusBadRequest,
+			want:   LevelState{Level: InfoLevel, Components: map[string]LogLevel{"db": WarnLevel}},
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			status: http.StatusMethodNotAllowed,
+			want:   LevelState{Level: InfoLevel, Components: map[string]LogLevel{"db": WarnLevel}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewLevelController(InfoLevel)
+			c.SetComponentLevel("db", WarnLevel)
+
+			rec := httptest.NewRecorder()
+			c.ServeHTTP(rec, httptest.NewRequest(tt.method, "/log-level", strings.NewReader(tt.body)))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if got := c.State(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("state = %+v, want %+v", got, tt.want)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var body LevelState
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(body, tt.want) {
+				t.Fatalf("response = %+v, want %+v", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelControllerEnabled(t *testing.T) {
+	c := NewLevelController(WarnLevel)
+	c.SetComponentLevel("payments", DebugLevel)
+
+	tests := []struct {
+		component string
+		level     LogLevel
+		want      bool
+	}{
+		{"", InfoLevel, false},
+		{"", WarnLevel, true},
+		{"payments", DebugLevel, true},
+		{"payments", TraceLevel, false},
+		{"db", InfoLevel, false},
+	}
+	for _, tt := range tests {
+		if got := c.Enabled(tt.component, tt.level); got != tt.want {
+			t.Errorf("Enabled(%q, %v) = %v, want %v", tt.component, tt.level, got, tt.want)
+		}
+	}
+
+	c.ClearComponentLevel("payments")
+	if c.Enabled("payments", DebugLevel) {
+		t.Fatal("override still applied after ClearComponentLevel")
+	}
+}
+
+func TestReloadFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		file    string // Contents of LOG_LEVEL_FILE, if set
+		want    LevelState
+		wantErr string
+	}{
+		{
+			name: "unset keeps state",
+			want: LevelState{Level: InfoLevel, Components: map[string]LogLevel{"db": WarnLevel}},
+		},
+		{
+			name: "level and overrides",
+			env:  map[string]string{EnvLogLevel: "debug", EnvLogLevelOverrides: " payments=TRACE, ,kafka=error"},
+			want: LevelState{Level: DebugLevel, Components: map[string]LogLevel{"payments": TraceLevel, "kafka": ErrorLevel}},
+		},
+		{
+			name: "file wins over environment",
+			env:  map[string]string{EnvLogLevel: "debug"},
+			file: "# mounted from a ConfigMap\nLOG_LEVEL=\"warn\"\nLOG_LEVEL_OVERRIDES='db=error'\nnot a pair\n",
+			want: LevelState{Level: WarnLevel, Components: map[string]LogLevel{"db": ErrorLevel}},
+		},
+		{
+			name:    "bad level",
+			env:     map[string]string{EnvLogLevel: "loud"},
+			want:    LevelState{Level: InfoLevel, Components: map[string]LogLevel{"db": WarnLevel}},
+			wantErr: EnvLogLevel,
+		},
+		{
+			name:    "bad override",
+			env:     map[string]string{EnvLogLevel: "debug", EnvLogLevelOverrides: "payments"},
+			want:    LevelState{Level: InfoLevel, Components: map[string]LogLevel{"db": WarnLevel}},
+			wantErr: EnvLogLevelOverrides,
+		},
+		{
+			name:    "missing override component",
+			env:     map[string]string{EnvLogLevelOverrides: "=DEBUG"},
+			want:    LevelState{Level: InfoLevel, Components: map[string]LogLevel{"db": WarnLevel}},
+			wantErr: EnvLogLevelOverrides,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{EnvLogLevel, EnvLogLevelOverrides, EnvLogLevelFile} {
+				t.Setenv(name, tt.env[name])
+			}
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "levels.env")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("write level file: %v", err)
+				}
+				t.Setenv(EnvLogLevelFile, path)
+			}
+
+			c := NewLevelController(InfoLevel)
+			c.SetComponentLevel("db", WarnLevel)
+			err := c.ReloadFromEnv()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ReloadFromEnv: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ReloadFromEnv error = %v, want one naming %s", err, tt.wantErr)
+			}
+			if got := c.State(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("state = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReloadFromEnvMissingFile(t *testing.T) {
+	t.Setenv(EnvLogLevelFile, filepath.Join(t.TempDir(), "missing.env"))
+	if err := NewLevelController(InfoLevel).ReloadFromEnv(); err == nil {
+		t.Fatal("ReloadFromEnv succeeded with a missing level file")
+	}
+}
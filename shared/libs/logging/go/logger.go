@@ -12,9 +12,8 @@ package logging
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
-	"regexp"
-	"strings"
 	"sync"
 	"time"
 
@@ -101,11 +100,12 @@ type LogEntry struct {
 
 // ErrorInfo contains error details
 type ErrorInfo struct {
-	Type     string `json:"type"`
-	Message  string `json:"message"`
-	Stack    string `json:"stack,omitempty"`
-	Code     string `json:"code,omitempty"`
-	Category string `json:"category,omitempty"`
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+	Stack     string `json:"stack,omitempty"`
+	Code      string `json:"code,omitempty"`
+	Category  string `json:"category,omitempty"`
+	Retryable bool   `json:"retryable,omitempty"`
 }
 
 // MetricsInfo contains performance metrics
@@ -149,6 +149,8 @@ type SecurityInfo struct {
 
 // WideEvent represents a wide event for Observability 2.0
 type WideEvent struct {
+	SchemaVersion string `json:"schema_version,omitempty"`
+
 	EventID     string                 `json:"event_id"`
 	EventType   string                 `json:"event_type"`
 	Timestamp   string                 `json:"timestamp"`
@@ -192,9 +194,27 @@ type Config struct {
 	Level         LogLevel
 	Format        string // "json", "text", "pretty"
 	MaskPII       bool
-	OpenTelemetry bool
+	PII           PIIConfig // Extra masking rules; see LoadPIIConfig
+	OpenTelemetry bool      // Records error entries as events on the active span
 	InstanceID    string
 	Region        string
+	Sampling      SamplingConfig // Drops repetitive entries; zero value logs everything
+
+	// Async moves encoding and I/O off the calling goroutine. Entries that
+	// do not fit in the buffer are dropped and counted in
+	// logger_dropped_total; see DroppedCollector.
+	Async         bool
+	BufferSize    int           // Entries; default 1000
+	FlushInterval time.Duration // Default 5s
+
+	// OTLP, when set, also ships every entry to an OpenTelemetry collector.
+	OTLP *OTLPConfig
+
+	// File, when set, also writes JSON entries to a rotating local file.
+	File *FileConfig
+
+	// WideEvents routes wide events to dedicated sinks instead of the log
+	WideEvents WideEventConfig
 }
 
 // Logger is the main logger interface
@@ -207,6 +227,14 @@ type Logger interface {
 	Error(message string, fields ...Field)
 	Fatal(message string, fields ...Field)
 
+	// Context-aware variants pick up the LogContext stored with
+	// WithLoggerContext and the active trace span from ctx
+	TraceCtx(ctx context.Context, message string, fields ...Field)
+	DebugCtx(ctx context.Context, message string, fields ...Field)
+	InfoCtx(ctx context.Context, message string, fields ...Field)
+	WarnCtx(ctx context.Context, message string, fields ...Field)
+	ErrorCtx(ctx context.Context, message string, fields ...Field)
+
 	// Context management
 	WithContext(ctx LogContext) Logger
 	WithRequestID(requestID string) Logger
@@ -222,48 +250,77 @@ type Logger interface {
 	LogDatabaseQuery(query *DatabaseQuery)
 	LogSecurityEvent(event *SecurityEvent)
 
+	// EmitWideEvent sends event to the wide-event sinks, filling in empty
+	// identity and context fields from the logger and ctx
+	EmitWideEvent(ctx context.Context, event WideEvent)
+
+	// Processing
+	RegisterProcessor(p Processor)
+
+	// Runtime control
+	Levels() *LevelController
+	SetSampling(cfg SamplingConfig) error
+	DroppedEntries() uint64
+
 	// Lifecycle
 	Flush() error
 	Close() error
 }
 
-// Field represents a key-value pair for structured logging
+// Field represents a key-value pair for structured logging. The typed
+// constructors keep their value unboxed, so building and passing fields does
+// not allocate; Value holds the value of fields built with Any or Error.
+// Calls through the Logger interface still allocate the variadic slice, so
+// per-transaction hot paths should hold the *SuuupraLogger returned by New.
 type Field struct {
 	Key   string
 	Value interface{}
+
+	kind fieldKind
+	num  int64
+	str  string
 }
 
 // Field constructors for type safety and performance
 func String(key, val string) Field {
-	return Field{Key: key, Value: val}
+	return Field{Key: key, kind: stringField, str: val}
 }
 
 func Int(key string, val int) Field {
-	return Field{Key: key, Value: val}
+	return Field{Key: key, kind: intField, num: int64(val)}
 }
 
 func Int64(key string, val int64) Field {
-	return Field{Key: key, Value: val}
+	return Field{Key: key, kind: int64Field, num: val}
 }
 
 func Float64(key string, val float64) Field {
-	return Field{Key: key, Value: val}
+	return Field{Key: key, kind: float64Field, num: int64(math.Float64bits(val))}
 }
 
 func Bool(key string, val bool) Field {
-	return Field{Key: key, Value: val}
+	f := Field{Key: key, kind: boolField}
+	if val {
+		f.num = 1
+	}
+	return f
 }
 
 func Duration(key string, val time.Duration) Field {
-	return Field{Key: key, Value: val.Milliseconds()}
+	return Field{Key: key, kind: int64Field, num: val.Milliseconds()}
 }
 
 func Any(key string, val interface{}) Field {
 	return Field{Key: key, Value: val}
 }
 
+// Error attaches err to the entry. Its message is logged under "error", and
+// its type, code, category and stack populate the entry's ErrorInfo.
 func Error(err error) Field {
-	return Field{Key: "error", Value: err.Error()}
+	if err == nil {
+		return Field{Key: "error", Value: nil}
+	}
+	return Field{Key: "error", Value: err}
 }
 
 // Timer interface for performance measurements
@@ -314,70 +371,19 @@ type SuuupraLogger struct {
 	config  Config
 	context LogContext
 	zap     *zap.Logger
+	zapCtx  *zap.Logger // zap with the context fields pre-encoded
 	tracer  trace.Tracer
 	pii     *PIIMasker
+	sampler *Sampler
+	async   *asyncCore
+	otlp    *otlpCore
+	levels  *LevelController
+	procs   *processorChain
+	events  *wideEventEmitter
+	file    *RotatingFile
 	mu      sync.RWMutex
 }
 
-// PIIMasker handles PII masking
-type PIIMasker struct {
-	emailRegex      *regexp.Regexp
-	creditCardRegex *regexp.Regexp
-	ssnRegex        *regexp.Regexp
-	phoneRegex      *regexp.Regexp
-	piiFields       map[string]bool
-}
-
-// NewPIIMasker creates a new PII masker
-func NewPIIMasker() *PIIMasker {
-	return &PIIMasker{
-		emailRegex:      regexp.MustCompile(`\b[\w\.-]+@[\w\.-]+\.\w+\b`),
-		creditCardRegex: regexp.MustCompile(`\b\d{4}[- ]?\d{4}[- ]?\d{4}[- ]?\d{4}\b`),
-		ssnRegex:        regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
-		phoneRegex:      regexp.MustCompile(`\b\d{3}-\d{3}-\d{4}\b`),
-		piiFields: map[string]bool{
-			"password":    true,
-			"token":       true,
-			"secret":      true,
-			"key":         true,
-			"ssn":         true,
-			"credit_card": true,
-			"creditcard":  true,
-		},
-	}
-}
-
-// MaskText masks PII in text
-func (p *PIIMasker) MaskText(text string) string {
-	text = p.emailRegex.ReplaceAllString(text, "[EMAIL]")
-	text = p.creditCardRegex.ReplaceAllString(text, "[CARD]")
-	text = p.ssnRegex.ReplaceAllString(text, "[SSN]")
-	text = p.phoneRegex.ReplaceAllString(text, "[PHONE]")
-	return text
-}
-
-// MaskData masks PII in structured data
-func (p *PIIMasker) MaskData(data map[string]interface{}) map[string]interface{} {
-	if data == nil {
-		return nil
-	}
-
-	masked := make(map[string]interface{})
-	for k, v := range data {
-		key := strings.ToLower(k)
-		if p.piiFields[key] {
-			masked[k] = "[REDACTED]"
-		} else if str, ok := v.(string); ok {
-			masked[k] = p.MaskText(str)
-		} else if nested, ok := v.(map[string]interface{}); ok {
-			masked[k] = p.MaskData(nested)
-		} else {
-			masked[k] = v
-		}
-	}
-	return masked
-}
-
 // timer implements the Timer interface
 type timer struct {
 	name      string
@@ -418,11 +424,37 @@ func New(config Config) Logger {
 		zapConfig = zap.NewDevelopmentConfig()
 	}
 
+	// Production used to drop debug entries at the zap level; keep that as
+	// the floor, but filter in shouldLog so the level can be lowered at
+	// runtime.
+	level := config.Level
+	if config.Environment == "production" && level < InfoLevel {
+		level = InfoLevel
+	}
+	levels := NewLevelController(level)
+	zapConfig.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+
 	zapConfig.EncoderConfig.TimeKey = "timestamp"
 	zapConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 
 	zapLogger, _ := zapConfig.Build()
 
+	if err := levels.ReloadFromEnv(); err != nil {
+		zapLogger.Warn("Invalid log level in environment", zap.Error(err))
+	}
+
+	pii, err := NewPIIMaskerWithConfig(config.PII)
+	if err != nil {
+		zapLogger.Warn("Invalid PII config, using built-in rules", zap.Error(err))
+		pii = NewPIIMasker()
+	}
+
+	sampler, err := NewSampler(config.Sampling)
+	if err != nil {
+		zapLogger.Warn("Invalid sampling config, logging everything", zap.Error(err))
+		sampler, _ = NewSampler(SamplingConfig{})
+	}
+
 	context := LogContext{
 		Service:     config.Service,
 		Version:     config.Version,
@@ -432,48 +464,114 @@ func New(config Config) Logger {
 		Extra:       make(map[string]interface{}),
 	}
 
+	var file *RotatingFile
+	if config.File != nil {
+		file, err = NewRotatingFile(*config.File)
+		if err != nil {
+			zapLogger.Warn("File logging disabled", zap.Error(err))
+		} else {
+			fileCore := zapcore.NewCore(zapcore.NewJSONEncoder(zapConfig.EncoderConfig), file, zapConfig.Level)
+			zapLogger = zapLogger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+				return zapcore.NewTee(core, fileCore)
+			}))
+		}
+	}
+
+	var otlp *otlpCore
+	if config.OTLP != nil {
+		otlp, err = newOTLPCore(*config.OTLP, context, zapConfig.Level)
+		if err != nil {
+			zapLogger.Warn("OTLP log export disabled", zap.Error(err))
+		} else {
+			zapLogger = zapLogger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+				return zapcore.NewTee(core, otlp)
+			}))
+		}
+	}
+
+	var async *asyncCore
+	if config.Async {
+		zapLogger = zapLogger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			async = newAsyncCore(core, config.BufferSize, config.FlushInterval)
+			return async
+		}))
+	}
+
+	var events *wideEventEmitter
+	if len(config.WideEvents.Sinks) > 0 {
+		events = newWideEventEmitter(config.WideEvents, func(sink WideEventSink, err error) {
+			zapLogger.Warn("Wide event sink write failed", zap.String("sink", fmt.Sprintf("%T", sink)), zap.Error(err))
+		})
+	}
+
 	return &SuuupraLogger{
 		config:  config,
 		context: context,
 		zap:     zapLogger,
+		zapCtx:  withContextFields(zapLogger, context),
 		tracer:  otel.Tracer("suuupra-logger"),
-		pii:     NewPIIMasker(),
+		pii:     pii,
+		sampler: sampler,
+		async:   async,
+		otlp:    otlp,
+		levels:  levels,
+		procs:   newProcessorChain(),
+		events:  events,
+		file:    file,
 	}
 }
 
 func (l *SuuupraLogger) shouldLog(level LogLevel) bool {
-	return level >= l.config.Level
+	return l.levels.Enabled(l.context.Component, level)
 }
 
-func (l *SuuupraLogger) createLogEntry(level LogLevel, message string, fields []Field) LogEntry {
+// createLogEntry builds a pooled entry; release it with releaseLogEntry once
+// written.
+func (l *SuuupraLogger) createLogEntry(ctx context.Context, level LogLevel, message string, fields []Field) *LogEntry {
 	timestamp := time.Now().UTC().Format(time.RFC3339Nano)
 
-	// Extract context from current goroutine if available
-	ctx := context.Background()
+	// Request-scoped context from the caller takes precedence over the
+	// logger's own, and the active span over any stored trace ID
+	logCtx := l.context
+	if reqCtx, ok := FromContext(ctx); ok {
+		logCtx = mergeLogContext(logCtx, reqCtx)
+	}
 	if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
-		l.context.TraceID = span.SpanContext().TraceID().String()
-		l.context.SpanID = span.SpanContext().SpanID().String()
+		logCtx.TraceID = span.SpanContext().TraceID().String()
+		logCtx.SpanID = span.SpanContext().SpanID().String()
 	}
 
-	// Build data from fields
-	data := make(map[string]interface{})
+	// Build data from fields; the first error becomes the entry's ErrorInfo
+	entry := getLogEntry()
+	data := entry.Data
+	var errInfo *ErrorInfo
 	for _, field := range fields {
-		data[field.Key] = field.Value
+		if err, ok := field.Value.(error); ok {
+			if errInfo == nil {
+				errInfo = newErrorInfo(err)
+			}
+			data[field.Key] = err.Error()
+			continue
+		}
+		data[field.Key] = field.value()
 	}
 
 	// Mask PII if enabled
 	if l.config.MaskPII {
 		message = l.pii.MaskText(message)
 		data = l.pii.MaskData(data)
+		if errInfo != nil {
+			errInfo.Message = l.pii.MaskText(errInfo.Message)
+		}
 	}
 
-	return LogEntry{
-		Timestamp: timestamp,
-		Level:     level.String(),
-		Message:   message,
-		Context:   l.context,
-		Data:      data,
-	}
+	entry.Timestamp = timestamp
+	entry.Level = level.String()
+	entry.Message = message
+	entry.Context = logCtx
+	entry.Data = data
+	entry.Error = errInfo
+	return entry
 }
 
 func (l *SuuupraLogger) createWideEvent(eventType string, data map[string]interface{}) WideEvent {
@@ -495,18 +593,10 @@ func (l *SuuupraLogger) createWideEvent(eventType string, data map[string]interf
 	}
 }
 
-func (l *SuuupraLogger) writeEntry(entry LogEntry) {
-	// Convert to Zap fields for performance
-	zapFields := make([]zap.Field, 0, len(entry.Data)+10)
-
-	// Add context fields
-	zapFields = append(zapFields,
-		zap.String("service", entry.Context.Service),
-		zap.String("environment", entry.Context.Environment),
-		zap.String("request_id", entry.Context.RequestID),
-		zap.String("trace_id", entry.Context.TraceID),
-		zap.String("user_id", entry.Context.UserID),
-	)
+func (l *SuuupraLogger) writeEntry(entry *LogEntry) {
+	buf := getZapFields()
+	defer putZapFields(buf)
+	zapFields := appendContextFields(*buf, &entry.Context)
 
 	// Add data fields
 	for k, v := range entry.Data {
@@ -519,8 +609,20 @@ func (l *SuuupraLogger) writeEntry(entry LogEntry) {
 			zap.String("error_type", entry.Error.Type),
 			zap.String("error_message", entry.Error.Message),
 		)
+		if entry.Error.Code != "" {
+			zapFields = append(zapFields,
+				zap.String("error_code", entry.Error.Code),
+				zap.String("error_category", entry.Error.Category),
+				zap.Bool("error_retryable", entry.Error.Retryable),
+			)
+		}
+		if entry.Error.Stack != "" {
+			zapFields = append(zapFields, zap.String("error_stack", entry.Error.Stack))
+		}
 	}
 
+	*buf = zapFields
+
 	// Write to Zap logger
 	switch entry.Level {
 	case "TRACE", "DEBUG":
@@ -538,48 +640,83 @@ func (l *SuuupraLogger) writeEntry(entry LogEntry) {
 
 // Core logging methods
 func (l *SuuupraLogger) Trace(message string, fields ...Field) {
-	if l.shouldLog(TraceLevel) {
-		entry := l.createLogEntry(TraceLevel, message, fields)
-		l.writeEntry(entry)
-	}
+	l.log(context.Background(), TraceLevel, message, fields)
 }
 
 func (l *SuuupraLogger) Debug(message string, fields ...Field) {
-	if l.shouldLog(DebugLevel) {
-		entry := l.createLogEntry(DebugLevel, message, fields)
-		l.writeEntry(entry)
-	}
+	l.log(context.Background(), DebugLevel, message, fields)
 }
 
 func (l *SuuupraLogger) Info(message string, fields ...Field) {
-	if l.shouldLog(InfoLevel) {
-		entry := l.createLogEntry(InfoLevel, message, fields)
-		l.writeEntry(entry)
-	}
+	l.log(context.Background(), InfoLevel, message, fields)
 }
 
 func (l *SuuupraLogger) Warn(message string, fields ...Field) {
-	if l.shouldLog(WarnLevel) {
-		entry := l.createLogEntry(WarnLevel, message, fields)
-		l.writeEntry(entry)
-	}
+	l.log(context.Background(), WarnLevel, message, fields)
 }
 
 func (l *SuuupraLogger) Error(message string, fields ...Field) {
-	if l.shouldLog(ErrorLevel) {
-		entry := l.createLogEntry(ErrorLevel, message, fields)
-		l.writeEntry(entry)
-	}
+	l.log(context.Background(), ErrorLevel, message, fields)
 }
 
 func (l *SuuupraLogger) Fatal(message string, fields ...Field) {
 	if l.shouldLog(FatalLevel) {
-		entry := l.createLogEntry(FatalLevel, message, fields)
+		entry := l.createLogEntry(context.Background(), FatalLevel, message, fields)
+		l.procs.apply(entry)
 		l.writeEntry(entry)
 		os.Exit(1)
 	}
 }
 
+func (l *SuuupraLogger) TraceCtx(ctx context.Context, message string, fields ...Field) {
+	l.log(ctx, TraceLevel, message, fields)
+}
+
+func (l *SuuupraLogger) DebugCtx(ctx context.Context, message string, fields ...Field) {
+	l.log(ctx, DebugLevel, message, fields)
+}
+
+func (l *SuuupraLogger) InfoCtx(ctx context.Context, message string, fields ...Field) {
+	l.log(ctx, InfoLevel, message, fields)
+}
+
+func (l *SuuupraLogger) WarnCtx(ctx context.Context, message string, fields ...Field) {
+	l.log(ctx, WarnLevel, message, fields)
+}
+
+func (l *SuuupraLogger) ErrorCtx(ctx context.Context, message string, fields ...Field) {
+	l.log(ctx, ErrorLevel, message, fields)
+}
+
+func (l *SuuupraLogger) log(ctx context.Context, level LogLevel, message string, fields []Field) {
+	component := l.context.Component
+	reqCtx, hasReqCtx := FromContext(ctx)
+	if hasReqCtx && reqCtx.Component != "" {
+		component = reqCtx.Component
+	}
+	if !l.levels.Enabled(component, level) || !l.sampler.Allow(level, message, fields) {
+		return
+	}
+	if l.canWriteFast(ctx, fields) {
+		var req *LogContext
+		if hasReqCtx {
+			req = &reqCtx
+		}
+		if z := l.fastZap(ctx, req); z != nil {
+			l.writeFast(z, level, message, fields)
+			return
+		}
+	}
+
+	entry := l.createLogEntry(ctx, level, message, fields)
+	l.procs.apply(entry)
+	l.writeEntry(entry)
+	if l.config.OpenTelemetry && level >= ErrorLevel {
+		recordSpanEvent(ctx, entry)
+	}
+	releaseLogEntry(entry)
+}
+
 // Context management
 func (l *SuuupraLogger) WithContext(ctx LogContext) Logger {
 	l.mu.Lock()
@@ -604,10 +741,18 @@ func (l *SuuupraLogger) WithContext(ctx LogContext) Logger {
 			OrganizationID:   ctx.OrganizationID,
 			Extra:            ctx.Extra,
 		},
-		zap:    l.zap,
-		tracer: l.tracer,
-		pii:    l.pii,
+		zap:     l.zap,
+		tracer:  l.tracer,
+		pii:     l.pii,
+		sampler: l.sampler,
+		async:   l.async,
+		otlp:    l.otlp,
+		levels:  l.levels,
+		procs:   l.procs,
+		events:  l.events,
+		file:    l.file,
 	}
+	newLogger.zapCtx = withContextFields(l.zap, newLogger.context)
 
 	return newLogger
 }
@@ -654,7 +799,20 @@ func (l *SuuupraLogger) LogRequest(req *HTTPRequest, resp *HTTPResponse) {
 		},
 	})
 
-	l.Info("HTTP Request", Any("wide_event", wideEvent))
+	l.emitWideEvent(context.Background(), wideEvent, "HTTP Request")
+}
+
+func (l *SuuupraLogger) EmitWideEvent(ctx context.Context, event WideEvent) {
+	l.emitWideEvent(ctx, event, "Wide Event")
+}
+
+func (l *SuuupraLogger) emitWideEvent(ctx context.Context, event WideEvent, message string) {
+	event = l.completeWideEvent(ctx, event)
+	if l.events == nil {
+		l.InfoCtx(ctx, message, Any("wide_event", event))
+		return
+	}
+	l.events.emit(event)
 }
 
 func (l *SuuupraLogger) LogDatabaseQuery(query *DatabaseQuery) {
@@ -683,13 +841,73 @@ func (l *SuuupraLogger) LogSecurityEvent(event *SecurityEvent) {
 	)
 }
 
+// Processing
+
+// RegisterProcessor adds p to the processors run on every entry of this
+// logger and every logger derived from it, including ones derived before
+// the call.
+func (l *SuuupraLogger) RegisterProcessor(p Processor) {
+	l.procs.register(p)
+}
+
+// Runtime control
+
+// Levels returns the level controller shared by this logger and every logger
+// derived from it. Mount it as an HTTP handler or call WatchSignals on it to
+// change levels without a redeploy.
+func (l *SuuupraLogger) Levels() *LevelController {
+	return l.levels
+}
+
+// SetSampling replaces the sampling rules at runtime. The change applies to
+// this logger and every logger derived from it.
+func (l *SuuupraLogger) SetSampling(cfg SamplingConfig) error {
+	return l.sampler.Configure(cfg)
+}
+
+// DroppedEntries returns how many entries sampling or a full async buffer
+// has dropped.
+func (l *SuuupraLogger) DroppedEntries() uint64 {
+	dropped := l.sampler.Dropped()
+	if l.async != nil {
+		dropped += l.async.Dropped()
+	}
+	return dropped
+}
+
 // Lifecycle
 func (l *SuuupraLogger) Flush() error {
 	return l.zap.Sync()
 }
 
+// Close delivers buffered wide events and entries and stops the wide-event
+// emitter, async writer, log file and OTLP exporter, if enabled. It must be called
+// once, on the root logger, before the process exits.
 func (l *SuuupraLogger) Close() error {
-	return l.zap.Sync()
+	var err error
+	if l.events != nil {
+		err = l.events.Close()
+	}
+	if l.async != nil {
+		if closeErr := l.async.Close(); err == nil {
+			err = closeErr
+		}
+	} else if syncErr := l.zap.Sync(); err == nil {
+		err = syncErr
+	}
+	if l.file != nil {
+		if closeErr := l.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if l.otlp != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if shutdownErr := l.otlp.Shutdown(ctx); err == nil {
+			err = shutdownErr
+		}
+	}
+	return err
 }
 
 // Context utilities
@@ -708,6 +926,37 @@ func FromContext(ctx context.Context) (LogContext, bool) {
 	return logCtx, ok
 }
 
+// mergeLogContext overlays the request-scoped fields of req that are set
+// onto base. Service identity always comes from base.
+func mergeLogContext(base, req LogContext) LogContext {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&base.RequestID, req.RequestID)
+	set(&base.TraceID, req.TraceID)
+	set(&base.SpanID, req.SpanID)
+	set(&base.UserID, req.UserID)
+	set(&base.SessionID, req.SessionID)
+	set(&base.Component, req.Component)
+	set(&base.AvailabilityZone, req.AvailabilityZone)
+	set(&base.TenantID, req.TenantID)
+	set(&base.OrganizationID, req.OrganizationID)
+
+	if len(req.Extra) > 0 {
+		extra := make(map[string]interface{}, len(base.Extra)+len(req.Extra))
+		for k, v := range base.Extra {
+			extra[k] = v
+		}
+		for k, v := range req.Extra {
+			extra[k] = v
+		}
+		base.Extra = extra
+	}
+	return base
+}
+
 // CreateRequestContext creates a new request context
 func CreateRequestContext(requestID string) LogContext {
 	if requestID == "" {
@@ -0,0 +1,48 @@
+//go:build !race
+
+// The race detector instruments memory accesses and allocates on its own,
+// so allocation counts and timings only hold in a normal build.
+
+package logging
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInfoDoesNotAllocate(t *testing.T) {
+	l := discardLogger(t, Config{Level: InfoLevel})
+	txn := l.WithRequestID("req-1")
+	reqCtx, reqLogger := requestContext(l)
+	fields := txnFields()
+
+	cases := map[string]func(){
+		"Info":          func() { l.Info("Transaction processed", fields...) },
+		"InfoCtx":       func() { l.InfoCtx(context.Background(), "Transaction processed", fields...) },
+		"WithRequestID": func() { txn.Info("Transaction processed", fields...) },
+		"Disabled":      func() { l.Debug("Transaction processed", fields...) },
+		// Middleware stores a LogContext in every request context
+		"RequestLogger":  func() { reqLogger.InfoCtx(reqCtx, "Transaction processed", fields...) },
+		"RootWithReqCtx": func() { l.InfoCtx(reqCtx, "Transaction processed", fields...) },
+	}
+	for name, fn := range cases {
+		if allocs := testing.AllocsPerRun(1000, fn); allocs != 0 {
+			t.Errorf("%s: %v allocations per call, want 0", name, allocs)
+		}
+	}
+}
+
+// TestInfoOverheadUnderMicrosecond checks what the logger adds on top of
+// zap's encoder for a typical transaction entry. The bound is loose so the
+// test holds on slow CI machines; BenchmarkInfoFieldsOverhead has the
+// actual figure.
+func TestInfoOverheadUnderMicrosecond(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing test")
+	}
+	res := testing.Benchmark(BenchmarkInfoFieldsOverhead)
+	if perOp := time.Duration(res.NsPerOp()); perOp >= time.Microsecond {
+		t.Fatalf("Info with 5 fields costs %v before encoding, want under 1µs", perOp)
+	}
+}
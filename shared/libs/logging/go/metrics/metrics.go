@@ -0,0 +1,201 @@
+// Package metrics is the Suuupra metrics facade: counters, gauges and
+// histograms on Prometheus with one naming and label scheme for every
+// service.
+//
+// Every metric is named <service>_<name>, carries constant service and
+// environment labels, and, when recorded with a context holding an active
+// trace span, attaches the trace ID as an exemplar so dashboards can jump to
+// the trace.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	logging "github.com/suuupra/logging/go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultBuckets suit request latencies in seconds, from 1ms to 10s.
+var DefaultBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Config mirrors logging.Config so both are built from the same settings.
+type Config struct {
+	Service     string
+	Environment string
+	// Registry defaults to the global Prometheus registry.
+	Registry *prometheus.Registry
+}
+
+// FromLoggingConfig takes the service identity from a logger's config.
+func FromLoggingConfig(cfg logging.Config) Config {
+	return Config{Service: cfg.Service, Environment: cfg.Environment}
+}
+
+// Registry creates metrics following the shared conventions.
+type Registry struct {
+	namespace   string
+	constLabels prometheus.Labels
+	registerer  prometheus.Registerer
+	gatherer    prometheus.Gatherer
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// New creates a registry for a service.
+func New(cfg Config) *Registry {
+	if cfg.Service == "" {
+		cfg.Service = "unknown-service"
+	}
+	if cfg.Environment == "" {
+		cfg.Environment = "development"
+	}
+
+	r := &Registry{
+		namespace: invalidNameChars.ReplaceAllString(strings.ToLower(cfg.Service), "_"),
+		constLabels: prometheus.Labels{
+			"service":     cfg.Service,
+			"environment": cfg.Environment,
+		},
+		registerer: prometheus.DefaultRegisterer,
+		gatherer:   prometheus.DefaultGatherer,
+	}
+	if cfg.Registry != nil {
+		r.registerer = cfg.Registry
+		r.gatherer = cfg.Registry
+	}
+	return r
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.gatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// RegisterLogging registers the logging library's own metrics, such as
+// <service>_logger_dropped_total, with the registry's prefix and labels.
+func (r *Registry) RegisterLogging() {
+	registerer := prometheus.WrapRegistererWith(r.constLabels,
+		prometheus.WrapRegistererWithPrefix(r.namespace+"_", r.registerer))
+	register(registerer, logging.DroppedCollector())
+}
+
+// Counter is a monotonically increasing metric.
+type Counter struct {
+	vec *prometheus.CounterVec
+}
+
+// Counter registers a counter. Names should end in _total.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   r.namespace,
+		Name:        name,
+		Help:        help,
+		ConstLabels: r.constLabels,
+	}, labels)
+	return &Counter{vec: register(r.registerer, vec)}
+}
+
+// Inc adds one.
+func (c *Counter) Inc(ctx context.Context, labelValues ...string) {
+	c.Add(ctx, 1, labelValues...)
+}
+
+// Add adds v, which must not be negative.
+func (c *Counter) Add(ctx context.Context, v float64, labelValues ...string) {
+	counter := c.vec.WithLabelValues(labelValues...)
+	if exemplar := exemplarFrom(ctx); exemplar != nil {
+		if adder, ok := counter.(prometheus.ExemplarAdder); ok {
+			adder.AddWithExemplar(v, exemplar)
+			return
+		}
+	}
+	counter.Add(v)
+}
+
+// Gauge is a metric that can go up and down.
+type Gauge struct {
+	vec *prometheus.GaugeVec
+}
+
+// Gauge registers a gauge.
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   r.namespace,
+		Name:        name,
+		Help:        help,
+		ConstLabels: r.constLabels,
+	}, labels)
+	return &Gauge{vec: register(r.registerer, vec)}
+}
+
+// Set sets the gauge to v.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Set(v)
+}
+
+// Add adds v, which may be negative.
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Add(v)
+}
+
+// Histogram samples observations into buckets.
+type Histogram struct {
+	vec *prometheus.HistogramVec
+}
+
+// Histogram registers a histogram. Nil buckets means DefaultBuckets.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   r.namespace,
+		Name:        name,
+		Help:        help,
+		Buckets:     buckets,
+		ConstLabels: r.constLabels,
+	}, labels)
+	return &Histogram{vec: register(r.registerer, vec)}
+}
+
+// Observe records v.
+func (h *Histogram) Observe(ctx context.Context, v float64, labelValues ...string) {
+	observer := h.vec.WithLabelValues(labelValues...)
+	if exemplar := exemplarFrom(ctx); exemplar != nil {
+		if eo, ok := observer.(prometheus.ExemplarObserver); ok {
+			eo.ObserveWithExemplar(v, exemplar)
+			return
+		}
+	}
+	observer.Observe(v)
+}
+
+// register registers c, returning the already registered collector when the
+// same metric is declared twice, e.g. by two instances of a component.
+func register[C prometheus.Collector](registerer prometheus.Registerer, c C) C {
+	if err := registerer.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
+
+func exemplarFrom(ctx context.Context) prometheus.Labels {
+	if ctx == nil {
+		return nil
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || !sc.IsSampled() {
+		return nil
+	}
+	return prometheus.Labels{"trace_id": sc.TraceID().String()}
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	logging "github.com/suuupra/logging/go"
+)
+
+func TestRegisterLoggingUsesServiceLabels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	r := New(Config{Service: "upi-core", Environment: "production", Registry: registry})
+	r.RegisterLogging()
+	// Registering twice, e.g. from two components, is harmless
+	r.RegisterLogging()
+
+	// A series must exist to be gathered
+	logging.DroppedCollector().(*prometheus.CounterVec).WithLabelValues("buffer_full").Add(0)
+
+	want := `
+# HELP upi_core_logger_dropped_total Total number of log entries dropped before being written
+# TYPE upi_core_logger_dropped_total counter
+upi_core_logger_dropped_total{environment="production",reason="buffer_full",service="upi-core"} 0
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "upi_core_logger_dropped_total"); err != nil {
+		t.Fatal(err)
+	}
+}
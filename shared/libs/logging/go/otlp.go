@@ -0,0 +1,263 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// OTLPConfig configures shipping log records to an OpenTelemetry collector
+// over OTLP/HTTP, alongside the regular stdout output. Unset fields fall back
+// to the standard OTEL_EXPORTER_OTLP_* environment variables, then to the
+// exporter defaults.
+type OTLPConfig struct {
+	Endpoint string // host:port, e.g. "otel-collector:4318"
+	Insecure bool
+	Headers  map[string]string
+	Timeout  time.Duration // Per export request
+
+	// Batching
+	MaxQueueSize   int           // Records held before new ones are dropped; default 2048
+	MaxBatchSize   int           // Records per export request; default 512
+	ExportInterval time.Duration // Default 1s
+
+	// Retry of failed exports, with exponential backoff
+	RetryMaxElapsed time.Duration // Default 1m; negative disables retries
+}
+
+// fatalFlushTimeout bounds how long a fatal or panic entry waits for its
+// export before the process goes down.
+const fatalFlushTimeout = 5 * time.Second
+
+// otlpCore is a zap core that converts entries into OTLP log records. Records
+// are batched and exported in the background by the SDK.
+type otlpCore struct {
+	zapcore.LevelEnabler
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+	fields   []zapcore.Field
+}
+
+// newOTLPCore builds the exporter pipeline. Resource attributes identify the
+// emitting service and are taken from the logger's root context.
+func newOTLPCore(cfg OTLPConfig, logCtx LogContext, enab zapcore.LevelEnabler) (*otlpCore, error) {
+	opts := []otlploghttp.Option{}
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlploghttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlploghttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlploghttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlploghttp.WithTimeout(cfg.Timeout))
+	}
+	retry := otlploghttp.RetryConfig{
+		Enabled:         cfg.RetryMaxElapsed >= 0,
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     10 * time.Second,
+		MaxElapsedTime:  time.Minute,
+	}
+	if cfg.RetryMaxElapsed > 0 {
+		retry.MaxElapsedTime = cfg.RetryMaxElapsed
+	}
+	opts = append(opts, otlploghttp.WithRetry(retry))
+
+	exporter, err := otlploghttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp log exporter: %w", err)
+	}
+
+	batchOpts := []sdklog.BatchProcessorOption{}
+	if cfg.MaxQueueSize > 0 {
+		batchOpts = append(batchOpts, sdklog.WithMaxQueueSize(cfg.MaxQueueSize))
+	}
+	if cfg.MaxBatchSize > 0 {
+		batchOpts = append(batchOpts, sdklog.WithExportMaxBatchSize(cfg.MaxBatchSize))
+	}
+	if cfg.ExportInterval > 0 {
+		batchOpts = append(batchOpts, sdklog.WithExportInterval(cfg.ExportInterval))
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(resource.NewSchemaless(resourceAttributes(logCtx)...)),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter, batchOpts...)),
+	)
+
+	return &otlpCore{
+		LevelEnabler: enab,
+		provider:     provider,
+		logger:       provider.Logger("suuupra-logger"),
+	}, nil
+}
+
+// resourceAttributes maps the service-level part of a LogContext onto
+// OpenTelemetry semantic convention resource attributes.
+func resourceAttributes(ctx LogContext) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("service.name", ctx.Service),
+		attribute.String("deployment.environment", ctx.Environment),
+	}
+	optional := map[string]string{
+		"service.version":         ctx.Version,
+		"service.instance.id":     ctx.InstanceID,
+		"cloud.region":            ctx.Region,
+		"cloud.availability_zone": ctx.AvailabilityZone,
+	}
+	for key, value := range optional {
+		if value != "" {
+			attrs = append(attrs, attribute.String(key, value))
+		}
+	}
+	return attrs
+}
+
+func (c *otlpCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field{}, c.fields...), fields...)
+	return &clone
+}
+
+func (c *otlpCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *otlpCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	var record otellog.Record
+	record.SetTimestamp(ent.Time)
+	record.SetObservedTimestamp(time.Now())
+	record.SetSeverity(otlpSeverity(ent.Level))
+	record.SetSeverityText(ent.Level.CapitalString())
+	record.SetBody(otellog.StringValue(ent.Message))
+	for key, value := range enc.Fields {
+		if value == "" {
+			continue
+		}
+		record.AddAttributes(otellog.KeyValue{Key: key, Value: otlpValue(value)})
+	}
+	if wide, ok := enc.Fields["wide_event"].(WideEvent); ok {
+		record.SetEventName(wide.EventType)
+	}
+
+	// The SDK takes the record's trace context from ctx
+	ctx := context.Background()
+	traceID, _ := enc.Fields["trace_id"].(string)
+	spanID, _ := enc.Fields["span_id"].(string)
+	if sc, ok := spanContextFromIDs(traceID, spanID); ok {
+		ctx = trace.ContextWithSpanContext(ctx, sc)
+	}
+	c.logger.Emit(ctx, record)
+
+	// zap exits or panics right after writing these, before the batch
+	// processor's next export
+	if ent.Level > zapcore.ErrorLevel {
+		flushCtx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+		defer cancel()
+		return c.provider.ForceFlush(flushCtx)
+	}
+	return nil
+}
+
+func (c *otlpCore) Sync() error {
+	return c.provider.ForceFlush(context.Background())
+}
+
+// Shutdown exports whatever is still queued and stops the exporter.
+func (c *otlpCore) Shutdown(ctx context.Context) error {
+	return c.provider.Shutdown(ctx)
+}
+
+func otlpSeverity(level zapcore.Level) otellog.Severity {
+	switch level {
+	case zapcore.DebugLevel:
+		return otellog.SeverityDebug
+	case zapcore.InfoLevel:
+		return otellog.SeverityInfo
+	case zapcore.WarnLevel:
+		return otellog.SeverityWarn
+	case zapcore.ErrorLevel:
+		return otellog.SeverityError
+	default:
+		return otellog.SeverityFatal
+	}
+}
+
+// otlpValue converts a field value as produced by zap's map encoder into an
+// OTLP value. Structs such as WideEvent go through their JSON form so they
+// keep the same shape as on stdout.
+func otlpValue(v interface{}) otellog.Value {
+	switch val := v.(type) {
+	case nil:
+		return otellog.Value{}
+	case string:
+		return otellog.StringValue(val)
+	case bool:
+		return otellog.BoolValue(val)
+	case int:
+		return otellog.IntValue(val)
+	case int32:
+		return otellog.Int64Value(int64(val))
+	case int64:
+		return otellog.Int64Value(val)
+	case uint32:
+		return otellog.Int64Value(int64(val))
+	case uint64:
+		return otellog.Int64Value(int64(val))
+	case float32:
+		return otellog.Float64Value(float64(val))
+	case float64:
+		return otellog.Float64Value(val)
+	case []byte:
+		return otellog.BytesValue(val)
+	case time.Time:
+		return otellog.StringValue(val.UTC().Format(time.RFC3339Nano))
+	case time.Duration:
+		return otellog.Int64Value(val.Milliseconds())
+	case error:
+		return otellog.StringValue(val.Error())
+	case []interface{}:
+		values := make([]otellog.Value, len(val))
+		for i, item := range val {
+			values[i] = otlpValue(item)
+		}
+		return otellog.SliceValue(values...)
+	case map[string]interface{}:
+		kvs := make([]otellog.KeyValue, 0, len(val))
+		for key, item := range val {
+			kvs = append(kvs, otellog.KeyValue{Key: key, Value: otlpValue(item)})
+		}
+		return otellog.MapValue(kvs...)
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return otellog.StringValue(fmt.Sprint(v))
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return otellog.StringValue(string(raw))
+	}
+	return otlpValue(decoded)
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.uber.org/zap/zapcore"
+)
+
+type recordingExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *recordingExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error   { return nil }
+func (e *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+func (e *recordingExporter) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.records)
+}
+
+func TestOTLPCoreFlushesAboveError(t *testing.T) {
+	exporter := &recordingExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(
+		sdklog.NewBatchProcessor(exporter, sdklog.WithExportInterval(time.Hour)),
+	))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	core := &otlpCore{LevelEnabler: zapcore.DebugLevel, provider: provider, logger: provider.Logger("test")}
+
+	tests := []struct {
+		level zapcore.Level
+		want  int
+	}{
+		{zapcore.ErrorLevel, 0}, // left to the batch interval
+		{zapcore.FatalLevel, 2}, // flushed together with the queued error
+		{zapcore.PanicLevel, 3},
+	}
+	for _, tt := range tests {
+		if err := core.Write(zapcore.Entry{Level: tt.level, Time: time.Now(), Message: "boom"}, nil); err != nil {
+			t.Fatalf("%s: write: %v", tt.level, err)
+		}
+		if got := exporter.count(); got != tt.want {
+			t.Fatalf("after %s: %d records exported, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestResourceAttributesLeaveComponentOut(t *testing.T) {
+	attrs := resourceAttributes(LogContext{Service: "upi-core", Environment: "production", Component: "ledger"})
+	for _, kv := range attrs {
+		if kv.Key == "service.namespace" || kv.Value.AsString() == "ledger" {
+			t.Fatalf("component mapped onto resource attribute %s", kv.Key)
+		}
+	}
+}
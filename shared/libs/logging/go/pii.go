@@ -0,0 +1,197 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// PIIPattern is a named regular expression whose matches are replaced in
+// log text. Replacement may refer to submatches as $1, $2, ...
+type PIIPattern struct {
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// PIIConfig customizes the masker for a service.
+type PIIConfig struct {
+	// Patterns are applied after the built-in ones. A pattern named like a
+	// built-in replaces it.
+	Patterns []PIIPattern `json:"patterns,omitempty"`
+	// DisablePatterns switches off built-in patterns by name.
+	DisablePatterns []string `json:"disablePatterns,omitempty"`
+	// DenyFields are redacted whatever their value, in addition to the
+	// built-in list.
+	DenyFields []string `json:"denyFields,omitempty"`
+	// AllowFields are never masked, e.g. a merchant VPA that is not personal
+	// data. Allow wins over deny.
+	AllowFields []string `json:"allowFields,omitempty"`
+}
+
+// defaultPIIPatterns are applied in order; broader patterns come after the
+// ones they would otherwise swallow (cards before Aadhaar, emails before
+// VPAs).
+var defaultPIIPatterns = []PIIPattern{
+	{Name: "email", Pattern: `\b[\w\.-]+@[\w\.-]+\.\w+\b`, Replacement: "[EMAIL]"},
+	{Name: "credit_card", Pattern: `\b\d{4}[- ]?\d{4}[- ]?\d{4}[- ]?\d{4}\b`, Replacement: "[CARD]"},
+	// Indian mobile numbers with optional +91 or 0 prefix; before
+	// Aadhaar, which would otherwise take +91 and the number as 12 digits
+	{Name: "phone_in", Pattern: `(?:\+91[- ]?|\b0|\b)[6-9]\d{4}[- ]?\d{5}\b`, Replacement: "[PHONE]"},
+	// 12 digits, never starting with 0 or 1, optionally grouped 4-4-4
+	{Name: "aadhaar", Pattern: `\b[2-9]\d{3}[- ]?\d{4}[- ]?\d{4}\b`, Replacement: "[AADHAAR]"},
+	// AAAAA9999A, where the fourth letter is the holder type
+	{Name: "pan", Pattern: `\b[A-Z]{3}[ABCFGHLJPT][A-Z]\d{4}[A-Z]\b`, Replacement: "[PAN]"},
+	// UPI virtual payment address: handle@psp, with no dot in the PSP part
+	{Name: "vpa", Pattern: `\b[\w.\-]{2,256}@[A-Za-z][A-Za-z0-9]{1,63}\b`, Replacement: "[VPA]"},
+	{Name: "ssn", Pattern: `\b\d{3}-\d{2}-\d{4}\b`, Replacement: "[SSN]"},
+	{Name: "phone", Pattern: `\b\d{3}-\d{3}-\d{4}\b`, Replacement: "[PHONE]"},
+	// Bank account numbers are just digits, so only mask them next to a
+	// label such as "A/C No." or "account:"
+	{Name: "account_number", Pattern: `(?i)(\b(?:a/c|acct|account)(?:[ _]?(?:no|number|num))?\.?\s*[:#=-]?\s*)\d{9,18}\b`, Replacement: "${1}[ACCOUNT]"},
+}
+
+var defaultPIIFields = []string{
+	"password", "token", "secret", "key", "ssn", "credit_card", "creditcard",
+	"aadhaar", "aadhaar_number", "pan", "pan_number",
+	"account_number", "account_no", "acct_no",
+}
+
+type piiRule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// PIIMasker handles PII masking
+type PIIMasker struct {
+	rules       []piiRule
+	piiFields   map[string]bool
+	allowFields map[string]bool
+}
+
+// NewPIIMasker creates a new PII masker with the built-in rules
+func NewPIIMasker() *PIIMasker {
+	masker, err := NewPIIMaskerWithConfig(PIIConfig{})
+	if err != nil {
+		panic(err)
+	}
+	return masker
+}
+
+// NewPIIMaskerWithConfig creates a PII masker with the built-in rules
+// adjusted by cfg.
+func NewPIIMaskerWithConfig(cfg PIIConfig) (*PIIMasker, error) {
+	disabled := make(map[string]bool, len(cfg.DisablePatterns))
+	for _, name := range cfg.DisablePatterns {
+		disabled[name] = true
+	}
+	overrides := make(map[string]PIIPattern, len(cfg.Patterns))
+	for _, pattern := range cfg.Patterns {
+		overrides[pattern.Name] = pattern
+	}
+
+	// Overrides take the built-in's place so ordering still holds; other
+	// custom patterns run last. A name given more than once keeps its last
+	// definition.
+	patterns := make([]PIIPattern, 0, len(defaultPIIPatterns)+len(cfg.Patterns))
+	for _, pattern := range defaultPIIPatterns {
+		if override, ok := overrides[pattern.Name]; ok {
+			pattern = override
+			delete(overrides, pattern.Name)
+		}
+		patterns = append(patterns, pattern)
+	}
+	for _, pattern := range cfg.Patterns {
+		if last, ok := overrides[pattern.Name]; ok {
+			patterns = append(patterns, last)
+			delete(overrides, pattern.Name)
+		}
+	}
+
+	p := &PIIMasker{
+		piiFields:   make(map[string]bool),
+		allowFields: make(map[string]bool),
+	}
+	for _, pattern := range patterns {
+		if disabled[pattern.Name] {
+			continue
+		}
+		re, err := regexp.Compile(pattern.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pii pattern %q: %w", pattern.Name, err)
+		}
+		p.rules = append(p.rules, piiRule{re: re, replacement: pattern.Replacement})
+	}
+
+	for _, field := range append(append([]string{}, defaultPIIFields...), cfg.DenyFields...) {
+		p.piiFields[strings.ToLower(field)] = true
+	}
+	for _, field := range cfg.AllowFields {
+		p.allowFields[strings.ToLower(field)] = true
+	}
+	return p, nil
+}
+
+// MaskText masks PII in text
+func (p *PIIMasker) MaskText(text string) string {
+	for _, rule := range p.rules {
+		text = rule.re.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
+
+// MaskData masks PII in structured data
+func (p *PIIMasker) MaskData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+
+	masked := make(map[string]interface{})
+	for k, v := range data {
+		key := strings.ToLower(k)
+		if p.allowFields[key] {
+			masked[k] = v
+		} else if p.piiFields[key] {
+			masked[k] = "[REDACTED]"
+		} else if str, ok := v.(string); ok {
+			masked[k] = p.MaskText(str)
+		} else if nested, ok := v.(map[string]interface{}); ok {
+			masked[k] = p.MaskData(nested)
+		} else {
+			masked[k] = v
+		}
+	}
+	return masked
+}
+
+// LoadPIIConfig reads masking rules from a shared logging config file (see
+// config/default.json): the global security.piiFields and
+// security.piiPatterns, plus the services.<service>.pii section.
+func LoadPIIConfig(path, service string) (PIIConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return PIIConfig{}, fmt.Errorf("read logging config: %w", err)
+	}
+
+	var file struct {
+		Logging struct {
+			Security struct {
+				PIIFields   []string     `json:"piiFields"`
+				PIIPatterns []PIIPattern `json:"piiPatterns"`
+			} `json:"security"`
+			Services map[string]struct {
+				PII PIIConfig `json:"pii"`
+			} `json:"services"`
+		} `json:"logging"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return PIIConfig{}, fmt.Errorf("parse logging config: %w", err)
+	}
+
+	cfg := file.Logging.Services[service].PII
+	cfg.Patterns = append(file.Logging.Security.PIIPatterns, cfg.Patterns...)
+	cfg.DenyFields = append(file.Logging.Security.PIIFields, cfg.DenyFields...)
+	return cfg, nil
+}
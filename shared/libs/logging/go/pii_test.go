@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCustomPatternsDedupeByName(t *testing.T) {
+	masker, err := NewPIIMaskerWithConfig(PIIConfig{Patterns: []PIIPattern{
+		{Name: "employee_id", Pattern: `EMP\d{4}`, Replacement: "[OLD]"},
+		{Name: "ticket", Pattern: `TKT-\d+`, Replacement: "[TICKET]"},
+		{Name: "employee_id", Pattern: `EMP\d{4}`, Replacement: "[EMPLOYEE]"},
+	}})
+	if err != nil {
+		t.Fatalf("NewPIIMaskerWithConfig: %v", err)
+	}
+
+	if got := len(masker.rules); got != len(defaultPIIPatterns)+2 {
+		t.Fatalf("%d rules, want the built-ins plus 2 custom", got)
+	}
+	if got := masker.MaskText("EMP1234 raised TKT-42"); got != "[EMPLOYEE] raised [TICKET]" {
+		t.Fatalf("MaskText = %q, want the last employee_id definition applied", got)
+	}
+}
+
+func TestMaskText(t *testing.T) {
+	masker := NewPIIMasker()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"email", "mail ravi.k@example.co.in now", "mail [EMAIL] now"},
+		{"card", "card 4111 1111 1111 1111 charged", "card [CARD] charged"},
+		{"card dashed", "4111-1111-1111-1111", "[CARD]"},
+		{"mobile", "call 9876543210", "call [PHONE]"},
+		{"mobile +91", "call +91 98765 43210", "call [PHONE]"},
+		{"mobile +91 before aadhaar", "call +919876543210", "call [PHONE]"},
+		{"mobile leading zero", "call 09876543210", "call [PHONE]"},
+		{"aadhaar", "aadhaar 2345 6789 0123", "aadhaar [AADHAAR]"},
+		{"aadhaar plain", "234567890123", "[AADHAAR]"},
+		{"aadhaar starting with 1", "134567890123", "134567890123"},
+		{"aadhaar in card", "2345 6789 0123 4567", "[CARD]"},
+		{"pan", "PAN ABCPE1234F", "PAN [PAN]"},
+		{"pan bad holder type", "ABCDE1234F", "ABCDE1234F"},
+		{"vpa", "pay ravi.k@okhdfc", "pay [VPA]"},
+		{"ssn", "ssn 123-45-6789", "ssn [SSN]"},
+		{"us phone", "555-123-4567", "[PHONE]"},
+		{"account", "A/C No. 123456789012", "A/C No. [ACCOUNT]"},
+		{"account colon", "account: 000123456789", "account: [ACCOUNT]"},
+		{"unlabelled digits", "order 000123456789", "order 000123456789"},
+		{"clean", "payment settled", "payment settled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := masker.MaskText(tt.in); got != tt.want {
+				t.Fatalf("MaskText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPIIConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  PIIConfig
+		in   string
+		want string
+	}{
+		{
+			name: "disable built-in",
+			cfg:  PIIConfig{DisablePatterns: []string{"vpa"}},
+			in:   "pay merchant@okaxis",
+			want: "pay merchant@okaxis",
+		},
+		{
+			name: "override keeps position",
+			cfg:  PIIConfig{Patterns: []PIIPattern{{Name: "phone_in", Pattern: `\b[6-9]\d{9}\b`, Replacement: "[MOBILE]"}}},
+			in:   "call 9876543210 or 234567890123",
+			want: "call [MOBILE] or [AADHAAR]",
+		},
+		{
+			name: "custom runs last",
+			cfg:  PIIConfig{Patterns: []PIIPattern{{Name: "masked_email", Pattern: `\[EMAIL\]`, Replacement: "[X]"}}},
+			in:   "a@b.com",
+			want: "[X]",
+		},
+		{
+			name: "submatch replacement",
+			cfg:  PIIConfig{Patterns: []PIIPattern{{Name: "ifsc", Pattern: `\b([A-Z]{4})0[A-Z0-9]{6}\b`, Replacement: "${1}0[IFSC]"}}},
+			in:   "HDFC0001234",
+			want: "HDFC0[IFSC]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			masker, err := NewPIIMaskerWithConfig(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewPIIMaskerWithConfig: %v", err)
+			}
+			if got := masker.MaskText(tt.in); got != tt.want {
+				t.Fatalf("MaskText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewPIIMaskerWithConfig(PIIConfig{Patterns: []PIIPattern{{Name: "bad", Pattern: `(`}}}); err == nil {
+		t.Fatal("invalid pattern accepted")
+	}
+}
+
+func TestMaskData(t *testing.T) {
+	masker, err := NewPIIMaskerWithConfig(PIIConfig{
+		DenyFields:  []string{"otp"},
+		AllowFields: []string{"merchant_vpa", "Token"},
+	})
+	if err != nil {
+		t.Fatalf("NewPIIMaskerWithConfig: %v", err)
+	}
+
+	got := masker.MaskData(map[string]interface{}{
+		"Password":     "hunter2",
+		"otp":          123456,
+		"token":        "allowed wins over deny",
+		"merchant_vpa": "shop@okaxis",
+		"note":         "refund to ravi@okhdfc",
+		"amount":       100,
+		"payer":        map[string]interface{}{"aadhaar": "234567890123", "mobile": "9876543210"},
+	})
+	want := map[string]interface{}{
+		"Password":     "[REDACTED]",
+		"otp":          "[REDACTED]",
+		"token":        "allowed wins over deny",
+		"merchant_vpa": "shop@okaxis",
+		"note":         "refund to [VPA]",
+		"amount":       100,
+		"payer":        map[string]interface{}{"aadhaar": "[REDACTED]", "mobile": "[PHONE]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MaskData = %v, want %v", got, want)
+	}
+	if masker.MaskData(nil) != nil {
+		t.Fatal("MaskData(nil) != nil")
+	}
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Processor inspects or rewrites an entry after built-in PII masking and
+// before it is written, e.g. to mask domain-specific identifiers or add
+// fields. Processors run synchronously on the logging goroutine, in
+// registration order, and must be safe for concurrent use. Entries are pooled,
+// so a processor must not keep entry or its Data map after it returns.
+// Loggers without processors or PII masking write entries without building a
+// LogEntry at all.
+type Processor func(entry *LogEntry)
+
+// processorChain is shared by a logger and everything derived from it.
+// Registration copies the slice so the logging path never locks.
+type processorChain struct {
+	mu         sync.Mutex
+	processors atomic.Pointer[[]Processor]
+}
+
+func newProcessorChain() *processorChain {
+	c := &processorChain{}
+	c.processors.Store(&[]Processor{})
+	return c
+}
+
+func (c *processorChain) register(p Processor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current := *c.processors.Load()
+	next := make([]Processor, len(current), len(current)+1)
+	copy(next, current)
+	next = append(next, p)
+	c.processors.Store(&next)
+}
+
+func (c *processorChain) empty() bool {
+	return len(*c.processors.Load()) == 0
+}
+
+func (c *processorChain) apply(entry *LogEntry) {
+	for _, p := range *c.processors.Load() {
+		p(entry)
+	}
+}
+
+// RedactFields returns a processor that replaces the named top-level data
+// fields with "[REDACTED]", for identifiers only one domain treats as
+// sensitive (device IDs, card tokens) that the shared masker does not know.
+func RedactFields(fields ...string) Processor {
+	return func(entry *LogEntry) {
+		for _, field := range fields {
+			if _, ok := entry.Data[field]; ok {
+				entry.Data[field] = "[REDACTED]"
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Headers carrying request context between services. gRPC uses the same
+// names, lower-cased, as metadata keys.
+const (
+	HeaderRequestID = "X-Request-ID"
+	HeaderUserID    = "X-User-ID"
+	HeaderSessionID = "X-Session-ID"
+	HeaderTenantID  = "X-Tenant-ID"
+)
+
+const requestLoggerKey contextKey = "suuupra_request_logger"
+
+// ContextWithLogger stores a request-scoped logger in ctx.
+func ContextWithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, requestLoggerKey, logger)
+}
+
+// LoggerFromContext returns the request-scoped logger stored in ctx, or
+// fallback if there is none.
+func LoggerFromContext(ctx context.Context, fallback Logger) Logger {
+	if logger, ok := ctx.Value(requestLoggerKey).(Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// StartRequest prepares ctx for an incoming request: it extracts the W3C
+// trace context, builds the request's LogContext from the propagation
+// headers (generating a request ID if the caller sent none), and stores both
+// that context and a request-scoped logger in the returned ctx. get reads a
+// header or metadata value by canonical header name.
+func StartRequest(ctx context.Context, logger Logger, carrier propagation.TextMapCarrier, get func(string) string) (context.Context, Logger, LogContext) {
+	// W3C trace context is always honoured, even before the service has
+	// installed a global propagator
+	propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, otel.GetTextMapPropagator())
+	ctx = propagator.Extract(ctx, carrier)
+
+	logCtx := LogContext{
+		RequestID: get(HeaderRequestID),
+		UserID:    get(HeaderUserID),
+		SessionID: get(HeaderSessionID),
+		TenantID:  get(HeaderTenantID),
+	}
+	if logCtx.RequestID == "" {
+		logCtx.RequestID = uuid.New().String()
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logCtx.TraceID = sc.TraceID().String()
+		logCtx.SpanID = sc.SpanID().String()
+	}
+
+	reqLogger := logger.WithContext(logCtx)
+	ctx = WithLoggerContext(ctx, logCtx)
+	ctx = ContextWithLogger(ctx, reqLogger)
+	return ctx, reqLogger, logCtx
+}
+
+// StartHTTPRequest is StartRequest for net/http headers.
+func StartHTTPRequest(ctx context.Context, logger Logger, h http.Header) (context.Context, Logger, LogContext) {
+	return StartRequest(ctx, logger, propagation.HeaderCarrier(h), h.Get)
+}
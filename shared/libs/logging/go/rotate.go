@@ -0,0 +1,254 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileConfig configures the rotating file output, for deployments that
+// cannot ship logs to a collector.
+type FileConfig struct {
+	Path        string
+	MaxSizeMB   int           // Rotate when the file would exceed this; default 100
+	RotateEvery time.Duration // Also rotate on this boundary, e.g. 24h; 0 disables
+	MaxBackups  int           // Rotated files to keep; default 10
+	Compress    bool          // Gzip rotated files
+}
+
+const (
+	backupTimeFormat = "20060102T150405.000"
+	// rotateRetryInterval spaces out attempts after a failed rotation, while
+	// writes carry on into the current file.
+	rotateRetryInterval = 10 * time.Second
+)
+
+// RotatingFile is a zapcore.WriteSyncer that rotates by size and time.
+// Rotated files are named <name>-<timestamp><ext>, with _<n> appended to
+// the timestamp if that name is taken, optionally gzipped, and pruned to
+// MaxBackups in the background.
+type RotatingFile struct {
+	cfg     FileConfig
+	maxSize int64
+
+	mu       sync.Mutex
+	f        *os.File // nil after the file could not be reopened
+	closed   bool
+	size     int64
+	rotateAt time.Time
+	retryAt  time.Time
+	now      func() time.Time
+	rename   func(oldpath, newpath string) error
+
+	// housekeeping serializes compression and pruning; pending tracks the
+	// goroutines doing it so Close can wait for them
+	housekeeping sync.Mutex
+	pending      sync.WaitGroup
+}
+
+// NewRotatingFile opens cfg.Path for appending, creating its directory if
+// needed.
+func NewRotatingFile(cfg FileConfig) (*RotatingFile, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("log file path is required")
+	}
+	if cfg.MaxSizeMB <= 0 {
+		cfg.MaxSizeMB = 100
+	}
+	if cfg.MaxBackups <= 0 {
+		cfg.MaxBackups = 10
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+
+	r := &RotatingFile{cfg: cfg, maxSize: int64(cfg.MaxSizeMB) * 1024 * 1024, now: time.Now, rename: os.Rename}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+
+	r.f = f
+	r.size = info.Size()
+	if r.cfg.RotateEvery > 0 {
+		r.rotateAt = r.now().Truncate(r.cfg.RotateEvery).Add(r.cfg.RotateEvery)
+	}
+	return nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.f == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	now := r.now()
+	due := r.cfg.RotateEvery > 0 && !now.Before(r.rotateAt)
+	if r.size > 0 && (due || r.size+int64(len(p)) > r.maxSize) && !now.Before(r.retryAt) {
+		if err := r.rotate(); err != nil {
+			if r.f == nil {
+				return 0, err
+			}
+			// Keep logging into the current file rather than lose entries
+			fmt.Fprintf(os.Stderr, "logging: %v\n", err)
+			r.retryAt = now.Add(rotateRetryInterval)
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current file aside and opens a fresh one. If the file
+// cannot be moved it is reopened in place, so a failed rotation never
+// leaves later writes without a file. Called with mu held.
+func (r *RotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return r.reopen(fmt.Errorf("close log file: %w", err))
+	}
+
+	backup := r.backupName()
+	if err := r.rename(r.cfg.Path, backup); err != nil {
+		return r.reopen(fmt.Errorf("rotate log file: %w", err))
+	}
+	if err := r.open(); err != nil {
+		r.f = nil
+		return err
+	}
+
+	r.pending.Add(1)
+	go r.compressAndPrune(backup)
+	return nil
+}
+
+// reopen opens the current file again after a failed rotation and returns
+// err, joined with the open error if that failed too.
+func (r *RotatingFile) reopen(err error) error {
+	if openErr := r.open(); openErr != nil {
+		r.f = nil
+		return errors.Join(err, openErr)
+	}
+	return err
+}
+
+// backupName returns an unused name for the file being rotated. Several
+// rotations within one millisecond get _1, _2, ... after the timestamp,
+// which still sorts after the first.
+func (r *RotatingFile) backupName() string {
+	ext := filepath.Ext(r.cfg.Path)
+	base := fmt.Sprintf("%s-%s", strings.TrimSuffix(r.cfg.Path, ext), r.now().UTC().Format(backupTimeFormat))
+	backup := base + ext
+	for n := 1; exists(backup) || exists(backup+".gz"); n++ {
+		backup = fmt.Sprintf("%s_%d%s", base, n, ext)
+	}
+	return backup
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func (r *RotatingFile) compressAndPrune(backup string) {
+	defer r.pending.Done()
+	r.housekeeping.Lock()
+	defer r.housekeeping.Unlock()
+
+	if r.cfg.Compress {
+		if err := gzipFile(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "logging: compress %s: %v\n", backup, err)
+		}
+	}
+
+	ext := filepath.Ext(r.cfg.Path)
+	pattern := strings.TrimSuffix(r.cfg.Path, ext) + "-*" + ext + "*"
+	backups, err := filepath.Glob(pattern)
+	if err != nil {
+		return
+	}
+	// Timestamps sort lexically, oldest first
+	sort.Strings(backups)
+	for len(backups) > r.cfg.MaxBackups {
+		os.Remove(backups[0])
+		backups = backups[1:]
+	}
+}
+
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (r *RotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	return r.f.Sync()
+}
+
+// Close closes the current file and waits for pending compression.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	var err error
+	if r.f != nil {
+		err = r.f.Close()
+		r.f = nil
+	}
+	r.mu.Unlock()
+
+	r.pending.Wait()
+	return err
+}
@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestRotatingFile returns a rotating file in a temporary directory with
+// a clock advanced by the returned function.
+func newTestRotatingFile(t *testing.T, cfg FileConfig) (*RotatingFile, func(time.Duration)) {
+	t.Helper()
+	cfg.Path = filepath.Join(t.TempDir(), "app.log")
+	r, err := NewRotatingFile(cfg)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	if cfg.RotateEvery > 0 {
+		r.rotateAt = now.Truncate(cfg.RotateEvery).Add(cfg.RotateEvery)
+	}
+	return r, func(d time.Duration) { now = now.Add(d) }
+}
+
+// backups waits for housekeeping and returns the rotated files, oldest
+// first.
+func backups(t *testing.T, r *RotatingFile) []string {
+	t.Helper()
+	r.pending.Wait()
+	matches, err := filepath.Glob(strings.TrimSuffix(r.cfg.Path, ".log") + "-*")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+func write(t *testing.T, r *RotatingFile, s string) {
+	t.Helper()
+	if _, err := r.Write([]byte(s)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	mb := strings.Repeat("x", 1024*1024)
+
+	tests := []struct {
+		name  string
+		cfg   FileConfig
+		steps func(r *RotatingFile, advance func(time.Duration))
+		want  int // backups left
+	}{
+		{"under the size limit", FileConfig{MaxSizeMB: 2}, func(r *RotatingFile, _ func(time.Duration)) {
+			write(t, r, mb)
+			write(t, r, "entry\n")
+		}, 0},
+		{"over the size limit", FileConfig{MaxSizeMB: 1}, func(r *RotatingFile, _ func(time.Duration)) {
+			write(t, r, mb)
+			write(t, r, "entry\n")
+		}, 1},
+		{"time boundary", FileConfig{RotateEvery: time.Hour}, func(r *RotatingFile, advance func(time.Duration)) {
+			write(t, r, "entry\n")
+			advance(29 * time.Minute)
+			write(t, r, "entry\n")
+			advance(time.Minute)
+			write(t, r, "entry\n")
+		}, 1},
+		{"same millisecond", FileConfig{MaxSizeMB: 1}, func(r *RotatingFile, _ func(time.Duration)) {
+			for i := 0; i < 4; i++ {
+				write(t, r, mb)
+			}
+		}, 3},
+		{"retention", FileConfig{MaxSizeMB: 1, MaxBackups: 2}, func(r *RotatingFile, advance func(time.Duration)) {
+			for i := 0; i < 5; i++ {
+				write(t, r, mb)
+				advance(time.Second)
+			}
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, advance := newTestRotatingFile(t, tt.cfg)
+			tt.steps(r, advance)
+			if got := backups(t, r); len(got) != tt.want {
+				t.Fatalf("backups = %v, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotatingFileKeepsNewestBackups(t *testing.T) {
+	r, advance := newTestRotatingFile(t, FileConfig{MaxSizeMB: 1, MaxBackups: 2})
+	for _, content := range []string{"first", "second", "third", "fourth"} {
+		write(t, r, content+strings.Repeat(" ", 1024*1024-len(content)))
+		advance(time.Second)
+	}
+
+	got := backups(t, r)
+	if len(got) != 2 {
+		t.Fatalf("backups = %v, want 2", got)
+	}
+	for i, want := range []string{"second", "third"} {
+		raw, err := os.ReadFile(got[i])
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if !strings.HasPrefix(string(raw), want) {
+			t.Fatalf("backup %d starts %q, want %q", i, raw[:6], want)
+		}
+	}
+}
+
+func TestRotatingFileCompresses(t *testing.T) {
+	r, _ := newTestRotatingFile(t, FileConfig{MaxSizeMB: 1, Compress: true})
+	write(t, r, "rotated entry\n"+strings.Repeat("x", 1024*1024))
+	write(t, r, "current entry\n")
+
+	got := backups(t, r)
+	if len(got) != 1 || !strings.HasSuffix(got[0], ".log.gz") {
+		t.Fatalf("backups = %v, want one .log.gz", got)
+	}
+	f, err := os.Open(got[0])
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	raw, err := io.ReadAll(zr)
+	if err != nil || !strings.HasPrefix(string(raw), "rotated entry\n") {
+		t.Fatalf("decompressed %d bytes, err %v", len(raw), err)
+	}
+}
+
+func TestRotatingFileSurvivesFailedRename(t *testing.T) {
+	r, advance := newTestRotatingFile(t, FileConfig{MaxSizeMB: 1})
+	r.rename = func(string, string) error { return errors.New("disk full") }
+
+	write(t, r, strings.Repeat("x", 1024*1024))
+	// Rotation fails, but the entry still lands in the current file
+	write(t, r, "after failed rotation\n")
+	write(t, r, "and again\n")
+
+	raw, err := os.ReadFile(r.cfg.Path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.HasSuffix(string(raw), "after failed rotation\nand again\n") {
+		t.Fatal("entries written after the failed rotation are missing")
+	}
+
+	r.rename = os.Rename
+	advance(rotateRetryInterval)
+	write(t, r, "rotated\n")
+	if got := backups(t, r); len(got) != 1 {
+		t.Fatalf("backups = %v, want the retry to rotate", got)
+	}
+}
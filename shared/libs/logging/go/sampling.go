@@ -0,0 +1,239 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LevelSampling thins out identical messages at one level. Within each tick
+// the first First occurrences of a message are logged, then one in every
+// Thereafter. Thereafter <= 0 drops everything past First.
+type LevelSampling struct {
+	First      int `json:"first"`
+	Thereafter int `json:"thereafter"`
+}
+
+// KeyRateLimit caps how many entries per second are logged for each value
+// of a field, e.g. errors per error code. Entries without the field are not
+// limited.
+type KeyRateLimit struct {
+	Field     string   `json:"field"`
+	Level     LogLevel `json:"level"` // Applies to entries at this level and above
+	PerSecond int      `json:"per_second"`
+}
+
+// SamplingConfig configures the sampler. The zero value logs everything.
+type SamplingConfig struct {
+	Tick       time.Duration              `json:"tick"` // Default: 1s
+	Levels     map[LogLevel]LevelSampling `json:"levels,omitempty"`
+	RateLimits []KeyRateLimit             `json:"rate_limits,omitempty"`
+}
+
+const (
+	samplerBuckets = 4096
+	// maxTrackedKeys bounds the per-second key map of each rate limit; keys
+	// past it share a single overflow budget.
+	maxTrackedKeys = 10000
+)
+
+// Sampler decides which entries are dropped before they reach the encoder.
+// It is shared by a logger and everything derived from it, and can be
+// reconfigured while in use.
+type Sampler struct {
+	state   atomic.Pointer[samplerState]
+	dropped atomic.Uint64
+	now     func() time.Time
+}
+
+type samplerState struct {
+	cfg      SamplingConfig
+	counters map[LogLevel]*[samplerBuckets]sampleCounter
+	limits   []*keyLimiter
+}
+
+type sampleCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+type keyLimiter struct {
+	rule   KeyRateLimit
+	mu     sync.Mutex
+	second int64
+	counts map[string]int
+}
+
+// NewSampler creates a sampler with the given configuration.
+func NewSampler(cfg SamplingConfig) (*Sampler, error) {
+	s := &Sampler{now: time.Now}
+	if err := s.Configure(cfg); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Configure replaces the sampling rules. Counts restart from zero.
+func (s *Sampler) Configure(cfg SamplingConfig) error {
+	if cfg.Tick < 0 {
+		return fmt.Errorf("sampling tick must not be negative")
+	}
+	if cfg.Tick == 0 {
+		cfg.Tick = time.Second
+	}
+
+	state := &samplerState{cfg: cfg, counters: make(map[LogLevel]*[samplerBuckets]sampleCounter)}
+	for level, ls := range cfg.Levels {
+		if level >= FatalLevel {
+			return fmt.Errorf("fatal entries cannot be sampled")
+		}
+		if ls.First < 0 {
+			return fmt.Errorf("sampling for %s: first must not be negative", level)
+		}
+		state.counters[level] = new([samplerBuckets]sampleCounter)
+	}
+	for _, rule := range cfg.RateLimits {
+		if rule.Field == "" {
+			return fmt.Errorf("rate limit field is required")
+		}
+		if rule.PerSecond < 0 {
+			return fmt.Errorf("rate limit on %s: per_second must not be negative", rule.Field)
+		}
+		state.limits = append(state.limits, &keyLimiter{rule: rule, counts: make(map[string]int)})
+	}
+
+	s.state.Store(state)
+	return nil
+}
+
+// Config returns the active sampling configuration.
+func (s *Sampler) Config() SamplingConfig {
+	return s.state.Load().cfg
+}
+
+// Dropped returns how many entries have been sampled out since creation.
+func (s *Sampler) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Allow reports whether an entry should be logged. Fatal entries are always
+// allowed.
+func (s *Sampler) Allow(level LogLevel, message string, fields []Field) bool {
+	if level >= FatalLevel {
+		return true
+	}
+	state := s.state.Load()
+	if len(state.counters) == 0 && len(state.limits) == 0 {
+		return true
+	}
+
+	now := s.now()
+	if !state.allowMessage(level, message, now) || !state.allowKeys(level, fields, now) {
+		s.dropped.Add(1)
+		droppedTotal.WithLabelValues("sampled").Inc()
+		return false
+	}
+	return true
+}
+
+func (st *samplerState) allowMessage(level LogLevel, message string, now time.Time) bool {
+	counters, ok := st.counters[level]
+	if !ok {
+		return true
+	}
+	ls := st.cfg.Levels[level]
+
+	c := &counters[fnv32a(message)%samplerBuckets]
+
+	n := c.incCheckReset(now, st.cfg.Tick)
+	if n <= uint64(ls.First) {
+		return true
+	}
+	if ls.Thereafter <= 0 {
+		return false
+	}
+	return (n-uint64(ls.First))%uint64(ls.Thereafter) == 0
+}
+
+// fnv32a is FNV-1a over s, without the allocations of hash/fnv.
+func fnv32a(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	h := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= prime32
+	}
+	return h
+}
+
+// incCheckReset counts an occurrence, starting a new tick if the current one
+// has expired, and returns the count within the tick.
+func (c *sampleCounter) incCheckReset(now time.Time, tick time.Duration) uint64 {
+	tn := now.UnixNano()
+	resetAfter := c.resetAt.Load()
+	if resetAfter > tn {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+	newResetAfter := tn + tick.Nanoseconds()
+	if !c.resetAt.CompareAndSwap(resetAfter, newResetAfter) {
+		// Another goroutine started the tick first.
+		return c.count.Add(1)
+	}
+	return 1
+}
+
+func (st *samplerState) allowKeys(level LogLevel, fields []Field, now time.Time) bool {
+	for _, limiter := range st.limits {
+		if level < limiter.rule.Level {
+			continue
+		}
+		if key, ok := fieldKey(fields, limiter.rule.Field); ok && !limiter.allow(key, now) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldKey returns the value of the named field as a string. "error_code"
+// also matches the code of a classified error passed with Error.
+func fieldKey(fields []Field, name string) (string, bool) {
+	for _, field := range fields {
+		if field.Key == name {
+			return fmt.Sprint(field.value()), true
+		}
+	}
+	if name == "error_code" {
+		for _, field := range fields {
+			if err, ok := field.Value.(error); ok {
+				if code := errorCode(err); code != "" {
+					return code, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func (k *keyLimiter) allow(key string, now time.Time) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if second := now.Unix(); second != k.second {
+		k.second = second
+		k.counts = make(map[string]int)
+	}
+	if _, ok := k.counts[key]; !ok && len(k.counts) >= maxTrackedKeys {
+		key = ""
+	}
+	if k.counts[key] >= k.rule.PerSecond {
+		return false
+	}
+	k.counts[key]++
+	return true
+}
@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// codedErr is a classified error carrying only a code.
+type codedErr string
+
+func (e codedErr) Error() string     { return string(e) }
+func (e codedErr) ErrorCode() string { return string(e) }
+
+// newTestSampler returns a sampler with a clock advanced by the returned
+// function.
+func newTestSampler(t *testing.T, cfg SamplingConfig) (*Sampler, func(time.Duration)) {
+	t.Helper()
+	s, err := NewSampler(cfg)
+	if err != nil {
+		t.Fatalf("NewSampler: %v", err)
+	}
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestSamplerFirstThereafter(t *testing.T) {
+	tests := []struct {
+		name     string
+		sampling LevelSampling
+		level    LogLevel
+		n        int
+		want     []int // Indexes of the allowed entries
+	}{
+		{"first only", LevelSampling{First: 3}, InfoLevel, 6, []int{0, 1, 2}},
+		{"thereafter", LevelSampling{First: 2, Thereafter: 3}, InfoLevel, 9, []int{0, 1, 4, 7}},
+		{"thereafter one", LevelSampling{First: 1, Thereafter: 1}, InfoLevel, 4, []int{0, 1, 2, 3}},
+		{"drop all", LevelSampling{}, InfoLevel, 3, nil},
+		{"other level", LevelSampling{}, WarnLevel, 3, []int{0, 1, 2}},
+		{"fatal", LevelSampling{}, FatalLevel, 3, []int{0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestSampler(t, SamplingConfig{Levels: map[LogLevel]LevelSampling{InfoLevel: tt.sampling}})
+			var got []int
+			for i := 0; i < tt.n; i++ {
+				if s.Allow(tt.level, "payment processed", nil) {
+					got = append(got, i)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("allowed %v, want %v", got, tt.want)
+			}
+			if dropped := s.Dropped(); dropped != uint64(tt.n-len(tt.want)) {
+				t.Fatalf("Dropped() = %d, want %d", dropped, tt.n-len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSamplerTickAndMessages(t *testing.T) {
+	s, advance := newTestSampler(t, SamplingConfig{
+		Tick:   time.Second,
+		Levels: map[LogLevel]LevelSampling{InfoLevel: {First: 1}},
+	})
+
+	if !s.Allow(InfoLevel, "a", nil) || s.Allow(InfoLevel, "a", nil) {
+		t.Fatal("want only the first \"a\" in the tick")
+	}
+	if !s.Allow(InfoLevel, "b", nil) {
+		t.Fatal("a different message has its own count")
+	}
+	advance(time.Second)
+	if !s.Allow(InfoLevel, "a", nil) {
+		t.Fatal("count did not restart with the next tick")
+	}
+}
+
+func TestSamplerKeyRateLimit(t *testing.T) {
+	limit := KeyRateLimit{Field: "error_code", Level: WarnLevel, PerSecond: 2}
+
+	tests := []struct {
+		name    string
+		level   LogLevel
+		entries [][]Field
+		want    []bool
+	}{
+		{
+			name:  "per key",
+			level: ErrorLevel,
+			entries: [][]Field{
+				{String("error_code", "E1")}, {String("error_code", "E1")}, {String("error_code", "E1")},
+				{String("error_code", "E2")},
+			},
+			want: []bool{true, true, false, true},
+		},
+		{
+			name:    "classified error",
+			level:   ErrorLevel,
+			entries: [][]Field{{Error(codedErr("E1"))}, {Error(codedErr("E1"))}, {Error(codedErr("E1"))}},
+			want:    []bool{true, true, false},
+		},
+		{
+			name:    "field wins over error",
+			level:   ErrorLevel,
+			entries: [][]Field{{String("error_code", "E1"), Error(codedErr("E2"))}, {Error(codedErr("E2"))}, {Error(codedErr("E2"))}},
+			want:    []bool{true, true, true},
+		},
+		{
+			name:    "without field",
+			level:   ErrorLevel,
+			entries: [][]Field{{String("user", "u1")}, {String("user", "u1")}, {String("user", "u1")}},
+			want:    []bool{true, true, true},
+		},
+		{
+			name:    "below level",
+			level:   InfoLevel,
+			entries: [][]Field{{String("error_code", "E1")}, {String("error_code", "E1")}, {String("error_code", "E1")}},
+			want:    []bool{true, true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestSampler(t, SamplingConfig{RateLimits: []KeyRateLimit{limit}})
+			for i, fields := range tt.entries {
+				if got := s.Allow(tt.level, "failed", fields); got != tt.want[i] {
+					t.Fatalf("entry %d: Allow = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSamplerKeyRateLimitSecond(t *testing.T) {
+	s, advance := newTestSampler(t, SamplingConfig{RateLimits: []KeyRateLimit{{Field: "code", PerSecond: 1}}})
+	fields := []Field{String("code", "E1")}
+
+	if !s.Allow(InfoLevel, "m", fields) || s.Allow(InfoLevel, "m", fields) {
+		t.Fatal("want one entry per second")
+	}
+	advance(time.Second)
+	if !s.Allow(InfoLevel, "m", fields) {
+		t.Fatal("budget did not reset with the next second")
+	}
+}
+
+func TestSamplerKeyOverflow(t *testing.T) {
+	s, _ := newTestSampler(t, SamplingConfig{RateLimits: []KeyRateLimit{{Field: "user", PerSecond: 1}}})
+	for i := 0; i < maxTrackedKeys; i++ {
+		s.Allow(InfoLevel, "m", []Field{Int("user", i)})
+	}
+
+	// Untracked keys share one budget
+	if !s.Allow(InfoLevel, "m", []Field{String("user", "new-1")}) {
+		t.Fatal("first untracked key was dropped")
+	}
+	if s.Allow(InfoLevel, "m", []Field{String("user", "new-2")}) {
+		t.Fatal("second untracked key got its own budget")
+	}
+}
+
+func TestSamplerConfigure(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SamplingConfig
+		ok   bool
+	}{
+		{"zero", SamplingConfig{}, true},
+		{"negative tick", SamplingConfig{Tick: -time.Second}, false},
+		{"fatal", SamplingConfig{Levels: map[LogLevel]LevelSampling{FatalLevel: {First: 1}}}, false},
+		{"negative first", SamplingConfig{Levels: map[LogLevel]LevelSampling{InfoLevel: {First: -1}}}, false},
+		{"no field", SamplingConfig{RateLimits: []KeyRateLimit{{PerSecond: 1}}}, false},
+		{"negative rate", SamplingConfig{RateLimits: []KeyRateLimit{{Field: "code", PerSecond: -1}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSampler(tt.cfg)
+			if (err == nil) != tt.ok {
+				t.Fatalf("NewSampler error = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
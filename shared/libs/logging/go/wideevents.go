@@ -0,0 +1,278 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WideEventSchemaVersion is stamped on every emitted wide event. Bump it
+// when a field changes meaning or type; adding optional fields does not
+// need a bump.
+const WideEventSchemaVersion = "1"
+
+// WideEventSink receives batches of wide events. Write is only ever called
+// from the emitter's goroutine.
+type WideEventSink interface {
+	Write(ctx context.Context, events []WideEvent) error
+	Close() error
+}
+
+// WideEventConfig configures dedicated wide-event delivery. Without sinks,
+// wide events are written as a field of an ordinary log line.
+type WideEventConfig struct {
+	Sinks         []WideEventSink
+	BufferSize    int           // Events; default 10000
+	BatchSize     int           // Events per sink write; default 500
+	FlushInterval time.Duration // Default 1s
+}
+
+// wideEventEmitter batches events in the background and fans every batch
+// out to all sinks. When the buffer is full, events are dropped.
+type wideEventEmitter struct {
+	cfg     WideEventConfig
+	queue   chan WideEvent
+	onError func(sink WideEventSink, err error)
+	stop    chan struct{}
+	done    chan struct{}
+	mu      sync.RWMutex
+	closed  bool
+}
+
+func newWideEventEmitter(cfg WideEventConfig, onError func(WideEventSink, error)) *wideEventEmitter {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 10000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+
+	e := &wideEventEmitter{
+		cfg:     cfg,
+		queue:   make(chan WideEvent, cfg.BufferSize),
+		onError: onError,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+func (e *wideEventEmitter) emit(event WideEvent) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		return
+	}
+	select {
+	case e.queue <- event:
+	default:
+		droppedTotal.WithLabelValues("wide_event_buffer_full").Inc()
+	}
+}
+
+func (e *wideEventEmitter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]WideEvent, 0, e.cfg.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		e.write(batch)
+		batch = make([]WideEvent, 0, e.cfg.BatchSize)
+	}
+
+	for {
+		select {
+		case event := <-e.queue:
+			batch = append(batch, event)
+			if len(batch) >= e.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.stop:
+			for {
+				select {
+				case event := <-e.queue:
+					batch = append(batch, event)
+					if len(batch) >= e.cfg.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *wideEventEmitter) write(batch []WideEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, sink := range e.cfg.Sinks {
+		if err := sink.Write(ctx, batch); err != nil {
+			droppedTotal.WithLabelValues("wide_event_sink_error").Add(float64(len(batch)))
+			if e.onError != nil {
+				e.onError(sink, err)
+			}
+		}
+	}
+}
+
+// Close delivers everything still buffered and closes the sinks.
+func (e *wideEventEmitter) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	e.mu.Unlock()
+
+	close(e.stop)
+	<-e.done
+
+	var firstErr error
+	for _, sink := range e.cfg.Sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// completeWideEvent fills in whatever the caller left empty: identity,
+// timestamp, schema version, service context from the logger, and request
+// context from ctx.
+func (l *SuuupraLogger) completeWideEvent(ctx context.Context, event WideEvent) WideEvent {
+	logCtx := l.context
+	if reqCtx, ok := FromContext(ctx); ok {
+		logCtx = mergeLogContext(logCtx, reqCtx)
+	}
+	if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+		logCtx.TraceID = span.SpanContext().TraceID().String()
+		logCtx.SpanID = span.SpanContext().SpanID().String()
+	}
+
+	set := func(dst *string, v string) {
+		if *dst == "" {
+			*dst = v
+		}
+	}
+	set(&event.SchemaVersion, WideEventSchemaVersion)
+	set(&event.EventID, uuid.New().String())
+	set(&event.Timestamp, time.Now().UTC().Format(time.RFC3339Nano))
+	set(&event.RequestID, logCtx.RequestID)
+	set(&event.TraceID, logCtx.TraceID)
+	set(&event.SpanID, logCtx.SpanID)
+	set(&event.Service, logCtx.Service)
+	set(&event.Version, logCtx.Version)
+	set(&event.Environment, logCtx.Environment)
+	set(&event.InstanceID, logCtx.InstanceID)
+	set(&event.Region, logCtx.Region)
+	return event
+}
+
+// FileWideEventSink appends events to a file as newline-delimited JSON.
+type FileWideEventSink struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+// NewFileWideEventSink opens path for appending, creating it if needed.
+func NewFileWideEventSink(path string) (*FileWideEventSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open wide event file: %w", err)
+	}
+	return &FileWideEventSink{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+func (s *FileWideEventSink) Write(_ context.Context, events []WideEvent) error {
+	enc := json.NewEncoder(s.w)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("write wide event: %w", err)
+		}
+	}
+	return s.w.Flush()
+}
+
+func (s *FileWideEventSink) Close() error {
+	if err := s.w.Flush(); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
+
+// HTTPWideEventSink posts each batch as newline-delimited JSON to a
+// collector endpoint.
+type HTTPWideEventSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPWideEventSink creates a sink posting to url with the given extra
+// headers, e.g. an Authorization header.
+func NewHTTPWideEventSink(url string, headers map[string]string) *HTTPWideEventSink {
+	return &HTTPWideEventSink{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (s *HTTPWideEventSink) Write(ctx context.Context, events []WideEvent) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("encode wide event: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &body)
+	if err != nil {
+		return fmt.Errorf("build wide event request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("X-Wide-Event-Schema", WideEventSchemaVersion)
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post wide events: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("post wide events: collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *HTTPWideEventSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/suuupra/upi-bank-integration-tests/internal/authz"
+)
+
+var (
+	paymentsJWTSecret = flag.String("payments-jwt-secret", "dev-jwt-secret-key", "JWT_SECRET the payments gateway verifies tokens with")
+	massLiveURL       = flag.String("mass-live-url", "", "mass-live base URL, e.g. http://localhost:8088; its authorization checks skip without it")
+	massLiveJWTSecret = flag.String("mass-live-jwt-secret", "your-super-secret-jwt-key-change-in-production", "JWT_SECRET mass-live verifies tokens with")
+)
+
+// Every authenticated endpoint must refuse requests without valid
+// credentials, and refuse a tenant access to another tenant's resources.
+// Each endpoint and case that got through is reported.
+func TestAuthorizationEnforced(t *testing.T) {
+	services := []struct {
+		url     string
+		flag    string
+		service func(baseURL, secret string) authz.Service
+		secret  string
+	}{
+		{*paymentsURL, "-payments-url", authz.Payments, *paymentsJWTSecret},
+		{*massLiveURL, "-mass-live-url", authz.MassLive, *massLiveJWTSecret},
+	}
+	checker := authz.Checker{Client: &http.Client{Timeout: 10 * time.Second}}
+
+	for _, s := range services {
+		svc := s.service(s.url, s.secret)
+		t.Run(svc.Name, func(t *testing.T) {
+			if s.url == "" || *offline {
+				t.Skipf("needs %s; pass %s", svc.Name, s.flag)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+			for _, f := range checker.Check(ctx, svc) {
+				t.Error(f)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package integration
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+	"github.com/suuupra/upi-bank-integration-tests/internal/faultproxy"
+)
+
+// chaos enables the fault scenarios. They need UPI Core's dependencies
+// routed through cmd/faultproxy, with the control APIs given in
+// CHAOS_BANK_PROXY and CHAOS_REDIS_PROXY. The suite's own Bank Simulator
+// client still connects directly, so balances can be checked mid-fault.
+var chaos = flag.Bool("chaos", false, "run the fault-injection scenarios")
+
+// Bounds the scenarios hold UPI Core to.
+const (
+	// BankCallBound is the longest UPI Core may wait on an unresponsive bank
+	// before giving up on the transaction.
+	BankCallBound = 10 * time.Second
+	// FastFailBound is how quickly calls must fail once the circuit breaker
+	// for a bank is open.
+	FastFailBound = time.Second
+	// SettleBound is how long a faulted transaction may take to reach a
+	// final state after the fault is cleared.
+	SettleBound = 30 * time.Second
+)
+
+// chaosProxy returns the control client for the proxy named by env, skipping
+// the test when chaos scenarios are off or the proxy is not configured.
+func chaosProxy(t *testing.T, env string) *faultproxy.Client {
+	t.Helper()
+	if !*chaos || *offline {
+		t.Skip("chaos scenarios need -chaos and the services behind cmd/faultproxy")
+	}
+	url := os.Getenv(env)
+	if url == "" {
+		t.Skipf("%s is not set", env)
+	}
+	client := faultproxy.NewClient(url)
+	t.Cleanup(func() {
+		// Leave the environment healthy for the next scenario
+		if err := client.Reset(context.Background()); err != nil {
+			t.Errorf("failed to clear faults: %v", err)
+		}
+	})
+	return client
+}
+
+func (suite *IntegrationTestSuite) balance(t *testing.T, party *testParty) int64 {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := suite.bankSimClient.GetAccountBalance(ctx, &banksim.AccountBalanceRequest{
+		BankCode:      party.bank,
+		AccountNumber: party.account,
+	})
+	require.NoError(t, err)
+	return resp.AvailableBalancePaisa
+}
+
+func (suite *IntegrationTestSuite) pay(ctx context.Context, payer, payee *testParty, amount int64) (*upicore.TransactionResponse, string, error) {
+	id := uuid.New().String()
+	resp, err := suite.upiCoreClient.ProcessTransaction(ctx, &upicore.TransactionRequest{
+		TransactionId: "CHAOS_" + id,
+		Rrn:           id[:12],
+		PayerVpa:      payer.vpa,
+		PayeeVpa:      payee.vpa,
+		AmountPaisa:   amount,
+		Currency:      "INR",
+		Type:          upicore.TransactionType_TRANSACTION_TYPE_P2P,
+		Reference:     "CHAOS_TEST",
+		InitiatedAt:   timestamppb.Now(),
+	})
+	return resp, "CHAOS_" + id, err
+}
+
+// settle polls UPI Core until the transaction leaves PENDING.
+func (suite *IntegrationTestSuite) settle(t *testing.T, transactionID string) upicore.TransactionStatus {
+	t.Helper()
+	return suite.settleWithin(t, transactionID, SettleBound)
+}
+
+// settleWithin is settle with its own bound.
+func (suite *IntegrationTestSuite) settleWithin(t *testing.T, transactionID string, bound time.Duration) upicore.TransactionStatus {
+	t.Helper()
+	deadline := time.Now().Add(bound)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		resp, err := suite.upiCoreClient.GetTransactionStatus(ctx, &upicore.TransactionStatusRequest{TransactionId: transactionID})
+		cancel()
+		if err == nil && resp.Status != upicore.TransactionStatus_TRANSACTION_STATUS_PENDING {
+			return resp.Status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("transaction %s did not settle within %v (last error %v)", transactionID, bound, err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// assertConserved checks that money was neither created nor lost: either
+// the payment completed in full or both balances are back where they were.
+func (suite *IntegrationTestSuite) assertConserved(t *testing.T, final upicore.TransactionStatus, payer, payee *testParty, payerBefore, payeeBefore, amount int64) {
+	t.Helper()
+	payerAfter, payeeAfter := suite.balance(t, payer), suite.balance(t, payee)
+	if final == upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
+		assert.Equal(t, payerBefore-amount, payerAfter, "payer debited once")
+		assert.Equal(t, payeeBefore+amount, payeeAfter, "payee credited once")
+		return
+	}
+	assert.Equal(t, payerBefore, payerAfter, "payer debit reversed after %s", final)
+	assert.Equal(t, payeeBefore, payeeAfter, "payee not credited after %s", final)
+}
+
+// Chaos 1: the bank simulator dies mid-transaction and comes back. A
+// transaction caught in flight must end fully applied or fully reversed.
+func TestChaosBankKilledMidTransaction(t *testing.T) {
+	bankProxy := chaosProxy(t, "CHAOS_BANK_PROXY")
+	suite, err := setupTestSuite()
+	require.NoError(t, err)
+	defer suite.cleanup()
+
+	payer, payee := suite.CreateFundedAccountPair(t, InitialDepositPaisa, InitialDepositPaisa)
+	suite.LinkVPAs(t, payer, payee)
+	payerBefore, payeeBefore := suite.balance(t, payer), suite.balance(t, payee)
+
+	// Slow the bank so the kill lands between the debit and the credit
+	ctx := context.Background()
+	require.NoError(t, bankProxy.SetFaults(ctx, faultproxy.Faults{Latency: 300 * time.Millisecond}))
+
+	type result struct {
+		resp *upicore.TransactionResponse
+		id   string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		callCtx, cancel := context.WithTimeout(ctx, BankCallBound+5*time.Second)
+		defer cancel()
+		resp, id, err := suite.pay(callCtx, payer, payee, TransactionAmount)
+		done <- result{resp, id, err}
+	}()
+
+	time.Sleep(time.Second)
+	require.NoError(t, bankProxy.SetFaults(ctx, faultproxy.Faults{Partitioned: true}))
+	require.NoError(t, bankProxy.KillConnections(ctx))
+	time.Sleep(2 * time.Second)
+	require.NoError(t, bankProxy.Reset(ctx))
+
+	r := <-done
+	if r.err == nil {
+		assert.NotEqual(t, upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS, r.resp.Status,
+			"transaction reported success while the bank was down")
+	}
+	final := suite.settle(t, r.id)
+	t.Logf("Transaction %s settled as %s (initial error: %v)", r.id, final, r.err)
+	suite.assertConserved(t, final, payer, payee, payerBefore, payeeBefore, TransactionAmount)
+}
+
+// Chaos 2: every bank call takes 5s. UPI Core must answer within its own
+// bound instead of holding the caller for as long as the bank does, and
+// must not leave money half-moved.
+func TestChaosBankLatency(t *testing.T) {
+	bankProxy := chaosProxy(t, "CHAOS_BANK_PROXY")
+	suite, err := setupTestSuite()
+	require.NoError(t, err)
+	defer suite.cleanup()
+
+	payer, payee := suite.CreateFundedAccountPair(t, InitialDepositPaisa, InitialDepositPaisa)
+	suite.LinkVPAs(t, payer, payee)
+	payerBefore, payeeBefore := suite.balance(t, payer), suite.balance(t, payee)
+
+	ctx := context.Background()
+	require.NoError(t, bankProxy.SetFaults(ctx, faultproxy.Faults{Latency: 5 * time.Second}))
+
+	callCtx, cancel := context.WithTimeout(ctx, BankCallBound+5*time.Second)
+	defer cancel()
+	start := time.Now()
+	resp, id, err := suite.pay(callCtx, payer, payee, TransactionAmount)
+	took := time.Since(start)
+
+	assert.LessOrEqual(t, took, BankCallBound, "UPI Core waited %v on a slow bank", took)
+	if err != nil {
+		assert.Contains(t, []codes.Code{codes.DeadlineExceeded, codes.Unavailable}, status.Code(err),
+			"unexpected error class: %v", err)
+	} else {
+		assert.Contains(t, []upicore.TransactionStatus{
+			upicore.TransactionStatus_TRANSACTION_STATUS_TIMEOUT,
+			upicore.TransactionStatus_TRANSACTION_STATUS_PENDING,
+			upicore.TransactionStatus_TRANSACTION_STATUS_FAILED,
+			upicore.TransactionStatus_TRANSACTION_STATUS_REVERSED,
+		}, resp.Status)
+	}
+
+	require.NoError(t, bankProxy.Reset(ctx))
+	final := suite.settle(t, id)
+	t.Logf("Slow-bank transaction %s answered in %v, settled as %s", id, took, final)
+	suite.assertConserved(t, final, payer, payee, payerBefore, payeeBefore, TransactionAmount)
+}
+
+// Chaos 3: Redis is partitioned away. UPI Core only uses Redis to cache VPA
+// mappings, so resolution must fall back to the database promptly rather
+// than hang on the cache.
+func TestChaosRedisPartition(t *testing.T) {
+	redisProxy := chaosProxy(t, "CHAOS_REDIS_PROXY")
+	suite, err := setupTestSuite()
+	require.NoError(t, err)
+	defer suite.cleanup()
+
+	party := suite.CreateFundedParty(t, TestBankCode, "PAYEE", InitialDepositPaisa)
+	suite.LinkVPAs(t, party)
+
+	ctx := context.Background()
+	require.NoError(t, redisProxy.SetFaults(ctx, faultproxy.Faults{Partitioned: true}))
+
+	callCtx, cancel := context.WithTimeout(ctx, BankCallBound)
+	defer cancel()
+	start := time.Now()
+	resp, err := suite.upiCoreClient.ResolveVPA(callCtx, &upicore.ResolveVPARequest{Vpa: party.vpa})
+	took := time.Since(start)
+
+	require.NoError(t, err, "VPA resolution failed while Redis was partitioned")
+	assert.True(t, resp.Exists)
+	assert.Equal(t, party.account, resp.AccountNumber)
+	assert.Less(t, took, 3*time.Second, "resolution took %v with Redis partitioned", took)
+}
+
+// Chaos 4: with the bank unreachable, repeated failures open the circuit
+// breaker and later calls fail fast; once the bank is back the breaker
+// lets traffic through again.
+func TestChaosCircuitBreaker(t *testing.T) {
+	bankProxy := chaosProxy(t, "CHAOS_BANK_PROXY")
+	suite, err := setupTestSuite()
+	require.NoError(t, err)
+	defer suite.cleanup()
+
+	payer, payee := suite.CreateFundedAccountPair(t, InitialDepositPaisa*10, InitialDepositPaisa)
+	suite.LinkVPAs(t, payer, payee)
+
+	ctx := context.Background()
+	require.NoError(t, bankProxy.SetFaults(ctx, faultproxy.Faults{Partitioned: true}))
+	require.NoError(t, bankProxy.KillConnections(ctx))
+
+	const attempts = 10
+	var last time.Duration
+	for i := 0; i < attempts; i++ {
+		callCtx, cancel := context.WithTimeout(ctx, BankCallBound+5*time.Second)
+		start := time.Now()
+		resp, _, err := suite.pay(callCtx, payer, payee, 100)
+		last = time.Since(start)
+		cancel()
+		if err == nil {
+			require.NotEqual(t, upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS, resp.Status,
+				"attempt %d succeeded with the bank unreachable", i)
+		}
+	}
+	assert.Less(t, last, FastFailBound, "call %d still took %v; breaker did not open", attempts, last)
+
+	require.NoError(t, bankProxy.Reset(ctx))
+	deadline := time.Now().Add(SettleBound)
+	for {
+		callCtx, cancel := context.WithTimeout(ctx, BankCallBound)
+		resp, _, err := suite.pay(callCtx, payer, payee, 100)
+		cancel()
+		if err == nil && resp.Status == upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("breaker did not close within %v of the bank recovering (last: %v)", SettleBound, err)
+		}
+		time.Sleep(time.Second)
+	}
+}
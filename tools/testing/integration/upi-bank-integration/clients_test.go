@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+	"github.com/suuupra/upi-bank-integration-tests/internal/report"
+)
+
+// offline runs the suite against in-process fakes, for working on the tests
+// themselves without the services running. It proves nothing about the
+// services; CI runs without it.
+var offline = flag.Bool("offline", false, "run against in-process fakes instead of the running services")
+
+// SamplesPerMethod is how many successful and how many failed calls of each
+// gRPC method -capture-samples keeps.
+const SamplesPerMethod = 3
+
+var (
+	captureSamples = flag.String("capture-samples", "", "file sample gRPC requests and responses are written to, for cmd/report")
+	samples        = report.NewRecorder(SamplesPerMethod)
+)
+
+// serviceClients holds the generated gRPC clients the suite talks to.
+type serviceClients struct {
+	bankSim banksim.BankSimulatorClient
+	upiCore upicore.UpiCoreClient
+	conns   []*grpc.ClientConn
+	servers []*grpc.Server
+}
+
+// dialServices connects to the Bank Simulator and UPI Core, or to fakes
+// when -offline is set.
+func dialServices() (*serviceClients, error) {
+	c := &serviceClients{}
+	bankAddr, upiAddr := *bankSimAddr, *upiCoreAddr
+	var dialer func(context.Context, string) (net.Conn, error)
+
+	if *offline {
+		bankAddr, upiAddr = "bank-simulator", "upi-core"
+		// The banks the Bank Simulator seeds
+		bank := newFakeBank("HDFC", "SBI", "ICICI", "AXIS", "KOTAK")
+		listeners := map[string]*bufconn.Listener{
+			bankAddr: c.serve(func(s *grpc.Server) { banksim.RegisterBankSimulatorServer(s, bank) }),
+			upiAddr:  c.serve(func(s *grpc.Server) { upicore.RegisterUpiCoreServer(s, newFakeUpiCore(bank)) }),
+		}
+		dialer = func(ctx context.Context, addr string) (net.Conn, error) {
+			return listeners[addr].DialContext(ctx)
+		}
+	}
+
+	bankConn, err := c.dial(bankAddr, dialer)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to connect to Bank Simulator: %v", err)
+	}
+	upiConn, err := c.dial(upiAddr, dialer)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to connect to UPI Core: %v", err)
+	}
+	c.bankSim = banksim.NewBankSimulatorClient(bankConn)
+	c.upiCore = upicore.NewUpiCoreClient(upiConn)
+	return c, nil
+}
+
+func (c *serviceClients) dial(addr string, dialer func(context.Context, string) (net.Conn, error)) (*grpc.ClientConn, error) {
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if dialer != nil {
+		opts = append(opts, grpc.WithContextDialer(dialer))
+	}
+	if *captureSamples != "" {
+		opts = append(opts, grpc.WithUnaryInterceptor(samples.UnaryClientInterceptor()))
+	}
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.conns = append(c.conns, conn)
+	return conn, nil
+}
+
+func (c *serviceClients) serve(register func(*grpc.Server)) *bufconn.Listener {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	register(srv)
+	go srv.Serve(lis)
+	c.servers = append(c.servers, srv)
+	return lis
+}
+
+// Close closes the connections and stops any fakes.
+func (c *serviceClients) Close() {
+	for _, conn := range c.conns {
+		conn.Close()
+	}
+	for _, srv := range c.servers {
+		srv.Stop()
+	}
+}
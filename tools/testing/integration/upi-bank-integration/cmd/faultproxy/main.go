@@ -0,0 +1,51 @@
+// Command faultproxy sits between a service and one of its dependencies and
+// injects faults on request, for the chaos scenarios in the suite:
+//
+//	go run ./cmd/faultproxy -listen :60050 -upstream bank-simulator:50050 -control :7070
+//
+// Point the service at -listen instead of the dependency, then drive the
+// faults through the HTTP control API on -control.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/suuupra/upi-bank-integration-tests/internal/faultproxy"
+)
+
+func main() {
+	listen := flag.String("listen", ":60050", "address to accept proxied connections on")
+	upstream := flag.String("upstream", "localhost:50050", "dependency address to forward to")
+	control := flag.String("control", ":7070", "address for the HTTP control API")
+	flag.Parse()
+
+	proxy, err := faultproxy.Listen(*listen, *upstream)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+	defer proxy.Close()
+
+	srv := &http.Server{Addr: *control, Handler: faultproxy.Handler(proxy), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Control API failed: %v", err)
+		}
+	}()
+	log.Printf("Proxying %s -> %s, control API on %s", proxy.Addr(), *upstream, *control)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(shutdownCtx)
+}
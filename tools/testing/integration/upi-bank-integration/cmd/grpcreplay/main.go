@@ -0,0 +1,67 @@
+// Command grpcreplay records the gRPC calls a service makes to a dependency
+// and replays them later without the dependency:
+//
+//	go run ./cmd/grpcreplay -mode record -listen :60050 -upstream bank-simulator:50050 -fixtures bank.jsonl
+//	go run ./cmd/grpcreplay -mode replay -listen :60050 -fixtures bank.jsonl
+//
+// Point the service at -listen instead of the dependency. Recording appends
+// to -fixtures, so several sessions can build up one file.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/suuupra/upi-bank-integration-tests/internal/grpcreplay"
+)
+
+func main() {
+	mode := flag.String("mode", "replay", "record or replay")
+	listen := flag.String("listen", ":60050", "address to accept calls on")
+	upstream := flag.String("upstream", "", "dependency address to record from (record mode)")
+	path := flag.String("fixtures", "fixtures.jsonl", "file exchanges are recorded to and replayed from")
+	match := flag.String("match", "exact", "how replayed calls are matched: exact (same request) or method (in recorded order)")
+	flag.Parse()
+
+	fixtures := &grpcreplay.Fixtures{Path: *path}
+	var proxy *grpcreplay.Proxy
+	var err error
+	switch *mode {
+	case "record":
+		if *upstream == "" {
+			log.Fatal("-upstream is required to record")
+		}
+		proxy, err = grpcreplay.Record(*listen, *upstream, fixtures)
+		if err == nil {
+			log.Printf("Recording %s -> %s to %s", proxy.Addr(), *upstream, *path)
+		}
+	case "replay":
+		var m grpcreplay.Match
+		switch *match {
+		case "exact":
+			m = grpcreplay.MatchExact
+		case "method":
+			m = grpcreplay.MatchMethod
+		default:
+			log.Fatalf("Unknown -match %q", *match)
+		}
+		proxy, err = grpcreplay.Replay(*listen, fixtures, m)
+		if err == nil {
+			log.Printf("Replaying %s on %s", *path, proxy.Addr())
+		}
+	default:
+		log.Fatalf("Unknown -mode %q", *mode)
+	}
+	if err != nil {
+		log.Fatalf("Failed to start: %v", err)
+	}
+	defer proxy.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+}
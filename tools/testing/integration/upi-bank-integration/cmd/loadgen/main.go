@@ -0,0 +1,124 @@
+// Command loadgen drives UPI Core's ProcessTransaction at a target rate and
+// fails when the run misses its SLOs:
+//
+//	go run ./cmd/loadgen -payer alice@hdfc -payee bob@hdfc \
+//	    -profile 30s:50,2m:200,30s:0 -slo-p99 500ms -slo-success 0.99
+//
+// The payer and payee VPAs must already exist and the payer must hold
+// enough balance for the whole run.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+	"github.com/suuupra/upi-bank-integration-tests/internal/loadgen"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:50052", "UPI Core gRPC address")
+	payer := flag.String("payer", "", "payer VPA (required)")
+	payee := flag.String("payee", "", "payee VPA (required)")
+	amount := flag.Int64("amount", 100, "amount per transaction in paisa")
+	tps := flag.Float64("tps", 50, "constant rate, when -profile is not set")
+	duration := flag.Duration("duration", time.Minute, "run length, when -profile is not set")
+	profileFlag := flag.String("profile", "", "ramp profile as duration:tps stages, e.g. 30s:50,2m:200,30s:0")
+	concurrency := flag.Int("concurrency", 100, "maximum requests in flight")
+	timeout := flag.Duration("timeout", 5*time.Second, "per-request timeout")
+	sloP99 := flag.Duration("slo-p99", 500*time.Millisecond, "fail if p99 latency exceeds this (0 disables)")
+	sloSuccess := flag.Float64("slo-success", 0.99, "fail if the success rate falls below this (0 disables)")
+	jsonOut := flag.String("json", "", "also write the report as JSON to this file")
+	flag.Parse()
+
+	if *payer == "" || *payee == "" {
+		log.Fatal("-payer and -payee are required")
+	}
+	profile := loadgen.ConstantProfile(*tps, *duration)
+	if *profileFlag != "" {
+		var err error
+		if profile, err = loadgen.ParseProfile(*profileFlag); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to UPI Core: %v", err)
+	}
+	defer conn.Close()
+	client := upicore.NewUpiCoreClient(conn)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Driving %s for %v (concurrency %d)", *addr, profile.Duration(), *concurrency)
+	report := loadgen.Run(ctx, loadgen.Config{
+		Profile:        profile,
+		Concurrency:    *concurrency,
+		RequestTimeout: *timeout,
+	}, func(ctx context.Context) loadgen.Outcome {
+		return processTransaction(ctx, client, *payer, *payee, *amount)
+	})
+
+	report.WriteText(os.Stdout)
+	if *jsonOut != "" {
+		raw, err := json.MarshalIndent(report, "", "  ")
+		if err == nil {
+			err = os.WriteFile(*jsonOut, raw, 0o644)
+		}
+		if err != nil {
+			log.Printf("Failed to write JSON report: %v", err)
+		}
+	}
+
+	violations := report.Violations(loadgen.SLO{MaxP99: *sloP99, MinSuccessRate: *sloSuccess})
+	for _, v := range violations {
+		fmt.Fprintf(os.Stderr, "SLO violated: %s\n", v)
+	}
+	if len(violations) > 0 {
+		os.Exit(1)
+	}
+}
+
+// processTransaction sends one payment. Failures are classed by gRPC code
+// for transport errors and by status and error code for declines.
+func processTransaction(ctx context.Context, client upicore.UpiCoreClient, payer, payee string, amount int64) loadgen.Outcome {
+	id := uuid.New().String()
+	resp, err := client.ProcessTransaction(ctx, &upicore.TransactionRequest{
+		TransactionId: "LOAD_" + id,
+		Rrn:           id[:12],
+		PayerVpa:      payer,
+		PayeeVpa:      payee,
+		AmountPaisa:   amount,
+		Currency:      "INR",
+		Type:          upicore.TransactionType_TRANSACTION_TYPE_P2P,
+		Reference:     "LOAD_TEST",
+		InitiatedAt:   timestamppb.Now(),
+	})
+	if err != nil {
+		return loadgen.Outcome{Class: "grpc_" + status.Code(err).String()}
+	}
+	switch resp.Status {
+	case upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS, upicore.TransactionStatus_TRANSACTION_STATUS_PENDING:
+		return loadgen.Outcome{OK: true}
+	}
+	class := resp.Status.String()
+	if resp.ErrorCode != "" {
+		class += "/" + resp.ErrorCode
+	}
+	return loadgen.Outcome{Class: class}
+}
@@ -0,0 +1,107 @@
+// Command prober runs the suite's health, VPA and transaction checks
+// against a live environment on an interval and exports the results as
+// Prometheus metrics, for alerting when UPI stops working end to end:
+//
+//	go run ./cmd/prober -bank-sim-addr bank-simulator:50050 \
+//	    -upi-core-addr upi-core:50052 -vpa-a probe.a@hdfc -vpa-b probe.b@sbi
+//
+// The transaction probe pays -amount from -vpa-a to -vpa-b and back each
+// round, so both accounts need that much headroom but are not drained.
+// Without the VPAs only the health probes run.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+	"github.com/suuupra/upi-bank-integration-tests/internal/probe"
+)
+
+func main() {
+	bankSimAddr := flag.String("bank-sim-addr", "localhost:50050", "Bank Simulator gRPC address")
+	upiCoreAddr := flag.String("upi-core-addr", "localhost:50052", "UPI Core gRPC address")
+	bank := flag.String("bank", "HDFC", "bank whose health is probed")
+	vpaA := flag.String("vpa-a", "", "first VPA of the transaction probe; empty disables VPA and transaction probes")
+	vpaB := flag.String("vpa-b", "", "second VPA of the transaction probe")
+	amount := flag.Int64("amount", 100, "transaction probe amount in paisa")
+	interval := flag.Duration("interval", 30*time.Second, "time between probe rounds")
+	timeout := flag.Duration("timeout", 10*time.Second, "per-probe timeout")
+	listen := flag.String("listen", ":9115", "address serving /metrics")
+	flag.Parse()
+
+	if (*vpaA == "") != (*vpaB == "") {
+		log.Fatal("set both -vpa-a and -vpa-b, or neither")
+	}
+
+	bankConn, err := dial(*bankSimAddr)
+	if err != nil {
+		log.Fatalf("Failed to connect to Bank Simulator: %v", err)
+	}
+	defer bankConn.Close()
+	upiConn, err := dial(*upiCoreAddr)
+	if err != nil {
+		log.Fatalf("Failed to connect to UPI Core: %v", err)
+	}
+	defer upiConn.Close()
+	upiCore := upicore.NewUpiCoreClient(upiConn)
+
+	probes := []probe.Probe{
+		{Name: "bank_health", Check: probe.BankHealth(banksim.NewBankSimulatorClient(bankConn), *bank)},
+		{Name: "upi_core_health", Check: probe.GRPCHealth(grpc_health_v1.NewHealthClient(upiConn))},
+	}
+	if *vpaA != "" {
+		probes = append(probes,
+			probe.Probe{Name: "vpa_resolution", Check: probe.ResolveVPA(upiCore, *vpaA)},
+			probe.Probe{Name: "transaction", Check: probe.RoundTrip(upiCore, *vpaA, *vpaB, *amount)},
+		)
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	metrics := probe.NewMetrics(reg)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	srv := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Metrics server failed: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Probing every %v; metrics on %s/metrics", *interval, *listen)
+	probe.Loop(ctx, *interval, *timeout, probes, metrics, func(results []probe.Result) {
+		for _, r := range results {
+			if r.Err != nil {
+				log.Printf("FAIL %s after %v: %v", r.Probe, r.Duration.Round(time.Millisecond), r.Err)
+			}
+		}
+	})
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(shutdownCtx)
+}
+
+func dial(addr string) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
@@ -0,0 +1,92 @@
+// Command report turns a suite run into build artifacts: JUnit XML for CI
+// and an HTML summary of scenarios, latency and sample traffic.
+//
+//	go test -json . -perf-results perf-results.jsonl -capture-samples samples.jsonl > test_results.json
+//	go run ./cmd/loadgen -payer a@hdfc -payee b@sbi -json load.json
+//	go run ./cmd/report -go-test-json test_results.json -perf-results perf-results.jsonl \
+//	    -samples samples.jsonl -load load.json -out reports
+//
+// It writes reports/junit.xml and reports/index.html. Only -go-test-json
+// is required; missing optional inputs leave their section out.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/suuupra/upi-bank-integration-tests/internal/perfbaseline"
+	"github.com/suuupra/upi-bank-integration-tests/internal/report"
+)
+
+func main() {
+	goTestJSON := flag.String("go-test-json", "", "output of go test -json (required)")
+	perfResults := flag.String("perf-results", "", "performance results store, for the p95 trend charts")
+	samples := flag.String("samples", "", "samples captured with the suite's -capture-samples")
+	load := flag.String("load", "", "comma-separated cmd/loadgen -json reports")
+	out := flag.String("out", "reports", "directory junit.xml and index.html are written to")
+	title := flag.String("title", "UPI Integration Test Report", "HTML page title")
+	flag.Parse()
+
+	if *goTestJSON == "" {
+		log.Fatal("-go-test-json is required")
+	}
+	f, err := os.Open(*goTestJSON)
+	if err != nil {
+		log.Fatal(err)
+	}
+	packages, err := report.ParseGoTestJSON(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("%s: %v", *goTestJSON, err)
+	}
+
+	summary := report.Summary{Title: *title, Generated: time.Now(), Packages: packages}
+	if *perfResults != "" {
+		if summary.Perf, err = (perfbaseline.Store{Path: *perfResults}).Load(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *samples != "" {
+		if summary.Samples, err = report.ReadSamples(*samples); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *load != "" {
+		for _, path := range strings.Split(*load, ",") {
+			run := report.LoadRun{Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))}
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := json.Unmarshal(raw, &run.Report); err != nil {
+				log.Fatalf("%s: %v", path, err)
+			}
+			summary.Load = append(summary.Load, run)
+		}
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	write(filepath.Join(*out, "junit.xml"), func(f *os.File) error { return report.WriteJUnit(f, packages) })
+	write(filepath.Join(*out, "index.html"), func(f *os.File) error { return report.WriteHTML(f, summary) })
+}
+
+func write(path string, render func(*os.File) error) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := render(f); err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Wrote %s", path)
+}
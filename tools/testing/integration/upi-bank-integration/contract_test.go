@@ -0,0 +1,288 @@
+package integration
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+)
+
+// The contract tests check that the suite, UPI Core and the Bank Simulator
+// agree on the protos in the services tree. They read sources only, so they
+// run the same with or without -offline.
+
+const servicesDir = "../../../../services"
+
+var (
+	bankSimProto   = filepath.Join(servicesDir, "bank-simulator/proto/bank_simulator.proto")
+	bankSimServer  = filepath.Join(servicesDir, "bank-simulator/src/grpc/server.ts")
+	upiCoreProto   = filepath.Join(servicesDir, "upi-core/proto/upi_core.proto")
+	bankClientDir  = filepath.Join(servicesDir, "upi-core/internal/infrastructure/bankclient")
+	simulatorEntry = regexp.MustCompile(`^\s+(\w+): bankSimulatorService\.`)
+	bankClientCall = regexp.MustCompile(`\.client\(\)\.(\w+)\(`)
+)
+
+// protoSchema is the part of a proto file a peer depends on: field numbers
+// and types, enum values and RPC signatures, keyed by name.
+type protoSchema struct {
+	Messages map[string]map[string]string // message -> field -> "number type"
+	Enums    map[string]map[string]string // enum -> value -> number
+	RPCs     map[string]string            // rpc -> "(in) returns (out)"
+}
+
+func newProtoSchema() protoSchema {
+	return protoSchema{
+		Messages: map[string]map[string]string{},
+		Enums:    map[string]map[string]string{},
+		RPCs:     map[string]string{},
+	}
+}
+
+var (
+	protoBlock = regexp.MustCompile(`^(message|enum|service)\s+(\w+)\s*\{(\s*\})?$`)
+	protoField = regexp.MustCompile(`^(repeated\s+)?(map<\s*\w+\s*,\s*[\w.]+\s*>|[\w.]+)\s+(\w+)\s*=\s*(\d+);$`)
+	protoValue = regexp.MustCompile(`^(\w+)\s*=\s*(-?\d+);$`)
+	protoRPC   = regexp.MustCompile(`^rpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\);$`)
+)
+
+// parseProto reads the schema of a proto file. It handles the flat layout
+// the services' protos use: top-level messages, enums and services, with
+// no nesting, oneofs or options inside them.
+func parseProto(path string) (protoSchema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return protoSchema{}, err
+	}
+	defer f.Close()
+
+	schema := newProtoSchema()
+	var kind, name string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if kind == "" {
+			if m := protoBlock.FindStringSubmatch(line); m != nil {
+				kind, name = m[1], m[2]
+				switch kind {
+				case "message":
+					schema.Messages[name] = map[string]string{}
+				case "enum":
+					schema.Enums[name] = map[string]string{}
+				}
+				if m[3] != "" {
+					kind = ""
+				}
+			}
+			continue
+		}
+		if line == "}" {
+			kind = ""
+			continue
+		}
+
+		var m []string
+		switch kind {
+		case "message":
+			if m = protoField.FindStringSubmatch(line); m != nil {
+				typ := strings.Join(strings.Fields(m[2]), "")
+				if m[1] != "" {
+					typ = "repeated " + typ
+				}
+				schema.Messages[name][m[3]] = m[4] + " " + typ
+			}
+		case "enum":
+			if m = protoValue.FindStringSubmatch(line); m != nil {
+				schema.Enums[name][m[1]] = m[2]
+			}
+		case "service":
+			if m = protoRPC.FindStringSubmatch(line); m != nil {
+				schema.RPCs[m[1]] = rpcSignature(m[2] != "", m[3], m[4] != "", m[5])
+			}
+		}
+		if m == nil {
+			return protoSchema{}, fmt.Errorf("%s:%d: unsupported %s line %q", path, n, kind, line)
+		}
+	}
+	return schema, scanner.Err()
+}
+
+// schemaOf reads the schema a generated file descriptor was built from
+func schemaOf(file protoreflect.FileDescriptor) protoSchema {
+	schema := newProtoSchema()
+	pkg := string(file.Package()) + "."
+
+	for i := 0; i < file.Messages().Len(); i++ {
+		msg := file.Messages().Get(i)
+		fields := map[string]string{}
+		for j := 0; j < msg.Fields().Len(); j++ {
+			fd := msg.Fields().Get(j)
+			var typ string
+			switch {
+			case fd.IsMap():
+				typ = "map<" + fieldType(fd.MapKey(), pkg) + "," + fieldType(fd.MapValue(), pkg) + ">"
+			case fd.IsList():
+				typ = "repeated " + fieldType(fd, pkg)
+			default:
+				typ = fieldType(fd, pkg)
+			}
+			fields[string(fd.Name())] = fmt.Sprintf("%d %s", fd.Number(), typ)
+		}
+		schema.Messages[string(msg.Name())] = fields
+	}
+
+	for i := 0; i < file.Enums().Len(); i++ {
+		enum := file.Enums().Get(i)
+		values := map[string]string{}
+		for j := 0; j < enum.Values().Len(); j++ {
+			v := enum.Values().Get(j)
+			values[string(v.Name())] = fmt.Sprint(v.Number())
+		}
+		schema.Enums[string(enum.Name())] = values
+	}
+
+	for i := 0; i < file.Services().Len(); i++ {
+		methods := file.Services().Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			md := methods.Get(j)
+			schema.RPCs[string(md.Name())] = rpcSignature(
+				md.IsStreamingClient(), strings.TrimPrefix(string(md.Input().FullName()), pkg),
+				md.IsStreamingServer(), strings.TrimPrefix(string(md.Output().FullName()), pkg),
+			)
+		}
+	}
+	return schema
+}
+
+// fieldType names a field's type as the proto source spells it, relative
+// to the file's package
+func fieldType(fd protoreflect.FieldDescriptor, pkg string) string {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return strings.TrimPrefix(string(fd.Message().FullName()), pkg)
+	case protoreflect.EnumKind:
+		return strings.TrimPrefix(string(fd.Enum().FullName()), pkg)
+	default:
+		return fd.Kind().String()
+	}
+}
+
+func rpcSignature(clientStream bool, in string, serverStream bool, out string) string {
+	stream := func(streaming bool) string {
+		if streaming {
+			return "stream "
+		}
+		return ""
+	}
+	return fmt.Sprintf("(%s%s) returns (%s%s)", stream(clientStream), in, stream(serverStream), out)
+}
+
+// simulatorHandlers returns the RPCs the Bank Simulator registers handlers
+// for
+func simulatorHandlers(t *testing.T) []string {
+	f, err := os.Open(bankSimServer)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var handlers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := simulatorEntry.FindStringSubmatch(scanner.Text()); m != nil {
+			handlers = append(handlers, m[1])
+		}
+	}
+	require.NoError(t, scanner.Err())
+	require.NotEmpty(t, handlers, "no handlers found in %s", bankSimServer)
+	return handlers
+}
+
+// bankClientCalls returns the Bank Simulator RPCs UPI Core calls
+func bankClientCalls(t *testing.T) []string {
+	files, err := filepath.Glob(filepath.Join(bankClientDir, "*.go"))
+	require.NoError(t, err)
+
+	seen := map[string]bool{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		require.NoError(t, err)
+		for _, m := range bankClientCall.FindAllStringSubmatch(string(src), -1) {
+			seen[m[1]] = true
+		}
+	}
+	require.NotEmpty(t, seen, "no bank calls found in %s", bankClientDir)
+
+	calls := make([]string, 0, len(seen))
+	for call := range seen {
+		calls = append(calls, call)
+	}
+	sort.Strings(calls)
+	return calls
+}
+
+func TestContract_GeneratedClientsMatchProtos(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		proto string
+		file  protoreflect.FileDescriptor
+	}{
+		{"BankSimulator", bankSimProto, banksim.File_bank_simulator_proto},
+		{"UPICore", upiCoreProto, upicore.File_upi_core_proto},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want, err := parseProto(tc.proto)
+			require.NoError(t, err)
+			assert.Equal(t, want, schemaOf(tc.file),
+				"generated clients are stale against %s; regenerate them with ./generate.sh", tc.proto)
+		})
+	}
+}
+
+func TestContract_SimulatorServesProto(t *testing.T) {
+	schema, err := parseProto(bankSimProto)
+	require.NoError(t, err)
+
+	handlers := simulatorHandlers(t)
+	served := map[string]bool{}
+	for _, h := range handlers {
+		served[h] = true
+		assert.Contains(t, schema.RPCs, h, "%s registers %s, which is not in %s", bankSimServer, h, bankSimProto)
+	}
+	for rpc := range schema.RPCs {
+		assert.True(t, served[rpc], "%s has no handler for %s", bankSimServer, rpc)
+	}
+}
+
+func TestContract_UPICoreBankCallsAreServed(t *testing.T) {
+	schema, err := parseProto(bankSimProto)
+	require.NoError(t, err)
+
+	served := map[string]bool{}
+	for _, h := range simulatorHandlers(t) {
+		served[h] = true
+	}
+	for _, call := range bankClientCalls(t) {
+		assert.Contains(t, schema.RPCs, call, "UPI Core calls %s, which is not in %s", call, bankSimProto)
+		assert.True(t, served[call], "UPI Core calls %s, which the Bank Simulator does not serve", call)
+	}
+}
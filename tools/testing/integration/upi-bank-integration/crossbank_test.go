@@ -0,0 +1,128 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+)
+
+// SlowBankLatency is the delay injected into the slow bank's transactions,
+// enough to be visible in the payment's duration.
+const SlowBankLatency = 1500 * time.Millisecond
+
+// crossBankCase is one payment between two simulated banks, optionally
+// with a fault injected into one of them for its duration.
+type crossBankCase struct {
+	name         string
+	payer, payee string
+	deposit      int64 // Payer's opening balance
+	amount       int64
+	fault        *banksim.SetBankFaultRequest
+	want         upicore.TransactionStatus
+}
+
+var crossBankMatrix = []crossBankCase{
+	{name: "HDFC to SBI", payer: "HDFC", payee: "SBI", deposit: InitialDepositPaisa, amount: TransactionAmount, want: upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS},
+	{name: "SBI to ICICI", payer: "SBI", payee: "ICICI", deposit: InitialDepositPaisa, amount: TransactionAmount, want: upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS},
+	{name: "ICICI to HDFC", payer: "ICICI", payee: "HDFC", deposit: InitialDepositPaisa, amount: TransactionAmount, want: upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS},
+	{
+		// The payer is debited before the credit fails, so UPI Core must reverse it
+		name: "payee bank in maintenance", payer: "HDFC", payee: "SBI", deposit: InitialDepositPaisa, amount: TransactionAmount,
+		fault: &banksim.SetBankFaultRequest{BankCode: "SBI", Maintenance: true},
+		want:  upicore.TransactionStatus_TRANSACTION_STATUS_FAILED,
+	},
+	{
+		name: "payer bank in maintenance", payer: "SBI", payee: "ICICI", deposit: InitialDepositPaisa, amount: TransactionAmount,
+		fault: &banksim.SetBankFaultRequest{BankCode: "SBI", Maintenance: true},
+		want:  upicore.TransactionStatus_TRANSACTION_STATUS_FAILED,
+	},
+	{
+		name: "payee bank slow", payer: "HDFC", payee: "ICICI", deposit: InitialDepositPaisa, amount: TransactionAmount,
+		fault: &banksim.SetBankFaultRequest{BankCode: "ICICI", ExtraLatencyMs: int32(SlowBankLatency / time.Millisecond)},
+		want:  upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS,
+	},
+	{
+		name: "payer insufficient funds", payer: "SBI", payee: "ICICI", deposit: TransactionAmount / 2, amount: TransactionAmount,
+		want: upicore.TransactionStatus_TRANSACTION_STATUS_FAILED,
+	},
+}
+
+// Payments across banks reach the expected status, and money only moves
+// when they succeed: a failure at either bank leaves both balances where
+// they started. Faults are global to the Bank Simulator, so cases run one
+// at a time.
+func TestCrossBankTransactionMatrix(t *testing.T) {
+	suite, err := setupTestSuite()
+	require.NoError(t, err)
+	defer suite.cleanup()
+
+	for _, tc := range crossBankMatrix {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			payer := suite.CreateFundedParty(t, tc.payer, "PAYER", tc.deposit)
+			payee := suite.CreateFundedParty(t, tc.payee, "PAYEE", InitialDepositPaisa)
+			suite.LinkVPAs(t, payer, payee)
+			payerBefore, payeeBefore := suite.balance(t, payer), suite.balance(t, payee)
+
+			if tc.fault != nil {
+				suite.injectBankFault(t, tc.fault)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			start := time.Now()
+			resp, transactionID, err := suite.pay(ctx, payer, payee, tc.amount)
+			elapsed := time.Since(start)
+			require.NoError(t, err)
+
+			final := resp.Status
+			if final == upicore.TransactionStatus_TRANSACTION_STATUS_PENDING {
+				final = suite.settle(t, transactionID)
+			}
+			require.Equal(t, tc.want, final, "%s: %s", resp.ErrorCode, resp.ErrorMessage)
+			if final == upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
+				assert.Equal(t, tc.payer, resp.PayerBankCode)
+				assert.Equal(t, tc.payee, resp.PayeeBankCode)
+			}
+			if tc.fault != nil && tc.fault.ExtraLatencyMs > 0 {
+				assert.GreaterOrEqual(t, elapsed, SlowBankLatency, "injected latency not observed")
+			}
+			suite.assertConserved(t, final, payer, payee, payerBefore, payeeBefore, tc.amount)
+		})
+	}
+}
+
+// injectBankFault sets fault on its bank until the test ends, and checks
+// the bank reports it.
+func (suite *IntegrationTestSuite) injectBankFault(t *testing.T, fault *banksim.SetBankFaultRequest) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := suite.bankSimClient.SetBankFault(ctx, fault)
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+	t.Cleanup(func() { suite.clearBankFault(t, fault.BankCode) })
+
+	if fault.Maintenance {
+		health, err := suite.bankSimClient.CheckBankHealth(ctx, &banksim.BankHealthRequest{BankCode: fault.BankCode})
+		require.NoError(t, err)
+		require.Equal(t, banksim.HealthStatus_HEALTH_STATUS_MAINTENANCE, health.HealthStatus)
+	}
+}
+
+// clearBankFault brings bankCode back to health.
+func (suite *IntegrationTestSuite) clearBankFault(t *testing.T, bankCode string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := suite.bankSimClient.SetBankFault(ctx, &banksim.SetBankFaultRequest{BankCode: bankCode}); err != nil {
+		t.Errorf("clearing fault on %s: %v", bankCode, err)
+	}
+}
@@ -0,0 +1,3 @@
+-- One database per service, as in production
+CREATE DATABASE bank_simulator;
+CREATE DATABASE upi_core;
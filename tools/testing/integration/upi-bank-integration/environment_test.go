@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/internal/probe"
+	"github.com/suuupra/upi-bank-integration-tests/internal/report"
+	"github.com/suuupra/upi-bank-integration-tests/internal/testenv"
+)
+
+// Without addresses the suite starts its own environment from composeFile
+// and tears it down afterwards. Pass both addresses to run against services
+// that are already up.
+var (
+	bankSimAddr = flag.String("bank-sim-addr", "", "Bank Simulator gRPC address; empty starts the compose environment")
+	upiCoreAddr = flag.String("upi-core-addr", "", "UPI Core gRPC address; empty starts the compose environment")
+	keepEnv     = flag.Bool("keep-env", false, "leave the started environment running after the tests")
+)
+
+const (
+	composeFile = "environment/docker-compose.yml"
+	// Includes building the service images on a cold cache
+	startTimeout = 15 * time.Minute
+	readyTimeout = 2 * time.Minute
+)
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *offline || (*bankSimAddr != "" && *upiCoreAddr != "") {
+		os.Exit(runTests(m))
+	}
+	if *bankSimAddr != "" || *upiCoreAddr != "" {
+		log.Fatal("set both -bank-sim-addr and -upi-core-addr, or neither")
+	}
+
+	env, err := startEnvironment()
+	if err != nil {
+		log.Fatal(err)
+	}
+	code := runTests(m)
+	if *keepEnv {
+		log.Printf("Leaving environment running; stop it with: docker compose -f %s -p %s down -v", composeFile, env.Project())
+	} else if err := env.Down(context.Background()); err != nil {
+		log.Printf("Failed to tear down %s: %v", env.Project(), err)
+	}
+	os.Exit(code)
+}
+
+// runTests runs the tests and writes the samples they captured.
+func runTests(m *testing.M) int {
+	code := m.Run()
+	if *captureSamples != "" {
+		if err := report.WriteSamples(*captureSamples, samples.Samples()); err != nil {
+			log.Printf("Failed to write samples: %v", err)
+		}
+	}
+	return code
+}
+
+// startEnvironment brings up the compose environment, points the suite at
+// it and waits until both services answer health checks.
+func startEnvironment() (*testenv.Env, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	log.Printf("Starting test environment from %s", composeFile)
+	env, err := testenv.Up(ctx, testenv.Options{ComposeFile: composeFile})
+	if err != nil {
+		return nil, err
+	}
+	fail := func(err error) (*testenv.Env, error) {
+		logs := env.Logs(context.Background(), "bank-simulator", "upi-core")
+		env.Down(context.Background())
+		return nil, fmt.Errorf("%v\n%s", err, logs)
+	}
+
+	if *bankSimAddr, err = env.Addr(ctx, "bank-simulator", 50050); err != nil {
+		return fail(err)
+	}
+	if *upiCoreAddr, err = env.Addr(ctx, "upi-core", 50052); err != nil {
+		return fail(err)
+	}
+
+	readyCtx, readyCancel := context.WithTimeout(ctx, readyTimeout)
+	defer readyCancel()
+	// The test bank being healthy also proves migrations and seeding ran
+	bankSimReady := func(conn *grpc.ClientConn) probe.Check {
+		return probe.BankHealth(banksim.NewBankSimulatorClient(conn), TestBankCode)
+	}
+	upiCoreReady := func(conn *grpc.ClientConn) probe.Check {
+		return probe.GRPCHealth(grpc_health_v1.NewHealthClient(conn))
+	}
+	if err := waitReady(readyCtx, *bankSimAddr, bankSimReady); err != nil {
+		return fail(fmt.Errorf("Bank Simulator at %s not ready: %v", *bankSimAddr, err))
+	}
+	if err := waitReady(readyCtx, *upiCoreAddr, upiCoreReady); err != nil {
+		return fail(fmt.Errorf("UPI Core at %s not ready: %v", *upiCoreAddr, err))
+	}
+	log.Printf("Environment %s ready: Bank Simulator %s, UPI Core %s", env.Project(), *bankSimAddr, *upiCoreAddr)
+	return env, nil
+}
+
+func waitReady(ctx context.Context, addr string, check func(*grpc.ClientConn) probe.Check) error {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	ready := check(conn)
+	return testenv.WaitFor(ctx, 2*time.Second, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		return ready(ctx)
+	})
+}
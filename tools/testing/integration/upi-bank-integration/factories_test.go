@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+)
+
+// testParty is an account at one of the simulated banks, with its VPA once
+// linked.
+type testParty struct {
+	bank    string
+	account string
+	vpa     string
+}
+
+// newRunID returns the tag every customer ID and VPA created by one suite
+// starts with, so cleanup can delete exactly what the run created.
+func newRunID() string {
+	return "IT" + strings.ToUpper(strings.ReplaceAll(uuid.New().String(), "-", "")[:8])
+}
+
+// customerID returns a customer ID for this run, e.g. IT1A2B3C4D_PAYER_9f8e7d6c.
+func (suite *IntegrationTestSuite) customerID(kind string) string {
+	return fmt.Sprintf("%s_%s_%s", suite.runID, kind, uuid.New().String()[:8])
+}
+
+// vpa returns a VPA at the test bank for this run.
+func (suite *IntegrationTestSuite) vpa(kind string) string {
+	return suite.vpaAt(TestBankCode, kind)
+}
+
+// vpaAt returns a VPA at bank for this run.
+func (suite *IntegrationTestSuite) vpaAt(bank, kind string) string {
+	return strings.ToLower(suite.runID + kind + uuid.New().String()[:8] + "@" + bank)
+}
+
+// CreateFundedAccount opens a savings account at the test bank holding
+// depositPaisa.
+func (suite *IntegrationTestSuite) CreateFundedAccount(t testing.TB, kind string, depositPaisa int64) string {
+	t.Helper()
+	return suite.CreateFundedParty(t, TestBankCode, kind, depositPaisa).account
+}
+
+// CreateFundedParty opens a savings account at bank holding depositPaisa.
+func (suite *IntegrationTestSuite) CreateFundedParty(t testing.TB, bank, kind string, depositPaisa int64) *testParty {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := suite.bankSimClient.CreateAccount(ctx, &banksim.CreateAccountRequest{
+		BankCode:     bank,
+		CustomerId:   suite.customerID(kind),
+		AccountType:  banksim.AccountType_ACCOUNT_TYPE_SAVINGS,
+		MobileNumber: TestMobileNumber,
+		Email:        TestEmail,
+		KycDetails: &banksim.CustomerKYC{
+			Pan:           "ABCDE1234F",
+			AadhaarMasked: "****5678",
+			FullName:      "Test " + strings.ToLower(kind),
+			DateOfBirth:   "1990-01-01",
+			Address:       "Test Address, Test City",
+		},
+		InitialDepositPaisa: depositPaisa,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.AccountNumber, "account not created at %s: %s %s", bank, resp.ErrorCode, resp.ErrorMessage)
+	suite.testAccounts = append(suite.testAccounts, resp.AccountNumber)
+	return &testParty{bank: bank, account: resp.AccountNumber}
+}
+
+// CreateFundedAccountPair opens a payer and a payee account at the test
+// bank.
+func (suite *IntegrationTestSuite) CreateFundedAccountPair(t testing.TB, payerDepositPaisa, payeeDepositPaisa int64) (payer, payee *testParty) {
+	t.Helper()
+	payer = suite.CreateFundedParty(t, TestBankCode, "PAYER", payerDepositPaisa)
+	payee = suite.CreateFundedParty(t, TestBankCode, "PAYEE", payeeDepositPaisa)
+	return payer, payee
+}
+
+// LinkVPAs links a new primary VPA to each party's account.
+func (suite *IntegrationTestSuite) LinkVPAs(t testing.TB, parties ...*testParty) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, party := range parties {
+		vpa := suite.vpaAt(party.bank, "vpa")
+		resp, err := suite.bankSimClient.LinkVPA(ctx, &banksim.LinkVPARequest{
+			Vpa:           vpa,
+			BankCode:      party.bank,
+			AccountNumber: party.account,
+			IsPrimary:     true,
+		})
+		require.NoError(t, err)
+		require.True(t, resp.Success, "VPA not linked: %s %s", resp.ErrorCode, resp.ErrorMessage)
+		party.vpa = vpa
+		suite.testVPAs = append(suite.testVPAs, vpa)
+	}
+}
+
+// purgeTestData deletes everything this run created from the Bank Simulator.
+func (suite *IntegrationTestSuite) purgeTestData() (*banksim.PurgeTestDataResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return suite.bankSimClient.PurgeTestData(ctx, &banksim.PurgeTestDataRequest{
+		CustomerIdPrefix: suite.runID + "_",
+		VpaPrefix:        strings.ToLower(suite.runID),
+	})
+}
+
+func TestFactoriesAndCleanup(t *testing.T) {
+	suite, err := setupTestSuite()
+	require.NoError(t, err)
+	defer suite.cleanup()
+
+	payer, payee := suite.CreateFundedAccountPair(t, InitialDepositPaisa*2, InitialDepositPaisa)
+	suite.LinkVPAs(t, payer, payee)
+	require.NotEqual(t, payer.vpa, payee.vpa)
+	require.True(t, strings.HasPrefix(payer.vpa, strings.ToLower(suite.runID)))
+
+	// Another run's data must survive this run's cleanup
+	other := &IntegrationTestSuite{clients: suite.clients, bankSimClient: suite.bankSimClient, runID: newRunID()}
+	bystander := other.CreateFundedParty(t, TestBankCode, "BYSTANDER", InitialDepositPaisa)
+	other.LinkVPAs(t, bystander)
+	defer other.purgeTestData()
+
+	resp, err := suite.purgeTestData()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, resp.AccountsDeleted)
+	require.EqualValues(t, 2, resp.VpasDeleted)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resolved, err := suite.bankSimClient.ResolveVPA(ctx, &banksim.ResolveVPARequest{Vpa: payer.vpa})
+	require.NoError(t, err)
+	require.False(t, resolved.Exists, "purged VPA still resolves")
+	resolved, err = suite.bankSimClient.ResolveVPA(ctx, &banksim.ResolveVPARequest{Vpa: bystander.vpa})
+	require.NoError(t, err)
+	require.True(t, resolved.Exists, "another run's VPA was purged")
+}
@@ -0,0 +1,560 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+)
+
+// fakeBank is a small in-memory Bank Simulator used with -offline. It keeps
+// real balances so failure cases behave like the service, but only
+// implements the calls the suite makes.
+type fakeBank struct {
+	banksim.UnimplementedBankSimulatorServer
+
+	codes map[string]bool // Banks it simulates
+
+	mu        sync.Mutex
+	seq       int
+	accounts  map[string]*banksim.AccountDetailsResponse
+	banks     map[string]string // Account number to bank code
+	customers map[string]string // Account number to customer ID
+	vpas      map[string]string // VPA to account number
+	txns      map[string]*banksim.TransactionResponse
+	txnOwners map[string]string // Transaction ID to account number
+	faults    map[string]*banksim.SetBankFaultRequest
+}
+
+func newFakeBank(codes ...string) *fakeBank {
+	b := &fakeBank{
+		codes:     make(map[string]bool),
+		accounts:  make(map[string]*banksim.AccountDetailsResponse),
+		banks:     make(map[string]string),
+		customers: make(map[string]string),
+		vpas:      make(map[string]string),
+		txns:      make(map[string]*banksim.TransactionResponse),
+		txnOwners: make(map[string]string),
+		faults:    make(map[string]*banksim.SetBankFaultRequest),
+	}
+	for _, code := range codes {
+		b.codes[code] = true
+	}
+	return b
+}
+
+func (b *fakeBank) CreateAccount(ctx context.Context, req *banksim.CreateAccountRequest) (*banksim.CreateAccountResponse, error) {
+	if !b.codes[req.BankCode] {
+		return &banksim.CreateAccountResponse{ErrorCode: "BANK_NOT_FOUND", ErrorMessage: "unknown bank " + req.BankCode}, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	account := &banksim.AccountDetailsResponse{
+		AccountNumber:         fmt.Sprintf("%016d", b.seq),
+		IfscCode:              fmt.Sprintf("%s0%06d", req.BankCode, 1),
+		AccountHolderName:     req.GetKycDetails().GetFullName(),
+		AccountType:           req.AccountType,
+		Status:                banksim.AccountStatus_ACCOUNT_STATUS_ACTIVE,
+		MobileNumber:          req.MobileNumber,
+		Email:                 req.Email,
+		AvailableBalancePaisa: req.InitialDepositPaisa,
+		DailyLimitPaisa:       10000000,
+		CreatedAt:             timestamppb.Now(),
+	}
+	b.accounts[account.AccountNumber] = account
+	b.banks[account.AccountNumber] = req.BankCode
+	b.customers[account.AccountNumber] = req.CustomerId
+	return &banksim.CreateAccountResponse{
+		AccountNumber: account.AccountNumber,
+		IfscCode:      account.IfscCode,
+		Status:        account.Status,
+	}, nil
+}
+
+func (b *fakeBank) GetAccountDetails(ctx context.Context, req *banksim.AccountDetailsRequest) (*banksim.AccountDetailsResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	account, ok := b.accounts[req.AccountNumber]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "account %s not found", req.AccountNumber)
+	}
+	return proto.Clone(account).(*banksim.AccountDetailsResponse), nil
+}
+
+func (b *fakeBank) GetAccountBalance(ctx context.Context, req *banksim.AccountBalanceRequest) (*banksim.AccountBalanceResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	account, ok := b.accounts[req.AccountNumber]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "account %s not found", req.AccountNumber)
+	}
+	return &banksim.AccountBalanceResponse{
+		AccountNumber:         account.AccountNumber,
+		AvailableBalancePaisa: account.AvailableBalancePaisa,
+		LedgerBalancePaisa:    account.AvailableBalancePaisa,
+		LastUpdated:           timestamppb.Now(),
+	}, nil
+}
+
+func (b *fakeBank) LinkVPA(ctx context.Context, req *banksim.LinkVPARequest) (*banksim.LinkVPAResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.accounts[req.AccountNumber]; !ok {
+		return &banksim.LinkVPAResponse{ErrorCode: "ACCOUNT_NOT_FOUND", ErrorMessage: "account not found"}, nil
+	}
+	if _, ok := b.vpas[req.Vpa]; ok {
+		return &banksim.LinkVPAResponse{ErrorCode: "VPA_EXISTS", ErrorMessage: "VPA already linked"}, nil
+	}
+	b.vpas[req.Vpa] = req.AccountNumber
+	return &banksim.LinkVPAResponse{Success: true}, nil
+}
+
+func (b *fakeBank) UnlinkVPA(ctx context.Context, req *banksim.UnlinkVPARequest) (*banksim.UnlinkVPAResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.vpas[req.Vpa]; !ok {
+		return &banksim.UnlinkVPAResponse{ErrorCode: "VPA_NOT_FOUND", ErrorMessage: "VPA not found"}, nil
+	}
+	delete(b.vpas, req.Vpa)
+	return &banksim.UnlinkVPAResponse{Success: true}, nil
+}
+
+func (b *fakeBank) ResolveVPA(ctx context.Context, req *banksim.ResolveVPARequest) (*banksim.ResolveVPAResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	number, ok := b.vpas[req.Vpa]
+	if !ok {
+		return &banksim.ResolveVPAResponse{ErrorCode: "VPA_NOT_FOUND", ErrorMessage: "VPA not found"}, nil
+	}
+	account := b.accounts[number]
+	return &banksim.ResolveVPAResponse{
+		Exists:            true,
+		BankCode:          b.banks[number],
+		AccountNumber:     number,
+		AccountHolderName: account.AccountHolderName,
+		IsActive:          account.Status == banksim.AccountStatus_ACCOUNT_STATUS_ACTIVE,
+	}, nil
+}
+
+// ProcessTransaction debits or credits an account. Retrying a transaction
+// ID returns the first outcome.
+func (b *fakeBank) ProcessTransaction(ctx context.Context, req *banksim.TransactionRequest) (*banksim.TransactionResponse, error) {
+	fault := b.fault(req.BankCode)
+	if fault.ExtraLatencyMs > 0 {
+		select {
+		case <-time.After(time.Duration(fault.ExtraLatencyMs) * time.Millisecond):
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if resp, ok := b.txns[req.TransactionId]; ok {
+		return resp, nil
+	}
+
+	resp := &banksim.TransactionResponse{TransactionId: req.TransactionId, ProcessedAt: timestamppb.Now()}
+	account, ok := b.accounts[req.AccountNumber]
+	switch {
+	case fault.Maintenance:
+		resp.Status = banksim.TransactionStatus_TRANSACTION_STATUS_FAILED
+		resp.ErrorCode = "BNK_003"
+		resp.ErrorMessage = "Bank is under maintenance: " + req.BankCode
+	case fault.DeclineType != banksim.TransactionType_TRANSACTION_TYPE_UNSPECIFIED && req.Type == fault.DeclineType:
+		resp.Status = banksim.TransactionStatus_TRANSACTION_STATUS_FAILED
+		resp.ErrorCode = "SYS_001"
+		resp.ErrorMessage = "Simulated bank system failure: " + req.Type.String() + " declined"
+	case !ok || b.banks[req.AccountNumber] != req.BankCode:
+		resp.Status = banksim.TransactionStatus_TRANSACTION_STATUS_INVALID_ACCOUNT
+		resp.ErrorCode = "ACCOUNT_NOT_FOUND"
+	case req.AmountPaisa <= 0:
+		resp.Status = banksim.TransactionStatus_TRANSACTION_STATUS_FAILED
+		resp.ErrorCode = "INVALID_AMOUNT"
+	case req.Type == banksim.TransactionType_TRANSACTION_TYPE_DEBIT && account.AvailableBalancePaisa < req.AmountPaisa:
+		resp.Status = banksim.TransactionStatus_TRANSACTION_STATUS_INSUFFICIENT_FUNDS
+		resp.ErrorCode = "INSUFFICIENT_FUNDS"
+		resp.AccountBalancePaisa = account.AvailableBalancePaisa
+	case req.Type == banksim.TransactionType_TRANSACTION_TYPE_DEBIT:
+		account.AvailableBalancePaisa -= req.AmountPaisa
+	case req.Type == banksim.TransactionType_TRANSACTION_TYPE_CREDIT:
+		account.AvailableBalancePaisa += req.AmountPaisa
+	default:
+		resp.Status = banksim.TransactionStatus_TRANSACTION_STATUS_FAILED
+		resp.ErrorCode = "INVALID_TYPE"
+	}
+	if resp.Status == banksim.TransactionStatus_TRANSACTION_STATUS_UNSPECIFIED {
+		resp.Status = banksim.TransactionStatus_TRANSACTION_STATUS_SUCCESS
+		resp.BankReferenceId = fmt.Sprintf("%s%012d", req.BankCode, len(b.txns)+1)
+		resp.AccountBalancePaisa = account.AvailableBalancePaisa
+	}
+	b.txns[req.TransactionId] = resp
+	b.txnOwners[req.TransactionId] = req.AccountNumber
+	return resp, nil
+}
+
+func (b *fakeBank) GetTransactionStatus(ctx context.Context, req *banksim.TransactionStatusRequest) (*banksim.TransactionStatusResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	txn, ok := b.txns[req.TransactionId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "transaction %s not found", req.TransactionId)
+	}
+	return &banksim.TransactionStatusResponse{
+		TransactionId:   txn.TransactionId,
+		Status:          txn.Status,
+		BankReferenceId: txn.BankReferenceId,
+		ProcessedAt:     txn.ProcessedAt,
+		ErrorCode:       txn.ErrorCode,
+	}, nil
+}
+
+func (b *fakeBank) CheckBankHealth(ctx context.Context, req *banksim.BankHealthRequest) (*banksim.BankHealthResponse, error) {
+	if !b.codes[req.BankCode] {
+		return nil, status.Errorf(codes.NotFound, "unknown bank %s", req.BankCode)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var accounts int64
+	for _, bank := range b.banks {
+		if bank == req.BankCode {
+			accounts++
+		}
+	}
+	health, successRate := banksim.HealthStatus_HEALTH_STATUS_HEALTHY, int32(100)
+	if fault, ok := b.faults[req.BankCode]; ok && fault.Maintenance {
+		health, successRate = banksim.HealthStatus_HEALTH_STATUS_MAINTENANCE, 0
+	}
+	return &banksim.BankHealthResponse{
+		BankCode:           req.BankCode,
+		HealthStatus:       health,
+		SuccessRatePercent: successRate,
+		TotalAccounts:      accounts,
+		ActiveAccounts:     accounts,
+		LastChecked:        timestamppb.Now(),
+	}, nil
+}
+
+// PurgeTestData deletes by prefix like the service, which requires
+// ENABLE_TEST_DATA_CLEANUP; the fake always allows it.
+func (b *fakeBank) PurgeTestData(ctx context.Context, req *banksim.PurgeTestDataRequest) (*banksim.PurgeTestDataResponse, error) {
+	if req.CustomerIdPrefix == "" && req.VpaPrefix == "" {
+		return nil, status.Error(codes.InvalidArgument, "customer_id_prefix or vpa_prefix is required")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	purged := make(map[string]bool)
+	for number, customer := range b.customers {
+		if req.CustomerIdPrefix != "" && strings.HasPrefix(customer, req.CustomerIdPrefix) {
+			purged[number] = true
+		}
+	}
+	resp := &banksim.PurgeTestDataResponse{AccountsDeleted: int64(len(purged))}
+	for vpa, number := range b.vpas {
+		if purged[number] || (req.VpaPrefix != "" && strings.HasPrefix(vpa, req.VpaPrefix)) {
+			resp.VpasDeleted++
+			if !req.DryRun {
+				delete(b.vpas, vpa)
+			}
+		}
+	}
+	for id, number := range b.txnOwners {
+		if purged[number] {
+			resp.TransactionsDeleted++
+			if !req.DryRun {
+				delete(b.txns, id)
+				delete(b.txnOwners, id)
+			}
+		}
+	}
+	if !req.DryRun {
+		for number := range purged {
+			delete(b.accounts, number)
+			delete(b.banks, number)
+			delete(b.customers, number)
+		}
+	}
+	return resp, nil
+}
+
+func (b *fakeBank) SetBankFault(ctx context.Context, req *banksim.SetBankFaultRequest) (*banksim.SetBankFaultResponse, error) {
+	if !b.codes[req.BankCode] {
+		return nil, status.Errorf(codes.NotFound, "unknown bank %s", req.BankCode)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if req.Maintenance || req.ExtraLatencyMs > 0 || req.DeclineType != banksim.TransactionType_TRANSACTION_TYPE_UNSPECIFIED {
+		b.faults[req.BankCode] = proto.Clone(req).(*banksim.SetBankFaultRequest)
+	} else {
+		delete(b.faults, req.BankCode)
+	}
+	return &banksim.SetBankFaultResponse{Success: true}, nil
+}
+
+func (b *fakeBank) fault(code string) *banksim.SetBankFaultRequest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if fault, ok := b.faults[code]; ok {
+		return fault
+	}
+	return &banksim.SetBankFaultRequest{BankCode: code}
+}
+
+// fakeUpiCore routes payments between VPAs held at the banks of one
+// fakeBank, and settles the successful ones between those banks.
+type fakeUpiCore struct {
+	upicore.UnimplementedUpiCoreServer
+
+	bank *fakeBank
+
+	mu          sync.Mutex
+	txns        map[string]*upicore.TransactionResponse
+	transfers   []*fakeTransfer
+	settlements map[string]*upicore.SettlementStatusResponse
+}
+
+// fakeTransfer is a successful transaction as settlement sees it.
+type fakeTransfer struct {
+	payerBank, payeeBank string
+	amount               int64
+	at                   time.Time
+	settled              bool
+}
+
+func newFakeUpiCore(bank *fakeBank) *fakeUpiCore {
+	return &fakeUpiCore{
+		bank:        bank,
+		txns:        make(map[string]*upicore.TransactionResponse),
+		settlements: make(map[string]*upicore.SettlementStatusResponse),
+	}
+}
+
+func (u *fakeUpiCore) HealthCheck(ctx context.Context, req *upicore.HealthCheckRequest) (*upicore.HealthCheckResponse, error) {
+	return &upicore.HealthCheckResponse{
+		Status:    upicore.HealthStatus_HEALTH_STATUS_SERVING,
+		Details:   map[string]string{"mode": "offline"},
+		Timestamp: timestamppb.Now(),
+	}, nil
+}
+
+func (u *fakeUpiCore) ResolveVPA(ctx context.Context, req *upicore.ResolveVPARequest) (*upicore.ResolveVPAResponse, error) {
+	resp, err := u.bank.ResolveVPA(ctx, &banksim.ResolveVPARequest{Vpa: req.Vpa})
+	if err != nil {
+		return nil, err
+	}
+	return &upicore.ResolveVPAResponse{
+		Exists:            resp.Exists,
+		BankCode:          resp.BankCode,
+		AccountNumber:     resp.AccountNumber,
+		AccountHolderName: resp.AccountHolderName,
+		IsActive:          resp.IsActive,
+		ErrorCode:         resp.ErrorCode,
+		ErrorMessage:      resp.ErrorMessage,
+	}, nil
+}
+
+// ProcessTransaction debits the payer and credits the payee, reversing the
+// debit if the credit fails.
+func (u *fakeUpiCore) ProcessTransaction(ctx context.Context, req *upicore.TransactionRequest) (*upicore.TransactionResponse, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if resp, ok := u.txns[req.TransactionId]; ok {
+		return resp, nil
+	}
+
+	resp := &upicore.TransactionResponse{TransactionId: req.TransactionId, Rrn: req.Rrn, ProcessedAt: timestamppb.Now()}
+	fail := func(code, message string) (*upicore.TransactionResponse, error) {
+		resp.Status = upicore.TransactionStatus_TRANSACTION_STATUS_FAILED
+		resp.ErrorCode = code
+		resp.ErrorMessage = message
+		u.txns[req.TransactionId] = resp
+		return resp, nil
+	}
+
+	payer, err := u.ResolveVPA(ctx, &upicore.ResolveVPARequest{Vpa: req.PayerVpa})
+	if err != nil {
+		return nil, err
+	}
+	payee, err := u.ResolveVPA(ctx, &upicore.ResolveVPARequest{Vpa: req.PayeeVpa})
+	if err != nil {
+		return nil, err
+	}
+	if !payer.Exists || !payee.Exists {
+		return fail("VPA_NOT_FOUND", "payer or payee VPA not found")
+	}
+	resp.PayerBankCode = payer.BankCode
+	resp.PayeeBankCode = payee.BankCode
+
+	leg := func(suffix, bank, account string, typ banksim.TransactionType) (*banksim.TransactionResponse, error) {
+		return u.bank.ProcessTransaction(ctx, &banksim.TransactionRequest{
+			TransactionId: req.TransactionId + suffix,
+			BankCode:      bank,
+			AccountNumber: account,
+			AmountPaisa:   req.AmountPaisa,
+			Type:          typ,
+			Reference:     req.Rrn,
+		})
+	}
+	debit, err := leg("-DR", payer.BankCode, payer.AccountNumber, banksim.TransactionType_TRANSACTION_TYPE_DEBIT)
+	if err != nil {
+		return nil, err
+	}
+	if debit.Status != banksim.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
+		return fail(debit.ErrorCode, debit.ErrorMessage)
+	}
+	credit, err := leg("-CR", payee.BankCode, payee.AccountNumber, banksim.TransactionType_TRANSACTION_TYPE_CREDIT)
+	if err != nil {
+		return nil, err
+	}
+	if credit.Status != banksim.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
+		reversal, err := leg("-RV", payer.BankCode, payer.AccountNumber, banksim.TransactionType_TRANSACTION_TYPE_CREDIT)
+		if err != nil {
+			return nil, err
+		}
+		if reversal.Status != banksim.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
+			return fail("CRITICAL_ERROR", "credit failed and reversal failed: "+reversal.ErrorCode)
+		}
+		return fail(credit.ErrorCode, credit.ErrorMessage)
+	}
+
+	resp.Status = upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS
+	u.txns[req.TransactionId] = resp
+	u.transfers = append(u.transfers, &fakeTransfer{
+		payerBank: payer.BankCode,
+		payeeBank: payee.BankCode,
+		amount:    req.AmountPaisa,
+		at:        resp.ProcessedAt.AsTime(),
+	})
+	return resp, nil
+}
+
+func (u *fakeUpiCore) GetTransactionStatus(ctx context.Context, req *upicore.TransactionStatusRequest) (*upicore.TransactionStatusResponse, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	txn, ok := u.txns[req.TransactionId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "transaction %s not found", req.TransactionId)
+	}
+	return &upicore.TransactionStatusResponse{
+		TransactionId: txn.TransactionId,
+		Rrn:           txn.Rrn,
+		Status:        txn.Status,
+		PayerBankCode: txn.PayerBankCode,
+		PayeeBankCode: txn.PayeeBankCode,
+		ProcessedAt:   txn.ProcessedAt,
+		ErrorCode:     txn.ErrorCode,
+		ErrorMessage:  txn.ErrorMessage,
+	}, nil
+}
+
+// InitiateSettlement nets every unsettled transfer touching the requested
+// banks. The batch reports PROCESSING on its first status poll and
+// COMPLETED afterwards, so callers have to poll like they would for real.
+func (u *fakeUpiCore) InitiateSettlement(ctx context.Context, req *upicore.InitiateSettlementRequest) (*upicore.InitiateSettlementResponse, error) {
+	if req.BatchId == "" || len(req.BankCodes) == 0 {
+		return &upicore.InitiateSettlementResponse{ErrorCode: "INVALID_REQUEST", ErrorMessage: "batch_id and bank_codes are required"}, nil
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	settlement := &upicore.SettlementStatusResponse{
+		SettlementId: "STL_" + req.BatchId,
+		Status:       upicore.SettlementStatus_SETTLEMENT_STATUS_PENDING,
+		CreatedAt:    timestamppb.Now(),
+	}
+	if _, ok := u.settlements[settlement.SettlementId]; ok {
+		return &upicore.InitiateSettlementResponse{ErrorCode: "DUPLICATE_BATCH", ErrorMessage: "batch " + req.BatchId + " already settled"}, nil
+	}
+	for _, code := range req.BankCodes {
+		bank := &upicore.BankSettlement{BankCode: code, Status: upicore.SettlementStatus_SETTLEMENT_STATUS_COMPLETED}
+		for _, t := range u.transfers {
+			if t.settled {
+				continue
+			}
+			if t.payeeBank == code {
+				bank.CreditAmountPaisa += t.amount
+			}
+			if t.payerBank == code {
+				bank.DebitAmountPaisa += t.amount
+			}
+			if t.payeeBank == code || t.payerBank == code {
+				bank.TransactionCount++
+			}
+		}
+		bank.NetAmountPaisa = bank.CreditAmountPaisa - bank.DebitAmountPaisa
+		settlement.BankSettlements = append(settlement.BankSettlements, bank)
+	}
+	requested := make(map[string]bool)
+	for _, code := range req.BankCodes {
+		requested[code] = true
+	}
+	for _, t := range u.transfers {
+		if requested[t.payerBank] && requested[t.payeeBank] {
+			t.settled = true
+		}
+	}
+	u.settlements[settlement.SettlementId] = settlement
+
+	return &upicore.InitiateSettlementResponse{
+		Success:      true,
+		SettlementId: settlement.SettlementId,
+		InitiatedAt:  settlement.CreatedAt,
+	}, nil
+}
+
+func (u *fakeUpiCore) GetSettlementStatus(ctx context.Context, req *upicore.SettlementStatusRequest) (*upicore.SettlementStatusResponse, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	settlement, ok := u.settlements[req.SettlementId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "settlement %s not found", req.SettlementId)
+	}
+	switch settlement.Status {
+	case upicore.SettlementStatus_SETTLEMENT_STATUS_PENDING:
+		settlement.Status = upicore.SettlementStatus_SETTLEMENT_STATUS_PROCESSING
+	case upicore.SettlementStatus_SETTLEMENT_STATUS_PROCESSING:
+		settlement.Status = upicore.SettlementStatus_SETTLEMENT_STATUS_COMPLETED
+		settlement.CompletedAt = timestamppb.Now()
+	}
+	return proto.Clone(settlement).(*upicore.SettlementStatusResponse), nil
+}
+
+// GetSettlementReport totals the bank's successful transfers in the window,
+// settled or not: credits are what its accounts received, debits what they
+// paid.
+func (u *fakeUpiCore) GetSettlementReport(ctx context.Context, req *upicore.SettlementReportRequest) (*upicore.SettlementReportResponse, error) {
+	if !u.bank.codes[req.BankCode] {
+		return nil, status.Errorf(codes.NotFound, "unknown bank %s", req.BankCode)
+	}
+	from, to := req.FromDate.AsTime(), req.ToDate.AsTime()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	resp := &upicore.SettlementReportResponse{BankCode: req.BankCode}
+	for _, t := range u.transfers {
+		if t.at.Before(from) || t.at.After(to) || (t.payerBank != req.BankCode && t.payeeBank != req.BankCode) {
+			continue
+		}
+		if t.payeeBank == req.BankCode {
+			resp.TotalCreditPaisa += t.amount
+		}
+		if t.payerBank == req.BankCode {
+			resp.TotalDebitPaisa += t.amount
+		}
+		resp.TransactionCount++
+	}
+	resp.NetSettlementPaisa = resp.TotalCreditPaisa - resp.TotalDebitPaisa
+	return resp, nil
+}
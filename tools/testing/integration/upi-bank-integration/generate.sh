@@ -1,27 +1,37 @@
 #!/bin/bash
 
-# Generate gRPC Go code from protobuf definitions
+# Generate gRPC Go clients from the services' own protobuf definitions, so
+# the suite always speaks the wire format the services actually serve.
 set -e
 
+cd "$(dirname "$0")"
+
+SERVICES="../../../../services"
+MODULE="github.com/suuupra/upi-bank-integration-tests/generated"
+
 echo "🔄 Generating gRPC Go code from protobuf definitions..."
 
-# Create generated directories
-mkdir -p generated/banksim
-mkdir -p generated/upicore
+mkdir -p generated/banksim generated/upicore
 
-# Generate Bank Simulator gRPC code
-protoc --go_out=generated/banksim --go_opt=paths=source_relative \
+# Bank Simulator
+protoc -I "$SERVICES/bank-simulator/proto" \
+       --go_out=generated/banksim --go_opt=paths=source_relative \
+       --go_opt="Mbank_simulator.proto=$MODULE/banksim;banksim" \
        --go-grpc_out=generated/banksim --go-grpc_opt=paths=source_relative \
-       proto/bank_simulator.proto
+       --go-grpc_opt="Mbank_simulator.proto=$MODULE/banksim;banksim" \
+       bank_simulator.proto
 
-# Generate UPI Core gRPC code
-protoc --go_out=generated/upicore --go_opt=paths=source_relative \
+# UPI Core
+protoc -I "$SERVICES/upi-core/proto" \
+       --go_out=generated/upicore --go_opt=paths=source_relative \
+       --go_opt="Mupi_core.proto=$MODULE/upicore;upicore" \
        --go-grpc_out=generated/upicore --go-grpc_opt=paths=source_relative \
-       proto/upi_core.proto
+       --go-grpc_opt="Mupi_core.proto=$MODULE/upicore;upicore" \
+       upi_core.proto
 
 echo "✅ gRPC Go code generation completed!"
 echo "📁 Generated files:"
 echo "  - generated/banksim/bank_simulator.pb.go"
 echo "  - generated/banksim/bank_simulator_grpc.pb.go"
 echo "  - generated/upicore/upi_core.pb.go"
-echo "  - generated/upicore/upi_core_grpc.pb.go"
\ No newline at end of file
+echo "  - generated/upicore/upi_core_grpc.pb.go"
@@ -0,0 +1,565 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v6.32.0
+// source: bank_simulator.proto
+
+package banksim
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	BankSimulator_ProcessTransaction_FullMethodName   = "/bank_simulator.BankSimulator/ProcessTransaction"
+	BankSimulator_GetTransactionStatus_FullMethodName = "/bank_simulator.BankSimulator/GetTransactionStatus"
+	BankSimulator_CreateAccount_FullMethodName        = "/bank_simulator.BankSimulator/CreateAccount"
+	BankSimulator_GetAccountBalance_FullMethodName    = "/bank_simulator.BankSimulator/GetAccountBalance"
+	BankSimulator_GetAccountDetails_FullMethodName    = "/bank_simulator.BankSimulator/GetAccountDetails"
+	BankSimulator_LinkVPA_FullMethodName              = "/bank_simulator.BankSimulator/LinkVPA"
+	BankSimulator_UnlinkVPA_FullMethodName            = "/bank_simulator.BankSimulator/UnlinkVPA"
+	BankSimulator_ResolveVPA_FullMethodName           = "/bank_simulator.BankSimulator/ResolveVPA"
+	BankSimulator_GetBankInfo_FullMethodName          = "/bank_simulator.BankSimulator/GetBankInfo"
+	BankSimulator_CheckBankHealth_FullMethodName      = "/bank_simulator.BankSimulator/CheckBankHealth"
+	BankSimulator_GetBankStats_FullMethodName         = "/bank_simulator.BankSimulator/GetBankStats"
+	BankSimulator_PurgeTestData_FullMethodName        = "/bank_simulator.BankSimulator/PurgeTestData"
+	BankSimulator_SetBankFault_FullMethodName         = "/bank_simulator.BankSimulator/SetBankFault"
+)
+
+// BankSimulatorClient is the client API for BankSimulator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BankSimulatorClient interface {
+	// Core transaction processing
+	ProcessTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	GetTransactionStatus(ctx context.Context, in *TransactionStatusRequest, opts ...grpc.CallOption) (*TransactionStatusResponse, error)
+	// Account management
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
+	GetAccountBalance(ctx context.Context, in *AccountBalanceRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error)
+	GetAccountDetails(ctx context.Context, in *AccountDetailsRequest, opts ...grpc.CallOption) (*AccountDetailsResponse, error)
+	// VPA management
+	LinkVPA(ctx context.Context, in *LinkVPARequest, opts ...grpc.CallOption) (*LinkVPAResponse, error)
+	UnlinkVPA(ctx context.Context, in *UnlinkVPARequest, opts ...grpc.CallOption) (*UnlinkVPAResponse, error)
+	ResolveVPA(ctx context.Context, in *ResolveVPARequest, opts ...grpc.CallOption) (*ResolveVPAResponse, error)
+	// Bank operations
+	GetBankInfo(ctx context.Context, in *BankInfoRequest, opts ...grpc.CallOption) (*BankInfoResponse, error)
+	CheckBankHealth(ctx context.Context, in *BankHealthRequest, opts ...grpc.CallOption) (*BankHealthResponse, error)
+	// Admin operations
+	GetBankStats(ctx context.Context, in *BankStatsRequest, opts ...grpc.CallOption) (*BankStatsResponse, error)
+	// Test support, only served when ENABLE_TEST_DATA_CLEANUP=true
+	PurgeTestData(ctx context.Context, in *PurgeTestDataRequest, opts ...grpc.CallOption) (*PurgeTestDataResponse, error)
+	SetBankFault(ctx context.Context, in *SetBankFaultRequest, opts ...grpc.CallOption) (*SetBankFaultResponse, error)
+}
+
+type bankSimulatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBankSimulatorClient(cc grpc.ClientConnInterface) BankSimulatorClient {
+	return &bankSimulatorClient{cc}
+}
+
+func (c *bankSimulatorClient) ProcessTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_ProcessTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) GetTransactionStatus(ctx context.Context, in *TransactionStatusRequest, opts ...grpc.CallOption) (*TransactionStatusResponse, error) {
+	out := new(TransactionStatusResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_GetTransactionStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error) {
+	out := new(CreateAccountResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_CreateAccount_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) GetAccountBalance(ctx context.Context, in *AccountBalanceRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error) {
+	out := new(AccountBalanceResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_GetAccountBalance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) GetAccountDetails(ctx context.Context, in *AccountDetailsRequest, opts ...grpc.CallOption) (*AccountDetailsResponse, error) {
+	out := new(AccountDetailsResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_GetAccountDetails_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) LinkVPA(ctx context.Context, in *LinkVPARequest, opts ...grpc.CallOption) (*LinkVPAResponse, error) {
+	out := new(LinkVPAResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_LinkVPA_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) UnlinkVPA(ctx context.Context, in *UnlinkVPARequest, opts ...grpc.CallOption) (*UnlinkVPAResponse, error) {
+	out := new(UnlinkVPAResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_UnlinkVPA_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) ResolveVPA(ctx context.Context, in *ResolveVPARequest, opts ...grpc.CallOption) (*ResolveVPAResponse, error) {
+	out := new(ResolveVPAResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_ResolveVPA_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) GetBankInfo(ctx context.Context, in *BankInfoRequest, opts ...grpc.CallOption) (*BankInfoResponse, error) {
+	out := new(BankInfoResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_GetBankInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) CheckBankHealth(ctx context.Context, in *BankHealthRequest, opts ...grpc.CallOption) (*BankHealthResponse, error) {
+	out := new(BankHealthResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_CheckBankHealth_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) GetBankStats(ctx context.Context, in *BankStatsRequest, opts ...grpc.CallOption) (*BankStatsResponse, error) {
+	out := new(BankStatsResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_GetBankStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) PurgeTestData(ctx context.Context, in *PurgeTestDataRequest, opts ...grpc.CallOption) (*PurgeTestDataResponse, error) {
+	out := new(PurgeTestDataResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_PurgeTestData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bankSimulatorClient) SetBankFault(ctx context.Context, in *SetBankFaultRequest, opts ...grpc.CallOption) (*SetBankFaultResponse, error) {
+	out := new(SetBankFaultResponse)
+	err := c.cc.Invoke(ctx, BankSimulator_SetBankFault_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BankSimulatorServer is the server API for BankSimulator service.
+// All implementations must embed UnimplementedBankSimulatorServer
+// for forward compatibility
+type BankSimulatorServer interface {
+	// Core transaction processing
+	ProcessTransaction(context.Context, *TransactionRequest) (*TransactionResponse, error)
+	GetTransactionStatus(context.Context, *TransactionStatusRequest) (*TransactionStatusResponse, error)
+	// Account management
+	CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
+	GetAccountBalance(context.Context, *AccountBalanceRequest) (*AccountBalanceResponse, error)
+	GetAccountDetails(context.Context, *AccountDetailsRequest) (*AccountDetailsResponse, error)
+	// VPA management
+	LinkVPA(context.Context, *LinkVPARequest) (*LinkVPAResponse, error)
+	UnlinkVPA(context.Context, *UnlinkVPARequest) (*UnlinkVPAResponse, error)
+	ResolveVPA(context.Context, *ResolveVPARequest) (*ResolveVPAResponse, error)
+	// Bank operations
+	GetBankInfo(context.Context, *BankInfoRequest) (*BankInfoResponse, error)
+	CheckBankHealth(context.Context, *BankHealthRequest) (*BankHealthResponse, error)
+	// Admin operations
+	GetBankStats(context.Context, *BankStatsRequest) (*BankStatsResponse, error)
+	// Test support, only served when ENABLE_TEST_DATA_CLEANUP=true
+	PurgeTestData(context.Context, *PurgeTestDataRequest) (*PurgeTestDataResponse, error)
+	SetBankFault(context.Context, *SetBankFaultRequest) (*SetBankFaultResponse, error)
+	mustEmbedUnimplementedBankSimulatorServer()
+}
+
+// UnimplementedBankSimulatorServer must be embedded to have forward compatible implementations.
+type UnimplementedBankSimulatorServer struct {
+}
+
+func (UnimplementedBankSimulatorServer) ProcessTransaction(context.Context, *TransactionRequest) (*TransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessTransaction not implemented")
+}
+func (UnimplementedBankSimulatorServer) GetTransactionStatus(context.Context, *TransactionStatusRequest) (*TransactionStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionStatus not implemented")
+}
+func (UnimplementedBankSimulatorServer) CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedBankSimulatorServer) GetAccountBalance(context.Context, *AccountBalanceRequest) (*AccountBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountBalance not implemented")
+}
+func (UnimplementedBankSimulatorServer) GetAccountDetails(context.Context, *AccountDetailsRequest) (*AccountDetailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountDetails not implemented")
+}
+func (UnimplementedBankSimulatorServer) LinkVPA(context.Context, *LinkVPARequest) (*LinkVPAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkVPA not implemented")
+}
+func (UnimplementedBankSimulatorServer) UnlinkVPA(context.Context, *UnlinkVPARequest) (*UnlinkVPAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlinkVPA not implemented")
+}
+func (UnimplementedBankSimulatorServer) ResolveVPA(context.Context, *ResolveVPARequest) (*ResolveVPAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveVPA not implemented")
+}
+func (UnimplementedBankSimulatorServer) GetBankInfo(context.Context, *BankInfoRequest) (*BankInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBankInfo not implemented")
+}
+func (UnimplementedBankSimulatorServer) CheckBankHealth(context.Context, *BankHealthRequest) (*BankHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckBankHealth not implemented")
+}
+func (UnimplementedBankSimulatorServer) GetBankStats(context.Context, *BankStatsRequest) (*BankStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBankStats not implemented")
+}
+func (UnimplementedBankSimulatorServer) PurgeTestData(context.Context, *PurgeTestDataRequest) (*PurgeTestDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeTestData not implemented")
+}
+func (UnimplementedBankSimulatorServer) SetBankFault(context.Context, *SetBankFaultRequest) (*SetBankFaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBankFault not implemented")
+}
+func (UnimplementedBankSimulatorServer) mustEmbedUnimplementedBankSimulatorServer() {}
+
+// UnsafeBankSimulatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BankSimulatorServer will
+// result in compilation errors.
+type UnsafeBankSimulatorServer interface {
+	mustEmbedUnimplementedBankSimulatorServer()
+}
+
+func RegisterBankSimulatorServer(s grpc.ServiceRegistrar, srv BankSimulatorServer) {
+	s.RegisterService(&BankSimulator_ServiceDesc, srv)
+}
+
+func _BankSimulator_ProcessTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).ProcessTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_ProcessTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).ProcessTransaction(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_GetTransactionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).GetTransactionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_GetTransactionStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).GetTransactionStatus(ctx, req.(*TransactionStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).CreateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_CreateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).CreateAccount(ctx, req.(*CreateAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_GetAccountBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).GetAccountBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_GetAccountBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).GetAccountBalance(ctx, req.(*AccountBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_GetAccountDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountDetailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).GetAccountDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_GetAccountDetails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).GetAccountDetails(ctx, req.(*AccountDetailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_LinkVPA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkVPARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).LinkVPA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_LinkVPA_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).LinkVPA(ctx, req.(*LinkVPARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_UnlinkVPA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlinkVPARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).UnlinkVPA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_UnlinkVPA_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).UnlinkVPA(ctx, req.(*UnlinkVPARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_ResolveVPA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveVPARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).ResolveVPA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_ResolveVPA_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).ResolveVPA(ctx, req.(*ResolveVPARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_GetBankInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BankInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).GetBankInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_GetBankInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).GetBankInfo(ctx, req.(*BankInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_CheckBankHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BankHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).CheckBankHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_CheckBankHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).CheckBankHealth(ctx, req.(*BankHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_GetBankStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BankStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).GetBankStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_GetBankStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).GetBankStats(ctx, req.(*BankStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_PurgeTestData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeTestDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).PurgeTestData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_PurgeTestData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).PurgeTestData(ctx, req.(*PurgeTestDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BankSimulator_SetBankFault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBankFaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BankSimulatorServer).SetBankFault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BankSimulator_SetBankFault_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BankSimulatorServer).SetBankFault(ctx, req.(*SetBankFaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BankSimulator_ServiceDesc is the grpc.ServiceDesc for BankSimulator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BankSimulator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bank_simulator.BankSimulator",
+	HandlerType: (*BankSimulatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ProcessTransaction",
+			Handler:    _BankSimulator_ProcessTransaction_Handler,
+		},
+		{
+			MethodName: "GetTransactionStatus",
+			Handler:    _BankSimulator_GetTransactionStatus_Handler,
+		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _BankSimulator_CreateAccount_Handler,
+		},
+		{
+			MethodName: "GetAccountBalance",
+			Handler:    _BankSimulator_GetAccountBalance_Handler,
+		},
+		{
+			MethodName: "GetAccountDetails",
+			Handler:    _BankSimulator_GetAccountDetails_Handler,
+		},
+		{
+			MethodName: "LinkVPA",
+			Handler:    _BankSimulator_LinkVPA_Handler,
+		},
+		{
+			MethodName: "UnlinkVPA",
+			Handler:    _BankSimulator_UnlinkVPA_Handler,
+		},
+		{
+			MethodName: "ResolveVPA",
+			Handler:    _BankSimulator_ResolveVPA_Handler,
+		},
+		{
+			MethodName: "GetBankInfo",
+			Handler:    _BankSimulator_GetBankInfo_Handler,
+		},
+		{
+			MethodName: "CheckBankHealth",
+			Handler:    _BankSimulator_CheckBankHealth_Handler,
+		},
+		{
+			MethodName: "GetBankStats",
+			Handler:    _BankSimulator_GetBankStats_Handler,
+		},
+		{
+			MethodName: "PurgeTestData",
+			Handler:    _BankSimulator_PurgeTestData_Handler,
+		},
+		{
+			MethodName: "SetBankFault",
+			Handler:    _BankSimulator_SetBankFault_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "bank_simulator.proto",
+}
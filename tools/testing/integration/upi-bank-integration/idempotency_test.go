@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+)
+
+// DuplicateRequests is how many copies of one request the idempotency tests
+// race, the number of duplicates the payments incident produced for a
+// single idempotency key.
+const DuplicateRequests = 25
+
+var paymentsURL = flag.String("payments-url", "", "payments gateway base URL, e.g. http://localhost:8084; the payments idempotency test skips without it")
+
+// race runs fn DuplicateRequests times at once, releasing every call
+// together so they overlap as much as possible.
+func race(fn func(i int)) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < DuplicateRequests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			fn(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+}
+
+// Concurrent copies of one transaction must move money once: UPI Core may
+// answer each copy, but with the same transaction, and the bank sees one
+// debit and one credit.
+func TestIdempotencyUpiCoreConcurrentDuplicates(t *testing.T) {
+	suite, err := setupTestSuite()
+	require.NoError(t, err)
+	defer suite.cleanup()
+
+	payer, payee := suite.CreateFundedAccountPair(t, InitialDepositPaisa, InitialDepositPaisa)
+	suite.LinkVPAs(t, payer, payee)
+	payerBefore, payeeBefore := suite.balance(t, payer), suite.balance(t, payee)
+
+	id := uuid.New().String()
+	req := &upicore.TransactionRequest{
+		TransactionId: "IDEM_" + id,
+		Rrn:           id[:12],
+		PayerVpa:      payer.vpa,
+		PayeeVpa:      payee.vpa,
+		AmountPaisa:   TransactionAmount,
+		Currency:      "INR",
+		Type:          upicore.TransactionType_TRANSACTION_TYPE_P2P,
+		Reference:     "IDEMPOTENCY_TEST",
+		InitiatedAt:   timestamppb.Now(),
+	}
+
+	responses := make([]*upicore.TransactionResponse, DuplicateRequests)
+	errs := make([]error, DuplicateRequests)
+	race(func(i int) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		responses[i], errs[i] = suite.upiCoreClient.ProcessTransaction(ctx, req)
+	})
+
+	answered := 0
+	for i, resp := range responses {
+		if errs[i] != nil {
+			// Rejecting a copy outright is fine; applying it twice is not
+			t.Logf("copy %d rejected: %v", i, errs[i])
+			continue
+		}
+		answered++
+		assert.Equal(t, req.TransactionId, resp.TransactionId, "copy %d", i)
+		if resp.Status == upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
+			assert.Equal(t, req.Rrn, resp.Rrn, "copy %d reports a different transaction", i)
+		}
+	}
+	require.NotZero(t, answered, "every copy was rejected")
+
+	final := suite.settle(t, req.TransactionId)
+	assert.Equal(t, upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS, final)
+	suite.assertConserved(t, final, payer, payee, payerBefore, payeeBefore, TransactionAmount)
+}
+
+// Concurrent copies of one payment with the same Idempotency-Key must
+// create one payment. Copies that lose the race may be told the key is in
+// use, but any that succeed must return that same payment.
+func TestIdempotencyPaymentsConcurrentDuplicates(t *testing.T) {
+	if *paymentsURL == "" || *offline {
+		t.Skip("needs the payments gateway; pass -payments-url")
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	var intent struct {
+		ID string `json:"id"`
+	}
+	status, body, err := postJSON(client, "/api/v1/intents", uuid.New().String(), map[string]interface{}{
+		"merchant_id":    uuid.New().String(),
+		"amount":         "500.00",
+		"currency":       "INR",
+		"description":    "Idempotency stress test",
+		"payment_method": "upi",
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, status, "creating intent: %s", body)
+	require.NoError(t, json.Unmarshal(body, &intent))
+
+	key := uuid.New().String()
+	payment := map[string]interface{}{
+		"payment_intent_id": intent.ID,
+		"payer_vpa":         "idempotency.payer@hdfc",
+		"payee_vpa":         "idempotency.payee@sbi",
+	}
+	statuses := make([]int, DuplicateRequests)
+	bodies := make([][]byte, DuplicateRequests)
+	errs := make([]error, DuplicateRequests)
+	race(func(i int) {
+		statuses[i], bodies[i], errs[i] = postJSON(client, "/api/v1/payments", key, payment)
+	})
+
+	paymentIDs := make(map[string]int)
+	for i := range statuses {
+		switch {
+		case errs[i] != nil:
+			t.Errorf("copy %d: %v", i, errs[i])
+		case statuses[i] == http.StatusCreated:
+			var p struct {
+				ID string `json:"id"`
+			}
+			require.NoError(t, json.Unmarshal(bodies[i], &p), "copy %d", i)
+			paymentIDs[p.ID]++
+		case statuses[i] == http.StatusConflict:
+			t.Logf("copy %d rejected: %s", i, bodies[i])
+		default:
+			t.Errorf("copy %d: unexpected status %d: %s", i, statuses[i], bodies[i])
+		}
+	}
+	require.Len(t, paymentIDs, 1, "duplicates created separate payments: %v", paymentIDs)
+
+	// A later retry replays the stored result
+	status, body, err = postJSON(client, "/api/v1/payments", key, payment)
+	require.NoError(t, err)
+	var replay struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(body, &replay))
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Contains(t, paymentIDs, replay.ID, "retry after the race returned a different payment")
+}
+
+func postJSON(client *http.Client, path, idempotencyKey string, payload interface{}) (int, []byte, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return 0, nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, *paymentsURL+path, bytes.NewReader(raw))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, body, err
+}
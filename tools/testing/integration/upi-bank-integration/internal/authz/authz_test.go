@@ -0,0 +1,135 @@
+package authz
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const secret = "test-secret"
+
+// notes is a toy service: POST /notes creates a note owned by the caller
+// and GET /notes/{id} reads one. lax switches off the checks the Checker
+// should catch missing.
+type notes struct {
+	lax    bool
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+func (n *notes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenant, ok := n.authenticate(r.Header.Get("Authorization"))
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if r.Method == http.MethodPost {
+		id := uuid.New().String()
+		n.owners[id] = tenant
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"id": id}})
+		return
+	}
+	owner, ok := n.owners[strings.TrimPrefix(r.URL.Path, "/notes/")]
+	if !ok || (owner != tenant && !n.lax) {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write([]byte(`{}`))
+}
+
+func (n *notes) authenticate(header string) (string, bool) {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	parts := strings.Split(token, ".")
+	if !ok || len(parts) != 3 {
+		return "", false
+	}
+	if !n.lax || parts[2] != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(parts[0] + "." + parts[1]))
+		if got, err := base64.RawURLEncoding.DecodeString(parts[2]); err != nil || !hmac.Equal(got, mac.Sum(nil)) {
+			return "", false
+		}
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims struct {
+		Sub string `json:"sub"`
+		Exp int64  `json:"exp"`
+	}
+	if err != nil || json.Unmarshal(raw, &claims) != nil || (!n.lax && claims.Exp < time.Now().Unix()) {
+		return "", false
+	}
+	return claims.Sub, true
+}
+
+func notesService(url string) Service {
+	create := &Request{Method: "POST", Path: "/notes", Body: `{"owner":"{tenant}"}`}
+	return Service{
+		Name:    "notes",
+		BaseURL: url,
+		Secret:  secret,
+		Claims: func(tenant string) map[string]interface{} {
+			return map[string]interface{}{"sub": tenant, "exp": time.Now().Add(time.Hour).Unix()}
+		},
+		Endpoints: []Endpoint{
+			{Request: *create},
+			{Request: Request{Method: "GET", Path: "/notes/{id}"}, Create: create, IDPath: "data.id"},
+		},
+	}
+}
+
+func TestCheckPassesEnforcingService(t *testing.T) {
+	srv := httptest.NewServer(&notes{owners: map[string]string{}})
+	defer srv.Close()
+
+	if findings := (Checker{Client: srv.Client()}).Check(context.Background(), notesService(srv.URL)); len(findings) != 0 {
+		t.Fatalf("findings against an enforcing service: %v", findings)
+	}
+}
+
+func TestCheckFindsGaps(t *testing.T) {
+	srv := httptest.NewServer(&notes{lax: true, owners: map[string]string{}})
+	defer srv.Close()
+
+	findings := (Checker{Client: srv.Client()}).Check(context.Background(), notesService(srv.URL))
+	got := make(map[string]bool)
+	for _, f := range findings {
+		got[f.Endpoint+", "+f.Case] = true
+	}
+	for _, want := range []string{
+		"POST /notes, expired token",
+		"POST /notes, unsigned token (alg none)",
+		"GET /notes/{id}, another tenant's token",
+	} {
+		if !got[want] {
+			t.Errorf("missing finding %q in %v", want, findings)
+		}
+	}
+	if got["POST /notes, missing token"] || got["POST /notes, token signed with another key"] {
+		t.Errorf("findings for checks the service does make: %v", findings)
+	}
+}
+
+func TestSign(t *testing.T) {
+	token := Sign(map[string]interface{}{"sub": "alice"}, secret)
+	n := &notes{}
+	if sub, ok := n.authenticate("Bearer " + token); ok || sub != "" {
+		t.Fatalf("token without exp accepted")
+	}
+	token = Sign(map[string]interface{}{"sub": "alice", "exp": time.Now().Add(time.Minute).Unix()}, secret)
+	if sub, ok := n.authenticate("Bearer " + token); !ok || sub != "alice" {
+		t.Fatalf("valid token rejected: %q %v", sub, ok)
+	}
+}
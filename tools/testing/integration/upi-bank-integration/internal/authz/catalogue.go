@@ -0,0 +1,71 @@
+package authz
+
+import "time"
+
+// Payments is the payments gateway's /api/v1 group, every route of which
+// sits behind middleware.Authentication (services/payments/cmd/main.go).
+// Tokens carry the merchant as sub.
+func Payments(baseURL, secret string) Service {
+	intent := &Request{Method: "POST", Path: "/api/v1/intents",
+		Body: `{"merchant_id":"{tenant}","amount":"100.00","currency":"INR","payment_method":"upi","description":"authz check"}`}
+	webhook := &Request{Method: "POST", Path: "/api/v1/webhooks/endpoints",
+		Body: `{"merchant_id":"{tenant}","url":"https://example.com/hooks","events":["payment.succeeded"]}`}
+	return Service{
+		Name:    "payments",
+		BaseURL: baseURL,
+		Secret:  secret,
+		Claims: func(tenant string) map[string]interface{} {
+			return map[string]interface{}{
+				"sub":   tenant,
+				"email": "authz@example.com",
+				"roles": []string{"merchant"},
+				"iat":   time.Now().Unix(),
+				"exp":   time.Now().Add(time.Hour).Unix(),
+			}
+		},
+		Endpoints: []Endpoint{
+			{Request: *intent},
+			{Request: Request{Method: "GET", Path: "/api/v1/intents/{id}"}, Create: intent, IDPath: "id"},
+			{Request: Request{Method: "POST", Path: "/api/v1/payments",
+				Body: `{"payment_intent_id":"{id}","payer_vpa":"authz.payer@hdfc","payee_vpa":"authz.payee@sbi"}`}},
+			{Request: Request{Method: "GET", Path: "/api/v1/payments/{id}"}},
+			{Request: Request{Method: "POST", Path: "/api/v1/refunds", Body: `{"payment_id":"{id}","amount":"1.00"}`}},
+			{Request: Request{Method: "GET", Path: "/api/v1/refunds/{id}"}},
+			{Request: Request{Method: "POST", Path: "/api/v1/risk/assess", Body: `{"payment_intent_id":"{id}"}`}},
+			{Request: *webhook},
+			{Request: Request{Method: "GET", Path: "/api/v1/webhooks/endpoints"}},
+			{Request: Request{Method: "PUT", Path: "/api/v1/webhooks/endpoints/{id}", Body: `{"url":"https://attacker.example.com/hooks"}`},
+				Create: webhook, IDPath: "id"},
+			{Request: Request{Method: "DELETE", Path: "/api/v1/webhooks/endpoints/{id}"}, Create: webhook, IDPath: "id"},
+		},
+	}
+}
+
+// MassLive is the mass-live endpoints documented with @Security BearerAuth
+// (services/mass-live/internal/api/handlers). Tokens carry the creator as
+// user_id.
+func MassLive(baseURL, secret string) Service {
+	stream := &Request{Method: "POST", Path: "/api/v1/streams",
+		Body: `{"title":"authz check","creator_id":"{tenant}","is_public":false}`}
+	return Service{
+		Name:    "mass-live",
+		BaseURL: baseURL,
+		Secret:  secret,
+		Claims: func(tenant string) map[string]interface{} {
+			return map[string]interface{}{
+				"user_id":  tenant,
+				"username": "authz-" + tenant[:8],
+				"role":     "creator",
+				"iat":      time.Now().Unix(),
+				"exp":      time.Now().Add(time.Hour).Unix(),
+			}
+		},
+		Endpoints: []Endpoint{
+			{Request: *stream},
+			// Starting also needs the stream key, so another tenant is refused
+			// for that alone; stop shows whether ownership is checked
+			{Request: Request{Method: "POST", Path: "/api/v1/streams/{id}/start", Body: `{"stream_key":"authz"}`}},
+			{Request: Request{Method: "POST", Path: "/api/v1/streams/{id}/stop"}, Create: stream, IDPath: "data.id"},
+		},
+	}
+}
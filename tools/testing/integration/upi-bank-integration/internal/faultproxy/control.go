@@ -0,0 +1,128 @@
+package faultproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// faultsJSON is the wire form of Faults, with latency as a duration string
+// such as "5s".
+type faultsJSON struct {
+	Latency     string `json:"latency"`
+	Partitioned bool   `json:"partitioned"`
+}
+
+func (f Faults) wire() faultsJSON {
+	return faultsJSON{Latency: f.Latency.String(), Partitioned: f.Partitioned}
+}
+
+func (w faultsJSON) faults() (Faults, error) {
+	f := Faults{Partitioned: w.Partitioned}
+	if w.Latency != "" {
+		d, err := time.ParseDuration(w.Latency)
+		if err != nil || d < 0 {
+			return Faults{}, fmt.Errorf("invalid latency %q", w.Latency)
+		}
+		f.Latency = d
+	}
+	return f, nil
+}
+
+// Handler exposes a proxy for test suites running in another process:
+//
+//	GET    /faults  current faults
+//	PUT    /faults  {"latency": "5s", "partitioned": false}
+//	DELETE /faults  clear all faults
+//	POST   /kill    reset every open connection
+func Handler(p *Proxy) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/faults", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req faultsJSON
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			faults, err := req.faults()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			p.SetFaults(faults)
+		case http.MethodDelete:
+			p.Reset()
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, p.Faults().wire())
+	})
+	mux.HandleFunc("/kill", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, map[string]int{"killed": p.KillConnections()})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// Client drives a proxy's Handler.
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+}
+
+// NewClient returns a client for the control API at baseURL.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL, HTTP: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// SetFaults replaces the proxy's faults.
+func (c *Client) SetFaults(ctx context.Context, f Faults) error {
+	body, err := json.Marshal(f.wire())
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPut, "/faults", body)
+}
+
+// Reset clears the proxy's faults.
+func (c *Client) Reset(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/faults", nil)
+}
+
+// KillConnections resets every connection open through the proxy.
+func (c *Client) KillConnections(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/kill", nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("fault proxy %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fault proxy %s %s: %s", method, path, resp.Status)
+	}
+	return nil
+}
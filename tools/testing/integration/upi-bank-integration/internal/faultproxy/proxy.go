@@ -0,0 +1,193 @@
+// Package faultproxy is a TCP proxy that injects faults between a service
+// and one of its dependencies: added latency, a network partition, or a
+// sudden reset of every open connection, as if the dependency was killed.
+//
+// Chaos scenarios route a dependency through a proxy, for example UPI Core's
+// bank connection, and switch faults on and off through Handler.
+package faultproxy
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// Faults is the set of faults currently applied.
+type Faults struct {
+	// Latency delays every chunk of data in both directions.
+	Latency time.Duration
+	// Partitioned refuses new connections and stalls open ones without
+	// closing them, so callers only find out through their own timeouts.
+	Partitioned bool
+}
+
+// Proxy forwards connections accepted on a listener to an upstream address.
+type Proxy struct {
+	upstream string
+	lis      net.Listener
+
+	mu     sync.Mutex
+	faults Faults
+	healed chan struct{} // Closed when a partition ends
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Listen starts a proxy on addr (":0" for any port) forwarding to upstream.
+func Listen(addr, upstream string) (*Proxy, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	p := &Proxy{
+		upstream: upstream,
+		lis:      lis,
+		healed:   make(chan struct{}),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	close(p.healed)
+	p.wg.Add(1)
+	go p.accept()
+	return p, nil
+}
+
+// Addr returns the address clients should dial instead of the upstream.
+func (p *Proxy) Addr() string {
+	return p.lis.Addr().String()
+}
+
+// Faults returns the faults currently applied.
+func (p *Proxy) Faults() Faults {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.faults
+}
+
+// SetFaults replaces the applied faults. Ending a partition releases the
+// connections it stalled.
+func (p *Proxy) SetFaults(f Faults) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case f.Partitioned && !p.faults.Partitioned:
+		p.healed = make(chan struct{})
+	case !f.Partitioned && p.faults.Partitioned:
+		close(p.healed)
+	}
+	p.faults = f
+}
+
+// Reset clears all faults.
+func (p *Proxy) Reset() {
+	p.SetFaults(Faults{})
+}
+
+// KillConnections resets every open connection, as if the upstream process
+// died. New connections are still accepted.
+func (p *Proxy) KillConnections() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for conn := range p.conns {
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			// Send RST rather than FIN, like a crashed peer's kernel would
+			tcp.SetLinger(0)
+		}
+		conn.Close()
+	}
+	n := len(p.conns)
+	p.conns = make(map[net.Conn]struct{})
+	return n
+}
+
+// Close stops accepting and closes every open connection.
+func (p *Proxy) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	err := p.lis.Close()
+	p.KillConnections()
+	p.Reset()
+	p.wg.Wait()
+	return err
+}
+
+func (p *Proxy) accept() {
+	defer p.wg.Done()
+	for {
+		client, err := p.lis.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		if p.Faults().Partitioned {
+			client.Close()
+			continue
+		}
+		upstream, err := net.DialTimeout("tcp", p.upstream, 5*time.Second)
+		if err != nil {
+			client.Close()
+			continue
+		}
+		if !p.track(client, upstream) {
+			client.Close()
+			upstream.Close()
+			return
+		}
+		p.wg.Add(2)
+		go p.pipe(upstream, client)
+		go p.pipe(client, upstream)
+	}
+}
+
+func (p *Proxy) track(conns ...net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	for _, conn := range conns {
+		p.conns[conn] = struct{}{}
+	}
+	return true
+}
+
+// pipe copies src to dst, applying the current faults to every chunk.
+func (p *Proxy) pipe(dst, src net.Conn) {
+	defer p.wg.Done()
+	defer func() {
+		p.mu.Lock()
+		delete(p.conns, src)
+		delete(p.conns, dst)
+		p.mu.Unlock()
+		src.Close()
+		dst.Close()
+	}()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			p.mu.Lock()
+			faults, healed := p.faults, p.healed
+			p.mu.Unlock()
+			if faults.Partitioned {
+				<-healed
+			}
+			if faults.Latency > 0 {
+				time.Sleep(faults.Latency)
+			}
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			// Either side closing ends the whole connection; the protocols
+			// proxied here never half-close
+			return
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package faultproxy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// startEcho runs a line echo server and returns its address.
+func startEcho(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func startProxy(t *testing.T) *Proxy {
+	t.Helper()
+	p, err := Listen("127.0.0.1:0", startEcho(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+// roundTrip sends a line and waits up to timeout for the echo.
+func roundTrip(conn net.Conn, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	conn.SetDeadline(start.Add(timeout))
+	if _, err := conn.Write([]byte("ping\n")); err != nil {
+		return 0, err
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	if line != "ping\n" {
+		return 0, errors.New("unexpected echo " + line)
+	}
+	return time.Since(start), nil
+}
+
+func dial(t *testing.T, p *Proxy) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", p.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestProxyLatency(t *testing.T) {
+	p := startProxy(t)
+	conn := dial(t, p)
+
+	if _, err := roundTrip(conn, time.Second); err != nil {
+		t.Fatalf("round trip without faults: %v", err)
+	}
+	p.SetFaults(Faults{Latency: 100 * time.Millisecond})
+	took, err := roundTrip(conn, 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Applied once in each direction
+	if took < 200*time.Millisecond {
+		t.Fatalf("round trip took %v, want at least 200ms", took)
+	}
+}
+
+func TestProxyPartition(t *testing.T) {
+	p := startProxy(t)
+	conn := dial(t, p)
+
+	p.SetFaults(Faults{Partitioned: true})
+	if _, err := roundTrip(conn, 200*time.Millisecond); !isTimeout(err) {
+		t.Fatalf("round trip during partition = %v, want a timeout", err)
+	}
+
+	refused := dial(t, p)
+	refused.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := refused.Read(make([]byte, 1)); err == nil || isTimeout(err) {
+		t.Fatalf("new connection during partition read = %v, want it closed", err)
+	}
+
+	// The stalled ping is released on heal, then traffic flows again
+	p.Reset()
+	conn.SetDeadline(time.Now().Add(time.Second))
+	if line, err := bufio.NewReader(conn).ReadString('\n'); err != nil || line != "ping\n" {
+		t.Fatalf("stalled ping after heal = %q, %v", line, err)
+	}
+	if _, err := roundTrip(conn, time.Second); err != nil {
+		t.Fatalf("round trip after heal: %v", err)
+	}
+}
+
+func TestProxyKillConnections(t *testing.T) {
+	p := startProxy(t)
+	conn := dial(t, p)
+	if _, err := roundTrip(conn, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := p.KillConnections(); n != 2 {
+		t.Fatalf("killed %d connections, want client and upstream", n)
+	}
+	if _, err := roundTrip(conn, time.Second); err == nil || isTimeout(err) {
+		t.Fatalf("round trip on killed connection = %v, want a reset", err)
+	}
+	if _, err := roundTrip(dial(t, p), time.Second); err != nil {
+		t.Fatalf("new connection after kill: %v", err)
+	}
+}
+
+func TestControlAPI(t *testing.T) {
+	p := startProxy(t)
+	srv := httptest.NewServer(Handler(p))
+	defer srv.Close()
+	client := NewClient(srv.URL)
+	ctx := context.Background()
+
+	want := Faults{Latency: 5 * time.Second, Partitioned: true}
+	if err := client.SetFaults(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Faults(); got != want {
+		t.Fatalf("faults = %+v, want %+v", got, want)
+	}
+	if err := client.KillConnections(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Reset(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Faults(); got != (Faults{}) {
+		t.Fatalf("faults after reset = %+v", got)
+	}
+
+	bad := &Client{BaseURL: srv.URL, HTTP: srv.Client()}
+	if err := bad.do(ctx, "PUT", "/faults", []byte(`{"latency":"-1s"}`)); err == nil {
+		t.Fatal("negative latency accepted")
+	}
+	if err := bad.do(ctx, "GET", "/kill", nil); err == nil {
+		t.Fatal("GET /kill accepted")
+	}
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
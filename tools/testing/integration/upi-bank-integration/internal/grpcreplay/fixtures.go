@@ -0,0 +1,77 @@
+package grpcreplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Exchange is one recorded call: the messages the client sent, the messages
+// the upstream answered with, and the status the call ended with. Messages
+// are kept as their protobuf wire bytes, so the proxy works for any service
+// without its generated code.
+type Exchange struct {
+	Method    string     `json:"method"`
+	Requests  [][]byte   `json:"requests"`
+	Responses [][]byte   `json:"responses,omitempty"`
+	Code      codes.Code `json:"code"`
+	Message   string     `json:"message,omitempty"`
+}
+
+// Fixtures is a file of recorded exchanges, one JSON object per line.
+type Fixtures struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Append adds an exchange to the file, creating it if needed.
+func (f *Fixtures) Append(e Exchange) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load reads every exchange in the file, in the order they were recorded.
+// A missing file has none.
+func (f *Fixtures) Load() ([]Exchange, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.Open(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var exchanges []Exchange
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var e Exchange
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", f.Path, line, err)
+		}
+		exchanges = append(exchanges, e)
+	}
+	return exchanges, scanner.Err()
+}
@@ -0,0 +1,145 @@
+package grpcreplay
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+)
+
+// directory is a UPI Core that knows one VPA and counts its calls.
+type directory struct {
+	upicore.UnimplementedUpiCoreServer
+	calls     atomic.Int32
+	requestID atomic.Value
+}
+
+func (d *directory) ResolveVPA(ctx context.Context, req *upicore.ResolveVPARequest) (*upicore.ResolveVPAResponse, error) {
+	n := d.calls.Add(1)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-request-id")) > 0 {
+		d.requestID.Store(md.Get("x-request-id")[0])
+	}
+	if req.Vpa != "alice@hdfc" {
+		return nil, status.Errorf(codes.NotFound, "VPA %s not found", req.Vpa)
+	}
+	// The holder name changes with every call, to tell replayed answers apart
+	return &upicore.ResolveVPAResponse{Exists: true, BankCode: "HDFC", AccountHolderName: string(rune('A' + n - 1))}, nil
+}
+
+func startDirectory(t *testing.T) (*directory, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &directory{}
+	srv := grpc.NewServer()
+	upicore.RegisterUpiCoreServer(srv, d)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return d, lis.Addr().String()
+}
+
+func client(t *testing.T, addr string) upicore.UpiCoreClient {
+	t.Helper()
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return upicore.NewUpiCoreClient(conn)
+}
+
+func resolve(t *testing.T, c upicore.UpiCoreClient, vpa string) (*upicore.ResolveVPAResponse, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", "req-1")
+	return c.ResolveVPA(ctx, &upicore.ResolveVPARequest{Vpa: vpa})
+}
+
+// record runs calls through a recording proxy and returns the fixtures.
+func record(t *testing.T, calls func(upicore.UpiCoreClient)) *Fixtures {
+	t.Helper()
+	d, upstream := startDirectory(t)
+	fixtures := &Fixtures{Path: filepath.Join(t.TempDir(), "fixtures.jsonl")}
+	p, err := Record("127.0.0.1:0", upstream, fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls(client(t, p.Addr()))
+	p.Close()
+
+	if got := d.requestID.Load(); got != "req-1" {
+		t.Errorf("upstream saw request ID %v, want the client's metadata forwarded", got)
+	}
+	return fixtures
+}
+
+func replay(t *testing.T, fixtures *Fixtures, match Match) upicore.UpiCoreClient {
+	t.Helper()
+	p, err := Replay("127.0.0.1:0", fixtures, match)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return client(t, p.Addr())
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	fixtures := record(t, func(c upicore.UpiCoreClient) {
+		for _, vpa := range []string{"alice@hdfc", "alice@hdfc", "bob@sbi"} {
+			resolve(t, c, vpa)
+		}
+	})
+	exchanges, err := fixtures.Load()
+	if err != nil || len(exchanges) != 3 {
+		t.Fatalf("recorded %d exchanges, %v", len(exchanges), err)
+	}
+	if e := exchanges[2]; e.Method != "/upi_core.UpiCore/ResolveVPA" || e.Code != codes.NotFound || len(e.Responses) != 0 {
+		t.Errorf("failed call recorded as %+v", e)
+	}
+
+	// Nothing upstream now: every answer comes from the fixtures
+	c := replay(t, fixtures, MatchExact)
+	for _, want := range []string{"A", "B", "B"} {
+		resp, err := resolve(t, c, "alice@hdfc")
+		if err != nil || resp.AccountHolderName != want || resp.BankCode != "HDFC" {
+			t.Fatalf("replayed %+v, %v; want holder %s", resp, err, want)
+		}
+	}
+	if _, err := resolve(t, c, "bob@sbi"); status.Code(err) != codes.NotFound || status.Convert(err).Message() != "VPA bob@sbi not found" {
+		t.Errorf("replayed error = %v", err)
+	}
+	if _, err := resolve(t, c, "carol@icici"); status.Code(err) != codes.Unimplemented {
+		t.Errorf("unrecorded request: err = %v, want Unimplemented", err)
+	}
+}
+
+func TestReplayMatchingByMethod(t *testing.T) {
+	fixtures := record(t, func(c upicore.UpiCoreClient) {
+		resolve(t, c, "alice@hdfc")
+		resolve(t, c, "alice@hdfc")
+	})
+
+	c := replay(t, fixtures, MatchMethod)
+	for _, want := range []string{"A", "B"} {
+		resp, err := resolve(t, c, "someone-new@hdfc")
+		if err != nil || resp.AccountHolderName != want {
+			t.Fatalf("replayed %+v, %v; want holder %s", resp, err, want)
+		}
+	}
+	if _, err := c.ProcessTransaction(context.Background(), &upicore.TransactionRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("unrecorded method: err = %v, want Unimplemented", err)
+	}
+}
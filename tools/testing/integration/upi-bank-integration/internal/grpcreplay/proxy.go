@@ -0,0 +1,261 @@
+// Package grpcreplay is a gRPC proxy that records the calls a service makes
+// to one of its dependencies and replays them later without the dependency,
+// for example UPI Core's calls to a bank, or the payments gateway's calls to
+// UPI Core.
+//
+// Teams working downstream record fixtures once against the real services
+// and then develop and test against them with nothing else running. The
+// proxy handles any service: it forwards and stores messages as protobuf
+// wire bytes, without decoding them.
+package grpcreplay
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"log"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Match is how a replayed call is matched with a recorded one.
+type Match int
+
+const (
+	// MatchExact replays an exchange for the same method with byte-for-byte
+	// the same request messages.
+	MatchExact Match = iota
+	// MatchMethod replays the exchanges of a method in recorded order,
+	// whatever the requests, for callers that put fresh IDs or timestamps
+	// in every request.
+	MatchMethod
+)
+
+// Proxy is a gRPC server that either records or replays exchanges.
+type Proxy struct {
+	lis      net.Listener
+	srv      *grpc.Server
+	fixtures *Fixtures
+
+	// Recording
+	upstream *grpc.ClientConn
+
+	// Replaying
+	match Match
+	mu    sync.Mutex
+	queue map[string]*replayQueue
+}
+
+// replayQueue hands out the exchanges recorded for a key in order, and
+// keeps repeating the last one once they run out.
+type replayQueue struct {
+	exchanges []Exchange
+	next      int
+}
+
+// Record starts a proxy on addr (":0" for any port) that forwards every call
+// to upstream and appends each finished exchange to fixtures.
+func Record(addr, upstream string, fixtures *Fixtures) (*Proxy, error) {
+	conn, err := grpc.Dial(upstream,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{})),
+	)
+	if err != nil {
+		return nil, err
+	}
+	p := &Proxy{fixtures: fixtures, upstream: conn}
+	if err := p.listen(addr, p.record); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// Replay starts a proxy on addr that answers calls from fixtures alone.
+// Calls with no matching exchange fail with codes.Unimplemented.
+func Replay(addr string, fixtures *Fixtures, match Match) (*Proxy, error) {
+	exchanges, err := fixtures.Load()
+	if err != nil {
+		return nil, err
+	}
+	p := &Proxy{fixtures: fixtures, match: match, queue: make(map[string]*replayQueue)}
+	for _, e := range exchanges {
+		key := p.key(e.Method, e.Requests)
+		q := p.queue[key]
+		if q == nil {
+			q = &replayQueue{}
+			p.queue[key] = q
+		}
+		q.exchanges = append(q.exchanges, e)
+	}
+	if err := p.listen(addr, p.replay); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Proxy) listen(addr string, handler func(grpc.ServerStream) error) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	p.lis = lis
+	p.srv = grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+			return handler(stream)
+		}),
+	)
+	go p.srv.Serve(lis)
+	return nil
+}
+
+// Addr returns the address clients should dial instead of the upstream.
+func (p *Proxy) Addr() string {
+	return p.lis.Addr().String()
+}
+
+// Close stops the proxy, ending any calls in flight.
+func (p *Proxy) Close() error {
+	p.srv.Stop()
+	if p.upstream != nil {
+		return p.upstream.Close()
+	}
+	return nil
+}
+
+// record forwards a call in both directions and stores it once the
+// upstream has answered. Calls the client abandons are not stored.
+func (p *Proxy) record(stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(stream.Context(), md.Copy()))
+	defer cancel()
+
+	up, err := p.upstream.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}, method)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var requests [][]byte
+	go func() {
+		for {
+			var f frame
+			if err := stream.RecvMsg(&f); err != nil {
+				if err == io.EOF {
+					up.CloseSend()
+				} else {
+					cancel()
+				}
+				return
+			}
+			mu.Lock()
+			requests = append(requests, f.payload)
+			mu.Unlock()
+			// A failed send means the upstream ended the call; RecvMsg
+			// below reports how.
+			if up.SendMsg(&f) != nil {
+				return
+			}
+		}
+	}()
+
+	if header, err := up.Header(); err == nil && len(header) > 0 {
+		stream.SendHeader(header)
+	}
+	var responses [][]byte
+	var st *status.Status
+	for {
+		var f frame
+		err := up.RecvMsg(&f)
+		if err == io.EOF {
+			st = status.New(codes.OK, "")
+			break
+		}
+		if err != nil {
+			st = status.Convert(err)
+			break
+		}
+		responses = append(responses, f.payload)
+		if err := stream.SendMsg(&f); err != nil {
+			return err
+		}
+	}
+	stream.SetTrailer(up.Trailer())
+	if stream.Context().Err() != nil {
+		return stream.Context().Err()
+	}
+
+	mu.Lock()
+	exchange := Exchange{Method: method, Requests: requests, Responses: responses, Code: st.Code(), Message: st.Message()}
+	mu.Unlock()
+	if err := p.fixtures.Append(exchange); err != nil {
+		log.Printf("grpcreplay: failed to record %s: %v", method, err)
+	}
+	return st.Err()
+}
+
+// replay reads the whole request side of a call, then answers it from the
+// matching exchange. Streaming calls are answered once the client closes
+// its side, so calls that wait on a reply before sending more cannot be
+// replayed.
+func (p *Proxy) replay(stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	var requests [][]byte
+	for {
+		var f frame
+		err := stream.RecvMsg(&f)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		requests = append(requests, f.payload)
+	}
+
+	p.mu.Lock()
+	q := p.queue[p.key(method, requests)]
+	var e Exchange
+	if q != nil {
+		e = q.exchanges[q.next]
+		if q.next < len(q.exchanges)-1 {
+			q.next++
+		}
+	}
+	p.mu.Unlock()
+	if q == nil {
+		return status.Errorf(codes.Unimplemented, "grpcreplay: no recorded exchange for %s matches the request", method)
+	}
+
+	for _, payload := range e.Responses {
+		if err := stream.SendMsg(&frame{payload: payload}); err != nil {
+			return err
+		}
+	}
+	return status.Error(e.Code, e.Message)
+}
+
+// key identifies the exchanges a call can be answered with.
+func (p *Proxy) key(method string, requests [][]byte) string {
+	if p.match == MatchMethod {
+		return method
+	}
+	h := sha256.New()
+	var n [8]byte
+	for _, r := range requests {
+		binary.BigEndian.PutUint64(n[:], uint64(len(r)))
+		h.Write(n[:])
+		h.Write(r)
+	}
+	return method + " " + hex.EncodeToString(h.Sum(nil))
+}
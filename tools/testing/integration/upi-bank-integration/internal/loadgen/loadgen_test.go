@@ -0,0 +1,139 @@
+package loadgen
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseProfile(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Profile
+		ok   bool
+	}{
+		{"1m:100", Profile{{time.Minute, 100}}, true},
+		{"0s:50, 30s:50,10s:0", Profile{{0, 50}, {30 * time.Second, 50}, {10 * time.Second, 0}}, true},
+		{"", nil, false},
+		{"0s:10", nil, false},
+		{"30s", nil, false},
+		{"soon:10", nil, false},
+		{"30s:-1", nil, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseProfile(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseProfile(%q) error = %v, want ok %v", tt.in, err, tt.ok)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseProfile(%q) = %v, want %v", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseProfile(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestProfileRateAt(t *testing.T) {
+	profile := Profile{{10 * time.Second, 100}, {0, 20}, {10 * time.Second, 20}}
+
+	tests := []struct {
+		elapsed time.Duration
+		want    float64
+		ok      bool
+	}{
+		{0, 0, true},
+		{5 * time.Second, 50, true},
+		{10 * time.Second, 20, true}, // Zero-length stage jumps
+		{15 * time.Second, 20, true},
+		{20 * time.Second, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := profile.RateAt(tt.elapsed)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RateAt(%v) = %v, %v; want %v, %v", tt.elapsed, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	rec := newRecorder()
+	for i := 1; i <= 100; i++ {
+		outcome := Outcome{OK: true}
+		if i%10 == 0 {
+			outcome = Outcome{Class: "UNAVAILABLE"}
+		}
+		rec.record(time.Duration(i)*time.Millisecond, outcome)
+	}
+	rec.markSaturated()
+	r := rec.report(10 * time.Second)
+
+	if r.P50 != 50*time.Millisecond || r.P99 != 99*time.Millisecond || r.Max != 100*time.Millisecond {
+		t.Fatalf("p50 %v p99 %v max %v, want 50ms 99ms 100ms", r.P50, r.P99, r.Max)
+	}
+	if r.Sent != 100 || r.Succeeded != 90 || r.AchievedTPS != 10 {
+		t.Fatalf("sent %d succeeded %d tps %v, want 100 90 10", r.Sent, r.Succeeded, r.AchievedTPS)
+	}
+	if want := 90.0 / 101; r.SuccessRate != want {
+		t.Fatalf("success rate %v, want %v", r.SuccessRate, want)
+	}
+	if r.Errors["UNAVAILABLE"] != 10 || r.Errors[SaturatedClass] != 1 {
+		t.Fatalf("errors = %v", r.Errors)
+	}
+
+	tests := []struct {
+		slo  SLO
+		want int
+	}{
+		{SLO{}, 0},
+		{SLO{MaxP99: 100 * time.Millisecond, MinSuccessRate: 0.85}, 0},
+		{SLO{MaxP99: 50 * time.Millisecond}, 1},
+		{SLO{MaxP99: 50 * time.Millisecond, MinSuccessRate: 0.99}, 2},
+	}
+	for _, tt := range tests {
+		if got := r.Violations(tt.slo); len(got) != tt.want {
+			t.Errorf("Violations(%+v) = %q, want %d", tt.slo, got, tt.want)
+		}
+	}
+	if got := (Report{}).Violations(SLO{}); len(got) != 1 {
+		t.Errorf("empty run violations = %q, want one", got)
+	}
+}
+
+func TestRunOffersProfileRate(t *testing.T) {
+	profile := ConstantProfile(200, 500*time.Millisecond)
+	r := Run(context.Background(), Config{Profile: profile, Concurrency: 4}, func(ctx context.Context) Outcome {
+		return Outcome{OK: true}
+	})
+
+	// 100 requests are due; allow for timer slack on a loaded machine
+	if r.Sent+r.Saturated < 80 || r.Sent+r.Saturated > 101 {
+		t.Fatalf("offered %d requests, want about 100", r.Sent+r.Saturated)
+	}
+	if r.Succeeded != r.Sent {
+		t.Fatalf("succeeded %d of %d", r.Succeeded, r.Sent)
+	}
+}
+
+func TestRunCountsSaturation(t *testing.T) {
+	release := make(chan struct{})
+	profile := ConstantProfile(100, 200*time.Millisecond)
+	done := make(chan Report)
+	go func() {
+		done <- Run(context.Background(), Config{Profile: profile, Concurrency: 1}, func(ctx context.Context) Outcome {
+			<-release
+			return Outcome{OK: true}
+		})
+	}()
+	time.Sleep(300 * time.Millisecond)
+	close(release)
+	r := <-done
+
+	if r.Sent != 1 || r.Saturated == 0 || r.Errors[SaturatedClass] != r.Saturated {
+		t.Fatalf("sent %d saturated %d errors %v, want one sent and the rest saturated", r.Sent, r.Saturated, r.Errors)
+	}
+}
@@ -0,0 +1,79 @@
+// Package loadgen drives a request function at a target rate and
+// summarizes the latencies and failures it saw.
+package loadgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stage ramps the request rate linearly from the previous stage's target
+// (zero for the first stage) to Target over Duration, like a k6 stage.
+type Stage struct {
+	Duration time.Duration
+	Target   float64 // Requests per second at the end of the stage
+}
+
+// Profile is a sequence of stages.
+type Profile []Stage
+
+// ConstantProfile runs at tps for d.
+func ConstantProfile(tps float64, d time.Duration) Profile {
+	return Profile{{Duration: 0, Target: tps}, {Duration: d, Target: tps}}
+}
+
+// ParseProfile parses "duration:tps" stages separated by commas, e.g.
+// "30s:50,2m:200,30s:0" ramps to 50 TPS, then to 200, then back down.
+// A zero-length stage jumps straight to its target.
+func ParseProfile(s string) (Profile, error) {
+	var profile Profile
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		d, tps, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid stage %q, want duration:tps", part)
+		}
+		duration, err := time.ParseDuration(d)
+		if err != nil || duration < 0 {
+			return nil, fmt.Errorf("invalid stage %q: bad duration", part)
+		}
+		target, err := strconv.ParseFloat(tps, 64)
+		if err != nil || target < 0 {
+			return nil, fmt.Errorf("invalid stage %q: bad tps", part)
+		}
+		profile = append(profile, Stage{Duration: duration, Target: target})
+	}
+	if profile.Duration() == 0 {
+		return nil, fmt.Errorf("profile %q has no duration", s)
+	}
+	return profile, nil
+}
+
+// Duration returns the total length of the profile.
+func (p Profile) Duration() time.Duration {
+	var total time.Duration
+	for _, stage := range p {
+		total += stage.Duration
+	}
+	return total
+}
+
+// RateAt returns the target rate at elapsed, or false once the profile
+// has finished.
+func (p Profile) RateAt(elapsed time.Duration) (float64, bool) {
+	var from float64
+	for _, stage := range p {
+		if elapsed < stage.Duration {
+			progress := float64(elapsed) / float64(stage.Duration)
+			return from + (stage.Target-from)*progress, true
+		}
+		elapsed -= stage.Duration
+		from = stage.Target
+	}
+	return 0, false
+}
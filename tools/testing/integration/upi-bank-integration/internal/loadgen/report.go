@@ -0,0 +1,142 @@
+package loadgen
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Report summarizes a run. Latencies cover every request that was sent,
+// failed or not.
+type Report struct {
+	Elapsed     time.Duration  `json:"elapsed"`
+	Sent        int            `json:"sent"`
+	Succeeded   int            `json:"succeeded"`
+	Saturated   int            `json:"saturated"`
+	SuccessRate float64        `json:"success_rate"` // Of sent plus saturated
+	AchievedTPS float64        `json:"achieved_tps"`
+	P50         time.Duration  `json:"p50"`
+	P90         time.Duration  `json:"p90"`
+	P99         time.Duration  `json:"p99"`
+	Max         time.Duration  `json:"max"`
+	Errors      map[string]int `json:"errors"` // By class
+}
+
+// SLO is the pass bar for a run. Zero fields are not checked.
+type SLO struct {
+	MaxP99         time.Duration
+	MinSuccessRate float64 // 0..1
+}
+
+// Violations lists every way r misses slo; empty means the run passed.
+func (r Report) Violations(slo SLO) []string {
+	var violations []string
+	if slo.MaxP99 > 0 && r.P99 > slo.MaxP99 {
+		violations = append(violations, fmt.Sprintf("p99 %v exceeds %v", r.P99, slo.MaxP99))
+	}
+	if slo.MinSuccessRate > 0 && r.SuccessRate < slo.MinSuccessRate {
+		violations = append(violations, fmt.Sprintf("success rate %.3f%% below %.3f%%", r.SuccessRate*100, slo.MinSuccessRate*100))
+	}
+	if r.Sent+r.Saturated == 0 {
+		violations = append(violations, "no requests were sent")
+	}
+	return violations
+}
+
+// WriteText prints the report for people.
+func (r Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "elapsed:      %v\n", r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "sent:         %d (%.1f/s)\n", r.Sent, r.AchievedTPS)
+	fmt.Fprintf(w, "succeeded:    %d (%.3f%%)\n", r.Succeeded, r.SuccessRate*100)
+	fmt.Fprintf(w, "latency:      p50 %v  p90 %v  p99 %v  max %v\n", r.P50, r.P90, r.P99, r.Max)
+
+	classes := make([]string, 0, len(r.Errors))
+	for class := range r.Errors {
+		classes = append(classes, class)
+	}
+	sort.Slice(classes, func(i, j int) bool { return r.Errors[classes[i]] > r.Errors[classes[j]] })
+	if len(classes) > 0 {
+		fmt.Fprintln(w, "errors:")
+	}
+	for _, class := range classes {
+		fmt.Fprintf(w, "  %-28s %d\n", class, r.Errors[class])
+	}
+}
+
+type recorder struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	succeeded int
+	saturated int
+	errors    map[string]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{errors: make(map[string]int)}
+}
+
+func (r *recorder) record(latency time.Duration, outcome Outcome) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies = append(r.latencies, latency)
+	if outcome.OK {
+		r.succeeded++
+		return
+	}
+	class := outcome.Class
+	if class == "" {
+		class = "unknown"
+	}
+	r.errors[class]++
+}
+
+func (r *recorder) markSaturated() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saturated++
+	r.errors[SaturatedClass]++
+}
+
+func (r *recorder) report(elapsed time.Duration) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sorted := append([]time.Duration(nil), r.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	report := Report{
+		Elapsed:   elapsed,
+		Sent:      len(sorted),
+		Succeeded: r.succeeded,
+		Saturated: r.saturated,
+		P50:       percentile(sorted, 0.50),
+		P90:       percentile(sorted, 0.90),
+		P99:       percentile(sorted, 0.99),
+		Errors:    make(map[string]int, len(r.errors)),
+	}
+	if len(sorted) > 0 {
+		report.Max = sorted[len(sorted)-1]
+	}
+	if offered := report.Sent + report.Saturated; offered > 0 {
+		report.SuccessRate = float64(report.Succeeded) / float64(offered)
+	}
+	if elapsed > 0 {
+		report.AchievedTPS = float64(report.Sent) / elapsed.Seconds()
+	}
+	for class, n := range r.errors {
+		report.Errors[class] = n
+	}
+	return report
+}
+
+// percentile uses the nearest-rank method on sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
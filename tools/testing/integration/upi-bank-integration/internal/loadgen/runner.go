@@ -0,0 +1,101 @@
+package loadgen
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Outcome is the result of one request. Class groups failures for the
+// report, e.g. a gRPC code or a transaction status; it is ignored when OK.
+type Outcome struct {
+	OK    bool
+	Class string
+}
+
+// Func sends one request.
+type Func func(ctx context.Context) Outcome
+
+// Config controls a run.
+type Config struct {
+	Profile        Profile
+	Concurrency    int           // Requests in flight at most; default 50
+	RequestTimeout time.Duration // Default 5s
+}
+
+// SaturatedClass counts requests that were due but not sent because every
+// worker was busy. They count as failures: the target rate was not offered.
+const SaturatedClass = "client_saturated"
+
+// Run offers requests at the profile's rate until it ends or ctx is
+// cancelled. The schedule is open-loop: a slow server does not slow the
+// offered rate down, so latency under overload shows up in the report
+// instead of being hidden by back-pressure.
+func Run(ctx context.Context, cfg Config, fn Func) Report {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 50
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 5 * time.Second
+	}
+
+	rec := newRecorder()
+	jobs := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				reqCtx, cancel := context.WithTimeout(ctx, cfg.RequestTimeout)
+				start := time.Now()
+				outcome := fn(reqCtx)
+				rec.record(time.Since(start), outcome)
+				cancel()
+			}
+		}()
+	}
+
+	start := time.Now()
+	next := start
+	for ctx.Err() == nil {
+		now := time.Now()
+		rate, ok := cfg.Profile.RateAt(now.Sub(start))
+		if !ok {
+			break
+		}
+		if rate <= 0 {
+			// Nothing to send; look again shortly in case a ramp starts
+			next = now.Add(10 * time.Millisecond)
+			sleep(ctx, 10*time.Millisecond)
+			continue
+		}
+		if wait := next.Sub(now); wait > 0 {
+			sleep(ctx, wait)
+			continue
+		}
+
+		select {
+		case jobs <- struct{}{}:
+		default:
+			rec.markSaturated()
+		}
+		next = next.Add(time.Duration(float64(time.Second) / rate))
+		if behind := time.Since(next); behind > time.Second {
+			// Do not burst to catch up after a stall, such as a GC pause
+			next = time.Now()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return rec.report(time.Since(start))
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
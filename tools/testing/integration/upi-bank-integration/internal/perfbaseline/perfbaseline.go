@@ -0,0 +1,198 @@
+// Package perfbaseline records the latency of each suite run and compares
+// a run with the runs before it.
+//
+// A fixed latency bar is either too loose to catch a regression or too tight
+// for a slower machine. Comparing p95 with the median of recent runs on the
+// same store adapts to wherever the suite runs and still fails when a
+// change makes things slower.
+package perfbaseline
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// Result summarizes the latencies of one measurement, e.g. VPA resolution.
+type Result struct {
+	Samples int           `json:"samples"`
+	P50     time.Duration `json:"p50"`
+	P95     time.Duration `json:"p95"`
+	Max     time.Duration `json:"max"`
+}
+
+// Summarize computes a Result from raw latencies.
+func Summarize(latencies []time.Duration) Result {
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	r := Result{
+		Samples: len(sorted),
+		P50:     percentile(sorted, 0.50),
+		P95:     percentile(sorted, 0.95),
+	}
+	if len(sorted) > 0 {
+		r.Max = sorted[len(sorted)-1]
+	}
+	return r
+}
+
+// Run is one suite run's results by measurement name.
+type Run struct {
+	ID      string            `json:"id"`
+	Time    time.Time         `json:"time"`
+	Results map[string]Result `json:"results"`
+	// Regressed runs are kept for the record but left out of baselines,
+	// so a regression cannot become the new normal by repetition.
+	Regressed bool `json:"regressed,omitempty"`
+}
+
+// Store is a file of runs, one JSON object per line, oldest first.
+type Store struct {
+	Path string
+}
+
+// Load returns every run in the store. A missing file is an empty store.
+func (s Store) Load() ([]Run, error) {
+	f, err := os.Open(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.Path, line, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, scanner.Err()
+}
+
+// Append adds run to the end of the store, creating it if needed.
+func (s Store) Append(run Run) error {
+	raw, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(raw, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Policy decides when a run has regressed. Zero fields take the defaults.
+type Policy struct {
+	// Threshold is how far p95 may exceed the baseline, as a fraction;
+	// default 0.2.
+	Threshold float64
+	// MinDelta is the smallest increase that counts, so jitter on
+	// millisecond latencies does not fail runs; default 5ms.
+	MinDelta time.Duration
+	// Window is how many recent runs form the baseline; default 10.
+	Window int
+	// MinRuns is how many runs a measurement needs before it is compared;
+	// default 3.
+	MinRuns int
+}
+
+func (p Policy) withDefaults() Policy {
+	if p.Threshold <= 0 {
+		p.Threshold = 0.2
+	}
+	if p.MinDelta <= 0 {
+		p.MinDelta = 5 * time.Millisecond
+	}
+	if p.Window <= 0 {
+		p.Window = 10
+	}
+	if p.MinRuns <= 0 {
+		p.MinRuns = 3
+	}
+	return p
+}
+
+// Baseline is the median p95 of each measurement over the last Window runs
+// that did not regress. Measurements with fewer than MinRuns runs are left
+// out.
+func (p Policy) Baseline(history []Run) map[string]time.Duration {
+	p = p.withDefaults()
+	p95s := make(map[string][]time.Duration)
+	used := 0
+	for i := len(history) - 1; i >= 0 && used < p.Window; i-- {
+		if history[i].Regressed {
+			continue
+		}
+		used++
+		for name, result := range history[i].Results {
+			p95s[name] = append(p95s[name], result.P95)
+		}
+	}
+
+	baseline := make(map[string]time.Duration)
+	for name, values := range p95s {
+		if len(values) < p.MinRuns {
+			continue
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		baseline[name] = values[len(values)/2]
+	}
+	return baseline
+}
+
+// Regressions lists every measurement in run whose p95 is worse than the
+// baseline of history allows; empty means the run passed.
+func (p Policy) Regressions(run Run, history []Run) []string {
+	p = p.withDefaults()
+	baseline := p.Baseline(history)
+
+	names := make([]string, 0, len(run.Results))
+	for name := range run.Results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var regressions []string
+	for _, name := range names {
+		base, ok := baseline[name]
+		if !ok {
+			continue
+		}
+		p95 := run.Results[name].P95
+		limit := time.Duration(float64(base) * (1 + p.Threshold))
+		if floor := base + p.MinDelta; limit < floor {
+			limit = floor
+		}
+		if p95 > limit {
+			regressions = append(regressions, fmt.Sprintf("%s p95 %v exceeds baseline %v by more than %.0f%%", name, p95, base, p.Threshold*100))
+		}
+	}
+	return regressions
+}
+
+// percentile uses the nearest-rank method on sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
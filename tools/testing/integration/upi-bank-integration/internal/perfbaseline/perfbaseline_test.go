@@ -0,0 +1,107 @@
+package perfbaseline
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ms(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+func run(id string, p95 time.Duration) Run {
+	return Run{ID: id, Results: map[string]Result{"vpa": {P95: p95}}}
+}
+
+func TestSummarize(t *testing.T) {
+	var latencies []time.Duration
+	for i := 20; i >= 1; i-- {
+		latencies = append(latencies, ms(i))
+	}
+	got := Summarize(latencies)
+	want := Result{Samples: 20, P50: ms(10), P95: ms(19), Max: ms(20)}
+	if got != want {
+		t.Fatalf("Summarize = %+v, want %+v", got, want)
+	}
+	if got := Summarize(nil); got != (Result{}) {
+		t.Fatalf("Summarize(nil) = %+v", got)
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := Store{Path: filepath.Join(t.TempDir(), "results.jsonl")}
+	runs, err := store.Load()
+	if err != nil || len(runs) != 0 {
+		t.Fatalf("Load of missing store = %v, %v", runs, err)
+	}
+
+	first, second := run("a", ms(10)), run("b", ms(12))
+	second.Regressed = true
+	for _, r := range []Run{first, second} {
+		if err := store.Append(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runs, err = store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != "a" || runs[1].ID != "b" || !runs[1].Regressed || runs[0].Results["vpa"].P95 != ms(10) {
+		t.Fatalf("Load = %+v", runs)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	history := []Run{run("old", ms(500)), run("1", ms(10)), run("2", ms(30)), run("3", ms(20))}
+
+	got := Policy{Window: 3}.Baseline(history)
+	if got["vpa"] != ms(20) {
+		t.Fatalf("baseline = %v, want median of the last 3 runs, 20ms", got["vpa"])
+	}
+
+	// Regressed runs neither count nor use up the window
+	history = append(history, Run{ID: "bad", Regressed: true, Results: map[string]Result{"vpa": {P95: ms(900)}}})
+	if got := (Policy{Window: 3}).Baseline(history); got["vpa"] != ms(20) {
+		t.Fatalf("baseline with a regressed run = %v, want 20ms", got["vpa"])
+	}
+
+	if got := (Policy{MinRuns: 5}).Baseline(history); len(got) != 0 {
+		t.Fatalf("baseline from too few runs = %v, want none", got)
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	history := []Run{run("1", ms(100)), run("2", ms(100)), run("3", ms(100))}
+	policy := Policy{Threshold: 0.2}
+
+	tests := []struct {
+		p95       time.Duration
+		regressed bool
+	}{
+		{ms(100), false},
+		{ms(120), false},
+		{ms(121), true},
+	}
+	for _, tt := range tests {
+		got := policy.Regressions(run("new", tt.p95), history)
+		if (len(got) > 0) != tt.regressed {
+			t.Errorf("p95 %v: regressions = %v, want regressed %v", tt.p95, got, tt.regressed)
+		}
+	}
+
+	got := policy.Regressions(run("new", ms(200)), history)
+	if len(got) != 1 || !strings.Contains(got[0], "vpa") {
+		t.Fatalf("regressions = %v", got)
+	}
+
+	// Small absolute increases on fast calls are jitter, not regressions
+	fast := []Run{run("1", ms(2)), run("2", ms(2)), run("3", ms(2))}
+	if got := policy.Regressions(run("new", ms(6)), fast); len(got) != 0 {
+		t.Fatalf("jitter flagged: %v", got)
+	}
+
+	// Without enough history there is nothing to compare with
+	if got := policy.Regressions(run("new", ms(900)), history[:2]); len(got) != 0 {
+		t.Fatalf("compared with too little history: %v", got)
+	}
+}
@@ -0,0 +1,184 @@
+// Package probe holds the health, VPA and transaction checks the suite and
+// the synthetic prober both run, and the loop that runs them on an interval
+// and exports their results as Prometheus metrics.
+package probe
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+)
+
+// Check is one probe: nil means the service behaved.
+type Check func(ctx context.Context) error
+
+// Probe is a named Check.
+type Probe struct {
+	Name  string
+	Check Check
+}
+
+// BankHealth checks the Bank Simulator reports bank as healthy.
+func BankHealth(client banksim.BankSimulatorClient, bank string) Check {
+	return func(ctx context.Context) error {
+		resp, err := client.CheckBankHealth(ctx, &banksim.BankHealthRequest{BankCode: bank})
+		if err != nil {
+			return err
+		}
+		if resp.HealthStatus != banksim.HealthStatus_HEALTH_STATUS_HEALTHY {
+			return fmt.Errorf("bank %s is %v", bank, resp.HealthStatus)
+		}
+		return nil
+	}
+}
+
+// GRPCHealth checks a server's standard gRPC health service reports
+// SERVING.
+func GRPCHealth(client grpc_health_v1.HealthClient) Check {
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+			return fmt.Errorf("health status %v", resp.Status)
+		}
+		return nil
+	}
+}
+
+// ResolveVPA checks UPI Core resolves vpa to an active account.
+func ResolveVPA(client upicore.UpiCoreClient, vpa string) Check {
+	return func(ctx context.Context) error {
+		resp, err := client.ResolveVPA(ctx, &upicore.ResolveVPARequest{Vpa: vpa})
+		if err != nil {
+			return err
+		}
+		if !resp.Exists || !resp.IsActive {
+			return fmt.Errorf("%s does not resolve to an active account: %s %s", vpa, resp.ErrorCode, resp.ErrorMessage)
+		}
+		return nil
+	}
+}
+
+// RoundTrip pays amountPaisa from a to b through UPI Core and then back,
+// so probing repeatedly leaves both balances where they were. Both
+// payments must succeed.
+func RoundTrip(client upicore.UpiCoreClient, a, b string, amountPaisa int64) Check {
+	return func(ctx context.Context) error {
+		for _, leg := range [][2]string{{a, b}, {b, a}} {
+			id := uuid.New().String()
+			resp, err := client.ProcessTransaction(ctx, &upicore.TransactionRequest{
+				TransactionId: "PROBE_" + id,
+				Rrn:           id[:12],
+				PayerVpa:      leg[0],
+				PayeeVpa:      leg[1],
+				AmountPaisa:   amountPaisa,
+				Currency:      "INR",
+				Type:          upicore.TransactionType_TRANSACTION_TYPE_P2P,
+				Reference:     "SYNTHETIC_PROBE",
+				InitiatedAt:   timestamppb.Now(),
+			})
+			if err != nil {
+				return err
+			}
+			if resp.Status != upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS {
+				return fmt.Errorf("%s to %s: %v %s %s", leg[0], leg[1], resp.Status, resp.ErrorCode, resp.ErrorMessage)
+			}
+		}
+		return nil
+	}
+}
+
+// Metrics are the prober's Prometheus metrics, labelled by probe name.
+type Metrics struct {
+	runs        *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	success     *prometheus.GaugeVec
+	lastSuccess *prometheus.GaugeVec
+}
+
+// NewMetrics registers the prober's metrics with reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "upi_probe_runs_total",
+			Help: "Probe runs by result, success or failure.",
+		}, []string{"probe", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "upi_probe_duration_seconds",
+			Help:    "How long each probe took, failed or not.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"probe"}),
+		success: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "upi_probe_success",
+			Help: "1 if the last run of the probe succeeded, 0 if it failed.",
+		}, []string{"probe"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "upi_probe_last_success_timestamp_seconds",
+			Help: "Unix time the probe last succeeded.",
+		}, []string{"probe"}),
+	}
+	reg.MustRegister(m.runs, m.duration, m.success, m.lastSuccess)
+	return m
+}
+
+// Result is the outcome of one probe run.
+type Result struct {
+	Probe    string
+	Duration time.Duration
+	Err      error
+}
+
+// RunOnce runs every probe in turn, each with its own timeout, and records
+// the results in m when it is not nil.
+func RunOnce(ctx context.Context, probes []Probe, timeout time.Duration, m *Metrics) []Result {
+	results := make([]Result, 0, len(probes))
+	for _, p := range probes {
+		probeCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := p.Check(probeCtx)
+		cancel()
+		result := Result{Probe: p.Name, Duration: time.Since(start), Err: err}
+		results = append(results, result)
+		if m != nil {
+			m.record(result)
+		}
+	}
+	return results
+}
+
+func (m *Metrics) record(r Result) {
+	m.duration.WithLabelValues(r.Probe).Observe(r.Duration.Seconds())
+	if r.Err != nil {
+		m.runs.WithLabelValues(r.Probe, "failure").Inc()
+		m.success.WithLabelValues(r.Probe).Set(0)
+		return
+	}
+	m.runs.WithLabelValues(r.Probe, "success").Inc()
+	m.success.WithLabelValues(r.Probe).Set(1)
+	m.lastSuccess.WithLabelValues(r.Probe).SetToCurrentTime()
+}
+
+// Loop runs the probes every interval until ctx is cancelled, passing each
+// round's results to report.
+func Loop(ctx context.Context, interval, timeout time.Duration, probes []Probe, m *Metrics, report func([]Result)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report(RunOnce(ctx, probes, timeout, m))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+)
+
+type fakeBank struct {
+	banksim.UnimplementedBankSimulatorServer
+	status banksim.HealthStatus
+}
+
+func (b *fakeBank) CheckBankHealth(ctx context.Context, req *banksim.BankHealthRequest) (*banksim.BankHealthResponse, error) {
+	return &banksim.BankHealthResponse{BankCode: req.BankCode, HealthStatus: b.status}, nil
+}
+
+// fakeUpiCore declines payments from decline and records the rest.
+type fakeUpiCore struct {
+	upicore.UnimplementedUpiCoreServer
+	decline  string
+	payments [][2]string
+}
+
+func (u *fakeUpiCore) ResolveVPA(ctx context.Context, req *upicore.ResolveVPARequest) (*upicore.ResolveVPAResponse, error) {
+	ok := strings.HasSuffix(req.Vpa, "@hdfc")
+	return &upicore.ResolveVPAResponse{Exists: ok, IsActive: ok}, nil
+}
+
+func (u *fakeUpiCore) ProcessTransaction(ctx context.Context, req *upicore.TransactionRequest) (*upicore.TransactionResponse, error) {
+	if req.PayerVpa == u.decline {
+		return &upicore.TransactionResponse{Status: upicore.TransactionStatus_TRANSACTION_STATUS_FAILED, ErrorCode: "INSUFFICIENT_FUNDS"}, nil
+	}
+	u.payments = append(u.payments, [2]string{req.PayerVpa, req.PayeeVpa})
+	return &upicore.TransactionResponse{Status: upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS}, nil
+}
+
+func serve(t *testing.T, register func(*grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestChecks(t *testing.T) {
+	bank := &fakeBank{status: banksim.HealthStatus_HEALTH_STATUS_HEALTHY}
+	upi := &fakeUpiCore{}
+	bankClient := banksim.NewBankSimulatorClient(serve(t, func(s *grpc.Server) { banksim.RegisterBankSimulatorServer(s, bank) }))
+	upiClient := upicore.NewUpiCoreClient(serve(t, func(s *grpc.Server) { upicore.RegisterUpiCoreServer(s, upi) }))
+	ctx := context.Background()
+
+	if err := BankHealth(bankClient, "HDFC")(ctx); err != nil {
+		t.Errorf("healthy bank: %v", err)
+	}
+	bank.status = banksim.HealthStatus_HEALTH_STATUS_MAINTENANCE
+	if err := BankHealth(bankClient, "HDFC")(ctx); err == nil || !strings.Contains(err.Error(), "MAINTENANCE") {
+		t.Errorf("bank in maintenance: %v", err)
+	}
+
+	if err := ResolveVPA(upiClient, "a@hdfc")(ctx); err != nil {
+		t.Errorf("known VPA: %v", err)
+	}
+	if err := ResolveVPA(upiClient, "a@nowhere")(ctx); err == nil {
+		t.Error("unknown VPA passed")
+	}
+
+	if err := RoundTrip(upiClient, "a@hdfc", "b@hdfc", 100)(ctx); err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	if want := [][2]string{{"a@hdfc", "b@hdfc"}, {"b@hdfc", "a@hdfc"}}; len(upi.payments) != 2 || upi.payments[0] != want[0] || upi.payments[1] != want[1] {
+		t.Fatalf("payments = %v, want %v", upi.payments, want)
+	}
+	upi.decline = "b@hdfc"
+	if err := RoundTrip(upiClient, "a@hdfc", "b@hdfc", 100)(ctx); err == nil || !strings.Contains(err.Error(), "INSUFFICIENT_FUNDS") {
+		t.Errorf("declined return leg: %v", err)
+	}
+}
+
+func TestRunOnceRecordsMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+	probes := []Probe{
+		{Name: "ok", Check: func(context.Context) error { return nil }},
+		{Name: "broken", Check: func(context.Context) error { return errors.New("down") }},
+		{Name: "slow", Check: func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() }},
+	}
+
+	results := RunOnce(context.Background(), probes, 10*time.Millisecond, m)
+	if len(results) != 3 || results[0].Err != nil || results[1].Err == nil || !errors.Is(results[2].Err, context.DeadlineExceeded) {
+		t.Fatalf("results = %+v", results)
+	}
+
+	for _, tc := range []struct {
+		probe   string
+		success float64
+	}{{"ok", 1}, {"broken", 0}, {"slow", 0}} {
+		if got := testutil.ToFloat64(m.success.WithLabelValues(tc.probe)); got != tc.success {
+			t.Errorf("upi_probe_success{probe=%q} = %v, want %v", tc.probe, got, tc.success)
+		}
+	}
+	if got := testutil.ToFloat64(m.runs.WithLabelValues("broken", "failure")); got != 1 {
+		t.Errorf("failures of broken = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.lastSuccess.WithLabelValues("ok")); got == 0 {
+		t.Error("last success of ok not set")
+	}
+	if got := testutil.ToFloat64(m.lastSuccess.WithLabelValues("broken")); got != 0 {
+		t.Errorf("last success of broken = %v, want unset", got)
+	}
+}
+
+func TestLoopStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rounds := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Loop(ctx, time.Millisecond, time.Second, []Probe{{Name: "ok", Check: func(context.Context) error { return nil }}}, nil, func([]Result) {
+			if rounds++; rounds == 3 {
+				cancel()
+			}
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Loop did not stop after cancel")
+	}
+	if rounds < 3 {
+		t.Fatalf("ran %d rounds, want at least 3", rounds)
+	}
+}
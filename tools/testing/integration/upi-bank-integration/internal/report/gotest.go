@@ -0,0 +1,153 @@
+// Package report turns a suite run into artifacts for release sign-off:
+// JUnit XML for CI test views and a self-contained HTML summary with
+// scenario results, latency charts and sample requests and responses.
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Status is the outcome of a test or package.
+type Status string
+
+const (
+	Pass Status = "pass"
+	Fail Status = "fail"
+	Skip Status = "skip"
+)
+
+// Test is one test or subtest.
+type Test struct {
+	Name    string
+	Status  Status
+	Elapsed time.Duration
+	Output  string
+}
+
+// Package is one package's tests, in the order they started.
+type Package struct {
+	Name    string
+	Status  Status
+	Elapsed time.Duration
+	Tests   []Test
+	// Output is what the package printed outside any test, such as a
+	// build failure or a panic in TestMain.
+	Output string
+}
+
+// Count returns how many of p's tests ended with status.
+func (p Package) Count(status Status) int {
+	n := 0
+	for _, t := range p.Tests {
+		if t.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// event is one line of go test -json.
+type event struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// ParseGoTestJSON reads the output of go test -json. Lines that are not
+// JSON, such as build errors on a merged stderr, are skipped. Tests still
+// running when the stream ends, as after a panic or timeout, count as
+// failed.
+func ParseGoTestJSON(r io.Reader) ([]Package, error) {
+	var packages []*Package
+	byName := make(map[string]*Package)
+	tests := make(map[string]map[string]int)
+	// Keyed by package and test, as growing Tests moves its elements
+	output := make(map[[2]string]*strings.Builder)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := scanner.Bytes()
+		if len(raw) == 0 || raw[0] != '{' {
+			continue
+		}
+		var e event
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if e.Package == "" {
+			continue
+		}
+		pkg, ok := byName[e.Package]
+		if !ok {
+			pkg = &Package{Name: e.Package}
+			byName[e.Package] = pkg
+			packages = append(packages, pkg)
+			tests[e.Package] = make(map[string]int)
+		}
+
+		if e.Test == "" {
+			switch e.Action {
+			case "output":
+				pkg.Output += e.Output
+			case "pass", "fail", "skip":
+				pkg.Status = Status(e.Action)
+				pkg.Elapsed = seconds(e.Elapsed)
+			}
+			continue
+		}
+
+		i, ok := tests[e.Package][e.Test]
+		if !ok {
+			i = len(pkg.Tests)
+			tests[e.Package][e.Test] = i
+			pkg.Tests = append(pkg.Tests, Test{Name: e.Test})
+		}
+		switch e.Action {
+		case "output":
+			key := [2]string{e.Package, e.Test}
+			b := output[key]
+			if b == nil {
+				b = &strings.Builder{}
+				output[key] = b
+			}
+			b.WriteString(e.Output)
+		case "pass", "fail", "skip":
+			pkg.Tests[i].Status = Status(e.Action)
+			pkg.Tests[i].Elapsed = seconds(e.Elapsed)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]Package, 0, len(packages))
+	for _, pkg := range packages {
+		for i := range pkg.Tests {
+			test := &pkg.Tests[i]
+			if b := output[[2]string{pkg.Name, test.Name}]; b != nil {
+				test.Output = b.String()
+			}
+			if test.Status == "" {
+				test.Status = Fail
+				test.Output += "(test did not finish)\n"
+			}
+		}
+		if pkg.Status == "" {
+			pkg.Status = Fail
+		}
+		result = append(result, *pkg)
+	}
+	return result, nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
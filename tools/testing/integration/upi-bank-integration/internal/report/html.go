@@ -0,0 +1,219 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/suuupra/upi-bank-integration-tests/internal/loadgen"
+	"github.com/suuupra/upi-bank-integration-tests/internal/perfbaseline"
+)
+
+// LoadRun is a named load generator report, as written by cmd/loadgen -json.
+type LoadRun struct {
+	Name   string
+	Report loadgen.Report
+}
+
+// Summary is what the HTML summary shows. Every part but Packages is
+// optional.
+type Summary struct {
+	Title     string
+	Generated time.Time
+	Packages  []Package
+	// Perf is the performance history, oldest first; the charts show its
+	// last TrendRuns runs.
+	Perf    []perfbaseline.Run
+	Load    []LoadRun
+	Samples []Sample
+}
+
+// TrendRuns is how many recent runs the p95 trend charts cover.
+const TrendRuns = 30
+
+const (
+	chartWidth  = 640
+	chartHeight = 160
+	barHeight   = 22
+)
+
+type trendChart struct {
+	Name          string
+	Points        string
+	Latest, Worst time.Duration
+	Runs          int
+}
+
+type bar struct {
+	Label string
+	Value time.Duration
+	Width int
+	Y     int
+}
+
+type loadView struct {
+	LoadRun
+	Bars       []bar
+	Height     int
+	ErrorClass []string
+}
+
+type pageData struct {
+	Summary
+	Passed, Failed, Skipped int
+	Trends                  []trendChart
+	Loads                   []loadView
+	ChartWidth, ChartHeight int
+}
+
+// WriteHTML writes s as a single self-contained HTML page.
+func WriteHTML(w io.Writer, s Summary) error {
+	data := pageData{Summary: s, Trends: trends(s.Perf), ChartWidth: chartWidth, ChartHeight: chartHeight}
+	for _, pkg := range s.Packages {
+		data.Passed += pkg.Count(Pass)
+		data.Failed += pkg.Count(Fail)
+		data.Skipped += pkg.Count(Skip)
+	}
+	for _, run := range s.Load {
+		data.Loads = append(data.Loads, loadChart(run))
+	}
+	return page.Execute(w, data)
+}
+
+// trends charts each measurement's p95 over the recent runs.
+func trends(runs []perfbaseline.Run) []trendChart {
+	if len(runs) > TrendRuns {
+		runs = runs[len(runs)-TrendRuns:]
+	}
+	series := make(map[string][]time.Duration)
+	for _, run := range runs {
+		for name, result := range run.Results {
+			series[name] = append(series[name], result.P95)
+		}
+	}
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var charts []trendChart
+	for _, name := range names {
+		values := series[name]
+		chart := trendChart{Name: name, Latest: values[len(values)-1], Runs: len(values)}
+		for _, v := range values {
+			chart.Worst = max(chart.Worst, v)
+		}
+		points := make([]string, len(values))
+		for i, v := range values {
+			x := 0
+			if len(values) > 1 {
+				x = i * chartWidth / (len(values) - 1)
+			}
+			y := chartHeight
+			if chart.Worst > 0 {
+				y = chartHeight - int(int64(v)*int64(chartHeight-10)/int64(chart.Worst))
+			}
+			points[i] = fmt.Sprintf("%d,%d", x, y)
+		}
+		chart.Points = strings.Join(points, " ")
+		charts = append(charts, chart)
+	}
+	return charts
+}
+
+// loadChart draws a run's latency percentiles as bars on one scale.
+func loadChart(run LoadRun) loadView {
+	r := run.Report
+	view := loadView{LoadRun: run}
+	for i, b := range []bar{{Label: "p50", Value: r.P50}, {Label: "p90", Value: r.P90}, {Label: "p99", Value: r.P99}, {Label: "max", Value: r.Max}} {
+		if r.Max > 0 {
+			b.Width = int(int64(b.Value) * int64(chartWidth-160) / int64(r.Max))
+		}
+		b.Y = i * (barHeight + 6)
+		view.Bars = append(view.Bars, b)
+	}
+	view.Height = len(view.Bars) * (barHeight + 6)
+	for class := range r.Errors {
+		view.ErrorClass = append(view.ErrorClass, class)
+	}
+	sort.Slice(view.ErrorClass, func(i, j int) bool { return r.Errors[view.ErrorClass[i]] > r.Errors[view.ErrorClass[j]] })
+	return view
+}
+
+var page = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ms":  func(d time.Duration) string { return d.Round(100 * time.Microsecond).String() },
+	"pct": func(f float64) string { return fmt.Sprintf("%.2f%%", f*100) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font: 14px/1.4 system-ui, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+.pass { color: #1a7f37; } .fail { color: #cf222e; font-weight: bold; } .skip { color: #9a6700; }
+pre { background: #f6f8fa; padding: 8px; max-height: 20em; overflow: auto; margin: 0; }
+svg { background: #fafafa; border: 1px solid #eee; }
+.totals span { margin-right: 2em; font-size: 120%; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}</p>
+<p class="totals"><span class="pass">{{.Passed}} passed</span><span class="fail">{{.Failed}} failed</span><span class="skip">{{.Skipped}} skipped</span></p>
+
+<h2>Scenarios</h2>
+{{range .Packages}}
+<h3>{{.Name}} <span class="{{.Status}}">{{.Status}}</span> in {{ms .Elapsed}}</h3>
+<table>
+<tr><th>Test</th><th>Result</th><th>Time</th></tr>
+{{range .Tests}}<tr><td>{{.Name}}</td><td class="{{.Status}}">{{.Status}}</td><td>{{ms .Elapsed}}</td></tr>
+{{if eq .Status "fail"}}<tr><td colspan="3"><pre>{{.Output}}</pre></td></tr>
+{{end}}{{end}}</table>
+{{if and (eq .Status "fail") .Output}}<pre>{{.Output}}</pre>{{end}}
+{{end}}
+
+{{if .Trends}}
+<h2>Latency trend (p95 of each recorded run)</h2>
+{{range .Trends}}
+<h3>{{.Name}}: latest {{ms .Latest}}, worst {{ms .Worst}} over {{.Runs}} runs</h3>
+<svg width="{{$.ChartWidth}}" height="{{$.ChartHeight}}" viewBox="0 0 {{$.ChartWidth}} {{$.ChartHeight}}">
+<polyline fill="none" stroke="#0969da" stroke-width="2" points="{{.Points}}"/>
+</svg>
+{{end}}
+{{end}}
+
+{{if .Loads}}
+<h2>Load runs</h2>
+{{range .Loads}}
+<h3>{{.Name}}</h3>
+<table>
+<tr><th>Sent</th><th>Succeeded</th><th>Success rate</th><th>Achieved TPS</th><th>Elapsed</th></tr>
+<tr><td>{{.Report.Sent}}</td><td>{{.Report.Succeeded}}</td><td>{{pct .Report.SuccessRate}}</td><td>{{printf "%.1f" .Report.AchievedTPS}}</td><td>{{ms .Report.Elapsed}}</td></tr>
+</table>
+<svg width="{{$.ChartWidth}}" height="{{.Height}}" viewBox="0 0 {{$.ChartWidth}} {{.Height}}">
+{{range .Bars}}<text x="0" y="{{.Y}}" dy="16">{{.Label}}</text>
+<rect x="40" y="{{.Y}}" width="{{.Width}}" height="22" fill="#0969da"/>
+<text x="{{.Width}}" y="{{.Y}}" dx="48" dy="16">{{ms .Value}}</text>
+{{end}}</svg>
+{{if .ErrorClass}}<table><tr><th>Error class</th><th>Count</th></tr>
+{{$errors := .Report.Errors}}{{range .ErrorClass}}<tr><td>{{.}}</td><td>{{index $errors .}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}
+{{end}}
+
+{{if .Samples}}
+<h2>Sample requests and responses</h2>
+<table>
+<tr><th>Method</th><th>Latency</th><th>Request</th><th>Response</th></tr>
+{{range .Samples}}<tr><td>{{.Method}}</td><td>{{ms .Latency}}</td><td><pre>{{printf "%s" .Request}}</pre></td><td>{{if .Error}}<pre class="fail">{{.Error}}</pre>{{else}}<pre>{{printf "%s" .Response}}</pre>{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,110 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Cases     []junitCase `xml:"testcase"`
+	SystemOut string      `xml:"system-out,omitempty"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Skipped   *junitMessage `xml:"skipped"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes packages as JUnit XML, one testsuite per package.
+// A package that failed outside its tests, e.g. to build, gets a failing
+// testcase of its own so CI does not read it as empty and green.
+func WriteJUnit(w io.Writer, packages []Package) error {
+	var doc junitSuites
+	for _, pkg := range packages {
+		suite := junitSuite{
+			Name:     pkg.Name,
+			Failures: pkg.Count(Fail),
+			Skipped:  pkg.Count(Skip),
+			Time:     fmt.Sprintf("%.3f", pkg.Elapsed.Seconds()),
+		}
+		for _, t := range pkg.Tests {
+			c := junitCase{
+				ClassName: pkg.Name,
+				Name:      t.Name,
+				Time:      fmt.Sprintf("%.3f", t.Elapsed.Seconds()),
+			}
+			switch t.Status {
+			case Fail:
+				c.Failure = &junitMessage{Message: "failed", Body: t.Output}
+			case Skip:
+				c.Skipped = &junitMessage{Message: skipReason(t.Output)}
+			default:
+				c.SystemOut = t.Output
+			}
+			suite.Cases = append(suite.Cases, c)
+		}
+		if pkg.Status == Fail && suite.Failures == 0 {
+			suite.Failures = 1
+			suite.Cases = append(suite.Cases, junitCase{
+				ClassName: pkg.Name,
+				Name:      "package",
+				Failure:   &junitMessage{Message: "package failed", Body: pkg.Output},
+			})
+		} else {
+			suite.SystemOut = pkg.Output
+		}
+		suite.Tests = len(suite.Cases)
+
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Skipped += suite.Skipped
+		doc.Suites = append(doc.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// skipReason is the last line a skipped test logged, usually its t.Skip
+// message.
+func skipReason(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" && !strings.HasPrefix(line, "--- SKIP") {
+			return line
+		}
+	}
+	return "skipped"
+}
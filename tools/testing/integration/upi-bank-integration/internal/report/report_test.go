@@ -0,0 +1,185 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+	"github.com/suuupra/upi-bank-integration-tests/internal/loadgen"
+	"github.com/suuupra/upi-bank-integration-tests/internal/perfbaseline"
+)
+
+const goTestJSON = `{"Action":"start","Package":"example/suite"}
+{"Action":"run","Package":"example/suite","Test":"TestOK"}
+{"Action":"output","Package":"example/suite","Test":"TestOK","Output":"=== RUN   TestOK\n"}
+{"Action":"pass","Package":"example/suite","Test":"TestOK","Elapsed":0.25}
+{"Action":"run","Package":"example/suite","Test":"TestBroken"}
+{"Action":"run","Package":"example/suite","Test":"TestBroken/sub"}
+{"Action":"output","Package":"example/suite","Test":"TestBroken/sub","Output":"    suite_test.go:10: want 1, got 2\n"}
+{"Action":"fail","Package":"example/suite","Test":"TestBroken/sub","Elapsed":0.1}
+{"Action":"fail","Package":"example/suite","Test":"TestBroken","Elapsed":0.1}
+{"Action":"run","Package":"example/suite","Test":"TestNeedsPayments"}
+{"Action":"output","Package":"example/suite","Test":"TestNeedsPayments","Output":"    idempotency_test.go:104: needs the payments gateway; pass -payments-url\n"}
+{"Action":"output","Package":"example/suite","Test":"TestNeedsPayments","Output":"--- SKIP: TestNeedsPayments (0.00s)\n"}
+{"Action":"skip","Package":"example/suite","Test":"TestNeedsPayments"}
+{"Action":"run","Package":"example/suite","Test":"TestHangs"}
+{"Action":"output","Package":"example/suite","Output":"panic: test timed out after 30m0s\n"}
+{"Action":"fail","Package":"example/suite","Elapsed":1800}
+# example/broken
+broken.go:3:1: syntax error
+{"Action":"output","Package":"example/broken","Output":"FAIL\texample/broken [build failed]\n"}
+{"Action":"fail","Package":"example/broken"}
+`
+
+func TestParseGoTestJSON(t *testing.T) {
+	packages, err := ParseGoTestJSON(strings.NewReader(goTestJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 2 {
+		t.Fatalf("got %d packages, want 2", len(packages))
+	}
+	suite := packages[0]
+	if suite.Status != Fail || suite.Elapsed != 30*time.Minute {
+		t.Errorf("package = %s in %v", suite.Status, suite.Elapsed)
+	}
+	want := []struct {
+		name   string
+		status Status
+	}{
+		{"TestOK", Pass},
+		{"TestBroken", Fail},
+		{"TestBroken/sub", Fail},
+		{"TestNeedsPayments", Skip},
+		{"TestHangs", Fail},
+	}
+	if len(suite.Tests) != len(want) {
+		t.Fatalf("tests = %+v", suite.Tests)
+	}
+	for i, w := range want {
+		if got := suite.Tests[i]; got.Name != w.name || got.Status != w.status {
+			t.Errorf("test %d = %s %s, want %s %s", i, got.Name, got.Status, w.name, w.status)
+		}
+	}
+	if !strings.Contains(suite.Tests[2].Output, "want 1, got 2") {
+		t.Errorf("subtest output = %q", suite.Tests[2].Output)
+	}
+	if suite.Tests[0].Elapsed != 250*time.Millisecond {
+		t.Errorf("elapsed = %v", suite.Tests[0].Elapsed)
+	}
+	if suite.Count(Fail) != 3 || suite.Count(Pass) != 1 || suite.Count(Skip) != 1 {
+		t.Errorf("counts = %d failed, %d passed, %d skipped", suite.Count(Fail), suite.Count(Pass), suite.Count(Skip))
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	packages, err := ParseGoTestJSON(strings.NewReader(goTestJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, packages); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 6 || doc.Failures != 4 || doc.Skipped != 1 {
+		t.Errorf("totals = %d tests, %d failures, %d skipped", doc.Tests, doc.Failures, doc.Skipped)
+	}
+	skipped := doc.Suites[0].Cases[3]
+	if skipped.Skipped == nil || !strings.Contains(skipped.Skipped.Message, "pass -payments-url") {
+		t.Errorf("skipped case = %+v", skipped)
+	}
+	// The package that failed to build is a failure, not an empty suite
+	broken := doc.Suites[1]
+	if broken.Failures != 1 || len(broken.Cases) != 1 || broken.Cases[0].Failure == nil {
+		t.Errorf("build failure = %+v", broken)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder(2)
+	intercept := rec.UnaryClientInterceptor()
+	fail := errors.New("rpc error: code = Unavailable")
+	for i := 0; i < 5; i++ {
+		for _, err := range []error{nil, fail} {
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				reply.(*upicore.ResolveVPAResponse).Exists = true
+				return err
+			}
+			intercept(context.Background(), "/upi.UpiCore/ResolveVPA", &upicore.ResolveVPARequest{Vpa: "alice@hdfc"}, &upicore.ResolveVPAResponse{}, nil, invoker)
+		}
+	}
+
+	got := rec.Samples()
+	if len(got) != 4 {
+		t.Fatalf("kept %d samples, want 2 successes and 2 failures", len(got))
+	}
+	if string(got[0].Request) == "" || !strings.Contains(string(got[0].Response), `"exists"`) {
+		t.Errorf("success sample = %+v", got[0])
+	}
+	if got[1].Error != fail.Error() || got[1].Response != nil {
+		t.Errorf("failure sample = %+v", got[1])
+	}
+
+	path := filepath.Join(t.TempDir(), "samples.jsonl")
+	if err := WriteSamples(path, got); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadSamples(path)
+	if err != nil || len(read) != 4 || read[0].Method != "/upi.UpiCore/ResolveVPA" {
+		t.Fatalf("ReadSamples = %+v, %v", read, err)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	packages, err := ParseGoTestJSON(strings.NewReader(goTestJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var perf []perfbaseline.Run
+	for _, p95 := range []time.Duration{10, 12, 11} {
+		perf = append(perf, perfbaseline.Run{Results: map[string]perfbaseline.Result{"vpa_resolution": {P95: p95 * time.Millisecond}}})
+	}
+	summary := Summary{
+		Title:    "Release <check>",
+		Packages: packages,
+		Perf:     perf,
+		Load: []LoadRun{{Name: "ramp", Report: loadgen.Report{
+			Sent: 100, Succeeded: 98, SuccessRate: 0.98,
+			P50: 5 * time.Millisecond, P90: 9 * time.Millisecond, P99: 20 * time.Millisecond, Max: 40 * time.Millisecond,
+			Errors: map[string]int{"DeadlineExceeded": 2},
+		}}},
+		Samples: []Sample{{Method: "/upi.UpiCore/ResolveVPA", Request: []byte(`{"vpa":"<script>"}`), Response: []byte(`{"exists":true}`)}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, summary); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	for _, want := range []string{
+		"Release &lt;check&gt;",
+		"1 passed", "3 failed", "1 skipped",
+		"want 1, got 2",
+		"vpa_resolution: latest 11ms, worst 12ms over 3 runs",
+		"<polyline",
+		"DeadlineExceeded",
+		"&lt;script&gt;",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+}
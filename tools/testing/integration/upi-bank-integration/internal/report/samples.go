@@ -0,0 +1,134 @@
+package report
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Sample is one captured gRPC exchange.
+type Sample struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Latency  time.Duration   `json:"latency"`
+}
+
+// Recorder keeps the first few successful and the first few failed calls of
+// each method, enough to show what the traffic looked like without storing
+// a whole run.
+type Recorder struct {
+	perMethod int
+
+	mu      sync.Mutex
+	counts  map[string]int
+	samples []Sample
+}
+
+// NewRecorder returns a Recorder keeping perMethod successes and perMethod
+// failures of each method.
+func NewRecorder(perMethod int) *Recorder {
+	return &Recorder{perMethod: perMethod, counts: make(map[string]int)}
+}
+
+// UnaryClientInterceptor records the calls made through a client
+// connection.
+func (r *Recorder) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		r.record(method, req, reply, err, time.Since(start))
+		return err
+	}
+}
+
+func (r *Recorder) record(method string, req, reply interface{}, err error, latency time.Duration) {
+	key := method + " ok"
+	if err != nil {
+		key = method + " error"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts[key] >= r.perMethod {
+		return
+	}
+	r.counts[key]++
+
+	sample := Sample{Method: method, Request: marshal(req), Latency: latency}
+	if err != nil {
+		sample.Error = err.Error()
+	} else {
+		sample.Response = marshal(reply)
+	}
+	r.samples = append(r.samples, sample)
+}
+
+// Samples returns the calls recorded so far, in the order they finished.
+func (r *Recorder) Samples() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Sample(nil), r.samples...)
+}
+
+func marshal(v interface{}) json.RawMessage {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil
+	}
+	raw, err := protojson.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+// WriteSamples writes samples to path, one JSON object per line.
+func WriteSamples(path string, samples []Sample) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, s := range samples {
+		if err := enc.Encode(s); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// ReadSamples reads samples written by WriteSamples. A missing file has
+// none.
+func ReadSamples(path string) ([]Sample, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var s Sample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, scanner.Err()
+}
@@ -0,0 +1,130 @@
+// Package testenv brings up the services the suite runs against with Docker
+// Compose, finds the host ports they were published on, and tears them down
+// again.
+//
+// Every port in the compose file is published on a random host port and each
+// run uses its own compose project, so concurrent runs on one machine do not
+// collide and nothing depends on fixed ports being free.
+package testenv
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options configures Up.
+type Options struct {
+	// ComposeFile is the compose file describing the environment.
+	ComposeFile string
+	// Project is the compose project name. Empty picks a unique one.
+	Project string
+	// Docker is the docker CLI to run. Empty means "docker".
+	Docker string
+}
+
+// Env is a running compose project.
+type Env struct {
+	docker  string
+	file    string
+	project string
+}
+
+// Up builds and starts every service in the compose file and waits until
+// those with a healthcheck report healthy. If that fails, the partial
+// environment is torn down and the error includes the services' recent logs.
+func Up(ctx context.Context, opts Options) (*Env, error) {
+	e := &Env{docker: opts.Docker, file: opts.ComposeFile, project: opts.Project}
+	if e.docker == "" {
+		e.docker = "docker"
+	}
+	if e.project == "" {
+		e.project = "upi-it-" + randomSuffix()
+	}
+	if _, err := e.compose(ctx, "up", "--detach", "--build", "--wait"); err != nil {
+		logs := e.Logs(context.Background())
+		e.Down(context.Background())
+		return nil, fmt.Errorf("starting %s: %v\n%s", e.project, err, logs)
+	}
+	return e, nil
+}
+
+// Project returns the compose project name.
+func (e *Env) Project() string {
+	return e.project
+}
+
+// Addr returns the host address a service's container port is published on.
+func (e *Env) Addr(ctx context.Context, service string, port int) (string, error) {
+	out, err := e.compose(ctx, "port", service, strconv.Itoa(port))
+	if err != nil {
+		return "", err
+	}
+	// Printed as 0.0.0.0:49153, or [::]:49153
+	host, hostPort, err := net.SplitHostPort(strings.TrimSpace(out))
+	if err != nil {
+		return "", fmt.Errorf("%s port %d: unexpected output %q", service, port, out)
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, hostPort), nil
+}
+
+// Logs returns the last lines each service logged, for failure reports.
+func (e *Env) Logs(ctx context.Context, services ...string) string {
+	out, err := e.compose(ctx, append([]string{"logs", "--no-color", "--tail", "50"}, services...)...)
+	if err != nil {
+		return fmt.Sprintf("(no logs: %v)", err)
+	}
+	return out
+}
+
+// Down stops the environment and removes its containers and volumes.
+func (e *Env) Down(ctx context.Context) error {
+	_, err := e.compose(ctx, "down", "--volumes", "--remove-orphans")
+	return err
+}
+
+func (e *Env) compose(ctx context.Context, args ...string) (string, error) {
+	args = append([]string{"compose", "--file", e.file, "--project-name", e.project}, args...)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.docker, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// WaitFor calls probe every interval until it succeeds or ctx is done, and
+// then returns the last probe error.
+func WaitFor(ctx context.Context, interval time.Duration, probe func(context.Context) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := probe(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%v (last error: %v)", ctx.Err(), err)
+		case <-ticker.C:
+		}
+	}
+}
+
+func randomSuffix() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,117 @@
+package testenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDocker writes a docker CLI stand-in that records each invocation and
+// runs script for it, with the arguments in "$*".
+func fakeDocker(t *testing.T, script string) (docker string, calls func() []string) {
+	t.Helper()
+	dir := t.TempDir()
+	log := filepath.Join(dir, "calls")
+	docker = filepath.Join(dir, "docker")
+	body := "#!/bin/sh\necho \"$*\" >> " + log + "\n" + script + "\n"
+	if err := os.WriteFile(docker, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return docker, func() []string {
+		raw, _ := os.ReadFile(log)
+		return strings.Split(strings.TrimSpace(string(raw)), "\n")
+	}
+}
+
+func TestUpAddrDown(t *testing.T) {
+	docker, calls := fakeDocker(t, `
+case "$*" in
+  *"port upi-core 50052"*) echo "0.0.0.0:49153" ;;
+  *"port redis 6379"*) echo "[::]:49154" ;;
+esac`)
+	ctx := context.Background()
+
+	env, err := Up(ctx, Options{ComposeFile: "env.yml", Docker: docker})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(env.Project(), "upi-it-") {
+		t.Fatalf("project = %q", env.Project())
+	}
+	for _, tc := range []struct {
+		service string
+		port    int
+		want    string
+	}{
+		{"upi-core", 50052, "127.0.0.1:49153"},
+		{"redis", 6379, "127.0.0.1:49154"},
+	} {
+		addr, err := env.Addr(ctx, tc.service, tc.port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != tc.want {
+			t.Errorf("Addr(%s, %d) = %q, want %q", tc.service, tc.port, addr, tc.want)
+		}
+	}
+	if err := env.Down(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := "compose --file env.yml --project-name " + env.Project() + " "
+	want := []string{
+		prefix + "up --detach --build --wait",
+		prefix + "port upi-core 50052",
+		prefix + "port redis 6379",
+		prefix + "down --volumes --remove-orphans",
+	}
+	if got := calls(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("docker calls:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestUpFailureTearsDown(t *testing.T) {
+	docker, calls := fakeDocker(t, `
+case "$*" in
+  *" up "*) echo "bank-simulator is unhealthy" >&2; exit 1 ;;
+  *" logs "*) echo "bank-simulator | migration failed" ;;
+esac`)
+
+	_, err := Up(context.Background(), Options{ComposeFile: "env.yml", Project: "p", Docker: docker})
+	if err == nil {
+		t.Fatal("Up succeeded")
+	}
+	for _, want := range []string{"bank-simulator is unhealthy", "migration failed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	got := calls()
+	if last := got[len(got)-1]; !strings.Contains(last, " down ") {
+		t.Fatalf("last docker call = %q, want down", last)
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	attempts := 0
+	err := WaitFor(context.Background(), time.Millisecond, func(context.Context) error {
+		if attempts++; attempts < 3 {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("WaitFor = %v after %d attempts", err, attempts)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = WaitFor(ctx, time.Millisecond, func(context.Context) error { return errors.New("still starting") })
+	if err == nil || !strings.Contains(err.Error(), "still starting") {
+		t.Fatalf("WaitFor after deadline = %v", err)
+	}
+}
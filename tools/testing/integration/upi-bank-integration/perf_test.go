@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/upi-bank-integration-tests/internal/perfbaseline"
+)
+
+var (
+	perfResults   = flag.String("perf-results", "", "file performance results are recorded in and compared with; empty only logs them")
+	perfThreshold = flag.Float64("perf-threshold", 0.2, "fail when a p95 exceeds the rolling baseline by more than this fraction")
+	perfWindow    = flag.Int("perf-window", 10, "number of recent runs the baseline is the median of")
+)
+
+// recordPerformance logs the latencies of this run and, with -perf-results,
+// fails the test on any p95 regression against the rolling baseline before
+// appending the run to the store. Runs that already failed are not
+// recorded: their samples are incomplete.
+func (suite *IntegrationTestSuite) recordPerformance(t *testing.T, latencies map[string][]time.Duration) {
+	t.Helper()
+	run := perfbaseline.Run{ID: suite.runID, Time: time.Now().UTC(), Results: make(map[string]perfbaseline.Result)}
+	for name, samples := range latencies {
+		result := perfbaseline.Summarize(samples)
+		run.Results[name] = result
+		t.Logf("%s: %d samples, p50 %v, p95 %v, max %v", name, result.Samples, result.P50, result.P95, result.Max)
+	}
+
+	// Fakes answer in microseconds, which would drag any baseline down
+	if *perfResults == "" || *offline || t.Failed() {
+		return
+	}
+	store := perfbaseline.Store{Path: *perfResults}
+	history, err := store.Load()
+	require.NoError(t, err)
+
+	policy := perfbaseline.Policy{Threshold: *perfThreshold, Window: *perfWindow}
+	regressions := policy.Regressions(run, history)
+	run.Regressed = len(regressions) > 0
+	require.NoError(t, store.Append(run))
+	for _, regression := range regressions {
+		t.Error(regression)
+	}
+}
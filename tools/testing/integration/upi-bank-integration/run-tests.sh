@@ -13,13 +13,17 @@ BLUE='\033[0;34m'
 CYAN='\033[0;36m'
 NC='\033[0m' # No Color
 
-# Configuration
-COMPOSE_FILE="../../../../docker-compose.integration.yml"
-TEST_DIR="$(pwd)"
-BANK_SIMULATOR_GRPC="localhost:50050"
-UPI_CORE_GRPC="localhost:50052"
-MAX_WAIT_TIME=120 # 2 minutes
-WAIT_INTERVAL=5
+# Configuration. The suite starts environment/docker-compose.yml itself
+# unless both addresses are set, in which case it uses those services.
+BANK_SIMULATOR_GRPC="${BANK_SIMULATOR_GRPC:-}"
+UPI_CORE_GRPC="${UPI_CORE_GRPC:-}"
+PAYMENTS_URL="${PAYMENTS_URL:-}"
+MASS_LIVE_URL="${MASS_LIVE_URL:-}"
+# Performance results accumulate here; the baseline is computed from them
+PERF_RESULTS="${PERF_RESULTS:-perf-results.jsonl}"
+# Comma-separated cmd/loadgen -json reports to include in the HTML summary
+LOAD_REPORTS="${LOAD_REPORTS:-}"
+GO_TEST_FLAGS=()
 
 # Function to print colored output
 print_status() {
@@ -44,39 +48,16 @@ print_header() {
     echo -e "${CYAN}================================${NC}"
 }
 
-# Function to check if services are ready
-wait_for_service() {
-    local service_name=$1
-    local grpc_endpoint=$2
-    local waited=0
-
-    print_status "Waiting for $service_name to be ready..."
-    
-    while [ $waited -lt $MAX_WAIT_TIME ]; do
-        if nc -z $(echo $grpc_endpoint | tr ':' ' ') 2>/dev/null; then
-            print_success "$service_name is ready!"
-            return 0
-        fi
-        
-        echo -n "."
-        sleep $WAIT_INTERVAL
-        waited=$((waited + WAIT_INTERVAL))
-    done
-    
-    print_error "$service_name failed to become ready within $MAX_WAIT_TIME seconds"
-    return 1
-}
-
-# Function to run specific test
-run_test_function() {
-    local test_name=$1
-    print_status "Running: $test_name"
+# Function to run tests matching a pattern, in one environment
+run_tests() {
+    local pattern=$1
+    print_status "Running: $pattern"
     
-    if go test -v -run "$test_name" -timeout 10m; then
-        print_success "✓ $test_name passed"
+    if go test -v -run "$pattern" -timeout 30m "${GO_TEST_FLAGS[@]}"; then
+        print_success "✓ $pattern passed"
         return 0
     else
-        print_error "✗ $test_name failed"
+        print_error "✗ $pattern failed"
         return 1
     fi
 }
@@ -86,10 +67,8 @@ run_benchmarks() {
     print_header "Running Performance Benchmarks"
     
     print_status "Running VPA resolution benchmarks..."
-    go test -bench=BenchmarkVPAResolution -benchmem -timeout 5m
-    
+    go test -run '^$' -bench=. -benchmem -timeout 30m "${GO_TEST_FLAGS[@]}" > benchmark_results.txt 2>&1
     print_status "Benchmark results saved to benchmark_results.txt"
-    go test -bench=. -benchmem -timeout 5m > benchmark_results.txt 2>&1
 }
 
 # Function to generate test report
@@ -105,14 +84,14 @@ generate_test_report() {
         echo ""
         
         echo "Test Environment:"
-        echo "- Bank Simulator gRPC: $BANK_SIMULATOR_GRPC"
-        echo "- UPI Core gRPC: $UPI_CORE_GRPC"
+        echo "- Bank Simulator gRPC: ${BANK_SIMULATOR_GRPC:-started by the suite}"
+        echo "- UPI Core gRPC: ${UPI_CORE_GRPC:-started by the suite}"
         echo "- Go Version: $(go version)"
         echo ""
         
         echo "Test Results:"
         echo "============="
-        go test -v -json 2>&1 | tee test_results.json
+        go test -v -json "${GO_TEST_FLAGS[@]}" -capture-samples samples.jsonl 2>&1 | tee test_results.json
         
         echo ""
         echo "Performance Benchmarks:"
@@ -123,63 +102,24 @@ generate_test_report() {
             echo "No benchmark results available"
         fi
         
-        echo ""
-        echo "Service Health Status:"
-        echo "====================="
-        echo "Bank Simulator: $(nc -z $(echo $BANK_SIMULATOR_GRPC | tr ':' ' ') && echo 'UP' || echo 'DOWN')"
-        echo "UPI Core: $(nc -z $(echo $UPI_CORE_GRPC | tr ':' ' ') && echo 'UP' || echo 'DOWN')"
         
     } > "$report_file"
     
     print_success "Test report generated: $report_file"
-}
-
-# Function to cleanup
-cleanup() {
-    print_status "Cleaning up test environment..."
-    cd $(dirname "$COMPOSE_FILE")
-    docker-compose -f $(basename "$COMPOSE_FILE") down -v > /dev/null 2>&1 || true
-    cd "$TEST_DIR"
-    print_success "Cleanup completed"
-}
-
-# Function to start services
-start_services() {
-    print_header "Starting Test Environment"
-    
-    cd $(dirname "$COMPOSE_FILE")
     
-    print_status "Stopping any existing services..."
-    docker-compose -f $(basename "$COMPOSE_FILE") down -v > /dev/null 2>&1 || true
-    
-    print_status "Starting services with Docker Compose..."
-    if ! docker-compose -f $(basename "$COMPOSE_FILE") up -d; then
-        print_error "Failed to start services"
-        return 1
+    # JUnit XML and the HTML summary, for CI and release sign-off
+    local report_flags=(-go-test-json test_results.json -samples samples.jsonl -out reports)
+    if [ -n "$PERF_RESULTS" ]; then
+        report_flags+=(-perf-results "$PERF_RESULTS")
     fi
-    
-    cd "$TEST_DIR"
-    
-    # Wait for dependencies first
-    print_status "Waiting for infrastructure services..."
-    sleep 10  # Give PostgreSQL time to start
-    sleep 5   # Give Redis time to start
-    sleep 15  # Give Kafka time to start
-    
-    # Wait for application services
-    if ! wait_for_service "Bank Simulator" "$BANK_SIMULATOR_GRPC"; then
-        return 1
+    if [ -n "$LOAD_REPORTS" ]; then
+        report_flags+=(-load "$LOAD_REPORTS")
     fi
-    
-    if ! wait_for_service "UPI Core" "$UPI_CORE_GRPC"; then
-        return 1
+    if go run ./cmd/report "${report_flags[@]}"; then
+        print_success "JUnit and HTML reports written to reports/"
+    else
+        print_error "Failed to generate JUnit and HTML reports"
     fi
-    
-    # Additional wait for services to fully initialize
-    print_status "Allowing services to fully initialize..."
-    sleep 10
-    
-    print_success "All services are ready!"
 }
 
 # Main execution function
@@ -203,12 +143,19 @@ main() {
                 specific_test="$2"
                 shift 2
                 ;;
+            -k|--keep)
+                GO_TEST_FLAGS+=("-keep-env")
+                shift
+                ;;
             -h|--help)
                 echo "Usage: $0 [OPTIONS]"
                 echo "Options:"
                 echo "  -b, --benchmark    Run performance benchmarks"
                 echo "  -r, --report       Generate detailed test report"
                 echo "  -t, --test TEST    Run specific test function"
+                echo "  -k, --keep         Leave the started environment running"
+                echo ""
+                echo "Set BANK_SIMULATOR_GRPC and UPI_CORE_GRPC to test services that are already running."
                 echo "  -h, --help         Show this help message"
                 exit 0
                 ;;
@@ -227,14 +174,26 @@ main() {
         exit 1
     fi
     
-    if ! command -v docker-compose &> /dev/null; then
-        print_error "Docker Compose is not installed"
+    if [ -n "$BANK_SIMULATOR_GRPC" ] && [ -n "$UPI_CORE_GRPC" ]; then
+        GO_TEST_FLAGS+=("-bank-sim-addr" "$BANK_SIMULATOR_GRPC" "-upi-core-addr" "$UPI_CORE_GRPC")
+    elif ! docker compose version &> /dev/null; then
+        print_error "Docker Compose v2 is not installed"
         exit 1
     fi
-    
-    if ! command -v nc &> /dev/null; then
-        print_error "netcat (nc) is not installed"
-        exit 1
+    if [ -n "$PAYMENTS_URL" ]; then
+        GO_TEST_FLAGS+=("-payments-url" "$PAYMENTS_URL")
+    fi
+    if [ -n "$PAYMENTS_JWT_SECRET" ]; then
+        GO_TEST_FLAGS+=("-payments-jwt-secret" "$PAYMENTS_JWT_SECRET")
+    fi
+    if [ -n "$MASS_LIVE_URL" ]; then
+        GO_TEST_FLAGS+=("-mass-live-url" "$MASS_LIVE_URL")
+    fi
+    if [ -n "$MASS_LIVE_JWT_SECRET" ]; then
+        GO_TEST_FLAGS+=("-mass-live-jwt-secret" "$MASS_LIVE_JWT_SECRET")
+    fi
+    if [ -n "$PERF_RESULTS" ]; then
+        GO_TEST_FLAGS+=("-perf-results" "$PERF_RESULTS")
     fi
     
     # Ensure go mod is initialized
@@ -247,16 +206,6 @@ main() {
     print_status "Downloading Go dependencies..."
     go mod tidy
     
-    # Start services
-    if ! start_services; then
-        print_error "Failed to start services"
-        cleanup
-        exit 1
-    fi
-    
-    # Set trap for cleanup
-    trap cleanup EXIT
-    
     # Run tests
     print_header "Running Integration Tests"
     
@@ -264,11 +213,11 @@ main() {
     
     if [ -n "$specific_test" ]; then
         print_status "Running specific test: $specific_test"
-        if ! run_test_function "$specific_test"; then
+        if ! run_tests "$specific_test"; then
             test_failed=true
         fi
     else
-        # Run all test categories
+        # Run all test categories against one environment
         local test_categories=(
             "TestServiceHealthChecks"
             "TestAccountCreationFlow"
@@ -276,14 +225,17 @@ main() {
             "TestTransactionProcessingFlow"
             "TestErrorHandlingAndEdgeCases"
             "TestPerformanceBaseline"
+            "TestIdempotency.*"
+            "TestEndToEndSettlement"
+            "TestCrossBankTransactionMatrix"
+            "TestAuthorizationEnforced"
         )
         
-        for test in "${test_categories[@]}"; do
-            if ! run_test_function "$test"; then
-                test_failed=true
-            fi
-            echo ""
-        done
+        local pattern
+        pattern="^($(IFS='|'; echo "${test_categories[*]}"))$"
+        if ! run_tests "$pattern"; then
+            test_failed=true
+        fi
     fi
     
     # Run benchmarks if requested
@@ -301,35 +253,9 @@ main() {
     
     if [ "$test_failed" = true ]; then
         print_error "Some tests failed. Check the output above for details."
-        
-        # Show service logs for debugging
-        print_status "Showing recent service logs for debugging..."
-        cd $(dirname "$COMPOSE_FILE")
-        echo "Bank Simulator logs:"
-        docker-compose -f $(basename "$COMPOSE_FILE") logs --tail=20 bank-simulator
-        echo ""
-        echo "UPI Core logs:"
-        docker-compose -f $(basename "$COMPOSE_FILE") logs --tail=20 upi-core
-        cd "$TEST_DIR"
-        
         exit 1
     else
         print_success "🎉 All tests passed successfully!"
-        
-        echo ""
-        print_status "Service Status:"
-        echo "  • Bank Simulator gRPC: $BANK_SIMULATOR_GRPC"
-        echo "  • UPI Core gRPC: $UPI_CORE_GRPC"
-        echo ""
-        
-        # Prompt to keep services running
-        read -p "Keep services running for manual testing? (y/N): " -n 1 -r
-        echo
-        if [[ $REPLY =~ ^[Yy]$ ]]; then
-            print_status "Services are running. Use Ctrl+C to stop them."
-            trap - EXIT  # Remove the cleanup trap
-            wait
-        fi
     fi
 }
 
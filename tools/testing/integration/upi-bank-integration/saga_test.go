@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+)
+
+// Bounds of UPI Core's saga under the environment's settings.
+const (
+	// UPICoreBankTimeout is how long UPI Core waits on one bank call, its
+	// banks.request_timeout.
+	UPICoreBankTimeout = 10 * time.Second
+	// RecoveryBound is how long a saga stuck compensating may take to be
+	// resumed once its banks recover: saga.stale_after and
+	// saga.scan_interval in the compose file, plus the reversal.
+	RecoveryBound = 30 * time.Second
+)
+
+// sagaCase is a payment from HDFC to SBI with faults that break it between
+// the debit and the credit, or on either leg alone.
+type sagaCase struct {
+	name   string
+	faults []*banksim.SetBankFaultRequest
+	// answerBound caps how long UPI Core may take to answer; zero is
+	// unbounded.
+	answerBound time.Duration
+	// stalls means the saga cannot finish until the faults are cleared: the
+	// payer stays debited and the transaction PENDING until then.
+	stalls bool
+	want   upicore.TransactionStatus
+}
+
+var sagaCases = []sagaCase{
+	{
+		name:   "credit declined",
+		faults: []*banksim.SetBankFaultRequest{{BankCode: "SBI", DeclineType: banksim.TransactionType_TRANSACTION_TYPE_CREDIT}},
+		want:   upicore.TransactionStatus_TRANSACTION_STATUS_REVERSED,
+	},
+	{
+		name:   "debit declined",
+		faults: []*banksim.SetBankFaultRequest{{BankCode: "HDFC", DeclineType: banksim.TransactionType_TRANSACTION_TYPE_DEBIT}},
+		want:   upicore.TransactionStatus_TRANSACTION_STATUS_FAILED,
+	},
+	{
+		// The payee bank drops the credit once UPI Core gives up on it, so
+		// the reversal must leave both balances where they started
+		name:        "credit timed out",
+		faults:      []*banksim.SetBankFaultRequest{{BankCode: "SBI", ExtraLatencyMs: int32((UPICoreBankTimeout + 2*time.Second) / time.Millisecond)}},
+		answerBound: UPICoreBankTimeout + 5*time.Second,
+		want:        upicore.TransactionStatus_TRANSACTION_STATUS_REVERSED,
+	},
+	{
+		// A reversal is a credit at the payer bank, so declining credits
+		// there too leaves the saga compensating until the bank recovers
+		name: "reversal declined",
+		faults: []*banksim.SetBankFaultRequest{
+			{BankCode: "SBI", DeclineType: banksim.TransactionType_TRANSACTION_TYPE_CREDIT},
+			{BankCode: "HDFC", DeclineType: banksim.TransactionType_TRANSACTION_TYPE_CREDIT},
+		},
+		stalls: true,
+		want:   upicore.TransactionStatus_TRANSACTION_STATUS_REVERSED,
+	},
+}
+
+// Payments broken partway by bank faults end fully applied or fully
+// undone: a failed credit or a credit that timed out reverses the debit,
+// and a reversal that failed is retried until it goes through. Faults are
+// global to the Bank Simulator, so cases run one at a time.
+func TestSagaCompensation(t *testing.T) {
+	if *offline {
+		t.Skip("the offline fakes do not run UPI Core's saga")
+	}
+	suite, err := setupTestSuite()
+	require.NoError(t, err)
+	defer suite.cleanup()
+
+	for _, tc := range sagaCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			payer := suite.CreateFundedParty(t, "HDFC", "PAYER", InitialDepositPaisa)
+			payee := suite.CreateFundedParty(t, "SBI", "PAYEE", InitialDepositPaisa)
+			suite.LinkVPAs(t, payer, payee)
+			payerBefore, payeeBefore := suite.balance(t, payer), suite.balance(t, payee)
+
+			var latency time.Duration
+			for _, fault := range tc.faults {
+				suite.injectBankFault(t, fault)
+				if d := time.Duration(fault.ExtraLatencyMs) * time.Millisecond; d > latency {
+					latency = d
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			start := time.Now()
+			resp, transactionID, err := suite.pay(ctx, payer, payee, TransactionAmount)
+			elapsed := time.Since(start)
+			if tc.answerBound > 0 {
+				assert.LessOrEqual(t, elapsed, tc.answerBound, "UPI Core took %v to answer", elapsed)
+			}
+			if err == nil {
+				assert.NotEqual(t, upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS, resp.Status,
+					"payment succeeded through the faults")
+			}
+
+			bound := SettleBound
+			if tc.stalls {
+				status, err := suite.upiCoreClient.GetTransactionStatus(ctx, &upicore.TransactionStatusRequest{TransactionId: transactionID})
+				require.NoError(t, err)
+				assert.Equal(t, upicore.TransactionStatus_TRANSACTION_STATUS_PENDING, status.Status,
+					"transaction finished with its saga stuck")
+				assert.Equal(t, payerBefore-TransactionAmount, suite.balance(t, payer), "payer not debited before the stall")
+				for _, fault := range tc.faults {
+					suite.clearBankFault(t, fault.BankCode)
+				}
+				bound = RecoveryBound
+			}
+
+			final := suite.settleWithin(t, transactionID, bound)
+			require.Equal(t, tc.want, final)
+			// Check balances only once any delayed leg would have landed
+			time.Sleep(time.Until(start.Add(latency + time.Second)))
+			t.Logf("%s: %s after %v", transactionID, final, elapsed)
+			suite.assertConserved(t, final, payer, payee, payerBefore, payeeBefore, TransactionAmount)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
+)
+
+// SettlementBanks are the banks the settlement scenario moves money
+// between. All are seeded by the Bank Simulator.
+var SettlementBanks = []string{"HDFC", "SBI", "ICICI"}
+
+// A batch of cross-bank payments is settled end to end: the settlement
+// completes, nets to zero across banks, and each bank's report matches the
+// payments made. The report window is this test's own runtime, so the
+// check assumes nothing else pays through these banks meanwhile.
+func TestEndToEndSettlement(t *testing.T) {
+	suite, err := setupTestSuite()
+	require.NoError(t, err)
+	defer suite.cleanup()
+
+	parties := make(map[string]*testParty)
+	for _, bank := range SettlementBanks {
+		parties[bank] = suite.CreateFundedParty(t, bank, "SETTLE", InitialDepositPaisa)
+		suite.LinkVPAs(t, parties[bank])
+	}
+
+	from := time.Now()
+	payments := []struct {
+		payer, payee string
+		amount       int64
+	}{
+		{"HDFC", "SBI", 50000},
+		{"SBI", "ICICI", 30000},
+		{"ICICI", "HDFC", 20000},
+		{"HDFC", "ICICI", 10000},
+		{"SBI", "HDFC", 5000},
+	}
+	want := make(map[string]*upicore.SettlementReportResponse)
+	for _, bank := range SettlementBanks {
+		want[bank] = &upicore.SettlementReportResponse{BankCode: bank}
+	}
+	for _, p := range payments {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		_, transactionID, err := suite.pay(ctx, parties[p.payer], parties[p.payee], p.amount)
+		cancel()
+		require.NoError(t, err, "%s -> %s", p.payer, p.payee)
+		require.Equal(t, upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS, suite.settle(t, transactionID), "%s -> %s", p.payer, p.payee)
+
+		want[p.payer].TotalDebitPaisa += p.amount
+		want[p.payer].TransactionCount++
+		want[p.payee].TotalCreditPaisa += p.amount
+		want[p.payee].TransactionCount++
+	}
+	for _, w := range want {
+		w.NetSettlementPaisa = w.TotalCreditPaisa - w.TotalDebitPaisa
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	initiated, err := suite.upiCoreClient.InitiateSettlement(ctx, &upicore.InitiateSettlementRequest{
+		BatchId:        suite.runID + "_" + uuid.New().String()[:8],
+		BankCodes:      SettlementBanks,
+		SettlementDate: timestamppb.Now(),
+	})
+	require.NoError(t, err)
+	require.True(t, initiated.Success, "settlement not initiated: %s %s", initiated.ErrorCode, initiated.ErrorMessage)
+	require.NotEmpty(t, initiated.SettlementId)
+
+	settlement := suite.awaitSettlement(t, initiated.SettlementId)
+	var net int64
+	settled := make(map[string]bool)
+	for _, bank := range settlement.BankSettlements {
+		settled[bank.BankCode] = true
+		net += bank.NetAmountPaisa
+	}
+	for _, bank := range SettlementBanks {
+		assert.True(t, settled[bank], "settlement has no entry for %s", bank)
+	}
+	assert.Zero(t, net, "settlement does not net to zero across banks")
+
+	to := time.Now()
+	for _, bank := range SettlementBanks {
+		report, err := suite.upiCoreClient.GetSettlementReport(ctx, &upicore.SettlementReportRequest{
+			BankCode: bank,
+			FromDate: timestamppb.New(from),
+			ToDate:   timestamppb.New(to),
+		})
+		require.NoError(t, err, bank)
+		assert.Equal(t, want[bank].TotalCreditPaisa, report.TotalCreditPaisa, "%s credits", bank)
+		assert.Equal(t, want[bank].TotalDebitPaisa, report.TotalDebitPaisa, "%s debits", bank)
+		assert.Equal(t, want[bank].NetSettlementPaisa, report.NetSettlementPaisa, "%s net", bank)
+		assert.Equal(t, want[bank].TransactionCount, report.TransactionCount, "%s transaction count", bank)
+	}
+}
+
+// awaitSettlement polls UPI Core until the settlement completes, failing if
+// it fails or takes longer than SettleBound.
+func (suite *IntegrationTestSuite) awaitSettlement(t *testing.T, settlementID string) *upicore.SettlementStatusResponse {
+	t.Helper()
+	deadline := time.Now().Add(SettleBound)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		resp, err := suite.upiCoreClient.GetSettlementStatus(ctx, &upicore.SettlementStatusRequest{SettlementId: settlementID})
+		cancel()
+		if err == nil {
+			switch resp.Status {
+			case upicore.SettlementStatus_SETTLEMENT_STATUS_COMPLETED:
+				return resp
+			case upicore.SettlementStatus_SETTLEMENT_STATUS_FAILED:
+				t.Fatalf("settlement %s failed", settlementID)
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("settlement %s did not complete within %v (last error %v)", settlementID, SettleBound, err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
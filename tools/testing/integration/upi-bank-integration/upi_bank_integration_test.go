@@ -1,4 +1,4 @@
-package main
+package integration
 
 import (
 	"context"
@@ -10,59 +10,59 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/suuupra/upi-bank-integration-tests/generated/banksim"
+	"github.com/suuupra/upi-bank-integration-tests/generated/upicore"
 )
 
 // Test configuration
 const (
-	BankSimulatorAddress = "localhost:50050"
-	UpiCoreAddress       = "localhost:50052"
-	TestBankCode         = "HDFC"
-	TestMobileNumber     = "+919876543210"
-	TestEmail            = "test@hdfc.com"
-	InitialDepositPaisa  = 100000 // 1000 INR
-	TransactionAmount    = 50000   // 500 INR
+	TestBankCode        = "HDFC"
+	TestMobileNumber    = "+919876543210"
+	TestEmail           = "test@hdfc.com"
+	InitialDepositPaisa = 100000 // 1000 INR
+	TransactionAmount   = 50000  // 500 INR
 )
 
 // Test suite structure
 type IntegrationTestSuite struct {
-	bankSimClient BankSimulatorClient
-	upiCoreClient UpiCoreClient
+	clients       *serviceClients
+	bankSimClient banksim.BankSimulatorClient
+	upiCoreClient upicore.UpiCoreClient
+	runID         string // Prefix of every customer ID and VPA this suite creates
 	testAccounts  []string
 	testVPAs      []string
 }
 
 // Setup test suite
 func setupTestSuite() (*IntegrationTestSuite, error) {
-	// Connect to Bank Simulator
-	bankConn, err := grpc.Dial(BankSimulatorAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Bank Simulator: %v", err)
-	}
-	bankSimClient := NewBankSimulatorClient(bankConn)
-
-	// Connect to UPI Core
-	upiConn, err := grpc.Dial(UpiCoreAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	clients, err := dialServices()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to UPI Core: %v", err)
+		return nil, err
 	}
-	upiCoreClient := NewUpiCoreClient(upiConn)
-
 	return &IntegrationTestSuite{
-		bankSimClient: bankSimClient,
-		upiCoreClient: upiCoreClient,
+		clients:       clients,
+		bankSimClient: clients.bankSim,
+		upiCoreClient: clients.upiCore,
+		runID:         newRunID(),
 		testAccounts:  make([]string, 0),
 		testVPAs:      make([]string, 0),
 	}, nil
 }
 
-// Cleanup test data
+// Cleanup test data, so repeated runs don't accumulate accounts in the
+// Bank Simulator. Needs ENABLE_TEST_DATA_CLEANUP=true there.
 func (suite *IntegrationTestSuite) cleanup() {
-	// Note: In a real implementation, we would have cleanup methods
-	// For now, we'll just log the cleanup
-	log.Printf("Cleaning up test data: %d accounts, %d VPAs", len(suite.testAccounts), len(suite.testVPAs))
+	resp, err := suite.purgeTestData()
+	if err != nil {
+		log.Printf("Failed to clean up run %s (%d accounts, %d VPAs): %v", suite.runID, len(suite.testAccounts), len(suite.testVPAs), err)
+	} else {
+		log.Printf("Cleaned up run %s: %d accounts, %d VPAs, %d transactions", suite.runID, resp.AccountsDeleted, resp.VpasDeleted, resp.TransactionsDeleted)
+	}
+	suite.clients.Close()
 }
 
 // Test 1: Service Health Checks
@@ -75,7 +75,7 @@ func TestServiceHealthChecks(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		req := &BankHealthRequest{
+		req := &banksim.BankHealthRequest{
 			BankCode: TestBankCode,
 		}
 
@@ -83,9 +83,9 @@ func TestServiceHealthChecks(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, resp)
 		assert.Equal(t, TestBankCode, resp.BankCode)
-		assert.Equal(t, HealthStatus_HEALTH_STATUS_HEALTHY, resp.HealthStatus)
-		
-		t.Logf("Bank Health Status: %v, Success Rate: %d%%, Avg Response Time: %dms", 
+		assert.Equal(t, banksim.HealthStatus_HEALTH_STATUS_HEALTHY, resp.HealthStatus)
+
+		t.Logf("Bank Health Status: %v, Success Rate: %d%%, Avg Response Time: %dms",
 			resp.HealthStatus, resp.SuccessRatePercent, resp.AvgResponseTimeMs)
 	})
 
@@ -93,15 +93,15 @@ func TestServiceHealthChecks(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		req := &HealthCheckRequest{
+		req := &upicore.HealthCheckRequest{
 			Service: "upi-core",
 		}
 
 		resp, err := suite.upiCoreClient.HealthCheck(ctx, req)
 		require.NoError(t, err)
 		assert.NotNil(t, resp)
-		assert.Equal(t, HealthStatus_HEALTH_STATUS_SERVING, resp.Status)
-		
+		assert.Equal(t, upicore.HealthStatus_HEALTH_STATUS_SERVING, resp.Status)
+
 		t.Logf("UPI Core Health Status: %v", resp.Status)
 	})
 }
@@ -116,20 +116,20 @@ func TestAccountCreationFlow(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		customerID := fmt.Sprintf("CUST_%s", uuid.New().String()[:8])
-		
-		req := &CreateAccountRequest{
-			BankCode:    TestBankCode,
-			CustomerId:  customerID,
-			AccountType: AccountType_ACCOUNT_TYPE_SAVINGS,
+		customerID := suite.customerID("CUST")
+
+		req := &banksim.CreateAccountRequest{
+			BankCode:     TestBankCode,
+			CustomerId:   customerID,
+			AccountType:  banksim.AccountType_ACCOUNT_TYPE_SAVINGS,
 			MobileNumber: TestMobileNumber,
-			Email:       TestEmail,
-			KycDetails: &CustomerKYC{
-				Pan:            "ABCDE1234F",
-				AadhaarMasked:  "****5678",
-				FullName:       "Test Customer",
-				DateOfBirth:    "1990-01-01",
-				Address:        "Test Address, Test City",
+			Email:        TestEmail,
+			KycDetails: &banksim.CustomerKYC{
+				Pan:           "ABCDE1234F",
+				AadhaarMasked: "****5678",
+				FullName:      "Test Customer",
+				DateOfBirth:   "1990-01-01",
+				Address:       "Test Address, Test City",
 			},
 			InitialDepositPaisa: InitialDepositPaisa,
 		}
@@ -139,7 +139,7 @@ func TestAccountCreationFlow(t *testing.T) {
 		assert.NotNil(t, resp)
 		assert.NotEmpty(t, resp.AccountNumber)
 		assert.NotEmpty(t, resp.IfscCode)
-		assert.Equal(t, AccountStatus_ACCOUNT_STATUS_ACTIVE, resp.Status)
+		assert.Equal(t, banksim.AccountStatus_ACCOUNT_STATUS_ACTIVE, resp.Status)
 
 		// Store for cleanup
 		suite.testAccounts = append(suite.testAccounts, resp.AccountNumber)
@@ -152,19 +152,19 @@ func TestAccountCreationFlow(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		customerID := fmt.Sprintf("CUST_%s", uuid.New().String()[:8])
-		createReq := &CreateAccountRequest{
+		customerID := suite.customerID("CUST")
+		createReq := &banksim.CreateAccountRequest{
 			BankCode:     TestBankCode,
 			CustomerId:   customerID,
-			AccountType:  AccountType_ACCOUNT_TYPE_SAVINGS,
+			AccountType:  banksim.AccountType_ACCOUNT_TYPE_SAVINGS,
 			MobileNumber: TestMobileNumber,
 			Email:        TestEmail,
-			KycDetails: &CustomerKYC{
-				Pan:         "ABCDE1234F",
+			KycDetails: &banksim.CustomerKYC{
+				Pan:           "ABCDE1234F",
 				AadhaarMasked: "****5678",
-				FullName:    "Test Customer",
-				DateOfBirth: "1990-01-01",
-				Address:     "Test Address, Test City",
+				FullName:      "Test Customer",
+				DateOfBirth:   "1990-01-01",
+				Address:       "Test Address, Test City",
 			},
 			InitialDepositPaisa: InitialDepositPaisa,
 		}
@@ -174,7 +174,7 @@ func TestAccountCreationFlow(t *testing.T) {
 		suite.testAccounts = append(suite.testAccounts, createResp.AccountNumber)
 
 		// Now get account details
-		detailsReq := &AccountDetailsRequest{
+		detailsReq := &banksim.AccountDetailsRequest{
 			BankCode:      TestBankCode,
 			AccountNumber: createResp.AccountNumber,
 		}
@@ -184,9 +184,9 @@ func TestAccountCreationFlow(t *testing.T) {
 		assert.Equal(t, createResp.AccountNumber, detailsResp.AccountNumber)
 		assert.Equal(t, createResp.IfscCode, detailsResp.IfscCode)
 		assert.Equal(t, int64(InitialDepositPaisa), detailsResp.AvailableBalancePaisa)
-		assert.Equal(t, AccountStatus_ACCOUNT_STATUS_ACTIVE, detailsResp.Status)
+		assert.Equal(t, banksim.AccountStatus_ACCOUNT_STATUS_ACTIVE, detailsResp.Status)
 
-		t.Logf("Account Details - Balance: %d paisa, Status: %v", 
+		t.Logf("Account Details - Balance: %d paisa, Status: %v",
 			detailsResp.AvailableBalancePaisa, detailsResp.Status)
 	})
 }
@@ -203,19 +203,19 @@ func TestVPAResolutionFlow(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		customerID := fmt.Sprintf("CUST_%s", uuid.New().String()[:8])
-		req := &CreateAccountRequest{
+		customerID := suite.customerID("CUST")
+		req := &banksim.CreateAccountRequest{
 			BankCode:     TestBankCode,
 			CustomerId:   customerID,
-			AccountType:  AccountType_ACCOUNT_TYPE_SAVINGS,
+			AccountType:  banksim.AccountType_ACCOUNT_TYPE_SAVINGS,
 			MobileNumber: TestMobileNumber,
 			Email:        TestEmail,
-			KycDetails: &CustomerKYC{
-				Pan:         "ABCDE1234F",
+			KycDetails: &banksim.CustomerKYC{
+				Pan:           "ABCDE1234F",
 				AadhaarMasked: "****5678",
-				FullName:    "Test Customer VPA",
-				DateOfBirth: "1990-01-01",
-				Address:     "Test Address, Test City",
+				FullName:      "Test Customer VPA",
+				DateOfBirth:   "1990-01-01",
+				Address:       "Test Address, Test City",
 			},
 			InitialDepositPaisa: InitialDepositPaisa,
 		}
@@ -230,9 +230,9 @@ func TestVPAResolutionFlow(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		testVPA := fmt.Sprintf("test%s@hdfc", uuid.New().String()[:8])
-		
-		req := &LinkVPARequest{
+		testVPA := suite.vpa("test")
+
+		req := &banksim.LinkVPARequest{
 			Vpa:           testVPA,
 			BankCode:      TestBankCode,
 			AccountNumber: testAccountNumber,
@@ -257,8 +257,8 @@ func TestVPAResolutionFlow(t *testing.T) {
 		defer cancel()
 
 		testVPA := suite.testVPAs[0]
-		
-		req := &ResolveVPARequest{
+
+		req := &banksim.ResolveVPARequest{
 			Vpa: testVPA,
 		}
 
@@ -270,7 +270,7 @@ func TestVPAResolutionFlow(t *testing.T) {
 		assert.NotEmpty(t, resp.AccountHolderName)
 		assert.True(t, resp.IsActive)
 
-		t.Logf("Resolved VPA: %s -> Account: %s, Bank: %s", 
+		t.Logf("Resolved VPA: %s -> Account: %s, Bank: %s",
 			testVPA, resp.AccountNumber, resp.BankCode)
 	})
 
@@ -283,8 +283,8 @@ func TestVPAResolutionFlow(t *testing.T) {
 		defer cancel()
 
 		testVPA := suite.testVPAs[0]
-		
-		req := &ResolveVPARequest{
+
+		req := &upicore.ResolveVPARequest{
 			Vpa: testVPA,
 		}
 
@@ -309,89 +309,17 @@ func TestTransactionProcessingFlow(t *testing.T) {
 	var payerVPA, payeeVPA string
 
 	t.Run("Setup_PayerAndPayeeAccounts", func(t *testing.T) {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-		defer cancel()
-
-		// Create payer account
-		payerCustomerID := fmt.Sprintf("PAYER_%s", uuid.New().String()[:8])
-		payerReq := &CreateAccountRequest{
-			BankCode:     TestBankCode,
-			CustomerId:   payerCustomerID,
-			AccountType:  AccountType_ACCOUNT_TYPE_SAVINGS,
-			MobileNumber: "+919876543210",
-			Email:        "payer@hdfc.com",
-			KycDetails: &CustomerKYC{
-				Pan:         "PAYER1234F",
-				AadhaarMasked: "****1234",
-				FullName:    "Payer Customer",
-				DateOfBirth: "1990-01-01",
-				Address:     "Payer Address, Test City",
-			},
-			InitialDepositPaisa: InitialDepositPaisa * 2, // More money for payer
-		}
-
-		payerResp, err := suite.bankSimClient.CreateAccount(ctx, payerReq)
-		require.NoError(t, err)
-		payerAccount = payerResp.AccountNumber
-		suite.testAccounts = append(suite.testAccounts, payerAccount)
-
-		// Create payee account
-		payeeCustomerID := fmt.Sprintf("PAYEE_%s", uuid.New().String()[:8])
-		payeeReq := &CreateAccountRequest{
-			BankCode:     TestBankCode,
-			CustomerId:   payeeCustomerID,
-			AccountType:  AccountType_ACCOUNT_TYPE_SAVINGS,
-			MobileNumber: "+919876543211",
-			Email:        "payee@hdfc.com",
-			KycDetails: &CustomerKYC{
-				Pan:         "PAYEE1234F",
-				AadhaarMasked: "****5678",
-				FullName:    "Payee Customer",
-				DateOfBirth: "1991-01-01",
-				Address:     "Payee Address, Test City",
-			},
-			InitialDepositPaisa: InitialDepositPaisa,
-		}
-
-		payeeResp, err := suite.bankSimClient.CreateAccount(ctx, payeeReq)
-		require.NoError(t, err)
-		payeeAccount = payeeResp.AccountNumber
-		suite.testAccounts = append(suite.testAccounts, payeeAccount)
+		payer, payee := suite.CreateFundedAccountPair(t, InitialDepositPaisa*2, InitialDepositPaisa) // More money for payer
+		payerAccount, payeeAccount = payer.account, payee.account
 
 		t.Logf("Created payer account: %s, payee account: %s", payerAccount, payeeAccount)
 	})
 
 	t.Run("Setup_VPAs", func(t *testing.T) {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-		defer cancel()
-
-		// Create payer VPA
-		payerVPA = fmt.Sprintf("payer%s@hdfc", uuid.New().String()[:8])
-		payerVPAReq := &LinkVPARequest{
-			Vpa:           payerVPA,
-			BankCode:      TestBankCode,
-			AccountNumber: payerAccount,
-			IsPrimary:     true,
-		}
-
-		payerVPAResp, err := suite.bankSimClient.LinkVPA(ctx, payerVPAReq)
-		require.NoError(t, err)
-		assert.True(t, payerVPAResp.Success)
-		suite.testVPAs = append(suite.testVPAs, payerVPA)
-
-		// Create payee VPA
-		payeeVPA = fmt.Sprintf("payee%s@hdfc", uuid.New().String()[:8])
-		payeeVPAReq := &LinkVPARequest{
-			Vpa:           payeeVPA,
-			BankCode:      TestBankCode,
-			AccountNumber: payeeAccount,
-			IsPrimary:     true,
-		}
-
-		payeeVPAResp, err := suite.bankSimClient.LinkVPA(ctx, payeeVPAReq)
-		require.NoError(t, err)
-		assert.True(t, payeeVPAResp.Success)
-		suite.testVPAs = append(suite.testVPAs, payeeVPA)
+		payer := &testParty{bank: TestBankCode, account: payerAccount}
+		payee := &testParty{bank: TestBankCode, account: payeeAccount}
+		suite.LinkVPAs(t, payer, payee)
+		payerVPA, payeeVPA = payer.vpa, payee.vpa
 
 		t.Logf("Created VPAs - Payer: %s, Payee: %s", payerVPA, payeeVPA)
 	})
@@ -402,12 +330,12 @@ func TestTransactionProcessingFlow(t *testing.T) {
 
 		transactionID := fmt.Sprintf("TXN_%s", uuid.New().String())
 
-		req := &TransactionRequest{
+		req := &banksim.TransactionRequest{
 			TransactionId: transactionID,
 			BankCode:      TestBankCode,
 			AccountNumber: payerAccount,
 			AmountPaisa:   TransactionAmount,
-			Type:          TransactionType_TRANSACTION_TYPE_DEBIT,
+			Type:          banksim.TransactionType_TRANSACTION_TYPE_DEBIT,
 			Reference:     "P2P_TRANSFER",
 			Description:   "Test P2P transfer payment",
 			Metadata: map[string]string{
@@ -420,10 +348,10 @@ func TestTransactionProcessingFlow(t *testing.T) {
 		resp, err := suite.bankSimClient.ProcessTransaction(ctx, req)
 		require.NoError(t, err)
 		assert.Equal(t, transactionID, resp.TransactionId)
-		assert.Equal(t, TransactionStatus_TRANSACTION_STATUS_SUCCESS, resp.Status)
+		assert.Equal(t, banksim.TransactionStatus_TRANSACTION_STATUS_SUCCESS, resp.Status)
 		assert.NotEmpty(t, resp.BankReferenceId)
 
-		t.Logf("Debit transaction processed: %s, Bank Ref: %s, New Balance: %d paisa", 
+		t.Logf("Debit transaction processed: %s, Bank Ref: %s, New Balance: %d paisa",
 			resp.TransactionId, resp.BankReferenceId, resp.AccountBalancePaisa)
 	})
 
@@ -433,12 +361,12 @@ func TestTransactionProcessingFlow(t *testing.T) {
 
 		transactionID := fmt.Sprintf("TXN_%s", uuid.New().String())
 
-		req := &TransactionRequest{
+		req := &banksim.TransactionRequest{
 			TransactionId: transactionID,
 			BankCode:      TestBankCode,
 			AccountNumber: payeeAccount,
 			AmountPaisa:   TransactionAmount,
-			Type:          TransactionType_TRANSACTION_TYPE_CREDIT,
+			Type:          banksim.TransactionType_TRANSACTION_TYPE_CREDIT,
 			Reference:     "P2P_TRANSFER",
 			Description:   "Test P2P transfer receipt",
 			Metadata: map[string]string{
@@ -451,10 +379,10 @@ func TestTransactionProcessingFlow(t *testing.T) {
 		resp, err := suite.bankSimClient.ProcessTransaction(ctx, req)
 		require.NoError(t, err)
 		assert.Equal(t, transactionID, resp.TransactionId)
-		assert.Equal(t, TransactionStatus_TRANSACTION_STATUS_SUCCESS, resp.Status)
+		assert.Equal(t, banksim.TransactionStatus_TRANSACTION_STATUS_SUCCESS, resp.Status)
 		assert.NotEmpty(t, resp.BankReferenceId)
 
-		t.Logf("Credit transaction processed: %s, Bank Ref: %s, New Balance: %d paisa", 
+		t.Logf("Credit transaction processed: %s, Bank Ref: %s, New Balance: %d paisa",
 			resp.TransactionId, resp.BankReferenceId, resp.AccountBalancePaisa)
 	})
 
@@ -465,14 +393,14 @@ func TestTransactionProcessingFlow(t *testing.T) {
 		transactionID := fmt.Sprintf("UPI_%s", uuid.New().String())
 		rrn := fmt.Sprintf("RRN_%s", uuid.New().String()[:12])
 
-		req := &TransactionRequest{
+		req := &upicore.TransactionRequest{
 			TransactionId: transactionID,
 			Rrn:           rrn,
 			PayerVpa:      payerVPA,
 			PayeeVpa:      payeeVPA,
 			AmountPaisa:   TransactionAmount,
 			Currency:      "INR",
-			Type:          TransactionType_TRANSACTION_TYPE_P2P,
+			Type:          upicore.TransactionType_TRANSACTION_TYPE_P2P,
 			Description:   "UPI Integration Test Transfer",
 			Reference:     "INTEGRATION_TEST",
 			Signature:     "dummy_signature",
@@ -489,14 +417,14 @@ func TestTransactionProcessingFlow(t *testing.T) {
 		assert.Equal(t, rrn, resp.Rrn)
 		assert.NotEmpty(t, resp.PayerBankCode)
 		assert.NotEmpty(t, resp.PayeeBankCode)
-		
+
 		// UPI transactions might be async, so status could be PENDING or SUCCESS
-		assert.Contains(t, []TransactionStatus{
-			TransactionStatus_TRANSACTION_STATUS_PENDING,
-			TransactionStatus_TRANSACTION_STATUS_SUCCESS,
+		assert.Contains(t, []upicore.TransactionStatus{
+			upicore.TransactionStatus_TRANSACTION_STATUS_PENDING,
+			upicore.TransactionStatus_TRANSACTION_STATUS_SUCCESS,
 		}, resp.Status)
 
-		t.Logf("UPI transaction processed: %s, RRN: %s, Status: %v, Payer Bank: %s, Payee Bank: %s", 
+		t.Logf("UPI transaction processed: %s, RRN: %s, Status: %v, Payer Bank: %s, Payee Bank: %s",
 			resp.TransactionId, resp.Rrn, resp.Status, resp.PayerBankCode, resp.PayeeBankCode)
 	})
 }
@@ -511,7 +439,7 @@ func TestErrorHandlingAndEdgeCases(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		req := &ResolveVPARequest{
+		req := &banksim.ResolveVPARequest{
 			Vpa: "nonexistent@unknown",
 		}
 
@@ -528,19 +456,19 @@ func TestErrorHandlingAndEdgeCases(t *testing.T) {
 		defer cancel()
 
 		// First create an account with low balance
-		customerID := fmt.Sprintf("POOR_%s", uuid.New().String()[:8])
-		createReq := &CreateAccountRequest{
+		customerID := suite.customerID("POOR")
+		createReq := &banksim.CreateAccountRequest{
 			BankCode:     TestBankCode,
 			CustomerId:   customerID,
-			AccountType:  AccountType_ACCOUNT_TYPE_SAVINGS,
+			AccountType:  banksim.AccountType_ACCOUNT_TYPE_SAVINGS,
 			MobileNumber: "+919876543299",
 			Email:        "poor@hdfc.com",
-			KycDetails: &CustomerKYC{
-				Pan:         "POOR12345F",
+			KycDetails: &banksim.CustomerKYC{
+				Pan:           "POOR12345F",
 				AadhaarMasked: "****9999",
-				FullName:    "Poor Customer",
-				DateOfBirth: "1990-01-01",
-				Address:     "Poor Address, Test City",
+				FullName:      "Poor Customer",
+				DateOfBirth:   "1990-01-01",
+				Address:       "Poor Address, Test City",
 			},
 			InitialDepositPaisa: 1000, // Only 10 INR
 		}
@@ -551,12 +479,12 @@ func TestErrorHandlingAndEdgeCases(t *testing.T) {
 
 		// Try to debit more than available
 		transactionID := fmt.Sprintf("FAIL_%s", uuid.New().String())
-		txnReq := &TransactionRequest{
+		txnReq := &banksim.TransactionRequest{
 			TransactionId: transactionID,
 			BankCode:      TestBankCode,
 			AccountNumber: createResp.AccountNumber,
 			AmountPaisa:   100000, // 1000 INR - more than available
-			Type:          TransactionType_TRANSACTION_TYPE_DEBIT,
+			Type:          banksim.TransactionType_TRANSACTION_TYPE_DEBIT,
 			Reference:     "INSUFFICIENT_FUNDS_TEST",
 			Description:   "Test insufficient funds scenario",
 			InitiatedAt:   timestamppb.Now(),
@@ -564,7 +492,7 @@ func TestErrorHandlingAndEdgeCases(t *testing.T) {
 
 		txnResp, err := suite.bankSimClient.ProcessTransaction(ctx, txnReq)
 		require.NoError(t, err)
-		assert.Equal(t, TransactionStatus_TRANSACTION_STATUS_INSUFFICIENT_FUNDS, txnResp.Status)
+		assert.Equal(t, banksim.TransactionStatus_TRANSACTION_STATUS_INSUFFICIENT_FUNDS, txnResp.Status)
 		assert.NotEmpty(t, txnResp.ErrorCode)
 
 		t.Logf("Insufficient funds test: Status: %v, Error: %s", txnResp.Status, txnResp.ErrorMessage)
@@ -574,19 +502,17 @@ func TestErrorHandlingAndEdgeCases(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		req := &AccountDetailsRequest{
+		req := &banksim.AccountDetailsRequest{
 			BankCode:      TestBankCode,
 			AccountNumber: "99999999999999",
 		}
 
-		resp, err := suite.bankSimClient.GetAccountDetails(ctx, req)
-		// This should either return an error or a response with error code
-		if err == nil {
-			assert.NotEmpty(t, resp.ErrorCode)
-			t.Logf("Non-existent account: Error Code: %s, Message: %s", resp.ErrorCode, resp.ErrorMessage)
-		} else {
-			t.Logf("Non-existent account query failed as expected: %v", err)
-		}
+		// AccountDetailsResponse has no error fields, so a missing account
+		// can only be reported as a gRPC status
+		_, err := suite.bankSimClient.GetAccountDetails(ctx, req)
+		require.Error(t, err)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		t.Logf("Non-existent account query failed as expected: %v", err)
 	})
 }
 
@@ -596,24 +522,26 @@ func TestPerformanceBaseline(t *testing.T) {
 	require.NoError(t, err)
 	defer suite.cleanup()
 
+	latencies := make(map[string][]time.Duration)
+
 	t.Run("VPA_Resolution_Performance", func(t *testing.T) {
 		// Create account and VPA for testing
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		customerID := fmt.Sprintf("PERF_%s", uuid.New().String()[:8])
-		createReq := &CreateAccountRequest{
+		customerID := suite.customerID("PERF")
+		createReq := &banksim.CreateAccountRequest{
 			BankCode:     TestBankCode,
 			CustomerId:   customerID,
-			AccountType:  AccountType_ACCOUNT_TYPE_SAVINGS,
+			AccountType:  banksim.AccountType_ACCOUNT_TYPE_SAVINGS,
 			MobileNumber: "+919876543298",
 			Email:        "perf@hdfc.com",
-			KycDetails: &CustomerKYC{
-				Pan:         "PERF12345F",
+			KycDetails: &banksim.CustomerKYC{
+				Pan:           "PERF12345F",
 				AadhaarMasked: "****9998",
-				FullName:    "Performance Test Customer",
-				DateOfBirth: "1990-01-01",
-				Address:     "Performance Test Address",
+				FullName:      "Performance Test Customer",
+				DateOfBirth:   "1990-01-01",
+				Address:       "Performance Test Address",
 			},
 			InitialDepositPaisa: InitialDepositPaisa,
 		}
@@ -622,8 +550,8 @@ func TestPerformanceBaseline(t *testing.T) {
 		require.NoError(t, err)
 		suite.testAccounts = append(suite.testAccounts, createResp.AccountNumber)
 
-		testVPA := fmt.Sprintf("perf%s@hdfc", uuid.New().String()[:8])
-		vpaReq := &LinkVPARequest{
+		testVPA := suite.vpa("perf")
+		vpaReq := &banksim.LinkVPARequest{
 			Vpa:           testVPA,
 			BankCode:      TestBankCode,
 			AccountNumber: createResp.AccountNumber,
@@ -635,29 +563,19 @@ func TestPerformanceBaseline(t *testing.T) {
 		suite.testVPAs = append(suite.testVPAs, testVPA)
 
 		// Performance test: Multiple VPA resolutions
-		const iterations = 10
-		totalDuration := time.Duration(0)
+		const iterations = 50
 
 		for i := 0; i < iterations; i++ {
 			start := time.Now()
-			
-			resolveReq := &ResolveVPARequest{Vpa: testVPA}
+
+			resolveReq := &banksim.ResolveVPARequest{Vpa: testVPA}
 			resp, err := suite.bankSimClient.ResolveVPA(ctx, resolveReq)
-			
-			duration := time.Since(start)
-			totalDuration += duration
+
+			latencies["vpa_resolution"] = append(latencies["vpa_resolution"], time.Since(start))
 
 			require.NoError(t, err)
 			assert.True(t, resp.Exists)
 		}
-
-		avgDuration := totalDuration / iterations
-		t.Logf("VPA Resolution Performance: %d iterations, Avg: %v, Total: %v", 
-			iterations, avgDuration, totalDuration)
-
-		// Performance requirement: VPA resolution should be < 300ms
-		assert.Less(t, avgDuration.Milliseconds(), int64(300), 
-			"VPA resolution took longer than 300ms on average")
 	})
 
 	t.Run("Transaction_Processing_Performance", func(t *testing.T) {
@@ -665,19 +583,19 @@ func TestPerformanceBaseline(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		customerID := fmt.Sprintf("TXNPERF_%s", uuid.New().String()[:8])
-		createReq := &CreateAccountRequest{
+		customerID := suite.customerID("TXNPERF")
+		createReq := &banksim.CreateAccountRequest{
 			BankCode:     TestBankCode,
 			CustomerId:   customerID,
-			AccountType:  AccountType_ACCOUNT_TYPE_SAVINGS,
+			AccountType:  banksim.AccountType_ACCOUNT_TYPE_SAVINGS,
 			MobileNumber: "+919876543297",
 			Email:        "txnperf@hdfc.com",
-			KycDetails: &CustomerKYC{
-				Pan:         "TXNPR1234F",
+			KycDetails: &banksim.CustomerKYC{
+				Pan:           "TXNPR1234F",
 				AadhaarMasked: "****9997",
-				FullName:    "Transaction Performance Test",
-				DateOfBirth: "1990-01-01",
-				Address:     "Transaction Performance Address",
+				FullName:      "Transaction Performance Test",
+				DateOfBirth:   "1990-01-01",
+				Address:       "Transaction Performance Address",
 			},
 			InitialDepositPaisa: InitialDepositPaisa * 10, // More balance for multiple transactions
 		}
@@ -687,42 +605,35 @@ func TestPerformanceBaseline(t *testing.T) {
 		suite.testAccounts = append(suite.testAccounts, createResp.AccountNumber)
 
 		// Performance test: Multiple small transactions
-		const iterations = 5
+		const iterations = 20
 		const txnAmount = 1000 // 10 INR each
-		totalDuration := time.Duration(0)
 
 		for i := 0; i < iterations; i++ {
 			start := time.Now()
-			
+
 			transactionID := fmt.Sprintf("PERF_%d_%s", i, uuid.New().String()[:8])
-			txnReq := &TransactionRequest{
+			txnReq := &banksim.TransactionRequest{
 				TransactionId: transactionID,
 				BankCode:      TestBankCode,
 				AccountNumber: createResp.AccountNumber,
 				AmountPaisa:   txnAmount,
-				Type:          TransactionType_TRANSACTION_TYPE_DEBIT,
+				Type:          banksim.TransactionType_TRANSACTION_TYPE_DEBIT,
 				Reference:     "PERFORMANCE_TEST",
 				Description:   fmt.Sprintf("Performance test transaction %d", i),
 				InitiatedAt:   timestamppb.Now(),
 			}
 
 			resp, err := suite.bankSimClient.ProcessTransaction(ctx, txnReq)
-			
-			duration := time.Since(start)
-			totalDuration += duration
+
+			latencies["transaction_processing"] = append(latencies["transaction_processing"], time.Since(start))
 
 			require.NoError(t, err)
-			assert.Equal(t, TransactionStatus_TRANSACTION_STATUS_SUCCESS, resp.Status)
+			assert.Equal(t, banksim.TransactionStatus_TRANSACTION_STATUS_SUCCESS, resp.Status)
 		}
-
-		avgDuration := totalDuration / iterations
-		t.Logf("Transaction Processing Performance: %d iterations, Avg: %v, Total: %v", 
-			iterations, avgDuration, totalDuration)
-
-		// Performance requirement: Transaction processing should be < 500ms
-		assert.Less(t, avgDuration.Milliseconds(), int64(500), 
-			"Transaction processing took longer than 500ms on average")
 	})
+
+	// Latency is judged against earlier runs rather than a fixed bar
+	suite.recordPerformance(t, latencies)
 }
 
 // Benchmark tests
@@ -735,19 +646,19 @@ func BenchmarkVPAResolution(b *testing.B) {
 
 	// Setup a test VPA
 	ctx := context.Background()
-	customerID := fmt.Sprintf("BENCH_%s", uuid.New().String()[:8])
-	createReq := &CreateAccountRequest{
+	customerID := suite.customerID("BENCH")
+	createReq := &banksim.CreateAccountRequest{
 		BankCode:     TestBankCode,
 		CustomerId:   customerID,
-		AccountType:  AccountType_ACCOUNT_TYPE_SAVINGS,
+		AccountType:  banksim.AccountType_ACCOUNT_TYPE_SAVINGS,
 		MobileNumber: "+919876543296",
 		Email:        "bench@hdfc.com",
-		KycDetails: &CustomerKYC{
-			Pan:         "BENCH1234F",
+		KycDetails: &banksim.CustomerKYC{
+			Pan:           "BENCH1234F",
 			AadhaarMasked: "****9996",
-			FullName:    "Benchmark Test Customer",
-			DateOfBirth: "1990-01-01",
-			Address:     "Benchmark Test Address",
+			FullName:      "Benchmark Test Customer",
+			DateOfBirth:   "1990-01-01",
+			Address:       "Benchmark Test Address",
 		},
 		InitialDepositPaisa: InitialDepositPaisa,
 	}
@@ -757,8 +668,8 @@ func BenchmarkVPAResolution(b *testing.B) {
 		b.Fatalf("Failed to create account: %v", err)
 	}
 
-	testVPA := fmt.Sprintf("bench%s@hdfc", uuid.New().String()[:8])
-	vpaReq := &LinkVPARequest{
+	testVPA := suite.vpa("bench")
+	vpaReq := &banksim.LinkVPARequest{
 		Vpa:           testVPA,
 		BankCode:      TestBankCode,
 		AccountNumber: createResp.AccountNumber,
@@ -774,7 +685,7 @@ func BenchmarkVPAResolution(b *testing.B) {
 
 	b.Run("BankSimulator_ResolveVPA", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			req := &ResolveVPARequest{Vpa: testVPA}
+			req := &banksim.ResolveVPARequest{Vpa: testVPA}
 			_, err := suite.bankSimClient.ResolveVPA(ctx, req)
 			if err != nil {
 				b.Errorf("VPA resolution failed: %v", err)
@@ -784,11 +695,11 @@ func BenchmarkVPAResolution(b *testing.B) {
 
 	b.Run("UpiCore_ResolveVPA", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			req := &ResolveVPARequest{Vpa: testVPA}
+			req := &upicore.ResolveVPARequest{Vpa: testVPA}
 			_, err := suite.upiCoreClient.ResolveVPA(ctx, req)
 			if err != nil {
 				b.Errorf("UPI Core VPA resolution failed: %v", err)
 			}
 		}
 	})
-}
\ No newline at end of file
+}